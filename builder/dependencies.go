@@ -15,7 +15,8 @@ type VM interface {
 	StopChan() chan struct{}
 	EngineChan() chan<- common.Message
 	PreferredBlock(context.Context) (*chain.StatelessBlock, error)
-	Logger() logging.Logger
+	ModuleLogger(module string) logging.Logger
 	Mempool() chain.Mempool
-	Rules(int64) chain.Rules
+	StateManager() chain.StateManager
+	chain.Parser
 }
@@ -7,6 +7,8 @@ import (
 	"context"
 
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+
+	"github.com/ava-labs/hypersdk/logs"
 )
 
 var _ Builder = (*Manual)(nil)
@@ -34,7 +36,7 @@ func (b *Manual) Force(context.Context) error {
 	select {
 	case b.vm.EngineChan() <- common.PendingTxs:
 	default:
-		b.vm.Logger().Debug("dropping message to consensus engine")
+		b.vm.ModuleLogger(logs.Builder).Debug("dropping message to consensus engine")
 	}
 	return nil
 }
@@ -12,6 +12,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/logs"
 )
 
 // minBuildGap ensures we don't build blocks too quickly (can fail
@@ -48,17 +51,33 @@ func (b *Time) Run() {
 
 func (b *Time) handleTimerNotify() {
 	if err := b.Force(context.TODO()); err != nil {
-		b.vm.Logger().Warn("unable to build", zap.Error(err))
+		b.vm.ModuleLogger(logs.Builder).Warn("unable to build", zap.Error(err))
 	} else {
 		txs := b.vm.Mempool().Len(context.TODO())
-		b.vm.Logger().Debug("trigger to notify", zap.Int("txs", txs))
+		b.vm.ModuleLogger(logs.Builder).Debug("trigger to notify", zap.Int("txs", txs))
 	}
 	b.waiting.Store(false)
 }
 
-func (b *Time) nextTime(now int64, preferred int64) int64 {
-	gap := b.vm.Rules(now).GetMinBlockGap()
-	next := math.Max(b.lastQueue+minBuildGap, preferred+gap)
+// minBlockGap returns the min block gap of a block built on top of
+// [preferred] at [now], which may be overridden in the state of [preferred].
+func (b *Time) minBlockGap(ctx context.Context, now int64, preferred *chain.StatelessBlock) int64 {
+	view, err := preferred.View(ctx, false)
+	if err != nil {
+		b.vm.ModuleLogger(logs.Builder).Warn("unable to load preferred state", zap.Error(err))
+		return b.vm.Rules(now).GetMinBlockGap()
+	}
+	r, err := chain.StateRules(ctx, b.vm, b.vm.StateManager(), view, now)
+	if err != nil {
+		b.vm.ModuleLogger(logs.Builder).Warn("unable to load rules overrides", zap.Error(err))
+		return b.vm.Rules(now).GetMinBlockGap()
+	}
+	return r.GetMinBlockGap()
+}
+
+func (b *Time) nextTime(ctx context.Context, now int64, preferred *chain.StatelessBlock) int64 {
+	gap := b.minBlockGap(ctx, now, preferred)
+	next := math.Max(b.lastQueue+minBuildGap, preferred.Tmstmp+gap)
 	if next < now {
 		return -1
 	}
@@ -67,23 +86,23 @@ func (b *Time) nextTime(now int64, preferred int64) int64 {
 
 func (b *Time) Queue(ctx context.Context) {
 	if !b.waiting.CompareAndSwap(false, true) {
-		b.vm.Logger().Debug("unable to acquire waiting lock")
+		b.vm.ModuleLogger(logs.Builder).Debug("unable to acquire waiting lock")
 		return
 	}
 	preferredBlk, err := b.vm.PreferredBlock(context.TODO())
 	if err != nil {
 		b.waiting.Store(false)
-		b.vm.Logger().Warn("unable to load preferred block", zap.Error(err))
+		b.vm.ModuleLogger(logs.Builder).Warn("unable to load preferred block", zap.Error(err))
 		return
 	}
 	now := time.Now().UnixMilli()
-	next := b.nextTime(now, preferredBlk.Tmstmp)
+	next := b.nextTime(ctx, now, preferredBlk)
 	if next < 0 {
 		if err := b.Force(ctx); err != nil {
-			b.vm.Logger().Warn("unable to build", zap.Error(err))
+			b.vm.ModuleLogger(logs.Builder).Warn("unable to build", zap.Error(err))
 		} else {
 			txs := b.vm.Mempool().Len(context.TODO())
-			b.vm.Logger().Debug("notifying to build without waiting", zap.Int("txs", txs))
+			b.vm.ModuleLogger(logs.Builder).Debug("notifying to build without waiting", zap.Int("txs", txs))
 		}
 		b.waiting.Store(false)
 		return
@@ -91,7 +110,7 @@ func (b *Time) Queue(ctx context.Context) {
 	sleep := next - now
 	sleepDur := time.Duration(sleep * int64(time.Millisecond))
 	b.timer.SetTimeoutIn(sleepDur)
-	b.vm.Logger().Debug("waiting to notify to build", zap.Duration("t", sleepDur))
+	b.vm.ModuleLogger(logs.Builder).Debug("waiting to notify to build", zap.Duration("t", sleepDur))
 }
 
 func (b *Time) Force(context.Context) error {
@@ -99,7 +118,7 @@ func (b *Time) Force(context.Context) error {
 	case b.vm.EngineChan() <- common.PendingTxs:
 		b.lastQueue = time.Now().UnixMilli()
 	default:
-		b.vm.Logger().Debug("dropping message to consensus engine")
+		b.vm.ModuleLogger(logs.Builder).Debug("dropping message to consensus engine")
 	}
 	return nil
 }
@@ -0,0 +1,79 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+// MaxAccessListKeys is the maximum number of keys in the access list of a
+// [Transaction].
+const MaxAccessListKeys = 1_024
+
+// accessListLen is the size of [accessList] in the encoding of a
+// [Transaction] (it is only encoded if it is not empty).
+func accessListLen(accessList []string) int {
+	if len(accessList) == 0 {
+		return 0
+	}
+	size := consts.IntLen
+	for _, k := range accessList {
+		size += codec.StringLen(k)
+	}
+	return size
+}
+
+func packAccessList(p *codec.Packer, accessList []string) {
+	if len(accessList) == 0 {
+		return
+	}
+	p.PackInt(len(accessList))
+	for _, k := range accessList {
+		p.PackString(k)
+	}
+}
+
+func unpackAccessList(p *codec.Packer) ([]string, error) {
+	count := p.UnpackInt(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if count > MaxAccessListKeys {
+		return nil, fmt.Errorf("%w: %d keys", ErrInvalidAccessList, count)
+	}
+	accessList := make([]string, 0, count)
+	seen := set.NewSet[string](count)
+	for i := 0; i < count; i++ {
+		k := p.UnpackString(true)
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		if !keys.Valid(k) {
+			return nil, fmt.Errorf("%w: invalid key %x", ErrInvalidAccessList, k)
+		}
+		if seen.Contains(k) {
+			return nil, fmt.Errorf("%w: duplicate key %x", ErrInvalidAccessList, k)
+		}
+		seen.Add(k)
+		accessList = append(accessList, k)
+	}
+	return accessList, nil
+}
+
+// checkAccessList returns [ErrAccessListMismatch] if [actionKeys] are not
+// all declared in [accessList].
+func checkAccessList(accessList set.Set[string], actionKeys []string) error {
+	for _, k := range actionKeys {
+		if !accessList.Contains(k) {
+			return fmt.Errorf("%w: undeclared key %x", ErrAccessListMismatch, k)
+		}
+	}
+	return nil
+}
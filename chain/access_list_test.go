@@ -0,0 +1,76 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+func TestAccessListEncoding(t *testing.T) {
+	require := require.New(t)
+
+	accessList := []string{
+		string(keys.EncodeChunks([]byte{0x1}, 1)),
+		string(keys.EncodeChunks([]byte{0x2}, 2)),
+	}
+	size := accessListLen(accessList)
+	p := codec.NewWriter(size, size)
+	packAccessList(p, accessList)
+	require.NoError(p.Err())
+	require.LessOrEqual(len(p.Bytes()), size)
+	parsed, err := unpackAccessList(codec.NewReader(p.Bytes(), size))
+	require.NoError(err)
+	require.Equal(accessList, parsed)
+
+	// Empty access lists are not encoded
+	require.Zero(accessListLen(nil))
+
+	// Malformed access lists can't be parsed
+	for _, malformed := range [][]string{
+		{accessList[0], accessList[0]},
+		{"x"},
+	} {
+		size := accessListLen(malformed)
+		p := codec.NewWriter(size, size)
+		packAccessList(p, malformed)
+		_, err := unpackAccessList(codec.NewReader(p.Bytes(), size))
+		require.ErrorIs(err, ErrInvalidAccessList)
+	}
+	p = codec.NewWriter(consts.IntLen, consts.IntLen)
+	p.PackInt(MaxAccessListKeys + 1)
+	_, err = unpackAccessList(codec.NewReader(p.Bytes(), consts.IntLen))
+	require.ErrorIs(err, ErrInvalidAccessList)
+}
+
+func TestAccessListStateKeys(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	r := newUnitsRules(ctrl, false)
+	tx, _ := newUnitsTx(t, ctrl, r)
+	actionKeys := tx.Action.StateKeys(tx.Auth.Actor(), tx.ID())
+	extra := string(keys.EncodeChunks([]byte("extra"), 1))
+
+	// A superset of the action keys is used as its scope
+	tx.AccessList = append([]string{extra}, actionKeys...)
+	stateKeys, err := tx.StateKeys(testStateManager{}, r)
+	require.NoError(err)
+	require.True(stateKeys.Contains(extra))
+	for _, k := range actionKeys {
+		require.True(stateKeys.Contains(k))
+	}
+
+	// Undeclared keys are rejected
+	tx, _ = newUnitsTx(t, ctrl, r)
+	tx.AccessList = []string{extra, actionKeys[0]}
+	_, err = tx.StateKeys(testStateManager{}, r)
+	require.ErrorIs(err, ErrAccessListMismatch)
+}
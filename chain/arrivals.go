@@ -0,0 +1,50 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+// arrivalConflict is added to the conflicts of every transaction executed
+// during FCFS block building (see [VM.GetFCFSOrdering]), so that the
+// executor runs them one at a time in the order they are queued. It is never
+// a valid state key.
+const arrivalConflict = ""
+
+// sortByArrival orders [txs] by when they were received by this node. Txs
+// received at the same time keep their relative order.
+func sortByArrival(txs []*Transaction) {
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].Arrival() < txs[j].Arrival()
+	})
+}
+
+func withArrivalConflict(stateKeys set.Set[string]) set.Set[string] {
+	conflicts := set.NewSet[string](stateKeys.Len() + 1)
+	conflicts.Union(stateKeys)
+	conflicts.Add(arrivalConflict)
+	return conflicts
+}
+
+// verifyArrivals ensures [arrivals] (if any) never decrease and were all
+// observed before the block was built at [timestamp]. The number of
+// arrivals is checked against the number of transactions when the block is
+// unmarshalled.
+func verifyArrivals(timestamp int64, arrivals []int64) error {
+	var last int64
+	for i, arrival := range arrivals {
+		if arrival < last {
+			return fmt.Errorf("%w: arrival %d decreases", ErrInvalidArrivals, i)
+		}
+		if arrival > timestamp {
+			return fmt.Errorf("%w: arrival %d is after block timestamp", ErrInvalidArrivals, i)
+		}
+		last = arrival
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortByArrival(t *testing.T) {
+	require := require.New(t)
+
+	// Txs received at the same time keep their relative order
+	txs := make([]*Transaction, 4)
+	for i, arrival := range []int64{30, 10, 20, 10} {
+		txs[i] = &Transaction{}
+		txs[i].SetArrival(arrival)
+	}
+	sorted := []*Transaction{txs[1], txs[3], txs[2], txs[0]}
+	sortByArrival(txs)
+	require.Equal(sorted, txs)
+}
+
+func TestWithArrivalConflict(t *testing.T) {
+	require := require.New(t)
+
+	stateKeys := set.Of("a", "b")
+	conflicts := withArrivalConflict(stateKeys)
+	require.Equal(set.Of("a", "b", arrivalConflict), conflicts)
+	require.Equal(set.Of("a", "b"), stateKeys)
+}
+
+func TestVerifyArrivals(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(verifyArrivals(100, nil))
+	require.NoError(verifyArrivals(100, []int64{10, 10, 100}))
+	require.ErrorIs(verifyArrivals(100, []int64{20, 10}), ErrInvalidArrivals)
+	require.ErrorIs(verifyArrivals(100, []int64{10, 101}), ErrInvalidArrivals)
+}
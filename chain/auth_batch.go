@@ -7,13 +7,14 @@ import (
 	"context"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/workers"
 )
 
 const authWorkerBacklog = 16_384
 
 type AuthVM interface {
-	Logger() logging.Logger
+	ModuleLogger(module string) logging.Logger
 	GetAuthBatchVerifier(authTypeID uint8, cores int, count int) (AuthBatchVerifier, bool)
 }
 
@@ -24,34 +25,70 @@ type AuthVM interface {
 type AuthBatch struct {
 	vm  AuthVM
 	job workers.Job
-	bvs map[uint8]*authBatchWorker
+	bvs map[uint8]*authBatchWorker // nil if the auth type doesn't support batching
+
+	// If [streaming], batch verifiers are created when the first auth of
+	// their type is added and sized for the [remaining] auths.
+	streaming bool
+	remaining int
 }
 
+// NewAuthBatch creates an [AuthBatch] that [authTypes] (the number of auths of
+// each type) will be added to.
 func NewAuthBatch(vm AuthVM, job workers.Job, authTypes map[uint8]int) *AuthBatch {
-	bvs := map[uint8]*authBatchWorker{}
+	a := &AuthBatch{vm: vm, job: job, bvs: map[uint8]*authBatchWorker{}}
 	for t, count := range authTypes {
-		bv, ok := vm.GetAuthBatchVerifier(t, job.Workers(), count)
-		if !ok {
-			continue
-		}
-		bw := &authBatchWorker{
-			vm,
-			job,
-			bv,
-			make(chan *authBatchObject, authWorkerBacklog),
-			make(chan struct{}),
-		}
-		go bw.start()
-		bvs[t] = bw
+		a.addWorker(t, count)
 	}
-	return &AuthBatch{vm, job, bvs}
+	return a
+}
+
+// NewStreamingAuthBatch creates an [AuthBatch] that [count] auths will be
+// added to, without knowing their types in advance. This allows verification
+// to start while the rest of the auths are still being unmarshalled.
+//
+// Because the number of auths of each type is only bounded by the number of
+// auths not yet added, batches of less common types may be larger than
+// necessary.
+func NewStreamingAuthBatch(vm AuthVM, job workers.Job, count int) *AuthBatch {
+	return &AuthBatch{
+		vm:        vm,
+		job:       job,
+		bvs:       map[uint8]*authBatchWorker{},
+		streaming: true,
+		remaining: count,
+	}
+}
+
+func (a *AuthBatch) addWorker(t uint8, count int) *authBatchWorker {
+	bv, ok := a.vm.GetAuthBatchVerifier(t, a.job.Workers(), count)
+	if !ok {
+		a.bvs[t] = nil
+		return nil
+	}
+	bw := &authBatchWorker{
+		a.vm,
+		a.job,
+		bv,
+		make(chan *authBatchObject, authWorkerBacklog),
+		make(chan struct{}),
+	}
+	go bw.start()
+	a.bvs[t] = bw
+	return bw
 }
 
 func (a *AuthBatch) Add(digest []byte, auth Auth) {
+	t := auth.GetTypeID()
+	bv, ok := a.bvs[t]
+	if !ok && a.streaming {
+		bv = a.addWorker(t, a.remaining)
+	}
+	a.remaining--
+
 	// If batch doesn't exist for auth, just add verify right to job and start
 	// processing.
-	bv, ok := a.bvs[auth.GetTypeID()]
-	if !ok {
+	if bv == nil {
 		a.job.Go(func() error { return auth.Verify(context.TODO(), digest) })
 		return
 	}
@@ -60,12 +97,15 @@ func (a *AuthBatch) Add(digest []byte, auth Auth) {
 
 func (a *AuthBatch) Done(f func()) {
 	for _, bw := range a.bvs {
+		if bw == nil {
+			continue
+		}
 		close(bw.items)
 		<-bw.done
 
 		for _, item := range bw.bv.Done() {
 			a.job.Go(item)
-			a.vm.Logger().Debug("enqueued batch for processing during done")
+			a.vm.ModuleLogger(logs.Chain).Debug("enqueued batch for processing during done")
 		}
 	}
 	a.job.Done(f)
@@ -91,7 +131,7 @@ func (b *authBatchWorker) start() {
 		if j := b.bv.Add(object.digest, object.auth); j != nil {
 			// May finish parts of batch early, let's start computing them as soon as possible
 			b.job.Go(j)
-			b.vm.Logger().Debug("enqueued batch for processing during add")
+			b.vm.ModuleLogger(logs.Chain).Debug("enqueued batch for processing during add")
 		}
 	}
 }
@@ -11,13 +11,42 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 )
 
-const BaseSize = consts.Uint64Len*2 + consts.IDLen
+const (
+	BaseSize = consts.Uint64Len*2 + consts.IDLen
+
+	// HeightBaseSize is the size of a [Base] with a [Height] expiry.
+	HeightBaseSize = BaseSize + consts.Uint64Len
+
+	// NonceBaseSize is the size of a [Base] with a [Nonce].
+	NonceBaseSize = HeightBaseSize + consts.Uint64Len
+
+	// MaxBaseSize is the size of a [Base] with a [Nonce] and a [FeeAsset]
+	// (including the prefix that marks the [FeeAsset] as included).
+	MaxBaseSize = NonceBaseSize + consts.IDLen + consts.Uint64Len + consts.Int64Len + consts.ByteLen
+)
 
 type Base struct {
 	// Timestamp is the expiry of the transaction (inclusive). Once this time passes and the
 	// transaction is not included in a block, it is safe to regenerate it.
+	//
+	// Timestamp must be 0 if the transaction expires at [Height] instead (or
+	// never expires).
 	Timestamp int64 `json:"timestamp"`
 
+	// Height is the expiry block height of the transaction (inclusive), for
+	// applications that need block-precise expiry that doesn't depend on the
+	// clocks of validators. It is only used if [Timestamp] is 0 and requires
+	// [Rules.GetHeightValidityWindow] to be non-zero.
+	//
+	// If both [Timestamp] and [Height] are 0, the transaction never expires
+	// and is instead protected from replay by [Nonce].
+	Height uint64 `json:"height,omitempty"`
+
+	// Nonce must equal the number of transactions previously executed by the
+	// sponsor of the transaction. It is only used if the transaction has no
+	// expiry and requires [Rules.GetSequentialNonces] to be true.
+	Nonce uint64 `json:"nonce,omitempty"`
+
 	// ChainID protects against replay attacks on different VM instances.
 	ChainID ids.ID `json:"chainId"`
 
@@ -26,9 +55,45 @@ type Base struct {
 	//
 	// If the fee is too low to pay all fees, the transaction will be dropped.
 	MaxFee uint64 `json:"maxFee"`
+
+	// FeeAsset, if not empty, is the asset that fees are paid in instead of
+	// the native asset. Fees (and [MaxFee]) are still priced in the native
+	// asset and are converted by the [FeeConverter] of the [StateManager]
+	// when the transaction is executed.
+	//
+	// It is only encoded if it is set (see [txExtensions]).
+	FeeAsset ids.ID `json:"feeAsset"`
+
+	// MaxFeeAsset is the most of [FeeAsset] the user will pay for [MaxFee].
+	// If [MaxFee] converts to more (e.g. because the rate changed after the
+	// transaction was signed), the transaction is dropped.
+	//
+	// It must be non-zero if [FeeAsset] is set and is only encoded with it.
+	MaxFeeAsset uint64 `json:"maxFeeAsset,omitempty"`
 }
 
-func (b *Base) Execute(chainID ids.ID, r Rules, timestamp int64) error {
+// HeightExpiry returns true if the transaction expires at [Height] instead of
+// [Timestamp].
+func (b *Base) HeightExpiry() bool {
+	return b.Timestamp == 0 && b.Height != 0
+}
+
+// SequentialNonce returns true if the transaction never expires and is
+// ordered by [Nonce] instead.
+func (b *Base) SequentialNonce() bool {
+	return b.Timestamp == 0 && b.Height == 0
+}
+
+func (b *Base) Execute(chainID ids.ID, r Rules, timestamp int64, height uint64) error {
+	if b.SequentialNonce() {
+		return b.executeNonce(chainID, r)
+	}
+	if r.GetSequentialNonces() {
+		return ErrNonceRequired
+	}
+	if b.HeightExpiry() {
+		return b.executeHeight(chainID, r, height)
+	}
 	switch {
 	case b.Timestamp%consts.MillisecondsPerSecond != 0:
 		// TODO: make this modulus configurable
@@ -44,24 +109,106 @@ func (b *Base) Execute(chainID ids.ID, r Rules, timestamp int64) error {
 	}
 }
 
-func (*Base) Size() int {
-	return BaseSize
+func (b *Base) executeHeight(chainID ids.ID, r Rules, height uint64) error {
+	window := r.GetHeightValidityWindow()
+	switch {
+	case window == 0:
+		return ErrHeightExpiryDisabled
+	case b.Height < height: // tx: 100 block: 110
+		return ErrHeightTooLate
+	case b.Height-height > window: // tx: 100 block 10
+		return ErrHeightTooEarly
+	case b.ChainID != chainID:
+		return ErrInvalidChainID
+	default:
+		return nil
+	}
+}
+
+// executeNonce only verifies that nonces are enabled, the value of [Nonce]
+// is checked against state in [Transaction.PreExecute].
+func (b *Base) executeNonce(chainID ids.ID, r Rules) error {
+	switch {
+	case !r.GetSequentialNonces():
+		return ErrNoncesDisabled
+	case b.ChainID != chainID:
+		return ErrInvalidChainID
+	default:
+		return nil
+	}
+}
+
+// HasFeeAsset returns true if fees are paid in [FeeAsset] instead of the
+// native asset.
+func (b *Base) HasFeeAsset() bool {
+	return b.FeeAsset != ids.Empty
 }
 
+func (b *Base) Size() int {
+	var size int
+	switch {
+	case b.SequentialNonce():
+		size = NonceBaseSize
+	case b.HeightExpiry():
+		size = HeightBaseSize
+	default:
+		size = BaseSize
+	}
+	if b.HasFeeAsset() {
+		size += consts.IDLen + consts.Uint64Len
+	}
+	return size
+}
+
+// Marshal packs [b] (without the prefix that marks its [FeeAsset] as
+// included, which precedes it in the encoding of a [Transaction]).
 func (b *Base) Marshal(p *codec.Packer) {
 	p.PackInt64(b.Timestamp)
+	if b.Timestamp == 0 {
+		p.PackUint64(b.Height)
+		if b.Height == 0 {
+			p.PackUint64(b.Nonce)
+		}
+	}
 	p.PackID(b.ChainID)
 	p.PackUint64(b.MaxFee)
+	if b.HasFeeAsset() {
+		p.PackID(b.FeeAsset)
+		p.PackUint64(b.MaxFeeAsset)
+	}
 }
 
-func UnmarshalBase(p *codec.Packer) (*Base, error) {
-	var base Base
-	base.Timestamp = p.UnpackInt64(true)
+// unmarshalBase unpacks a [Base] (and the optional fields of its
+// [Transaction], if any are included).
+func unmarshalBase(p *codec.Packer) (*Base, txExtensions, error) {
+	var (
+		base Base
+		exts txExtensions
+		err  error
+	)
+	base.Timestamp = p.UnpackInt64(false)
+	if base.Timestamp == extendedTxMarker {
+		exts, err = unpackExtensions(p)
+		if err != nil {
+			return nil, 0, err
+		}
+		base.Timestamp = p.UnpackInt64(false)
+	}
 	if base.Timestamp%consts.MillisecondsPerSecond != 0 {
 		// TODO: make this modulus configurable
-		return nil, fmt.Errorf("%w: timestamp=%d", ErrMisalignedTime, base.Timestamp)
+		return nil, 0, fmt.Errorf("%w: timestamp=%d", ErrMisalignedTime, base.Timestamp)
+	}
+	if base.Timestamp == 0 {
+		base.Height = p.UnpackUint64(false)
+		if base.Height == 0 {
+			base.Nonce = p.UnpackUint64(false)
+		}
 	}
 	p.UnpackID(true, &base.ChainID)
 	base.MaxFee = p.UnpackUint64(true)
-	return &base, p.Err()
+	if exts&extFeeAsset != 0 {
+		p.UnpackID(true, &base.FeeAsset)
+		base.MaxFeeAsset = p.UnpackUint64(true)
+	}
+	return &base, exts, p.Err()
 }
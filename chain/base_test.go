@@ -0,0 +1,110 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// newExpiryRules returns rules with a height validity window of [window]
+// (disabled if 0) and sequential nonces enabled if [nonces] is true.
+func newExpiryRules(ctrl *gomock.Controller, window uint64, nonces bool) *MockRules {
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetHeightValidityWindow().Return(window).AnyTimes()
+	r.EXPECT().GetSequentialNonces().Return(nonces).AnyTimes()
+	r.EXPECT().GetValidityWindow().Return(int64(60_000)).AnyTimes()
+	return r
+}
+
+func TestBaseMarshal(t *testing.T) {
+	chainID := ids.GenerateTestID()
+	for _, test := range []struct {
+		name string
+		base *Base
+		size int
+	}{
+		{
+			name: "timestamp",
+			base: &Base{Timestamp: 1_000, ChainID: chainID, MaxFee: 1},
+			size: BaseSize,
+		},
+		{
+			name: "height",
+			base: &Base{Height: 100, ChainID: chainID, MaxFee: 1},
+			size: HeightBaseSize,
+		},
+		{
+			name: "nonce",
+			base: &Base{Nonce: 7, ChainID: chainID, MaxFee: 1},
+			size: NonceBaseSize,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			require.Equal(test.size, test.base.Size())
+			p := codec.NewWriter(test.size, test.size)
+			test.base.Marshal(p)
+			require.NoError(p.Err())
+			require.Len(p.Bytes(), test.size)
+
+			parsed, _, err := unmarshalBase(codec.NewReader(p.Bytes(), test.size))
+			require.NoError(err)
+			require.Equal(test.base, parsed)
+		})
+	}
+}
+
+func TestBaseHeightExpiry(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	chainID := ids.GenerateTestID()
+	base := &Base{Height: 100, ChainID: chainID}
+	require.True(base.HeightExpiry())
+	require.False(base.SequentialNonce())
+
+	// Height expiry is disabled by default
+	require.ErrorIs(base.Execute(chainID, newExpiryRules(ctrl, 0, false), 1_000, 100), ErrHeightExpiryDisabled)
+
+	// The block time is ignored once enabled
+	r := newExpiryRules(ctrl, 10, false)
+	require.ErrorIs(base.Execute(chainID, r, 1_000, 89), ErrHeightTooEarly)
+	require.NoError(base.Execute(chainID, r, 0, 90))
+	require.NoError(base.Execute(chainID, r, consts.MaxInt64, 100))
+	require.ErrorIs(base.Execute(chainID, r, 1_000, 101), ErrHeightTooLate)
+	require.ErrorIs(base.Execute(ids.GenerateTestID(), r, 1_000, 100), ErrInvalidChainID)
+	require.Equal(uint64(90), OldestAllowedHeight(r, 100))
+	require.Equal(uint64(0), OldestAllowedHeight(r, 5))
+
+	// Expiring transactions are rejected if nonces are required
+	require.ErrorIs(base.Execute(chainID, newExpiryRules(ctrl, 10, true), 1_000, 100), ErrNonceRequired)
+}
+
+func TestBaseSequentialNonce(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	chainID := ids.GenerateTestID()
+	base := &Base{Nonce: 7, ChainID: chainID}
+	require.True(base.SequentialNonce())
+	require.False(base.HeightExpiry())
+
+	// Nonces are disabled by default
+	require.ErrorIs(base.Execute(chainID, newExpiryRules(ctrl, 0, false), 1_000, 1), ErrNoncesDisabled)
+
+	// Once enabled, neither the block time nor height are checked
+	r := newExpiryRules(ctrl, 0, true)
+	require.NoError(base.Execute(chainID, r, consts.MaxInt64, consts.MaxUint64))
+	require.ErrorIs(base.Execute(ids.GenerateTestID(), r, 1_000, 1), ErrInvalidChainID)
+	expiring := &Base{Timestamp: 1_000, ChainID: chainID}
+	require.ErrorIs(expiring.Execute(chainID, r, 1_000, 1), ErrNonceRequired)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// MaxBlobSize is the maximum size of the blob attached to a [Transaction].
+const MaxBlobSize = 1 * units.MiB
+
+// blobDimension is the [Dimension] blob bytes are charged on (or -1 if blobs
+// are not enabled).
+var blobDimension Dimension = -1
+
+// EnableBlobs allows transactions to carry a blob (a large payload that is
+// not accessible during execution). Blob bytes are charged on a dedicated
+// [Dimension] instead of [Bandwidth], so that their price is only driven by
+// demand for blob space.
+//
+// Like [RegisterDimension], EnableBlobs must be called from an init function.
+func EnableBlobs() Dimension {
+	blobDimension = RegisterDimension("blob")
+	return blobDimension
+}
+
+// BlobDimension returns the [Dimension] blob bytes are charged on, if blobs
+// are enabled.
+func BlobDimension() (Dimension, bool) {
+	return blobDimension, blobDimension >= 0
+}
+
+// addBlobUnits charges [blobSize] on the blob dimension of [d] instead of
+// [Bandwidth].
+func addBlobUnits(blobSize int, d *Dimensions) {
+	if blobSize == 0 {
+		return
+	}
+	d[Bandwidth] -= uint64(blobSize)
+	d[blobDimension] = uint64(blobSize)
+}
+
+// EstimateMaxBlobUnits is like [EstimateMaxUnits] but for a transaction that
+// carries [blob].
+func EstimateMaxBlobUnits(r Rules, action Action, authFactory AuthFactory, blob []byte) (Dimensions, error) {
+	d, err := EstimateMaxUnits(r, action, authFactory, nil)
+	if err != nil {
+		return Dimensions{}, err
+	}
+	if len(blob) == 0 {
+		return d, nil
+	}
+	if _, ok := BlobDimension(); !ok {
+		return Dimensions{}, ErrBlobsDisabled
+	}
+	d[blobDimension] = uint64(len(blob))
+	return d, nil
+}
+
+// BlobsRoot commits to the blobs of [txs] (in order). If none of [txs] have a
+// blob, [ids.Empty] is returned.
+func BlobsRoot(txs []*Transaction) ids.ID {
+	var hashes []byte
+	for _, tx := range txs {
+		if len(tx.Blob) == 0 {
+			continue
+		}
+		if hashes == nil {
+			hashes = make([]byte, 0, len(txs)*consts.IDLen)
+		}
+		hashes = append(hashes, hashing.ComputeHash256(tx.Blob)...)
+	}
+	if hashes == nil {
+		return ids.Empty
+	}
+	return utils.ToID(hashes)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/avalanchego/x/merkledb"
@@ -23,9 +24,10 @@ import (
 
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
 	"github.com/ava-labs/hypersdk/utils"
-	"github.com/ava-labs/hypersdk/window"
 	"github.com/ava-labs/hypersdk/workers"
 )
 
@@ -35,6 +37,11 @@ var (
 	_ block.StateSummary      = &SyncableBlock{}
 )
 
+// StatefulBlock is packed as its fields in order. [BlobsRoot] and
+// [Beneficiary] are always packed, while [Witness] and [Arrivals] are
+// trailers that are only packed if present. Nodes can't parse blocks packed
+// with fields they don't know about, so adding a field requires all nodes of
+// an existing chain to upgrade at the same time.
 type StatefulBlock struct {
 	Prnt   ids.ID `json:"parent"`
 	Tmstmp int64  `json:"timestamp"`
@@ -53,11 +60,34 @@ type StatefulBlock struct {
 	StateRoot   ids.ID     `json:"stateRoot"`
 	WarpResults set.Bits64 `json:"warpResults"`
 
+	// BlobsRoot commits to the blobs of [Txs] (see [BlobsRoot]), so that
+	// they can be verified after they are fetched from a node that stores
+	// them separately.
+	BlobsRoot ids.ID `json:"blobsRoot"`
+
+	// Beneficiary is credited with the builder's share of the fees paid in
+	// [Txs] (see [Rules.GetBuilderFeeShare]). If empty, all fees are burned.
+	Beneficiary codec.Address `json:"beneficiary"`
+
+	// Witness is the marshalled [BlockWitness] of the block. It is only
+	// included if [Rules.GetBlockWitnesses] is enabled.
+	Witness []byte `json:"witness,omitempty"`
+
+	// Arrivals commits to when the builder received each of [Txs] (in
+	// milliseconds, in the same order). It is only included by builders
+	// that order transactions by arrival (see [VM.GetFCFSOrdering]) and,
+	// when present, must not decrease.
+	Arrivals []int64 `json:"arrivals,omitempty"`
+
 	size int
 
 	// authCounts can be used by batch signature verification
 	// to preallocate memory
 	authCounts map[uint8]int
+
+	// parseTimes is how long each of [Txs] took to unmarshal (only recorded
+	// if parsing was traced)
+	parseTimes []time.Duration
 }
 
 func (b *StatefulBlock) Size() int {
@@ -65,7 +95,10 @@ func (b *StatefulBlock) Size() int {
 }
 
 func (b *StatefulBlock) ID() (ids.ID, error) {
-	blk, err := b.Marshal()
+	// The bytes are only hashed, so the buffer can be reused
+	p := codec.GetWriter(b.marshalSize(), consts.NetworkSizeLimit)
+	defer codec.PutWriter(p)
+	blk, err := b.marshal(p)
 	if err != nil {
 		return ids.ID{}, err
 	}
@@ -116,12 +149,29 @@ type StatelessBlock struct {
 	bctx         *block.Context
 	vdrState     validators.State
 
-	results    []*Result
-	feeManager *FeeManager
+	results         []*Result
+	feeManager      *FeeManager
+	burned          Dimensions
+	rewardRecipient codec.Address
+	reward          uint64
+	snapshot        *ValidatorSnapshot
+	snapshotMessage *warp.UnsignedMessage
+
+	// bundles included at the start of the block (only populated if this
+	// node built it)
+	bundles []*Bundle
+
+	// trace is only recorded if execution of the block was traced (see
+	// [BlockTrace])
+	trace *BlockTrace
 
 	vm   VM
 	view merkledb.View
 
+	// changes are the keys modified by the block (and their new values),
+	// which are updated in the [StateCache] when it is committed.
+	changes map[string]maybe.Maybe[[]byte]
+
 	sigJob workers.Job
 }
 
@@ -145,35 +195,97 @@ func ParseBlock(
 ) (*StatelessBlock, error) {
 	ctx, span := vm.Tracer().Start(ctx, "chain.ParseBlock")
 	defer span.End()
+	var pt *parseTracer
+	if shouldTrace(ctx, span) {
+		pt = &parseTracer{ctx, vm.Tracer()}
+	}
 
-	blk, err := UnmarshalBlock(source, vm)
+	// Start verifying signatures while the rest of the block is still being
+	// unmarshalled
+	blk, sigs, err := unmarshalBlock(source, vm, pt, func(height uint64, count int) (*sigVerifier, error) {
+		if !shouldPopulateTxs(vm, height) {
+			return nil, nil
+		}
+		return newSigVerifier(ctx, vm, count, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
 	// Not guaranteed that a parsed block is verified
-	return ParseStatefulBlock(ctx, blk, source, status, vm)
+	return parseStatefulBlock(ctx, blk, source, status, vm, sigs)
 }
 
-// populateTxs is only called on blocks we did not build
-func (b *StatelessBlock) populateTxs(ctx context.Context) error {
-	ctx, span := b.vm.Tracer().Start(ctx, "StatelessBlock.populateTxs")
-	defer span.End()
+// shouldPopulateTxs returns false if a block at [height] is older than the
+// last accepted block. Such blocks will not be re-executed and should not be
+// tracked as parsed blocks.
+func shouldPopulateTxs(vm VM, height uint64) bool {
+	lastAccepted := vm.LastAcceptedBlock()
+	return lastAccepted != nil && height > lastAccepted.Hght // nil when parsing genesis
+}
 
-	// Setup signature verification job
-	_, sigVerifySpan := b.vm.Tracer().Start(ctx, "StatelessBlock.verifySignatures")
-	job, err := b.vm.AuthVerifiers().NewJob(len(b.Txs))
+// sigVerifier verifies the auth of the transactions of a block
+// asynchronously.
+type sigVerifier struct {
+	vm    VM
+	job   workers.Job
+	batch *AuthBatch
+	span  oteltrace.Span
+}
+
+// newSigVerifier creates a [sigVerifier] for at most [count] transactions. If
+// [authCounts] is nil, auths are batched as they are added (see
+// [NewStreamingAuthBatch]).
+func newSigVerifier(ctx context.Context, vm VM, count int, authCounts map[uint8]int) (*sigVerifier, error) {
+	job, err := vm.AuthVerifiers().NewJob(count)
+	if err != nil {
+		return nil, err
+	}
+	_, span := vm.Tracer().Start(ctx, "StatelessBlock.verifySignatures")
+	var batch *AuthBatch
+	if authCounts == nil {
+		batch = NewStreamingAuthBatch(vm, job, count)
+	} else {
+		batch = NewAuthBatch(vm, job, authCounts)
+	}
+	return &sigVerifier{vm, job, batch, span}, nil
+}
+
+func (s *sigVerifier) add(tx *Transaction) error {
+	if !s.vm.GetVerifyAuth() {
+		return nil
+	}
+	txDigest, err := tx.Digest()
 	if err != nil {
 		return err
 	}
-	b.sigJob = job
-	batchVerifier := NewAuthBatch(b.vm, b.sigJob, b.authCounts)
+	s.batch.Add(txDigest, tx.Auth)
+	return nil
+}
 
-	// Make sure to always call [Done], otherwise we will block all future [Workers]
-	defer func() {
-		// BatchVerifier is given the responsibility to call [b.sigJob.Done()] because it may add things
-		// to the work queue async and that may not have completed by this point.
-		go batchVerifier.Done(func() { sigVerifySpan.End() })
-	}()
+// done must always be called, otherwise we will block all future [Workers].
+func (s *sigVerifier) done() {
+	// AuthBatch is given the responsibility to call [s.job.Done()] because it may add things
+	// to the work queue async and that may not have completed by this point.
+	go s.batch.Done(func() { s.span.End() })
+}
+
+// populateTxs is only called on blocks we did not build. If [sigs] is
+// provided, the auth of all transactions has already been added to it.
+func (b *StatelessBlock) populateTxs(ctx context.Context, sigs *sigVerifier) error {
+	ctx, span := b.vm.Tracer().Start(ctx, "StatelessBlock.populateTxs")
+	defer span.End()
+
+	// Setup signature verification job
+	added := sigs != nil
+	if !added {
+		var err error
+		sigs, err = newSigVerifier(ctx, b.vm, len(b.Txs), b.authCounts)
+		if err != nil {
+			return err
+		}
+	}
+	b.sigJob = sigs.job
+	defer sigs.done()
 
 	// Confirm no transaction duplicates and setup
 	// AWM processing
@@ -187,12 +299,10 @@ func (b *StatelessBlock) populateTxs(ctx context.Context) error {
 		b.txsSet.Add(tx.ID())
 
 		// Verify signature async
-		if b.vm.GetVerifyAuth() {
-			txDigest, err := tx.Digest()
-			if err != nil {
+		if !added {
+			if err := sigs.add(tx); err != nil {
 				return err
 			}
-			batchVerifier.Add(txDigest, tx.Auth)
 		}
 
 		// Check if we need the block context to verify the block (which contains
@@ -218,6 +328,9 @@ func (b *StatelessBlock) populateTxs(ctx context.Context) error {
 			b.containsWarp = true
 		}
 	}
+	if root := BlobsRoot(b.Txs); root != b.BlobsRoot {
+		return fmt.Errorf("%w: expected=%s found=%s", ErrBlobsRootMismatch, root, b.BlobsRoot)
+	}
 	return nil
 }
 
@@ -227,18 +340,37 @@ func ParseStatefulBlock(
 	source []byte,
 	status choices.Status,
 	vm VM,
+) (*StatelessBlock, error) {
+	return parseStatefulBlock(ctx, blk, source, status, vm, nil)
+}
+
+// parseStatefulBlock takes ownership of [sigs] (which is provided if [blk]
+// was unmarshalled by [unmarshalBlock]).
+func parseStatefulBlock(
+	ctx context.Context,
+	blk *StatefulBlock,
+	source []byte,
+	status choices.Status,
+	vm VM,
+	sigs *sigVerifier,
 ) (*StatelessBlock, error) {
 	ctx, span := vm.Tracer().Start(ctx, "chain.ParseStatefulBlock")
 	defer span.End()
 
 	// Perform basic correctness checks before doing any expensive work
 	if blk.Tmstmp > time.Now().Add(FutureBound).UnixMilli() {
+		if sigs != nil {
+			sigs.done()
+		}
 		return nil, ErrTimestampTooLate
 	}
 
 	if len(source) == 0 {
 		nsource, err := blk.Marshal()
 		if err != nil {
+			if sigs != nil {
+				sigs.done()
+			}
 			return nil, err
 		}
 		source = nsource
@@ -254,13 +386,15 @@ func ParseStatefulBlock(
 
 	// If we are parsing an older block, it will not be re-executed and should
 	// not be tracked as a parsed block
-	lastAccepted := b.vm.LastAcceptedBlock()
-	if lastAccepted == nil || b.Hght <= lastAccepted.Hght { // nil when parsing genesis
+	if !shouldPopulateTxs(vm, b.Hght) {
+		if sigs != nil {
+			sigs.done()
+		}
 		return b, nil
 	}
 
 	// Populate hashes and tx set
-	return b, b.populateTxs(ctx)
+	return b, b.populateTxs(ctx, sigs)
 }
 
 // [initializeBuilt] is invoked after a block is built
@@ -298,7 +432,7 @@ func (b *StatelessBlock) ID() ids.ID { return b.id }
 
 // implements "block.WithVerifyContext"
 func (b *StatelessBlock) ShouldVerifyWithContext(context.Context) (bool, error) {
-	return b.containsWarp, nil
+	return b.containsWarp || snapshotsValidators(b.vm.Rules(b.Tmstmp), b.Hght), nil
 }
 
 // implements "block.WithVerifyContext"
@@ -351,8 +485,22 @@ func (b *StatelessBlock) Verify(ctx context.Context) error {
 }
 
 func (b *StatelessBlock) verify(ctx context.Context, stateReady bool) error {
-	log := b.vm.Logger()
+	log := b.vm.ModuleLogger(logs.Chain)
 	switch {
+	case !stateReady && b.vm.Rules(b.Tmstmp).GetBlockWitnesses():
+		// If the state of the accepted tip has not been fully fetched, we can
+		// still execute the block against its witness.
+		if _, err := b.ExecuteWitness(ctx); err != nil {
+			log.Warn("witness verification failed",
+				zap.Uint64("height", b.Hght),
+				zap.Stringer("blkID", b.ID()),
+				zap.Error(err),
+			)
+			return err
+		}
+		if err := b.sigJob.Wait(); err != nil {
+			return err
+		}
 	case !stateReady:
 		// If the state of the accepted tip has not been fully fetched, it is not safe to
 		// verify any block.
@@ -378,7 +526,7 @@ func (b *StatelessBlock) verify(ctx context.Context, stateReady bool) error {
 		// context. Otherwise, the parent block will be used as the execution context.
 		vctx, err := b.vm.GetVerifyContext(ctx, b.Hght, b.Prnt)
 		if err != nil {
-			b.vm.Logger().Warn("unable to get verify context",
+			b.vm.ModuleLogger(logs.Chain).Warn("unable to get verify context",
 				zap.Uint64("height", b.Hght),
 				zap.Stringer("blkID", b.ID()),
 				zap.Error(err),
@@ -389,7 +537,7 @@ func (b *StatelessBlock) verify(ctx context.Context, stateReady bool) error {
 		// Parent block may not be processed when we verify this block, so [innerVerify] may
 		// recursively verify ancestry.
 		if err := b.innerVerify(ctx, vctx); err != nil {
-			b.vm.Logger().Warn("verification failed",
+			b.vm.ModuleLogger(logs.Chain).Warn("verification failed",
 				zap.Uint64("height", b.Hght),
 				zap.Stringer("blkID", b.ID()),
 				zap.Error(err),
@@ -413,10 +561,15 @@ func (b *StatelessBlock) verifyWarpMessage(ctx context.Context, r Rules, msg *wa
 	// itself a message to trigger a chain upgrade.
 	allowed, num, denom := r.GetWarpConfig(msg.SourceChainID)
 	if !allowed {
-		b.vm.Logger().
+		b.vm.ModuleLogger(logs.Chain).
 			Warn("unable to verify warp message", zap.Stringer("warpID", msg.ID()), zap.Error(ErrDisabledChainID))
 		return false
 	}
+	if err := VerifyWarpQuorum(num, denom); err != nil {
+		b.vm.ModuleLogger(logs.Chain).
+			Warn("unable to verify warp message", zap.Stringer("warpID", msg.ID()), zap.Error(err))
+		return false
+	}
 	if err := msg.Signature.Verify(
 		ctx,
 		&msg.UnsignedMessage,
@@ -426,7 +579,7 @@ func (b *StatelessBlock) verifyWarpMessage(ctx context.Context, r Rules, msg *wa
 		num,
 		denom,
 	); err != nil {
-		b.vm.Logger().
+		b.vm.ModuleLogger(logs.Chain).
 			Warn("unable to verify warp message", zap.Stringer("warpID", msg.ID()), zap.Error(err))
 		return false
 	}
@@ -447,7 +600,7 @@ func (b *StatelessBlock) verifyWarpMessage(ctx context.Context, r Rules, msg *wa
 //     state sync)
 func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) error {
 	var (
-		log = b.vm.Logger()
+		log = b.vm.ModuleLogger(logs.Chain)
 		r   = b.vm.Rules(b.Tmstmp)
 	)
 
@@ -455,6 +608,9 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	if b.Timestamp().UnixMilli() > time.Now().Add(FutureBound).UnixMilli() {
 		return ErrTimestampTooLate
 	}
+	if err := verifyArrivals(b.Tmstmp, b.Arrivals); err != nil {
+		return err
+	}
 
 	// Fetch view where we will apply block state transitions
 	//
@@ -464,6 +620,26 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 		return fmt.Errorf("%w: unable to load parent view", err)
 	}
 
+	// Record all keys read during execution so that we can ensure they are
+	// covered by the witness
+	var recorder *witnessRecorder
+	switch {
+	case r.GetBlockWitnesses() && len(b.Witness) == 0:
+		return ErrMissingWitness
+	case r.GetBlockWitnesses():
+		recorder = newWitnessRecorder(parentView)
+		parentView = recorder
+	case len(b.Witness) > 0:
+		return ErrUnexpectedWitness
+	}
+
+	// Apply any overrides written by our ancestors (which can't change
+	// whether witnesses are required)
+	r, err = StateRules(ctx, b.vm, b.vm.StateManager(), parentView, b.Tmstmp)
+	if err != nil {
+		return err
+	}
+
 	// Fetch parent height key and ensure block height is valid
 	heightKey := HeightKey(b.vm.StateManager().HeightKey())
 	parentHeightRaw, err := parentView.GetValue(ctx, heightKey)
@@ -505,7 +681,7 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 			// Can occur if verifying genesis
 			oldestAllowed = 0
 		}
-		dup, err := vctx.IsRepeat(ctx, oldestAllowed, b.Txs, set.NewBits(), true)
+		dup, err := vctx.IsRepeat(ctx, oldestAllowed, OldestAllowedHeight(r, b.Hght), b.Txs, set.NewBits(), true)
 		if err != nil {
 			return err
 		}
@@ -568,59 +744,97 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	}
 
 	// Compute next unit prices to use
-	feeKey := FeeKey(b.vm.StateManager().FeeKey())
-	feeRaw, err := parentView.GetValue(ctx, feeKey)
+	parentFeeManager, legacyFee, err := GetFeeManager(ctx, b.vm.StateManager(), parentView)
 	if err != nil {
 		return err
 	}
-	parentFeeManager := NewFeeManager(feeRaw)
 	feeManager, err := parentFeeManager.ComputeNext(parentTimestamp, b.Tmstmp, r)
 	if err != nil {
 		return err
 	}
 
 	// Process transactions
-	results, ts, err := b.Execute(ctx, b.vm.Tracer(), parentView, feeManager, r)
+	var (
+		results []*Result
+		ts      *tstate.TState
+		et      *ExecutionTrace
+	)
+	if b.vm.GetExecutionTrace() {
+		results, ts, et, err = b.ExecuteWithTrace(ctx, b.vm.Tracer(), parentView, feeManager, r)
+	} else {
+		results, ts, err = b.Execute(ctx, b.vm.Tracer(), parentView, feeManager, r)
+	}
 	if err != nil {
 		log.Error("failed to execute block", zap.Error(err))
 		return err
 	}
+	if et != nil {
+		b.checkDeterminism(ctx, parentView, parentFeeManager, parentTimestamp, r, et)
+	}
 	b.results = results
 	b.feeManager = feeManager
 
+	// Run the epoch transition (if this block ends an epoch)
+	if err := endEpoch(ctx, b.vm.EpochHandler(), r, parentView, ts, b.Tmstmp, b.Hght); err != nil {
+		return err
+	}
+	if snapshotsValidators(r, b.Hght) {
+		if b.bctx == nil {
+			log.Error(
+				"missing verify block context",
+				zap.Uint64("height", b.Hght),
+				zap.Stringer("id", b.ID()),
+			)
+			return ErrMissingBlockContext
+		}
+		b.snapshot, b.snapshotMessage, err = storeValidatorSnapshot(
+			ctx, r, b.vm.StateManager(), b.vm.ValidatorState(), ts, b.Hght, b.bctx.PChainHeight,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Credit the builder's share of fees and burn the rest
+	burned, err := distributeFees(ctx, r, b.vm.StateManager(), parentView, ts, feeManager.UnitPrices(), b.Beneficiary, results)
+	if err != nil {
+		return err
+	}
+	b.burned = burned
+
+	// Mint the block reward
+	b.rewardRecipient, b.reward, err = mintBlockReward(ctx, r, b.vm.StateManager(), parentView, ts, b.Hght, b.Beneficiary)
+	if err != nil {
+		return err
+	}
+
+	// Prune the records of warp messages that can no longer be imported
+	if err := pruneWarpReplays(ctx, r, b.vm.StateManager(), parentView, ts, b.Tmstmp, b.Txs, results); err != nil {
+		return err
+	}
+
 	// Ensure warp results are correct
 	if invalidWarpResult {
 		return ErrWarpResultMismatch
 	}
-	numWarp := len(b.warpMessages)
-	if numWarp > MaxWarpMessages {
-		return ErrTooManyWarpMessages
-	}
-	var warpResultsLimit set.Bits64
-	warpResultsLimit.Add(uint(numWarp))
-	if b.WarpResults >= warpResultsLimit {
-		// If the value of [WarpResults] is greater than the value of uint64 with
-		// a 1-bit shifted [numWarp] times, then there are unused bits set to
-		// 1 (which should is not allowed).
-		return ErrWarpResultMismatch
+	if err := b.checkWarpResults(); err != nil {
+		return err
 	}
 
 	// Update chain metadata
 	heightKeyStr := string(heightKey)
 	timestampKeyStr := string(timestampKey)
-	feeKeyStr := string(feeKey)
-	tsv := ts.NewView(set.Of(heightKeyStr, timestampKeyStr, feeKeyStr), map[string][]byte{
+	tsv := newMetadataView(ts, b.vm.StateManager(), map[string][]byte{
 		heightKeyStr:    parentHeightRaw,
 		timestampKeyStr: parentTimestampRaw,
-		feeKeyStr:       parentFeeManager.Bytes(),
-	})
+	}, parentFeeManager, legacyFee)
 	if err := tsv.Insert(ctx, heightKey, binary.BigEndian.AppendUint64(nil, b.Hght)); err != nil {
 		return err
 	}
 	if err := tsv.Insert(ctx, timestampKey, binary.BigEndian.AppendUint64(nil, uint64(b.Tmstmp))); err != nil {
 		return err
 	}
-	if err := tsv.Insert(ctx, feeKey, feeManager.Bytes()); err != nil {
+	if err := storeFeeManager(ctx, tsv, b.vm.StateManager(), feeManager, legacyFee); err != nil {
 		return err
 	}
 	tsv.Commit()
@@ -646,6 +860,20 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 		)
 	}
 
+	// Ensure the witness proves every key we read (now that we know
+	// [b.StateRoot] is correct)
+	if recorder != nil {
+		ws, err := b.VerifyWitness(ctx, b.vm.GetStateBranchFactor())
+		if err != nil {
+			return err
+		}
+		for _, k := range recorder.Keys() {
+			if !ws.Contains(k) {
+				return fmt.Errorf("%w: %x", ErrIncompleteWitness, k)
+			}
+		}
+	}
+
 	// Ensure signatures are verified
 	_, sspan := b.vm.Tracer().Start(ctx, "StatelessBlock.Verify.WaitSignatures")
 	start = time.Now()
@@ -659,6 +887,7 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	// Get view from [tstate] after processing all state transitions
 	b.vm.RecordStateChanges(ts.PendingChanges())
 	b.vm.RecordStateOperations(ts.OpIndex())
+	b.changes = ts.Changes()
 	view, err := ts.ExportMerkleDBView(ctx, b.vm.Tracer(), parentView)
 	if err != nil {
 		return err
@@ -683,6 +912,67 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	return nil
 }
 
+// checkWarpResults ensures [WarpResults] has no bits set beyond the warp
+// messages in the block.
+func (b *StatelessBlock) checkWarpResults() error {
+	numWarp := len(b.warpMessages)
+	if numWarp > MaxWarpMessages {
+		return ErrTooManyWarpMessages
+	}
+	var warpResultsLimit set.Bits64
+	warpResultsLimit.Add(uint(numWarp))
+	if b.WarpResults >= warpResultsLimit {
+		// If the value of [WarpResults] is greater than the value of uint64 with
+		// a 1-bit shifted [numWarp] times, then there are unused bits set to
+		// 1 (which should is not allowed).
+		return ErrWarpResultMismatch
+	}
+	return nil
+}
+
+// checkDeterminism re-executes the block sequentially and logs every
+// difference from the parallel execution recorded in [et]. Divergence does
+// not fail verification (the parallel result is still used), as this is only
+// meant to help debug custom actions.
+func (b *StatelessBlock) checkDeterminism(
+	ctx context.Context,
+	parentView state.View,
+	parentFeeManager *FeeManager,
+	parentTimestamp int64,
+	r Rules,
+	et *ExecutionTrace,
+) {
+	log := b.vm.ModuleLogger(logs.Chain)
+	feeManager, err := parentFeeManager.ComputeNext(parentTimestamp, b.Tmstmp, r)
+	if err != nil {
+		log.Warn("unable to compute fees for sequential replay", zap.Error(err))
+		return
+	}
+	sequential, err := b.Replay(ctx, b.vm.Tracer(), parentView, feeManager, r, et)
+	if err != nil {
+		log.Error("sequential replay failed",
+			zap.Stringer("blkID", b.ID()),
+			zap.Uint64("height", b.Hght),
+			zap.Error(err),
+		)
+		return
+	}
+	diffs := et.Diff(sequential)
+	if len(diffs) == 0 {
+		log.Debug("parallel execution matches sequential replay",
+			zap.Stringer("blkID", b.ID()),
+			zap.Uint64("height", b.Hght),
+		)
+		return
+	}
+	log.Error("parallel execution diverged from sequential replay",
+		zap.Stringer("blkID", b.ID()),
+		zap.Uint64("height", b.Hght),
+		zap.Ints("order", et.Order),
+		zap.Strings("diffs", diffs),
+	)
+}
+
 // implements "snowman.Block.choices.Decidable"
 func (b *StatelessBlock) Accept(ctx context.Context) error {
 	start := time.Now()
@@ -704,10 +994,15 @@ func (b *StatelessBlock) Accept(ctx context.Context) error {
 			return err
 		}
 		if updated {
-			b.vm.Logger().Info("updated state sync target",
+			b.vm.ModuleLogger(logs.Chain).Info("updated state sync target",
 				zap.Stringer("id", b.ID()),
 				zap.Stringer("root", b.StateRoot),
 			)
+
+			// The block is accepted (without state) so that it is persisted and
+			// its transactions count towards [ValidityWindow]. Once the sync to
+			// this root completes, there are no blocks left to execute.
+			b.MarkAccepted(ctx)
 			return nil // the sync is still ongoing
 		}
 
@@ -717,7 +1012,7 @@ func (b *StatelessBlock) Accept(ctx context.Context) error {
 		//
 		// If state sync completes before accept is called
 		// then we need to process it here.
-		b.vm.Logger().Info("verifying unprocessed block in accept",
+		b.vm.ModuleLogger(logs.Chain).Info("verifying unprocessed block in accept",
 			zap.Stringer("id", b.ID()),
 			zap.Stringer("root", b.StateRoot),
 		)
@@ -732,7 +1027,7 @@ func (b *StatelessBlock) Accept(ctx context.Context) error {
 
 	// Commit view if we don't return before here (would happen if we are still
 	// syncing)
-	if err := b.view.CommitToDB(ctx); err != nil {
+	if err := b.commit(ctx); err != nil {
 		return fmt.Errorf("%w: unable to commit block", err)
 	}
 
@@ -769,6 +1064,10 @@ func (b *StatelessBlock) Status() choices.Status { return b.st }
 // implements "snowman.Block"
 func (b *StatelessBlock) Parent() ids.ID { return b.StatefulBlock.Prnt }
 
+// Seed is the pseudo-random seed available to actions executed in the block
+// (see [NewBlockSeed]).
+func (b *StatelessBlock) Seed() ids.ID { return NewBlockSeed(b.Prnt, b.Hght) }
+
 // implements "snowman.Block"
 func (b *StatelessBlock) Bytes() []byte { return b.bytes }
 
@@ -818,7 +1117,7 @@ func (b *StatelessBlock) View(ctx context.Context, verify bool) (state.View, err
 			// block was accepted (this is not obvious because
 			// the accepted state may be that of the parent of the last
 			// accepted block right after state sync finishes).
-			return b.vm.State()
+			return b.acceptedState()
 		}
 		return b.view, nil
 	}
@@ -843,20 +1142,20 @@ func (b *StatelessBlock) View(ctx context.Context, verify bool) (state.View, err
 		}
 		acceptedHeight := binary.BigEndian.Uint64(acceptedHeightRaw)
 		if acceptedHeight == b.Hght {
-			b.vm.Logger().Info("accepted block not processed but found post-execution state on-disk",
+			b.vm.ModuleLogger(logs.Chain).Info("accepted block not processed but found post-execution state on-disk",
 				zap.Uint64("height", b.Hght),
 				zap.Stringer("blkID", b.ID()),
 				zap.Bool("verify", verify),
 			)
-			return acceptedState, nil
+			return b.acceptedState()
 		}
-		b.vm.Logger().Info("accepted block not processed and does not match state on-disk",
+		b.vm.ModuleLogger(logs.Chain).Info("accepted block not processed and does not match state on-disk",
 			zap.Uint64("height", b.Hght),
 			zap.Stringer("blkID", b.ID()),
 			zap.Bool("verify", verify),
 		)
 	} else {
-		b.vm.Logger().Info("block not processed",
+		b.vm.ModuleLogger(logs.Chain).Info("block not processed",
 			zap.Uint64("height", b.Hght),
 			zap.Stringer("blkID", b.ID()),
 			zap.Bool("verify", verify),
@@ -874,18 +1173,18 @@ func (b *StatelessBlock) View(ctx context.Context, verify bool) (state.View, err
 	// and [acceptedState] will correspond to the post-execution state
 	// of the new block's grandparent (our parent). To remedy this,
 	// we need to process this block to return a valid view.
-	b.vm.Logger().Info("verifying block when view requested",
+	b.vm.ModuleLogger(logs.Chain).Info("verifying block when view requested",
 		zap.Uint64("height", b.Hght),
 		zap.Stringer("blkID", b.ID()),
 		zap.Bool("accepted", b.st == choices.Accepted),
 	)
 	vctx, err := b.vm.GetVerifyContext(ctx, b.Hght, b.Prnt)
 	if err != nil {
-		b.vm.Logger().Error("unable to get verify context", zap.Error(err))
+		b.vm.ModuleLogger(logs.Chain).Error("unable to get verify context", zap.Error(err))
 		return nil, err
 	}
 	if err := b.innerVerify(ctx, vctx); err != nil {
-		b.vm.Logger().Error("unable to verify block", zap.Error(err))
+		b.vm.ModuleLogger(logs.Chain).Error("unable to verify block", zap.Error(err))
 		return nil, err
 	}
 	if b.st != choices.Accepted {
@@ -900,21 +1199,47 @@ func (b *StatelessBlock) View(ctx context.Context, verify bool) (state.View, err
 	// It is not possible to reach this function if this block
 	// is not the child of the block whose post-execution state
 	// is currently stored on disk, so it is safe to call [CommitToDB].
+	if err := b.commit(ctx); err != nil {
+		b.vm.ModuleLogger(logs.Chain).Error("unable to commit to DB", zap.Error(err))
+		return nil, err
+	}
+	return b.acceptedState()
+}
+
+// commit writes the changes of [b] to the accepted state.
+func (b *StatelessBlock) commit(ctx context.Context) error {
 	if err := b.view.CommitToDB(ctx); err != nil {
-		b.vm.Logger().Error("unable to commit to DB", zap.Error(err))
+		return err
+	}
+	if c := b.vm.StateCache(); c != nil {
+		c.Update(b.changes)
+	}
+	return nil
+}
+
+// acceptedState returns the accepted state (read through the [StateCache], if
+// there is one).
+func (b *StatelessBlock) acceptedState() (state.View, error) {
+	db, err := b.vm.State()
+	if err != nil {
 		return nil, err
 	}
-	return b.vm.State()
+	if c := b.vm.StateCache(); c != nil {
+		return c.View(db), nil
+	}
+	return db, nil
 }
 
 // IsRepeat returns a bitset of all transactions that are considered repeats in
-// the range that spans back to [oldestAllowed].
+// the range that spans back to [oldestAllowed] and [oldestAllowedHeight] (see
+// [OldestAllowedHeight]).
 //
 // If [stop] is set to true, IsRepeat will return as soon as the first repeat
 // is found (useful for block verification).
 func (b *StatelessBlock) IsRepeat(
 	ctx context.Context,
 	oldestAllowed int64,
+	oldestAllowedHeight uint64,
 	txs []*Transaction,
 	marker set.Bits,
 	stop bool,
@@ -922,11 +1247,12 @@ func (b *StatelessBlock) IsRepeat(
 	ctx, span := b.vm.Tracer().Start(ctx, "StatelessBlock.IsRepeat")
 	defer span.End()
 
-	// Early exit if we are already back at least [ValidityWindow]
+	// Early exit if we are already back at least [ValidityWindow] and
+	// [HeightValidityWindow]
 	//
 	// It is critical to ensure this logic is equivalent to [emap] to avoid
 	// non-deterministic verification.
-	if b.Tmstmp < oldestAllowed {
+	if b.Tmstmp < oldestAllowed && b.Hght < oldestAllowedHeight {
 		return marker, nil
 	}
 
@@ -952,7 +1278,18 @@ func (b *StatelessBlock) IsRepeat(
 	if err != nil {
 		return marker, err
 	}
-	return prnt.IsRepeat(ctx, oldestAllowed, txs, marker, stop)
+	return prnt.IsRepeat(ctx, oldestAllowed, oldestAllowedHeight, txs, marker, stop)
+}
+
+// OldestAllowedHeight returns the height of the oldest block that could
+// include a transaction that is valid at [height] (transactions can't expire
+// more than [Rules.GetHeightValidityWindow] blocks ahead).
+func OldestAllowedHeight(r Rules, height uint64) uint64 {
+	window := r.GetHeightValidityWindow()
+	if window >= height {
+		return 0
+	}
+	return height - window
 }
 
 func (b *StatelessBlock) GetTxs() []*Transaction {
@@ -967,18 +1304,189 @@ func (b *StatelessBlock) Results() []*Result {
 	return b.results
 }
 
+// Trace returns the [BlockTrace] of the execution of the block, if it was
+// traced.
+func (b *StatelessBlock) Trace() *BlockTrace {
+	return b.trace
+}
+
+// Changes returns the keys modified by the block (and their new values). It
+// is nil if the block was not executed by this node (like blocks accepted
+// during state sync).
+func (b *StatelessBlock) Changes() map[string]maybe.Maybe[[]byte] {
+	return b.changes
+}
+
 func (b *StatelessBlock) FeeManager() *FeeManager {
 	return b.feeManager
 }
 
+// Burned is the amount of fees burned in each dimension by the block. It is
+// only populated if the block was executed by this node.
+func (b *StatelessBlock) Burned() Dimensions {
+	return b.burned
+}
+
+// Reward is the recipient and amount of the block reward minted by the block
+// (see [Rules.GetBlockReward]). Like [Burned], it is only populated if the
+// block was executed by this node.
+func (b *StatelessBlock) Reward() (codec.Address, uint64) {
+	return b.rewardRecipient, b.reward
+}
+
+// Bundles returns the [Bundle]s included at the start of the block, if it
+// was built by this node.
+func (b *StatelessBlock) Bundles() []*Bundle {
+	return b.bundles
+}
+
+// ValidatorSnapshot is the [ValidatorSnapshot] taken by the block and the
+// warp message attesting to it (see [Rules.GetValidatorSnapshots]). Like
+// [Burned], it is only populated if the block was executed by this node.
+func (b *StatelessBlock) ValidatorSnapshot() (*ValidatorSnapshot, *warp.UnsignedMessage) {
+	return b.snapshot, b.snapshotMessage
+}
+
+// VerifyWitness verifies [Witness] against [StateRoot] and returns the
+// values it proves (see [BlockWitness]).
+func (b *StatefulBlock) VerifyWitness(
+	ctx context.Context,
+	branchFactor merkledb.BranchFactor,
+) (*WitnessState, error) {
+	if len(b.Witness) == 0 {
+		return nil, ErrMissingWitness
+	}
+	w, err := UnmarshalBlockWitness(b.Witness)
+	if err != nil {
+		return nil, err
+	}
+	return w.Verify(ctx, b.StateRoot, branchFactor)
+}
+
+// ExecuteWitness executes the block against the [WitnessState] proven by
+// [Witness] instead of the state of its parent, so it can be run by a node
+// with no copy of state. Execution fails with [ErrKeyNotWitnessed] if it reads
+// a key the witness does not prove.
+//
+// The witness is only checked against the [StateRoot] claimed by the block and
+// the post-execution state root is only committed to by its child, so the
+// changes made by the block are not checked. Replay protection (which requires
+// the transactions of the ancestry of the block) is skipped and warp messages
+// are assumed to have the results recorded in [WarpResults].
+func (b *StatelessBlock) ExecuteWitness(ctx context.Context) ([]*Result, error) {
+	ctx, span := b.vm.Tracer().Start(ctx, "StatelessBlock.ExecuteWitness")
+	defer span.End()
+
+	r := b.vm.Rules(b.Tmstmp)
+	if !r.GetBlockWitnesses() {
+		return nil, ErrUnexpectedWitness
+	}
+	ws, err := b.VerifyWitness(ctx, b.vm.GetStateBranchFactor())
+	if err != nil {
+		return nil, err
+	}
+	sm := b.vm.StateManager()
+	r, err = StateRules(ctx, b.vm, sm, ws, b.Tmstmp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure block height and timestamp are valid
+	parentHeightRaw, err := ws.GetValue(ctx, HeightKey(sm.HeightKey()))
+	if err != nil {
+		return nil, err
+	}
+	if b.Hght != binary.BigEndian.Uint64(parentHeightRaw)+1 {
+		return nil, ErrInvalidBlockHeight
+	}
+	parentTimestampRaw, err := ws.GetValue(ctx, TimestampKey(sm.TimestampKey()))
+	if err != nil {
+		return nil, err
+	}
+	parentTimestamp := int64(binary.BigEndian.Uint64(parentTimestampRaw))
+	if b.Tmstmp < parentTimestamp+r.GetMinBlockGap() {
+		return nil, ErrTimestampTooEarly
+	}
+	if len(b.Txs) == 0 && b.Tmstmp < parentTimestamp+r.GetMinEmptyBlockGap() {
+		return nil, ErrTimestampTooEarly
+	}
+	if err := b.checkWarpResults(); err != nil {
+		return nil, err
+	}
+
+	// Compute next unit prices to use
+	parentFeeManager, _, err := GetFeeManager(ctx, sm, ws)
+	if err != nil {
+		return nil, err
+	}
+	feeManager, err := parentFeeManager.ComputeNext(parentTimestamp, b.Tmstmp, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process transactions sequentially using the warp results in the block
+	warpResults := newExecutionTrace(b, true)
+	for i, tx := range b.Txs {
+		if msg, ok := b.warpMessages[tx.ID()]; ok {
+			warpResults.Txs[i] = &TxTrace{ID: tx.ID(), WarpVerified: b.WarpResults.Contains(uint(msg.warpNum))}
+		}
+	}
+	results, ts, err := b.execute(ctx, b.vm.Tracer(), ws, feeManager, r, nil, warpResults)
+	if err != nil {
+		return nil, err
+	}
+	if err := endEpoch(ctx, b.vm.EpochHandler(), r, ws, ts, b.Tmstmp, b.Hght); err != nil {
+		return nil, err
+	}
+	if _, err := distributeFees(ctx, r, sm, ws, ts, feeManager.UnitPrices(), b.Beneficiary, results); err != nil {
+		return nil, err
+	}
+	if _, _, err := mintBlockReward(ctx, r, sm, ws, ts, b.Hght, b.Beneficiary); err != nil {
+		return nil, err
+	}
+	if err := pruneWarpReplays(ctx, r, sm, ws, ts, b.Tmstmp, b.Txs, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// blockHeaderSize is the size of the fields of a [StatefulBlock] that are
+// always marshalled (everything but [Txs], [Witness], and [Arrivals]).
+const blockHeaderSize = consts.IDLen + consts.Int64Len + consts.Uint64Len + // Prnt, Tmstmp, Hght
+	consts.IntLen + // len(Txs)
+	consts.IDLen + consts.Uint64Len + consts.IDLen + codec.AddressLen // StateRoot, WarpResults, BlobsRoot, Beneficiary
+
+// marshalSize is the size of [b] when marshalled (if all of its transactions
+// were unmarshalled or signed).
+func (b *StatefulBlock) marshalSize() int {
+	return blockHeaderSize + codec.CummSize(b.Txs) + trailerSize(b.Witness, b.Arrivals)
+}
+
+func trailerSize(witness []byte, arrivals []int64) int {
+	if len(arrivals) > 0 {
+		return codec.BytesLen(witness) + consts.IntLen + len(arrivals)*consts.Int64Len
+	}
+	if len(witness) == 0 {
+		return 0
+	}
+	return codec.BytesLen(witness)
+}
+
+// Marshal returns the bytes of [b], which are kept by the caller (use
+// [StatefulBlock.ID] to hash [b] without allocating them and
+// [StatefulBlock.AppendMarshal] to copy them into another buffer).
 func (b *StatefulBlock) Marshal() ([]byte, error) {
-	size := consts.IDLen + consts.Uint64Len + consts.Uint64Len +
-		consts.Uint64Len + window.WindowSliceSize +
-		consts.IntLen + codec.CummSize(b.Txs) +
-		consts.IDLen + consts.Uint64Len + consts.Uint64Len
+	return b.marshal(codec.NewWriter(b.marshalSize(), consts.NetworkSizeLimit))
+}
 
-	p := codec.NewWriter(size, consts.NetworkSizeLimit)
+// AppendMarshal appends the bytes of [b] to [dst], only allocating if [dst]
+// doesn't have enough spare capacity.
+func (b *StatefulBlock) AppendMarshal(dst []byte) ([]byte, error) {
+	return b.marshal(codec.NewAppendWriter(dst, len(dst)+consts.NetworkSizeLimit))
+}
 
+func (b *StatefulBlock) marshal(p *codec.Packer) ([]byte, error) {
+	start := p.Offset()
 	p.PackID(b.Prnt)
 	p.PackInt64(b.Tmstmp)
 	p.PackUint64(b.Hght)
@@ -994,15 +1502,48 @@ func (b *StatefulBlock) Marshal() ([]byte, error) {
 
 	p.PackID(b.StateRoot)
 	p.PackUint64(uint64(b.WarpResults))
+	p.PackID(b.BlobsRoot)
+	p.PackFixedBytes(b.Beneficiary[:])
+	if len(b.Witness) > 0 || len(b.Arrivals) > 0 {
+		// Omitted when empty so that blocks without witnesses don't grow (an
+		// empty witness is packed to mark the start of [Arrivals])
+		p.PackBytes(b.Witness)
+	}
+	if len(b.Arrivals) > 0 {
+		p.PackInt(len(b.Arrivals))
+		for _, arrival := range b.Arrivals {
+			p.PackInt64(arrival)
+		}
+	}
 	bytes := p.Bytes()
 	if err := p.Err(); err != nil {
 		return nil, err
 	}
-	b.size = len(bytes)
+	b.size = len(bytes) - start
 	return bytes, nil
 }
 
 func UnmarshalBlock(raw []byte, parser Parser) (*StatefulBlock, error) {
+	blk, _, err := unmarshalBlock(raw, parser, nil, nil)
+	return blk, err
+}
+
+// minTxSize is the size of a [Transaction] with an empty [Action] and [Auth].
+const minTxSize = BaseSize + consts.IntLen + consts.ByteLen + consts.ByteLen
+
+// unmarshalBlock calls [verify] (if provided) once the height and
+// number of transactions of the block are known. If it returns a
+// [sigVerifier], the auth of each transaction is added to it as soon as the
+// transaction is unmarshalled. If an error is returned, [done] has already
+// been called on the [sigVerifier].
+//
+// If [pt] is provided, the parsing of each transaction is traced.
+func unmarshalBlock(
+	raw []byte,
+	parser Parser,
+	pt *parseTracer,
+	verify func(height uint64, count int) (*sigVerifier, error),
+) (blk *StatefulBlock, sigs *sigVerifier, err error) {
 	var (
 		p = codec.NewReader(raw, consts.NetworkSizeLimit)
 		b StatefulBlock
@@ -1015,13 +1556,46 @@ func UnmarshalBlock(raw []byte, parser Parser) (*StatefulBlock, error) {
 
 	// Parse transactions
 	txCount := p.UnpackInt(false) // can produce empty blocks
+	if verify != nil && p.Err() == nil {
+		// [txCount] is untrusted, so we bound it by the number of transactions
+		// that could fit in the rest of the block
+		count := txCount
+		if maxCount := (len(raw) - p.Offset()) / minTxSize; count > maxCount {
+			count = maxCount
+		}
+		sigs, err = verify(b.Hght, count)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sigs != nil {
+			defer func() {
+				if err != nil {
+					sigs.done()
+					sigs = nil
+				}
+			}()
+		}
+	}
 	actionRegistry, authRegistry := parser.Registry()
 	b.Txs = []*Transaction{} // don't preallocate all to avoid DoS
 	b.authCounts = map[uint8]int{}
+	if pt != nil {
+		b.parseTimes = []time.Duration{}
+	}
 	for i := 0; i < txCount; i++ {
-		tx, err := UnmarshalTx(p, actionRegistry, authRegistry)
+		tx, elapsed, err := pt.parseTx(func() (*Transaction, error) {
+			return UnmarshalTx(p, actionRegistry, authRegistry)
+		})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if pt != nil {
+			b.parseTimes = append(b.parseTimes, elapsed)
+		}
+		if sigs != nil {
+			if err := sigs.add(tx); err != nil {
+				return nil, nil, err
+			}
 		}
 		b.Txs = append(b.Txs, tx)
 		b.authCounts[tx.Auth.GetTypeID()]++
@@ -1029,12 +1603,38 @@ func UnmarshalBlock(raw []byte, parser Parser) (*StatefulBlock, error) {
 
 	p.UnpackID(false, &b.StateRoot)
 	b.WarpResults = set.Bits64(p.UnpackUint64(false))
+	p.UnpackID(false, &b.BlobsRoot)
+	beneficiary := b.Beneficiary[:]
+	p.UnpackFixedBytes(codec.AddressLen, &beneficiary) // may be empty
+	if !p.Empty() {
+		p.UnpackBytes(consts.NetworkSizeLimit, false, &b.Witness)
+		if len(b.Witness) == 0 {
+			// An empty witness is only packed before [Arrivals]
+			b.Witness = nil
+			if p.Empty() {
+				return nil, nil, fmt.Errorf("%w: empty witness", ErrInvalidObject)
+			}
+		}
+	}
+	if !p.Empty() && p.Err() == nil {
+		count := p.UnpackInt(true)
+		if count != len(b.Txs) || len(raw)-p.Offset() < count*consts.Int64Len {
+			return nil, nil, fmt.Errorf("%w: arrivals=%d txs=%d", ErrInvalidArrivals, count, len(b.Txs))
+		}
+		b.Arrivals = make([]int64, count)
+		for i := range b.Arrivals {
+			b.Arrivals[i] = p.UnpackInt64(false)
+		}
+	}
 
 	// Ensure no leftover bytes
 	if !p.Empty() {
-		return nil, fmt.Errorf("%w: remaining=%d", ErrInvalidObject, len(raw)-p.Offset())
+		return nil, nil, fmt.Errorf("%w: remaining=%d", ErrInvalidObject, len(raw)-p.Offset())
+	}
+	if err := p.Err(); err != nil {
+		return nil, nil, err
 	}
-	return &b, p.Err()
+	return &b, sigs, nil
 }
 
 type SyncableBlock struct {
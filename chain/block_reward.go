@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// HalvingReward is a schedule for [Rules.GetBlockReward] that starts at
+// [initial] and halves every [interval] blocks. If [interval] is 0, the
+// reward never halves.
+func HalvingReward(initial uint64, interval uint64, height uint64) uint64 {
+	if interval == 0 {
+		return initial
+	}
+	halvings := height / interval
+	if halvings >= 64 {
+		return 0
+	}
+	return initial >> halvings
+}
+
+// mintBlockReward mints the reward of the block at [height] (see
+// [Rules.GetBlockReward]) to [beneficiary] (or the reward pool if
+// [beneficiary] is empty).
+//
+// [im] must be the state that [ts] was executed on, so that the value of
+// any keys not modified by [ts] can be read.
+//
+// mintBlockReward returns the recipient of the reward and the amount minted.
+func mintBlockReward(
+	ctx context.Context,
+	r Rules,
+	sm StateManager,
+	im state.Immutable,
+	ts *tstate.TState,
+	height uint64,
+	beneficiary codec.Address,
+) (codec.Address, uint64, error) {
+	recipient := beneficiary
+	if recipient == codec.EmptyAddress {
+		recipient = r.GetBlockRewardPool()
+	}
+	reward := r.GetBlockReward(height)
+	if recipient == codec.EmptyAddress || reward == 0 {
+		return codec.EmptyAddress, 0, nil
+	}
+
+	minter, ok := sm.(Minter)
+	var stateKeys set.Set[string]
+	if ok {
+		stateKeys = set.Of(minter.MintStateKeys(recipient)...)
+	} else {
+		stateKeys = set.Of(sm.SponsorStateKeys(recipient)...)
+	}
+	storage := make(map[string][]byte, len(stateKeys))
+	for k := range stateKeys {
+		v, err := im.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return codec.EmptyAddress, 0, err
+		}
+		storage[k] = v
+	}
+	tsv := ts.NewView(stateKeys, storage)
+	if ok {
+		if err := minter.Mint(ctx, recipient, tsv, reward); err != nil {
+			return codec.EmptyAddress, 0, err
+		}
+	} else if err := sm.Credit(ctx, recipient, tsv, reward); err != nil {
+		return codec.EmptyAddress, 0, err
+	}
+	tsv.Commit()
+	return recipient, reward, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// testMinter is a [testLedger] that tracks the supply of the native asset.
+type testMinter struct {
+	testLedger
+}
+
+var testSupplyKey = keys.EncodeChunks([]byte{0x5}, 1)
+
+func (m testMinter) MintStateKeys(addr codec.Address) []string {
+	return append(m.SponsorStateKeys(addr), string(testSupplyKey))
+}
+
+func (m testMinter) Mint(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error {
+	supply, err := getTestBalance(ctx, mu, testSupplyKey)
+	if err != nil {
+		return err
+	}
+	if err := mu.Insert(ctx, testSupplyKey, binary.BigEndian.AppendUint64(nil, supply+amount)); err != nil {
+		return err
+	}
+	return m.Credit(ctx, addr, mu, amount)
+}
+
+func TestHalvingReward(t *testing.T) {
+	require := require.New(t)
+
+	for height, reward := range []uint64{1_000, 1_000, 500, 500, 250} {
+		require.Equal(reward, HalvingReward(1_000, 2, uint64(height)))
+	}
+	require.Zero(HalvingReward(1_000, 2, 128))
+
+	// Without an interval, the reward never halves
+	require.Equal(uint64(1_000), HalvingReward(1_000, 0, 1_000_000))
+}
+
+func TestMintBlockReward(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	var (
+		pool        = codec.CreateAddress(0, ids.GenerateTestID())
+		beneficiary = codec.CreateAddress(0, ids.GenerateTestID())
+		sm          = testLedger{}
+		mem         = memoryState{}
+	)
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetBlockReward(gomock.Any()).DoAndReturn(func(height uint64) uint64 {
+		return HalvingReward(1_000, 2, height)
+	}).AnyTimes()
+	pools := r.EXPECT().GetBlockRewardPool().Return(pool).AnyTimes()
+	mint := func(sm StateManager, height uint64, beneficiary codec.Address) (codec.Address, uint64) {
+		ts := tstate.New(0)
+		recipient, reward, err := mintBlockReward(ctx, r, sm, mem, ts, height, beneficiary)
+		require.NoError(err)
+		mem.commit(ts)
+		return recipient, reward
+	}
+
+	// The reward is minted to the beneficiary (or the pool if there is none)
+	recipient, reward := mint(sm, 1, beneficiary)
+	require.Equal(beneficiary, recipient)
+	require.Equal(uint64(1_000), reward)
+	recipient, reward = mint(sm, 2, codec.EmptyAddress)
+	require.Equal(pool, recipient)
+	require.Equal(uint64(500), reward)
+	require.Equal(uint64(1_000), sm.balance(t, mem, beneficiary))
+	require.Equal(uint64(500), sm.balance(t, mem, pool))
+
+	// [Minter]s control how the reward is created
+	recipient, reward = mint(testMinter{}, 4, beneficiary)
+	require.Equal(beneficiary, recipient)
+	require.Equal(uint64(250), reward)
+	require.Equal(uint64(1_250), sm.balance(t, mem, beneficiary))
+	supply, err := getTestBalance(ctx, mem, testSupplyKey)
+	require.NoError(err)
+	require.Equal(uint64(250), supply)
+
+	// Nothing is minted once the reward runs out or without a recipient
+	changes := len(mem)
+	recipient, reward = mint(sm, 128, beneficiary)
+	require.Zero(recipient)
+	require.Zero(reward)
+	pools.Return(codec.EmptyAddress)
+	recipient, reward = mint(sm, 1, codec.EmptyAddress)
+	require.Zero(recipient)
+	require.Zero(reward)
+	require.Len(mem, changes)
+	require.Equal(uint64(1_250), sm.balance(t, mem, beneficiary))
+}
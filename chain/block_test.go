@@ -0,0 +1,132 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// testTypedAuth is the minimal [Auth] needed to marshal a block (its
+// transactions are already marshalled).
+type testTypedAuth struct {
+	Auth
+}
+
+func (testTypedAuth) GetTypeID() uint8 { return 0 }
+
+func newTestBlock(txs int, witness []byte, arrivals []int64) *StatefulBlock {
+	b := &StatefulBlock{
+		Prnt:        ids.GenerateTestID(),
+		Tmstmp:      1_000,
+		Hght:        10,
+		StateRoot:   ids.GenerateTestID(),
+		WarpResults: 3,
+		BlobsRoot:   ids.GenerateTestID(),
+		Beneficiary: codec.CreateAddress(0, ids.GenerateTestID()),
+		Witness:     witness,
+		Arrivals:    arrivals,
+	}
+	for i := 0; i < txs; i++ {
+		raw := make([]byte, 100+i)
+		raw[0] = byte(i)
+		b.Txs = append(b.Txs, &Transaction{bytes: raw, size: len(raw), Auth: testTypedAuth{}})
+	}
+	return b
+}
+
+func TestBlockMarshalSize(t *testing.T) {
+	require := require.New(t)
+
+	for name, b := range map[string]*StatefulBlock{
+		"empty":    newTestBlock(0, nil, nil),
+		"txs":      newTestBlock(5, nil, nil),
+		"witness":  newTestBlock(5, []byte{1, 2, 3}, nil),
+		"arrivals": newTestBlock(2, nil, []int64{1, 2}),
+		"trailer":  newTestBlock(2, []byte{1, 2, 3}, []int64{1, 2}),
+	} {
+		// The size includes every field, so marshalling never reallocates
+		size := b.marshalSize()
+		p := codec.NewWriter(size, consts.NetworkSizeLimit)
+		raw, err := b.marshal(p)
+		require.NoError(err, name)
+		require.Len(raw, size, name)
+		require.Equal(size, cap(raw), name)
+		require.Equal(size, b.Size(), name)
+
+		id, err := b.ID()
+		require.NoError(err, name)
+		require.Equal(utils.ToID(raw), id, name)
+
+		// Appending only adds the bytes of the block
+		prefix := []byte{0xff, 0xfe}
+		appended, err := b.AppendMarshal(prefix)
+		require.NoError(err, name)
+		require.Equal(append(prefix, raw...), appended, name)
+		require.Equal(size, b.Size(), name)
+	}
+}
+
+func TestBlockMarshalAllocs(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBlock(100, []byte{1, 2, 3}, make([]int64, 100))
+
+	// The block bytes are allocated once (in addition to the packer and the
+	// auth counts)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, err := b.Marshal()
+		require.NoError(err)
+	})
+	require.LessOrEqual(allocs, float64(4))
+
+	// Hashing and appending to a buffer with enough capacity don't allocate
+	// the block bytes
+	idAllocs := testing.AllocsPerRun(100, func() {
+		_, err := b.ID()
+		require.NoError(err)
+	})
+	require.Less(idAllocs, allocs)
+	dst := make([]byte, 0, b.marshalSize())
+	appendAllocs := testing.AllocsPerRun(100, func() {
+		_, err := b.AppendMarshal(dst)
+		require.NoError(err)
+	})
+	require.Less(appendAllocs, allocs)
+}
+
+func BenchmarkBlockMarshal(b *testing.B) {
+	blk := newTestBlock(1_000, []byte{1, 2, 3}, make([]int64, 1_000))
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := blk.Marshal(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("id", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := blk.ID(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("append", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]byte, 0, blk.marshalSize())
+		for i := 0; i < b.N; i++ {
+			if _, err := blk.AppendMarshal(dst); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
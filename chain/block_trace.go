@@ -0,0 +1,225 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+type blockTraceKey struct{}
+
+// WithBlockTrace returns a copy of [ctx] that records a [BlockTrace] of any
+// block parsed or executed with it, even if tracing is disabled (like when
+// replaying blocks offline).
+func WithBlockTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, blockTraceKey{}, true)
+}
+
+// shouldTrace returns true if the transactions processed under [span] should
+// be traced individually.
+func shouldTrace(ctx context.Context, span oteltrace.Span) bool {
+	if span.IsRecording() {
+		return true
+	}
+	forced, _ := ctx.Value(blockTraceKey{}).(bool)
+	return forced
+}
+
+// BlockTrace records how long each transaction of a block spent in each phase
+// of its processing. It is only recorded if the block is sampled by the
+// tracer (or executed with [WithBlockTrace]) and can be exported as JSON to
+// analyze slow blocks offline.
+//
+// All durations are in nanoseconds.
+type BlockTrace struct {
+	Block  ids.ID `json:"block"`
+	Height uint64 `json:"height"`
+	Txs    int    `json:"txs"`
+
+	// Start is when execution of the block started (in unix milliseconds).
+	Start     int64         `json:"start"`
+	Execution time.Duration `json:"execution"`
+
+	Timings []*TxTiming `json:"timings"`
+}
+
+// TxTiming records how long a transaction spent in each phase of its
+// processing. Phases that were not traced (like parsing for blocks built by
+// this node) are 0.
+type TxTiming struct {
+	ID     ids.ID `json:"id"`
+	Action string `json:"action"`
+	Status Status `json:"status"`
+
+	// Offset is when execution of the transaction started, relative to the
+	// start of the block. Transactions that don't conflict are executed
+	// concurrently.
+	Offset time.Duration `json:"offset"`
+
+	Parse   time.Duration `json:"parse"`
+	Read    time.Duration `json:"read"`
+	Verify  time.Duration `json:"verify"`
+	Execute time.Duration `json:"execute"`
+	Write   time.Duration `json:"write"`
+}
+
+func newBlockTrace(b *StatelessBlock, start time.Time) *BlockTrace {
+	bt := &BlockTrace{
+		Block:   b.ID(),
+		Height:  b.Hght,
+		Txs:     len(b.Txs),
+		Start:   start.UnixMilli(),
+		Timings: make([]*TxTiming, len(b.Txs)),
+	}
+	actionRegistry, _ := b.vm.Registry()
+	for i, tx := range b.Txs {
+		bt.Timings[i] = &TxTiming{
+			ID:     tx.ID(),
+			Action: actionName(actionRegistry, tx.Action.GetTypeID()),
+		}
+		if i < len(b.parseTimes) {
+			bt.Timings[i].Parse = b.parseTimes[i]
+		}
+	}
+	return bt
+}
+
+// actionName returns the registered name of [typeID] or, if it has none, the
+// type ID itself.
+func actionName(registry *codec.TypeParser[Action, *warp.Message, bool], typeID uint8) string {
+	if registry != nil {
+		if name, ok := registry.LookupName(typeID); ok {
+			return name
+		}
+	}
+	return strconv.Itoa(int(typeID))
+}
+
+type txPhase int
+
+const (
+	txRead txPhase = iota
+	txVerify
+	txExecute
+	txWrite
+)
+
+var txPhaseNames = [...]string{"Processor.Tx.Read", "Processor.Tx.Verify", "Processor.Tx.Execute", "Processor.Tx.Write"}
+
+// txSpan traces the execution of a single transaction. Each phase is a child
+// span of the transaction span and its duration is recorded in [timing].
+//
+// All methods are no-ops on a nil [txSpan], so callers don't need to check if
+// the block is traced.
+type txSpan struct {
+	tracer trace.Tracer
+	ctx    context.Context
+	span   oteltrace.Span
+	timing *TxTiming
+
+	phase      txPhase
+	phaseSpan  oteltrace.Span
+	phaseStart time.Time
+}
+
+// startTxSpan starts tracing [tx] (described by [timing]) in a block that
+// started executing at [blockStart].
+func startTxSpan(
+	ctx context.Context,
+	tracer trace.Tracer, //nolint:interfacer
+	tx *Transaction,
+	timing *TxTiming,
+	blockStart time.Time,
+) *txSpan {
+	timing.Offset = time.Since(blockStart)
+	ctx, span := tracer.Start(ctx, "Processor.Tx", oteltrace.WithAttributes(
+		attribute.Stringer("txID", tx.ID()),
+		attribute.String("action", timing.Action),
+	))
+	return &txSpan{tracer: tracer, ctx: ctx, span: span, timing: timing}
+}
+
+// next ends the current phase (if any) and starts [phase]. The returned
+// context should be used for all work done during [phase].
+func (s *txSpan) next(ctx context.Context, phase txPhase) context.Context {
+	if s == nil {
+		return ctx
+	}
+	s.endPhase()
+	s.phase = phase
+	ctx, s.phaseSpan = s.tracer.Start(s.ctx, txPhaseNames[phase])
+	s.phaseStart = time.Now()
+	return ctx
+}
+
+func (s *txSpan) endPhase() {
+	if s.phaseSpan == nil {
+		return
+	}
+	s.phaseSpan.End()
+	s.phaseSpan = nil
+	elapsed := time.Since(s.phaseStart)
+	switch s.phase {
+	case txRead:
+		s.timing.Read = elapsed
+	case txVerify:
+		s.timing.Verify = elapsed
+	case txExecute:
+		s.timing.Execute = elapsed
+	case txWrite:
+		s.timing.Write = elapsed
+	}
+}
+
+func (s *txSpan) setResult(result *Result) {
+	if s == nil {
+		return
+	}
+	s.timing.Status = result.Status
+	s.span.SetAttributes(attribute.Stringer("status", result.Status))
+}
+
+// end must be called once the transaction is processed (even if it errored).
+func (s *txSpan) end() {
+	if s == nil {
+		return
+	}
+	s.endPhase()
+	s.span.End()
+}
+
+// parseTracer traces the parsing of each transaction of a block. All methods
+// are no-ops on a nil [parseTracer].
+type parseTracer struct {
+	ctx    context.Context
+	tracer trace.Tracer
+}
+
+// parseTx calls [parse] in a child span of [p.ctx] and returns how long it
+// took.
+func (p *parseTracer) parseTx(parse func() (*Transaction, error)) (*Transaction, time.Duration, error) {
+	if p == nil {
+		tx, err := parse()
+		return tx, 0, err
+	}
+	_, span := p.tracer.Start(p.ctx, "chain.ParseTx")
+	defer span.End()
+	start := time.Now()
+	tx, err := parse()
+	if err != nil {
+		return nil, 0, err
+	}
+	span.SetAttributes(attribute.Stringer("txID", tx.ID()))
+	return tx, time.Since(start), nil
+}
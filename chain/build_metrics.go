@@ -0,0 +1,160 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+)
+
+// BuildPhase is a step of [BuildBlock] that is timed separately, so that
+// operators can see where block building spends its time.
+type BuildPhase int
+
+const (
+	// BuildPrepare fetches the parent state and computes the rules and fees
+	// of the block.
+	BuildPrepare BuildPhase = iota
+	// BuildBundles includes bundles at the start of the block.
+	BuildBundles
+	// BuildExecute executes transactions streamed from the mempool.
+	BuildExecute
+	// BuildFinalize runs block hooks, distributes fees, and updates chain
+	// metadata.
+	BuildFinalize
+	// BuildCommit waits for the parent root, generates the witness (if
+	// enabled), and exports the view of the block.
+	BuildCommit
+
+	NumBuildPhases
+)
+
+var buildPhaseNames = [NumBuildPhases]string{"prepare", "bundles", "execute", "finalize", "commit"}
+
+func (p BuildPhase) String() string {
+	if p < 0 || p >= NumBuildPhases {
+		return "unknown"
+	}
+	return buildPhaseNames[p]
+}
+
+// phaseTimer records the duration of each [BuildPhase] as it ends.
+type phaseTimer struct {
+	m     Metrics
+	phase BuildPhase
+	start time.Time
+}
+
+func newPhaseTimer(m Metrics) *phaseTimer {
+	return &phaseTimer{m: m, phase: BuildPrepare, start: time.Now()}
+}
+
+// next records the current phase and starts [phase].
+func (t *phaseTimer) next(phase BuildPhase) {
+	now := time.Now()
+	t.m.RecordBuildPhase(t.phase, now.Sub(t.start))
+	t.phase = phase
+	t.start = now
+}
+
+// done records the last phase. Phases of blocks that failed to build are not
+// recorded once building fails.
+func (t *phaseTimer) done() {
+	t.m.RecordBuildPhase(t.phase, time.Since(t.start))
+}
+
+// Reasons a transaction was dropped (instead of being included or returned to
+// the mempool) while building a block.
+const (
+	DropDuplicate           = "duplicate"
+	DropDeprecated          = "deprecated"
+	DropInvalidStateKeys    = "invalid_state_keys"
+	DropInvalidAccessList   = "invalid_access_list"
+	DropAccessListMismatch  = "access_list_mismatch"
+	DropInsufficientPrice   = "insufficient_price"
+	DropMaxFeeAssetExceeded = "max_fee_asset_exceeded"
+	DropExpired             = "expired"
+	DropInvalidExpiry       = "invalid_expiry"
+	DropInvalidNonce        = "invalid_nonce"
+	DropCancelled           = "cancelled"
+	DropWarpReplay          = "warp_replay"
+	DropWarpExpired         = "warp_expired"
+	DropTooManyWarpSigners  = "too_many_warp_signers"
+	DropInsufficientBalance = "insufficient_balance"
+	DropUnauthorized        = "unauthorized"
+	DropActionDisabled      = "action_disabled"
+	DropRejectedByHook      = "rejected_by_hook"
+	DropFeeExemptFailed     = "fee_exempt_failed"
+	DropUnknown             = "unknown"
+)
+
+// preExecuteErrors maps each error returned by [Transaction.PreExecute] (or
+// [PreExecuteHook]) to whether the transaction should be restored to the
+// mempool or, if not, why it was dropped.
+var preExecuteErrors = []struct {
+	err     error
+	restore bool
+	reason  string
+}{
+	{ErrInsufficientPrice, false, DropInsufficientPrice},
+	{ErrMaxFeeAssetExceeded, false, DropMaxFeeAssetExceeded},
+	{ErrTimestampTooEarly, true, ""},
+	{ErrTimestampTooLate, false, DropExpired},
+	{ErrHeightTooEarly, true, ""},
+	{ErrHeightTooLate, false, DropExpired},
+	{ErrHeightExpiryDisabled, false, DropInvalidExpiry},
+	// May be executable once the preceding nonces are included
+	{ErrNonceTooHigh, true, ""},
+	{ErrNonceTooLow, false, DropInvalidNonce},
+	{ErrNoncesDisabled, false, DropInvalidNonce},
+	{ErrNonceRequired, false, DropInvalidNonce},
+	{ErrTxCancelled, false, DropCancelled},
+	{ErrWarpReplay, false, DropWarpReplay},
+	{ErrWarpMessageExpired, false, DropWarpExpired},
+	{ErrTooManyWarpSigners, false, DropTooManyWarpSigners},
+	{ErrInvalidBalance, false, DropInsufficientBalance},
+	{ErrAuthNotActivated, false, DropUnauthorized},
+	{ErrAuthNotAuthorized, false, DropUnauthorized},
+	{ErrAuthFailed, false, DropUnauthorized},
+	{ErrActionNotActivated, false, DropActionDisabled},
+	{ErrActionDisabled, false, DropActionDisabled},
+	{ErrActionDeprecated, false, DropDeprecated},
+	{ErrInvalidAccessList, false, DropInvalidAccessList},
+	{ErrAccessListMismatch, false, DropAccessListMismatch},
+	{ErrTxRejectedByHook, false, DropRejectedByHook},
+}
+
+// classifyPreExecute returns whether a transaction that failed PreExecute
+// with [err] should be restored to the mempool and, if not, the reason it
+// is dropped.
+func classifyPreExecute(log logging.Logger, err error) (bool, string) {
+	for _, e := range preExecuteErrors {
+		if errors.Is(err, e.err) {
+			return e.restore, e.reason
+		}
+	}
+	// If unknown error, drop
+	log.Warn("unknown PreExecute error", zap.Error(err))
+	return false, DropUnknown
+}
+
+// handlePreExecute is [HandlePreExecute] for transactions streamed from the
+// mempool, which records why they are dropped.
+func handlePreExecute(m Metrics, log logging.Logger, err error) bool {
+	restore, reason := classifyPreExecute(log, err)
+	if !restore {
+		m.RecordTxsDropped(reason, 1)
+	}
+	return restore
+}
+
+// HandlePreExecute returns true if a transaction that failed PreExecute with
+// [err] may be executable later (and should be restored to the mempool).
+func HandlePreExecute(log logging.Logger, err error) bool {
+	restore, _ := classifyPreExecute(log, err)
+	return restore
+}
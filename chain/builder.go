@@ -14,7 +14,6 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
-	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"go.opentelemetry.io/otel/attribute"
@@ -22,6 +21,7 @@ import (
 
 	"github.com/ava-labs/hypersdk/executor"
 	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/tstate"
 )
 
@@ -36,29 +36,6 @@ const (
 
 var errBlockFull = errors.New("block full")
 
-func HandlePreExecute(log logging.Logger, err error) bool {
-	switch {
-	case errors.Is(err, ErrInsufficientPrice):
-		return false
-	case errors.Is(err, ErrTimestampTooEarly):
-		return true
-	case errors.Is(err, ErrTimestampTooLate):
-		return false
-	case errors.Is(err, ErrInvalidBalance):
-		return false
-	case errors.Is(err, ErrAuthNotActivated):
-		return false
-	case errors.Is(err, ErrAuthFailed):
-		return false
-	case errors.Is(err, ErrActionNotActivated):
-		return false
-	default:
-		// If unknown error, drop
-		log.Warn("unknown PreExecute error", zap.Error(err))
-		return false
-	}
-}
-
 func BuildBlock(
 	ctx context.Context,
 	vm VM,
@@ -67,7 +44,8 @@ func BuildBlock(
 ) (*StatelessBlock, error) {
 	ctx, span := vm.Tracer().Start(ctx, "chain.BuildBlock")
 	defer span.End()
-	log := vm.Logger()
+	log := vm.ModuleLogger(logs.Builder)
+	timer := newPhaseTimer(vm)
 
 	// We don't need to fetch the [VerifyContext] because
 	// we will always have a block to build on.
@@ -80,6 +58,7 @@ func BuildBlock(
 		return nil, ErrTimestampTooEarly
 	}
 	b := NewBlock(vm, parent, nextTime)
+	ctx = WithBlockSeed(ctx, b.Seed())
 
 	// Fetch view where we will apply block state transitions
 	//
@@ -92,24 +71,48 @@ func BuildBlock(
 		log.Warn("block building failed: couldn't get parent db", zap.Error(err))
 		return nil, err
 	}
+	var recorder *witnessRecorder
+	if r.GetBlockWitnesses() {
+		recorder = newWitnessRecorder(parentView)
+		parentView = recorder
+	}
+	r, err = StateRules(ctx, vm, vm.StateManager(), parentView, nextTime)
+	if err != nil {
+		return nil, err
+	}
+	if nextTime < parent.Tmstmp+r.GetMinBlockGap() {
+		// The min block gap was overridden by our ancestors
+		log.Debug("block building failed", zap.Error(ErrTimestampTooEarly))
+		return nil, ErrTimestampTooEarly
+	}
 
 	// Compute next unit prices to use
-	feeKey := FeeKey(vm.StateManager().FeeKey())
-	feeRaw, err := parentView.GetValue(ctx, feeKey)
+	parentFeeManager, legacyFee, err := GetFeeManager(ctx, vm.StateManager(), parentView)
 	if err != nil {
 		return nil, err
 	}
-	parentFeeManager := NewFeeManager(feeRaw)
 	feeManager, err := parentFeeManager.ComputeNext(parent.Tmstmp, nextTime, r)
 	if err != nil {
 		return nil, err
 	}
 	maxUnits := r.GetMaxBlockUnits()
 	targetUnits := r.GetWindowTargetUnits()
+	hooks := vm.Hooks()
+	if err := preBlock(ctx, hooks, r, parentView, nextTime, b.Hght); err != nil {
+		log.Warn("block building failed", zap.Error(err))
+		return nil, err
+	}
+	snapshot := snapshotsValidators(r, b.Hght)
+	if snapshot && blockContext == nil {
+		// Fail before taking any transactions from the mempool
+		log.Warn("block building failed", zap.Error(ErrMissingBlockContext))
+		return nil, ErrMissingBlockContext
+	}
 
 	var (
 		ts            = tstate.New(changesEstimate)
 		oldestAllowed = nextTime - r.GetValidityWindow()
+		oldestHeight  = OldestAllowedHeight(r, b.Hght)
 
 		mempool = vm.Mempool()
 
@@ -126,19 +129,41 @@ func BuildBlock(
 		warpAdded    = uint(0)
 		start        = time.Now()
 		txsAttempted = 0
-		results      = []*Result{}
+		results      []*Result
 
 		vdrState = vm.ValidatorState()
 		sm       = vm.StateManager()
 
+		// fcfs executes transactions one at a time in the order they were
+		// received (bundles are never included because they would be
+		// placed ahead of transactions received before them)
+		fcfs        = vm.GetFCFSOrdering()
+		lastArrival int64
+
 		// prepareStreamLock ensures we don't overwrite stream prefetching spawned
 		// asynchronously.
 		prepareStreamLock sync.Mutex
 	)
 
+	// Include bundles before any transactions are executed concurrently, so
+	// that their changes can be undone if they can't be included in full
+	b.Txs = []*Transaction{}
+	timer.next(BuildBundles)
+	if !fcfs {
+		results, err = includeBundles(ctx, vm, parent, b, r, feeManager, parentView, ts)
+		if err != nil {
+			log.Warn("block building failed", zap.Error(err))
+			return nil, err
+		}
+	}
+	bundled := set.NewSet[ids.ID](len(b.Txs))
+	for _, tx := range b.Txs {
+		bundled.Add(tx.ID())
+	}
+
 	// Batch fetch items from mempool to unblock incoming RPC/Gossip traffic
+	timer.next(BuildExecute)
 	mempool.StartStreaming(ctx)
-	b.Txs = []*Transaction{}
 	for time.Since(start) < vm.GetTargetBuildDuration() {
 		prepareStreamLock.Lock()
 		txs := mempool.Stream(ctx, streamBatch)
@@ -147,10 +172,13 @@ func BuildBlock(
 			b.vm.RecordClearedMempool()
 			break
 		}
+		if fcfs {
+			sortByArrival(txs)
+		}
 		ctx, executeSpan := vm.Tracer().Start(ctx, "chain.BuildBlock.Execute")
 
 		// Perform a batch repeat check
-		dup, err := parent.IsRepeat(ctx, oldestAllowed, txs, set.NewBits(), false)
+		dup, err := parent.IsRepeat(ctx, oldestAllowed, oldestHeight, txs, set.NewBits(), false)
 		if err != nil {
 			restorable = append(restorable, txs...)
 			break
@@ -165,7 +193,11 @@ func BuildBlock(
 			tx := ltx
 
 			// Skip any duplicates before going async
+			if bundled.Contains(tx.ID()) {
+				continue
+			}
 			if dup.Contains(i) {
+				vm.RecordTxsDropped(DropDuplicate, 1)
 				continue
 			}
 
@@ -181,12 +213,22 @@ func BuildBlock(
 				continue
 			}
 
-			stateKeys, err := tx.StateKeys(sm)
+			stateKeys, err := tx.StateKeys(sm, r)
 			if err != nil {
 				// Drop bad transaction and continue
 				//
 				// This should not happen because we check this before
 				// adding a transaction to the mempool.
+				if errors.Is(err, ErrAccessListMismatch) {
+					vm.RecordAccessListMismatch()
+				}
+				vm.RecordTxsDropped(DropInvalidStateKeys, 1)
+				continue
+			}
+			if err := tx.CheckDeprecated(r, b.Hght); err != nil {
+				// Drop transactions that can no longer be included
+				log.Debug("dropping deprecated transaction", zap.Stringer("txID", tx.ID()), zap.Error(err))
+				vm.RecordTxsDropped(DropDeprecated, 1)
 				continue
 			}
 
@@ -205,7 +247,11 @@ func BuildBlock(
 			pendingLock.Lock()
 			pending[tx.ID()] = tx
 			pendingLock.Unlock()
-			e.Run(stateKeys, func() error {
+			conflicts := stateKeys
+			if fcfs {
+				conflicts = withArrivalConflict(stateKeys)
+			}
+			e.Run(conflicts, func() error {
 				// We use defer here instead of covering all returns because it is
 				// much easier to manage.
 				var restore bool
@@ -222,6 +268,22 @@ func BuildBlock(
 					restorableLock.Unlock()
 				}()
 
+				// Transactions received before one that is already included
+				// are left for the next block, so that [b.Arrivals] never
+				// decreases (as are those received after we started building)
+				if fcfs {
+					blockLock.RLock()
+					late := tx.Arrival() < lastArrival
+					blockLock.RUnlock()
+					if late {
+						vm.RecordFCFSDeviation()
+					}
+					if late || tx.Arrival() > nextTime {
+						restore = true
+						return nil
+					}
+				}
+
 				// Fetch keys from cache
 				var (
 					storage  = make(map[string][]byte, len(stateKeys))
@@ -273,12 +335,14 @@ func BuildBlock(
 
 				// Execute block
 				tsv := ts.NewView(stateKeys, storage)
-				if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, nextTime); err != nil {
+				if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, nextTime, b.Hght); err != nil {
 					// We don't need to rollback [tsv] here because it will never
 					// be committed.
-					if HandlePreExecute(log, err) {
-						restore = true
-					}
+					restore = handlePreExecute(vm, log, err)
+					return nil
+				}
+				if err := PreExecuteHook(ctx, hooks, r, tsv, nextTime, b.Hght, tx); err != nil {
+					handlePreExecute(vm, log, err)
 					return nil
 				}
 
@@ -295,10 +359,13 @@ func BuildBlock(
 					// itself a message to trigger a chain upgrade.
 					allowed, num, denom := r.GetWarpConfig(tx.WarpMessage.SourceChainID)
 					if allowed {
-						warpErr = tx.WarpMessage.Signature.Verify(
-							ctx, &tx.WarpMessage.UnsignedMessage, r.NetworkID(),
-							vdrState, blockContext.PChainHeight, num, denom,
-						)
+						warpErr = VerifyWarpQuorum(num, denom)
+						if warpErr == nil {
+							warpErr = tx.WarpMessage.Signature.Verify(
+								ctx, &tx.WarpMessage.UnsignedMessage, r.NetworkID(),
+								vdrState, blockContext.PChainHeight, num, denom,
+							)
+						}
 					} else {
 						warpErr = ErrDisabledChainID
 					}
@@ -332,6 +399,7 @@ func BuildBlock(
 					// This should not happen because we check this before
 					// adding a transaction to the mempool.
 					log.Warn("invalid tx: invalid state keys")
+					vm.RecordTxsDropped(DropInvalidStateKeys, 1)
 					return nil
 				}
 				result, err := tx.Execute(
@@ -351,6 +419,14 @@ func BuildBlock(
 					restore = true
 					return err
 				}
+				if !result.Success() && tx.FeeExempt(r) {
+					// Nobody would pay for the failure, so it is never included
+					// (we don't need to rollback [tsv] because it will never be
+					// committed)
+					log.Debug("dropping tx: fee exempt tx failed", zap.Stringer("txID", tx.ID()))
+					vm.RecordTxsDropped(DropFeeExemptFailed, 1)
+					return nil
+				}
 
 				// Need to atomically check there aren't too many warp messages and add to block
 				blockLock.Lock()
@@ -373,12 +449,24 @@ func BuildBlock(
 					if feeManager.LastConsumed(dimension) >= targetUnits[dimension] {
 						return errBlockFull
 					}
+
+					// Otherwise, only stream transactions that can fit in what is left
+					// of [dimension] (instead of executing them just to restore them)
+					mempool.LimitStream(ctx, int(dimension), maxUnits[dimension]-feeManager.LastConsumed(dimension))
+					return nil
 				}
 
 				// Update block with new transaction
+				if hooks != nil {
+					hooks.PostExecute(ctx, r, tsv, nextTime, b.Hght, tx, result)
+				}
 				tsv.Commit()
 				b.Txs = append(b.Txs, tx)
 				results = append(results, result)
+				if fcfs {
+					b.Arrivals = append(b.Arrivals, tx.Arrival())
+					lastArrival = tx.Arrival()
+				}
 				if tx.WarpMessage != nil {
 					if warpErr == nil {
 						// Add a bit if the warp message was verified
@@ -403,10 +491,13 @@ func BuildBlock(
 				// sure all transactions are returned to the mempool.
 				go func() {
 					prepareStreamLock.Lock() // we never need to unlock this as it will not be used after this
-					restored := mempool.FinishStreaming(ctx, append(b.Txs, restorable...))
-					b.vm.Logger().Debug("transactions restored to mempool", zap.Int("count", restored))
+					restored := mempool.FinishStreaming(ctx, append(b.Txs[bundled.Len():], restorable...))
+					b.vm.ModuleLogger(logs.Builder).Debug("transactions restored to mempool", zap.Int("count", restored))
 				}()
-				b.vm.Logger().Warn("build failed", zap.Error(execErr))
+				if len(b.bundles) > 0 {
+					vm.Bundles().Add(ctx, b.bundles)
+				}
+				b.vm.ModuleLogger(logs.Builder).Warn("build failed", zap.Error(execErr))
 				return nil, execErr
 			}
 			break
@@ -418,7 +509,7 @@ func BuildBlock(
 	go func() {
 		prepareStreamLock.Lock()
 		restored := mempool.FinishStreaming(ctx, restorable)
-		b.vm.Logger().Debug("transactions restored to mempool", zap.Int("count", restored))
+		b.vm.ModuleLogger(logs.Builder).Debug("transactions restored to mempool", zap.Int("count", restored))
 	}()
 
 	// Update tracking metrics
@@ -429,6 +520,7 @@ func BuildBlock(
 	if time.Since(start) > b.vm.GetTargetBuildDuration() {
 		b.vm.RecordBuildCapped()
 	}
+	timer.next(BuildFinalize)
 
 	// Perform basic validity checks to make sure the block is well-formatted
 	if len(b.Txs) == 0 {
@@ -437,28 +529,64 @@ func BuildBlock(
 		}
 		vm.RecordEmptyBlockBuilt()
 	}
+	if hooks != nil {
+		hooks.PostBlock(ctx, r, nextTime, b.Hght, b.Txs, results)
+	}
+
+	// Run the epoch transition (if this block ends an epoch)
+	if err := endEpoch(ctx, vm.EpochHandler(), r, parentView, ts, b.Tmstmp, b.Hght); err != nil {
+		log.Warn("block building failed", zap.Error(err))
+		return nil, err
+	}
+	if snapshot {
+		b.snapshot, b.snapshotMessage, err = storeValidatorSnapshot(
+			ctx, r, sm, vdrState, ts, b.Hght, blockContext.PChainHeight,
+		)
+		if err != nil {
+			log.Warn("block building failed", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Credit the builder's share of fees and burn the rest
+	b.Beneficiary = vm.Beneficiary()
+	burned, err := distributeFees(ctx, r, sm, parentView, ts, feeManager.UnitPrices(), b.Beneficiary, results)
+	if err != nil {
+		return nil, err
+	}
+	b.burned = burned
+
+	// Mint the block reward
+	b.rewardRecipient, b.reward, err = mintBlockReward(ctx, r, sm, parentView, ts, b.Hght, b.Beneficiary)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prune the records of warp messages that can no longer be imported
+	if err := pruneWarpReplays(ctx, r, sm, parentView, ts, b.Tmstmp, b.Txs, results); err != nil {
+		return nil, err
+	}
 
 	// Update chain metadata
 	heightKey := HeightKey(sm.HeightKey())
 	heightKeyStr := string(heightKey)
 	timestampKey := TimestampKey(b.vm.StateManager().TimestampKey())
 	timestampKeyStr := string(timestampKey)
-	feeKeyStr := string(feeKey)
-	tsv := ts.NewView(set.Of(heightKeyStr, timestampKeyStr, feeKeyStr), map[string][]byte{
+	tsv := newMetadataView(ts, sm, map[string][]byte{
 		heightKeyStr:    binary.BigEndian.AppendUint64(nil, parent.Hght),
 		timestampKeyStr: binary.BigEndian.AppendUint64(nil, uint64(parent.Tmstmp)),
-		feeKeyStr:       parentFeeManager.Bytes(),
-	})
+	}, parentFeeManager, legacyFee)
 	if err := tsv.Insert(ctx, heightKey, binary.BigEndian.AppendUint64(nil, b.Hght)); err != nil {
 		return nil, fmt.Errorf("%w: unable to insert height", err)
 	}
 	if err := tsv.Insert(ctx, timestampKey, binary.BigEndian.AppendUint64(nil, uint64(b.Tmstmp))); err != nil {
 		return nil, fmt.Errorf("%w: unable to insert timestamp", err)
 	}
-	if err := tsv.Insert(ctx, feeKey, feeManager.Bytes()); err != nil {
+	if err := storeFeeManager(ctx, tsv, sm, feeManager, legacyFee); err != nil {
 		return nil, fmt.Errorf("%w: unable to insert fees", err)
 	}
 	tsv.Commit()
+	timer.next(BuildCommit)
 
 	// Fetch [parentView] root as late as possible to allow
 	// for async processing to complete
@@ -467,8 +595,25 @@ func BuildBlock(
 		return nil, err
 	}
 	b.StateRoot = root
+	b.BlobsRoot = BlobsRoot(b.Txs)
+
+	// Prove every key read while building (including the keys verifiers read
+	// to check the height and timestamp)
+	if recorder != nil {
+		recorder.record(heightKey, timestampKey)
+		witness, err := NewBlockWitness(ctx, recorder.View, recorder.Keys())
+		if err != nil {
+			log.Warn("block building failed: couldn't generate witness", zap.Error(err))
+			return nil, err
+		}
+		b.Witness, err = witness.Marshal()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Get view from [tstate] after writing all changed keys
+	b.changes = ts.Changes()
 	view, err := ts.ExportMerkleDBView(ctx, vm.Tracer(), parentView)
 	if err != nil {
 		return nil, err
@@ -479,6 +624,7 @@ func BuildBlock(
 		log.Warn("block failed", zap.Int("txs", len(b.Txs)), zap.Any("consumed", feeManager.UnitsConsumed()))
 		return nil, err
 	}
+	timer.done()
 
 	// Kickoff root generation
 	go func() {
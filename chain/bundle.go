@@ -0,0 +1,269 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// Bundle is an ordered list of transactions that must be included
+// contiguously in a single block (or not at all). If any transaction in a
+// bundle can't be included or doesn't succeed, none of them are.
+//
+// Bundles are only honored by the node they are submitted to when it builds
+// a block. They are not gossiped and, once included, their transactions are
+// verified like any other.
+type Bundle struct {
+	Txs []*Transaction
+
+	id     ids.ID
+	expiry int64
+	size   int
+}
+
+func NewBundle(txs []*Transaction) (*Bundle, error) {
+	if len(txs) == 0 {
+		return nil, ErrEmptyBundle
+	}
+	if len(txs) > MaxBundleTxs {
+		return nil, ErrBundleTooLarge
+	}
+	var (
+		seen   = set.NewSet[ids.ID](len(txs))
+		txIDs  = make([]byte, 0, len(txs)*consts.IDLen)
+		expiry = int64(consts.MaxInt64)
+		size   int
+	)
+	for _, tx := range txs {
+		// Warp messages must be verified against the block context, which
+		// bundles aren't executed with
+		if tx.WarpMessage != nil {
+			return nil, ErrBundleWarpMessage
+		}
+		txID := tx.ID()
+		if seen.Contains(txID) {
+			return nil, ErrDuplicateTx
+		}
+		seen.Add(txID)
+		txIDs = append(txIDs, txID[:]...)
+		if e := tx.Expiry(); e < expiry {
+			expiry = e
+		}
+		size += tx.Size()
+	}
+	return &Bundle{
+		Txs:    txs,
+		id:     utils.ToID(txIDs),
+		expiry: expiry,
+		size:   size,
+	}, nil
+}
+
+// ID is the hash of the IDs of the transactions in the bundle (in order).
+func (b *Bundle) ID() ids.ID { return b.id }
+
+// Expiry is the earliest expiry of the transactions in the bundle.
+func (b *Bundle) Expiry() int64 { return b.expiry }
+
+// Sponsor is the sponsor of the first transaction in the bundle.
+func (b *Bundle) Sponsor() codec.Address { return b.Txs[0].Sponsor() }
+
+func (b *Bundle) Size() int { return b.size }
+
+// includeBundles executes the [Bundle]s pending in [vm] at the start of [b]
+// and returns the [Result]s of their transactions.
+//
+// Invariant: no other transactions have been executed on [ts]
+func includeBundles(
+	ctx context.Context,
+	vm VM,
+	parent *StatelessBlock,
+	b *StatelessBlock,
+	r Rules,
+	feeManager *FeeManager,
+	parentView state.View,
+	ts *tstate.TState,
+) ([]*Result, error) {
+	pool := vm.Bundles()
+	if pool == nil {
+		return nil, nil
+	}
+	var (
+		log           = vm.ModuleLogger(logs.Chain)
+		oldestAllowed = b.Tmstmp - r.GetValidityWindow()
+		oldestHeight  = OldestAllowedHeight(r, b.Hght)
+		included      = set.Set[ids.ID]{}
+		restorable    = []*Bundle{}
+		results       = []*Result{}
+	)
+	pool.StartStreaming(ctx)
+	defer func() {
+		pool.FinishStreaming(ctx, restorable)
+	}()
+	bundles := pool.Stream(ctx, streamBatch)
+	for i, bundle := range bundles {
+		dup, err := parent.IsRepeat(ctx, oldestAllowed, oldestHeight, bundle.Txs, set.NewBits(), true)
+		if err != nil {
+			restorable = append(restorable, bundles[i:]...)
+			return nil, err
+		}
+		if dup.Len() > 0 || bundleOverlaps(bundle, included) {
+			log.Debug("dropping bundle with duplicate transactions", zap.Stringer("bundleID", bundle.ID()))
+			continue
+		}
+		bundleResults, err := includeBundle(ctx, vm, b, bundle, r, feeManager, parentView, ts)
+		if err != nil {
+			log.Debug("skipping bundle", zap.Stringer("bundleID", bundle.ID()), zap.Error(err))
+			if errors.Is(err, errBlockFull) || !errors.Is(err, ErrBundleTxFailed) && HandlePreExecute(log, err) {
+				restorable = append(restorable, bundle)
+			}
+			continue
+		}
+		for _, tx := range bundle.Txs {
+			included.Add(tx.ID())
+		}
+		b.Txs = append(b.Txs, bundle.Txs...)
+		b.bundles = append(b.bundles, bundle)
+		results = append(results, bundleResults...)
+	}
+	return results, nil
+}
+
+func bundleOverlaps(bundle *Bundle, txIDs set.Set[ids.ID]) bool {
+	for _, tx := range bundle.Txs {
+		if txIDs.Contains(tx.ID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// includeBundle executes the transactions in [bundle] in order on [ts]. If
+// any of them can't be included, all of their changes are undone.
+func includeBundle(
+	ctx context.Context,
+	vm VM,
+	b *StatelessBlock,
+	bundle *Bundle,
+	r Rules,
+	feeManager *FeeManager,
+	parentView state.View,
+	ts *tstate.TState,
+) ([]*Result, error) {
+	var (
+		sm        = vm.StateManager()
+		stateKeys = make([]set.Set[string], len(bundle.Txs))
+		allKeys   = set.Set[string]{}
+	)
+	for i, tx := range bundle.Txs {
+		txKeys, err := tx.StateKeys(sm, r)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.CheckDeprecated(r, b.Hght); err != nil {
+			return nil, err
+		}
+		stateKeys[i] = txKeys
+		allKeys.Union(txKeys)
+	}
+
+	checkpoint := ts.Checkpoint(allKeys)
+	var (
+		views    = make([]*tstate.TStateView, 0, len(bundle.Txs))
+		results  = make([]*Result, 0, len(bundle.Txs))
+		consumed Dimensions
+	)
+	for i, tx := range bundle.Txs {
+		tsv, result, err := executeBundleTx(ctx, vm, b, tx, stateKeys[i], r, feeManager, parentView, ts)
+		if err == nil && !result.Success() {
+			err = fmt.Errorf("%w: %s", ErrBundleTxFailed, tx.ID())
+		}
+		if err == nil {
+			consumed, err = Add(consumed, result.Consumed)
+		}
+		if err != nil {
+			ts.Restore(checkpoint)
+			return nil, err
+		}
+		views = append(views, tsv)
+		results = append(results, result)
+	}
+	if ok, dimension := feeManager.Consume(consumed, r.GetMaxBlockUnits()); !ok {
+		ts.Restore(checkpoint)
+		return nil, fmt.Errorf("%w: dimension=%d", errBlockFull, dimension)
+	}
+
+	// Hooks are only invoked once the whole bundle is included, so the view
+	// of each transaction also includes the changes of later transactions
+	// in the bundle to keys it didn't modify.
+	if hooks := vm.Hooks(); hooks != nil {
+		for i, tx := range bundle.Txs {
+			hooks.PostExecute(ctx, r, views[i], b.Tmstmp, b.Hght, tx, results[i])
+		}
+	}
+	return results, nil
+}
+
+// executeBundleTx executes [tx] on a new view of [ts] and commits its
+// changes.
+func executeBundleTx(
+	ctx context.Context,
+	vm VM,
+	b *StatelessBlock,
+	tx *Transaction,
+	stateKeys set.Set[string],
+	r Rules,
+	feeManager *FeeManager,
+	parentView state.View,
+	ts *tstate.TState,
+) (*tstate.TStateView, *Result, error) {
+	var (
+		sm      = vm.StateManager()
+		storage = make(map[string][]byte, len(stateKeys))
+		reads   = make(map[string]uint16, len(stateKeys))
+	)
+	for k := range stateKeys {
+		v, err := parentView.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			reads[k] = 0
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		numChunks, ok := keys.NumChunks(v)
+		if !ok {
+			return nil, nil, ErrInvalidKeyValue
+		}
+		storage[k] = v
+		reads[k] = numChunks
+	}
+	tsv := ts.NewView(stateKeys, storage)
+	if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, b.Tmstmp, b.Hght); err != nil {
+		return nil, nil, err
+	}
+	if err := PreExecuteHook(ctx, vm.Hooks(), r, tsv, b.Tmstmp, b.Hght, tx); err != nil {
+		return nil, nil, err
+	}
+	result, err := tx.Execute(ctx, feeManager, reads, sm, r, tsv, b.Tmstmp, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	tsv.Commit()
+	return tsv, result, nil
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const cancelledTxLen = ids.IDLen + consts.Int64Len
+
+// CancelledTx is a transaction that can't be included in a block with a
+// timestamp at or before [Expiry].
+type CancelledTx struct {
+	TxID   ids.ID `json:"txId"`
+	Expiry int64  `json:"expiry"`
+}
+
+// CancelKey returns the state key (suffixed with [CancelKeyChunks]) that
+// stores the transactions cancelled by [sponsor], if [sm] implements
+// [Canceller].
+func CancelKey(sm StateManager, sponsor codec.Address) ([]byte, error) {
+	c, ok := sm.(Canceller)
+	if !ok {
+		return nil, ErrCancelUnsupported
+	}
+	return keys.EncodeChunks(c.CancelKey(sponsor), CancelKeyChunks), nil
+}
+
+// GetCancelled returns the transactions cancelled by [sponsor] (some of which
+// may have already expired).
+func GetCancelled(
+	ctx context.Context,
+	sm StateManager,
+	im state.Immutable,
+	sponsor codec.Address,
+) ([]CancelledTx, error) {
+	k, err := CancelKey(sm, sponsor)
+	if err != nil {
+		return nil, err
+	}
+	return getCancelled(ctx, im, k)
+}
+
+func getCancelled(ctx context.Context, im state.Immutable, k []byte) ([]CancelledTx, error) {
+	v, err := im.GetValue(ctx, k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelled(v)
+}
+
+// ParseCancelled decodes the value stored at a [CancelKey].
+func ParseCancelled(v []byte) ([]CancelledTx, error) {
+	if len(v)%cancelledTxLen != 0 || len(v)/cancelledTxLen > MaxCancelledTxs {
+		return nil, fmt.Errorf("%w: cancelled txs have %d bytes", ErrInvalidObject, len(v))
+	}
+	cancelled := make([]CancelledTx, 0, len(v)/cancelledTxLen)
+	for i := 0; i < len(v); i += cancelledTxLen {
+		var c CancelledTx
+		copy(c.TxID[:], v[i:])
+		c.Expiry = int64(binary.BigEndian.Uint64(v[i+ids.IDLen:]))
+		cancelled = append(cancelled, c)
+	}
+	return cancelled, nil
+}
+
+// CancelTx prevents [txID] from being included in any block that is built
+// after the record is committed to [k] (the [CancelKey] of its sponsor). The record is
+// dropped once [timestamp] passes [expiry], so [expiry] should be at least the
+// expiry of the cancelled transaction.
+//
+// Cancelling a transaction that was already cancelled only updates its
+// expiry.
+func CancelTx(
+	ctx context.Context,
+	mu state.Mutable,
+	k []byte,
+	timestamp int64,
+	txID ids.ID,
+	expiry int64,
+) error {
+	cancelled, err := getCancelled(ctx, mu, k)
+	if err != nil {
+		return err
+	}
+	v := make([]byte, 0, (len(cancelled)+1)*cancelledTxLen)
+	for _, c := range cancelled {
+		// Prune expired or replaced records
+		if c.Expiry < timestamp || c.TxID == txID {
+			continue
+		}
+		v = append(v, c.TxID[:]...)
+		v = binary.BigEndian.AppendUint64(v, uint64(c.Expiry))
+	}
+	if len(v)/cancelledTxLen >= MaxCancelledTxs {
+		return ErrTooManyCancelled
+	}
+	v = append(v, txID[:]...)
+	v = binary.BigEndian.AppendUint64(v, uint64(expiry))
+	return mu.Insert(ctx, k, v)
+}
+
+// verifyNotCancelled returns [ErrTxCancelled] if the sponsor of the
+// transaction cancelled it. It is a no-op if cancellation is disabled (see
+// [Rules.GetTxCancellation]) or [sm] does not implement [Canceller].
+func (t *Transaction) verifyNotCancelled(ctx context.Context, sm StateManager, r Rules, im state.Immutable, timestamp int64) error {
+	if !r.GetTxCancellation() {
+		return nil
+	}
+	k, err := CancelKey(sm, t.Auth.Sponsor())
+	if errors.Is(err, ErrCancelUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cancelled, err := getCancelled(ctx, im, k)
+	if err != nil {
+		return err
+	}
+	txID := t.ID()
+	for _, c := range cancelled {
+		if c.TxID == txID && c.Expiry >= timestamp {
+			return ErrTxCancelled
+		}
+	}
+	return nil
+}
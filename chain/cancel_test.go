@@ -0,0 +1,97 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+func TestCancelKey(t *testing.T) {
+	require := require.New(t)
+
+	sponsor := codec.CreateAddress(0, ids.GenerateTestID())
+	k, err := CancelKey(testStateManager{}, sponsor)
+	require.NoError(err)
+	require.True(keys.Valid(string(k)))
+	chunks, ok := keys.MaxChunks(k)
+	require.True(ok)
+	require.Equal(uint16(CancelKeyChunks), chunks)
+
+	_, err = CancelKey(testWarpManager{}, sponsor)
+	require.ErrorIs(err, ErrCancelUnsupported)
+}
+
+func TestCancelTx(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sponsor := codec.CreateAddress(0, ids.GenerateTestID())
+	k, err := CancelKey(testStateManager{}, sponsor)
+	require.NoError(err)
+	mem := memoryState{}
+	cancelled := func() []CancelledTx {
+		cancelled, err := GetCancelled(ctx, testStateManager{}, mem, sponsor)
+		require.NoError(err)
+		return cancelled
+	}
+
+	// Cancelling a tx again only updates its expiry
+	first, second := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(CancelTx(ctx, mem, k, 0, first, 10))
+	require.NoError(CancelTx(ctx, mem, k, 0, second, 20))
+	require.NoError(CancelTx(ctx, mem, k, 0, first, 30))
+	require.Equal([]CancelledTx{{TxID: second, Expiry: 20}, {TxID: first, Expiry: 30}}, cancelled())
+
+	// Expired records are pruned when the key is updated
+	third := ids.GenerateTestID()
+	require.NoError(CancelTx(ctx, mem, k, 21, third, 40))
+	require.Equal([]CancelledTx{{TxID: first, Expiry: 30}, {TxID: third, Expiry: 40}}, cancelled())
+
+	// A sponsor can't have more than [MaxCancelledTxs] pending cancellations
+	for i := len(cancelled()); i < MaxCancelledTxs; i++ {
+		require.NoError(CancelTx(ctx, mem, k, 21, ids.GenerateTestID(), 40))
+	}
+	require.ErrorIs(CancelTx(ctx, mem, k, 21, ids.GenerateTestID(), 40), ErrTooManyCancelled)
+
+	_, err = ParseCancelled(mem[string(k)][1:])
+	require.ErrorIs(err, ErrInvalidObject)
+}
+
+func TestVerifyNotCancelled(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sponsor := codec.CreateAddress(0, ids.GenerateTestID())
+	auth := NewMockAuth(ctrl)
+	auth.EXPECT().Sponsor().Return(sponsor).AnyTimes()
+	tx := &Transaction{Auth: auth, id: ids.GenerateTestID()}
+	k, err := CancelKey(testStateManager{}, sponsor)
+	require.NoError(err)
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetTxCancellation().Return(true).AnyTimes()
+	mem := memoryState{}
+	require.NoError(tx.verifyNotCancelled(ctx, testStateManager{}, r, mem, 10))
+
+	// A cancelled tx is rejected until its record expires
+	require.NoError(CancelTx(ctx, mem, k, 0, tx.ID(), 10))
+	require.ErrorIs(tx.verifyNotCancelled(ctx, testStateManager{}, r, mem, 10), ErrTxCancelled)
+	require.NoError(tx.verifyNotCancelled(ctx, testStateManager{}, r, mem, 11))
+
+	// Cancellation is ignored if the [StateManager] doesn't support it
+	require.NoError(tx.verifyNotCancelled(ctx, testWarpManager{}, r, mem, 10))
+
+	// Cancellation is ignored if the [Rules] don't enable it
+	disabled := NewMockRules(ctrl)
+	disabled.EXPECT().GetTxCancellation().Return(false).AnyTimes()
+	require.NoError(tx.verifyNotCancelled(ctx, testStateManager{}, disabled, mem, 10))
+}
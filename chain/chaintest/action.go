@@ -0,0 +1,258 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chaintest provides helpers for testing implementations of the
+// interfaces defined in [chain].
+package chaintest
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// ActionTest describes a single execution of a [chain.Action] checked by
+// [RunActionTest].
+type ActionTest struct {
+	Name string
+
+	Action chain.Action
+	// Unmarshal is the function registered for [Action] in the
+	// [chain.ActionRegistry].
+	Unmarshal func(*codec.Packer, *warp.Message) (chain.Action, error)
+	// ExpectedUnmarshalErr, if non-nil, is the error [Unmarshal] should
+	// return for [Action] (e.g. if it can never be sent over the wire).
+	ExpectedUnmarshalErr error
+
+	Rules chain.Rules
+	// State is the state [Action] is executed against. Keys must include
+	// their chunk suffix.
+	State        map[string][]byte
+	Timestamp    int64
+	Actor        codec.Address
+	TxID         ids.ID
+	WarpMessage  *warp.Message
+	WarpVerified bool
+	// BlockSeed, if not empty, is available to [Execute] (see
+	// [chain.GetBlockSeed]).
+	BlockSeed ids.ID
+
+	// ExpectedSuccess is the expected [success] returned by [Execute].
+	ExpectedSuccess bool
+	// ExpectedOutput, if non-nil, is the expected [output] returned by
+	// [Execute] (or the message of the failure it returned).
+	ExpectedOutput []byte
+	// ExpectedStatus, if not [chain.StatusFailed], is the expected status of
+	// a failure returned by [Execute] (see [chain.Fail]).
+	ExpectedStatus chain.Status
+}
+
+// RunActionTest checks that [test.Action] conforms to the invariants that
+// the hypersdk relies on but cannot enforce at compile time:
+//
+//   - Marshal writes exactly [Size] bytes and round-trips through [Unmarshal]
+//   - StateKeys are suffixed with the chunks reported by StateKeysMaxChunks
+//   - Execute only touches keys returned by StateKeys and never writes more
+//     chunks to a key than its suffix allows
+//   - Execute consumes no more than MaxComputeUnits and only emits a warp
+//     message if OutputsWarpMessage is set
+//   - Execute is deterministic
+//   - ValidRange is well-formed and Execute behaves identically at each
+//     boundary of the range
+func RunActionTest(t *testing.T, test ActionTest) {
+	t.Run(test.Name, func(t *testing.T) {
+		checkMarshal(t, test)
+		checkStateKeys(t, test)
+
+		timestamps := []int64{test.Timestamp}
+		start, end := test.Action.ValidRange(test.Rules)
+		if start >= 0 && end >= 0 {
+			require.LessOrEqual(t, start, end, "invalid valid range")
+		}
+		if start >= 0 {
+			timestamps = append(timestamps, start)
+		}
+		if end >= 0 {
+			timestamps = append(timestamps, end)
+		}
+		for _, timestamp := range timestamps {
+			first := checkExecute(t, test, timestamp)
+			second := checkExecute(t, test, timestamp)
+			require.Equal(t, first, second, "non-deterministic execution at %d", timestamp)
+		}
+	})
+}
+
+func checkMarshal(t *testing.T, test ActionTest) {
+	require := require.New(t)
+
+	size := test.Action.Size()
+	p := codec.NewWriter(size, consts.NetworkSizeLimit)
+	test.Action.Marshal(p)
+	require.NoError(p.Err())
+	b := p.Bytes()
+	require.Len(b, size, "Size does not match marshaled length")
+
+	r := codec.NewReader(b, consts.NetworkSizeLimit)
+	parsed, err := test.Unmarshal(r, test.WarpMessage)
+	if test.ExpectedUnmarshalErr != nil {
+		require.ErrorIs(err, test.ExpectedUnmarshalErr)
+		return
+	}
+	require.NoError(err)
+	require.NoError(r.Err())
+	require.True(r.Empty(), "Unmarshal did not consume all bytes")
+	require.Equal(test.Action.GetTypeID(), parsed.GetTypeID())
+
+	p = codec.NewWriter(parsed.Size(), consts.NetworkSizeLimit)
+	parsed.Marshal(p)
+	require.NoError(p.Err())
+	require.Equal(b, p.Bytes(), "round-trip changed encoding")
+}
+
+func checkStateKeys(t *testing.T, test ActionTest) {
+	require := require.New(t)
+
+	stateKeys := test.Action.StateKeys(test.Actor, test.TxID)
+	maxChunks := test.Action.StateKeysMaxChunks()
+	require.Len(maxChunks, len(stateKeys), "StateKeysMaxChunks does not match StateKeys")
+	for i, k := range stateKeys {
+		chunks, ok := keys.MaxChunks([]byte(k))
+		require.True(ok, "state key %x is missing chunk suffix", k)
+		require.Equal(maxChunks[i], chunks, "state key %x has unexpected chunk suffix", k)
+	}
+}
+
+type executionResult struct {
+	Success      bool
+	Status       chain.Status
+	ComputeUnits uint64
+	Output       []byte
+	WarpMessage  *warp.UnsignedMessage
+	State        map[string][]byte
+}
+
+func checkExecute(t *testing.T, test ActionTest, timestamp int64) *executionResult {
+	require := require.New(t)
+
+	scope := set.Of(test.Action.StateKeys(test.Actor, test.TxID)...)
+	mu := newRecorder(test.State)
+	ctx := context.Background()
+	if test.BlockSeed != ids.Empty {
+		ctx = chain.WithBlockSeed(ctx, test.BlockSeed)
+	}
+	success, computeUnits, output, warpMessage, err := test.Action.Execute(
+		ctx,
+		test.Rules,
+		mu,
+		timestamp,
+		test.Actor,
+		test.TxID,
+		test.WarpVerified,
+	)
+	status := chain.StatusFailed
+	if err != nil {
+		var ok bool
+		status, output, ok = chain.AsFailure(err)
+		require.True(ok, "Execute returned a fatal error: %v", err)
+		require.False(success, "Execute succeeded with a failure")
+	} else if success {
+		status = chain.StatusSuccess
+	}
+	require.Equal(test.ExpectedSuccess, success, "unexpected success (output=%s)", output)
+	if test.ExpectedOutput != nil {
+		require.Equal(test.ExpectedOutput, output)
+	}
+	if test.ExpectedStatus != chain.StatusFailed {
+		require.Equal(test.ExpectedStatus, status, "unexpected status (output=%s)", output)
+	}
+
+	missing := []string{}
+	for k := range mu.touched {
+		if !scope.Contains(k) {
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(missing, "Execute touched keys not returned by StateKeys")
+	require.Empty(mu.invalid, "Execute wrote values larger than the chunk suffix of their keys")
+
+	require.LessOrEqual(computeUnits, test.Action.MaxComputeUnits(test.Rules), "Execute exceeded MaxComputeUnits")
+	if !test.Action.OutputsWarpMessage() {
+		require.Nil(warpMessage, "Execute emitted a warp message but OutputsWarpMessage is false")
+	} else if warpMessage != nil {
+		chunks, ok := keys.NumChunks(warpMessage.Bytes())
+		require.True(ok)
+		require.LessOrEqual(chunks, uint16(chain.MaxOutgoingWarpChunks), "warp message is too large")
+	}
+	return &executionResult{
+		Success:      success,
+		Status:       status,
+		ComputeUnits: computeUnits,
+		Output:       output,
+		WarpMessage:  warpMessage,
+		State:        mu.storage,
+	}
+}
+
+var _ state.Mutable = (*recorder)(nil)
+
+// recorder is an unrestricted [state.Mutable] that records every key
+// accessed, so that accesses outside of the declared scope can be reported
+// together instead of failing on the first one.
+type recorder struct {
+	storage map[string][]byte
+	touched set.Set[string]
+	invalid []string
+}
+
+func newRecorder(initial map[string][]byte) *recorder {
+	storage := make(map[string][]byte, len(initial))
+	for k, v := range initial {
+		storage[k] = bytes.Clone(v)
+	}
+	return &recorder{
+		storage: storage,
+		touched: set.Set[string]{},
+	}
+}
+
+func (r *recorder) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	k := string(key)
+	r.touched.Add(k)
+	v, ok := r.storage[k]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return bytes.Clone(v), nil
+}
+
+func (r *recorder) Insert(_ context.Context, key []byte, value []byte) error {
+	k := string(key)
+	r.touched.Add(k)
+	if !keys.VerifyValue(key, value) {
+		r.invalid = append(r.invalid, k)
+	}
+	r.storage[k] = bytes.Clone(value)
+	return nil
+}
+
+func (r *recorder) Remove(_ context.Context, key []byte) error {
+	k := string(key)
+	r.touched.Add(k)
+	delete(r.storage, k)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaintest
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+var (
+	_ chain.Hooks        = (*Controller)(nil)
+	_ chain.EpochHandler = (*Controller)(nil)
+)
+
+// Controller wraps the [vm.Controller] of a VM so that tests can intercept
+// the execution of its blocks and transactions with [chain.Hooks] and
+// [chain.EpochHandler]. Each hook is a no-op unless its function is set.
+//
+// Hooks may be invoked concurrently (see [chain.Hooks]), so functions that
+// record their calls must be safe for concurrent use. Optional interfaces
+// implemented by the wrapped controller (like [vm.BeneficiaryController])
+// are hidden by the wrapper.
+type Controller struct {
+	vm.Controller
+
+	PreBlockF    func(ctx context.Context, r chain.Rules, im state.Immutable, timestamp int64, height uint64) error
+	PreExecuteF  func(ctx context.Context, r chain.Rules, im state.Immutable, timestamp int64, height uint64, tx *chain.Transaction) error
+	PostExecuteF func(ctx context.Context, r chain.Rules, im state.Immutable, timestamp int64, height uint64, tx *chain.Transaction, result *chain.Result)
+	PostBlockF   func(ctx context.Context, r chain.Rules, timestamp int64, height uint64, txs []*chain.Transaction, results []*chain.Result)
+
+	EpochStateKeysF func(epoch uint64) []string
+	EndEpochF       func(ctx context.Context, r chain.Rules, mu state.Mutable, timestamp int64, epoch uint64) error
+}
+
+func (c *Controller) PreBlock(ctx context.Context, r chain.Rules, im state.Immutable, timestamp int64, height uint64) error {
+	if c.PreBlockF == nil {
+		return nil
+	}
+	return c.PreBlockF(ctx, r, im, timestamp, height)
+}
+
+func (c *Controller) PreExecute(
+	ctx context.Context,
+	r chain.Rules,
+	im state.Immutable,
+	timestamp int64,
+	height uint64,
+	tx *chain.Transaction,
+) error {
+	if c.PreExecuteF == nil {
+		return nil
+	}
+	return c.PreExecuteF(ctx, r, im, timestamp, height, tx)
+}
+
+func (c *Controller) PostExecute(
+	ctx context.Context,
+	r chain.Rules,
+	im state.Immutable,
+	timestamp int64,
+	height uint64,
+	tx *chain.Transaction,
+	result *chain.Result,
+) {
+	if c.PostExecuteF != nil {
+		c.PostExecuteF(ctx, r, im, timestamp, height, tx, result)
+	}
+}
+
+func (c *Controller) PostBlock(
+	ctx context.Context,
+	r chain.Rules,
+	timestamp int64,
+	height uint64,
+	txs []*chain.Transaction,
+	results []*chain.Result,
+) {
+	if c.PostBlockF != nil {
+		c.PostBlockF(ctx, r, timestamp, height, txs, results)
+	}
+}
+
+func (c *Controller) EpochStateKeys(epoch uint64) []string {
+	if c.EpochStateKeysF == nil {
+		return nil
+	}
+	return c.EpochStateKeysF(epoch)
+}
+
+func (c *Controller) EndEpoch(ctx context.Context, r chain.Rules, mu state.Mutable, timestamp int64, epoch uint64) error {
+	if c.EndEpochF == nil {
+		return nil
+	}
+	return c.EndEpochF(ctx, r, mu, timestamp, epoch)
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaintest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// Invariant checks a property that must hold across the execution of [tx]
+// (e.g. that the total supply of an asset is conserved). [before] and
+// [after] are the full state before and after execution.
+type Invariant func(tx *chain.Transaction, result *chain.Result, before, after map[string][]byte) error
+
+// TxFuzzer feeds arbitrary bytes through [chain.UnmarshalTx] and executes
+// every transaction that parses against a scratch copy of [State].
+//
+// Authorization is not verified, so fuzzed transactions do not need valid
+// signatures to reach execution.
+//
+// Usage:
+//
+//	func FuzzTx(f *testing.F) {
+//		fuzzer := &chaintest.TxFuzzer{...}
+//		f.Add(seedTx.Bytes())
+//		f.Fuzz(fuzzer.Fuzz)
+//	}
+type TxFuzzer struct {
+	Parser       chain.Parser
+	StateManager chain.StateManager
+
+	// State is the state transactions are executed against. Keys must
+	// include their chunk suffix.
+	State     map[string][]byte
+	Timestamp int64
+	Height    uint64
+
+	// UnitPrices are used to compute the fee of each transaction.
+	UnitPrices chain.Dimensions
+
+	Invariants []Invariant
+}
+
+// Fuzz checks that [data] can be processed without panicking and, if it
+// parses into a transaction, that the encoding is canonical and that
+// execution satisfies all [Invariants].
+func (f *TxFuzzer) Fuzz(t *testing.T, data []byte) {
+	require := require.New(t)
+
+	actionRegistry, authRegistry := f.Parser.Registry()
+	p := codec.NewReader(data, consts.NetworkSizeLimit)
+	tx, err := chain.UnmarshalTx(p, actionRegistry, authRegistry)
+	if err != nil || p.Err() != nil {
+		return
+	}
+
+	// A transaction must have exactly one valid encoding, otherwise the same
+	// transaction could be included twice under different IDs.
+	canonical := chain.NewTx(tx.Base, tx.WarpMessage, tx.Action)
+	canonical.Auth = tx.Auth
+	w := codec.NewWriter(tx.Size(), consts.NetworkSizeLimit)
+	require.NoError(canonical.Marshal(w))
+	require.Equal(tx.Bytes(), w.Bytes(), "non-canonical transaction encoding")
+
+	// Execute transaction
+	ctx := context.Background()
+	r := f.Parser.Rules(f.Timestamp)
+	stateKeys, err := tx.StateKeys(f.StateManager, r)
+	if err != nil {
+		return
+	}
+	var (
+		reads   = make(map[string]uint16, len(stateKeys))
+		storage = make(map[string][]byte, len(stateKeys))
+	)
+	for k := range stateKeys {
+		v, ok := f.State[k]
+		if !ok {
+			reads[k] = 0
+			continue
+		}
+		chunks, ok := keys.NumChunks(v)
+		require.True(ok)
+		reads[k] = chunks
+		storage[k] = v
+	}
+	feeManager := chain.NewFeeManager(nil)
+	for i := chain.Dimension(0); int(i) < chain.FeeDimensions(); i++ {
+		feeManager.SetUnitPrice(i, f.UnitPrices[i])
+	}
+	ts := tstate.New(1)
+	tsv := ts.NewView(stateKeys, storage)
+	if err := tx.PreExecute(ctx, feeManager, f.StateManager, r, tsv, f.Timestamp, f.Height); err != nil {
+		return
+	}
+	result, err := tx.Execute(ctx, feeManager, reads, f.StateManager, r, tsv, f.Timestamp, false)
+	require.NoError(err, "execution failed after PreExecute succeeded")
+
+	// Reconstruct the full state after execution
+	after := make(map[string][]byte, len(f.State))
+	for k, v := range f.State {
+		after[k] = bytes.Clone(v)
+	}
+	for k := range stateKeys {
+		v, err := tsv.GetValue(ctx, []byte(k))
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			delete(after, k)
+		case err != nil:
+			require.NoError(err)
+		default:
+			after[k] = v
+		}
+	}
+	for _, invariant := range f.Invariants {
+		require.NoError(invariant(tx, result, f.State, after))
+	}
+}
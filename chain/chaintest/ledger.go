@@ -0,0 +1,95 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaintest
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// LedgerPrefix is the first byte of all keys stored by [Ledger] and
+// [AssetLedger]. Modules tested with them must use another prefix.
+const LedgerPrefix = 0x0
+
+// Ledger stores a single balance for each address, for testing modules that
+// move the native asset of a VM.
+type Ledger struct{}
+
+func (Ledger) BalanceKey(addr codec.Address) []byte {
+	return keys.EncodeChunks(append([]byte{LedgerPrefix}, addr[:]...), 1)
+}
+
+func (Ledger) BalanceChunks() uint16 {
+	return 1
+}
+
+// Balance returns the balance of [addr] (or 0 if it has none).
+func (l Ledger) Balance(ctx context.Context, im state.Immutable, addr codec.Address) uint64 {
+	return getBalance(ctx, im, l.BalanceKey(addr))
+}
+
+func (l Ledger) AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	return addBalance(ctx, mu, l.BalanceKey(addr), amount)
+}
+
+func (l Ledger) SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	return subBalance(ctx, mu, l.BalanceKey(addr), amount)
+}
+
+// AssetLedger stores a balance of each asset for each address, for testing
+// modules that move multiple assets.
+type AssetLedger struct{}
+
+func (AssetLedger) BalanceKey(addr codec.Address, asset ids.ID) []byte {
+	k := append([]byte{LedgerPrefix}, addr[:]...)
+	return keys.EncodeChunks(append(k, asset[:]...), 1)
+}
+
+func (AssetLedger) BalanceChunks() uint16 {
+	return 1
+}
+
+// Balance returns the balance of [asset] of [addr] (or 0 if it has none).
+func (l AssetLedger) Balance(ctx context.Context, im state.Immutable, addr codec.Address, asset ids.ID) uint64 {
+	return getBalance(ctx, im, l.BalanceKey(addr, asset))
+}
+
+func (l AssetLedger) AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, asset ids.ID, amount uint64) error {
+	return addBalance(ctx, mu, l.BalanceKey(addr, asset), amount)
+}
+
+func (l AssetLedger) SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, asset ids.ID, amount uint64) error {
+	return subBalance(ctx, mu, l.BalanceKey(addr, asset), amount)
+}
+
+func getBalance(ctx context.Context, im state.Immutable, k []byte) uint64 {
+	v, err := im.GetValue(ctx, k)
+	if err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+func addBalance(ctx context.Context, mu state.Mutable, k []byte, amount uint64) error {
+	bal, err := smath.Add64(getBalance(ctx, mu, k), amount)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, bal))
+}
+
+func subBalance(ctx context.Context, mu state.Mutable, k []byte, amount uint64) error {
+	bal, err := smath.Sub(getBalance(ctx, mu, k), amount)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, bal))
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chaintest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ state.Mutable = State{}
+
+// State is an unrestricted, in-memory [state.Mutable]. Keys must include
+// their chunk suffix.
+type State map[string][]byte
+
+func (s State) GetValue(_ context.Context, k []byte) ([]byte, error) {
+	v, ok := s[string(k)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s State) Insert(_ context.Context, k []byte, v []byte) error {
+	s[string(k)] = v
+	return nil
+}
+
+func (s State) Remove(_ context.Context, k []byte) error {
+	delete(s, string(k))
+	return nil
+}
+
+// Execute executes [action] by [actor] at [timestamp] against [mu] (with no
+// rules) and requires it to succeed. It returns the output of [action].
+func Execute(t *testing.T, mu state.Mutable, action chain.Action, timestamp int64, actor codec.Address) []byte {
+	success, _, output, _, err := action.Execute(context.Background(), nil, mu, timestamp, actor, ids.Empty, false)
+	require.NoError(t, err)
+	require.True(t, success, string(output))
+	return output
+}
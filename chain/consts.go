@@ -23,17 +23,40 @@ const (
 	// MaxWarpMessages is the maximum number of warp messages allows in a single
 	// block.
 	MaxWarpMessages = 64
-	// MaxIncomingWarpChunks is the number of chunks stored for an incoming warp message.
-	MaxIncomingWarpChunks = 0
+	// MaxCancelledTxs is the maximum number of unexpired transactions a
+	// sponsor can have cancelled at once (see [CancelTx]).
+	MaxCancelledTxs = 4
+	// MaxBundleTxs is the maximum number of transactions in a [Bundle].
+	MaxBundleTxs = 16
+	// MaxIncomingWarpChunks is the number of chunks stored for an incoming warp message
+	// (see [IncomingWarpKey]).
+	MaxIncomingWarpChunks = 1
+	// WarpReplayBucketDuration is how long (in milliseconds) of record expiries
+	// are listed in each bucket (see [pruneWarpReplays]).
+	WarpReplayBucketDuration = 10_000
+	// MaxWarpReplayBucketChunks is the number of chunks stored for a bucket of
+	// expiring warp replay records (64 bytes per record).
+	MaxWarpReplayBucketChunks = 1024
+	// MaxWarpReplayPrunes is the maximum number of buckets of expired warp
+	// replay records that are pruned in a single block.
+	MaxWarpReplayPrunes = 4
+	// WarpReplayCursorChunks is the number of chunks stored for the next and
+	// last buckets of warp replay records to prune.
+	WarpReplayCursorChunks = 1
 	// MaxOutgoingWarpChunks is the max number of chunks that can be stored for an outgoing warp message.
 	//
 	// This is defined as a constant because storage of warp messages is handled by the hypersdk,
 	// not the [Controller]. In this mechanism, we frequently query warp messages by TxID across
 	// ranges (so, we can't expose a way to modify this over time).
-	MaxOutgoingWarpChunks = 4
-	HeightKeyChunks       = 1
-	TimestampKeyChunks    = 1
-	FeeKeyChunks          = 8 // 96 (per dimension) * 5 (num dimensions)
+	MaxOutgoingWarpChunks   = 4
+	HeightKeyChunks         = 1
+	TimestampKeyChunks      = 1
+	FeeKeyChunks            = 13 // 96 (per dimension) * 8 (max dimensions)
+	LegacyFeeKeyChunks      = 8  // 96 (per dimension) * 5 (default dimensions)
+	BurnKeyChunks           = 2  // 8 (per dimension) * 8 (max dimensions)
+	NonceKeyChunks          = 1
+	CancelKeyChunks         = 3 // 40 (per cancelled tx) * 4 (max cancelled txs)
+	RulesOverridesKeyChunks = 2 // 1 (flags) + 8 (min block gap) + 8 (validity window) + 8 * 8 (max block units)
 )
 
 func HeightKey(prefix []byte) []byte {
@@ -47,3 +70,13 @@ func TimestampKey(prefix []byte) []byte {
 func FeeKey(prefix []byte) []byte {
 	return keys.EncodeChunks(prefix, FeeKeyChunks)
 }
+
+// LegacyFeeKey is where the fee state was stored before VMs could register
+// custom fee dimensions (see [GetFeeManager]).
+func LegacyFeeKey(prefix []byte) []byte {
+	return keys.EncodeChunks(prefix, LegacyFeeKeyChunks)
+}
+
+func BurnKey(prefix []byte) []byte {
+	return keys.EncodeChunks(prefix, BurnKeyChunks)
+}
@@ -42,15 +42,21 @@ type Metrics interface {
 	RecordStateChanges(int)
 	RecordStateOperations(int)
 	RecordBuildCapped()
+	RecordBuildPhase(BuildPhase, time.Duration)
+	RecordTxsDropped(reason string, count int)
 	RecordEmptyBlockBuilt()
 	RecordClearedMempool()
+	RecordFCFSDeviation()
+	RecordAccessListMismatch()
+	RecordStateCacheHit()
+	RecordStateCacheMiss()
 	GetExecutorBuildRecorder() executor.Metrics
 	GetExecutorVerifyRecorder() executor.Metrics
 }
 
 type Monitoring interface {
 	Tracer() trace.Tracer
-	Logger() logging.Logger
+	ModuleLogger(module string) logging.Logger
 }
 
 type VM interface {
@@ -71,14 +77,42 @@ type VM interface {
 	GetVerifyContext(ctx context.Context, blockHeight uint64, parent ids.ID) (VerifyContext, error)
 
 	State() (merkledb.MerkleDB, error)
+	StateCache() *StateCache // nil if disabled
 	StateManager() StateManager
 	ValidatorState() validators.State
 
 	Mempool() Mempool
+	Bundles() BundlePool // nil if bundles aren't accepted
 	IsRepeat(context.Context, []*Transaction, set.Bits, bool) set.Bits
 	GetTargetBuildDuration() time.Duration
 	GetTransactionExecutionCores() int
 
+	// GetFCFSOrdering returns true if blocks built by this node should
+	// include transactions strictly in the order they were received and
+	// commit to when they were received (see [StatefulBlock.Arrivals]).
+	GetFCFSOrdering() bool
+
+	// GetExecutionTrace returns true if each verified block should be
+	// re-executed sequentially and compared to its parallel execution.
+	GetExecutionTrace() bool
+
+	// Beneficiary is the address credited with the builder's share of the
+	// fees in blocks built by this node. If it is [codec.EmptyAddress], all
+	// fees are burned.
+	Beneficiary() codec.Address
+
+	// Hooks returns the [Hooks] invoked around execution (or nil if there
+	// are none).
+	Hooks() Hooks
+
+	// GetStateBranchFactor returns the branch factor of the state trie, which
+	// is needed to verify a [BlockWitness].
+	GetStateBranchFactor() merkledb.BranchFactor
+
+	// EpochHandler returns the [EpochHandler] invoked at the end of each epoch
+	// (or nil if there is none).
+	EpochHandler() EpochHandler
+
 	Verified(context.Context, *StatelessBlock)
 	Rejected(context.Context, *StatelessBlock)
 	Accepted(context.Context, *StatelessBlock)
@@ -91,15 +125,60 @@ type VM interface {
 	StateReady() bool
 }
 
+// Hooks are invoked around the execution of each block and transaction
+// (whether it is being built, verified, or is a candidate for the mempool).
+//
+// Hooks may be invoked multiple times for the same block and for blocks that
+// are never accepted, so any accounting should only be finalized once a block
+// is accepted. Because transactions are executed in parallel, [PreExecute] and
+// [PostExecute] may be invoked concurrently.
+type Hooks interface {
+	// PreBlock is invoked before any transaction in a block is executed with
+	// the state of its parent. If an error is returned, the block is invalid
+	// (and will not be built).
+	PreBlock(ctx context.Context, r Rules, im state.Immutable, timestamp int64, height uint64) error
+
+	// PreExecute is invoked before [tx] is executed (after its fees are checked)
+	// with a view limited to its state keys. If an error is returned, [tx] is
+	// handled as if it failed [Transaction.PreExecute]: it is not added to the
+	// mempool or to built blocks and any block that includes it is invalid.
+	PreExecute(ctx context.Context, r Rules, im state.Immutable, timestamp int64, height uint64, tx *Transaction) error
+
+	// PostExecute is invoked after [tx] is executed with its [Result] and a
+	// view of its state keys (including its changes). In blocks being built,
+	// it is only invoked for transactions that are included.
+	PostExecute(ctx context.Context, r Rules, im state.Immutable, timestamp int64, height uint64, tx *Transaction, result *Result)
+
+	// PostBlock is invoked after all transactions in a block are executed with
+	// their [Result]s (in block order).
+	PostBlock(ctx context.Context, r Rules, timestamp int64, height uint64, txs []*Transaction, results []*Result)
+}
+
+// EpochHandler is invoked at the end of each epoch, after the last block of
+// the epoch is executed (but before its fees are distributed). Like
+// transactions, it can only access the keys it declares up front.
+//
+// Because it is executed by every node that processes the block, it must be
+// deterministic (and should be cheap, as it isn't charged any fees).
+type EpochHandler interface {
+	// EpochStateKeys returns the keys that [EndEpoch] may access for [epoch].
+	EpochStateKeys(epoch uint64) []string
+
+	// EndEpoch is invoked with the last block of [epoch]. If an error is
+	// returned, the block is invalid (and will not be built).
+	EndEpoch(ctx context.Context, r Rules, mu state.Mutable, timestamp int64, epoch uint64) error
+}
+
 type VerifyContext interface {
 	View(ctx context.Context, verify bool) (state.View, error)
-	IsRepeat(ctx context.Context, oldestAllowed int64, txs []*Transaction, marker set.Bits, stop bool) (set.Bits, error)
+	IsRepeat(ctx context.Context, oldestAllowed int64, oldestAllowedHeight uint64, txs []*Transaction, marker set.Bits, stop bool) (set.Bits, error)
 }
 
 type Mempool interface {
 	Len(context.Context) int  // items
 	Size(context.Context) int // bytes
 	Add(context.Context, []*Transaction)
+	IDs(context.Context) []ids.ID
 
 	Top(
 		context.Context,
@@ -110,9 +189,19 @@ type Mempool interface {
 	StartStreaming(context.Context)
 	PrepareStream(context.Context, int)
 	Stream(context.Context, int) []*Transaction
+	LimitStream(context.Context, int, uint64)
 	FinishStreaming(context.Context, []*Transaction) int
 }
 
+// BundlePool holds the [Bundle]s to include at the start of built blocks.
+type BundlePool interface {
+	Add(context.Context, []*Bundle)
+
+	StartStreaming(context.Context)
+	Stream(context.Context, int) []*Bundle
+	FinishStreaming(context.Context, []*Bundle) int
+}
+
 type Rules interface {
 	// Should almost always be constant (unless there is a fork of
 	// a live network)
@@ -123,8 +212,21 @@ type Rules interface {
 	GetMinEmptyBlockGap() int64 // in milliseconds
 	GetValidityWindow() int64   // in milliseconds
 
+	// GetHeightValidityWindow returns how many blocks ahead of the block that
+	// includes it a transaction may expire, if it expires at a height (see
+	// [Base.Height]). If it returns 0, transactions can only expire at a
+	// timestamp.
+	GetHeightValidityWindow() uint64
+
 	GetMinUnitPrice() Dimensions
 	GetUnitPriceChangeDenominator() Dimensions
+
+	// GetMinFee is the minimum fee charged to each transaction that pays
+	// fees, regardless of unit prices, so that tiny transactions aren't
+	// nearly free when unit prices are at [GetMinUnitPrice]. If it is 0,
+	// transactions only pay for the units they consume.
+	GetMinFee() uint64
+
 	GetWindowTargetUnits() Dimensions
 	GetMaxBlockUnits() Dimensions
 
@@ -133,6 +235,88 @@ type Rules interface {
 	GetWarpComputeUnitsPerSigner() uint64
 	GetOutgoingWarpComputeUnits() uint64
 
+	// GetActionComputeUnits returns the compute units charged for executing an
+	// [Action] with [typeID], if the chain overrides them. If not overridden,
+	// [Action.MaxComputeUnits] and the units returned by [Action.Execute] are
+	// used instead.
+	GetActionComputeUnits(typeID uint8) (uint64, bool)
+
+	// IsActionDisabled returns true if [Action]s with [typeID] can no longer be
+	// included in blocks. As [Rules] are fetched for the timestamp of each
+	// block, disabling an action doesn't affect the replay of blocks
+	// accepted before.
+	IsActionDisabled(typeID uint8) bool
+
+	// GetActionDeprecationHeight returns the height starting at which
+	// [version] of the [Action] with [typeID] can no longer be included in
+	// blocks, if it is deprecated (see [Versioned]). Types that are not
+	// versioned only have version 0.
+	GetActionDeprecationHeight(typeID uint8, version uint8) (uint64, bool)
+	// GetAuthDeprecationHeight is like [GetActionDeprecationHeight] for
+	// [Auth].
+	GetAuthDeprecationHeight(typeID uint8, version uint8) (uint64, bool)
+
+	// GetBuilderFeeShare is the percentage (0-100) of the fees collected in a
+	// block that is credited to its [StatefulBlock.Beneficiary]. The rest of
+	// the fees are burned.
+	GetBuilderFeeShare() uint64
+
+	// GetEpochLength is the number of blocks in each epoch (see [Epoch]). If
+	// it is 0, the chain has no epochs.
+	//
+	// Changing the epoch length renumbers all epochs, so it should only be
+	// changed at a height where the epoch boundaries of both lengths align.
+	GetEpochLength() uint64
+
+	// GetBlockReward is the amount of the native asset minted by the block at
+	// [height]. It is credited to the [StatefulBlock.Beneficiary] of the
+	// block or, if it has none, to [GetBlockRewardPool]. If both are empty,
+	// nothing is minted.
+	GetBlockReward(height uint64) uint64
+	GetBlockRewardPool() codec.Address
+
+	// GetValidatorSnapshots returns true if the last block of each epoch
+	// takes a [ValidatorSnapshot] of the subnet (at the P-Chain height of its
+	// block context) and stores a warp message attesting to it in state.
+	// Snapshots require epochs (see [GetEpochLength]).
+	GetValidatorSnapshots() bool
+
+	// GetBlockWitnesses returns true if each block must carry a [BlockWitness]
+	// proving every key it reads against [StatefulBlock.StateRoot].
+	GetBlockWitnesses() bool
+
+	// GetSequentialNonces returns true if transactions are protected from
+	// replay by the [Base.Nonce] of their sponsor instead of by expiring (see
+	// [Base.SequentialNonce]). When enabled, transactions that expire are
+	// rejected. Sequential nonces require the [StateManager] to implement
+	// [NonceManager].
+	GetSequentialNonces() bool
+
+	// GetTxCancellation returns true if sponsors can cancel their pending
+	// transactions (see [CancelTx]). When enabled, every transaction reads
+	// the [CancelKey] of its sponsor (if the [StateManager] implements
+	// [Canceller]), so it should only be enabled by VMs that let sponsors
+	// cancel transactions.
+	GetTxCancellation() bool
+
+	// GetActionFeePayer returns the account charged for the fees of
+	// transactions with an [Action] of [typeID] (instead of their sponsor),
+	// if overridden. If the account is [codec.EmptyAddress], these
+	// transactions are exempt from fees. This allows protocol housekeeping
+	// (like executing governance decisions or processing a scheduled queue)
+	// to be included without a funded sponsor.
+	//
+	// Because anyone can include these transactions at no cost, the [Action]
+	// must limit what they can do (and how often).
+	GetActionFeePayer(typeID uint8) (codec.Address, bool)
+
+	// IsFeatureActive returns true if the feature flag [feature] (see
+	// package fork) is active at the timestamp these [Rules] were fetched
+	// for. Actions and auth (during execution) and hooks (during block
+	// verification) check it to change consensus-relevant behavior at the
+	// same block on every validator.
+	IsFeatureActive(feature string) bool
+
 	// Invariants:
 	// * Controllers must manage the max key length and max value length (max network
 	//   limit is ~2MB)
@@ -153,7 +337,20 @@ type Rules interface {
 	GetStorageKeyWriteUnits() uint64
 	GetStorageValueWriteUnits() uint64 // per chunk
 
+	// GetWarpConfig returns whether warp messages from [sourceChainID] can be
+	// imported and the fraction (numerator, denominator) of the stake weight
+	// of its subnet that must sign them (see [VerifyWarpQuorum]).
 	GetWarpConfig(sourceChainID ids.ID) (bool, uint64, uint64)
+	// GetWarpMaxSigners is the most validators that can sign a warp message
+	// imported by a transaction (0 for no limit). Each signer adds to the cost
+	// of verifying a message, so chains importing from subnets with large
+	// validator sets can cap it (and lower their quorum to remain live).
+	GetWarpMaxSigners() int
+	// GetWarpReplayRetention is how long (in milliseconds) after it was sent
+	// that a warp message can be imported (0 for no limit). It only applies
+	// to messages imported by a [WarpTimestamper], whose records are pruned
+	// once they are rejected. Records of other messages are kept forever.
+	GetWarpReplayRetention() int64
 
 	FetchCustom(string) (any, bool)
 }
@@ -162,10 +359,13 @@ type MetadataManager interface {
 	HeightKey() []byte
 	TimestampKey() []byte
 	FeeKey() []byte
+	BurnKey() []byte // total fees burned in each dimension
 }
 
 type WarpManager interface {
-	IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) []byte
+	// IncomingWarpPrefix is the prefix of the keys that record imported warp
+	// messages (see [IncomingWarpKey]). No other key should start with it.
+	IncomingWarpPrefix() []byte
 	OutgoingWarpKeyPrefix(txID ids.ID) []byte
 }
 
@@ -192,6 +392,90 @@ type FeeHandler interface {
 	//
 	// Refund is only invoked if [amount] > 0.
 	Refund(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error
+
+	// Credit adds [amount] to [addr] when the fees collected in a block are
+	// distributed to its builder (see [Rules.GetBuilderFeeShare]).
+	//
+	// Unlike [Refund], Credit may create new keys.
+	//
+	// Credit is only invoked if [amount] > 0.
+	Credit(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error
+}
+
+// Minter can be implemented by a [StateManager] to control how block rewards
+// are minted (e.g. to track the supply of the native asset). If it is not
+// implemented, rewards are added with [FeeHandler.Credit].
+type Minter interface {
+	// MintStateKeys is a full enumeration of all keys that could be touched
+	// when minting to [addr].
+	MintStateKeys(addr codec.Address) []string
+
+	// Mint creates [amount] of the native asset and credits it to [addr].
+	//
+	// Mint is only invoked if [amount] > 0.
+	Mint(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error
+}
+
+// FeeConverter can be implemented by a [StateManager] to let transactions
+// pay fees in an asset other than the native one (see [Base.FeeAsset]). The
+// fees of these transactions are still computed in the native asset and are
+// converted when they are deducted (e.g. at a rate stored in state).
+type FeeConverter interface {
+	// FeeAssetStateKeys is a full enumeration of all keys that could be
+	// touched when [addr] pays fees in [asset] (including any keys read to
+	// convert them). They replace [FeeHandler.SponsorStateKeys] for these
+	// transactions.
+	FeeAssetStateKeys(r Rules, addr codec.Address, asset ids.ID) []string
+
+	// CanDeductAsset returns the amount of [asset] that [DeductAsset] would
+	// remove for [amount] (of the native asset) or an error if it cannot be
+	// paid by [addr].
+	CanDeductAsset(ctx context.Context, r Rules, addr codec.Address, asset ids.ID, im state.Immutable, timestamp int64, amount uint64) (uint64, error)
+
+	// DeductAsset removes [amount] (of the native asset) converted to [asset]
+	// from [addr] during transaction execution and returns the amount of
+	// [asset] removed.
+	DeductAsset(ctx context.Context, r Rules, addr codec.Address, asset ids.ID, mu state.Mutable, timestamp int64, amount uint64) (uint64, error)
+
+	// RefundAsset returns [converted] of [asset] to [addr] after transaction
+	// execution for the [amount] (of the native asset) of fees that were not
+	// used. [converted] is computed at the rate of [DeductAsset], so that
+	// actions can't change the rate of their own refund.
+	//
+	// Like [FeeHandler.Refund], RefundAsset can't create new keys and is only
+	// invoked if [amount] > 0.
+	RefundAsset(ctx context.Context, r Rules, addr codec.Address, asset ids.ID, mu state.Mutable, amount uint64, converted uint64) error
+}
+
+// NonceManager can be implemented by a [StateManager] to store the next nonce
+// of each account (see [Rules.GetSequentialNonces]).
+type NonceManager interface {
+	// NonceKey is the key that stores the next nonce of [addr]. It should not
+	// be suffixed with the number of chunks (see [NonceKeyChunks]).
+	NonceKey(addr codec.Address) []byte
+}
+
+// Canceller can be implemented by a [StateManager] to let sponsors void their
+// pending transactions before they are included (see [CancelTx]).
+type Canceller interface {
+	// CancelKey is the key that stores the transactions cancelled by
+	// [sponsor]. It should not be suffixed with the number of chunks (see
+	// [CancelKeyChunks]).
+	//
+	// This key is added to the state keys of every transaction.
+	CancelKey(sponsor codec.Address) []byte
+}
+
+// RulesOverrider can be implemented by a [StateManager] to let actions
+// override some [Rules] by writing to state (see [RulesOverrides]).
+type RulesOverrider interface {
+	// RulesOverridesKey is the key that stores the [RulesOverrides] of the
+	// chain. It should not be suffixed with the number of chunks (see
+	// [RulesOverridesKeyChunks]).
+	//
+	// This key is read by every block, so actions that write it should be
+	// restricted to some authority.
+	RulesOverridesKey() []byte
 }
 
 // StateManager allows [Chain] to safely store certain types of items in state
@@ -224,6 +508,18 @@ type Object interface {
 	Size() int
 }
 
+// Versioned is implemented by an [Action] or [Auth] whose type is registered
+// with [codec.TypeParser.RegisterVersion]. Its version is encoded after its
+// type ID (and is not included in [Object.Size]).
+//
+// Versions of a type can be deprecated at a height by [Rules] so that new
+// encodings can replace old ones without breaking the parsing of historical
+// blocks. [AuthFactory]s that produce versioned auths should also implement
+// Versioned so that [EstimateMaxUnits] accounts for the version.
+type Versioned interface {
+	GetVersion() uint8
+}
+
 type Action interface {
 	Object
 
@@ -257,7 +553,9 @@ type Action interface {
 	// will revert and the max fee will be charged.
 	//
 	// An error should only be returned if a fatal error was encountered, otherwise [success] should
-	// be marked as false and fees will still be charged.
+	// be marked as false and fees will still be charged. The only exception are errors returned by
+	// [Fail] or [NewStatusError], which are charged like any other failure but also set the [Status]
+	// of the [Result] (instead of [StatusFailed]).
 	Execute(
 		ctx context.Context,
 		r Rules,
@@ -273,6 +571,15 @@ type Action interface {
 	OutputsWarpMessage() bool
 }
 
+// CustomUnitsAction can be implemented by an [Action] to consume units of the
+// dimensions added with [RegisterDimension].
+type CustomUnitsAction interface {
+	// CustomUnits is the amount of each custom dimension consumed by the
+	// action (the default dimensions are ignored). It is charged whether or
+	// not the action is successful.
+	CustomUnits(Rules) Dimensions
+}
+
 type Auth interface {
 	Object
 
@@ -305,6 +612,57 @@ type Auth interface {
 	Sponsor() codec.Address
 }
 
+// ScopedAuth can be implemented by an [Auth] that may only authorize some
+// transactions (e.g. a session key delegated limited permissions).
+type ScopedAuth interface {
+	// Authorizes returns an error if [Auth] may not authorize a transaction
+	// with [action] that pays at most [maxFee], when executed at [timestamp].
+	Authorizes(action Action, maxFee uint64, timestamp int64) error
+}
+
+// StatefulAuth can be implemented by an [Auth] whose validity depends on
+// state (e.g. a key that must be registered before it can be used).
+type StatefulAuth interface {
+	// StateKeys are the keys read by [VerifyState]. They are charged like any
+	// other key accessed by the transaction.
+	StateKeys() []string
+
+	// VerifyState returns an error if [Auth] may not authorize a transaction
+	// in [im].
+	VerifyState(ctx context.Context, im state.Immutable) error
+}
+
+// WarpTimestamper can be implemented by an [Action] that imports warp
+// messages that include when they were sent (see
+// [Rules.GetWarpReplayRetention]).
+type WarpTimestamper interface {
+	// WarpTimestamp returns when [msg] was sent (in milliseconds), or false if
+	// it isn't known.
+	WarpTimestamp(msg *warp.Message) (int64, bool)
+}
+
+// MeteredAuth can be implemented by a [StatefulAuth] that limits the total
+// amount the transactions it authorizes can spend (e.g. a session key with a
+// spending cap). [Charge] and [Refund] may only modify its [StateKeys].
+type MeteredAuth interface {
+	StatefulAuth
+
+	// CanCharge returns an error if [Auth] may not authorize a transaction
+	// with [action] that is charged a fee of at most [maxFee] in [im].
+	CanCharge(ctx context.Context, im state.Immutable, action Action, maxFee uint64) error
+
+	// Charge records that a transaction authorized by [Auth] was charged
+	// [fee] and, if [action] is not nil, that it successfully executed
+	// [action]. It is called with the max fee (and a nil [action]) before
+	// [action] executes, so that the fee is recorded even if [action] is
+	// reverted, and again with a [fee] of 0 if [action] succeeds.
+	Charge(ctx context.Context, mu state.Mutable, action Action, fee uint64) error
+
+	// Refund records that [refund] of the fee passed to [Charge] was
+	// returned.
+	Refund(ctx context.Context, mu state.Mutable, refund uint64) error
+}
+
 type AuthBatchVerifier interface {
 	Add([]byte, Auth) func() error
 	Done() []func() error
@@ -315,3 +673,9 @@ type AuthFactory interface {
 	Sign(msg []byte) (Auth, error)
 	MaxUnits() (bandwidth uint64, compute uint64)
 }
+
+// StatefulAuthFactory should be implemented by the [AuthFactory] of a
+// [StatefulAuth] so that the keys it reads are included in fee estimates.
+type StatefulAuthFactory interface {
+	StateKeysMaxChunks() []uint16
+}
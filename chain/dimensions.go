@@ -0,0 +1,95 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// MaxFeeDimensions is the most dimensions a chain can meter (including the
+// default ones). It bounds the size of [Dimensions] so that it can be copied
+// and compared by value.
+const MaxFeeDimensions = 8
+
+// dimensionNames has an entry for each [Dimension] metered by the chain.
+var dimensionNames = []string{
+	Bandwidth:       "bandwidth",
+	Compute:         "compute",
+	StorageRead:     "storage(read)",
+	StorageAllocate: "storage(allocate)",
+	StorageWrite:    "storage(write)",
+}
+
+// RegisterDimension adds a custom [Dimension] (i.e. WASM fuel) that is priced
+// and limited independently of all others. The targets, limits, and minimum
+// prices of the new [Dimension] are provided by [Rules] like those of the
+// default dimensions. Actions consume it by implementing [CustomUnitsAction].
+//
+// RegisterDimension must be called from an init function (every node must
+// register the same dimensions in the same order).
+func RegisterDimension(name string) Dimension {
+	if len(dimensionNames) == MaxFeeDimensions {
+		panic(fmt.Sprintf("%s: %s", ErrTooManyDimensions, name))
+	}
+	for _, existing := range dimensionNames {
+		if existing == name {
+			panic(fmt.Sprintf("%s: %s", ErrDuplicateDimension, name))
+		}
+	}
+	dimensionNames = append(dimensionNames, name)
+	return Dimension(len(dimensionNames) - 1)
+}
+
+// FeeDimensions is the number of dimensions metered by the chain.
+func FeeDimensions() int {
+	return len(dimensionNames)
+}
+
+// DimensionsLen is the size of [Dimensions] when serialized.
+func DimensionsLen() int {
+	return consts.Uint64Len * FeeDimensions()
+}
+
+func (d Dimension) String() string {
+	if int(d) >= len(dimensionNames) {
+		return fmt.Sprintf("unknown(%d)", int(d))
+	}
+	return dimensionNames[d]
+}
+
+// MarshalJSON only includes the dimensions metered by the chain.
+func (d Dimensions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d[:FeeDimensions()])
+}
+
+// UnmarshalJSON allows fewer values than the dimensions metered by the chain
+// (missing values are 0) but not more.
+func (d *Dimensions) UnmarshalJSON(b []byte) error {
+	var values []uint64
+	if err := json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+	if len(values) > FeeDimensions() {
+		return fmt.Errorf("%w: found=%d max=%d", ErrWrongDimensionSize, len(values), FeeDimensions())
+	}
+	*d = Dimensions{}
+	copy(d[:], values)
+	return nil
+}
+
+// addCustomUnits sets the custom dimensions of [d] to those consumed by
+// [action].
+func addCustomUnits(r Rules, action Action, d *Dimensions) {
+	ca, ok := action.(CustomUnitsAction)
+	if !ok {
+		return
+	}
+	custom := ca.CustomUnits(r)
+	for i := StorageWrite + 1; int(i) < FeeDimensions(); i++ {
+		d[i] = custom[i]
+	}
+}
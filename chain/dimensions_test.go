@@ -0,0 +1,118 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// testDimension is registered for every test in this package (like a VM
+// would in an init function).
+var testDimension = RegisterDimension("test")
+
+func TestRegisterDimension(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(StorageWrite+1, testDimension)
+	require.Equal(6, FeeDimensions())
+	require.Equal(6*consts.Uint64Len, DimensionsLen())
+	require.Equal("test", testDimension.String())
+	require.Equal("unknown(7)", Dimension(7).String())
+
+	// Registering the same name twice is not allowed (and doesn't add a
+	// dimension)
+	require.PanicsWithValue(ErrDuplicateDimension.Error()+": test", func() {
+		RegisterDimension("test")
+	})
+	require.Equal(6, FeeDimensions())
+}
+
+func TestCustomDimensionFees(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetWindowTargetUnits().Return(Dimensions{100, 100, 100, 100, 100, 10}).AnyTimes()
+	r.EXPECT().GetUnitPriceChangeDenominator().Return(Dimensions{2, 2, 2, 2, 2, 2}).AnyTimes()
+	r.EXPECT().GetMinUnitPrice().Return(Dimensions{1, 1, 1, 1, 1, 1}).AnyTimes()
+	limits := Dimensions{1_000, 1_000, 1_000, 1_000, 1_000, 1_000}
+
+	// Fee state created before the dimension was registered starts with a
+	// unit price of 0 for it
+	old := NewFeeManager(make([]byte, 5*dimensionStateLen))
+	require.Len(old.Bytes(), 6*dimensionStateLen)
+	require.Zero(old.UnitPrice(testDimension))
+	fm, err := old.ComputeNext(0, 1_000, r)
+	require.NoError(err)
+	require.Equal(Dimensions{1, 1, 1, 1, 1, 1}, fm.UnitPrices())
+
+	// Only consuming the custom dimension only raises its price
+	ok, _ := fm.Consume(Dimensions{0, 0, 0, 0, 0, 100}, limits)
+	require.True(ok)
+	require.Equal(uint64(100), fm.LastConsumed(testDimension))
+	require.Zero(fm.LastConsumed(Bandwidth))
+	fm, err = fm.ComputeNext(1_000, 2_000, r)
+	require.NoError(err)
+	prices := fm.UnitPrices()
+	require.Greater(prices[testDimension], uint64(1))
+	require.Equal(Dimensions{1, 1, 1, 1, 1, prices[testDimension]}, prices)
+	require.NotEqual(fm.Window(Bandwidth), fm.Window(testDimension))
+
+	// Consuming the custom dimension is limited separately
+	ok, d := fm.Consume(Dimensions{0, 0, 0, 0, 0, 1_001}, limits)
+	require.False(ok)
+	require.Equal(testDimension, d)
+
+	// Consuming the default dimensions doesn't change its price or window
+	busy := NewFeeManager(append([]byte{}, fm.Bytes()...))
+	ok, _ = busy.Consume(Dimensions{500, 500, 500, 500, 500, 0}, limits)
+	require.True(ok)
+	busy, err = busy.ComputeNext(2_000, 3_000, r)
+	require.NoError(err)
+	idle, err := fm.ComputeNext(2_000, 3_000, r)
+	require.NoError(err)
+	next := busy.UnitPrices()
+	require.Equal(idle.UnitPrice(testDimension), next[testDimension])
+	require.Equal(idle.Window(testDimension), busy.Window(testDimension))
+	for i := Bandwidth; i <= StorageWrite; i++ {
+		require.Greater(next[i], idle.UnitPrice(i), i.String())
+	}
+	fm = busy
+
+	// Fees include the custom dimension
+	fee, err := fm.MaxFee(Dimensions{0, 0, 0, 0, 0, 3})
+	require.NoError(err)
+	require.Equal(3*next[testDimension], fee)
+}
+
+func TestDimensionsEncoding(t *testing.T) {
+	require := require.New(t)
+
+	d := Dimensions{1, 2, 3, 4, 5, 6}
+	b, err := json.Marshal(d)
+	require.NoError(err)
+	require.Equal("[1,2,3,4,5,6]", string(b))
+	var parsed Dimensions
+	require.NoError(json.Unmarshal(b, &parsed))
+	require.Equal(d, parsed)
+
+	// Missing dimensions default to 0
+	require.NoError(json.Unmarshal([]byte("[1,2,3,4,5]"), &parsed))
+	require.Equal(Dimensions{1, 2, 3, 4, 5, 0}, parsed)
+
+	// Unregistered dimensions are rejected
+	require.ErrorIs(json.Unmarshal([]byte("[1,2,3,4,5,6,7]"), &parsed), ErrWrongDimensionSize)
+
+	unpacked, err := UnpackDimensions(d.Bytes())
+	require.NoError(err)
+	require.Equal(d, unpacked)
+	_, err = UnpackDimensions(d.Bytes()[:5*consts.Uint64Len])
+	require.ErrorIs(err, ErrWrongDimensionSize)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// Encoding is a wire encoding of transactions, results, and blocks. Clients
+// pick the encoding of each request (see [Encodings]), but nodes always
+// convert objects to [BinaryEncoding] before processing them, so IDs and
+// signatures don't depend on the encoding.
+type Encoding uint8
+
+const (
+	// BinaryEncoding is the native encoding produced by [codec.Packer].
+	BinaryEncoding Encoding = 0
+	// ProtobufEncoding is described in proto/chain.proto. It only defines the
+	// containers of the hypersdk: actions and auths keep their
+	// [BinaryEncoding] inside of it.
+	ProtobufEncoding Encoding = 1
+)
+
+// Encodings returns all encodings supported by this version of the
+// hypersdk.
+func Encodings() []Encoding {
+	return []Encoding{BinaryEncoding, ProtobufEncoding}
+}
+
+func (e Encoding) String() string {
+	switch e {
+	case BinaryEncoding:
+		return "binary"
+	case ProtobufEncoding:
+		return "protobuf"
+	default:
+		return fmt.Sprintf("unknown(%d)", e)
+	}
+}
+
+// MarshalTxEncoding encodes [tx] with [e].
+func MarshalTxEncoding(e Encoding, tx *Transaction) ([]byte, error) {
+	switch e {
+	case BinaryEncoding:
+		if b := tx.Bytes(); len(b) > 0 {
+			return b, nil
+		}
+		p := codec.NewWriter(tx.Size(), consts.NetworkSizeLimit)
+		if err := tx.Marshal(p); err != nil {
+			return nil, err
+		}
+		return p.Bytes(), p.Err()
+	case ProtobufEncoding:
+		return MarshalTxProto(tx)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownEncoding, e)
+	}
+}
+
+// UnmarshalTxEncoding decodes a single transaction encoded with [e]. Unlike
+// [UnmarshalTx], it fails if [raw] has extra bytes.
+func UnmarshalTxEncoding(
+	e Encoding,
+	raw []byte,
+	actionRegistry ActionRegistry,
+	authRegistry AuthRegistry,
+) (*Transaction, error) {
+	switch e {
+	case BinaryEncoding:
+		p := codec.NewReader(raw, consts.NetworkSizeLimit)
+		tx, err := UnmarshalTx(p, actionRegistry, authRegistry)
+		if err != nil {
+			return nil, err
+		}
+		if !p.Empty() {
+			return nil, fmt.Errorf("%w: remaining=%d", ErrInvalidObject, len(raw)-p.Offset())
+		}
+		return tx, nil
+	case ProtobufEncoding:
+		return UnmarshalTxProto(raw, actionRegistry, authRegistry)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownEncoding, e)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// Epoch returns the epoch of the block at [height]. Epoch [e] contains the
+// blocks at heights [e*length, (e+1)*length). If the chain has no epochs,
+// all blocks are in epoch 0.
+func Epoch(r Rules, height uint64) uint64 {
+	length := r.GetEpochLength()
+	if length == 0 {
+		return 0
+	}
+	return height / length
+}
+
+// IsEpochEnd returns true if the block at [height] is the last block of its
+// epoch.
+func IsEpochEnd(r Rules, height uint64) bool {
+	length := r.GetEpochLength()
+	return length > 0 && height%length == length-1
+}
+
+// endEpoch invokes [EpochHandler.EndEpoch] on [ts] if the block at [height]
+// is the last block of its epoch (and [handler] is not nil).
+//
+// [im] must be the state that [ts] was executed on, so that the value of
+// any keys not modified by [ts] can be read.
+func endEpoch(
+	ctx context.Context,
+	handler EpochHandler,
+	r Rules,
+	im state.Immutable,
+	ts *tstate.TState,
+	timestamp int64,
+	height uint64,
+) error {
+	if handler == nil || !IsEpochEnd(r, height) {
+		return nil
+	}
+	epoch := Epoch(r, height)
+	stateKeys := set.Of(handler.EpochStateKeys(epoch)...)
+	storage := make(map[string][]byte, len(stateKeys))
+	for k := range stateKeys {
+		v, err := im.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		storage[k] = v
+	}
+	tsv := ts.NewView(stateKeys, storage)
+	if err := handler.EndEpoch(ctx, r, tsv, timestamp, epoch); err != nil {
+		return fmt.Errorf("%w: epoch=%d: %v", ErrEpochEndFailed, epoch, err)
+	}
+	tsv.Commit()
+	return nil
+}
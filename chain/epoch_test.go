@@ -0,0 +1,125 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+var (
+	testEpochKey = string(keys.EncodeChunks([]byte{0x5}, 1))
+
+	errTestEpoch = errors.New("test epoch")
+)
+
+// testEpochHandler counts the epochs that ended in [testEpochKey] and
+// records each invocation.
+type testEpochHandler struct {
+	ended []uint64
+	key   string // key written by [EndEpoch] (if not [testEpochKey])
+	err   error
+}
+
+func (*testEpochHandler) EpochStateKeys(uint64) []string {
+	return []string{testEpochKey}
+}
+
+func (h *testEpochHandler) EndEpoch(ctx context.Context, _ Rules, mu state.Mutable, _ int64, epoch uint64) error {
+	h.ended = append(h.ended, epoch)
+	if h.err != nil {
+		return h.err
+	}
+	count, err := getTestBalance(ctx, mu, []byte(testEpochKey))
+	if err != nil {
+		return err
+	}
+	key := h.key
+	if len(key) == 0 {
+		key = testEpochKey
+	}
+	return mu.Insert(ctx, []byte(key), binary.BigEndian.AppendUint64(nil, count+1))
+}
+
+func TestEpoch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	for _, test := range []struct {
+		length uint64
+		height uint64
+		epoch  uint64
+		end    bool
+	}{
+		{length: 0, height: 0, epoch: 0, end: false},
+		{length: 0, height: 100, epoch: 0, end: false},
+		{length: 1, height: 0, epoch: 0, end: true},
+		{length: 1, height: 7, epoch: 7, end: true},
+		{length: 3, height: 0, epoch: 0, end: false},
+		{length: 3, height: 2, epoch: 0, end: true},
+		{length: 3, height: 3, epoch: 1, end: false},
+		{length: 3, height: 5, epoch: 1, end: true},
+		{length: 3, height: 6, epoch: 2, end: false},
+	} {
+		r := NewMockRules(ctrl)
+		r.EXPECT().GetEpochLength().Return(test.length).AnyTimes()
+		require.Equal(t, test.epoch, Epoch(r, test.height), "length=%d height=%d", test.length, test.height)
+		require.Equal(t, test.end, IsEpochEnd(r, test.height), "length=%d height=%d", test.length, test.height)
+	}
+}
+
+func TestEndEpoch(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetEpochLength().Return(uint64(3)).AnyTimes()
+	mem := memoryState{}
+	run := func(handler EpochHandler, height uint64) error {
+		ts := tstate.New(0)
+		if err := endEpoch(ctx, handler, r, mem, ts, 0, height); err != nil {
+			return err
+		}
+		mem.commit(ts)
+		return nil
+	}
+
+	// The handler runs exactly once for the last block of each epoch and its
+	// changes are applied to the block
+	handler := &testEpochHandler{}
+	for height := uint64(0); height < 9; height++ {
+		require.NoError(run(handler, height))
+	}
+	require.Equal([]uint64{0, 1, 2}, handler.ended)
+	count, err := getTestBalance(ctx, mem, []byte(testEpochKey))
+	require.NoError(err)
+	require.Equal(uint64(3), count)
+
+	// Failures (including accesses outside of the declared keys) invalidate
+	// the block without applying any changes
+	for _, handler := range []*testEpochHandler{
+		{err: errTestEpoch},
+		{key: string(keys.EncodeChunks([]byte{0x6}, 1))},
+	} {
+		require.ErrorIs(run(handler, 11), ErrEpochEndFailed)
+		require.Equal([]uint64{3}, handler.ended)
+	}
+	require.Len(mem, 1)
+	count, err = getTestBalance(ctx, mem, []byte(testEpochKey))
+	require.NoError(err)
+	require.Equal(uint64(3), count)
+
+	// Nothing runs without a handler
+	require.NoError(run(nil, 2))
+	require.Len(mem, 1)
+}
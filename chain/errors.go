@@ -28,6 +28,7 @@ var (
 	ErrStateRootMismatch    = errors.New("state root mismatch")
 	ErrInvalidResult        = errors.New("invalid result")
 	ErrInvalidBlockHeight   = errors.New("invalid block height")
+	ErrFeeExemptTxFailed    = errors.New("fee exempt transaction failed")
 
 	// Tx Correctness
 	ErrInvalidSignature     = errors.New("invalid signature")
@@ -46,10 +47,30 @@ var (
 	ErrTooManyTxs           = errors.New("too many transactions")
 	ErrActionNotActivated   = errors.New("action not activated")
 	ErrAuthNotActivated     = errors.New("auth not activated")
+	ErrActionDisabled       = errors.New("action disabled")
+	ErrActionDeprecated     = errors.New("action version deprecated")
+	ErrAuthDeprecated       = errors.New("auth version deprecated")
+	ErrAuthNotAuthorized    = errors.New("auth not authorized")
 	ErrAuthFailed           = errors.New("auth failed")
 	ErrMisalignedTime       = errors.New("misaligned time")
+	ErrHeightTooEarly       = errors.New("height too early")
+	ErrHeightTooLate        = errors.New("height too late")
+	ErrHeightExpiryDisabled = errors.New("height expiry disabled")
+	ErrNoncesDisabled       = errors.New("sequential nonces disabled")
+	ErrNonceRequired        = errors.New("sequential nonce required")
+	ErrNoncesUnsupported    = errors.New("state manager does not support nonces")
+	ErrNonceTooLow          = errors.New("nonce too low")
+	ErrNonceTooHigh         = errors.New("nonce too high")
+	ErrTxCancelled          = errors.New("transaction cancelled")
+	ErrCancelUnsupported    = errors.New("state manager does not support cancellation")
+	ErrTooManyCancelled     = errors.New("too many cancelled transactions")
+	ErrInvalidAccessList    = errors.New("invalid access list")
+	ErrAccessListMismatch   = errors.New("access list mismatch")
 	ErrInvalidActor         = errors.New("invalid actor")
 	ErrInvalidSponsor       = errors.New("invalid sponsor")
+	ErrEpochEndFailed       = errors.New("epoch end failed")
+	ErrTxRejectedByHook     = errors.New("transaction rejected by hook")
+	ErrBlockRejectedByHook  = errors.New("block rejected by hook")
 
 	// Execution Correctness
 	ErrInvalidBalance  = errors.New("invalid balance")
@@ -65,6 +86,30 @@ var (
 	ErrEmptyWarpPayload          = errors.New("empty warp payload")
 	ErrTooManyWarpMessages       = errors.New("too many warp messages")
 	ErrWarpResultMismatch        = errors.New("warp result mismatch")
+	ErrWarpReplay                = errors.New("warp message already imported")
+	ErrWarpMessageExpired        = errors.New("warp message expired")
+	ErrTooManyWarpSigners        = errors.New("too many warp signers")
+	ErrInvalidWarpQuorum         = errors.New("warp quorum must be in (0, 1]")
+	ErrInvalidSnapshot           = errors.New("invalid validator snapshot")
+
+	// Witness
+	ErrMissingWitness     = errors.New("missing block witness")
+	ErrUnexpectedWitness  = errors.New("unexpected block witness")
+	ErrInvalidWitness     = errors.New("invalid block witness")
+	ErrIncompleteWitness  = errors.New("block witness does not cover key")
+	ErrKeyNotWitnessed    = errors.New("key not in block witness")
+	ErrWitnessUnsupported = errors.New("view cannot generate proofs")
+
+	// Arrivals
+	ErrInvalidArrivals = errors.New("invalid arrivals")
+
+	// Rules Overrides
+	ErrRulesOverridesUnsupported = errors.New("state manager does not support rules overrides")
+	ErrInvalidRulesOverrides     = errors.New("invalid rules overrides")
+
+	// Fee Assets
+	ErrFeeAssetUnsupported = errors.New("state manager does not support fee assets")
+	ErrMaxFeeAssetExceeded = errors.New("converted fee exceeds max fee asset")
 
 	// Misc
 	ErrNotImplemented         = errors.New("not implemented")
@@ -72,4 +117,19 @@ var (
 	ErrInvalidKeyValue        = errors.New("invalid key or value")
 	ErrModificationNotAllowed = errors.New("modification not allowed")
 	ErrWrongDimensionSize     = errors.New("wrong dimensions size")
+	ErrTooManyDimensions      = errors.New("too many dimensions")
+	ErrDuplicateDimension     = errors.New("duplicate dimension")
+	ErrBlobsDisabled          = errors.New("blobs not enabled")
+	ErrBlobsRootMismatch      = errors.New("blobs root mismatch")
+	ErrInvalidBuilderFeeShare = errors.New("builder fee share must be at most 100")
+
+	// Bundles
+	ErrEmptyBundle       = errors.New("empty bundle")
+	ErrBundleTooLarge    = errors.New("too many transactions in bundle")
+	ErrBundleWarpMessage = errors.New("bundles cannot include warp messages")
+	ErrBundleTxFailed    = errors.New("bundle transaction failed")
+
+	// Encoding
+	ErrUnknownEncoding = errors.New("unknown encoding")
+	ErrInvalidProto    = errors.New("invalid protobuf")
 )
@@ -0,0 +1,170 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/executor"
+)
+
+// ExecutionTrace records how the transactions in a block were executed: the
+// order in which they finished and the state each one observed and produced.
+//
+// Comparing the trace of a parallel execution with that of a sequential
+// [StatelessBlock.Replay] pinpoints transactions whose outcome depends on
+// scheduling (usually an [Action] that touches keys it didn't declare or
+// relies on some other non-deterministic input).
+type ExecutionTrace struct {
+	Block      ids.ID
+	Height     uint64
+	Sequential bool
+
+	// Order is the index of each transaction in the order it finished
+	// executing.
+	Order []int
+	Txs   []*TxTrace
+
+	l sync.Mutex
+}
+
+// TxTrace is the state snapshot of a single transaction execution.
+type TxTrace struct {
+	ID           ids.ID
+	WarpVerified bool
+
+	// Reads is the value of each state key when execution started and
+	// Writes is the set of changes committed by the transaction.
+	Reads  map[string]maybe.Maybe[[]byte]
+	Writes map[string]maybe.Maybe[[]byte]
+
+	Result *Result
+}
+
+func newExecutionTrace(blk *StatelessBlock, sequential bool) *ExecutionTrace {
+	return &ExecutionTrace{
+		Block:      blk.ID(),
+		Height:     blk.Hght,
+		Sequential: sequential,
+		Order:      make([]int, 0, len(blk.Txs)),
+		Txs:        make([]*TxTrace, len(blk.Txs)),
+	}
+}
+
+func (t *ExecutionTrace) record(i int, tx *TxTrace) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	t.Order = append(t.Order, i)
+	t.Txs[i] = tx
+}
+
+// Diff returns a description of every transaction whose reads, writes, or
+// result differ between [t] and [o]. Both traces must be of the same block.
+func (t *ExecutionTrace) Diff(o *ExecutionTrace) []string {
+	diffs := []string{}
+	if t.Block != o.Block || len(t.Txs) != len(o.Txs) {
+		return append(diffs, fmt.Sprintf("block mismatch: %s (%d txs) != %s (%d txs)", t.Block, len(t.Txs), o.Block, len(o.Txs)))
+	}
+	for i, tx := range t.Txs {
+		otx := o.Txs[i]
+		switch {
+		case tx == nil && otx == nil:
+			continue
+		case tx == nil || otx == nil:
+			diffs = append(diffs, fmt.Sprintf("tx %d: executed in only one trace", i))
+			continue
+		}
+		for _, k := range diffValues(tx.Reads, otx.Reads) {
+			diffs = append(diffs, fmt.Sprintf("tx %d (%s): read of %s differs: %s != %s", i, tx.ID, hex.EncodeToString([]byte(k)), formatValue(tx.Reads[k]), formatValue(otx.Reads[k])))
+		}
+		for _, k := range diffValues(tx.Writes, otx.Writes) {
+			diffs = append(diffs, fmt.Sprintf("tx %d (%s): write of %s differs: %s != %s", i, tx.ID, hex.EncodeToString([]byte(k)), formatValue(tx.Writes[k]), formatValue(otx.Writes[k])))
+		}
+		if !equalResults(tx.Result, otx.Result) {
+			diffs = append(diffs, fmt.Sprintf("tx %d (%s): result differs: %+v != %+v", i, tx.ID, tx.Result, otx.Result))
+		}
+	}
+	return diffs
+}
+
+// diffValues returns the sorted keys that are not the same in [a] and [b].
+func diffValues(a, b map[string]maybe.Maybe[[]byte]) []string {
+	keys := set.NewSet[string](len(a) + len(b))
+	for k, v := range a {
+		if ov, ok := b[k]; !ok || !maybe.Equal(v, ov, bytes.Equal) {
+			keys.Add(k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys.Add(k)
+		}
+	}
+	diff := keys.List()
+	sort.Strings(diff)
+	return diff
+}
+
+func formatValue(v maybe.Maybe[[]byte]) string {
+	if v.IsNothing() {
+		return "<nothing>"
+	}
+	return hex.EncodeToString(v.Value())
+}
+
+func equalResults(a, b *Result) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Status != b.Status || !bytes.Equal(a.Output, b.Output) || a.Consumed != b.Consumed || a.Fee != b.Fee {
+		return false
+	}
+	if a.WarpMessage == nil || b.WarpMessage == nil {
+		return a.WarpMessage == b.WarpMessage
+	}
+	return bytes.Equal(a.WarpMessage.Bytes(), b.WarpMessage.Bytes())
+}
+
+type txExecutor interface {
+	Run(set.Set[string], func() error)
+	Stop()
+	Wait() error
+}
+
+var (
+	_ txExecutor = (*executor.Executor)(nil)
+	_ txExecutor = (*sequentialExecutor)(nil)
+)
+
+// sequentialExecutor runs each task as soon as it is added, so transactions
+// are executed one at a time in block order.
+type sequentialExecutor struct {
+	err error
+}
+
+func (s *sequentialExecutor) Run(_ set.Set[string], f func() error) {
+	if s.err != nil {
+		return
+	}
+	s.err = f()
+}
+
+func (s *sequentialExecutor) Stop() {
+	if s.err == nil {
+		s.err = executor.ErrStopped
+	}
+}
+
+func (s *sequentialExecutor) Wait() error {
+	return s.err
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// extendedTxMarker prefixes the encoding of a [Transaction] that includes
+// optional fields (see [txExtensions]).
+//
+// It is encoded where the [Base.Timestamp] of other transactions is and is
+// not a valid timestamp (it isn't a multiple of a second), so transactions
+// without optional fields keep the encoding (and ID) they had before these
+// fields existed and parsers that don't support them reject transactions
+// that include them.
+const extendedTxMarker int64 = -1
+
+// txExtensions is the set of optional fields included in the encoding of a
+// [Transaction].
+type txExtensions uint8
+
+const (
+	// extFeeAsset is set if [Base.FeeAsset] is encoded.
+	extFeeAsset txExtensions = 1 << iota
+	// extBlob is set if [Transaction.Blob] is encoded.
+	extBlob
+	// extAccessList is set if [Transaction.AccessList] is encoded.
+	extAccessList
+
+	allExtensions = extFeeAsset | extBlob | extAccessList
+)
+
+// extensions returns the optional fields of the transaction that are
+// encoded.
+func (t *Transaction) extensions() txExtensions {
+	return txExtensionsOf(t.Base, t.Blob, t.AccessList)
+}
+
+func txExtensionsOf(base *Base, blob []byte, accessList []string) txExtensions {
+	var exts txExtensions
+	if base.HasFeeAsset() {
+		exts |= extFeeAsset
+	}
+	if len(blob) > 0 {
+		exts |= extBlob
+	}
+	if len(accessList) > 0 {
+		exts |= extAccessList
+	}
+	return exts
+}
+
+// blobLen is the size of [blob] in the encoding of a [Transaction].
+func blobLen(blob []byte) int {
+	if len(blob) == 0 {
+		return 0
+	}
+	return codec.BytesLen(blob)
+}
+
+func packBlob(p *codec.Packer, blob []byte) {
+	if len(blob) == 0 {
+		return
+	}
+	p.PackBytes(blob)
+}
+
+// extensionsLen is the size of the prefix that marks [exts] as included.
+func extensionsLen(exts txExtensions) int {
+	if exts == 0 {
+		return 0
+	}
+	return consts.Int64Len + consts.ByteLen
+}
+
+func packExtensions(p *codec.Packer, exts txExtensions) {
+	if exts == 0 {
+		return
+	}
+	p.PackInt64(extendedTxMarker)
+	p.PackByte(byte(exts))
+}
+
+// unpackExtensions unpacks the set of optional fields that follows
+// [extendedTxMarker].
+func unpackExtensions(p *codec.Packer) (txExtensions, error) {
+	exts := txExtensions(p.UnpackByte())
+	if err := p.Err(); err != nil {
+		return 0, err
+	}
+	if exts == 0 || exts&^allExtensions != 0 {
+		// Enforce object standardization
+		return 0, fmt.Errorf("%w: invalid extensions %08b", ErrInvalidObject, exts)
+	}
+	return exts, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+func packBase(base *Base) []byte {
+	exts := txExtensionsOf(base, nil, nil)
+	p := codec.NewWriter(extensionsLen(exts)+base.Size(), consts.NetworkSizeLimit)
+	packExtensions(p, exts)
+	base.Marshal(p)
+	return p.Bytes()
+}
+
+func TestBaseExtensions(t *testing.T) {
+	require := require.New(t)
+
+	// A [Base] without optional fields is encoded without a prefix
+	base := &Base{Timestamp: 1_000, ChainID: ids.GenerateTestID(), MaxFee: 10}
+	b := packBase(base)
+	require.Len(b, BaseSize)
+	parsed, exts, err := unmarshalBase(codec.NewReader(b, consts.NetworkSizeLimit))
+	require.NoError(err)
+	require.Zero(exts)
+	require.Equal(base, parsed)
+
+	// A [FeeAsset] is marked as included
+	base.FeeAsset = ids.GenerateTestID()
+	base.MaxFeeAsset = 20
+	b = packBase(base)
+	require.Len(b, BaseSize+consts.IDLen+consts.Uint64Len+extensionsLen(extFeeAsset))
+	parsed, exts, err = unmarshalBase(codec.NewReader(b, consts.NetworkSizeLimit))
+	require.NoError(err)
+	require.Equal(extFeeAsset, exts)
+	require.Equal(base, parsed)
+
+	// The prefix is never a valid timestamp
+	p := codec.NewReader(b, consts.NetworkSizeLimit)
+	require.NotZero(p.UnpackInt64(true) % consts.MillisecondsPerSecond)
+
+	// A blob is only encoded if it is set
+	require.Zero(blobLen(nil))
+	require.Equal(extBlob, txExtensionsOf(&Base{}, []byte{1}, nil))
+
+	// An access list is only encoded if it is set
+	require.Zero(accessListLen(nil))
+	require.Equal(extAccessList, txExtensionsOf(&Base{}, nil, []string{"k"}))
+
+	// Unknown (or no) extensions are rejected
+	for _, exts := range []byte{0, 0xff} {
+		b[consts.Int64Len] = exts
+		_, _, err = unmarshalBase(codec.NewReader(b, consts.NetworkSizeLimit))
+		require.ErrorIs(err, ErrInvalidObject)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/math"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// feeConverter returns the [FeeConverter] of [sm] or
+// [ErrFeeAssetUnsupported] if it doesn't implement one.
+func feeConverter(sm StateManager) (FeeConverter, error) {
+	fc, ok := sm.(FeeConverter)
+	if !ok {
+		return nil, ErrFeeAssetUnsupported
+	}
+	return fc, nil
+}
+
+// payerStateKeys returns the keys touched when [payer] pays the fees of [t].
+func (t *Transaction) payerStateKeys(sm StateManager, r Rules, payer codec.Address) ([]string, error) {
+	if !t.Base.HasFeeAsset() {
+		return sm.SponsorStateKeys(payer), nil
+	}
+	fc, err := feeConverter(sm)
+	if err != nil {
+		return nil, err
+	}
+	return fc.FeeAssetStateKeys(r, payer, t.Base.FeeAsset), nil
+}
+
+// canDeductFee returns an error if [payer] can't pay [amount] or if it
+// converts to more than [Base.MaxFeeAsset].
+func (t *Transaction) canDeductFee(
+	ctx context.Context,
+	sm StateManager,
+	r Rules,
+	payer codec.Address,
+	im state.Immutable,
+	timestamp int64,
+	amount uint64,
+) error {
+	if !t.Base.HasFeeAsset() {
+		return sm.CanDeduct(ctx, payer, im, amount)
+	}
+	fc, err := feeConverter(sm)
+	if err != nil {
+		return err
+	}
+	converted, err := fc.CanDeductAsset(ctx, r, payer, t.Base.FeeAsset, im, timestamp, amount)
+	if err != nil {
+		return err
+	}
+	return t.verifyMaxFeeAsset(converted)
+}
+
+// verifyMaxFeeAsset returns [ErrMaxFeeAssetExceeded] if [converted] is more
+// than the user agreed to pay.
+func (t *Transaction) verifyMaxFeeAsset(converted uint64) error {
+	if converted > t.Base.MaxFeeAsset {
+		return fmt.Errorf("%w: %d > %d", ErrMaxFeeAssetExceeded, converted, t.Base.MaxFeeAsset)
+	}
+	return nil
+}
+
+// deductFee removes [amount] from [payer] and returns the amount removed in
+// the fee asset of [t] (or [amount] if it pays in the native asset).
+func (t *Transaction) deductFee(
+	ctx context.Context,
+	sm StateManager,
+	r Rules,
+	payer codec.Address,
+	mu state.Mutable,
+	timestamp int64,
+	amount uint64,
+) (uint64, error) {
+	if !t.Base.HasFeeAsset() {
+		return amount, sm.Deduct(ctx, payer, mu, amount)
+	}
+	fc, err := feeConverter(sm)
+	if err != nil {
+		return 0, err
+	}
+	converted, err := fc.DeductAsset(ctx, r, payer, t.Base.FeeAsset, mu, timestamp, amount)
+	if err != nil {
+		return 0, err
+	}
+	// This should never fail (as we check [canDeductFee] immediately before)
+	if err := t.verifyMaxFeeAsset(converted); err != nil {
+		return 0, err
+	}
+	return converted, nil
+}
+
+// refundFee returns [refund] of the [maxFee] deducted from [payer] (which
+// was [converted] in the fee asset of [t]).
+func (t *Transaction) refundFee(
+	ctx context.Context,
+	sm StateManager,
+	r Rules,
+	payer codec.Address,
+	mu state.Mutable,
+	maxFee uint64,
+	converted uint64,
+	refund uint64,
+) error {
+	if !t.Base.HasFeeAsset() {
+		return sm.Refund(ctx, payer, mu, refund)
+	}
+	fc, err := feeConverter(sm)
+	if err != nil {
+		return err
+	}
+	return fc.RefundAsset(ctx, r, payer, t.Base.FeeAsset, mu, refund, convertRefund(maxFee, converted, refund))
+}
+
+// convertRefund returns the share of [converted] that [refund] is of
+// [maxFee] (rounded down, in favor of the chain). [refund] must be at most
+// [maxFee].
+func convertRefund(maxFee uint64, converted uint64, refund uint64) uint64 {
+	// [converted]*[refund] is less than [converted]*[maxFee], so the
+	// quotient can't overflow
+	hi, lo := bits.Mul64(converted, refund)
+	quo, _ := bits.Div64(hi, lo, maxFee)
+	return quo
+}
+
+// EstimateMaxFeeAssetUnits is like [EstimateMaxUnits] for a transaction that
+// pays fees in an asset (see [Base.FeeAsset]), which touches keys with
+// [feeAssetStateKeysMaxChunks] instead of the keys of its sponsor.
+func EstimateMaxFeeAssetUnits(
+	r Rules,
+	action Action,
+	authFactory AuthFactory,
+	warpMessage *warp.Message,
+	feeAssetStateKeysMaxChunks []uint16,
+) (Dimensions, error) {
+	d, err := EstimateMaxUnits(r, action, authFactory, warpMessage)
+	if err != nil {
+		return Dimensions{}, err
+	}
+	readsOp := math.NewUint64Operator(d[StorageRead])
+	allocatesOp := math.NewUint64Operator(d[StorageAllocate])
+	writesOp := math.NewUint64Operator(d[StorageWrite])
+	for _, maxChunks := range feeAssetStateKeysMaxChunks {
+		readsOp.Add(r.GetStorageKeyReadUnits())
+		allocatesOp.Add(r.GetStorageKeyAllocateUnits())
+		writesOp.Add(r.GetStorageKeyWriteUnits())
+
+		readsOp.MulAdd(uint64(maxChunks), r.GetStorageValueReadUnits())
+		allocatesOp.MulAdd(uint64(maxChunks), r.GetStorageValueAllocateUnits())
+		writesOp.MulAdd(uint64(maxChunks), r.GetStorageValueWriteUnits())
+	}
+	if d[StorageRead], err = readsOp.Value(); err != nil {
+		return Dimensions{}, err
+	}
+	if d[StorageAllocate], err = allocatesOp.Value(); err != nil {
+		return Dimensions{}, err
+	}
+	if d[StorageWrite], err = writesOp.Value(); err != nil {
+		return Dimensions{}, err
+	}
+	return d, nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// testFeeConverter converts every fee to [converted].
+type testFeeConverter struct {
+	StateManager
+	FeeConverter
+
+	converted uint64
+}
+
+func (c *testFeeConverter) CanDeductAsset(context.Context, Rules, codec.Address, ids.ID, state.Immutable, int64, uint64) (uint64, error) {
+	return c.converted, nil
+}
+
+func (c *testFeeConverter) DeductAsset(context.Context, Rules, codec.Address, ids.ID, state.Mutable, int64, uint64) (uint64, error) {
+	return c.converted, nil
+}
+
+func TestMaxFeeAsset(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tx := &Transaction{Base: &Base{MaxFee: 100, FeeAsset: ids.GenerateTestID(), MaxFeeAsset: 150}}
+	fc := &testFeeConverter{converted: 150}
+	require.NoError(tx.canDeductFee(ctx, fc, nil, codec.EmptyAddress, nil, 0, 100))
+	converted, err := tx.deductFee(ctx, fc, nil, codec.EmptyAddress, nil, 0, 100)
+	require.NoError(err)
+	require.Equal(uint64(150), converted)
+
+	// Fees that convert to more than the user signed for are rejected
+	fc.converted = 151
+	require.ErrorIs(tx.canDeductFee(ctx, fc, nil, codec.EmptyAddress, nil, 0, 100), ErrMaxFeeAssetExceeded)
+	_, err = tx.deductFee(ctx, fc, nil, codec.EmptyAddress, nil, 0, 100)
+	require.ErrorIs(err, ErrMaxFeeAssetExceeded)
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// MaxBuilderFeeShare is the maximum value of [Rules.GetBuilderFeeShare].
+const MaxBuilderFeeShare = 100 // percent
+
+// SplitFees divides the fees paid in [results] (at [prices]) between the
+// beneficiary of the block, which receives [share] percent of them, and the
+// burn. The builder's share is taken from each dimension, so the amount
+// burned can be tracked per dimension.
+//
+// Any fee paid above the cost of the units consumed (because it was raised to
+// [Rules.GetMinFee]) is attributed to [Bandwidth].
+func SplitFees(share uint64, prices Dimensions, results []*Result) (uint64, Dimensions, error) {
+	if share > MaxBuilderFeeShare {
+		return 0, Dimensions{}, ErrInvalidBuilderFeeShare
+	}
+	var (
+		consumed  Dimensions
+		surcharge uint64
+	)
+	for _, result := range results {
+		nconsumed, err := Add(consumed, result.Consumed)
+		if err != nil {
+			return 0, Dimensions{}, err
+		}
+		consumed = nconsumed
+		unitFee, err := MulSum(prices, result.Consumed)
+		if err != nil {
+			return 0, Dimensions{}, err
+		}
+		if result.Fee > unitFee {
+			surcharge, err = math.Add64(surcharge, result.Fee-unitFee)
+			if err != nil {
+				return 0, Dimensions{}, err
+			}
+		}
+	}
+	var (
+		builderFees uint64
+		burned      Dimensions
+	)
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
+		fees, err := math.Mul64(prices[i], consumed[i])
+		if err != nil {
+			return 0, Dimensions{}, err
+		}
+		if i == Bandwidth {
+			fees, err = math.Add64(fees, surcharge)
+			if err != nil {
+				return 0, Dimensions{}, err
+			}
+		}
+		// Split the division to avoid overflowing when multiplying by [share]
+		builderShare := fees/MaxBuilderFeeShare*share + fees%MaxBuilderFeeShare*share/MaxBuilderFeeShare
+		builderFees, err = math.Add64(builderFees, builderShare)
+		if err != nil {
+			return 0, Dimensions{}, err
+		}
+		burned[i] = fees - builderShare
+	}
+	return builderFees, burned, nil
+}
+
+// distributeFees credits the builder's share of the fees paid in [results] to
+// [beneficiary] and adds the rest to the total burned in [ts]. If
+// [beneficiary] is empty, all fees are burned.
+//
+// [im] must be the state that [ts] was executed on, so that the value of
+// any keys not modified by [ts] can be read.
+//
+// distributeFees returns the amount burned in each dimension.
+func distributeFees(
+	ctx context.Context,
+	r Rules,
+	sm StateManager,
+	im state.Immutable,
+	ts *tstate.TState,
+	prices Dimensions,
+	beneficiary codec.Address,
+	results []*Result,
+) (Dimensions, error) {
+	share := r.GetBuilderFeeShare()
+	if beneficiary == codec.EmptyAddress {
+		share = 0
+	}
+	builderFees, burned, err := SplitFees(share, prices, results)
+	if err != nil {
+		return Dimensions{}, err
+	}
+
+	// Fetch any keys that could be modified. Keys modified during execution
+	// are read from [ts] instead.
+	burnKey := BurnKey(sm.BurnKey())
+	burnKeyStr := string(burnKey)
+	stateKeys := set.Of(burnKeyStr)
+	if builderFees > 0 {
+		stateKeys.Add(sm.SponsorStateKeys(beneficiary)...)
+	}
+	storage := make(map[string][]byte, len(stateKeys))
+	for k := range stateKeys {
+		v, err := im.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return Dimensions{}, err
+		}
+		storage[k] = v
+	}
+	tsv := ts.NewView(stateKeys, storage)
+	if builderFees > 0 {
+		if err := sm.Credit(ctx, beneficiary, tsv, builderFees); err != nil {
+			return Dimensions{}, err
+		}
+	}
+	total, err := getBurned(ctx, tsv, burnKey)
+	if err != nil {
+		return Dimensions{}, err
+	}
+	total, err = Add(total, burned)
+	if err != nil {
+		return Dimensions{}, err
+	}
+	if err := tsv.Insert(ctx, burnKey, total.Bytes()); err != nil {
+		return Dimensions{}, err
+	}
+	tsv.Commit()
+	return burned, nil
+}
+
+// getBurned returns the total fees burned in each dimension stored at
+// [burnKey].
+func getBurned(ctx context.Context, im state.Immutable, burnKey []byte) (Dimensions, error) {
+	v, err := im.GetValue(ctx, burnKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return Dimensions{}, nil
+	}
+	if err != nil {
+		return Dimensions{}, err
+	}
+	return UnpackDimensions(v)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// testLedger is a [testStateManager] that stores the balance of each
+// account in its sponsor key.
+type testLedger struct {
+	testStateManager
+}
+
+func (testLedger) BurnKey() []byte {
+	return []byte{0x4}
+}
+
+func (l testLedger) Credit(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error {
+	k := []byte(l.SponsorStateKeys(addr)[0])
+	balance, err := getTestBalance(ctx, mu, k)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, balance+amount))
+}
+
+func getTestBalance(ctx context.Context, im state.Immutable, k []byte) (uint64, error) {
+	v, err := im.GetValue(ctx, k)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (l testLedger) balance(t *testing.T, mem memoryState, addr codec.Address) uint64 {
+	balance, err := getTestBalance(context.Background(), mem, []byte(l.SponsorStateKeys(addr)[0]))
+	require.NoError(t, err)
+	return balance
+}
+
+func TestSplitFees(t *testing.T) {
+	require := require.New(t)
+
+	prices := Dimensions{}
+	prices[Bandwidth] = 2
+	prices[Compute] = 1
+	prices[StorageWrite] = 1
+	first := &Result{Consumed: Dimensions{}, Fee: 25}
+	first.Consumed[Bandwidth] = 10
+	first.Consumed[Compute] = 5
+	// Pays 5 above the cost of its units (like a tx raised to the min fee)
+	second := &Result{Consumed: Dimensions{}, Fee: 20}
+	second.Consumed[Bandwidth] = 5
+	second.Consumed[StorageWrite] = 5
+
+	// The share is taken from each dimension (rounding down) and any
+	// surcharge is attributed to bandwidth
+	share, burned, err := SplitFees(40, prices, []*Result{first, second})
+	require.NoError(err)
+	expected := Dimensions{}
+	expected[Bandwidth] = 21 // 35 - 14
+	expected[Compute] = 3    // 5 - 2
+	expected[StorageWrite] = 3
+	require.Equal(expected, burned)
+	require.Equal(uint64(18), share)
+
+	// Every unit of fee is either shared or burned
+	burnedTotal, err := MulSum(Dimensions{1, 1, 1, 1, 1}, burned)
+	require.NoError(err)
+	require.Equal(first.Fee+second.Fee, share+burnedTotal)
+
+	// Without a share, all fees are burned
+	share, burned, err = SplitFees(0, prices, []*Result{first, second})
+	require.NoError(err)
+	require.Zero(share)
+	burnedTotal, err = MulSum(Dimensions{1, 1, 1, 1, 1}, burned)
+	require.NoError(err)
+	require.Equal(first.Fee+second.Fee, burnedTotal)
+
+	_, _, err = SplitFees(MaxBuilderFeeShare+1, prices, nil)
+	require.ErrorIs(err, ErrInvalidBuilderFeeShare)
+}
+
+func TestDistributeFees(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetBuilderFeeShare().Return(uint64(50)).AnyTimes()
+	sm := testLedger{}
+	mem := memoryState{}
+	beneficiary := codec.CreateAddress(0, ids.GenerateTestID())
+	prices := Dimensions{1, 1, 1, 1, 1}
+	result := &Result{Consumed: Dimensions{}, Fee: 10}
+	result.Consumed[Bandwidth] = 10
+	distribute := func(beneficiary codec.Address) Dimensions {
+		ts := tstate.New(0)
+		burned, err := distributeFees(ctx, r, sm, mem, ts, prices, beneficiary, []*Result{result})
+		require.NoError(err)
+		mem.commit(ts)
+		return burned
+	}
+	totalBurned := func() Dimensions {
+		burned, err := getBurned(ctx, mem, BurnKey(sm.BurnKey()))
+		require.NoError(err)
+		return burned
+	}
+
+	// The beneficiary is credited with its share and the rest is burned
+	burned := distribute(beneficiary)
+	require.Equal(uint64(5), burned[Bandwidth])
+	require.Equal(uint64(5), sm.balance(t, mem, beneficiary))
+	require.Equal(burned, totalBurned())
+
+	// Without a beneficiary, all fees are burned (and accumulated with the
+	// fees burned by previous blocks)
+	burned = distribute(codec.EmptyAddress)
+	require.Equal(uint64(10), burned[Bandwidth])
+	require.Equal(uint64(5), sm.balance(t, mem, beneficiary))
+	require.Equal(uint64(15), totalBurned()[Bandwidth])
+}
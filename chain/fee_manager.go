@@ -4,13 +4,19 @@
 package chain
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
 	"github.com/ava-labs/hypersdk/window"
 )
 
@@ -21,15 +27,12 @@ const (
 	StorageAllocate Dimension = 3
 	StorageWrite    Dimension = 4 // includes delete
 
-	FeeDimensions = 5
-
-	DimensionsLen     = consts.Uint64Len * FeeDimensions
 	dimensionStateLen = consts.Uint64Len + window.WindowSliceSize + consts.Uint64Len
 )
 
 type (
 	Dimension  int
-	Dimensions [FeeDimensions]uint64
+	Dimensions [MaxFeeDimensions]uint64
 )
 
 // FeeManager is safe for concurrent use
@@ -39,12 +42,69 @@ type FeeManager struct {
 }
 
 func NewFeeManager(raw []byte) *FeeManager {
-	if len(raw) == 0 {
-		raw = make([]byte, FeeDimensions*dimensionStateLen)
+	// If dimensions were registered after [raw] was created, they start with
+	// a unit price of 0 (and are raised to the min unit price on the next
+	// block).
+	if l := FeeDimensions() * dimensionStateLen; len(raw) < l {
+		padded := make([]byte, l)
+		copy(padded, raw)
+		raw = padded
 	}
 	return &FeeManager{raw: raw}
 }
 
+// GetFeeManager returns the [FeeManager] stored at [FeeKey]. Chains created
+// before [FeeKeyChunks] was raised store it at [LegacyFeeKey] instead, which is
+// read if nothing is stored at [FeeKey] ([legacy] is true in that case). The
+// next accepted block moves it to [FeeKey].
+func GetFeeManager(ctx context.Context, sm StateManager, im state.Immutable) (fm *FeeManager, legacy bool, err error) {
+	raw, err := im.GetValue(ctx, FeeKey(sm.FeeKey()))
+	if errors.Is(err, database.ErrNotFound) {
+		raw, err = im.GetValue(ctx, LegacyFeeKey(sm.FeeKey()))
+		legacy = true
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return NewFeeManager(raw), legacy, nil
+}
+
+// newMetadataView returns a view over the chain metadata written at the end of
+// a block ([storage] holds the parent values of the other metadata keys).
+func newMetadataView(
+	ts *tstate.TState,
+	sm StateManager,
+	storage map[string][]byte,
+	parent *FeeManager,
+	legacy bool,
+) *tstate.TStateView {
+	feeKey := string(FeeKey(sm.FeeKey()))
+	scope := set.Of(feeKey)
+	for k := range storage {
+		scope.Add(k)
+	}
+	if legacy {
+		legacyFeeKey := string(LegacyFeeKey(sm.FeeKey()))
+		scope.Add(legacyFeeKey)
+		storage[legacyFeeKey] = parent.Bytes()
+	} else {
+		storage[feeKey] = parent.Bytes()
+	}
+	return ts.NewView(scope, storage)
+}
+
+// storeFeeManager writes [fm] to [FeeKey] (removing the [LegacyFeeKey] if the
+// parent fee state was read from it).
+func storeFeeManager(ctx context.Context, tsv *tstate.TStateView, sm StateManager, fm *FeeManager, legacy bool) error {
+	if err := tsv.Insert(ctx, FeeKey(sm.FeeKey()), fm.Bytes()); err != nil {
+		return err
+	}
+	if legacy {
+		return tsv.Remove(ctx, LegacyFeeKey(sm.FeeKey()))
+	}
+	return nil
+}
+
 func (f *FeeManager) UnitPrice(d Dimension) uint64 {
 	f.l.RLock()
 	defer f.l.RUnlock()
@@ -89,8 +149,8 @@ func (f *FeeManager) ComputeNext(lastTime int64, currTime int64, r Rules) (*FeeM
 	unitPriceChangeDenom := r.GetUnitPriceChangeDenominator()
 	minUnitPrice := r.GetMinUnitPrice()
 	since := int((currTime - lastTime) / consts.MillisecondsPerSecond)
-	bytes := make([]byte, dimensionStateLen*FeeDimensions)
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	bytes := make([]byte, dimensionStateLen*FeeDimensions())
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		nextUnitPrice, nextUnitWindow, err := computeNextPriceWindow(
 			f.Window(i),
 			f.LastConsumed(i),
@@ -140,7 +200,7 @@ func (f *FeeManager) Consume(d Dimensions, l Dimensions) (bool, Dimension) {
 	defer f.l.Unlock()
 
 	// Ensure we can consume (don't want partial update of values)
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		consumed, err := math.Add64(f.lastConsumed(i), d[i])
 		if err != nil {
 			return false, i
@@ -151,7 +211,7 @@ func (f *FeeManager) Consume(d Dimensions, l Dimensions) (bool, Dimension) {
 	}
 
 	// Commit to consumption
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		consumed, err := math.Add64(f.lastConsumed(i), d[i])
 		if err != nil {
 			return false, i
@@ -173,7 +233,7 @@ func (f *FeeManager) MaxFee(d Dimensions) (uint64, error) {
 	defer f.l.RUnlock()
 
 	fee := uint64(0)
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		contribution, err := math.Mul64(f.unitPrice(i), d[i])
 		if err != nil {
 			return 0, err
@@ -192,7 +252,7 @@ func (f *FeeManager) UnitPrices() Dimensions {
 	defer f.l.RUnlock()
 
 	var d Dimensions
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		d[i] = f.unitPrice(i)
 	}
 	return d
@@ -203,12 +263,39 @@ func (f *FeeManager) UnitsConsumed() Dimensions {
 	defer f.l.RUnlock()
 
 	var d Dimensions
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		d[i] = f.lastConsumed(i)
 	}
 	return d
 }
 
+// Congested returns the dominant dimension of [units] (the one that uses the
+// largest share of its [Rules.GetWindowTargetUnits]) and whether the fee
+// window of [f] is already over target in that dimension.
+func (f *FeeManager) Congested(units Dimensions, r Rules) (Dimension, bool) {
+	f.l.RLock()
+	defer f.l.RUnlock()
+
+	var (
+		targetUnits = r.GetWindowTargetUnits()
+		dominant    = Dimension(-1)
+		share       float64
+	)
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
+		if targetUnits[i] == 0 || units[i] == 0 {
+			continue
+		}
+		if s := float64(units[i]) / float64(targetUnits[i]); s > share {
+			dominant = i
+			share = s
+		}
+	}
+	if dominant < 0 {
+		return 0, false
+	}
+	return dominant, window.Sum(f.window(dominant)) > targetUnits[dominant]
+}
+
 func computeNextPriceWindow(
 	previous window.Window,
 	previousConsumed uint64,
@@ -281,7 +368,7 @@ func computeNextPriceWindow(
 
 func Add(a, b Dimensions) (Dimensions, error) {
 	d := Dimensions{}
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		v, err := math.Add64(a[i], b[i])
 		if err != nil {
 			return Dimensions{}, err
@@ -293,7 +380,7 @@ func Add(a, b Dimensions) (Dimensions, error) {
 
 func MulSum(a, b Dimensions) (uint64, error) {
 	val := uint64(0)
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		v, err := math.Mul64(a[i], b[i])
 		if err != nil {
 			return 0, err
@@ -307,6 +394,25 @@ func MulSum(a, b Dimensions) (uint64, error) {
 	return val, nil
 }
 
+// MinFee returns [fee] or, if it is lower, the minimum fee of a transaction
+// (see [Rules.GetMinFee]).
+func MinFee(r Rules, fee uint64) uint64 {
+	if minFee := r.GetMinFee(); fee < minFee {
+		return minFee
+	}
+	return fee
+}
+
+// EstimateFee returns the max fee of a transaction that consumes at most
+// [maxUnits] at [unitPrices].
+func EstimateFee(r Rules, unitPrices Dimensions, maxUnits Dimensions) (uint64, error) {
+	fee, err := MulSum(unitPrices, maxUnits)
+	if err != nil {
+		return 0, err
+	}
+	return MinFee(r, fee), nil
+}
+
 func (d Dimensions) Add(i Dimension, v uint64) error {
 	newValue, err := math.Add64(d[i], v)
 	if err != nil {
@@ -317,7 +423,7 @@ func (d Dimensions) Add(i Dimension, v uint64) error {
 }
 
 func (d Dimensions) CanAdd(a Dimensions, l Dimensions) bool {
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		consumed, err := math.Add64(d[i], a[i])
 		if err != nil {
 			return false
@@ -330,8 +436,8 @@ func (d Dimensions) CanAdd(a Dimensions, l Dimensions) bool {
 }
 
 func (d Dimensions) Bytes() []byte {
-	bytes := make([]byte, DimensionsLen)
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	bytes := make([]byte, DimensionsLen())
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		binary.BigEndian.PutUint64(bytes[i*consts.Uint64Len:], d[i])
 	}
 	return bytes
@@ -342,7 +448,7 @@ func (d Dimensions) Bytes() []byte {
 //
 // This would be considered a fatal error.
 func (d Dimensions) Greater(o Dimensions) bool {
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		if d[i] < o[i] {
 			return false
 		}
@@ -351,22 +457,22 @@ func (d Dimensions) Greater(o Dimensions) bool {
 }
 
 func UnpackDimensions(raw []byte) (Dimensions, error) {
-	if len(raw) != DimensionsLen {
-		return Dimensions{}, fmt.Errorf("%w: found=%d wanted=%d", ErrWrongDimensionSize, len(raw), DimensionsLen)
+	if len(raw) != DimensionsLen() {
+		return Dimensions{}, fmt.Errorf("%w: found=%d wanted=%d", ErrWrongDimensionSize, len(raw), DimensionsLen())
 	}
 	d := Dimensions{}
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		d[i] = binary.BigEndian.Uint64(raw[i*consts.Uint64Len:])
 	}
 	return d, nil
 }
 
 func ParseDimensions(raw []string) (Dimensions, error) {
-	if len(raw) != FeeDimensions {
+	if len(raw) != FeeDimensions() {
 		return Dimensions{}, ErrWrongDimensionSize
 	}
 	d := Dimensions{}
-	for i := Dimension(0); i < FeeDimensions; i++ {
+	for i := Dimension(0); int(i) < FeeDimensions(); i++ {
 		v, err := strconv.ParseUint(raw[i], 10, 64)
 		if err != nil {
 			return Dimensions{}, err
@@ -0,0 +1,62 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+type testFeeManager struct {
+	StateManager
+}
+
+func (testFeeManager) FeeKey() []byte {
+	return []byte{0x6}
+}
+
+func TestLegacyFeeManager(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sm := testFeeManager{}
+	mem := memoryState{}
+	_, _, err := GetFeeManager(ctx, sm, mem)
+	require.Error(err)
+
+	// Fee state stored before custom fee dimensions is read from the legacy key
+	parent := NewFeeManager(nil)
+	parent.SetUnitPrice(Bandwidth, 7)
+	require.NoError(mem.Insert(ctx, LegacyFeeKey(sm.FeeKey()), parent.Bytes()))
+	fm, legacy, err := GetFeeManager(ctx, sm, mem)
+	require.NoError(err)
+	require.True(legacy)
+	require.Equal(uint64(7), fm.UnitPrice(Bandwidth))
+
+	// The next block moves it to the new key
+	ts := tstate.New(0)
+	tsv := newMetadataView(ts, sm, map[string][]byte{}, fm, legacy)
+	next := NewFeeManager(nil)
+	next.SetUnitPrice(Bandwidth, 8)
+	require.NoError(storeFeeManager(ctx, tsv, sm, next, legacy))
+	tsv.Commit()
+	mem.commit(ts)
+	require.NotContains(mem, string(LegacyFeeKey(sm.FeeKey())))
+	fm, legacy, err = GetFeeManager(ctx, sm, mem)
+	require.NoError(err)
+	require.False(legacy)
+	require.Equal(uint64(8), fm.UnitPrice(Bandwidth))
+
+	// Once moved, the fee state is only updated at the new key
+	ts = tstate.New(0)
+	tsv = newMetadataView(ts, sm, map[string][]byte{}, fm, legacy)
+	require.NoError(storeFeeManager(ctx, tsv, sm, fm, legacy))
+	tsv.Commit()
+	mem.commit(ts)
+	require.Len(mem, 1)
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// preBlock invokes [Hooks.PreBlock] (if [hooks] is not nil).
+func preBlock(ctx context.Context, hooks Hooks, r Rules, im state.Immutable, timestamp int64, height uint64) error {
+	if hooks == nil {
+		return nil
+	}
+	if err := hooks.PreBlock(ctx, r, im, timestamp, height); err != nil {
+		return fmt.Errorf("%w: %v", ErrBlockRejectedByHook, err)
+	}
+	return nil
+}
+
+// PreExecuteHook invokes [Hooks.PreExecute] (if [hooks] is not nil).
+func PreExecuteHook(
+	ctx context.Context,
+	hooks Hooks,
+	r Rules,
+	im state.Immutable,
+	timestamp int64,
+	height uint64,
+	tx *Transaction,
+) error {
+	if hooks == nil {
+		return nil
+	}
+	if err := hooks.PreExecute(ctx, r, im, timestamp, height, tx); err != nil {
+		return fmt.Errorf("%w: %v", ErrTxRejectedByHook, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// The canonical JSON encoding of transactions, results, and blocks is shared
+// by RPC responses and the cli so that tools never need to decode their
+// binary format.
+//
+// It is deterministic: fields are always encoded in the same order, byte
+// slices are encoded as base64, and fixed-size identifiers (like
+// [codec.Address]) are encoded as hex. Actions and auths are tagged with
+// their name in the registry (see [codec.TypeParser.SetName]).
+
+// TypeRegistry names the types of actions and auths (every [Parser] is a
+// TypeRegistry).
+type TypeRegistry interface {
+	Registry() (ActionRegistry, AuthRegistry)
+}
+
+// TypedJSON is the canonical JSON encoding of an [Action] or [Auth].
+type TypedJSON struct {
+	// Type is the registered name of the type or, if it has none, its type
+	// ID.
+	Type   string `json:"type"`
+	TypeID uint8  `json:"typeId"`
+	// Version is only set if the type is [Versioned].
+	Version *uint8          `json:"version,omitempty"`
+	Value   json.RawMessage `json:"value"`
+}
+
+func newTypedJSON[T any](
+	registry *codec.TypeParser[T, *warp.Message, bool],
+	typeID uint8,
+	v T,
+) (*TypedJSON, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	name, ok := registry.LookupName(typeID)
+	if !ok {
+		name = strconv.Itoa(int(typeID))
+	}
+	typed := &TypedJSON{Type: name, TypeID: typeID, Value: value}
+	if versioned, ok := any(v).(Versioned); ok {
+		version := versioned.GetVersion()
+		typed.Version = &version
+	}
+	return typed, nil
+}
+
+// TransactionJSON is the canonical JSON encoding of a [Transaction].
+type TransactionJSON struct {
+	ID          ids.ID     `json:"id"`
+	Base        *Base      `json:"base"`
+	WarpMessage []byte     `json:"warpMessage,omitempty"`
+	Blob        []byte     `json:"blob,omitempty"`
+	AccessList  [][]byte   `json:"accessList,omitempty"`
+	Action      *TypedJSON `json:"action"`
+	Auth        *TypedJSON `json:"auth"`
+	Size        int        `json:"size"`
+}
+
+func NewTransactionJSON(registry TypeRegistry, tx *Transaction) (*TransactionJSON, error) {
+	actionRegistry, authRegistry := registry.Registry()
+	action, err := newTypedJSON(actionRegistry, tx.Action.GetTypeID(), tx.Action)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := newTypedJSON(authRegistry, tx.Auth.GetTypeID(), tx.Auth)
+	if err != nil {
+		return nil, err
+	}
+	txJSON := &TransactionJSON{
+		ID:     tx.ID(),
+		Base:   tx.Base,
+		Blob:   tx.Blob,
+		Action: action,
+		Auth:   auth,
+		Size:   tx.Size(),
+	}
+	if tx.WarpMessage != nil {
+		txJSON.WarpMessage = tx.WarpMessage.Bytes()
+	}
+	for _, k := range tx.AccessList {
+		txJSON.AccessList = append(txJSON.AccessList, []byte(k))
+	}
+	return txJSON, nil
+}
+
+// ResultJSON is the canonical JSON encoding of a [Result].
+type ResultJSON struct {
+	Success     bool       `json:"success"`
+	Status      Status     `json:"status"`
+	Output      []byte     `json:"output"`
+	Consumed    Dimensions `json:"consumed"`
+	Fee         uint64     `json:"fee"`
+	WarpMessage []byte     `json:"warpMessage"`
+
+	// DecodedOutput is the structured form of [Output] (only set by
+	// [NewTypedResultJSON] for actions with an [OutputDecoder]).
+	DecodedOutput any `json:"decodedOutput,omitempty"`
+}
+
+func NewResultJSON(result *Result) *ResultJSON {
+	r := &ResultJSON{
+		Success:  result.Success(),
+		Status:   result.Status,
+		Output:   result.Output,
+		Consumed: result.Consumed,
+		Fee:      result.Fee,
+	}
+	if result.WarpMessage != nil {
+		r.WarpMessage = result.WarpMessage.Bytes()
+	}
+	return r
+}
+
+// NewTypedResultJSON is [NewResultJSON] with the output of [result] (the
+// result of [tx]) decoded by [registry] (see [OutputTypeRegistry]).
+func NewTypedResultJSON(registry TypeRegistry, tx *Transaction, result *Result) (*ResultJSON, error) {
+	r := NewResultJSON(result)
+	decoded, ok, err := DecodeOutput(registry, tx, result)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		r.DecodedOutput = decoded
+	}
+	return r, nil
+}
+
+// BlockJSON is the canonical JSON encoding of a [StatefulBlock].
+type BlockJSON struct {
+	ID          ids.ID             `json:"id"`
+	Parent      ids.ID             `json:"parent"`
+	Height      uint64             `json:"height"`
+	Timestamp   int64              `json:"timestamp"`
+	Txs         []*TransactionJSON `json:"txs"`
+	StateRoot   ids.ID             `json:"stateRoot"`
+	WarpResults uint64             `json:"warpResults"`
+	BlobsRoot   ids.ID             `json:"blobsRoot"`
+	Beneficiary codec.Address      `json:"beneficiary"`
+	Size        int                `json:"size"`
+
+	// Results are only included if the block was executed.
+	Results []*ResultJSON `json:"results,omitempty"`
+}
+
+// NewBlockJSON encodes [blk] (with ID [blkID]) and, if provided, the
+// [results] of its execution.
+func NewBlockJSON(registry TypeRegistry, blkID ids.ID, blk *StatefulBlock, results []*Result) (*BlockJSON, error) {
+	blkJSON := &BlockJSON{
+		ID:          blkID,
+		Parent:      blk.Prnt,
+		Height:      blk.Hght,
+		Timestamp:   blk.Tmstmp,
+		Txs:         make([]*TransactionJSON, len(blk.Txs)),
+		StateRoot:   blk.StateRoot,
+		WarpResults: uint64(blk.WarpResults),
+		BlobsRoot:   blk.BlobsRoot,
+		Beneficiary: blk.Beneficiary,
+		Size:        blk.Size(),
+	}
+	for i, tx := range blk.Txs {
+		txJSON, err := NewTransactionJSON(registry, tx)
+		if err != nil {
+			return nil, err
+		}
+		blkJSON.Txs[i] = txJSON
+	}
+	if results != nil {
+		blkJSON.Results = make([]*ResultJSON, len(results))
+		for i, result := range results {
+			resultJSON, err := NewTypedResultJSON(registry, blk.Txs[i], result)
+			if err != nil {
+				return nil, err
+			}
+			blkJSON.Results[i] = resultJSON
+		}
+	}
+	return blkJSON, nil
+}
@@ -16,6 +16,7 @@ import (
 	reflect "reflect"
 
 	ids "github.com/ava-labs/avalanchego/ids"
+	codec "github.com/ava-labs/hypersdk/codec"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -71,6 +72,66 @@ func (mr *MockRulesMockRecorder) FetchCustom(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCustom", reflect.TypeOf((*MockRules)(nil).FetchCustom), arg0)
 }
 
+// GetActionComputeUnits mocks base method.
+func (m *MockRules) GetActionComputeUnits(arg0 uint8) (uint64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionComputeUnits", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetActionComputeUnits indicates an expected call of GetActionComputeUnits.
+func (mr *MockRulesMockRecorder) GetActionComputeUnits(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionComputeUnits", reflect.TypeOf((*MockRules)(nil).GetActionComputeUnits), arg0)
+}
+
+// GetActionDeprecationHeight mocks base method.
+func (m *MockRules) GetActionDeprecationHeight(arg0, arg1 uint8) (uint64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionDeprecationHeight", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetActionDeprecationHeight indicates an expected call of GetActionDeprecationHeight.
+func (mr *MockRulesMockRecorder) GetActionDeprecationHeight(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionDeprecationHeight", reflect.TypeOf((*MockRules)(nil).GetActionDeprecationHeight), arg0, arg1)
+}
+
+// GetActionFeePayer mocks base method.
+func (m *MockRules) GetActionFeePayer(arg0 uint8) (codec.Address, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionFeePayer", arg0)
+	ret0, _ := ret[0].(codec.Address)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetActionFeePayer indicates an expected call of GetActionFeePayer.
+func (mr *MockRulesMockRecorder) GetActionFeePayer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionFeePayer", reflect.TypeOf((*MockRules)(nil).GetActionFeePayer), arg0)
+}
+
+// GetAuthDeprecationHeight mocks base method.
+func (m *MockRules) GetAuthDeprecationHeight(arg0, arg1 uint8) (uint64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthDeprecationHeight", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetAuthDeprecationHeight indicates an expected call of GetAuthDeprecationHeight.
+func (mr *MockRulesMockRecorder) GetAuthDeprecationHeight(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthDeprecationHeight", reflect.TypeOf((*MockRules)(nil).GetAuthDeprecationHeight), arg0, arg1)
+}
+
 // GetBaseComputeUnits mocks base method.
 func (m *MockRules) GetBaseComputeUnits() uint64 {
 	m.ctrl.T.Helper()
@@ -99,6 +160,90 @@ func (mr *MockRulesMockRecorder) GetBaseWarpComputeUnits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBaseWarpComputeUnits", reflect.TypeOf((*MockRules)(nil).GetBaseWarpComputeUnits))
 }
 
+// GetBlockReward mocks base method.
+func (m *MockRules) GetBlockReward(arg0 uint64) uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockReward", arg0)
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetBlockReward indicates an expected call of GetBlockReward.
+func (mr *MockRulesMockRecorder) GetBlockReward(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockReward", reflect.TypeOf((*MockRules)(nil).GetBlockReward), arg0)
+}
+
+// GetBlockRewardPool mocks base method.
+func (m *MockRules) GetBlockRewardPool() codec.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockRewardPool")
+	ret0, _ := ret[0].(codec.Address)
+	return ret0
+}
+
+// GetBlockRewardPool indicates an expected call of GetBlockRewardPool.
+func (mr *MockRulesMockRecorder) GetBlockRewardPool() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockRewardPool", reflect.TypeOf((*MockRules)(nil).GetBlockRewardPool))
+}
+
+// GetBlockWitnesses mocks base method.
+func (m *MockRules) GetBlockWitnesses() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockWitnesses")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetBlockWitnesses indicates an expected call of GetBlockWitnesses.
+func (mr *MockRulesMockRecorder) GetBlockWitnesses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockWitnesses", reflect.TypeOf((*MockRules)(nil).GetBlockWitnesses))
+}
+
+// GetBuilderFeeShare mocks base method.
+func (m *MockRules) GetBuilderFeeShare() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuilderFeeShare")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetBuilderFeeShare indicates an expected call of GetBuilderFeeShare.
+func (mr *MockRulesMockRecorder) GetBuilderFeeShare() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuilderFeeShare", reflect.TypeOf((*MockRules)(nil).GetBuilderFeeShare))
+}
+
+// GetEpochLength mocks base method.
+func (m *MockRules) GetEpochLength() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochLength")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetEpochLength indicates an expected call of GetEpochLength.
+func (mr *MockRulesMockRecorder) GetEpochLength() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochLength", reflect.TypeOf((*MockRules)(nil).GetEpochLength))
+}
+
+// GetHeightValidityWindow mocks base method.
+func (m *MockRules) GetHeightValidityWindow() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeightValidityWindow")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetHeightValidityWindow indicates an expected call of GetHeightValidityWindow.
+func (mr *MockRulesMockRecorder) GetHeightValidityWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeightValidityWindow", reflect.TypeOf((*MockRules)(nil).GetHeightValidityWindow))
+}
+
 // GetMaxBlockUnits mocks base method.
 func (m *MockRules) GetMaxBlockUnits() Dimensions {
 	m.ctrl.T.Helper()
@@ -141,6 +286,20 @@ func (mr *MockRulesMockRecorder) GetMinEmptyBlockGap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMinEmptyBlockGap", reflect.TypeOf((*MockRules)(nil).GetMinEmptyBlockGap))
 }
 
+// GetMinFee mocks base method.
+func (m *MockRules) GetMinFee() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMinFee")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetMinFee indicates an expected call of GetMinFee.
+func (mr *MockRulesMockRecorder) GetMinFee() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMinFee", reflect.TypeOf((*MockRules)(nil).GetMinFee))
+}
+
 // GetMinUnitPrice mocks base method.
 func (m *MockRules) GetMinUnitPrice() Dimensions {
 	m.ctrl.T.Helper()
@@ -169,6 +328,34 @@ func (mr *MockRulesMockRecorder) GetOutgoingWarpComputeUnits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingWarpComputeUnits", reflect.TypeOf((*MockRules)(nil).GetOutgoingWarpComputeUnits))
 }
 
+// GetSequentialNonces mocks base method.
+func (m *MockRules) GetSequentialNonces() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSequentialNonces")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetSequentialNonces indicates an expected call of GetSequentialNonces.
+func (mr *MockRulesMockRecorder) GetSequentialNonces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSequentialNonces", reflect.TypeOf((*MockRules)(nil).GetSequentialNonces))
+}
+
+// GetTxCancellation mocks base method.
+func (m *MockRules) GetTxCancellation() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxCancellation")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetTxCancellation indicates an expected call of GetTxCancellation.
+func (mr *MockRulesMockRecorder) GetTxCancellation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxCancellation", reflect.TypeOf((*MockRules)(nil).GetTxCancellation))
+}
+
 // GetSponsorStateKeysMaxChunks mocks base method.
 func (m *MockRules) GetSponsorStateKeysMaxChunks() []uint16 {
 	m.ctrl.T.Helper()
@@ -281,6 +468,20 @@ func (mr *MockRulesMockRecorder) GetUnitPriceChangeDenominator() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnitPriceChangeDenominator", reflect.TypeOf((*MockRules)(nil).GetUnitPriceChangeDenominator))
 }
 
+// GetValidatorSnapshots mocks base method.
+func (m *MockRules) GetValidatorSnapshots() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorSnapshots")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetValidatorSnapshots indicates an expected call of GetValidatorSnapshots.
+func (mr *MockRulesMockRecorder) GetValidatorSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorSnapshots", reflect.TypeOf((*MockRules)(nil).GetValidatorSnapshots))
+}
+
 // GetValidityWindow mocks base method.
 func (m *MockRules) GetValidityWindow() int64 {
 	m.ctrl.T.Helper()
@@ -325,6 +526,34 @@ func (mr *MockRulesMockRecorder) GetWarpConfig(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarpConfig", reflect.TypeOf((*MockRules)(nil).GetWarpConfig), arg0)
 }
 
+// GetWarpMaxSigners mocks base method.
+func (m *MockRules) GetWarpMaxSigners() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarpMaxSigners")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetWarpMaxSigners indicates an expected call of GetWarpMaxSigners.
+func (mr *MockRulesMockRecorder) GetWarpMaxSigners() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarpMaxSigners", reflect.TypeOf((*MockRules)(nil).GetWarpMaxSigners))
+}
+
+// GetWarpReplayRetention mocks base method.
+func (m *MockRules) GetWarpReplayRetention() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarpReplayRetention")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetWarpReplayRetention indicates an expected call of GetWarpReplayRetention.
+func (mr *MockRulesMockRecorder) GetWarpReplayRetention() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarpReplayRetention", reflect.TypeOf((*MockRules)(nil).GetWarpReplayRetention))
+}
+
 // GetWindowTargetUnits mocks base method.
 func (m *MockRules) GetWindowTargetUnits() Dimensions {
 	m.ctrl.T.Helper()
@@ -339,6 +568,34 @@ func (mr *MockRulesMockRecorder) GetWindowTargetUnits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWindowTargetUnits", reflect.TypeOf((*MockRules)(nil).GetWindowTargetUnits))
 }
 
+// IsActionDisabled mocks base method.
+func (m *MockRules) IsActionDisabled(arg0 uint8) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsActionDisabled", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsActionDisabled indicates an expected call of IsActionDisabled.
+func (mr *MockRulesMockRecorder) IsActionDisabled(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsActionDisabled", reflect.TypeOf((*MockRules)(nil).IsActionDisabled), arg0)
+}
+
+// IsFeatureActive mocks base method.
+func (m *MockRules) IsFeatureActive(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFeatureActive", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsFeatureActive indicates an expected call of IsFeatureActive.
+func (mr *MockRulesMockRecorder) IsFeatureActive(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFeatureActive", reflect.TypeOf((*MockRules)(nil).IsFeatureActive), arg0)
+}
+
 // NetworkID mocks base method.
 func (m *MockRules) NetworkID() uint32 {
 	m.ctrl.T.Helper()
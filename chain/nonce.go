@@ -0,0 +1,89 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// NonceKey returns the state key (suffixed with [NonceKeyChunks]) that stores
+// the next nonce of [addr], if [sm] implements [NonceManager].
+func NonceKey(sm StateManager, addr codec.Address) ([]byte, error) {
+	nm, ok := sm.(NonceManager)
+	if !ok {
+		return nil, ErrNoncesUnsupported
+	}
+	return keys.EncodeChunks(nm.NonceKey(addr), NonceKeyChunks), nil
+}
+
+// GetNonce returns the nonce that the next transaction sponsored by [addr]
+// must use.
+func GetNonce(ctx context.Context, sm StateManager, im state.Immutable, addr codec.Address) (uint64, error) {
+	k, err := NonceKey(sm, addr)
+	if err != nil {
+		return 0, err
+	}
+	return getNonce(ctx, im, k)
+}
+
+func getNonce(ctx context.Context, im state.Immutable, k []byte) (uint64, error) {
+	v, err := im.GetValue(ctx, k)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return ParseNonce(v)
+}
+
+// ParseNonce decodes the value stored at a [NonceKey].
+func ParseNonce(v []byte) (uint64, error) {
+	if len(v) != consts.Uint64Len {
+		return 0, fmt.Errorf("%w: nonce has %d bytes", ErrInvalidObject, len(v))
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// verifyNonce returns [ErrNonceTooHigh] if the transaction is ahead of the
+// next nonce of its sponsor (so it may become valid once the gap is filled)
+// and [ErrNonceTooLow] if it can never be executed.
+func (t *Transaction) verifyNonce(ctx context.Context, sm StateManager, im state.Immutable) error {
+	k, err := NonceKey(sm, t.Auth.Sponsor())
+	if err != nil {
+		return err
+	}
+	next, err := getNonce(ctx, im, k)
+	if err != nil {
+		return err
+	}
+	switch {
+	case t.Base.Nonce < next:
+		return fmt.Errorf("%w: next=%d nonce=%d", ErrNonceTooLow, next, t.Base.Nonce)
+	case t.Base.Nonce > next:
+		return fmt.Errorf("%w: next=%d nonce=%d", ErrNonceTooHigh, next, t.Base.Nonce)
+	default:
+		return nil
+	}
+}
+
+// incrementNonce consumes the nonce of the transaction, whether or not its
+// [Action] succeeds.
+func (t *Transaction) incrementNonce(ctx context.Context, sm StateManager, mu state.Mutable) error {
+	k, err := NonceKey(sm, t.Auth.Sponsor())
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, t.Base.Nonce+1))
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+type testNonceManager struct {
+	StateManager
+}
+
+func (testNonceManager) NonceKey(addr codec.Address) []byte {
+	return append([]byte{0x4}, addr[:]...)
+}
+
+func TestNonceKey(t *testing.T) {
+	require := require.New(t)
+
+	sponsor := codec.CreateAddress(0, ids.GenerateTestID())
+	k, err := NonceKey(testNonceManager{}, sponsor)
+	require.NoError(err)
+	require.True(keys.Valid(string(k)))
+	chunks, ok := keys.MaxChunks(k)
+	require.True(ok)
+	require.Equal(uint16(NonceKeyChunks), chunks)
+
+	_, err = NonceKey(testWarpManager{}, sponsor)
+	require.ErrorIs(err, ErrNoncesUnsupported)
+}
+
+func TestVerifyNonce(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sponsor := codec.CreateAddress(0, ids.GenerateTestID())
+	auth := NewMockAuth(ctrl)
+	auth.EXPECT().Sponsor().Return(sponsor).AnyTimes()
+	sm := testNonceManager{}
+	mem := memoryState{}
+	nonce := func() uint64 {
+		nonce, err := GetNonce(ctx, sm, mem, sponsor)
+		require.NoError(err)
+		return nonce
+	}
+	require.Zero(nonce())
+
+	// A transaction ahead of the next nonce may become valid once the gap is
+	// filled
+	tx0 := &Transaction{Base: &Base{Nonce: 0}, Auth: auth}
+	tx1 := &Transaction{Base: &Base{Nonce: 1}, Auth: auth}
+	require.ErrorIs(tx1.verifyNonce(ctx, sm, mem), ErrNonceTooHigh)
+	require.NoError(tx0.verifyNonce(ctx, sm, mem))
+	require.NoError(tx0.incrementNonce(ctx, sm, mem))
+	require.Equal(uint64(1), nonce())
+	require.NoError(tx1.verifyNonce(ctx, sm, mem))
+	require.NoError(tx1.incrementNonce(ctx, sm, mem))
+	require.Equal(uint64(2), nonce())
+
+	// Nonces can't be reused
+	require.ErrorIs(tx0.verifyNonce(ctx, sm, mem), ErrNonceTooLow)
+	require.ErrorIs(tx1.verifyNonce(ctx, sm, mem), ErrNonceTooLow)
+
+	k, err := NonceKey(sm, sponsor)
+	require.NoError(err)
+	_, err = ParseNonce(mem[string(k)][1:])
+	require.ErrorIs(err, ErrInvalidObject)
+}
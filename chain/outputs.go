@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// OutputDecoder returns the JSON-serializable form of the output of a
+// successful [Action].
+type OutputDecoder func(output []byte) (any, error)
+
+// NewOutputDecoder returns an [OutputDecoder] for outputs unmarshaled by [f]
+// (like the Unmarshal function of the output's type).
+func NewOutputDecoder[T any](f func([]byte) (T, error)) OutputDecoder {
+	return func(output []byte) (any, error) {
+		return f(output)
+	}
+}
+
+// OutputRegistry maps the type ID of each [Action] to the [OutputDecoder] of
+// its output, so that outputs can be served as structured JSON instead of
+// bytes that clients must know how to parse.
+//
+// Actions without structured outputs (or that only output on failure) don't
+// need to register a decoder.
+type OutputRegistry struct {
+	l        sync.RWMutex
+	decoders map[uint8]OutputDecoder
+}
+
+func NewOutputRegistry() *OutputRegistry {
+	return &OutputRegistry{decoders: map[uint8]OutputDecoder{}}
+}
+
+func (r *OutputRegistry) Register(typeID uint8, d OutputDecoder) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if _, ok := r.decoders[typeID]; ok {
+		return fmt.Errorf("%w: output of %d", codec.ErrDuplicateItem, typeID)
+	}
+	r.decoders[typeID] = d
+	return nil
+}
+
+// Decode returns the decoded [output] of the [Action] with [typeID]. False is
+// returned if the action has no registered decoder (or [r] is nil).
+func (r *OutputRegistry) Decode(typeID uint8, output []byte) (any, bool, error) {
+	if r == nil {
+		return nil, false, nil
+	}
+	r.l.RLock()
+	d, ok := r.decoders[typeID]
+	r.l.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	v, err := d(output)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: output of %d: %w", ErrInvalidObject, typeID, err)
+	}
+	return v, true, nil
+}
+
+// OutputTypeRegistry can be implemented by a [TypeRegistry] (like a
+// [Parser]) that can decode the outputs of its actions.
+type OutputTypeRegistry interface {
+	OutputRegistry() *OutputRegistry
+}
+
+// DecodeOutput returns the decoded output of [result] (the result of [tx]).
+// False is returned if [result] failed or [registry] can't decode the output
+// of the action of [tx].
+func DecodeOutput(registry TypeRegistry, tx *Transaction, result *Result) (any, bool, error) {
+	outputs, ok := registry.(OutputTypeRegistry)
+	if !ok || !result.Success() || len(result.Output) == 0 {
+		return nil, false, nil
+	}
+	return outputs.OutputRegistry().Decode(tx.Action.GetTypeID(), result.Output)
+}
@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 
 	"github.com/ava-labs/hypersdk/executor"
 	"github.com/ava-labs/hypersdk/keys"
@@ -31,9 +33,73 @@ func (b *StatelessBlock) Execute(
 	im state.Immutable,
 	feeManager *FeeManager,
 	r Rules,
+) ([]*Result, *tstate.TState, error) {
+	return b.execute(ctx, tracer, im, feeManager, r, nil, nil)
+}
+
+// ExecuteWithTrace is the same as [Execute] but also returns an
+// [ExecutionTrace] of the parallel execution.
+func (b *StatelessBlock) ExecuteWithTrace(
+	ctx context.Context,
+	tracer trace.Tracer, //nolint:interfacer
+	im state.Immutable,
+	feeManager *FeeManager,
+	r Rules,
+) ([]*Result, *tstate.TState, *ExecutionTrace, error) {
+	et := newExecutionTrace(b, false)
+	results, ts, err := b.execute(ctx, tracer, im, feeManager, r, et, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return results, ts, et, nil
+}
+
+// Replay re-executes the block one transaction at a time, in block order, and
+// returns its [ExecutionTrace]. Warp verification results are taken from
+// [recorded] instead of being awaited again.
+//
+// [feeManager] must be equivalent to (but not the same as) the one used to
+// produce [recorded].
+func (b *StatelessBlock) Replay(
+	ctx context.Context,
+	tracer trace.Tracer, //nolint:interfacer
+	im state.Immutable,
+	feeManager *FeeManager,
+	r Rules,
+	recorded *ExecutionTrace,
+) (*ExecutionTrace, error) {
+	et := newExecutionTrace(b, true)
+	if _, _, err := b.execute(ctx, tracer, im, feeManager, r, et, recorded); err != nil {
+		return nil, err
+	}
+	return et, nil
+}
+
+// execute processes all transactions in the block. If [et] is not nil, each
+// execution is recorded in it. If [recorded] is not nil, transactions are
+// executed sequentially using the warp results in [recorded].
+func (b *StatelessBlock) execute(
+	ctx context.Context,
+	tracer trace.Tracer, //nolint:interfacer
+	im state.Immutable,
+	feeManager *FeeManager,
+	r Rules,
+	et *ExecutionTrace,
+	recorded *ExecutionTrace,
 ) ([]*Result, *tstate.TState, error) {
 	ctx, span := tracer.Start(ctx, "Processor.Execute")
 	defer span.End()
+	ctx = WithBlockSeed(ctx, b.Seed())
+
+	// Only executions that are neither replayed nor checked for determinism
+	// are traced per transaction
+	var (
+		start = time.Now()
+		bt    *BlockTrace
+	)
+	if recorded == nil && shouldTrace(ctx, span) {
+		bt = newBlockTrace(b, start)
+	}
 
 	var (
 		sm        = b.vm.StateManager()
@@ -42,22 +108,45 @@ func (b *StatelessBlock) Execute(
 		cacheLock sync.RWMutex
 		cache     = make(map[string]*fetchData, numTxs)
 
-		e       = executor.New(numTxs, b.vm.GetTransactionExecutionCores(), b.vm.GetExecutorVerifyRecorder())
+		e       txExecutor
 		ts      = tstate.New(numTxs * 2) // TODO: tune this heuristic
 		results = make([]*Result, numTxs)
+		hooks   = b.vm.Hooks()
 	)
+	if err := preBlock(ctx, hooks, r, im, t, b.Hght); err != nil {
+		return nil, nil, err
+	}
+	if recorded != nil {
+		e = &sequentialExecutor{}
+	} else {
+		e = executor.New(numTxs, b.vm.GetTransactionExecutionCores(), b.vm.GetExecutorVerifyRecorder())
+	}
 
 	// Fetch required keys and execute transactions
 	for li, ltx := range b.Txs {
 		i := li
 		tx := ltx
 
-		stateKeys, err := tx.StateKeys(sm)
+		if err := tx.CheckDeprecated(r, b.Hght); err != nil {
+			e.Stop()
+			return nil, nil, err
+		}
+		stateKeys, err := tx.StateKeys(sm, r)
 		if err != nil {
+			if errors.Is(err, ErrAccessListMismatch) {
+				b.vm.RecordAccessListMismatch()
+			}
 			e.Stop()
 			return nil, nil, err
 		}
 		e.Run(stateKeys, func() error {
+			var s *txSpan
+			if bt != nil {
+				s = startTxSpan(ctx, tracer, tx, bt.Timings[i], start)
+				defer s.end()
+			}
+			ctx := s.next(ctx, txRead)
+
 			// Fetch keys from cache
 			var (
 				reads    = make(map[string]uint16, len(stateKeys))
@@ -107,27 +196,54 @@ func (b *StatelessBlock) Execute(
 			// It is critical we explicitly set the scope before each transaction is
 			// processed
 			tsv := ts.NewView(stateKeys, storage)
+			var txTrace *TxTrace
+			if et != nil {
+				txTrace = &TxTrace{ID: tx.ID(), Reads: make(map[string]maybe.Maybe[[]byte], len(stateKeys))}
+				for k := range stateKeys {
+					v, err := tsv.GetValue(ctx, []byte(k))
+					switch {
+					case err == nil:
+						txTrace.Reads[k] = maybe.Some(v)
+					case errors.Is(err, database.ErrNotFound):
+						txTrace.Reads[k] = maybe.Nothing[[]byte]()
+					default:
+						return err
+					}
+				}
+			}
 
 			// Ensure we have enough funds to pay fees
-			if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, t); err != nil {
+			ctx = s.next(ctx, txVerify)
+			if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, t, b.Hght); err != nil {
+				return err
+			}
+			if err := PreExecuteHook(ctx, hooks, r, tsv, t, b.Hght, tx); err != nil {
 				return err
 			}
 
 			// Wait to execute transaction until we have the warp result processed.
 			var warpVerified bool
 			warpMsg, ok := b.warpMessages[tx.ID()]
-			if ok {
+			switch {
+			case ok && recorded != nil:
+				warpVerified = recorded.Txs[i] != nil && recorded.Txs[i].WarpVerified
+			case ok:
 				select {
 				case warpVerified = <-warpMsg.verifiedChan:
 				case <-ctx.Done():
 					return ctx.Err()
 				}
 			}
+			ctx = s.next(ctx, txExecute)
 			result, err := tx.Execute(ctx, feeManager, reads, sm, r, tsv, t, ok && warpVerified)
 			if err != nil {
 				return err
 			}
+			if !result.Success() && tx.FeeExempt(r) {
+				return fmt.Errorf("%w: %s", ErrFeeExemptTxFailed, tx.ID())
+			}
 			results[i] = result
+			s.setResult(result)
 
 			// Update block metadata with units actually consumed (if more is consumed than block allows, we will non-deterministically
 			// exit with an error based on which tx over the limit is processed first)
@@ -135,7 +251,18 @@ func (b *StatelessBlock) Execute(
 				return fmt.Errorf("%w: %d too large", ErrInvalidUnitsConsumed, d)
 			}
 
+			if hooks != nil {
+				hooks.PostExecute(ctx, r, tsv, t, b.Hght, tx, result)
+			}
+
 			// Commit results to parent [TState]
+			s.next(ctx, txWrite)
+			if txTrace != nil {
+				txTrace.WarpVerified = warpVerified
+				txTrace.Writes = tsv.Changes()
+				txTrace.Result = result
+				et.record(i, txTrace)
+			}
 			tsv.Commit()
 
 			// Update key cache
@@ -152,6 +279,13 @@ func (b *StatelessBlock) Execute(
 	if err := e.Wait(); err != nil {
 		return nil, nil, err
 	}
+	if bt != nil {
+		bt.Execution = time.Since(start)
+		b.trace = bt
+	}
+	if hooks != nil {
+		hooks.PostBlock(ctx, r, t, b.Hght, b.Txs, results)
+	}
 
 	// Return tstate that can be used to add block-level keys to state
 	return results, ts, nil
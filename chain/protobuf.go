@@ -0,0 +1,623 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// Field numbers of the messages in proto/chain.proto.
+//
+// The messages are encoded by hand (instead of with generated code) so that
+// decoding can be bounded like the rest of the wire protocol. Objects are
+// decoded by converting them to [BinaryEncoding], so every check done by the
+// native parser applies to them too.
+//
+// Actions and auths are carried as opaque payloads in their [BinaryEncoding]
+// (see [appendProtoTyped]), as their fields are defined by each VM.
+const (
+	protoBaseTimestamp   protowire.Number = 1
+	protoBaseChainID     protowire.Number = 2
+	protoBaseMaxFee      protowire.Number = 3
+	protoBaseHeight      protowire.Number = 4
+	protoBaseNonce       protowire.Number = 5
+	protoBaseFeeAsset    protowire.Number = 6
+	protoBaseMaxFeeAsset protowire.Number = 7
+
+	protoTypedTypeID  protowire.Number = 1
+	protoTypedPayload protowire.Number = 2
+	protoTypedVersion protowire.Number = 3
+
+	protoTxBase        protowire.Number = 1
+	protoTxWarpMessage protowire.Number = 2
+	protoTxBlob        protowire.Number = 3
+	protoTxAction      protowire.Number = 4
+	protoTxAuth        protowire.Number = 5
+	protoTxAccessList  protowire.Number = 6
+
+	protoResultSuccess     protowire.Number = 1
+	protoResultOutput      protowire.Number = 2
+	protoResultConsumed    protowire.Number = 3
+	protoResultFee         protowire.Number = 4
+	protoResultWarpMessage protowire.Number = 5
+	protoResultStatus      protowire.Number = 6
+
+	protoResultsResults protowire.Number = 1
+
+	protoBlockParent      protowire.Number = 1
+	protoBlockTimestamp   protowire.Number = 2
+	protoBlockHeight      protowire.Number = 3
+	protoBlockTxs         protowire.Number = 4
+	protoBlockStateRoot   protowire.Number = 5
+	protoBlockWarpResults protowire.Number = 6
+	protoBlockBlobsRoot   protowire.Number = 7
+	protoBlockBeneficiary protowire.Number = 8
+	protoBlockArrivals    protowire.Number = 9
+)
+
+// protoField is a field of a protobuf message. [v] is set for varint fields
+// and [b] for length-delimited fields.
+type protoField struct {
+	num protowire.Number
+	typ protowire.Type
+	v   uint64
+	b   []byte
+}
+
+// rangeProto calls [f] for each field of the message [b]. Unknown fields must
+// be ignored by [f] so that the schema can be extended.
+func rangeProto(b []byte, f func(protoField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidProto, protowire.ParseError(n))
+		}
+		b = b[n:]
+		field := protoField{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.v, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			field.b, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidProto, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if err := f(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f protoField) varint() (uint64, error) {
+	if f.typ != protowire.VarintType {
+		return 0, fmt.Errorf("%w: field %d is not a varint", ErrInvalidProto, f.num)
+	}
+	return f.v, nil
+}
+
+func (f protoField) bytes() ([]byte, error) {
+	if f.typ != protowire.BytesType {
+		return nil, fmt.Errorf("%w: field %d is not length-delimited", ErrInvalidProto, f.num)
+	}
+	return f.b, nil
+}
+
+func (f protoField) uint8() (uint8, error) {
+	v, err := f.varint()
+	if err != nil {
+		return 0, err
+	}
+	if v > uint64(consts.MaxUint8) {
+		return 0, fmt.Errorf("%w: field %d overflows uint8", ErrInvalidProto, f.num)
+	}
+	return uint8(v), nil
+}
+
+// fixed decodes a field of exactly [size] bytes into [dest]. As with proto3
+// default values, [dest] is left empty if the field is missing.
+func (f protoField) fixed(size int, dest []byte) error {
+	b, err := f.bytes()
+	if err != nil {
+		return err
+	}
+	if len(b) != size {
+		return fmt.Errorf("%w: field %d has length %d (expected %d)", ErrInvalidProto, f.num, len(b), size)
+	}
+	copy(dest, b)
+	return nil
+}
+
+func appendProtoVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendProtoBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	return appendProtoMessage(b, num, v)
+}
+
+// appendProtoMessage appends [v] even if it is empty, as messages have no
+// default value.
+func appendProtoMessage(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendProtoBool(b []byte, num protowire.Number, v bool) []byte {
+	return appendProtoVarint(b, num, protowire.EncodeBool(v))
+}
+
+// appendProtoTyped appends [o] as a TypedPayload, where [payload] is the
+// [BinaryEncoding] of its fields.
+func appendProtoTyped(b []byte, num protowire.Number, o Object, payload []byte) []byte {
+	typed := appendProtoVarint(nil, protoTypedTypeID, uint64(o.GetTypeID()))
+	typed = appendProtoBytes(typed, protoTypedPayload, payload)
+	if v, ok := o.(Versioned); ok {
+		typed = appendProtoVarint(typed, protoTypedVersion, uint64(v.GetVersion()))
+	}
+	return appendProtoMessage(b, num, typed)
+}
+
+func appendTxProto(b []byte, tx *Transaction) ([]byte, error) {
+	base := appendProtoVarint(nil, protoBaseTimestamp, uint64(tx.Base.Timestamp))
+	base = appendProtoBytes(base, protoBaseChainID, tx.Base.ChainID[:])
+	base = appendProtoVarint(base, protoBaseMaxFee, tx.Base.MaxFee)
+	if tx.Base.HeightExpiry() {
+		base = appendProtoVarint(base, protoBaseHeight, tx.Base.Height)
+	}
+	if tx.Base.SequentialNonce() {
+		base = appendProtoVarint(base, protoBaseNonce, tx.Base.Nonce)
+	}
+	if tx.Base.HasFeeAsset() {
+		base = appendProtoBytes(base, protoBaseFeeAsset, tx.Base.FeeAsset[:])
+		base = appendProtoVarint(base, protoBaseMaxFeeAsset, tx.Base.MaxFeeAsset)
+	}
+
+	// The payloads are copied into [b], so their buffers can be reused
+	action := codec.GetWriter(tx.Action.Size(), consts.NetworkSizeLimit)
+	defer codec.PutWriter(action)
+	tx.Action.Marshal(action)
+	if err := action.Err(); err != nil {
+		return nil, err
+	}
+	auth := codec.GetWriter(tx.Auth.Size(), consts.NetworkSizeLimit)
+	defer codec.PutWriter(auth)
+	tx.Auth.Marshal(auth)
+	if err := auth.Err(); err != nil {
+		return nil, err
+	}
+
+	b = appendProtoMessage(b, protoTxBase, base)
+	if tx.WarpMessage != nil {
+		warpBytes := tx.WarpMessage.Bytes()
+		if len(warpBytes) == 0 {
+			return nil, ErrWarpMessageNotInitialized
+		}
+		b = appendProtoBytes(b, protoTxWarpMessage, warpBytes)
+	}
+	b = appendProtoBytes(b, protoTxBlob, tx.Blob)
+	b = appendProtoTyped(b, protoTxAction, tx.Action, action.Bytes())
+	b = appendProtoTyped(b, protoTxAuth, tx.Auth, auth.Bytes())
+	for _, k := range tx.AccessList {
+		b = appendProtoBytes(b, protoTxAccessList, []byte(k))
+	}
+	return b, nil
+}
+
+// MarshalTxProto encodes [tx] with [ProtobufEncoding].
+func MarshalTxProto(tx *Transaction) ([]byte, error) {
+	return appendTxProto(nil, tx)
+}
+
+func parseBaseProto(raw []byte) (*Base, error) {
+	var base Base
+	err := rangeProto(raw, func(f protoField) error {
+		switch f.num {
+		case protoBaseTimestamp:
+			v, err := f.varint()
+			base.Timestamp = int64(v)
+			return err
+		case protoBaseChainID:
+			return f.fixed(consts.IDLen, base.ChainID[:])
+		case protoBaseMaxFee:
+			v, err := f.varint()
+			base.MaxFee = v
+			return err
+		case protoBaseHeight:
+			v, err := f.varint()
+			base.Height = v
+			return err
+		case protoBaseNonce:
+			v, err := f.varint()
+			base.Nonce = v
+			return err
+		case protoBaseFeeAsset:
+			return f.fixed(consts.IDLen, base.FeeAsset[:])
+		case protoBaseMaxFeeAsset:
+			v, err := f.varint()
+			base.MaxFeeAsset = v
+			return err
+		}
+		return nil
+	})
+	return &base, err
+}
+
+// packTypedProto converts an action or auth encoded with [ProtobufEncoding]
+// to [BinaryEncoding]. The version is only packed if the type is versioned in
+// [registry].
+func packTypedProto[T any](
+	p *codec.Packer,
+	raw []byte,
+	registry *codec.TypeParser[T, *warp.Message, bool],
+) error {
+	var (
+		typeID, version uint8
+		payload         []byte
+	)
+	err := rangeProto(raw, func(f protoField) error {
+		var err error
+		switch f.num {
+		case protoTypedTypeID:
+			typeID, err = f.uint8()
+		case protoTypedPayload:
+			payload, err = f.bytes()
+		case protoTypedVersion:
+			version, err = f.uint8()
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	p.PackByte(typeID)
+	if registry.IsVersioned(typeID) {
+		p.PackByte(version)
+	} else if version != 0 {
+		return fmt.Errorf("%w: %d is not versioned", ErrInvalidProto, typeID)
+	}
+	p.PackFixedBytes(payload)
+	return p.Err()
+}
+
+// nativeTxProto converts a transaction encoded with [ProtobufEncoding] to
+// [BinaryEncoding].
+func nativeTxProto(raw []byte, actionRegistry ActionRegistry, authRegistry AuthRegistry) ([]byte, error) {
+	var (
+		baseRaw, actionRaw, authRaw []byte
+		warpBytes, blob             []byte
+		accessList                  []string
+		hasAction, hasAuth          bool
+	)
+	err := rangeProto(raw, func(f protoField) error {
+		var err error
+		switch f.num {
+		case protoTxBase:
+			baseRaw, err = f.bytes()
+		case protoTxWarpMessage:
+			warpBytes, err = f.bytes()
+		case protoTxBlob:
+			blob, err = f.bytes()
+		case protoTxAction:
+			actionRaw, err = f.bytes()
+			hasAction = true
+		case protoTxAuth:
+			authRaw, err = f.bytes()
+			hasAuth = true
+		case protoTxAccessList:
+			var k []byte
+			k, err = f.bytes()
+			accessList = append(accessList, string(k))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasAction || !hasAuth {
+		return nil, fmt.Errorf("%w: missing action or auth", ErrInvalidProto)
+	}
+	base, err := parseBaseProto(baseRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Typed payloads are about as large in both encodings
+	exts := txExtensionsOf(base, blob, accessList)
+	size := extensionsLen(exts) + base.Size() +
+		codec.BytesLen(warpBytes) +
+		blobLen(blob) +
+		accessListLen(accessList) +
+		len(actionRaw) + len(authRaw)
+	p := codec.NewWriter(size, consts.NetworkSizeLimit)
+	packExtensions(p, exts)
+	base.Marshal(p)
+	p.PackBytes(warpBytes)
+	packBlob(p, blob)
+	packAccessList(p, accessList)
+	if err := packTypedProto[Action](p, actionRaw, actionRegistry); err != nil {
+		return nil, err
+	}
+	if err := packTypedProto[Auth](p, authRaw, authRegistry); err != nil {
+		return nil, err
+	}
+	return p.Bytes(), p.Err()
+}
+
+// UnmarshalTxProto decodes a transaction encoded with [ProtobufEncoding].
+func UnmarshalTxProto(
+	raw []byte,
+	actionRegistry ActionRegistry,
+	authRegistry AuthRegistry,
+) (*Transaction, error) {
+	native, err := nativeTxProto(raw, actionRegistry, authRegistry)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalTxEncoding(BinaryEncoding, native, actionRegistry, authRegistry)
+}
+
+func appendResultProto(b []byte, result *Result) []byte {
+	consumed := make([]byte, 0, FeeDimensions()*protowire.SizeVarint(0))
+	for _, units := range result.Consumed[:FeeDimensions()] {
+		consumed = protowire.AppendVarint(consumed, units)
+	}
+	b = appendProtoBool(b, protoResultSuccess, result.Success())
+	b = appendProtoBytes(b, protoResultOutput, result.Output)
+	b = appendProtoBytes(b, protoResultConsumed, consumed) // packed
+	b = appendProtoVarint(b, protoResultFee, result.Fee)
+	if result.WarpMessage != nil {
+		b = appendProtoBytes(b, protoResultWarpMessage, result.WarpMessage.Bytes())
+	}
+	b = appendProtoVarint(b, protoResultStatus, uint64(result.Status))
+	return b
+}
+
+// MarshalResultsProto encodes [results] with [ProtobufEncoding].
+func MarshalResultsProto(results []*Result) []byte {
+	var b []byte
+	for _, result := range results {
+		b = appendProtoMessage(b, protoResultsResults, appendResultProto(nil, result))
+	}
+	return b
+}
+
+func parseResultProto(raw []byte) ([]byte, error) {
+	var (
+		success         bool
+		status          uint64
+		hasStatus       bool
+		output          []byte
+		consumed        Dimensions
+		consumedCount   int
+		fee             uint64
+		warpMessage     []byte
+		errTooManyUnits = fmt.Errorf("%w: found more than %d", ErrWrongDimensionSize, FeeDimensions())
+	)
+	err := rangeProto(raw, func(f protoField) error {
+		var err error
+		switch f.num {
+		case protoResultSuccess:
+			var v uint64
+			v, err = f.varint()
+			success = protowire.DecodeBool(v)
+		case protoResultOutput:
+			output, err = f.bytes()
+		case protoResultConsumed:
+			// Repeated scalars may be packed or not
+			if f.typ == protowire.VarintType {
+				if consumedCount == FeeDimensions() {
+					return errTooManyUnits
+				}
+				consumed[consumedCount] = f.v
+				consumedCount++
+				return nil
+			}
+			packed, err := f.bytes()
+			if err != nil {
+				return err
+			}
+			for len(packed) > 0 {
+				v, n := protowire.ConsumeVarint(packed)
+				if n < 0 {
+					return fmt.Errorf("%w: %v", ErrInvalidProto, protowire.ParseError(n))
+				}
+				if consumedCount == FeeDimensions() {
+					return errTooManyUnits
+				}
+				consumed[consumedCount] = v
+				consumedCount++
+				packed = packed[n:]
+			}
+		case protoResultFee:
+			fee, err = f.varint()
+		case protoResultWarpMessage:
+			warpMessage, err = f.bytes()
+		case protoResultStatus:
+			status, err = f.varint()
+			hasStatus = true
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case status > math.MaxUint8:
+		return nil, fmt.Errorf("%w: status %d", ErrInvalidProto, status)
+	case !hasStatus && success:
+		// Encoded without a status (which is omitted when it is [StatusFailed])
+		status = uint64(StatusSuccess)
+	}
+
+	size := consts.ByteLen + codec.BytesLen(output) + DimensionsLen() + consts.Uint64Len + codec.BytesLen(warpMessage)
+	p := codec.NewWriter(size, consts.MaxInt)
+	p.PackByte(byte(status))
+	p.PackBytes(output)
+	p.PackFixedBytes(consumed.Bytes())
+	p.PackUint64(fee)
+	p.PackBytes(warpMessage)
+	return p.Bytes(), p.Err()
+}
+
+// UnmarshalResultsProto decodes results encoded with [ProtobufEncoding].
+func UnmarshalResultsProto(raw []byte) ([]*Result, error) {
+	var native [][]byte
+	err := rangeProto(raw, func(f protoField) error {
+		if f.num != protoResultsResults {
+			return nil
+		}
+		b, err := f.bytes()
+		if err != nil {
+			return err
+		}
+		result, err := parseResultProto(b)
+		if err != nil {
+			return err
+		}
+		native = append(native, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	size := consts.IntLen
+	for _, result := range native {
+		size += len(result)
+	}
+	p := codec.NewWriter(size, consts.MaxInt)
+	p.PackInt(len(native))
+	for _, result := range native {
+		p.PackFixedBytes(result)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return UnmarshalResults(p.Bytes())
+}
+
+// MarshalBlockProto encodes [b] with [ProtobufEncoding].
+func MarshalBlockProto(b *StatefulBlock) ([]byte, error) {
+	var raw []byte
+	raw = appendProtoBytes(raw, protoBlockParent, b.Prnt[:])
+	raw = appendProtoVarint(raw, protoBlockTimestamp, uint64(b.Tmstmp))
+	raw = appendProtoVarint(raw, protoBlockHeight, b.Hght)
+	// Each tx is copied into [raw], so the same buffer is used to encode all
+	// of them
+	var txRaw []byte
+	for _, tx := range b.Txs {
+		var err error
+		txRaw, err = appendTxProto(txRaw[:0], tx)
+		if err != nil {
+			return nil, err
+		}
+		raw = appendProtoMessage(raw, protoBlockTxs, txRaw)
+	}
+	raw = appendProtoBytes(raw, protoBlockStateRoot, b.StateRoot[:])
+	raw = appendProtoVarint(raw, protoBlockWarpResults, uint64(b.WarpResults))
+	raw = appendProtoBytes(raw, protoBlockBlobsRoot, b.BlobsRoot[:])
+	raw = appendProtoBytes(raw, protoBlockBeneficiary, b.Beneficiary[:])
+	for _, arrival := range b.Arrivals {
+		raw = appendProtoVarint(raw, protoBlockArrivals, uint64(arrival))
+	}
+	return raw, nil
+}
+
+// UnmarshalBlockProto decodes a block encoded with [ProtobufEncoding].
+func UnmarshalBlockProto(raw []byte, parser Parser) (*StatefulBlock, error) {
+	var (
+		b   StatefulBlock
+		txs [][]byte
+
+		actionRegistry, authRegistry = parser.Registry()
+	)
+	err := rangeProto(raw, func(f protoField) error {
+		var err error
+		switch f.num {
+		case protoBlockParent:
+			err = f.fixed(consts.IDLen, b.Prnt[:])
+		case protoBlockTimestamp:
+			var v uint64
+			v, err = f.varint()
+			b.Tmstmp = int64(v)
+		case protoBlockHeight:
+			b.Hght, err = f.varint()
+		case protoBlockTxs:
+			var txRaw []byte
+			if txRaw, err = f.bytes(); err != nil {
+				return err
+			}
+			var native []byte
+			if native, err = nativeTxProto(txRaw, actionRegistry, authRegistry); err != nil {
+				return err
+			}
+			txs = append(txs, native)
+		case protoBlockStateRoot:
+			err = f.fixed(consts.IDLen, b.StateRoot[:])
+		case protoBlockWarpResults:
+			var v uint64
+			v, err = f.varint()
+			b.WarpResults = set.Bits64(v)
+		case protoBlockBlobsRoot:
+			err = f.fixed(consts.IDLen, b.BlobsRoot[:])
+		case protoBlockBeneficiary:
+			err = f.fixed(codec.AddressLen, b.Beneficiary[:])
+		case protoBlockArrivals:
+			var v uint64
+			v, err = f.varint()
+			b.Arrivals = append(b.Arrivals, int64(v))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	size := blockHeaderSize + trailerSize(nil, b.Arrivals)
+	for _, tx := range txs {
+		size += len(tx)
+	}
+	p := codec.NewWriter(size, consts.NetworkSizeLimit)
+	p.PackID(b.Prnt)
+	p.PackInt64(b.Tmstmp)
+	p.PackUint64(b.Hght)
+	p.PackInt(len(txs))
+	for _, tx := range txs {
+		p.PackFixedBytes(tx)
+	}
+	p.PackID(b.StateRoot)
+	p.PackUint64(uint64(b.WarpResults))
+	p.PackID(b.BlobsRoot)
+	p.PackFixedBytes(b.Beneficiary[:])
+	if len(b.Arrivals) > 0 {
+		p.PackBytes(nil) // no witness
+		p.PackInt(len(b.Arrivals))
+		for _, arrival := range b.Arrivals {
+			p.PackInt64(arrival)
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return UnmarshalBlock(p.Bytes(), parser)
+}
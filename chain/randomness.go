@@ -0,0 +1,62 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+type blockSeedKey struct{}
+
+// NewBlockSeed returns the pseudo-random seed of the block at [height] that
+// extends [parent].
+//
+// Because [parent] commits to all of its ancestors, seeds form a hash chain
+// that no single builder controls. However, the builder of [parent] can
+// grind its contents to pick between seeds, and the seed is public as soon
+// as [parent] is built. It must not be used where these parties could
+// profit from knowing or biasing it (use a commit-reveal scheme instead).
+func NewBlockSeed(parent ids.ID, height uint64) ids.ID {
+	b := make([]byte, 0, consts.IDLen+consts.Uint64Len)
+	b = append(b, parent[:]...)
+	b = binary.BigEndian.AppendUint64(b, height)
+	return utils.ToID(b)
+}
+
+// WithBlockSeed returns a copy of [ctx] that carries [seed] (see
+// [GetBlockSeed]).
+func WithBlockSeed(ctx context.Context, seed ids.ID) context.Context {
+	return context.WithValue(ctx, blockSeedKey{}, seed)
+}
+
+// GetBlockSeed returns the seed of the block being executed (see
+// [NewBlockSeed]). It is available in [Action.Execute] and returns false
+// outside of block execution.
+func GetBlockSeed(ctx context.Context) (ids.ID, bool) {
+	seed, ok := ctx.Value(blockSeedKey{}).(ids.ID)
+	return seed, ok
+}
+
+// GetTxSeed returns a pseudo-random seed unique to [txID] in the block being
+// executed, so that actions in the same block don't share randomness.
+//
+// Issuers can grind [txID] to pick between seeds if they know the block
+// their transaction will be included in, so the caveats of [NewBlockSeed]
+// apply.
+func GetTxSeed(ctx context.Context, txID ids.ID) (ids.ID, bool) {
+	seed, ok := GetBlockSeed(ctx)
+	if !ok {
+		return ids.Empty, false
+	}
+	b := make([]byte, 0, consts.IDLen*2)
+	b = append(b, seed[:]...)
+	b = append(b, txID[:]...)
+	return utils.ToID(b), true
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSeed(t *testing.T) {
+	require := require.New(t)
+
+	parent := ids.GenerateTestID()
+	seed := NewBlockSeed(parent, 10)
+	require.NotEqual(ids.Empty, seed)
+
+	// Seeds only depend on the parent and height of the block
+	require.Equal(seed, NewBlockSeed(parent, 10))
+	blk := &StatelessBlock{StatefulBlock: &StatefulBlock{Prnt: parent, Hght: 10, Tmstmp: 1}}
+	require.Equal(seed, blk.Seed())
+	blk.Tmstmp = 2
+	require.Equal(seed, blk.Seed())
+	require.NotEqual(seed, NewBlockSeed(parent, 11))
+	require.NotEqual(seed, NewBlockSeed(ids.GenerateTestID(), 10))
+}
+
+func TestGetBlockSeed(t *testing.T) {
+	require := require.New(t)
+
+	// Seeds are unavailable outside of block execution
+	ctx := context.Background()
+	_, ok := GetBlockSeed(ctx)
+	require.False(ok)
+	_, ok = GetTxSeed(ctx, ids.GenerateTestID())
+	require.False(ok)
+
+	seed := NewBlockSeed(ids.GenerateTestID(), 1)
+	ctx = WithBlockSeed(ctx, seed)
+	blockSeed, ok := GetBlockSeed(ctx)
+	require.True(ok)
+	require.Equal(seed, blockSeed)
+
+	// Each transaction gets its own seed
+	txID := ids.GenerateTestID()
+	txSeed, ok := GetTxSeed(ctx, txID)
+	require.True(ok)
+	require.NotEqual(seed, txSeed)
+	again, ok := GetTxSeed(ctx, txID)
+	require.True(ok)
+	require.Equal(txSeed, again)
+	other, ok := GetTxSeed(ctx, ids.GenerateTestID())
+	require.True(ok)
+	require.NotEqual(txSeed, other)
+
+	// The seed of a transaction changes with the block it is included in
+	next, ok := GetTxSeed(WithBlockSeed(context.Background(), NewBlockSeed(ids.GenerateTestID(), 1)), txID)
+	require.True(ok)
+	require.NotEqual(txSeed, next)
+}
@@ -10,8 +10,8 @@ import (
 )
 
 type Result struct {
-	Success bool
-	Output  []byte
+	Status Status
+	Output []byte
 
 	Consumed Dimensions
 	Fee      uint64
@@ -19,8 +19,13 @@ type Result struct {
 	WarpMessage *warp.UnsignedMessage
 }
 
+// Success returns true if the [Action] of the transaction succeeded.
+func (r *Result) Success() bool {
+	return r.Status == StatusSuccess
+}
+
 func (r *Result) Size() int {
-	size := consts.BoolLen + codec.BytesLen(r.Output) + DimensionsLen + consts.Uint64Len
+	size := consts.ByteLen + codec.BytesLen(r.Output) + DimensionsLen() + consts.Uint64Len
 	if r.WarpMessage != nil {
 		size += codec.BytesLen(r.WarpMessage.Bytes())
 	} else {
@@ -30,7 +35,7 @@ func (r *Result) Size() int {
 }
 
 func (r *Result) Marshal(p *codec.Packer) error {
-	p.PackBool(r.Success)
+	p.PackByte(byte(r.Status))
 	p.PackBytes(r.Output)
 	p.PackFixedBytes(r.Consumed.Bytes())
 	p.PackUint64(r.Fee)
@@ -43,28 +48,41 @@ func (r *Result) Marshal(p *codec.Packer) error {
 }
 
 func MarshalResults(src []*Result) ([]byte, error) {
-	size := consts.IntLen + codec.CummSize(src)
-	p := codec.NewWriter(size, consts.MaxInt) // could be much larger than [NetworkSizeLimit]
+	p := codec.NewWriter(ResultsSize(src), consts.MaxInt) // could be much larger than [NetworkSizeLimit]
+	if err := PackResults(p, src); err != nil {
+		return nil, err
+	}
+	return p.Bytes(), nil
+}
+
+// ResultsSize is the size of [src] when marshalled with [MarshalResults].
+func ResultsSize(src []*Result) int {
+	return consts.IntLen + codec.CummSize(src)
+}
+
+// PackResults writes [src] to [p] like [MarshalResults], so that [p] can be
+// reused when the bytes are copied elsewhere.
+func PackResults(p *codec.Packer, src []*Result) error {
 	p.PackInt(len(src))
 	for _, result := range src {
 		if err := result.Marshal(p); err != nil {
-			return nil, err
+			return err
 		}
 	}
-	return p.Bytes(), p.Err()
+	return p.Err()
 }
 
 func UnmarshalResult(p *codec.Packer) (*Result, error) {
 	result := &Result{
-		Success: p.UnpackBool(),
+		Status: Status(p.UnpackByte()),
 	}
 	p.UnpackBytes(consts.MaxInt, false, &result.Output)
 	if len(result.Output) == 0 {
 		// Enforce object standardization
 		result.Output = nil
 	}
-	consumedRaw := make([]byte, DimensionsLen)
-	p.UnpackFixedBytes(DimensionsLen, &consumedRaw)
+	consumedRaw := make([]byte, DimensionsLen())
+	p.UnpackFixedBytes(DimensionsLen(), &consumedRaw)
 	consumed, err := UnpackDimensions(consumedRaw)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,231 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	overrideMinBlockGap byte = 1 << iota
+	overrideValidityWindow
+	overrideMaxBlockUnits
+)
+
+// RulesOverrides replaces some [Rules] with values stored in state, so that
+// actions (like a governance proposal) can change them without an upgrade.
+// Nil fields are not overridden.
+type RulesOverrides struct {
+	MinBlockGap    *int64      `json:"minBlockGap,omitempty"`    // ms
+	ValidityWindow *int64      `json:"validityWindow,omitempty"` // ms
+	MaxBlockUnits  *Dimensions `json:"maxBlockUnits,omitempty"`
+}
+
+func (o *RulesOverrides) empty() bool {
+	return o.MinBlockGap == nil && o.ValidityWindow == nil && o.MaxBlockUnits == nil
+}
+
+func (o *RulesOverrides) Marshal() []byte {
+	var (
+		flags byte
+		size  = consts.ByteLen
+	)
+	if o.MinBlockGap != nil {
+		flags |= overrideMinBlockGap
+		size += consts.Int64Len
+	}
+	if o.ValidityWindow != nil {
+		flags |= overrideValidityWindow
+		size += consts.Int64Len
+	}
+	if o.MaxBlockUnits != nil {
+		flags |= overrideMaxBlockUnits
+		size += DimensionsLen()
+	}
+	p := codec.NewWriter(size, size)
+	p.PackByte(flags)
+	if o.MinBlockGap != nil {
+		p.PackInt64(*o.MinBlockGap)
+	}
+	if o.ValidityWindow != nil {
+		p.PackInt64(*o.ValidityWindow)
+	}
+	if o.MaxBlockUnits != nil {
+		p.PackFixedBytes(o.MaxBlockUnits.Bytes())
+	}
+	return p.Bytes()
+}
+
+// ParseRulesOverrides decodes the value stored at a [RulesOverridesKey].
+func ParseRulesOverrides(v []byte) (*RulesOverrides, error) {
+	var (
+		p     = codec.NewReader(v, len(v))
+		flags = p.UnpackByte()
+		o     = &RulesOverrides{}
+	)
+	if flags&overrideMinBlockGap != 0 {
+		gap := p.UnpackInt64(false)
+		o.MinBlockGap = &gap
+	}
+	if flags&overrideValidityWindow != 0 {
+		window := p.UnpackInt64(true)
+		o.ValidityWindow = &window
+	}
+	if flags&overrideMaxBlockUnits != 0 {
+		raw := make([]byte, DimensionsLen())
+		p.UnpackFixedBytes(DimensionsLen(), &raw)
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		units, err := UnpackDimensions(raw)
+		if err != nil {
+			return nil, err
+		}
+		o.MaxBlockUnits = &units
+	}
+	if !p.Empty() {
+		return nil, fmt.Errorf("%w: unexpected rules overrides bytes", ErrInvalidObject)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// RulesOverridesKey returns the state key (suffixed with
+// [RulesOverridesKeyChunks]) that stores the [RulesOverrides] of the chain,
+// if [sm] implements [RulesOverrider].
+func RulesOverridesKey(sm StateManager) ([]byte, error) {
+	ro, ok := sm.(RulesOverrider)
+	if !ok {
+		return nil, ErrRulesOverridesUnsupported
+	}
+	return keys.EncodeChunks(ro.RulesOverridesKey(), RulesOverridesKeyChunks), nil
+}
+
+// GetRulesOverrides returns the [RulesOverrides] stored in [im] or nil if
+// there are none (or [sm] does not implement [RulesOverrider]).
+func GetRulesOverrides(ctx context.Context, sm StateManager, im state.Immutable) (*RulesOverrides, error) {
+	k, err := RulesOverridesKey(sm)
+	if errors.Is(err, ErrRulesOverridesUnsupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v, err := im.GetValue(ctx, k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseRulesOverrides(v)
+}
+
+// SetRulesOverrides replaces the [RulesOverrides] stored at [k] (the
+// [RulesOverridesKey]) with [o]. They apply starting at the block after the
+// one that sets them (so all transactions in a block are processed with the
+// same [Rules]).
+//
+// Passing nil (or empty overrides) restores the [Rules] of the [Parser].
+//
+// Overridden max block units must be at least [minBlockUnits] (and never 0)
+// in every dimension. Callers should pass the max units of the transaction
+// that updates the overrides, otherwise the chain could end up with overrides
+// that no transaction can ever revert.
+func SetRulesOverrides(
+	ctx context.Context,
+	mu state.Mutable,
+	k []byte,
+	o *RulesOverrides,
+	minBlockUnits Dimensions,
+) error {
+	if o == nil || o.empty() {
+		return mu.Remove(ctx, k)
+	}
+	if o.MinBlockGap != nil && *o.MinBlockGap < 0 {
+		return fmt.Errorf("%w: min block gap %d", ErrInvalidRulesOverrides, *o.MinBlockGap)
+	}
+	if o.ValidityWindow != nil && *o.ValidityWindow <= 0 {
+		return fmt.Errorf("%w: validity window %d", ErrInvalidRulesOverrides, *o.ValidityWindow)
+	}
+	if o.MaxBlockUnits != nil {
+		for i := 0; i < FeeDimensions(); i++ {
+			if o.MaxBlockUnits[i] == 0 || o.MaxBlockUnits[i] < minBlockUnits[i] {
+				return fmt.Errorf(
+					"%w: max %s units %d (min=%d)",
+					ErrInvalidRulesOverrides,
+					Dimension(i),
+					o.MaxBlockUnits[i],
+					minBlockUnits[i],
+				)
+			}
+		}
+	}
+	return mu.Insert(ctx, k, o.Marshal())
+}
+
+type overriddenRules struct {
+	Rules
+	o *RulesOverrides
+}
+
+func (r *overriddenRules) GetMinBlockGap() int64 {
+	if r.o.MinBlockGap != nil {
+		return *r.o.MinBlockGap
+	}
+	return r.Rules.GetMinBlockGap()
+}
+
+func (r *overriddenRules) GetValidityWindow() int64 {
+	if r.o.ValidityWindow != nil {
+		return *r.o.ValidityWindow
+	}
+	return r.Rules.GetValidityWindow()
+}
+
+func (r *overriddenRules) GetMaxBlockUnits() Dimensions {
+	if r.o.MaxBlockUnits != nil {
+		return *r.o.MaxBlockUnits
+	}
+	return r.Rules.GetMaxBlockUnits()
+}
+
+// UnwrapRules returns the [Rules] of the [Parser] that [r] overrides (or [r]
+// if it has no overrides), so that they can be type asserted to access
+// getters that aren't part of [Rules].
+func UnwrapRules(r Rules) Rules {
+	if or, ok := r.(*overriddenRules); ok {
+		return or.Rules
+	}
+	return r
+}
+
+// WithRulesOverrides returns [r] with [o] applied (or [r] if [o] is nil).
+func WithRulesOverrides(r Rules, o *RulesOverrides) Rules {
+	if o == nil || o.empty() {
+		return r
+	}
+	return &overriddenRules{r, o}
+}
+
+// StateRules returns the [Rules] of a block with [timestamp] built on top of
+// [parent] (the state after its parent block was executed).
+func StateRules(ctx context.Context, p Parser, sm StateManager, parent state.Immutable, timestamp int64) (Rules, error) {
+	o, err := GetRulesOverrides(ctx, sm, parent)
+	if err != nil {
+		return nil, err
+	}
+	return WithRulesOverrides(p.Rules(timestamp), o), nil
+}
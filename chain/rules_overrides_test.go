@@ -0,0 +1,152 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+type testRulesOverrider struct {
+	StateManager
+}
+
+func (testRulesOverrider) RulesOverridesKey() []byte {
+	return []byte{0x5}
+}
+
+type testParser struct {
+	Parser
+
+	rules Rules
+}
+
+func (p *testParser) Rules(int64) Rules {
+	return p.rules
+}
+
+func TestRulesOverridesMarshal(t *testing.T) {
+	gap, window := int64(100), int64(5_000)
+	units := Dimensions{1, 2, 3, 4, 5}
+	for _, test := range []struct {
+		name      string
+		overrides *RulesOverrides
+	}{
+		{
+			name:      "min block gap",
+			overrides: &RulesOverrides{MinBlockGap: &gap},
+		},
+		{
+			name:      "validity window",
+			overrides: &RulesOverrides{ValidityWindow: &window},
+		},
+		{
+			name:      "all",
+			overrides: &RulesOverrides{MinBlockGap: &gap, ValidityWindow: &window, MaxBlockUnits: &units},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			raw := test.overrides.Marshal()
+			chunks, ok := keys.NumChunks(raw)
+			require.True(ok)
+			require.LessOrEqual(chunks, uint16(RulesOverridesKeyChunks))
+			parsed, err := ParseRulesOverrides(raw)
+			require.NoError(err)
+			require.Equal(test.overrides, parsed)
+
+			_, err = ParseRulesOverrides(append(raw, 0))
+			require.ErrorIs(err, ErrInvalidObject)
+		})
+	}
+}
+
+func TestSetRulesOverrides(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sm := testRulesOverrider{}
+	k, err := RulesOverridesKey(sm)
+	require.NoError(err)
+	mem := memoryState{}
+	overrides := func() *RulesOverrides {
+		o, err := GetRulesOverrides(ctx, sm, mem)
+		require.NoError(err)
+		return o
+	}
+	require.Nil(overrides())
+
+	window := int64(5_000)
+	o := &RulesOverrides{ValidityWindow: &window}
+	require.NoError(SetRulesOverrides(ctx, mem, k, o, Dimensions{}))
+	require.Equal(o, overrides())
+
+	// Invalid overrides are rejected
+	negative, zero := int64(-1), int64(0)
+	for _, invalid := range []*RulesOverrides{
+		{MinBlockGap: &negative},
+		{ValidityWindow: &zero},
+		{MaxBlockUnits: &Dimensions{1, 1, 0, 1, 1}},
+	} {
+		require.ErrorIs(SetRulesOverrides(ctx, mem, k, invalid, Dimensions{}), ErrInvalidRulesOverrides)
+	}
+	require.ErrorIs(
+		SetRulesOverrides(ctx, mem, k, &RulesOverrides{MaxBlockUnits: &Dimensions{10, 10, 10, 10, 10}}, Dimensions{10, 11, 10, 10, 10}),
+		ErrInvalidRulesOverrides,
+	)
+	require.Equal(o, overrides())
+
+	// Empty overrides restore the rules of the parser
+	require.NoError(SetRulesOverrides(ctx, mem, k, &RulesOverrides{}, Dimensions{}))
+	require.Nil(overrides())
+
+	// Overrides are ignored if the [StateManager] doesn't support them
+	_, err = RulesOverridesKey(testWarpManager{})
+	require.ErrorIs(err, ErrRulesOverridesUnsupported)
+	o, err = GetRulesOverrides(ctx, testWarpManager{}, mem)
+	require.NoError(err)
+	require.Nil(o)
+}
+
+func TestStateRules(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetMinBlockGap().Return(int64(250)).AnyTimes()
+	r.EXPECT().GetValidityWindow().Return(int64(60_000)).AnyTimes()
+	r.EXPECT().GetMaxBlockUnits().Return(Dimensions{100, 100, 100, 100, 100}).AnyTimes()
+	p := &testParser{rules: r}
+	sm := testRulesOverrider{}
+	k, err := RulesOverridesKey(sm)
+	require.NoError(err)
+	mem := memoryState{}
+
+	// Without overrides, the rules of the parser are used
+	sr, err := StateRules(ctx, p, sm, mem, 0)
+	require.NoError(err)
+	require.Equal(Rules(r), sr)
+
+	// Only overridden rules are replaced
+	window := int64(5_000)
+	var units Dimensions
+	for i := 0; i < FeeDimensions(); i++ {
+		units[i] = 10
+	}
+	require.NoError(SetRulesOverrides(ctx, mem, k, &RulesOverrides{ValidityWindow: &window, MaxBlockUnits: &units}, Dimensions{}))
+	sr, err = StateRules(ctx, p, sm, mem, 0)
+	require.NoError(err)
+	require.Equal(int64(250), sr.GetMinBlockGap())
+	require.Equal(window, sr.GetValidityWindow())
+	require.Equal(units, sr.GetMaxBlockUnits())
+	require.Equal(Rules(r), UnwrapRules(sr))
+	require.Equal(Rules(r), UnwrapRules(r))
+}
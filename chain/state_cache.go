@@ -0,0 +1,124 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// StateCache is an LRU cache of the values (and absences) of keys in the
+// accepted state. It saves a merkledb traversal each time a hot key (like the
+// fee key or a popular balance) is read by a block built on the last
+// accepted block.
+//
+// Cached keys written by a block are updated once it is committed, so the
+// cache never serves a value that differs from the accepted state (and keys
+// written in every block, like the fee key, stay cached).
+type StateCache struct {
+	m Metrics
+
+	l       sync.Mutex
+	version uint64 // incremented on each commit
+	values  *cache.LRU[string, maybe.Maybe[[]byte]]
+}
+
+func NewStateCache(size int, m Metrics) *StateCache {
+	return &StateCache{
+		m:      m,
+		values: &cache.LRU[string, maybe.Maybe[[]byte]]{Size: size},
+	}
+}
+
+// View returns a view of [db] (which must be the accepted state) that serves
+// reads from the cache.
+func (c *StateCache) View(db merkledb.MerkleDB) state.View {
+	return &cachedState{db, c}
+}
+
+// Update replaces the cached values of [changes] after they were written to
+// the accepted state. Keys that are not cached are not added.
+func (c *StateCache) Update(changes map[string]maybe.Maybe[[]byte]) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.version++
+	for k, v := range changes {
+		if _, ok := c.values.Get(k); !ok {
+			continue
+		}
+		if v.HasValue() {
+			v = maybe.Some(copyValue(v.Value()))
+		}
+		c.values.Put(k, v)
+	}
+}
+
+func (c *StateCache) Len() int {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	return c.values.Len()
+}
+
+func (c *StateCache) getValue(ctx context.Context, db merkledb.MerkleDB, key []byte) ([]byte, error) {
+	k := string(key)
+	c.l.Lock()
+	v, ok := c.values.Get(k)
+	version := c.version
+	c.l.Unlock()
+	if ok {
+		c.m.RecordStateCacheHit()
+		if v.IsNothing() {
+			return nil, database.ErrNotFound
+		}
+		// Callers may modify the value they read
+		return copyValue(v.Value()), nil
+	}
+	c.m.RecordStateCacheMiss()
+
+	value, err := db.GetValue(ctx, key)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		v = maybe.Nothing[[]byte]()
+	case err != nil:
+		return nil, err
+	default:
+		v = maybe.Some(copyValue(value))
+	}
+
+	// If the accepted state was modified while reading, [value] may be from
+	// either version of it
+	c.l.Lock()
+	if c.version == version {
+		c.values.Put(k, v)
+	}
+	c.l.Unlock()
+	return value, err
+}
+
+func copyValue(v []byte) []byte {
+	c := make([]byte, len(v))
+	copy(c, v)
+	return c
+}
+
+// cachedState is a [merkledb.MerkleDB] whose reads go through a [StateCache].
+type cachedState struct {
+	merkledb.MerkleDB
+
+	c *StateCache
+}
+
+func (s *cachedState) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	return s.c.getValue(ctx, s.MerkleDB, key)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/tstate"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// Status describes the outcome of executing a transaction, so that clients
+// can react to failures without parsing their output.
+//
+// Statuses are encoded as a single byte. [StatusFailed] and [StatusSuccess]
+// share the encoding of false and true, so results stored before statuses
+// were introduced decode as one of them.
+type Status uint8
+
+const (
+	// StatusFailed is any failure not covered by a more specific status.
+	StatusFailed  Status = 0
+	StatusSuccess Status = 1
+
+	// StatusInsufficientBalance is returned when the actor (or payer) does
+	// not have enough of an asset.
+	StatusInsufficientBalance Status = 2
+	// StatusKeyNotDeclared is returned when the action accesses a key it
+	// did not include in its state keys.
+	StatusKeyNotDeclared Status = 3
+	// StatusAssetMissing is returned when the action references an asset
+	// that does not exist.
+	StatusAssetMissing Status = 4
+	// StatusOverflow is returned when an amount overflows (or underflows).
+	StatusOverflow Status = 5
+
+	// StatusCustom is the first status reserved for controllers. Controllers
+	// can use any status from [StatusCustom] to 255 for their own failures
+	// (and must document what they mean to their clients).
+	StatusCustom Status = 128
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusFailed:
+		return "failed"
+	case StatusSuccess:
+		return "success"
+	case StatusInsufficientBalance:
+		return "insufficient balance"
+	case StatusKeyNotDeclared:
+		return "key not declared"
+	case StatusAssetMissing:
+		return "asset missing"
+	case StatusOverflow:
+		return "overflow"
+	}
+	if s >= StatusCustom {
+		return fmt.Sprintf("custom(%d)", s-StatusCustom)
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(s))
+}
+
+var _ error = (*StatusError)(nil)
+
+// StatusError is an error with the [Status] of the failure it caused.
+type StatusError struct {
+	status Status
+	err    error
+}
+
+// NewStatusError returns [err] tagged with [status]. Its message is the
+// message of [err], so tagging an error never changes the output of a
+// failure.
+func NewStatusError(status Status, err error) error {
+	return &StatusError{status: status, err: err}
+}
+
+func (e *StatusError) Status() Status {
+	return e.status
+}
+
+func (e *StatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.err
+}
+
+// StatusOf returns the [Status] of a failure caused by [err]. Errors tagged
+// with [NewStatusError] have their own status and errors from accessing
+// undeclared keys or from safe math are recognized. All other errors are
+// [StatusFailed].
+func StatusOf(err error) Status {
+	var serr *StatusError
+	switch {
+	case errors.As(err, &serr):
+		return serr.status
+	case errors.Is(err, tstate.ErrKeyNotSpecified):
+		return StatusKeyNotDeclared
+	case errors.Is(err, smath.ErrOverflow), errors.Is(err, smath.ErrUnderflow):
+		return StatusOverflow
+	default:
+		return StatusFailed
+	}
+}
+
+// Fail returns an error that [Action.Execute] can return to fail with
+// [StatusOf] of [err] (instead of returning false and [err] as its output).
+// Unlike any other error returned by [Action.Execute], it is handled like
+// any other failure: the output is the message of [err] and only the units
+// consumed are charged.
+func Fail(err error) error {
+	var serr *StatusError
+	if errors.As(err, &serr) && serr == err {
+		return err
+	}
+	return NewStatusError(StatusOf(err), err)
+}
+
+// AsFailure returns the status and output of the failure [err] (returned by
+// [Action.Execute]) describes. False is returned if [err] was not created
+// with [Fail] or [NewStatusError], in which case the action reverts.
+func AsFailure(err error) (Status, []byte, bool) {
+	serr, ok := err.(*StatusError) //nolint:errorlint
+	if !ok {
+		return StatusFailed, nil, false
+	}
+	if serr.status == StatusSuccess {
+		// A failure can't succeed
+		return StatusFailed, utils.ErrBytes(serr), true
+	}
+	return serr.status, utils.ErrBytes(serr), true
+}
@@ -8,10 +8,10 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"golang.org/x/exp/slices"
 
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
@@ -33,6 +33,17 @@ type Transaction struct {
 	Base        *Base         `json:"base"`
 	WarpMessage *warp.Message `json:"warpMessage"`
 
+	// Blob is stored by nodes for a limited time after the transaction is
+	// accepted but is never accessible to [Action] execution.
+	Blob []byte `json:"blob,omitempty"`
+
+	// AccessList, if not empty, declares the state keys the [Action] may
+	// access ahead of execution. It must include every key returned by
+	// [Action.StateKeys] (otherwise the transaction is rejected with
+	// [ErrAccessListMismatch]) and replaces them when scheduling the
+	// transaction.
+	AccessList []string `json:"accessList,omitempty"`
+
 	// TODO: turn [Action] into an array (#335)
 	Action Action `json:"action"`
 	Auth   Auth   `json:"auth"`
@@ -48,6 +59,12 @@ type Transaction struct {
 	// warpID from the same sourceChainID to be accepted.
 	warpID    ids.ID
 	stateKeys set.Set[string]
+	// stateKeysInputs are the inputs from [Rules] that [stateKeys] was
+	// computed with
+	stateKeysInputs *stateKeysInputs
+	// arrival is when this node first received the transaction (in
+	// milliseconds), if it was submitted to it
+	arrival int64
 }
 
 type WarpResult struct {
@@ -67,18 +84,23 @@ func (t *Transaction) Digest() ([]byte, error) {
 	if len(t.digest) > 0 {
 		return t.digest, nil
 	}
-	actionID := t.Action.GetTypeID()
 	var warpBytes []byte
 	if t.WarpMessage != nil {
 		warpBytes = t.WarpMessage.Bytes()
 	}
-	size := t.Base.Size() +
+	exts := t.extensions()
+	size := extensionsLen(exts) + t.Base.Size() +
 		codec.BytesLen(warpBytes) +
-		consts.ByteLen + t.Action.Size()
+		blobLen(t.Blob) +
+		accessListLen(t.AccessList) +
+		typeLen(t.Action) + t.Action.Size()
 	p := codec.NewWriter(size, consts.NetworkSizeLimit)
+	packExtensions(p, exts)
 	t.Base.Marshal(p)
 	p.PackBytes(warpBytes)
-	p.PackByte(actionID)
+	packBlob(p, t.Blob)
+	packAccessList(p, t.AccessList)
+	packType(p, t.Action)
 	t.Action.Marshal(p)
 	return p.Bytes(), p.Err()
 }
@@ -100,7 +122,7 @@ func (t *Transaction) Sign(
 
 	// Ensure transaction is fully initialized and correct by reloading it from
 	// bytes
-	size := len(msg) + consts.ByteLen + t.Auth.Size()
+	size := len(msg) + typeLen(t.Auth) + t.Auth.Size()
 	p := codec.NewWriter(size, consts.NetworkSizeLimit)
 	if err := t.Marshal(p); err != nil {
 		return nil, err
@@ -118,20 +140,82 @@ func (t *Transaction) Size() int { return t.size }
 
 func (t *Transaction) ID() ids.ID { return t.id }
 
-func (t *Transaction) Expiry() int64 { return t.Base.Timestamp }
+// Arrival returns when [t] was first received by this node (see
+// [Transaction.SetArrival]) or 0 if it is unknown. It is local to this node
+// and never marshalled with [t].
+func (t *Transaction) Arrival() int64 { return t.arrival }
+
+// SetArrival records [arrival] as the time [t] was first received by this
+// node. It is used to order transactions when building blocks with
+// [VM.GetFCFSOrdering].
+func (t *Transaction) SetArrival(arrival int64) { t.arrival = arrival }
+
+// Expiry returns the timestamp at which [t] expires or, if it expires at a
+// height instead (or uses a sequential nonce), [consts.MaxInt64] (it never
+// expires by time).
+func (t *Transaction) Expiry() int64 {
+	if t.Base.Timestamp == 0 {
+		return consts.MaxInt64
+	}
+	return t.Base.Timestamp
+}
+
+// ExpiryTimestamp and ExpiryHeight key transactions in an [emap.EMap] that
+// tracks them by timestamp or by height (transactions that expire the other
+// way are keyed by 0, which is never tracked).
+func ExpiryTimestamp(t *Transaction) int64 { return t.Base.Timestamp }
+
+func ExpiryHeight(t *Transaction) int64 { return int64(t.Base.Height) }
 
 func (t *Transaction) MaxFee() uint64 { return t.Base.MaxFee }
 
-func (t *Transaction) StateKeys(sm StateManager) (set.Set[string], error) {
-	if t.stateKeys != nil {
+// stateKeysInputs are the values [Transaction.StateKeys] depends on that
+// come from [Rules], which may change between calls (i.e. after an upgrade or
+// if the rules are overridden).
+type stateKeysInputs struct {
+	payer        codec.Address
+	payerKeys    []string
+	cancellation bool
+}
+
+func (i *stateKeysInputs) equal(o *stateKeysInputs) bool {
+	return i.payer == o.payer && i.cancellation == o.cancellation && slices.Equal(i.payerKeys, o.payerKeys)
+}
+
+func (t *Transaction) StateKeys(sm StateManager, r Rules) (set.Set[string], error) {
+	payer, charged := t.feePayer(r)
+	inputs := &stateKeysInputs{payer: payer, cancellation: r.GetTxCancellation()}
+	if charged && (payer != t.Auth.Sponsor() || t.Base.HasFeeAsset()) {
+		// The paymaster of a fee asset is set by [r]
+		payerKeys, err := t.payerStateKeys(sm, r, payer)
+		if err != nil {
+			return nil, err
+		}
+		inputs.payerKeys = payerKeys
+	}
+	if t.stateKeys != nil && t.stateKeysInputs.equal(inputs) {
 		return t.stateKeys, nil
 	}
 
 	// Verify the formatting of state keys passed by the controller
 	actionKeys := t.Action.StateKeys(t.Auth.Actor(), t.ID())
+	if len(t.AccessList) > 0 {
+		// Keys in [AccessList] are verified when parsed
+		if err := checkAccessList(set.Of(t.AccessList...), actionKeys); err != nil {
+			return nil, err
+		}
+		actionKeys = t.AccessList
+	}
 	sponsorKeys := sm.SponsorStateKeys(t.Auth.Sponsor())
-	stateKeys := set.NewSet[string](len(actionKeys) + len(sponsorKeys))
-	for _, arr := range [][]string{actionKeys, sponsorKeys} {
+	if len(inputs.payerKeys) > 0 {
+		sponsorKeys = append(append(make([]string, 0, len(sponsorKeys)+len(inputs.payerKeys)), sponsorKeys...), inputs.payerKeys...)
+	}
+	var authKeys []string
+	if stateful, ok := t.Auth.(StatefulAuth); ok {
+		authKeys = stateful.StateKeys()
+	}
+	stateKeys := set.NewSet[string](len(actionKeys) + len(sponsorKeys) + len(authKeys))
+	for _, arr := range [][]string{actionKeys, sponsorKeys, authKeys} {
 		for _, k := range arr {
 			if !keys.Valid(k) {
 				return nil, ErrInvalidKeyValue
@@ -142,9 +226,8 @@ func (t *Transaction) StateKeys(sm StateManager) (set.Set[string], error) {
 
 	// Add keys used to manage warp operations
 	if t.WarpMessage != nil {
-		p := sm.IncomingWarpKeyPrefix(t.WarpMessage.SourceChainID, t.warpID)
-		k := keys.EncodeChunks(p, MaxIncomingWarpChunks)
-		stateKeys.Add(string(k))
+		stateKeys.Add(string(IncomingWarpKey(sm, t.WarpMessage.SourceChainID, t.warpID)))
+		stateKeys.Add(string(LegacyIncomingWarpKey(sm, t.WarpMessage.SourceChainID, t.warpID)))
 	}
 	if t.Action.OutputsWarpMessage() {
 		p := sm.OutgoingWarpKeyPrefix(t.id)
@@ -152,20 +235,61 @@ func (t *Transaction) StateKeys(sm StateManager) (set.Set[string], error) {
 		stateKeys.Add(string(k))
 	}
 
+	// Add the nonce of the sponsor
+	if t.Base.SequentialNonce() {
+		k, err := NonceKey(sm, t.Auth.Sponsor())
+		if err != nil {
+			return nil, err
+		}
+		stateKeys.Add(string(k))
+	}
+
+	// Add the transactions cancelled by the sponsor
+	if inputs.cancellation {
+		k, err := CancelKey(sm, t.Auth.Sponsor())
+		switch {
+		case errors.Is(err, ErrCancelUnsupported):
+		case err != nil:
+			return nil, err
+		default:
+			stateKeys.Add(string(k))
+		}
+	}
+
 	// Cache keys if called again
 	t.stateKeys = stateKeys
+	t.stateKeysInputs = inputs
 	return stateKeys, nil
 }
 
 // Sponsor is the [codec.Address] that pays fees for this transaction.
 func (t *Transaction) Sponsor() codec.Address { return t.Auth.Sponsor() }
 
+// feePayer returns the [codec.Address] charged for the fees of this
+// transaction and false if it is exempt from fees (see
+// [Rules.GetActionFeePayer]).
+func (t *Transaction) feePayer(r Rules) (codec.Address, bool) {
+	payer, ok := r.GetActionFeePayer(t.Action.GetTypeID())
+	if !ok {
+		return t.Auth.Sponsor(), true
+	}
+	return payer, payer != codec.EmptyAddress
+}
+
+// FeeExempt returns true if no account is charged for the fees of this
+// transaction (see [Rules.GetActionFeePayer]). Exempt transactions must
+// succeed to be included in a block, as failures would be free.
+func (t *Transaction) FeeExempt(r Rules) bool {
+	_, charged := t.feePayer(r)
+	return !charged
+}
+
 // Units is charged whether or not a transaction is successful because state
 // lookup is not free.
 func (t *Transaction) MaxUnits(sm StateManager, r Rules) (Dimensions, error) {
 	// Cacluate max compute costs
 	maxComputeUnitsOp := math.NewUint64Operator(r.GetBaseComputeUnits())
-	maxComputeUnitsOp.Add(t.Action.MaxComputeUnits(r))
+	maxComputeUnitsOp.Add(maxActionComputeUnits(r, t.Action))
 	maxComputeUnitsOp.Add(t.Auth.ComputeUnits(r))
 	if t.WarpMessage != nil {
 		maxComputeUnitsOp.Add(r.GetBaseWarpComputeUnits())
@@ -184,7 +308,7 @@ func (t *Transaction) MaxUnits(sm StateManager, r Rules) (Dimensions, error) {
 	// state keys.
 	//
 	// TODO: make this a tighter bound (allow for granular storage controls)
-	stateKeys, err := t.StateKeys(sm)
+	stateKeys, err := t.StateKeys(sm, r)
 	if err != nil {
 		return Dimensions{}, err
 	}
@@ -218,27 +342,53 @@ func (t *Transaction) MaxUnits(sm StateManager, r Rules) (Dimensions, error) {
 	if err != nil {
 		return Dimensions{}, err
 	}
-	return Dimensions{uint64(t.Size()), maxComputeUnits, reads, allocates, writes}, nil
+	d := Dimensions{uint64(t.Size()), maxComputeUnits, reads, allocates, writes}
+	addCustomUnits(r, t.Action, &d)
+	addBlobUnits(len(t.Blob), &d)
+	return d, nil
+}
+
+// maxActionComputeUnits returns the most compute units [action] could be
+// charged, preferring any override in [r].
+func maxActionComputeUnits(r Rules, action Action) uint64 {
+	if units, ok := r.GetActionComputeUnits(action.GetTypeID()); ok {
+		return units
+	}
+	return action.MaxComputeUnits(r)
 }
 
 // EstimateMaxUnits provides a pessimistic estimate of the cost to execute a transaction. This is
 // typically used during transaction construction.
 func EstimateMaxUnits(r Rules, action Action, authFactory AuthFactory, warpMessage *warp.Message) (Dimensions, error) {
 	authBandwidth, authCompute := authFactory.MaxUnits()
-	bandwidth := BaseSize + consts.ByteLen + uint64(action.Size()) + consts.ByteLen + authBandwidth
+	bandwidth := MaxBaseSize + uint64(typeLen(action)+action.Size()+typeLen(authFactory)) + authBandwidth
+	bandwidth += uint64(codec.BytesLen(nil)) // blob (see [EstimateMaxBlobUnits])
 	actionStateKeysMaxChunks := action.StateKeysMaxChunks()
 	sponsorStateKeyMaxChunks := r.GetSponsorStateKeysMaxChunks()
 	stateKeysMaxChunks := make([]uint16, 0, len(sponsorStateKeyMaxChunks)+len(actionStateKeysMaxChunks))
 	stateKeysMaxChunks = append(stateKeysMaxChunks, sponsorStateKeyMaxChunks...)
 	stateKeysMaxChunks = append(stateKeysMaxChunks, actionStateKeysMaxChunks...)
+	if stateful, ok := authFactory.(StatefulAuthFactory); ok {
+		stateKeysMaxChunks = append(stateKeysMaxChunks, stateful.StateKeysMaxChunks()...)
+	}
+	if r.GetSequentialNonces() {
+		stateKeysMaxChunks = append(stateKeysMaxChunks, NonceKeyChunks)
+	}
+	if r.GetTxCancellation() {
+		stateKeysMaxChunks = append(stateKeysMaxChunks, CancelKeyChunks)
+	}
+	if payer, ok := r.GetActionFeePayer(action.GetTypeID()); ok && payer != codec.EmptyAddress {
+		// The payer may differ from the sponsor
+		stateKeysMaxChunks = append(stateKeysMaxChunks, sponsorStateKeyMaxChunks...)
+	}
 
 	// Estimate compute costs
 	computeUnitsOp := math.NewUint64Operator(r.GetBaseComputeUnits())
 	computeUnitsOp.Add(authCompute)
-	computeUnitsOp.Add(action.MaxComputeUnits(r))
+	computeUnitsOp.Add(maxActionComputeUnits(r, action))
 	if warpMessage != nil {
 		bandwidth += uint64(codec.BytesLen(warpMessage.Bytes()))
-		stateKeysMaxChunks = append(stateKeysMaxChunks, MaxIncomingWarpChunks)
+		stateKeysMaxChunks = append(stateKeysMaxChunks, MaxIncomingWarpChunks, 0) // legacy record
 		computeUnitsOp.Add(r.GetBaseWarpComputeUnits())
 		numSigners, err := warpMessage.Signature.NumSigners()
 		if err != nil {
@@ -261,7 +411,7 @@ func EstimateMaxUnits(r Rules, action Action, authFactory AuthFactory, warpMessa
 	readsOp := math.NewUint64Operator(0)
 	allocatesOp := math.NewUint64Operator(0)
 	writesOp := math.NewUint64Operator(0)
-	for maxChunks := range stateKeysMaxChunks {
+	for _, maxChunks := range stateKeysMaxChunks {
 		// Compute key costs
 		readsOp.Add(r.GetStorageKeyReadUnits())
 		allocatesOp.Add(r.GetStorageKeyAllocateUnits())
@@ -284,7 +434,31 @@ func EstimateMaxUnits(r Rules, action Action, authFactory AuthFactory, warpMessa
 	if err != nil {
 		return Dimensions{}, err
 	}
-	return Dimensions{bandwidth, computeUnits, reads, allocates, writes}, nil
+	d := Dimensions{bandwidth, computeUnits, reads, allocates, writes}
+	addCustomUnits(r, action, &d)
+	return d, nil
+}
+
+// CheckDeprecated returns an error if [t] uses a version of an [Action] or
+// [Auth] that is deprecated at [height] (see [Versioned]).
+func (t *Transaction) CheckDeprecated(r Rules, height uint64) error {
+	actionID, actionVersion := t.Action.GetTypeID(), typeVersion(t.Action)
+	if deprecated, ok := r.GetActionDeprecationHeight(actionID, actionVersion); ok && height >= deprecated {
+		return fmt.Errorf("%w: type=%d version=%d height=%d", ErrActionDeprecated, actionID, actionVersion, deprecated)
+	}
+	authID, authVersion := t.Auth.GetTypeID(), typeVersion(t.Auth)
+	if deprecated, ok := r.GetAuthDeprecationHeight(authID, authVersion); ok && height >= deprecated {
+		return fmt.Errorf("%w: type=%d version=%d height=%d", ErrAuthDeprecated, authID, authVersion, deprecated)
+	}
+	return nil
+}
+
+// typeVersion returns the version of [o] (0 if it is not [Versioned]).
+func typeVersion(o Object) uint8 {
+	if v, ok := o.(Versioned); ok {
+		return v.GetVersion()
+	}
+	return 0
 }
 
 func (t *Transaction) PreExecute(
@@ -294,10 +468,14 @@ func (t *Transaction) PreExecute(
 	r Rules,
 	im state.Immutable,
 	timestamp int64,
+	height uint64,
 ) error {
-	if err := t.Base.Execute(r.ChainID(), r, timestamp); err != nil {
+	if err := t.Base.Execute(r.ChainID(), r, timestamp, height); err != nil {
 		return err
 	}
+	if r.IsActionDisabled(t.Action.GetTypeID()) {
+		return ErrActionDisabled
+	}
 	start, end := t.Action.ValidRange(r)
 	if start >= 0 && timestamp < start {
 		return ErrActionNotActivated
@@ -312,15 +490,55 @@ func (t *Transaction) PreExecute(
 	if end >= 0 && timestamp > end {
 		return ErrAuthNotActivated
 	}
+	if scoped, ok := t.Auth.(ScopedAuth); ok {
+		if err := scoped.Authorizes(t.Action, t.Base.MaxFee, timestamp); err != nil {
+			return fmt.Errorf("%w: %w", ErrAuthNotAuthorized, err)
+		}
+	}
+	if stateful, ok := t.Auth.(StatefulAuth); ok {
+		if err := stateful.VerifyState(ctx, im); err != nil {
+			return fmt.Errorf("%w: %w", ErrAuthNotAuthorized, err)
+		}
+	}
 	maxUnits, err := t.MaxUnits(s, r)
 	if err != nil {
 		return err
 	}
-	maxFee, err := feeManager.MaxFee(maxUnits)
-	if err != nil {
+	var maxFee uint64
+	if payer, charged := t.feePayer(r); charged {
+		if minFee := r.GetMinFee(); t.Base.MaxFee < minFee {
+			return fmt.Errorf("%w: max fee %d is below minimum %d", ErrInsufficientPrice, t.Base.MaxFee, minFee)
+		}
+		maxFee, err = feeManager.MaxFee(maxUnits)
+		if err != nil {
+			return err
+		}
+		maxFee = MinFee(r, maxFee)
+		if err := t.canDeductFee(ctx, s, r, payer, im, timestamp, maxFee); err != nil {
+			return err
+		}
+	}
+	if metered, ok := t.Auth.(MeteredAuth); ok {
+		if err := metered.CanCharge(ctx, im, t.Action, maxFee); err != nil {
+			return fmt.Errorf("%w: %w", ErrAuthNotAuthorized, err)
+		}
+	}
+	if err := t.verifyNotCancelled(ctx, s, r, im, timestamp); err != nil {
+		return err
+	}
+	if err := t.verifyWarpSigners(r); err != nil {
+		return err
+	}
+	if err := t.verifyWarpReplay(ctx, s, r, im, timestamp); err != nil {
 		return err
 	}
-	return s.CanDeduct(ctx, t.Auth.Sponsor(), im, maxFee)
+
+	// Checked last so that [ErrNonceTooHigh] implies that the transaction is
+	// otherwise valid
+	if t.Base.SequentialNonce() {
+		return t.verifyNonce(ctx, s, im)
+	}
+	return nil
 }
 
 // Execute after knowing a transaction can pay a fee. Attempt
@@ -346,32 +564,55 @@ func (t *Transaction) Execute(
 		// Should never happen
 		return nil, err
 	}
-	maxFee, err := feeManager.MaxFee(maxUnits)
-	if err != nil {
-		// Should never happen
-		return nil, err
+	payer, charged := t.feePayer(r)
+	var maxFee, converted uint64
+	if charged {
+		maxFee, err = feeManager.MaxFee(maxUnits)
+		if err != nil {
+			// Should never happen
+			return nil, err
+		}
+		maxFee = MinFee(r, maxFee)
+		converted, err = t.deductFee(ctx, s, r, payer, ts, timestamp, maxFee)
+		if err != nil {
+			// This should never fail for low balance (as we check [CanDeductFee]
+			// immediately before).
+			return nil, err
+		}
 	}
-	if err := s.Deduct(ctx, t.Auth.Sponsor(), ts, maxFee); err != nil {
-		// This should never fail for low balance (as we check [CanDeductFee]
-		// immediately before).
-		return nil, err
+	// Fees count towards the limits of a [MeteredAuth] whether or not [Action]
+	// succeeds, so they are charged before [Action] can be reverted
+	metered, isMetered := t.Auth.(MeteredAuth)
+	if isMetered {
+		// Checked by [MeteredAuth.CanCharge] in [PreExecute]
+		if err := metered.Charge(ctx, ts, nil, maxFee); err != nil {
+			return nil, err
+		}
+	}
+	if t.Base.SequentialNonce() {
+		if err := t.incrementNonce(ctx, s, ts); err != nil {
+			return nil, err
+		}
 	}
 
 	// Check warp message is not duplicate
 	if t.WarpMessage != nil {
-		p := s.IncomingWarpKeyPrefix(t.WarpMessage.SourceChainID, t.warpID)
-		k := keys.EncodeChunks(p, MaxIncomingWarpChunks)
-		_, err := ts.GetValue(ctx, k)
+		err := t.verifyWarpReplay(ctx, s, r, ts, timestamp)
 		switch {
 		case err == nil:
-			// Override all errors because warp message is a duplicate
-			warpVerified = false
-		case errors.Is(err, database.ErrNotFound):
 			// This means there are no conflicts
-		case err != nil:
+		case errors.Is(err, ErrWarpReplay), errors.Is(err, ErrWarpMessageExpired):
+			// Override all errors because warp message is a duplicate (or too old)
+			warpVerified = false
+		default:
 			// An error here can indicate there is an issue with the database or that
 			// the key was not properly specified.
-			return &Result{false, utils.ErrBytes(err), maxUnits, maxFee, nil}, nil
+			return &Result{
+				Status:   StatusOf(err),
+				Output:   utils.ErrBytes(err),
+				Consumed: maxUnits,
+				Fee:      maxFee,
+			}, nil
 		}
 	}
 
@@ -382,11 +623,29 @@ func (t *Transaction) Execute(
 		// are set when this function is defined. If any of them are
 		// modified later, they will not be used here.
 		ts.Rollback(ctx, actionStart)
-		return &Result{false, utils.ErrBytes(rerr), maxUnits, maxFee, nil}, nil
-	}
+		return &Result{
+			Status:   StatusOf(rerr),
+			Output:   utils.ErrBytes(rerr),
+			Consumed: maxUnits,
+			Fee:      maxFee,
+		}, nil
+	}
+	status := StatusFailed
 	success, actionCUs, output, warpMessage, err := t.Action.Execute(ctx, r, ts, timestamp, t.Auth.Actor(), t.id, warpVerified)
 	if err != nil {
-		return handleRevert(err)
+		// Failures returned with [Fail] (or [NewStatusError]) are charged like
+		// any other failure, instead of reverting.
+		failStatus, failOutput, ok := AsFailure(err)
+		if !ok {
+			return handleRevert(err)
+		}
+		success, output, warpMessage, status = false, failOutput, nil, failStatus
+	}
+	if success {
+		status = StatusSuccess
+	}
+	if units, ok := r.GetActionComputeUnits(t.Action.GetTypeID()); ok {
+		actionCUs = units
 	}
 	if len(output) == 0 && output != nil {
 		// Enforce object standardization (this is a VM bug and we should fail
@@ -405,9 +664,7 @@ func (t *Transaction) Execute(
 
 		// Store incoming warp messages in state by their ID to prevent replays
 		if t.WarpMessage != nil {
-			p := s.IncomingWarpKeyPrefix(t.WarpMessage.SourceChainID, t.warpID)
-			k := keys.EncodeChunks(p, MaxIncomingWarpChunks)
-			if err := ts.Insert(ctx, k, nil); err != nil {
+			if err := t.recordWarpReplay(ctx, s, r, ts); err != nil {
 				return handleRevert(err)
 			}
 		}
@@ -430,6 +687,13 @@ func (t *Transaction) Execute(
 				return handleRevert(err)
 			}
 		}
+
+		if isMetered {
+			// The fee was already charged
+			if err := metered.Charge(ctx, ts, t.Action, 0); err != nil {
+				return handleRevert(err)
+			}
+		}
 	}
 
 	// Calculate units used
@@ -454,14 +718,20 @@ func (t *Transaction) Execute(
 
 	// Because we compute the fee before [Auth.Refund] is called, we need
 	// to pessimistically precompute the storage it will change.
-	for _, key := range s.SponsorStateKeys(t.Auth.Sponsor()) {
-		// maxChunks will be greater than the chunks read in any of these keys,
-		// so we don't need to check for pre-existing values.
-		maxChunks, ok := keys.MaxChunks([]byte(key))
-		if !ok {
-			return handleRevert(ErrInvalidKeyValue)
+	if charged {
+		payerKeys, err := t.payerStateKeys(s, r, payer)
+		if err != nil {
+			return handleRevert(err)
+		}
+		for _, key := range payerKeys {
+			// maxChunks will be greater than the chunks read in any of these keys,
+			// so we don't need to check for pre-existing values.
+			maxChunks, ok := keys.MaxChunks([]byte(key))
+			if !ok {
+				return handleRevert(ErrInvalidKeyValue)
+			}
+			writes[key] = maxChunks
 		}
-		writes[key] = maxChunks
 	}
 
 	// We only charge for the chunks read from disk instead of charging for the max chunks
@@ -494,6 +764,8 @@ func (t *Transaction) Execute(
 		return handleRevert(err)
 	}
 	used := Dimensions{uint64(t.Size()), computeUnits, readUnits, allocateUnits, writeUnits}
+	addCustomUnits(r, t.Action, &used)
+	addBlobUnits(len(t.Blob), &used)
 
 	// Check to see if the units consumed are greater than the max units
 	//
@@ -506,21 +778,30 @@ func (t *Transaction) Execute(
 	// Return any funds from unused units
 	//
 	// To avoid storage abuse of [Auth.Refund], we precharge for possible usage.
-	feeRequired, err := feeManager.MaxFee(used)
-	if err != nil {
-		return handleRevert(err)
-	}
-	refund := maxFee - feeRequired
-	if refund > 0 {
-		ts.DisableAllocation()
-		defer ts.EnableAllocation()
-		if err := s.Refund(ctx, t.Auth.Sponsor(), ts, refund); err != nil {
+	var feeRequired uint64
+	if charged {
+		feeRequired, err = feeManager.MaxFee(used)
+		if err != nil {
 			return handleRevert(err)
 		}
+		feeRequired = MinFee(r, feeRequired)
+		refund := maxFee - feeRequired
+		if refund > 0 {
+			ts.DisableAllocation()
+			defer ts.EnableAllocation()
+			if err := t.refundFee(ctx, s, r, payer, ts, maxFee, converted, refund); err != nil {
+				return handleRevert(err)
+			}
+			if isMetered {
+				if err := metered.Refund(ctx, ts, refund); err != nil {
+					return handleRevert(err)
+				}
+			}
+		}
 	}
 	return &Result{
-		Success: success,
-		Output:  output,
+		Status: status,
+		Output: output,
 
 		Consumed: used,
 		Fee:      feeRequired,
@@ -535,8 +816,7 @@ func (t *Transaction) Marshal(p *codec.Packer) error {
 		return p.Err()
 	}
 
-	actionID := t.Action.GetTypeID()
-	authID := t.Auth.GetTypeID()
+	packExtensions(p, t.extensions())
 	t.Base.Marshal(p)
 	var warpBytes []byte
 	if t.WarpMessage != nil {
@@ -546,13 +826,46 @@ func (t *Transaction) Marshal(p *codec.Packer) error {
 		}
 	}
 	p.PackBytes(warpBytes)
-	p.PackByte(actionID)
+	packBlob(p, t.Blob)
+	packAccessList(p, t.AccessList)
+	packType(p, t.Action)
 	t.Action.Marshal(p)
-	p.PackByte(authID)
+	packType(p, t.Auth)
 	t.Auth.Marshal(p)
 	return p.Err()
 }
 
+// typeLen is the size of the type ID (and version, if [o] is [Versioned])
+// that prefixes the encoding of [o].
+func typeLen(o any) int {
+	if _, ok := o.(Versioned); ok {
+		return consts.ByteLen + consts.ByteLen
+	}
+	return consts.ByteLen
+}
+
+func packType(p *codec.Packer, o Object) {
+	p.PackByte(o.GetTypeID())
+	if v, ok := o.(Versioned); ok {
+		p.PackByte(v.GetVersion())
+	}
+}
+
+// unpackType unpacks the type ID of an [Action] or [Auth] (and its version, if
+// it is versioned in [registry]) and returns its decoder.
+func unpackType[T any](
+	p *codec.Packer,
+	registry *codec.TypeParser[T, *warp.Message, bool],
+) (uint8, uint8, func(*codec.Packer, *warp.Message) (T, error), bool, bool) {
+	typeID := p.UnpackByte()
+	var version uint8
+	if registry.IsVersioned(typeID) {
+		version = p.UnpackByte()
+	}
+	unmarshal, warp, ok := registry.LookupVersion(typeID, version)
+	return typeID, version, unmarshal, warp, ok
+}
+
 func MarshalTxs(txs []*Transaction) ([]byte, error) {
 	if len(txs) == 0 {
 		return nil, ErrNoTxs
@@ -593,15 +906,35 @@ func UnmarshalTxs(
 	return authCounts, txs, p.Err()
 }
 
-func UnmarshalTx(
+// UnmarshalUnsignedTx parses [digest] (as returned by [Transaction.Digest])
+// into a [Transaction] that has not yet been signed. This allows an unsigned
+// transaction to be passed between hosts (e.g. to sign it offline) using the
+// same stable encoding that is signed.
+func UnmarshalUnsignedTx(digest []byte, actionRegistry ActionRegistry) (*Transaction, error) {
+	p := codec.NewReader(digest, consts.NetworkSizeLimit)
+	tx, _, err := unmarshalUnsignedTx(p, actionRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidObject
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	tx.digest = digest
+	return tx, nil
+}
+
+// unmarshalUnsignedTx unpacks all fields of a [Transaction] that precede its
+// [Auth] and returns whether the [Action] requires a warp message.
+func unmarshalUnsignedTx(
 	p *codec.Packer,
-	actionRegistry *codec.TypeParser[Action, *warp.Message, bool],
-	authRegistry *codec.TypeParser[Auth, *warp.Message, bool],
-) (*Transaction, error) {
-	start := p.Offset()
-	base, err := UnmarshalBase(p)
+	actionRegistry ActionRegistry,
+) (*Transaction, bool, error) {
+	base, exts, err := unmarshalBase(p)
 	if err != nil {
-		return nil, fmt.Errorf("%w: could not unmarshal base", err)
+		return nil, false, fmt.Errorf("%w: could not unmarshal base", err)
 	}
 	var warpBytes []byte
 	p.UnpackBytes(MaxWarpMessageSize, false, &warpBytes)
@@ -610,35 +943,68 @@ func UnmarshalTx(
 	if len(warpBytes) > 0 {
 		msg, err := warp.ParseMessage(warpBytes)
 		if err != nil {
-			return nil, fmt.Errorf("%w: could not unmarshal warp message", err)
+			return nil, false, fmt.Errorf("%w: could not unmarshal warp message", err)
 		}
 		if len(msg.Payload) == 0 {
-			return nil, ErrEmptyWarpPayload
+			return nil, false, ErrEmptyWarpPayload
 		}
 		warpMessage = msg
 		numSigners, err := msg.Signature.NumSigners()
 		if err != nil {
-			return nil, fmt.Errorf("%w: could not calculate number of warp signers", err)
+			return nil, false, fmt.Errorf("%w: could not calculate number of warp signers", err)
 		}
 		numWarpSigners = numSigners
 	}
-	actionType := p.UnpackByte()
-	unmarshalAction, actionWarp, ok := actionRegistry.LookupIndex(actionType)
+	var blob []byte
+	if exts&extBlob != 0 {
+		if _, ok := BlobDimension(); !ok {
+			return nil, false, ErrBlobsDisabled
+		}
+		p.UnpackBytes(MaxBlobSize, true, &blob)
+	}
+	var accessList []string
+	if exts&extAccessList != 0 {
+		accessList, err = unpackAccessList(p)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	actionType, actionVersion, unmarshalAction, actionWarp, ok := unpackType(p, actionRegistry)
 	if !ok {
-		return nil, fmt.Errorf("%w: %d is unknown action type", ErrInvalidObject, actionType)
+		return nil, false, fmt.Errorf("%w: %d (version %d) is unknown action type", ErrInvalidObject, actionType, actionVersion)
 	}
 	if actionWarp && warpMessage == nil {
-		return nil, fmt.Errorf("%w: action %d", ErrExpectedWarpMessage, actionType)
+		return nil, false, fmt.Errorf("%w: action %d", ErrExpectedWarpMessage, actionType)
 	}
 	action, err := unmarshalAction(p, warpMessage)
 	if err != nil {
-		return nil, fmt.Errorf("%w: could not unmarshal action", err)
+		return nil, false, fmt.Errorf("%w: could not unmarshal action", err)
+	}
+	return &Transaction{
+		Base:           base,
+		WarpMessage:    warpMessage,
+		Blob:           blob,
+		AccessList:     accessList,
+		Action:         action,
+		numWarpSigners: numWarpSigners,
+	}, actionWarp, nil
+}
+
+func UnmarshalTx(
+	p *codec.Packer,
+	actionRegistry *codec.TypeParser[Action, *warp.Message, bool],
+	authRegistry *codec.TypeParser[Auth, *warp.Message, bool],
+) (*Transaction, error) {
+	start := p.Offset()
+	tx, actionWarp, err := unmarshalUnsignedTx(p, actionRegistry)
+	if err != nil {
+		return nil, err
 	}
+	warpMessage := tx.WarpMessage
 	digest := p.Offset()
-	authType := p.UnpackByte()
-	unmarshalAuth, authWarp, ok := authRegistry.LookupIndex(authType)
+	authType, authVersion, unmarshalAuth, authWarp, ok := unpackType(p, authRegistry)
 	if !ok {
-		return nil, fmt.Errorf("%w: %d is unknown auth type", ErrInvalidObject, authType)
+		return nil, fmt.Errorf("%w: %d (version %d) is unknown auth type", ErrInvalidObject, authType, authVersion)
 	}
 	if authWarp && warpMessage == nil {
 		return nil, fmt.Errorf("%w: auth %d", ErrExpectedWarpMessage, authType)
@@ -658,10 +1024,6 @@ func UnmarshalTx(
 		return nil, ErrUnexpectedWarpMessage
 	}
 
-	var tx Transaction
-	tx.Base = base
-	tx.Action = action
-	tx.WarpMessage = warpMessage
 	tx.Auth = auth
 	if err := p.Err(); err != nil {
 		return nil, p.Err()
@@ -672,8 +1034,7 @@ func UnmarshalTx(
 	tx.size = len(tx.bytes)
 	tx.id = utils.ToID(tx.bytes)
 	if tx.WarpMessage != nil {
-		tx.numWarpSigners = numWarpSigners
 		tx.warpID = tx.WarpMessage.ID()
 	}
-	return &tx, nil
+	return tx, nil
 }
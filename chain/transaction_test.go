@@ -0,0 +1,292 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// testStateManager stores the balance of each sponsor in a single key with
+// [sponsorChunks] and lets sponsors cancel transactions.
+type testStateManager struct {
+	StateManager
+}
+
+const sponsorChunks = 2
+
+func (testStateManager) SponsorStateKeys(addr codec.Address) []string {
+	return []string{string(keys.EncodeChunks(append([]byte{0x0}, addr[:]...), sponsorChunks))}
+}
+
+func (testStateManager) CancelKey(addr codec.Address) []byte {
+	return append([]byte{0x3}, addr[:]...)
+}
+
+// newUnitsRules returns rules where each dimension of every storage key
+// costs 1 unit plus 10 units per chunk.
+func newUnitsRules(ctrl *gomock.Controller, cancellation bool) *MockRules {
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetBaseComputeUnits().Return(uint64(1)).AnyTimes()
+	r.EXPECT().GetActionComputeUnits(gomock.Any()).Return(uint64(0), false).AnyTimes()
+	r.EXPECT().GetActionFeePayer(gomock.Any()).Return(codec.EmptyAddress, false).AnyTimes()
+	r.EXPECT().GetSponsorStateKeysMaxChunks().Return([]uint16{sponsorChunks}).AnyTimes()
+	r.EXPECT().GetSequentialNonces().Return(false).AnyTimes()
+	r.EXPECT().GetTxCancellation().Return(cancellation).AnyTimes()
+	r.EXPECT().GetStorageKeyReadUnits().Return(uint64(1)).AnyTimes()
+	r.EXPECT().GetStorageKeyAllocateUnits().Return(uint64(1)).AnyTimes()
+	r.EXPECT().GetStorageKeyWriteUnits().Return(uint64(1)).AnyTimes()
+	r.EXPECT().GetStorageValueReadUnits().Return(uint64(10)).AnyTimes()
+	r.EXPECT().GetStorageValueAllocateUnits().Return(uint64(10)).AnyTimes()
+	r.EXPECT().GetStorageValueWriteUnits().Return(uint64(10)).AnyTimes()
+	return r
+}
+
+// newUnitsTx returns a transaction whose action touches keys with 5 and 7
+// chunks and the estimate of its units.
+func newUnitsTx(t *testing.T, ctrl *gomock.Controller, r Rules) (*Transaction, Dimensions) {
+	actor := codec.CreateAddress(0, ids.GenerateTestID())
+	action := NewMockAction(ctrl)
+	action.EXPECT().GetTypeID().Return(uint8(0)).AnyTimes()
+	action.EXPECT().Size().Return(0).AnyTimes()
+	action.EXPECT().MaxComputeUnits(gomock.Any()).Return(uint64(3)).AnyTimes()
+	action.EXPECT().OutputsWarpMessage().Return(false).AnyTimes()
+	action.EXPECT().StateKeysMaxChunks().Return([]uint16{5, 7}).AnyTimes()
+	action.EXPECT().StateKeys(gomock.Any(), gomock.Any()).Return([]string{
+		string(keys.EncodeChunks([]byte{0x1}, 5)),
+		string(keys.EncodeChunks([]byte{0x2}, 7)),
+	}).AnyTimes()
+	auth := NewMockAuth(ctrl)
+	auth.EXPECT().Actor().Return(actor).AnyTimes()
+	auth.EXPECT().Sponsor().Return(actor).AnyTimes()
+	auth.EXPECT().ComputeUnits(gomock.Any()).Return(uint64(2)).AnyTimes()
+	factory := NewMockAuthFactory(ctrl)
+	factory.EXPECT().MaxUnits().Return(uint64(0), uint64(2)).AnyTimes()
+
+	estimate, err := EstimateMaxUnits(r, action, factory, nil)
+	require.NoError(t, err)
+	return &Transaction{Base: &Base{Timestamp: 1_000}, Action: action, Auth: auth}, estimate
+}
+
+func TestEstimateMaxUnits(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		cancellation bool
+		storageUnits uint64
+	}{
+		{
+			name:         "cancellation disabled",
+			storageUnits: 3 + 10*(sponsorChunks+5+7),
+		},
+		{
+			name:         "cancellation enabled",
+			cancellation: true,
+			storageUnits: 4 + 10*(sponsorChunks+5+7+CancelKeyChunks),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			r := newUnitsRules(ctrl, test.cancellation)
+			tx, estimate := newUnitsTx(t, ctrl, r)
+			maxUnits, err := tx.MaxUnits(testStateManager{}, r)
+			require.NoError(err)
+
+			// The estimate matches the units charged (only the size of the
+			// transaction, which isn't known until it is signed, is pessimistic)
+			u := test.storageUnits
+			require.Equal(Dimensions{0, 6, u, u, u}, maxUnits)
+			require.GreaterOrEqual(estimate[Bandwidth], maxUnits[Bandwidth])
+			estimate[Bandwidth] = maxUnits[Bandwidth]
+			require.Equal(maxUnits, estimate)
+		})
+	}
+}
+
+// testPaymasterManager charges fees paid in any asset to [addr] and the
+// paymaster set by each [Rules] (if any).
+type testPaymasterManager struct {
+	testStateManager
+	FeeConverter
+
+	paymasters map[Rules]codec.Address
+}
+
+func (m *testPaymasterManager) FeeAssetStateKeys(r Rules, addr codec.Address, _ ids.ID) []string {
+	feeKeys := m.SponsorStateKeys(addr)
+	if paymaster, ok := m.paymasters[r]; ok {
+		feeKeys = append(feeKeys, m.SponsorStateKeys(paymaster)...)
+	}
+	return feeKeys
+}
+
+func TestStateKeysRulesChange(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	var (
+		disabled = newUnitsRules(ctrl, false)
+		enabled  = newUnitsRules(ctrl, true)
+		sm       = &testPaymasterManager{paymasters: map[Rules]codec.Address{}}
+	)
+	tx, _ := newUnitsTx(t, ctrl, disabled)
+	cancelKey, err := CancelKey(sm, tx.Sponsor())
+	require.NoError(err)
+
+	// Keys aren't reused when cancellation is enabled (i.e. by an upgrade)
+	stateKeys, err := tx.StateKeys(sm, disabled)
+	require.NoError(err)
+	require.False(stateKeys.Contains(string(cancelKey)))
+	stateKeys, err = tx.StateKeys(sm, enabled)
+	require.NoError(err)
+	require.True(stateKeys.Contains(string(cancelKey)))
+	stateKeys, err = tx.StateKeys(sm, disabled)
+	require.NoError(err)
+	require.False(stateKeys.Contains(string(cancelKey)))
+
+	// Keys aren't reused when the paymaster of the fee asset changes
+	tx.Base.FeeAsset = ids.GenerateTestID()
+	tx.stateKeys = nil
+	paymaster := codec.CreateAddress(0, ids.GenerateTestID())
+	paymasterKey := sm.SponsorStateKeys(paymaster)[0]
+	stateKeys, err = tx.StateKeys(sm, disabled)
+	require.NoError(err)
+	require.False(stateKeys.Contains(paymasterKey))
+	sm.paymasters[disabled] = paymaster
+	stateKeys, err = tx.StateKeys(sm, disabled)
+	require.NoError(err)
+	require.True(stateKeys.Contains(paymasterKey))
+}
+
+// testFeeHandler charges fees without tracking balances.
+type testFeeHandler struct {
+	testStateManager
+}
+
+func (testFeeHandler) Deduct(context.Context, codec.Address, state.Mutable, uint64) error {
+	return nil
+}
+
+func (testFeeHandler) Refund(context.Context, codec.Address, state.Mutable, uint64) error {
+	return nil
+}
+
+// testActionSpends is the amount [testMeteredAuth] records for each action
+// that succeeds.
+const testActionSpends = 100
+
+// testMeteredAuth stores the total amount charged to it at [spentKey].
+type testMeteredAuth struct {
+	*MockAuth
+
+	spentKey []byte
+}
+
+func (a *testMeteredAuth) StateKeys() []string {
+	return []string{string(a.spentKey)}
+}
+
+func (*testMeteredAuth) VerifyState(context.Context, state.Immutable) error {
+	return nil
+}
+
+func (*testMeteredAuth) CanCharge(context.Context, state.Immutable, Action, uint64) error {
+	return nil
+}
+
+func (a *testMeteredAuth) spent(ctx context.Context, im state.Immutable) uint64 {
+	v, err := im.GetValue(ctx, a.spentKey)
+	if err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+func (a *testMeteredAuth) Charge(ctx context.Context, mu state.Mutable, action Action, fee uint64) error {
+	spent := a.spent(ctx, mu) + fee
+	if action != nil {
+		spent += testActionSpends
+	}
+	return mu.Insert(ctx, a.spentKey, binary.BigEndian.AppendUint64(nil, spent))
+}
+
+func (a *testMeteredAuth) Refund(ctx context.Context, mu state.Mutable, refund uint64) error {
+	return mu.Insert(ctx, a.spentKey, binary.BigEndian.AppendUint64(nil, a.spent(ctx, mu)-refund))
+}
+
+func TestMeteredAuthCharge(t *testing.T) {
+	errRevert := errors.New("revert")
+	for _, test := range []struct {
+		name    string
+		success bool
+		err     error
+		status  Status
+		spends  uint64
+	}{
+		{
+			name:    "success",
+			success: true,
+			status:  StatusSuccess,
+			spends:  testActionSpends,
+		},
+		{
+			name:   "failure",
+			status: StatusFailed,
+		},
+		{
+			// Reverted transactions keep the max fee, which must be
+			// recorded even though the changes of the action are rolled back
+			name:   "revert",
+			err:    errRevert,
+			status: StatusFailed,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+			ctx := context.Background()
+
+			r := newUnitsRules(ctrl, false)
+			r.EXPECT().GetMinFee().Return(uint64(0)).AnyTimes()
+			tx, _ := newUnitsTx(t, ctrl, r)
+			auth := &testMeteredAuth{MockAuth: tx.Auth.(*MockAuth), spentKey: keys.EncodeChunks([]byte{0x4}, 1)}
+			tx.Auth = auth
+			tx.Action.(*MockAction).EXPECT().
+				Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(test.success, uint64(1), nil, nil, test.err)
+
+			sm := testFeeHandler{}
+			stateKeys, err := tx.StateKeys(sm, r)
+			require.NoError(err)
+			reads := make(map[string]uint16, len(stateKeys))
+			for k := range stateKeys {
+				reads[k] = 0
+			}
+			feeManager := NewFeeManager(nil)
+			for i := Dimension(0); int(i) < FeeDimensions(); i++ {
+				feeManager.SetUnitPrice(i, 1)
+			}
+			tsv := tstate.New(1).NewView(stateKeys, map[string][]byte{})
+			result, err := tx.Execute(ctx, feeManager, reads, sm, r, tsv, 1_000, false)
+			require.NoError(err)
+			require.Equal(test.status, result.Status)
+
+			// The fee charged is recorded whether or not the action succeeds
+			require.NotZero(result.Fee)
+			require.Equal(result.Fee+test.spends, auth.spent(ctx, tsv))
+		})
+	}
+}
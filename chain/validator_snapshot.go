@@ -0,0 +1,223 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/tstate"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	// SnapshotCommitmentLen is the size of the payload of the warp message
+	// that attests to a [ValidatorSnapshot].
+	SnapshotCommitmentLen = consts.Uint64Len*3 + sha256.Size
+
+	// maxSnapshotValidators bounds the size of a [ValidatorSnapshot] we are
+	// willing to unmarshal.
+	maxSnapshotValidators = 1 << 16
+
+	snapshotIDPrefix = "validatorSnapshot"
+)
+
+type SnapshotValidator struct {
+	NodeID    ids.NodeID `json:"nodeID"`
+	PublicKey []byte     `json:"publicKey"` // empty if the validator has no BLS key
+	Weight    uint64     `json:"weight"`
+}
+
+// ValidatorSnapshot is the validator set of the subnet at [PChainHeight],
+// taken at the end of [Epoch] (see [Rules.GetValidatorSnapshots]).
+//
+// Validators are sorted by NodeID.
+type ValidatorSnapshot struct {
+	Epoch        uint64               `json:"epoch"`
+	PChainHeight uint64               `json:"pChainHeight"`
+	Validators   []*SnapshotValidator `json:"validators"`
+}
+
+// NewValidatorSnapshot fetches the validator set of the subnet that runs
+// [chainID] at [pChainHeight] from [vdrState].
+func NewValidatorSnapshot(
+	ctx context.Context,
+	vdrState validators.State,
+	chainID ids.ID,
+	epoch uint64,
+	pChainHeight uint64,
+) (*ValidatorSnapshot, error) {
+	subnetID, err := vdrState.GetSubnetID(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	vdrSet, err := vdrState.GetValidatorSet(ctx, pChainHeight, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	vdrs := make([]*SnapshotValidator, 0, len(vdrSet))
+	for _, vdr := range vdrSet {
+		sv := &SnapshotValidator{
+			NodeID: vdr.NodeID,
+			Weight: vdr.Weight,
+		}
+		if vdr.PublicKey != nil {
+			sv.PublicKey = bls.PublicKeyToBytes(vdr.PublicKey)
+		}
+		vdrs = append(vdrs, sv)
+	}
+	sort.Slice(vdrs, func(i, j int) bool {
+		return bytes.Compare(vdrs[i].NodeID[:], vdrs[j].NodeID[:]) < 0
+	})
+	return &ValidatorSnapshot{
+		Epoch:        epoch,
+		PChainHeight: pChainHeight,
+		Validators:   vdrs,
+	}, nil
+}
+
+// TotalWeight is the sum of the weights of all validators in [s].
+func (s *ValidatorSnapshot) TotalWeight() (uint64, error) {
+	var total uint64
+	for _, vdr := range s.Validators {
+		var err error
+		total, err = math.Add64(total, vdr.Weight)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+func (s *ValidatorSnapshot) Size() int {
+	size := consts.Uint64Len*2 + consts.IntLen
+	for _, vdr := range s.Validators {
+		size += ids.NodeIDLen + codec.BytesLen(vdr.PublicKey) + consts.Uint64Len
+	}
+	return size
+}
+
+func (s *ValidatorSnapshot) Marshal() ([]byte, error) {
+	p := codec.NewWriter(s.Size(), consts.MaxInt)
+	p.PackUint64(s.Epoch)
+	p.PackUint64(s.PChainHeight)
+	p.PackInt(len(s.Validators))
+	for _, vdr := range s.Validators {
+		p.PackFixedBytes(vdr.NodeID[:])
+		p.PackBytes(vdr.PublicKey)
+		p.PackUint64(vdr.Weight)
+	}
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalValidatorSnapshot(b []byte) (*ValidatorSnapshot, error) {
+	p := codec.NewReader(b, consts.MaxInt)
+	s := &ValidatorSnapshot{
+		Epoch:        p.UnpackUint64(false),
+		PChainHeight: p.UnpackUint64(false),
+	}
+	count := p.UnpackInt(false)
+	if count > maxSnapshotValidators {
+		return nil, fmt.Errorf("%w: %d validators", ErrInvalidSnapshot, count)
+	}
+	s.Validators = make([]*SnapshotValidator, count)
+	for i := range s.Validators {
+		vdr := &SnapshotValidator{}
+		nodeID := vdr.NodeID[:]
+		p.UnpackFixedBytes(ids.NodeIDLen, &nodeID)
+		p.UnpackBytes(bls.PublicKeyLen, false, &vdr.PublicKey)
+		vdr.Weight = p.UnpackUint64(false)
+		s.Validators[i] = vdr
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidObject
+	}
+	return s, p.Err()
+}
+
+// Commitment returns the payload of the warp message that attests to [s]:
+// Epoch | PChainHeight | TotalWeight | sha256(Marshal).
+//
+// The full snapshot is too large to sign and store in state, so external
+// verifiers fetch it separately and check it against the commitment.
+func (s *ValidatorSnapshot) Commitment() ([]byte, error) {
+	b, err := s.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	totalWeight, err := s.TotalWeight()
+	if err != nil {
+		return nil, err
+	}
+	root := sha256.Sum256(b)
+	p := codec.NewWriter(SnapshotCommitmentLen, SnapshotCommitmentLen)
+	p.PackUint64(s.Epoch)
+	p.PackUint64(s.PChainHeight)
+	p.PackUint64(totalWeight)
+	p.PackFixedBytes(root[:])
+	return p.Bytes(), p.Err()
+}
+
+// ValidatorSnapshotID is the ID that the warp message attesting to the
+// snapshot of [epoch] is stored (and signed) under, in place of a
+// transaction ID.
+func ValidatorSnapshotID(epoch uint64) ids.ID {
+	return utils.ToID(binary.BigEndian.AppendUint64([]byte(snapshotIDPrefix), epoch))
+}
+
+// storeValidatorSnapshot takes the [ValidatorSnapshot] of the epoch that the
+// block at [height] ends and stores the warp message attesting to it in [ts].
+//
+// The message is stored at the outgoing warp key of [ValidatorSnapshotID],
+// so it can be signed and served like any other outgoing warp message.
+func storeValidatorSnapshot(
+	ctx context.Context,
+	r Rules,
+	sm StateManager,
+	vdrState validators.State,
+	ts *tstate.TState,
+	height uint64,
+	pChainHeight uint64,
+) (*ValidatorSnapshot, *warp.UnsignedMessage, error) {
+	epoch := Epoch(r, height)
+	snapshot, err := NewValidatorSnapshot(ctx, vdrState, r.ChainID(), epoch, pChainHeight)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	commitment, err := snapshot.Commitment()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := warp.NewUnsignedMessage(r.NetworkID(), r.ChainID(), commitment)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := keys.EncodeChunks(sm.OutgoingWarpKeyPrefix(ValidatorSnapshotID(epoch)), MaxOutgoingWarpChunks)
+	tsv := ts.NewView(set.Of(string(k)), nil)
+	if err := tsv.Insert(ctx, k, msg.Bytes()); err != nil {
+		return nil, nil, err
+	}
+	tsv.Commit()
+	return snapshot, msg, nil
+}
+
+// snapshotsValidators returns true if the block at [height] must take a
+// [ValidatorSnapshot].
+func snapshotsValidators(r Rules, height uint64) bool {
+	return r.GetValidatorSnapshots() && IsEpochEnd(r, height)
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import "fmt"
+
+// VerifyWarpQuorum returns [ErrInvalidWarpQuorum] unless [num]/[denom] (the
+// fraction of stake weight that must sign imported warp messages) is greater
+// than 0 and at most 1.
+func VerifyWarpQuorum(num uint64, denom uint64) error {
+	if num == 0 || num > denom {
+		return fmt.Errorf("%w: %d/%d", ErrInvalidWarpQuorum, num, denom)
+	}
+	return nil
+}
+
+// verifyWarpSigners returns [ErrTooManyWarpSigners] if the warp message of the
+// transaction has more signers than [Rules.GetWarpMaxSigners] allows.
+//
+// This is checked before the signature is verified, so that messages that
+// would be too expensive to verify are dropped instead of paying for it.
+func (t *Transaction) verifyWarpSigners(r Rules) error {
+	if t.WarpMessage == nil {
+		return nil
+	}
+	if limit := r.GetWarpMaxSigners(); limit > 0 && t.numWarpSigners > limit {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyWarpSigners, t.numWarpSigners, limit)
+	}
+	return nil
+}
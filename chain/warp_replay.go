@@ -0,0 +1,323 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// warpReplayEntryLen is the size of a record listed in a bucket of expiring
+// records (its source chain and message ID).
+const warpReplayEntryLen = ids.IDLen * 2
+
+// maxWarpReplayBucketEntries is the most records listed in a single bucket.
+const maxWarpReplayBucketEntries = MaxWarpReplayBucketChunks - 1
+
+// IncomingWarpKey returns the state key (suffixed with
+// [MaxIncomingWarpChunks]) that records that the warp message [msgID] from
+// [sourceChainID] was imported.
+//
+// Every imported message is recorded under the [WarpManager.IncomingWarpPrefix]
+// of [sm], so no transaction can import it again. The record is only pruned
+// once the message is too old to be imported (see
+// [Rules.GetWarpReplayRetention]).
+func IncomingWarpKey(sm StateManager, sourceChainID ids.ID, msgID ids.ID) []byte {
+	p := sm.IncomingWarpPrefix()
+	k := make([]byte, 0, len(p)+ids.IDLen*2+consts.Uint16Len)
+	k = append(k, p...)
+	k = append(k, sourceChainID[:]...)
+	k = append(k, msgID[:]...)
+	return keys.EncodeChunks(k, MaxIncomingWarpChunks)
+}
+
+// LegacyIncomingWarpKey returns the state key that recorded that the warp
+// message [msgID] from [sourceChainID] was imported before records were kept
+// under [IncomingWarpKey] (the [WarpManager.IncomingWarpPrefix] of [sm]
+// followed by [sourceChainID] and [msgID], without any chunks).
+//
+// These records are never written or pruned, but they are still checked so
+// that messages imported before the upgrade can't be imported again.
+func LegacyIncomingWarpKey(sm StateManager, sourceChainID ids.ID, msgID ids.ID) []byte {
+	p := sm.IncomingWarpPrefix()
+	k := make([]byte, 0, len(p)+ids.IDLen*2+consts.Uint16Len)
+	k = append(k, p...)
+	k = append(k, sourceChainID[:]...)
+	k = append(k, msgID[:]...)
+	return keys.EncodeChunks(k, 0)
+}
+
+// getWarpReplay returns the value of the record of the warp message [msgID]
+// from [sourceChainID], falling back to its [LegacyIncomingWarpKey].
+func getWarpReplay(
+	ctx context.Context,
+	sm StateManager,
+	im state.Immutable,
+	sourceChainID ids.ID,
+	msgID ids.ID,
+) ([]byte, error) {
+	v, err := im.GetValue(ctx, IncomingWarpKey(sm, sourceChainID, msgID))
+	if !errors.Is(err, database.ErrNotFound) {
+		return v, err
+	}
+	// Legacy records have no expiry
+	return im.GetValue(ctx, LegacyIncomingWarpKey(sm, sourceChainID, msgID))
+}
+
+// warpReplayBucketKey returns the state key that lists the records that
+// expire in [bucket] (see [WarpReplayBucketDuration]).
+func warpReplayBucketKey(sm StateManager, bucket uint64) []byte {
+	p := sm.IncomingWarpPrefix()
+	k := make([]byte, 0, len(p)+consts.Uint64Len+consts.Uint16Len)
+	k = append(k, p...)
+	k = binary.BigEndian.AppendUint64(k, bucket)
+	return keys.EncodeChunks(k, MaxWarpReplayBucketChunks)
+}
+
+// warpReplayCursorKey returns the state key that stores the next and last
+// buckets of records to prune (if there are any).
+func warpReplayCursorKey(sm StateManager) []byte {
+	p := sm.IncomingWarpPrefix()
+	k := make([]byte, 0, len(p)+consts.Uint16Len)
+	k = append(k, p...)
+	return keys.EncodeChunks(k, WarpReplayCursorChunks)
+}
+
+// WarpReplayExpiry returns the timestamp after which a warp message sent at
+// [sent] can no longer be imported (or [math.MaxInt64] if it always can).
+// Its record is pruned after this, which can't allow the message to be
+// imported again.
+func WarpReplayExpiry(r Rules, sent int64) int64 {
+	retention := r.GetWarpReplayRetention()
+	if retention <= 0 || sent > math.MaxInt64-retention {
+		return math.MaxInt64
+	}
+	return sent + retention
+}
+
+// GetWarpReplay returns the expiry of the record of the warp message [msgID]
+// from [sourceChainID], if it was imported.
+func GetWarpReplay(
+	ctx context.Context,
+	sm StateManager,
+	im state.Immutable,
+	sourceChainID ids.ID,
+	msgID ids.ID,
+) (int64, bool, error) {
+	v, err := getWarpReplay(ctx, sm, im, sourceChainID, msgID)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	expiry, err := ParseWarpReplay(v)
+	if err != nil {
+		return 0, false, err
+	}
+	return expiry, true, nil
+}
+
+// ParseWarpReplay decodes the expiry stored at an [IncomingWarpKey]. Records
+// without an expiry never expire.
+func ParseWarpReplay(v []byte) (int64, error) {
+	switch len(v) {
+	case 0:
+		return math.MaxInt64, nil
+	case consts.Int64Len:
+		return int64(binary.BigEndian.Uint64(v)), nil
+	default:
+		return 0, fmt.Errorf("%w: warp replay record has %d bytes", ErrInvalidObject, len(v))
+	}
+}
+
+// warpReplayExpiry returns when the warp message of the transaction can no
+// longer be imported (see [WarpReplayExpiry]).
+func (t *Transaction) warpReplayExpiry(r Rules) int64 {
+	timestamper, ok := t.Action.(WarpTimestamper)
+	if !ok {
+		return math.MaxInt64
+	}
+	sent, ok := timestamper.WarpTimestamp(t.WarpMessage)
+	if !ok {
+		return math.MaxInt64
+	}
+	return WarpReplayExpiry(r, sent)
+}
+
+// verifyWarpReplay returns [ErrWarpMessageExpired] if the warp message of the
+// transaction is too old to be imported at [timestamp], or [ErrWarpReplay] if
+// it was already imported.
+func (t *Transaction) verifyWarpReplay(ctx context.Context, sm StateManager, r Rules, im state.Immutable, timestamp int64) error {
+	if t.WarpMessage == nil {
+		return nil
+	}
+	if expiry := t.warpReplayExpiry(r); timestamp > expiry {
+		return fmt.Errorf("%w: warpID=%s expiry=%d", ErrWarpMessageExpired, t.warpID, expiry)
+	}
+	_, err := getWarpReplay(ctx, sm, im, t.WarpMessage.SourceChainID, t.warpID)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	default:
+		return fmt.Errorf("%w: warpID=%s", ErrWarpReplay, t.warpID)
+	}
+}
+
+// recordWarpReplay records that the warp message of the transaction was
+// imported.
+func (t *Transaction) recordWarpReplay(ctx context.Context, sm StateManager, r Rules, mu state.Mutable) error {
+	k := IncomingWarpKey(sm, t.WarpMessage.SourceChainID, t.warpID)
+	v := binary.BigEndian.AppendUint64(nil, uint64(t.warpReplayExpiry(r)))
+	return mu.Insert(ctx, k, v)
+}
+
+// pruneWarpReplays lists the records of the warp messages imported by [txs]
+// in the bucket of when they expire (or a later one, if it is full) and
+// removes the records in the buckets that expired by [timestamp]. At most
+// [MaxWarpReplayPrunes] buckets are pruned in each block, so records may be
+// kept for longer than their expiry (which is always safe, as the messages
+// are rejected regardless).
+//
+// [im] must be the state that [ts] was executed on, so that the value of
+// any keys not modified by [ts] can be read.
+func pruneWarpReplays(
+	ctx context.Context,
+	r Rules,
+	sm StateManager,
+	im state.Immutable,
+	ts *tstate.TState,
+	timestamp int64,
+	txs []*Transaction,
+	results []*Result,
+) error {
+	storage := map[string][]byte{}
+	stateKeys := set.Set[string]{}
+	read := func(k []byte) ([]byte, error) {
+		sk := string(k)
+		if v, ok := storage[sk]; ok || stateKeys.Contains(sk) {
+			return v, nil
+		}
+		stateKeys.Add(sk)
+		v, err := im.GetValue(ctx, k)
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		storage[sk] = v
+		return v, nil
+	}
+
+	// Find the buckets that still have records to prune
+	cursorKey := warpReplayCursorKey(sm)
+	cursor, err := read(cursorKey)
+	if err != nil {
+		return err
+	}
+	var next, last uint64
+	pending := len(cursor) == consts.Uint64Len*2
+	if pending {
+		next = binary.BigEndian.Uint64(cursor)
+		last = binary.BigEndian.Uint64(cursor[consts.Uint64Len:])
+	}
+
+	// List the new records by when they expire
+	buckets := map[uint64][]byte{}
+	for i, tx := range txs {
+		if tx.WarpMessage == nil || !results[i].Success() {
+			continue
+		}
+		expiry := tx.warpReplayExpiry(r)
+		if expiry == math.MaxInt64 {
+			continue
+		}
+		// Messages that expired before [timestamp] are rejected, so this is
+		// never before [next]
+		bucket := uint64(expiry) / WarpReplayBucketDuration
+		for {
+			v, ok := buckets[bucket]
+			if !ok {
+				v, err = read(warpReplayBucketKey(sm, bucket))
+				if err != nil {
+					return err
+				}
+			}
+			if len(v)/warpReplayEntryLen < maxWarpReplayBucketEntries {
+				v = append(v[:len(v):len(v)], tx.WarpMessage.SourceChainID[:]...)
+				buckets[bucket] = append(v, tx.warpID[:]...)
+				break
+			}
+			buckets[bucket] = v
+			bucket++
+		}
+		if !pending {
+			next, last, pending = uint64(timestamp)/WarpReplayBucketDuration, bucket, true
+		}
+		if bucket > last {
+			last = bucket
+		}
+	}
+
+	// Remove the records in the buckets that expired
+	var removals [][]byte
+	for i := 0; pending && i < MaxWarpReplayPrunes && (next+1)*WarpReplayBucketDuration <= uint64(timestamp); i++ {
+		k := warpReplayBucketKey(sm, next)
+		v, err := read(k)
+		if err != nil {
+			return err
+		}
+		for j := 0; j+warpReplayEntryLen <= len(v); j += warpReplayEntryLen {
+			sourceChainID := ids.ID(v[j : j+ids.IDLen])
+			msgID := ids.ID(v[j+ids.IDLen : j+warpReplayEntryLen])
+			rk := IncomingWarpKey(sm, sourceChainID, msgID)
+			if _, err := read(rk); err != nil {
+				return err
+			}
+			removals = append(removals, rk)
+		}
+		delete(buckets, next)
+		removals = append(removals, k)
+		next++
+		pending = next <= last
+	}
+
+	tsv := ts.NewView(stateKeys, storage)
+	for bucket, v := range buckets {
+		if err := tsv.Insert(ctx, warpReplayBucketKey(sm, bucket), v); err != nil {
+			return err
+		}
+	}
+	for _, k := range removals {
+		if err := tsv.Remove(ctx, k); err != nil {
+			return err
+		}
+	}
+	if pending {
+		v := binary.BigEndian.AppendUint64(nil, next)
+		v = binary.BigEndian.AppendUint64(v, last)
+		if err := tsv.Insert(ctx, cursorKey, v); err != nil {
+			return err
+		}
+	} else if err := tsv.Remove(ctx, cursorKey); err != nil {
+		return err
+	}
+	tsv.Commit()
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+type memoryState map[string][]byte
+
+func (s memoryState) GetValue(_ context.Context, k []byte) ([]byte, error) {
+	v, ok := s[string(k)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s memoryState) Insert(_ context.Context, k []byte, v []byte) error {
+	s[string(k)] = v
+	return nil
+}
+
+func (s memoryState) Remove(_ context.Context, k []byte) error {
+	delete(s, string(k))
+	return nil
+}
+
+// commit applies the changes of [ts] to [s].
+func (s memoryState) commit(ts *tstate.TState) {
+	for k, v := range ts.Changes() {
+		if v.IsNothing() {
+			delete(s, k)
+			continue
+		}
+		s[k] = v.Value()
+	}
+}
+
+type testWarpManager struct {
+	StateManager
+}
+
+func (testWarpManager) IncomingWarpPrefix() []byte {
+	return []byte{0x0}
+}
+
+type testWarpAction struct {
+	Action
+
+	sent int64
+}
+
+func (a *testWarpAction) WarpTimestamp(*warp.Message) (int64, bool) {
+	return a.sent, true
+}
+
+func newWarpTx(action Action) *Transaction {
+	return &Transaction{
+		WarpMessage: &warp.Message{UnsignedMessage: warp.UnsignedMessage{SourceChainID: ids.GenerateTestID()}},
+		Action:      action,
+		warpID:      ids.GenerateTestID(),
+	}
+}
+
+func TestParseWarpReplay(t *testing.T) {
+	require := require.New(t)
+
+	expiry, err := ParseWarpReplay(nil)
+	require.NoError(err)
+	require.Equal(int64(math.MaxInt64), expiry)
+
+	expiry, err = ParseWarpReplay(binary.BigEndian.AppendUint64(nil, 10))
+	require.NoError(err)
+	require.Equal(int64(10), expiry)
+
+	_, err = ParseWarpReplay([]byte{1})
+	require.ErrorIs(err, ErrInvalidObject)
+}
+
+func TestWarpReplayExpiry(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetWarpReplayRetention().Return(int64(0))
+	require.Equal(int64(math.MaxInt64), WarpReplayExpiry(r, 10))
+
+	r.EXPECT().GetWarpReplayRetention().Return(int64(5)).Times(2)
+	require.Equal(int64(15), WarpReplayExpiry(r, 10))
+	require.Equal(int64(math.MaxInt64), WarpReplayExpiry(r, math.MaxInt64-1))
+}
+
+func TestPruneWarpReplays(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetWarpReplayRetention().Return(int64(60_000)).AnyTimes()
+	sm := testWarpManager{}
+	mem := memoryState{}
+	prune := func(timestamp int64, txs []*Transaction, results []*Result) {
+		ts := tstate.New(0)
+		require.NoError(pruneWarpReplays(ctx, r, sm, mem, ts, timestamp, txs, results))
+		mem.commit(ts)
+	}
+	record := func(tx *Transaction) {
+		k := IncomingWarpKey(sm, tx.WarpMessage.SourceChainID, tx.warpID)
+		mem[string(k)] = binary.BigEndian.AppendUint64(nil, uint64(tx.warpReplayExpiry(r)))
+	}
+	imported := func(tx *Transaction) bool {
+		_, ok, err := GetWarpReplay(ctx, sm, mem, tx.WarpMessage.SourceChainID, tx.warpID)
+		require.NoError(err)
+		return ok
+	}
+	success := &Result{Status: StatusSuccess}
+
+	// Only the records of messages that can expire are listed
+	expiring := newWarpTx(&testWarpAction{sent: 95_000})
+	permanent := newWarpTx(NewMockAction(ctrl))
+	failed := newWarpTx(&testWarpAction{sent: 95_000})
+	record(expiring)
+	record(permanent)
+	prune(
+		100_000,
+		[]*Transaction{expiring, permanent, failed},
+		[]*Result{success, success, {Status: StatusFailed}},
+	)
+	bucket, ok := mem[string(warpReplayBucketKey(sm, 15))]
+	require.True(ok)
+	require.Len(bucket, warpReplayEntryLen)
+	cursor := mem[string(warpReplayCursorKey(sm))]
+	require.Equal(uint64(10), binary.BigEndian.Uint64(cursor))
+	require.Equal(uint64(15), binary.BigEndian.Uint64(cursor[8:]))
+
+	// Imported messages are rejected until they expire
+	require.ErrorIs(expiring.verifyWarpReplay(ctx, sm, r, mem, 155_000), ErrWarpReplay)
+	require.ErrorIs(expiring.verifyWarpReplay(ctx, sm, r, mem, 155_001), ErrWarpMessageExpired)
+	require.ErrorIs(permanent.verifyWarpReplay(ctx, sm, r, mem, math.MaxInt64), ErrWarpReplay)
+	require.NoError(failed.verifyWarpReplay(ctx, sm, r, mem, 100_000))
+
+	// At most [MaxWarpReplayPrunes] buckets are pruned in each block
+	prune(159_999, nil, nil)
+	require.True(imported(expiring))
+	cursor = mem[string(warpReplayCursorKey(sm))]
+	require.Equal(uint64(10+MaxWarpReplayPrunes), binary.BigEndian.Uint64(cursor))
+
+	// Records are pruned once their bucket expires
+	prune(160_000, nil, nil)
+	require.False(imported(expiring))
+	require.True(imported(permanent))
+	require.NotContains(mem, string(warpReplayBucketKey(sm, 15)))
+	require.NotContains(mem, string(warpReplayCursorKey(sm)))
+	require.ErrorIs(expiring.verifyWarpReplay(ctx, sm, r, mem, 160_000), ErrWarpMessageExpired)
+}
+
+func TestPruneWarpReplaysFullBucket(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetWarpReplayRetention().Return(int64(60_000)).AnyTimes()
+	sm := testWarpManager{}
+	mem := memoryState{}
+
+	// Records that don't fit in the bucket of their expiry are listed in the
+	// next one (so they are pruned later)
+	txs := make([]*Transaction, maxWarpReplayBucketEntries+1)
+	results := make([]*Result, len(txs))
+	for i := range txs {
+		txs[i] = newWarpTx(&testWarpAction{sent: 95_000})
+		results[i] = &Result{Status: StatusSuccess}
+	}
+	ts := tstate.New(0)
+	require.NoError(pruneWarpReplays(ctx, r, sm, mem, ts, 100_000, txs, results))
+	mem.commit(ts)
+	require.Len(mem[string(warpReplayBucketKey(sm, 15))], maxWarpReplayBucketEntries*warpReplayEntryLen)
+	require.Len(mem[string(warpReplayBucketKey(sm, 16))], warpReplayEntryLen)
+	cursor := mem[string(warpReplayCursorKey(sm))]
+	require.Equal(uint64(16), binary.BigEndian.Uint64(cursor[8:]))
+}
+
+func TestLegacyWarpReplay(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetWarpReplayRetention().Return(int64(0)).AnyTimes()
+	sm := testWarpManager{}
+	mem := memoryState{}
+	tx := newWarpTx(NewMockAction(ctrl))
+	require.NoError(tx.verifyWarpReplay(ctx, sm, r, mem, 100_000))
+
+	// Messages imported before the upgrade are still rejected
+	legacy := LegacyIncomingWarpKey(sm, tx.WarpMessage.SourceChainID, tx.warpID)
+	require.NotEqual(IncomingWarpKey(sm, tx.WarpMessage.SourceChainID, tx.warpID), legacy)
+	mem[string(legacy)] = nil
+	require.ErrorIs(tx.verifyWarpReplay(ctx, sm, r, mem, 100_000), ErrWarpReplay)
+	expiry, ok, err := GetWarpReplay(ctx, sm, mem, tx.WarpMessage.SourceChainID, tx.warpID)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(int64(math.MaxInt64), expiry)
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+// BlockWitness proves the value (or absence) of every key read while
+// executing a block against the [StatefulBlock.StateRoot] of the block (see
+// [Rules.GetBlockWitnesses]).
+//
+// A node with no copy of state can execute the block against the
+// [WitnessState] returned by [BlockWitness.Verify] (see
+// [StatelessBlock.ExecuteWitness]). Computing the state root of the block
+// still requires the trie of the parent.
+type BlockWitness struct {
+	Proofs []*merkledb.Proof
+}
+
+type prover interface {
+	GetProof(ctx context.Context, key []byte) (*merkledb.Proof, error)
+}
+
+// NewBlockWitness generates a proof for each of [keys] from [view].
+func NewBlockWitness(ctx context.Context, view state.View, keys []string) (*BlockWitness, error) {
+	p, ok := view.(prover)
+	if !ok {
+		return nil, ErrWitnessUnsupported
+	}
+	w := &BlockWitness{Proofs: make([]*merkledb.Proof, len(keys))}
+	for i, k := range keys {
+		proof, err := p.GetProof(ctx, []byte(k))
+		if err != nil {
+			return nil, err
+		}
+		w.Proofs[i] = proof
+	}
+	return w, nil
+}
+
+func (w *BlockWitness) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.IntLen, consts.NetworkSizeLimit)
+	p.PackInt(len(w.Proofs))
+	for _, proof := range w.Proofs {
+		b, err := proto.Marshal(proof.ToProto())
+		if err != nil {
+			return nil, err
+		}
+		p.PackBytes(b)
+	}
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalBlockWitness(raw []byte) (*BlockWitness, error) {
+	p := codec.NewReader(raw, consts.NetworkSizeLimit)
+	count := p.UnpackInt(false)
+	w := &BlockWitness{Proofs: []*merkledb.Proof{}} // don't preallocate to avoid DoS
+	for i := 0; i < count && p.Err() == nil; i++ {
+		var b []byte
+		p.UnpackBytes(consts.NetworkSizeLimit, true, &b)
+		if p.Err() != nil {
+			break
+		}
+		var pbProof pb.Proof
+		if err := proto.Unmarshal(b, &pbProof); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWitness, err)
+		}
+		var proof merkledb.Proof
+		if err := proof.UnmarshalProto(&pbProof); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWitness, err)
+		}
+		w.Proofs = append(w.Proofs, &proof)
+	}
+	if !p.Empty() {
+		return nil, fmt.Errorf("%w: remaining=%d", ErrInvalidObject, len(raw)-p.Offset())
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Verify checks every proof in [w] against [root] and returns the proven
+// values.
+func (w *BlockWitness) Verify(
+	ctx context.Context,
+	root ids.ID,
+	branchFactor merkledb.BranchFactor,
+) (*WitnessState, error) {
+	tokenSize := merkledb.BranchFactorToTokenSize[branchFactor]
+	values := make(map[string]maybe.Maybe[[]byte], len(w.Proofs))
+	for _, proof := range w.Proofs {
+		if err := proof.Verify(ctx, root, tokenSize); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWitness, err)
+		}
+		k := string(proof.Key.Bytes())
+		if _, ok := values[k]; ok {
+			return nil, fmt.Errorf("%w: duplicate key %x", ErrInvalidWitness, k)
+		}
+		values[k] = proof.Value
+	}
+	return &WitnessState{values: values}, nil
+}
+
+var _ state.Immutable = (*WitnessState)(nil)
+
+// WitnessState serves the values proven by a [BlockWitness]. Reading a key
+// that is not in the witness returns [ErrKeyNotWitnessed].
+type WitnessState struct {
+	values map[string]maybe.Maybe[[]byte]
+}
+
+func (s *WitnessState) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	v, ok := s.values[string(key)]
+	if !ok {
+		return nil, ErrKeyNotWitnessed
+	}
+	if v.IsNothing() {
+		return nil, database.ErrNotFound
+	}
+	return v.Value(), nil
+}
+
+// Contains returns true if [key] is proven by the witness.
+func (s *WitnessState) Contains(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+// witnessRecorder records every key read from [View] (which may be read
+// concurrently during execution).
+type witnessRecorder struct {
+	state.View
+
+	l    sync.Mutex
+	keys set.Set[string]
+}
+
+func newWitnessRecorder(view state.View) *witnessRecorder {
+	return &witnessRecorder{View: view}
+}
+
+func (r *witnessRecorder) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	r.record(key)
+	return r.View.GetValue(ctx, key)
+}
+
+func (r *witnessRecorder) record(keys ...[]byte) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	for _, k := range keys {
+		r.keys.Add(string(k))
+	}
+}
+
+// Keys returns the recorded keys in sorted order.
+func (r *witnessRecorder) Keys() []string {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	keys := r.keys.List()
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// NameResolver returns the address [name] resolves to (like with the
+// resolver of the names module). False is returned if [name] doesn't
+// resolve to any address.
+type NameResolver func(ctx context.Context, name string) (codec.Address, bool, error)
+
+// SetNameResolver makes [PromptAddress] (and [ResolveAddress]) accept names
+// resolved by [r] wherever an address is expected.
+func (h *Handler) SetNameResolver(r NameResolver) {
+	h.names = r
+}
+
+// ResolveAddress parses [input] as an address or, if it isn't one, looks it
+// up as a saved alias (see [AddAlias]) and then (if a [NameResolver] is set)
+// resolves it as a name.
+func (h *Handler) ResolveAddress(ctx context.Context, input string) (codec.Address, error) {
+	addr, err := h.c.ParseAddress(input)
+	if err == nil {
+		return addr, nil
+	}
+	addr, ok, aerr := h.GetAlias(input)
+	if aerr != nil {
+		return codec.EmptyAddress, aerr
+	}
+	if ok {
+		utils.Outf("{{yellow}}alias %s:{{/}} %s\n", input, h.c.Address(addr))
+		return addr, nil
+	}
+	if h.names == nil {
+		return codec.EmptyAddress, err
+	}
+	addr, ok, rerr := h.names(ctx, input)
+	if rerr != nil {
+		return codec.EmptyAddress, rerr
+	}
+	if !ok {
+		return codec.EmptyAddress, fmt.Errorf("%w: %s", ErrUnknownName, input)
+	}
+	utils.Outf("{{yellow}}resolved %s:{{/}} %s\n", input, h.c.Address(addr))
+	return addr, nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// MaxAliasLen is the longest alias that can be saved.
+const MaxAliasLen = 64
+
+// ValidAlias returns nil if [alias] can be saved: it must be at most
+// [MaxAliasLen] bytes without any whitespace and must not itself be an
+// address.
+func (h *Handler) ValidAlias(alias string) error {
+	if len(alias) == 0 {
+		return ErrInputEmpty
+	}
+	if _, err := h.c.ParseAddress(alias); err == nil {
+		return fmt.Errorf("%w: %s is an address", ErrInvalidAlias, alias)
+	}
+	if len(alias) > MaxAliasLen {
+		return ErrInputTooLarge
+	}
+	if strings.IndexFunc(alias, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: %q contains whitespace", ErrInvalidAlias, alias)
+	}
+	return nil
+}
+
+// AddAlias saves [alias] as another name for [address], which can then be
+// entered anywhere the cli prompts for an address.
+func (h *Handler) AddAlias(alias string, address string) error {
+	if err := h.ValidAlias(alias); err != nil {
+		return err
+	}
+	addr, err := h.c.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+	if err := h.StoreAlias(alias, addr); err != nil {
+		return fmt.Errorf("%w: %s", err, alias)
+	}
+	utils.Outf("{{green}}added alias:{{/}} %s {{green}}=>{{/}} %s\n", alias, h.c.Address(addr))
+	return nil
+}
+
+func (h *Handler) RemoveAlias(alias string) error {
+	if err := h.DeleteAlias(alias); err != nil {
+		return err
+	}
+	utils.Outf("{{green}}removed alias:{{/}} %s\n", alias)
+	return nil
+}
+
+// aliasOutputs returns all saved aliases sorted by alias.
+func (h *Handler) aliasOutputs() ([]*AliasOutput, error) {
+	aliases, err := h.GetAliases()
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]*AliasOutput, 0, len(aliases))
+	for alias, addr := range aliases {
+		outputs = append(outputs, &AliasOutput{Alias: alias, Address: h.c.Address(addr)})
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].Alias < outputs[j].Alias
+	})
+	return outputs, nil
+}
+
+func (h *Handler) PrintAliases() error {
+	outputs, err := h.aliasOutputs()
+	if err != nil {
+		return err
+	}
+	if h.json {
+		return h.PrintJSON(outputs)
+	}
+	for _, output := range outputs {
+		utils.Outf("{{cyan}}%s:{{/}} %s\n", output.Alias, output.Address)
+	}
+	return nil
+}
+
+// ExportAliases writes all saved aliases to [path] (as a JSON array of
+// [AliasOutput]).
+func (h *Handler) ExportAliases(path string) error {
+	outputs, err := h.aliasOutputs()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(outputs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.SaveBytes(path, b); err != nil {
+		return err
+	}
+	utils.Outf("{{green}}exported %d aliases to:{{/}} %s\n", len(outputs), path)
+	return nil
+}
+
+// ImportAliases saves the aliases written to [path] by [ExportAliases].
+//
+// Aliases that are already saved for the same address are skipped. If any
+// alias is invalid or already saved for a different address, nothing is
+// imported.
+func (h *Handler) ImportAliases(path string) error {
+	b, err := utils.LoadBytes(path, -1)
+	if err != nil {
+		return err
+	}
+	var outputs []*AliasOutput
+	if err := json.Unmarshal(b, &outputs); err != nil {
+		return err
+	}
+	existing, err := h.GetAliases()
+	if err != nil {
+		return err
+	}
+	toStore := make([]*AliasOutput, 0, len(outputs))
+	for _, output := range outputs {
+		if err := h.ValidAlias(output.Alias); err != nil {
+			return err
+		}
+		addr, err := h.c.ParseAddress(output.Address)
+		if err != nil {
+			return fmt.Errorf("%w: alias %s", err, output.Alias)
+		}
+		prev, ok := existing[output.Alias]
+		switch {
+		case ok && prev == addr:
+			continue
+		case ok:
+			return fmt.Errorf("%w: %s is saved as %s", ErrDuplicate, output.Alias, h.c.Address(prev))
+		}
+		existing[output.Alias] = addr
+		toStore = append(toStore, output)
+	}
+	for _, output := range toStore {
+		if err := h.StoreAlias(output.Alias, existing[output.Alias]); err != nil {
+			return err
+		}
+	}
+	utils.Outf("{{green}}imported %d aliases from:{{/}} %s\n", len(toStore), path)
+	return nil
+}
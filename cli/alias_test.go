@@ -0,0 +1,166 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const testHRP = "test"
+
+type testController struct{}
+
+func (testController) DatabasePath() string { return "" }
+
+func (testController) Symbol() string { return "TKN" }
+
+func (testController) Decimals() uint8 { return 9 }
+
+func (testController) Address(addr codec.Address) string {
+	return codec.MustAddressBech32(testHRP, addr)
+}
+
+func (testController) ParseAddress(addr string) (codec.Address, error) {
+	return codec.ParseAddressBech32(testHRP, addr)
+}
+
+func newTestHandler() *Handler {
+	return &Handler{c: testController{}, db: memdb.New()}
+}
+
+func newTestAddress() (codec.Address, string) {
+	addr := codec.CreateAddress(0, ids.GenerateTestID())
+	return addr, codec.MustAddressBech32(testHRP, addr)
+}
+
+func TestValidAlias(t *testing.T) {
+	require := require.New(t)
+	h := newTestHandler()
+	_, saddr := newTestAddress()
+
+	tests := []struct {
+		alias string
+		err   error
+	}{
+		{"alice", nil},
+		{"alice.eth", nil},
+		{strings.Repeat("a", MaxAliasLen), nil},
+		{"", ErrInputEmpty},
+		{strings.Repeat("a", MaxAliasLen+1), ErrInputTooLarge},
+		{"alice bob", ErrInvalidAlias},
+		{"alice\t", ErrInvalidAlias},
+		{saddr, ErrInvalidAlias},
+	}
+	for _, tt := range tests {
+		require.ErrorIs(h.ValidAlias(tt.alias), tt.err, tt.alias)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	h := newTestHandler()
+	alice, salice := newTestAddress()
+	bob, sbob := newTestAddress()
+
+	require.NoError(h.AddAlias("bob", sbob))
+	require.NoError(h.AddAlias("alice", salice))
+	require.ErrorIs(h.AddAlias("alice", sbob), ErrDuplicate)
+	require.Error(h.AddAlias("carol", "carol"))
+	require.ErrorIs(h.AddAlias("carol dan", salice), ErrInvalidAlias)
+
+	// Aliases are accepted wherever an address is
+	addr, err := h.ResolveAddress(ctx, salice)
+	require.NoError(err)
+	require.Equal(alice, addr)
+	addr, err = h.ResolveAddress(ctx, "bob")
+	require.NoError(err)
+	require.Equal(bob, addr)
+	_, err = h.ResolveAddress(ctx, "carol")
+	require.Error(err)
+
+	// Names are only resolved if they aren't aliases
+	h.SetNameResolver(func(_ context.Context, name string) (codec.Address, bool, error) {
+		return alice, name == "alice" || name == "bob", nil
+	})
+	addr, err = h.ResolveAddress(ctx, "bob")
+	require.NoError(err)
+	require.Equal(bob, addr)
+	_, err = h.ResolveAddress(ctx, "carol")
+	require.ErrorIs(err, ErrUnknownName)
+
+	// Aliases are listed in order
+	out, text := captureOutput(t, func() { require.NoError(h.PrintAliases()) })
+	require.Empty(out)
+	require.Less(strings.Index(text, salice), strings.Index(text, sbob))
+	require.NoError(h.SetOutput(JSONOutput))
+	out, text = captureOutput(t, func() { require.NoError(h.PrintAliases()) })
+	require.NoError(h.SetOutput(TextOutput))
+	require.Empty(text)
+	require.JSONEq(`[{"alias":"alice","address":"`+salice+`"},{"alias":"bob","address":"`+sbob+`"}]`, out)
+
+	require.NoError(h.RemoveAlias("alice"))
+	require.ErrorIs(h.RemoveAlias("alice"), ErrUnknownAlias)
+	aliases, err := h.GetAliases()
+	require.NoError(err)
+	require.Equal(map[string]codec.Address{"bob": bob}, aliases)
+}
+
+func TestExportImportAliases(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	h := newTestHandler()
+	alice, salice := newTestAddress()
+	_, sbob := newTestAddress()
+	require.NoError(h.AddAlias("alice", salice))
+	require.NoError(h.AddAlias("bob", sbob))
+
+	path := filepath.Join(dir, "aliases.json")
+	require.NoError(h.ExportAliases(path))
+	b, err := utils.LoadBytes(path, -1)
+	require.NoError(err)
+	require.JSONEq(`[{"alias":"alice","address":"`+salice+`"},{"alias":"bob","address":"`+sbob+`"}]`, string(b))
+
+	// Importing aliases that are already saved is a no-op
+	other := newTestHandler()
+	require.NoError(other.AddAlias("alice", salice))
+	require.NoError(other.ImportAliases(path))
+	require.NoError(other.ImportAliases(path))
+	expected, err := h.GetAliases()
+	require.NoError(err)
+	aliases, err := other.GetAliases()
+	require.NoError(err)
+	require.Equal(expected, aliases)
+
+	// Nothing is imported if any alias conflicts or is invalid
+	for name, aliases := range map[string][]*AliasOutput{
+		"conflict": {{Alias: "carol", Address: salice}, {Alias: "alice", Address: sbob}},
+		"invalid":  {{Alias: "carol", Address: salice}, {Alias: "dan dan", Address: salice}},
+		"address":  {{Alias: "carol", Address: salice}, {Alias: "dan", Address: "dan"}},
+	} {
+		b, err := json.Marshal(aliases)
+		require.NoError(err, name)
+		path := filepath.Join(dir, name+".json")
+		require.NoError(utils.SaveBytes(path, b), name)
+		require.Error(other.ImportAliases(path), name)
+		_, ok, err := other.GetAlias("carol")
+		require.NoError(err, name)
+		require.False(ok, name)
+	}
+	addr, ok, err := other.GetAlias("alice")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(alice, addr)
+}
@@ -5,6 +5,7 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -17,7 +18,6 @@ import (
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/consts"
-	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/ava-labs/hypersdk/window"
@@ -181,6 +181,13 @@ func (h *Handler) PrintChainInfo() error {
 	if err != nil {
 		return err
 	}
+	if h.json {
+		return h.PrintJSON(&ChainInfoOutput{
+			NetworkID: networkID,
+			SubnetID:  subnetID,
+			ChainID:   chainID,
+		})
+	}
 	utils.Outf(
 		"{{cyan}}networkID:{{/}} %d {{cyan}}subnetID:{{/}} %s {{cyan}}chainID:{{/}} %s",
 		networkID,
@@ -190,7 +197,10 @@ func (h *Handler) PrintChainInfo() error {
 	return nil
 }
 
-func (h *Handler) WatchChain(hideTxs bool, getParser func(string, uint32, ids.ID) (chain.Parser, error), handleTx func(*chain.Transaction, *chain.Result)) error {
+// WatchChain prints the blocks accepted by a chain and, unless [hideTxs], their
+// transactions (formatted by [handleTx] or, if [jsonTxs], in their canonical
+// JSON encoding).
+func (h *Handler) WatchChain(hideTxs bool, jsonTxs bool, getParser func(string, uint32, ids.ID) (chain.Parser, error), handleTx func(*chain.Transaction, *chain.Result)) error {
 	ctx := context.Background()
 	chainID, uris, err := h.PromptChain("select chainID", nil)
 	if err != nil {
@@ -209,7 +219,14 @@ func (h *Handler) WatchChain(hideTxs bool, getParser func(string, uint32, ids.ID
 	if err != nil {
 		return err
 	}
-	scli, err := rpc.NewWebSocketClient(uris[0], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize) // we write the max read
+	wsConfig := rpc.DefaultReconnectConfig()
+	wsConfig.OnReconnect = func(err error) {
+		utils.Outf("{{orange}}reconnected to %s:{{/}} %v\n", uris[0], err)
+	}
+	wsConfig.OnGap = func(gap *rpc.BlockGap) {
+		utils.Outf("{{orange}}missed blocks:{{/}} %d-%d\n", gap.From, gap.To)
+	}
+	scli, err := rpc.NewReconnectingWebSocketClient(uris[0], wsConfig)
 	if err != nil {
 		return err
 	}
@@ -281,8 +298,34 @@ func (h *Handler) WatchChain(hideTxs bool, getParser func(string, uint32, ids.ID
 			continue
 		}
 		for i, tx := range blk.Txs {
+			if jsonTxs {
+				if err := printTxJSON(parser, tx, results[i]); err != nil {
+					return err
+				}
+				continue
+			}
 			handleTx(tx, results[i])
 		}
 	}
 	return nil
 }
+
+func printTxJSON(parser chain.Parser, tx *chain.Transaction, result *chain.Result) error {
+	txJSON, err := chain.NewTransactionJSON(parser, tx)
+	if err != nil {
+		return err
+	}
+	resultJSON, err := chain.NewTypedResultJSON(parser, tx, result)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(&struct {
+		Tx     *chain.TransactionJSON `json:"tx"`
+		Result *chain.ResultJSON      `json:"result"`
+	}{txJSON, resultJSON})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -12,6 +12,13 @@ type Handler struct {
 	c Controller
 
 	db database.Database
+
+	// json is true if results are printed as JSON (see [JSONOutput])
+	json bool
+
+	// names (if set) resolves inputs of [PromptAddress] that aren't
+	// addresses (see [SetNameResolver])
+	names NameResolver
 }
 
 func New(c Controller) (*Handler, error) {
@@ -19,5 +26,5 @@ func New(c Controller) (*Handler, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Handler{c, db}, nil
+	return &Handler{c: c, db: db}, nil
 }
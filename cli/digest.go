@@ -0,0 +1,90 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/exp/maps"
+
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+// DigestState prints the [vm.StateDigest] of the state stored by a node in
+// [chainDataDir] (at its last accepted block). The node must be stopped (or
+// [chainDataDir] copied) because its databases are opened directly.
+//
+// If [out] is set, the full digest (including the summaries of the VM) is
+// written to it as JSON.
+func (*Handler) DigestState(
+	newVM func() *vm.VM,
+	chainDataDir string,
+	genesisBytes []byte,
+	networkID uint32,
+	chainID ids.ID,
+	out string,
+) error {
+	ctx := context.Background()
+	if _, err := os.Stat(chainDataDir); err != nil {
+		return err
+	}
+	v, err := initializeOfflineVM(ctx, newVM, chainDataDir, genesisBytes, networkID, chainID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := v.Shutdown(context.Background()); err != nil {
+			utils.Outf("{{red}}unable to shutdown vm:{{/}} %v\n", err)
+		}
+	}()
+
+	utils.Outf("{{green}}walking state of %s{{/}}\n", chainDataDir)
+	digest, err := v.StateDigest(ctx)
+	if err != nil {
+		return err
+	}
+	utils.Outf(
+		"{{green}}height:{{/}}%d {{green}}block:{{/}}%s {{green}}root:{{/}}%s\n",
+		digest.Height,
+		digest.BlockID,
+		digest.Root,
+	)
+	utils.Outf(
+		"{{green}}digest:{{/}}%s {{green}}keys:{{/}}%d {{green}}bytes:{{/}}%d\n",
+		digest.Digest,
+		digest.Keys,
+		digest.Bytes,
+	)
+	for _, prefix := range digest.Prefixes {
+		utils.Outf(
+			"  {{yellow}}prefix:{{/}}0x%02x {{yellow}}digest:{{/}}%s {{yellow}}keys:{{/}}%d {{yellow}}bytes:{{/}}%d\n",
+			prefix.Prefix,
+			prefix.Digest,
+			prefix.Keys,
+			prefix.Bytes,
+		)
+	}
+	names := maps.Keys(digest.Summaries)
+	sort.Strings(names)
+	for _, name := range names {
+		b, err := json.Marshal(digest.Summaries[name])
+		if err != nil {
+			return err
+		}
+		utils.Outf("  {{yellow}}%s:{{/}} %s\n", name, b)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	b, err := json.MarshalIndent(digest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, b, fsModeWrite)
+}
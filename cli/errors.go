@@ -15,4 +15,11 @@ var (
 	ErrNoChains            = errors.New("no available chains")
 	ErrNoKeys              = errors.New("no available keys")
 	ErrTxFailed            = errors.New("tx failed on-chain")
+	ErrUnknownProfile      = errors.New("unknown spam profile")
+	ErrUnsupportedVersion  = errors.New("unsupported version")
+	ErrDigestMismatch      = errors.New("does not match digest")
+	ErrUnknownOutput       = errors.New("unknown output format")
+	ErrUnknownName         = errors.New("name does not resolve to an address")
+	ErrUnknownAlias        = errors.New("unknown alias")
+	ErrInvalidAlias        = errors.New("invalid alias")
 )
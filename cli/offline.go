@@ -0,0 +1,220 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// OfflineTxVersion is the version of the [UnsignedTx] and [SignedTx]
+// encodings. It must be incremented whenever either encoding changes in a
+// way that older versions of the cli can't read.
+const OfflineTxVersion = 1
+
+// UnsignedTx is a transaction that was built on a networked host and still
+// needs to be signed (possibly on a host that is never connected to the
+// network).
+//
+// [Digest] is the only field that is signed. All other fields are
+// informational. The base fields are checked against [Digest] before signing
+// and signers should only ever be shown the action decoded from [Digest]
+// (never [Action]) so that the informational fields can't be used to
+// mislead them.
+type UnsignedTx struct {
+	Version uint8  `json:"version"`
+	ChainID ids.ID `json:"chainID"`
+	Actor   string `json:"actor"`
+	Expiry  int64  `json:"expiry"`
+
+	// Fee quote used to set [MaxFee]
+	UnitPrices chain.Dimensions `json:"unitPrices"`
+	MaxUnits   chain.Dimensions `json:"maxUnits"`
+	MaxFee     uint64           `json:"maxFee"`
+
+	Action json.RawMessage `json:"action"`
+	Digest string          `json:"digest"` // hex
+}
+
+// SignedTx is a signed transaction that is ready to be broadcast.
+type SignedTx struct {
+	Version uint8  `json:"version"`
+	ChainID ids.ID `json:"chainID"`
+	TxID    ids.ID `json:"txID"`
+	Bytes   string `json:"bytes"` // hex
+}
+
+// NewUnsignedTx prepares [tx] (which must not be signed) to be signed by
+// [actor]. [unitPrices] and [maxUnits] are the fee quote used to populate
+// [tx.Base.MaxFee].
+func NewUnsignedTx(
+	tx *chain.Transaction,
+	actor string,
+	unitPrices chain.Dimensions,
+	maxUnits chain.Dimensions,
+) (*UnsignedTx, error) {
+	digest, err := tx.Digest()
+	if err != nil {
+		return nil, err
+	}
+	action, err := json.Marshal(tx.Action)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedTx{
+		Version:    OfflineTxVersion,
+		ChainID:    tx.Base.ChainID,
+		Actor:      actor,
+		Expiry:     tx.Base.Timestamp,
+		UnitPrices: unitPrices,
+		MaxUnits:   maxUnits,
+		MaxFee:     tx.Base.MaxFee,
+		Action:     action,
+		Digest:     hex.EncodeToString(digest),
+	}, nil
+}
+
+// Tx parses [Digest] and ensures it matches the informational base fields.
+func (u *UnsignedTx) Tx(actionRegistry chain.ActionRegistry) (*chain.Transaction, error) {
+	if u.Version != OfflineTxVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, u.Version)
+	}
+	digest, err := hex.DecodeString(u.Digest)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := chain.UnmarshalUnsignedTx(digest, actionRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Base.ChainID != u.ChainID || tx.Base.Timestamp != u.Expiry || tx.Base.MaxFee != u.MaxFee {
+		return nil, ErrDigestMismatch
+	}
+	return tx, nil
+}
+
+// Sign signs the transaction with [factory].
+func (u *UnsignedTx) Sign(
+	factory chain.AuthFactory,
+	actionRegistry chain.ActionRegistry,
+	authRegistry chain.AuthRegistry,
+) (*SignedTx, error) {
+	tx, err := u.Tx(actionRegistry)
+	if err != nil {
+		return nil, err
+	}
+	stx, err := tx.Sign(factory, actionRegistry, authRegistry)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTx{
+		Version: OfflineTxVersion,
+		ChainID: u.ChainID,
+		TxID:    stx.ID(),
+		Bytes:   hex.EncodeToString(stx.Bytes()),
+	}, nil
+}
+
+// Tx parses and returns the signed transaction.
+func (s *SignedTx) Tx(
+	actionRegistry chain.ActionRegistry,
+	authRegistry chain.AuthRegistry,
+) (*chain.Transaction, error) {
+	if s.Version != OfflineTxVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version)
+	}
+	txBytes, err := hex.DecodeString(s.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	p := codec.NewReader(txBytes, consts.NetworkSizeLimit)
+	tx, err := chain.UnmarshalTx(p, actionRegistry, authRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, chain.ErrInvalidObject
+	}
+	if tx.ID() != s.TxID || tx.Base.ChainID != s.ChainID {
+		return nil, ErrDigestMismatch
+	}
+	return tx, nil
+}
+
+// BroadcastTx submits the signed transaction stored at [path] to the chain
+// it was built for and waits for it to be accepted.
+func (h *Handler) BroadcastTx(
+	path string,
+	actionRegistry chain.ActionRegistry,
+	authRegistry chain.AuthRegistry,
+) error {
+	ctx := context.Background()
+	var stx SignedTx
+	if err := LoadJSON(path, &stx); err != nil {
+		return err
+	}
+	tx, err := stx.Tx(actionRegistry, authRegistry)
+	if err != nil {
+		return err
+	}
+	uris, err := h.GetChain(stx.ChainID)
+	if err != nil {
+		return err
+	}
+	if len(uris) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoChains, stx.ChainID)
+	}
+	utils.Outf("{{yellow}}uri:{{/}} %s\n", uris[0])
+	scli, err := rpc.NewWebSocketClient(uris[0], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+	if err != nil {
+		return err
+	}
+	defer scli.Close()
+	if err := scli.RegisterTx(tx); err != nil {
+		return err
+	}
+	for {
+		txID, dErr, result, err := scli.ListenTx(ctx)
+		if err != nil {
+			return err
+		}
+		if txID != tx.ID() {
+			continue
+		}
+		if dErr != nil {
+			return dErr
+		}
+		h.PrintStatus(txID, result.Status)
+		return nil
+	}
+}
+
+// SaveJSON writes the indented JSON encoding of [v] to [path].
+func SaveJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.SaveBytes(path, b)
+}
+
+// LoadJSON reads the JSON encoded file at [path] into [v].
+func LoadJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
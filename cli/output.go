@@ -0,0 +1,106 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/onsi/ginkgo/v2/formatter"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	// TextOutput prints human-readable (colored) text to stdout.
+	TextOutput = "text"
+
+	// JSONOutput prints a single JSON document per result to stdout. All
+	// human-readable text (including prompts) is written to stderr instead.
+	//
+	// The structures printed in this mode (e.g. [TxOutput]) are part of the
+	// cli's public interface and fields are only ever added to them.
+	JSONOutput = "json"
+)
+
+// promptStdout is where prompts are written (the default of stdout is used if
+// nil).
+var promptStdout io.WriteCloser
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// SetOutput configures how results are printed. [format] must be either
+// [TextOutput] or [JSONOutput]. It should be called before anything is
+// printed.
+func (h *Handler) SetOutput(format string) error {
+	switch format {
+	case TextOutput:
+		h.json = false
+		promptStdout = nil
+		utils.SetOutfWriter(formatter.ColorableStdOut)
+	case JSONOutput:
+		h.json = true
+		promptStdout = nopWriteCloser{os.Stderr}
+		utils.SetOutfWriter(os.Stderr)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOutput, format)
+	}
+	return nil
+}
+
+// JSON returns true if results should be printed with [PrintJSON].
+func (h *Handler) JSON() bool {
+	return h.json
+}
+
+// PrintJSON writes [v] to stdout as indented JSON.
+func (*Handler) PrintJSON(v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(b))
+	return err
+}
+
+// TxOutput is printed for every transaction issued by the cli.
+type TxOutput struct {
+	TxID    ids.ID       `json:"txID"`
+	Success bool         `json:"success"`
+	Status  chain.Status `json:"status"`
+}
+
+// BalanceOutput is printed for every balance looked up by the cli. [Balance]
+// is denominated in the smallest unit of [Asset] (use [Decimals] to format
+// it).
+type BalanceOutput struct {
+	URI      string `json:"uri"`
+	Address  string `json:"address"`
+	Asset    ids.ID `json:"asset"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	Balance  uint64 `json:"balance"`
+}
+
+// AliasOutput is printed for every alias listed by the cli. Exported
+// aliases are a JSON array of them.
+type AliasOutput struct {
+	Alias   string `json:"alias"`
+	Address string `json:"address"`
+}
+
+// ChainInfoOutput is printed when looking up the info of a chain.
+type ChainInfoOutput struct {
+	NetworkID uint32 `json:"networkID"`
+	SubnetID  ids.ID `json:"subnetID"`
+	ChainID   ids.ID `json:"chainID"`
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/onsi/ginkgo/v2/formatter"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// captureOutput returns everything [f] writes to stdout (where JSON results
+// are printed) and with [utils.Outf] (where text is printed).
+func captureOutput(t *testing.T, f func()) (string, string) {
+	require := require.New(t)
+
+	r, w, err := os.Pipe()
+	require.NoError(err)
+	stdout := os.Stdout
+	os.Stdout = w
+	var text bytes.Buffer
+	utils.SetOutfWriter(&text)
+	defer func() {
+		os.Stdout = stdout
+		utils.SetOutfWriter(formatter.ColorableStdOut)
+	}()
+
+	f()
+	require.NoError(w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(err)
+	return string(out), text.String()
+}
+
+func TestSetOutput(t *testing.T) {
+	require := require.New(t)
+	h := &Handler{}
+
+	tests := []struct {
+		format string
+		json   bool
+		err    error
+	}{
+		{TextOutput, false, nil},
+		{JSONOutput, true, nil},
+		{"yaml", true, ErrUnknownOutput}, // keeps the previous format
+		{"", true, ErrUnknownOutput},
+		{TextOutput, false, nil},
+	}
+	for _, tt := range tests {
+		require.ErrorIs(h.SetOutput(tt.format), tt.err, tt.format)
+		require.Equal(tt.json, h.JSON(), tt.format)
+	}
+
+	// Switching back to text writes prompts to stdout again
+	require.Nil(promptStdout)
+}
+
+func TestPrintStatus(t *testing.T) {
+	require := require.New(t)
+	h := &Handler{}
+	txID := ids.GenerateTestID()
+
+	// Text is colored, so we only check what is printed
+	out, text := captureOutput(t, func() { h.PrintStatus(txID, chain.StatusSuccess) })
+	require.Empty(out)
+	require.Contains(text, "txID:")
+	require.Contains(text, txID.String())
+	require.NotContains(text, "status:")
+	out, text = captureOutput(t, func() { h.PrintStatus(txID, chain.StatusFailed) })
+	require.Empty(out)
+	require.Contains(text, txID.String())
+	require.Contains(text, chain.StatusFailed.String())
+
+	// JSON is written to stdout (and nothing else is)
+	require.NoError(h.SetOutput(JSONOutput))
+	defer func() {
+		require.NoError(h.SetOutput(TextOutput))
+	}()
+	for _, status := range []chain.Status{chain.StatusSuccess, chain.StatusFailed} {
+		out, text = captureOutput(t, func() { h.PrintStatus(txID, status) })
+		require.Empty(text, status.String())
+		var printed TxOutput
+		require.NoError(json.Unmarshal([]byte(out), &printed), status.String())
+		require.Equal(TxOutput{TxID: txID, Success: status == chain.StatusSuccess, Status: status}, printed)
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	require := require.New(t)
+	h := &Handler{}
+
+	// Fields of printed structures are part of the cli's interface
+	id := ids.ID{1}
+	tests := []struct {
+		output   any
+		expected string
+	}{
+		{
+			&TxOutput{TxID: id, Success: false, Status: chain.StatusFailed},
+			`{"txID":"` + id.String() + `","success":false,"status":0}`,
+		},
+		{
+			&BalanceOutput{URI: "uri", Address: "addr", Asset: id, Symbol: "TKN", Decimals: 9, Balance: 10},
+			`{"uri":"uri","address":"addr","asset":"` + id.String() + `","symbol":"TKN","decimals":9,"balance":10}`,
+		},
+		{
+			&AliasOutput{Alias: "alice", Address: "addr"},
+			`{"alias":"alice","address":"addr"}`,
+		},
+		{
+			&ChainInfoOutput{NetworkID: 5, SubnetID: id, ChainID: id},
+			`{"networkID":5,"subnetID":"` + id.String() + `","chainID":"` + id.String() + `"}`,
+		},
+	}
+	for _, tt := range tests {
+		out, text := captureOutput(t, func() {
+			require.NoError(h.PrintJSON(tt.output))
+		})
+		require.Empty(text)
+		require.JSONEq(tt.expected, out)
+
+		// Results are indented and end with a newline
+		require.Contains(out, "\n  \"")
+		require.Equal(byte('\n'), out[len(out)-1])
+	}
+}
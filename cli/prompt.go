@@ -4,6 +4,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,18 +13,27 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/hd"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/manifoldco/promptui"
 )
 
 func (h *Handler) PromptAddress(label string) (codec.Address, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
 			}
 			_, err := h.c.ParseAddress(input)
+			if err == nil || h.names != nil {
+				// Names are only resolved once submitted
+				return nil
+			}
+			if _, ok, aerr := h.GetAlias(input); ok || aerr != nil {
+				return aerr
+			}
 			return err
 		},
 	}
@@ -32,12 +42,13 @@ func (h *Handler) PromptAddress(label string) (codec.Address, error) {
 		return codec.EmptyAddress, err
 	}
 	recipient = strings.TrimSpace(recipient)
-	return h.c.ParseAddress(recipient)
+	return h.ResolveAddress(context.Background(), recipient)
 }
 
 func (*Handler) PromptString(label string, min int, max int) (string, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) < min {
 				return ErrInputEmpty
@@ -55,6 +66,36 @@ func (*Handler) PromptString(label string, min int, max int) (string, error) {
 	return strings.TrimSpace(text), err
 }
 
+// PromptSeed prompts for a mnemonic and its (optional) passphrase and returns
+// the seed that keys can be derived from.
+func (*Handler) PromptSeed() ([]byte, error) {
+	promptText := promptui.Prompt{
+		Stdout: promptStdout,
+		Label:  "mnemonic",
+		Mask:   '*',
+		Validate: func(input string) error {
+			if _, err := hd.NewSeed(input, ""); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	mnemonic, err := promptText.Run()
+	if err != nil {
+		return nil, err
+	}
+	promptText = promptui.Prompt{
+		Stdout: promptStdout,
+		Label:  "passphrase (optional)",
+		Mask:   '*',
+	}
+	passphrase, err := promptText.Run()
+	if err != nil {
+		return nil, err
+	}
+	return hd.NewSeed(mnemonic, passphrase)
+}
+
 func (h *Handler) PromptAsset(label string, allowNative bool) (ids.ID, error) {
 	symbol := h.c.Symbol()
 	text := fmt.Sprintf("%s (use %s for native token)", label, symbol)
@@ -62,7 +103,8 @@ func (h *Handler) PromptAsset(label string, allowNative bool) (ids.ID, error) {
 		text = label
 	}
 	promptText := promptui.Prompt{
-		Label: text,
+		Stdout: promptStdout,
+		Label:  text,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -99,7 +141,8 @@ func (*Handler) PromptAmount(
 	f func(input uint64) error,
 ) (uint64, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -130,7 +173,8 @@ func (*Handler) PromptInt(
 	max int,
 ) (int, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -162,7 +206,8 @@ func (*Handler) PromptChoice(label string, max int) (int, error) {
 		return 0, nil
 	}
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -186,7 +231,8 @@ func (*Handler) PromptChoice(label string, max int) (int, error) {
 
 func (*Handler) PromptTime(label string) (int64, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -204,7 +250,8 @@ func (*Handler) PromptTime(label string) (int64, error) {
 
 func (*Handler) PromptContinue() (bool, error) {
 	promptText := promptui.Prompt{
-		Label: "continue (y/n)",
+		Stdout: promptStdout,
+		Label:  "continue (y/n)",
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -230,7 +277,8 @@ func (*Handler) PromptContinue() (bool, error) {
 
 func (*Handler) PromptBool(label string) (bool, error) {
 	promptText := promptui.Prompt{
-		Label: fmt.Sprintf("%s (y/n)", label),
+		Stdout: promptStdout,
+		Label:  fmt.Sprintf("%s (y/n)", label),
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -255,7 +303,8 @@ func (*Handler) PromptBool(label string) (bool, error) {
 
 func (*Handler) PromptID(label string) (ids.ID, error) {
 	promptText := promptui.Prompt{
-		Label: label,
+		Stdout: promptStdout,
+		Label:  label,
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return ErrInputEmpty
@@ -318,32 +367,34 @@ func (h *Handler) PromptChain(label string, excluded set.Set[ids.ID]) (ids.ID, [
 	return chainID, chains[chainID], nil
 }
 
-func (*Handler) PrintStatus(txID ids.ID, success bool) {
-	status := "❌"
+func (h *Handler) PrintStatus(txID ids.ID, status chain.Status) {
+	success := status == chain.StatusSuccess
+	if h.json {
+		if err := h.PrintJSON(&TxOutput{TxID: txID, Success: success, Status: status}); err != nil {
+			utils.Outf("{{red}}unable to print tx:{{/}} %v\n", err)
+		}
+		return
+	}
 	if success {
-		status = "✅"
+		utils.Outf("✅ {{yellow}}txID:{{/}} %s\n", txID)
+		return
 	}
-	utils.Outf("%s {{yellow}}txID:{{/}} %s\n", status, txID)
+	utils.Outf("❌ {{yellow}}txID:{{/}} %s {{yellow}}status:{{/}} %s\n", txID, status)
 }
 
 func PrintUnitPrices(d chain.Dimensions) {
-	utils.Outf(
-		"{{cyan}}unit prices{{/}} {{yellow}}bandwidth:{{/}} %d {{yellow}}compute:{{/}} %d {{yellow}}storage(read):{{/}} %d {{yellow}}storage(allocate):{{/}} %d {{yellow}}storage(write):{{/}} %d\n",
-		d[chain.Bandwidth],
-		d[chain.Compute],
-		d[chain.StorageRead],
-		d[chain.StorageAllocate],
-		d[chain.StorageWrite],
-	)
+	var sb strings.Builder
+	sb.WriteString("{{cyan}}unit prices{{/}}")
+	for i := chain.Dimension(0); int(i) < chain.FeeDimensions(); i++ {
+		fmt.Fprintf(&sb, " {{yellow}}%s:{{/}} %d", i, d[i])
+	}
+	utils.Outf(sb.String() + "\n")
 }
 
 func ParseDimensions(d chain.Dimensions) string {
-	return fmt.Sprintf(
-		"bandwidth=%d compute=%d storage(read)=%d storage(allocate)=%d storage(write)=%d",
-		d[chain.Bandwidth],
-		d[chain.Compute],
-		d[chain.StorageRead],
-		d[chain.StorageAllocate],
-		d[chain.StorageWrite],
-	)
+	values := make([]string, chain.FeeDimensions())
+	for i := range values {
+		values[i] = fmt.Sprintf("%s=%d", chain.Dimension(i), d[i])
+	}
+	return strings.Join(values, " ")
 }
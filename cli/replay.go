@@ -0,0 +1,216 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/pebble"
+	"github.com/ava-labs/hypersdk/storage"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+// Offline VMs never build or gossip blocks.
+const offlineVMConfig = `{"testMode":true}`
+
+// ReplayBlocks re-executes the blocks stored by a node in [chainDataDir]
+// against a fresh state created from [genesisBytes] and verifies the state
+// root of every block. The node must be stopped (or [chainDataDir] copied)
+// because its block database is opened directly.
+//
+// If [showUnits] is set, the units consumed by each transaction are printed
+// in addition to the summary of each block. If [traceDir] is set, the
+// [chain.BlockTrace] of each block is written to it as <height>.json.
+func (*Handler) ReplayBlocks(
+	newVM func() *vm.VM,
+	chainDataDir string,
+	genesisBytes []byte,
+	networkID uint32,
+	chainID ids.ID,
+	start uint64,
+	end uint64,
+	showUnits bool,
+	traceDir string,
+) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			utils.Outf("{{yellow}}exiting replay{{/}}\n")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Open stored blocks
+	blockPath := storage.BlockPath(chainDataDir)
+	if _, err := os.Stat(blockPath); err != nil {
+		return err
+	}
+	source, _, err := pebble.New(blockPath, pebble.NewDefaultConfig())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	// Initialize fresh state
+	dataDir, err := os.MkdirTemp("", "replay-chainData")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dataDir)
+	v, err := initializeOfflineVM(ctx, newVM, dataDir, genesisBytes, networkID, chainID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := v.Shutdown(context.Background()); err != nil {
+			utils.Outf("{{red}}unable to shutdown replay vm:{{/}} %v\n", err)
+		}
+	}()
+	v.ForceReady()
+
+	// Replay blocks
+	if len(traceDir) > 0 {
+		if err := os.MkdirAll(traceDir, 0o755); err != nil {
+			return err
+		}
+		ctx = chain.WithBlockTrace(ctx)
+	}
+	utils.Outf("{{green}}replaying blocks %d-%d from %s{{/}}\n", start, end, chainDataDir)
+	var (
+		replayStart = time.Now()
+		blocks      int
+		txs         int
+		execution   time.Duration
+	)
+	err = v.Replay(ctx, source, start, end, func(r *vm.ReplayedBlock) error {
+		blk := r.Block
+		consumed := chain.Dimensions{}
+		for _, result := range r.Results {
+			nconsumed, err := chain.Add(consumed, result.Consumed)
+			if err != nil {
+				return err
+			}
+			consumed = nconsumed
+		}
+		utils.Outf(
+			"{{green}}height:{{/}}%d {{green}}txs:{{/}}%d {{green}}root:{{/}}%s {{green}}units consumed:{{/}} [%s] {{green}}unit prices:{{/}} [%s] [{{green}}verify:{{/}}%v {{green}}accept:{{/}}%v]\n",
+			blk.Hght,
+			len(blk.Txs),
+			r.Root,
+			ParseDimensions(consumed),
+			ParseDimensions(r.UnitPrices),
+			r.Verify,
+			r.Accept,
+		)
+		if showUnits {
+			for i, tx := range blk.Txs {
+				result := r.Results[i]
+				utils.Outf(
+					"  {{yellow}}tx:{{/}}%s {{yellow}}status:{{/}}%s {{yellow}}units consumed:{{/}} [%s] {{yellow}}fee:{{/}}%d\n",
+					tx.ID(),
+					result.Status,
+					ParseDimensions(result.Consumed),
+					result.Fee,
+				)
+			}
+		}
+		if len(traceDir) > 0 {
+			if err := writeBlockTrace(traceDir, r.Trace); err != nil {
+				return err
+			}
+		}
+		blocks++
+		txs += len(blk.Txs)
+		execution += r.Verify + r.Accept
+		return nil
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: replay failed", err)
+	}
+	var tps float64
+	if execution > 0 {
+		tps = float64(txs) / execution.Seconds()
+	}
+	utils.Outf(
+		"{{green}}replayed %d blocks (%d txs) in %v{{/}} [{{green}}execution:{{/}}%v {{green}}TPS:{{/}}%.2f]\n",
+		blocks,
+		txs,
+		time.Since(replayStart),
+		execution,
+		tps,
+	)
+	return nil
+}
+
+// initializeOfflineVM initializes a VM that is never connected to the network
+// on the chain data in [chainDataDir] (which is created from [genesisBytes]
+// if empty).
+func initializeOfflineVM(
+	ctx context.Context,
+	newVM func() *vm.VM,
+	chainDataDir string,
+	genesisBytes []byte,
+	networkID uint32,
+	chainID ids.ID,
+) (*vm.VM, error) {
+	sk, err := bls.NewSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	snowCtx := &snow.Context{
+		NetworkID:      networkID,
+		SubnetID:       ids.Empty,
+		ChainID:        chainID,
+		NodeID:         ids.EmptyNodeID,
+		Log:            logging.NoLog{},
+		ChainDataDir:   chainDataDir,
+		Metrics:        metrics.NewOptionalGatherer(),
+		PublicKey:      bls.PublicFromSecretKey(sk),
+		WarpSigner:     warp.NewSigner(sk, networkID, chainID),
+		ValidatorState: &validators.TestState{},
+	}
+	toEngine := make(chan common.Message, 1)
+	v := newVM()
+	if err := v.Initialize(ctx, snowCtx, memdb.New(), genesisBytes, nil, []byte(offlineVMConfig), toEngine, nil, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func writeBlockTrace(dir string, trace *chain.BlockTrace) error {
+	b, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.json", trace.Height)), b, fsModeWrite)
+}
@@ -1,28 +1,21 @@
 // Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-//nolint:gosec
 package cli
 
 import (
 	"context"
 	"fmt"
-	"math"
-	"math/rand"
 	"os"
 	"os/signal"
-	"strings"
-	"sync"
-	"sync/atomic"
 	"syscall"
 	"time"
 
-	"golang.org/x/sync/errgroup"
-
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/loadgen"
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
@@ -31,22 +24,24 @@ import (
 const (
 	defaultRange          = 32
 	issuerShutdownTimeout = 60 * time.Second
-)
-
-var (
-	issuerWg sync.WaitGroup
-	exiting  sync.Once
 
-	l            sync.Mutex
-	confirmedTxs uint64
-	totalTxs     uint64
-
-	inflight atomic.Int64
-	sent     atomic.Int64
+	// UniformProfile sends transfers to recipients selected uniformly at
+	// random (or to new accounts, if random recipients are requested).
+	UniformProfile = "uniform"
+	// ZipfianProfile sends transfers to a small set of hot accounts.
+	ZipfianProfile = "zipfian"
 )
 
+// Spam issues transactions generated by the [loadgen.Profile] named
+// [profileName] from a set of newly funded accounts. If [ramp] is non-nil, the
+// issuance rate is controlled by [ramp] instead of a fixed number of
+// transactions per account per second.
+//
+// [profiles] can be used to register VM-specific profiles in addition to
+// [UniformProfile] and [ZipfianProfile].
 func (h *Handler) Spam(
 	maxTxBacklog int, maxFee *uint64, randomRecipient bool,
+	profileName string, ramp *loadgen.TPSRamp,
 	createClient func(string, uint32, ids.ID) error, // must save on caller side
 	getFactory func(*PrivateKey) (chain.AuthFactory, error),
 	createAccount func() (*PrivateKey, error),
@@ -54,9 +49,33 @@ func (h *Handler) Spam(
 	getParser func(context.Context, ids.ID) (chain.Parser, error),
 	getTransfer func(codec.Address, uint64) chain.Action,
 	submitDummy func(*rpc.JSONRPCClient, *PrivateKey) func(context.Context, uint64) error,
+	profiles map[string]loadgen.Profile,
 ) error {
 	ctx := context.Background()
 
+	// Select workload
+	available := map[string]loadgen.Profile{
+		UniformProfile: loadgen.UniformProfile(getTransfer),
+		ZipfianProfile: loadgen.ZipfianProfile(getTransfer),
+	}
+	if randomRecipient {
+		available[UniformProfile] = loadgen.RandomRecipientProfile(getTransfer, func() (codec.Address, error) {
+			priv, err := createAccount()
+			if err != nil {
+				return codec.EmptyAddress, err
+			}
+			return priv.Address, nil
+		})
+	}
+	for name, profile := range profiles {
+		available[name] = profile
+	}
+	profile, ok := available[profileName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, profileName)
+	}
+	utils.Outf("{{cyan}}spam profile:{{/}} %s\n", profileName)
+
 	// Select chain
 	chainID, uris, err := h.PromptChain("select chainID", nil)
 	if err != nil {
@@ -111,7 +130,8 @@ func (h *Handler) Spam(
 		return err
 	}
 	action := getTransfer(keys[0].Address, 0)
-	maxUnits, err := chain.EstimateMaxUnits(parser.Rules(time.Now().UnixMilli()), action, factory, nil)
+	rules := parser.Rules(time.Now().UnixMilli())
+	maxUnits, err := chain.EstimateMaxUnits(rules, action, factory, nil)
 	if err != nil {
 		return err
 	}
@@ -121,9 +141,19 @@ func (h *Handler) Spam(
 	if err != nil {
 		return err
 	}
-	numTxsPerAccount, err := h.PromptInt("number of transactions per account per second", consts.MaxInt)
-	if err != nil {
-		return err
+	var numTxsPerAccount int
+	if ramp == nil {
+		numTxsPerAccount, err = h.PromptInt("number of transactions per account per second", consts.MaxInt)
+		if err != nil {
+			return err
+		}
+	} else {
+		utils.Outf(
+			"{{cyan}}ramping tps:{{/}} %d -> %d over %s\n",
+			ramp.Start,
+			ramp.Target,
+			ramp.Duration,
+		)
 	}
 	numClients, err := h.PromptInt("number of clients per node", consts.MaxInt)
 	if err != nil {
@@ -133,7 +163,7 @@ func (h *Handler) Spam(
 	if err != nil {
 		return err
 	}
-	feePerTx, err := chain.MulSum(unitPrices, maxUnits)
+	feePerTx, err := chain.EstimateFee(rules, unitPrices, maxUnits)
 	if err != nil {
 		return err
 	}
@@ -151,20 +181,22 @@ func (h *Handler) Spam(
 		utils.FormatBalance(distAmount, h.c.Decimals()),
 		h.c.Symbol(),
 	)
-	accounts := make([]*PrivateKey, numAccounts)
+	accounts := make([]*loadgen.Account, numAccounts)
 	dcli, err := rpc.NewWebSocketClient(uris[0], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize) // we write the max read
 	if err != nil {
 		return err
 	}
-	funds := map[codec.Address]uint64{}
-	var fundsL sync.Mutex
 	for i := 0; i < numAccounts; i++ {
 		// Create account
 		pk, err := createAccount()
 		if err != nil {
 			return err
 		}
-		accounts[i] = pk
+		f, err := getFactory(pk)
+		if err != nil {
+			return err
+		}
+		accounts[i] = &loadgen.Account{Address: pk.Address, Factory: f, Balance: distAmount}
 
 		// Send funds
 		_, tx, err := cli.GenerateTransactionManual(parser, nil, getTransfer(pk.Address, distAmount), factory, feePerTx)
@@ -174,7 +206,6 @@ func (h *Handler) Spam(
 		if err := dcli.RegisterTx(tx); err != nil {
 			return fmt.Errorf("%w: failed to register tx", err)
 		}
-		funds[pk.Address] = distAmount
 	}
 	for i := 0; i < numAccounts; i++ {
 		_, dErr, result, err := dcli.ListenTx(ctx)
@@ -184,70 +215,72 @@ func (h *Handler) Spam(
 		if dErr != nil {
 			return dErr
 		}
-		if !result.Success {
+		if !result.Success() {
 			// Should never happen
 			return fmt.Errorf("%w: %s", ErrTxFailed, result.Output)
 		}
 	}
-	var recipientFunc func() (*PrivateKey, error)
-	if randomRecipient {
-		recipientFunc = createAccount
-	}
 	utils.Outf("{{yellow}}distributed funds to %d accounts{{/}}\n", numAccounts)
 
 	// Kickoff txs
-	clients := []*txIssuer{}
+	metrics := loadgen.NewMetrics()
+	issuers := []*loadgen.Issuer{}
 	for i := 0; i < len(uris); i++ {
 		for j := 0; j < numClients; j++ {
-			cli := rpc.NewJSONRPCClient(uris[i])
-			dcli, err := rpc.NewWebSocketClient(uris[i], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize) // we write the max read
+			issuer, err := loadgen.NewIssuer(uris[i], metrics)
 			if err != nil {
 				return err
 			}
-			clients = append(clients, &txIssuer{c: cli, d: dcli, uri: i})
+			issuers = append(issuers, issuer)
 		}
 	}
-	signals := make(chan os.Signal, 2)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
-
-	// confirm txs (track failure rate)
-	unitPrices, err = clients[0].c.UnitPrices(ctx, false)
+	generator, err := loadgen.New(&loadgen.Config{
+		Parser:        parser,
+		Accounts:      accounts,
+		Profile:       profile,
+		TxsPerAccount: numTxsPerAccount,
+		Ramp:          ramp,
+		MaxTxBacklog:  maxTxBacklog,
+		MaxFee:        maxFee,
+	}, issuers, metrics)
 	if err != nil {
 		return err
 	}
 	PrintUnitPrices(unitPrices)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 	cctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	for _, client := range clients {
-		startIssuer(cctx, client)
-	}
+	go func() {
+		select {
+		case <-signals:
+			utils.Outf("{{yellow}}exiting broadcast loop{{/}}\n")
+			cancel()
+		case <-cctx.Done():
+		}
+	}()
 
 	// log stats
-	t := time.NewTicker(1 * time.Second) // ensure no duplicates created
-	defer t.Stop()
-	var psent int64
+	spamStart := time.Now()
 	go func() {
+		t := time.NewTicker(1 * time.Second)
+		defer t.Stop()
+		var psent uint64
 		for {
 			select {
 			case <-t.C:
-				current := sent.Load()
-				l.Lock()
-				if totalTxs > 0 {
-					unitPrices, err = clients[0].c.UnitPrices(ctx, false)
-					if err != nil {
-						continue
-					}
+				snapshot := metrics.Snapshot()
+				if snapshot.Seen > 0 {
 					utils.Outf(
 						"{{yellow}}txs seen:{{/}} %d {{yellow}}success rate:{{/}} %.2f%% {{yellow}}inflight:{{/}} %d {{yellow}}issued/s:{{/}} %d {{yellow}}unit prices:{{/}} [%s]\n", //nolint:lll
-						totalTxs,
-						float64(confirmedTxs)/float64(totalTxs)*100,
-						inflight.Load(),
-						current-psent,
-						ParseDimensions(unitPrices),
+						snapshot.Seen,
+						snapshot.SuccessRate()*100,
+						snapshot.Inflight,
+						snapshot.Issued-psent,
+						ParseDimensions(generator.UnitPrices()),
 					)
 				}
-				l.Unlock()
-				psent = current
+				psent = snapshot.Issued
 			case <-cctx.Done():
 				return
 			}
@@ -255,128 +288,14 @@ func (h *Handler) Spam(
 	}()
 
 	// broadcast txs
-	g, gctx := errgroup.WithContext(ctx)
-	for ri := 0; ri < numAccounts; ri++ {
-		i := ri
-		g.Go(func() error {
-			t := time.NewTimer(0) // ensure no duplicates created
-			defer t.Stop()
-
-			issuerIndex, issuer := getRandomIssuer(clients)
-			factory, err := getFactory(accounts[i])
-			if err != nil {
-				return err
-			}
-			fundsL.Lock()
-			balance := funds[accounts[i].Address]
-			fundsL.Unlock()
-			defer func() {
-				fundsL.Lock()
-				funds[accounts[i].Address] = balance
-				fundsL.Unlock()
-			}()
-			ut := time.Now().Unix()
-			for {
-				select {
-				case <-t.C:
-					// Ensure we aren't too backlogged
-					if inflight.Load() > int64(maxTxBacklog) {
-						t.Reset(1 * time.Second)
-						continue
-					}
-
-					// Select tx time
-					//
-					// Needed to prevent duplicates if called within the same
-					// unix second.
-					nextTime := time.Now().Unix()
-					if nextTime <= ut {
-						nextTime = ut + 1
-					}
-					ut = nextTime
-					tm := &timeModifier{nextTime*consts.MillisecondsPerSecond + parser.Rules(nextTime).GetValidityWindow() - 5*consts.MillisecondsPerSecond}
-
-					// Send transaction
-					start := time.Now()
-					selected := map[codec.Address]int{}
-					for k := 0; k < numTxsPerAccount; k++ {
-						recipient, err := getNextRecipient(i, recipientFunc, accounts)
-						if err != nil {
-							utils.Outf("{{orange}}failed to get next recipient:{{/}} %v\n", err)
-							return err
-						}
-						v := selected[recipient] + 1
-						selected[recipient] = v
-						action := getTransfer(recipient, uint64(v))
-						fee, err := chain.MulSum(unitPrices, maxUnits)
-						if err != nil {
-							utils.Outf("{{orange}}failed to estimate max fee:{{/}} %v\n", err)
-							return err
-						}
-						if maxFee != nil {
-							fee = *maxFee
-						}
-						_, tx, err := issuer.c.GenerateTransactionManual(parser, nil, action, factory, fee, tm)
-						if err != nil {
-							utils.Outf("{{orange}}failed to generate tx:{{/}} %v\n", err)
-							continue
-						}
-						if err := issuer.d.RegisterTx(tx); err != nil {
-							issuer.l.Lock()
-							if issuer.d.Closed() {
-								if issuer.abandoned != nil {
-									issuer.l.Unlock()
-									return issuer.abandoned
-								}
-								// recreate issuer
-								utils.Outf("{{orange}}re-creating issuer:{{/}} %d {{orange}}uri:{{/}} %d\n", issuerIndex, issuer.uri)
-								dcli, err := rpc.NewWebSocketClient(uris[issuer.uri], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize) // we write the max read
-								if err != nil {
-									issuer.abandoned = err
-									utils.Outf("{{orange}}could not re-create closed issuer:{{/}} %v\n", err)
-									issuer.l.Unlock()
-									return err
-								}
-								issuer.d = dcli
-								startIssuer(cctx, issuer)
-								issuer.l.Unlock()
-								utils.Outf("{{green}}re-created closed issuer:{{/}} %d\n", issuerIndex)
-							}
-							continue
-						}
-						balance -= (fee + uint64(v))
-						issuer.l.Lock()
-						issuer.outstandingTxs++
-						issuer.l.Unlock()
-						inflight.Add(1)
-						sent.Add(1)
-					}
-
-					// Determine how long to sleep
-					dur := time.Since(start)
-					sleep := math.Max(float64(consts.MillisecondsPerSecond-dur.Milliseconds()), 0)
-					t.Reset(time.Duration(sleep) * time.Millisecond)
-				case <-gctx.Done():
-					return gctx.Err()
-				case <-cctx.Done():
-					return nil
-				case <-signals:
-					exiting.Do(func() {
-						utils.Outf("{{yellow}}exiting broadcast loop{{/}}\n")
-						cancel()
-					})
-					return nil
-				}
-			}
-		})
-	}
-	if err := g.Wait(); err != nil {
+	if err := generator.Run(cctx); err != nil {
 		return err
 	}
+	cancel()
 
 	// Wait for all issuers to finish
 	utils.Outf("{{yellow}}waiting for issuers to return{{/}}\n")
-	dctx, cancel := context.WithCancel(ctx)
+	dctx, dcancel := context.WithCancel(ctx)
 	go func() {
 		// Send a dummy transaction if shutdown is taking too long (listeners are
 		// expired on accept if dropped)
@@ -385,22 +304,25 @@ func (h *Handler) Spam(
 		for {
 			select {
 			case <-t.C:
-				utils.Outf("{{yellow}}remaining:{{/}} %d\n", inflight.Load())
+				utils.Outf("{{yellow}}remaining:{{/}} %d\n", metrics.Inflight())
 				_ = h.SubmitDummy(dctx, cli, submitDummy(cli, key))
 			case <-dctx.Done():
 				return
 			}
 		}
 	}()
-	issuerWg.Wait()
-	cancel()
+	if err := generator.Drain(issuerShutdownTimeout); err != nil {
+		utils.Outf("{{orange}}issuer shutdown timeout{{/}}\n")
+	}
+	dcancel()
+	printSpamSummary(profileName, time.Since(spamStart), metrics)
 
 	// Return funds
 	unitPrices, err = cli.UnitPrices(ctx, false)
 	if err != nil {
 		return err
 	}
-	feePerTx, err = chain.MulSum(unitPrices, maxUnits)
+	feePerTx, err = chain.EstimateFee(rules, unitPrices, maxUnits)
 	if err != nil {
 		return err
 	}
@@ -413,18 +335,14 @@ func (h *Handler) Spam(
 		returnsSent     int
 	)
 	for i := 0; i < numAccounts; i++ {
-		balance := funds[accounts[i].Address]
+		balance := accounts[i].Balance
 		if feePerTx > balance {
 			continue
 		}
 		returnsSent++
 		// Send funds
 		returnAmt := balance - feePerTx
-		f, err := getFactory(accounts[i])
-		if err != nil {
-			return err
-		}
-		_, tx, err := cli.GenerateTransactionManual(parser, nil, getTransfer(key.Address, returnAmt), f, feePerTx)
+		_, tx, err := cli.GenerateTransactionManual(parser, nil, getTransfer(key.Address, returnAmt), accounts[i].Factory, feePerTx)
 		if err != nil {
 			return err
 		}
@@ -444,7 +362,7 @@ func (h *Handler) Spam(
 		if dErr != nil {
 			return dErr
 		}
-		if !result.Success {
+		if !result.Success() {
 			// Should never happen
 			return fmt.Errorf("%w: %s", ErrTxFailed, result.Output)
 		}
@@ -457,99 +375,32 @@ func (h *Handler) Spam(
 	return nil
 }
 
-type txIssuer struct {
-	c *rpc.JSONRPCClient
-	d *rpc.WebSocketClient
-
-	l              sync.Mutex
-	uri            int
-	abandoned      error
-	outstandingTxs int
-}
-
-type timeModifier struct {
-	Timestamp int64
-}
-
-func (t *timeModifier) Base(b *chain.Base) {
-	b.Timestamp = t.Timestamp
-}
-
-func startIssuer(cctx context.Context, issuer *txIssuer) {
-	issuerWg.Add(1)
-	go func() {
-		for {
-			_, dErr, result, err := issuer.d.ListenTx(context.TODO())
-			if err != nil {
-				return
-			}
-			inflight.Add(-1)
-			issuer.l.Lock()
-			issuer.outstandingTxs--
-			issuer.l.Unlock()
-			l.Lock()
-			if result != nil {
-				if result.Success {
-					confirmedTxs++
-				} else {
-					utils.Outf("{{orange}}on-chain tx failure:{{/}} %s %t\n", string(result.Output), result.Success)
-				}
-			} else {
-				// We can't error match here because we receive it over the wire.
-				if !strings.Contains(dErr.Error(), rpc.ErrExpired.Error()) {
-					utils.Outf("{{orange}}pre-execute tx failure:{{/}} %v\n", dErr)
-				}
-			}
-			totalTxs++
-			l.Unlock()
-		}
-	}()
-	go func() {
-		defer func() {
-			_ = issuer.d.Close()
-			issuerWg.Done()
-		}()
-
-		<-cctx.Done()
-		start := time.Now()
-		for time.Since(start) < issuerShutdownTimeout {
-			if issuer.d.Closed() {
-				return
-			}
-			issuer.l.Lock()
-			outstanding := issuer.outstandingTxs
-			issuer.l.Unlock()
-			if outstanding == 0 {
-				return
-			}
-			time.Sleep(500 * time.Millisecond)
-		}
-		utils.Outf("{{orange}}issuer shutdown timeout{{/}}\n")
-	}()
-}
-
-func getNextRecipient(self int, createAccount func() (*PrivateKey, error), keys []*PrivateKey) (codec.Address, error) {
-	// Send to a random, new account
-	if createAccount != nil {
-		priv, err := createAccount()
-		if err != nil {
-			return codec.EmptyAddress, err
-		}
-		return priv.Address, nil
-	}
-
-	// Select item from array
-	index := rand.Int() % len(keys)
-	if index == self {
-		index++
-		if index == len(keys) {
-			index = 0
-		}
+func printSpamSummary(profileName string, elapsed time.Duration, metrics *loadgen.Metrics) {
+	snapshot := metrics.Snapshot()
+	utils.Outf("{{yellow}}summary ({{/}}%s{{yellow}}):{{/}}\n", profileName)
+	utils.Outf(
+		"{{yellow}}duration:{{/}} %s {{yellow}}issued:{{/}} %d {{yellow}}avg issued/s:{{/}} %.2f\n",
+		elapsed.Round(time.Second),
+		snapshot.Issued,
+		float64(snapshot.Issued)/elapsed.Seconds(),
+	)
+	if snapshot.Seen == 0 {
+		return
 	}
-	return keys[index].Address, nil
-}
-
-func getRandomIssuer(issuers []*txIssuer) (int, *txIssuer) {
-	index := rand.Int() % len(issuers)
-	return index, issuers[index]
+	utils.Outf(
+		"{{yellow}}txs seen:{{/}} %d {{yellow}}success rate:{{/}} %.2f%% {{yellow}}failed:{{/}} %d {{yellow}}expired:{{/}} %d {{yellow}}dropped:{{/}} %d\n",
+		snapshot.Seen,
+		snapshot.SuccessRate()*100,
+		snapshot.Failed,
+		snapshot.Expired,
+		snapshot.Dropped,
+	)
+	latencies := metrics.Latencies()
+	utils.Outf(
+		"{{yellow}}latency p50:{{/}} %s {{yellow}}p90:{{/}} %s {{yellow}}p99:{{/}} %s {{yellow}}max:{{/}} %s\n",
+		latencies.P50.Round(time.Millisecond),
+		latencies.P90.Round(time.Millisecond),
+		latencies.P99.Round(time.Millisecond),
+		latencies.Max.Round(time.Millisecond),
+	)
 }
@@ -0,0 +1,28 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/loadgen"
+)
+
+func TestSpamSummary(t *testing.T) {
+	require := require.New(t)
+
+	// Results (and their latencies) are only summarized once some are seen
+	out, text := captureOutput(t, func() {
+		printSpamSummary(ZipfianProfile, 90*time.Second+400*time.Millisecond, loadgen.NewMetrics())
+	})
+	require.Empty(out)
+	require.Contains(text, ZipfianProfile)
+	require.Contains(text, "1m30s")
+	require.Contains(text, "0.00")
+	require.NotContains(text, "success rate:")
+	require.NotContains(text, "latency")
+}
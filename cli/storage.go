@@ -18,6 +18,7 @@ const (
 	defaultPrefix = 0x0
 	keyPrefix     = 0x1
 	chainPrefix   = 0x2
+	aliasPrefix   = 0x3
 
 	defaultKeyKey   = "key"
 	defaultChainKey = "chain"
@@ -216,6 +217,63 @@ func (h *Handler) DeleteChains() ([]ids.ID, error) {
 	return chainIDs, nil
 }
 
+func aliasKey(alias string) []byte {
+	k := make([]byte, 1+len(alias))
+	k[0] = aliasPrefix
+	copy(k[1:], alias)
+	return k
+}
+
+// StoreAlias saves [alias] as another name for [addr]. An existing alias is
+// never overwritten.
+func (h *Handler) StoreAlias(alias string, addr codec.Address) error {
+	k := aliasKey(alias)
+	has, err := h.db.Has(k)
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrDuplicate
+	}
+	return h.db.Put(k, addr[:])
+}
+
+// GetAlias returns the address saved as [alias] (and false if there is none).
+func (h *Handler) GetAlias(alias string) (codec.Address, bool, error) {
+	v, err := h.db.Get(aliasKey(alias))
+	if errors.Is(err, database.ErrNotFound) {
+		return codec.EmptyAddress, false, nil
+	}
+	if err != nil {
+		return codec.EmptyAddress, false, err
+	}
+	return codec.Address(v), true, nil
+}
+
+// GetAliases returns all saved aliases.
+func (h *Handler) GetAliases() (map[string]codec.Address, error) {
+	iter := h.db.NewIteratorWithPrefix([]byte{aliasPrefix})
+	defer iter.Release()
+
+	aliases := map[string]codec.Address{}
+	for iter.Next() {
+		aliases[string(iter.Key()[1:])] = codec.Address(iter.Value())
+	}
+	return aliases, iter.Error()
+}
+
+func (h *Handler) DeleteAlias(alias string) error {
+	k := aliasKey(alias)
+	has, err := h.db.Has(k)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("%w: %s", ErrUnknownAlias, alias)
+	}
+	return h.db.Delete(k)
+}
+
 func (h *Handler) CloseDatabase() error {
 	if h.db == nil {
 		return nil
@@ -0,0 +1,590 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package clientgen generates strongly typed client helpers from the actions
+// registered by a VM, so that VM clients don't need to construct actions and
+// unmarshal their outputs by hand.
+//
+// For each action in the action registry, the generated file has a
+// Build<Action>Tx function that takes the fields of the action as arguments
+// and generates a transaction with [rpc.JSONRPCClient.GenerateTransaction].
+// For each action with an output decoder in the output registry, it has a
+// Decode<Action>Result function that returns the typed output of a result.
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	contextImport = "context"
+	errorsImport  = "errors"
+	chainImport   = "github.com/ava-labs/hypersdk/chain"
+	rpcImport     = "github.com/ava-labs/hypersdk/rpc"
+	warpImport    = "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	hypersdkModule = "github.com/ava-labs/hypersdk"
+)
+
+// Config parameterizes a generated client.
+type Config struct {
+	// Package is the name of the package of the generated file.
+	Package string
+	// Registry is the file that registers the actions of the VM (and the
+	// decoders of their outputs).
+	Registry string
+	// Actions is the directory of the package that defines the registered
+	// actions.
+	Actions string
+}
+
+// Action is an action found in the registry.
+type Action struct {
+	Name   string
+	Warp   bool
+	Fields []*Field
+
+	// Decoder is the function that unmarshals the output of the action (if
+	// it registered one), Output is its type, and Zero is the zero value of
+	// Output.
+	Decoder string
+	Output  string
+	Zero    string
+}
+
+// Field is an exported field of an [Action].
+type Field struct {
+	Name  string
+	Param string
+	Type  string
+}
+
+type generator struct {
+	alias       string
+	actionsPath string
+
+	// imports maps the name of each package used by the generated file to
+	// its path.
+	imports map[string]string
+	// reserved are the identifiers that parameters can't shadow.
+	reserved map[string]bool
+
+	types  map[string]*ast.StructType
+	funcs  map[string]*ast.FuncType
+	fileOf map[ast.Node]map[string]string
+}
+
+// Generate returns the formatted source of the client helpers of the actions
+// registered in [c.Registry].
+func Generate(c *Config) ([]byte, error) {
+	g := &generator{
+		imports: map[string]string{
+			"context": contextImport,
+			"chain":   chainImport,
+			"rpc":     rpcImport,
+		},
+		reserved: map[string]bool{
+			"ctx":       true,
+			"cli":       true,
+			"parser":    true,
+			"factory":   true,
+			"wm":        true,
+			"modifiers": true,
+			"result":    true,
+		},
+		types:  map[string]*ast.StructType{},
+		funcs:  map[string]*ast.FuncType{},
+		fileOf: map[ast.Node]map[string]string{},
+	}
+	actions, err := g.parseRegistry(c.Registry)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.parseActions(c.Actions); err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if err := g.resolve(action); err != nil {
+			return nil, fmt.Errorf("%s: %w", action.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, map[string]any{
+		"Package": c.Package,
+		"Imports": g.importGroups(),
+		"Alias":   g.alias,
+		"Actions": actions,
+	}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parseRegistry returns the actions registered in [path] (in the order they
+// are registered).
+func (g *generator) parseRegistry(path string) ([]*Action, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		actions  []*Action
+		byName   = map[string]*Action{}
+		decoders = map[string]string{}
+		walkErr  error
+	)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || walkErr != nil {
+			return walkErr == nil
+		}
+		registry, ok := registerCall(call)
+		if !ok {
+			return true
+		}
+		alias, name, ok := registeredType(call.Args[0])
+		if !ok {
+			return true
+		}
+		switch {
+		case registry == "ActionRegistry" && len(call.Args) == 3:
+			if _, ok := byName[name]; ok {
+				return true
+			}
+			if len(g.alias) == 0 {
+				g.alias = alias
+			}
+			warp, _ := call.Args[2].(*ast.Ident)
+			action := &Action{Name: name, Warp: warp != nil && warp.Name == "true"}
+			actions = append(actions, action)
+			byName[name] = action
+		case registry == "OutputRegistry" && len(call.Args) == 2:
+			decoder, ok := outputDecoder(call.Args[1], alias)
+			if !ok {
+				walkErr = fmt.Errorf("%w: %s", ErrInvalidDecoder, name)
+				return false
+			}
+			decoders[name] = decoder
+		}
+		return true
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoActions, path)
+	}
+	for name, decoder := range decoders {
+		action, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has an output decoder but is not registered", ErrMissingAction, name)
+		}
+		action.Decoder = decoder
+	}
+
+	imports := fileImports(f)
+	actionsPath, ok := imports[g.alias]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingImport, g.alias)
+	}
+	g.actionsPath = actionsPath
+	if err := g.use(g.alias, actionsPath); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// fileImports maps the name of each package imported by [f] to its path.
+func fileImports(f *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// registerCall returns the name of the registry (like "ActionRegistry") if
+// [call] is a call to its Register method.
+func registerCall(call *ast.CallExpr) (string, bool) {
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "Register" || len(call.Args) == 0 {
+		return "", false
+	}
+	switch registry := fun.X.(type) {
+	case *ast.SelectorExpr:
+		return registry.Sel.Name, true
+	case *ast.Ident:
+		return registry.Name, true
+	default:
+		return "", false
+	}
+}
+
+// registeredType returns the package and name of the type registered by
+// [expr] (of the form "(&pkg.Name{}).GetTypeID()").
+func registeredType(expr ast.Expr) (string, string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", "", false
+	}
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "GetTypeID" {
+		return "", "", false
+	}
+	x := fun.X
+	if paren, ok := x.(*ast.ParenExpr); ok {
+		x = paren.X
+	}
+	if unary, ok := x.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		x = unary.X
+	}
+	lit, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return "", "", false
+	}
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	return pkg.Name, sel.Sel.Name, true
+}
+
+// outputDecoder returns the name of the function in [alias] wrapped by
+// [expr] (of the form "chain.NewOutputDecoder(alias.UnmarshalOutput)").
+func outputDecoder(expr ast.Expr, alias string) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "NewOutputDecoder" {
+		return "", false
+	}
+	f, ok := call.Args[0].(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := f.X.(*ast.Ident)
+	if !ok || pkg.Name != alias {
+		return "", false
+	}
+	return f.Sel.Name, true
+}
+
+// parseActions parses the types and functions declared in the actions
+// package in [dir].
+func (g *generator) parseActions(dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			imports := fileImports(f)
+			for _, decl := range f.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						spec, ok := spec.(*ast.TypeSpec)
+						if !ok {
+							continue
+						}
+						st, _ := spec.Type.(*ast.StructType)
+						g.types[spec.Name.Name] = st
+						if st != nil {
+							g.fileOf[st] = imports
+						}
+					}
+				case *ast.FuncDecl:
+					if decl.Recv == nil {
+						g.funcs[decl.Name.Name] = decl.Type
+						g.fileOf[decl.Type] = imports
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve populates the fields and output of [action].
+func (g *generator) resolve(action *Action) error {
+	st, ok := g.types[action.Name]
+	if !ok || st == nil {
+		return fmt.Errorf("%w: %s.%s is not a struct", ErrMissingAction, g.alias, action.Name)
+	}
+	imports := g.fileOf[st]
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 || skipField(field) {
+			continue
+		}
+		typ, err := g.typeString(field.Type, imports)
+		if err != nil {
+			return err
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			action.Fields = append(action.Fields, &Field{Name: name.Name, Type: typ})
+		}
+	}
+	if action.Warp {
+		if err := g.use("warp", warpImport); err != nil {
+			return err
+		}
+	}
+	if len(action.Decoder) > 0 {
+		decoder, ok := g.funcs[action.Decoder]
+		if !ok || decoder.Results == nil || len(decoder.Results.List) != 2 {
+			return fmt.Errorf("%w: %s.%s", ErrInvalidDecoder, g.alias, action.Decoder)
+		}
+		output, err := g.typeString(decoder.Results.List[0].Type, g.fileOf[decoder])
+		if err != nil {
+			return err
+		}
+		action.Output = output
+		action.Zero = "nil"
+		if !strings.HasPrefix(output, "*") && !strings.HasPrefix(output, "[]") && !strings.HasPrefix(output, "map[") {
+			action.Zero = "*new(" + output + ")"
+		}
+		if err := g.use("errors", errorsImport); err != nil {
+			return err
+		}
+	}
+
+	// Parameter names are assigned once all imports are known, so that they
+	// don't shadow any package.
+	for _, field := range action.Fields {
+		field.Param = g.paramName(field.Name)
+	}
+	return nil
+}
+
+// skipField returns true if [field] is not part of the JSON encoding of its
+// action.
+func skipField(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	return reflect.StructTag(tag).Get("json") == "-"
+}
+
+// typeString returns [expr] as it should be written in the generated file,
+// where [imports] are the imports of the file that declared [expr].
+func (g *generator) typeString(expr ast.Expr, imports map[string]string) (string, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.types[expr.Name]; ok {
+			return g.alias + "." + expr.Name, nil
+		}
+		if types.Universe.Lookup(expr.Name) != nil {
+			return expr.Name, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, expr.Name)
+	case *ast.SelectorExpr:
+		pkg, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("%w: %T", ErrUnsupportedType, expr.X)
+		}
+		path, ok := imports[pkg.Name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMissingImport, pkg.Name)
+		}
+		if err := g.use(pkg.Name, path); err != nil {
+			return "", err
+		}
+		return pkg.Name + "." + expr.Sel.Name, nil
+	case *ast.StarExpr:
+		elem, err := g.typeString(expr.X, imports)
+		if err != nil {
+			return "", err
+		}
+		return "*" + elem, nil
+	case *ast.ArrayType:
+		elem, err := g.typeString(expr.Elt, imports)
+		if err != nil {
+			return "", err
+		}
+		if expr.Len == nil {
+			return "[]" + elem, nil
+		}
+		length, ok := expr.Len.(*ast.BasicLit)
+		if !ok {
+			return "", fmt.Errorf("%w: array length %T", ErrUnsupportedType, expr.Len)
+		}
+		return "[" + length.Value + "]" + elem, nil
+	case *ast.MapType:
+		key, err := g.typeString(expr.Key, imports)
+		if err != nil {
+			return "", err
+		}
+		value, err := g.typeString(expr.Value, imports)
+		if err != nil {
+			return "", err
+		}
+		return "map[" + key + "]" + value, nil
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedType, expr)
+	}
+}
+
+// use records that the generated file imports [path] as [name].
+func (g *generator) use(name, path string) error {
+	if existing, ok := g.imports[name]; ok && existing != path {
+		return fmt.Errorf("%w: %s is both %s and %s", ErrImportConflict, name, existing, path)
+	}
+	g.imports[name] = path
+	return nil
+}
+
+// paramName returns the name of the parameter of the field [name] (like
+// "txID" for "TxID").
+func (g *generator) paramName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		// Keep the start of the next word (like the "P" of "URLPath")
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	param := string(runes)
+	if token.IsKeyword(param) || types.Universe.Lookup(param) != nil || g.reserved[param] {
+		return param + "Arg"
+	}
+	if _, ok := g.imports[param]; ok {
+		return param + "Arg"
+	}
+	return param
+}
+
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// importGroups returns the imports of the generated file, grouped into the
+// standard library, third-party packages, and the hypersdk (including the
+// VM).
+func (g *generator) importGroups() [][]importSpec {
+	groups := make([][]importSpec, 3)
+	for name, path := range g.imports {
+		spec := importSpec{Path: path}
+		if name != path[strings.LastIndex(path, "/")+1:] {
+			spec.Name = name
+		}
+		switch {
+		case !strings.Contains(strings.SplitN(path, "/", 2)[0], "."):
+			groups[0] = append(groups[0], spec)
+		case repo(path) == hypersdkModule || repo(path) == repo(g.actionsPath):
+			groups[2] = append(groups[2], spec)
+		default:
+			groups[1] = append(groups[1], spec)
+		}
+	}
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+	}
+	return groups
+}
+
+// repo returns the first 3 elements of [path] (like
+// "github.com/ava-labs/hypersdk").
+func repo(path string) string {
+	parts := strings.SplitN(path, "/", 4)
+	return strings.Join(parts[:math.Min(len(parts), 3)], "/")
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Code generated by hypersdk-cli gen-client. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+{{range .}}	{{with .Name}}{{.}} {{end}}"{{.Path}}"
+{{end}}
+{{- end}}
+)
+{{$alias := .Alias}}
+{{- range .Actions}}
+{{$action := .}}
+// Build{{.Name}}Tx generates a transaction that executes
+// [{{$alias}}.{{.Name}}]{{if .Warp}} with [wm]{{end}}.
+func Build{{.Name}}Tx(
+	ctx context.Context,
+	cli *rpc.JSONRPCClient,
+	parser chain.Parser,
+	factory chain.AuthFactory,
+{{- if .Warp}}
+	wm *warp.Message,
+{{- end}}
+{{- range .Fields}}
+	{{.Param}} {{.Type}},
+{{- end}}
+	modifiers ...rpc.Modifier,
+) (func(context.Context) error, *chain.Transaction, uint64, error) {
+	action := &{{$alias}}.{{.Name}}{
+{{- range .Fields}}
+		{{.Name}}: {{.Param}},
+{{- end}}
+	}
+	return cli.GenerateTransaction(ctx, parser, {{if .Warp}}wm{{else}}nil{{end}}, action, factory, modifiers...)
+}
+{{- if .Decoder}}
+
+// Decode{{.Name}}Result returns the output of [{{$alias}}.{{.Name}}] in
+// [result]. If the action failed, the returned error has the status of
+// [result] (see [chain.StatusOf]).
+func Decode{{.Name}}Result(result *chain.Result) ({{.Output}}, error) {
+	if !result.Success() {
+		return {{.Zero}}, chain.NewStatusError(result.Status, errors.New(string(result.Output)))
+	}
+	return {{$alias}}.{{.Decoder}}(result.Output)
+}
+{{- end}}
+{{- end}}
+`))
@@ -0,0 +1,126 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clientgen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	src, err := Generate(&Config{
+		Package:  "rpc",
+		Registry: filepath.Join("testdata", "registry", "registry.go"),
+		Actions:  filepath.Join("testdata", "actions"),
+	})
+	require.NoError(err)
+	f, err := parser.ParseFile(token.NewFileSet(), "actions_gen.go", src, parser.ImportsOnly)
+	require.NoError(err)
+	require.Equal("rpc", f.Name.Name)
+
+	out := string(src)
+	for _, want := range []string{
+		// Fields are arguments (skipping unexported and unencoded fields)
+		"\tto codec.Address,\n\tassets []ids.ID,\n\treturnArg bool,\n\tkind actions.Kind,\n\tmodifiers ...rpc.Modifier,\n",
+		"Return: returnArg,",
+		"cli.GenerateTransaction(ctx, parser, nil, action, factory, modifiers...)",
+
+		// Warp actions take the message to import
+		"\twm *warp.Message,\n\tfill bool,\n",
+		"cli.GenerateTransaction(ctx, parser, wm, action, factory, modifiers...)",
+
+		// Only actions with a decoder have one
+		"func DecodeSendResult(result *chain.Result) (*actions.Result, error) {",
+		"return actions.UnmarshalResult(result.Output)",
+		"\"github.com/ava-labs/avalanchego/vms/platformvm/warp\"\n\n",
+	} {
+		require.Contains(out, want)
+	}
+	require.NotContains(out, "DecodeClaimResult")
+	require.NotContains(out, "Cached")
+	require.NotContains(out, "ED25519")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	registry := filepath.Join(dir, "registry.go")
+	for _, test := range []struct {
+		src string
+		err error
+	}{
+		{"package registry\n", ErrNoActions},
+		{
+			"package registry\n\nimport \"example.com/actions\"\n\nfunc init() {\n" +
+				"\tconsts.ActionRegistry.Register((&actions.Missing{}).GetTypeID(), nil, false)\n}\n",
+			ErrMissingAction,
+		},
+		{
+			"package registry\n\nimport \"example.com/actions\"\n\nfunc init() {\n" +
+				"\tconsts.ActionRegistry.Register((&actions.Send{}).GetTypeID(), nil, false)\n" +
+				"\tconsts.OutputRegistry.Register((&actions.Send{}).GetTypeID(), decode)\n}\n",
+			ErrInvalidDecoder,
+		},
+		{
+			"package registry\n\nfunc init() {\n" +
+				"\tconsts.ActionRegistry.Register((&actions.Send{}).GetTypeID(), nil, false)\n}\n",
+			ErrMissingImport,
+		},
+	} {
+		require.NoError(os.WriteFile(registry, []byte(test.src), 0o600))
+		_, err := Generate(&Config{
+			Package:  "rpc",
+			Registry: registry,
+			Actions:  filepath.Join("testdata", "actions"),
+		})
+		require.ErrorIs(err, test.err, test.src)
+	}
+}
+
+func TestParamName(t *testing.T) {
+	require := require.New(t)
+
+	g := &generator{imports: map[string]string{"codec": ""}, reserved: map[string]bool{"ctx": true}}
+	for name, param := range map[string]string{
+		"Value":   "value",
+		"TxID":    "txID",
+		"ID":      "id",
+		"URLPath": "urlPath",
+		"Return":  "returnArg",
+		"Len":     "lenArg",
+		"Ctx":     "ctxArg",
+		"Codec":   "codecArg",
+	} {
+		require.Equal(param, g.paramName(name), name)
+	}
+}
+
+// TestExamplesUpToDate ensures the clients generated for the example VMs
+// were regenerated after their actions changed.
+func TestExamplesUpToDate(t *testing.T) {
+	for _, vm := range []string{"morpheusvm", "tokenvm"} {
+		t.Run(vm, func(t *testing.T) {
+			require := require.New(t)
+
+			root := filepath.Join("..", "examples", vm)
+			src, err := Generate(&Config{
+				Package:  "rpc",
+				Registry: filepath.Join(root, "registry", "registry.go"),
+				Actions:  filepath.Join(root, "actions"),
+			})
+			require.NoError(err)
+			existing, err := os.ReadFile(filepath.Join(root, "rpc", "actions_gen.go"))
+			require.NoError(err)
+			require.Equal(string(existing), string(src), "run \"go generate ./rpc\" in examples/%s", vm)
+		})
+	}
+}
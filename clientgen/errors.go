@@ -0,0 +1,15 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package clientgen
+
+import "errors"
+
+var (
+	ErrNoActions       = errors.New("no registered actions")
+	ErrMissingAction   = errors.New("missing action")
+	ErrMissingImport   = errors.New("missing import")
+	ErrImportConflict  = errors.New("import conflict")
+	ErrUnsupportedType = errors.New("unsupported field type")
+	ErrInvalidDecoder  = errors.New("invalid output decoder")
+)
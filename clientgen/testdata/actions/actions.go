@@ -0,0 +1,37 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+type Send struct {
+	To     codec.Address `json:"to"`
+	Assets []ids.ID      `json:"assets"`
+	Return bool          `json:"return"`
+	Kind   Kind          `json:"kind"`
+
+	Cached []byte `json:"-"`
+	actor  codec.Address
+}
+
+type Kind uint8
+
+type Claim struct {
+	Fill bool `json:"fill"`
+
+	warpMessage *warp.Message
+}
+
+type Result struct {
+	Value uint64 `json:"value"`
+}
+
+func UnmarshalResult([]byte) (*Result, error) {
+	return &Result{}, nil
+}
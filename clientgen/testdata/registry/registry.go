@@ -0,0 +1,24 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry
+
+import (
+	"github.com/ava-labs/hypersdk/chain"
+
+	"github.com/ava-labs/hypersdk/clientgen/testdata/actions"
+	"github.com/ava-labs/hypersdk/clientgen/testdata/auth"
+	"github.com/ava-labs/hypersdk/clientgen/testdata/consts"
+)
+
+func init() {
+	errs.Add(
+		consts.ActionRegistry.Register((&actions.Send{}).GetTypeID(), actions.UnmarshalSend, false),
+		consts.ActionRegistry.Register((&actions.Claim{}).GetTypeID(), actions.UnmarshalClaim, true),
+
+		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
+	)
+	errs.Add(
+		consts.OutputRegistry.Register((&actions.Send{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalResult)),
+	)
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// hypersdk-cli contains tools for developing hypersdk VMs.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/hypersdk/clientgen"
+	"github.com/ava-labs/hypersdk/scaffold"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+const usage = `Usage: hypersdk-cli <command> [flags]
+
+Commands:
+  new-vm       generate the skeleton of a new VM
+  gen-client   generate typed client helpers for the actions of a VM
+
+Run "hypersdk-cli <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "new-vm":
+		err = newVM(os.Args[2:])
+	case "gen-client":
+		err = genClient(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownCommand, os.Args[1])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hypersdk-cli failed %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newVM(args []string) error {
+	var (
+		fs       = flag.NewFlagSet("new-vm", flag.ExitOnError)
+		c        = &scaffold.Config{}
+		dir      string
+		decimals uint
+	)
+	fs.StringVar(&c.Name, "name", "", "name of the VM (e.g. examplevm)")
+	fs.StringVar(&c.Module, "module", "", "Go module path of the VM (e.g. github.com/example/examplevm)")
+	fs.StringVar(&c.HRP, "hrp", "", "human-readable part of addresses (e.g. example)")
+	fs.StringVar(&c.Symbol, "symbol", "", "symbol of the native token (e.g. EX)")
+	fs.UintVar(&decimals, "decimals", scaffold.MaxDecimals, "decimals of the native token")
+	fs.StringVar(&dir, "dir", "", "directory to generate the VM in (defaults to the name of the VM)")
+	fs.StringVar(&c.HypersdkVersion, "hypersdk-version", scaffold.HypersdkVersion(), "version of the hypersdk to require")
+	fs.StringVar(&c.HypersdkReplace, "hypersdk-replace", "", "path to a local hypersdk checkout to use instead of [hypersdk-version]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if decimals > scaffold.MaxDecimals {
+		return fmt.Errorf("%w: %d > %d", scaffold.ErrInvalidDecimals, decimals, scaffold.MaxDecimals)
+	}
+	c.Decimals = uint8(decimals)
+	if len(dir) == 0 {
+		dir = c.Name
+	}
+	files, err := scaffold.Generate(c, dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		utils.Outf("{{yellow}}created:{{/}} %s\n", file)
+	}
+	utils.Outf("{{green}}generated %s in %s{{/}} (run \"go mod tidy\" in %s to fetch dependencies)\n", c.Name, dir, dir)
+	return nil
+}
+
+func genClient(args []string) error {
+	var (
+		fs  = flag.NewFlagSet("gen-client", flag.ExitOnError)
+		c   = &clientgen.Config{}
+		out string
+	)
+	// [GOPACKAGE] is set by "go generate"
+	fs.StringVar(&c.Package, "package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	fs.StringVar(&c.Registry, "registry", "", "file that registers the actions of the VM")
+	fs.StringVar(&c.Actions, "actions", "", "directory of the package that defines the actions")
+	fs.StringVar(&out, "out", "actions_gen.go", "file to write the generated helpers to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := clientgen.Generate(c)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil { //nolint:gosec
+		return err
+	}
+	utils.Outf("{{green}}generated:{{/}} %s\n", out)
+	return nil
+}
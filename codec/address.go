@@ -25,6 +25,21 @@ type Address [AddressLen]byte
 
 var EmptyAddress = [AddressLen]byte{}
 
+// MarshalText encodes [a] as hex. Bech32 is not used because its HRP is
+// defined by each VM.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(ToHex(a[:])), nil
+}
+
+func (a *Address) UnmarshalText(b []byte) error {
+	bytes, err := LoadHex(string(b), AddressLen)
+	if err != nil {
+		return err
+	}
+	copy(a[:], bytes)
+	return nil
+}
+
 // CreateAddress returns [Address] made from concatenating
 // [typeID] with [id].
 func CreateAddress(typeID uint8, id ids.ID) Address {
@@ -5,6 +5,7 @@ package codec
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -41,3 +42,18 @@ func TestInvalidAddressChecksum(t *testing.T) {
 	_, err := ParseAddressBech32(hrp, addr)
 	require.ErrorContains(err, "invalid checksum")
 }
+
+func TestAddressJSON(t *testing.T) {
+	require := require.New(t)
+
+	addr := CreateAddress(1, ids.GenerateTestID())
+	b, err := json.Marshal(addr)
+	require.NoError(err)
+	require.Equal(`"`+ToHex(addr[:])+`"`, string(b))
+
+	var parsed Address
+	require.NoError(json.Unmarshal(b, &parsed))
+	require.Equal(addr, parsed)
+
+	require.Error(json.Unmarshal([]byte(`"00"`), &parsed))
+}
@@ -8,6 +8,7 @@ import "errors"
 var (
 	ErrTooManyItems       = errors.New("too many items")
 	ErrDuplicateItem      = errors.New("duplicate item")
+	ErrUnknownItem        = errors.New("unknown item")
 	ErrFieldNotPopulated  = errors.New("field is not populated")
 	ErrInvalidBitset      = errors.New("invalid bitset")
 	ErrIncorrectHRP       = errors.New("incorrect hrp")
@@ -17,23 +17,37 @@ import (
 // from avalanchego/utils/wrappers/packing.go. A bool [required] parameter is
 // added to many unpacking methods, which signals the packer to add an error
 // if the expected method does not unpack properly.
+//
+// The wrapped Packer is stored by value so that creating a Packer only
+// allocates once.
 type Packer struct {
-	p *wrappers.Packer
+	p wrappers.Packer
 }
 
 // NewReader returns a Packer instance with the current byte array set to [byte]
 // and it's MaxSize set to [limit].
 func NewReader(src []byte, limit int) *Packer {
 	return &Packer{
-		p: &wrappers.Packer{Bytes: src, MaxSize: limit},
+		p: wrappers.Packer{Bytes: src, MaxSize: limit},
 	}
 }
 
 // NewWriter returns a Packer instance with an initial size of [initial] and a
 // MaxSize set to [limit].
+//
+// If [initial] is the exact size of what will be packed (usually computed
+// with the Size method of the packed objects), writing never reallocates.
 func NewWriter(initial, limit int) *Packer {
 	return &Packer{
-		p: &wrappers.Packer{Bytes: make([]byte, 0, initial), MaxSize: limit},
+		p: wrappers.Packer{Bytes: make([]byte, 0, initial), MaxSize: limit},
+	}
+}
+
+// NewAppendWriter returns a Packer instance that appends to [dst], reusing
+// its spare capacity. [limit] includes the bytes already in [dst].
+func NewAppendWriter(dst []byte, limit int) *Packer {
+	return &Packer{
+		p: wrappers.Packer{Bytes: dst, Offset: len(dst), MaxSize: limit},
 	}
 }
 
@@ -0,0 +1,63 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/window"
+)
+
+const fuzzMaxBytes = 256
+
+// FuzzUnpack interprets the first byte of [data] as a sequence of unpack
+// operations to run over the rest of [data] and ensures none of them panic.
+func FuzzUnpack(f *testing.F) {
+	wr := NewWriter(0, consts.MaxInt)
+	wr.PackBool(true)
+	wr.PackID(ids.GenerateTestID())
+	wr.PackUint64(1)
+	wr.PackBytes([]byte{1, 2, 3})
+	wr.PackString(TestString)
+	f.Add(uint64(0x0403020100), wr.Bytes())
+	f.Add(uint64(0), []byte{})
+
+	f.Fuzz(func(_ *testing.T, ops uint64, data []byte) {
+		p := NewReader(data, consts.NetworkSizeLimit)
+		for i := 0; i < 16 && p.Err() == nil; i++ {
+			switch (ops >> (4 * i)) & 0xf {
+			case 0:
+				p.UnpackBool()
+			case 1:
+				var id ids.ID
+				p.UnpackID(i%2 == 0, &id)
+			case 2:
+				p.UnpackUint64(i%2 == 0)
+			case 3:
+				var b []byte
+				p.UnpackBytes(fuzzMaxBytes, i%2 == 0, &b)
+			case 4:
+				p.UnpackString(i%2 == 0)
+			case 5:
+				var a Address
+				p.UnpackAddress(&a)
+			case 6:
+				p.UnpackInt64(i%2 == 0)
+			case 7:
+				p.UnpackInt(i%2 == 0)
+			case 8:
+				var w window.Window
+				p.UnpackWindow(&w)
+			case 9:
+				var b []byte
+				p.UnpackFixedBytes(int(ops>>60), &b)
+			default:
+				p.UnpackByte()
+			}
+		}
+		_ = p.Empty()
+	})
+}
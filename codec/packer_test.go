@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/window"
 	"github.com/stretchr/testify/require"
@@ -132,3 +133,76 @@ func TestNewReader(t *testing.T) {
 	require.Equal(uint64(0), rp.UnpackUint64(true), "Reader unpacked correctly.")
 	require.Error(rp.Err(), "Reader error not set.")
 }
+
+func TestNewAppendWriter(t *testing.T) {
+	require := require.New(t)
+
+	dst := make([]byte, 2, 2+consts.Uint64Len)
+	dst[0], dst[1] = 1, 2
+	wp := NewAppendWriter(dst, 2+consts.Uint64Len)
+	wp.PackUint64(3)
+	require.NoError(wp.Err())
+	require.Equal([]byte{1, 2, 0, 0, 0, 0, 0, 0, 0, 3}, wp.Bytes())
+	require.Equal(&dst[0], &wp.Bytes()[0], "Bytes not appended in place.")
+
+	// [limit] includes the bytes in [dst]
+	wp.PackByte(4)
+	require.ErrorIs(wp.Err(), wrappers.ErrInsufficientLength)
+}
+
+func TestWriterPool(t *testing.T) {
+	require := require.New(t)
+
+	wp := GetWriter(consts.Uint64Len, consts.Uint64Len)
+	wp.PackUint64(1)
+	wp.PackUint64(2)
+	require.Error(wp.Err())
+	PutWriter(wp)
+
+	// Writers are reset when they are reused
+	for i := 0; i < 10; i++ {
+		wp = GetWriter(consts.Uint64Len, consts.Uint64Len)
+		require.True(wp.Empty())
+		require.Empty(wp.Bytes())
+		require.NoError(wp.Err())
+		wp.PackUint64(uint64(i))
+		require.NoError(wp.Err())
+		require.Len(wp.Bytes(), consts.Uint64Len)
+		PutWriter(wp)
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	const fields = 64
+	size := fields * (consts.Uint64Len + StringLen(TestString))
+	pack := func(wp *Packer) {
+		for i := 0; i < fields; i++ {
+			wp.PackUint64(uint64(i))
+			wp.PackString(TestString)
+		}
+		if err := wp.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pack(NewWriter(size, size))
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wp := GetWriter(size, size)
+			pack(wp)
+			PutWriter(wp)
+		}
+	})
+	b.Run("append", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]byte, 0, size)
+		for i := 0; i < b.N; i++ {
+			pack(NewAppendWriter(dst[:0], size))
+		}
+	})
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"sync"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// maxPooledSize is the capacity above which buffers are not returned to the
+// pool, so that a single large object doesn't stay in memory.
+const maxPooledSize = consts.NetworkSizeLimit
+
+var writerPool = sync.Pool{
+	New: func() any {
+		return &Packer{}
+	},
+}
+
+// GetWriter is like [NewWriter] but reuses a buffer released with
+// [PutWriter]. It should be used when the packed bytes are only needed
+// temporarily (e.g. to hash them).
+func GetWriter(initial, limit int) *Packer {
+	p := writerPool.Get().(*Packer)
+	if cap(p.p.Bytes) < initial {
+		p.p.Bytes = make([]byte, 0, initial)
+	}
+	p.p.Bytes = p.p.Bytes[:0]
+	p.p.Offset = 0
+	p.p.MaxSize = limit
+	p.p.Err = nil
+	return p
+}
+
+// PutWriter releases [p] to be reused by [GetWriter]. The bytes of [p] must not
+// be used afterwards.
+func PutWriter(p *Packer) {
+	if cap(p.p.Bytes) > maxPooledSize {
+		return
+	}
+	writerPool.Put(p)
+}
@@ -4,12 +4,19 @@
 package codec
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/hypersdk/consts"
 )
 
 type decoder[T any, X any, Y any] struct {
-	f func(*Packer, X) (T, error)
-	y Y
+	f    func(*Packer, X) (T, error)
+	y    Y
+	name string
+
+	// versions is only set if the type was registered with
+	// [TypeParser.RegisterVersion] (in which case [f] and [y] are unused).
+	versions map[uint8]*decoder[T, X, Y]
 }
 
 // The number of types is limited to 255.
@@ -36,15 +43,96 @@ func (p *TypeParser[T, X, Y]) Register(id uint8, f func(*Packer, X) (T, error),
 	if _, ok := p.indexToDecoder[id]; ok {
 		return ErrDuplicateItem
 	}
-	p.indexToDecoder[id] = &decoder[T, X, Y]{f, y}
+	p.indexToDecoder[id] = &decoder[T, X, Y]{f: f, y: y}
+	return nil
+}
+
+// RegisterVersion registers [version] of the type [id]. Unlike types
+// registered with [Register], the encoding of a versioned type is prefixed
+// with its version (after its type ID), so new encodings of a type can be
+// added while older ones are still parsed. A type registered with [Register]
+// can't become versioned later (its historical encodings have no version), so
+// types that may change should be versioned from the start.
+//
+// Returns an error if [id] was registered with [Register], [version] of [id]
+// has already been registered, or the TypeParser is full.
+func (p *TypeParser[T, X, Y]) RegisterVersion(id uint8, version uint8, f func(*Packer, X) (T, error), y Y) error {
+	d, ok := p.indexToDecoder[id]
+	switch {
+	case !ok:
+		if len(p.indexToDecoder) == int(consts.MaxUint8)+1 {
+			return ErrTooManyItems
+		}
+		d = &decoder[T, X, Y]{versions: map[uint8]*decoder[T, X, Y]{}}
+		p.indexToDecoder[id] = d
+	case d.versions == nil:
+		return fmt.Errorf("%w: %d is not versioned", ErrDuplicateItem, id)
+	}
+	if _, ok := d.versions[version]; ok {
+		return fmt.Errorf("%w: version %d of %d", ErrDuplicateItem, version, id)
+	}
+	d.versions[version] = &decoder[T, X, Y]{f: f, y: y}
 	return nil
 }
 
+// IsVersioned returns true if [index] was registered with
+// [RegisterVersion].
+func (p *TypeParser[T, X, Y]) IsVersioned(index uint8) bool {
+	d, ok := p.indexToDecoder[index]
+	return ok && d.versions != nil
+}
+
+// SetName sets the name of the type registered at [id], which identifies it
+// in JSON encodings. Returns an error if [id] is not registered or [name] is
+// already used by another type.
+func (p *TypeParser[T, X, Y]) SetName(id uint8, name string) error {
+	d, ok := p.indexToDecoder[id]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownItem, id)
+	}
+	if other, ok := p.typeToIndex[name]; ok && other != id {
+		return fmt.Errorf("%w: %s", ErrDuplicateItem, name)
+	}
+	delete(p.typeToIndex, d.name)
+	d.name = name
+	p.typeToIndex[name] = id
+	return nil
+}
+
+// LookupName returns the name of the type registered at [index], if it has
+// one.
+func (p *TypeParser[T, X, Y]) LookupName(index uint8) (string, bool) {
+	d, ok := p.indexToDecoder[index]
+	if !ok || len(d.name) == 0 {
+		return "", false
+	}
+	return d.name, true
+}
+
+// LookupType returns the index of the type named [name].
+func (p *TypeParser[T, X, Y]) LookupType(name string) (uint8, bool) {
+	index, ok := p.typeToIndex[name]
+	return index, ok
+}
+
 // LookupIndex returns the decoder function and success of lookup of [index]
-// from Typeparser [p].
+// from Typeparser [p]. If [index] is versioned, the decoder of version 0 is
+// returned.
 func (p *TypeParser[T, X, Y]) LookupIndex(index uint8) (func(*Packer, X) (T, error), Y, bool) {
+	return p.LookupVersion(index, 0)
+}
+
+// LookupVersion returns the decoder function and success of lookup of
+// [version] of [index]. Types that are not versioned only have version 0.
+func (p *TypeParser[T, X, Y]) LookupVersion(index uint8, version uint8) (func(*Packer, X) (T, error), Y, bool) {
 	d, ok := p.indexToDecoder[index]
-	if ok {
+	switch {
+	case !ok:
+	case d.versions != nil:
+		if v, ok := d.versions[version]; ok {
+			return v.f, v.y, true
+		}
+	case version == 0:
 		return d.f, d.y, true
 	}
 	return nil, *new(Y), false
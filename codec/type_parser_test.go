@@ -34,6 +34,8 @@ func (*Blah3) GetTypeID() uint8 { return 2 }
 
 func TestTypeParser(t *testing.T) {
 	tp := NewTypeParser[Blah, any, bool]()
+	blah1ID := (&Blah1{}).GetTypeID()
+	blah2ID := (&Blah2{}).GetTypeID()
 
 	t.Run("empty parser", func(t *testing.T) {
 		require := require.New(t)
@@ -78,6 +80,29 @@ func TestTypeParser(t *testing.T) {
 		require.ErrorContains(err, "blah2")
 	})
 
+	t.Run("names", func(t *testing.T) {
+		require := require.New(t)
+
+		_, ok := tp.LookupName(blah1ID)
+		require.False(ok)
+		require.NoError(tp.SetName(blah1ID, "blah1"))
+		require.NoError(tp.SetName(blah2ID, "blah2"))
+		require.ErrorIs(tp.SetName(blah1ID, "blah2"), ErrDuplicateItem)
+		require.ErrorIs(tp.SetName(2, "blah3"), ErrUnknownItem)
+
+		name, ok := tp.LookupName(blah1ID)
+		require.True(ok)
+		require.Equal("blah1", name)
+		index, ok := tp.LookupType("blah2")
+		require.True(ok)
+		require.Equal(blah2ID, index)
+
+		// Renaming frees the previous name
+		require.NoError(tp.SetName(blah1ID, "blah"))
+		_, ok = tp.LookupType("blah1")
+		require.False(ok)
+	})
+
 	t.Run("duplicate item", func(t *testing.T) {
 		require := require.New(t)
 		require.ErrorIs(tp.Register((&Blah1{}).GetTypeID(), nil, true), ErrDuplicateItem)
@@ -94,3 +119,42 @@ func TestTypeParser(t *testing.T) {
 		require.ErrorIs(tp.Register(uint8(4), nil, true), ErrTooManyItems)
 	})
 }
+
+func TestTypeParserVersions(t *testing.T) {
+	require := require.New(t)
+
+	tp := NewTypeParser[Blah, any, bool]()
+	blah1ID := (&Blah1{}).GetTypeID()
+	blah2ID := (&Blah2{}).GetTypeID()
+	require.NoError(tp.Register(blah1ID, func(*Packer, any) (Blah, error) { return &Blah1{}, nil }, false))
+	require.NoError(tp.RegisterVersion(blah2ID, 0, func(*Packer, any) (Blah, error) { return &Blah2{}, nil }, false))
+	require.NoError(tp.RegisterVersion(blah2ID, 1, func(*Packer, any) (Blah, error) { return &Blah3{}, nil }, true))
+
+	require.False(tp.IsVersioned(blah1ID))
+	require.True(tp.IsVersioned(blah2ID))
+	require.False(tp.IsVersioned(2))
+
+	// Types that are not versioned only have version 0
+	_, _, ok := tp.LookupVersion(blah1ID, 0)
+	require.True(ok)
+	_, _, ok = tp.LookupVersion(blah1ID, 1)
+	require.False(ok)
+
+	f, b, ok := tp.LookupVersion(blah2ID, 1)
+	require.True(ok)
+	require.True(b)
+	res, err := f(nil, nil)
+	require.NoError(err)
+	require.Equal("blah3", res.Bark())
+	f, _, ok = tp.LookupIndex(blah2ID)
+	require.True(ok)
+	res, err = f(nil, nil)
+	require.NoError(err)
+	require.Equal("blah2", res.Bark())
+	_, _, ok = tp.LookupVersion(blah2ID, 2)
+	require.False(ok)
+
+	require.ErrorIs(tp.RegisterVersion(blah1ID, 1, nil, false), ErrDuplicateItem)
+	require.ErrorIs(tp.RegisterVersion(blah2ID, 1, nil, false), ErrDuplicateItem)
+	require.ErrorIs(tp.Register(blah2ID, nil, false), ErrDuplicateItem)
+}
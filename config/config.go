@@ -11,6 +11,12 @@ import (
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/network"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/slo"
 	"github.com/ava-labs/hypersdk/trace"
 )
 
@@ -18,12 +24,24 @@ type Config struct{}
 
 func (c *Config) GetLogLevel() logging.Level                { return logging.Info }
 func (c *Config) GetAuthVerificationCores() int             { return 1 }
+func (c *Config) GetAuthVerificationMinCores() int          { return 1 }
 func (c *Config) GetRootGenerationCores() int               { return 1 }
 func (c *Config) GetTransactionExecutionCores() int         { return 1 }
+func (c *Config) GetExecutionTrace() bool                   { return false }
 func (c *Config) GetMempoolSize() int                       { return 2_048 }
 func (c *Config) GetMempoolSponsorSize() int                { return 32 }
 func (c *Config) GetMempoolExemptSponsors() []codec.Address { return nil }
+func (c *Config) GetMempoolMaxNonceGap() int                { return 16 }
+func (c *Config) GetMempoolCongestionPolicy() string        { return "accept" }
+func (c *Config) GetMempoolBundleSize() int                 { return 64 }
+func (c *Config) GetMempoolFeeExemptSize() int              { return 64 }
+func (c *Config) GetFCFSOrdering() bool                     { return false }
 func (c *Config) GetStreamingBacklogSize() int              { return 1024 }
+func (c *Config) GetStreamingReplaySize() int               { return 128 }
+func (c *Config) GetStreamingMaxConnections() int           { return pubsub.MaxConnections }
+func (c *Config) GetStreamingMaxConnectionsPerIP() int      { return pubsub.MaxConnectionsPerIP }
+func (c *Config) GetStreamingAuthTokens() []string          { return nil }
+func (c *Config) GetStreamingMempool() bool                 { return false }
 func (c *Config) GetIntermediateNodeCacheSize() int         { return 4 * units.GiB }
 func (c *Config) GetStateIntermediateWriteBufferSize() int  { return 32 * units.MiB }
 func (c *Config) GetStateIntermediateWriteBatchSize() int   { return 4 * units.MiB }
@@ -33,6 +51,7 @@ func (c *Config) GetStateSyncParallelism() int              { return 4 }
 func (c *Config) GetStateSyncServerDelay() time.Duration    { return 0 } // used for testing
 
 func (c *Config) GetParsedBlockCacheSize() int     { return 128 }
+func (c *Config) GetStateCacheSize() int           { return 4_096 }
 func (c *Config) GetStateHistoryLength() int       { return 256 }
 func (c *Config) GetAcceptedBlockWindowCache() int { return 128 }    // 256MB at 2MB blocks
 func (c *Config) GetAcceptedBlockWindow() int      { return 50_000 } // ~3.5hr with 250ms block time (100GB at 2MB)
@@ -43,7 +62,27 @@ func (c *Config) GetContinuousProfilerConfig() *profiler.Config {
 	return &profiler.Config{Enabled: false}
 }
 func (c *Config) GetVerifyAuth() bool                    { return true }
+func (c *Config) GetED25519Backend() string              { return string(ed25519.DefaultBackend) }
 func (c *Config) GetTargetBuildDuration() time.Duration  { return 100 * time.Millisecond }
 func (c *Config) GetProcessingBuildSkip() int            { return 16 }
 func (c *Config) GetTargetGossipDuration() time.Duration { return 20 * time.Millisecond }
 func (c *Config) GetBlockCompactionFrequency() int       { return 32 } // 64 MB of deletion if 2 MB blocks
+func (c *Config) GetCompactionInterval() time.Duration   { return 0 }
+func (c *Config) GetStorageStatsInterval() time.Duration { return 5 * time.Minute }
+func (c *Config) GetBlockBackfillDepth() int             { return 0 }
+func (c *Config) GetSLOConfig() *slo.Config              { return slo.DefaultConfig() }
+func (c *Config) GetAPINode() bool                       { return false }
+func (c *Config) GetShutdownTimeout() time.Duration      { return 10 * time.Second }
+func (c *Config) GetAdminAPIEnabled() bool               { return false }
+func (c *Config) GetAdminAPITokens() []string            { return nil }
+func (c *Config) GetCORSAllowedOrigins() []string        { return nil }
+func (c *Config) GetRPCLimitConfig() *rpc.LimitConfig    { return rpc.NewDefaultLimitConfig() }
+func (c *Config) GetReloadConfigFile() string            { return "" }
+func (c *Config) GetBlobRetention() uint64               { return 4_096 }
+func (c *Config) GetWarpMessageRetention() uint64        { return 262_144 }
+func (c *Config) GetSeenTimeGranularity() int64          { return consts.MillisecondsPerSecond } // tx timestamps are whole seconds
+func (c *Config) GetSeenHeightGranularity() int64        { return 1 }
+
+func (c *Config) GetServeLimitConfig() *network.ServeLimitConfig {
+	return network.NewDefaultServeLimitConfig()
+}
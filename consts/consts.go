@@ -28,5 +28,6 @@ const (
 	MaxInt                = int(MaxUint >> 1)
 	MaxUint64Offset       = 63
 	MaxUint64             = ^uint64(0)
+	MaxInt64              = int64(MaxUint64 >> 1)
 	MillisecondsPerSecond = 1000
 )
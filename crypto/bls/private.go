@@ -38,3 +38,10 @@ func PublicFromPrivateKey(pk *PrivateKey) *PublicKey {
 func Sign(msg []byte, pk *PrivateKey) *Signature {
 	return bls.Sign(pk, msg)
 }
+
+// SignProofOfPossession proves ownership of the public key of [pk]. It must be
+// checked before the public key is aggregated with others to prevent
+// rogue-key attacks.
+func SignProofOfPossession(pk *PrivateKey) *Signature {
+	return bls.SignProofOfPossession(pk, PublicKeyToBytes(PublicFromPrivateKey(pk)))
+}
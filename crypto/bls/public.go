@@ -33,3 +33,7 @@ func AggregatePublicKeys(pks []*PublicKey) (*PublicKey, error) {
 func Verify(msg []byte, pk *PublicKey, sig *Signature) bool {
 	return bls.Verify(pk, sig, msg)
 }
+
+func VerifyProofOfPossession(pk *PublicKey, sig *Signature) bool {
+	return bls.VerifyProofOfPossession(pk, sig, PublicKeyToBytes(pk))
+}
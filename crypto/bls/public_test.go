@@ -84,3 +84,21 @@ func TestAggregatePublicKeys(t *testing.T) {
 	// Verifies aggregate signature with aggregate public key
 	require.True(Verify(msg, aggPks, aggSigs))
 }
+
+func TestVerifyProofOfPossession(t *testing.T) {
+	require := require.New(t)
+
+	sk1, err := GeneratePrivateKey()
+	require.NoError(err)
+	pk1 := PublicFromPrivateKey(sk1)
+	sk2, err := GeneratePrivateKey()
+	require.NoError(err)
+	pk2 := PublicFromPrivateKey(sk2)
+
+	pop := SignProofOfPossession(sk1)
+	require.True(VerifyProofOfPossession(pk1, pop))
+	require.False(VerifyProofOfPossession(pk2, pop))
+
+	// A regular signature over the public key is not a proof of possession
+	require.False(VerifyProofOfPossession(pk1, Sign(PublicKeyToBytes(pk1), sk1)))
+}
@@ -49,3 +49,20 @@ func TestAggregateSignaturesNoop(t *testing.T) {
 	require.Equal(sig, aggSig)
 	require.Equal(sigBytes, aggSigBytes)
 }
+
+func BenchmarkVerify(b *testing.B) {
+	msg := utils.RandomBytes(128)
+	sk, err := GeneratePrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	pk := PublicFromPrivateKey(sk)
+	sig := Sign(msg, sk)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !Verify(msg, pk, sig) {
+			b.Fatal("invalid signature")
+		}
+	}
+}
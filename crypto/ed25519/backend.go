@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/hdevalence/ed25519consensus"
+
+	oed25519 "github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
+)
+
+// Backend is an implementation of ed25519 signature verification.
+//
+// All backends enforce ZIP-215, so they accept exactly the same signatures and
+// can be mixed freely across validators. They only differ in speed, which
+// depends on the platform (run "go test -bench Backend ./crypto/ed25519" to
+// compare them).
+type Backend string
+
+const (
+	// ConsensusBackend verifies signatures with
+	// github.com/hdevalence/ed25519consensus.
+	ConsensusBackend Backend = "consensus"
+
+	// VoiBackend verifies signatures with
+	// github.com/oasisprotocol/curve25519-voi, which is usually faster on
+	// amd64.
+	VoiBackend Backend = "voi"
+
+	DefaultBackend = ConsensusBackend
+)
+
+var (
+	ErrUnknownBackend = errors.New("unknown ed25519 backend")
+
+	voiOptions = &oed25519.Options{
+		Verify: oed25519.VerifyOptionsZIP_215,
+	}
+
+	backend atomic.Value
+)
+
+func init() {
+	backend.Store(DefaultBackend)
+}
+
+// Backends returns all supported backends.
+func Backends() []Backend {
+	return []Backend{ConsensusBackend, VoiBackend}
+}
+
+// SetBackend changes the backend used by [Verify] and [NewBatch]. Batches
+// created before the change keep using the previous backend.
+func SetBackend(b Backend) error {
+	switch b {
+	case ConsensusBackend, VoiBackend:
+		backend.Store(b)
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownBackend, b)
+	}
+}
+
+// GetBackend returns the backend used by [Verify] and [NewBatch].
+func GetBackend() Backend {
+	return backend.Load().(Backend)
+}
+
+func verify(b Backend, msg []byte, p PublicKey, s Signature) bool {
+	if b == VoiBackend {
+		return oed25519.VerifyWithOptions(p[:], msg, s[:], voiOptions)
+	}
+	return ed25519consensus.Verify(p[:], msg, s[:])
+}
+
+type batchVerifier interface {
+	Add(msg []byte, p PublicKey, s Signature)
+	Verify() bool
+}
+
+func newBatchVerifier(b Backend, size int) batchVerifier {
+	if b == VoiBackend {
+		return &voiBatch{oed25519.NewBatchVerifierWithCapacity(size)}
+	}
+	return &consensusBatch{ed25519consensus.NewPreallocatedBatchVerifier(size)}
+}
+
+type consensusBatch struct {
+	bv ed25519consensus.BatchVerifier
+}
+
+func (c *consensusBatch) Add(msg []byte, p PublicKey, s Signature) {
+	c.bv.Add(p[:], msg, s[:])
+}
+
+func (c *consensusBatch) Verify() bool {
+	return c.bv.Verify()
+}
+
+type voiBatch struct {
+	bv *oed25519.BatchVerifier
+}
+
+func (v *voiBatch) Add(msg []byte, p PublicKey, s Signature) {
+	v.bv.AddWithOptions(p[:], msg, s[:], voiOptions)
+}
+
+func (v *voiBatch) Verify() bool {
+	return v.bv.VerifyBatchOnly(nil)
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ed25519
+
+import (
+	"crypto/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBackend(t *testing.T) {
+	require := require.New(t)
+	defer func() {
+		require.NoError(SetBackend(DefaultBackend))
+	}()
+
+	require.Equal(DefaultBackend, GetBackend())
+	for _, b := range Backends() {
+		require.NoError(SetBackend(b))
+		require.Equal(b, GetBackend())
+	}
+	require.ErrorIs(SetBackend("unknown"), ErrUnknownBackend)
+	require.Equal(VoiBackend, GetBackend())
+}
+
+func TestBackendsAgree(t *testing.T) {
+	require := require.New(t)
+	defer func() {
+		require.NoError(SetBackend(DefaultBackend))
+	}()
+
+	msg := []byte("msg")
+	sig := Sign(msg, TestPrivateKey)
+	pub := TestPrivateKey.PublicKey()
+	badSig := sig
+	badSig[0]++
+
+	for _, b := range Backends() {
+		require.NoError(SetBackend(b))
+		require.True(Verify(msg, pub, sig), b)
+		require.False(Verify(msg, pub, badSig), b)
+
+		batch := NewBatch(2)
+		batch.Add(msg, pub, sig)
+		batch.Add(msg, pub, sig)
+		require.NoError(batch.VerifyAsync()(), b)
+
+		batch = NewBatch(2)
+		batch.Add(msg, pub, sig)
+		batch.Add(msg, pub, badSig)
+		require.False(batch.Verify(), b)
+	}
+}
+
+func BenchmarkBackendVerify(b *testing.B) {
+	msg := make([]byte, 128)
+	if _, err := rand.Read(msg); err != nil {
+		b.Fatal(err)
+	}
+	priv, err := GeneratePrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	pub := priv.PublicKey()
+	sig := Sign(msg, priv)
+	for _, backend := range Backends() {
+		b.Run(string(backend), func(b *testing.B) {
+			if err := SetBackend(backend); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !Verify(msg, pub, sig) {
+					b.Fatal("invalid signature")
+				}
+			}
+		})
+	}
+	if err := SetBackend(DefaultBackend); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkBackendBatchAddVerify(b *testing.B) {
+	for _, numItems := range []int{MinBatchSize, 64, 512, 4096} {
+		pubs := make([]PublicKey, numItems)
+		msgs := make([][]byte, numItems)
+		sigs := make([]Signature, numItems)
+		for i := 0; i < numItems; i++ {
+			priv, err := GeneratePrivateKey()
+			if err != nil {
+				b.Fatal(err)
+			}
+			msg := make([]byte, 128)
+			if _, err := rand.Read(msg); err != nil {
+				b.Fatal(err)
+			}
+			pubs[i] = priv.PublicKey()
+			msgs[i] = msg
+			sigs[i] = Sign(msg, priv)
+		}
+		for _, backend := range Backends() {
+			b.Run(string(backend)+"/"+strconv.Itoa(numItems), func(b *testing.B) {
+				if err := SetBackend(backend); err != nil {
+					b.Fatal(err)
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					batch := NewBatch(numItems)
+					for j := 0; j < numItems; j++ {
+						batch.Add(msgs[j], pubs[j], sigs[j])
+					}
+					if !batch.Verify() {
+						b.Fatal("invalid signature")
+					}
+				}
+			})
+		}
+	}
+	if err := SetBackend(DefaultBackend); err != nil {
+		b.Fatal(err)
+	}
+}
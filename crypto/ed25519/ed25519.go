@@ -5,8 +5,7 @@ package ed25519
 
 import (
 	"crypto/ed25519"
-
-	"github.com/hdevalence/ed25519consensus"
+	"encoding/hex"
 
 	"github.com/ava-labs/hypersdk/crypto"
 )
@@ -71,19 +70,19 @@ func Sign(msg []byte, pk PrivateKey) Signature {
 
 // Verify returns whether s is a valid signature of msg by p.
 func Verify(msg []byte, p PublicKey, s Signature) bool {
-	return ed25519consensus.Verify(p[:], msg, s[:])
+	return verify(GetBackend(), msg, p, s)
 }
 
 type Batch struct {
-	bv ed25519consensus.BatchVerifier
+	bv batchVerifier
 }
 
 func NewBatch(size int) *Batch {
-	return &Batch{bv: ed25519consensus.NewPreallocatedBatchVerifier(size)}
+	return &Batch{bv: newBatchVerifier(GetBackend(), size)}
 }
 
 func (b *Batch) Add(msg []byte, p PublicKey, s Signature) {
-	b.bv.Add(p[:], msg, s[:])
+	b.bv.Add(msg, p, s)
 }
 
 func (b *Batch) Verify() bool {
@@ -98,3 +97,30 @@ func (b *Batch) VerifyAsync() func() error {
 		return nil
 	}
 }
+
+// MarshalText encodes [p] as hex.
+func (p PublicKey) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(p[:])), nil
+}
+
+func (p *PublicKey) UnmarshalText(b []byte) error {
+	return unmarshalHex(p[:], b)
+}
+
+// MarshalText encodes [s] as hex.
+func (s Signature) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(s[:])), nil
+}
+
+func (s *Signature) UnmarshalText(b []byte) error {
+	return unmarshalHex(s[:], b)
+}
+
+// unmarshalHex decodes hex [b] into [dst], which it must fill exactly.
+func unmarshalHex(dst []byte, b []byte) error {
+	if hex.DecodedLen(len(b)) != len(dst) {
+		return crypto.ErrInvalidHexLength
+	}
+	_, err := hex.Decode(dst, b)
+	return err
+}
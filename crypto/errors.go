@@ -9,4 +9,5 @@ var (
 	ErrInvalidPrivateKey = errors.New("invalid private key")
 	ErrInvalidPublicKey  = errors.New("invalid public key")
 	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrInvalidHexLength  = errors.New("invalid hex length")
 )
@@ -0,0 +1,12 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hd
+
+import "errors"
+
+var (
+	ErrInvalidMnemonic  = errors.New("invalid mnemonic")
+	ErrInvalidPath      = errors.New("invalid derivation path")
+	ErrHardenedRequired = errors.New("ed25519 only supports hardened derivation")
+)
@@ -0,0 +1,142 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package hd derives keys from a single seed (usually recovered from a
+// mnemonic) as specified by SLIP-0010, the generalization of BIP-32 to
+// ed25519 and secp256r1.
+//
+// source: https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+package hd
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	hed25519 "github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+const (
+	// HardenedOffset is added to the index of hardened children.
+	HardenedOffset uint32 = 1 << 31
+
+	// DefaultPath follows BIP-44 with the coin type of Avalanche. All levels
+	// are hardened so that it can be used with every curve.
+	DefaultPath = "m/44'/9000'/0'/0'/0'"
+
+	ed25519Curve   = "ed25519 seed"
+	secp256r1Curve = "Nist256p1 seed"
+)
+
+var secp256r1Order = elliptic.P256().Params().N
+
+// ParsePath parses a derivation path like "m/44'/9000'/0'/0'/0'". Hardened
+// indices are marked with "'" or "h".
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("%w: %s does not start with m", ErrInvalidPath, path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+		index, err := strconv.ParseUint(segment, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPath, path)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// DeriveED25519 derives the ed25519 key at [path] from [seed]. Every index of
+// [path] must be hardened.
+func DeriveED25519(seed []byte, path string) (hed25519.PrivateKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return hed25519.EmptyPrivateKey, err
+	}
+	key, chainCode := split(hmacSHA512([]byte(ed25519Curve), seed))
+	for _, index := range indices {
+		if index < HardenedOffset {
+			return hed25519.EmptyPrivateKey, ErrHardenedRequired
+		}
+		key, chainCode = split(hmacSHA512(chainCode, hardenedData(key, index)))
+	}
+	return hed25519.PrivateKey(ed25519.NewKeyFromSeed(key)), nil
+}
+
+// DeriveSECP256R1 derives the secp256r1 key at [path] from [seed].
+func DeriveSECP256R1(seed []byte, path string) (secp256r1.PrivateKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return secp256r1.EmptyPrivateKey, err
+	}
+
+	// Keys outside of the curve order are skipped by hashing again
+	i := hmacSHA512([]byte(secp256r1Curve), seed)
+	for {
+		key, _ := split(i)
+		if k := new(big.Int).SetBytes(key); k.Sign() != 0 && k.Cmp(secp256r1Order) < 0 {
+			break
+		}
+		i = hmacSHA512([]byte(secp256r1Curve), i)
+	}
+	key, chainCode := split(i)
+	for _, index := range indices {
+		var data []byte
+		if index >= HardenedOffset {
+			data = hardenedData(key, index)
+		} else {
+			pk := secp256r1.PrivateKey(key).PublicKey()
+			data = binary.BigEndian.AppendUint32(pk[:], index)
+		}
+		for {
+			i := hmacSHA512(chainCode, data)
+			il, ir := split(i)
+			child := new(big.Int).SetBytes(il)
+			if child.Cmp(secp256r1Order) < 0 {
+				child.Add(child, new(big.Int).SetBytes(key))
+				child.Mod(child, secp256r1Order)
+				if child.Sign() != 0 {
+					key = child.FillBytes(make([]byte, secp256r1.PrivateKeyLen))
+					chainCode = ir
+					break
+				}
+			}
+			data = binary.BigEndian.AppendUint32(append([]byte{1}, ir...), index)
+		}
+	}
+	return secp256r1.PrivateKey(key), nil
+}
+
+func hmacSHA512(key []byte, data []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	_, _ = h.Write(data)
+	return h.Sum(nil)
+}
+
+// split returns the key and chain code of the output of an HMAC.
+func split(i []byte) ([]byte, []byte) {
+	return i[:32], i[32:]
+}
+
+func hardenedData(key []byte, index uint32) []byte {
+	data := make([]byte, 0, 1+len(key)+4)
+	data = append(data, 0)
+	data = append(data, key...)
+	return binary.BigEndian.AppendUint32(data, index)
+}
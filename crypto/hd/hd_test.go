@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test vector 1 of SLIP-0010
+var testSeed, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+func TestParsePath(t *testing.T) {
+	require := require.New(t)
+
+	indices, err := ParsePath("m/44'/9000h/0/1")
+	require.NoError(err)
+	require.Equal([]uint32{44 + HardenedOffset, 9000 + HardenedOffset, 0, 1}, indices)
+
+	indices, err = ParsePath("m")
+	require.NoError(err)
+	require.Empty(indices)
+
+	for _, path := range []string{"", "44'/0'", "m/", "m/-1", "m/2147483648", "m/a'"} {
+		_, err = ParsePath(path)
+		require.ErrorIs(err, ErrInvalidPath, path)
+	}
+}
+
+func TestDeriveED25519(t *testing.T) {
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{"m/0'", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{"m/0'/1'", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+		{"m/0'/1'/2'/2'/1000000000'", "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require := require.New(t)
+			priv, err := DeriveED25519(testSeed, tt.path)
+			require.NoError(err)
+			require.Equal(tt.key, hex.EncodeToString(priv[:32]))
+		})
+	}
+
+	_, err := DeriveED25519(testSeed, "m/0'/1")
+	require.ErrorIs(t, err, ErrHardenedRequired)
+}
+
+func TestDeriveSECP256R1(t *testing.T) {
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2"},
+		{"m/0'", "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c"},
+		{"m/0'/1", "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require := require.New(t)
+			priv, err := DeriveSECP256R1(testSeed, tt.path)
+			require.NoError(err)
+			require.Equal(tt.key, hex.EncodeToString(priv[:]))
+		})
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	require := require.New(t)
+
+	mnemonic, err := NewMnemonic()
+	require.NoError(err)
+	seed, err := NewSeed(mnemonic, "")
+	require.NoError(err)
+
+	// Recovering the mnemonic derives the same keys
+	recovered, err := NewSeed("  "+mnemonic+"\n", "")
+	require.NoError(err)
+	require.Equal(seed, recovered)
+	priv, err := DeriveED25519(seed, DefaultPath)
+	require.NoError(err)
+	recoveredPriv, err := DeriveED25519(recovered, DefaultPath)
+	require.NoError(err)
+	require.Equal(priv, recoveredPriv)
+
+	// The passphrase changes the seed
+	protected, err := NewSeed(mnemonic, "passphrase")
+	require.NoError(err)
+	require.NotEqual(seed, protected)
+
+	_, err = NewSeed("not a mnemonic", "")
+	require.ErrorIs(err, ErrInvalidMnemonic)
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package hd
+
+import (
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// MnemonicEntropy is the entropy (in bits) of mnemonics created by
+// [NewMnemonic], which results in 24 words.
+const MnemonicEntropy = 256
+
+// NewMnemonic returns a new random BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MnemonicEntropy)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// NewSeed returns the BIP-39 seed of [mnemonic], which keys are derived from
+// with [DeriveED25519] and [DeriveSECP256R1]. [passphrase] is optional.
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	return bip39.NewSeed(mnemonic, passphrase), nil
+}
@@ -8,12 +8,15 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
 
 	"golang.org/x/crypto/cryptobyte"
 	"golang.org/x/crypto/cryptobyte/asn1"
+
+	"github.com/ava-labs/hypersdk/crypto"
 )
 
 const (
@@ -111,6 +114,16 @@ func generateSignature(r, s *big.Int) Signature {
 	return Signature(sigBytes)
 }
 
+// NewNormalizedSignature returns the [Signature] of [r] and [s] (e.g. parsed
+// with [ParseASN1Signature]), adjusting [s] to be in the lower half of the
+// curve order.
+//
+// Signers that don't normalize [s] (like WebAuthn authenticators) produce
+// signatures that [Verify] only accepts after this adjustment.
+func NewNormalizedSignature(r, s []byte) Signature {
+	return generateSignature(new(big.Int).SetBytes(r), normalizeS(new(big.Int).SetBytes(s)))
+}
+
 // Sign returns a valid signature for msg using pk.
 //
 // This function also adjusts [s] to be in the lower
@@ -233,3 +246,30 @@ func denormalizedSign(msg []byte, pk PrivateKey) (Signature, error) {
 		}
 	}
 }
+
+// MarshalText encodes [p] as hex.
+func (p PublicKey) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(p[:])), nil
+}
+
+func (p *PublicKey) UnmarshalText(b []byte) error {
+	return unmarshalHex(p[:], b)
+}
+
+// MarshalText encodes [s] as hex.
+func (s Signature) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(s[:])), nil
+}
+
+func (s *Signature) UnmarshalText(b []byte) error {
+	return unmarshalHex(s[:], b)
+}
+
+// unmarshalHex decodes hex [b] into [dst], which it must fill exactly.
+func unmarshalHex(dst []byte, b []byte) error {
+	if hex.DecodedLen(len(b)) != len(dst) {
+		return crypto.ErrInvalidHexLength
+	}
+	_, err := hex.Decode(dst, b)
+	return err
+}
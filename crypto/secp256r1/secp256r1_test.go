@@ -147,3 +147,39 @@ func TestASN1Parsing(t *testing.T) {
 	msg := []byte("aaa")
 	require.True(Verify(msg, PublicKey(cpk), Signature(append(r, s...))))
 }
+
+func TestNewNormalizedSignature(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := GeneratePrivateKey()
+	require.NoError(err)
+	pub := priv.PublicKey()
+	msg := []byte("hello")
+	sig, err := denormalizedSign(msg, priv)
+	require.NoError(err)
+	require.False(Verify(msg, pub, sig))
+
+	nsig := NewNormalizedSignature(sig[:rsLen], sig[rsLen:])
+	require.True(Verify(msg, pub, nsig))
+	require.Equal(nsig, NewNormalizedSignature(nsig[:rsLen], nsig[rsLen:]))
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("msg")
+	priv, err := GeneratePrivateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	pub := priv.PublicKey()
+	sig, err := Sign(msg, priv)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !Verify(msg, pub, sig) {
+			b.Fatal("invalid signature")
+		}
+	}
+}
@@ -96,6 +96,15 @@ func (eh *ExpiryHeap[T]) PopMin() (T, bool) {
 	return item, true
 }
 
+// Get returns the item with [id] in eh, if it exists.
+func (eh *ExpiryHeap[T]) Get(id ids.ID) (T, bool) {
+	entry, ok := eh.minHeap.Get(id)
+	if !ok {
+		return *new(T), false
+	}
+	return entry.Item, true
+}
+
 // Has returns if [item] is in eh.
 func (eh *ExpiryHeap[T]) Has(item ids.ID) bool {
 	return eh.minHeap.Has(item)
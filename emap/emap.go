@@ -22,12 +22,36 @@ type Item interface {
 	Expiry() int64 // method for returing this items timestamp
 }
 
+// defaultCapacity is the number of buckets preallocated by [NewEMap].
+const defaultCapacity = 120
+
+// Config customizes the bucketing of an EMap.
+type Config[T Item] struct {
+	// Expiry keys items instead of [Item.Expiry] (for example, to track them
+	// by height). Items keyed by 0 are not tracked.
+	Expiry func(T) int64
+
+	// Granularity is the range of expiries grouped in each bucket (defaults
+	// to 1). Expiries are rounded up to a multiple of [Granularity], so
+	// items may be retained for up to [Granularity]-1 longer than their
+	// expiry but never evicted early. Coarser buckets keep [SetMin] cheap
+	// when expiries are dense (like with sub-second blocks) or spread out
+	// (like with long validity windows).
+	Granularity int64
+
+	// Capacity is the number of buckets to preallocate.
+	Capacity int
+}
+
 // A Emap implements en eviction map that stores the status
 // of txs and their linked timestamps. The type [T] must implement the
 // Item interface.
 type EMap[T Item] struct {
 	mu sync.RWMutex
 
+	expiry      func(T) int64 // if nil, [Item.Expiry] is used
+	granularity int64
+
 	bh    *heap.Heap[*bucket, int64]
 	seen  set.Set[ids.ID]   // Stores a set of unique tx ids
 	times map[int64]*bucket // Uses timestamp as keys to map to buckets of ids.
@@ -35,10 +59,26 @@ type EMap[T Item] struct {
 
 // NewEMap returns a pointer to a instance of an empty EMap struct.
 func NewEMap[T Item]() *EMap[T] {
+	return NewEMapWithConfig(Config[T]{})
+}
+
+// NewEMapWithConfig returns an empty EMap that buckets items as specified by
+// [cfg].
+func NewEMapWithConfig[T Item](cfg Config[T]) *EMap[T] {
+	granularity := cfg.Granularity
+	if granularity <= 0 {
+		granularity = 1
+	}
+	capacity := cfg.Capacity
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
 	return &EMap[T]{
-		seen:  set.Set[ids.ID]{},
-		times: make(map[int64]*bucket),
-		bh:    heap.New[*bucket, int64](120, true),
+		expiry:      cfg.Expiry,
+		granularity: granularity,
+		seen:        set.Set[ids.ID]{},
+		times:       make(map[int64]*bucket, capacity),
+		bh:          heap.New[*bucket, int64](capacity, true),
 	}
 }
 
@@ -48,10 +88,17 @@ func (e *EMap[T]) Add(items []T) {
 	defer e.mu.Unlock()
 
 	for _, item := range items {
-		e.add(item.ID(), item.Expiry())
+		e.add(item.ID(), e.itemExpiry(item))
 	}
 }
 
+func (e *EMap[T]) itemExpiry(item T) int64 {
+	if e.expiry == nil {
+		return item.Expiry()
+	}
+	return e.expiry(item)
+}
+
 // Add adds an id with a timestampt [t] to the EMap. If the timestamp
 // is genesis(0) or the id has been seen already, add returns. The id is
 // added to a bucket with timestamp [t]. If no bucket exists, add creates a
@@ -61,6 +108,7 @@ func (e *EMap[T]) add(id ids.ID, t int64) {
 	if t == 0 {
 		return
 	}
+	t = e.bucketKey(t)
 
 	// Check if already exists
 	if e.seen.Contains(id) {
@@ -88,6 +136,19 @@ func (e *EMap[T]) add(id ids.ID, t int64) {
 	})
 }
 
+// bucketKey rounds [t] up to a multiple of the granularity of e.
+func (e *EMap[T]) bucketKey(t int64) int64 {
+	r := t % e.granularity
+	switch {
+	case r > 0:
+		return t + e.granularity - r
+	case r < 0:
+		return t - r
+	default:
+		return t
+	}
+}
+
 // SetMin removes all buckets with a lower
 // timestamp than [t] from e's bucketHeap.
 func (e *EMap[T]) SetMin(t int64) []ids.ID {
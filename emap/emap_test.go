@@ -243,3 +243,41 @@ func TestSetMinPopsAll(t *testing.T) {
 
 	require.Equal(emptyEmap, e, "EMap not empty")
 }
+
+func TestEmapWithExpiry(t *testing.T) {
+	require := require.New(t)
+	e := NewEMapWithConfig(Config[*TestTx]{Expiry: func(tx *TestTx) int64 { return tx.t / 10 }})
+
+	tx1 := &TestTx{id: ids.GenerateTestID(), t: 10}
+	tx2 := &TestTx{id: ids.GenerateTestID(), t: 5} // keyed by 0, so not tracked
+	e.Add([]*TestTx{tx1, tx2})
+	require.True(e.Any([]*TestTx{tx1}))
+	require.False(e.Any([]*TestTx{tx2}))
+
+	// Items are evicted by their key, not by [Expiry]
+	require.Empty(e.SetMin(1))
+	require.Equal([]ids.ID{tx1.id}, e.SetMin(2))
+	require.False(e.Any([]*TestTx{tx1}))
+}
+
+func TestEmapGranularity(t *testing.T) {
+	require := require.New(t)
+	e := NewEMapWithConfig(Config[*TestTx]{Granularity: 10, Capacity: 1})
+
+	txs := []*TestTx{
+		{id: ids.GenerateTestID(), t: 1},
+		{id: ids.GenerateTestID(), t: 10},
+		{id: ids.GenerateTestID(), t: 11},
+		{id: ids.GenerateTestID(), t: -5},
+	}
+	e.Add(txs)
+	require.Len(e.times, 3) // buckets 0, 10, and 20
+
+	// Items are never evicted before their expiry
+	require.Equal([]ids.ID{txs[3].id}, e.SetMin(2))
+	require.True(e.Any(txs[:1]))
+	require.ElementsMatch([]ids.ID{txs[0].id, txs[1].id}, e.SetMin(11))
+	require.True(e.Any(txs[2:3]))
+	require.Equal([]ids.ID{txs[2].id}, e.SetMin(21))
+	require.Empty(e.times)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Cancel)(nil)
+
+// Cancel voids [TxID], a pending transaction sponsored by the actor, so that
+// it fails if it is later included in a block. [Expiry] should be the
+// timestamp of the cancelled transaction (after which it can't be included
+// anyway) and must be within the validity window.
+type Cancel struct {
+	TxID   ids.ID `json:"txId"`
+	Expiry int64  `json:"expiry"`
+}
+
+func (*Cancel) GetTypeID() uint8 {
+	return mconsts.CancelID
+}
+
+func (*Cancel) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{string(cancelKey(actor))}
+}
+
+func (*Cancel) StateKeysMaxChunks() []uint16 {
+	return []uint16{chain.CancelKeyChunks}
+}
+
+func (*Cancel) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *Cancel) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if !r.GetTxCancellation() {
+		return false, CancelComputeUnits, OutputCancellationDisabled, nil, nil
+	}
+	if c.Expiry < timestamp || c.Expiry > timestamp+r.GetValidityWindow() {
+		return false, CancelComputeUnits, OutputInvalidExpiry, nil, nil
+	}
+	if err := chain.CancelTx(ctx, mu, cancelKey(actor), timestamp, c.TxID, c.Expiry); err != nil {
+		return false, CancelComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, CancelComputeUnits, nil, nil, nil
+}
+
+func cancelKey(actor codec.Address) []byte {
+	return keys.EncodeChunks(storage.CancelKey(actor), chain.CancelKeyChunks)
+}
+
+func (*Cancel) MaxComputeUnits(chain.Rules) uint64 {
+	return CancelComputeUnits
+}
+
+func (*Cancel) Size() int {
+	return consts.IDLen + consts.Int64Len
+}
+
+func (c *Cancel) Marshal(p *codec.Packer) {
+	p.PackID(c.TxID)
+	p.PackInt64(c.Expiry)
+}
+
+func UnmarshalCancel(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var cancel Cancel
+	p.UnpackID(true, &cancel.TxID)
+	cancel.Expiry = p.UnpackInt64(true)
+	return &cancel, p.Err()
+}
+
+func (*Cancel) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+)
+
+// encodeCancelled is the value stored at the cancel key of an actor that
+// cancelled [cancelled].
+func encodeCancelled(cancelled ...chain.CancelledTx) []byte {
+	v := []byte{}
+	for _, c := range cancelled {
+		v = append(v, c.TxID[:]...)
+		v = binary.BigEndian.AppendUint64(v, uint64(c.Expiry))
+	}
+	return v
+}
+
+func TestCancelConformance(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	g := genesis.Default()
+	g.TxCancellation = true
+	var (
+		rules     = g.Rules(0, 1, ids.GenerateTestID())
+		actor     = auth.NewED25519Address(priv.PublicKey())
+		key       = string(cancelKey(actor))
+		timestamp = int64(100_000)
+		txID      = ids.GenerateTestID()
+		full      = make([]chain.CancelledTx, chain.MaxCancelledTxs)
+	)
+	for i := range full {
+		full[i] = chain.CancelledTx{TxID: ids.GenerateTestID(), Expiry: timestamp}
+	}
+	parsed, err := chain.ParseCancelled(encodeCancelled(full...))
+	require.NoError(err)
+	require.Equal(full, parsed)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "cancel",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp + 1_000},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "already expired",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp - 1},
+			ExpectedOutput:  OutputInvalidExpiry,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "prune expired records",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp + 1_000},
+			State:           map[string][]byte{key: encodeCancelled(full...)},
+			Timestamp:       timestamp + 1,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "too many cancelled",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp + 1_000},
+			State:           map[string][]byte{key: encodeCancelled(full...)},
+			ExpectedOutput:  []byte(chain.ErrTooManyCancelled.Error()),
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "replace cancelled",
+			Action:          &Cancel{TxID: full[0].TxID, Expiry: timestamp + 1_000},
+			State:           map[string][]byte{key: encodeCancelled(full...)},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "beyond validity window",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp + rules.GetValidityWindow() + 1},
+			ExpectedOutput:  OutputInvalidExpiry,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "cancellation disabled",
+			Action:          &Cancel{TxID: txID, Expiry: timestamp + 1_000},
+			Rules:           genesis.Default().Rules(0, 1, ids.GenerateTestID()),
+			ExpectedOutput:  OutputCancellationDisabled,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Unmarshal = UnmarshalCancel
+		if test.Rules == nil {
+			test.Rules = rules
+		}
+		test.Actor = actor
+		if test.Timestamp == 0 {
+			test.Timestamp = timestamp
+		}
+		chaintest.RunActionTest(t, test)
+	}
+}
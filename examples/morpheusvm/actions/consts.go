@@ -3,4 +3,22 @@
 
 package actions
 
-const TransferComputeUnits = 1
+const (
+	TransferComputeUnits = 1
+
+	// RegisterAggregateComputeUnits is charged in addition to verifying the
+	// proof of possession of each signer.
+	RegisterAggregateComputeUnits = 1
+
+	RotateKeyComputeUnits = 1
+
+	SetGuardiansComputeUnits     = 1
+	InitiateRecoveryComputeUnits = 1
+	FinalizeRecoveryComputeUnits = 1
+
+	CancelComputeUnits = 1
+
+	UpdateRulesComputeUnits = 1
+
+	RevokeSessionComputeUnits = 1
+)
@@ -0,0 +1,12 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import "errors"
+
+var (
+	ErrInvalidSigners            = errors.New("invalid signers")
+	ErrInvalidProofsOfPossession = errors.New("invalid proofs of possession")
+	ErrTooManyGuardians          = errors.New("too many guardians")
+)
@@ -3,4 +3,23 @@
 
 package actions
 
-var OutputValueZero = []byte("value is zero")
+var (
+	OutputValueZero                = []byte("value is zero")
+	OutputSelfTransfer             = []byte("cannot transfer to self")
+	OutputInvalidSigners           = []byte("invalid signers")
+	OutputDuplicateSigner          = []byte("duplicate signer")
+	OutputInvalidProofOfPossession = []byte("invalid proof of possession")
+	OutputAggregateRegistered      = []byte("aggregate already registered")
+	OutputNotAccount               = []byte("actor is not an account")
+	OutputInvalidKey               = []byte("invalid key")
+	OutputInvalidGuardians         = []byte("invalid guardians")
+	OutputNoGuardians              = []byte("account has no guardians")
+	OutputNotGuardian              = []byte("actor is not a guardian")
+	OutputNoRecovery               = []byte("no pending recovery")
+	OutputRecoveryNotReady         = []byte("recovery not ready")
+	OutputInvalidExpiry            = []byte("invalid expiry")
+	OutputCancellationDisabled     = []byte("cancellation disabled")
+	OutputNotRulesAuthority        = []byte("actor is not the rules authority")
+	OutputNoSessionAccount         = []byte("actor has no session account")
+	OutputSessionRevoked           = []byte("session already revoked")
+)
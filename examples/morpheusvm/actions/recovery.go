@@ -0,0 +1,332 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Social recovery lets the guardians of an [auth.Account] replace its key
+// if the owner loses it:
+//
+//  1. The owner registers guardians with [SetGuardians]
+//  2. Guardians approve a new key with [InitiateRecovery]
+//  3. Once enough guardians approve, a timelock starts during which the
+//     owner can cancel the recovery by calling [SetGuardians] again
+//  4. After the timelock, anyone can apply the new key with
+//     [FinalizeRecovery]
+var (
+	_ chain.Action = (*SetGuardians)(nil)
+	_ chain.Action = (*InitiateRecovery)(nil)
+	_ chain.Action = (*FinalizeRecovery)(nil)
+)
+
+// SetGuardians replaces the guardians of the actor (an [auth.Account]) and
+// cancels any pending recovery. Setting no guardians (and a [Threshold] of 0)
+// disables recovery.
+type SetGuardians struct {
+	Guardians []codec.Address `json:"guardians"`
+
+	// Threshold is the number of [Guardians] that must approve a recovery.
+	Threshold uint8 `json:"threshold"`
+
+	// Delay is the time (in ms) the owner has to cancel a recovery once it
+	// is approved.
+	Delay int64 `json:"delay"`
+}
+
+func (*SetGuardians) GetTypeID() uint8 {
+	return mconsts.SetGuardiansID
+}
+
+func (*SetGuardians) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.GuardiansKey(actor)),
+		string(storage.RecoveryKey(actor)),
+	}
+}
+
+func (*SetGuardians) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.GuardiansChunks, storage.RecoveryChunks}
+}
+
+func (*SetGuardians) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SetGuardians) verify(actor codec.Address) bool {
+	if s.Delay < 0 || len(s.Guardians) > storage.MaxGuardians {
+		return false
+	}
+	if len(s.Guardians) == 0 {
+		return s.Threshold == 0
+	}
+	if s.Threshold == 0 || int(s.Threshold) > len(s.Guardians) {
+		return false
+	}
+	guardians := make(map[codec.Address]struct{}, len(s.Guardians))
+	for _, guardian := range s.Guardians {
+		if guardian == actor {
+			return false
+		}
+		if _, ok := guardians[guardian]; ok {
+			return false
+		}
+		guardians[guardian] = struct{}{}
+	}
+	return true
+}
+
+func (s *SetGuardians) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if actor[0] != mconsts.AccountID {
+		return false, SetGuardiansComputeUnits, OutputNotAccount, nil, nil
+	}
+	if !s.verify(actor) {
+		return false, SetGuardiansComputeUnits, OutputInvalidGuardians, nil, nil
+	}
+	if err := storage.DeleteRecovery(ctx, mu, actor); err != nil {
+		return false, SetGuardiansComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if len(s.Guardians) == 0 {
+		if err := storage.DeleteGuardians(ctx, mu, actor); err != nil {
+			return false, SetGuardiansComputeUnits, nil, nil, chain.Fail(err)
+		}
+		return true, SetGuardiansComputeUnits, nil, nil, nil
+	}
+	if err := storage.SetGuardians(ctx, mu, actor, &storage.Guardians{
+		Addresses: s.Guardians,
+		Threshold: s.Threshold,
+		Delay:     s.Delay,
+	}); err != nil {
+		return false, SetGuardiansComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, SetGuardiansComputeUnits, nil, nil, nil
+}
+
+func (*SetGuardians) MaxComputeUnits(chain.Rules) uint64 {
+	return SetGuardiansComputeUnits
+}
+
+func (s *SetGuardians) Size() int {
+	return consts.ByteLen + len(s.Guardians)*codec.AddressLen + consts.ByteLen + consts.Int64Len
+}
+
+func (s *SetGuardians) Marshal(p *codec.Packer) {
+	p.PackByte(uint8(len(s.Guardians)))
+	for _, guardian := range s.Guardians {
+		p.PackAddress(guardian)
+	}
+	p.PackByte(s.Threshold)
+	p.PackInt64(s.Delay)
+}
+
+func UnmarshalSetGuardians(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var set SetGuardians
+	numGuardians := int(p.UnpackByte())
+	if numGuardians > storage.MaxGuardians {
+		return nil, ErrTooManyGuardians
+	}
+	set.Guardians = make([]codec.Address, numGuardians)
+	for i := range set.Guardians {
+		p.UnpackAddress(&set.Guardians[i])
+	}
+	set.Threshold = p.UnpackByte()
+	set.Delay = p.UnpackInt64(false)
+	return &set, p.Err()
+}
+
+func (*SetGuardians) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// InitiateRecovery approves replacing the key of [Account] with [NewKey]. It
+// must be sent by a guardian of [Account].
+//
+// The first approval of a different [NewKey] replaces the pending recovery.
+type InitiateRecovery struct {
+	Account codec.Address     `json:"account"`
+	NewKey  ed25519.PublicKey `json:"newKey"`
+}
+
+func (*InitiateRecovery) GetTypeID() uint8 {
+	return mconsts.InitiateRecoveryID
+}
+
+func (i *InitiateRecovery) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(storage.GuardiansKey(i.Account)),
+		string(storage.RecoveryKey(i.Account)),
+	}
+}
+
+func (*InitiateRecovery) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.GuardiansChunks, storage.RecoveryChunks}
+}
+
+func (*InitiateRecovery) OutputsWarpMessage() bool {
+	return false
+}
+
+func (i *InitiateRecovery) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if i.NewKey == ed25519.EmptyPublicKey {
+		return false, InitiateRecoveryComputeUnits, OutputInvalidKey, nil, nil
+	}
+	guardians, exists, err := storage.GetGuardians(ctx, mu, i.Account)
+	if err != nil {
+		return false, InitiateRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, InitiateRecoveryComputeUnits, OutputNoGuardians, nil, nil
+	}
+	index, ok := guardians.Index(actor)
+	if !ok {
+		return false, InitiateRecoveryComputeUnits, OutputNotGuardian, nil, nil
+	}
+	recovery, exists, err := storage.GetRecovery(ctx, mu, i.Account)
+	if err != nil {
+		return false, InitiateRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists || recovery.NewKey != i.NewKey {
+		recovery = &storage.Recovery{NewKey: i.NewKey}
+	}
+	recovery.Approvals |= 1 << index
+	if recovery.ReadyAt == 0 && bits.OnesCount8(recovery.Approvals) >= int(guardians.Threshold) {
+		recovery.ReadyAt = timestamp + guardians.Delay
+	}
+	if err := storage.SetRecovery(ctx, mu, i.Account, recovery); err != nil {
+		return false, InitiateRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, InitiateRecoveryComputeUnits, nil, nil, nil
+}
+
+func (*InitiateRecovery) MaxComputeUnits(chain.Rules) uint64 {
+	return InitiateRecoveryComputeUnits
+}
+
+func (*InitiateRecovery) Size() int {
+	return codec.AddressLen + ed25519.PublicKeyLen
+}
+
+func (i *InitiateRecovery) Marshal(p *codec.Packer) {
+	p.PackAddress(i.Account)
+	p.PackFixedBytes(i.NewKey[:])
+}
+
+func UnmarshalInitiateRecovery(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var initiate InitiateRecovery
+	p.UnpackAddress(&initiate.Account)
+	newKey := initiate.NewKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &newKey)
+	return &initiate, p.Err()
+}
+
+func (*InitiateRecovery) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// FinalizeRecovery sets the key of [Account] to the key approved by its
+// guardians once the timelock of the recovery has passed. It can be sent by
+// anyone.
+type FinalizeRecovery struct {
+	Account codec.Address `json:"account"`
+}
+
+func (*FinalizeRecovery) GetTypeID() uint8 {
+	return mconsts.FinalizeRecoveryID
+}
+
+func (f *FinalizeRecovery) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(storage.RecoveryKey(f.Account)),
+		string(storage.AccountKey(f.Account)),
+	}
+}
+
+func (*FinalizeRecovery) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.RecoveryChunks, storage.AccountChunks}
+}
+
+func (*FinalizeRecovery) OutputsWarpMessage() bool {
+	return false
+}
+
+func (f *FinalizeRecovery) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	recovery, exists, err := storage.GetRecovery(ctx, mu, f.Account)
+	if err != nil {
+		return false, FinalizeRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, FinalizeRecoveryComputeUnits, OutputNoRecovery, nil, nil
+	}
+	if recovery.ReadyAt == 0 || timestamp < recovery.ReadyAt {
+		return false, FinalizeRecoveryComputeUnits, OutputRecoveryNotReady, nil, nil
+	}
+	if err := rotateKey(ctx, mu, f.Account, recovery.NewKey); err != nil {
+		return false, FinalizeRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.DeleteRecovery(ctx, mu, f.Account); err != nil {
+		return false, FinalizeRecoveryComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, FinalizeRecoveryComputeUnits, nil, nil, nil
+}
+
+func (*FinalizeRecovery) MaxComputeUnits(chain.Rules) uint64 {
+	return FinalizeRecoveryComputeUnits
+}
+
+func (*FinalizeRecovery) Size() int {
+	return codec.AddressLen
+}
+
+func (f *FinalizeRecovery) Marshal(p *codec.Packer) {
+	p.PackAddress(f.Account)
+}
+
+func UnmarshalFinalizeRecovery(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var finalize FinalizeRecovery
+	p.UnpackAddress(&finalize.Account)
+	return &finalize, p.Err()
+}
+
+func (*FinalizeRecovery) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
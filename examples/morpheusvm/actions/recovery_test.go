@@ -0,0 +1,207 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestRecoveryConformance(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	newPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	var (
+		rules     = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		account   = auth.NewAccountAddress(priv.PublicKey())
+		newKey    = newPriv.PublicKey()
+		guardian1 = codec.CreateAddress(0, ids.GenerateTestID())
+		guardian2 = codec.CreateAddress(0, ids.GenerateTestID())
+		guardians = chaintest.State{}
+		pending   = chaintest.State{}
+		ready     = chaintest.State{}
+	)
+	require.NoError(storage.SetGuardians(ctx, guardians, account, &storage.Guardians{
+		Addresses: []codec.Address{guardian1, guardian2},
+		Threshold: 2,
+		Delay:     100,
+	}))
+	require.NoError(storage.SetRecovery(ctx, pending, account, &storage.Recovery{NewKey: newKey, Approvals: 1}))
+	require.NoError(storage.SetRecovery(ctx, ready, account, &storage.Recovery{NewKey: newKey, Approvals: 3, ReadyAt: 100}))
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "set guardians",
+			Action:          &SetGuardians{Guardians: []codec.Address{guardian1, guardian2}, Threshold: 1, Delay: 10},
+			Unmarshal:       UnmarshalSetGuardians,
+			Actor:           account,
+			State:           pending,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "remove guardians",
+			Action:          &SetGuardians{},
+			Unmarshal:       UnmarshalSetGuardians,
+			Actor:           account,
+			State:           guardians,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "threshold above guardians",
+			Action:          &SetGuardians{Guardians: []codec.Address{guardian1}, Threshold: 2},
+			Unmarshal:       UnmarshalSetGuardians,
+			Actor:           account,
+			ExpectedOutput:  OutputInvalidGuardians,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "self guardian",
+			Action:          &SetGuardians{Guardians: []codec.Address{account}, Threshold: 1},
+			Unmarshal:       UnmarshalSetGuardians,
+			Actor:           account,
+			ExpectedOutput:  OutputInvalidGuardians,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "set guardians of non-account",
+			Action:          &SetGuardians{Guardians: []codec.Address{guardian1}, Threshold: 1},
+			Unmarshal:       UnmarshalSetGuardians,
+			Actor:           guardian2,
+			ExpectedOutput:  OutputNotAccount,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "initiate recovery",
+			Action:          &InitiateRecovery{Account: account, NewKey: newKey},
+			Unmarshal:       UnmarshalInitiateRecovery,
+			Actor:           guardian1,
+			State:           guardians,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "initiate recovery without guardians",
+			Action:          &InitiateRecovery{Account: account, NewKey: newKey},
+			Unmarshal:       UnmarshalInitiateRecovery,
+			Actor:           guardian1,
+			ExpectedOutput:  OutputNoGuardians,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "initiate recovery by non-guardian",
+			Action:          &InitiateRecovery{Account: account, NewKey: newKey},
+			Unmarshal:       UnmarshalInitiateRecovery,
+			Actor:           account,
+			State:           guardians,
+			ExpectedOutput:  OutputNotGuardian,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "finalize recovery",
+			Action:          &FinalizeRecovery{Account: account},
+			Unmarshal:       UnmarshalFinalizeRecovery,
+			Actor:           guardian2,
+			State:           ready,
+			Timestamp:       100,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "finalize recovery during timelock",
+			Action:          &FinalizeRecovery{Account: account},
+			Unmarshal:       UnmarshalFinalizeRecovery,
+			Actor:           guardian2,
+			State:           ready,
+			Timestamp:       99,
+			ExpectedOutput:  OutputRecoveryNotReady,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "finalize recovery below threshold",
+			Action:          &FinalizeRecovery{Account: account},
+			Unmarshal:       UnmarshalFinalizeRecovery,
+			Actor:           guardian2,
+			State:           pending,
+			Timestamp:       1_000,
+			ExpectedOutput:  OutputRecoveryNotReady,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Rules = rules
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestRecoveryTimelock(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	newPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	var (
+		mu        = chaintest.State{}
+		account   = auth.NewAccountAddress(priv.PublicKey())
+		guardians = []codec.Address{
+			codec.CreateAddress(0, ids.GenerateTestID()),
+			codec.CreateAddress(0, ids.GenerateTestID()),
+			codec.CreateAddress(0, ids.GenerateTestID()),
+		}
+		initiate = &InitiateRecovery{Account: account, NewKey: newPriv.PublicKey()}
+		finalize = &FinalizeRecovery{Account: account}
+	)
+	success, _, _, _, err := (&SetGuardians{Guardians: guardians, Threshold: 2, Delay: 100}).Execute(ctx, nil, mu, 0, account, ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+
+	// The timelock starts once the threshold is reached
+	success, _, _, _, err = initiate.Execute(ctx, nil, mu, 10, guardians[0], ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+	success, _, _, _, err = initiate.Execute(ctx, nil, mu, 20, guardians[2], ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+	recovery, exists, err := storage.GetRecovery(ctx, mu, account)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(int64(120), recovery.ReadyAt)
+
+	success, _, output, _, err := finalize.Execute(ctx, nil, mu, 119, guardians[1], ids.Empty, false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputRecoveryNotReady, output)
+	success, _, _, _, err = finalize.Execute(ctx, nil, mu, 120, guardians[1], ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+	key, rotated, err := storage.GetAccountKey(ctx, mu, account)
+	require.NoError(err)
+	require.True(rotated)
+	require.Equal(newPriv.PublicKey(), key)
+
+	// Re-registering guardians cancels a pending recovery
+	success, _, _, _, err = initiate.Execute(ctx, nil, mu, 200, guardians[0], ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+	success, _, _, _, err = (&SetGuardians{Guardians: guardians, Threshold: 2, Delay: 100}).Execute(ctx, nil, mu, 210, account, ids.Empty, false)
+	require.NoError(err)
+	require.True(success)
+	_, exists, err = storage.GetRecovery(ctx, mu, account)
+	require.NoError(err)
+	require.False(exists)
+}
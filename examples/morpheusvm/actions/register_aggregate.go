@@ -0,0 +1,180 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RegisterAggregate)(nil)
+
+// RegisterAggregate registers the aggregate public key of [Signers] so that
+// it can be used with [auth.BLSAggregate].
+//
+// Each signer must prove possession of its key, otherwise a signer could
+// pick a key that cancels out the keys of the others (a rogue-key attack)
+// and sign for the aggregate alone.
+type RegisterAggregate struct {
+	Signers []*bls.PublicKey `json:"signers"`
+
+	// ProofsOfPossession are the [bls.SignProofOfPossession] of each of
+	// [Signers].
+	ProofsOfPossession []*bls.Signature `json:"proofsOfPossession"`
+
+	aggregate codec.Address
+}
+
+// Aggregate returns the address of the aggregate public key of [Signers].
+func (r *RegisterAggregate) Aggregate() (codec.Address, error) {
+	if r.aggregate != codec.EmptyAddress {
+		return r.aggregate, nil
+	}
+	pk, err := bls.AggregatePublicKeys(r.Signers)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	r.aggregate = auth.NewBLSAggregateAddress(pk)
+	return r.aggregate, nil
+}
+
+func (*RegisterAggregate) GetTypeID() uint8 {
+	return mconsts.RegisterAggregateID
+}
+
+func (r *RegisterAggregate) StateKeys(codec.Address, ids.ID) []string {
+	// If [Signers] cannot be aggregated, [Execute] will fail before accessing
+	// state.
+	addr, _ := r.Aggregate()
+	return []string{string(storage.AggregateKey(addr))}
+}
+
+func (*RegisterAggregate) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AggregateChunks}
+}
+
+func (*RegisterAggregate) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RegisterAggregate) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if len(r.Signers) == 0 || len(r.Signers) > auth.MaxAggregateSigners {
+		return false, RegisterAggregateComputeUnits, OutputInvalidSigners, nil, nil
+	}
+	if len(r.ProofsOfPossession) != len(r.Signers) {
+		return false, RegisterAggregateComputeUnits, OutputInvalidProofOfPossession, nil, nil
+	}
+	signers := make(map[string]struct{}, len(r.Signers))
+	for _, signer := range r.Signers {
+		k := string(bls.PublicKeyToBytes(signer))
+		if _, ok := signers[k]; ok {
+			return false, RegisterAggregateComputeUnits, OutputDuplicateSigner, nil, nil
+		}
+		signers[k] = struct{}{}
+	}
+	computeUnits := r.MaxComputeUnits(nil)
+	for i, signer := range r.Signers {
+		if !bls.VerifyProofOfPossession(signer, r.ProofsOfPossession[i]) {
+			return false, computeUnits, OutputInvalidProofOfPossession, nil, nil
+		}
+	}
+	addr, err := r.Aggregate()
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	_, registered, err := storage.GetAggregate(ctx, mu, addr)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if registered {
+		return false, computeUnits, OutputAggregateRegistered, nil, nil
+	}
+	if err := storage.SetAggregate(ctx, mu, addr, uint16(len(r.Signers))); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (r *RegisterAggregate) MaxComputeUnits(chain.Rules) uint64 {
+	return RegisterAggregateComputeUnits + uint64(len(r.Signers))*auth.BLSComputeUnits
+}
+
+func (r *RegisterAggregate) Size() int {
+	return consts.ByteLen + len(r.Signers)*bls.PublicKeyLen +
+		consts.ByteLen + len(r.ProofsOfPossession)*bls.SignatureLen
+}
+
+func (r *RegisterAggregate) Marshal(p *codec.Packer) {
+	p.PackByte(uint8(len(r.Signers)))
+	for _, signer := range r.Signers {
+		p.PackFixedBytes(bls.PublicKeyToBytes(signer))
+	}
+	p.PackByte(uint8(len(r.ProofsOfPossession)))
+	for _, proof := range r.ProofsOfPossession {
+		p.PackFixedBytes(bls.SignatureToBytes(proof))
+	}
+}
+
+func UnmarshalRegisterAggregate(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var register RegisterAggregate
+
+	numSigners := int(p.UnpackByte())
+	if numSigners == 0 || numSigners > auth.MaxAggregateSigners {
+		return nil, ErrInvalidSigners
+	}
+	register.Signers = make([]*bls.PublicKey, numSigners)
+	for i := range register.Signers {
+		signer := make([]byte, bls.PublicKeyLen)
+		p.UnpackFixedBytes(bls.PublicKeyLen, &signer)
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		pk, err := bls.PublicKeyFromBytes(signer)
+		if err != nil {
+			return nil, err
+		}
+		register.Signers[i] = pk
+	}
+	numProofs := int(p.UnpackByte())
+	if numProofs != numSigners {
+		return nil, ErrInvalidProofsOfPossession
+	}
+	register.ProofsOfPossession = make([]*bls.Signature, numProofs)
+	for i := range register.ProofsOfPossession {
+		proof := make([]byte, bls.SignatureLen)
+		p.UnpackFixedBytes(bls.SignatureLen, &proof)
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		sig, err := bls.SignatureFromBytes(proof)
+		if err != nil {
+			return nil, err
+		}
+		register.ProofsOfPossession[i] = sig
+	}
+	return &register, p.Err()
+}
+
+func (*RegisterAggregate) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
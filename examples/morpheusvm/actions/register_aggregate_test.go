@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestRegisterAggregateConformance(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		rules  = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		actor  = codec.CreateAddress(0, ids.GenerateTestID())
+		pks    = make([]*bls.PublicKey, 2)
+		proofs = make([]*bls.Signature, len(pks))
+	)
+	for i := range pks {
+		priv, err := bls.GeneratePrivateKey()
+		require.NoError(err)
+		pks[i] = bls.PublicFromPrivateKey(priv)
+		proofs[i] = bls.SignProofOfPossession(priv)
+	}
+	registered := &RegisterAggregate{Signers: pks, ProofsOfPossession: proofs}
+	addr, err := registered.Aggregate()
+	require.NoError(err)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "register",
+			Action:          &RegisterAggregate{Signers: pks, ProofsOfPossession: proofs},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:   "already registered",
+			Action: &RegisterAggregate{Signers: pks, ProofsOfPossession: proofs},
+			State: map[string][]byte{
+				string(storage.AggregateKey(addr)): {0, 2},
+			},
+			ExpectedOutput:  OutputAggregateRegistered,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "invalid proof of possession",
+			Action:          &RegisterAggregate{Signers: pks, ProofsOfPossession: []*bls.Signature{proofs[1], proofs[0]}},
+			ExpectedOutput:  OutputInvalidProofOfPossession,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "duplicate signer",
+			Action:          &RegisterAggregate{Signers: []*bls.PublicKey{pks[0], pks[0]}, ProofsOfPossession: []*bls.Signature{proofs[0], proofs[0]}},
+			ExpectedOutput:  OutputDuplicateSigner,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:                 "missing proof of possession",
+			Action:               &RegisterAggregate{Signers: pks, ProofsOfPossession: proofs[:1]},
+			ExpectedUnmarshalErr: ErrInvalidProofsOfPossession,
+			ExpectedOutput:       OutputInvalidProofOfPossession,
+			ExpectedSuccess:      false,
+		},
+	} {
+		test.Unmarshal = UnmarshalRegisterAggregate
+		test.Rules = rules
+		test.Actor = actor
+		chaintest.RunActionTest(t, test)
+	}
+}
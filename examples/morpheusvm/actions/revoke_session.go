@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RevokeSession)(nil)
+
+// RevokeSession permanently revokes [Delegation] (see [auth.Delegation.ID])
+// of the session account of the actor, so that its session key can no
+// longer sign transactions. The actor must be the key that signed the
+// delegation or a session of its account.
+type RevokeSession struct {
+	Delegation ids.ID `json:"delegation"`
+}
+
+func (*RevokeSession) GetTypeID() uint8 {
+	return mconsts.RevokeSessionID
+}
+
+func (r *RevokeSession) StateKeys(actor codec.Address, _ ids.ID) []string {
+	account, ok := auth.SessionAddressOf(actor)
+	if !ok {
+		// Never accessed (see [Execute])
+		account = actor
+	}
+	return []string{string(storage.SessionKey(account, r.Delegation))}
+}
+
+func (*RevokeSession) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.SessionChunks}
+}
+
+func (*RevokeSession) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RevokeSession) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	account, ok := auth.SessionAddressOf(actor)
+	if !ok {
+		return false, RevokeSessionComputeUnits, OutputNoSessionAccount, nil, nil
+	}
+	spent, revoked, err := storage.GetSession(ctx, mu, account, r.Delegation)
+	if err != nil {
+		return false, RevokeSessionComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if revoked {
+		return false, RevokeSessionComputeUnits, OutputSessionRevoked, nil, nil
+	}
+	if err := storage.SetSession(ctx, mu, account, r.Delegation, spent, true); err != nil {
+		return false, RevokeSessionComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, RevokeSessionComputeUnits, nil, nil, nil
+}
+
+func (*RevokeSession) MaxComputeUnits(chain.Rules) uint64 {
+	return RevokeSessionComputeUnits
+}
+
+func (*RevokeSession) Size() int {
+	return consts.IDLen
+}
+
+func (r *RevokeSession) Marshal(p *codec.Packer) {
+	p.PackID(r.Delegation)
+}
+
+func UnmarshalRevokeSession(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var revoke RevokeSession
+	p.UnpackID(true, &revoke.Delegation)
+	return &revoke, p.Err()
+}
+
+func (*RevokeSession) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestRevokeSessionConformance(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	var (
+		signer     = auth.NewED25519Address(priv.PublicKey())
+		account    = auth.NewSessionAddress(priv.PublicKey())
+		delegation = ids.GenerateTestID()
+		key        = string(storage.SessionKey(account, delegation))
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "revoke unused session",
+			Action:          &RevokeSession{Delegation: delegation},
+			Actor:           signer,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "revoke session with spending",
+			Action:          &RevokeSession{Delegation: delegation},
+			State:           map[string][]byte{key: append(make([]byte, 7), 0x10, 0x0)},
+			Actor:           signer,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "revoke from session account",
+			Action:          &RevokeSession{Delegation: delegation},
+			Actor:           account,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "already revoked",
+			Action:          &RevokeSession{Delegation: delegation},
+			State:           map[string][]byte{key: append(make([]byte, 8), 0x1)},
+			Actor:           signer,
+			ExpectedOutput:  OutputSessionRevoked,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "no session account",
+			Action:          &RevokeSession{Delegation: delegation},
+			Actor:           codec.CreateAddress(consts.BLSID, ids.GenerateTestID()),
+			ExpectedOutput:  OutputNoSessionAccount,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Unmarshal = UnmarshalRevokeSession
+		test.Rules = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		chaintest.RunActionTest(t, test)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RotateKey)(nil)
+
+// RotateKey replaces the key that authorizes transactions of the actor (an
+// [auth.Account]) with [NewKey]. The address of the account, and everything
+// bound to it, is unchanged.
+type RotateKey struct {
+	NewKey ed25519.PublicKey `json:"newKey"`
+}
+
+func (*RotateKey) GetTypeID() uint8 {
+	return mconsts.RotateKeyID
+}
+
+func (*RotateKey) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{string(storage.AccountKey(actor))}
+}
+
+func (*RotateKey) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AccountChunks}
+}
+
+func (*RotateKey) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RotateKey) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if actor[0] != mconsts.AccountID {
+		return false, RotateKeyComputeUnits, OutputNotAccount, nil, nil
+	}
+	if r.NewKey == ed25519.EmptyPublicKey {
+		return false, RotateKeyComputeUnits, OutputInvalidKey, nil, nil
+	}
+	if err := rotateKey(ctx, mu, actor, r.NewKey); err != nil {
+		return false, RotateKeyComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, RotateKeyComputeUnits, nil, nil, nil
+}
+
+// rotateKey sets the key of [account] to [key].
+func rotateKey(ctx context.Context, mu state.Mutable, account codec.Address, key ed25519.PublicKey) error {
+	// Rotating back to the key the account was derived from doesn't need a
+	// record.
+	if auth.NewAccountAddress(key) == account {
+		return storage.DeleteAccountKey(ctx, mu, account)
+	}
+	return storage.SetAccountKey(ctx, mu, account, key)
+}
+
+func (*RotateKey) MaxComputeUnits(chain.Rules) uint64 {
+	return RotateKeyComputeUnits
+}
+
+func (*RotateKey) Size() int {
+	return ed25519.PublicKeyLen
+}
+
+func (r *RotateKey) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(r.NewKey[:])
+}
+
+func UnmarshalRotateKey(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var rotate RotateKey
+	newKey := rotate.NewKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &newKey)
+	return &rotate, p.Err()
+}
+
+func (*RotateKey) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
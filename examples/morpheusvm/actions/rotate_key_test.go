@@ -0,0 +1,68 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestRotateKeyConformance(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	newPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	var (
+		rules   = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		account = auth.NewAccountAddress(priv.PublicKey())
+		newKey  = newPriv.PublicKey()
+		rotated = map[string][]byte{
+			string(storage.AccountKey(account)): newKey[:],
+		}
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "rotate",
+			Action:          &RotateKey{NewKey: newKey},
+			Actor:           account,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "rotate back to original key",
+			Action:          &RotateKey{NewKey: priv.PublicKey()},
+			Actor:           account,
+			State:           rotated,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "empty key",
+			Action:          &RotateKey{},
+			Actor:           account,
+			ExpectedOutput:  OutputInvalidKey,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "not an account",
+			Action:          &RotateKey{NewKey: newKey},
+			Actor:           auth.NewED25519Address(priv.PublicKey()),
+			ExpectedOutput:  OutputNotAccount,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Unmarshal = UnmarshalRotateKey
+		test.Rules = rules
+		chaintest.RunActionTest(t, test)
+	}
+}
@@ -11,13 +11,16 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
 	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
-var _ chain.Action = (*Transfer)(nil)
+var (
+	_ chain.Action = (*Transfer)(nil)
+	_ auth.Spender = (*Transfer)(nil)
+)
 
 type Transfer struct {
 	// To is the recipient of the [Value].
@@ -48,7 +51,7 @@ func (*Transfer) OutputsWarpMessage() bool {
 
 func (t *Transfer) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
 	_ int64,
 	actor codec.Address,
@@ -58,11 +61,14 @@ func (t *Transfer) Execute(
 	if t.Value == 0 {
 		return false, 1, OutputValueZero, nil, nil
 	}
+	if t.To == actor && r.IsFeatureActive(mconsts.NoSelfTransfers) {
+		return false, 1, OutputSelfTransfer, nil, nil
+	}
 	if err := storage.SubBalance(ctx, mu, actor, t.Value); err != nil {
-		return false, 1, utils.ErrBytes(err), nil, nil
+		return false, 1, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, t.To, t.Value, true); err != nil {
-		return false, 1, utils.ErrBytes(err), nil, nil
+		return false, 1, nil, nil, chain.Fail(err)
 	}
 	return true, 1, nil, nil, nil
 }
@@ -90,6 +96,11 @@ func UnmarshalTransfer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
 	return &transfer, nil
 }
 
+// Spends is the amount transferred out of the actor's account.
+func (t *Transfer) Spends() uint64 {
+	return t.Value
+}
+
 func (*Transfer) ValidRange(chain.Rules) (int64, int64) {
 	// Returning -1, -1 means that the action is always valid.
 	return -1, -1
@@ -0,0 +1,83 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestTransferConformance(t *testing.T) {
+	forked, err := genesis.New(nil, []byte(`[{"timestamp":1000,"features":["noSelfTransfers"]}]`))
+	require.NoError(t, err)
+
+	var (
+		rules     = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		actor     = codec.CreateAddress(0, ids.GenerateTestID())
+		recipient = codec.CreateAddress(0, ids.GenerateTestID())
+		balance   = map[string][]byte{
+			string(storage.BalanceKey(actor)): binary.BigEndian.AppendUint64(nil, 100),
+		}
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "transfer",
+			Action:          &Transfer{To: recipient, Value: 10},
+			State:           balance,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "transfer to self",
+			Action:          &Transfer{To: actor, Value: 10},
+			State:           balance,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "transfer to self before fork",
+			Action:          &Transfer{To: actor, Value: 10},
+			Rules:           forked.Rules(999, 1, ids.GenerateTestID()),
+			State:           balance,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "transfer to self after fork",
+			Action:          &Transfer{To: actor, Value: 10},
+			Rules:           forked.Rules(1000, 1, ids.GenerateTestID()),
+			State:           balance,
+			ExpectedOutput:  OutputSelfTransfer,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:                 "zero value",
+			Action:               &Transfer{To: recipient},
+			ExpectedUnmarshalErr: codec.ErrFieldNotPopulated,
+			State:                balance,
+			ExpectedOutput:       OutputValueZero,
+			ExpectedSuccess:      false,
+		},
+		{
+			Name:            "insufficient balance",
+			Action:          &Transfer{To: recipient, Value: 101},
+			State:           balance,
+			ExpectedStatus:  chain.StatusInsufficientBalance,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Unmarshal = UnmarshalTransfer
+		if test.Rules == nil {
+			test.Rules = rules
+		}
+		test.Actor = actor
+		chaintest.RunActionTest(t, test)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*UpdateRules)(nil)
+
+// maxRulesOverridesSize is the size of [chain.RulesOverrides] with every
+// rule overridden.
+const maxRulesOverridesSize = 1 + 8 + 8 + 8*chain.MaxFeeDimensions
+
+// rulesAuthorityFactories are the auths the rules authority can sign an
+// [UpdateRules] transaction with.
+var rulesAuthorityFactories = []chain.AuthFactory{
+	&auth.ED25519Factory{},
+	&auth.SECP256R1Factory{},
+	&auth.BLSFactory{},
+	&auth.BLSAggregateFactory{},
+	&auth.AccountFactory{},
+}
+
+// UpdateRules replaces the overridden rules of the chain with [Overrides],
+// starting at the next block. Only the rules authority (set in genesis) can
+// update them.
+type UpdateRules struct {
+	Overrides chain.RulesOverrides `json:"overrides"`
+}
+
+// rulesAuthority is implemented by [genesis.Rules].
+type rulesAuthority interface {
+	GetRulesAuthority() codec.Address
+}
+
+func (*UpdateRules) GetTypeID() uint8 {
+	return mconsts.UpdateRulesID
+}
+
+func (*UpdateRules) StateKeys(codec.Address, ids.ID) []string {
+	return []string{string(rulesOverridesKey())}
+}
+
+func (*UpdateRules) StateKeysMaxChunks() []uint16 {
+	return []uint16{chain.RulesOverridesKeyChunks}
+}
+
+func (*UpdateRules) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateRules) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	ar, ok := chain.UnwrapRules(r).(rulesAuthority)
+	if !ok || ar.GetRulesAuthority() == codec.EmptyAddress || ar.GetRulesAuthority() != actor {
+		return false, UpdateRulesComputeUnits, OutputNotRulesAuthority, nil, nil
+	}
+	minBlockUnits, err := updateRulesMaxUnits(r)
+	if err != nil {
+		return false, UpdateRulesComputeUnits, nil, nil, err
+	}
+	if err := chain.SetRulesOverrides(ctx, mu, rulesOverridesKey(), &u.Overrides, minBlockUnits); err != nil {
+		return false, UpdateRulesComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, UpdateRulesComputeUnits, nil, nil, nil
+}
+
+// updateRulesMaxUnits returns the most units an [UpdateRules] transaction
+// signed by the rules authority can use, so that overridden max block units
+// always leave room to update them again.
+func updateRulesMaxUnits(r chain.Rules) (chain.Dimensions, error) {
+	var (
+		maxOverrides = chain.RulesOverrides{
+			MinBlockGap:    new(int64),
+			ValidityWindow: new(int64),
+			MaxBlockUnits:  &chain.Dimensions{},
+		}
+		action   = &UpdateRules{Overrides: maxOverrides}
+		maxUnits chain.Dimensions
+	)
+	for _, factory := range rulesAuthorityFactories {
+		units, err := chain.EstimateMaxUnits(r, action, factory, nil)
+		if err != nil {
+			return chain.Dimensions{}, err
+		}
+		for i := 0; i < chain.FeeDimensions(); i++ {
+			if units[i] > maxUnits[i] {
+				maxUnits[i] = units[i]
+			}
+		}
+	}
+	return maxUnits, nil
+}
+
+func rulesOverridesKey() []byte {
+	return keys.EncodeChunks(storage.RulesOverridesKey(), chain.RulesOverridesKeyChunks)
+}
+
+func (*UpdateRules) MaxComputeUnits(chain.Rules) uint64 {
+	return UpdateRulesComputeUnits
+}
+
+func (u *UpdateRules) Size() int {
+	return codec.BytesLen(u.Overrides.Marshal())
+}
+
+func (u *UpdateRules) Marshal(p *codec.Packer) {
+	p.PackBytes(u.Overrides.Marshal())
+}
+
+func UnmarshalUpdateRules(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var raw []byte
+	p.UnpackBytes(maxRulesOverridesSize, true, &raw)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	overrides, err := chain.ParseRulesOverrides(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateRules{Overrides: *overrides}, nil
+}
+
+func (*UpdateRules) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+)
+
+func TestUpdateRulesConformance(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		authority = codec.CreateAddress(0, ids.GenerateTestID())
+		gen       = genesis.Default()
+		gap       = int64(250)
+		window    = int64(10_000)
+		units     = chain.Dimensions{1_000_000, 1_000, 1_000, 1_000, 1_000}
+		all       = chain.RulesOverrides{MinBlockGap: &gap, ValidityWindow: &window, MaxBlockUnits: &units}
+	)
+	gen.RulesAuthority = authority
+	rules := gen.Rules(0, 1, ids.GenerateTestID())
+	require.Len(all.Marshal(), maxRulesOverridesSize-8*(chain.MaxFeeDimensions-chain.FeeDimensions()))
+	parsed, err := chain.ParseRulesOverrides(all.Marshal())
+	require.NoError(err)
+	require.Equal(&all, parsed)
+
+	// Overrides don't hide the authority
+	overridden := chain.WithRulesOverrides(rules, &all)
+	require.Equal(window, overridden.GetValidityWindow())
+	require.Equal(units, overridden.GetMaxBlockUnits())
+	require.Equal(gen.MinEmptyBlockGap, overridden.GetMinEmptyBlockGap())
+
+	// Max block units must leave room for another UpdateRules transaction
+	minUnits, err := updateRulesMaxUnits(rules)
+	require.NoError(err)
+	var (
+		negativeWindow = int64(-1)
+		zeroWindow     = int64(0)
+		atMinUnits     = minUnits
+		zeroUnits      = units
+		belowMinUnits  = minUnits
+	)
+	for i := 0; i < chain.FeeDimensions(); i++ {
+		if atMinUnits[i] == 0 {
+			atMinUnits[i] = 1
+		}
+	}
+	zeroUnits[chain.StorageWrite] = 0
+	belowMinUnits[chain.Bandwidth]--
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "override all",
+			Action:          &UpdateRules{Overrides: all},
+			Rules:           overridden,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "override validity window",
+			Action:          &UpdateRules{Overrides: chain.RulesOverrides{ValidityWindow: &window}},
+			State:           map[string][]byte{string(rulesOverridesKey()): all.Marshal()},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "clear overrides",
+			Action:          &UpdateRules{},
+			State:           map[string][]byte{string(rulesOverridesKey()): all.Marshal()},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "invalid validity window",
+			Action:          &UpdateRules{Overrides: chain.RulesOverrides{ValidityWindow: &negativeWindow}},
+			ExpectedSuccess: false,
+		},
+		{
+			Name:                 "zero validity window",
+			Action:               &UpdateRules{Overrides: chain.RulesOverrides{ValidityWindow: &zeroWindow}},
+			ExpectedUnmarshalErr: codec.ErrFieldNotPopulated,
+			ExpectedSuccess:      false,
+		},
+		{
+			Name:            "min max block units",
+			Action:          &UpdateRules{Overrides: chain.RulesOverrides{MaxBlockUnits: &atMinUnits}},
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "zero max block units",
+			Action:          &UpdateRules{Overrides: chain.RulesOverrides{MaxBlockUnits: &zeroUnits}},
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "max block units below an UpdateRules transaction",
+			Action:          &UpdateRules{Overrides: chain.RulesOverrides{MaxBlockUnits: &belowMinUnits}},
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "not authority",
+			Action:          &UpdateRules{Overrides: all},
+			Actor:           codec.CreateAddress(0, ids.GenerateTestID()),
+			ExpectedOutput:  OutputNotRulesAuthority,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "no authority",
+			Action:          &UpdateRules{Overrides: all},
+			Rules:           genesis.Default().Rules(0, 1, ids.GenerateTestID()),
+			ExpectedOutput:  OutputNotRulesAuthority,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Unmarshal = UnmarshalUpdateRules
+		if test.Rules == nil {
+			test.Rules = rules
+		}
+		if test.Actor == codec.EmptyAddress {
+			test.Actor = authority
+		}
+		chaintest.RunActionTest(t, test)
+	}
+}
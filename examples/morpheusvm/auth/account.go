@@ -0,0 +1,164 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var (
+	_ chain.Auth         = (*Account)(nil)
+	_ chain.StatefulAuth = (*Account)(nil)
+)
+
+const (
+	AccountComputeUnits = 5
+	AccountSize         = codec.AddressLen + ed25519.PublicKeyLen + ed25519.SignatureLen
+)
+
+// Account is signed by the current key of a stable [Address].
+//
+// [Address] is derived from the key that created it (see [NewAccountAddress])
+// and is authorized by that key until it is rotated with
+// [actions.RotateKey]. After a rotation, only the key recorded in state can
+// authorize transactions of [Address].
+type Account struct {
+	Address   codec.Address     `json:"address"`
+	Signer    ed25519.PublicKey `json:"signer"`
+	Signature ed25519.Signature `json:"signature"`
+}
+
+func (*Account) GetTypeID() uint8 {
+	return consts.AccountID
+}
+
+func (*Account) ComputeUnits(chain.Rules) uint64 {
+	return AccountComputeUnits
+}
+
+func (*Account) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (a *Account) Verify(_ context.Context, msg []byte) error {
+	if !ed25519.Verify(msg, a.Signer, a.Signature) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (a *Account) StateKeys() []string {
+	return []string{string(storage.AccountKey(a.Address))}
+}
+
+func (a *Account) VerifyState(ctx context.Context, im state.Immutable) error {
+	key, rotated, err := storage.GetAccountKey(ctx, im, a.Address)
+	if err != nil {
+		return err
+	}
+	if !rotated {
+		key = ed25519.EmptyPublicKey
+		if NewAccountAddress(a.Signer) == a.Address {
+			key = a.Signer
+		}
+	}
+	if key != a.Signer {
+		return ErrWrongAccountKey
+	}
+	return nil
+}
+
+func (a *Account) Actor() codec.Address {
+	return a.Address
+}
+
+func (a *Account) Sponsor() codec.Address {
+	return a.Address
+}
+
+func (*Account) Size() int {
+	return AccountSize
+}
+
+func (a *Account) Marshal(p *codec.Packer) {
+	p.PackAddress(a.Address)
+	p.PackFixedBytes(a.Signer[:])
+	p.PackFixedBytes(a.Signature[:])
+}
+
+func UnmarshalAccount(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var a Account
+	p.UnpackAddress(&a.Address)
+	signer := a.Signer[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &signer)
+	signature := a.Signature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.SignatureLen, &signature)
+	return &a, p.Err()
+}
+
+var (
+	_ chain.AuthFactory         = (*AccountFactory)(nil)
+	_ chain.StatefulAuthFactory = (*AccountFactory)(nil)
+)
+
+// NewAccountFactory signs transactions of [addr] with [priv], which must be
+// its current key.
+func NewAccountFactory(addr codec.Address, priv ed25519.PrivateKey) *AccountFactory {
+	return &AccountFactory{addr, priv}
+}
+
+type AccountFactory struct {
+	addr codec.Address
+	priv ed25519.PrivateKey
+}
+
+func (a *AccountFactory) Sign(msg []byte) (chain.Auth, error) {
+	sig := ed25519.Sign(msg, a.priv)
+	return &Account{Address: a.addr, Signer: a.priv.PublicKey(), Signature: sig}, nil
+}
+
+func (*AccountFactory) MaxUnits() (uint64, uint64) {
+	return AccountSize, AccountComputeUnits
+}
+
+func (*AccountFactory) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AccountChunks}
+}
+
+type AccountAuthEngine struct{}
+
+func (*AccountAuthEngine) GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier {
+	return &AccountBatch{(&ED25519AuthEngine{}).GetBatchVerifier(cores, count).(*ED25519Batch)}
+}
+
+func (*AccountAuthEngine) Cache(chain.Auth) {}
+
+type AccountBatch struct {
+	batch *ED25519Batch
+}
+
+func (b *AccountBatch) Add(msg []byte, rauth chain.Auth) func() error {
+	auth := rauth.(*Account)
+	return b.batch.add(msg, auth.Signer, auth.Signature)
+}
+
+func (b *AccountBatch) Done() []func() error {
+	return b.batch.Done()
+}
+
+// NewAccountAddress returns the account created by [pk]. It remains the same
+// when the key of the account is rotated.
+func NewAccountAddress(pk ed25519.PublicKey) codec.Address {
+	return codec.CreateAddress(consts.AccountID, utils.ToID(pk[:]))
+}
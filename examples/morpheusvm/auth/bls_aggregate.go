@@ -0,0 +1,180 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var (
+	_ chain.Auth         = (*BLSAggregate)(nil)
+	_ chain.StatefulAuth = (*BLSAggregate)(nil)
+)
+
+const (
+	BLSAggregateComputeUnits = 10
+	BLSAggregateSize         = bls.PublicKeyLen + bls.SignatureLen
+
+	// MaxAggregateSigners is the most keys that can be registered as a single
+	// aggregate public key.
+	MaxAggregateSigners = 64
+)
+
+// BLSAggregate authorizes a transaction with a single aggregate signature of
+// a set of signers. The aggregate public key of the signers must be
+// registered (see [actions.RegisterAggregate]) before it can be used, which
+// ensures each signer has proven possession of its key.
+//
+// The size of [BLSAggregate] does not depend on the number of signers.
+type BLSAggregate struct {
+	Signer    *bls.PublicKey `json:"signer,omitempty"`
+	Signature *bls.Signature `json:"signature,omitempty"`
+
+	addr codec.Address
+}
+
+func (b *BLSAggregate) address() codec.Address {
+	if b.addr == codec.EmptyAddress {
+		b.addr = NewBLSAggregateAddress(b.Signer)
+	}
+	return b.addr
+}
+
+func (*BLSAggregate) GetTypeID() uint8 {
+	return consts.BLSAggregateID
+}
+
+func (*BLSAggregate) ComputeUnits(chain.Rules) uint64 {
+	return BLSAggregateComputeUnits
+}
+
+func (*BLSAggregate) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (b *BLSAggregate) Verify(_ context.Context, msg []byte) error {
+	if !bls.Verify(msg, b.Signer, b.Signature) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (b *BLSAggregate) StateKeys() []string {
+	return []string{string(storage.AggregateKey(b.address()))}
+}
+
+func (b *BLSAggregate) VerifyState(ctx context.Context, im state.Immutable) error {
+	_, registered, err := storage.GetAggregate(ctx, im, b.address())
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return ErrAggregateNotRegistered
+	}
+	return nil
+}
+
+func (b *BLSAggregate) Actor() codec.Address {
+	return b.address()
+}
+
+func (b *BLSAggregate) Sponsor() codec.Address {
+	return b.address()
+}
+
+func (*BLSAggregate) Size() int {
+	return BLSAggregateSize
+}
+
+func (b *BLSAggregate) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(bls.PublicKeyToBytes(b.Signer))
+	p.PackFixedBytes(bls.SignatureToBytes(b.Signature))
+}
+
+func UnmarshalBLSAggregate(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var b BLSAggregate
+
+	signer := make([]byte, bls.PublicKeyLen)
+	p.UnpackFixedBytes(bls.PublicKeyLen, &signer)
+	signature := make([]byte, bls.SignatureLen)
+	p.UnpackFixedBytes(bls.SignatureLen, &signature)
+
+	pk, err := bls.PublicKeyFromBytes(signer)
+	if err != nil {
+		return nil, err
+	}
+	b.Signer = pk
+
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Signature = sig
+	return &b, p.Err()
+}
+
+var (
+	_ chain.AuthFactory         = (*BLSAggregateFactory)(nil)
+	_ chain.StatefulAuthFactory = (*BLSAggregateFactory)(nil)
+)
+
+// BLSAggregateFactory signs with every key of an aggregate. Signers that do
+// not share their private keys should instead sign the transaction digest
+// individually and combine their signatures with [NewBLSAggregate].
+type BLSAggregateFactory struct {
+	aggregate *bls.PublicKey
+	privs     []*bls.PrivateKey
+}
+
+func NewBLSAggregateFactory(privs []*bls.PrivateKey) (*BLSAggregateFactory, error) {
+	pks := make([]*bls.PublicKey, len(privs))
+	for i, priv := range privs {
+		pks[i] = bls.PublicFromPrivateKey(priv)
+	}
+	aggregate, err := bls.AggregatePublicKeys(pks)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSAggregateFactory{aggregate, privs}, nil
+}
+
+func (b *BLSAggregateFactory) Sign(msg []byte) (chain.Auth, error) {
+	sigs := make([]*bls.Signature, len(b.privs))
+	for i, priv := range b.privs {
+		sigs[i] = bls.Sign(msg, priv)
+	}
+	return NewBLSAggregate(b.aggregate, sigs)
+}
+
+func (*BLSAggregateFactory) MaxUnits() (uint64, uint64) {
+	return BLSAggregateSize, BLSAggregateComputeUnits
+}
+
+func (*BLSAggregateFactory) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AggregateChunks}
+}
+
+// NewBLSAggregate combines the signatures of each signer of [aggregate].
+func NewBLSAggregate(aggregate *bls.PublicKey, sigs []*bls.Signature) (*BLSAggregate, error) {
+	sig, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSAggregate{Signer: aggregate, Signature: sig}, nil
+}
+
+func NewBLSAggregateAddress(pk *bls.PublicKey) codec.Address {
+	return codec.CreateAddress(consts.BLSAggregateID, utils.ToID(bls.PublicKeyToBytes(pk)))
+}
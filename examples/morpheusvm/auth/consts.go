@@ -12,5 +12,7 @@ func Engines() map[uint8]vm.AuthEngine {
 	return map[uint8]vm.AuthEngine{
 		// Only ed25519 batch verification is supported
 		consts.ED25519ID: &ED25519AuthEngine{},
+		consts.SessionID: &SessionAuthEngine{},
+		consts.AccountID: &AccountAuthEngine{},
 	}
 }
@@ -126,10 +126,14 @@ type ED25519Batch struct {
 
 func (b *ED25519Batch) Add(msg []byte, rauth chain.Auth) func() error {
 	auth := rauth.(*ED25519)
+	return b.add(msg, auth.Signer, auth.Signature)
+}
+
+func (b *ED25519Batch) add(msg []byte, signer ed25519.PublicKey, signature ed25519.Signature) func() error {
 	if b.batch == nil {
 		b.batch = ed25519.NewBatch(b.batchSize)
 	}
-	b.batch.Add(msg, auth.Signer, auth.Signature)
+	b.batch.Add(msg, signer, signature)
 	b.counter++
 	b.totalCounter++
 	if b.counter == b.batchSize {
@@ -145,7 +149,9 @@ func (b *ED25519Batch) Add(msg []byte, rauth chain.Auth) func() error {
 }
 
 func (b *ED25519Batch) Done() []func() error {
-	if b.batch == nil {
+	// [total] may overestimate the number of signatures added, in which case
+	// the last batch is empty.
+	if b.batch == nil || b.counter == 0 {
 		return nil
 	}
 	return []func() error{b.batch.VerifyAsync()}
@@ -0,0 +1,36 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func TestED25519BatchFewerThanCount(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	factory := NewED25519Factory(priv)
+	msg := []byte("digest")
+	auth, err := factory.Sign(msg)
+	require.NoError(err)
+
+	// Batches are sized for 8 signatures but only 4 (a full batch) are
+	// added, so no empty batch should be verified.
+	bv := (&ED25519AuthEngine{}).GetBatchVerifier(2, 8)
+	var verify []func() error
+	for i := 0; i < 4; i++ {
+		if f := bv.Add(msg, auth); f != nil {
+			verify = append(verify, f)
+		}
+	}
+	verify = append(verify, bv.Done()...)
+	require.Len(verify, 1)
+	require.NoError(verify[0]())
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import "errors"
+
+var (
+	ErrSessionExpired      = errors.New("session expired")
+	ErrActionNotDelegated  = errors.New("action not delegated")
+	ErrSpendingCapExceeded = errors.New("spending cap exceeded")
+	ErrSessionRevoked      = errors.New("session revoked")
+
+	ErrAggregateNotRegistered = errors.New("aggregate not registered")
+	ErrWrongAccountKey        = errors.New("wrong account key")
+
+	ErrInvalidAuthenticatorData = errors.New("invalid authenticator data")
+	ErrUserNotPresent           = errors.New("user not present")
+	ErrInvalidClientData        = errors.New("invalid client data")
+	ErrChallengeMismatch        = errors.New("challenge mismatch")
+)
@@ -0,0 +1,372 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+var (
+	_ chain.Auth        = (*Session)(nil)
+	_ chain.ScopedAuth  = (*Session)(nil)
+	_ chain.MeteredAuth = (*Session)(nil)
+)
+
+const (
+	SessionComputeUnits = 2 * ED25519ComputeUnits
+
+	// MaxDelegatedActions is the maximum number of action types a
+	// [Delegation] can authorize.
+	MaxDelegatedActions = 16
+)
+
+// Spender is implemented by actions that transfer funds out of the actor's
+// account, so that they count towards the spending cap of a [Delegation].
+type Spender interface {
+	Spends() uint64
+}
+
+// Delegation authorizes [SessionKey] to sign transactions on behalf of the
+// key that signs it.
+type Delegation struct {
+	SessionKey ed25519.PublicKey `json:"sessionKey"`
+
+	// Expiry is the last timestamp (in ms) a transaction signed by
+	// [SessionKey] can be executed at.
+	Expiry int64 `json:"expiry"`
+
+	// ActionTypes are the only actions [SessionKey] can sign.
+	ActionTypes []uint8 `json:"actionTypes"`
+
+	// SpendingCap is the maximum amount all transactions signed by
+	// [SessionKey] can spend in total (the fees charged plus the amount spent
+	// by their actions, see [Spender]). The amount spent so far is tracked in
+	// state until the delegation is revoked.
+	SpendingCap uint64 `json:"spendingCap"`
+
+	id ids.ID
+}
+
+func (d *Delegation) size() int {
+	return ed25519.PublicKeyLen + consts.Int64Len + codec.BytesLen(d.ActionTypes) + consts.Uint64Len
+}
+
+func (d *Delegation) marshal(p *codec.Packer) {
+	p.PackFixedBytes(d.SessionKey[:])
+	p.PackInt64(d.Expiry)
+	p.PackBytes(d.ActionTypes)
+	p.PackUint64(d.SpendingCap)
+}
+
+func unmarshalDelegation(p *codec.Packer) *Delegation {
+	var d Delegation
+	sessionKey := d.SessionKey[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &sessionKey)
+	d.Expiry = p.UnpackInt64(true)
+	p.UnpackBytes(MaxDelegatedActions, true, &d.ActionTypes)
+	d.SpendingCap = p.UnpackUint64(false)
+	return &d
+}
+
+func (d *Delegation) allows(typeID uint8) bool {
+	for _, allowed := range d.ActionTypes {
+		if allowed == typeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Digest is the message signed by the master key to authorize the session.
+func (d *Delegation) Digest() []byte {
+	p := codec.NewWriter(consts.ByteLen+d.size(), consts.MaxInt)
+	p.PackByte(mconsts.SessionID) // domain separation from transaction digests
+	d.marshal(p)
+	return p.Bytes()
+}
+
+// ID identifies [d] in the session account of the key that signs it (see
+// [storage.SessionKey]).
+func (d *Delegation) ID() ids.ID {
+	if d.id == ids.Empty {
+		d.id = utils.ToID(d.Digest())
+	}
+	return d.id
+}
+
+// SignDelegation signs [d] with [priv], the master key of the session.
+func SignDelegation(priv ed25519.PrivateKey, d *Delegation) ed25519.Signature {
+	return ed25519.Sign(d.Digest(), priv)
+}
+
+// Session is signed by a session key on behalf of [Signer], which delegated
+// it limited permissions.
+//
+// Because the [Actor] of an [Auth] must be prefixed by its type, sessions
+// spend from a dedicated account of [Signer] (see [NewSessionAddress]). The
+// master key can fund this account and use it directly by delegating to
+// itself.
+type Session struct {
+	Signer              ed25519.PublicKey `json:"signer"`
+	Delegation          *Delegation       `json:"delegation"`
+	DelegationSignature ed25519.Signature `json:"delegationSignature"` // signed by [Signer]
+	Signature           ed25519.Signature `json:"signature"`           // signed by the session key
+
+	addr codec.Address
+}
+
+func (s *Session) address() codec.Address {
+	if s.addr == codec.EmptyAddress {
+		s.addr = NewSessionAddress(s.Signer)
+	}
+	return s.addr
+}
+
+func (*Session) GetTypeID() uint8 {
+	return mconsts.SessionID
+}
+
+func (*Session) ComputeUnits(chain.Rules) uint64 {
+	return SessionComputeUnits
+}
+
+func (*Session) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (s *Session) Verify(_ context.Context, msg []byte) error {
+	if !ed25519.Verify(s.Delegation.Digest(), s.Signer, s.DelegationSignature) {
+		return crypto.ErrInvalidSignature
+	}
+	if !ed25519.Verify(msg, s.Delegation.SessionKey, s.Signature) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Session) Authorizes(action chain.Action, _ uint64, timestamp int64) error {
+	if timestamp > s.Delegation.Expiry {
+		return ErrSessionExpired
+	}
+	if !s.Delegation.allows(action.GetTypeID()) {
+		return ErrActionNotDelegated
+	}
+	return nil
+}
+
+func (s *Session) StateKeys() []string {
+	return []string{string(storage.SessionKey(s.address(), s.Delegation.ID()))}
+}
+
+func (s *Session) VerifyState(ctx context.Context, im state.Immutable) error {
+	_, revoked, err := storage.GetSession(ctx, im, s.address(), s.Delegation.ID())
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func (s *Session) CanCharge(ctx context.Context, im state.Immutable, action chain.Action, maxFee uint64) error {
+	spent, _, err := storage.GetSession(ctx, im, s.address(), s.Delegation.ID())
+	if err != nil {
+		return err
+	}
+	spent, err = math.Add64(spent, maxFee)
+	if err != nil {
+		return err
+	}
+	spent, err = math.Add64(spent, spends(action))
+	if err != nil {
+		return err
+	}
+	if spent > s.Delegation.SpendingCap {
+		return ErrSpendingCapExceeded
+	}
+	return nil
+}
+
+func (s *Session) Charge(ctx context.Context, mu state.Mutable, action chain.Action, fee uint64) error {
+	spent, revoked, err := storage.GetSession(ctx, mu, s.address(), s.Delegation.ID())
+	if err != nil {
+		return err
+	}
+	spent, err = math.Add64(spent, fee)
+	if err != nil {
+		return err
+	}
+	spent, err = math.Add64(spent, spends(action))
+	if err != nil {
+		return err
+	}
+	if spent == 0 {
+		return nil
+	}
+	return storage.SetSession(ctx, mu, s.address(), s.Delegation.ID(), spent, revoked)
+}
+
+func (s *Session) Refund(ctx context.Context, mu state.Mutable, refund uint64) error {
+	spent, revoked, err := storage.GetSession(ctx, mu, s.address(), s.Delegation.ID())
+	if err != nil {
+		return err
+	}
+	spent, err = math.Sub(spent, refund)
+	if err != nil {
+		return err
+	}
+	return storage.SetSession(ctx, mu, s.address(), s.Delegation.ID(), spent, revoked)
+}
+
+// spends returns the amount [action] spends from the actor's account (if
+// any).
+func spends(action chain.Action) uint64 {
+	if spender, ok := action.(Spender); ok {
+		return spender.Spends()
+	}
+	return 0
+}
+
+func (s *Session) Actor() codec.Address {
+	return s.address()
+}
+
+func (s *Session) Sponsor() codec.Address {
+	return s.address()
+}
+
+func (s *Session) Size() int {
+	return ed25519.PublicKeyLen + s.Delegation.size() + 2*ed25519.SignatureLen
+}
+
+func (s *Session) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(s.Signer[:])
+	s.Delegation.marshal(p)
+	p.PackFixedBytes(s.DelegationSignature[:])
+	p.PackFixedBytes(s.Signature[:])
+}
+
+func UnmarshalSession(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var s Session
+	signer := s.Signer[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.PublicKeyLen, &signer)
+	s.Delegation = unmarshalDelegation(p)
+	delegationSignature := s.DelegationSignature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.SignatureLen, &delegationSignature)
+	signature := s.Signature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(ed25519.SignatureLen, &signature)
+	return &s, p.Err()
+}
+
+var _ chain.AuthFactory = (*SessionFactory)(nil)
+
+// NewSessionFactory signs transactions with the session key [priv] on
+// behalf of [signer], which authorized it with [delegationSignature] over
+// [delegation].
+func NewSessionFactory(
+	priv ed25519.PrivateKey,
+	signer ed25519.PublicKey,
+	delegation *Delegation,
+	delegationSignature ed25519.Signature,
+) *SessionFactory {
+	return &SessionFactory{priv, signer, delegation, delegationSignature}
+}
+
+type SessionFactory struct {
+	priv                ed25519.PrivateKey
+	signer              ed25519.PublicKey
+	delegation          *Delegation
+	delegationSignature ed25519.Signature
+}
+
+func (s *SessionFactory) Sign(msg []byte) (chain.Auth, error) {
+	sig := ed25519.Sign(msg, s.priv)
+	return &Session{
+		Signer:              s.signer,
+		Delegation:          s.delegation,
+		DelegationSignature: s.delegationSignature,
+		Signature:           sig,
+	}, nil
+}
+
+func (s *SessionFactory) MaxUnits() (uint64, uint64) {
+	return uint64(ed25519.PublicKeyLen + s.delegation.size() + 2*ed25519.SignatureLen), SessionComputeUnits
+}
+
+func (*SessionFactory) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.SessionChunks}
+}
+
+// SessionAuthEngine batch verifies both the delegation and transaction
+// signatures of each [Session].
+type SessionAuthEngine struct{}
+
+func (*SessionAuthEngine) GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier {
+	// Each [Session] contains 2 signatures
+	return &SessionBatch{(&ED25519AuthEngine{}).GetBatchVerifier(cores, 2*count).(*ED25519Batch)}
+}
+
+func (*SessionAuthEngine) Cache(chain.Auth) {}
+
+type SessionBatch struct {
+	batch *ED25519Batch
+}
+
+func (b *SessionBatch) Add(msg []byte, rauth chain.Auth) func() error {
+	auth := rauth.(*Session)
+	verifyDelegation := b.batch.add(auth.Delegation.Digest(), auth.Signer, auth.DelegationSignature)
+	verifySignature := b.batch.add(msg, auth.Delegation.SessionKey, auth.Signature)
+	switch {
+	case verifyDelegation == nil:
+		return verifySignature
+	case verifySignature == nil:
+		return verifyDelegation
+	default:
+		return func() error {
+			if err := verifyDelegation(); err != nil {
+				return err
+			}
+			return verifySignature()
+		}
+	}
+}
+
+func (b *SessionBatch) Done() []func() error {
+	return b.batch.Done()
+}
+
+// NewSessionAddress returns the account that sessions delegated by [pk]
+// spend from.
+func NewSessionAddress(pk ed25519.PublicKey) codec.Address {
+	return codec.CreateAddress(mconsts.SessionID, utils.ToID(pk[:]))
+}
+
+// SessionAddressOf returns the session account of [actor], which is either
+// the [ED25519] key that delegates to it or the account itself, and false if
+// [actor] has no session account.
+func SessionAddressOf(actor codec.Address) (codec.Address, bool) {
+	switch actor[0] {
+	case mconsts.ED25519ID:
+		return codec.CreateAddress(mconsts.SessionID, ids.ID(actor[1:])), true
+	case mconsts.SessionID:
+		return actor, true
+	default:
+		return codec.EmptyAddress, false
+	}
+}
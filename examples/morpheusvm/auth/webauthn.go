@@ -0,0 +1,218 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Auth = (*WebAuthn)(nil)
+
+const (
+	WebAuthnComputeUnits = SECP256R1ComputeUnits + 5 // parsing [ClientDataJSON]
+
+	// MaxAuthenticatorDataLen and MaxClientDataJSONLen bound the size of
+	// an assertion. Authenticators may append extensions to both.
+	MaxAuthenticatorDataLen = 256
+	MaxClientDataJSONLen    = 1024
+
+	// webAuthnGet is the type of the client data of an assertion.
+	webAuthnGet = "webauthn.get"
+
+	// Authenticator data is [rpIdHash] (32 bytes) || [flags] (1 byte) ||
+	// [signCount] (4 bytes) || extensions.
+	//
+	// source: https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+	authenticatorDataMinLen = sha256.Size + 1 + 4
+	authenticatorDataFlags  = sha256.Size
+	flagUserPresent         = 0x01
+	flagUserVerified        = 0x04
+)
+
+// WebAuthn is a WebAuthn assertion by a P-256 passkey. It lets browsers sign
+// transactions with a passkey instead of a key managed by the wallet.
+//
+// The challenge of the assertion must be [WebAuthnChallenge] of the
+// transaction digest. Because the relying party is not known on-chain, the
+// rpIdHash of [AuthenticatorData] is not checked.
+type WebAuthn struct {
+	Signer            secp256r1.PublicKey `json:"signer"`
+	AuthenticatorData []byte              `json:"authenticatorData"`
+	ClientDataJSON    []byte              `json:"clientDataJSON"`
+
+	// Signature must be normalized (see [secp256r1.NewNormalizedSignature])
+	// so that it can't be malleated.
+	Signature secp256r1.Signature `json:"signature"`
+
+	addr codec.Address
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func (w *WebAuthn) address() codec.Address {
+	if w.addr == codec.EmptyAddress {
+		w.addr = NewWebAuthnAddress(w.Signer)
+	}
+	return w.addr
+}
+
+func (*WebAuthn) GetTypeID() uint8 {
+	return consts.WebAuthnID
+}
+
+func (*WebAuthn) ComputeUnits(chain.Rules) uint64 {
+	return WebAuthnComputeUnits
+}
+
+func (*WebAuthn) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (w *WebAuthn) Verify(_ context.Context, msg []byte) error {
+	if len(w.AuthenticatorData) < authenticatorDataMinLen {
+		return ErrInvalidAuthenticatorData
+	}
+	if w.AuthenticatorData[authenticatorDataFlags]&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	var data clientData
+	if err := json.Unmarshal(w.ClientDataJSON, &data); err != nil {
+		return ErrInvalidClientData
+	}
+	if data.Type != webAuthnGet {
+		return ErrInvalidClientData
+	}
+	if data.Challenge != WebAuthnChallenge(msg) {
+		return ErrChallengeMismatch
+	}
+	if !secp256r1.Verify(webAuthnSignedData(w.AuthenticatorData, w.ClientDataJSON), w.Signer, w.Signature) {
+		return crypto.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (w *WebAuthn) Actor() codec.Address {
+	return w.address()
+}
+
+func (w *WebAuthn) Sponsor() codec.Address {
+	return w.address()
+}
+
+func (w *WebAuthn) Size() int {
+	return secp256r1.PublicKeyLen +
+		codec.BytesLen(w.AuthenticatorData) +
+		codec.BytesLen(w.ClientDataJSON) +
+		secp256r1.SignatureLen
+}
+
+func (w *WebAuthn) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(w.Signer[:])
+	p.PackBytes(w.AuthenticatorData)
+	p.PackBytes(w.ClientDataJSON)
+	p.PackFixedBytes(w.Signature[:])
+}
+
+func UnmarshalWebAuthn(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var w WebAuthn
+	signer := w.Signer[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(secp256r1.PublicKeyLen, &signer)
+	p.UnpackBytes(MaxAuthenticatorDataLen, true, &w.AuthenticatorData)
+	p.UnpackBytes(MaxClientDataJSONLen, true, &w.ClientDataJSON)
+	signature := w.Signature[:] // avoid allocating additional memory
+	p.UnpackFixedBytes(secp256r1.SignatureLen, &signature)
+	return &w, p.Err()
+}
+
+// WebAuthnChallenge is the challenge a passkey must sign to authorize a
+// transaction with digest [msg].
+func WebAuthnChallenge(msg []byte) string {
+	digest := sha256.Sum256(msg)
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// webAuthnSignedData is the message signed by an authenticator.
+//
+// source: https://www.w3.org/TR/webauthn-2/#sctn-op-get-assertion
+func webAuthnSignedData(authenticatorData []byte, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := make([]byte, 0, len(authenticatorData)+len(clientDataHash))
+	signed = append(signed, authenticatorData...)
+	return append(signed, clientDataHash[:]...)
+}
+
+var _ chain.AuthFactory = (*WebAuthnFactory)(nil)
+
+// WebAuthnFactory produces the same assertions as a passkey registered with
+// [rpID] used from [origin]. It is mostly useful for testing, as the private
+// key of a passkey never leaves its authenticator.
+type WebAuthnFactory struct {
+	priv   secp256r1.PrivateKey
+	rpID   string
+	origin string
+}
+
+func NewWebAuthnFactory(priv secp256r1.PrivateKey, rpID string, origin string) *WebAuthnFactory {
+	return &WebAuthnFactory{priv, rpID, origin}
+}
+
+func (w *WebAuthnFactory) authenticatorData() []byte {
+	rpIDHash := sha256.Sum256([]byte(w.rpID))
+	data := make([]byte, authenticatorDataMinLen)
+	copy(data, rpIDHash[:])
+	data[authenticatorDataFlags] = flagUserPresent | flagUserVerified
+	return data
+}
+
+func (w *WebAuthnFactory) clientDataJSON(msg []byte) ([]byte, error) {
+	return json.Marshal(&clientData{
+		Type:      webAuthnGet,
+		Challenge: WebAuthnChallenge(msg),
+		Origin:    w.origin,
+	})
+}
+
+func (w *WebAuthnFactory) Sign(msg []byte) (chain.Auth, error) {
+	authenticatorData := w.authenticatorData()
+	clientDataJSON, err := w.clientDataJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := secp256r1.Sign(webAuthnSignedData(authenticatorData, clientDataJSON), w.priv)
+	if err != nil {
+		return nil, err
+	}
+	return &WebAuthn{
+		Signer:            w.priv.PublicKey(),
+		AuthenticatorData: authenticatorData,
+		ClientDataJSON:    clientDataJSON,
+		Signature:         sig,
+	}, nil
+}
+
+func (w *WebAuthnFactory) MaxUnits() (uint64, uint64) {
+	// The client data of every transaction has the same length
+	clientDataJSON, _ := w.clientDataJSON(nil)
+	auth := &WebAuthn{AuthenticatorData: w.authenticatorData(), ClientDataJSON: clientDataJSON}
+	return uint64(auth.Size()), WebAuthnComputeUnits
+}
+
+func NewWebAuthnAddress(pk secp256r1.PublicKey) codec.Address {
+	return codec.CreateAddress(consts.WebAuthnID, utils.ToID(pk[:]))
+}
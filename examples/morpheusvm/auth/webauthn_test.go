@@ -0,0 +1,66 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/crypto/secp256r1"
+)
+
+func TestWebAuthn(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := secp256r1.GeneratePrivateKey()
+	require.NoError(err)
+	factory := NewWebAuthnFactory(priv, "example.com", "https://example.com")
+	msg := []byte("digest")
+
+	rauth, err := factory.Sign(msg)
+	require.NoError(err)
+	auth := rauth.(*WebAuthn)
+	require.NoError(auth.Verify(ctx, msg))
+	require.Equal(NewWebAuthnAddress(priv.PublicKey()), auth.Actor())
+
+	// Round-trip through the wire format
+	bandwidth, _ := factory.MaxUnits()
+	require.Equal(uint64(auth.Size()), bandwidth)
+	p := codec.NewWriter(auth.Size(), consts.NetworkSizeLimit)
+	auth.Marshal(p)
+	require.NoError(p.Err())
+	parsed, err := UnmarshalWebAuthn(codec.NewReader(p.Bytes(), consts.NetworkSizeLimit), nil)
+	require.NoError(err)
+	require.NoError(parsed.Verify(ctx, msg))
+
+	// The assertion is bound to the transaction digest
+	require.ErrorIs(auth.Verify(ctx, []byte("other digest")), ErrChallengeMismatch)
+
+	// The assertion requires user presence
+	absent := *auth
+	absent.AuthenticatorData = append([]byte{}, auth.AuthenticatorData...)
+	absent.AuthenticatorData[authenticatorDataFlags] = 0
+	require.ErrorIs(absent.Verify(ctx, msg), ErrUserNotPresent)
+
+	// The client data must be an assertion
+	var data clientData
+	require.NoError(json.Unmarshal(auth.ClientDataJSON, &data))
+	data.Type = "webauthn.create"
+	created := *auth
+	created.ClientDataJSON, err = json.Marshal(&data)
+	require.NoError(err)
+	require.ErrorIs(created.Verify(ctx, msg), ErrInvalidClientData)
+
+	// Modifying the authenticator data invalidates the signature
+	tampered := *auth
+	tampered.AuthenticatorData = append(append([]byte{}, auth.AuthenticatorData...), 0)
+	require.ErrorIs(tampered.Verify(ctx, msg), crypto.ErrInvalidSignature)
+}
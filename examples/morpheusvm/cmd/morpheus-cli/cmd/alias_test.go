@@ -0,0 +1,64 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+func TestAliasCommands(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	alice := codec.MustAddressBech32(consts.HRP, codec.CreateAddress(0, ids.GenerateTestID()))
+	bob := codec.MustAddressBech32(consts.HRP, codec.CreateAddress(0, ids.GenerateTestID()))
+
+	for _, args := range [][]string{
+		{"alias", "add", "alice"},
+		{"alias", "add", "alice", alice, bob},
+		{"alias", "remove"},
+		{"alias", "import"},
+		{"alias", "export", "a", "b"},
+	} {
+		_, err := execute(t, dir, args...)
+		require.ErrorIs(err, ErrInvalidArgs, args)
+	}
+
+	_, err := execute(t, dir, "alias", "add", "bob", bob)
+	require.NoError(err)
+	_, err = execute(t, dir, "alias", "add", "alice", alice)
+	require.NoError(err)
+	_, err = execute(t, dir, "alias", "add", "alice", bob)
+	require.ErrorIs(err, cli.ErrDuplicate)
+	_, err = execute(t, dir, "alias", "add", bob, alice)
+	require.ErrorIs(err, cli.ErrInvalidAlias)
+	out, err := execute(t, dir, "alias", "list", "--output", cli.JSONOutput)
+	require.NoError(err)
+	listed := `[{"alias":"alice","address":"` + alice + `"},{"alias":"bob","address":"` + bob + `"}]`
+	require.JSONEq(listed, out)
+
+	// Exported aliases can be imported after they are removed
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	_, err = execute(t, dir, "alias", "export", path)
+	require.NoError(err)
+	_, err = execute(t, dir, "alias", "remove", "alice")
+	require.NoError(err)
+	_, err = execute(t, dir, "alias", "remove", "alice")
+	require.ErrorIs(err, cli.ErrUnknownAlias)
+	out, err = execute(t, dir, "alias", "list", "--output", cli.JSONOutput)
+	require.NoError(err)
+	require.JSONEq(`[{"alias":"bob","address":"`+bob+`"}]`, out)
+	_, err = execute(t, dir, "alias", "import", path)
+	require.NoError(err)
+	out, err = execute(t, dir, "alias", "list", "--output", cli.JSONOutput)
+	require.NoError(err)
+	require.JSONEq(listed, out)
+}
@@ -5,11 +5,13 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/controller"
 	brpc "github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
 )
 
@@ -64,9 +66,68 @@ var chainInfoCmd = &cobra.Command{
 var watchChainCmd = &cobra.Command{
 	Use: "watch",
 	RunE: func(_ *cobra.Command, args []string) error {
-		return handler.Root().WatchChain(hideTxs, func(uri string, networkID uint32, chainID ids.ID) (chain.Parser, error) {
+		return handler.Root().WatchChain(hideTxs, jsonTxs, func(uri string, networkID uint32, chainID ids.ID) (chain.Parser, error) {
 			cli := brpc.NewJSONRPCClient(uri, networkID, chainID)
 			return cli.Parser(context.TODO())
 		}, handleTx)
 	},
 }
+
+var replayChainCmd = &cobra.Command{
+	Use: "replay [chain data dir]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		chainID, err := ids.FromString(replayChainID)
+		if err != nil {
+			return err
+		}
+		genesisBytes, err := os.ReadFile(genesisFile)
+		if err != nil {
+			return err
+		}
+		return handler.Root().ReplayBlocks(
+			controller.New,
+			args[0],
+			genesisBytes,
+			replayNetworkID,
+			chainID,
+			replayStart,
+			replayEnd,
+			replayShowUnits,
+			replayTraceDir,
+		)
+	},
+}
+
+var digestChainCmd = &cobra.Command{
+	Use: "digest [chain data dir]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		chainID, err := ids.FromString(replayChainID)
+		if err != nil {
+			return err
+		}
+		genesisBytes, err := os.ReadFile(genesisFile)
+		if err != nil {
+			return err
+		}
+		return handler.Root().DigestState(
+			controller.New,
+			args[0],
+			genesisBytes,
+			replayNetworkID,
+			chainID,
+			digestOut,
+		)
+	},
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/crypto/bls"
 	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/hd"
 	"github.com/ava-labs/hypersdk/crypto/secp256r1"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
@@ -123,6 +124,33 @@ func loadPrivateKey(k string, path string) (*cli.PrivateKey, error) {
 	}
 }
 
+// derivePrivateKey derives the key of type [k] at [path] from [seed]. BLS
+// keys can't be derived.
+func derivePrivateKey(k string, seed []byte, path string) (*cli.PrivateKey, error) {
+	switch k {
+	case ed25519Key:
+		p, err := hd.DeriveED25519(seed, path)
+		if err != nil {
+			return nil, err
+		}
+		return &cli.PrivateKey{
+			Address: auth.NewED25519Address(p.PublicKey()),
+			Bytes:   p[:],
+		}, nil
+	case secp256r1Key:
+		p, err := hd.DeriveSECP256R1(seed, path)
+		if err != nil {
+			return nil, err
+		}
+		return &cli.PrivateKey{
+			Address: auth.NewSECP256R1Address(p.PublicKey()),
+			Bytes:   p[:],
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s keys can't be derived from a mnemonic", ErrInvalidKeyType, k)
+	}
+}
+
 var keyCmd = &cobra.Command{
 	Use: "key",
 	RunE: func(*cobra.Command, []string) error {
@@ -184,6 +212,75 @@ var importKeyCmd = &cobra.Command{
 	},
 }
 
+var mnemonicKeyCmd = &cobra.Command{
+	Use: "mnemonic [ed25519/secp256r1]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return checkKeyType(args[0])
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		mnemonic, err := hd.NewMnemonic()
+		if err != nil {
+			return err
+		}
+		seed, err := hd.NewSeed(mnemonic, "")
+		if err != nil {
+			return err
+		}
+		priv, err := derivePrivateKey(args[0], seed, derivationPath)
+		if err != nil {
+			return err
+		}
+		if err := handler.h.StoreKey(priv); err != nil {
+			return err
+		}
+		if err := handler.h.StoreDefaultKey(priv.Address); err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}mnemonic (keep it secret, it can recover all your keys):{{/}} %s\n", mnemonic)
+		utils.Outf(
+			"{{green}}created address:{{/}} %s {{yellow}}path:{{/}} %s\n",
+			codec.MustAddressBech32(consts.HRP, priv.Address),
+			derivationPath,
+		)
+		return nil
+	},
+}
+
+var recoverKeyCmd = &cobra.Command{
+	Use: "recover [ed25519/secp256r1]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return checkKeyType(args[0])
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		seed, err := handler.Root().PromptSeed()
+		if err != nil {
+			return err
+		}
+		priv, err := derivePrivateKey(args[0], seed, derivationPath)
+		if err != nil {
+			return err
+		}
+		if err := handler.h.StoreKey(priv); err != nil {
+			return err
+		}
+		if err := handler.h.StoreDefaultKey(priv.Address); err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}recovered address:{{/}} %s {{yellow}}path:{{/}} %s\n",
+			codec.MustAddressBech32(consts.HRP, priv.Address),
+			derivationPath,
+		)
+		return nil
+	},
+}
+
 func lookupSetKeyBalance(choice int, address string, uri string, networkID uint32, chainID ids.ID) error {
 	// TODO: just load once
 	cli := brpc.NewJSONRPCClient(uri, networkID, chainID)
@@ -218,7 +315,22 @@ var setKeyCmd = &cobra.Command{
 }
 
 func lookupKeyBalance(addr codec.Address, uri string, networkID uint32, chainID ids.ID, _ ids.ID) error {
-	_, err := handler.GetBalance(context.TODO(), brpc.NewJSONRPCClient(uri, networkID, chainID), addr)
+	bcli := brpc.NewJSONRPCClient(uri, networkID, chainID)
+	if handler.Root().JSON() {
+		saddr := codec.MustAddressBech32(consts.HRP, addr)
+		balance, err := bcli.Balance(context.TODO(), saddr)
+		if err != nil {
+			return err
+		}
+		return handler.Root().PrintJSON(&cli.BalanceOutput{
+			URI:      uri,
+			Address:  saddr,
+			Symbol:   consts.Symbol,
+			Decimals: consts.Decimals,
+			Balance:  balance,
+		})
+	}
+	_, err := handler.GetBalance(context.TODO(), bcli, addr)
 	return err
 }
 
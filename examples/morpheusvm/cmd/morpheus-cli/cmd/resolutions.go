@@ -52,16 +52,16 @@ func sendAndWait(
 		utils.Outf("{{yellow}}skipping unexpected transaction:{{/}} %s\n", tx.ID())
 	}
 	if printStatus {
-		handler.Root().PrintStatus(tx.ID(), result.Success)
+		handler.Root().PrintStatus(tx.ID(), result.Status)
 	}
-	return result.Success, tx.ID(), nil
+	return result.Success(), tx.ID(), nil
 }
 
 func handleTx(tx *chain.Transaction, result *chain.Result) {
 	summaryStr := string(result.Output)
 	actor := tx.Auth.Actor()
 	status := "❌"
-	if result.Success {
+	if result.Success() {
 		status = "✅"
 		switch action := tx.Action.(type) { //nolint:gocritic
 		case *actions.Transfer:
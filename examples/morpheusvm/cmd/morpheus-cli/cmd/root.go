@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/crypto/hd"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 )
@@ -22,14 +24,20 @@ var (
 	handler *Handler
 
 	dbPath                string
+	outputFormat          string
 	genesisFile           string
 	minUnitPrice          []string
 	maxBlockUnits         []string
 	windowTargetUnits     []string
 	minBlockGap           int64
 	hideTxs               bool
+	jsonTxs               bool
 	randomRecipient       bool
 	maxTxBacklog          int
+	spamProfile           string
+	startTPS              int
+	targetTPS             int
+	rampDuration          time.Duration
 	checkAllChains        bool
 	prometheusBaseURI     string
 	prometheusOpenBrowser bool
@@ -37,6 +45,14 @@ var (
 	prometheusData        string
 	startPrometheus       bool
 	maxFee                int64
+	replayNetworkID       uint32
+	replayChainID         string
+	replayStart           uint64
+	replayEnd             uint64
+	replayShowUnits       bool
+	replayTraceDir        string
+	digestOut             string
+	derivationPath        string
 
 	rootCmd = &cobra.Command{
 		Use:        "morpheus-cli",
@@ -50,6 +66,7 @@ func init() {
 	rootCmd.AddCommand(
 		genesisCmd,
 		keyCmd,
+		aliasCmd,
 		chainCmd,
 		actionCmd,
 		spamCmd,
@@ -61,13 +78,23 @@ func init() {
 		defaultDatabase,
 		"path to database (will create it missing)",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&outputFormat,
+		"output",
+		cli.TextOutput,
+		fmt.Sprintf("output format (%s or %s)", cli.TextOutput, cli.JSONOutput),
+	)
 	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
-		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		controller := NewController(dbPath)
 		root, err := cli.New(controller)
 		if err != nil {
 			return err
 		}
+		if err := root.SetOutput(outputFormat); err != nil {
+			_ = root.CloseDatabase()
+			return err
+		}
+		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		handler = NewHandler(root)
 		return err
 	}
@@ -118,13 +145,32 @@ func init() {
 		false,
 		"check all chains",
 	)
+	for _, cmd := range []*cobra.Command{mnemonicKeyCmd, recoverKeyCmd} {
+		cmd.PersistentFlags().StringVar(
+			&derivationPath,
+			"path",
+			hd.DefaultPath,
+			"derivation path of the key",
+		)
+	}
 	keyCmd.AddCommand(
 		genKeyCmd,
 		importKeyCmd,
+		mnemonicKeyCmd,
+		recoverKeyCmd,
 		setKeyCmd,
 		balanceKeyCmd,
 	)
 
+	// alias
+	aliasCmd.AddCommand(
+		addAliasCmd,
+		removeAliasCmd,
+		listAliasCmd,
+		importAliasCmd,
+		exportAliasCmd,
+	)
+
 	// chain
 	watchChainCmd.PersistentFlags().BoolVar(
 		&hideTxs,
@@ -132,6 +178,78 @@ func init() {
 		false,
 		"hide txs",
 	)
+	watchChainCmd.PersistentFlags().BoolVar(
+		&jsonTxs,
+		"json",
+		false,
+		"print txs as JSON",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&genesisFile,
+		"genesis-file",
+		defaultGenesis,
+		"genesis file path",
+	)
+	replayChainCmd.PersistentFlags().Uint32Var(
+		&replayNetworkID,
+		"network-id",
+		constants.LocalID,
+		"network id of the chain",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&replayChainID,
+		"chain-id",
+		"",
+		"chain id of the chain",
+	)
+	replayChainCmd.PersistentFlags().Uint64Var(
+		&replayStart,
+		"start",
+		1,
+		"first height to report",
+	)
+	replayChainCmd.PersistentFlags().Uint64Var(
+		&replayEnd,
+		"end",
+		0,
+		"last height to replay",
+	)
+	replayChainCmd.PersistentFlags().BoolVar(
+		&replayShowUnits,
+		"show-units",
+		false,
+		"show units consumed by each tx",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&replayTraceDir,
+		"trace-dir",
+		"",
+		"directory to write the trace of each block to",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&genesisFile,
+		"genesis-file",
+		defaultGenesis,
+		"genesis file path",
+	)
+	digestChainCmd.PersistentFlags().Uint32Var(
+		&replayNetworkID,
+		"network-id",
+		constants.LocalID,
+		"network id of the chain",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&replayChainID,
+		"chain-id",
+		"",
+		"chain id of the chain",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&digestOut,
+		"out",
+		"",
+		"file to write the digest to as JSON",
+	)
 	chainCmd.AddCommand(
 		importChainCmd,
 		importANRChainCmd,
@@ -139,6 +257,8 @@ func init() {
 		setChainCmd,
 		chainInfoCmd,
 		watchChainCmd,
+		replayChainCmd,
+		digestChainCmd,
 	)
 
 	// actions
@@ -165,6 +285,30 @@ func init() {
 		-1,
 		"max fee per tx",
 	)
+	runSpamCmd.PersistentFlags().StringVar(
+		&spamProfile,
+		"profile",
+		cli.UniformProfile,
+		"workload profile",
+	)
+	runSpamCmd.PersistentFlags().IntVar(
+		&startTPS,
+		"start-tps",
+		0,
+		"tps to issue at the start of the ramp",
+	)
+	runSpamCmd.PersistentFlags().IntVar(
+		&targetTPS,
+		"target-tps",
+		0,
+		"tps to ramp to (0 to issue a fixed number of txs per account)",
+	)
+	runSpamCmd.PersistentFlags().DurationVar(
+		&rampDuration,
+		"ramp-duration",
+		0,
+		"duration over which tps is ramped to target",
+	)
 	spamCmd.AddCommand(
 		runSpamCmd,
 	)
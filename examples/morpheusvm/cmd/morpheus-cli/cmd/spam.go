@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	brpc "github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+	"github.com/ava-labs/hypersdk/loadgen"
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
@@ -47,6 +48,15 @@ var spamCmd = &cobra.Command{
 	},
 }
 
+// spamRamp returns the ramp configured by the spam flags (or nil if a fixed
+// number of txs should be issued per account).
+func spamRamp() *loadgen.TPSRamp {
+	if targetTPS <= 0 {
+		return nil
+	}
+	return &loadgen.TPSRamp{Start: startTPS, Target: targetTPS, Duration: rampDuration}
+}
+
 var runSpamCmd = &cobra.Command{
 	Use: "run [ed25519/secp256r1/bls]",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -63,7 +73,7 @@ var runSpamCmd = &cobra.Command{
 			v := uint64(maxFee)
 			maxFeeParsed = &v
 		}
-		return handler.Root().Spam(maxTxBacklog, maxFeeParsed, randomRecipient,
+		return handler.Root().Spam(maxTxBacklog, maxFeeParsed, randomRecipient, spamProfile, spamRamp(),
 			func(uri string, networkID uint32, chainID ids.ID) error { // createClient
 				bclient = brpc.NewJSONRPCClient(uri, networkID, chainID)
 				ws, err := rpc.NewWebSocketClient(uri, rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
@@ -113,6 +123,7 @@ var runSpamCmd = &cobra.Command{
 					return err
 				}
 			},
+			nil,
 		)
 	},
 }
@@ -0,0 +1,46 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/loadgen"
+)
+
+func TestSpamFlags(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		args    []string
+		profile string
+		ramp    *loadgen.TPSRamp
+		err     error
+	}{
+		{nil, cli.UniformProfile, nil, ErrInvalidArgs},
+		{[]string{"rsa"}, cli.UniformProfile, nil, ErrInvalidKeyType},
+		{[]string{ed25519Key}, cli.UniformProfile, nil, cli.ErrNoChains},
+		{[]string{blsKey, "--profile", cli.ZipfianProfile}, cli.ZipfianProfile, nil, cli.ErrNoChains},
+		{[]string{ed25519Key, "--profile", "orderbook"}, "orderbook", nil, cli.ErrUnknownProfile},
+		{
+			[]string{secp256r1Key, "--start-tps", "10", "--target-tps", "100", "--ramp-duration", "1m"},
+			cli.UniformProfile,
+			&loadgen.TPSRamp{Start: 10, Target: 100, Duration: time.Minute},
+			cli.ErrNoChains,
+		},
+	}
+	for _, tt := range tests {
+		// Spamming requires a chain, so we can only check that arguments
+		// are parsed before it is selected
+		_, err := execute(t, dir, append([]string{"spam", "run"}, tt.args...)...)
+		require.ErrorIs(err, tt.err, tt.args)
+		require.Equal(tt.profile, spamProfile, tt.args)
+		require.Equal(tt.ramp, spamRamp(), tt.args)
+	}
+}
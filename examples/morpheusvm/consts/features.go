@@ -0,0 +1,16 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consts
+
+// Feature flags that an upgrade can activate (see package fork).
+const (
+	// NoSelfTransfers makes transfers to the actor fail instead of only
+	// charging fees.
+	NoSelfTransfers = "noSelfTransfers"
+)
+
+// Features are all the feature flags known by the morpheusvm.
+var Features = []string{
+	NoSelfTransfers,
+}
@@ -5,10 +5,22 @@ package consts
 
 const (
 	// Action TypeIDs
-	TransferID uint8 = 0
+	TransferID          uint8 = 0
+	RegisterAggregateID uint8 = 1
+	RotateKeyID         uint8 = 2
+	SetGuardiansID      uint8 = 3
+	InitiateRecoveryID  uint8 = 4
+	FinalizeRecoveryID  uint8 = 5
+	CancelID            uint8 = 6
+	UpdateRulesID       uint8 = 7
+	RevokeSessionID     uint8 = 8
 
 	// Auth TypeIDs
-	ED25519ID   uint8 = 0
-	SECP256R1ID uint8 = 1
-	BLSID       uint8 = 2
+	ED25519ID      uint8 = 0
+	SECP256R1ID    uint8 = 1
+	BLSID          uint8 = 2
+	SessionID      uint8 = 3
+	BLSAggregateID uint8 = 4
+	AccountID      uint8 = 5
+	WebAuthnID     uint8 = 6
 )
@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/gossiper"
 	hrpc "github.com/ava-labs/hypersdk/rpc"
 	hstorage "github.com/ava-labs/hypersdk/storage"
@@ -29,7 +30,11 @@ import (
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/version"
 )
 
-var _ vm.Controller = (*Controller)(nil)
+var (
+	_ vm.Controller              = (*Controller)(nil)
+	_ vm.BeneficiaryController   = (*Controller)(nil)
+	_ vm.StateRegistryController = (*Controller)(nil)
+)
 
 type Controller struct {
 	inner *vm.VM
@@ -39,6 +44,8 @@ type Controller struct {
 	config       *config.Config
 	stateManager *storage.StateManager
 
+	stateRegistry *hrpc.StateRegistry
+
 	metrics *metrics
 
 	metaDB database.Database
@@ -84,7 +91,6 @@ func (c *Controller) Initialize(
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	c.snowCtx.Log.SetLevel(c.config.GetLogLevel())
 	snowCtx.Log.Info("initialized config", zap.Bool("loaded", c.config.Loaded()), zap.Any("contents", c.config))
 
 	c.genesis, err = genesis.New(genesisBytes, upgradeBytes)
@@ -111,11 +117,16 @@ func (c *Controller) Initialize(
 	jsonRPCHandler, err := hrpc.NewJSONRPCHandler(
 		consts.Name,
 		rpc.NewJSONRPCServer(c),
+		c.inner.RecordRPCRequest,
 	)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 	apis[rpc.JSONRPCEndpoint] = jsonRPCHandler
+	c.stateRegistry = hrpc.NewStateRegistry()
+	if err := storage.RegisterStateQueries(c.stateRegistry); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
 
 	// Create builder and gossiper
 	var (
@@ -129,6 +140,13 @@ func (c *Controller) Initialize(
 	} else {
 		build = builder.NewTime(inner)
 		gcfg := gossiper.DefaultProposerConfig()
+		if c.config.APINode {
+			// API nodes never build blocks, so they should forward all txs they
+			// receive instead of holding them when they are a proposer.
+			c.inner.Logger().Info("running as api node")
+			build = builder.NewManual(inner)
+			gcfg.NoGossipBuilderDiff = 0
+		}
 		gossip, err = gossiper.NewProposer(inner, gcfg)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
@@ -138,7 +156,6 @@ func (c *Controller) Initialize(
 }
 
 func (c *Controller) Rules(t int64) chain.Rules {
-	// TODO: extend with [UpgradeBytes]
 	return c.genesis.Rules(t, c.snowCtx.NetworkID, c.snowCtx.ChainID)
 }
 
@@ -146,6 +163,18 @@ func (c *Controller) StateManager() chain.StateManager {
 	return c.stateManager
 }
 
+func (c *Controller) StateRegistry() *hrpc.StateRegistry {
+	return c.stateRegistry
+}
+
+func (*Controller) NewStateSummary() vm.StateSummary {
+	return storage.NewStateSummary()
+}
+
+func (c *Controller) Beneficiary() (codec.Address, bool) {
+	return c.config.GetBeneficiary()
+}
+
 func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) error {
 	batch := c.metaDB.NewBatch()
 	defer batch.Reset()
@@ -159,7 +188,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				batch,
 				tx.ID(),
 				blk.GetTimestamp(),
-				result.Success,
+				result.Status,
 				result.Consumed,
 				result.Fee,
 			)
@@ -167,7 +196,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				return err
 			}
 		}
-		if result.Success {
+		if result.Success() {
 			switch tx.Action.(type) { //nolint:gocritic
 			case *actions.Transfer:
 				c.metrics.transfer.Inc()
@@ -181,8 +210,10 @@ func (*Controller) Rejected(context.Context, *chain.StatelessBlock) error {
 	return nil
 }
 
-func (*Controller) Shutdown(context.Context) error {
+func (c *Controller) Shutdown(context.Context) error {
 	// Do not close any databases provided during initialization. The VM will
 	// close any databases your provided.
-	return nil
+	//
+	// [metaDB] is only used by the controller, so we close it here.
+	return c.metaDB.Close()
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 )
@@ -30,7 +31,7 @@ func (c *Controller) Tracer() trace.Tracer {
 func (c *Controller) GetTransaction(
 	ctx context.Context,
 	txID ids.ID,
-) (bool, int64, bool, chain.Dimensions, uint64, error) {
+) (bool, int64, chain.Status, chain.Dimensions, uint64, error) {
 	return storage.GetTransaction(ctx, c.metaDB, txID)
 }
 
@@ -40,3 +41,39 @@ func (c *Controller) GetBalanceFromState(
 ) (uint64, error) {
 	return storage.GetBalanceFromState(ctx, c.inner.ReadState, acct)
 }
+
+func (c *Controller) GetAggregateFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (uint16, bool, error) {
+	return storage.GetAggregateFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetAccountKeyFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (ed25519.PublicKey, bool, error) {
+	return storage.GetAccountKeyFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetGuardiansFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (*storage.Guardians, bool, error) {
+	return storage.GetGuardiansFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetRecoveryFromState(
+	ctx context.Context,
+	acct codec.Address,
+) (*storage.Recovery, bool, error) {
+	return storage.GetRecoveryFromState(ctx, c.inner.ReadState, acct)
+}
+
+func (c *Controller) GetSessionFromState(
+	ctx context.Context,
+	acct codec.Address,
+	delegation ids.ID,
+) (uint64, bool, error) {
+	return storage.GetSessionFromState(ctx, c.inner.ReadState, acct, delegation)
+}
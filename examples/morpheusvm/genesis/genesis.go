@@ -17,6 +17,7 @@ import (
 	hconsts "github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/fork"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/vm"
 )
@@ -28,6 +29,15 @@ type CustomAllocation struct {
 	Balance uint64 `json:"balance"`
 }
 
+// Deprecation prevents a version of an action or auth (see
+// [chain.Versioned]) from being included in blocks starting at [Height].
+type Deprecation struct {
+	Auth    bool   `json:"auth"` // false for an action
+	TypeID  uint8  `json:"typeId"`
+	Version uint8  `json:"version"`
+	Height  uint64 `json:"height"`
+}
+
 type Genesis struct {
 	// State Parameters
 	StateBranchFactor merkledb.BranchFactor `json:"stateBranchFactor"`
@@ -41,9 +51,25 @@ type Genesis struct {
 	UnitPriceChangeDenominator chain.Dimensions `json:"unitPriceChangeDenominator"`
 	WindowTargetUnits          chain.Dimensions `json:"windowTargetUnits"` // 10s
 	MaxBlockUnits              chain.Dimensions `json:"maxBlockUnits"`     // must be possible to reach before block too large
+	BuilderFeeShare            uint64           `json:"builderFeeShare"`   // % of fees credited to the block builder (rest is burned)
+	MinFee                     uint64           `json:"minFee"`            // charged to each tx that pays fees, 0 disables
+
+	// Block Reward Parameters
+	//
+	// Each block mints [BlockReward] (halved every [BlockRewardHalvingInterval]
+	// blocks) to its beneficiary or, if it has none, to [BlockRewardPool].
+	BlockReward                uint64        `json:"blockReward"`                // 0 disables rewards
+	BlockRewardHalvingInterval uint64        `json:"blockRewardHalvingInterval"` // blocks (0 disables halving)
+	BlockRewardPool            codec.Address `json:"blockRewardPool"`            // hex (if empty, blocks without a beneficiary mint nothing)
 
 	// Tx Parameters
-	ValidityWindow int64 `json:"validityWindow"` // ms
+	ValidityWindow       int64  `json:"validityWindow"`       // ms
+	HeightValidityWindow uint64 `json:"heightValidityWindow"` // blocks (0 disables height expiry)
+	EpochLength          uint64 `json:"epochLength"`          // blocks (0 disables epochs)
+	ValidatorSnapshots   bool   `json:"validatorSnapshots"`   // snapshot the validator set at the end of each epoch
+	BlockWitnesses       bool   `json:"blockWitnesses"`       // include merkle witnesses of all keys read in blocks
+	SequentialNonces     bool   `json:"sequentialNonces"`     // protect txs from replay with account nonces instead of expiry
+	TxCancellation       bool   `json:"txCancellation"`       // let sponsors cancel pending txs (every tx reads the cancel key of its sponsor)
 
 	// Tx Fee Parameters
 	BaseComputeUnits          uint64 `json:"baseUnits"`
@@ -57,8 +83,28 @@ type Genesis struct {
 	StorageKeyWriteUnits      uint64 `json:"storageKeyWriteUnits"`
 	StorageValueWriteUnits    uint64 `json:"storageValueWriteUnits"` // per chunk
 
+	// ActionComputeUnits overrides the compute units charged for executing
+	// each action (keyed by action type ID).
+	ActionComputeUnits map[uint8]uint64 `json:"actionComputeUnits"`
+
+	// ActionFeePayers charges the fees of transactions with each action
+	// (keyed by action type ID) to an account (hex) instead of their sponsor.
+	// Actions mapped to the empty address are exempt from fees.
+	ActionFeePayers map[uint8]codec.Address `json:"actionFeePayers"`
+
+	// RulesAuthority can override the min block gap, validity window, and
+	// max block units with an UpdateRules action (hex, if empty the rules
+	// can't be overridden).
+	RulesAuthority codec.Address `json:"rulesAuthority"`
+
+	// Deprecations of action and auth versions
+	Deprecations []*Deprecation `json:"deprecations"`
+
 	// Allocates
 	CustomAllocation []*CustomAllocation `json:"customAllocation"`
+
+	activations []*activation
+	forks       *fork.Schedule
 }
 
 func Default() *Genesis {
@@ -71,10 +117,13 @@ func Default() *Genesis {
 		MinEmptyBlockGap: 2_500,
 
 		// Chain Fee Parameters
-		MinUnitPrice:               chain.Dimensions{100, 100, 100, 100, 100},
-		UnitPriceChangeDenominator: chain.Dimensions{48, 48, 48, 48, 48},
-		WindowTargetUnits:          chain.Dimensions{20_000_000, 1_000, 1_000, 1_000, 1_000},
-		MaxBlockUnits:              chain.Dimensions{1_800_000, 2_000, 2_000, 2_000, 2_000},
+		//
+		// Blobs are not counted towards bandwidth, so the max blob units (in
+		// bytes) must leave room for the rest of the block.
+		MinUnitPrice:               chain.Dimensions{100, 100, 100, 100, 100, 1},
+		UnitPriceChangeDenominator: chain.Dimensions{48, 48, 48, 48, 48, 48},
+		WindowTargetUnits:          chain.Dimensions{20_000_000, 1_000, 1_000, 1_000, 1_000, 1_048_576},
+		MaxBlockUnits:              chain.Dimensions{1_800_000, 2_000, 2_000, 2_000, 2_000, 262_144},
 
 		// Tx Parameters
 		ValidityWindow: 60 * hconsts.MillisecondsPerSecond, // ms
@@ -97,13 +146,25 @@ func Default() *Genesis {
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal config %s: %w", string(b), err)
 		}
 	}
+	if len(upgradeBytes) > 0 {
+		var upgrades []*Upgrade
+		if err := json.Unmarshal(upgradeBytes, &upgrades); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal upgrades %s: %w", string(upgradeBytes), err)
+		}
+		g.activations = activate(g, upgrades)
+		forks, err := schedule(upgrades)
+		if err != nil {
+			return nil, err
+		}
+		g.forks = forks
+	}
 	return g, nil
 }
 
@@ -114,6 +175,9 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 	if err := g.StateBranchFactor.Valid(); err != nil {
 		return err
 	}
+	if g.BuilderFeeShare > chain.MaxBuilderFeeShare {
+		return chain.ErrInvalidBuilderFeeShare
+	}
 
 	supply := uint64(0)
 	for _, alloc := range g.CustomAllocation {
@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+
+	_ "github.com/ava-labs/hypersdk/examples/morpheusvm/registry" // registers the blob dimension
+)
+
+func TestCustomDimensionTargets(t *testing.T) {
+	require := require.New(t)
+
+	blobDimension, ok := chain.BlobDimension()
+	require.True(ok)
+	require.Equal(6, chain.FeeDimensions())
+
+	g, err := New([]byte(`{
+		"minUnitPrice":[100,100,100,100,100,7],
+		"windowTargetUnits":[20000000,1000,1000,1000,1000,4096],
+		"maxBlockUnits":[1800000,2000,2000,2000,2000]
+	}`), nil)
+	require.NoError(err)
+	r := g.Rules(0, 1, ids.Empty)
+	require.Equal(uint64(7), r.GetMinUnitPrice()[blobDimension])
+	require.Equal(uint64(4096), r.GetWindowTargetUnits()[blobDimension])
+	require.Equal(uint64(1000), r.GetWindowTargetUnits()[chain.StorageWrite])
+	require.Zero(r.GetMaxBlockUnits()[blobDimension]) // omitted values are 0
+	require.Equal(Default().UnitPriceChangeDenominator, r.GetUnitPriceChangeDenominator())
+
+	// Targets are written for every registered dimension and load the same
+	b, err := json.Marshal(g)
+	require.NoError(err)
+	var raw map[string]json.RawMessage
+	require.NoError(json.Unmarshal(b, &raw))
+	require.JSONEq(`[20000000,1000,1000,1000,1000,4096]`, string(raw["windowTargetUnits"]))
+	require.JSONEq(`[1800000,2000,2000,2000,2000,0]`, string(raw["maxBlockUnits"]))
+	loaded, err := New(b, nil)
+	require.NoError(err)
+	require.Equal(g.WindowTargetUnits, loaded.WindowTargetUnits)
+	require.Equal(g.MinUnitPrice, loaded.MinUnitPrice)
+	require.Equal(g.MaxBlockUnits, loaded.MaxBlockUnits)
+
+	// Targets for dimensions that aren't registered are rejected
+	_, err = New([]byte(`{"windowTargetUnits":[1,1,1,1,1,1,1]}`), nil)
+	require.ErrorIs(err, chain.ErrWrongDimensionSize)
+}
@@ -6,6 +6,7 @@ package genesis
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 )
 
@@ -16,17 +17,30 @@ type Rules struct {
 
 	networkID uint32
 	chainID   ids.ID
+	timestamp int64
+	a         *activation
 }
 
-// TODO: use upgradeBytes
-func (g *Genesis) Rules(_ int64, networkID uint32, chainID ids.ID) *Rules {
-	return &Rules{g, networkID, chainID}
+func (g *Genesis) Rules(t int64, networkID uint32, chainID ids.ID) *Rules {
+	return &Rules{g, networkID, chainID, t, g.activation(t)}
+}
+
+func (r *Rules) IsFeatureActive(feature string) bool {
+	return r.g.forks != nil && r.g.forks.IsActive(feature, r.timestamp)
 }
 
 func (*Rules) GetWarpConfig(ids.ID) (bool, uint64, uint64) {
 	return false, 0, 0
 }
 
+func (*Rules) GetWarpMaxSigners() int {
+	return 0
+}
+
+func (*Rules) GetWarpReplayRetention() int64 {
+	return 0
+}
+
 func (r *Rules) NetworkID() uint32 {
 	return r.networkID
 }
@@ -47,6 +61,10 @@ func (r *Rules) GetValidityWindow() int64 {
 	return r.g.ValidityWindow
 }
 
+func (r *Rules) GetHeightValidityWindow() uint64 {
+	return r.g.HeightValidityWindow
+}
+
 func (r *Rules) GetMaxBlockUnits() chain.Dimensions {
 	return r.g.MaxBlockUnits
 }
@@ -71,6 +89,64 @@ func (*Rules) GetSponsorStateKeysMaxChunks() []uint16 {
 	return []uint16{storage.BalanceChunks}
 }
 
+func (r *Rules) GetActionComputeUnits(typeID uint8) (uint64, bool) {
+	units, ok := r.a.actionComputeUnits[typeID]
+	return units, ok
+}
+
+func (r *Rules) IsActionDisabled(typeID uint8) bool {
+	return r.a.disabledActions[typeID]
+}
+
+func (r *Rules) GetActionDeprecationHeight(typeID uint8, version uint8) (uint64, bool) {
+	return r.g.deprecationHeight(false, typeID, version)
+}
+
+func (r *Rules) GetAuthDeprecationHeight(typeID uint8, version uint8) (uint64, bool) {
+	return r.g.deprecationHeight(true, typeID, version)
+}
+
+func (r *Rules) GetBuilderFeeShare() uint64 {
+	return r.g.BuilderFeeShare
+}
+
+func (r *Rules) GetEpochLength() uint64 {
+	return r.g.EpochLength
+}
+
+func (r *Rules) GetBlockReward(height uint64) uint64 {
+	return chain.HalvingReward(r.g.BlockReward, r.g.BlockRewardHalvingInterval, height)
+}
+
+func (r *Rules) GetBlockRewardPool() codec.Address {
+	return r.g.BlockRewardPool
+}
+
+func (r *Rules) GetValidatorSnapshots() bool {
+	return r.g.ValidatorSnapshots
+}
+
+func (r *Rules) GetBlockWitnesses() bool {
+	return r.g.BlockWitnesses
+}
+
+func (r *Rules) GetSequentialNonces() bool {
+	return r.g.SequentialNonces
+}
+
+func (r *Rules) GetTxCancellation() bool {
+	return r.g.TxCancellation
+}
+
+func (r *Rules) GetActionFeePayer(typeID uint8) (codec.Address, bool) {
+	payer, ok := r.g.ActionFeePayers[typeID]
+	return payer, ok
+}
+
+func (r *Rules) GetRulesAuthority() codec.Address {
+	return r.g.RulesAuthority
+}
+
 func (r *Rules) GetStorageKeyReadUnits() uint64 {
 	return r.g.StorageKeyReadUnits
 }
@@ -99,6 +175,10 @@ func (r *Rules) GetMinUnitPrice() chain.Dimensions {
 	return r.g.MinUnitPrice
 }
 
+func (r *Rules) GetMinFee() uint64 {
+	return r.g.MinFee
+}
+
 func (r *Rules) GetUnitPriceChangeDenominator() chain.Dimensions {
 	return r.g.UnitPriceChangeDenominator
 }
@@ -0,0 +1,126 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"sort"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/fork"
+)
+
+// Upgrade changes the [Rules] of a live network (provided as a JSON list in
+// upgradeBytes).
+type Upgrade struct {
+	Timestamp int64 `json:"timestamp"` // ms
+
+	// ActionComputeUnits overrides the compute units charged for executing
+	// each action (keyed by action type ID). Overrides are added to those
+	// provided in genesis and by earlier upgrades.
+	ActionComputeUnits map[uint8]uint64 `json:"actionComputeUnits"`
+
+	// DisabledActions prevents actions (keyed by action type ID) from being
+	// included in blocks, so that buggy actions can be sunset. Blocks
+	// accepted before the upgrade can still be replayed. Actions disabled by
+	// earlier upgrades can be enabled again by setting them to false.
+	DisabledActions map[uint8]bool `json:"disabledActions"`
+
+	// Features activates each feature flag (see [consts.Features]) at
+	// [Timestamp]. A feature can only be activated by one upgrade.
+	Features []string `json:"features"`
+}
+
+// activation is the set of overrides in effect starting at [timestamp].
+type activation struct {
+	timestamp          int64
+	actionComputeUnits map[uint8]uint64
+	disabledActions    map[uint8]bool
+}
+
+// activate merges [upgrades] in order of activation so that [Rules] does not
+// need to do so on each call.
+func activate(g *Genesis, upgrades []*Upgrade) []*activation {
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		return upgrades[i].Timestamp < upgrades[j].Timestamp
+	})
+	var (
+		activations = make([]*activation, 0, len(upgrades))
+		prev        = g.genesisActivation()
+	)
+	for _, upgrade := range upgrades {
+		a := &activation{
+			timestamp:          upgrade.Timestamp,
+			actionComputeUnits: merge(prev.actionComputeUnits, upgrade.ActionComputeUnits),
+			disabledActions:    merge(prev.disabledActions, upgrade.DisabledActions),
+		}
+		for typeID, disabled := range a.disabledActions {
+			if !disabled {
+				delete(a.disabledActions, typeID)
+			}
+		}
+		activations = append(activations, a)
+		prev = a
+	}
+	return activations
+}
+
+// schedule returns the activation timestamp of each feature flag in
+// [upgrades].
+func schedule(upgrades []*Upgrade) (*fork.Schedule, error) {
+	forks := fork.NewSchedule(consts.Features...)
+	for _, upgrade := range upgrades {
+		for _, feature := range upgrade.Features {
+			if err := forks.Activate(feature, upgrade.Timestamp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return forks, nil
+}
+
+func merge[V any](prev map[uint8]V, next map[uint8]V) map[uint8]V {
+	merged := make(map[uint8]V, len(prev)+len(next))
+	for typeID, v := range prev {
+		merged[typeID] = v
+	}
+	for typeID, v := range next {
+		merged[typeID] = v
+	}
+	return merged
+}
+
+func (g *Genesis) genesisActivation() *activation {
+	return &activation{actionComputeUnits: g.ActionComputeUnits}
+}
+
+// activation returns the overrides in effect at [t].
+func (g *Genesis) activation(t int64) *activation {
+	current := g.genesisActivation()
+	for _, a := range g.activations {
+		if a.timestamp > t {
+			break
+		}
+		current = a
+	}
+	return current
+}
+
+// deprecationHeight returns the earliest deprecation of [version] of the
+// action (or auth, if [auth] is set) with [typeID].
+func (g *Genesis) deprecationHeight(auth bool, typeID uint8, version uint8) (uint64, bool) {
+	var (
+		height uint64
+		found  bool
+	)
+	for _, d := range g.Deprecations {
+		if d.Auth != auth || d.TypeID != typeID || d.Version != version {
+			continue
+		}
+		if !found || d.Height < height {
+			height = d.Height
+			found = true
+		}
+	}
+	return height, found
+}
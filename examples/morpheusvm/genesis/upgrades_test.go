@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/fork"
+)
+
+func TestActionComputeUnits(t *testing.T) {
+	require := require.New(t)
+
+	g, err := New(
+		[]byte(`{"actionComputeUnits":{"0":10}}`),
+		[]byte(`[
+			{"timestamp":2000,"actionComputeUnits":{"1":30}},
+			{"timestamp":1000,"actionComputeUnits":{"0":20}}
+		]`),
+	)
+	require.NoError(err)
+
+	get := func(t int64, typeID uint8) (uint64, bool) {
+		return g.Rules(t, 1, ids.Empty).GetActionComputeUnits(typeID)
+	}
+
+	// Genesis
+	units, ok := get(0, 0)
+	require.True(ok)
+	require.Equal(uint64(10), units)
+	_, ok = get(0, 1)
+	require.False(ok)
+
+	// First upgrade
+	units, ok = get(1000, 0)
+	require.True(ok)
+	require.Equal(uint64(20), units)
+	_, ok = get(1999, 1)
+	require.False(ok)
+
+	// Second upgrade keeps earlier overrides
+	units, ok = get(2000, 0)
+	require.True(ok)
+	require.Equal(uint64(20), units)
+	units, ok = get(2000, 1)
+	require.True(ok)
+	require.Equal(uint64(30), units)
+
+	// Malformed upgrades are rejected
+	_, err = New(nil, []byte(`{}`))
+	require.Error(err)
+}
+
+func TestDisabledActions(t *testing.T) {
+	require := require.New(t)
+
+	g, err := New(
+		nil,
+		[]byte(`[
+			{"timestamp":1000,"disabledActions":{"1":true}},
+			{"timestamp":2000,"disabledActions":{"1":false}}
+		]`),
+	)
+	require.NoError(err)
+
+	disabled := func(t int64, typeID uint8) bool {
+		return g.Rules(t, 1, ids.Empty).IsActionDisabled(typeID)
+	}
+
+	// Genesis
+	require.False(disabled(999, 1))
+
+	// First upgrade only disables the listed action
+	require.True(disabled(1000, 1))
+	require.True(disabled(1999, 1))
+	require.False(disabled(1000, 0))
+
+	// Second upgrade re-enables it
+	require.False(disabled(2000, 1))
+}
+
+func TestFeatures(t *testing.T) {
+	require := require.New(t)
+
+	g, err := New(nil, []byte(`[{"timestamp":1000,"features":["noSelfTransfers"]}]`))
+	require.NoError(err)
+	require.False(g.Rules(999, 1, ids.Empty).IsFeatureActive(consts.NoSelfTransfers))
+	require.True(g.Rules(1000, 1, ids.Empty).IsFeatureActive(consts.NoSelfTransfers))
+	require.False(Default().Rules(1000, 1, ids.Empty).IsFeatureActive(consts.NoSelfTransfers))
+
+	// Typos and conflicting activations are rejected
+	_, err = New(nil, []byte(`[{"timestamp":1000,"features":["noSelfTransfer"]}]`))
+	require.ErrorIs(err, fork.ErrUnknownFeature)
+	_, err = New(nil, []byte(`[
+		{"timestamp":1000,"features":["noSelfTransfers"]},
+		{"timestamp":2000,"features":["noSelfTransfers"]}
+	]`))
+	require.ErrorIs(err, fork.ErrAlreadyScheduled)
+}
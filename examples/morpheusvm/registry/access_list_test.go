@@ -0,0 +1,66 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+func TestAccessListEncoding(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	factory := auth.NewED25519Factory(priv)
+	action := &actions.Transfer{To: auth.NewED25519Address(ed25519.PublicKey{1}), Value: 1}
+	actionKeys := action.StateKeys(auth.NewED25519Address(priv.PublicKey()), ids.Empty)
+	extra := string(keys.EncodeChunks([]byte("extra"), 1))
+
+	sign := func(accessList []string) *chain.Transaction {
+		tx := chain.NewTx(&chain.Base{Timestamp: fuzzTimestamp, ChainID: ids.GenerateTestID(), MaxFee: 1_000}, nil, action)
+		tx.AccessList = accessList
+		tx, err := tx.Sign(factory, consts.ActionRegistry, consts.AuthRegistry)
+		require.NoError(err)
+		return tx
+	}
+
+	// The access list is preserved by every encoding
+	tx := sign(append([]string{extra}, actionKeys...))
+	for _, e := range chain.Encodings() {
+		raw, err := chain.MarshalTxEncoding(e, tx)
+		require.NoError(err)
+		parsed, err := chain.UnmarshalTxEncoding(e, raw, consts.ActionRegistry, consts.AuthRegistry)
+		require.NoError(err)
+		require.Equal(tx.ID(), parsed.ID())
+		require.Equal(tx.AccessList, parsed.AccessList)
+	}
+
+	// Malformed access lists can't be parsed
+	for _, accessList := range [][]string{{extra, extra}, {"x"}} {
+		tx := chain.NewTx(&chain.Base{Timestamp: fuzzTimestamp, ChainID: ids.GenerateTestID(), MaxFee: 1_000}, nil, action)
+		tx.AccessList = accessList
+		_, err := tx.Sign(factory, consts.ActionRegistry, consts.AuthRegistry)
+		require.ErrorIs(err, chain.ErrInvalidAccessList)
+	}
+
+	// Transactions without an access list don't encode it
+	tx = sign(nil)
+	require.Nil(tx.AccessList)
+	p := codec.NewReader(tx.Bytes(), hconsts.NetworkSizeLimit)
+	parsed, err := chain.UnmarshalTx(p, consts.ActionRegistry, consts.AuthRegistry)
+	require.NoError(err)
+	require.Nil(parsed.AccessList)
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+func signedTransfer(t *testing.T, value uint64) *chain.Transaction {
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+	tx := chain.NewTx(
+		&chain.Base{Timestamp: fuzzTimestamp, ChainID: ids.GenerateTestID(), MaxFee: 1_000},
+		nil,
+		&actions.Transfer{To: auth.NewED25519Address(ed25519.PublicKey{1}), Value: value},
+	)
+	tx, err = tx.Sign(auth.NewED25519Factory(priv), consts.ActionRegistry, consts.AuthRegistry)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestTxEncodings(t *testing.T) {
+	tx := signedTransfer(t, 10)
+	for _, e := range chain.Encodings() {
+		t.Run(e.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			raw, err := chain.MarshalTxEncoding(e, tx)
+			require.NoError(err)
+			parsed, err := chain.UnmarshalTxEncoding(e, raw, consts.ActionRegistry, consts.AuthRegistry)
+			require.NoError(err)
+			require.Equal(tx.ID(), parsed.ID())
+			require.Equal(tx.Bytes(), parsed.Bytes())
+
+			_, err = chain.UnmarshalTxEncoding(e, append(raw, 0), consts.ActionRegistry, consts.AuthRegistry)
+			require.Error(err)
+		})
+	}
+
+	_, err := chain.UnmarshalTxEncoding(chain.ProtobufEncoding+1, tx.Bytes(), consts.ActionRegistry, consts.AuthRegistry)
+	require.ErrorIs(t, err, chain.ErrUnknownEncoding)
+}
+
+func TestTxProtoUnknownFields(t *testing.T) {
+	require := require.New(t)
+
+	tx := signedTransfer(t, 10)
+	raw, err := chain.MarshalTxProto(tx)
+	require.NoError(err)
+
+	// Fields added to the schema later are ignored by older nodes
+	raw = protowire.AppendTag(raw, 100, protowire.Fixed64Type)
+	raw = protowire.AppendFixed64(raw, 1)
+	parsed, err := chain.UnmarshalTxProto(raw, consts.ActionRegistry, consts.AuthRegistry)
+	require.NoError(err)
+	require.Equal(tx.ID(), parsed.ID())
+
+	// Type IDs are a single byte
+	var invalid []byte
+	invalid = protowire.AppendTag(invalid, 1, protowire.BytesType)
+	invalid = protowire.AppendBytes(invalid, nil)
+	for _, num := range []protowire.Number{4, 5} {
+		typed := protowire.AppendTag(nil, 1, protowire.VarintType)
+		typed = protowire.AppendVarint(typed, 256)
+		invalid = protowire.AppendTag(invalid, num, protowire.BytesType)
+		invalid = protowire.AppendBytes(invalid, typed)
+	}
+	_, err = chain.UnmarshalTxProto(invalid, consts.ActionRegistry, consts.AuthRegistry)
+	require.ErrorIs(err, chain.ErrInvalidProto)
+}
+
+func TestResultsProto(t *testing.T) {
+	require := require.New(t)
+
+	results := []*chain.Result{
+		{Status: chain.StatusSuccess, Consumed: chain.Dimensions{1, 2, 3, 4, 5}, Fee: 15},
+		{Output: []byte("error"), Consumed: chain.Dimensions{1, 0, 0, 2, 0}, Fee: 3},
+		{Status: chain.StatusInsufficientBalance, Output: []byte("invalid balance"), Consumed: chain.Dimensions{1, 0, 0, 2, 0}, Fee: 3},
+		{Status: chain.StatusCustom + 1, Consumed: chain.Dimensions{1, 0, 0, 2, 0}, Fee: 3},
+	}
+	parsed, err := chain.UnmarshalResultsProto(chain.MarshalResultsProto(results))
+	require.NoError(err)
+	require.Equal(results, parsed)
+
+	parsed, err = chain.UnmarshalResultsProto(nil)
+	require.NoError(err)
+	require.Empty(parsed)
+}
+
+func TestBlockProto(t *testing.T) {
+	require := require.New(t)
+
+	blk := &chain.StatefulBlock{
+		Prnt:        ids.GenerateTestID(),
+		Tmstmp:      fuzzTimestamp,
+		Hght:        10,
+		Txs:         []*chain.Transaction{signedTransfer(t, 1), signedTransfer(t, 2)},
+		StateRoot:   ids.GenerateTestID(),
+		WarpResults: 0,
+		BlobsRoot:   ids.GenerateTestID(),
+		Beneficiary: codec.CreateAddress(consts.ED25519ID, ids.GenerateTestID()),
+		Arrivals:    []int64{fuzzTimestamp - 2, fuzzTimestamp - 1},
+	}
+	expected, err := blk.Marshal()
+	require.NoError(err)
+	parsed, err := chain.UnmarshalBlock(expected, &parser{})
+	require.NoError(err)
+	require.Equal(blk.Arrivals, parsed.Arrivals)
+
+	raw, err := chain.MarshalBlockProto(blk)
+	require.NoError(err)
+	parsed, err = chain.UnmarshalBlockProto(raw, &parser{})
+	require.NoError(err)
+	require.Equal(blk.Arrivals, parsed.Arrivals)
+	actual, err := parsed.Marshal()
+	require.NoError(err)
+	require.Equal(expected, actual)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+func TestExecutionTraceDiff(t *testing.T) {
+	require := require.New(t)
+
+	blkID := ids.GenerateTestID()
+	newTrace := func(read []byte, write []byte, fee uint64) *chain.ExecutionTrace {
+		return &chain.ExecutionTrace{
+			Block: blkID,
+			Order: []int{0},
+			Txs: []*chain.TxTrace{{
+				ID:     ids.Empty,
+				Reads:  map[string]maybe.Maybe[[]byte]{"a": maybe.Some(read), "b": maybe.Nothing[[]byte]()},
+				Writes: map[string]maybe.Maybe[[]byte]{"a": maybe.Some(write)},
+				Result: &chain.Result{Status: chain.StatusSuccess, Fee: fee},
+			}},
+		}
+	}
+	parallel := newTrace([]byte{1}, []byte{2}, 10)
+	require.Empty(parallel.Diff(newTrace([]byte{1}, []byte{2}, 10)))
+
+	// Each divergence is reported
+	diffs := parallel.Diff(newTrace([]byte{0}, []byte{3}, 11))
+	require.Len(diffs, 3)
+	require.Contains(diffs[0], "read of 61 differs: 01 != 00")
+	require.Contains(diffs[1], "write of 61 differs: 02 != 03")
+	require.Contains(diffs[2], "result differs")
+
+	// Traces of different blocks can't be compared
+	other := newTrace([]byte{1}, []byte{2}, 10)
+	other.Block = ids.GenerateTestID()
+	require.Len(parallel.Diff(other), 1)
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+func TestHeightExpiryEncoding(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	base := &chain.Base{Height: 100, ChainID: ids.GenerateTestID(), MaxFee: 1_000}
+	tx := chain.NewTx(base, nil, &actions.Transfer{To: auth.NewED25519Address(ed25519.PublicKey{1}), Value: 1})
+	tx, err = tx.Sign(auth.NewED25519Factory(priv), consts.ActionRegistry, consts.AuthRegistry)
+	require.NoError(err)
+	require.Equal(chain.HeightBaseSize, tx.Base.Size())
+	require.Equal(hconsts.MaxInt64, tx.Expiry())
+	require.Equal(int64(0), chain.ExpiryTimestamp(tx))
+	require.Equal(int64(100), chain.ExpiryHeight(tx))
+
+	// The height is preserved by every encoding
+	for _, e := range chain.Encodings() {
+		raw, err := chain.MarshalTxEncoding(e, tx)
+		require.NoError(err)
+		parsed, err := chain.UnmarshalTxEncoding(e, raw, consts.ActionRegistry, consts.AuthRegistry)
+		require.NoError(err)
+		require.Equal(tx.ID(), parsed.ID())
+		require.Equal(uint64(100), parsed.Base.Height)
+	}
+}
+
+func TestSequentialNonceEncoding(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	base := &chain.Base{Nonce: 7, ChainID: ids.GenerateTestID(), MaxFee: 1_000}
+	tx := chain.NewTx(base, nil, &actions.Transfer{To: auth.NewED25519Address(ed25519.PublicKey{1}), Value: 1})
+	tx, err = tx.Sign(auth.NewED25519Factory(priv), consts.ActionRegistry, consts.AuthRegistry)
+	require.NoError(err)
+	require.Equal(chain.NonceBaseSize, tx.Base.Size())
+	require.Equal(hconsts.MaxInt64, tx.Expiry())
+
+	// The nonce is preserved by every encoding
+	for _, e := range chain.Encodings() {
+		raw, err := chain.MarshalTxEncoding(e, tx)
+		require.NoError(err)
+		parsed, err := chain.UnmarshalTxEncoding(e, raw, consts.ActionRegistry, consts.AuthRegistry)
+		require.NoError(err)
+		require.Equal(tx.ID(), parsed.ID())
+		require.Equal(uint64(7), parsed.Base.Nonce)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+
+	_ "github.com/ava-labs/hypersdk/examples/morpheusvm/registry"
+)
+
+const fuzzTimestamp = 1_000_000
+
+var _ chain.Parser = (*parser)(nil)
+
+type parser struct {
+	rules chain.Rules
+}
+
+func (p *parser) Rules(int64) chain.Rules {
+	return p.rules
+}
+
+func (*parser) Registry() (chain.ActionRegistry, chain.AuthRegistry) {
+	return consts.ActionRegistry, consts.AuthRegistry
+}
+
+// conserveBalances ensures that the sum of all balances only decreases by
+// the fee paid.
+func conserveBalances(balanceKeys []string) chaintest.Invariant {
+	sum := func(state map[string][]byte) uint64 {
+		var total uint64
+		for _, k := range balanceKeys {
+			if v, ok := state[k]; ok {
+				total += binary.BigEndian.Uint64(v)
+			}
+		}
+		return total
+	}
+	return func(_ *chain.Transaction, result *chain.Result, before, after map[string][]byte) error {
+		if sum(before) != sum(after)+result.Fee {
+			return fmt.Errorf("balances not conserved (before=%d after=%d fee=%d)", sum(before), sum(after), result.Fee)
+		}
+		return nil
+	}
+}
+
+func FuzzTx(f *testing.F) {
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(f, err)
+	actor := auth.NewED25519Address(priv.PublicKey())
+	recipient := auth.NewED25519Address(ed25519.PublicKey{1})
+	actorKey := string(storage.BalanceKey(actor))
+
+	chainID := ids.GenerateTestID()
+	gen := genesis.Default()
+	p := &parser{rules: gen.Rules(fuzzTimestamp, 1, chainID)}
+	fuzzer := &chaintest.TxFuzzer{
+		Parser:       p,
+		StateManager: &storage.StateManager{},
+		State: map[string][]byte{
+			actorKey: binary.BigEndian.AppendUint64(nil, 1_000_000),
+		},
+		Timestamp:  fuzzTimestamp,
+		UnitPrices: chain.Dimensions{1, 1, 1, 1, 1},
+		Invariants: []chaintest.Invariant{
+			conserveBalances([]string{actorKey, string(storage.BalanceKey(recipient))}),
+		},
+	}
+
+	// Seed with valid transfers
+	for _, value := range []uint64{1, 1_000, 999_999} {
+		tx := chain.NewTx(
+			&chain.Base{
+				Timestamp: fuzzTimestamp + gen.ValidityWindow/2,
+				ChainID:   chainID,
+				MaxFee:    10_000,
+			},
+			nil,
+			&actions.Transfer{To: recipient, Value: value},
+		)
+		tx, err := tx.Sign(auth.NewED25519Factory(priv), consts.ActionRegistry, consts.AuthRegistry)
+		require.NoError(f, err)
+		f.Add(tx.Bytes())
+	}
+	f.Fuzz(fuzzer.Fuzz)
+}
@@ -16,6 +16,9 @@ import (
 
 // Setup types
 func init() {
+	// Must be enabled before any fees are computed
+	chain.EnableBlobs()
+
 	consts.ActionRegistry = codec.NewTypeParser[chain.Action, *warp.Message]()
 	consts.AuthRegistry = codec.NewTypeParser[chain.Auth, *warp.Message]()
 
@@ -23,11 +26,43 @@ func init() {
 	errs.Add(
 		// When registering new actions, ALWAYS make sure to append at the end.
 		consts.ActionRegistry.Register((&actions.Transfer{}).GetTypeID(), actions.UnmarshalTransfer, false),
+		consts.ActionRegistry.Register((&actions.RegisterAggregate{}).GetTypeID(), actions.UnmarshalRegisterAggregate, false),
+		consts.ActionRegistry.Register((&actions.RotateKey{}).GetTypeID(), actions.UnmarshalRotateKey, false),
+		consts.ActionRegistry.Register((&actions.SetGuardians{}).GetTypeID(), actions.UnmarshalSetGuardians, false),
+		consts.ActionRegistry.Register((&actions.InitiateRecovery{}).GetTypeID(), actions.UnmarshalInitiateRecovery, false),
+		consts.ActionRegistry.Register((&actions.FinalizeRecovery{}).GetTypeID(), actions.UnmarshalFinalizeRecovery, false),
+		consts.ActionRegistry.Register((&actions.Cancel{}).GetTypeID(), actions.UnmarshalCancel, false),
+		consts.ActionRegistry.Register((&actions.UpdateRules{}).GetTypeID(), actions.UnmarshalUpdateRules, false),
+		consts.ActionRegistry.Register((&actions.RevokeSession{}).GetTypeID(), actions.UnmarshalRevokeSession, false),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
 		consts.AuthRegistry.Register((&auth.SECP256R1{}).GetTypeID(), auth.UnmarshalSECP256R1, false),
 		consts.AuthRegistry.Register((&auth.BLS{}).GetTypeID(), auth.UnmarshalBLS, false),
+		consts.AuthRegistry.Register((&auth.Session{}).GetTypeID(), auth.UnmarshalSession, false),
+		consts.AuthRegistry.Register((&auth.BLSAggregate{}).GetTypeID(), auth.UnmarshalBLSAggregate, false),
+		consts.AuthRegistry.Register((&auth.Account{}).GetTypeID(), auth.UnmarshalAccount, false),
+		consts.AuthRegistry.Register((&auth.WebAuthn{}).GetTypeID(), auth.UnmarshalWebAuthn, false),
+	)
+	errs.Add(
+		// Names identify types in the JSON encoding of transactions (unlike type
+		// IDs, they are not part of consensus).
+		consts.ActionRegistry.SetName((&actions.Transfer{}).GetTypeID(), "transfer"),
+		consts.ActionRegistry.SetName((&actions.RegisterAggregate{}).GetTypeID(), "registerAggregate"),
+		consts.ActionRegistry.SetName((&actions.RotateKey{}).GetTypeID(), "rotateKey"),
+		consts.ActionRegistry.SetName((&actions.SetGuardians{}).GetTypeID(), "setGuardians"),
+		consts.ActionRegistry.SetName((&actions.InitiateRecovery{}).GetTypeID(), "initiateRecovery"),
+		consts.ActionRegistry.SetName((&actions.FinalizeRecovery{}).GetTypeID(), "finalizeRecovery"),
+		consts.ActionRegistry.SetName((&actions.Cancel{}).GetTypeID(), "cancel"),
+		consts.ActionRegistry.SetName((&actions.UpdateRules{}).GetTypeID(), "updateRules"),
+		consts.ActionRegistry.SetName((&actions.RevokeSession{}).GetTypeID(), "revokeSession"),
+		consts.AuthRegistry.SetName((&auth.ED25519{}).GetTypeID(), "ed25519"),
+		consts.AuthRegistry.SetName((&auth.SECP256R1{}).GetTypeID(), "secp256r1"),
+		consts.AuthRegistry.SetName((&auth.BLS{}).GetTypeID(), "bls"),
+		consts.AuthRegistry.SetName((&auth.Session{}).GetTypeID(), "session"),
+		consts.AuthRegistry.SetName((&auth.BLSAggregate{}).GetTypeID(), "blsAggregate"),
+		consts.AuthRegistry.SetName((&auth.Account{}).GetTypeID(), "account"),
+		consts.AuthRegistry.SetName((&auth.WebAuthn{}).GetTypeID(), "webAuthn"),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -0,0 +1,76 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+func TestStatusOf(t *testing.T) {
+	require := require.New(t)
+
+	errBalance := chain.NewStatusError(chain.StatusInsufficientBalance, errors.New("invalid balance"))
+	for err, status := range map[error]chain.Status{
+		errBalance:                             chain.StatusInsufficientBalance,
+		fmt.Errorf("%w: transfer", errBalance): chain.StatusInsufficientBalance,
+		tstate.ErrKeyNotSpecified:              chain.StatusKeyNotDeclared,
+		smath.ErrUnderflow:                     chain.StatusOverflow,
+		errors.New("invalid"):                  chain.StatusFailed,
+	} {
+		require.Equal(status, chain.StatusOf(err), err.Error())
+
+		// Failures keep the message of their error as output
+		failed, output, ok := chain.AsFailure(chain.Fail(err))
+		require.True(ok)
+		require.Equal(status, failed)
+		require.Equal([]byte(err.Error()), output)
+		require.ErrorIs(chain.Fail(err), err)
+	}
+	require.Equal(errBalance, chain.Fail(errBalance))
+
+	_, _, ok := chain.AsFailure(errBalance)
+	require.True(ok)
+	_, _, ok = chain.AsFailure(fmt.Errorf("%w: transfer", errBalance))
+	require.False(ok)
+
+	require.Equal("insufficient balance", chain.StatusInsufficientBalance.String())
+	require.Equal("custom(2)", (chain.StatusCustom + 2).String())
+}
+
+func TestResultStatus(t *testing.T) {
+	require := require.New(t)
+
+	results := []*chain.Result{
+		{Status: chain.StatusSuccess},
+		{Status: chain.StatusFailed, Output: []byte("invalid")},
+		{Status: chain.StatusAssetMissing, Output: []byte("asset missing")},
+		{Status: chain.StatusCustom},
+	}
+	b, err := chain.MarshalResults(results)
+	require.NoError(err)
+	parsed, err := chain.UnmarshalResults(b)
+	require.NoError(err)
+	require.Equal(results, parsed)
+	require.True(parsed[0].Success())
+	require.False(parsed[2].Success())
+
+	// Results encoded before statuses were introduced only set success
+	legacy := protowire.AppendTag(nil, 1, protowire.VarintType)
+	legacy = protowire.AppendVarint(legacy, 1)
+	raw := protowire.AppendTag(nil, 1, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, legacy)
+	parsed, err = chain.UnmarshalResultsProto(raw)
+	require.NoError(err)
+	require.Len(parsed, 1)
+	require.Equal(chain.StatusSuccess, parsed[0].Status)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+)
+
+var (
+	_ chain.Versioned = (*transferV0)(nil)
+	_ chain.Versioned = (*transferV1)(nil)
+)
+
+// transferV0 is [actions.Transfer] registered as a versioned type.
+type transferV0 struct {
+	actions.Transfer
+}
+
+func (*transferV0) GetVersion() uint8 {
+	return 0
+}
+
+func unmarshalTransferV0(p *codec.Packer, msg *warp.Message) (chain.Action, error) {
+	transfer, err := actions.UnmarshalTransfer(p, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &transferV0{*transfer.(*actions.Transfer)}, nil
+}
+
+// transferV1 is a second version of [transferV0] that encodes [Value]
+// before [To].
+type transferV1 struct {
+	actions.Transfer
+}
+
+func (*transferV1) GetVersion() uint8 {
+	return 1
+}
+
+func (t *transferV1) Marshal(p *codec.Packer) {
+	p.PackUint64(t.Value)
+	p.PackAddress(t.To)
+}
+
+func unmarshalTransferV1(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var transfer transferV1
+	transfer.Value = p.UnpackUint64(true)
+	p.UnpackAddress(&transfer.To)
+	return &transfer, p.Err()
+}
+
+func versionedRegistry(t *testing.T) chain.ActionRegistry {
+	registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	id := (&actions.Transfer{}).GetTypeID()
+	require.NoError(t, registry.RegisterVersion(id, 0, unmarshalTransferV0, false))
+	require.NoError(t, registry.RegisterVersion(id, 1, unmarshalTransferV1, false))
+	return registry
+}
+
+func TestVersionedAction(t *testing.T) {
+	require := require.New(t)
+
+	registry := versionedRegistry(t)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	to := auth.NewED25519Address(ed25519.PublicKey{1})
+	base := &chain.Base{Timestamp: fuzzTimestamp, ChainID: ids.GenerateTestID(), MaxFee: 1_000}
+	tx, err := chain.NewTx(base, nil, &transferV1{actions.Transfer{To: to, Value: 10}}).Sign(
+		auth.NewED25519Factory(priv),
+		registry,
+		consts.AuthRegistry,
+	)
+	require.NoError(err)
+
+	// The version follows the type ID
+	digest, err := tx.Digest()
+	require.NoError(err)
+	require.Equal([]byte{consts.TransferID, 1}, digest[chain.BaseSize+4:][:2])
+	parsed, ok := tx.Action.(*transferV1)
+	require.True(ok)
+	require.Equal(to, parsed.To)
+	require.Equal(uint64(10), parsed.Value)
+
+	for _, e := range chain.Encodings() {
+		raw, err := chain.MarshalTxEncoding(e, tx)
+		require.NoError(err)
+		decoded, err := chain.UnmarshalTxEncoding(e, raw, registry, consts.AuthRegistry)
+		require.NoError(err, e)
+		require.Equal(tx.ID(), decoded.ID(), e)
+	}
+
+	// Versioned types can't be parsed by a registry that doesn't know them
+	_, err = chain.UnmarshalTxEncoding(chain.BinaryEncoding, tx.Bytes(), consts.ActionRegistry, consts.AuthRegistry)
+	require.Error(err)
+	v0Registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	require.NoError(v0Registry.RegisterVersion(consts.TransferID, 0, unmarshalTransferV0, false))
+	_, err = chain.UnmarshalTxEncoding(chain.BinaryEncoding, tx.Bytes(), v0Registry, consts.AuthRegistry)
+	require.ErrorIs(err, chain.ErrInvalidObject)
+}
+
+func TestDeprecatedAction(t *testing.T) {
+	require := require.New(t)
+
+	registry := versionedRegistry(t)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	factory := auth.NewED25519Factory(priv)
+	base := &chain.Base{Timestamp: fuzzTimestamp, ChainID: ids.GenerateTestID(), MaxFee: 1_000}
+	to := auth.NewED25519Address(ed25519.PublicKey{1})
+	v0, err := chain.NewTx(base, nil, &transferV0{actions.Transfer{To: to, Value: 10}}).Sign(factory, registry, consts.AuthRegistry)
+	require.NoError(err)
+	v1, err := chain.NewTx(base, nil, &transferV1{actions.Transfer{To: to, Value: 10}}).Sign(factory, registry, consts.AuthRegistry)
+	require.NoError(err)
+
+	gen := genesis.Default()
+	gen.Deprecations = []*genesis.Deprecation{
+		{TypeID: consts.TransferID, Version: 1, Height: 20},
+		{TypeID: consts.TransferID, Version: 1, Height: 10},
+		{Auth: true, TypeID: consts.ED25519ID, Version: 1, Height: 1},
+	}
+	r := gen.Rules(fuzzTimestamp, 1, base.ChainID)
+	height, ok := r.GetActionDeprecationHeight(consts.TransferID, 1)
+	require.True(ok)
+	require.Equal(uint64(10), height)
+	_, ok = r.GetActionDeprecationHeight(consts.TransferID, 0)
+	require.False(ok)
+
+	require.NoError(v1.CheckDeprecated(r, 9))
+	require.ErrorIs(v1.CheckDeprecated(r, 10), chain.ErrActionDeprecated)
+	require.NoError(v0.CheckDeprecated(r, 10))
+}
@@ -10,12 +10,19 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 )
 
 type Controller interface {
 	Genesis() *genesis.Genesis
 	Tracer() trace.Tracer
-	GetTransaction(context.Context, ids.ID) (bool, int64, bool, chain.Dimensions, uint64, error)
+	GetTransaction(context.Context, ids.ID) (bool, int64, chain.Status, chain.Dimensions, uint64, error)
 	GetBalanceFromState(context.Context, codec.Address) (uint64, error)
+	GetAggregateFromState(context.Context, codec.Address) (uint16, bool, error)
+	GetAccountKeyFromState(context.Context, codec.Address) (ed25519.PublicKey, bool, error)
+	GetGuardiansFromState(context.Context, codec.Address) (*storage.Guardians, bool, error)
+	GetRecoveryFromState(context.Context, codec.Address) (*storage.Recovery, bool, error)
+	GetSessionFromState(context.Context, codec.Address, ids.ID) (uint64, bool, error)
 }
@@ -3,13 +3,17 @@
 
 package rpc
 
+//go:generate go run github.com/ava-labs/hypersdk/cmd/hypersdk-cli gen-client -registry ../registry/registry.go -actions ../actions
+
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 	_ "github.com/ava-labs/hypersdk/examples/morpheusvm/registry" // ensure registry populated
@@ -35,6 +39,29 @@ func NewJSONRPCClient(uri string, networkID uint32, chainID ids.ID) *JSONRPCClie
 	return &JSONRPCClient{req, networkID, chainID, nil}
 }
 
+// NewJSONRPCClientWithConfig returns a client that sends each request to the
+// first healthy node of [uris] and retries requests as configured by
+// [config].
+func NewJSONRPCClientWithConfig(uris []string, networkID uint32, chainID ids.ID, config *requester.Config) *JSONRPCClient {
+	req := requester.NewWithConfig(
+		rpc.EndpointURIs(uris, JSONRPCEndpoint),
+		consts.Name,
+		config.WithDefaultHealthMethod("genesis"),
+	)
+	return &JSONRPCClient{req, networkID, chainID, nil}
+}
+
+// Endpoints returns the health of each node [cli] sends requests to.
+func (cli *JSONRPCClient) Endpoints() []*requester.EndpointStatus {
+	return cli.requester.Endpoints()
+}
+
+// MonitorHealth checks each node [cli] sends requests to every [interval]
+// until [ctx] is done.
+func (cli *JSONRPCClient) MonitorHealth(ctx context.Context, interval time.Duration) error {
+	return cli.requester.MonitorHealth(ctx, interval)
+}
+
 func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error) {
 	if cli.g != nil {
 		return cli.g, nil
@@ -54,7 +81,9 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error)
 	return resp.Genesis, nil
 }
 
-func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, bool, int64, uint64, error) {
+// Tx returns whether [id] was found and, if it was, its status, timestamp and
+// fee.
+func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, chain.Status, int64, uint64, error) {
 	resp := new(TxReply)
 	err := cli.requester.SendRequest(
 		ctx,
@@ -66,11 +95,11 @@ func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, bool, int64,
 	// We use string parsing here because the JSON-RPC library we use may not
 	// allows us to perform errors.Is.
 	case err != nil && strings.Contains(err.Error(), ErrTxNotFound.Error()):
-		return false, false, -1, 0, nil
+		return false, chain.StatusFailed, -1, 0, nil
 	case err != nil:
-		return false, false, -1, 0, err
+		return false, chain.StatusFailed, -1, 0, err
 	}
-	return true, resp.Success, resp.Timestamp, resp.Fee, nil
+	return true, resp.Status, resp.Timestamp, resp.Fee, nil
 }
 
 func (cli *JSONRPCClient) Balance(ctx context.Context, addr string) (uint64, error) {
@@ -86,6 +115,67 @@ func (cli *JSONRPCClient) Balance(ctx context.Context, addr string) (uint64, err
 	return resp.Amount, err
 }
 
+// Aggregate returns the number of signers of the BLS aggregate public key
+// registered for [addr] and whether one is registered at all.
+func (cli *JSONRPCClient) Aggregate(ctx context.Context, addr string) (uint16, bool, error) {
+	resp := new(AggregateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"aggregate",
+		&AggregateArgs{
+			Address: addr,
+		},
+		resp,
+	)
+	return resp.Signers, resp.Registered, err
+}
+
+// Account returns the key [addr] was rotated to. If the key of [addr] was
+// never rotated, it is authorized by the key it was derived from.
+func (cli *JSONRPCClient) Account(ctx context.Context, addr string) (ed25519.PublicKey, bool, error) {
+	resp := new(AccountReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"account",
+		&AccountArgs{
+			Address: addr,
+		},
+		resp,
+	)
+	return resp.Key, resp.Rotated, err
+}
+
+// Recovery returns the guardians of [addr] and any recovery they have
+// approved.
+func (cli *JSONRPCClient) Recovery(ctx context.Context, addr string) (*RecoveryReply, error) {
+	resp := new(RecoveryReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"recovery",
+		&RecoveryArgs{
+			Address: addr,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// Session returns the amount spent by transactions authorized by
+// [delegation] from the session account [addr] and whether it was revoked.
+func (cli *JSONRPCClient) Session(ctx context.Context, addr string, delegation ids.ID) (uint64, bool, error) {
+	resp := new(SessionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"session",
+		&SessionArgs{
+			Address:    addr,
+			Delegation: delegation,
+		},
+		resp,
+	)
+	return resp.Spent, resp.Revoked, err
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr string,
@@ -112,11 +202,11 @@ func (cli *JSONRPCClient) WaitForTransaction(ctx context.Context, txID ids.ID) (
 	var success bool
 	var fee uint64
 	if err := rpc.Wait(ctx, func(ctx context.Context) (bool, error) {
-		found, isuccess, _, ifee, err := cli.Tx(ctx, txID)
+		found, status, _, ifee, err := cli.Tx(ctx, txID)
 		if err != nil {
 			return false, err
 		}
-		success = isuccess
+		success = status == chain.StatusSuccess
 		fee = ifee
 		return found, nil
 	}); err != nil {
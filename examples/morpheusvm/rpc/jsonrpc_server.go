@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 )
@@ -38,6 +39,7 @@ type TxArgs struct {
 type TxReply struct {
 	Timestamp int64            `json:"timestamp"`
 	Success   bool             `json:"success"`
+	Status    chain.Status     `json:"status"`
 	Units     chain.Dimensions `json:"units"`
 	Fee       uint64           `json:"fee"`
 }
@@ -46,7 +48,7 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Tx")
 	defer span.End()
 
-	found, t, success, units, fee, err := j.c.GetTransaction(ctx, args.TxID)
+	found, t, status, units, fee, err := j.c.GetTransaction(ctx, args.TxID)
 	if err != nil {
 		return err
 	}
@@ -54,7 +56,8 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 		return ErrTxNotFound
 	}
 	reply.Timestamp = t
-	reply.Success = success
+	reply.Success = status == chain.StatusSuccess
+	reply.Status = status
 	reply.Units = units
 	reply.Fee = fee
 	return nil
@@ -83,3 +86,132 @@ func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *Bal
 	reply.Amount = balance
 	return err
 }
+
+type AggregateArgs struct {
+	Address string `json:"address"`
+}
+
+type AggregateReply struct {
+	Registered bool   `json:"registered"`
+	Signers    uint16 `json:"signers"`
+}
+
+func (j *JSONRPCServer) Aggregate(req *http.Request, args *AggregateArgs, reply *AggregateReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Aggregate")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	signers, registered, err := j.c.GetAggregateFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	reply.Registered = registered
+	reply.Signers = signers
+	return nil
+}
+
+type AccountArgs struct {
+	Address string `json:"address"`
+}
+
+type AccountReply struct {
+	Rotated bool              `json:"rotated"`
+	Key     ed25519.PublicKey `json:"key"`
+}
+
+func (j *JSONRPCServer) Account(req *http.Request, args *AccountArgs, reply *AccountReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Account")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	key, rotated, err := j.c.GetAccountKeyFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	reply.Rotated = rotated
+	reply.Key = key
+	return nil
+}
+
+type RecoveryArgs struct {
+	Address string `json:"address"`
+}
+
+type RecoveryReply struct {
+	Guardians []string `json:"guardians"`
+	Threshold uint8    `json:"threshold"`
+	Delay     int64    `json:"delay"`
+
+	// Pending is set if guardians have approved a new key that has not been
+	// applied yet.
+	Pending   bool              `json:"pending"`
+	NewKey    ed25519.PublicKey `json:"newKey"`
+	Approvals uint8             `json:"approvals"`
+	ReadyAt   int64             `json:"readyAt"`
+}
+
+func (j *JSONRPCServer) Recovery(req *http.Request, args *RecoveryArgs, reply *RecoveryReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Recovery")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	guardians, exists, err := j.c.GetGuardiansFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if exists {
+		reply.Guardians = make([]string, len(guardians.Addresses))
+		for i, guardian := range guardians.Addresses {
+			reply.Guardians[i] = codec.MustAddressBech32(consts.HRP, guardian)
+		}
+		reply.Threshold = guardians.Threshold
+		reply.Delay = guardians.Delay
+	}
+	recovery, pending, err := j.c.GetRecoveryFromState(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if pending {
+		reply.Pending = true
+		reply.NewKey = recovery.NewKey
+		reply.Approvals = recovery.Approvals
+		reply.ReadyAt = recovery.ReadyAt
+	}
+	return nil
+}
+
+type SessionArgs struct {
+	Address    string `json:"address"` // of the session account
+	Delegation ids.ID `json:"delegation"`
+}
+
+type SessionReply struct {
+	Spent   uint64 `json:"spent"`
+	Revoked bool   `json:"revoked"`
+}
+
+func (j *JSONRPCServer) Session(req *http.Request, args *SessionArgs, reply *SessionReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Session")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	spent, revoked, err := j.c.GetSessionFromState(ctx, addr, args.Delegation)
+	if err != nil {
+		return err
+	}
+	reply.Spent = spent
+	reply.Revoked = revoked
+	return nil
+}
@@ -35,12 +35,12 @@ if [[ ${MODE} != "run" ]]; then
   UNLIMITED_USAGE=true
 fi
 
-WINDOW_TARGET_UNITS="40000000,450000,450000,450000,450000"
-MAX_BLOCK_UNITS="1800000,15000,15000,2500,15000"
+WINDOW_TARGET_UNITS="40000000,450000,450000,450000,450000,1048576"
+MAX_BLOCK_UNITS="1800000,15000,15000,2500,15000,262144"
 if ${UNLIMITED_USAGE}; then
-  WINDOW_TARGET_UNITS="${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64}"
+  WINDOW_TARGET_UNITS="${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64}"
   # If we don't limit the block size, AvalancheGo will reject the block.
-  MAX_BLOCK_UNITS="1800000,${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64}"
+  MAX_BLOCK_UNITS="1800000,${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},${MAX_UINT64},262144"
 fi
 
 echo "Running with:"
@@ -0,0 +1,35 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/rpc"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+// RegisterStateQueries registers the state served by the queryState RPC.
+//
+// "balance" is keyed by address.
+func RegisterStateQueries(r *rpc.StateRegistry) error {
+	return r.Register("balance", &rpc.StateQuery{
+		Key: func(key string) ([]byte, error) {
+			addr, err := codec.ParseAddressBech32(mconsts.HRP, key)
+			if err != nil {
+				return nil, err
+			}
+			return BalanceKey(addr), nil
+		},
+		Decode: func(v []byte) (any, error) {
+			if len(v) != consts.Uint64Len {
+				return nil, ErrInvalidBalance
+			}
+			return binary.BigEndian.Uint64(v), nil
+		},
+	})
+}
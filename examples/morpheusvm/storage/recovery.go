@@ -0,0 +1,195 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// MaxGuardians is the most guardians an account can register. Approvals of a
+// recovery are tracked as a bitmap of guardian indices, so this can't exceed
+// 8.
+const MaxGuardians = 8
+
+const (
+	guardiansHeaderLen = consts.ByteLen + consts.Uint64Len + consts.ByteLen
+	recoveryLen        = ed25519.PublicKeyLen + consts.ByteLen + consts.Uint64Len
+)
+
+// Guardians can recover an account by agreeing on a new key for it.
+type Guardians struct {
+	Addresses []codec.Address
+
+	// Threshold is the number of [Addresses] that must approve a recovery.
+	Threshold uint8
+
+	// Delay is the time (in ms) between reaching [Threshold] approvals and
+	// being able to finalize a recovery. The owner of the account can cancel
+	// the recovery during this period by registering guardians again.
+	Delay int64
+}
+
+// Index returns the index of [addr] in [Addresses], if it is a guardian.
+func (g *Guardians) Index(addr codec.Address) (int, bool) {
+	for i, guardian := range g.Addresses {
+		if guardian == addr {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Recovery is a pending change of the key of an account, approved by some of
+// its guardians.
+type Recovery struct {
+	NewKey ed25519.PublicKey
+
+	// Approvals is a bitmap of the indices of the guardians that approved
+	// [NewKey].
+	Approvals uint8
+
+	// ReadyAt is the time the recovery can be finalized. It is 0 until the
+	// guardian threshold is reached.
+	ReadyAt int64
+}
+
+// [guardiansPrefix] + [address]
+func GuardiansKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = guardiansPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], GuardiansChunks)
+	return
+}
+
+func SetGuardians(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	guardians *Guardians,
+) error {
+	v := make([]byte, guardiansHeaderLen+len(guardians.Addresses)*codec.AddressLen)
+	v[0] = guardians.Threshold
+	binary.BigEndian.PutUint64(v[consts.ByteLen:], uint64(guardians.Delay))
+	v[consts.ByteLen+consts.Uint64Len] = uint8(len(guardians.Addresses))
+	for i, guardian := range guardians.Addresses {
+		copy(v[guardiansHeaderLen+i*codec.AddressLen:], guardian[:])
+	}
+	return mu.Insert(ctx, GuardiansKey(addr), v)
+}
+
+func DeleteGuardians(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+) error {
+	return mu.Remove(ctx, GuardiansKey(addr))
+}
+
+func GetGuardians(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (*Guardians, bool, error) {
+	return innerGetGuardians(im.GetValue(ctx, GuardiansKey(addr)))
+}
+
+// Used to serve RPC queries
+func GetGuardiansFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (*Guardians, bool, error) {
+	values, errs := f(ctx, [][]byte{GuardiansKey(addr)})
+	return innerGetGuardians(values[0], errs[0])
+}
+
+func innerGetGuardians(v []byte, err error) (*Guardians, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	guardians := &Guardians{
+		Threshold: v[0],
+		Delay:     int64(binary.BigEndian.Uint64(v[consts.ByteLen:])),
+		Addresses: make([]codec.Address, v[consts.ByteLen+consts.Uint64Len]),
+	}
+	for i := range guardians.Addresses {
+		copy(guardians.Addresses[i][:], v[guardiansHeaderLen+i*codec.AddressLen:])
+	}
+	return guardians, true, nil
+}
+
+// [recoveryPrefix] + [address]
+func RecoveryKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = recoveryPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], RecoveryChunks)
+	return
+}
+
+func SetRecovery(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	recovery *Recovery,
+) error {
+	v := make([]byte, recoveryLen)
+	copy(v, recovery.NewKey[:])
+	v[ed25519.PublicKeyLen] = recovery.Approvals
+	binary.BigEndian.PutUint64(v[ed25519.PublicKeyLen+consts.ByteLen:], uint64(recovery.ReadyAt))
+	return mu.Insert(ctx, RecoveryKey(addr), v)
+}
+
+func DeleteRecovery(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+) error {
+	return mu.Remove(ctx, RecoveryKey(addr))
+}
+
+func GetRecovery(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (*Recovery, bool, error) {
+	return innerGetRecovery(im.GetValue(ctx, RecoveryKey(addr)))
+}
+
+// Used to serve RPC queries
+func GetRecoveryFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (*Recovery, bool, error) {
+	values, errs := f(ctx, [][]byte{RecoveryKey(addr)})
+	return innerGetRecovery(values[0], errs[0])
+}
+
+func innerGetRecovery(v []byte, err error) (*Recovery, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	recovery := &Recovery{
+		Approvals: v[ed25519.PublicKeyLen],
+		ReadyAt:   int64(binary.BigEndian.Uint64(v[ed25519.PublicKeyLen+consts.ByteLen:])),
+	}
+	copy(recovery.NewKey[:], v)
+	return recovery, true, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const sessionLen = consts.Uint64Len + consts.BoolLen
+
+// [sessionPrefix] + [address] + [delegation]
+func SessionKey(addr codec.Address, delegation ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.IDLen+consts.Uint16Len)
+	k[0] = sessionPrefix
+	copy(k[1:], addr[:])
+	copy(k[1+codec.AddressLen:], delegation[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+consts.IDLen:], SessionChunks)
+	return
+}
+
+// SetSession records that transactions authorized by [delegation] have
+// [spent] from [addr] and whether it was [revoked].
+func SetSession(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	delegation ids.ID,
+	spent uint64,
+	revoked bool,
+) error {
+	v := make([]byte, sessionLen)
+	binary.BigEndian.PutUint64(v, spent)
+	if revoked {
+		v[consts.Uint64Len] = 0x1
+	}
+	return mu.Insert(ctx, SessionKey(addr, delegation), v)
+}
+
+// GetSession returns the amount spent from [addr] by transactions
+// authorized by [delegation] and whether it was revoked.
+func GetSession(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+	delegation ids.ID,
+) (uint64, bool, error) {
+	return innerGetSession(im.GetValue(ctx, SessionKey(addr, delegation)))
+}
+
+// Used to serve RPC queries
+func GetSessionFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+	delegation ids.ID,
+) (uint64, bool, error) {
+	values, errs := f(ctx, [][]byte{SessionKey(addr, delegation)})
+	return innerGetSession(values[0], errs[0])
+}
+
+func innerGetSession(v []byte, err error) (uint64, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), v[consts.Uint64Len] == 0x1, nil
+}
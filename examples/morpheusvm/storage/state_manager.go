@@ -12,7 +12,12 @@ import (
 	"github.com/ava-labs/hypersdk/state"
 )
 
-var _ (chain.StateManager) = (*StateManager)(nil)
+var (
+	_ (chain.StateManager)   = (*StateManager)(nil)
+	_ (chain.NonceManager)   = (*StateManager)(nil)
+	_ (chain.Canceller)      = (*StateManager)(nil)
+	_ (chain.RulesOverrider) = (*StateManager)(nil)
+)
 
 type StateManager struct{}
 
@@ -28,14 +33,30 @@ func (*StateManager) FeeKey() []byte {
 	return FeeKey()
 }
 
-func (*StateManager) IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) []byte {
-	return IncomingWarpKeyPrefix(sourceChainID, msgID)
+func (*StateManager) BurnKey() []byte {
+	return BurnKey()
+}
+
+func (*StateManager) IncomingWarpPrefix() []byte {
+	return IncomingWarpPrefix()
 }
 
 func (*StateManager) OutgoingWarpKeyPrefix(txID ids.ID) []byte {
 	return OutgoingWarpKeyPrefix(txID)
 }
 
+func (*StateManager) NonceKey(addr codec.Address) []byte {
+	return NonceKey(addr)
+}
+
+func (*StateManager) CancelKey(addr codec.Address) []byte {
+	return CancelKey(addr)
+}
+
+func (*StateManager) RulesOverridesKey() []byte {
+	return RulesOverridesKey()
+}
+
 func (*StateManager) SponsorStateKeys(addr codec.Address) []string {
 	return []string{
 		string(BalanceKey(addr)),
@@ -76,3 +97,12 @@ func (*StateManager) Refund(
 	// Don't create account if it doesn't exist (may have sent all funds).
 	return AddBalance(ctx, mu, addr, amount, false)
 }
+
+func (*StateManager) Credit(
+	ctx context.Context,
+	addr codec.Address,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	return AddBalance(ctx, mu, addr, amount, true)
+}
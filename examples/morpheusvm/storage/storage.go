@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/state"
 
 	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
@@ -36,6 +37,20 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x3/ (hypersdk-fee)
 // 0x4/ (hypersdk-incoming warp)
 // 0x5/ (hypersdk-outgoing warp)
+// 0x6/ (hypersdk-burn)
+// 0x7/ (bls aggregate)
+//   -> [address] => signers
+// 0x8/ (account)
+//   -> [address] => key
+// 0x9/ (guardians)
+//   -> [address] => guardians
+// 0xa/ (recovery)
+//   -> [address] => pending recovery
+// 0xb/ (hypersdk-nonce)
+// 0xc/ (hypersdk-cancel)
+// 0xd/ (hypersdk-rules overrides)
+// 0xe/ (session)
+//   -> [address|delegation] => spent|revoked
 
 const (
 	// metaDB
@@ -48,16 +63,33 @@ const (
 	feePrefix          = 0x3
 	incomingWarpPrefix = 0x4
 	outgoingWarpPrefix = 0x5
+	burnPrefix         = 0x6
+	aggregatePrefix    = 0x7
+	accountPrefix      = 0x8
+	guardiansPrefix    = 0x9
+	recoveryPrefix     = 0xa
+	noncePrefix        = 0xb
+	cancelPrefix       = 0xc
+	rulesPrefix        = 0xd
+	sessionPrefix      = 0xe
 )
 
-const BalanceChunks uint16 = 1
+const (
+	BalanceChunks   uint16 = 1
+	AggregateChunks uint16 = 1
+	AccountChunks   uint16 = 1
+	GuardiansChunks uint16 = 5
+	RecoveryChunks  uint16 = 1
+	SessionChunks   uint16 = 1
+)
 
 var (
-	failureByte  = byte(0x0)
-	successByte  = byte(0x1)
-	heightKey    = []byte{heightPrefix}
-	timestampKey = []byte{timestampPrefix}
-	feeKey       = []byte{feePrefix}
+	heightKey       = []byte{heightPrefix}
+	timestampKey    = []byte{timestampPrefix}
+	feeKey          = []byte{feePrefix}
+	burnKey         = []byte{burnPrefix}
+	incomingWarpKey = []byte{incomingWarpPrefix}
+	rulesKey        = []byte{rulesPrefix}
 )
 
 // [txPrefix] + [txID]
@@ -73,20 +105,16 @@ func StoreTransaction(
 	db database.KeyValueWriter,
 	id ids.ID,
 	t int64,
-	success bool,
+	status chain.Status,
 	units chain.Dimensions,
 	fee uint64,
 ) error {
 	k := TxKey(id)
-	v := make([]byte, consts.Uint64Len+1+chain.DimensionsLen+consts.Uint64Len)
+	v := make([]byte, consts.Uint64Len+1+chain.DimensionsLen()+consts.Uint64Len)
 	binary.BigEndian.PutUint64(v, uint64(t))
-	if success {
-		v[consts.Uint64Len] = successByte
-	} else {
-		v[consts.Uint64Len] = failureByte
-	}
+	v[consts.Uint64Len] = byte(status)
 	copy(v[consts.Uint64Len+1:], units.Bytes())
-	binary.BigEndian.PutUint64(v[consts.Uint64Len+1+chain.DimensionsLen:], fee)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len+1+chain.DimensionsLen():], fee)
 	return db.Put(k, v)
 }
 
@@ -94,26 +122,23 @@ func GetTransaction(
 	_ context.Context,
 	db database.KeyValueReader,
 	id ids.ID,
-) (bool, int64, bool, chain.Dimensions, uint64, error) {
+) (bool, int64, chain.Status, chain.Dimensions, uint64, error) {
 	k := TxKey(id)
 	v, err := db.Get(k)
 	if errors.Is(err, database.ErrNotFound) {
-		return false, 0, false, chain.Dimensions{}, 0, nil
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, nil
 	}
 	if err != nil {
-		return false, 0, false, chain.Dimensions{}, 0, err
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, err
 	}
 	t := int64(binary.BigEndian.Uint64(v))
-	success := true
-	if v[consts.Uint64Len] == failureByte {
-		success = false
-	}
-	d, err := chain.UnpackDimensions(v[consts.Uint64Len+1 : consts.Uint64Len+1+chain.DimensionsLen])
+	status := chain.Status(v[consts.Uint64Len])
+	d, err := chain.UnpackDimensions(v[consts.Uint64Len+1 : consts.Uint64Len+1+chain.DimensionsLen()])
 	if err != nil {
-		return false, 0, false, chain.Dimensions{}, 0, err
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, err
 	}
-	fee := binary.BigEndian.Uint64(v[consts.Uint64Len+1+chain.DimensionsLen:])
-	return true, t, success, d, fee, nil
+	fee := binary.BigEndian.Uint64(v[consts.Uint64Len+1+chain.DimensionsLen():])
+	return true, t, status, d, fee, nil
 }
 
 // [balancePrefix] + [address]
@@ -125,6 +150,15 @@ func BalanceKey(addr codec.Address) (k []byte) {
 	return
 }
 
+// ParseBalanceKey returns the address of a key created with [BalanceKey]. If
+// [k] is not a balance key, false is returned.
+func ParseBalanceKey(k []byte) (codec.Address, bool) {
+	if len(k) != 1+codec.AddressLen+consts.Uint16Len || k[0] != balancePrefix {
+		return codec.EmptyAddress, false
+	}
+	return codec.Address(k[1 : 1+codec.AddressLen]), true
+}
+
 // If locked is 0, then account does not exist
 func GetBalance(
 	ctx context.Context,
@@ -207,13 +241,13 @@ func AddBalance(
 	}
 	nbal, err := smath.Add64(bal, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusOverflow, fmt.Errorf(
 			"%w: could not add balance (bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
 			bal,
 			codec.MustAddressBech32(mconsts.HRP, addr),
 			amount,
-		)
+		))
 	}
 	return setBalance(ctx, mu, key, nbal)
 }
@@ -230,13 +264,13 @@ func SubBalance(
 	}
 	nbal, err := smath.Sub(bal, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusInsufficientBalance, fmt.Errorf(
 			"%w: could not subtract balance (bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
 			bal,
 			codec.MustAddressBech32(mconsts.HRP, addr),
 			amount,
-		)
+		))
 	}
 	if nbal == 0 {
 		// If there is no balance left, we should delete the record instead of
@@ -246,6 +280,115 @@ func SubBalance(
 	return setBalance(ctx, mu, key, nbal)
 }
 
+// [aggregatePrefix] + [address]
+func AggregateKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = aggregatePrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], AggregateChunks)
+	return
+}
+
+// SetAggregate registers the BLS aggregate public key of [addr] as the
+// aggregate of [signers] keys.
+func SetAggregate(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	signers uint16,
+) error {
+	return mu.Insert(ctx, AggregateKey(addr), binary.BigEndian.AppendUint16(nil, signers))
+}
+
+// GetAggregate returns the number of signers of the BLS aggregate public key
+// of [addr] and whether it is registered.
+func GetAggregate(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (uint16, bool, error) {
+	return innerGetAggregate(im.GetValue(ctx, AggregateKey(addr)))
+}
+
+// Used to serve RPC queries
+func GetAggregateFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (uint16, bool, error) {
+	values, errs := f(ctx, [][]byte{AggregateKey(addr)})
+	return innerGetAggregate(values[0], errs[0])
+}
+
+func innerGetAggregate(v []byte, err error) (uint16, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint16(v), true, nil
+}
+
+// [accountPrefix] + [address]
+func AccountKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = accountPrefix
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], AccountChunks)
+	return
+}
+
+// SetAccountKey sets the key that authorizes transactions of [addr].
+func SetAccountKey(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+	key ed25519.PublicKey,
+) error {
+	return mu.Insert(ctx, AccountKey(addr), key[:])
+}
+
+// DeleteAccountKey removes the record of [addr], so that it is authorized by
+// the key it was derived from.
+func DeleteAccountKey(
+	ctx context.Context,
+	mu state.Mutable,
+	addr codec.Address,
+) error {
+	return mu.Remove(ctx, AccountKey(addr))
+}
+
+// GetAccountKey returns the key that authorizes transactions of [addr], if
+// it has been rotated.
+func GetAccountKey(
+	ctx context.Context,
+	im state.Immutable,
+	addr codec.Address,
+) (ed25519.PublicKey, bool, error) {
+	return innerGetAccountKey(im.GetValue(ctx, AccountKey(addr)))
+}
+
+// Used to serve RPC queries
+func GetAccountKeyFromState(
+	ctx context.Context,
+	f ReadState,
+	addr codec.Address,
+) (ed25519.PublicKey, bool, error) {
+	values, errs := f(ctx, [][]byte{AccountKey(addr)})
+	return innerGetAccountKey(values[0], errs[0])
+}
+
+func innerGetAccountKey(v []byte, err error) (ed25519.PublicKey, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return ed25519.EmptyPublicKey, false, nil
+	}
+	if err != nil {
+		return ed25519.EmptyPublicKey, false, err
+	}
+	return ed25519.PublicKey(v), true, nil
+}
+
 func HeightKey() (k []byte) {
 	return heightKey
 }
@@ -258,12 +401,16 @@ func FeeKey() (k []byte) {
 	return feeKey
 }
 
-func IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) (k []byte) {
-	k = make([]byte, 1+consts.IDLen*2)
-	k[0] = incomingWarpPrefix
-	copy(k[1:], sourceChainID[:])
-	copy(k[1+consts.IDLen:], msgID[:])
-	return k
+func BurnKey() (k []byte) {
+	return burnKey
+}
+
+func RulesOverridesKey() (k []byte) {
+	return rulesKey
+}
+
+func IncomingWarpPrefix() (k []byte) {
+	return incomingWarpKey
 }
 
 func OutgoingWarpKeyPrefix(txID ids.ID) (k []byte) {
@@ -272,3 +419,17 @@ func OutgoingWarpKeyPrefix(txID ids.ID) (k []byte) {
 	copy(k[1:], txID[:])
 	return k
 }
+
+func NonceKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen)
+	k[0] = noncePrefix
+	copy(k[1:], addr[:])
+	return k
+}
+
+func CancelKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen)
+	k[0] = cancelPrefix
+	copy(k[1:], addr[:])
+	return k
+}
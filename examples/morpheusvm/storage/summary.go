@@ -0,0 +1,50 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// BalanceSummary is the number of accounts with a balance and the sum of
+// their balances.
+type BalanceSummary struct {
+	Accounts uint64 `json:"accounts"`
+	Total    uint64 `json:"total"`
+}
+
+// StateSummary summarizes the balances in state for audits (see
+// [vm.StateDigest]).
+type StateSummary struct {
+	balances BalanceSummary
+}
+
+func NewStateSummary() *StateSummary {
+	return &StateSummary{}
+}
+
+func (s *StateSummary) Add(k []byte, v []byte) error {
+	if _, ok := ParseBalanceKey(k); !ok {
+		return nil
+	}
+	if len(v) != consts.Uint64Len {
+		return fmt.Errorf("%w: key=%x", ErrInvalidBalance, k)
+	}
+	total, err := smath.Add64(s.balances.Total, binary.BigEndian.Uint64(v))
+	if err != nil {
+		return err
+	}
+	s.balances.Total = total
+	s.balances.Accounts++
+	return nil
+}
+
+func (s *StateSummary) Summaries() map[string]any {
+	return map[string]any{"balances": s.balances}
+}
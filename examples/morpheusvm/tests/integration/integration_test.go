@@ -225,7 +225,7 @@ var _ = ginkgo.BeforeSuite(func() {
 			genesisBytes,
 			nil,
 			[]byte(
-				`{"parallelism":3, "testMode":true, "logLevel":"debug"}`,
+				`{"parallelism":3, "testMode":true, "logLevel":"debug", "executionTrace":true, "streamingMempool":true}`,
 			),
 			toEngine,
 			nil,
@@ -356,6 +356,16 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			gomega.Ω(err).To(gomega.Not(gomega.BeNil()))
 		})
 
+		ginkgo.By("skip duplicate (raw)", func() {
+			for _, format := range []string{rpc.HexFormat, rpc.Base64Format} {
+				raw, err := rpc.EncodeRawTx(transferTxRoot.Bytes(), format)
+				gomega.Ω(err).Should(gomega.BeNil())
+				txID, err := instances[0].cli.SubmitRawTx(context.Background(), raw, "")
+				gomega.Ω(txID).To(gomega.Equal(transferTxRoot.ID()))
+				gomega.Ω(err).To(gomega.Not(gomega.BeNil()))
+			}
+		})
+
 		ginkgo.By("send gossip from node 0 to 1", func() {
 			err := instances[0].vm.Gossiper().Force(context.TODO())
 			gomega.Ω(err).Should(gomega.BeNil())
@@ -425,7 +435,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 
 			results := blk.(*chain.StatelessBlock).Results()
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 			gomega.Ω(results[0].Output).Should(gomega.BeNil())
 
 			// Unit explanation
@@ -473,7 +483,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[1])
 			results := accept(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 			// Unit explanation
 			//
@@ -566,7 +576,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			// read: 2 keys reads, 1 chunk each
 			// allocate: 0 key created
 			// write: 2 key modified
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 			transferTxConsumed := chain.Dimensions{191, 7, 14, 0, 26}
 			gomega.Ω(results[0].Consumed).Should(gomega.Equal(transferTxConsumed))
 			// Fee explanation
@@ -581,7 +591,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			// read: 2 keys reads (previously read), 1 chunk each
 			// allocate: 0 key created
 			// write: 2 keys modified
-			gomega.Ω(results[1].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[1].Success()).Should(gomega.BeTrue())
 			transferTxConsumed = chain.Dimensions{191, 7, 14, 0, 26}
 			gomega.Ω(results[1].Consumed).Should(gomega.Equal(transferTxConsumed))
 			// Fee explanation
@@ -596,7 +606,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			// read: 1 keys read (0 chunk), 1 key read (1 chunk)
 			// allocate: 1 key created (1 chunk)
 			// write: 2 key modified (1 chunk), both previously modified
-			gomega.Ω(results[2].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[2].Success()).Should(gomega.BeTrue())
 			transferTxConsumed = chain.Dimensions{191, 7, 12, 25, 26}
 			gomega.Ω(results[2].Consumed).Should(gomega.Equal(transferTxConsumed))
 			// Fee explanation
@@ -611,7 +621,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			// read: 2 keys reads (1 chunk, 0 chunk) -> note, this is based on disk BEFORE block
 			// allocate: 0 key created
 			// write: 2 keys modified (1 chunk)
-			gomega.Ω(results[3].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[3].Success()).Should(gomega.BeTrue())
 			transferTxConsumed = chain.Dimensions{191, 7, 12, 0, 26}
 			gomega.Ω(results[3].Consumed).Should(gomega.Equal(transferTxConsumed))
 			// Fee explanation
@@ -667,10 +677,10 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		ginkgo.By("clear processing tip", func() {
 			results := accept(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 			results = accept2(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		})
 	})
 
@@ -752,6 +762,11 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(cli.RegisterBlocks()).Should(gomega.BeNil())
 
+		// Subscribe to block decisions
+		dcli, err := rpc.NewWebSocketClient(instances[0].WebSocketServer.URL, rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(dcli.RegisterBlockDecisions()).Should(gomega.BeNil())
+
 		// Wait for message to be sent
 		time.Sleep(2 * pubsub.MaxMessageWait)
 
@@ -783,7 +798,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept = expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		// Read item from connection
 		blk, lresults, prices, err := cli.ListenBlock(context.TODO(), parser)
@@ -799,8 +814,21 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(balance).Should(gomega.Equal(balancea + lresults[0].Fee + 1))
 
+		// Block decisions report verification before acceptance
+		blkID, err := blk.ID()
+		gomega.Ω(err).Should(gomega.BeNil())
+		for _, status := range []rpc.BlockStatus{rpc.BlockVerified, rpc.BlockAccepted} {
+			decision, err := dcli.ListenBlockDecision(context.TODO(), parser)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(decision.Status).Should(gomega.Equal(status))
+			gomega.Ω(decision.BlockID).Should(gomega.Equal(blkID))
+			gomega.Ω(decision.Height).Should(gomega.Equal(blk.Hght))
+			gomega.Ω(decision.Results).Should(gomega.Equal(results))
+		}
+
 		// Close connection when done
 		gomega.Ω(cli.Close()).Should(gomega.BeNil())
+		gomega.Ω(dcli.Close()).Should(gomega.BeNil())
 	})
 
 	ginkgo.It("processes valid index transactions (w/streaming verification)", func() {
@@ -826,6 +854,12 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		)
 		gomega.Ω(err).Should(gomega.BeNil())
 
+		// Watch the mempool from another client
+		mcli, err := rpc.NewWebSocketClient(instances[0].WebSocketServer.URL, rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(mcli.RegisterMempool(true)).Should(gomega.BeNil())
+		time.Sleep(2 * pubsub.MaxMessageWait)
+
 		// Submit tx and accept block
 		gomega.Ω(cli.RegisterTx(tx)).Should(gomega.BeNil())
 
@@ -839,17 +873,23 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			time.Sleep(500 * time.Millisecond)
 		}
 		gomega.Ω(err).Should(gomega.BeNil())
+		pendingID, pendingBytes, err := mcli.ListenMempool(context.TODO())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(pendingID).Should(gomega.Equal(tx.ID()))
+		gomega.Ω(pendingBytes).Should(gomega.Equal(tx.Bytes()))
+		gomega.Ω(mcli.Close()).Should(gomega.BeNil())
+
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		// Read decision from connection
 		txID, dErr, result, err := cli.ListenTx(context.TODO())
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(txID).Should(gomega.Equal(tx.ID()))
 		gomega.Ω(dErr).Should(gomega.BeNil())
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
 		gomega.Ω(result).Should(gomega.Equal(results[0]))
 
 		// Close connection when done
@@ -881,7 +921,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[0])
 			results := accept(false)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 			balance, err := instances[0].lcli.Balance(context.TODO(), codec.MustAddressBech32(lconsts.HRP, r1addr))
 			gomega.Ω(err).Should(gomega.BeNil())
@@ -906,7 +946,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[0])
 			results := accept(false)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		})
 	})
 
@@ -935,7 +975,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[0])
 			results := accept(false)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 			balance, err := instances[0].lcli.Balance(context.TODO(), codec.MustAddressBech32(lconsts.HRP, r1addr))
 			gomega.Ω(err).Should(gomega.BeNil())
@@ -960,7 +1000,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[0])
 			results := accept(false)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		})
 	})
 })
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/profiles"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+func TestAdminLogLevels(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	network := newNetwork(t, withVMConfig(`{"testMode":true,"adminAPIEnabled":true,"logLevel":"warn"}`))
+
+	// Modules start at the configured level
+	admin := hrpc.NewAdminClient(network.Instances()[0].URI)
+	level, modules, err := admin.LogLevels(ctx)
+	require.NoError(err)
+	require.Equal(logging.Warn, level)
+	for _, module := range []string{logs.VM, logs.Chain, logs.Builder, logs.Gossiper, logs.Backfill} {
+		require.Equal(logging.Warn, modules[module])
+	}
+
+	// Only the level of the debugged module changes
+	require.NoError(admin.SetLogLevel(ctx, logs.Gossiper, logging.Debug))
+	require.NoError(admin.SetLogLevel(ctx, "", logging.Info))
+	level, modules, err = admin.LogLevels(ctx)
+	require.NoError(err)
+	require.Equal(logging.Info, level)
+	require.Equal(logging.Debug, modules[logs.Gossiper])
+	require.Equal(logging.Info, modules[logs.Chain])
+
+	require.NoError(admin.ResetLogLevel(ctx, logs.Gossiper))
+	_, modules, err = admin.LogLevels(ctx)
+	require.NoError(err)
+	require.Equal(logging.Info, modules[logs.Gossiper])
+
+	require.ErrorContains(admin.SetLogLevel(ctx, "p2p", logging.Debug), logs.ErrUnknownModule.Error())
+	require.ErrorContains(admin.ResetLogLevel(ctx, ""), hrpc.ErrMissingLogLevel.Error())
+}
+
+func TestAdminProfile(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	network := newNetwork(t, withVMConfig(`{"testMode":true,"adminAPIEnabled":true}`))
+
+	admin := hrpc.NewAdminClient(network.Instances()[0].URI)
+	require.NoError(admin.StartProfile(ctx, profiles.Heap, time.Minute))
+	require.ErrorContains(admin.StartProfile(ctx, profiles.Heap, time.Minute), profiles.ErrProfileRunning.Error())
+	require.NoError(admin.StopProfile(ctx, profiles.Heap))
+	profile, err := admin.GetProfile(ctx, profiles.Heap)
+	require.NoError(err)
+	require.NotEmpty(profile)
+
+	// Profiles stop on their own
+	require.NoError(admin.StartProfile(ctx, profiles.Block, 10*time.Millisecond))
+	require.Eventually(func() bool {
+		_, err := admin.GetProfile(ctx, profiles.Block)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Continuous profiler writes to disk
+	dir := t.TempDir()
+	require.NoError(admin.StartContinuousProfiler(ctx, dir, time.Second, 1))
+	require.NoError(admin.StopContinuousProfiler(ctx))
+	require.Error(admin.StopContinuousProfiler(ctx))
+	require.Error(admin.StartContinuousProfiler(ctx, "", time.Second, 1))
+}
+
+func TestAdminCompact(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"adminAPIEnabled":true,"compactionInterval":10000000}`),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.BuildBlock(ctx, 0)
+		require.NoError(err)
+	}
+	admin := hrpc.NewAdminClient(network.Instances()[0].URI)
+	require.NoError(admin.Compact(ctx, hrpc.CompactBlockDB))
+	require.NoError(admin.Compact(ctx, hrpc.CompactStateDB))
+	require.NoError(admin.Compact(ctx, hrpc.CompactAllDBs))
+	require.ErrorContains(admin.Compact(ctx, "meta"), vm.ErrUnknownDatabase.Error())
+
+	// Blocks are still built while compacting in the background
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 4}, factory)
+	require.NoError(err)
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 1)
+}
+
+func TestServeLimits(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"serveBytesPerSecond":1,"serveBytesBurst":1}`),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.BuildBlock(ctx, 0)
+		require.NoError(err)
+	}
+
+	// Only the requested block is served once the budget is exceeded
+	inst := network.Instances()[0]
+	last := inst.VM.LastAcceptedBlock()
+	ancestors, err := inst.VM.GetAncestors(ctx, last.ID(), 10, 1<<20, time.Second)
+	require.NoError(err)
+	require.Len(ancestors, 1)
+	blks, err := inst.VM.BatchedParseBlock(ctx, ancestors)
+	require.NoError(err)
+	require.Equal(last.ID(), blks[0].ID())
+
+	// Nothing is served until the budget is refilled
+	ancestors, err = inst.VM.GetAncestors(ctx, last.ID(), 10, 1<<20, time.Second)
+	require.NoError(err)
+	require.Empty(ancestors)
+}
+
+func TestAdminStorageStats(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"adminAPIEnabled":true,"storageStatsInterval":10000000}`),
+	)
+
+	admin := hrpc.NewAdminClient(network.Instances()[0].URI)
+	var stats *hrpc.StorageStats
+	require.Eventually(func() bool {
+		stats, err = admin.StorageStats(ctx)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(stats.BlockDB)
+	require.NotNil(stats.StateDB)
+	require.Positive(stats.ValueNodes) // genesis allocation
+
+	// New accounts add nodes
+	recipient := codec.CreateAddress(consts.ED25519ID, ids.GenerateTestID())
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Eventually(func() bool {
+		next, err := admin.StorageStats(ctx)
+		return err == nil && next.ValueNodes > stats.ValueNodes && next.StateDB.DiskUsage > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
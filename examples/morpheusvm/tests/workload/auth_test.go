@@ -0,0 +1,298 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/tests/workload"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+)
+
+func TestSessionKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	master := auth.NewSessionAddress(priv.PublicKey())
+	signer := auth.NewED25519Address(priv.PublicKey())
+	sessionPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	priv2, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	recipient := auth.NewED25519Address(priv2.PublicKey())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(master, signer),
+	)
+
+	delegation := &auth.Delegation{
+		SessionKey:  sessionPriv.PublicKey(),
+		Expiry:      time.Now().Add(time.Hour).UnixMilli(),
+		ActionTypes: []uint8{consts.TransferID},
+		SpendingCap: 100_000,
+	}
+	factory := auth.NewSessionFactory(sessionPriv, priv.PublicKey(), delegation, auth.SignDelegation(priv, delegation))
+
+	// The session key spends from the session account of the master key
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1_000}, factory)
+	require.NoError(err)
+	result, err := network.Confirm(ctx, 1, tx.ID())
+	require.NoError(err)
+	for _, inst := range network.Instances() {
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, master))
+		require.NoError(err)
+		require.Equal(10_000_000-1_000-result.Fee, balance)
+	}
+
+	// Transactions over the spending cap are rejected
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 100_000}, factory)
+	require.ErrorContains(err, auth.ErrSpendingCapExceeded.Error())
+
+	// Spending (including fees charged) accumulates across transactions, so
+	// transactions that are each under the cap are rejected once their total
+	// would exceed it
+	spent := 1_000 + result.Fee
+	for i := uint64(0); i < 2; i++ {
+		tx, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 40_000 + i}, factory)
+		require.NoError(err)
+		result, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+		require.True(result.Success())
+		spent += 40_000 + i + result.Fee
+	}
+	for _, inst := range network.Instances() {
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		sessionSpent, revoked, err := cli.Session(ctx, codec.MustAddressBech32(consts.HRP, master), delegation.ID())
+		require.NoError(err)
+		require.Equal(spent, sessionSpent)
+		require.False(revoked)
+	}
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 40_002}, factory)
+	require.ErrorContains(err, auth.ErrSpendingCapExceeded.Error())
+
+	// Spending that remains under the cap is still allowed until the master
+	// key revokes the delegation
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	tx, err = network.Issue(ctx, 0, &actions.RevokeSession{Delegation: delegation.ID()}, auth.NewED25519Factory(priv))
+	require.NoError(err)
+	result, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.True(result.Success())
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory)
+	require.ErrorContains(err, auth.ErrSessionRevoked.Error())
+
+	// Delegations can't be forged by the session key
+	forged := auth.NewSessionFactory(sessionPriv, priv.PublicKey(), delegation, auth.SignDelegation(sessionPriv, delegation))
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, forged)
+	require.Error(err)
+
+	// Sessions can't be used after they expire
+	expired := &auth.Delegation{
+		SessionKey:  sessionPriv.PublicKey(),
+		Expiry:      time.Now().Add(-time.Minute).UnixMilli(),
+		ActionTypes: []uint8{consts.TransferID},
+		SpendingCap: 100_000,
+	}
+	factory = auth.NewSessionFactory(sessionPriv, priv.PublicKey(), expired, auth.SignDelegation(priv, expired))
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory)
+	require.ErrorContains(err, auth.ErrSessionExpired.Error())
+}
+
+func TestBLSAggregate(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	factory := auth.NewED25519Factory(priv)
+	funder := auth.NewED25519Address(priv.PublicKey())
+
+	var (
+		privs  = make([]*bls.PrivateKey, 3)
+		pks    = make([]*bls.PublicKey, len(privs))
+		proofs = make([]*bls.Signature, len(privs))
+	)
+	for i := range privs {
+		privs[i], err = bls.GeneratePrivateKey()
+		require.NoError(err)
+		pks[i] = bls.PublicFromPrivateKey(privs[i])
+		proofs[i] = bls.SignProofOfPossession(privs[i])
+	}
+	aggregateFactory, err := auth.NewBLSAggregateFactory(privs)
+	require.NoError(err)
+	aggregatePK, err := bls.AggregatePublicKeys(pks)
+	require.NoError(err)
+	aggregate := auth.NewBLSAggregateAddress(aggregatePK)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(funder, aggregate),
+	)
+
+	// Aggregate keys can't be used before they are registered
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: funder, Value: 1}, aggregateFactory)
+	require.ErrorContains(err, auth.ErrAggregateNotRegistered.Error())
+
+	// Every signer must prove possession of its key
+	forged := []*bls.Signature{proofs[0], proofs[0], proofs[2]}
+	tx, err := network.Issue(ctx, 0, &actions.RegisterAggregate{Signers: pks, ProofsOfPossession: forged}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.ErrorIs(err, workload.ErrTxFailed)
+
+	tx, err = network.Issue(ctx, 0, &actions.RegisterAggregate{Signers: pks, ProofsOfPossession: proofs}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+
+	// A single aggregate signature authorizes transactions of all signers
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: funder, Value: 1_000}, aggregateFactory)
+	require.NoError(err)
+	require.Equal(auth.BLSAggregateSize, tx.Auth.Size())
+	result, err := network.Confirm(ctx, 1, tx.ID())
+	require.NoError(err)
+	for _, inst := range network.Instances() {
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, aggregate))
+		require.NoError(err)
+		require.Equal(10_000_000-1_000-result.Fee, balance)
+		signers, registered, err := cli.Aggregate(ctx, codec.MustAddressBech32(consts.HRP, aggregate))
+		require.NoError(err)
+		require.True(registered)
+		require.Equal(uint16(len(pks)), signers)
+	}
+
+	// A subset of the signers can't authorize transactions on their own (the
+	// aggregate of their keys is not registered)
+	partial, err := auth.NewBLSAggregateFactory(privs[:2])
+	require.NoError(err)
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: funder, Value: 2}, partial)
+	require.Error(err)
+}
+
+func TestRotateKey(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	account := auth.NewAccountAddress(priv.PublicKey())
+	newPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	recipient := auth.NewED25519Address(newPriv.PublicKey())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(account),
+	)
+
+	// Only the key an account was derived from can use it before rotation
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, auth.NewAccountFactory(account, newPriv))
+	require.ErrorContains(err, auth.ErrWrongAccountKey.Error())
+
+	factory := auth.NewAccountFactory(account, priv)
+	tx, err := network.Issue(ctx, 0, &actions.RotateKey{NewKey: newPriv.PublicKey()}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	for _, inst := range network.Instances() {
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		key, rotated, err := cli.Account(ctx, codec.MustAddressBech32(consts.HRP, account))
+		require.NoError(err)
+		require.True(rotated)
+		require.Equal(newPriv.PublicKey(), key)
+	}
+
+	// The previous key can no longer authorize transactions of the account
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory)
+	require.ErrorContains(err, auth.ErrWrongAccountKey.Error())
+
+	// The new key spends from the same address
+	factory = auth.NewAccountFactory(account, newPriv)
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1_000}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	cli := rpc.NewJSONRPCClient(network.Instances()[0].URI, network.NetworkID(), network.ChainID())
+	balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, recipient))
+	require.NoError(err)
+	require.Equal(uint64(1_000), balance)
+}
+
+func TestSocialRecovery(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	account := auth.NewAccountAddress(priv.PublicKey())
+	newPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+
+	var (
+		guardianFactories = make([]chain.AuthFactory, 3)
+		guardians         = make([]codec.Address, len(guardianFactories))
+		allocations       = []*workload.Allocation{{Address: account, Balance: 10_000_000}}
+	)
+	for i := range guardians {
+		gpriv, err := ed25519.GeneratePrivateKey()
+		require.NoError(err)
+		guardianFactories[i] = auth.NewED25519Factory(gpriv)
+		guardians[i] = auth.NewED25519Address(gpriv.PublicKey())
+		allocations = append(allocations, &workload.Allocation{Address: guardians[i], Balance: 10_000_000})
+	}
+
+	network := newNetwork(t, withAllocations(allocations...))
+	confirm := func(action chain.Action, factory chain.AuthFactory) error {
+		tx, err := network.Issue(ctx, 0, action, factory)
+		if err != nil {
+			return err
+		}
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		return err
+	}
+
+	// The owner registers 2-of-3 guardians before losing its key
+	require.NoError(confirm(&actions.SetGuardians{Guardians: guardians, Threshold: 2}, auth.NewAccountFactory(account, priv)))
+
+	// A single guardian can't recover the account
+	initiate := &actions.InitiateRecovery{Account: account, NewKey: newPriv.PublicKey()}
+	finalize := &actions.FinalizeRecovery{Account: account}
+	require.NoError(confirm(initiate, guardianFactories[0]))
+	require.ErrorIs(confirm(finalize, guardianFactories[0]), workload.ErrTxFailed)
+
+	require.NoError(confirm(initiate, guardianFactories[1]))
+	cli := rpc.NewJSONRPCClient(network.Instances()[0].URI, network.NetworkID(), network.ChainID())
+	recovery, err := cli.Recovery(ctx, codec.MustAddressBech32(consts.HRP, account))
+	require.NoError(err)
+	require.Len(recovery.Guardians, len(guardians))
+	require.True(recovery.Pending)
+	require.Equal(newPriv.PublicKey(), recovery.NewKey)
+	require.NotZero(recovery.ReadyAt)
+
+	// Once finalized, the new key controls the account
+	require.NoError(confirm(&actions.FinalizeRecovery{Account: account}, guardianFactories[2]))
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: guardians[0], Value: 1}, auth.NewAccountFactory(account, priv))
+	require.ErrorContains(err, auth.ErrWrongAccountKey.Error())
+	require.NoError(confirm(&actions.Transfer{To: guardians[0], Value: 2}, auth.NewAccountFactory(account, newPriv)))
+}
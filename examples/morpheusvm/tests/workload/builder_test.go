@@ -0,0 +1,219 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withVMConfig(`{"testMode":true,"mempoolSponsorSize":1}`),
+		withFunds(sender),
+	)
+	gather := func() map[string]*dto.MetricFamily {
+		families, err := network.Instances()[0].Metrics.Gather()
+		require.NoError(err)
+		byName := make(map[string]*dto.MetricFamily, len(families))
+		for _, f := range families {
+			byName[f.GetName()] = f
+		}
+		return byName
+	}
+
+	// The second tx of the sender doesn't fit in the mempool
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory)
+	require.NoError(err)
+	metrics := gather()
+	fees := metrics["hypersdk_chain_mempool_max_fee"].GetMetric()[0].GetHistogram()
+	require.Equal(uint64(1), fees.GetSampleCount())
+	require.Equal(float64(tx.MaxFee()), fees.GetSampleSum())
+	dropped := metrics["hypersdk_chain_txs_dropped"].GetMetric()
+	require.Len(dropped, 1)
+	require.Equal("sponsor_limit", dropped[0].GetLabel()[0].GetValue())
+	require.Equal(float64(1), dropped[0].GetCounter().GetValue())
+
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	metrics = gather()
+	require.Zero(metrics["hypersdk_chain_mempool_max_fee"].GetMetric()[0].GetHistogram().GetSampleCount())
+	require.Equal(float64(1), metrics["hypersdk_chain_blocks_built"].GetMetric()[0].GetCounter().GetValue())
+	require.GreaterOrEqual(metrics["hypersdk_chain_build_attempts"].GetMetric()[0].GetCounter().GetValue(), float64(1))
+	for phase := chain.BuildPrepare; phase < chain.NumBuildPhases; phase++ {
+		count := metrics["hypersdk_chain_build_"+phase.String()+"_count"].GetMetric()[0].GetCounter().GetValue()
+		require.Equal(float64(1), count, phase.String())
+	}
+}
+
+func TestCongestionPolicy(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withGenesis(func(gen *genesis.Genesis) {
+			// Bandwidth is the dominant dimension of a transfer and is over
+			// target after 2 transfers
+			gen.WindowTargetUnits[chain.Bandwidth] = 300
+		}),
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"mempoolCongestionPolicy":"reject"}`),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+	}
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 2)
+
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 3}, factory)
+	require.ErrorContains(err, vm.ErrCongested.Error())
+	require.Zero(network.Instances()[0].VM.Mempool().Len(ctx))
+}
+
+func TestBundle(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipientPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	recipient := auth.NewED25519Address(recipientPriv.PublicKey())
+	recipientFactory := auth.NewED25519Factory(recipientPriv)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+	generate := func(action chain.Action, factory chain.AuthFactory) *chain.Transaction {
+		_, tx, _, err := inst.Client.GenerateTransaction(ctx, inst.VM, nil, action, factory)
+		require.NoError(err)
+		return tx
+	}
+
+	// The second transfer can only be paid for by the first, so the bundle
+	// is included ahead of transactions in the mempool
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, factory)
+	require.NoError(err)
+	bundle := []*chain.Transaction{
+		generate(&actions.Transfer{To: recipient, Value: 100_000}, factory),
+		generate(&actions.Transfer{To: sender, Value: 1_000}, recipientFactory),
+	}
+	bundleID, err := inst.Client.SubmitBundle(ctx, [][]byte{bundle[0].Bytes(), bundle[1].Bytes()})
+	require.NoError(err)
+	expected, err := chain.NewBundle(bundle)
+	require.NoError(err)
+	require.Equal(expected.ID(), bundleID)
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 3)
+	require.Equal(bundle[0].ID(), blk.Txs[0].ID())
+	require.Equal(bundle[1].ID(), blk.Txs[1].ID())
+	require.Equal(tx.ID(), blk.Txs[2].ID())
+	for _, result := range blk.Results() {
+		require.True(result.Success())
+	}
+
+	// No transaction in the bundle is included if any of them can't be
+	unfundedPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	bundle = []*chain.Transaction{
+		generate(&actions.Transfer{To: recipient, Value: 1}, factory),
+		generate(&actions.Transfer{To: sender, Value: 1}, auth.NewED25519Factory(unfundedPriv)),
+	}
+	_, err = inst.Client.SubmitBundle(ctx, [][]byte{bundle[0].Bytes(), bundle[1].Bytes()})
+	require.NoError(err)
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 2}, factory)
+	require.NoError(err)
+	blk, err = network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 1)
+	require.Equal(tx.ID(), blk.Txs[0].ID())
+
+	// Bundles must be well-formed
+	_, err = inst.Client.SubmitBundle(ctx, nil)
+	require.ErrorContains(err, chain.ErrEmptyBundle.Error())
+	_, err = inst.Client.SubmitBundle(ctx, [][]byte{bundle[0].Bytes(), bundle[0].Bytes()})
+	require.ErrorContains(err, chain.ErrDuplicateTx.Error())
+}
+
+func TestFCFSOrdering(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"fcfsOrdering":true}`),
+	)
+	inst := network.Instances()[0]
+
+	txs := make([]*chain.Transaction, 4)
+	for i := range txs {
+		txs[i], err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		time.Sleep(2 * time.Millisecond)
+	}
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, len(txs))
+	require.Len(blk.Arrivals, len(txs))
+	for i, tx := range txs {
+		require.Equal(tx.ID(), blk.Txs[i].ID())
+		if i > 0 {
+			require.Greater(blk.Arrivals[i], blk.Arrivals[i-1])
+		}
+	}
+
+	// Arrivals are verified by the other node
+	require.Equal(blk.Arrivals, network.Instances()[1].VM.LastAcceptedBlock().Arrivals)
+
+	// Bundles would be included ahead of earlier transactions
+	_, err = inst.Client.SubmitBundle(ctx, [][]byte{txs[0].Bytes()})
+	require.ErrorContains(err, vm.ErrBundlesDisabled.Error())
+}
@@ -0,0 +1,304 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/tests/workload"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+)
+
+// totalBurned returns the sum of the fees burned in each dimension.
+func totalBurned(burned chain.Dimensions) uint64 {
+	var total uint64
+	for i := chain.Dimension(0); int(i) < chain.FeeDimensions(); i++ {
+		total += burned[i]
+	}
+	return total
+}
+
+func TestBlobs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"blobRetention":1}`),
+	)
+
+	// Blobs are charged on their own dimension
+	inst := network.Instances()[0]
+	blob := bytes.Repeat([]byte{0xAB}, 1_024)
+	submit, tx, _, err := inst.Client.GenerateBlobTransaction(ctx, inst.VM, &actions.Transfer{To: sender, Value: 1}, blob, factory)
+	require.NoError(err)
+	require.NoError(submit(ctx))
+	require.NoError(inst.VM.Gossiper().Force(ctx))
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 1)
+	require.Equal(chain.BlobsRoot(blk.Txs), blk.BlobsRoot)
+	require.NotEqual(ids.Empty, blk.BlobsRoot)
+	result := blk.Results()[0]
+	require.True(result.Success())
+	blobDimension, ok := chain.BlobDimension()
+	require.True(ok)
+	require.Equal(uint64(len(blob)), result.Consumed[blobDimension])
+	require.Less(result.Consumed[chain.Bandwidth], uint64(len(blob)))
+
+	// Every node stores the blob
+	for _, inst := range network.Instances() {
+		height, stored, err := inst.Client.GetBlob(ctx, tx.ID())
+		require.NoError(err)
+		require.Equal(blk.Hght, height)
+		require.Equal(blob, stored)
+	}
+
+	// Blobs are deleted once they are no longer retained
+	tx2, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 2}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx2.ID())
+	require.NoError(err)
+	_, _, err = inst.Client.GetBlob(ctx, tx.ID())
+	require.ErrorContains(err, database.ErrNotFound.Error())
+}
+
+func TestBuilderFees(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	priv2, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	beneficiary := codec.MustAddressBech32(consts.HRP, auth.NewED25519Address(priv2.PublicKey()))
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.BuilderFeeShare = 40
+		}),
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"beneficiary":"`+beneficiary+`"}`),
+	)
+
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, auth.NewED25519Factory(priv))
+	require.NoError(err)
+	result, err := network.Confirm(ctx, 1, tx.ID())
+	require.NoError(err)
+	blk := network.Instances()[1].VM.LastAcceptedBlock()
+	require.Equal(beneficiary, codec.MustAddressBech32(consts.HRP, blk.Beneficiary))
+
+	// The configured beneficiary is credited with its share and the rest is
+	// burned (see [chain.SplitFees])
+	burned := blk.Burned()
+	for _, inst := range network.Instances() {
+		stored, err := inst.Client.Burned(ctx)
+		require.NoError(err)
+		require.Equal(burned, stored)
+
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		share, err := cli.Balance(ctx, beneficiary)
+		require.NoError(err)
+		require.NotZero(share)
+		require.Equal(result.Fee, share+totalBurned(burned))
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, sender))
+		require.NoError(err)
+		require.Equal(10_000_000-result.Fee, balance)
+	}
+}
+
+func TestBurned(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t, withFunds(sender))
+
+	// Without a beneficiary, all fees are burned and accumulated across blocks
+	inst := network.Instances()[0]
+	var fees uint64
+	for i := 0; i < 2; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		result, err := network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+		fees += result.Fee
+	}
+	burned, err := inst.Client.Burned(ctx)
+	require.NoError(err)
+	require.Equal(fees, totalBurned(burned))
+}
+
+func TestMinFee(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	const minFee = 1_000_000
+
+	network := newNetwork(t,
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.MinFee = minFee
+		}),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+
+	// Transactions that can't pay the minimum fee are rejected
+	submit, _, err := inst.Client.GenerateTransactionManual(inst.VM, nil, &actions.Transfer{To: sender, Value: 1}, factory, minFee-1)
+	require.NoError(err)
+	require.ErrorContains(submit(ctx), chain.ErrInsufficientPrice.Error())
+
+	// A transfer costs less than the minimum at the floor prices, so it is
+	// charged the minimum (all of which is burned)
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, factory)
+	require.NoError(err)
+	require.Equal(uint64(minFee), tx.Base.MaxFee)
+	result, err := network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.Equal(uint64(minFee), result.Fee)
+	burned, err := inst.Client.Burned(ctx)
+	require.NoError(err)
+	require.Equal(uint64(minFee), totalBurned(burned))
+	balance, err := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID()).Balance(ctx, codec.MustAddressBech32(consts.HRP, sender))
+	require.NoError(err)
+	require.Equal(uint64(10_000_000-minFee), balance)
+}
+
+func TestBlockReward(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	pool := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.BlockReward = 1_000
+			gen.BlockRewardHalvingInterval = 2
+			gen.BlockRewardPool = pool
+		}),
+		withFunds(sender),
+	)
+
+	// Without a beneficiary, each block mints its reward (following the
+	// schedule of the genesis) to the pool
+	inst := network.Instances()[0]
+	cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+	var minted uint64
+	for i := 0; i < 3; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+
+		blk := inst.VM.LastAcceptedBlock()
+		recipient, reward := blk.Reward()
+		require.Equal(pool, recipient)
+		require.Equal(chain.HalvingReward(1_000, 2, blk.Hght), reward)
+		minted += reward
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, pool))
+		require.NoError(err)
+		require.Equal(minted, balance)
+	}
+}
+
+func TestActionFeePayers(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+	protocolPriv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	protocol := auth.NewED25519Address(protocolPriv.PublicKey())
+
+	network := newNetwork(t,
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.TxCancellation = true
+			gen.ActionFeePayers = map[uint8]codec.Address{
+				consts.TransferID: protocol,
+				consts.CancelID:   codec.EmptyAddress,
+			}
+		}),
+		withAllocations(
+			&workload.Allocation{Address: sender, Balance: 1_000},
+			&workload.Allocation{Address: protocol, Balance: 10_000_000},
+		),
+	)
+
+	// Transfers are paid for by the protocol account
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1_000}, factory)
+	require.NoError(err)
+	result, err := network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.True(result.Success())
+	require.Positive(result.Fee)
+	fee := result.Fee
+	cli := rpc.NewJSONRPCClient(network.Instances()[0].URI, network.NetworkID(), network.ChainID())
+	balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, sender))
+	require.NoError(err)
+	require.Zero(balance)
+	balance, err = cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, protocol))
+	require.NoError(err)
+	require.Equal(10_000_000-fee, balance)
+
+	// Cancellations are exempt from fees (even for unfunded sponsors)
+	tx, err = network.Issue(ctx, 0, &actions.Cancel{TxID: ids.GenerateTestID(), Expiry: time.Now().UnixMilli() + 30_000}, factory)
+	require.NoError(err)
+	result, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.True(result.Success())
+	require.Zero(result.Fee)
+	balance, err = cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, protocol))
+	require.NoError(err)
+	require.Equal(10_000_000-fee, balance)
+
+	// Failed cancellations would be free, so they are never included
+	_, err = network.Issue(ctx, 0, &actions.Cancel{TxID: ids.GenerateTestID(), Expiry: time.Now().UnixMilli() + 10*time.Hour.Milliseconds()}, factory)
+	require.NoError(err)
+	tx, err = network.Issue(ctx, 0, &actions.Cancel{TxID: ids.GenerateTestID(), Expiry: time.Now().UnixMilli() + 30_000}, factory)
+	require.NoError(err)
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 1)
+	require.Equal(tx.ID(), blk.Txs[0].ID())
+	require.Zero(network.Instances()[0].VM.Mempool().Len(ctx))
+}
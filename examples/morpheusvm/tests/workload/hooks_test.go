@@ -0,0 +1,228 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/controller"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/version"
+)
+
+func TestHooks(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	// Each instance rejects transfers of the value it blocks and records the
+	// results of the transactions it executes
+	var (
+		l        sync.Mutex
+		nodes    int
+		blocked  = map[int]uint64{}
+		executed = map[int]map[ids.ID]*chain.Result{}
+		blocks   = map[int][]*chain.Result{}
+	)
+	newVM := func() *vm.VM {
+		l.Lock()
+		node := nodes
+		nodes++
+		executed[node] = map[ids.ID]*chain.Result{}
+		l.Unlock()
+		return vm.New(&chaintest.Controller{
+			Controller: &controller.Controller{},
+			PreExecuteF: func(_ context.Context, _ chain.Rules, _ state.Immutable, _ int64, _ uint64, tx *chain.Transaction) error {
+				l.Lock()
+				defer l.Unlock()
+
+				value, ok := blocked[node]
+				if transfer := tx.Action.(*actions.Transfer); ok && transfer.Value == value {
+					return errors.New("blocked")
+				}
+				return nil
+			},
+			PostExecuteF: func(_ context.Context, _ chain.Rules, _ state.Immutable, _ int64, _ uint64, tx *chain.Transaction, result *chain.Result) {
+				l.Lock()
+				defer l.Unlock()
+
+				executed[node][tx.ID()] = result
+			},
+			PostBlockF: func(_ context.Context, _ chain.Rules, _ int64, _ uint64, _ []*chain.Transaction, results []*chain.Result) {
+				l.Lock()
+				defer l.Unlock()
+
+				blocks[node] = results
+			},
+		}, version.Version)
+	}
+	network := newNetwork(t,
+		withNodes(2),
+		withVM(newVM),
+		withFunds(sender),
+	)
+	block := func(node int, value uint64) {
+		l.Lock()
+		defer l.Unlock()
+
+		blocked = map[int]uint64{node: value}
+	}
+
+	// Rejected transactions aren't added to the mempool
+	block(0, 1)
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.ErrorContains(err, chain.ErrTxRejectedByHook.Error())
+
+	// Transactions rejected while building are dropped
+	block(0, 3)
+	allowed, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory)
+	require.NoError(err)
+	rejected, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 4}, factory)
+	require.NoError(err)
+	block(0, 4)
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 1)
+	require.Equal(allowed.ID(), blk.Txs[0].ID())
+	require.Zero(network.Instances()[0].VM.Mempool().Len(ctx))
+
+	// Post-hooks see the results of the block on the builder and verifier
+	results := blk.Results()
+	l.Lock()
+	for node := 0; node < 2; node++ {
+		require.Len(executed[node], 1)
+		require.True(executed[node][allowed.ID()].Success())
+		require.Equal(results[0], executed[node][allowed.ID()])
+		require.Equal(results, blocks[node])
+	}
+	require.NotContains(executed[0], rejected.ID())
+	l.Unlock()
+
+	// Blocks that include a rejected transaction fail verification
+	block(1, 5)
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 5}, factory)
+	require.NoError(err)
+	_, err = network.BuildBlock(ctx, 0)
+	require.ErrorIs(err, chain.ErrTxRejectedByHook)
+}
+
+func TestBlockSeed(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	// Each instance records the seeds available to the transactions it
+	// executes (and how often none was available)
+	var (
+		l        sync.Mutex
+		nodes    int
+		unseeded int
+		blocks   = map[int]map[uint64]ids.ID{}
+		txs      = map[int]map[ids.ID]ids.ID{}
+	)
+	newVM := func() *vm.VM {
+		l.Lock()
+		node := nodes
+		nodes++
+		blocks[node] = map[uint64]ids.ID{}
+		txs[node] = map[ids.ID]ids.ID{}
+		l.Unlock()
+		return vm.New(&chaintest.Controller{
+			Controller: &controller.Controller{},
+			PreExecuteF: func(ctx context.Context, _ chain.Rules, _ state.Immutable, _ int64, _ uint64, _ *chain.Transaction) error {
+				l.Lock()
+				defer l.Unlock()
+
+				if _, ok := chain.GetBlockSeed(ctx); !ok {
+					unseeded++
+				}
+				return nil
+			},
+			PostExecuteF: func(ctx context.Context, _ chain.Rules, _ state.Immutable, _ int64, height uint64, tx *chain.Transaction, _ *chain.Result) {
+				l.Lock()
+				defer l.Unlock()
+
+				// Missing seeds are recorded as empty
+				blockSeed, _ := chain.GetBlockSeed(ctx)
+				txSeed, _ := chain.GetTxSeed(ctx, tx.ID())
+				blocks[node][height] = blockSeed
+				txs[node][tx.ID()] = txSeed
+			},
+		}, version.Version)
+	}
+	network := newNetwork(t,
+		withNodes(2),
+		withVM(newVM),
+		withFunds(sender),
+	)
+
+	// Seeds aren't available when transactions are submitted
+	txIDs := []ids.ID{}
+	for i := uint64(1); i <= 2; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: i}, factory)
+		require.NoError(err)
+		txIDs = append(txIDs, tx.ID())
+	}
+	l.Lock()
+	require.Positive(unseeded)
+	unseeded = 0
+	l.Unlock()
+
+	// The builder and the verifier see the same seeds, which match the
+	// accepted block
+	blk, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	require.Len(blk.Txs, 2)
+	l.Lock()
+	require.Zero(unseeded)
+	l.Unlock()
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 3}, factory)
+	require.NoError(err)
+	txIDs = append(txIDs, tx.ID())
+	next, err := network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	l.Lock()
+	defer l.Unlock()
+	for _, inst := range network.Instances() {
+		accepted, err := inst.VM.GetStatelessBlock(ctx, blk.ID())
+		require.NoError(err)
+		require.Equal(chain.NewBlockSeed(blk.Prnt, blk.Hght), accepted.Seed())
+	}
+	require.Equal(blocks[0], blocks[1])
+	require.Equal(txs[0], txs[1])
+	require.Equal(blk.Seed(), blocks[0][blk.Hght])
+	require.Equal(next.Seed(), blocks[0][next.Hght])
+
+	// Seeds differ between blocks and between transactions
+	require.NotEqual(blk.Seed(), next.Seed())
+	seeds := set.Set[ids.ID]{}
+	for _, txID := range txIDs {
+		seeds.Add(txs[0][txID])
+	}
+	require.Equal(3, seeds.Len())
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestAPINode(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+
+	network := newNetwork(t,
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"apiNode":true}`),
+	)
+
+	// API nodes accept txs but never build blocks
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, auth.NewED25519Factory(priv))
+	require.NoError(err)
+	_, err = network.BuildBlock(ctx, 0)
+	require.ErrorIs(err, vm.ErrAPINode)
+}
+
+func TestRestartMempool(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+
+	network := newNetwork(t, withFunds(sender))
+
+	// Pending transactions are persisted on shutdown and re-submitted once
+	// the node is ready
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, auth.NewED25519Factory(priv))
+	require.NoError(err)
+	require.NoError(network.Restart(ctx, 0))
+	require.Eventually(func() bool {
+		return network.Instances()[0].VM.Mempool().Len(ctx) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+}
+
+func TestReloadConfig(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"adminAPIEnabled":true}`),
+	)
+
+	// Lower the mempool size without restarting
+	inst := network.Instances()[0]
+	admin := hrpc.NewAdminClient(inst.URI)
+	require.NoError(admin.ReloadConfig(ctx, []byte(`{"testMode":true,"mempoolSize":1}`)))
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 2}, factory)
+	require.NoError(err)
+	require.Equal(1, inst.VM.Mempool().Len(ctx))
+
+	// Malformed configs are rejected
+	require.Error(admin.ReloadConfig(ctx, []byte(`{"mempoolSize":"big"}`)))
+}
+
+func TestStateCache(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+	)
+
+	// Each block reads (and then writes) the same hot keys
+	for i := 0; i < 3; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+	}
+	for _, inst := range network.Instances() {
+		c := inst.VM.StateCache()
+		require.NotNil(c)
+		require.Positive(c.Len())
+		db, err := inst.VM.State()
+		require.NoError(err)
+		view := c.View(db)
+		for _, k := range [][]byte{storage.BalanceKey(sender), storage.BalanceKey(recipient)} {
+			expected, err := db.GetValue(ctx, k)
+			require.NoError(err)
+			cached, err := view.GetValue(ctx, k)
+			require.NoError(err)
+			require.Equal(expected, cached)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+)
+
+func TestSequentialNonces(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.SequentialNonces = true
+		}),
+		withFunds(sender),
+	)
+	cli := network.Instances()[0].Client
+	nonce, err := cli.GetNonce(ctx, sender)
+	require.NoError(err)
+	require.Zero(nonce)
+
+	// A transaction ahead of the next nonce waits in the mempool until the gap
+	// is filled
+	tx1, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory, hrpc.WithNonce(1))
+	require.NoError(err)
+	require.True(tx1.Base.SequentialNonce())
+	tx0, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory, hrpc.WithNonce(0))
+	require.NoError(err)
+	mempool := network.Instances()[0].VM.Mempool()
+	included := []ids.ID{}
+	for len(included) < 2 {
+		// [tx1] is skipped (and restored asynchronously) if it is streamed
+		// before [tx0]
+		require.Eventually(func() bool {
+			return mempool.Len(ctx) == 2-len(included)
+		}, time.Second, 10*time.Millisecond)
+		blk, err := network.BuildBlock(ctx, 0)
+		require.NoError(err)
+		require.NotEmpty(blk.Txs)
+		for _, tx := range blk.Txs {
+			included = append(included, tx.ID())
+		}
+	}
+	require.Equal([]ids.ID{tx0.ID(), tx1.ID()}, included)
+	for _, inst := range network.Instances() {
+		nonce, err := inst.Client.GetNonce(ctx, sender)
+		require.NoError(err)
+		require.Equal(uint64(2), nonce)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(g *genesis.Genesis) { g.TxCancellation = true }),
+		withFunds(sender),
+	)
+
+	// Sign a transfer without submitting it
+	inst := network.Instances()[0]
+	submit, tx, _, err := inst.Client.GenerateTransaction(ctx, inst.VM, nil, &actions.Transfer{To: recipient, Value: 1_000}, factory)
+	require.NoError(err)
+
+	cancel, err := network.Issue(ctx, 0, &actions.Cancel{TxID: tx.ID(), Expiry: tx.Expiry()}, factory)
+	require.NoError(err)
+	result, err := network.Confirm(ctx, 0, cancel.ID())
+	require.NoError(err)
+	require.True(result.Success())
+
+	// The cancelled transaction can no longer be included (on any node)
+	require.ErrorContains(submit(ctx), chain.ErrTxCancelled.Error())
+	_, err = network.Instances()[1].Client.SubmitTx(ctx, tx.Bytes())
+	require.ErrorContains(err, chain.ErrTxCancelled.Error())
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+	balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, recipient))
+	require.NoError(err)
+	require.Equal(uint64(1), balance)
+}
+
+func TestTxNonInclusion(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.MinEmptyBlockGap = 0
+			gen.HeightValidityWindow = 4
+		}),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+	verifier := network.Instances()[1].Client
+
+	// Build past genesis so that the proof doesn't reach it
+	for i := 0; i < 3; i++ {
+		_, err = network.BuildBlock(ctx, 0)
+		require.NoError(err)
+	}
+
+	// Generate a tx that is never submitted and one that is included
+	_, height, _, err := inst.Client.Accepted(ctx)
+	require.NoError(err)
+	expiry := height + 3
+	_, dropped, _, err := inst.Client.GenerateTransaction(
+		ctx, inst.VM, nil, &actions.Transfer{To: recipient, Value: 1}, factory, hrpc.WithHeightExpiry(expiry),
+	)
+	require.NoError(err)
+	included, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 2}, factory, hrpc.WithHeightExpiry(expiry))
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, included.ID())
+	require.NoError(err)
+
+	// Non-inclusion can't be proven until the tx expires
+	_, err = inst.Client.TxNonInclusion(ctx, dropped.ID(), 0, expiry)
+	require.ErrorContains(err, hrpc.ErrTxNotExpired.Error())
+	for i := 0; i < 3; i++ {
+		_, err = network.BuildBlock(ctx, 0)
+		require.NoError(err)
+	}
+
+	proof, err := inst.Client.TxNonInclusion(ctx, dropped.ID(), 0, expiry)
+	require.NoError(err)
+	require.Equal(expiry+1, proof.After.Height)
+	require.Len(proof.Blocks, 5) // [expiry - window, expiry]
+	require.Equal(expiry-4, proof.Blocks[0].Height)
+	require.Equal(expiry-5, proof.Before.Height)
+	require.NoError(verifier.VerifyTxNonInclusion(ctx, inst.VM, dropped.ID(), 0, expiry, proof))
+
+	// Proofs that skip a block in the validity window are rejected
+	skipped := &hrpc.GetTxNonInclusionReply{Before: proof.Before, Blocks: proof.Blocks[1:], After: proof.After}
+	require.ErrorIs(
+		verifier.VerifyTxNonInclusion(ctx, inst.VM, dropped.ID(), 0, expiry, skipped),
+		hrpc.ErrInvalidNonInclusion,
+	)
+
+	// The included tx is detected by both the server and the verifier
+	_, err = inst.Client.TxNonInclusion(ctx, included.ID(), 0, expiry)
+	require.ErrorContains(err, hrpc.ErrTxIncluded.Error())
+	require.ErrorIs(
+		verifier.VerifyTxNonInclusion(ctx, inst.VM, included.ID(), 0, expiry, proof),
+		hrpc.ErrTxIncluded,
+	)
+
+	// Timestamp expiries are covered back to genesis while every block is in
+	// the validity window
+	_, _, timestamp, err := inst.Client.Accepted(ctx)
+	require.NoError(err)
+	txID := ids.GenerateTestID()
+	proof, err = inst.Client.TxNonInclusion(ctx, txID, timestamp-1, 0)
+	require.NoError(err)
+	require.Zero(proof.Before.Height)
+	require.Greater(proof.After.Timestamp, timestamp-1)
+	require.NoError(verifier.VerifyTxNonInclusion(ctx, inst.VM, txID, timestamp-1, 0, proof))
+}
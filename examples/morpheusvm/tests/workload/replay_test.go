@@ -0,0 +1,163 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestReplay(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	priv2, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	recipient := auth.NewED25519Address(priv2.PublicKey())
+
+	network := newNetwork(t, withFunds(sender))
+
+	// Store the blocks (and results) accepted by the network
+	source := memdb.New()
+	blks := []*chain.StatelessBlock{}
+	for i := 0; i < 3; i++ {
+		_, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		blk, err := network.BuildBlock(ctx, 0)
+		require.NoError(err)
+		blks = append(blks, blk)
+
+		require.NoError(source.Put(vm.PrefixBlockKey(blk.Hght), blk.Bytes()))
+		results, prices, err := network.Instances()[0].VM.GetDiskBlockResults(blk.Hght)
+		require.NoError(err)
+		mresults, err := chain.MarshalResults(results)
+		require.NoError(err)
+		require.NoError(source.Put(vm.PrefixBlockResultsKey(blk.Hght), append(prices.Bytes(), mresults...)))
+	}
+
+	// Replay all blocks on a fresh instance, only reporting the last two
+	replayer, err := network.AddInstance(ctx)
+	require.NoError(err)
+	replayed := []*vm.ReplayedBlock{}
+	require.NoError(replayer.VM.Replay(chain.WithBlockTrace(ctx), source, 2, 3, func(r *vm.ReplayedBlock) error {
+		replayed = append(replayed, r)
+		return nil
+	}))
+	require.Len(replayed, 2)
+	for i, r := range replayed {
+		blk := blks[i+1]
+		require.Equal(blk.ID(), r.Block.ID())
+		require.Equal(blk.Results(), r.Results)
+
+		// Each transaction is traced
+		require.Equal(blk.ID(), r.Trace.Block)
+		require.Positive(r.Trace.Execution)
+		require.Len(r.Trace.Timings, 1)
+		timing := r.Trace.Timings[0]
+		require.Equal(blk.Txs[0].ID(), timing.ID)
+		require.Equal("transfer", timing.Action)
+		require.Equal(chain.StatusSuccess, timing.Status)
+		require.Positive(timing.Parse)
+		require.Positive(timing.Execute)
+		trace, err := replayer.VM.BlockTrace(blk.ID())
+		require.NoError(err)
+		require.Equal(r.Trace, trace)
+	}
+	require.Equal(blks[2].ID(), replayer.VM.LastAcceptedBlock().ID())
+
+	// Blocks executed without tracing have no trace
+	_, err = network.Instances()[0].VM.BlockTrace(blks[2].ID())
+	require.ErrorIs(err, vm.ErrNoBlockTrace)
+
+	// Instance can keep up with the network after replaying
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 4}, factory)
+	require.NoError(err)
+	_, err = network.BuildBlock(ctx, 0)
+	require.NoError(err)
+
+	// Divergent results are reported
+	results, prices, err := network.Instances()[0].VM.GetDiskBlockResults(1)
+	require.NoError(err)
+	results[0].Fee++
+	mresults, err := chain.MarshalResults(results)
+	require.NoError(err)
+	require.NoError(source.Put(vm.PrefixBlockResultsKey(1), append(prices.Bytes(), mresults...)))
+	diverged, err := network.AddInstance(ctx)
+	require.NoError(err)
+	err = diverged.VM.Replay(ctx, source, 1, 3, func(*vm.ReplayedBlock) error { return nil })
+	require.ErrorIs(err, vm.ErrReplayDiverged)
+}
+
+func TestStateDigest(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+	)
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 100_000}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+
+	// Nodes that executed the same blocks have the same digest
+	digest, err := network.Instances()[0].VM.StateDigest(ctx)
+	require.NoError(err)
+	other, err := network.Instances()[1].VM.StateDigest(ctx)
+	require.NoError(err)
+	require.Equal(digest, other)
+	require.Equal(uint64(1), digest.Height)
+
+	var keys uint64
+	for _, prefix := range digest.Prefixes {
+		keys += prefix.Keys
+	}
+	require.Equal(digest.Keys, keys)
+
+	// Balances are summarized by the controller
+	cli := rpc.NewJSONRPCClient(network.Instances()[0].URI, network.NetworkID(), network.ChainID())
+	var total uint64
+	for _, addr := range []codec.Address{sender, recipient} {
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, addr))
+		require.NoError(err)
+		total += balance
+	}
+	require.Equal(storage.BalanceSummary{Accounts: 2, Total: total}, digest.Summaries["balances"])
+
+	// Any change to state changes the digest
+	tx, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	_, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	next, err := network.Instances()[0].VM.StateDigest(ctx)
+	require.NoError(err)
+	require.NotEqual(digest.Digest, next.Digest)
+	require.NotEqual(digest.Root, next.Root)
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+)
+
+// overriddenParser signs transactions with the rules overridden in state.
+type overriddenParser struct {
+	chain.Parser
+	overrides *chain.RulesOverrides
+}
+
+func (p *overriddenParser) Rules(t int64) chain.Rules {
+	return chain.WithRulesOverrides(p.Parser.Rules(t), p.overrides)
+}
+
+func TestRulesOverrides(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.RulesAuthority = sender
+		}),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+
+	window := int64(5_000)
+	tx, err := network.Issue(ctx, 0, &actions.UpdateRules{Overrides: chain.RulesOverrides{ValidityWindow: &window}}, factory)
+	require.NoError(err)
+	result, err := network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.True(result.Success())
+	overrides, err := inst.Client.RulesOverrides(ctx)
+	require.NoError(err)
+	require.Equal(window, *overrides.ValidityWindow)
+	require.Nil(overrides.MinBlockGap)
+
+	// Transactions expiring within the validity window of genesis are now
+	// too far in the future
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.ErrorContains(err, chain.ErrTimestampTooEarly.Error())
+
+	parser := &overriddenParser{inst.VM, overrides}
+	submit, tx, _, err := inst.Client.GenerateTransaction(ctx, parser, nil, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	require.NoError(submit(ctx))
+	require.NoError(inst.VM.Gossiper().Force(ctx))
+	result, err = network.Confirm(ctx, 0, tx.ID())
+	require.NoError(err)
+	require.True(result.Success())
+}
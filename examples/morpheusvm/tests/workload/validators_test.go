@@ -0,0 +1,237 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/crypto/bls"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/keys"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/controller"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/version"
+)
+
+func TestValidatorSnapshot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.EpochLength = 2
+			gen.ValidatorSnapshots = true
+		}),
+		withFunds(sender),
+	)
+
+	// The last block of epoch 0 (height 1) takes a snapshot that both
+	// instances agree on
+	for i := 0; i < 2; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+	}
+	for _, inst := range network.Instances() {
+		snapshot, msg, err := inst.Client.GetValidatorSnapshot(ctx, 0)
+		require.NoError(err)
+		require.Len(snapshot.Validators, 2)
+		require.Negative(bytes.Compare(snapshot.Validators[0].NodeID[:], snapshot.Validators[1].NodeID[:]))
+		totalWeight, err := snapshot.TotalWeight()
+		require.NoError(err)
+		require.Equal(uint64(2), totalWeight)
+		require.Equal(network.ChainID(), msg.SourceChainID)
+
+		// Each instance signs the snapshot on accept
+		signatures, err := inst.VM.GetWarpSignatures(chain.ValidatorSnapshotID(0))
+		require.NoError(err)
+		require.Len(signatures, 1)
+		pk, err := bls.PublicKeyFromBytes(signatures[0].PublicKey)
+		require.NoError(err)
+		sig, err := bls.SignatureFromBytes(signatures[0].Signature)
+		require.NoError(err)
+		require.True(bls.Verify(msg.Bytes(), pk, sig))
+
+		// Epoch 1 has not ended yet
+		_, _, err = inst.Client.GetValidatorSnapshot(ctx, 1)
+		require.ErrorContains(err, hrpc.ErrSnapshotMissing.Error())
+	}
+}
+
+func TestResignWarpMessage(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.EpochLength = 2
+			gen.ValidatorSnapshots = true
+		}),
+		withFunds(sender),
+		withVMConfig(`{"testMode":true,"warpMessageRetention":2}`),
+		withValidators(),
+	)
+
+	// The snapshot message of epoch 0 is emitted by the block at height 1
+	blks := []*chain.StatelessBlock{}
+	for i := 0; i < 2; i++ {
+		_, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		blk, err := network.BuildBlock(ctx, 0)
+		require.NoError(err)
+		blks = append(blks, blk)
+	}
+	inst := network.Instances()[0]
+	_, snapshotMsg, err := inst.Client.GetValidatorSnapshot(ctx, 0)
+	require.NoError(err)
+
+	// The message is returned with the context of its block and signed by
+	// the node, while the other validator is asked for its signature
+	snapshotID := chain.ValidatorSnapshotID(0)
+	msg, signature, requested, err := inst.Client.ResignWarpMessage(ctx, snapshotID)
+	require.NoError(err)
+	require.Equal(blks[0].Hght, msg.Height)
+	require.Equal(blks[0].Tmstmp, msg.Timestamp)
+	require.Equal(blks[0].ID(), msg.BlockID)
+	require.Equal(snapshotMsg.Bytes(), msg.Message.Bytes())
+	pk, err := bls.PublicKeyFromBytes(signature.PublicKey)
+	require.NoError(err)
+	sig, err := bls.SignatureFromBytes(signature.Signature)
+	require.NoError(err)
+	require.True(bls.Verify(msg.Message.Bytes(), pk, sig))
+	require.LessOrEqual(requested, 1)
+	require.Eventually(func() bool {
+		_, _, signatures, err := inst.Client.GetWarpSignatures(ctx, snapshotID)
+		return err == nil && len(signatures) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Relayers can't re-sign a message in quick succession
+	_, _, _, err = inst.Client.ResignWarpMessage(ctx, snapshotID)
+	require.ErrorContains(err, vm.ErrResignTooFrequent.Error())
+
+	// Messages can't be re-signed once they are no longer retained
+	_, err = network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: 3}, factory)
+	require.NoError(err)
+	_, err = network.BuildBlock(ctx, 0)
+	require.NoError(err)
+	_, err = inst.VM.GetDiskWarpMessage(snapshotID)
+	require.ErrorIs(err, database.ErrNotFound)
+	_, _, _, err = network.Instances()[1].Client.ResignWarpMessage(ctx, snapshotID)
+	require.ErrorContains(err, hrpc.ErrMessageMissing.Error())
+}
+
+func TestEpochHandler(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	// Each instance counts the epochs that ended in [epochKey] and records
+	// every invocation
+	var (
+		l        sync.Mutex
+		nodes    int
+		ended    = map[int][]uint64{}
+		diverge  = map[int]bool{}
+		epochKey = keys.EncodeChunks([]byte{0xf0}, 1)
+	)
+	newVM := func() *vm.VM {
+		l.Lock()
+		node := nodes
+		nodes++
+		l.Unlock()
+		return vm.New(&chaintest.Controller{
+			Controller:      &controller.Controller{},
+			EpochStateKeysF: func(uint64) []string { return []string{string(epochKey)} },
+			EndEpochF: func(ctx context.Context, _ chain.Rules, mu state.Mutable, _ int64, epoch uint64) error {
+				l.Lock()
+				defer l.Unlock()
+
+				ended[node] = append(ended[node], epoch)
+				count := uint64(0)
+				v, err := mu.GetValue(ctx, epochKey)
+				if err == nil {
+					count = binary.BigEndian.Uint64(v)
+				} else if !errors.Is(err, database.ErrNotFound) {
+					return err
+				}
+				if diverge[node] {
+					count++
+				}
+				return mu.Insert(ctx, epochKey, binary.BigEndian.AppendUint64(nil, count+1))
+			},
+		}, version.Version)
+	}
+	network := newNetwork(t,
+		withNodes(2),
+		withVM(newVM),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.EpochLength = 2
+		}),
+		withFunds(sender),
+	)
+	build := func(value uint64) error {
+		_, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: value}, factory)
+		require.NoError(err)
+		_, err = network.BuildBlock(ctx, 0)
+		return err
+	}
+
+	// Blocks 1 and 3 end epochs 0 and 1, which are processed exactly once by
+	// the builder and the verifier
+	for i := uint64(1); i <= 4; i++ {
+		require.NoError(build(i))
+	}
+	l.Lock()
+	require.Equal(map[int][]uint64{0: {0, 1}, 1: {0, 1}}, ended)
+	l.Unlock()
+	for _, inst := range network.Instances() {
+		db, err := inst.VM.State()
+		require.NoError(err)
+		v, err := db.GetValue(ctx, epochKey)
+		require.NoError(err)
+		require.Equal(uint64(2), binary.BigEndian.Uint64(v))
+	}
+
+	// The changes of the handler are committed to the state root, so a block
+	// built on a diverging epoch transition is rejected
+	l.Lock()
+	diverge[1] = true
+	l.Unlock()
+	require.NoError(build(5))
+	require.ErrorIs(build(6), chain.ErrStateRootMismatch)
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/pubsub"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+)
+
+// dropProxy forwards connections to [target] until they are dropped.
+type dropProxy struct {
+	ln     net.Listener
+	target string
+
+	l     sync.Mutex
+	conns []net.Conn
+}
+
+func newDropProxy(t *testing.T, target string) *dropProxy {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+	p := &dropProxy{ln: ln, target: target}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				_ = conn.Close()
+				continue
+			}
+			p.l.Lock()
+			p.conns = append(p.conns, conn, upstream)
+			p.l.Unlock()
+			go func() {
+				_, _ = io.Copy(upstream, conn)
+				_ = upstream.Close()
+			}()
+			go func() {
+				_, _ = io.Copy(conn, upstream)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return p
+}
+
+func (p *dropProxy) URI() string {
+	return "http://" + p.ln.Addr().String()
+}
+
+func (p *dropProxy) drop() {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	for _, conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.conns = nil
+}
+
+func TestReconnectingWebSocketClient(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	// Only the last block is retained for resuming clients
+	network := newNetwork(t,
+		withNodes(2),
+		withVMConfig(`{"testMode":true,"streamingReplaySize":1}`),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+	proxy := newDropProxy(t, strings.TrimPrefix(inst.URI, "http://"))
+
+	var (
+		gaps       = make(chan *hrpc.BlockGap, 1)
+		reconnects = make(chan error, 1)
+		config     = hrpc.DefaultReconnectConfig()
+	)
+	config.MinBackoff = 10 * time.Millisecond
+	config.Backfill = inst.Client
+	config.OnGap = func(gap *hrpc.BlockGap) {
+		gaps <- gap
+	}
+	config.OnReconnect = func(err error) {
+		reconnects <- err
+	}
+	cli, err := hrpc.NewReconnectingWebSocketClient(proxy.URI(), config)
+	require.NoError(err)
+	defer cli.Close()
+	require.NoError(cli.RegisterBlocks())
+
+	// [control] is never disconnected
+	control, err := hrpc.NewWebSocketClient(inst.URI, hrpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+	require.NoError(err)
+	defer control.Close()
+	require.NoError(control.RegisterBlocks())
+	time.Sleep(2 * pubsub.MaxMessageWait)
+
+	confirm := func(value uint64) uint64 {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: value}, factory)
+		require.NoError(err)
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+		blk, _, _, err := control.ListenBlock(ctx, inst.VM)
+		require.NoError(err)
+		return blk.Hght
+	}
+	first := confirm(1)
+	blk, _, _, err := cli.ListenBlock(ctx, inst.VM)
+	require.NoError(err)
+	require.Equal(first, blk.Hght)
+
+	// Blocks accepted while disconnected are no longer retained except for
+	// the last one
+	proxy.drop()
+	for i := uint64(2); i <= 4; i++ {
+		confirm(i)
+	}
+	blk, _, _, err = cli.ListenBlock(ctx, inst.VM)
+	require.NoError(err)
+	require.Equal(first+3, blk.Hght)
+	require.Equal(1, cli.Reconnects())
+	require.Error(<-reconnects)
+
+	gap := <-gaps
+	require.Equal(first+1, gap.From)
+	require.Equal(first+2, gap.To)
+	require.NoError(gap.Err)
+	require.Len(gap.Blocks, 2)
+	for i, missed := range gap.Blocks {
+		require.Equal(gap.From+uint64(i), missed.Height)
+		require.Len(missed.Txs, 1)
+	}
+
+	// The client keeps listening on the new connection
+	require.Equal(first+4, confirm(5))
+	blk, _, _, err = cli.ListenBlock(ctx, inst.VM)
+	require.NoError(err)
+	require.Equal(first+4, blk.Hght)
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+)
+
+func TestBlockWitnesses(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+
+	network := newNetwork(t,
+		withNodes(2),
+		withGenesis(func(gen *genesis.Genesis) {
+			gen.BlockWitnesses = true
+		}),
+		withFunds(sender),
+	)
+
+	var blk *chain.StatelessBlock
+	for i := 0; i < 2; i++ {
+		tx, err := network.Issue(ctx, 0, &actions.Transfer{To: sender, Value: uint64(i + 1)}, factory)
+		require.NoError(err)
+		_, err = network.Confirm(ctx, 0, tx.ID())
+		require.NoError(err)
+
+		// The other instance verified the witness before accepting the block
+		blk = network.Instances()[1].VM.LastAcceptedBlock()
+		require.NotEmpty(blk.Witness)
+	}
+
+	// An instance with only the genesis state executes the block against its
+	// witness
+	inst, err := network.AddInstance(ctx)
+	require.NoError(err)
+	sblk, err := inst.VM.ParseBlock(ctx, blk.Bytes())
+	require.NoError(err)
+	results, err := sblk.(*chain.StatelessBlock).ExecuteWitness(ctx)
+	require.NoError(err)
+	require.Len(results, 1)
+	require.True(results[0].Success())
+	require.Equal(blk.Results()[0].Consumed, results[0].Consumed)
+	require.Equal(blk.Results()[0].Fee, results[0].Fee)
+
+	// Execution fails if the witness doesn't prove a key it reads
+	w, err := chain.UnmarshalBlockWitness(blk.Witness)
+	require.NoError(err)
+	proofs := w.Proofs[:0]
+	for _, proof := range w.Proofs {
+		if !bytes.Equal(proof.Key.Bytes(), storage.BalanceKey(sender)) {
+			proofs = append(proofs, proof)
+		}
+	}
+	require.Len(proofs, len(w.Proofs)-1)
+	w.Proofs = proofs
+	incomplete := *blk.StatefulBlock
+	incomplete.Witness, err = w.Marshal()
+	require.NoError(err)
+	iblk, err := chain.ParseStatefulBlock(ctx, &incomplete, nil, choices.Processing, inst.VM)
+	require.NoError(err)
+	_, err = iblk.ExecuteWitness(ctx)
+	require.ErrorIs(err, chain.ErrKeyNotWitnessed)
+
+	branchFactor := genesis.Default().StateBranchFactor
+	ws, err := blk.VerifyWitness(ctx, branchFactor)
+	require.NoError(err)
+	balance, err := ws.GetValue(ctx, storage.BalanceKey(sender))
+	require.NoError(err)
+	require.NotEmpty(balance)
+	_, err = ws.GetValue(ctx, storage.BalanceKey(codec.CreateAddress(0, ids.GenerateTestID())))
+	require.ErrorIs(err, chain.ErrKeyNotWitnessed)
+
+	// The witness does not prove anything against another root
+	forged := *blk.StatefulBlock
+	forged.StateRoot = ids.GenerateTestID()
+	_, err = forged.VerifyWitness(ctx, branchFactor)
+	require.ErrorIs(err, chain.ErrInvalidWitness)
+}
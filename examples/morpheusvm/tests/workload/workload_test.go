@@ -0,0 +1,169 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/tests/workload"
+	"github.com/ava-labs/hypersdk/vm"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/auth"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/controller"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
+)
+
+// networkOption modifies the [workload.Config] of a network started with
+// [newNetwork].
+type networkOption func(*workload.Config)
+
+// newNetwork starts a network with a single node running the default
+// controller and genesis (modified by [opts]). It is shut down once [t] and
+// all of its subtests complete.
+func newNetwork(t *testing.T, opts ...networkOption) *workload.Network {
+	config := &workload.Config{
+		NumNodes:  1,
+		NetworkID: 1,
+		NewVM:     controller.New,
+		Genesis:   newGenesis(func(*genesis.Genesis) {}),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	network, err := workload.New(context.Background(), config)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, network.Shutdown(context.Background()))
+	})
+	return network
+}
+
+// newGenesis returns a [workload.Config.Genesis] that applies [f] to the
+// genesis used by all tests before it is encoded.
+func newGenesis(f func(*genesis.Genesis)) func([]*workload.Allocation) ([]byte, error) {
+	return func(allocations []*workload.Allocation) ([]byte, error) {
+		gen := genesis.Default()
+		gen.MinUnitPrice = chain.Dimensions{1, 1, 1, 1, 1}
+		gen.MinBlockGap = 0
+		for _, alloc := range allocations {
+			gen.CustomAllocation = append(gen.CustomAllocation, &genesis.CustomAllocation{
+				Address: codec.MustAddressBech32(consts.HRP, alloc.Address),
+				Balance: alloc.Balance,
+			})
+		}
+		f(gen)
+		return json.Marshal(gen)
+	}
+}
+
+func withNodes(n int) networkOption {
+	return func(c *workload.Config) { c.NumNodes = n }
+}
+
+func withVM(newVM func() *vm.VM) networkOption {
+	return func(c *workload.Config) { c.NewVM = newVM }
+}
+
+// withGenesis applies [f] to the genesis of the network.
+func withGenesis(f func(*genesis.Genesis)) networkOption {
+	return func(c *workload.Config) { c.Genesis = newGenesis(f) }
+}
+
+// withFunds allocates 10,000,000 to each of [addrs] at genesis.
+func withFunds(addrs ...codec.Address) networkOption {
+	allocations := make([]*workload.Allocation, len(addrs))
+	for i, addr := range addrs {
+		allocations[i] = &workload.Allocation{Address: addr, Balance: 10_000_000}
+	}
+	return withAllocations(allocations...)
+}
+
+func withAllocations(allocations ...*workload.Allocation) networkOption {
+	return func(c *workload.Config) { c.Allocations = append(c.Allocations, allocations...) }
+}
+
+func withVMConfig(config string) networkOption {
+	return func(c *workload.Config) { c.VMConfig = []byte(config) }
+}
+
+func withValidators() networkOption {
+	return func(c *workload.Config) { c.TrackValidators = true }
+}
+
+func TestTransfer(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	priv2, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	recipient := auth.NewED25519Address(priv2.PublicKey())
+
+	network := newNetwork(t,
+		withNodes(3),
+		withFunds(sender),
+	)
+
+	tx, err := network.Issue(ctx, 0, &actions.Transfer{To: recipient, Value: 100_000}, auth.NewED25519Factory(priv))
+	require.NoError(err)
+
+	// Block is built by a node that only received the tx via gossip
+	_, err = network.Confirm(ctx, 1, tx.ID())
+	require.NoError(err)
+
+	for _, inst := range network.Instances() {
+		cli := rpc.NewJSONRPCClient(inst.URI, network.NetworkID(), network.ChainID())
+		balance, err := cli.Balance(ctx, codec.MustAddressBech32(consts.HRP, recipient))
+		require.NoError(err)
+		require.Equal(uint64(100_000), balance)
+	}
+}
+
+func TestFetchTxs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	sender := auth.NewED25519Address(priv.PublicKey())
+	factory := auth.NewED25519Factory(priv)
+	recipient := codec.CreateAddress(0, ids.GenerateTestID())
+
+	network := newNetwork(t,
+		withNodes(2),
+		withFunds(sender),
+	)
+	inst := network.Instances()[0]
+
+	// Submit without gossiping, so that only the first node has the tx
+	submit, tx, _, err := inst.Client.GenerateTransaction(ctx, inst.VM, nil, &actions.Transfer{To: recipient, Value: 1}, factory)
+	require.NoError(err)
+	require.NoError(submit(ctx))
+	peer := network.Instances()[1].VM
+	require.Zero(peer.Mempool().Len(ctx))
+
+	txs, err := peer.FetchTxs(ctx, []ids.ID{tx.ID()})
+	require.NoError(err)
+	require.Len(txs, 1)
+	require.Equal(tx.Bytes(), txs[0].Bytes())
+
+	// Txs that no peer has are reported as missing
+	txs, err = peer.FetchTxs(ctx, []ids.ID{ids.GenerateTestID(), tx.ID()})
+	require.ErrorIs(err, vm.ErrTxsUnavailable)
+	require.Len(txs, 1)
+	require.Equal(tx.ID(), txs[0].ID())
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*AddLiquidity)(nil)
+
+// AddLiquidity deposits [AssetA] and [AssetB] into their pool at its current
+// price in exchange for shares of its reserves.
+type AddLiquidity struct {
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+
+	// [MaxA] and [MaxB] are the most of each asset that will be deposited.
+	// Only as much of one as matches the price of the pool given the other is
+	// used.
+	MaxA uint64 `json:"maxA"`
+	MaxB uint64 `json:"maxB"`
+
+	// [MinShares] is the fewest shares to accept (to protect against the
+	// price moving before the transaction is executed).
+	MinShares uint64 `json:"minShares"`
+}
+
+func (*AddLiquidity) GetTypeID() uint8 {
+	return addLiquidityID
+}
+
+func (a *AddLiquidity) StateKeys(actor codec.Address, _ ids.ID) []string {
+	pool := storage.PoolID(a.AssetA, a.AssetB)
+	return []string{
+		string(storage.PoolKey(pool)),
+		string(storage.LiquidityKey(pool, actor)),
+		string(storage.BalanceKey(actor, a.AssetA)),
+		string(storage.BalanceKey(actor, a.AssetB)),
+	}
+}
+
+func (*AddLiquidity) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.LiquidityChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*AddLiquidity) OutputsWarpMessage() bool {
+	return false
+}
+
+func (a *AddLiquidity) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if a.AssetA == a.AssetB {
+		return false, AddLiquidityComputeUnits, OutputSameInOut, nil, nil
+	}
+	exists, reserveA, reserveB, totalShares, err := storage.GetPool(ctx, mu, a.AssetA, a.AssetB)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, AddLiquidityComputeUnits, OutputPoolMissing, nil, nil
+	}
+
+	// Mint shares in proportion to the scarcer of the deposits
+	sharesA, err := mulDiv(a.MaxA, totalShares, reserveA)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	sharesB, err := mulDiv(a.MaxB, totalShares, reserveB)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	shares := sharesA
+	if sharesB < shares {
+		shares = sharesB
+	}
+	if shares == 0 {
+		return false, AddLiquidityComputeUnits, OutputInsufficientInput, nil, nil
+	}
+	if shares < a.MinShares {
+		return false, AddLiquidityComputeUnits, OutputBelowMinimum, nil, nil
+	}
+
+	// Round deposits up so that existing shares are never diluted
+	amountA, err := mulDivUp(shares, reserveA, totalShares)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	amountB, err := mulDivUp(shares, reserveB, totalShares)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	nreserveA, err := smath.Add64(reserveA, amountA)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	nreserveB, err := smath.Add64(reserveB, amountB)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	ntotalShares, err := smath.Add64(totalShares, shares)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	pool := storage.PoolID(a.AssetA, a.AssetB)
+	liquidity, err := storage.GetLiquidity(ctx, mu, pool, actor)
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SubBalance(ctx, mu, actor, a.AssetA, amountA); err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SubBalance(ctx, mu, actor, a.AssetB, amountB); err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SetPool(ctx, mu, a.AssetA, a.AssetB, nreserveA, nreserveB, ntotalShares); err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	// [liquidity] can't overflow because it is at most [totalShares]
+	if err := storage.SetLiquidity(ctx, mu, pool, actor, liquidity+shares); err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	lr := &LiquidityResult{AmountA: amountA, AmountB: amountB, Shares: shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return false, AddLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, AddLiquidityComputeUnits, output, nil, nil
+}
+
+func (*AddLiquidity) MaxComputeUnits(chain.Rules) uint64 {
+	return AddLiquidityComputeUnits
+}
+
+func (*AddLiquidity) Size() int {
+	return consts.IDLen*2 + consts.Uint64Len*3
+}
+
+func (a *AddLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(a.AssetA)
+	p.PackID(a.AssetB)
+	p.PackUint64(a.MaxA)
+	p.PackUint64(a.MaxB)
+	p.PackUint64(a.MinShares)
+}
+
+func UnmarshalAddLiquidity(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var add AddLiquidity
+	p.UnpackID(false, &add.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &add.AssetB) // empty ID is the native asset
+	add.MaxA = p.UnpackUint64(true)
+	add.MaxB = p.UnpackUint64(true)
+	add.MinShares = p.UnpackUint64(false)
+	return &add, p.Err()
+}
+
+func (*AddLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestAllowanceConformance(t *testing.T) {
+	var (
+		rules   = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		owner   = codec.CreateAddress(0, ids.GenerateTestID())
+		spender = codec.CreateAddress(0, ids.GenerateTestID())
+		to      = codec.CreateAddress(0, ids.GenerateTestID())
+		asset   = ids.GenerateTestID()
+		balance = string(storage.BalanceKey(owner, asset))
+		allow   = string(storage.AllowanceKey(owner, spender, asset))
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "approve",
+			Action:          &Approve{Spender: spender, Asset: asset, Value: 10},
+			Unmarshal:       UnmarshalApprove,
+			Actor:           owner,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:   "revoke approval",
+			Action: &Approve{Spender: spender, Asset: asset},
+			State: map[string][]byte{
+				allow: binary.BigEndian.AppendUint64(nil, 10),
+			},
+			Unmarshal:       UnmarshalApprove,
+			Actor:           owner,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "approve self",
+			Action:          &Approve{Spender: owner, Asset: asset, Value: 10},
+			Unmarshal:       UnmarshalApprove,
+			Actor:           owner,
+			ExpectedOutput:  OutputSelfApproval,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:   "transfer from",
+			Action: &TransferFrom{From: owner, To: to, Asset: asset, Value: 10},
+			State: map[string][]byte{
+				balance: binary.BigEndian.AppendUint64(nil, 100),
+				allow:   binary.BigEndian.AppendUint64(nil, 10),
+			},
+			Unmarshal:       UnmarshalTransferFrom,
+			Actor:           spender,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:   "transfer from unlimited allowance",
+			Action: &TransferFrom{From: owner, To: to, Asset: asset, Value: 100},
+			State: map[string][]byte{
+				balance: binary.BigEndian.AppendUint64(nil, 100),
+				allow:   binary.BigEndian.AppendUint64(nil, math.MaxUint64),
+			},
+			Unmarshal:       UnmarshalTransferFrom,
+			Actor:           spender,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:   "transfer from exceeds allowance",
+			Action: &TransferFrom{From: owner, To: to, Asset: asset, Value: 11},
+			State: map[string][]byte{
+				balance: binary.BigEndian.AppendUint64(nil, 100),
+				allow:   binary.BigEndian.AppendUint64(nil, 10),
+			},
+			Unmarshal:       UnmarshalTransferFrom,
+			Actor:           spender,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:   "transfer from exceeds balance",
+			Action: &TransferFrom{From: owner, To: to, Asset: asset, Value: 10},
+			State: map[string][]byte{
+				balance: binary.BigEndian.AppendUint64(nil, 5),
+				allow:   binary.BigEndian.AppendUint64(nil, 10),
+			},
+			Unmarshal:       UnmarshalTransferFrom,
+			Actor:           spender,
+			ExpectedStatus:  chain.StatusInsufficientBalance,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:                 "transfer from zero value",
+			Action:               &TransferFrom{From: owner, To: to, Asset: asset},
+			Unmarshal:            UnmarshalTransferFrom,
+			ExpectedUnmarshalErr: codec.ErrFieldNotPopulated,
+			Actor:                spender,
+			ExpectedOutput:       OutputValueZero,
+			ExpectedSuccess:      false,
+		},
+	} {
+		test.Rules = rules
+		test.TxID = ids.GenerateTestID()
+		chaintest.RunActionTest(t, test)
+	}
+}
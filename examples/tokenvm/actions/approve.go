@@ -0,0 +1,96 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Approve)(nil)
+
+// Approve allows [Spender] to transfer up to [Value] of the actor's [Asset]
+// with [TransferFrom]. Like ERC-20, it replaces any previous allowance (so a
+// [Value] of 0 revokes it) and an allowance of [math.MaxUint64] is never
+// spent.
+type Approve struct {
+	// Spender can transfer [Value] on behalf of the actor.
+	Spender codec.Address `json:"spender"`
+
+	// Asset that [Spender] can transfer.
+	Asset ids.ID `json:"asset"`
+
+	// Value is the new allowance of [Spender].
+	Value uint64 `json:"value"`
+}
+
+func (*Approve) GetTypeID() uint8 {
+	return approveID
+}
+
+func (a *Approve) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.AllowanceKey(actor, a.Spender, a.Asset)),
+	}
+}
+
+func (*Approve) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AllowanceChunks}
+}
+
+func (*Approve) OutputsWarpMessage() bool {
+	return false
+}
+
+func (a *Approve) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if a.Spender == actor {
+		return false, ApproveComputeUnits, OutputSelfApproval, nil, nil
+	}
+	if err := storage.SetAllowance(ctx, mu, actor, a.Spender, a.Asset, a.Value); err != nil {
+		return false, ApproveComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, ApproveComputeUnits, nil, nil, nil
+}
+
+func (*Approve) MaxComputeUnits(chain.Rules) uint64 {
+	return ApproveComputeUnits
+}
+
+func (*Approve) Size() int {
+	return codec.AddressLen + consts.IDLen + consts.Uint64Len
+}
+
+func (a *Approve) Marshal(p *codec.Packer) {
+	p.PackAddress(a.Spender)
+	p.PackID(a.Asset)
+	p.PackUint64(a.Value)
+}
+
+func UnmarshalApprove(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var approve Approve
+	p.UnpackAddress(&approve.Spender)
+	p.UnpackID(false, &approve.Asset) // empty ID is the native asset
+	approve.Value = p.UnpackUint64(false)
+	return &approve, p.Err()
+}
+
+func (*Approve) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
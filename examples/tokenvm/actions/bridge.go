@@ -0,0 +1,45 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// GetBridgeLocked returns the value of [asset] (native to the chain of [im])
+// locked for [destination].
+func GetBridgeLocked(ctx context.Context, im state.Immutable, asset ids.ID, destination ids.ID) (uint64, error) {
+	return storage.GetLoan(ctx, im, asset, destination)
+}
+
+// GetBridgeMinted returns the supply of [asset] (native to [source]) minted on
+// the chain of [im].
+func GetBridgeMinted(ctx context.Context, im state.Immutable, asset ids.ID, source ids.ID) (uint64, error) {
+	_, _, _, _, supply, _, _, err := storage.GetAsset(ctx, im, ImportedAssetID(asset, source))
+	return supply, err
+}
+
+// ReconcileBridge checks the value of an asset [locked] on its source chain
+// for a destination (see [GetBridgeLocked]) against the supply [minted] for
+// it on the destination (see [GetBridgeMinted]) and returns the value of the
+// transfers that are in flight (exported by one chain and not yet imported by
+// the other).
+//
+// Exporting an asset locks it on the source before importing it mints it on
+// the destination, and exporting it back burns it on the destination before
+// importing it unlocks it on the source. So, if [minted] exceeds [locked], the
+// destination holds assets that are not backed by the source and
+// [ErrUnbackedSupply] is returned.
+func ReconcileBridge(locked uint64, minted uint64) (uint64, error) {
+	if minted > locked {
+		return 0, fmt.Errorf("%w: minted=%d locked=%d", ErrUnbackedSupply, minted, locked)
+	}
+	return locked - minted, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+// bridgeChain is a tokenvm chain that executes actions directly against its
+// state and delivers its warp messages without signatures.
+type bridgeChain struct {
+	rules chain.Rules
+	state chaintest.State
+}
+
+func newBridgeChain() *bridgeChain {
+	return &bridgeChain{
+		rules: genesis.Default().Rules(0, 1, ids.GenerateTestID()),
+		state: chaintest.State{},
+	}
+}
+
+func (c *bridgeChain) export(t *testing.T, actor codec.Address, action *ExportAsset) *warp.Message {
+	require := require.New(t)
+
+	success, _, output, unsigned, err := action.Execute(
+		context.Background(),
+		c.rules,
+		c.state,
+		0,
+		actor,
+		ids.GenerateTestID(),
+		false,
+	)
+	require.NoError(err)
+	require.True(success, "export failed (output=%s)", output)
+	unsigned, err = warp.NewUnsignedMessage(1, c.rules.ChainID(), unsigned.Payload)
+	require.NoError(err)
+	msg, err := warp.NewMessage(unsigned, &warp.BitSetSignature{})
+	require.NoError(err)
+	return msg
+}
+
+func (c *bridgeChain) importMessage(t *testing.T, actor codec.Address, msg *warp.Message) {
+	require := require.New(t)
+
+	p := codec.NewWriter(0, 1)
+	(&ImportAsset{}).Marshal(p)
+	action, err := UnmarshalImportAsset(codec.NewReader(p.Bytes(), 1), msg)
+	require.NoError(err)
+	success, _, output, _, err := action.Execute(
+		context.Background(),
+		c.rules,
+		c.state,
+		0,
+		actor,
+		ids.GenerateTestID(),
+		true,
+	)
+	require.NoError(err)
+	require.True(success, "import failed (output=%s)", output)
+}
+
+func TestBridge(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+
+		source      = newBridgeChain()
+		destination = newBridgeChain()
+		sourceID    = source.rules.ChainID()
+		destID      = destination.rules.ChainID()
+
+		alice = codec.CreateAddress(0, ids.GenerateTestID())
+		bob   = codec.CreateAddress(0, ids.GenerateTestID())
+		asset = ids.GenerateTestID()
+	)
+	require.NoError(storage.SetAsset(ctx, source.state, asset, []byte("TKN"), 9, []byte("token"), 100, alice, false))
+	require.NoError(storage.SetBalance(ctx, source.state, alice, asset, 100))
+
+	reconcile := func(expectedInFlight uint64) {
+		locked, err := GetBridgeLocked(ctx, source.state, asset, destID)
+		require.NoError(err)
+		minted, err := GetBridgeMinted(ctx, destination.state, asset, sourceID)
+		require.NoError(err)
+		inFlight, err := ReconcileBridge(locked, minted)
+		require.NoError(err)
+		require.Equal(expectedInFlight, inFlight)
+	}
+
+	// Lock on the source, then mint on the destination
+	msg := source.export(t, alice, &ExportAsset{To: bob, Asset: asset, Value: 60, Destination: destID})
+	reconcile(60)
+	destination.importMessage(t, bob, msg)
+	reconcile(0)
+	imported := ImportedAssetID(asset, sourceID)
+	balance, err := storage.GetBalance(ctx, destination.state, bob, imported)
+	require.NoError(err)
+	require.Equal(uint64(60), balance)
+
+	// Burn on the destination, then unlock on the source
+	msg = destination.export(t, bob, &ExportAsset{To: bob, Asset: imported, Value: 25, Return: true, Destination: sourceID})
+	reconcile(25)
+	source.importMessage(t, bob, msg)
+	reconcile(0)
+	balance, err = storage.GetBalance(ctx, source.state, bob, asset)
+	require.NoError(err)
+	require.Equal(uint64(25), balance)
+
+	// Minting on the destination without locking on the source is detected
+	require.NoError(storage.SubLoan(ctx, source.state, asset, destID, 10))
+	locked, err := GetBridgeLocked(ctx, source.state, asset, destID)
+	require.NoError(err)
+	minted, err := GetBridgeMinted(ctx, destination.state, asset, sourceID)
+	require.NoError(err)
+	_, err = ReconcileBridge(locked, minted)
+	require.ErrorIs(err, ErrUnbackedSupply)
+}
+
+func TestExportAssetToSelf(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		c     = newBridgeChain()
+		actor = codec.CreateAddress(0, ids.GenerateTestID())
+		asset = ids.GenerateTestID()
+	)
+	success, _, output, msg, err := (&ExportAsset{
+		To:          actor,
+		Asset:       asset,
+		Value:       1,
+		Destination: c.rules.ChainID(),
+	}).Execute(context.Background(), c.rules, c.state, 0, actor, ids.GenerateTestID(), false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputInvalidDestination, output)
+	require.Nil(msg)
+	require.Empty(c.state)
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*BurnAsset)(nil)
@@ -60,21 +59,21 @@ func (b *BurnAsset) Execute(
 		return false, BurnComputeUnits, OutputValueZero, nil, nil
 	}
 	if err := storage.SubBalance(ctx, mu, actor, b.Asset, b.Value); err != nil {
-		return false, BurnComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, BurnComputeUnits, nil, nil, chain.Fail(err)
 	}
 	exists, symbol, decimals, metadata, supply, owner, warp, err := storage.GetAsset(ctx, mu, b.Asset)
 	if err != nil {
-		return false, BurnComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, BurnComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
-		return false, BurnComputeUnits, OutputAssetMissing, nil, nil
+		return false, BurnComputeUnits, nil, nil, ErrAssetMissing
 	}
 	newSupply, err := smath.Sub(supply, b.Value)
 	if err != nil {
-		return false, BurnComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, BurnComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.SetAsset(ctx, mu, b.Asset, symbol, decimals, metadata, newSupply, owner, warp); err != nil {
-		return false, BurnComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, BurnComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, BurnComputeUnits, nil, nil, nil
 }
@@ -13,7 +13,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*CloseOrder)(nil)
@@ -57,7 +56,7 @@ func (c *CloseOrder) Execute(
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	exists, _, _, out, _, remaining, owner, err := storage.GetOrder(ctx, mu, c.Order)
 	if err != nil {
-		return false, CloseOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CloseOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
 		return false, CloseOrderComputeUnits, OutputOrderMissing, nil, nil
@@ -69,10 +68,10 @@ func (c *CloseOrder) Execute(
 		return false, CloseOrderComputeUnits, OutputWrongOut, nil, nil
 	}
 	if err := storage.DeleteOrder(ctx, mu, c.Order); err != nil {
-		return false, CloseOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CloseOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, actor, c.Out, remaining, true); err != nil {
-		return false, CloseOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CloseOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, CloseOrderComputeUnits, nil, nil, nil
 }
@@ -0,0 +1,125 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*CloseStream)(nil)
+
+// CloseStream stops [Stream] (which only its payer can do), pays its payee
+// everything streamed that it hasn't withdrawn yet, and refunds the rest of
+// the deposit to the payer.
+type CloseStream struct {
+	// [Stream] is the ID of the transaction that opened the stream.
+	Stream ids.ID `json:"stream"`
+
+	// [Payee] and [Asset] are the recipient and asset of the stream. We need
+	// to provide these to populate [StateKeys].
+	Payee codec.Address `json:"payee"`
+	Asset ids.ID        `json:"asset"`
+}
+
+func (*CloseStream) GetTypeID() uint8 {
+	return closeStreamID
+}
+
+func (c *CloseStream) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.StreamKey(c.Stream)),
+		string(storage.BalanceKey(actor, c.Asset)),
+		string(storage.BalanceKey(c.Payee, c.Asset)),
+	}
+}
+
+func (*CloseStream) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.StreamChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*CloseStream) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *CloseStream) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, payer, payee, asset, rate, start, deposit, withdrawn, err := storage.GetStream(ctx, mu, c.Stream)
+	if err != nil {
+		return false, CloseStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, CloseStreamComputeUnits, OutputStreamMissing, nil, nil
+	}
+	if payer != actor {
+		return false, CloseStreamComputeUnits, OutputUnauthorized, nil, nil
+	}
+	if payee != c.Payee {
+		return false, CloseStreamComputeUnits, OutputWrongPayee, nil, nil
+	}
+	if asset != c.Asset {
+		return false, CloseStreamComputeUnits, OutputWrongAsset, nil, nil
+	}
+	var (
+		streamed = storage.Streamed(rate, start, deposit, timestamp)
+		owed     = streamed - withdrawn
+		refund   = deposit - streamed
+	)
+	if err := storage.DeleteStream(ctx, mu, c.Stream); err != nil {
+		return false, CloseStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if owed > 0 {
+		if err := storage.AddBalance(ctx, mu, payee, asset, owed, true); err != nil {
+			return false, CloseStreamComputeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if refund > 0 {
+		if err := storage.AddBalance(ctx, mu, actor, asset, refund, true); err != nil {
+			return false, CloseStreamComputeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	return true, CloseStreamComputeUnits, nil, nil, nil
+}
+
+func (*CloseStream) MaxComputeUnits(chain.Rules) uint64 {
+	return CloseStreamComputeUnits
+}
+
+func (*CloseStream) Size() int {
+	return consts.IDLen*2 + codec.AddressLen
+}
+
+func (c *CloseStream) Marshal(p *codec.Packer) {
+	p.PackID(c.Stream)
+	p.PackAddress(c.Payee)
+	p.PackID(c.Asset)
+}
+
+func UnmarshalCloseStream(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var cl CloseStream
+	p.UnpackID(true, &cl.Stream)
+	p.UnpackAddress(&cl.Payee)
+	p.UnpackID(false, &cl.Asset) // empty ID is the native asset
+	return &cl, p.Err()
+}
+
+func (*CloseStream) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
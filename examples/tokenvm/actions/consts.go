@@ -5,29 +5,53 @@ package actions
 
 // Note: Registry will error during initialization if a duplicate ID is assigned. We explicitly assign IDs to avoid accidental remapping.
 const (
-	burnAssetID   uint8 = 0
-	closeOrderID  uint8 = 1
-	createAssetID uint8 = 2
-	exportAssetID uint8 = 3
-	importAssetID uint8 = 4
-	createOrderID uint8 = 5
-	fillOrderID   uint8 = 6
-	mintAssetID   uint8 = 7
-	transferID    uint8 = 8
+	burnAssetID       uint8 = 0
+	closeOrderID      uint8 = 1
+	createAssetID     uint8 = 2
+	exportAssetID     uint8 = 3
+	importAssetID     uint8 = 4
+	createOrderID     uint8 = 5
+	fillOrderID       uint8 = 6
+	mintAssetID       uint8 = 7
+	transferID        uint8 = 8
+	approveID         uint8 = 9
+	transferFromID    uint8 = 10
+	updateFeeRateID   uint8 = 11
+	createPoolID      uint8 = 12
+	addLiquidityID    uint8 = 13
+	removeLiquidityID uint8 = 14
+	swapExactID       uint8 = 15
+	openStreamID      uint8 = 16
+	withdrawStreamID  uint8 = 17
+	closeStreamID     uint8 = 18
 )
 
 const (
 	// TODO: tune this
-	BurnComputeUnits        = 2
-	CloseOrderComputeUnits  = 5
-	CreateAssetComputeUnits = 10
-	ExportAssetComputeUnits = 10
-	ImportAssetComputeUnits = 10
-	CreateOrderComputeUnits = 5
-	NoFillOrderComputeUnits = 5
-	FillOrderComputeUnits   = 15
-	MintAssetComputeUnits   = 2
-	TransferComputeUnits    = 1
+	BurnComputeUnits            = 2
+	CloseOrderComputeUnits      = 5
+	CreateAssetComputeUnits     = 10
+	ExportAssetComputeUnits     = 10
+	ImportAssetComputeUnits     = 10
+	CreateOrderComputeUnits     = 5
+	NoFillOrderComputeUnits     = 5
+	FillOrderComputeUnits       = 15
+	MintAssetComputeUnits       = 2
+	TransferComputeUnits        = 1
+	ApproveComputeUnits         = 1
+	TransferFromComputeUnits    = 2
+	UpdateFeeRateComputeUnits   = 1
+	CreatePoolComputeUnits      = 10
+	AddLiquidityComputeUnits    = 5
+	RemoveLiquidityComputeUnits = 5
+	SwapExactComputeUnits       = 5
+	OpenStreamComputeUnits      = 2
+	WithdrawStreamComputeUnits  = 2
+	CloseStreamComputeUnits     = 3
+
+	// PoolFeeBasisPoints is the share of the input of a swap that is kept
+	// by the pool (and accrues to its liquidity providers).
+	PoolFeeBasisPoints = 30
 
 	MaxSymbolSize   = 8
 	MaxMemoSize     = 256
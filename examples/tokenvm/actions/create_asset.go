@@ -13,7 +13,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*CreateAsset)(nil)
@@ -69,7 +68,7 @@ func (c *CreateAsset) Execute(
 	// It should only be possible to overwrite an existing asset if there is
 	// a hash collision.
 	if err := storage.SetAsset(ctx, mu, txID, c.Symbol, c.Decimals, c.Metadata, 0, actor, false); err != nil {
-		return false, CreateAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CreateAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, CreateAssetComputeUnits, nil, nil, nil
 }
@@ -14,7 +14,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*CreateOrder)(nil)
@@ -91,10 +90,10 @@ func (c *CreateOrder) Execute(
 		return false, CreateOrderComputeUnits, OutputSupplyMisaligned, nil, nil
 	}
 	if err := storage.SubBalance(ctx, mu, actor, c.Out, c.Supply); err != nil {
-		return false, CreateOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CreateOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.SetOrder(ctx, mu, txID, c.In, c.InTick, c.Out, c.OutTick, c.Supply, actor); err != nil {
-		return false, CreateOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, CreateOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, CreateOrderComputeUnits, nil, nil, nil
 }
@@ -0,0 +1,125 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*CreatePool)(nil)
+
+// CreatePool creates a constant-product pool that trades [AssetA] for
+// [AssetB] (there can only be one for each pair of assets).
+type CreatePool struct {
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+
+	// [AmountA] and [AmountB] are the initial reserves of the pool, which
+	// set its price.
+	AmountA uint64 `json:"amountA"`
+	AmountB uint64 `json:"amountB"`
+}
+
+func (*CreatePool) GetTypeID() uint8 {
+	return createPoolID
+}
+
+func (c *CreatePool) StateKeys(actor codec.Address, _ ids.ID) []string {
+	pool := storage.PoolID(c.AssetA, c.AssetB)
+	return []string{
+		string(storage.PoolKey(pool)),
+		string(storage.LiquidityKey(pool, actor)),
+		string(storage.BalanceKey(actor, c.AssetA)),
+		string(storage.BalanceKey(actor, c.AssetB)),
+	}
+}
+
+func (*CreatePool) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.LiquidityChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*CreatePool) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *CreatePool) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if c.AssetA == c.AssetB {
+		return false, CreatePoolComputeUnits, OutputSameInOut, nil, nil
+	}
+	if c.AmountA == 0 || c.AmountB == 0 {
+		return false, CreatePoolComputeUnits, OutputValueZero, nil, nil
+	}
+	exists, _, _, _, err := storage.GetPool(ctx, mu, c.AssetA, c.AssetB)
+	if err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if exists {
+		return false, CreatePoolComputeUnits, OutputPoolExists, nil, nil
+	}
+	shares := initialShares(c.AmountA, c.AmountB)
+	if err := storage.SubBalance(ctx, mu, actor, c.AssetA, c.AmountA); err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SubBalance(ctx, mu, actor, c.AssetB, c.AmountB); err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SetPool(ctx, mu, c.AssetA, c.AssetB, c.AmountA, c.AmountB, shares); err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SetLiquidity(ctx, mu, storage.PoolID(c.AssetA, c.AssetB), actor, shares); err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	lr := &LiquidityResult{AmountA: c.AmountA, AmountB: c.AmountB, Shares: shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return false, CreatePoolComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, CreatePoolComputeUnits, output, nil, nil
+}
+
+func (*CreatePool) MaxComputeUnits(chain.Rules) uint64 {
+	return CreatePoolComputeUnits
+}
+
+func (*CreatePool) Size() int {
+	return consts.IDLen*2 + consts.Uint64Len*2
+}
+
+func (c *CreatePool) Marshal(p *codec.Packer) {
+	p.PackID(c.AssetA)
+	p.PackID(c.AssetB)
+	p.PackUint64(c.AmountA)
+	p.PackUint64(c.AmountB)
+}
+
+func UnmarshalCreatePool(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var create CreatePool
+	p.UnpackID(false, &create.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &create.AssetB) // empty ID is the native asset
+	create.AmountA = p.UnpackUint64(true)
+	create.AmountB = p.UnpackUint64(true)
+	return &create, p.Err()
+}
+
+func (*CreatePool) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
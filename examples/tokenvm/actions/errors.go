@@ -3,6 +3,17 @@
 
 package actions
 
-import "errors"
+import (
+	"errors"
 
-var ErrNoSwapToFill = errors.New("no swap to fill")
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+var (
+	ErrNoSwapToFill   = errors.New("no swap to fill")
+	ErrUnbackedSupply = errors.New("bridged supply is not backed by locked assets")
+
+	// ErrAssetMissing fails an action with [chain.StatusAssetMissing] (and
+	// [OutputAssetMissing] as its output).
+	ErrAssetMissing = chain.NewStatusError(chain.StatusAssetMissing, errors.New(string(OutputAssetMissing)))
+)
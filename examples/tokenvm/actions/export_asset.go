@@ -15,7 +15,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*ExportAsset)(nil)
@@ -65,54 +64,55 @@ func (*ExportAsset) OutputsWarpMessage() bool {
 func (e *ExportAsset) executeReturn(
 	ctx context.Context,
 	mu state.Mutable,
+	timestamp int64,
 	actor codec.Address,
 	txID ids.ID,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	exists, symbol, decimals, metadata, supply, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
-		return false, ExportAssetComputeUnits, OutputAssetMissing, nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, ErrAssetMissing
 	}
 	if !isWarp {
 		return false, ExportAssetComputeUnits, OutputNotWarpAsset, nil, nil
 	}
 	allowedDestination, err := ids.ToID(metadata[consts.IDLen:])
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if allowedDestination != e.Destination {
 		return false, ExportAssetComputeUnits, OutputWrongDestination, nil, nil
 	}
 	newSupply, err := smath.Sub(supply, e.Value)
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	newSupply, err = smath.Sub(newSupply, e.Reward)
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if newSupply > 0 {
 		if err := storage.SetAsset(ctx, mu, e.Asset, symbol, decimals, metadata, newSupply, codec.EmptyAddress, true); err != nil {
-			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 		}
 	} else {
 		if err := storage.DeleteAsset(ctx, mu, e.Asset); err != nil {
-			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 		}
 	}
 	if err := storage.SubBalance(ctx, mu, actor, e.Asset, e.Value); err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if e.Reward > 0 {
 		if err := storage.SubBalance(ctx, mu, actor, e.Asset, e.Reward); err != nil {
-			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 		}
 	}
 	originalAsset, err := ids.ToID(metadata[:consts.IDLen])
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	wt := &WarpTransfer{
 		To:                 e.To,
@@ -128,10 +128,11 @@ func (e *ExportAsset) executeReturn(
 		SwapExpiry:         e.SwapExpiry,
 		TxID:               txID,
 		DestinationChainID: e.Destination,
+		Timestamp:          timestamp,
 	}
 	payload, err := wt.Marshal()
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	wm := &warp.UnsignedMessage{
 		// NetworkID + SourceChainID is populated by hypersdk
@@ -143,32 +144,33 @@ func (e *ExportAsset) executeReturn(
 func (e *ExportAsset) executeLoan(
 	ctx context.Context,
 	mu state.Mutable,
+	timestamp int64,
 	actor codec.Address,
 	txID ids.ID,
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	exists, symbol, decimals, _, _, _, isWarp, err := storage.GetAsset(ctx, mu, e.Asset)
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
-		return false, ExportAssetComputeUnits, OutputAssetMissing, nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, ErrAssetMissing
 	}
 	if isWarp {
 		// Cannot export an asset if it was warped in and not returning
 		return false, ExportAssetComputeUnits, OutputWarpAsset, nil, nil
 	}
 	if err := storage.AddLoan(ctx, mu, e.Asset, e.Destination, e.Value); err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.SubBalance(ctx, mu, actor, e.Asset, e.Value); err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if e.Reward > 0 {
 		if err := storage.AddLoan(ctx, mu, e.Asset, e.Destination, e.Reward); err != nil {
-			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 		}
 		if err := storage.SubBalance(ctx, mu, actor, e.Asset, e.Reward); err != nil {
-			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 		}
 	}
 	wt := &WarpTransfer{
@@ -185,10 +187,11 @@ func (e *ExportAsset) executeLoan(
 		SwapExpiry:         e.SwapExpiry,
 		TxID:               txID,
 		DestinationChainID: e.Destination,
+		Timestamp:          timestamp,
 	}
 	payload, err := wt.Marshal()
 	if err != nil {
-		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ExportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	wm := &warp.UnsignedMessage{
 		// NetworkID + SourceChainID is populated by hypersdk
@@ -199,9 +202,9 @@ func (e *ExportAsset) executeLoan(
 
 func (e *ExportAsset) Execute(
 	ctx context.Context,
-	_ chain.Rules,
+	r chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
 	txID ids.ID,
 	_ bool,
@@ -214,11 +217,15 @@ func (e *ExportAsset) Execute(
 		// transaction.
 		return false, ExportAssetComputeUnits, OutputAnycast, nil, nil
 	}
-	// TODO: check if destination is ourselves
+	if e.Destination == r.ChainID() {
+		// Assets exported to ourselves could be imported as a copy of
+		// themselves, which would never be reconciled with their loan.
+		return false, ExportAssetComputeUnits, OutputInvalidDestination, nil, nil
+	}
 	if e.Return {
-		return e.executeReturn(ctx, mu, actor, txID)
+		return e.executeReturn(ctx, mu, timestamp, actor, txID)
 	}
-	return e.executeLoan(ctx, mu, actor, txID)
+	return e.executeLoan(ctx, mu, timestamp, actor, txID)
 }
 
 func (*ExportAsset) MaxComputeUnits(chain.Rules) uint64 {
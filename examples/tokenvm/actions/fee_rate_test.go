@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestUpdateFeeRateConformance(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		asset     = ids.GenerateTestID()
+		paymaster = codec.CreateAddress(0, ids.GenerateTestID())
+		other     = codec.CreateAddress(0, ids.GenerateTestID())
+		g         = genesis.Default()
+
+		// Orders that sell the native asset for [asset] at 3 for 2
+		order      = ids.GenerateTestID()
+		otherOrder = ids.GenerateTestID()
+		// Order that sells [asset] for the native asset
+		reverseOrder = ids.GenerateTestID()
+		s            = chaintest.State{}
+	)
+	g.FeeAssets = []*genesis.FeeAsset{{Asset: asset, Paymaster: paymaster}}
+	rules := g.Rules(0, 1, ids.GenerateTestID())
+	require.NoError(t, storage.SetOrder(ctx, s, order, asset, 3, ids.Empty, 2, 10, paymaster))
+	require.NoError(t, storage.SetOrder(ctx, s, otherOrder, asset, 3, ids.Empty, 2, 10, other))
+	require.NoError(t, storage.SetOrder(ctx, s, reverseOrder, ids.Empty, 2, asset, 3, 9, paymaster))
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "update fee rate",
+			Action:          &UpdateFeeRate{Asset: asset, Order: order},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           paymaster,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "not paymaster",
+			Action:          &UpdateFeeRate{Asset: asset, Order: otherOrder},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           other,
+			ExpectedOutput:  OutputNotPaymaster,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "asset not allowed",
+			Action:          &UpdateFeeRate{Asset: ids.GenerateTestID(), Order: order},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           paymaster,
+			ExpectedOutput:  OutputFeeAssetNotAllowed,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "order missing",
+			Action:          &UpdateFeeRate{Asset: asset, Order: ids.GenerateTestID()},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           paymaster,
+			ExpectedOutput:  OutputOrderMissing,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "order of another account",
+			Action:          &UpdateFeeRate{Asset: asset, Order: otherOrder},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           paymaster,
+			ExpectedOutput:  OutputWrongOwner,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "order sells asset",
+			Action:          &UpdateFeeRate{Asset: asset, Order: reverseOrder},
+			Unmarshal:       UnmarshalUpdateFeeRate,
+			Actor:           paymaster,
+			ExpectedOutput:  OutputWrongIn,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:                 "no order",
+			Action:               &UpdateFeeRate{Asset: asset},
+			Unmarshal:            UnmarshalUpdateFeeRate,
+			ExpectedUnmarshalErr: codec.ErrFieldNotPopulated,
+			Actor:                paymaster,
+			ExpectedOutput:       OutputOrderMissing,
+			ExpectedSuccess:      false,
+		},
+	} {
+		test.Rules = rules
+		test.State = s
+		test.TxID = ids.GenerateTestID()
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestUpdateFeeRateFromOrder(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var (
+		asset     = ids.GenerateTestID()
+		paymaster = codec.CreateAddress(0, ids.GenerateTestID())
+		order     = ids.GenerateTestID()
+		g         = genesis.Default()
+		s         = chaintest.State{}
+	)
+	g.FeeAssets = []*genesis.FeeAsset{{Asset: asset, Paymaster: paymaster}}
+	rules := g.Rules(0, 1, ids.GenerateTestID())
+	require.NoError(storage.SetOrder(ctx, s, order, asset, 3, ids.Empty, 2, 10, paymaster))
+
+	// The rate is the price of the order: 3 of [asset] for 2 of the native
+	// asset
+	action := &UpdateFeeRate{Asset: asset, Order: order}
+	success, _, output, _, err := action.Execute(ctx, rules, s, 1_000, paymaster, ids.Empty, false)
+	require.NoError(err)
+	require.True(success, string(output))
+	exists, posted, native, units, err := storage.GetFeeRate(ctx, s, asset)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(int64(1_000), posted)
+	require.Equal(uint64(2), native)
+	require.Equal(uint64(3), units)
+}
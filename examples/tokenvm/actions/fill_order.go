@@ -15,7 +15,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*FillOrder)(nil)
@@ -72,7 +71,7 @@ func (f *FillOrder) Execute(
 ) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
 	exists, in, inTick, out, outTick, remaining, owner, err := storage.GetOrder(ctx, mu, f.Order)
 	if err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
 		return false, NoFillOrderComputeUnits, OutputOrderMissing, nil, nil
@@ -97,7 +96,7 @@ func (f *FillOrder) Execute(
 	// successful.
 	outputAmount, err := smath.Mul64(outTick, f.Value/inTick)
 	if err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if outputAmount == 0 {
 		// This should never happen because [f.Value] > 0
@@ -130,27 +129,27 @@ func (f *FillOrder) Execute(
 		return false, NoFillOrderComputeUnits, OutputInsufficientInput, nil, nil
 	}
 	if err := storage.SubBalance(ctx, mu, actor, f.In, inputAmount); err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, f.Owner, f.In, inputAmount, true); err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, actor, f.Out, outputAmount, true); err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if shouldDelete {
 		if err := storage.DeleteOrder(ctx, mu, f.Order); err != nil {
-			return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 		}
 	} else {
 		if err := storage.SetOrder(ctx, mu, f.Order, in, inTick, out, outTick, orderRemaining, owner); err != nil {
-			return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+			return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 		}
 	}
 	or := &OrderResult{In: inputAmount, Out: outputAmount, Remaining: orderRemaining}
 	output, err := or.Marshal()
 	if err != nil {
-		return false, NoFillOrderComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, NoFillOrderComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, FillOrderComputeUnits, output, nil, nil
 }
@@ -16,10 +16,12 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
-var _ chain.Action = (*ImportAsset)(nil)
+var (
+	_ chain.Action          = (*ImportAsset)(nil)
+	_ chain.WarpTimestamper = (*ImportAsset)(nil)
+)
 
 type ImportAsset struct {
 	// Fill indicates if the actor wishes to fill the order request in the warp
@@ -103,15 +105,15 @@ func (i *ImportAsset) executeMint(
 	ctx context.Context,
 	mu state.Mutable,
 	actor codec.Address,
-) []byte {
+) ([]byte, error) {
 	asset := ImportedAssetID(i.warpTransfer.Asset, i.warpMessage.SourceChainID)
 	exists, symbol, decimals, metadata, supply, _, warp, err := storage.GetAsset(ctx, mu, asset)
 	if err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if exists && !warp {
 		// Should not be possible
-		return OutputConflictingAsset
+		return OutputConflictingAsset, nil
 	}
 	if !exists {
 		symbol = i.warpTransfer.Symbol
@@ -120,76 +122,76 @@ func (i *ImportAsset) executeMint(
 	}
 	newSupply, err := smath.Add64(supply, i.warpTransfer.Value)
 	if err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	newSupply, err = smath.Add64(newSupply, i.warpTransfer.Reward)
 	if err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if err := storage.SetAsset(ctx, mu, asset, symbol, decimals, metadata, newSupply, codec.EmptyAddress, true); err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if err := storage.AddBalance(ctx, mu, i.warpTransfer.To, asset, i.warpTransfer.Value, true); err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if i.warpTransfer.Reward > 0 {
 		if err := storage.AddBalance(ctx, mu, actor, asset, i.warpTransfer.Reward, true); err != nil {
-			return utils.ErrBytes(err)
+			return nil, err
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 func (i *ImportAsset) executeReturn(
 	ctx context.Context,
 	mu state.Mutable,
 	actor codec.Address,
-) []byte {
+) ([]byte, error) {
 	exists, symbol, decimals, _, _, _, warp, err := storage.GetAsset(ctx, mu, i.warpTransfer.Asset)
 	if err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if !exists {
-		return OutputAssetMissing
+		return nil, ErrAssetMissing
 	}
 	if !bytes.Equal(i.warpTransfer.Symbol, symbol) {
-		return OutputSymbolIncorrect
+		return OutputSymbolIncorrect, nil
 	}
 	if i.warpTransfer.Decimals != decimals {
-		return OutputDecimalsIncorrect
+		return OutputDecimalsIncorrect, nil
 	}
 	if warp {
-		return OutputWarpAsset
+		return OutputWarpAsset, nil
 	}
 	if err := storage.SubLoan(
 		ctx, mu, i.warpTransfer.Asset,
 		i.warpMessage.SourceChainID, i.warpTransfer.Value,
 	); err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if err := storage.AddBalance(
 		ctx, mu, i.warpTransfer.To,
 		i.warpTransfer.Asset, i.warpTransfer.Value,
 		true,
 	); err != nil {
-		return utils.ErrBytes(err)
+		return nil, err
 	}
 	if i.warpTransfer.Reward > 0 {
 		if err := storage.SubLoan(
 			ctx, mu, i.warpTransfer.Asset,
 			i.warpMessage.SourceChainID, i.warpTransfer.Reward,
 		); err != nil {
-			return utils.ErrBytes(err)
+			return nil, err
 		}
 		if err := storage.AddBalance(
 			ctx, mu, actor,
 			i.warpTransfer.Asset, i.warpTransfer.Reward,
 			true,
 		); err != nil {
-			return utils.ErrBytes(err)
+			return nil, err
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 func (i *ImportAsset) Execute(
@@ -210,11 +212,17 @@ func (i *ImportAsset) Execute(
 	if i.warpTransfer.Value == 0 {
 		return false, ImportAssetComputeUnits, OutputValueZero, nil, nil
 	}
-	var output []byte
+	var (
+		output []byte
+		err    error
+	)
 	if i.warpTransfer.Return {
-		output = i.executeReturn(ctx, mu, actor)
+		output, err = i.executeReturn(ctx, mu, actor)
 	} else {
-		output = i.executeMint(ctx, mu, actor)
+		output, err = i.executeMint(ctx, mu, actor)
+	}
+	if err != nil {
+		return false, ImportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if len(output) > 0 {
 		return false, ImportAssetComputeUnits, output, nil, nil
@@ -237,16 +245,16 @@ func (i *ImportAsset) Execute(
 		assetIn = ImportedAssetID(i.warpTransfer.Asset, i.warpMessage.SourceChainID)
 	}
 	if err := storage.SubBalance(ctx, mu, i.warpTransfer.To, assetIn, i.warpTransfer.SwapIn); err != nil {
-		return false, ImportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ImportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, actor, assetIn, i.warpTransfer.SwapIn, true); err != nil {
-		return false, ImportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ImportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.SubBalance(ctx, mu, actor, i.warpTransfer.AssetOut, i.warpTransfer.SwapOut); err != nil {
-		return false, ImportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ImportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, i.warpTransfer.To, i.warpTransfer.AssetOut, i.warpTransfer.SwapOut, true); err != nil {
-		return false, ImportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, ImportAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, ImportAssetComputeUnits, nil, nil, nil
 }
@@ -286,6 +294,14 @@ func UnmarshalImportAsset(p *codec.Packer, wm *warp.Message) (chain.Action, erro
 	return &imp, nil
 }
 
+// WarpTimestamp returns when the [WarpTransfer] being imported was exported.
+func (i *ImportAsset) WarpTimestamp(*warp.Message) (int64, bool) {
+	if i.warpTransfer == nil {
+		return 0, false
+	}
+	return i.warpTransfer.Timestamp, true
+}
+
 func (*ImportAsset) ValidRange(chain.Rules) (int64, int64) {
 	// Returning -1, -1 means that the action is always valid.
 	return -1, -1
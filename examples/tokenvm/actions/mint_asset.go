@@ -15,7 +15,6 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
-	"github.com/ava-labs/hypersdk/utils"
 )
 
 var _ chain.Action = (*MintAsset)(nil)
@@ -67,10 +66,10 @@ func (m *MintAsset) Execute(
 	}
 	exists, symbol, decimals, metadata, supply, owner, isWarp, err := storage.GetAsset(ctx, mu, m.Asset)
 	if err != nil {
-		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, MintAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if !exists {
-		return false, MintAssetComputeUnits, OutputAssetMissing, nil, nil
+		return false, MintAssetComputeUnits, nil, nil, ErrAssetMissing
 	}
 	if isWarp {
 		return false, MintAssetComputeUnits, OutputWarpAsset, nil, nil
@@ -80,13 +79,13 @@ func (m *MintAsset) Execute(
 	}
 	newSupply, err := smath.Add64(supply, m.Value)
 	if err != nil {
-		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, MintAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.SetAsset(ctx, mu, m.Asset, symbol, decimals, metadata, newSupply, actor, isWarp); err != nil {
-		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, MintAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	if err := storage.AddBalance(ctx, mu, m.To, m.Asset, m.Value, true); err != nil {
-		return false, MintAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		return false, MintAssetComputeUnits, nil, nil, chain.Fail(err)
 	}
 	return true, MintAssetComputeUnits, nil, nil, nil
 }
@@ -0,0 +1,113 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*OpenStream)(nil)
+
+// OpenStream locks up [Deposit] of [Asset] and pays it to [Payee] at [Rate]
+// per second (starting at the timestamp of the block that includes it) until
+// it runs out or the stream is closed. The stream is identified by the ID of
+// the transaction that opens it.
+type OpenStream struct {
+	// [Payee] can withdraw the amount streamed so far at any time.
+	Payee codec.Address `json:"payee"`
+
+	Asset ids.ID `json:"asset"`
+
+	// [Rate] is the amount of [Asset] streamed each second.
+	Rate uint64 `json:"rate"`
+
+	// [Deposit] is the most that will be streamed.
+	Deposit uint64 `json:"deposit"`
+}
+
+func (*OpenStream) GetTypeID() uint8 {
+	return openStreamID
+}
+
+func (o *OpenStream) StateKeys(actor codec.Address, txID ids.ID) []string {
+	return []string{
+		string(storage.StreamKey(txID)),
+		string(storage.BalanceKey(actor, o.Asset)),
+	}
+}
+
+func (*OpenStream) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.StreamChunks, storage.BalanceChunks}
+}
+
+func (*OpenStream) OutputsWarpMessage() bool {
+	return false
+}
+
+func (o *OpenStream) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if o.Payee == actor {
+		return false, OpenStreamComputeUnits, OutputSelfStream, nil, nil
+	}
+	if o.Rate == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return false, OpenStreamComputeUnits, OutputRateZero, nil, nil
+	}
+	if o.Deposit == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return false, OpenStreamComputeUnits, OutputValueZero, nil, nil
+	}
+	if err := storage.SubBalance(ctx, mu, actor, o.Asset, o.Deposit); err != nil {
+		return false, OpenStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SetStream(ctx, mu, txID, actor, o.Payee, o.Asset, o.Rate, timestamp, o.Deposit, 0); err != nil {
+		return false, OpenStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, OpenStreamComputeUnits, nil, nil, nil
+}
+
+func (*OpenStream) MaxComputeUnits(chain.Rules) uint64 {
+	return OpenStreamComputeUnits
+}
+
+func (*OpenStream) Size() int {
+	return codec.AddressLen + consts.IDLen + consts.Uint64Len*2
+}
+
+func (o *OpenStream) Marshal(p *codec.Packer) {
+	p.PackAddress(o.Payee)
+	p.PackID(o.Asset)
+	p.PackUint64(o.Rate)
+	p.PackUint64(o.Deposit)
+}
+
+func UnmarshalOpenStream(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var open OpenStream
+	p.UnpackAddress(&open.Payee)
+	p.UnpackID(false, &open.Asset) // empty ID is the native asset
+	open.Rate = p.UnpackUint64(true)
+	open.Deposit = p.UnpackUint64(true)
+	return &open, p.Err()
+}
+
+func (*OpenStream) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
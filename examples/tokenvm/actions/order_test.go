@@ -0,0 +1,67 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestOrderConformance(t *testing.T) {
+	var (
+		rules = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		actor = codec.CreateAddress(0, ids.GenerateTestID())
+		asset = ids.GenerateTestID()
+		state = map[string][]byte{
+			string(storage.BalanceKey(actor, ids.Empty)): binary.BigEndian.AppendUint64(nil, 100),
+		}
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name: "create order",
+			Action: &CreateOrder{
+				In:      asset,
+				InTick:  1,
+				Out:     ids.Empty,
+				OutTick: 2,
+				Supply:  10,
+			},
+			Unmarshal:       UnmarshalCreateOrder,
+			ExpectedSuccess: true,
+		},
+		{
+			Name: "create order misaligned supply",
+			Action: &CreateOrder{
+				In:      asset,
+				InTick:  1,
+				Out:     ids.Empty,
+				OutTick: 3,
+				Supply:  10,
+			},
+			Unmarshal:       UnmarshalCreateOrder,
+			ExpectedOutput:  OutputSupplyMisaligned,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "close missing order",
+			Action:          &CloseOrder{Order: ids.GenerateTestID(), Out: ids.Empty},
+			Unmarshal:       UnmarshalCloseOrder,
+			ExpectedOutput:  OutputOrderMissing,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Rules = rules
+		test.State = state
+		test.Actor = actor
+		test.TxID = ids.GenerateTestID()
+		chaintest.RunActionTest(t, test)
+	}
+}
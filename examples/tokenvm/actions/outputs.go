@@ -37,4 +37,17 @@ var (
 	OutputMustFill               = []byte("must fill request")
 	OutputWarpVerificationFailed = []byte("warp verification failed")
 	OutputInvalidDestination     = []byte("invalid destination")
+	OutputSelfApproval           = []byte("cannot approve self")
+	OutputFeeAssetNotAllowed     = []byte("fees cannot be paid in asset")
+	OutputNotPaymaster           = []byte("actor is not paymaster")
+	OutputPoolExists             = []byte("pool already exists")
+	OutputPoolMissing            = []byte("pool missing")
+	OutputInsufficientLiquidity  = []byte("insufficient liquidity")
+	OutputBelowMinimum           = []byte("amount below minimum")
+	OutputStreamMissing          = []byte("stream missing")
+	OutputRateZero               = []byte("rate is zero")
+	OutputSelfStream             = []byte("cannot stream to self")
+	OutputWrongAsset             = []byte("wrong asset")
+	OutputWrongPayee             = []byte("wrong payee")
+	OutputNothingToWithdraw      = []byte("nothing to withdraw")
 )
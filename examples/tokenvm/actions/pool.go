@@ -0,0 +1,89 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"math/big"
+	"math/bits"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// mulDiv returns [a]*[b]/[d] (rounded down) without overflowing the
+// intermediate product.
+func mulDiv(a uint64, b uint64, d uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= d {
+		return 0, smath.ErrOverflow
+	}
+	quo, _ := bits.Div64(hi, lo, d)
+	return quo, nil
+}
+
+// mulDivUp is like [mulDiv] but rounds up.
+func mulDivUp(a uint64, b uint64, d uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= d {
+		return 0, smath.ErrOverflow
+	}
+	quo, rem := bits.Div64(hi, lo, d)
+	if rem == 0 {
+		return quo, nil
+	}
+	return smath.Add64(quo, 1)
+}
+
+// initialShares returns the shares minted when a pool is created with
+// [amountA] and [amountB] (their geometric mean, so that the value of a share
+// doesn't depend on the order of the assets).
+func initialShares(amountA uint64, amountB uint64) uint64 {
+	product := new(big.Int).Mul(new(big.Int).SetUint64(amountA), new(big.Int).SetUint64(amountB))
+	// The square root of a product of 2 uint64s always fits in a uint64
+	return new(big.Int).Sqrt(product).Uint64()
+}
+
+// swapOutput returns the amount of the out asset received for [value] of the
+// in asset from a pool with [reserveIn] and [reserveOut], after the pool
+// keeps [PoolFeeBasisPoints] of [value].
+func swapOutput(value uint64, reserveIn uint64, reserveOut uint64) (uint64, error) {
+	valueAfterFee, err := mulDiv(value, 10_000-PoolFeeBasisPoints, 10_000)
+	if err != nil {
+		return 0, err
+	}
+	// Keep [reserveIn]*[reserveOut] constant (before fees)
+	newReserveIn, err := smath.Add64(reserveIn, valueAfterFee)
+	if err != nil {
+		return 0, err
+	}
+	return mulDiv(valueAfterFee, reserveOut, newReserveIn)
+}
+
+// LiquidityResult is the output of a successful CreatePool, AddLiquidity, or
+// RemoveLiquidity with the amounts of each asset moved and the shares minted
+// or burned.
+type LiquidityResult struct {
+	AmountA uint64 `json:"amountA"`
+	AmountB uint64 `json:"amountB"`
+	Shares  uint64 `json:"shares"`
+}
+
+func UnmarshalLiquidityResult(b []byte) (*LiquidityResult, error) {
+	p := codec.NewReader(b, consts.Uint64Len*3)
+	var result LiquidityResult
+	result.AmountA = p.UnpackUint64(false)
+	result.AmountB = p.UnpackUint64(false)
+	result.Shares = p.UnpackUint64(true)
+	return &result, p.Err()
+}
+
+func (l *LiquidityResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.Uint64Len*3, consts.Uint64Len*3)
+	p.PackUint64(l.AmountA)
+	p.PackUint64(l.AmountB)
+	p.PackUint64(l.Shares)
+	return p.Bytes(), p.Err()
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestPoolConformance(t *testing.T) {
+	var (
+		rules  = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		actor  = codec.CreateAddress(0, ids.GenerateTestID())
+		asset  = ids.GenerateTestID()
+		native = ids.Empty
+		pool   = storage.PoolID(native, asset)
+
+		balances = map[string][]byte{
+			string(storage.BalanceKey(actor, native)): binary.BigEndian.AppendUint64(nil, 10_000),
+			string(storage.BalanceKey(actor, asset)):  binary.BigEndian.AppendUint64(nil, 10_000),
+		}
+		// 1000 native and 4000 [asset] with 2000 shares owned by [actor]
+		withPool = map[string][]byte{
+			string(storage.PoolKey(pool)):             poolValue(1000, 4000, 2000),
+			string(storage.LiquidityKey(pool, actor)): binary.BigEndian.AppendUint64(nil, 2000),
+			string(storage.BalanceKey(actor, native)): binary.BigEndian.AppendUint64(nil, 10_000),
+			string(storage.BalanceKey(actor, asset)):  binary.BigEndian.AppendUint64(nil, 10_000),
+		}
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "create pool",
+			Action:          &CreatePool{AssetA: native, AssetB: asset, AmountA: 100, AmountB: 400},
+			Unmarshal:       UnmarshalCreatePool,
+			State:           balances,
+			ExpectedOutput:  liquidityOutput(100, 400, 200),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "create existing pool",
+			Action:          &CreatePool{AssetA: asset, AssetB: native, AmountA: 100, AmountB: 400},
+			Unmarshal:       UnmarshalCreatePool,
+			State:           withPool,
+			ExpectedOutput:  OutputPoolExists,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "create pool with same assets",
+			Action:          &CreatePool{AssetA: asset, AssetB: asset, AmountA: 100, AmountB: 400},
+			Unmarshal:       UnmarshalCreatePool,
+			State:           balances,
+			ExpectedOutput:  OutputSameInOut,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "add liquidity",
+			Action:          &AddLiquidity{AssetA: native, AssetB: asset, MaxA: 100, MaxB: 1000},
+			Unmarshal:       UnmarshalAddLiquidity,
+			State:           withPool,
+			ExpectedOutput:  liquidityOutput(100, 400, 200),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "add liquidity in reverse order",
+			Action:          &AddLiquidity{AssetA: asset, AssetB: native, MaxA: 1000, MaxB: 100},
+			Unmarshal:       UnmarshalAddLiquidity,
+			State:           withPool,
+			ExpectedOutput:  liquidityOutput(400, 100, 200),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "add liquidity below min shares",
+			Action:          &AddLiquidity{AssetA: native, AssetB: asset, MaxA: 100, MaxB: 1000, MinShares: 201},
+			Unmarshal:       UnmarshalAddLiquidity,
+			State:           withPool,
+			ExpectedOutput:  OutputBelowMinimum,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "add liquidity to missing pool",
+			Action:          &AddLiquidity{AssetA: native, AssetB: asset, MaxA: 100, MaxB: 1000},
+			Unmarshal:       UnmarshalAddLiquidity,
+			State:           balances,
+			ExpectedOutput:  OutputPoolMissing,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "remove liquidity",
+			Action:          &RemoveLiquidity{AssetA: native, AssetB: asset, Shares: 1000},
+			Unmarshal:       UnmarshalRemoveLiquidity,
+			State:           withPool,
+			ExpectedOutput:  liquidityOutput(500, 2000, 1000),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "remove all liquidity",
+			Action:          &RemoveLiquidity{AssetA: native, AssetB: asset, Shares: 2000, MinA: 1000, MinB: 4000},
+			Unmarshal:       UnmarshalRemoveLiquidity,
+			State:           withPool,
+			ExpectedOutput:  liquidityOutput(1000, 4000, 2000),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "remove more liquidity than owned",
+			Action:          &RemoveLiquidity{AssetA: native, AssetB: asset, Shares: 2001},
+			Unmarshal:       UnmarshalRemoveLiquidity,
+			State:           withPool,
+			ExpectedOutput:  OutputInsufficientLiquidity,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "remove liquidity below min",
+			Action:          &RemoveLiquidity{AssetA: native, AssetB: asset, Shares: 1000, MinB: 2001},
+			Unmarshal:       UnmarshalRemoveLiquidity,
+			State:           withPool,
+			ExpectedOutput:  OutputBelowMinimum,
+			ExpectedSuccess: false,
+		},
+		{
+			// 99 after fees: 99*4000/(1000+99)
+			Name:            "swap exact",
+			Action:          &SwapExact{In: native, Out: asset, Value: 100, MinOut: 360},
+			Unmarshal:       UnmarshalSwapExact,
+			State:           withPool,
+			ExpectedOutput:  swapOutputBytes(100, 360),
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "swap exact below min out",
+			Action:          &SwapExact{In: native, Out: asset, Value: 100, MinOut: 361},
+			Unmarshal:       UnmarshalSwapExact,
+			State:           withPool,
+			ExpectedOutput:  OutputBelowMinimum,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "swap exact too small",
+			Action:          &SwapExact{In: asset, Out: native, Value: 1},
+			Unmarshal:       UnmarshalSwapExact,
+			State:           withPool,
+			ExpectedOutput:  OutputInsufficientOutput,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "swap exact missing pool",
+			Action:          &SwapExact{In: native, Out: ids.GenerateTestID(), Value: 100},
+			Unmarshal:       UnmarshalSwapExact,
+			State:           withPool,
+			ExpectedOutput:  OutputPoolMissing,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Rules = rules
+		test.Actor = actor
+		test.TxID = ids.GenerateTestID()
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestSwapOutput(t *testing.T) {
+	require := require.New(t)
+
+	reserveIn, reserveOut := uint64(1_000_000), uint64(3_000_000)
+	for _, value := range []uint64{1_000, 50_000, 999_999, 10_000_000} {
+		output, err := swapOutput(value, reserveIn, reserveOut)
+		require.NoError(err)
+		require.Less(output, reserveOut)
+
+		// The product of the reserves never decreases
+		before, err := mulDiv(reserveIn, reserveOut, 1)
+		require.NoError(err)
+		after, err := mulDiv(reserveIn+value, reserveOut-output, 1)
+		require.NoError(err)
+		require.GreaterOrEqual(after, before)
+		reserveIn, reserveOut = reserveIn+value, reserveOut-output
+	}
+}
+
+// poolValue encodes a pool whose first asset sorts before its second (like
+// the native asset does before any other).
+func poolValue(reserveA uint64, reserveB uint64, shares uint64) []byte {
+	v := binary.BigEndian.AppendUint64(nil, reserveA)
+	v = binary.BigEndian.AppendUint64(v, reserveB)
+	return binary.BigEndian.AppendUint64(v, shares)
+}
+
+func liquidityOutput(amountA uint64, amountB uint64, shares uint64) []byte {
+	b, err := (&LiquidityResult{AmountA: amountA, AmountB: amountB, Shares: shares}).Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func swapOutputBytes(in uint64, out uint64) []byte {
+	b, err := (&SwapResult{In: in, Out: out}).Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RemoveLiquidity)(nil)
+
+// RemoveLiquidity burns [Shares] of the pool of [AssetA] and [AssetB] in
+// exchange for their part of its reserves. The pool is deleted once all of
+// its shares are burned.
+type RemoveLiquidity struct {
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+	Shares uint64 `json:"shares"`
+
+	// [MinA] and [MinB] are the least of each asset to accept (to protect
+	// against the price moving before the transaction is executed).
+	MinA uint64 `json:"minA"`
+	MinB uint64 `json:"minB"`
+}
+
+func (*RemoveLiquidity) GetTypeID() uint8 {
+	return removeLiquidityID
+}
+
+func (r *RemoveLiquidity) StateKeys(actor codec.Address, _ ids.ID) []string {
+	pool := storage.PoolID(r.AssetA, r.AssetB)
+	return []string{
+		string(storage.PoolKey(pool)),
+		string(storage.LiquidityKey(pool, actor)),
+		string(storage.BalanceKey(actor, r.AssetA)),
+		string(storage.BalanceKey(actor, r.AssetB)),
+	}
+}
+
+func (*RemoveLiquidity) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.LiquidityChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*RemoveLiquidity) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RemoveLiquidity) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if r.AssetA == r.AssetB {
+		return false, RemoveLiquidityComputeUnits, OutputSameInOut, nil, nil
+	}
+	if r.Shares == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return false, RemoveLiquidityComputeUnits, OutputValueZero, nil, nil
+	}
+	exists, reserveA, reserveB, totalShares, err := storage.GetPool(ctx, mu, r.AssetA, r.AssetB)
+	if err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, RemoveLiquidityComputeUnits, OutputPoolMissing, nil, nil
+	}
+	pool := storage.PoolID(r.AssetA, r.AssetB)
+	liquidity, err := storage.GetLiquidity(ctx, mu, pool, actor)
+	if err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if liquidity < r.Shares {
+		return false, RemoveLiquidityComputeUnits, OutputInsufficientLiquidity, nil, nil
+	}
+
+	// Round withdrawals down so that remaining shares are never diluted
+	amountA, err := mulDiv(r.Shares, reserveA, totalShares)
+	if err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	amountB, err := mulDiv(r.Shares, reserveB, totalShares)
+	if err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if amountA < r.MinA || amountB < r.MinB {
+		return false, RemoveLiquidityComputeUnits, OutputBelowMinimum, nil, nil
+	}
+	if r.Shares == totalShares {
+		if err := storage.DeletePool(ctx, mu, r.AssetA, r.AssetB); err != nil {
+			return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+		}
+	} else {
+		// Reserves can't reach 0 while there are shares left because
+		// withdrawals are rounded down
+		if err := storage.SetPool(
+			ctx,
+			mu,
+			r.AssetA,
+			r.AssetB,
+			reserveA-amountA,
+			reserveB-amountB,
+			totalShares-r.Shares,
+		); err != nil {
+			return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := storage.SetLiquidity(ctx, mu, pool, actor, liquidity-r.Shares); err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.AddBalance(ctx, mu, actor, r.AssetA, amountA, true); err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.AddBalance(ctx, mu, actor, r.AssetB, amountB, true); err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	lr := &LiquidityResult{AmountA: amountA, AmountB: amountB, Shares: r.Shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return false, RemoveLiquidityComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, RemoveLiquidityComputeUnits, output, nil, nil
+}
+
+func (*RemoveLiquidity) MaxComputeUnits(chain.Rules) uint64 {
+	return RemoveLiquidityComputeUnits
+}
+
+func (*RemoveLiquidity) Size() int {
+	return consts.IDLen*2 + consts.Uint64Len*3
+}
+
+func (r *RemoveLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(r.AssetA)
+	p.PackID(r.AssetB)
+	p.PackUint64(r.Shares)
+	p.PackUint64(r.MinA)
+	p.PackUint64(r.MinB)
+}
+
+func UnmarshalRemoveLiquidity(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var remove RemoveLiquidity
+	p.UnpackID(false, &remove.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &remove.AssetB) // empty ID is the native asset
+	remove.Shares = p.UnpackUint64(true)
+	remove.MinA = p.UnpackUint64(false)
+	remove.MinB = p.UnpackUint64(false)
+	return &remove, p.Err()
+}
+
+func (*RemoveLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestStreamConformance(t *testing.T) {
+	var (
+		rules  = genesis.Default().Rules(0, 1, ids.GenerateTestID())
+		payer  = codec.CreateAddress(0, ids.GenerateTestID())
+		payee  = codec.CreateAddress(0, ids.GenerateTestID())
+		asset  = ids.GenerateTestID()
+		stream = ids.GenerateTestID()
+
+		balances = map[string][]byte{
+			string(storage.BalanceKey(payer, asset)): binary.BigEndian.AppendUint64(nil, 10_000),
+		}
+		// 10 per second from 0 out of a deposit of 1000, 20 of which has been
+		// withdrawn
+		withStream = map[string][]byte{
+			string(storage.StreamKey(stream)):        streamValue(payer, payee, asset, 10, 0, 1_000, 20),
+			string(storage.BalanceKey(payer, asset)): binary.BigEndian.AppendUint64(nil, 10_000),
+			string(storage.BalanceKey(payee, asset)): binary.BigEndian.AppendUint64(nil, 20),
+		}
+	)
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "open stream",
+			Action:          &OpenStream{Payee: payee, Asset: asset, Rate: 10, Deposit: 1_000},
+			Unmarshal:       UnmarshalOpenStream,
+			State:           balances,
+			Actor:           payer,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "open stream to self",
+			Action:          &OpenStream{Payee: payer, Asset: asset, Rate: 10, Deposit: 1_000},
+			Unmarshal:       UnmarshalOpenStream,
+			State:           balances,
+			Actor:           payer,
+			ExpectedOutput:  OutputSelfStream,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "open stream above balance",
+			Action:          &OpenStream{Payee: payee, Asset: asset, Rate: 10, Deposit: 10_001},
+			Unmarshal:       UnmarshalOpenStream,
+			State:           balances,
+			Actor:           payer,
+			ExpectedStatus:  chain.StatusInsufficientBalance,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "withdraw stream",
+			Action:          &WithdrawStream{Stream: stream, Asset: asset},
+			Unmarshal:       UnmarshalWithdrawStream,
+			State:           withStream,
+			Actor:           payee,
+			Timestamp:       5_000,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "withdraw stream nothing streamed",
+			Action:          &WithdrawStream{Stream: stream, Asset: asset},
+			Unmarshal:       UnmarshalWithdrawStream,
+			State:           withStream,
+			Actor:           payee,
+			Timestamp:       2_999,
+			ExpectedOutput:  OutputNothingToWithdraw,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "withdraw stream not payee",
+			Action:          &WithdrawStream{Stream: stream, Asset: asset},
+			Unmarshal:       UnmarshalWithdrawStream,
+			State:           withStream,
+			Actor:           payer,
+			Timestamp:       5_000,
+			ExpectedOutput:  OutputUnauthorized,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "withdraw stream wrong asset",
+			Action:          &WithdrawStream{Stream: stream, Asset: ids.Empty},
+			Unmarshal:       UnmarshalWithdrawStream,
+			State:           withStream,
+			Actor:           payee,
+			Timestamp:       5_000,
+			ExpectedOutput:  OutputWrongAsset,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "withdraw missing stream",
+			Action:          &WithdrawStream{Stream: ids.GenerateTestID(), Asset: asset},
+			Unmarshal:       UnmarshalWithdrawStream,
+			State:           withStream,
+			Actor:           payee,
+			ExpectedOutput:  OutputStreamMissing,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "close stream",
+			Action:          &CloseStream{Stream: stream, Payee: payee, Asset: asset},
+			Unmarshal:       UnmarshalCloseStream,
+			State:           withStream,
+			Actor:           payer,
+			Timestamp:       5_000,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "close stream not payer",
+			Action:          &CloseStream{Stream: stream, Payee: payee, Asset: asset},
+			Unmarshal:       UnmarshalCloseStream,
+			State:           withStream,
+			Actor:           payee,
+			ExpectedOutput:  OutputUnauthorized,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "close stream wrong payee",
+			Action:          &CloseStream{Stream: stream, Payee: payer, Asset: asset},
+			Unmarshal:       UnmarshalCloseStream,
+			State:           withStream,
+			Actor:           payer,
+			ExpectedOutput:  OutputWrongPayee,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.Rules = rules
+		test.TxID = ids.GenerateTestID()
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestStreamed(t *testing.T) {
+	require := require.New(t)
+
+	require.Zero(storage.Streamed(10, 1_000, 100, 0))
+	require.Zero(storage.Streamed(10, 1_000, 100, 1_999))
+	require.Equal(uint64(10), storage.Streamed(10, 1_000, 100, 2_000))
+	require.Equal(uint64(90), storage.Streamed(10, 1_000, 100, 10_999))
+	require.Equal(uint64(100), storage.Streamed(10, 1_000, 100, 1_000_000))
+	require.Equal(uint64(100), storage.Streamed(^uint64(0), 0, 100, 10_000))
+}
+
+func streamValue(
+	payer codec.Address,
+	payee codec.Address,
+	asset ids.ID,
+	rate uint64,
+	start int64,
+	deposit uint64,
+	withdrawn uint64,
+) []byte {
+	v := append(append(append([]byte{}, payer[:]...), payee[:]...), asset[:]...)
+	v = binary.BigEndian.AppendUint64(v, rate)
+	v = binary.BigEndian.AppendUint64(v, uint64(start))
+	v = binary.BigEndian.AppendUint64(v, deposit)
+	return binary.BigEndian.AppendUint64(v, withdrawn)
+}
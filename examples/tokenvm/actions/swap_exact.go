@@ -0,0 +1,157 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*SwapExact)(nil)
+
+// SwapExact sells exactly [Value] of [In] to the pool of [In] and [Out].
+type SwapExact struct {
+	In    ids.ID `json:"in"`
+	Out   ids.ID `json:"out"`
+	Value uint64 `json:"value"`
+
+	// [MinOut] is the least of [Out] to accept (to protect against the price
+	// moving before the transaction is executed).
+	MinOut uint64 `json:"minOut"`
+}
+
+func (*SwapExact) GetTypeID() uint8 {
+	return swapExactID
+}
+
+func (s *SwapExact) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.PoolKey(storage.PoolID(s.In, s.Out))),
+		string(storage.BalanceKey(actor, s.In)),
+		string(storage.BalanceKey(actor, s.Out)),
+	}
+}
+
+func (*SwapExact) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*SwapExact) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SwapExact) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if s.In == s.Out {
+		return false, SwapExactComputeUnits, OutputSameInOut, nil, nil
+	}
+	if s.Value == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return false, SwapExactComputeUnits, OutputValueZero, nil, nil
+	}
+	exists, reserveIn, reserveOut, shares, err := storage.GetPool(ctx, mu, s.In, s.Out)
+	if err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, SwapExactComputeUnits, OutputPoolMissing, nil, nil
+	}
+	// [output] is always less than [reserveOut]
+	output, err := swapOutput(s.Value, reserveIn, reserveOut)
+	if err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if output == 0 {
+		return false, SwapExactComputeUnits, OutputInsufficientOutput, nil, nil
+	}
+	if output < s.MinOut {
+		return false, SwapExactComputeUnits, OutputBelowMinimum, nil, nil
+	}
+	nreserveIn, err := smath.Add64(reserveIn, s.Value)
+	if err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SubBalance(ctx, mu, actor, s.In, s.Value); err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.AddBalance(ctx, mu, actor, s.Out, output, true); err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SetPool(ctx, mu, s.In, s.Out, nreserveIn, reserveOut-output, shares); err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	sr := &SwapResult{In: s.Value, Out: output}
+	b, err := sr.Marshal()
+	if err != nil {
+		return false, SwapExactComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, SwapExactComputeUnits, b, nil, nil
+}
+
+func (*SwapExact) MaxComputeUnits(chain.Rules) uint64 {
+	return SwapExactComputeUnits
+}
+
+func (*SwapExact) Size() int {
+	return consts.IDLen*2 + consts.Uint64Len*2
+}
+
+func (s *SwapExact) Marshal(p *codec.Packer) {
+	p.PackID(s.In)
+	p.PackID(s.Out)
+	p.PackUint64(s.Value)
+	p.PackUint64(s.MinOut)
+}
+
+func UnmarshalSwapExact(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var swap SwapExact
+	p.UnpackID(false, &swap.In)  // empty ID is the native asset
+	p.UnpackID(false, &swap.Out) // empty ID is the native asset
+	swap.Value = p.UnpackUint64(true)
+	swap.MinOut = p.UnpackUint64(false)
+	return &swap, p.Err()
+}
+
+func (*SwapExact) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// SwapResult is a custom successful response output that provides the
+// amounts exchanged by a swap.
+type SwapResult struct {
+	In  uint64 `json:"in"`
+	Out uint64 `json:"out"`
+}
+
+func UnmarshalSwapResult(b []byte) (*SwapResult, error) {
+	p := codec.NewReader(b, consts.Uint64Len*2)
+	var result SwapResult
+	result.In = p.UnpackUint64(true)
+	result.Out = p.UnpackUint64(true)
+	return &result, p.Err()
+}
+
+func (s *SwapResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.Uint64Len*2, consts.Uint64Len*2)
+	p.PackUint64(s.In)
+	p.PackUint64(s.Out)
+	return p.Bytes(), p.Err()
+}
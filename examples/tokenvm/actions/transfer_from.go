@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*TransferFrom)(nil)
+
+// TransferFrom moves [Value] of [From]'s [Asset] to [To], spending the
+// allowance [From] gave the actor with [Approve].
+type TransferFrom struct {
+	// From is the owner of the [Value].
+	From codec.Address `json:"from"`
+
+	// To is the recipient of the [Value].
+	To codec.Address `json:"to"`
+
+	// Asset to transfer to [To].
+	Asset ids.ID `json:"asset"`
+
+	// Amount are transferred to [To].
+	Value uint64 `json:"value"`
+
+	// Optional message to accompany transaction.
+	Memo []byte `json:"memo"`
+}
+
+func (*TransferFrom) GetTypeID() uint8 {
+	return transferFromID
+}
+
+func (t *TransferFrom) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.AllowanceKey(t.From, actor, t.Asset)),
+		string(storage.BalanceKey(t.From, t.Asset)),
+		string(storage.BalanceKey(t.To, t.Asset)),
+	}
+}
+
+func (*TransferFrom) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.AllowanceChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (*TransferFrom) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *TransferFrom) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	if t.Value == 0 {
+		return false, TransferFromComputeUnits, OutputValueZero, nil, nil
+	}
+	if len(t.Memo) > MaxMemoSize {
+		return false, TransferFromComputeUnits, OutputMemoTooLarge, nil, nil
+	}
+	if err := storage.SubAllowance(ctx, mu, t.From, actor, t.Asset, t.Value); err != nil {
+		return false, TransferFromComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.SubBalance(ctx, mu, t.From, t.Asset, t.Value); err != nil {
+		return false, TransferFromComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := storage.AddBalance(ctx, mu, t.To, t.Asset, t.Value, true); err != nil {
+		return false, TransferFromComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, TransferFromComputeUnits, nil, nil, nil
+}
+
+func (*TransferFrom) MaxComputeUnits(chain.Rules) uint64 {
+	return TransferFromComputeUnits
+}
+
+func (t *TransferFrom) Size() int {
+	return codec.AddressLen*2 + consts.IDLen + consts.Uint64Len + codec.BytesLen(t.Memo)
+}
+
+func (t *TransferFrom) Marshal(p *codec.Packer) {
+	p.PackAddress(t.From)
+	p.PackAddress(t.To)
+	p.PackID(t.Asset)
+	p.PackUint64(t.Value)
+	p.PackBytes(t.Memo)
+}
+
+func UnmarshalTransferFrom(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var transfer TransferFrom
+	p.UnpackAddress(&transfer.From)
+	p.UnpackAddress(&transfer.To)
+	p.UnpackID(false, &transfer.Asset) // empty ID is the native asset
+	transfer.Value = p.UnpackUint64(true)
+	p.UnpackBytes(MaxMemoSize, false, &transfer.Memo)
+	return &transfer, p.Err()
+}
+
+func (*TransferFrom) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
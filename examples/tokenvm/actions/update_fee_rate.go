@@ -0,0 +1,126 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*UpdateFeeRate)(nil)
+
+// feeAssetRules is implemented by the rules of the tokenvm (see
+// [genesis.FeeAsset]).
+type feeAssetRules interface {
+	GetFeeAsset(asset ids.ID) (codec.Address, int64, bool)
+}
+
+// UpdateFeeRate posts the rate that fees paid in [Asset] are converted at.
+// Only the paymaster of [Asset] (which pays the converted fees in the native
+// asset) can post it.
+//
+// The rate is taken from [Order], an open order of the paymaster that sells
+// the native asset for [Asset], so fees are only converted at a rate that
+// anyone can trade at on the orderbook.
+type UpdateFeeRate struct {
+	// Asset that can pay fees.
+	Asset ids.ID `json:"asset"`
+
+	// Order that quotes the rate: [InTick] of [Asset] are worth [OutTick] of
+	// the native asset.
+	Order ids.ID `json:"order"`
+}
+
+func (*UpdateFeeRate) GetTypeID() uint8 {
+	return updateFeeRateID
+}
+
+func (u *UpdateFeeRate) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(storage.FeeRateKey(u.Asset)),
+		string(storage.OrderKey(u.Order)),
+	}
+}
+
+func (*UpdateFeeRate) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.FeeRateChunks, storage.OrderChunks}
+}
+
+func (*UpdateFeeRate) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateFeeRate) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	fr, ok := chain.UnwrapRules(r).(feeAssetRules)
+	if !ok {
+		return false, UpdateFeeRateComputeUnits, OutputFeeAssetNotAllowed, nil, nil
+	}
+	paymaster, _, ok := fr.GetFeeAsset(u.Asset)
+	if !ok {
+		return false, UpdateFeeRateComputeUnits, OutputFeeAssetNotAllowed, nil, nil
+	}
+	if actor != paymaster {
+		return false, UpdateFeeRateComputeUnits, OutputNotPaymaster, nil, nil
+	}
+	exists, in, inTick, out, outTick, _, owner, err := storage.GetOrder(ctx, mu, u.Order)
+	if err != nil {
+		return false, UpdateFeeRateComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, UpdateFeeRateComputeUnits, OutputOrderMissing, nil, nil
+	}
+	if owner != actor {
+		return false, UpdateFeeRateComputeUnits, OutputWrongOwner, nil, nil
+	}
+	if in != u.Asset {
+		return false, UpdateFeeRateComputeUnits, OutputWrongIn, nil, nil
+	}
+	if out != ids.Empty {
+		return false, UpdateFeeRateComputeUnits, OutputWrongOut, nil, nil
+	}
+	if err := storage.SetFeeRate(ctx, mu, u.Asset, timestamp, outTick, inTick); err != nil {
+		return false, UpdateFeeRateComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, UpdateFeeRateComputeUnits, nil, nil, nil
+}
+
+func (*UpdateFeeRate) MaxComputeUnits(chain.Rules) uint64 {
+	return UpdateFeeRateComputeUnits
+}
+
+func (*UpdateFeeRate) Size() int {
+	return consts.IDLen * 2
+}
+
+func (u *UpdateFeeRate) Marshal(p *codec.Packer) {
+	p.PackID(u.Asset)
+	p.PackID(u.Order)
+}
+
+func UnmarshalUpdateFeeRate(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var update UpdateFeeRate
+	p.UnpackID(true, &update.Asset) // fees are paid in the native asset by default
+	p.UnpackID(true, &update.Order)
+	return &update, p.Err()
+}
+
+func (*UpdateFeeRate) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -43,6 +43,11 @@ type WarpTransfer struct {
 	// DestinationChainID is the destination of this transfer. We assume this
 	// must be populated (not anycast).
 	DestinationChainID ids.ID `json:"destinationChainID"`
+
+	// Timestamp is when this message was sent (the timestamp of the block
+	// that created it). This is used to reject messages that are older than
+	// the warp replay retention of the destination.
+	Timestamp int64 `json:"timestamp"`
 }
 
 func (w *WarpTransfer) size() int {
@@ -50,7 +55,7 @@ func (w *WarpTransfer) size() int {
 		consts.Uint64Len + consts.BoolLen +
 		consts.Uint64Len + /* op bits */
 		consts.Uint64Len + consts.Uint64Len + consts.IDLen + consts.Uint64Len + consts.Int64Len +
-		consts.IDLen + consts.IDLen
+		consts.IDLen + consts.IDLen + consts.Int64Len
 }
 
 func (w *WarpTransfer) Marshal() ([]byte, error) {
@@ -70,6 +75,7 @@ func (w *WarpTransfer) Marshal() ([]byte, error) {
 	p.PackOptional(op)
 	p.PackID(w.TxID)
 	p.PackID(w.DestinationChainID)
+	p.PackInt64(w.Timestamp)
 	return p.Bytes(), p.Err()
 }
 
@@ -89,7 +95,7 @@ func UnmarshalWarpTransfer(b []byte) (*WarpTransfer, error) {
 		consts.Uint64Len + consts.BoolLen +
 		consts.Uint64Len + /* op bits */
 		consts.Uint64Len + consts.Uint64Len + consts.IDLen + consts.Uint64Len + consts.Int64Len +
-		consts.IDLen + consts.IDLen
+		consts.IDLen + consts.IDLen + consts.Int64Len
 
 	var transfer WarpTransfer
 	p := codec.NewReader(b, maxWarpTransferSize)
@@ -108,6 +114,7 @@ func UnmarshalWarpTransfer(b []byte) (*WarpTransfer, error) {
 	op.Done()
 	p.UnpackID(true, &transfer.TxID)
 	p.UnpackID(true, &transfer.DestinationChainID)
+	transfer.Timestamp = p.UnpackInt64(false)
 	if err := p.Err(); err != nil {
 		return nil, err
 	}
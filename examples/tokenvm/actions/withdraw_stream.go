@@ -0,0 +1,118 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*WithdrawStream)(nil)
+
+// WithdrawStream pays the payee of [Stream] everything streamed to it that it
+// hasn't withdrawn yet. The stream is removed once its deposit is fully
+// withdrawn.
+type WithdrawStream struct {
+	// [Stream] is the ID of the transaction that opened the stream.
+	Stream ids.ID `json:"stream"`
+
+	// [Asset] is the asset streamed. We need to provide this to populate
+	// [StateKeys].
+	Asset ids.ID `json:"asset"`
+}
+
+func (*WithdrawStream) GetTypeID() uint8 {
+	return withdrawStreamID
+}
+
+func (w *WithdrawStream) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(storage.StreamKey(w.Stream)),
+		string(storage.BalanceKey(actor, w.Asset)),
+	}
+}
+
+func (*WithdrawStream) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.StreamChunks, storage.BalanceChunks}
+}
+
+func (*WithdrawStream) OutputsWarpMessage() bool {
+	return false
+}
+
+func (w *WithdrawStream) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, payer, payee, asset, rate, start, deposit, withdrawn, err := storage.GetStream(ctx, mu, w.Stream)
+	if err != nil {
+		return false, WithdrawStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	if !exists {
+		return false, WithdrawStreamComputeUnits, OutputStreamMissing, nil, nil
+	}
+	if payee != actor {
+		return false, WithdrawStreamComputeUnits, OutputUnauthorized, nil, nil
+	}
+	if asset != w.Asset {
+		return false, WithdrawStreamComputeUnits, OutputWrongAsset, nil, nil
+	}
+	// [withdrawn] is never more than what has been streamed
+	amount := storage.Streamed(rate, start, deposit, timestamp) - withdrawn
+	if amount == 0 {
+		return false, WithdrawStreamComputeUnits, OutputNothingToWithdraw, nil, nil
+	}
+	withdrawn += amount
+	if withdrawn == deposit {
+		if err := storage.DeleteStream(ctx, mu, w.Stream); err != nil {
+			return false, WithdrawStreamComputeUnits, nil, nil, chain.Fail(err)
+		}
+	} else {
+		if err := storage.SetStream(ctx, mu, w.Stream, payer, payee, asset, rate, start, deposit, withdrawn); err != nil {
+			return false, WithdrawStreamComputeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := storage.AddBalance(ctx, mu, actor, asset, amount, true); err != nil {
+		return false, WithdrawStreamComputeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, WithdrawStreamComputeUnits, nil, nil, nil
+}
+
+func (*WithdrawStream) MaxComputeUnits(chain.Rules) uint64 {
+	return WithdrawStreamComputeUnits
+}
+
+func (*WithdrawStream) Size() int {
+	return consts.IDLen * 2
+}
+
+func (w *WithdrawStream) Marshal(p *codec.Packer) {
+	p.PackID(w.Stream)
+	p.PackID(w.Asset)
+}
+
+func UnmarshalWithdrawStream(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var withdraw WithdrawStream
+	p.UnpackID(true, &withdraw.Stream)
+	p.UnpackID(false, &withdraw.Asset) // empty ID is the native asset
+	return &withdraw, p.Err()
+}
+
+func (*WithdrawStream) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
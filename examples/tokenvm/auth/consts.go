@@ -7,7 +7,8 @@ import "github.com/ava-labs/hypersdk/vm"
 
 // Note: Registry will error during initialization if a duplicate ID is assigned. We explicitly assign IDs to avoid accidental remapping.
 const (
-	ed25519ID uint8 = 0
+	ed25519ID  uint8 = 0
+	multisigID uint8 = 1
 )
 
 func Engines() map[uint8]vm.AuthEngine {
@@ -144,7 +144,9 @@ func (b *ED25519Batch) Add(msg []byte, rauth chain.Auth) func() error {
 }
 
 func (b *ED25519Batch) Done() []func() error {
-	if b.batch == nil {
+	// [total] may overestimate the number of signatures added, in which case
+	// the last batch is empty.
+	if b.batch == nil || b.counter == 0 {
 		return nil
 	}
 	return []func() error{b.batch.VerifyAsync()}
@@ -5,4 +5,11 @@ package auth
 
 import "errors"
 
-var ErrInvalidSignature = errors.New("invalid signature")
+var (
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrInvalidSigners     = errors.New("invalid number of signers")
+	ErrInvalidThreshold   = errors.New("invalid threshold")
+	ErrDuplicateSigner    = errors.New("duplicate signer")
+	ErrInvalidSignerIndex = errors.New("invalid signer index")
+	ErrNotEnoughSigners   = errors.New("not enough signers")
+)
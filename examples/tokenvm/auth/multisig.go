@@ -0,0 +1,219 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Auth = (*Multisig)(nil)
+
+// MaxMultisigSigners is the maximum number of keys in a [Multisig].
+const MaxMultisigSigners = 16
+
+type MultisigSignature struct {
+	// Index is the position of the signer in [Multisig.Signers].
+	Index     uint8             `json:"index"`
+	Signature ed25519.Signature `json:"signature"`
+}
+
+// Multisig is signed by [Threshold] of [Signers]. Its address is derived
+// from the set of signers and the threshold (see [NewMultisigAddress]).
+type Multisig struct {
+	Signers   []ed25519.PublicKey `json:"signers"`
+	Threshold uint8               `json:"threshold"`
+
+	// Signatures must contain exactly [Threshold] signatures, sorted by
+	// signer index.
+	Signatures []*MultisigSignature `json:"signatures"`
+
+	addr codec.Address
+}
+
+// NewMultisig creates a [Multisig] from the collected [signatures] (by
+// signer index). If more than [threshold] signatures are provided, those of
+// the signers with the lowest indices are used.
+func NewMultisig(
+	signers []ed25519.PublicKey,
+	threshold uint8,
+	signatures map[uint8]ed25519.Signature,
+) (*Multisig, error) {
+	if err := verifySigners(signers, threshold); err != nil {
+		return nil, err
+	}
+	m := &Multisig{
+		Signers:    signers,
+		Threshold:  threshold,
+		Signatures: make([]*MultisigSignature, 0, threshold),
+	}
+	for i := 0; i < len(signers) && len(m.Signatures) < int(threshold); i++ {
+		sig, ok := signatures[uint8(i)]
+		if !ok {
+			continue
+		}
+		m.Signatures = append(m.Signatures, &MultisigSignature{Index: uint8(i), Signature: sig})
+	}
+	if len(m.Signatures) < int(threshold) {
+		return nil, ErrNotEnoughSigners
+	}
+	return m, nil
+}
+
+func verifySigners(signers []ed25519.PublicKey, threshold uint8) error {
+	if len(signers) == 0 || len(signers) > MaxMultisigSigners {
+		return ErrInvalidSigners
+	}
+	if threshold == 0 || int(threshold) > len(signers) {
+		return ErrInvalidThreshold
+	}
+	// A key could otherwise sign more than once
+	for i := range signers {
+		for j := i + 1; j < len(signers); j++ {
+			if signers[i] == signers[j] {
+				return ErrDuplicateSigner
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Multisig) address() codec.Address {
+	if m.addr == codec.EmptyAddress {
+		m.addr = NewMultisigAddress(m.Signers, m.Threshold)
+	}
+	return m.addr
+}
+
+func (*Multisig) GetTypeID() uint8 {
+	return multisigID
+}
+
+func (m *Multisig) ComputeUnits(chain.Rules) uint64 {
+	return uint64(m.Threshold) * ED25519ComputeUnits
+}
+
+func (*Multisig) ValidRange(chain.Rules) (int64, int64) {
+	return -1, -1
+}
+
+func (m *Multisig) Verify(_ context.Context, msg []byte) error {
+	for _, sig := range m.Signatures {
+		if !ed25519.Verify(msg, m.Signers[sig.Index], sig.Signature) {
+			return ErrInvalidSignature
+		}
+	}
+	return nil
+}
+
+func (m *Multisig) Actor() codec.Address {
+	return m.address()
+}
+
+func (m *Multisig) Sponsor() codec.Address {
+	return m.address()
+}
+
+func (m *Multisig) Size() int {
+	return multisigSize(len(m.Signers), m.Threshold)
+}
+
+func multisigSize(signers int, threshold uint8) int {
+	return consts.ByteLen + signers*ed25519.PublicKeyLen +
+		consts.ByteLen + int(threshold)*(consts.ByteLen+ed25519.SignatureLen)
+}
+
+func (m *Multisig) Marshal(p *codec.Packer) {
+	p.PackByte(uint8(len(m.Signers)))
+	for _, signer := range m.Signers {
+		p.PackFixedBytes(signer[:])
+	}
+	p.PackByte(m.Threshold)
+	for _, sig := range m.Signatures {
+		p.PackByte(sig.Index)
+		p.PackFixedBytes(sig.Signature[:])
+	}
+}
+
+func UnmarshalMultisig(p *codec.Packer, _ *warp.Message) (chain.Auth, error) {
+	var m Multisig
+	numSigners := int(p.UnpackByte())
+	if numSigners == 0 || numSigners > MaxMultisigSigners {
+		return nil, ErrInvalidSigners
+	}
+	m.Signers = make([]ed25519.PublicKey, numSigners)
+	for i := range m.Signers {
+		signer := m.Signers[i][:] // avoid allocating additional memory
+		p.UnpackFixedBytes(ed25519.PublicKeyLen, &signer)
+	}
+	m.Threshold = p.UnpackByte()
+	if err := verifySigners(m.Signers, m.Threshold); err != nil {
+		return nil, err
+	}
+	m.Signatures = make([]*MultisigSignature, m.Threshold)
+	for i := range m.Signatures {
+		sig := &MultisigSignature{Index: p.UnpackByte()}
+		// Indices must be strictly increasing to prevent a signer from being
+		// counted more than once
+		if int(sig.Index) >= numSigners || (i > 0 && sig.Index <= m.Signatures[i-1].Index) {
+			return nil, ErrInvalidSignerIndex
+		}
+		signature := sig.Signature[:] // avoid allocating additional memory
+		p.UnpackFixedBytes(ed25519.SignatureLen, &signature)
+		m.Signatures[i] = sig
+	}
+	return &m, p.Err()
+}
+
+var _ chain.AuthFactory = (*MultisigFactory)(nil)
+
+// NewMultisigFactory signs with the keys in [privs], which must belong to at
+// least [threshold] of [signers].
+func NewMultisigFactory(
+	signers []ed25519.PublicKey,
+	threshold uint8,
+	privs ...ed25519.PrivateKey,
+) *MultisigFactory {
+	return &MultisigFactory{signers, threshold, privs}
+}
+
+type MultisigFactory struct {
+	signers   []ed25519.PublicKey
+	threshold uint8
+	privs     []ed25519.PrivateKey
+}
+
+func (m *MultisigFactory) Sign(msg []byte) (chain.Auth, error) {
+	signatures := make(map[uint8]ed25519.Signature, len(m.privs))
+	for _, priv := range m.privs {
+		pk := priv.PublicKey()
+		for i, signer := range m.signers {
+			if signer == pk {
+				signatures[uint8(i)] = ed25519.Sign(msg, priv)
+			}
+		}
+	}
+	return NewMultisig(m.signers, m.threshold, signatures)
+}
+
+func (m *MultisigFactory) MaxUnits() (uint64, uint64) {
+	return uint64(multisigSize(len(m.signers), m.threshold)), uint64(m.threshold) * ED25519ComputeUnits
+}
+
+// NewMultisigAddress returns the address of the account controlled by
+// [threshold] of [signers]. The order of [signers] matters.
+func NewMultisigAddress(signers []ed25519.PublicKey, threshold uint8) codec.Address {
+	b := make([]byte, consts.ByteLen+len(signers)*ed25519.PublicKeyLen)
+	b[0] = threshold
+	for i, signer := range signers {
+		copy(b[consts.ByteLen+i*ed25519.PublicKeyLen:], signer[:])
+	}
+	return codec.CreateAddress(multisigID, utils.ToID(b))
+}
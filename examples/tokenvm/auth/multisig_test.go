@@ -0,0 +1,72 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisig(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var (
+		privs   = make([]ed25519.PrivateKey, 3)
+		signers = make([]ed25519.PublicKey, 3)
+	)
+	for i := range privs {
+		priv, err := ed25519.GeneratePrivateKey()
+		require.NoError(err)
+		privs[i] = priv
+		signers[i] = priv.PublicKey()
+	}
+	msg := []byte("digest")
+
+	// Any [threshold] signers can sign
+	rauth, err := NewMultisigFactory(signers, 2, privs[2], privs[0]).Sign(msg)
+	require.NoError(err)
+	m := rauth.(*Multisig)
+	require.Len(m.Signatures, 2)
+	require.Equal(uint8(0), m.Signatures[0].Index)
+	require.Equal(uint8(2), m.Signatures[1].Index)
+	require.NoError(m.Verify(ctx, msg))
+	require.ErrorIs(m.Verify(ctx, []byte("other")), ErrInvalidSignature)
+	require.Equal(NewMultisigAddress(signers, 2), m.Actor())
+	require.NotEqual(NewMultisigAddress(signers, 3), m.Actor())
+
+	// Round trip
+	p := codec.NewWriter(m.Size(), consts.MaxInt)
+	m.Marshal(p)
+	require.NoError(p.Err())
+	require.Len(p.Bytes(), m.Size())
+	um, err := UnmarshalMultisig(codec.NewReader(p.Bytes(), consts.MaxInt), nil)
+	require.NoError(err)
+	require.NoError(um.Verify(ctx, msg))
+	require.Equal(m.Actor(), um.Actor())
+
+	// Not enough signers
+	_, err = NewMultisigFactory(signers, 2, privs[1]).Sign(msg)
+	require.ErrorIs(err, ErrNotEnoughSigners)
+
+	// A signer can't be counted twice
+	m.Signatures[1] = m.Signatures[0]
+	p = codec.NewWriter(m.Size(), consts.MaxInt)
+	m.Marshal(p)
+	_, err = UnmarshalMultisig(codec.NewReader(p.Bytes(), consts.MaxInt), nil)
+	require.ErrorIs(err, ErrInvalidSignerIndex)
+	_, err = NewMultisig([]ed25519.PublicKey{signers[0], signers[0]}, 2, nil)
+	require.ErrorIs(err, ErrDuplicateSigner)
+
+	// Invalid thresholds
+	_, err = NewMultisig(signers, 0, nil)
+	require.ErrorIs(err, ErrInvalidThreshold)
+	_, err = NewMultisig(signers, 4, nil)
+	require.ErrorIs(err, ErrInvalidThreshold)
+}
@@ -509,14 +509,19 @@ func performImport(
 		}
 	}
 
-	// Generate warp signature (as long as >= 80% stake)
+	// Generate warp signature (as long as the quorum of the destination has
+	// signed)
+	gen, err := dtcli.Genesis(ctx)
+	if err != nil {
+		return err
+	}
 	var (
 		msg                     *warp.Message
 		subnetWeight, sigWeight uint64
 	)
 	for ctx.Err() == nil {
 		msg, subnetWeight, sigWeight, err = scli.GenerateAggregateWarpSignature(ctx, exportTxID)
-		if sigWeight >= (subnetWeight*4)/5 && err == nil {
+		if err == nil && sigWeight*gen.WarpQuorumDenominator >= subnetWeight*gen.WarpQuorumNumerator {
 			break
 		}
 		if err == nil {
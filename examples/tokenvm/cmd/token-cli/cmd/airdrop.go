@@ -0,0 +1,334 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+)
+
+const (
+	airdropPending  = "pending"
+	airdropAccepted = "accepted"
+	airdropFailed   = "failed"
+)
+
+// airdropRecipient is a single row of an airdrop file.
+type airdropRecipient struct {
+	line   int
+	to     codec.Address
+	amount uint64
+	memo   []byte
+}
+
+// airdropTx is the last transaction issued for a recipient.
+type airdropTx struct {
+	TxID   ids.ID `json:"txID"`
+	Expiry int64  `json:"expiry"`
+	Status string `json:"status"`
+}
+
+// airdropProgress is persisted next to the airdrop file after every
+// transaction is issued or decided so that an interrupted airdrop can be
+// resumed without paying any recipient twice.
+type airdropProgress struct {
+	Sender string             `json:"sender"`
+	Asset  ids.ID             `json:"asset"`
+	Txs    map[int]*airdropTx `json:"txs"` // by line
+}
+
+var airdropCmd = &cobra.Command{
+	Use: "airdrop",
+	PreRunE: func(*cobra.Command, []string) error {
+		if len(airdropFile) == 0 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(*cobra.Command, []string) error {
+		return airdrop(context.Background(), airdropFile, airdropAsset)
+	},
+}
+
+// parseAirdropFile reads the recipients in [path]. Each row must be of the
+// form "address,amount[,memo]" (an optional header row is skipped) and
+// [amount] is parsed using [decimals].
+func parseAirdropFile(path string, decimals uint8) ([]*airdropRecipient, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var (
+		recipients = []*airdropRecipient{}
+		seen       = map[string]int{}
+	)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if line == 1 && strings.EqualFold(record[0], "address") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidRecipient, line)
+		}
+		to, err := codec.ParseAddressBech32(tconsts.HRP, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRecipient, line, err)
+		}
+		if strings.HasPrefix(record[1], "-") {
+			return nil, fmt.Errorf("%w: line %d: amount is negative", ErrInvalidRecipient, line)
+		}
+		amount, err := utils.ParseBalance(record[1], decimals)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRecipient, line, err)
+		}
+		if amount == 0 {
+			return nil, fmt.Errorf("%w: line %d: amount is zero", ErrInvalidRecipient, line)
+		}
+		var memo []byte
+		if len(record) == 3 {
+			memo = []byte(record[2])
+		}
+		if len(memo) > actions.MaxMemoSize {
+			return nil, fmt.Errorf("%w: line %d: memo is too large", ErrInvalidRecipient, line)
+		}
+
+		// Identical rows would produce identical transactions
+		key := fmt.Sprintf("%s/%d/%x", to, amount, memo)
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: line %d duplicates line %d (add a memo to distinguish them)", ErrInvalidRecipient, line, prev)
+		}
+		seen[key] = line
+		recipients = append(recipients, &airdropRecipient{line, to, amount, memo})
+	}
+	return recipients, nil
+}
+
+func loadAirdropProgress(path string, sender string, asset ids.ID) (*airdropProgress, error) {
+	progress := &airdropProgress{Sender: sender, Asset: asset, Txs: map[int]*airdropTx{}}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err := cli.LoadJSON(path, progress); err != nil {
+		return nil, err
+	}
+	if progress.Sender != sender || progress.Asset != asset {
+		return nil, fmt.Errorf(
+			"%w: %s was created by %s for %s",
+			ErrAirdropMismatch, path, progress.Sender, progress.Asset,
+		)
+	}
+	return progress, nil
+}
+
+// settleAirdrop determines the outcome of all transactions that were pending
+// when a previous airdrop was interrupted. Transactions that were never
+// accepted (and can no longer be accepted) are forgotten so that they are
+// re-issued.
+func settleAirdrop(ctx context.Context, tcli *trpc.JSONRPCClient, progress *airdropProgress) error {
+	for line, atx := range progress.Txs {
+		if atx.Status != airdropPending {
+			continue
+		}
+		utils.Outf("{{yellow}}checking pending tx (line %d):{{/}} %s\n", line, atx.TxID)
+		if err := rpc.Wait(ctx, func(ctx context.Context) (bool, error) {
+			found, status, _, _, err := tcli.Tx(ctx, atx.TxID)
+			if err != nil {
+				return false, err
+			}
+			switch {
+			case found && status == chain.StatusSuccess:
+				atx.Status = airdropAccepted
+			case found:
+				atx.Status = airdropFailed
+			case time.Now().UnixMilli() > atx.Expiry:
+				delete(progress.Txs, line)
+			default:
+				return false, nil
+			}
+			return true, nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func airdrop(ctx context.Context, path string, sasset string) error {
+	assetID, err := parseAssetID(sasset)
+	if err != nil {
+		return err
+	}
+	_, priv, factory, hcli, scli, tcli, err := handler.DefaultActor()
+	if err != nil {
+		return err
+	}
+	defer scli.Close()
+	sender := codec.MustAddressBech32(tconsts.HRP, priv.Address)
+	symbol, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, assetID, true)
+	if balance == 0 || err != nil {
+		return err
+	}
+	recipients, err := parseAirdropFile(path, decimals)
+	if err != nil {
+		return err
+	}
+	progressPath := path + ".progress"
+	progress, err := loadAirdropProgress(progressPath, sender, assetID)
+	if err != nil {
+		return err
+	}
+	if err := settleAirdrop(ctx, tcli, progress); err != nil {
+		return err
+	}
+	if err := cli.SaveJSON(progressPath, progress); err != nil {
+		return err
+	}
+
+	// Determine which recipients still need to be paid
+	var (
+		remaining = make([]*airdropRecipient, 0, len(recipients))
+		total     uint64
+	)
+	for _, r := range recipients {
+		if atx, ok := progress.Txs[r.line]; ok && atx.Status == airdropAccepted {
+			continue
+		}
+		remaining = append(remaining, r)
+		total += r.amount
+	}
+	utils.Outf(
+		"{{yellow}}recipients:{{/}} %d {{yellow}}remaining:{{/}} %d {{yellow}}amount:{{/}} %s %s\n",
+		len(recipients),
+		len(remaining),
+		utils.FormatBalance(total, decimals),
+		symbol,
+	)
+	if len(remaining) == 0 {
+		return nil
+	}
+	if total > balance {
+		return cli.ErrInsufficientBalance
+	}
+	cont, err := handler.Root().PromptContinue()
+	if !cont || err != nil {
+		return err
+	}
+
+	parser, err := tcli.Parser(ctx)
+	if err != nil {
+		return err
+	}
+	var accepted, failed int
+	for len(remaining) > 0 {
+		// Fill a batch with as many transfers as could fit in a single block
+		var (
+			rules         = parser.Rules(time.Now().UnixMilli())
+			maxBlockUnits = rules.GetMaxBlockUnits()
+			batchUnits    = chain.Dimensions{}
+			batch         = map[ids.ID]*airdropRecipient{}
+		)
+		for len(remaining) > 0 {
+			r := remaining[0]
+			action := &actions.Transfer{To: r.to, Asset: assetID, Value: r.amount, Memo: r.memo}
+			maxUnits, err := chain.EstimateMaxUnits(rules, action, factory, nil)
+			if err != nil {
+				return err
+			}
+			nextUnits, err := chain.Add(batchUnits, maxUnits)
+			if err != nil {
+				return err
+			}
+			if len(batch) > 0 && !maxBlockUnits.Greater(nextUnits) {
+				break
+			}
+			_, tx, _, err := hcli.GenerateTransaction(ctx, parser, nil, action, factory)
+			if err != nil {
+				return err
+			}
+			batchUnits = nextUnits
+			batch[tx.ID()] = r
+			remaining = remaining[1:]
+
+			// Record the tx before issuing it in case we are interrupted
+			progress.Txs[r.line] = &airdropTx{TxID: tx.ID(), Expiry: tx.Base.Timestamp, Status: airdropPending}
+			if err := cli.SaveJSON(progressPath, progress); err != nil {
+				return err
+			}
+			if err := scli.RegisterTx(tx); err != nil {
+				return err
+			}
+		}
+
+		// Wait for the batch to be decided
+		for len(batch) > 0 {
+			txID, dErr, result, err := scli.ListenTx(ctx)
+			if err != nil {
+				return err
+			}
+			r, ok := batch[txID]
+			if !ok {
+				continue
+			}
+			delete(batch, txID)
+			switch {
+			case dErr != nil:
+				// The tx was never included, so we can safely re-issue it
+				utils.Outf("{{red}}tx dropped (line %d):{{/}} %v\n", r.line, dErr)
+				delete(progress.Txs, r.line)
+				remaining = append(remaining, r)
+			case result.Success():
+				progress.Txs[r.line].Status = airdropAccepted
+				accepted++
+			default:
+				utils.Outf("{{red}}tx failed (line %d):{{/}} %s %s\n", r.line, txID, result.Output)
+				progress.Txs[r.line].Status = airdropFailed
+				failed++
+			}
+			if err := cli.SaveJSON(progressPath, progress); err != nil {
+				return err
+			}
+		}
+		utils.Outf(
+			"{{green}}progress:{{/}} %d/%d {{yellow}}failed:{{/}} %d\n",
+			len(recipients)-len(remaining)-failed,
+			len(recipients),
+			failed,
+		)
+	}
+	if failed > 0 {
+		utils.Outf("{{red}}%d transfers failed (run the airdrop again to retry them){{/}}\n", failed)
+	}
+	utils.Outf("{{green}}airdrop complete:{{/}} %d transfers accepted\n", accepted)
+	return nil
+}
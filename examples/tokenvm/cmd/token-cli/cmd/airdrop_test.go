@@ -0,0 +1,127 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+func writeAirdropFile(t *testing.T, rows ...string) string {
+	path := filepath.Join(t.TempDir(), "airdrop.csv")
+	require.NoError(t, utils.SaveBytes(path, []byte(strings.Join(rows, "\n"))))
+	return path
+}
+
+func TestAirdropFlags(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := writeAirdropFile(t)
+
+	tests := []struct {
+		args  []string
+		file  string
+		asset string
+		err   error
+	}{
+		{nil, "", consts.Symbol, ErrInvalidArgs},
+		{[]string{"--asset", consts.Symbol}, "", consts.Symbol, ErrInvalidArgs},
+		{[]string{"--file", path}, path, consts.Symbol, cli.ErrNoKeys},
+		{[]string{"--file", path, "--asset", ids.Empty.String()}, path, ids.Empty.String(), cli.ErrNoKeys},
+		{[]string{"--file", path, "--asset", "TKN2"}, path, "TKN2", nil},
+	}
+	for _, tt := range tests {
+		// Airdrops require a key, so we can only check that arguments are
+		// parsed before it is loaded
+		_, err := execute(t, dir, append([]string{"airdrop"}, tt.args...)...)
+		if tt.err != nil {
+			require.ErrorIs(err, tt.err, tt.args)
+		} else {
+			require.Error(err, tt.args)
+			require.NotErrorIs(err, cli.ErrNoKeys, tt.args)
+		}
+		require.Equal(tt.file, airdropFile, tt.args)
+		require.Equal(tt.asset, airdropAsset, tt.args)
+	}
+}
+
+func TestParseAirdropFile(t *testing.T) {
+	require := require.New(t)
+	alice := codec.CreateAddress(0, ids.GenerateTestID())
+	bob := codec.CreateAddress(0, ids.GenerateTestID())
+	salice := codec.MustAddressBech32(consts.HRP, alice)
+	sbob := codec.MustAddressBech32(consts.HRP, bob)
+
+	// Amounts are parsed using the decimals of the asset and the header row
+	// is optional
+	recipients, err := parseAirdropFile(writeAirdropFile(t,
+		"address,amount,memo",
+		salice+",1.5",
+		sbob+", 2,thanks",
+		salice+",1.5,again",
+	), 2)
+	require.NoError(err)
+	require.Equal([]*airdropRecipient{
+		{line: 2, to: alice, amount: 150},
+		{line: 3, to: bob, amount: 200, memo: []byte("thanks")},
+		{line: 4, to: alice, amount: 150, memo: []byte("again")},
+	}, recipients)
+	recipients, err = parseAirdropFile(writeAirdropFile(t, salice+",1"), 9)
+	require.NoError(err)
+	require.Equal([]*airdropRecipient{{line: 1, to: alice, amount: 1_000_000_000}}, recipients)
+	recipients, err = parseAirdropFile(writeAirdropFile(t), 9)
+	require.NoError(err)
+	require.Empty(recipients)
+
+	for _, rows := range [][]string{
+		{salice},
+		{salice + ",1,memo,extra"},
+		{"alice,1"},
+		{codec.MustAddressBech32("morpheus", alice) + ",1"},
+		{salice + ",one"},
+		{salice + ",0"},
+		{salice + ",-1"},
+		{salice + ",1," + strings.Repeat("a", actions.MaxMemoSize+1)},
+		{salice + ",1", sbob + ",1", salice + ",1"}, // identical transfers
+		{salice + ",1", "address,amount"},           // header after the first row
+	} {
+		_, err := parseAirdropFile(writeAirdropFile(t, rows...), 9)
+		require.ErrorIs(err, ErrInvalidRecipient, rows)
+	}
+	_, err = parseAirdropFile(filepath.Join(t.TempDir(), "missing.csv"), 9)
+	require.Error(err)
+}
+
+func TestLoadAirdropProgress(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "airdrop.csv.progress")
+	asset := ids.GenerateTestID()
+
+	// Airdrops start without any progress
+	progress, err := loadAirdropProgress(path, "alice", asset)
+	require.NoError(err)
+	require.Equal(&airdropProgress{Sender: "alice", Asset: asset, Txs: map[int]*airdropTx{}}, progress)
+
+	// Progress can only be resumed by the same sender for the same asset
+	progress.Txs[2] = &airdropTx{TxID: ids.GenerateTestID(), Expiry: 10, Status: airdropAccepted}
+	progress.Txs[3] = &airdropTx{TxID: ids.GenerateTestID(), Expiry: 20, Status: airdropPending}
+	require.NoError(cli.SaveJSON(path, progress))
+	loaded, err := loadAirdropProgress(path, "alice", asset)
+	require.NoError(err)
+	require.Equal(progress, loaded)
+	_, err = loadAirdropProgress(path, "bob", asset)
+	require.ErrorIs(err, ErrAirdropMismatch)
+	_, err = loadAirdropProgress(path, "alice", ids.Empty)
+	require.ErrorIs(err, ErrAirdropMismatch)
+}
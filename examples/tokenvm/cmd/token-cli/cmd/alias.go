@@ -0,0 +1,74 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use: "alias",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var addAliasCmd = &cobra.Command{
+	Use: "add [alias] [address]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().AddAlias(args[0], args[1])
+	},
+}
+
+var removeAliasCmd = &cobra.Command{
+	Use: "remove [alias]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().RemoveAlias(args[0])
+	},
+}
+
+var listAliasCmd = &cobra.Command{
+	Use: "list",
+	RunE: func(*cobra.Command, []string) error {
+		return handler.Root().PrintAliases()
+	},
+}
+
+var importAliasCmd = &cobra.Command{
+	Use: "import [path]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().ImportAliases(args[0])
+	},
+}
+
+var exportAliasCmd = &cobra.Command{
+	Use: "export [path]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().ExportAliases(args[0])
+	},
+}
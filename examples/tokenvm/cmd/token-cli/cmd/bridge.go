@@ -0,0 +1,166 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//nolint:lll
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/rpc"
+	hutils "github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var bridgeCmd = &cobra.Command{
+	Use: "bridge",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var bridgeTransferCmd = &cobra.Command{
+	Use: "transfer",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		currentChainID, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select token to bridge (assets imported from another chain can only
+		// be bridged back to it)
+		assetID, err := handler.Root().PromptAsset("assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, balance, sourceChainID, err := handler.GetAssetInfo(ctx, tcli, priv.Address, assetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+		ret := sourceChainID != ids.Empty
+		destination := sourceChainID
+		if !ret {
+			destination, _, err = handler.Root().PromptChain("destination", set.Of(currentChainID))
+			if err != nil {
+				return err
+			}
+		}
+
+		// Select recipient and amount
+		recipient, err := handler.Root().PromptAddress("recipient (on destination)")
+		if err != nil {
+			return err
+		}
+		amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Lock (or burn, if returning) the asset on this chain
+		success, txID, err := sendAndWait(ctx, nil, &actions.ExportAsset{
+			To:          recipient,
+			Asset:       assetID,
+			Value:       amount,
+			Return:      ret,
+			Destination: destination,
+		}, cli, scli, tcli, factory, true)
+		if err != nil {
+			return err
+		}
+		if !success {
+			return errors.New("not successful")
+		}
+
+		// Mint (or unlock, if returning) the asset on the destination
+		uris, err := handler.Root().GetChain(destination)
+		if err != nil {
+			return err
+		}
+		networkID, _, _, err := cli.Network(ctx)
+		if err != nil {
+			return err
+		}
+		dcli := rpc.NewJSONRPCClient(uris[0])
+		dscli, err := rpc.NewWebSocketClient(uris[0], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+		if err != nil {
+			return err
+		}
+		dtcli := trpc.NewJSONRPCClient(uris[0], networkID, destination)
+		if err := performImport(ctx, cli, dcli, dscli, dtcli, txID, factory); err != nil {
+			return err
+		}
+
+		// Check that the supply on both chains still matches
+		if !ret {
+			return reconcileBridge(ctx, tcli, dtcli, assetID)
+		}
+		_, _, _, metadata, _, _, _, err := tcli.Asset(ctx, assetID, false)
+		if err != nil {
+			return err
+		}
+		return reconcileBridge(ctx, dtcli, tcli, ids.ID(metadata[:hconsts.IDLen]))
+	},
+}
+
+var bridgeReconcileCmd = &cobra.Command{
+	Use: "reconcile",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		currentChainID, _, _, cli, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select asset native to this chain and the chain it was bridged to
+		assetID, err := handler.Root().PromptAsset("assetID (native to this chain)", true)
+		if err != nil {
+			return err
+		}
+		destination, uris, err := handler.Root().PromptChain("destination", set.Of(currentChainID))
+		if err != nil {
+			return err
+		}
+		networkID, _, _, err := cli.Network(ctx)
+		if err != nil {
+			return err
+		}
+		return reconcileBridge(ctx, tcli, trpc.NewJSONRPCClient(uris[0], networkID, destination), assetID)
+	},
+}
+
+// reconcileBridge prints the supply of [assetID] bridged from the chain of
+// [source] to the chain of [destination].
+func reconcileBridge(
+	ctx context.Context,
+	source *trpc.JSONRPCClient,
+	destination *trpc.JSONRPCClient,
+	assetID ids.ID,
+) error {
+	supply, err := trpc.ReconcileBridge(ctx, source, destination, assetID)
+	if err != nil {
+		return err
+	}
+	hutils.Outf(
+		"{{yellow}}asset:{{/}} %s {{yellow}}imported asset:{{/}} %s {{yellow}}locked:{{/}} %d {{yellow}}minted:{{/}} %d {{yellow}}in flight:{{/}} %d\n",
+		supply.Asset,
+		supply.ImportedAsset,
+		supply.Locked,
+		supply.Minted,
+		supply.InFlight,
+	)
+	return nil
+}
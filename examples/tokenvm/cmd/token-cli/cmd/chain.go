@@ -6,11 +6,13 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/hypersdk/examples/tokenvm/controller"
 	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
 )
 
@@ -66,7 +68,7 @@ var watchChainCmd = &cobra.Command{
 	Use: "watch",
 	RunE: func(_ *cobra.Command, args []string) error {
 		var cli *trpc.JSONRPCClient
-		return handler.Root().WatchChain(hideTxs, func(uri string, networkID uint32, chainID ids.ID) (chain.Parser, error) {
+		return handler.Root().WatchChain(hideTxs, jsonTxs, func(uri string, networkID uint32, chainID ids.ID) (chain.Parser, error) {
 			cli = trpc.NewJSONRPCClient(uri, networkID, chainID)
 			return cli.Parser(context.TODO())
 		}, func(tx *chain.Transaction, result *chain.Result) {
@@ -78,3 +80,62 @@ var watchChainCmd = &cobra.Command{
 		})
 	},
 }
+
+var replayChainCmd = &cobra.Command{
+	Use: "replay [chain data dir]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		chainID, err := ids.FromString(replayChainID)
+		if err != nil {
+			return err
+		}
+		genesisBytes, err := os.ReadFile(genesisFile)
+		if err != nil {
+			return err
+		}
+		return handler.Root().ReplayBlocks(
+			controller.New,
+			args[0],
+			genesisBytes,
+			replayNetworkID,
+			chainID,
+			replayStart,
+			replayEnd,
+			replayShowUnits,
+			replayTraceDir,
+		)
+	},
+}
+
+var digestChainCmd = &cobra.Command{
+	Use: "digest [chain data dir]",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		chainID, err := ids.FromString(replayChainID)
+		if err != nil {
+			return err
+		}
+		genesisBytes, err := os.ReadFile(genesisFile)
+		if err != nil {
+			return err
+		}
+		return handler.Root().DigestState(
+			controller.New,
+			args[0],
+			genesisBytes,
+			replayNetworkID,
+			chainID,
+			digestOut,
+		)
+	},
+}
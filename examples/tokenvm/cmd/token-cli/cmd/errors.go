@@ -11,4 +11,8 @@ var (
 	ErrNotMultiple        = errors.New("must be a multiple")
 	ErrInsufficientSupply = errors.New("insufficient supply")
 	ErrMustFill           = errors.New("must fill")
+	ErrNothingToWatch     = errors.New("must specify an address or asset to watch")
+	ErrWrongSigner        = errors.New("default key is not the sender")
+	ErrInvalidRecipient   = errors.New("invalid recipient")
+	ErrAirdropMismatch    = errors.New("airdrop progress does not match")
 )
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/crypto/hd"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 
@@ -86,6 +87,68 @@ var importKeyCmd = &cobra.Command{
 	},
 }
 
+// storeDerivedKey stores the ed25519 key at [derivationPath] of [seed] as the
+// default key.
+func storeDerivedKey(seed []byte) (*cli.PrivateKey, error) {
+	p, err := hd.DeriveED25519(seed, derivationPath)
+	if err != nil {
+		return nil, err
+	}
+	priv := &cli.PrivateKey{
+		Address: auth.NewED25519Address(p.PublicKey()),
+		Bytes:   p[:],
+	}
+	if err := handler.h.StoreKey(priv); err != nil {
+		return nil, err
+	}
+	return priv, handler.h.StoreDefaultKey(priv.Address)
+}
+
+var mnemonicKeyCmd = &cobra.Command{
+	Use: "mnemonic",
+	RunE: func(*cobra.Command, []string) error {
+		mnemonic, err := hd.NewMnemonic()
+		if err != nil {
+			return err
+		}
+		seed, err := hd.NewSeed(mnemonic, "")
+		if err != nil {
+			return err
+		}
+		priv, err := storeDerivedKey(seed)
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{yellow}}mnemonic (keep it secret, it can recover all your keys):{{/}} %s\n", mnemonic)
+		utils.Outf(
+			"{{green}}created address:{{/}} %s {{yellow}}path:{{/}} %s\n",
+			codec.MustAddressBech32(tconsts.HRP, priv.Address),
+			derivationPath,
+		)
+		return nil
+	},
+}
+
+var recoverKeyCmd = &cobra.Command{
+	Use: "recover",
+	RunE: func(*cobra.Command, []string) error {
+		seed, err := handler.Root().PromptSeed()
+		if err != nil {
+			return err
+		}
+		priv, err := storeDerivedKey(seed)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}recovered address:{{/}} %s {{yellow}}path:{{/}} %s\n",
+			codec.MustAddressBech32(tconsts.HRP, priv.Address),
+			derivationPath,
+		)
+		return nil
+	},
+}
+
 func lookupSetKeyBalance(choice int, address string, uri string, networkID uint32, chainID ids.ID) error {
 	// TODO: just load once
 	cli := trpc.NewJSONRPCClient(uri, networkID, chainID)
@@ -111,9 +174,27 @@ var setKeyCmd = &cobra.Command{
 }
 
 func lookupKeyBalance(addr codec.Address, uri string, networkID uint32, chainID ids.ID, assetID ids.ID) error {
-	_, _, _, _, err := handler.GetAssetInfo(
-		context.TODO(), trpc.NewJSONRPCClient(uri, networkID, chainID),
-		addr, assetID, true)
+	tcli := trpc.NewJSONRPCClient(uri, networkID, chainID)
+	if handler.Root().JSON() {
+		saddr := codec.MustAddressBech32(tconsts.HRP, addr)
+		_, symbol, decimals, _, _, _, _, err := tcli.Asset(context.TODO(), assetID, true)
+		if err != nil {
+			return err
+		}
+		balance, err := tcli.Balance(context.TODO(), saddr, assetID)
+		if err != nil {
+			return err
+		}
+		return handler.Root().PrintJSON(&cli.BalanceOutput{
+			URI:      uri,
+			Address:  saddr,
+			Asset:    assetID,
+			Symbol:   string(symbol),
+			Decimals: decimals,
+			Balance:  balance,
+		})
+	}
+	_, _, _, _, err := handler.GetAssetInfo(context.TODO(), tcli, addr, assetID, true)
 	return err
 }
 
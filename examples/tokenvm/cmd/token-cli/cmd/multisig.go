@@ -0,0 +1,359 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+// multisigProposal is an unsigned transaction that is passed between the
+// signers of a multisig to collect their signatures offline.
+type multisigProposal struct {
+	Signers    []string         `json:"signers"` // hex public keys
+	Threshold  uint8            `json:"threshold"`
+	Digest     string           `json:"digest"`     // hex unsigned transaction
+	Signatures map[uint8]string `json:"signatures"` // hex signatures by signer index
+}
+
+func (m *multisigProposal) signers() ([]ed25519.PublicKey, error) {
+	signers := make([]ed25519.PublicKey, len(m.Signers))
+	for i, signer := range m.Signers {
+		pk, err := parsePublicKey(signer)
+		if err != nil {
+			return nil, err
+		}
+		signers[i] = pk
+	}
+	return signers, nil
+}
+
+func parsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ed25519.EmptyPublicKey, err
+	}
+	if len(b) != ed25519.PublicKeyLen {
+		return ed25519.EmptyPublicKey, ErrInvalidArgs
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+func loadProposal(path string) (*multisigProposal, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m multisigProposal
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func saveProposal(path string, m *multisigProposal) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.SaveBytes(path, b)
+}
+
+func promptSigners() ([]ed25519.PublicKey, uint8, error) {
+	numSigners, err := handler.Root().PromptInt("number of signers", auth.MaxMultisigSigners)
+	if err != nil {
+		return nil, 0, err
+	}
+	threshold, err := handler.Root().PromptInt("threshold", numSigners)
+	if err != nil {
+		return nil, 0, err
+	}
+	signers := make([]ed25519.PublicKey, numSigners)
+	for i := range signers {
+		raw, err := handler.Root().PromptString(
+			fmt.Sprintf("public key %d", i),
+			hex.EncodedLen(ed25519.PublicKeyLen),
+			hex.EncodedLen(ed25519.PublicKeyLen),
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		signers[i], err = parsePublicKey(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return signers, uint8(threshold), nil
+}
+
+func proposalArgs(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return ErrInvalidArgs
+	}
+	return nil
+}
+
+var multisigCmd = &cobra.Command{
+	Use: "multisig",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var publicKeyMultisigCmd = &cobra.Command{
+	Use: "public-key",
+	RunE: func(*cobra.Command, []string) error {
+		_, priv, err := handler.Root().GetDefaultKey(true)
+		if err != nil {
+			return err
+		}
+		pk := ed25519.PrivateKey(priv).PublicKey()
+		utils.Outf("{{green}}public key:{{/}} %s\n", hex.EncodeToString(pk[:]))
+		return nil
+	},
+}
+
+var addressMultisigCmd = &cobra.Command{
+	Use: "address",
+	RunE: func(*cobra.Command, []string) error {
+		signers, threshold, err := promptSigners()
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}multisig address:{{/}} %s\n",
+			codec.MustAddressBech32(tconsts.HRP, auth.NewMultisigAddress(signers, threshold)),
+		)
+		return nil
+	},
+}
+
+var transferMultisigCmd = &cobra.Command{
+	Use:     "transfer [proposal]",
+	PreRunE: proposalArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		_, _, _, cli, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select multisig
+		signers, threshold, err := promptSigners()
+		if err != nil {
+			return err
+		}
+		addr := auth.NewMultisigAddress(signers, threshold)
+		utils.Outf("{{yellow}}multisig address:{{/}} %s\n", codec.MustAddressBech32(tconsts.HRP, addr))
+
+		// Select token to send
+		assetID, err := handler.Root().PromptAsset("assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, addr, assetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Select amount
+		amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Generate unsigned transaction
+		action := &actions.Transfer{
+			To:    recipient,
+			Asset: assetID,
+			Value: amount,
+		}
+		parser, err := tcli.Parser(ctx)
+		if err != nil {
+			return err
+		}
+		unitPrices, err := cli.UnitPrices(ctx, false)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		rules := parser.Rules(now)
+		maxUnits, err := chain.EstimateMaxUnits(rules, action, auth.NewMultisigFactory(signers, threshold), nil)
+		if err != nil {
+			return err
+		}
+		maxFee, err := chain.EstimateFee(rules, unitPrices, maxUnits)
+		if err != nil {
+			return err
+		}
+		base := &chain.Base{
+			Timestamp: utils.UnixRMilli(now, rules.GetValidityWindow()),
+			ChainID:   rules.ChainID(),
+			MaxFee:    maxFee,
+		}
+		digest, err := chain.NewTx(base, nil, action).Digest()
+		if err != nil {
+			return err
+		}
+
+		proposal := &multisigProposal{
+			Signers:    make([]string, len(signers)),
+			Threshold:  threshold,
+			Digest:     hex.EncodeToString(digest),
+			Signatures: map[uint8]string{},
+		}
+		for i, signer := range signers {
+			proposal.Signers[i] = hex.EncodeToString(signer[:])
+		}
+		if err := saveProposal(args[0], proposal); err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}created proposal:{{/}} %s {{yellow}}expires:{{/}} %s\n",
+			args[0],
+			time.UnixMilli(base.Timestamp).Format(time.RFC3339),
+		)
+		return nil
+	},
+}
+
+var signMultisigCmd = &cobra.Command{
+	Use:     "sign [proposal]",
+	PreRunE: proposalArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		proposal, err := loadProposal(args[0])
+		if err != nil {
+			return err
+		}
+		signers, err := proposal.signers()
+		if err != nil {
+			return err
+		}
+		digest, err := hex.DecodeString(proposal.Digest)
+		if err != nil {
+			return err
+		}
+		_, rpriv, err := handler.Root().GetDefaultKey(true)
+		if err != nil {
+			return err
+		}
+		priv := ed25519.PrivateKey(rpriv)
+		pk := priv.PublicKey()
+		for i, signer := range signers {
+			if signer != pk {
+				continue
+			}
+			sig := ed25519.Sign(digest, priv)
+			proposal.Signatures[uint8(i)] = hex.EncodeToString(sig[:])
+			if err := saveProposal(args[0], proposal); err != nil {
+				return err
+			}
+			utils.Outf(
+				"{{green}}signed proposal:{{/}} %s {{yellow}}signatures:{{/}} %d/%d\n",
+				args[0],
+				len(proposal.Signatures),
+				proposal.Threshold,
+			)
+			return nil
+		}
+		return auth.ErrNotEnoughSigners
+	},
+}
+
+var submitMultisigCmd = &cobra.Command{
+	Use:     "submit [proposal]",
+	PreRunE: proposalArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		_, _, _, cli, _, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+		proposal, err := loadProposal(args[0])
+		if err != nil {
+			return err
+		}
+		signers, err := proposal.signers()
+		if err != nil {
+			return err
+		}
+		digest, err := hex.DecodeString(proposal.Digest)
+		if err != nil {
+			return err
+		}
+		signatures := make(map[uint8]ed25519.Signature, len(proposal.Signatures))
+		for i, rsig := range proposal.Signatures {
+			sig, err := hex.DecodeString(rsig)
+			if err != nil {
+				return err
+			}
+			if len(sig) != ed25519.SignatureLen {
+				return ErrInvalidArgs
+			}
+			signatures[i] = ed25519.Signature(sig)
+		}
+		multisig, err := auth.NewMultisig(signers, proposal.Threshold, signatures)
+		if err != nil {
+			return err
+		}
+
+		// Ensure the transaction is valid before submitting it
+		p := codec.NewWriter(len(digest)+consts.ByteLen+multisig.Size(), consts.NetworkSizeLimit)
+		p.PackFixedBytes(digest)
+		p.PackByte(multisig.GetTypeID())
+		multisig.Marshal(p)
+		if err := p.Err(); err != nil {
+			return err
+		}
+		parser, err := tcli.Parser(ctx)
+		if err != nil {
+			return err
+		}
+		actionRegistry, authRegistry := parser.Registry()
+		tx, err := chain.UnmarshalTx(codec.NewReader(p.Bytes(), consts.NetworkSizeLimit), actionRegistry, authRegistry)
+		if err != nil {
+			return err
+		}
+		if err := tx.Auth.Verify(ctx, digest); err != nil {
+			return err
+		}
+
+		txID, err := cli.SubmitTx(ctx, tx.Bytes())
+		if err != nil {
+			return err
+		}
+		var status chain.Status
+		if err := rpc.Wait(ctx, func(ctx context.Context) (bool, error) {
+			found, s, _, _, err := tcli.Tx(ctx, txID)
+			status = s
+			return found, err
+		}); err != nil {
+			return err
+		}
+		handler.Root().PrintStatus(txID, status)
+		return nil
+	},
+}
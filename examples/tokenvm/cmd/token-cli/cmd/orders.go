@@ -0,0 +1,81 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+)
+
+// OrdersOutput is printed by [ordersCmd] when using JSON output.
+type OrdersOutput struct {
+	Orders []*orderbook.Order `json:"orders"`
+}
+
+var ordersCmd = &cobra.Command{
+	Use: "orders [in assetID] [out assetID]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		inAssetID, err := parseAssetID(args[0])
+		if err != nil {
+			return err
+		}
+		outAssetID, err := parseAssetID(args[1])
+		if err != nil {
+			return err
+		}
+		chainID, uris, err := handler.Root().GetDefaultChain(true)
+		if err != nil {
+			return err
+		}
+		networkID, _, _, err := rpc.NewJSONRPCClient(uris[0]).Network(ctx)
+		if err != nil {
+			return err
+		}
+		tcli := trpc.NewJSONRPCClient(uris[0], networkID, chainID)
+		orders, err := tcli.Orders(ctx, actions.PairID(inAssetID, outAssetID))
+		if err != nil {
+			return err
+		}
+		if handler.Root().JSON() {
+			return handler.Root().PrintJSON(&OrdersOutput{Orders: orders})
+		}
+		_, inSymbol, inDecimals, _, _, _, _, err := tcli.Asset(ctx, inAssetID, true)
+		if err != nil {
+			return err
+		}
+		_, outSymbol, outDecimals, _, _, _, _, err := tcli.Asset(ctx, outAssetID, true)
+		if err != nil {
+			return err
+		}
+		utils.Outf("{{cyan}}available orders:{{/}} %d\n", len(orders))
+		for _, order := range orders {
+			utils.Outf(
+				"{{cyan}}orderID:{{/}} %s {{cyan}}Rate(in/out):{{/}} %.4f {{cyan}}InTick:{{/}} %s %s {{cyan}}OutTick:{{/}} %s %s {{cyan}}Remaining:{{/}} %s %s\n", //nolint:lll
+				order.ID,
+				float64(order.InTick)/float64(order.OutTick),
+				utils.FormatBalance(order.InTick, inDecimals),
+				inSymbol,
+				utils.FormatBalance(order.OutTick, outDecimals),
+				outSymbol,
+				utils.FormatBalance(order.Remaining, outDecimals),
+				outSymbol,
+			)
+		}
+		return nil
+	},
+}
@@ -53,16 +53,16 @@ func sendAndWait(
 		utils.Outf("{{yellow}}skipping unexpected transaction:{{/}} %s\n", tx.ID())
 	}
 	if printStatus {
-		handler.Root().PrintStatus(tx.ID(), res.Success)
+		handler.Root().PrintStatus(tx.ID(), res.Status)
 	}
-	return res.Success, tx.ID(), nil
+	return res.Success(), tx.ID(), nil
 }
 
 func handleTx(c *trpc.JSONRPCClient, tx *chain.Transaction, result *chain.Result) {
 	summaryStr := string(result.Output)
 	actor := tx.Auth.Actor()
 	status := "❌"
-	if result.Success {
+	if result.Success() {
 		status = "✅"
 		switch action := tx.Action.(type) {
 		case *actions.CreateAsset:
@@ -106,7 +106,7 @@ func handleTx(c *trpc.JSONRPCClient, tx *chain.Transaction, result *chain.Result
 			supplyStr := utils.FormatBalance(action.Supply, outDecimals)
 			summaryStr = fmt.Sprintf("%s %s -> %s %s (supply: %s %s)", inTickStr, inSymbol, outTickStr, outSymbol, supplyStr, outSymbol)
 		case *actions.FillOrder:
-			or, _ := actions.UnmarshalOrderResult(result.Output)
+			or, _ := trpc.DecodeFillOrderResult(result)
 			_, inSymbol, inDecimals, _, _, _, _, err := c.Asset(context.TODO(), action.In, true)
 			if err != nil {
 				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
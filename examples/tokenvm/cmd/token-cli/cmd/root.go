@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/crypto/hd"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/spf13/cobra"
 )
@@ -22,14 +25,20 @@ var (
 	handler *Handler
 
 	dbPath                string
+	outputFormat          string
 	genesisFile           string
 	minBlockGap           int64
 	minUnitPrice          []string
 	maxBlockUnits         []string
 	windowTargetUnits     []string
 	hideTxs               bool
+	jsonTxs               bool
 	randomRecipient       bool
 	maxTxBacklog          int
+	spamProfile           string
+	startTPS              int
+	targetTPS             int
+	rampDuration          time.Duration
 	checkAllChains        bool
 	prometheusBaseURI     string
 	prometheusOpenBrowser bool
@@ -37,7 +46,19 @@ var (
 	prometheusData        string
 	startPrometheus       bool
 	maxFee                int64
+	replayNetworkID       uint32
+	replayChainID         string
+	replayStart           uint64
+	replayEnd             uint64
+	replayShowUnits       bool
+	replayTraceDir        string
+	digestOut             string
 	numCores              int
+	derivationPath        string
+	watchAddresses        []string
+	watchAssets           []string
+	airdropFile           string
+	airdropAsset          string
 
 	rootCmd = &cobra.Command{
 		Use:        "token-cli",
@@ -51,10 +72,17 @@ func init() {
 	rootCmd.AddCommand(
 		genesisCmd,
 		keyCmd,
+		aliasCmd,
 		chainCmd,
 		actionCmd,
+		multisigCmd,
+		txCmd,
+		ordersCmd,
 		spamCmd,
 		prometheusCmd,
+		watchCmd,
+		airdropCmd,
+		bridgeCmd,
 	)
 	rootCmd.PersistentFlags().StringVar(
 		&dbPath,
@@ -62,13 +90,23 @@ func init() {
 		defaultDatabase,
 		"path to database (will create it missing)",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&outputFormat,
+		"output",
+		cli.TextOutput,
+		fmt.Sprintf("output format (%s or %s)", cli.TextOutput, cli.JSONOutput),
+	)
 	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
-		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		controller := NewController(dbPath)
 		root, err := cli.New(controller)
 		if err != nil {
 			return err
 		}
+		if err := root.SetOutput(outputFormat); err != nil {
+			_ = root.CloseDatabase()
+			return err
+		}
+		utils.Outf("{{yellow}}database:{{/}} %s\n", dbPath)
 		handler = NewHandler(root)
 		return nil
 	}
@@ -125,14 +163,33 @@ func init() {
 		4,
 		"number of cores to use when searching for faucet solutions",
 	)
+	for _, cmd := range []*cobra.Command{mnemonicKeyCmd, recoverKeyCmd} {
+		cmd.PersistentFlags().StringVar(
+			&derivationPath,
+			"path",
+			hd.DefaultPath,
+			"derivation path of the key",
+		)
+	}
 	keyCmd.AddCommand(
 		genKeyCmd,
 		importKeyCmd,
+		mnemonicKeyCmd,
+		recoverKeyCmd,
 		setKeyCmd,
 		balanceKeyCmd,
 		faucetKeyCmd,
 	)
 
+	// alias
+	aliasCmd.AddCommand(
+		addAliasCmd,
+		removeAliasCmd,
+		listAliasCmd,
+		importAliasCmd,
+		exportAliasCmd,
+	)
+
 	// chain
 	watchChainCmd.PersistentFlags().BoolVar(
 		&hideTxs,
@@ -140,6 +197,78 @@ func init() {
 		false,
 		"hide txs",
 	)
+	watchChainCmd.PersistentFlags().BoolVar(
+		&jsonTxs,
+		"json",
+		false,
+		"print txs as JSON",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&genesisFile,
+		"genesis-file",
+		defaultGenesis,
+		"genesis file path",
+	)
+	replayChainCmd.PersistentFlags().Uint32Var(
+		&replayNetworkID,
+		"network-id",
+		constants.LocalID,
+		"network id of the chain",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&replayChainID,
+		"chain-id",
+		"",
+		"chain id of the chain",
+	)
+	replayChainCmd.PersistentFlags().Uint64Var(
+		&replayStart,
+		"start",
+		1,
+		"first height to report",
+	)
+	replayChainCmd.PersistentFlags().Uint64Var(
+		&replayEnd,
+		"end",
+		0,
+		"last height to replay",
+	)
+	replayChainCmd.PersistentFlags().BoolVar(
+		&replayShowUnits,
+		"show-units",
+		false,
+		"show units consumed by each tx",
+	)
+	replayChainCmd.PersistentFlags().StringVar(
+		&replayTraceDir,
+		"trace-dir",
+		"",
+		"directory to write the trace of each block to",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&genesisFile,
+		"genesis-file",
+		defaultGenesis,
+		"genesis file path",
+	)
+	digestChainCmd.PersistentFlags().Uint32Var(
+		&replayNetworkID,
+		"network-id",
+		constants.LocalID,
+		"network id of the chain",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&replayChainID,
+		"chain-id",
+		"",
+		"chain id of the chain",
+	)
+	digestChainCmd.PersistentFlags().StringVar(
+		&digestOut,
+		"out",
+		"",
+		"file to write the digest to as JSON",
+	)
 	chainCmd.AddCommand(
 		importChainCmd,
 		importANRChainCmd,
@@ -147,6 +276,52 @@ func init() {
 		setChainCmd,
 		chainInfoCmd,
 		watchChainCmd,
+		replayChainCmd,
+		digestChainCmd,
+	)
+
+	// watch
+	watchCmd.PersistentFlags().StringSliceVar(
+		&watchAddresses,
+		"address",
+		[]string{},
+		"addresses to watch",
+	)
+	watchCmd.PersistentFlags().StringSliceVar(
+		&watchAssets,
+		"asset",
+		[]string{},
+		fmt.Sprintf("assets to watch (use %s for native token)", consts.Symbol),
+	)
+
+	// airdrop
+	airdropCmd.PersistentFlags().StringVar(
+		&airdropFile,
+		"file",
+		"",
+		"csv file of recipients (address,amount[,memo])",
+	)
+	airdropCmd.PersistentFlags().StringVar(
+		&airdropAsset,
+		"asset",
+		consts.Symbol,
+		fmt.Sprintf("asset to airdrop (use %s for native token)", consts.Symbol),
+	)
+
+	// multisig
+	multisigCmd.AddCommand(
+		publicKeyMultisigCmd,
+		addressMultisigCmd,
+		transferMultisigCmd,
+		signMultisigCmd,
+		submitMultisigCmd,
+	)
+
+	// tx
+	txCmd.AddCommand(
+		buildTxCmd,
+		signTxCmd,
+		broadcastTxCmd,
 	)
 
 	// actions
@@ -167,6 +342,12 @@ func init() {
 		exportAssetCmd,
 	)
 
+	// bridge
+	bridgeCmd.AddCommand(
+		bridgeTransferCmd,
+		bridgeReconcileCmd,
+	)
+
 	// spam
 	runSpamCmd.PersistentFlags().BoolVar(
 		&randomRecipient,
@@ -186,6 +367,30 @@ func init() {
 		-1,
 		"max fee per tx",
 	)
+	runSpamCmd.PersistentFlags().StringVar(
+		&spamProfile,
+		"profile",
+		cli.UniformProfile,
+		"workload profile",
+	)
+	runSpamCmd.PersistentFlags().IntVar(
+		&startTPS,
+		"start-tps",
+		0,
+		"tps to issue at the start of the ramp",
+	)
+	runSpamCmd.PersistentFlags().IntVar(
+		&targetTPS,
+		"target-tps",
+		0,
+		"tps to ramp to (0 to issue a fixed number of txs per account)",
+	)
+	runSpamCmd.PersistentFlags().DurationVar(
+		&rampDuration,
+		"ramp-duration",
+		0,
+		"duration over which tps is ramped to target",
+	)
 	spamCmd.AddCommand(
 		runSpamCmd,
 	)
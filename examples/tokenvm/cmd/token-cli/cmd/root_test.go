@@ -0,0 +1,69 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+// execute runs token-cli with [args] against the database in [dir] and
+// returns what it printed to stdout in JSON mode (text is only ever written
+// to the terminal).
+func execute(t *testing.T, dir string, args ...string) (string, error) {
+	require := require.New(t)
+
+	// Flags keep the values of previous runs unless they are reset
+	outputFormat = cli.TextOutput
+	watchAddresses = nil
+	watchAssets = nil
+	airdropFile = ""
+	airdropAsset = consts.Symbol
+	spamProfile = cli.UniformProfile
+	startTPS = 0
+	targetTPS = 0
+	rampDuration = 0
+
+	r, w, err := os.Pipe()
+	require.NoError(err)
+	stdout := os.Stdout
+	os.Stdout = w
+	rootCmd.SetArgs(append(args, "--database", dir))
+	err = rootCmd.Execute()
+	os.Stdout = stdout
+	require.NoError(w.Close())
+	out, rerr := io.ReadAll(r)
+	require.NoError(rerr)
+
+	// The database is only closed by successful commands
+	if handler != nil {
+		require.NoError(handler.Root().CloseDatabase())
+	}
+	return string(out), err
+}
+
+func TestOutputFlag(t *testing.T) {
+	require := require.New(t)
+
+	tests := []struct {
+		format string
+		out    string
+		err    error
+	}{
+		{cli.TextOutput, "", nil},
+		{cli.JSONOutput, "[]\n", nil},
+		{"yaml", "", cli.ErrUnknownOutput},
+	}
+	for _, tt := range tests {
+		out, err := execute(t, t.TempDir(), "alias", "list", "--output", tt.format)
+		require.ErrorIs(err, tt.err, tt.format)
+		require.Equal(tt.out, out, tt.format)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+	"github.com/ava-labs/hypersdk/loadgen"
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/utils"
@@ -28,6 +29,15 @@ var spamCmd = &cobra.Command{
 	},
 }
 
+// spamRamp returns the ramp configured by the spam flags (or nil if a fixed
+// number of txs should be issued per account).
+func spamRamp() *loadgen.TPSRamp {
+	if targetTPS <= 0 {
+		return nil
+	}
+	return &loadgen.TPSRamp{Start: startTPS, Target: targetTPS, Duration: rampDuration}
+}
+
 var runSpamCmd = &cobra.Command{
 	Use: "run",
 	RunE: func(*cobra.Command, []string) error {
@@ -38,7 +48,7 @@ var runSpamCmd = &cobra.Command{
 			v := uint64(maxFee)
 			maxFeeParsed = &v
 		}
-		return handler.Root().Spam(maxTxBacklog, maxFeeParsed, randomRecipient,
+		return handler.Root().Spam(maxTxBacklog, maxFeeParsed, randomRecipient, spamProfile, spamRamp(),
 			func(uri string, networkID uint32, chainID ids.ID) error { // createClient
 				tclient = trpc.NewJSONRPCClient(uri, networkID, chainID)
 				sc, err := rpc.NewWebSocketClient(uri, rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
@@ -94,6 +104,7 @@ var runSpamCmd = &cobra.Command{
 					return err
 				}
 			},
+			spamProfiles(),
 		)
 	},
 }
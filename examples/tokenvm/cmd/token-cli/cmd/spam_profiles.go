@@ -0,0 +1,92 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//nolint:gosec
+package cmd
+
+import (
+	"math/rand"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/loadgen"
+)
+
+const (
+	orderbookProfile = "orderbook"
+	payloadProfile   = "payload"
+
+	// Number of orders an account keeps open before it starts closing them.
+	maxOpenOrders = 16
+)
+
+// orderbookAsset is the (non-existent) asset that spam orders request in
+// exchange for the native asset. Because nobody holds it, orders are never
+// filled and churn comes entirely from creating and closing orders.
+var orderbookAsset = ids.ID{'s', 'p', 'a', 'm'}
+
+func spamProfiles() map[string]loadgen.Profile {
+	return map[string]loadgen.Profile{
+		orderbookProfile: func(int, []codec.Address) loadgen.Workload {
+			return &orderbookWorkload{}
+		},
+		payloadProfile: func(self int, accounts []codec.Address) loadgen.Workload {
+			memo := make([]byte, actions.MaxMemoSize)
+			_, _ = rand.Read(memo)
+			return &payloadWorkload{self: self, accounts: accounts, memo: memo}
+		},
+	}
+}
+
+// orderbookWorkload alternates between creating orders and closing the
+// oldest order it has open.
+type orderbookWorkload struct {
+	open []ids.ID
+}
+
+func (w *orderbookWorkload) Next(i int) (chain.Action, uint64, error) {
+	if len(w.open) > maxOpenOrders && i%2 == 1 {
+		order := w.open[0]
+		w.open = w.open[1:]
+		return &actions.CloseOrder{Order: order, Out: ids.Empty}, 0, nil
+	}
+	supply := uint64(i + 1) // prevent duplicate txs
+	return &actions.CreateOrder{
+		In:      orderbookAsset,
+		InTick:  1,
+		Out:     ids.Empty,
+		OutTick: 1,
+		Supply:  supply,
+	}, supply, nil
+}
+
+func (w *orderbookWorkload) Issued(action chain.Action, txID ids.ID) {
+	if _, ok := action.(*actions.CreateOrder); ok {
+		w.open = append(w.open, txID)
+	}
+}
+
+// payloadWorkload sends transfers carrying the largest allowed memo.
+type payloadWorkload struct {
+	self     int
+	accounts []codec.Address
+	memo     []byte
+}
+
+func (w *payloadWorkload) Next(i int) (chain.Action, uint64, error) {
+	index := rand.Intn(len(w.accounts))
+	if index == w.self {
+		index = (index + 1) % len(w.accounts)
+	}
+	value := uint64(i + 1) // prevent duplicate txs
+	return &actions.Transfer{
+		To:    w.accounts[index],
+		Asset: ids.Empty,
+		Value: value,
+		Memo:  w.memo,
+	}, value, nil
+}
+
+func (*payloadWorkload) Issued(chain.Action, ids.ID) {}
@@ -0,0 +1,121 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/loadgen"
+)
+
+func TestSpamFlags(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		args    []string
+		profile string
+		ramp    *loadgen.TPSRamp
+		err     error
+	}{
+		{nil, cli.UniformProfile, nil, cli.ErrNoChains},
+		{[]string{"--profile", cli.ZipfianProfile}, cli.ZipfianProfile, nil, cli.ErrNoChains},
+		{[]string{"--profile", orderbookProfile}, orderbookProfile, nil, cli.ErrNoChains},
+		{[]string{"--profile", payloadProfile}, payloadProfile, nil, cli.ErrNoChains},
+		{[]string{"--profile", "bursty"}, "bursty", nil, cli.ErrUnknownProfile},
+		{
+			[]string{"--start-tps", "10", "--target-tps", "100", "--ramp-duration", "1m"},
+			cli.UniformProfile,
+			&loadgen.TPSRamp{Start: 10, Target: 100, Duration: time.Minute},
+			cli.ErrNoChains,
+		},
+		{
+			// Without a target, a fixed number of txs is issued
+			[]string{"--start-tps", "10", "--ramp-duration", "1m"},
+			cli.UniformProfile,
+			nil,
+			cli.ErrNoChains,
+		},
+	}
+	for _, tt := range tests {
+		// Spamming requires a chain, so we can only check that arguments
+		// are parsed before it is selected
+		_, err := execute(t, dir, append([]string{"spam", "run"}, tt.args...)...)
+		require.ErrorIs(err, tt.err, tt.args)
+		require.Equal(tt.profile, spamProfile, tt.args)
+		require.Equal(tt.ramp, spamRamp(), tt.args)
+	}
+
+	_, err := execute(t, dir, "spam", "run", "--target-tps", "fast")
+	require.ErrorContains(err, "target-tps")
+}
+
+func TestOrderbookProfile(t *testing.T) {
+	require := require.New(t)
+	accounts := []codec.Address{codec.CreateAddress(0, ids.GenerateTestID())}
+	w := spamProfiles()[orderbookProfile](0, accounts)
+
+	// Orders are created until too many are open
+	var orders []ids.ID
+	for i := 0; i <= maxOpenOrders; i++ {
+		action, amount, err := w.Next(i)
+		require.NoError(err)
+		create, ok := action.(*actions.CreateOrder)
+		require.True(ok, i)
+		require.Equal(uint64(i+1), amount)
+		require.Equal(amount, create.Supply)
+		require.Equal(orderbookAsset, create.In)
+		require.Equal(ids.Empty, create.Out)
+		orders = append(orders, ids.GenerateTestID())
+		w.Issued(action, orders[i])
+	}
+
+	// Then the oldest order is closed every other tx
+	for i := maxOpenOrders + 1; i < maxOpenOrders+5; i++ {
+		action, amount, err := w.Next(i)
+		require.NoError(err)
+		if i%2 == 0 {
+			require.IsType(&actions.CreateOrder{}, action, i)
+			orders = append(orders, ids.GenerateTestID())
+			w.Issued(action, orders[len(orders)-1])
+			continue
+		}
+		closeOrder, ok := action.(*actions.CloseOrder)
+		require.True(ok, i)
+		require.Zero(amount)
+		require.Equal(orders[0], closeOrder.Order)
+		orders = orders[1:]
+		w.Issued(action, ids.GenerateTestID())
+	}
+}
+
+func TestPayloadProfile(t *testing.T) {
+	require := require.New(t)
+	accounts := make([]codec.Address, 3)
+	for i := range accounts {
+		accounts[i] = codec.CreateAddress(0, ids.GenerateTestID())
+	}
+	w := spamProfiles()[payloadProfile](1, accounts)
+
+	// Transfers carry the largest memo and are never sent to self
+	for i := 0; i < 100; i++ {
+		action, amount, err := w.Next(i)
+		require.NoError(err)
+		transfer, ok := action.(*actions.Transfer)
+		require.True(ok)
+		require.Len(transfer.Memo, actions.MaxMemoSize)
+		require.NotEqual(accounts[1], transfer.To)
+		require.Contains(accounts, transfer.To)
+		require.Equal(uint64(i+1), amount)
+		require.Equal(amount, transfer.Value)
+		require.Equal(ids.Empty, transfer.Asset)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+)
+
+var txCmd = &cobra.Command{
+	Use: "tx",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var buildTxCmd = &cobra.Command{
+	Use:     "build [unsigned tx]",
+	PreRunE: proposalArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		chainID, uris, err := handler.Root().GetDefaultChain(true)
+		if err != nil {
+			return err
+		}
+		jcli := rpc.NewJSONRPCClient(uris[0])
+		networkID, _, _, err := jcli.Network(ctx)
+		if err != nil {
+			return err
+		}
+		tcli := trpc.NewJSONRPCClient(uris[0], networkID, chainID)
+
+		// Select the (offline) sender
+		actor, err := handler.Root().PromptAddress("sender")
+		if err != nil {
+			return err
+		}
+
+		// Select token to send
+		assetID, err := handler.Root().PromptAsset("assetID", true)
+		if err != nil {
+			return err
+		}
+		_, decimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, actor, assetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select recipient
+		recipient, err := handler.Root().PromptAddress("recipient")
+		if err != nil {
+			return err
+		}
+
+		// Select amount
+		amount, err := handler.Root().PromptAmount("amount", decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Generate unsigned transaction (we only need the type of the
+		// sender's key to quote the fee)
+		action := &actions.Transfer{
+			To:    recipient,
+			Asset: assetID,
+			Value: amount,
+		}
+		parser, err := tcli.Parser(ctx)
+		if err != nil {
+			return err
+		}
+		unitPrices, err := jcli.UnitPrices(ctx, false)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		rules := parser.Rules(now)
+		maxUnits, err := chain.EstimateMaxUnits(rules, action, auth.NewED25519Factory(ed25519.EmptyPrivateKey), nil)
+		if err != nil {
+			return err
+		}
+		maxFee, err := chain.EstimateFee(rules, unitPrices, maxUnits)
+		if err != nil {
+			return err
+		}
+		base := &chain.Base{
+			Timestamp: utils.UnixRMilli(now, rules.GetValidityWindow()),
+			ChainID:   rules.ChainID(),
+			MaxFee:    maxFee,
+		}
+		utx, err := cli.NewUnsignedTx(
+			chain.NewTx(base, nil, action),
+			codec.MustAddressBech32(tconsts.HRP, actor),
+			unitPrices,
+			maxUnits,
+		)
+		if err != nil {
+			return err
+		}
+		if err := cli.SaveJSON(args[0], utx); err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}created unsigned tx:{{/}} %s {{yellow}}max fee:{{/}} %s %s {{yellow}}expires:{{/}} %s\n",
+			args[0],
+			utils.FormatBalance(maxFee, tconsts.Decimals),
+			tconsts.Symbol,
+			time.UnixMilli(base.Timestamp).Format(time.RFC3339),
+		)
+		return nil
+	},
+}
+
+var signTxCmd = &cobra.Command{
+	Use: "sign [unsigned tx] [signed tx]",
+	PreRunE: func(_ *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		// This command never connects to the network
+		var utx cli.UnsignedTx
+		if err := cli.LoadJSON(args[0], &utx); err != nil {
+			return err
+		}
+		tx, err := utx.Tx(tconsts.ActionRegistry)
+		if err != nil {
+			return err
+		}
+		addr, priv, err := handler.Root().GetDefaultKey(true)
+		if err != nil {
+			return err
+		}
+		if saddr := codec.MustAddressBech32(tconsts.HRP, addr); saddr != utx.Actor {
+			return ErrWrongSigner
+		}
+
+		// Only display what is actually signed
+		action, err := json.Marshal(tx.Action)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{yellow}}chainID:{{/}} %s {{yellow}}action:{{/}} %T %s\n",
+			tx.Base.ChainID,
+			tx.Action,
+			action,
+		)
+		utils.Outf(
+			"{{yellow}}max fee:{{/}} %s %s {{yellow}}expires:{{/}} %s\n",
+			utils.FormatBalance(utx.MaxFee, tconsts.Decimals),
+			tconsts.Symbol,
+			time.UnixMilli(utx.Expiry).Format(time.RFC3339),
+		)
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+		stx, err := utx.Sign(auth.NewED25519Factory(ed25519.PrivateKey(priv)), tconsts.ActionRegistry, tconsts.AuthRegistry)
+		if err != nil {
+			return err
+		}
+		if err := cli.SaveJSON(args[1], stx); err != nil {
+			return err
+		}
+		utils.Outf("{{green}}signed tx:{{/}} %s {{yellow}}txID:{{/}} %s\n", args[1], stx.TxID)
+		return nil
+	},
+}
+
+var broadcastTxCmd = &cobra.Command{
+	Use:     "broadcast [signed tx]",
+	PreRunE: proposalArgs,
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().BroadcastTx(args[0], tconsts.ActionRegistry, tconsts.AuthRegistry)
+	},
+}
@@ -0,0 +1,279 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use: "watch",
+	PreRunE: func(*cobra.Command, []string) error {
+		if len(watchAddresses) == 0 && len(watchAssets) == 0 {
+			return ErrNothingToWatch
+		}
+		return nil
+	},
+	RunE: func(*cobra.Command, []string) error {
+		return handler.Watch(watchAddresses, watchAssets)
+	},
+}
+
+// holding is the balance of a single asset held by an address.
+type holding struct {
+	addr  codec.Address
+	asset ids.ID
+}
+
+// watchFilter matches transactions that modify the balance of a watched
+// address or asset. If both addresses and assets are provided, only
+// balances of the watched assets held by the watched addresses match.
+type watchFilter struct {
+	addrs  set.Set[codec.Address]
+	assets set.Set[ids.ID]
+}
+
+func parseWatchFilter(addrs []string, assets []string) (*watchFilter, error) {
+	f := &watchFilter{
+		addrs:  set.NewSet[codec.Address](len(addrs)),
+		assets: set.NewSet[ids.ID](len(assets)),
+	}
+	for _, saddr := range addrs {
+		addr, err := codec.ParseAddressBech32(consts.HRP, saddr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, saddr)
+		}
+		f.addrs.Add(addr)
+	}
+	for _, sasset := range assets {
+		asset, err := parseAssetID(sasset)
+		if err != nil {
+			return nil, err
+		}
+		f.assets.Add(asset)
+	}
+	return f, nil
+}
+
+// parseAssetID parses an asset ID provided as an argument (the native asset
+// can be provided using its symbol).
+func parseAssetID(s string) (ids.ID, error) {
+	if s == consts.Symbol {
+		return ids.Empty, nil
+	}
+	asset, err := ids.FromString(s)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: %s", err, s)
+	}
+	return asset, nil
+}
+
+func (f *watchFilter) matches(h holding) bool {
+	if f.addrs.Len() > 0 && !f.addrs.Contains(h.addr) {
+		return false
+	}
+	return f.assets.Len() == 0 || f.assets.Contains(h.asset)
+}
+
+// holdings returns all balances that [tx] may modify (including the fee
+// paid by the sponsor).
+func holdings(tx *chain.Transaction) []holding {
+	keys := tx.Action.StateKeys(tx.Auth.Actor(), tx.ID())
+	hs := make([]holding, 0, len(keys)+1)
+	hs = append(hs, holding{tx.Auth.Sponsor(), ids.Empty})
+	for _, k := range keys {
+		addr, asset, ok := storage.ParseBalanceKey([]byte(k))
+		if !ok {
+			continue
+		}
+		hs = append(hs, holding{addr, asset})
+	}
+	return hs
+}
+
+// Watch prints all transactions that modify the balance of any of [addrs]
+// or [assets] as they are accepted. Incoming transfers and order fills are
+// highlighted and the updated balance of each watched address is printed
+// after every block that modifies it.
+func (h *Handler) Watch(addrs []string, assets []string) error {
+	ctx := context.Background()
+	filter, err := parseWatchFilter(addrs, assets)
+	if err != nil {
+		return err
+	}
+	chainID, uris, err := h.h.PromptChain("select chainID", nil)
+	if err != nil {
+		return err
+	}
+	if err := h.h.CloseDatabase(); err != nil {
+		return err
+	}
+	utils.Outf("{{yellow}}uri:{{/}} %s\n", uris[0])
+	rcli := rpc.NewJSONRPCClient(uris[0])
+	networkID, _, _, err := rcli.Network(ctx)
+	if err != nil {
+		return err
+	}
+	tcli := trpc.NewJSONRPCClient(uris[0], networkID, chainID)
+	parser, err := tcli.Parser(ctx)
+	if err != nil {
+		return err
+	}
+	scli, err := rpc.NewWebSocketClient(uris[0], rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+	if err != nil {
+		return err
+	}
+	defer scli.Close()
+	if err := scli.RegisterBlocks(); err != nil {
+		return err
+	}
+
+	// Report the starting balance of each watched holding we can enumerate
+	// (we can't enumerate all holders of an asset).
+	balances := map[holding]uint64{}
+	for addr := range filter.addrs {
+		for asset := range filter.assets {
+			if err := h.updateBalance(ctx, tcli, balances, holding{addr, asset}); err != nil {
+				return err
+			}
+		}
+	}
+	utils.Outf("{{green}}watching for balance changes on %s 👀{{/}}\n", chainID)
+	for ctx.Err() == nil {
+		blk, results, _, err := scli.ListenBlock(ctx, parser)
+		if err != nil {
+			return err
+		}
+		changed := set.Set[holding]{}
+		for i, tx := range blk.Txs {
+			var matched bool
+			for _, hl := range holdings(tx) {
+				if !filter.matches(hl) {
+					continue
+				}
+				matched = true
+				if filter.addrs.Contains(hl.addr) {
+					changed.Add(hl)
+				}
+			}
+			if !matched {
+				continue
+			}
+			result := results[i]
+			if result.Success() {
+				if err := printWatchEvent(ctx, tcli, filter, tx, result); err != nil {
+					return err
+				}
+			}
+			handleTx(tcli, tx, result)
+		}
+		for hl := range changed {
+			if err := h.updateBalance(ctx, tcli, balances, hl); err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// printWatchEvent highlights incoming transfers to and fills of watched
+// addresses.
+func printWatchEvent(
+	ctx context.Context,
+	tcli *trpc.JSONRPCClient,
+	filter *watchFilter,
+	tx *chain.Transaction,
+	result *chain.Result,
+) error {
+	switch action := tx.Action.(type) {
+	case *actions.Transfer:
+		if !filter.matches(holding{action.To, action.Asset}) {
+			return nil
+		}
+		_, symbol, decimals, _, _, _, _, err := tcli.Asset(ctx, action.Asset, true)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}incoming transfer:{{/}} %s %s %s -> %s\n",
+			utils.FormatBalance(action.Value, decimals),
+			symbol,
+			codec.MustAddressBech32(consts.HRP, tx.Auth.Actor()),
+			codec.MustAddressBech32(consts.HRP, action.To),
+		)
+	case *actions.FillOrder:
+		if !filter.matches(holding{action.Owner, action.In}) && !filter.matches(holding{tx.Auth.Actor(), action.Out}) {
+			return nil
+		}
+		or, err := trpc.DecodeFillOrderResult(result)
+		if err != nil {
+			return err
+		}
+		_, inSymbol, inDecimals, _, _, _, _, err := tcli.Asset(ctx, action.In, true)
+		if err != nil {
+			return err
+		}
+		_, outSymbol, outDecimals, _, _, _, _, err := tcli.Asset(ctx, action.Out, true)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}fill:{{/}} %s {{yellow}}owner:{{/}} %s {{yellow}}filler:{{/}} %s {{yellow}}paid:{{/}} %s %s {{yellow}}received:{{/}} %s %s {{yellow}}remaining:{{/}} %s %s\n",
+			action.Order,
+			codec.MustAddressBech32(consts.HRP, action.Owner),
+			codec.MustAddressBech32(consts.HRP, tx.Auth.Actor()),
+			utils.FormatBalance(or.In, inDecimals),
+			inSymbol,
+			utils.FormatBalance(or.Out, outDecimals),
+			outSymbol,
+			utils.FormatBalance(or.Remaining, outDecimals),
+			outSymbol,
+		)
+	}
+	return nil
+}
+
+// updateBalance fetches the current balance of [hl] and prints it (along
+// with the change since the last time it was fetched, if any).
+func (*Handler) updateBalance(
+	ctx context.Context,
+	tcli *trpc.JSONRPCClient,
+	balances map[holding]uint64,
+	hl holding,
+) error {
+	saddr := codec.MustAddressBech32(consts.HRP, hl.addr)
+	balance, err := tcli.Balance(ctx, saddr, hl.asset)
+	if err != nil {
+		return err
+	}
+	_, symbol, decimals, _, _, _, _, err := tcli.Asset(ctx, hl.asset, true)
+	if err != nil {
+		return err
+	}
+	previous, ok := balances[hl]
+	balances[hl] = balance
+	switch {
+	case !ok:
+		utils.Outf("{{yellow}}balance:{{/}} %s %s %s\n", saddr, utils.FormatBalance(balance, decimals), symbol)
+	case balance > previous:
+		utils.Outf("{{green}}balance:{{/}} %s %s %s (+%s)\n", saddr, utils.FormatBalance(balance, decimals), symbol, utils.FormatBalance(balance-previous, decimals))
+	case balance < previous:
+		utils.Outf("{{red}}balance:{{/}} %s %s %s (-%s)\n", saddr, utils.FormatBalance(balance, decimals), symbol, utils.FormatBalance(previous-balance, decimals))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/cli"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+func TestWatchFlags(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	alice := codec.MustAddressBech32(consts.HRP, codec.CreateAddress(0, ids.GenerateTestID()))
+	bob := codec.MustAddressBech32(consts.HRP, codec.CreateAddress(0, ids.GenerateTestID()))
+	asset := ids.GenerateTestID().String()
+
+	tests := []struct {
+		args   []string
+		addrs  []string
+		assets []string
+		err    error
+	}{
+		{nil, nil, nil, ErrNothingToWatch},
+		{[]string{"--address", alice}, []string{alice}, nil, cli.ErrNoChains},
+		{[]string{"--asset", consts.Symbol}, nil, []string{consts.Symbol}, cli.ErrNoChains},
+		{[]string{"--address", alice + "," + bob, "--asset", asset}, []string{alice, bob}, []string{asset}, cli.ErrNoChains},
+		{[]string{"--address", alice, "--address", bob}, []string{alice, bob}, nil, cli.ErrNoChains},
+		{[]string{"--address", "alice"}, []string{"alice"}, nil, nil},
+		{[]string{"--asset", "TKN2"}, nil, []string{"TKN2"}, nil},
+	}
+	for _, tt := range tests {
+		// Watching requires a chain, so we can only check that arguments
+		// are parsed before it is selected
+		_, err := execute(t, dir, append([]string{"watch"}, tt.args...)...)
+		if tt.err != nil {
+			require.ErrorIs(err, tt.err, tt.args)
+		} else {
+			require.Error(err, tt.args)
+			require.NotErrorIs(err, cli.ErrNoChains, tt.args)
+		}
+		require.Equal(tt.addrs, watchAddresses, tt.args)
+		require.Equal(tt.assets, watchAssets, tt.args)
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	require := require.New(t)
+	alice := codec.CreateAddress(0, ids.GenerateTestID())
+	bob := codec.CreateAddress(0, ids.GenerateTestID())
+	asset := ids.GenerateTestID()
+	salice := codec.MustAddressBech32(consts.HRP, alice)
+
+	tests := []struct {
+		addrs   []string
+		assets  []string
+		matches []holding
+		misses  []holding
+	}{
+		{
+			addrs:   []string{salice},
+			matches: []holding{{alice, ids.Empty}, {alice, asset}},
+			misses:  []holding{{bob, ids.Empty}, {bob, asset}},
+		},
+		{
+			assets:  []string{asset.String()},
+			matches: []holding{{alice, asset}, {bob, asset}},
+			misses:  []holding{{alice, ids.Empty}, {bob, ids.Empty}},
+		},
+		{
+			// The native asset can be watched using its symbol
+			assets:  []string{consts.Symbol},
+			matches: []holding{{alice, ids.Empty}, {bob, ids.Empty}},
+			misses:  []holding{{alice, asset}},
+		},
+		{
+			// Both must match if addresses and assets are provided
+			addrs:   []string{salice},
+			assets:  []string{asset.String()},
+			matches: []holding{{alice, asset}},
+			misses:  []holding{{alice, ids.Empty}, {bob, asset}},
+		},
+	}
+	for _, tt := range tests {
+		f, err := parseWatchFilter(tt.addrs, tt.assets)
+		require.NoError(err)
+		for _, h := range tt.matches {
+			require.True(f.matches(h), "addrs=%v assets=%v", tt.addrs, tt.assets)
+		}
+		for _, h := range tt.misses {
+			require.False(f.matches(h), "addrs=%v assets=%v", tt.addrs, tt.assets)
+		}
+	}
+
+	_, err := parseWatchFilter([]string{"alice"}, nil)
+	require.Error(err)
+	_, err = parseWatchFilter([]string{codec.MustAddressBech32("morpheus", alice)}, nil)
+	require.Error(err)
+	_, err = parseWatchFilter(nil, []string{"TKN2"})
+	require.Error(err)
+}
+
+func TestWatchHoldings(t *testing.T) {
+	require := require.New(t)
+
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	actor := auth.NewED25519Address(priv.PublicKey())
+	bob := codec.CreateAddress(0, ids.GenerateTestID())
+	asset := ids.GenerateTestID()
+
+	// The fee paid by the sponsor is always included
+	tx := &chain.Transaction{
+		Action: &actions.Transfer{To: bob, Asset: asset, Value: 1},
+		Auth:   &auth.ED25519{Signer: priv.PublicKey()},
+	}
+	require.Equal([]holding{{actor, ids.Empty}, {actor, asset}, {bob, asset}}, holdings(tx))
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/challenge"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/cmd/token-faucet/config"
@@ -122,11 +121,7 @@ func (m *Manager) sendFunds(ctx context.Context, destination codec.Address, amou
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-	submit, tx, maxFee, err := m.cli.GenerateTransaction(ctx, parser, nil, &actions.Transfer{
-		To:    destination,
-		Asset: ids.Empty,
-		Value: amount,
-	}, m.factory)
+	submit, tx, maxFee, err := trpc.BuildTransferTx(ctx, m.cli, parser, m.factory, destination, ids.Empty, amount, nil)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
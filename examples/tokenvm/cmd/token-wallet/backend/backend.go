@@ -215,7 +215,7 @@ func (b *Backend) collectBlocks() {
 
 			tx := blk.Txs[i]
 			actor := tx.Auth.Actor()
-			if !result.Success {
+			if !result.Success() {
 				failTxs++
 			}
 
@@ -234,14 +234,14 @@ func (b *Backend) collectBlocks() {
 				txInfo := &TransactionInfo{
 					ID:        tx.ID().String(),
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Timestamp: blk.Tmstmp,
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "Transfer",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
+				if result.Success() {
 					txInfo.Summary = fmt.Sprintf("%s %s -> %s", hutils.FormatBalance(action.Value, decimals), symbol, codec.MustAddressBech32(tconsts.HRP, action.To))
 					if len(action.Memo) > 0 {
 						txInfo.Summary += fmt.Sprintf(" (memo: %s)", action.Memo)
@@ -250,7 +250,7 @@ func (b *Backend) collectBlocks() {
 					txInfo.Summary = string(result.Output)
 				}
 				if action.To == b.addr {
-					if actor != b.addr && result.Success {
+					if actor != b.addr && result.Success() {
 						b.txAlertLock.Lock()
 						b.transactionAlerts = append(b.transactionAlerts, &Alert{"info", fmt.Sprintf("Received %s %s from Transfer", hutils.FormatBalance(action.Value, decimals), symbol)})
 						b.txAlertLock.Unlock()
@@ -288,14 +288,14 @@ func (b *Backend) collectBlocks() {
 				txInfo := &TransactionInfo{
 					ID:        tx.ID().String(),
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Timestamp: blk.Tmstmp,
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "CreateAsset",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
+				if result.Success() {
 					txInfo.Summary = fmt.Sprintf("assetID: %s symbol: %s decimals: %d metadata: %s", tx.ID(), action.Symbol, action.Decimals, action.Metadata)
 				} else {
 					txInfo.Summary = string(result.Output)
@@ -318,19 +318,19 @@ func (b *Backend) collectBlocks() {
 					ID:        tx.ID().String(),
 					Timestamp: blk.Tmstmp,
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "Mint",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
+				if result.Success() {
 					txInfo.Summary = fmt.Sprintf("%s %s -> %s", hutils.FormatBalance(action.Value, decimals), symbol, codec.MustAddressBech32(tconsts.HRP, action.To))
 				} else {
 					txInfo.Summary = string(result.Output)
 				}
 				if action.To == b.addr {
-					if actor != b.addr && result.Success {
+					if actor != b.addr && result.Success() {
 						b.txAlertLock.Lock()
 						b.transactionAlerts = append(b.transactionAlerts, &Alert{"info", fmt.Sprintf("Received %s %s from Mint", hutils.FormatBalance(action.Value, decimals), symbol)})
 						b.txAlertLock.Unlock()
@@ -375,13 +375,13 @@ func (b *Backend) collectBlocks() {
 					ID:        tx.ID().String(),
 					Timestamp: blk.Tmstmp,
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "CreateOrder",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
+				if result.Success() {
 					txInfo.Summary = fmt.Sprintf("%s %s -> %s %s (supply: %s %s)",
 						hutils.FormatBalance(action.InTick, inDecimals),
 						inSymbol,
@@ -416,14 +416,14 @@ func (b *Backend) collectBlocks() {
 					ID:        tx.ID().String(),
 					Timestamp: blk.Tmstmp,
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "FillOrder",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
-					or, _ := actions.UnmarshalOrderResult(result.Output)
+				if result.Success() {
+					or, _ := trpc.DecodeFillOrderResult(result)
 					txInfo.Summary = fmt.Sprintf("%s %s -> %s %s (remaining: %s %s)",
 						hutils.FormatBalance(or.In, inDecimals),
 						inSymbol,
@@ -456,13 +456,13 @@ func (b *Backend) collectBlocks() {
 					ID:        tx.ID().String(),
 					Timestamp: blk.Tmstmp,
 					Size:      fmt.Sprintf("%.2fKB", float64(tx.Size())/units.KiB),
-					Success:   result.Success,
+					Success:   result.Success(),
 					Actor:     codec.MustAddressBech32(tconsts.HRP, actor),
 					Type:      "CloseOrder",
 					Units:     hcli.ParseDimensions(result.Consumed),
 					Fee:       fmt.Sprintf("%s %s", hutils.FormatBalance(result.Fee, tconsts.Decimals), tconsts.Symbol),
 				}
-				if result.Success {
+				if result.Success() {
 					txInfo.Summary = fmt.Sprintf("OrderID: %s", action.Order)
 				} else {
 					txInfo.Summary = string(result.Output)
@@ -582,7 +582,7 @@ func (b *Backend) GetUnitPrices() []*GenericInfo {
 	b.blockLock.Lock()
 	defer b.blockLock.Unlock()
 
-	info := make([]*GenericInfo, 0, len(b.stats)*chain.FeeDimensions)
+	info := make([]*GenericInfo, 0, len(b.stats)*chain.FeeDimensions())
 	for i := 0; i < len(b.stats); i++ {
 		info = append(info, &GenericInfo{b.stats[i].Timestamp, b.stats[i].Prices[0], "Bandwidth"})
 		info = append(info, &GenericInfo{b.stats[i].Timestamp, b.stats[i].Prices[1], "Compute"})
@@ -639,11 +639,7 @@ func (b *Backend) CreateAsset(symbol string, decimals string, metadata string) e
 	if err != nil {
 		return err
 	}
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.CreateAsset{
-		Symbol:   []byte(symbol),
-		Decimals: uint8(udecimals),
-		Metadata: []byte(metadata),
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildCreateAssetTx(b.ctx, b.cli, b.parser, b.factory, []byte(symbol), uint8(udecimals), []byte(metadata))
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -662,7 +658,7 @@ func (b *Backend) CreateAsset(symbol string, decimals string, metadata string) e
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -694,11 +690,7 @@ func (b *Backend) MintAsset(asset string, address string, amount string) error {
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.MintAsset{
-		To:    to,
-		Asset: assetID,
-		Value: value,
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildMintAssetTx(b.ctx, b.cli, b.parser, b.factory, to, assetID, value)
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -717,7 +709,7 @@ func (b *Backend) MintAsset(asset string, address string, amount string) error {
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -758,12 +750,7 @@ func (b *Backend) Transfer(asset string, address string, amount string, memo str
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.Transfer{
-		To:    to,
-		Asset: assetID,
-		Value: value,
-		Memo:  []byte(memo),
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildTransferTx(b.ctx, b.cli, b.parser, b.factory, to, assetID, value, []byte(memo))
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -788,7 +775,7 @@ func (b *Backend) Transfer(asset string, address string, amount string, memo str
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -1112,13 +1099,7 @@ func (b *Backend) CreateOrder(assetIn string, inTick string, assetOut string, ou
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.CreateOrder{
-		In:      inID,
-		InTick:  iTick,
-		Out:     outID,
-		OutTick: oTick,
-		Supply:  oSupply,
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildCreateOrderTx(b.ctx, b.cli, b.parser, b.factory, inID, iTick, outID, oTick, oSupply)
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -1146,7 +1127,7 @@ func (b *Backend) CreateOrder(assetIn string, inTick string, assetOut string, ou
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 
@@ -1198,13 +1179,7 @@ func (b *Backend) FillOrder(orderID string, orderOwner string, assetIn string, i
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.FillOrder{
-		Order: oID,
-		Owner: owner,
-		In:    inID,
-		Out:   outID,
-		Value: inAmount,
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildFillOrderTx(b.ctx, b.cli, b.parser, b.factory, oID, owner, inID, outID, inAmount)
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -1232,7 +1207,7 @@ func (b *Backend) FillOrder(orderID string, orderOwner string, assetIn string, i
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -1255,10 +1230,7 @@ func (b *Backend) CloseOrder(orderID string, assetOut string) error {
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.CloseOrder{
-		Order: oID,
-		Out:   outID,
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildCloseOrderTx(b.ctx, b.cli, b.parser, b.factory, oID, outID)
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -1277,7 +1249,7 @@ func (b *Backend) CloseOrder(orderID string, assetOut string) error {
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -1395,12 +1367,7 @@ func (b *Backend) Message(message string, url string) error {
 	}
 
 	// Generate transaction
-	_, tx, maxFee, err := b.cli.GenerateTransaction(b.ctx, b.parser, nil, &actions.Transfer{
-		To:    recipientAddr,
-		Asset: ids.Empty,
-		Value: fee,
-		Memo:  data,
-	}, b.factory)
+	_, tx, maxFee, err := trpc.BuildTransferTx(b.ctx, b.cli, b.parser, b.factory, recipientAddr, ids.Empty, fee, data)
 	if err != nil {
 		return fmt.Errorf("%w: unable to generate transaction", err)
 	}
@@ -1419,7 +1386,7 @@ func (b *Backend) Message(message string, url string) error {
 	if dErr != nil {
 		return err
 	}
-	if !result.Success {
+	if !result.Success() {
 		return fmt.Errorf("transaction failed on-chain: %s", result.Output)
 	}
 	return nil
@@ -6,15 +6,21 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/netip"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/profiler"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/config"
 	"github.com/ava-labs/hypersdk/gossiper"
+	"github.com/ava-labs/hypersdk/network"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/trace"
 	"github.com/ava-labs/hypersdk/vm"
 
@@ -22,13 +28,23 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/version"
 )
 
-var _ vm.Config = (*Config)(nil)
+var (
+	_ vm.Config           = (*Config)(nil)
+	_ vm.ReloadableConfig = (*Config)(nil)
+)
 
 const (
 	defaultContinuousProfilerFrequency = 1 * time.Minute
 	defaultContinuousProfilerMaxFiles  = 10
 	defaultStoreTransactions           = true
 	defaultMaxOrdersPerPair            = 1024
+
+	// API nodes don't build blocks, so they can use more memory to serve
+	// queries and keep all accepted blocks on disk.
+	apiNodeAcceptedBlockWindowCache  = 1_024
+	apiNodeStateHistoryLength        = 1_024
+	apiNodeIntermediateNodeCacheSize = 8 * units.GiB
+	apiNodeValueNodeCacheSize        = 4 * units.GiB
 )
 
 type Config struct {
@@ -36,9 +52,13 @@ type Config struct {
 
 	// Concurrency
 	AuthVerificationCores     int `json:"authVerificationCores"`
+	AuthVerificationMinCores  int `json:"authVerificationMinCores"`
 	RootGenerationCores       int `json:"rootGenerationCores"`
 	TransactionExecutionCores int `json:"transactionExecutionCores"`
 
+	// Debugging
+	ExecutionTrace bool `json:"executionTrace"` // compare parallel execution of each verified block to a sequential replay
+
 	// Gossip
 	GossipMaxSize       int   `json:"gossipMaxSize"`
 	GossipProposerDiff  int   `json:"gossipProposerDiff"`
@@ -46,6 +66,10 @@ type Config struct {
 	NoGossipBuilderDiff int   `json:"noGossipBuilderDiff"`
 	VerifyTimeout       int64 `json:"verifyTimeout"`
 
+	GossipFilterInterval int64 `json:"gossipFilterInterval"` // ms between mempool filters sent to peers (0 disables)
+
+	TargetGossipDuration time.Duration `json:"targetGossipDuration"`
+
 	// Tracing
 	TraceEnabled    bool    `json:"traceEnabled"`
 	TraceSampleRate float64 `json:"traceSampleRate"`
@@ -54,12 +78,25 @@ type Config struct {
 	ContinuousProfilerDir string `json:"continuousProfilerDir"` // "*" is replaced with rand int
 
 	// Streaming settings
-	StreamingBacklogSize int `json:"streamingBacklogSize"`
+	StreamingBacklogSize         int      `json:"streamingBacklogSize"`
+	StreamingMaxConnections      int      `json:"streamingMaxConnections"`
+	StreamingMaxConnectionsPerIP int      `json:"streamingMaxConnectionsPerIP"`
+	StreamingAuthTokens          []string `json:"streamingAuthTokens"`
+	StreamingMempool             bool     `json:"streamingMempool"`    // allow clients to subscribe to txs entering the mempool
+	StreamingReplaySize          int      `json:"streamingReplaySize"` // messages retained per stream for resuming clients
 
 	// Mempool
 	MempoolSize           int      `json:"mempoolSize"`
 	MempoolSponsorSize    int      `json:"mempoolSponsorSize"`
 	MempoolExemptSponsors []string `json:"mempoolExemptSponsors"`
+	MempoolMaxNonceGap    int      `json:"mempoolMaxNonceGap"`   // only used with sequential nonces
+	MempoolBundleSize     int      `json:"mempoolBundleSize"`    // 0 disables bundles
+	MempoolFeeExemptSize  int      `json:"mempoolFeeExemptSize"` // txs exempt from fees (see ActionFeePayers in genesis)
+	FCFSOrdering          bool     `json:"fcfsOrdering"`         // build blocks in the order txs were received (disables bundles)
+
+	// "accept", "reject", or "deprioritize" txs whose dominant dimension is
+	// over target in the fee window
+	MempoolCongestionPolicy string `json:"mempoolCongestionPolicy"`
 
 	// Order Book
 	//
@@ -69,16 +106,52 @@ type Config struct {
 
 	// Misc
 	VerifyAuth        bool          `json:"verifyAuth"`
+	ED25519Backend    string        `json:"ed25519Backend"` // "consensus" or "voi"
 	StoreTransactions bool          `json:"storeTransactions"`
+	StoreBalances     bool          `json:"storeBalances"`
 	TestMode          bool          `json:"testMode"` // makes gossip/building manual
+	APINode           bool          `json:"apiNode"`  // only track the chain and serve APIs (enables archival and indexing)
 	LogLevel          logging.Level `json:"logLevel"`
 
 	// State Sync
 	StateSyncServerDelay time.Duration `json:"stateSyncServerDelay"` // for testing
+	BlockBackfillDepth   int           `json:"blockBackfillDepth"`   // blocks below the sync target to fetch from peers (0 to disable)
+
+	// Shutdown
+	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
+
+	// Storage Maintenance (0 to disable)
+	CompactionInterval   time.Duration `json:"compactionInterval"`
+	StorageStatsInterval time.Duration `json:"storageStatsInterval"`
+
+	// Admin
+	AdminAPIEnabled  bool     `json:"adminAPIEnabled"`
+	AdminAPITokens   []string `json:"adminAPITokens"`   // required as "Authorization: Bearer <token>" if set
+	ReloadConfigFile string   `json:"reloadConfigFile"` // reloaded on SIGHUP
+
+	// CORS
+	CORSAllowedOrigins []string `json:"corsAllowedOrigins"`
+
+	// RPC Limits (0 to disable)
+	RPCMaxRequestSize        int64              `json:"rpcMaxRequestSize"`
+	RPCMaxConcurrentRequests int                `json:"rpcMaxConcurrentRequests"`
+	RPCIPRate                float64            `json:"rpcIPRate"` // requests per second
+	RPCIPBurst               int                `json:"rpcIPBurst"`
+	RPCMethodRates           map[string]float64 `json:"rpcMethodRates"`    // requests per second per IP (keyed by "<service>.<method>")
+	RPCTrustedProxies        []string           `json:"rpcTrustedProxies"` // IPs or CIDRs of proxies that set X-Forwarded-For
+
+	// Limits on serving blocks and state to other nodes (0 to disable)
+	ServeMaxConcurrentRequests int `json:"serveMaxConcurrentRequests"`
+	ServeBytesPerSecond        int `json:"serveBytesPerSecond"`
+	ServeBytesBurst            int `json:"serveBytesBurst"`
+
+	// Protects values that can be changed by [Reload]
+	l sync.RWMutex
 
 	loaded               bool
 	nodeID               ids.NodeID
 	parsedExemptSponsors []codec.Address
+	parsedTrustedProxies []netip.Prefix
 }
 
 func New(nodeID ids.NodeID, b []byte) (*Config, error) {
@@ -101,6 +174,14 @@ func New(nodeID ids.NodeID, b []byte) (*Config, error) {
 		}
 		c.parsedExemptSponsors[i] = p
 	}
+
+	// Parse the proxies that requests can be forwarded by (when rate limiting
+	// by IP)
+	proxies, err := hrpc.ParseTrustedProxies(c.RPCTrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	c.parsedTrustedProxies = proxies
 	return c, nil
 }
 
@@ -112,14 +193,31 @@ func (c *Config) setDefault() {
 	c.GossipProposerDepth = gcfg.GossipProposerDepth
 	c.NoGossipBuilderDiff = gcfg.NoGossipBuilderDiff
 	c.VerifyTimeout = gcfg.VerifyTimeout
+	c.GossipFilterInterval = gcfg.GossipFilterInterval
 	c.AuthVerificationCores = c.Config.GetAuthVerificationCores()
+	c.AuthVerificationMinCores = c.Config.GetAuthVerificationMinCores()
 	c.RootGenerationCores = c.Config.GetRootGenerationCores()
 	c.TransactionExecutionCores = c.Config.GetTransactionExecutionCores()
 	c.MempoolSize = c.Config.GetMempoolSize()
 	c.MempoolSponsorSize = c.Config.GetMempoolSponsorSize()
+	c.MempoolMaxNonceGap = c.Config.GetMempoolMaxNonceGap()
+	c.MempoolCongestionPolicy = c.Config.GetMempoolCongestionPolicy()
+	c.MempoolBundleSize = c.Config.GetMempoolBundleSize()
+	c.MempoolFeeExemptSize = c.Config.GetMempoolFeeExemptSize()
 	c.StateSyncServerDelay = c.Config.GetStateSyncServerDelay()
+	c.ShutdownTimeout = c.Config.GetShutdownTimeout()
+	c.CompactionInterval = c.Config.GetCompactionInterval()
+	c.StorageStatsInterval = c.Config.GetStorageStatsInterval()
+	c.BlockBackfillDepth = c.Config.GetBlockBackfillDepth()
+	c.TargetGossipDuration = c.Config.GetTargetGossipDuration()
 	c.StreamingBacklogSize = c.Config.GetStreamingBacklogSize()
+	c.StreamingReplaySize = c.Config.GetStreamingReplaySize()
+	c.StreamingMaxConnections = c.Config.GetStreamingMaxConnections()
+	c.StreamingMaxConnectionsPerIP = c.Config.GetStreamingMaxConnectionsPerIP()
 	c.VerifyAuth = c.Config.GetVerifyAuth()
+	c.RPCMaxRequestSize = c.Config.GetRPCLimitConfig().MaxRequestSize
+	c.ServeMaxConcurrentRequests = c.Config.GetServeLimitConfig().MaxConcurrentRequests
+	c.ED25519Backend = c.Config.GetED25519Backend()
 	c.StoreTransactions = defaultStoreTransactions
 	c.MaxOrdersPerPair = defaultMaxOrdersPerPair
 }
@@ -127,12 +225,20 @@ func (c *Config) setDefault() {
 func (c *Config) GetLogLevel() logging.Level                { return c.LogLevel }
 func (c *Config) GetTestMode() bool                         { return c.TestMode }
 func (c *Config) GetAuthVerificationCores() int             { return c.AuthVerificationCores }
+func (c *Config) GetAuthVerificationMinCores() int          { return c.AuthVerificationMinCores }
 func (c *Config) GetRootGenerationCores() int               { return c.RootGenerationCores }
 func (c *Config) GetTransactionExecutionCores() int         { return c.TransactionExecutionCores }
-func (c *Config) GetMempoolSize() int                       { return c.MempoolSize }
-func (c *Config) GetMempoolSponsorSize() int                { return c.MempoolSponsorSize }
+func (c *Config) GetExecutionTrace() bool                   { return c.ExecutionTrace }
 func (c *Config) GetMempoolExemptSponsors() []codec.Address { return c.parsedExemptSponsors }
+func (c *Config) GetMempoolMaxNonceGap() int                { return c.MempoolMaxNonceGap }
+func (c *Config) GetMempoolCongestionPolicy() string        { return c.MempoolCongestionPolicy }
+func (c *Config) GetMempoolBundleSize() int                 { return c.MempoolBundleSize }
+func (c *Config) GetMempoolFeeExemptSize() int              { return c.MempoolFeeExemptSize }
+func (c *Config) GetFCFSOrdering() bool                     { return c.FCFSOrdering }
 func (c *Config) GetTraceConfig() *trace.Config {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
 	return &trace.Config{
 		Enabled:         c.TraceEnabled,
 		TraceSampleRate: c.TraceSampleRate,
@@ -142,7 +248,13 @@ func (c *Config) GetTraceConfig() *trace.Config {
 	}
 }
 func (c *Config) GetStateSyncServerDelay() time.Duration { return c.StateSyncServerDelay }
+func (c *Config) GetShutdownTimeout() time.Duration      { return c.ShutdownTimeout }
+func (c *Config) GetCompactionInterval() time.Duration   { return c.CompactionInterval }
+func (c *Config) GetStorageStatsInterval() time.Duration { return c.StorageStatsInterval }
+func (c *Config) GetBlockBackfillDepth() int             { return c.BlockBackfillDepth }
 func (c *Config) GetStreamingBacklogSize() int           { return c.StreamingBacklogSize }
+func (c *Config) GetStreamingMempool() bool              { return c.StreamingMempool }
+func (c *Config) GetStreamingReplaySize() int            { return c.StreamingReplaySize }
 func (c *Config) GetContinuousProfilerConfig() *profiler.Config {
 	if len(c.ContinuousProfilerDir) == 0 {
 		return &profiler.Config{Enabled: false}
@@ -157,6 +269,137 @@ func (c *Config) GetContinuousProfilerConfig() *profiler.Config {
 		MaxNumFiles: defaultContinuousProfilerMaxFiles,
 	}
 }
-func (c *Config) GetVerifyAuth() bool        { return c.VerifyAuth }
-func (c *Config) GetStoreTransactions() bool { return c.StoreTransactions }
-func (c *Config) Loaded() bool               { return c.loaded }
+func (c *Config) GetED25519Backend() string       { return c.ED25519Backend }
+func (c *Config) GetVerifyAuth() bool             { return c.VerifyAuth }
+func (c *Config) GetStoreTransactions() bool      { return c.StoreTransactions || c.APINode }
+func (c *Config) GetStoreBalances() bool          { return c.StoreBalances || c.APINode }
+func (c *Config) Loaded() bool                    { return c.loaded }
+func (c *Config) GetAPINode() bool                { return c.APINode }
+func (c *Config) GetAdminAPIEnabled() bool        { return c.AdminAPIEnabled }
+func (c *Config) GetAdminAPITokens() []string     { return c.AdminAPITokens }
+func (c *Config) GetCORSAllowedOrigins() []string { return c.CORSAllowedOrigins }
+func (c *Config) GetReloadConfigFile() string     { return c.ReloadConfigFile }
+
+func (c *Config) GetRPCLimitConfig() *hrpc.LimitConfig {
+	return &hrpc.LimitConfig{
+		MaxRequestSize:        c.RPCMaxRequestSize,
+		MaxConcurrentRequests: c.RPCMaxConcurrentRequests,
+		IPRate:                c.RPCIPRate,
+		IPBurst:               c.RPCIPBurst,
+		MethodRates:           c.RPCMethodRates,
+		TrustedProxies:        c.parsedTrustedProxies,
+	}
+}
+
+func (c *Config) GetServeLimitConfig() *network.ServeLimitConfig {
+	return &network.ServeLimitConfig{
+		MaxConcurrentRequests: c.ServeMaxConcurrentRequests,
+		BytesPerSecond:        c.ServeBytesPerSecond,
+		BytesBurst:            c.ServeBytesBurst,
+	}
+}
+
+func (c *Config) GetAcceptedBlockWindow() int {
+	if c.APINode {
+		return math.MaxInt // never delete blocks
+	}
+	return c.Config.GetAcceptedBlockWindow()
+}
+
+func (c *Config) GetAcceptedBlockWindowCache() int {
+	if c.APINode {
+		return apiNodeAcceptedBlockWindowCache
+	}
+	return c.Config.GetAcceptedBlockWindowCache()
+}
+
+func (c *Config) GetStateSyncMinBlocks() uint64 {
+	if c.APINode {
+		return math.MaxUint64 // never skip blocks with state sync
+	}
+	return c.Config.GetStateSyncMinBlocks()
+}
+
+func (c *Config) GetStateHistoryLength() int {
+	if c.APINode {
+		return apiNodeStateHistoryLength
+	}
+	return c.Config.GetStateHistoryLength()
+}
+
+func (c *Config) GetIntermediateNodeCacheSize() int {
+	if c.APINode {
+		return apiNodeIntermediateNodeCacheSize
+	}
+	return c.Config.GetIntermediateNodeCacheSize()
+}
+
+func (c *Config) GetValueNodeCacheSize() int {
+	if c.APINode {
+		return apiNodeValueNodeCacheSize
+	}
+	return c.Config.GetValueNodeCacheSize()
+}
+
+func (c *Config) GetMempoolSize() int {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.MempoolSize
+}
+
+func (c *Config) GetMempoolSponsorSize() int {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.MempoolSponsorSize
+}
+
+func (c *Config) GetStreamingMaxConnections() int {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.StreamingMaxConnections
+}
+
+func (c *Config) GetStreamingMaxConnectionsPerIP() int {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.StreamingMaxConnectionsPerIP
+}
+
+func (c *Config) GetStreamingAuthTokens() []string {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.StreamingAuthTokens
+}
+
+func (c *Config) GetTargetGossipDuration() time.Duration {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.TargetGossipDuration
+}
+
+// Reload updates the gossip, mempool, tracing, and streaming limits of [c]
+// from [b]. Values omitted from [b] are reset to their defaults.
+func (c *Config) Reload(b []byte) error {
+	nc, err := New(c.nodeID, b)
+	if err != nil {
+		return err
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.TargetGossipDuration = nc.TargetGossipDuration
+	c.MempoolSize = nc.MempoolSize
+	c.MempoolSponsorSize = nc.MempoolSponsorSize
+	c.TraceSampleRate = nc.TraceSampleRate
+	c.StreamingMaxConnections = nc.StreamingMaxConnections
+	c.StreamingMaxConnectionsPerIP = nc.StreamingMaxConnectionsPerIP
+	c.StreamingAuthTokens = nc.StreamingAuthTokens
+	return nil
+}
@@ -35,4 +35,5 @@ func init() {
 var (
 	ActionRegistry *codec.TypeParser[chain.Action, *warp.Message, bool]
 	AuthRegistry   *codec.TypeParser[chain.Auth, *warp.Message, bool]
+	OutputRegistry *chain.OutputRegistry
 )
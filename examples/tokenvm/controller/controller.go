@@ -10,9 +10,11 @@ import (
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/gossiper"
 	hrpc "github.com/ava-labs/hypersdk/rpc"
 	hstorage "github.com/ava-labs/hypersdk/storage"
@@ -30,7 +32,13 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/version"
 )
 
-var _ vm.Controller = (*Controller)(nil)
+var (
+	_ vm.Controller              = (*Controller)(nil)
+	_ vm.BurnController          = (*Controller)(nil)
+	_ vm.RewardController        = (*Controller)(nil)
+	_ vm.StateRegistryController = (*Controller)(nil)
+	_ chain.OutputTypeRegistry   = (*Controller)(nil)
+)
 
 type Controller struct {
 	inner *vm.VM
@@ -40,6 +48,8 @@ type Controller struct {
 	config       *config.Config
 	stateManager *StateManager
 
+	stateRegistry *hrpc.StateRegistry
+
 	metrics *metrics
 
 	metaDB database.Database
@@ -87,7 +97,6 @@ func (c *Controller) Initialize(
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	c.snowCtx.Log.SetLevel(c.config.GetLogLevel())
 	snowCtx.Log.Info("initialized config", zap.Bool("loaded", c.config.Loaded()), zap.Any("contents", c.config))
 
 	c.genesis, err = genesis.New(genesisBytes, upgradeBytes)
@@ -114,11 +123,16 @@ func (c *Controller) Initialize(
 	jsonRPCHandler, err := hrpc.NewJSONRPCHandler(
 		consts.Name,
 		rpc.NewJSONRPCServer(c),
+		c.inner.RecordRPCRequest,
 	)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 	apis[rpc.JSONRPCEndpoint] = jsonRPCHandler
+	c.stateRegistry = hrpc.NewStateRegistry()
+	if err := storage.RegisterStateQueries(c.stateRegistry); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
 
 	// Create builder and gossiper
 	var (
@@ -137,6 +151,14 @@ func (c *Controller) Initialize(
 		gcfg.GossipProposerDepth = c.config.GossipProposerDepth
 		gcfg.NoGossipBuilderDiff = c.config.NoGossipBuilderDiff
 		gcfg.VerifyTimeout = c.config.VerifyTimeout
+		gcfg.GossipFilterInterval = c.config.GossipFilterInterval
+		if c.config.APINode {
+			// API nodes never build blocks, so they should forward all txs they
+			// receive instead of holding them when they are a proposer.
+			c.inner.Logger().Info("running as api node")
+			build = builder.NewManual(inner)
+			gcfg.NoGossipBuilderDiff = 0
+		}
 		gossip, err = gossiper.NewProposer(inner, gcfg)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
@@ -149,10 +171,21 @@ func (c *Controller) Initialize(
 }
 
 func (c *Controller) Rules(t int64) chain.Rules {
-	// TODO: extend with [UpgradeBytes]
 	return c.genesis.Rules(t, c.snowCtx.NetworkID, c.snowCtx.ChainID)
 }
 
+func (c *Controller) StateRegistry() *hrpc.StateRegistry {
+	return c.stateRegistry
+}
+
+func (*Controller) NewStateSummary() vm.StateSummary {
+	return storage.NewStateSummary()
+}
+
+func (*Controller) OutputRegistry() *chain.OutputRegistry {
+	return consts.OutputRegistry
+}
+
 func (c *Controller) StateManager() chain.StateManager {
 	return c.stateManager
 }
@@ -170,7 +203,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				batch,
 				tx.ID(),
 				blk.GetTimestamp(),
-				result.Success,
+				result.Status,
 				result.Consumed,
 				result.Fee,
 			)
@@ -178,7 +211,7 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 				return err
 			}
 		}
-		if result.Success {
+		if result.Success() {
 			switch action := tx.Action.(type) {
 			case *actions.CreateAsset:
 				c.metrics.createAsset.Inc()
@@ -206,6 +239,20 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 			case *actions.CloseOrder:
 				c.metrics.closeOrder.Inc()
 				c.orderBook.Remove(action.Order)
+			case *actions.CreatePool:
+				c.metrics.createPool.Inc()
+			case *actions.AddLiquidity:
+				c.metrics.addLiquidity.Inc()
+			case *actions.RemoveLiquidity:
+				c.metrics.removeLiquidity.Inc()
+			case *actions.SwapExact:
+				c.metrics.swapExact.Inc()
+			case *actions.OpenStream:
+				c.metrics.openStream.Inc()
+			case *actions.WithdrawStream:
+				c.metrics.withdrawStream.Inc()
+			case *actions.CloseStream:
+				c.metrics.closeStream.Inc()
 			case *actions.ImportAsset:
 				c.metrics.importAsset.Inc()
 			case *actions.ExportAsset:
@@ -213,15 +260,70 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 			}
 		}
 	}
+	if c.config.GetStoreBalances() {
+		if err := c.storeBalances(ctx, batch, blk); err != nil {
+			return err
+		}
+	}
 	return batch.Write()
 }
 
+// storeBalances adds the balances modified by [blk] to the balance history.
+//
+// Balances at a height can only be served if the balance of every account at
+// that height is known, so the history is only stored if this node executed
+// every block since genesis.
+func (c *Controller) storeBalances(ctx context.Context, batch database.KeyValueWriter, blk *chain.StatelessBlock) error {
+	indexed, ok, err := storage.GetBalanceHistoryHeight(c.metaDB)
+	if err != nil {
+		return err
+	}
+	changes := blk.Changes()
+	switch {
+	case changes == nil:
+		// The block was accepted without being executed (during state sync)
+		if ok && indexed+1 == blk.Height() {
+			c.inner.Logger().Warn("stopped storing balance history", zap.Uint64("height", indexed))
+		}
+		return nil
+	case !ok && blk.Height() == 1:
+		for _, alloc := range c.genesis.CustomAllocation {
+			addr, err := codec.ParseAddressBech32(consts.HRP, alloc.Address)
+			if err != nil {
+				return err
+			}
+			if err := storage.StoreBalance(batch, addr, ids.Empty, 0, alloc.Balance); err != nil {
+				return err
+			}
+		}
+	case !ok || indexed+1 != blk.Height():
+		return nil
+	}
+	return storage.StoreBalanceChanges(ctx, batch, blk.Height(), changes)
+}
+
 func (*Controller) Rejected(context.Context, *chain.StatelessBlock) error {
 	return nil
 }
 
-func (*Controller) Shutdown(context.Context) error {
+func (c *Controller) Burned(_ context.Context, _ *chain.StatelessBlock, burned chain.Dimensions) error {
+	var total uint64
+	for i := chain.Dimension(0); int(i) < chain.FeeDimensions(); i++ {
+		total += burned[i]
+	}
+	c.metrics.feesBurned.Add(float64(total))
+	return nil
+}
+
+func (c *Controller) Rewarded(_ context.Context, _ *chain.StatelessBlock, _ codec.Address, amount uint64) error {
+	c.metrics.rewardsMinted.Add(float64(amount))
+	return nil
+}
+
+func (c *Controller) Shutdown(context.Context) error {
 	// Do not close any databases provided during initialization. The VM will
 	// close any databases your provided.
-	return nil
+	//
+	// [metaDB] is only used by the controller, so we close it here.
+	return c.metaDB.Close()
 }
@@ -21,8 +21,20 @@ type metrics struct {
 	fillOrder   prometheus.Counter
 	closeOrder  prometheus.Counter
 
+	createPool      prometheus.Counter
+	addLiquidity    prometheus.Counter
+	removeLiquidity prometheus.Counter
+	swapExact       prometheus.Counter
+
+	openStream     prometheus.Counter
+	withdrawStream prometheus.Counter
+	closeStream    prometheus.Counter
+
 	importAsset prometheus.Counter
 	exportAsset prometheus.Counter
+
+	feesBurned    prometheus.Counter
+	rewardsMinted prometheus.Counter
 }
 
 func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
@@ -62,6 +74,41 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "close_order",
 			Help:      "number of close order actions",
 		}),
+		createPool: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "create_pool",
+			Help:      "number of create pool actions",
+		}),
+		addLiquidity: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "add_liquidity",
+			Help:      "number of add liquidity actions",
+		}),
+		removeLiquidity: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "remove_liquidity",
+			Help:      "number of remove liquidity actions",
+		}),
+		swapExact: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "swap_exact",
+			Help:      "number of swap exact actions",
+		}),
+		openStream: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "open_stream",
+			Help:      "number of open stream actions",
+		}),
+		withdrawStream: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "withdraw_stream",
+			Help:      "number of withdraw stream actions",
+		}),
+		closeStream: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "close_stream",
+			Help:      "number of close stream actions",
+		}),
 		importAsset: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "actions",
 			Name:      "import_asset",
@@ -72,6 +119,16 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "export_asset",
 			Help:      "number of export asset actions",
 		}),
+		feesBurned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "fees_burned",
+			Help:      "amount of the native asset burned to pay fees",
+		}),
+		rewardsMinted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "rewards_minted",
+			Help:      "amount of the native asset minted as block rewards",
+		}),
 	}
 	r := prometheus.NewRegistry()
 	errs := wrappers.Errs{}
@@ -86,8 +143,20 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 		r.Register(m.fillOrder),
 		r.Register(m.closeOrder),
 
+		r.Register(m.createPool),
+		r.Register(m.addLiquidity),
+		r.Register(m.removeLiquidity),
+		r.Register(m.swapExact),
+
+		r.Register(m.openStream),
+		r.Register(m.withdrawStream),
+		r.Register(m.closeStream),
+
 		r.Register(m.importAsset),
 		r.Register(m.exportAsset),
+
+		r.Register(m.feesBurned),
+		r.Register(m.rewardsMinted),
 		gatherer.Register(consts.Name, r),
 	)
 	return m, errs.Err
@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	smath "github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
@@ -31,7 +32,7 @@ func (c *Controller) Tracer() trace.Tracer {
 func (c *Controller) GetTransaction(
 	ctx context.Context,
 	txID ids.ID,
-) (bool, int64, bool, chain.Dimensions, uint64, error) {
+) (bool, int64, chain.Status, chain.Dimensions, uint64, error) {
 	return storage.GetTransaction(ctx, c.metaDB, txID)
 }
 
@@ -50,6 +51,35 @@ func (c *Controller) GetBalanceFromState(
 	return storage.GetBalanceFromState(ctx, c.inner.ReadState, addr, asset)
 }
 
+func (c *Controller) GetBalanceAt(
+	ctx context.Context,
+	addr codec.Address,
+	asset ids.ID,
+	height uint64,
+) (uint64, error) {
+	return storage.GetBalanceAt(ctx, c.metaDB, addr, asset, height)
+}
+
+func (c *Controller) LastAcceptedHeight() uint64 {
+	return c.inner.LastAcceptedBlock().Height()
+}
+
+// TotalBurned returns the total fees burned (in the native asset).
+func (c *Controller) TotalBurned(ctx context.Context) (uint64, error) {
+	burned, err := c.inner.Burned(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	for _, amount := range burned {
+		total, err = smath.Add64(total, amount)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
 func (c *Controller) Orders(pair string, limit int) []*orderbook.Order {
 	return c.orderBook.Orders(pair, limit)
 }
@@ -77,3 +107,52 @@ func (c *Controller) GetLoanFromState(
 ) (uint64, error) {
 	return storage.GetLoanFromState(ctx, c.inner.ReadState, asset, destination)
 }
+
+func (c *Controller) GetAllowanceFromState(
+	ctx context.Context,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, error) {
+	return storage.GetAllowanceFromState(ctx, c.inner.ReadState, owner, spender, asset)
+}
+
+func (c *Controller) GetFeeRateFromState(
+	ctx context.Context,
+	asset ids.ID,
+) (bool, int64, uint64, uint64, error) {
+	return storage.GetFeeRateFromState(ctx, c.inner.ReadState, asset)
+}
+
+func (c *Controller) GetPoolFromState(
+	ctx context.Context,
+	assetA ids.ID,
+	assetB ids.ID,
+) (bool, uint64, uint64, uint64, error) {
+	return storage.GetPoolFromState(ctx, c.inner.ReadState, assetA, assetB)
+}
+
+func (c *Controller) GetLiquidityFromState(
+	ctx context.Context,
+	pool ids.ID,
+	owner codec.Address,
+) (uint64, error) {
+	return storage.GetLiquidityFromState(ctx, c.inner.ReadState, pool, owner)
+}
+
+func (c *Controller) GetStreamFromState(
+	ctx context.Context,
+	stream ids.ID,
+) (
+	bool, // exists
+	codec.Address, // payer
+	codec.Address, // payee
+	ids.ID, // asset
+	uint64, // rate
+	int64, // start
+	uint64, // deposit
+	uint64, // withdrawn
+	error,
+) {
+	return storage.GetStreamFromState(ctx, c.inner.ReadState, stream)
+}
@@ -5,15 +5,22 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/state"
 )
 
-var _ (chain.StateManager) = (*StateManager)(nil)
+var (
+	_ (chain.StateManager) = (*StateManager)(nil)
+	_ (chain.Minter)       = (*StateManager)(nil)
+	_ (chain.NonceManager) = (*StateManager)(nil)
+	_ (chain.FeeConverter) = (*StateManager)(nil)
+)
 
 type StateManager struct{}
 
@@ -29,14 +36,22 @@ func (*StateManager) FeeKey() []byte {
 	return storage.HeightKey()
 }
 
-func (*StateManager) IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) []byte {
-	return storage.IncomingWarpKeyPrefix(sourceChainID, msgID)
+func (*StateManager) BurnKey() []byte {
+	return storage.BurnKey()
+}
+
+func (*StateManager) IncomingWarpPrefix() []byte {
+	return storage.IncomingWarpPrefix()
 }
 
 func (*StateManager) OutgoingWarpKeyPrefix(txID ids.ID) []byte {
 	return storage.OutgoingWarpKeyPrefix(txID)
 }
 
+func (*StateManager) NonceKey(addr codec.Address) []byte {
+	return storage.NonceKey(addr)
+}
+
 func (*StateManager) SponsorStateKeys(addr codec.Address) []string {
 	return []string{
 		string(storage.BalanceKey(addr, ids.Empty)),
@@ -77,3 +92,199 @@ func (*StateManager) Refund(
 	// Don't create account if it doesn't exist (may have sent all funds).
 	return storage.AddBalance(ctx, mu, addr, ids.Empty, amount, false)
 }
+
+func (*StateManager) Credit(
+	ctx context.Context,
+	addr codec.Address,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	return storage.AddBalance(ctx, mu, addr, ids.Empty, amount, true)
+}
+
+func (*StateManager) MintStateKeys(addr codec.Address) []string {
+	return []string{
+		string(storage.BalanceKey(addr, ids.Empty)),
+		string(storage.AssetKey(ids.Empty)),
+	}
+}
+
+// Mint credits [amount] of the native asset to [addr] and adds it to the
+// supply of the native asset.
+func (*StateManager) Mint(
+	ctx context.Context,
+	addr codec.Address,
+	mu state.Mutable,
+	amount uint64,
+) error {
+	exists, symbol, decimals, metadata, supply, owner, isWarp, err := storage.GetAsset(ctx, mu, ids.Empty)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return storage.ErrNativeAssetMissing
+	}
+	newSupply, err := smath.Add64(supply, amount)
+	if err != nil {
+		return err
+	}
+	if err := storage.SetAsset(ctx, mu, ids.Empty, symbol, decimals, metadata, newSupply, owner, isWarp); err != nil {
+		return err
+	}
+	return storage.AddBalance(ctx, mu, addr, ids.Empty, amount, true)
+}
+
+// feeAssetRules is implemented by the rules of the tokenvm (see
+// [genesis.FeeAsset]).
+type feeAssetRules interface {
+	GetFeeAsset(asset ids.ID) (codec.Address, int64, bool)
+}
+
+func getPaymaster(r chain.Rules, asset ids.ID) (codec.Address, int64, error) {
+	fr, ok := chain.UnwrapRules(r).(feeAssetRules)
+	if !ok {
+		return codec.EmptyAddress, 0, storage.ErrFeeAssetNotAllowed
+	}
+	paymaster, maxRateAge, ok := fr.GetFeeAsset(asset)
+	if !ok {
+		return codec.EmptyAddress, 0, fmt.Errorf("%w: %s", storage.ErrFeeAssetNotAllowed, asset)
+	}
+	return paymaster, maxRateAge, nil
+}
+
+// convertFee returns the paymaster of [asset] and the amount of [asset]
+// worth [amount] at its latest fee rate.
+func convertFee(
+	ctx context.Context,
+	r chain.Rules,
+	asset ids.ID,
+	im state.Immutable,
+	timestamp int64,
+	amount uint64,
+) (codec.Address, uint64, error) {
+	paymaster, maxRateAge, err := getPaymaster(r, asset)
+	if err != nil {
+		return codec.EmptyAddress, 0, err
+	}
+	exists, posted, native, units, err := storage.GetFeeRate(ctx, im, asset)
+	if err != nil {
+		return codec.EmptyAddress, 0, err
+	}
+	if !exists {
+		return codec.EmptyAddress, 0, fmt.Errorf("%w: %s", storage.ErrFeeRateMissing, asset)
+	}
+	if maxRateAge > 0 && timestamp-posted > maxRateAge {
+		return codec.EmptyAddress, 0, fmt.Errorf("%w: %s posted at %d", storage.ErrStaleFeeRate, asset, posted)
+	}
+	converted, err := storage.ConvertFee(native, units, amount)
+	if err != nil {
+		return codec.EmptyAddress, 0, err
+	}
+	return paymaster, converted, nil
+}
+
+// FeeAssetStateKeys includes the balances of the paymaster of [asset], which
+// pays the native fee and receives the converted [asset].
+func (*StateManager) FeeAssetStateKeys(r chain.Rules, addr codec.Address, asset ids.ID) []string {
+	keys := []string{
+		string(storage.BalanceKey(addr, asset)),
+		string(storage.FeeRateKey(asset)),
+	}
+	if paymaster, _, err := getPaymaster(r, asset); err == nil {
+		keys = append(keys,
+			string(storage.BalanceKey(paymaster, ids.Empty)),
+			string(storage.BalanceKey(paymaster, asset)),
+		)
+	}
+	return keys
+}
+
+func (*StateManager) CanDeductAsset(
+	ctx context.Context,
+	r chain.Rules,
+	addr codec.Address,
+	asset ids.ID,
+	im state.Immutable,
+	timestamp int64,
+	amount uint64,
+) (uint64, error) {
+	paymaster, converted, err := convertFee(ctx, r, asset, im, timestamp, amount)
+	if err != nil {
+		return 0, err
+	}
+	bal, err := storage.GetBalance(ctx, im, addr, asset)
+	if err != nil {
+		return 0, err
+	}
+	if bal < converted {
+		return 0, storage.ErrInvalidBalance
+	}
+	nativeBal, err := storage.GetBalance(ctx, im, paymaster, ids.Empty)
+	if err != nil {
+		return 0, err
+	}
+	if nativeBal < amount {
+		return 0, fmt.Errorf("%w: paymaster cannot pay fee", storage.ErrInvalidBalance)
+	}
+	return converted, nil
+}
+
+// DeductAsset moves the converted fee from [addr] to the paymaster of
+// [asset], which pays [amount] of the native asset.
+func (*StateManager) DeductAsset(
+	ctx context.Context,
+	r chain.Rules,
+	addr codec.Address,
+	asset ids.ID,
+	mu state.Mutable,
+	timestamp int64,
+	amount uint64,
+) (uint64, error) {
+	paymaster, converted, err := convertFee(ctx, r, asset, mu, timestamp, amount)
+	if err != nil {
+		return 0, err
+	}
+	if err := storage.SubBalance(ctx, mu, addr, asset, converted); err != nil {
+		return 0, err
+	}
+	if err := storage.AddBalance(ctx, mu, paymaster, asset, converted, true); err != nil {
+		return 0, err
+	}
+	if err := storage.SubBalance(ctx, mu, paymaster, ids.Empty, amount); err != nil {
+		return 0, err
+	}
+	return converted, nil
+}
+
+func (*StateManager) RefundAsset(
+	ctx context.Context,
+	r chain.Rules,
+	addr codec.Address,
+	asset ids.ID,
+	mu state.Mutable,
+	amount uint64,
+	converted uint64,
+) error {
+	paymaster, _, err := getPaymaster(r, asset)
+	if err != nil {
+		return err
+	}
+	// Don't create the account of the paymaster if it doesn't exist (may have
+	// sent all funds).
+	if err := storage.AddBalance(ctx, mu, paymaster, ids.Empty, amount, false); err != nil {
+		return err
+	}
+	if converted == 0 {
+		return nil
+	}
+	// If [addr] spent all of [asset], the paymaster keeps the refund
+	// (balances of 0 are removed and refunds can't create keys).
+	bal, err := storage.GetBalance(ctx, mu, addr, asset)
+	if err != nil || bal == 0 {
+		return err
+	}
+	if err := storage.SubBalance(ctx, mu, paymaster, asset, converted); err != nil {
+		return err
+	}
+	return storage.AddBalance(ctx, mu, addr, asset, converted, false)
+}
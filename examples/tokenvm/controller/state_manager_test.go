@@ -0,0 +1,77 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+func TestFeeAssets(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var (
+		sm        = &StateManager{}
+		s         = chaintest.State{}
+		asset     = ids.GenerateTestID()
+		user      = codec.CreateAddress(0, ids.GenerateTestID())
+		paymaster = codec.CreateAddress(0, ids.GenerateTestID())
+		g         = genesis.Default()
+	)
+	g.FeeAssets = []*genesis.FeeAsset{{Asset: asset, Paymaster: paymaster, MaxRateAge: 1_000}}
+	r := g.Rules(0, 1, ids.GenerateTestID())
+	require.NoError(storage.SetBalance(ctx, s, user, asset, 500))
+	require.NoError(storage.SetBalance(ctx, s, paymaster, ids.Empty, 1_000))
+	require.Len(sm.FeeAssetStateKeys(r, user, asset), len(r.GetFeeAssetStateKeysMaxChunks()))
+
+	// Fees can't be paid in assets that aren't allowed or without a rate
+	_, err := sm.CanDeductAsset(ctx, r, user, ids.GenerateTestID(), s, 0, 100)
+	require.ErrorIs(err, storage.ErrFeeAssetNotAllowed)
+	_, err = sm.CanDeductAsset(ctx, r, user, asset, s, 0, 100)
+	require.ErrorIs(err, storage.ErrFeeRateMissing)
+
+	// 3 units of [asset] are worth 2 of the native asset
+	require.NoError(storage.SetFeeRate(ctx, s, asset, 0, 2, 3))
+	converted, err := sm.CanDeductAsset(ctx, r, user, asset, s, 500, 100)
+	require.NoError(err)
+	require.Equal(uint64(150), converted)
+	_, err = sm.CanDeductAsset(ctx, r, user, asset, s, 500, 1_000)
+	require.ErrorIs(err, storage.ErrInvalidBalance)
+	_, err = sm.CanDeductAsset(ctx, r, user, asset, s, 1_001, 100)
+	require.ErrorIs(err, storage.ErrStaleFeeRate)
+
+	// Conversions are rounded up
+	converted, err = sm.DeductAsset(ctx, r, user, asset, s, 500, 101)
+	require.NoError(err)
+	require.Equal(uint64(152), converted)
+	checkBalance := func(addr codec.Address, asset ids.ID, expected uint64) {
+		bal, err := storage.GetBalance(ctx, s, addr, asset)
+		require.NoError(err)
+		require.Equal(expected, bal)
+	}
+	checkBalance(user, asset, 348)
+	checkBalance(paymaster, asset, 152)
+	checkBalance(paymaster, ids.Empty, 899)
+
+	require.NoError(sm.RefundAsset(ctx, r, user, asset, s, 41, 61))
+	checkBalance(user, asset, 409)
+	checkBalance(paymaster, asset, 91)
+	checkBalance(paymaster, ids.Empty, 940)
+
+	// Refunds aren't returned to accounts that no longer exist
+	require.NoError(storage.DeleteBalance(ctx, s, user, asset))
+	require.NoError(sm.RefundAsset(ctx, r, user, asset, s, 10, 15))
+	checkBalance(user, asset, 0)
+	checkBalance(paymaster, asset, 91)
+	checkBalance(paymaster, ids.Empty, 950)
+}
@@ -8,4 +8,8 @@ import "errors"
 var (
 	ErrInvalidHRP    = errors.New("invalid HRP")
 	ErrInvalidTarget = errors.New("invalid target")
+
+	ErrInvalidFeeAsset = errors.New("invalid fee asset")
+
+	ErrInvalidWarpMaxSigners = errors.New("warp max signers must not be negative")
 )
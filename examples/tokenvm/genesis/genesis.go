@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
 	"github.com/ava-labs/hypersdk/chain"
@@ -29,6 +30,18 @@ type CustomAllocation struct {
 	Balance uint64 `json:"balance"`
 }
 
+// FeeAsset is an asset that can pay the fees of transactions instead of the
+// native asset (see chain.Base.FeeAsset). Its [Paymaster] posts the rate the
+// asset is converted at (with an UpdateFeeRate action, which takes it from
+// an open order of the paymaster that sells the native asset for the asset)
+// and, in exchange for the converted asset, pays the fees in the native
+// asset.
+type FeeAsset struct {
+	Asset      ids.ID        `json:"asset"`
+	Paymaster  codec.Address `json:"paymaster"`  // hex
+	MaxRateAge int64         `json:"maxRateAge"` // ms (0 means rates never go stale)
+}
+
 type Genesis struct {
 	// State Parameters
 	StateBranchFactor merkledb.BranchFactor `json:"stateBranchFactor"`
@@ -42,9 +55,34 @@ type Genesis struct {
 	UnitPriceChangeDenominator chain.Dimensions `json:"unitPriceChangeDenominator"`
 	WindowTargetUnits          chain.Dimensions `json:"windowTargetUnits"` // 10s
 	MaxBlockUnits              chain.Dimensions `json:"maxBlockUnits"`     // must be possible to reach before block too large
+	BuilderFeeShare            uint64           `json:"builderFeeShare"`   // % of fees credited to the block builder (rest is burned)
+	MinFee                     uint64           `json:"minFee"`            // charged to each tx that pays fees, 0 disables
+
+	// Block Reward Parameters
+	//
+	// Each block mints [BlockReward] (halved every [BlockRewardHalvingInterval]
+	// blocks) to its beneficiary or, if it has none, to [BlockRewardPool].
+	BlockReward                uint64        `json:"blockReward"`                // 0 disables rewards
+	BlockRewardHalvingInterval uint64        `json:"blockRewardHalvingInterval"` // blocks (0 disables halving)
+	BlockRewardPool            codec.Address `json:"blockRewardPool"`            // hex (if empty, blocks without a beneficiary mint nothing)
 
 	// Tx Parameters
-	ValidityWindow int64 `json:"validityWindow"` // ms
+	ValidityWindow       int64  `json:"validityWindow"`       // ms
+	HeightValidityWindow uint64 `json:"heightValidityWindow"` // blocks (0 disables height expiry)
+	EpochLength          uint64 `json:"epochLength"`          // blocks (0 disables epochs)
+	ValidatorSnapshots   bool   `json:"validatorSnapshots"`   // snapshot the validator set at the end of each epoch
+	BlockWitnesses       bool   `json:"blockWitnesses"`       // include merkle witnesses of all keys read in blocks
+	SequentialNonces     bool   `json:"sequentialNonces"`     // protect txs from replay with account nonces instead of expiry
+	WarpReplayRetention  int64  `json:"warpReplayRetention"`  // ms after being exported that warp messages can be imported (0 for no limit)
+
+	// Warp Parameters
+	//
+	// Imported messages must be signed by at least
+	// [WarpQuorumNumerator]/[WarpQuorumDenominator] of the stake weight of
+	// their source subnet.
+	WarpQuorumNumerator   uint64 `json:"warpQuorumNumerator"`
+	WarpQuorumDenominator uint64 `json:"warpQuorumDenominator"`
+	WarpMaxSigners        int    `json:"warpMaxSigners"` // 0 for no limit
 
 	// Tx Fee Parameters
 	BaseComputeUnits          uint64 `json:"baseUnits"`
@@ -58,8 +96,22 @@ type Genesis struct {
 	StorageKeyWriteUnits      uint64 `json:"storageKeyWriteUnits"`
 	StorageValueWriteUnits    uint64 `json:"storageValueWriteUnits"` // per chunk
 
+	// ActionComputeUnits overrides the compute units charged for executing
+	// each action (keyed by action type ID).
+	ActionComputeUnits map[uint8]uint64 `json:"actionComputeUnits"`
+
+	// ActionFeePayers charges the fees of transactions with each action
+	// (keyed by action type ID) to an account (hex) instead of their sponsor.
+	// Actions mapped to the empty address are exempt from fees.
+	ActionFeePayers map[uint8]codec.Address `json:"actionFeePayers"`
+
+	// FeeAssets can pay fees instead of the native asset.
+	FeeAssets []*FeeAsset `json:"feeAssets"`
+
 	// Allocates
 	CustomAllocation []*CustomAllocation `json:"customAllocation"`
+
+	activations []*activation
 }
 
 func Default() *Genesis {
@@ -80,6 +132,10 @@ func Default() *Genesis {
 		// Tx Parameters
 		ValidityWindow: 60 * hconsts.MillisecondsPerSecond, // ms
 
+		// Warp Parameters
+		WarpQuorumNumerator:   4,
+		WarpQuorumDenominator: 5,
+
 		// Tx Fee Compute Parameters
 		BaseComputeUnits:          1,
 		BaseWarpComputeUnits:      1_024,
@@ -98,16 +154,58 @@ func Default() *Genesis {
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal config %s: %w", string(b), err)
 		}
 	}
+	if len(upgradeBytes) > 0 {
+		var upgrades []*Upgrade
+		if err := json.Unmarshal(upgradeBytes, &upgrades); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal upgrades %s: %w", string(upgradeBytes), err)
+		}
+		for _, upgrade := range upgrades {
+			if err := verifyFeeAssets(upgrade.FeeAssets); err != nil {
+				return nil, err
+			}
+		}
+		g.activations = activate(g, upgrades)
+	}
+	if err := verifyFeeAssets(g.FeeAssets); err != nil {
+		return nil, err
+	}
+	if err := chain.VerifyWarpQuorum(g.WarpQuorumNumerator, g.WarpQuorumDenominator); err != nil {
+		return nil, err
+	}
+	if g.WarpMaxSigners < 0 {
+		return nil, ErrInvalidWarpMaxSigners
+	}
+	for _, fa := range g.FeeAssets {
+		if fa.Paymaster == codec.EmptyAddress {
+			return nil, fmt.Errorf("%w: missing paymaster for %s", ErrInvalidFeeAsset, fa.Asset)
+		}
+	}
 	return g, nil
 }
 
+func verifyFeeAssets(feeAssets []*FeeAsset) error {
+	assets := set.NewSet[ids.ID](len(feeAssets))
+	for _, fa := range feeAssets {
+		switch {
+		case fa.Asset == ids.Empty:
+			return fmt.Errorf("%w: native asset", ErrInvalidFeeAsset)
+		case assets.Contains(fa.Asset):
+			return fmt.Errorf("%w: duplicate asset %s", ErrInvalidFeeAsset, fa.Asset)
+		case fa.MaxRateAge < 0:
+			return fmt.Errorf("%w: negative max rate age for %s", ErrInvalidFeeAsset, fa.Asset)
+		}
+		assets.Add(fa.Asset)
+	}
+	return nil
+}
+
 func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutable) error {
 	ctx, span := tracer.Start(ctx, "Genesis.Load")
 	defer span.End()
@@ -115,6 +213,9 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 	if err := g.StateBranchFactor.Valid(); err != nil {
 		return err
 	}
+	if g.BuilderFeeShare > chain.MaxBuilderFeeShare {
+		return chain.ErrInvalidBuilderFeeShare
+	}
 
 	supply := uint64(0)
 	for _, alloc := range g.CustomAllocation {
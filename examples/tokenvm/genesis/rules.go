@@ -6,6 +6,7 @@ package genesis
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 )
 
@@ -16,19 +17,33 @@ type Rules struct {
 
 	networkID uint32
 	chainID   ids.ID
+	a         *activation
 }
 
-// TODO: use upgradeBytes
-func (g *Genesis) Rules(_ int64, networkID uint32, chainID ids.ID) *Rules {
-	return &Rules{g, networkID, chainID}
+func (g *Genesis) Rules(t int64, networkID uint32, chainID ids.ID) *Rules {
+	return &Rules{g, networkID, chainID, g.activation(t)}
 }
 
-func (*Rules) GetWarpConfig(ids.ID) (bool, uint64, uint64) {
-	// We allow inbound transfers from all sources as long as 80% of stake has
-	// signed a message.
+// IsFeatureActive always returns false because the tokenvm doesn't guard any
+// behavior with feature flags yet.
+func (*Rules) IsFeatureActive(string) bool {
+	return false
+}
+
+func (r *Rules) GetWarpConfig(ids.ID) (bool, uint64, uint64) {
+	// We allow inbound transfers from all sources as long as the quorum of
+	// stake has signed a message.
 	//
 	// This is safe because the tokenvm scopes all assets by their source chain.
-	return true, 4, 5
+	return true, r.g.WarpQuorumNumerator, r.g.WarpQuorumDenominator
+}
+
+func (r *Rules) GetWarpMaxSigners() int {
+	return r.g.WarpMaxSigners
+}
+
+func (r *Rules) GetWarpReplayRetention() int64 {
+	return r.g.WarpReplayRetention
 }
 
 func (r *Rules) NetworkID() uint32 {
@@ -51,6 +66,10 @@ func (r *Rules) GetValidityWindow() int64 {
 	return r.g.ValidityWindow
 }
 
+func (r *Rules) GetHeightValidityWindow() uint64 {
+	return r.g.HeightValidityWindow
+}
+
 func (r *Rules) GetMaxBlockUnits() chain.Dimensions {
 	return r.g.MaxBlockUnits
 }
@@ -75,6 +94,78 @@ func (*Rules) GetSponsorStateKeysMaxChunks() []uint16 {
 	return []uint16{storage.BalanceChunks}
 }
 
+func (r *Rules) GetActionComputeUnits(typeID uint8) (uint64, bool) {
+	units, ok := r.a.actionComputeUnits[typeID]
+	return units, ok
+}
+
+func (r *Rules) IsActionDisabled(typeID uint8) bool {
+	return r.a.disabledActions[typeID]
+}
+
+func (*Rules) GetActionDeprecationHeight(uint8, uint8) (uint64, bool) {
+	return 0, false
+}
+
+func (*Rules) GetAuthDeprecationHeight(uint8, uint8) (uint64, bool) {
+	return 0, false
+}
+
+func (r *Rules) GetBuilderFeeShare() uint64 {
+	return r.g.BuilderFeeShare
+}
+
+func (r *Rules) GetEpochLength() uint64 {
+	return r.g.EpochLength
+}
+
+func (r *Rules) GetBlockReward(height uint64) uint64 {
+	return chain.HalvingReward(r.g.BlockReward, r.g.BlockRewardHalvingInterval, height)
+}
+
+func (r *Rules) GetBlockRewardPool() codec.Address {
+	return r.g.BlockRewardPool
+}
+
+func (r *Rules) GetValidatorSnapshots() bool {
+	return r.g.ValidatorSnapshots
+}
+
+func (r *Rules) GetBlockWitnesses() bool {
+	return r.g.BlockWitnesses
+}
+
+func (r *Rules) GetSequentialNonces() bool {
+	return r.g.SequentialNonces
+}
+
+// GetTxCancellation is always false, as the tokenvm doesn't let sponsors
+// cancel transactions.
+func (*Rules) GetTxCancellation() bool {
+	return false
+}
+
+func (r *Rules) GetActionFeePayer(typeID uint8) (codec.Address, bool) {
+	payer, ok := r.g.ActionFeePayers[typeID]
+	return payer, ok
+}
+
+// GetFeeAsset returns the paymaster of [asset] and the max age of its rate
+// (see [FeeAsset]) if fees can be paid in [asset].
+func (r *Rules) GetFeeAsset(asset ids.ID) (codec.Address, int64, bool) {
+	fa, ok := r.a.feeAssets[asset]
+	if !ok {
+		return codec.EmptyAddress, 0, false
+	}
+	return fa.Paymaster, fa.MaxRateAge, true
+}
+
+// GetFeeAssetStateKeysMaxChunks is the max chunks of the keys touched when
+// fees are paid in a fee asset (see [chain.EstimateMaxFeeAssetUnits]).
+func (*Rules) GetFeeAssetStateKeysMaxChunks() []uint16 {
+	return []uint16{storage.BalanceChunks, storage.FeeRateChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
 func (r *Rules) GetStorageKeyReadUnits() uint64 {
 	return r.g.StorageKeyReadUnits
 }
@@ -103,6 +194,10 @@ func (r *Rules) GetMinUnitPrice() chain.Dimensions {
 	return r.g.MinUnitPrice
 }
 
+func (r *Rules) GetMinFee() uint64 {
+	return r.g.MinFee
+}
+
 func (r *Rules) GetUnitPriceChangeDenominator() chain.Dimensions {
 	return r.g.UnitPriceChangeDenominator
 }
@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Upgrade changes the [Rules] of a live network (provided as a JSON list in
+// upgradeBytes).
+type Upgrade struct {
+	Timestamp int64 `json:"timestamp"` // ms
+
+	// ActionComputeUnits overrides the compute units charged for executing
+	// each action (keyed by action type ID). Overrides are added to those
+	// provided in genesis and by earlier upgrades.
+	ActionComputeUnits map[uint8]uint64 `json:"actionComputeUnits"`
+
+	// DisabledActions prevents actions (keyed by action type ID) from being
+	// included in blocks, so that buggy actions can be sunset. Blocks
+	// accepted before the upgrade can still be replayed. Actions disabled by
+	// earlier upgrades can be enabled again by setting them to false.
+	DisabledActions map[uint8]bool `json:"disabledActions"`
+
+	// FeeAssets are added to (or replace) the fee assets provided in genesis
+	// and by earlier upgrades, so that assets created after genesis can pay
+	// fees. A fee asset without a paymaster can no longer pay fees.
+	FeeAssets []*FeeAsset `json:"feeAssets"`
+}
+
+// activation is the set of overrides in effect starting at [timestamp].
+type activation struct {
+	timestamp          int64
+	actionComputeUnits map[uint8]uint64
+	disabledActions    map[uint8]bool
+	feeAssets          map[ids.ID]*FeeAsset
+}
+
+// activate merges [upgrades] in order of activation so that [Rules] does not
+// need to do so on each call.
+func activate(g *Genesis, upgrades []*Upgrade) []*activation {
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		return upgrades[i].Timestamp < upgrades[j].Timestamp
+	})
+	var (
+		activations = make([]*activation, 0, len(upgrades))
+		prev        = g.genesisActivation()
+	)
+	for _, upgrade := range upgrades {
+		a := &activation{
+			timestamp:          upgrade.Timestamp,
+			actionComputeUnits: merge(prev.actionComputeUnits, upgrade.ActionComputeUnits),
+			disabledActions:    merge(prev.disabledActions, upgrade.DisabledActions),
+			feeAssets:          mergeFeeAssets(prev.feeAssets, upgrade.FeeAssets),
+		}
+		for typeID, disabled := range a.disabledActions {
+			if !disabled {
+				delete(a.disabledActions, typeID)
+			}
+		}
+		activations = append(activations, a)
+		prev = a
+	}
+	return activations
+}
+
+func merge[V any](prev map[uint8]V, next map[uint8]V) map[uint8]V {
+	merged := make(map[uint8]V, len(prev)+len(next))
+	for typeID, v := range prev {
+		merged[typeID] = v
+	}
+	for typeID, v := range next {
+		merged[typeID] = v
+	}
+	return merged
+}
+
+func mergeFeeAssets(prev map[ids.ID]*FeeAsset, next []*FeeAsset) map[ids.ID]*FeeAsset {
+	merged := make(map[ids.ID]*FeeAsset, len(prev)+len(next))
+	for asset, fa := range prev {
+		merged[asset] = fa
+	}
+	for _, fa := range next {
+		if fa.Paymaster == codec.EmptyAddress {
+			delete(merged, fa.Asset)
+			continue
+		}
+		merged[fa.Asset] = fa
+	}
+	return merged
+}
+
+func (g *Genesis) genesisActivation() *activation {
+	return &activation{
+		actionComputeUnits: g.ActionComputeUnits,
+		feeAssets:          mergeFeeAssets(nil, g.FeeAssets),
+	}
+}
+
+// activation returns the overrides in effect at [t].
+func (g *Genesis) activation(t int64) *activation {
+	current := g.genesisActivation()
+	for _, a := range g.activations {
+		if a.timestamp > t {
+			break
+		}
+		current = a
+	}
+	return current
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+)
+
+func TestDecodeOutput(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		parser = &rpc.Parser{}
+		fill   = &chain.Transaction{Action: &actions.FillOrder{Order: ids.GenerateTestID(), Value: 10}}
+		send   = &chain.Transaction{Action: &actions.Transfer{To: codec.EmptyAddress, Value: 10}}
+	)
+	output, err := (&actions.OrderResult{In: 10, Out: 20, Remaining: 30}).Marshal()
+	require.NoError(err)
+
+	r, err := chain.NewTypedResultJSON(parser, fill, &chain.Result{Status: chain.StatusSuccess, Output: output})
+	require.NoError(err)
+	b, err := json.Marshal(r.DecodedOutput)
+	require.NoError(err)
+	require.JSONEq(`{"in":10,"out":20,"remaining":30}`, string(b))
+
+	// Failures and actions without a decoder keep their raw output
+	for _, test := range []struct {
+		tx     *chain.Transaction
+		result *chain.Result
+	}{
+		{fill, &chain.Result{Status: chain.StatusFailed, Output: actions.OutputOrderMissing}},
+		{send, &chain.Result{Status: chain.StatusSuccess, Output: output}},
+	} {
+		r, err := chain.NewTypedResultJSON(parser, test.tx, test.result)
+		require.NoError(err)
+		require.Nil(r.DecodedOutput)
+		require.Equal(test.result.Output, r.Output)
+	}
+
+	// Outputs that don't match their decoder are invalid
+	_, err = chain.NewTypedResultJSON(parser, fill, &chain.Result{Status: chain.StatusSuccess, Output: []byte{1}})
+	require.ErrorIs(err, chain.ErrInvalidObject)
+}
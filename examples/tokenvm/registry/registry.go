@@ -18,6 +18,7 @@ import (
 func init() {
 	consts.ActionRegistry = codec.NewTypeParser[chain.Action, *warp.Message]()
 	consts.AuthRegistry = codec.NewTypeParser[chain.Auth, *warp.Message]()
+	consts.OutputRegistry = chain.NewOutputRegistry()
 
 	errs := &wrappers.Errs{}
 	errs.Add(
@@ -35,8 +36,56 @@ func init() {
 		consts.ActionRegistry.Register((&actions.ImportAsset{}).GetTypeID(), actions.UnmarshalImportAsset, true),
 		consts.ActionRegistry.Register((&actions.ExportAsset{}).GetTypeID(), actions.UnmarshalExportAsset, false),
 
+		consts.ActionRegistry.Register((&actions.Approve{}).GetTypeID(), actions.UnmarshalApprove, false),
+		consts.ActionRegistry.Register((&actions.TransferFrom{}).GetTypeID(), actions.UnmarshalTransferFrom, false),
+		consts.ActionRegistry.Register((&actions.UpdateFeeRate{}).GetTypeID(), actions.UnmarshalUpdateFeeRate, false),
+
+		consts.ActionRegistry.Register((&actions.CreatePool{}).GetTypeID(), actions.UnmarshalCreatePool, false),
+		consts.ActionRegistry.Register((&actions.AddLiquidity{}).GetTypeID(), actions.UnmarshalAddLiquidity, false),
+		consts.ActionRegistry.Register((&actions.RemoveLiquidity{}).GetTypeID(), actions.UnmarshalRemoveLiquidity, false),
+		consts.ActionRegistry.Register((&actions.SwapExact{}).GetTypeID(), actions.UnmarshalSwapExact, false),
+
+		consts.ActionRegistry.Register((&actions.OpenStream{}).GetTypeID(), actions.UnmarshalOpenStream, false),
+		consts.ActionRegistry.Register((&actions.WithdrawStream{}).GetTypeID(), actions.UnmarshalWithdrawStream, false),
+		consts.ActionRegistry.Register((&actions.CloseStream{}).GetTypeID(), actions.UnmarshalCloseStream, false),
+
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
+		consts.AuthRegistry.Register((&auth.Multisig{}).GetTypeID(), auth.UnmarshalMultisig, false),
+	)
+	errs.Add(
+		// Names identify types in the JSON encoding of transactions (unlike type
+		// IDs, they are not part of consensus).
+		consts.ActionRegistry.SetName((&actions.Transfer{}).GetTypeID(), "transfer"),
+		consts.ActionRegistry.SetName((&actions.CreateAsset{}).GetTypeID(), "createAsset"),
+		consts.ActionRegistry.SetName((&actions.MintAsset{}).GetTypeID(), "mintAsset"),
+		consts.ActionRegistry.SetName((&actions.BurnAsset{}).GetTypeID(), "burnAsset"),
+		consts.ActionRegistry.SetName((&actions.CreateOrder{}).GetTypeID(), "createOrder"),
+		consts.ActionRegistry.SetName((&actions.FillOrder{}).GetTypeID(), "fillOrder"),
+		consts.ActionRegistry.SetName((&actions.CloseOrder{}).GetTypeID(), "closeOrder"),
+		consts.ActionRegistry.SetName((&actions.ImportAsset{}).GetTypeID(), "importAsset"),
+		consts.ActionRegistry.SetName((&actions.ExportAsset{}).GetTypeID(), "exportAsset"),
+		consts.ActionRegistry.SetName((&actions.Approve{}).GetTypeID(), "approve"),
+		consts.ActionRegistry.SetName((&actions.TransferFrom{}).GetTypeID(), "transferFrom"),
+		consts.ActionRegistry.SetName((&actions.UpdateFeeRate{}).GetTypeID(), "updateFeeRate"),
+		consts.ActionRegistry.SetName((&actions.CreatePool{}).GetTypeID(), "createPool"),
+		consts.ActionRegistry.SetName((&actions.AddLiquidity{}).GetTypeID(), "addLiquidity"),
+		consts.ActionRegistry.SetName((&actions.RemoveLiquidity{}).GetTypeID(), "removeLiquidity"),
+		consts.ActionRegistry.SetName((&actions.SwapExact{}).GetTypeID(), "swapExact"),
+		consts.ActionRegistry.SetName((&actions.OpenStream{}).GetTypeID(), "openStream"),
+		consts.ActionRegistry.SetName((&actions.WithdrawStream{}).GetTypeID(), "withdrawStream"),
+		consts.ActionRegistry.SetName((&actions.CloseStream{}).GetTypeID(), "closeStream"),
+		consts.AuthRegistry.SetName((&auth.ED25519{}).GetTypeID(), "ed25519"),
+		consts.AuthRegistry.SetName((&auth.Multisig{}).GetTypeID(), "multisig"),
+	)
+	errs.Add(
+		// Decoders render the outputs of successful actions as JSON (see
+		// [chain.NewTypedResultJSON]).
+		consts.OutputRegistry.Register((&actions.FillOrder{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalOrderResult)),
+		consts.OutputRegistry.Register((&actions.CreatePool{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalLiquidityResult)),
+		consts.OutputRegistry.Register((&actions.AddLiquidity{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalLiquidityResult)),
+		consts.OutputRegistry.Register((&actions.RemoveLiquidity{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalLiquidityResult)),
+		consts.OutputRegistry.Register((&actions.SwapExact{}).GetTypeID(), chain.NewOutputDecoder(actions.UnmarshalSwapResult)),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
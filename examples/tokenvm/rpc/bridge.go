@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+)
+
+// BridgeSupply is the supply of an asset bridged from a source chain to a
+// destination chain.
+type BridgeSupply struct {
+	Asset         ids.ID `json:"asset"`         // on the source
+	ImportedAsset ids.ID `json:"importedAsset"` // on the destination
+	Locked        uint64 `json:"locked"`
+	Minted        uint64 `json:"minted"`
+	InFlight      uint64 `json:"inFlight"`
+}
+
+// ReconcileBridge compares the value of [asset] (native to the chain of
+// [source]) locked for the chain of [destination] with the supply minted for
+// it on [destination] (see [actions.ReconcileBridge]).
+//
+// The chains are queried one after the other, so a transfer that completes
+// between the queries can cause a spurious [actions.ErrUnbackedSupply]. The
+// check should be repeated before concluding that the bridge is broken.
+func ReconcileBridge(
+	ctx context.Context,
+	source *JSONRPCClient,
+	destination *JSONRPCClient,
+	asset ids.ID,
+) (*BridgeSupply, error) {
+	importedAsset := actions.ImportedAssetID(asset, source.chainID)
+	_, _, _, _, minted, _, _, err := destination.Asset(ctx, importedAsset, false)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := source.Loan(ctx, asset, destination.chainID)
+	if err != nil {
+		return nil, err
+	}
+	supply := &BridgeSupply{
+		Asset:         asset,
+		ImportedAsset: importedAsset,
+		Locked:        locked,
+		Minted:        minted,
+	}
+	supply.InFlight, err = actions.ReconcileBridge(locked, minted)
+	return supply, err
+}
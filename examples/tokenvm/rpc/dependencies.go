@@ -17,9 +17,12 @@ import (
 type Controller interface {
 	Genesis() *genesis.Genesis
 	Tracer() trace.Tracer
-	GetTransaction(context.Context, ids.ID) (bool, int64, bool, chain.Dimensions, uint64, error)
+	GetTransaction(context.Context, ids.ID) (bool, int64, chain.Status, chain.Dimensions, uint64, error)
 	GetAssetFromState(context.Context, ids.ID) (bool, []byte, uint8, []byte, uint64, codec.Address, bool, error)
 	GetBalanceFromState(context.Context, codec.Address, ids.ID) (uint64, error)
+	GetBalanceAt(context.Context, codec.Address, ids.ID, uint64) (uint64, error)
+	LastAcceptedHeight() uint64
+	TotalBurned(context.Context) (uint64, error)
 	Orders(pair string, limit int) []*orderbook.Order
 	GetOrderFromState(context.Context, ids.ID) (
 		bool, // exists
@@ -32,4 +35,9 @@ type Controller interface {
 		error,
 	)
 	GetLoanFromState(context.Context, ids.ID, ids.ID) (uint64, error)
+	GetAllowanceFromState(context.Context, codec.Address, codec.Address, ids.ID) (uint64, error)
+	GetFeeRateFromState(context.Context, ids.ID) (bool, int64, uint64, uint64, error)
+	GetPoolFromState(context.Context, ids.ID, ids.ID) (bool, uint64, uint64, uint64, error)
+	GetLiquidityFromState(context.Context, ids.ID, codec.Address) (uint64, error)
+	GetStreamFromState(context.Context, ids.ID) (bool, codec.Address, codec.Address, ids.ID, uint64, int64, uint64, uint64, error)
 }
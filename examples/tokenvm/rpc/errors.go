@@ -6,7 +6,10 @@ package rpc
 import "errors"
 
 var (
-	ErrTxNotFound    = errors.New("tx not found")
-	ErrAssetNotFound = errors.New("asset not found")
-	ErrOrderNotFound = errors.New("order not found")
+	ErrTxNotFound     = errors.New("tx not found")
+	ErrAssetNotFound  = errors.New("asset not found")
+	ErrSupplyMismatch = errors.New("burned exceeds supply")
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrPoolNotFound   = errors.New("pool not found")
+	ErrStreamNotFound = errors.New("stream not found")
 )
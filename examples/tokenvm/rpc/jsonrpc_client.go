@@ -3,11 +3,14 @@
 
 package rpc
 
+//go:generate go run github.com/ava-labs/hypersdk/cmd/hypersdk-cli gen-client -registry ../registry/registry.go -actions ../actions
+
 import (
 	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 
@@ -44,6 +47,34 @@ func NewJSONRPCClient(uri string, networkID uint32, chainID ids.ID) *JSONRPCClie
 	}
 }
 
+// NewJSONRPCClientWithConfig returns a client that sends each request to the
+// first healthy node of [uris] and retries requests as configured by
+// [config].
+func NewJSONRPCClientWithConfig(uris []string, networkID uint32, chainID ids.ID, config *requester.Config) *JSONRPCClient {
+	req := requester.NewWithConfig(
+		rpc.EndpointURIs(uris, JSONRPCEndpoint),
+		consts.Name,
+		config.WithDefaultHealthMethod("genesis"),
+	)
+	return &JSONRPCClient{
+		requester: req,
+		networkID: networkID,
+		chainID:   chainID,
+		assets:    map[ids.ID]*AssetReply{},
+	}
+}
+
+// Endpoints returns the health of each node [cli] sends requests to.
+func (cli *JSONRPCClient) Endpoints() []*requester.EndpointStatus {
+	return cli.requester.Endpoints()
+}
+
+// MonitorHealth checks each node [cli] sends requests to every [interval]
+// until [ctx] is done.
+func (cli *JSONRPCClient) MonitorHealth(ctx context.Context, interval time.Duration) error {
+	return cli.requester.MonitorHealth(ctx, interval)
+}
+
 func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error) {
 	if cli.g != nil {
 		return cli.g, nil
@@ -63,7 +94,9 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error)
 	return resp.Genesis, nil
 }
 
-func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, bool, int64, uint64, error) {
+// Tx returns whether [id] was found and, if it was, its status, timestamp and
+// fee.
+func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, chain.Status, int64, uint64, error) {
 	resp := new(TxReply)
 	err := cli.requester.SendRequest(
 		ctx,
@@ -75,11 +108,11 @@ func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, bool, int64,
 	// We use string parsing here because the JSON-RPC library we use may not
 	// allows us to perform errors.Is.
 	case err != nil && strings.Contains(err.Error(), ErrTxNotFound.Error()):
-		return false, false, -1, 0, nil
+		return false, chain.StatusFailed, -1, 0, nil
 	case err != nil:
-		return false, false, -1, 0, err
+		return false, chain.StatusFailed, -1, 0, err
 	}
-	return true, resp.Success, resp.Timestamp, resp.Fee, nil
+	return true, resp.Status, resp.Timestamp, resp.Fee, nil
 }
 
 func (cli *JSONRPCClient) Asset(
@@ -130,6 +163,36 @@ func (cli *JSONRPCClient) Balance(ctx context.Context, addr string, asset ids.ID
 	return resp.Amount, err
 }
 
+// BalanceAt returns the balance of [asset] held by [addr] after the block at
+// [height] was accepted.
+func (cli *JSONRPCClient) BalanceAt(ctx context.Context, addr string, asset ids.ID, height uint64) (uint64, error) {
+	resp := new(GetBalanceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getBalance",
+		&GetBalanceArgs{
+			Address: addr,
+			Asset:   asset,
+			Height:  &height,
+		},
+		resp,
+	)
+	return resp.Amount, err
+}
+
+func (cli *JSONRPCClient) TotalSupply(ctx context.Context, asset ids.ID) (*GetTotalSupplyReply, error) {
+	resp := new(GetTotalSupplyReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getTotalSupply",
+		&GetTotalSupplyArgs{
+			Asset: asset,
+		},
+		resp,
+	)
+	return resp, err
+}
+
 func (cli *JSONRPCClient) Orders(ctx context.Context, pair string) ([]*orderbook.Order, error) {
 	resp := new(OrdersReply)
 	err := cli.requester.SendRequest(
@@ -174,6 +237,112 @@ func (cli *JSONRPCClient) Loan(
 	return resp.Amount, err
 }
 
+// Allowance returns the amount of [asset] that [spender] can transfer on
+// behalf of [owner] with a TransferFrom action.
+func (cli *JSONRPCClient) Allowance(
+	ctx context.Context,
+	owner string,
+	spender string,
+	asset ids.ID,
+) (uint64, error) {
+	resp := new(AllowanceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"allowance",
+		&AllowanceArgs{
+			Owner:   owner,
+			Spender: spender,
+			Asset:   asset,
+		},
+		resp,
+	)
+	return resp.Amount, err
+}
+
+// FeeRate returns the latest rate posted for paying fees in [asset]: [units]
+// of [asset] are worth [native] of the native asset.
+func (cli *JSONRPCClient) FeeRate(
+	ctx context.Context,
+	asset ids.ID,
+) (bool, int64, uint64, uint64, error) {
+	resp := new(FeeRateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"feeRate",
+		&FeeRateArgs{
+			Asset: asset,
+		},
+		resp,
+	)
+	return resp.Exists, resp.Timestamp, resp.Native, resp.Units, err
+}
+
+// Pool returns the ID of the pool of [assetA] and [assetB], its reserves of
+// each (in the order they are provided), and its total shares.
+func (cli *JSONRPCClient) Pool(
+	ctx context.Context,
+	assetA ids.ID,
+	assetB ids.ID,
+) (bool, ids.ID, uint64, uint64, uint64, error) {
+	resp := new(PoolReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"pool",
+		&PoolArgs{
+			AssetA: assetA,
+			AssetB: assetB,
+		},
+		resp,
+	)
+	switch {
+	// We use string parsing here because the JSON-RPC library we use may not
+	// allows us to perform errors.Is.
+	case err != nil && strings.Contains(err.Error(), ErrPoolNotFound.Error()):
+		return false, ids.Empty, 0, 0, 0, nil
+	case err != nil:
+		return false, ids.Empty, 0, 0, 0, err
+	}
+	return true, resp.ID, resp.ReserveA, resp.ReserveB, resp.Shares, nil
+}
+
+// Liquidity returns the shares of [pool] owned by [addr].
+func (cli *JSONRPCClient) Liquidity(ctx context.Context, pool ids.ID, addr string) (uint64, error) {
+	resp := new(LiquidityReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"liquidity",
+		&LiquidityArgs{
+			Pool:    pool,
+			Address: addr,
+		},
+		resp,
+	)
+	return resp.Shares, err
+}
+
+// Stream returns the state of [stream]. The amount that can currently be
+// withdrawn is [storage.Streamed] minus [StreamReply.Withdrawn].
+func (cli *JSONRPCClient) Stream(ctx context.Context, stream ids.ID) (bool, *StreamReply, error) {
+	resp := new(StreamReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"stream",
+		&StreamArgs{
+			Stream: stream,
+		},
+		resp,
+	)
+	switch {
+	// We use string parsing here because the JSON-RPC library we use may not
+	// allows us to perform errors.Is.
+	case err != nil && strings.Contains(err.Error(), ErrStreamNotFound.Error()):
+		return false, nil, nil
+	case err != nil:
+		return false, nil, err
+	}
+	return true, resp, nil
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr string,
@@ -210,11 +379,11 @@ func (cli *JSONRPCClient) WaitForTransaction(ctx context.Context, txID ids.ID) (
 	var success bool
 	var fee uint64
 	if err := rpc.Wait(ctx, func(ctx context.Context) (bool, error) {
-		found, isuccess, _, ifee, err := cli.Tx(ctx, txID)
+		found, status, _, ifee, err := cli.Tx(ctx, txID)
 		if err != nil {
 			return false, err
 		}
-		success = isuccess
+		success = status == chain.StatusSuccess
 		fee = ifee
 		return found, nil
 	}); err != nil {
@@ -223,7 +392,10 @@ func (cli *JSONRPCClient) WaitForTransaction(ctx context.Context, txID ids.ID) (
 	return success, fee, nil
 }
 
-var _ chain.Parser = (*Parser)(nil)
+var (
+	_ chain.Parser             = (*Parser)(nil)
+	_ chain.OutputTypeRegistry = (*Parser)(nil)
+)
 
 type Parser struct {
 	networkID uint32
@@ -239,6 +411,10 @@ func (*Parser) Registry() (chain.ActionRegistry, chain.AuthRegistry) {
 	return consts.ActionRegistry, consts.AuthRegistry
 }
 
+func (*Parser) OutputRegistry() *chain.OutputRegistry {
+	return consts.OutputRegistry
+}
+
 func (cli *JSONRPCClient) Parser(ctx context.Context) (chain.Parser, error) {
 	g, err := cli.Genesis(ctx)
 	if err != nil {
@@ -4,6 +4,7 @@
 package rpc
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -13,6 +14,7 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 )
 
 type JSONRPCServer struct {
@@ -39,6 +41,7 @@ type TxArgs struct {
 type TxReply struct {
 	Timestamp int64            `json:"timestamp"`
 	Success   bool             `json:"success"`
+	Status    chain.Status     `json:"status"`
 	Units     chain.Dimensions `json:"units"`
 	Fee       uint64           `json:"fee"`
 }
@@ -47,7 +50,7 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Tx")
 	defer span.End()
 
-	found, t, success, units, fee, err := j.c.GetTransaction(ctx, args.TxID)
+	found, t, status, units, fee, err := j.c.GetTransaction(ctx, args.TxID)
 	if err != nil {
 		return err
 	}
@@ -55,7 +58,8 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 		return ErrTxNotFound
 	}
 	reply.Timestamp = t
-	reply.Success = success
+	reply.Success = status == chain.StatusSuccess
+	reply.Status = status
 	reply.Units = units
 	reply.Fee = fee
 	return nil
@@ -119,6 +123,84 @@ func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *Bal
 	return err
 }
 
+type GetBalanceArgs struct {
+	Address string `json:"address"`
+	Asset   ids.ID `json:"asset"`
+
+	// Height is the block after which to read the balance (defaults to the
+	// last accepted block). Past heights are only served by nodes that store
+	// the balance history.
+	Height *uint64 `json:"height,omitempty"`
+}
+
+type GetBalanceReply struct {
+	Amount uint64 `json:"amount"`
+	Height uint64 `json:"height"`
+}
+
+func (j *JSONRPCServer) GetBalance(req *http.Request, args *GetBalanceArgs, reply *GetBalanceReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.GetBalance")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	if args.Height == nil {
+		reply.Height = j.c.LastAcceptedHeight()
+		reply.Amount, err = j.c.GetBalanceFromState(ctx, addr, args.Asset)
+		return err
+	}
+	reply.Height = *args.Height
+	reply.Amount, err = j.c.GetBalanceAt(ctx, addr, args.Asset, *args.Height)
+	return err
+}
+
+type GetTotalSupplyArgs struct {
+	Asset ids.ID `json:"asset"`
+}
+
+type GetTotalSupplyReply struct {
+	// Minted is the supply recorded by the asset: everything allocated,
+	// minted, or imported less everything burned (with BurnAsset) or
+	// exported.
+	Minted uint64 `json:"minted"`
+	// Burned is the total of the fees burned (only for the native asset).
+	Burned uint64 `json:"burned"`
+	// Total is the amount of the asset held by accounts (and locked in
+	// orders, pools, and streams).
+	Total  uint64 `json:"total"`
+	Height uint64 `json:"height"`
+}
+
+func (j *JSONRPCServer) GetTotalSupply(req *http.Request, args *GetTotalSupplyArgs, reply *GetTotalSupplyReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.GetTotalSupply")
+	defer span.End()
+
+	reply.Height = j.c.LastAcceptedHeight()
+	exists, _, _, _, supply, _, _, err := j.c.GetAssetFromState(ctx, args.Asset)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAssetNotFound
+	}
+	reply.Minted = supply
+	reply.Total = supply
+	if args.Asset != ids.Empty {
+		return nil
+	}
+	reply.Burned, err = j.c.TotalBurned(ctx)
+	if err != nil {
+		return err
+	}
+	if reply.Burned > supply {
+		return fmt.Errorf("%w: %d > %d", ErrSupplyMismatch, reply.Burned, supply)
+	}
+	reply.Total = supply - reply.Burned
+	return nil
+}
+
 type OrdersArgs struct {
 	Pair string `json:"pair"`
 }
@@ -186,3 +268,149 @@ func (j *JSONRPCServer) Loan(req *http.Request, args *LoanArgs, reply *LoanReply
 	reply.Amount = amount
 	return nil
 }
+
+type AllowanceArgs struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	Asset   ids.ID `json:"asset"`
+}
+
+type AllowanceReply struct {
+	Amount uint64 `json:"amount"`
+}
+
+func (j *JSONRPCServer) Allowance(req *http.Request, args *AllowanceArgs, reply *AllowanceReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Allowance")
+	defer span.End()
+
+	owner, err := codec.ParseAddressBech32(consts.HRP, args.Owner)
+	if err != nil {
+		return err
+	}
+	spender, err := codec.ParseAddressBech32(consts.HRP, args.Spender)
+	if err != nil {
+		return err
+	}
+	amount, err := j.c.GetAllowanceFromState(ctx, owner, spender, args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Amount = amount
+	return nil
+}
+
+type FeeRateArgs struct {
+	Asset ids.ID `json:"asset"`
+}
+
+type FeeRateReply struct {
+	Exists    bool   `json:"exists"`
+	Timestamp int64  `json:"timestamp"`
+	Native    uint64 `json:"native"`
+	Units     uint64 `json:"units"`
+}
+
+func (j *JSONRPCServer) FeeRate(req *http.Request, args *FeeRateArgs, reply *FeeRateReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.FeeRate")
+	defer span.End()
+
+	exists, timestamp, native, units, err := j.c.GetFeeRateFromState(ctx, args.Asset)
+	if err != nil {
+		return err
+	}
+	reply.Exists = exists
+	reply.Timestamp = timestamp
+	reply.Native = native
+	reply.Units = units
+	return nil
+}
+
+type PoolArgs struct {
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+}
+
+type PoolReply struct {
+	ID       ids.ID `json:"id"`
+	ReserveA uint64 `json:"reserveA"`
+	ReserveB uint64 `json:"reserveB"`
+	Shares   uint64 `json:"shares"`
+}
+
+func (j *JSONRPCServer) Pool(req *http.Request, args *PoolArgs, reply *PoolReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Pool")
+	defer span.End()
+
+	exists, reserveA, reserveB, shares, err := j.c.GetPoolFromState(ctx, args.AssetA, args.AssetB)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrPoolNotFound
+	}
+	reply.ID = storage.PoolID(args.AssetA, args.AssetB)
+	reply.ReserveA = reserveA
+	reply.ReserveB = reserveB
+	reply.Shares = shares
+	return nil
+}
+
+type LiquidityArgs struct {
+	Pool    ids.ID `json:"pool"`
+	Address string `json:"address"`
+}
+
+type LiquidityReply struct {
+	Shares uint64 `json:"shares"`
+}
+
+func (j *JSONRPCServer) Liquidity(req *http.Request, args *LiquidityArgs, reply *LiquidityReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Liquidity")
+	defer span.End()
+
+	addr, err := codec.ParseAddressBech32(consts.HRP, args.Address)
+	if err != nil {
+		return err
+	}
+	shares, err := j.c.GetLiquidityFromState(ctx, args.Pool, addr)
+	if err != nil {
+		return err
+	}
+	reply.Shares = shares
+	return nil
+}
+
+type StreamArgs struct {
+	Stream ids.ID `json:"stream"`
+}
+
+type StreamReply struct {
+	Payer     string `json:"payer"`
+	Payee     string `json:"payee"`
+	Asset     ids.ID `json:"asset"`
+	Rate      uint64 `json:"rate"`
+	Start     int64  `json:"start"`
+	Deposit   uint64 `json:"deposit"`
+	Withdrawn uint64 `json:"withdrawn"`
+}
+
+func (j *JSONRPCServer) Stream(req *http.Request, args *StreamArgs, reply *StreamReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Stream")
+	defer span.End()
+
+	exists, payer, payee, asset, rate, start, deposit, withdrawn, err := j.c.GetStreamFromState(ctx, args.Stream)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrStreamNotFound
+	}
+	reply.Payer = codec.MustAddressBech32(consts.HRP, payer)
+	reply.Payee = codec.MustAddressBech32(consts.HRP, payee)
+	reply.Asset = asset
+	reply.Rate = rate
+	reply.Start = start
+	reply.Deposit = deposit
+	reply.Withdrawn = withdrawn
+	return nil
+}
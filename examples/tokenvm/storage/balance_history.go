@@ -0,0 +1,121 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+var balanceHistoryHeightKey = []byte{balanceHistoryHeightPrefix}
+
+// The balance history records the balance of each account after every block
+// that modified it, so that balances can be served at past heights.
+//
+// Heights are stored inverted so that the first key at or after
+// [owner|asset|^height] is the last change at or before [height].
+func balanceHistoryPrefixKey(addr codec.Address, asset ids.ID) []byte {
+	k := make([]byte, 1+codec.AddressLen+consts.IDLen, 1+codec.AddressLen+consts.IDLen+consts.Uint64Len)
+	k[0] = balanceHistoryPrefix
+	copy(k[1:], addr[:])
+	copy(k[1+codec.AddressLen:], asset[:])
+	return k
+}
+
+// [balanceHistoryPrefix] + [address] + [asset] + [^height]
+func BalanceHistoryKey(addr codec.Address, asset ids.ID, height uint64) []byte {
+	return binary.BigEndian.AppendUint64(balanceHistoryPrefixKey(addr, asset), math.MaxUint64-height)
+}
+
+// GetBalanceHistoryHeight returns the height of the last block whose balance
+// changes were stored. False is returned if no block has been indexed.
+func GetBalanceHistoryHeight(db database.KeyValueReader) (uint64, bool, error) {
+	v, err := db.Get(balanceHistoryHeightKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// StoreBalance records that [addr] had [balance] of [asset] as of [height].
+func StoreBalance(db database.KeyValueWriter, addr codec.Address, asset ids.ID, height uint64, balance uint64) error {
+	return db.Put(BalanceHistoryKey(addr, asset, height), binary.BigEndian.AppendUint64(nil, balance))
+}
+
+// StoreBalanceChanges records the balances modified by the block at [height]
+// (the changes of the block, see [chain.StatelessBlock.Changes]) and marks
+// [height] as indexed.
+//
+// Blocks must be stored in order, starting at height 1 (the balances of the
+// genesis should be stored at height 0 with [StoreBalance]).
+func StoreBalanceChanges(
+	_ context.Context,
+	db database.KeyValueWriter,
+	height uint64,
+	changes map[string]maybe.Maybe[[]byte],
+) error {
+	for k, v := range changes {
+		addr, asset, ok := ParseBalanceKey([]byte(k))
+		if !ok {
+			continue
+		}
+		// Removed balances are 0
+		var balance uint64
+		if v.HasValue() {
+			if len(v.Value()) != consts.Uint64Len {
+				return ErrInvalidBalance
+			}
+			balance = binary.BigEndian.Uint64(v.Value())
+		}
+		if err := StoreBalance(db, addr, asset, height, balance); err != nil {
+			return err
+		}
+	}
+	return db.Put(balanceHistoryHeightKey, binary.BigEndian.AppendUint64(nil, height))
+}
+
+// GetBalanceAt returns the balance of [asset] held by [addr] after the block
+// at [height] was accepted.
+//
+// If [height] has not been indexed (it is after the last indexed block, or
+// the history is not stored), [ErrHeightNotIndexed] is returned.
+func GetBalanceAt(
+	_ context.Context,
+	db database.Database,
+	addr codec.Address,
+	asset ids.ID,
+	height uint64,
+) (uint64, error) {
+	indexed, ok, err := GetBalanceHistoryHeight(db)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || height > indexed {
+		return 0, fmt.Errorf("%w: %d", ErrHeightNotIndexed, height)
+	}
+	prefix := balanceHistoryPrefixKey(addr, asset)
+	it := db.NewIteratorWithStartAndPrefix(BalanceHistoryKey(addr, asset, height), prefix)
+	defer it.Release()
+	if !it.Next() {
+		// The balance was never modified (at or before [height])
+		return 0, it.Error()
+	}
+	v := it.Value()
+	if len(v) != consts.Uint64Len {
+		return 0, ErrInvalidBalance
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
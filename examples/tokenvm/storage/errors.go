@@ -5,4 +5,14 @@ package storage
 
 import "errors"
 
-var ErrInvalidBalance = errors.New("invalid balance")
+var (
+	ErrInvalidBalance        = errors.New("invalid balance")
+	ErrNativeAssetMissing    = errors.New("native asset missing")
+	ErrInsufficientAllowance = errors.New("insufficient allowance")
+	ErrHeightNotIndexed      = errors.New("height not indexed")
+
+	ErrInvalidFeeRate     = errors.New("invalid fee rate")
+	ErrFeeRateMissing     = errors.New("fee rate missing")
+	ErrStaleFeeRate       = errors.New("stale fee rate")
+	ErrFeeAssetNotAllowed = errors.New("fees cannot be paid in asset")
+)
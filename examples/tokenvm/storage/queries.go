@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/rpc"
+
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+// Asset is the decoded form of an asset served by the queryState RPC.
+type Asset struct {
+	Symbol   []byte `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	Metadata []byte `json:"metadata"`
+	Supply   uint64 `json:"supply"`
+	Owner    string `json:"owner"`
+	Warp     bool   `json:"warp"`
+}
+
+// Order is the decoded form of an order served by the queryState RPC.
+type Order struct {
+	InAsset   ids.ID `json:"inAsset"`
+	InTick    uint64 `json:"inTick"`
+	OutAsset  ids.ID `json:"outAsset"`
+	OutTick   uint64 `json:"outTick"`
+	Remaining uint64 `json:"remaining"`
+	Owner     string `json:"owner"`
+}
+
+// RegisterStateQueries registers the state served by the queryState RPC.
+//
+// "balance" is keyed by "<address>:<asset>" (or just "<address>" for the
+// native asset), "asset" by asset ID, and "order" by order ID.
+func RegisterStateQueries(r *rpc.StateRegistry) error {
+	if err := r.Register("balance", &rpc.StateQuery{
+		Key: func(key string) ([]byte, error) {
+			rawAddr, rawAsset, _ := strings.Cut(key, ":")
+			addr, err := codec.ParseAddressBech32(tconsts.HRP, rawAddr)
+			if err != nil {
+				return nil, err
+			}
+			asset := ids.Empty
+			if len(rawAsset) > 0 {
+				asset, err = ids.FromString(rawAsset)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return BalanceKey(addr, asset), nil
+		},
+		Decode: func(v []byte) (any, error) {
+			if len(v) != consts.Uint64Len {
+				return nil, ErrInvalidBalance
+			}
+			return binary.BigEndian.Uint64(v), nil
+		},
+	}); err != nil {
+		return err
+	}
+	if err := r.Register("asset", &rpc.StateQuery{
+		Key: idKey(AssetKey),
+		Decode: func(v []byte) (any, error) {
+			_, symbol, decimals, metadata, supply, owner, warp, err := innerGetAsset(v, nil)
+			if err != nil {
+				return nil, err
+			}
+			return &Asset{
+				Symbol:   symbol,
+				Decimals: decimals,
+				Metadata: metadata,
+				Supply:   supply,
+				Owner:    codec.MustAddressBech32(tconsts.HRP, owner),
+				Warp:     warp,
+			}, nil
+		},
+	}); err != nil {
+		return err
+	}
+	return r.Register("order", &rpc.StateQuery{
+		Key: idKey(OrderKey),
+		Decode: func(v []byte) (any, error) {
+			_, in, inTick, out, outTick, remaining, owner, err := innerGetOrder(v, nil)
+			if err != nil {
+				return nil, err
+			}
+			return &Order{
+				InAsset:   in,
+				InTick:    inTick,
+				OutAsset:  out,
+				OutTick:   outTick,
+				Remaining: remaining,
+				Owner:     codec.MustAddressBech32(tconsts.HRP, owner),
+			}, nil
+		},
+	})
+}
+
+func idKey(f func(ids.ID) []byte) func(string) ([]byte, error) {
+	return func(key string) ([]byte, error) {
+		id, err := ids.FromString(key)
+		if err != nil {
+			return nil, err
+		}
+		return f(id), nil
+	}
+}
@@ -4,10 +4,13 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
+	"math/bits"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -18,6 +21,7 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
 )
 
 type ReadState func(context.Context, [][]byte) ([][]byte, []error)
@@ -25,6 +29,10 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // Metadata
 // 0x0/ (tx)
 //   -> [txID] => timestamp
+// 0x1/ (balance history)
+//   -> [owner|asset|^height] => balance
+// 0x2/ (balance history height)
+//   -> height of the last indexed block
 //
 // State
 // 0x0/ (balance)
@@ -40,10 +48,24 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x6/ (hypersdk-fee)
 // 0x7/ (hypersdk-incoming warp)
 // 0x8/ (hypersdk-outgoing warp)
+// 0x9/ (hypersdk-burn)
+// 0xa/ (hypersdk-nonce)
+// 0xb/ (allowances)
+//   -> [owner|spender|asset] => allowance
+// 0xc/ (fee rates)
+//   -> [asset] => timestamp|native|units
+// 0xd/ (pools)
+//   -> [pool] => reserveA|reserveB|shares
+// 0xe/ (liquidity)
+//   -> [pool|owner] => shares
+// 0xf/ (streams)
+//   -> [txID] => payer|payee|asset|rate|start|deposit|withdrawn
 
 const (
 	// metaDB
-	txPrefix = 0x0
+	txPrefix                   = 0x0
+	balanceHistoryPrefix       = 0x1
+	balanceHistoryHeightPrefix = 0x2
 
 	// stateDB
 	balancePrefix      = 0x0
@@ -55,21 +77,33 @@ const (
 	feePrefix          = 0x6
 	incomingWarpPrefix = 0x7
 	outgoingWarpPrefix = 0x8
+	burnPrefix         = 0x9
+	noncePrefix        = 0xa
+	allowancePrefix    = 0xb
+	feeRatePrefix      = 0xc
+	poolPrefix         = 0xd
+	liquidityPrefix    = 0xe
+	streamPrefix       = 0xf
 )
 
 const (
-	BalanceChunks uint16 = 1
-	AssetChunks   uint16 = 5
-	OrderChunks   uint16 = 2
-	LoanChunks    uint16 = 1
+	BalanceChunks   uint16 = 1
+	AssetChunks     uint16 = 5
+	OrderChunks     uint16 = 2
+	LoanChunks      uint16 = 1
+	AllowanceChunks uint16 = 1
+	FeeRateChunks   uint16 = 1
+	PoolChunks      uint16 = 1
+	LiquidityChunks uint16 = 1
+	StreamChunks    uint16 = 3
 )
 
 var (
-	failureByte  = byte(0x0)
-	successByte  = byte(0x1)
-	heightKey    = []byte{heightPrefix}
-	timestampKey = []byte{timestampPrefix}
-	feeKey       = []byte{feePrefix}
+	heightKey       = []byte{heightPrefix}
+	timestampKey    = []byte{timestampPrefix}
+	feeKey          = []byte{feePrefix}
+	burnKey         = []byte{burnPrefix}
+	incomingWarpKey = []byte{incomingWarpPrefix}
 
 	balanceKeyPool = sync.Pool{
 		New: func() any {
@@ -91,20 +125,16 @@ func StoreTransaction(
 	db database.KeyValueWriter,
 	id ids.ID,
 	t int64,
-	success bool,
+	status chain.Status,
 	units chain.Dimensions,
 	fee uint64,
 ) error {
 	k := TxKey(id)
-	v := make([]byte, consts.Uint64Len+1+chain.DimensionsLen+consts.Uint64Len)
+	v := make([]byte, consts.Uint64Len+1+chain.DimensionsLen()+consts.Uint64Len)
 	binary.BigEndian.PutUint64(v, uint64(t))
-	if success {
-		v[consts.Uint64Len] = successByte
-	} else {
-		v[consts.Uint64Len] = failureByte
-	}
+	v[consts.Uint64Len] = byte(status)
 	copy(v[consts.Uint64Len+1:], units.Bytes())
-	binary.BigEndian.PutUint64(v[consts.Uint64Len+1+chain.DimensionsLen:], fee)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len+1+chain.DimensionsLen():], fee)
 	return db.Put(k, v)
 }
 
@@ -112,26 +142,23 @@ func GetTransaction(
 	_ context.Context,
 	db database.KeyValueReader,
 	id ids.ID,
-) (bool, int64, bool, chain.Dimensions, uint64, error) {
+) (bool, int64, chain.Status, chain.Dimensions, uint64, error) {
 	k := TxKey(id)
 	v, err := db.Get(k)
 	if errors.Is(err, database.ErrNotFound) {
-		return false, 0, false, chain.Dimensions{}, 0, nil
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, nil
 	}
 	if err != nil {
-		return false, 0, false, chain.Dimensions{}, 0, err
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, err
 	}
 	t := int64(binary.BigEndian.Uint64(v))
-	success := true
-	if v[consts.Uint64Len] == failureByte {
-		success = false
-	}
-	d, err := chain.UnpackDimensions(v[consts.Uint64Len+1 : consts.Uint64Len+1+chain.DimensionsLen])
+	status := chain.Status(v[consts.Uint64Len])
+	d, err := chain.UnpackDimensions(v[consts.Uint64Len+1 : consts.Uint64Len+1+chain.DimensionsLen()])
 	if err != nil {
-		return false, 0, false, chain.Dimensions{}, 0, err
+		return false, 0, chain.StatusFailed, chain.Dimensions{}, 0, err
 	}
-	fee := binary.BigEndian.Uint64(v[consts.Uint64Len+1+chain.DimensionsLen:])
-	return true, t, success, d, fee, nil
+	fee := binary.BigEndian.Uint64(v[consts.Uint64Len+1+chain.DimensionsLen():])
+	return true, t, status, d, fee, nil
 }
 
 // [accountPrefix] + [address] + [asset]
@@ -144,6 +171,17 @@ func BalanceKey(addr codec.Address, asset ids.ID) (k []byte) {
 	return
 }
 
+// ParseBalanceKey returns the address and asset of a key created with
+// [BalanceKey]. If [k] is not a balance key, false is returned.
+func ParseBalanceKey(k []byte) (codec.Address, ids.ID, bool) {
+	if len(k) != 1+codec.AddressLen+consts.IDLen+consts.Uint16Len || k[0] != balancePrefix {
+		return codec.EmptyAddress, ids.Empty, false
+	}
+	addr := codec.Address(k[1 : 1+codec.AddressLen])
+	asset := ids.ID(k[1+codec.AddressLen : 1+codec.AddressLen+consts.IDLen])
+	return addr, asset, true
+}
+
 // If locked is 0, then account does not exist
 func GetBalance(
 	ctx context.Context,
@@ -242,14 +280,14 @@ func AddBalance(
 	}
 	nbal, err := smath.Add64(bal, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusOverflow, fmt.Errorf(
 			"%w: could not add balance (asset=%s, bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
 			asset,
 			bal,
 			codec.MustAddressBech32(tconsts.HRP, addr),
 			amount,
-		)
+		))
 	}
 	return setBalance(ctx, mu, key, nbal)
 }
@@ -267,14 +305,14 @@ func SubBalance(
 	}
 	nbal, err := smath.Sub(bal, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusInsufficientBalance, fmt.Errorf(
 			"%w: could not subtract balance (asset=%s, bal=%d, addr=%v, amount=%d)",
 			ErrInvalidBalance,
 			asset,
 			bal,
 			codec.MustAddressBech32(tconsts.HRP, addr),
 			amount,
-		)
+		))
 	}
 	if nbal == 0 {
 		// If there is no balance left, we should delete the record instead of
@@ -537,13 +575,13 @@ func AddLoan(
 	}
 	nloan, err := smath.Add64(loan, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusOverflow, fmt.Errorf(
 			"%w: could not add loan (asset=%s, destination=%s, amount=%d)",
 			ErrInvalidBalance,
 			asset,
 			destination,
 			amount,
-		)
+		))
 	}
 	return SetLoan(ctx, mu, asset, destination, nloan)
 }
@@ -561,13 +599,13 @@ func SubLoan(
 	}
 	nloan, err := smath.Sub(loan, amount)
 	if err != nil {
-		return fmt.Errorf(
+		return chain.NewStatusError(chain.StatusOverflow, fmt.Errorf(
 			"%w: could not subtract loan (asset=%s, destination=%s, amount=%d)",
 			ErrInvalidBalance,
 			asset,
 			destination,
 			amount,
-		)
+		))
 	}
 	if nloan == 0 {
 		// If there is no balance left, we should delete the record instead of
@@ -577,6 +615,440 @@ func SubLoan(
 	return SetLoan(ctx, mu, asset, destination, nloan)
 }
 
+// [allowancePrefix] + [owner] + [spender] + [asset]
+func AllowanceKey(owner codec.Address, spender codec.Address, asset ids.ID) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen*2+consts.IDLen+consts.Uint16Len)
+	k[0] = allowancePrefix
+	copy(k[1:], owner[:])
+	copy(k[1+codec.AddressLen:], spender[:])
+	copy(k[1+codec.AddressLen*2:], asset[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen*2+consts.IDLen:], AllowanceChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetAllowanceFromState(
+	ctx context.Context,
+	f ReadState,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{AllowanceKey(owner, spender, asset)})
+	return innerGetAllowance(values[0], errs[0])
+}
+
+func innerGetAllowance(v []byte, err error) (uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// GetAllowance returns the amount of [asset] that [spender] can transfer on
+// behalf of [owner].
+func GetAllowance(
+	ctx context.Context,
+	im state.Immutable,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+) (uint64, error) {
+	v, err := im.GetValue(ctx, AllowanceKey(owner, spender, asset))
+	return innerGetAllowance(v, err)
+}
+
+// SetAllowance replaces the allowance of [spender] (removing it if [amount]
+// is 0).
+func SetAllowance(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+	amount uint64,
+) error {
+	k := AllowanceKey(owner, spender, asset)
+	if amount == 0 {
+		return mu.Remove(ctx, k)
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, amount))
+}
+
+// SubAllowance spends [amount] of the allowance of [spender]. An allowance
+// of [math.MaxUint64] is unlimited and never decreases.
+func SubAllowance(
+	ctx context.Context,
+	mu state.Mutable,
+	owner codec.Address,
+	spender codec.Address,
+	asset ids.ID,
+	amount uint64,
+) error {
+	allowance, err := GetAllowance(ctx, mu, owner, spender, asset)
+	if err != nil {
+		return err
+	}
+	if allowance == math.MaxUint64 {
+		return nil
+	}
+	nallowance, err := smath.Sub(allowance, amount)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: could not subtract allowance (asset=%s, allowance=%d, amount=%d)",
+			ErrInsufficientAllowance,
+			asset,
+			allowance,
+			amount,
+		)
+	}
+	return SetAllowance(ctx, mu, owner, spender, asset, nallowance)
+}
+
+// [feeRatePrefix] + [asset]
+func FeeRateKey(asset ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = feeRatePrefix
+	copy(k[1:], asset[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], FeeRateChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetFeeRateFromState(
+	ctx context.Context,
+	f ReadState,
+	asset ids.ID,
+) (bool, int64, uint64, uint64, error) {
+	values, errs := f(ctx, [][]byte{FeeRateKey(asset)})
+	return innerGetFeeRate(values[0], errs[0])
+}
+
+func innerGetFeeRate(v []byte, err error) (bool, int64, uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, 0, 0, 0, nil
+	}
+	if err != nil {
+		return false, 0, 0, 0, err
+	}
+	if len(v) != consts.Uint64Len*3 {
+		return false, 0, 0, 0, ErrInvalidFeeRate
+	}
+	timestamp := int64(binary.BigEndian.Uint64(v))
+	native := binary.BigEndian.Uint64(v[consts.Uint64Len:])
+	units := binary.BigEndian.Uint64(v[consts.Uint64Len*2:])
+	return true, timestamp, native, units, nil
+}
+
+// GetFeeRate returns the rate [asset] is converted at to pay fees: [units]
+// of [asset] are worth [native] of the native asset. [timestamp] is when the
+// rate was posted.
+func GetFeeRate(
+	ctx context.Context,
+	im state.Immutable,
+	asset ids.ID,
+) (bool, int64, uint64, uint64, error) {
+	return innerGetFeeRate(im.GetValue(ctx, FeeRateKey(asset)))
+}
+
+func SetFeeRate(
+	ctx context.Context,
+	mu state.Mutable,
+	asset ids.ID,
+	timestamp int64,
+	native uint64,
+	units uint64,
+) error {
+	v := make([]byte, consts.Uint64Len*3)
+	binary.BigEndian.PutUint64(v, uint64(timestamp))
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], native)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2:], units)
+	return mu.Insert(ctx, FeeRateKey(asset), v)
+}
+
+// ConvertFee returns the amount of an asset with a fee rate of
+// [native]/[units] that is worth [amount] of the native asset (rounded up,
+// so that converted fees are never worth less than [amount]).
+func ConvertFee(native uint64, units uint64, amount uint64) (uint64, error) {
+	if native == 0 {
+		return 0, ErrInvalidFeeRate
+	}
+	hi, lo := bits.Mul64(amount, units)
+	if hi >= native {
+		return 0, smath.ErrOverflow
+	}
+	quo, rem := bits.Div64(hi, lo, native)
+	if rem == 0 {
+		return quo, nil
+	}
+	return smath.Add64(quo, 1)
+}
+
+// PoolID returns the ID of the pool that trades [assetA] for [assetB] (the
+// same regardless of their order).
+func PoolID(assetA ids.ID, assetB ids.ID) ids.ID {
+	if bytes.Compare(assetA[:], assetB[:]) > 0 {
+		assetA, assetB = assetB, assetA
+	}
+	v := make([]byte, consts.IDLen*2)
+	copy(v, assetA[:])
+	copy(v[consts.IDLen:], assetB[:])
+	return utils.ToID(v)
+}
+
+// [poolPrefix] + [pool]
+func PoolKey(pool ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = poolPrefix
+	copy(k[1:], pool[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], PoolChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetPoolFromState(
+	ctx context.Context,
+	f ReadState,
+	assetA ids.ID,
+	assetB ids.ID,
+) (bool, uint64, uint64, uint64, error) {
+	values, errs := f(ctx, [][]byte{PoolKey(PoolID(assetA, assetB))})
+	return innerGetPool(assetA, assetB, values[0], errs[0])
+}
+
+func innerGetPool(assetA ids.ID, assetB ids.ID, v []byte, err error) (bool, uint64, uint64, uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, 0, 0, 0, nil
+	}
+	if err != nil {
+		return false, 0, 0, 0, err
+	}
+	reserveA := binary.BigEndian.Uint64(v)
+	reserveB := binary.BigEndian.Uint64(v[consts.Uint64Len:])
+	shares := binary.BigEndian.Uint64(v[consts.Uint64Len*2:])
+	if bytes.Compare(assetA[:], assetB[:]) > 0 {
+		reserveA, reserveB = reserveB, reserveA
+	}
+	return true, reserveA, reserveB, shares, nil
+}
+
+// GetPool returns the reserves of [assetA] and [assetB] in their pool (in
+// the order they are provided) and the total shares of its liquidity
+// providers.
+func GetPool(
+	ctx context.Context,
+	im state.Immutable,
+	assetA ids.ID,
+	assetB ids.ID,
+) (bool, uint64, uint64, uint64, error) {
+	v, err := im.GetValue(ctx, PoolKey(PoolID(assetA, assetB)))
+	return innerGetPool(assetA, assetB, v, err)
+}
+
+func SetPool(
+	ctx context.Context,
+	mu state.Mutable,
+	assetA ids.ID,
+	assetB ids.ID,
+	reserveA uint64,
+	reserveB uint64,
+	shares uint64,
+) error {
+	if bytes.Compare(assetA[:], assetB[:]) > 0 {
+		reserveA, reserveB = reserveB, reserveA
+	}
+	v := make([]byte, consts.Uint64Len*3)
+	binary.BigEndian.PutUint64(v, reserveA)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], reserveB)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2:], shares)
+	return mu.Insert(ctx, PoolKey(PoolID(assetA, assetB)), v)
+}
+
+func DeletePool(ctx context.Context, mu state.Mutable, assetA ids.ID, assetB ids.ID) error {
+	return mu.Remove(ctx, PoolKey(PoolID(assetA, assetB)))
+}
+
+// [liquidityPrefix] + [pool] + [owner]
+func LiquidityKey(pool ids.ID, owner codec.Address) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = liquidityPrefix
+	copy(k[1:], pool[:])
+	copy(k[1+consts.IDLen:], owner[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen+codec.AddressLen:], LiquidityChunks)
+	return
+}
+
+// Used to serve RPC queries
+func GetLiquidityFromState(
+	ctx context.Context,
+	f ReadState,
+	pool ids.ID,
+	owner codec.Address,
+) (uint64, error) {
+	values, errs := f(ctx, [][]byte{LiquidityKey(pool, owner)})
+	return innerGetLiquidity(values[0], errs[0])
+}
+
+func innerGetLiquidity(v []byte, err error) (uint64, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// GetLiquidity returns the shares of [pool] owned by [owner].
+func GetLiquidity(
+	ctx context.Context,
+	im state.Immutable,
+	pool ids.ID,
+	owner codec.Address,
+) (uint64, error) {
+	v, err := im.GetValue(ctx, LiquidityKey(pool, owner))
+	return innerGetLiquidity(v, err)
+}
+
+// SetLiquidity replaces the shares of [pool] owned by [owner] (removing
+// them if [shares] is 0).
+func SetLiquidity(
+	ctx context.Context,
+	mu state.Mutable,
+	pool ids.ID,
+	owner codec.Address,
+	shares uint64,
+) error {
+	k := LiquidityKey(pool, owner)
+	if shares == 0 {
+		return mu.Remove(ctx, k)
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, shares))
+}
+
+// [streamPrefix] + [txID]
+func StreamKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 1+consts.IDLen+consts.Uint16Len)
+	k[0] = streamPrefix
+	copy(k[1:], txID[:])
+	binary.BigEndian.PutUint16(k[1+consts.IDLen:], StreamChunks)
+	return
+}
+
+func SetStream(
+	ctx context.Context,
+	mu state.Mutable,
+	txID ids.ID,
+	payer codec.Address,
+	payee codec.Address,
+	asset ids.ID,
+	rate uint64,
+	start int64,
+	deposit uint64,
+	withdrawn uint64,
+) error {
+	k := StreamKey(txID)
+	v := make([]byte, codec.AddressLen*2+consts.IDLen+consts.Uint64Len*4)
+	copy(v, payer[:])
+	copy(v[codec.AddressLen:], payee[:])
+	copy(v[codec.AddressLen*2:], asset[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen*2+consts.IDLen:], rate)
+	binary.BigEndian.PutUint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len:], uint64(start))
+	binary.BigEndian.PutUint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len*2:], deposit)
+	binary.BigEndian.PutUint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len*3:], withdrawn)
+	return mu.Insert(ctx, k, v)
+}
+
+func GetStream(
+	ctx context.Context,
+	im state.Immutable,
+	stream ids.ID,
+) (
+	bool, // exists
+	codec.Address, // payer
+	codec.Address, // payee
+	ids.ID, // asset
+	uint64, // rate
+	int64, // start
+	uint64, // deposit
+	uint64, // withdrawn
+	error,
+) {
+	v, err := im.GetValue(ctx, StreamKey(stream))
+	return innerGetStream(v, err)
+}
+
+// Used to serve RPC queries
+func GetStreamFromState(
+	ctx context.Context,
+	f ReadState,
+	stream ids.ID,
+) (
+	bool, // exists
+	codec.Address, // payer
+	codec.Address, // payee
+	ids.ID, // asset
+	uint64, // rate
+	int64, // start
+	uint64, // deposit
+	uint64, // withdrawn
+	error,
+) {
+	values, errs := f(ctx, [][]byte{StreamKey(stream)})
+	return innerGetStream(values[0], errs[0])
+}
+
+func innerGetStream(v []byte, err error) (
+	bool, // exists
+	codec.Address, // payer
+	codec.Address, // payee
+	ids.ID, // asset
+	uint64, // rate
+	int64, // start
+	uint64, // deposit
+	uint64, // withdrawn
+	error,
+) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, codec.EmptyAddress, codec.EmptyAddress, ids.Empty, 0, 0, 0, 0, nil
+	}
+	if err != nil {
+		return false, codec.EmptyAddress, codec.EmptyAddress, ids.Empty, 0, 0, 0, 0, err
+	}
+	var payer codec.Address
+	copy(payer[:], v[:codec.AddressLen])
+	var payee codec.Address
+	copy(payee[:], v[codec.AddressLen:codec.AddressLen*2])
+	var asset ids.ID
+	copy(asset[:], v[codec.AddressLen*2:codec.AddressLen*2+consts.IDLen])
+	rate := binary.BigEndian.Uint64(v[codec.AddressLen*2+consts.IDLen:])
+	start := int64(binary.BigEndian.Uint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len:]))
+	deposit := binary.BigEndian.Uint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len*2:])
+	withdrawn := binary.BigEndian.Uint64(v[codec.AddressLen*2+consts.IDLen+consts.Uint64Len*3:])
+	return true, payer, payee, asset, rate, start, deposit, withdrawn, nil
+}
+
+func DeleteStream(ctx context.Context, mu state.Mutable, stream ids.ID) error {
+	return mu.Remove(ctx, StreamKey(stream))
+}
+
+// Streamed returns the amount of a stream paying [rate] per second since
+// [start] (until [deposit] runs out) that has been paid at [timestamp].
+func Streamed(rate uint64, start int64, deposit uint64, timestamp int64) uint64 {
+	if timestamp <= start {
+		return 0
+	}
+	seconds := uint64(timestamp-start) / consts.MillisecondsPerSecond
+	hi, streamed := bits.Mul64(seconds, rate)
+	if hi > 0 || streamed > deposit {
+		return deposit
+	}
+	return streamed
+}
+
 func HeightKey() (k []byte) {
 	return heightKey
 }
@@ -589,12 +1061,12 @@ func FeeKey() (k []byte) {
 	return feeKey
 }
 
-func IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) (k []byte) {
-	k = make([]byte, 1+consts.IDLen*2)
-	k[0] = incomingWarpPrefix
-	copy(k[1:], sourceChainID[:])
-	copy(k[1+consts.IDLen:], msgID[:])
-	return k
+func BurnKey() (k []byte) {
+	return burnKey
+}
+
+func IncomingWarpPrefix() (k []byte) {
+	return incomingWarpKey
 }
 
 func OutgoingWarpKeyPrefix(txID ids.ID) (k []byte) {
@@ -603,3 +1075,10 @@ func OutgoingWarpKeyPrefix(txID ids.ID) (k []byte) {
 	copy(k[1:], txID[:])
 	return k
 }
+
+func NonceKey(addr codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen)
+	k[0] = noncePrefix
+	copy(k[1:], addr[:])
+	return k
+}
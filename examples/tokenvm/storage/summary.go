@@ -0,0 +1,86 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// AssetSummary is the number of accounts holding an asset and the sum of
+// their balances. Balances locked in orders, loans, and pools are not
+// included, so [Total] may be less than the [Supply] recorded for the
+// asset.
+type AssetSummary struct {
+	Accounts uint64 `json:"accounts"`
+	Total    uint64 `json:"total"`
+	Supply   uint64 `json:"supply"`
+}
+
+// StateSummary summarizes the balances of each asset in state for audits
+// (see [vm.StateDigest]).
+type StateSummary struct {
+	assets map[ids.ID]*AssetSummary
+	orders uint64
+}
+
+func NewStateSummary() *StateSummary {
+	return &StateSummary{assets: map[ids.ID]*AssetSummary{}}
+}
+
+func (s *StateSummary) asset(id ids.ID) *AssetSummary {
+	a, ok := s.assets[id]
+	if !ok {
+		a = &AssetSummary{}
+		s.assets[id] = a
+	}
+	return a
+}
+
+func (s *StateSummary) Add(k []byte, v []byte) error {
+	switch {
+	case len(k) == 0:
+		return nil
+	case k[0] == balancePrefix:
+		_, asset, ok := ParseBalanceKey(k)
+		if !ok {
+			return nil
+		}
+		if len(v) != consts.Uint64Len {
+			return fmt.Errorf("%w: key=%x", ErrInvalidBalance, k)
+		}
+		a := s.asset(asset)
+		total, err := smath.Add64(a.Total, binary.BigEndian.Uint64(v))
+		if err != nil {
+			return err
+		}
+		a.Total = total
+		a.Accounts++
+	case k[0] == assetPrefix && len(k) == 1+consts.IDLen+consts.Uint16Len:
+		exists, _, _, _, supply, _, _, err := innerGetAsset(v, nil)
+		if err != nil || !exists {
+			return err
+		}
+		s.asset(ids.ID(k[1 : 1+consts.IDLen])).Supply = supply
+	case k[0] == orderPrefix:
+		s.orders++
+	}
+	return nil
+}
+
+func (s *StateSummary) Summaries() map[string]any {
+	assets := make(map[string]*AssetSummary, len(s.assets))
+	for id, a := range s.assets {
+		assets[id.String()] = a
+	}
+	return map[string]any{
+		"assets": assets,
+		"orders": s.orders,
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/chain"
+	hcli "github.com/ava-labs/hypersdk/cli"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/crypto/ed25519"
@@ -48,6 +49,7 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/controller"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
 	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 )
 
 var (
@@ -122,6 +124,11 @@ var (
 
 	networkID uint32
 	gen       *genesis.Genesis
+
+	// warpSigner is the only validator of [warpSourceChainID], so that
+	// messages it signs can be imported
+	warpSigner        warp.Signer
+	warpSourceChainID ids.ID
 )
 
 type instance struct {
@@ -178,6 +185,7 @@ var _ = ginkgo.BeforeSuite(func() {
 	gen = genesis.Default()
 	gen.MinUnitPrice = chain.Dimensions{1, 1, 1, 1, 1}
 	gen.MinBlockGap = 0
+	gen.WarpMaxSigners = 1
 	gen.CustomAllocation = []*genesis.CustomAllocation{
 		{
 			Address: sender,
@@ -191,6 +199,29 @@ var _ = ginkgo.BeforeSuite(func() {
 	subnetID := ids.GenerateTestID()
 	chainID := ids.GenerateTestID()
 
+	warpSK, err := bls.NewSecretKey()
+	gomega.Ω(err).Should(gomega.BeNil())
+	warpSourceChainID = ids.GenerateTestID()
+	warpSigner = warp.NewSigner(warpSK, networkID, warpSourceChainID)
+	warpSubnetID := ids.GenerateTestID()
+	warpNodeID := ids.GenerateTestNodeID()
+	validatorState := &validators.TestState{
+		GetSubnetIDF: func(_ context.Context, chainID ids.ID) (ids.ID, error) {
+			if chainID != warpSourceChainID {
+				return ids.Empty, fmt.Errorf("unknown chain %s", chainID)
+			}
+			return warpSubnetID, nil
+		},
+		GetValidatorSetF: func(_ context.Context, _ uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			if subnetID != warpSubnetID {
+				return nil, fmt.Errorf("unknown subnet %s", subnetID)
+			}
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				warpNodeID: {NodeID: warpNodeID, PublicKey: bls.PublicFromSecretKey(warpSK), Weight: 1},
+			}, nil
+		},
+	}
+
 	app := &appSender{}
 	for i := range instances {
 		nodeID := ids.GenerateTestNodeID()
@@ -210,7 +241,7 @@ var _ = ginkgo.BeforeSuite(func() {
 			Metrics:        metrics.NewOptionalGatherer(),
 			PublicKey:      bls.PublicFromSecretKey(sk),
 			WarpSigner:     warp.NewSigner(sk, networkID, chainID),
-			ValidatorState: &validators.TestState{},
+			ValidatorState: validatorState,
 		}
 
 		toEngine := make(chan common.Message, 1)
@@ -224,7 +255,7 @@ var _ = ginkgo.BeforeSuite(func() {
 			genesisBytes,
 			nil,
 			[]byte(
-				`{"parallelism":3, "testMode":true, "logLevel":"debug", "trackedPairs":["*"]}`,
+				`{"parallelism":3, "testMode":true, "logLevel":"debug", "trackedPairs":["*"], "storeBalances":true}`,
 			),
 			toEngine,
 			nil,
@@ -426,7 +457,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 
 			results := blk.(*chain.StatelessBlock).Results()
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 			gomega.Ω(results[0].Output).Should(gomega.BeNil())
 
 			// Unit explanation
@@ -453,6 +484,43 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(balance2).To(gomega.Equal(uint64(100000)))
 		})
+
+		ginkgo.By("query balance with the generic state RPC", func() {
+			prefixes, err := instances[1].cli.StatePrefixes(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(prefixes).To(gomega.Equal([]string{"asset", "balance", "order"}))
+
+			var balance uint64
+			exists, err := instances[1].cli.QueryState(context.Background(), "balance", sender2, &balance)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(exists).To(gomega.BeTrue())
+			gomega.Ω(balance).To(gomega.Equal(uint64(100000)))
+
+			exists, err = instances[1].cli.QueryState(context.Background(), "balance", sender2+":"+ids.GenerateTestID().String(), &balance)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(exists).To(gomega.BeFalse())
+
+			_, err = instances[1].cli.QueryState(context.Background(), "loan", sender2, &balance)
+			gomega.Ω(err).To(gomega.MatchError(gomega.ContainSubstring(rpc.ErrUnknownStateQuery.Error())))
+		})
+
+		ginkgo.By("fetch accepted block", func() {
+			blk := blocks[len(blocks)-1]
+			latest, err := instances[1].cli.GetLatestBlock(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(latest.BlockID).To(gomega.Equal(blk.ID()))
+			gomega.Ω(latest.Txs).Should(gomega.HaveLen(1))
+			gomega.Ω(latest.Results).Should(gomega.HaveLen(1))
+			gomega.Ω(latest.Results[0].Fee).Should(gomega.Equal(uint64(297)))
+
+			byHeight, err := instances[1].cli.GetBlockByHeight(context.Background(), blk.Height())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(byHeight).To(gomega.Equal(latest))
+
+			byID, err := instances[1].cli.GetBlock(context.Background(), blk.ID())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(byID).To(gomega.Equal(latest))
+		})
 	})
 
 	ginkgo.It("ensure multiple txs work ", func() {
@@ -475,7 +543,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			accept := expectBlk(instances[1])
 			results := accept(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 			balance2, err := instances[1].tcli.Balance(context.Background(), sender2, ids.Empty)
 			gomega.Ω(err).To(gomega.BeNil())
@@ -523,10 +591,10 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		ginkgo.By("clear processing tip", func() {
 			results := accept(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 			results = accept2(true)
 			gomega.Ω(results).Should(gomega.HaveLen(1))
-			gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+			gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		})
 	})
 
@@ -583,7 +651,8 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			tx := blk2.(*chain.StatelessBlock).Txs[0]
 			sblk3 := blk3.(*chain.StatelessBlock)
 			sblk3t := sblk3.Timestamp().UnixMilli()
-			ok, err := sblk3.IsRepeat(ctx, sblk3t-n.vm.Rules(sblk3t).GetValidityWindow(), []*chain.Transaction{tx}, set.NewBits(), false)
+			r := n.vm.Rules(sblk3t)
+			ok, err := sblk3.IsRepeat(ctx, sblk3t-r.GetValidityWindow(), chain.OldestAllowedHeight(r, sblk3.Hght), []*chain.Transaction{tx}, set.NewBits(), false)
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(ok.Len()).Should(gomega.Equal(1))
 
@@ -650,7 +719,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept = expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		// Read item from connection
 		blk, lresults, prices, err := cli.ListenBlock(context.TODO(), parser)
@@ -710,14 +779,14 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		// Read decision from connection
 		txID, dErr, result, err := cli.ListenTx(context.TODO())
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(txID).Should(gomega.Equal(tx.ID()))
 		gomega.Ω(dErr).Should(gomega.BeNil())
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
 		gomega.Ω(result).Should(gomega.Equal(results[0]))
 
 		// Close connection when done
@@ -746,7 +815,68 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
+	})
+
+	ginkgo.It("transfer an asset with an offline-signed tx", func() {
+		other, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		parser, err := instances[0].tcli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		unitPrices, err := instances[0].cli.UnitPrices(context.Background(), false)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// Build the unsigned tx on a "networked" host
+		action := &actions.Transfer{
+			To:    auth.NewED25519Address(other.PublicKey()),
+			Value: 10,
+		}
+		now := time.Now().UnixMilli()
+		rules := parser.Rules(now)
+		maxUnits, err := chain.EstimateMaxUnits(rules, action, factory, nil)
+		gomega.Ω(err).Should(gomega.BeNil())
+		maxFee, err := chain.MulSum(unitPrices, maxUnits)
+		gomega.Ω(err).Should(gomega.BeNil())
+		base := &chain.Base{
+			Timestamp: hutils.UnixRMilli(now, rules.GetValidityWindow()),
+			ChainID:   rules.ChainID(),
+			MaxFee:    maxFee,
+		}
+		utx, err := hcli.NewUnsignedTx(chain.NewTx(base, nil, action), sender, unitPrices, maxUnits)
+		gomega.Ω(err).Should(gomega.BeNil())
+		utxBytes, err := json.MarshalIndent(utx, "", "  ")
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// Informational fields can't be changed without changing the digest
+		var tampered hcli.UnsignedTx
+		gomega.Ω(json.Unmarshal(utxBytes, &tampered)).Should(gomega.BeNil())
+		tampered.MaxFee++
+		_, err = tampered.Sign(factory, tconsts.ActionRegistry, tconsts.AuthRegistry)
+		gomega.Ω(err).Should(gomega.MatchError(hcli.ErrDigestMismatch))
+
+		// Sign on an "offline" host
+		var offline hcli.UnsignedTx
+		gomega.Ω(json.Unmarshal(utxBytes, &offline)).Should(gomega.BeNil())
+		stx, err := offline.Sign(factory, tconsts.ActionRegistry, tconsts.AuthRegistry)
+		gomega.Ω(err).Should(gomega.BeNil())
+		stxBytes, err := json.Marshal(stx)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// Broadcast from the "networked" host
+		var signed hcli.SignedTx
+		gomega.Ω(json.Unmarshal(stxBytes, &signed)).Should(gomega.BeNil())
+		tx, err := signed.Tx(parser.Registry())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(tx.ID()).Should(gomega.Equal(stx.TxID))
+		gomega.Ω(tx.Auth.Actor()).Should(gomega.Equal(rsender))
+		txID, err := instances[0].cli.SubmitTx(context.Background(), tx.Bytes())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(txID).Should(gomega.Equal(tx.ID()))
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		gomega.Ω(results).Should(gomega.HaveLen(1))
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Fee).Should(gomega.BeNumerically("<=", maxFee))
 	})
 
 	ginkgo.It("transfer an asset with large memo", func() {
@@ -805,7 +935,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("asset missing"))
 
@@ -926,7 +1056,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		asset1ID = tx.ID()
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset1ID)
@@ -942,6 +1072,17 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		gomega.Ω(supply).Should(gomega.Equal(uint64(0)))
 		gomega.Ω(owner).Should(gomega.Equal(sender))
 		gomega.Ω(warp).Should(gomega.BeFalse())
+
+		var asset storage.Asset
+		exists, err = instances[0].cli.QueryState(context.TODO(), "asset", asset1ID.String(), &asset)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(asset).Should(gomega.Equal(storage.Asset{
+			Symbol:   asset1Symbol,
+			Decimals: asset1Decimals,
+			Metadata: asset1,
+			Owner:    sender,
+		}))
 	})
 
 	ginkgo.It("mint a new asset", func() {
@@ -963,7 +1104,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, asset1ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1005,7 +1146,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("wrong owner"))
 
@@ -1038,7 +1179,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, asset1ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1077,7 +1218,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("invalid balance"))
 
@@ -1144,7 +1285,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("overflow"))
 
@@ -1217,7 +1358,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		asset2ID = tx.ID()
 
 		submit, _, _, err = instances[0].cli.GenerateTransaction(
@@ -1236,7 +1377,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept = expectBlk(instances[0])
 		results = accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset2ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1262,7 +1403,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 		asset3ID = tx.ID()
 
 		submit, _, _, err = instances[0].cli.GenerateTransaction(
@@ -1281,7 +1422,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept = expectBlk(instances[0])
 		results = accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, asset3ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1309,7 +1450,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset2ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1348,7 +1489,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("supply is misaligned"))
 	})
@@ -1374,7 +1515,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, asset3ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1413,7 +1554,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("invalid balance"))
 	})
@@ -1446,7 +1587,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("value is misaligned"))
 	})
@@ -1479,7 +1620,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("invalid balance"))
 	})
@@ -1512,7 +1653,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
 		or, err := actions.UnmarshalOrderResult(result.Output)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(or.In).Should(gomega.Equal(uint64(4)))
@@ -1556,7 +1697,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).
 			Should(gomega.ContainSubstring("unauthorized"))
 	})
@@ -1584,7 +1725,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset3ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1625,7 +1766,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		accept := expectBlk(instances[0])
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
-		gomega.Ω(results[0].Success).Should(gomega.BeTrue())
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
 
 		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset3ID)
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1670,7 +1811,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
 		or, err := actions.UnmarshalOrderResult(result.Output)
 		gomega.Ω(err).Should(gomega.BeNil())
 		gomega.Ω(or.In).Should(gomega.Equal(uint64(2)))
@@ -1867,10 +2008,83 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("warp verification failed"))
 	})
 
+	ginkgo.It("import replayed warp message", func() {
+		wt := &actions.WarpTransfer{
+			To:                 rsender,
+			Symbol:             []byte("s"),
+			Decimals:           2,
+			Asset:              ids.GenerateTestID(),
+			Value:              100,
+			TxID:               ids.GenerateTestID(),
+			DestinationChainID: instances[0].chainID,
+		}
+		wm := signWarpMessage(wt)
+		parser, err := instances[0].tcli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		submit, _, maxFee, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			wm,
+			&actions.ImportAsset{},
+			factory,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+		accept := expectBlkWithContext(instances[0])
+		results := accept(false)
+		gomega.Ω(results).Should(gomega.HaveLen(1))
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
+
+		// The same message can't be imported by another transaction
+		submit, _, err = instances[0].cli.GenerateTransactionManual(
+			parser,
+			wm,
+			&actions.ImportAsset{},
+			factory,
+			maxFee+1,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		err = submit(context.Background())
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		gomega.Ω(err.Error()).Should(gomega.ContainSubstring(chain.ErrWarpReplay.Error()))
+	})
+
+	ginkgo.It("import warp message with too many signers", func() {
+		wt := &actions.WarpTransfer{
+			To:                 rsender,
+			Symbol:             []byte("s"),
+			Decimals:           2,
+			Asset:              ids.GenerateTestID(),
+			Value:              100,
+			TxID:               ids.GenerateTestID(),
+			DestinationChainID: instances[0].chainID,
+		}
+		signed := signWarpMessage(wt)
+		bitSetSig := &warp.BitSetSignature{Signers: set.NewBits(0, 1).Bytes()}
+		bitSetSig.Signature = signed.Signature.(*warp.BitSetSignature).Signature
+		wm, err := warp.NewMessage(&signed.UnsignedMessage, bitSetSig)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// The message is rejected before its signature is verified
+		parser, err := instances[0].tcli.Parser(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			wm,
+			&actions.ImportAsset{},
+			factory,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		err = submit(context.Background())
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		gomega.Ω(err.Error()).Should(gomega.ContainSubstring(chain.ErrTooManyWarpSigners.Error()))
+	})
+
 	ginkgo.It("export native asset", func() {
 		dest := ids.GenerateTestID()
 		loan, err := instances[0].tcli.Loan(context.TODO(), ids.Empty, dest)
@@ -1899,7 +2113,10 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeTrue())
+		gomega.Ω(result.Success()).Should(gomega.BeTrue())
+		exported, err := actions.UnmarshalWarpTransfer(result.WarpMessage.Payload)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exported.Timestamp).Should(gomega.BeNumerically(">", 0))
 		wt := &actions.WarpTransfer{
 			To:                 rsender,
 			Symbol:             []byte(tconsts.Symbol),
@@ -1910,6 +2127,7 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			Reward:             10,
 			TxID:               tx.ID(),
 			DestinationChainID: dest,
+			Timestamp:          exported.Timestamp,
 		}
 		wtb, err := wt.Marshal()
 		gomega.Ω(err).Should(gomega.BeNil())
@@ -1945,9 +2163,84 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		results := accept(false)
 		gomega.Ω(results).Should(gomega.HaveLen(1))
 		result := results[0]
-		gomega.Ω(result.Success).Should(gomega.BeFalse())
+		gomega.Ω(result.Success()).Should(gomega.BeFalse())
 		gomega.Ω(string(result.Output)).Should(gomega.ContainSubstring("not warp asset"))
 	})
+
+	ginkgo.It("serves balances at past heights and total supply", func() {
+		ctx := context.Background()
+		tcli := instances[0].tcli
+		g, err := tcli.Genesis(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		for _, alloc := range g.CustomAllocation {
+			balance, err := tcli.BalanceAt(ctx, alloc.Address, ids.Empty, 0)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(balance).Should(gomega.Equal(alloc.Balance))
+		}
+
+		_, height, _, err := instances[0].cli.Accepted(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		before, err := tcli.Balance(ctx, sender2, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		balance, err := tcli.BalanceAt(ctx, sender2, ids.Empty, height)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(before))
+
+		parser, err := tcli.Parser(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		submit, _, _, err := trpc.BuildTransferTx(ctx, instances[0].cli, parser, factory, rsender2, ids.Empty, 1_000, nil)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(submit(ctx)).Should(gomega.BeNil())
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		gomega.Ω(results).Should(gomega.HaveLen(1))
+		gomega.Ω(results[0].Success()).Should(gomega.BeTrue())
+
+		balance, err = tcli.BalanceAt(ctx, sender2, ids.Empty, height)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(before))
+		balance, err = tcli.BalanceAt(ctx, sender2, ids.Empty, height+1)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(balance).Should(gomega.Equal(before + 1_000))
+		_, err = tcli.BalanceAt(ctx, sender2, ids.Empty, height+2)
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		gomega.Ω(err.Error()).Should(gomega.ContainSubstring("height not indexed"))
+
+		supply, err := tcli.TotalSupply(ctx, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(supply.Height).Should(gomega.Equal(height + 1))
+		_, _, _, _, minted, _, _, err := tcli.Asset(ctx, ids.Empty, false)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(supply.Minted).Should(gomega.Equal(minted))
+		burned, err := instances[0].cli.Burned(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		var totalBurned uint64
+		for _, amount := range burned {
+			totalBurned += amount
+		}
+		gomega.Ω(totalBurned).ShouldNot(gomega.BeZero())
+		gomega.Ω(supply.Burned).Should(gomega.Equal(totalBurned))
+		gomega.Ω(supply.Total).Should(gomega.Equal(minted - totalBurned))
+	})
+
+	ginkgo.It("digests state with asset summaries", func() {
+		ctx := context.Background()
+		digest, err := instances[0].vm.StateDigest(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		other, err := instances[0].vm.StateDigest(ctx)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(other).Should(gomega.Equal(digest))
+		gomega.Ω(digest.Height).Should(gomega.Equal(instances[0].vm.LastAcceptedBlock().Hght))
+
+		assets, ok := digest.Summaries["assets"].(map[string]*storage.AssetSummary)
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		native, ok := assets[ids.Empty.String()]
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		balance, err := instances[0].tcli.Balance(ctx, sender2, ids.Empty)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(native.Accounts).Should(gomega.BeNumerically(">", 1))
+		gomega.Ω(native.Total).Should(gomega.BeNumerically(">=", balance))
+	})
 })
 
 func expectBlk(i instance) func(bool) []*chain.Result {
@@ -1983,6 +2276,22 @@ func expectBlk(i instance) func(bool) []*chain.Result {
 	}
 }
 
+// signWarpMessage returns [wt] sent from [warpSourceChainID] and signed by
+// all of its validators.
+func signWarpMessage(wt *actions.WarpTransfer) *warp.Message {
+	wtb, err := wt.Marshal()
+	gomega.Ω(err).Should(gomega.BeNil())
+	uwm, err := warp.NewUnsignedMessage(networkID, warpSourceChainID, wtb)
+	gomega.Ω(err).Should(gomega.BeNil())
+	sig, err := warpSigner.Sign(uwm)
+	gomega.Ω(err).Should(gomega.BeNil())
+	bitSetSig := &warp.BitSetSignature{Signers: set.NewBits(0).Bytes()}
+	copy(bitSetSig.Signature[:], sig)
+	wm, err := warp.NewMessage(uwm, bitSetSig)
+	gomega.Ω(err).Should(gomega.BeNil())
+	return wm
+}
+
 // TODO: unify with expectBlk
 func expectBlkWithContext(i instance) func(bool) []*chain.Result {
 	ctx := context.TODO()
@@ -400,11 +400,11 @@ var _ = ginkgo.Describe("load tests vm", func() {
 				accept()
 				log.Debug("block produced", zap.Uint64("height", blk.Hght), zap.Int("txs", len(blk.Txs)))
 				for _, result := range blk.Results() {
-					if !result.Success {
+					if !result.Success() {
 						unitPrices, _ := instances[0].cli.UnitPrices(context.Background(), false)
 						fmt.Println("tx failed", "unit prices:", unitPrices, "consumed:", result.Consumed, "fee:", result.Fee, "output:", string(result.Output))
 					}
-					gomega.Ω(result.Success).Should(gomega.BeTrue())
+					gomega.Ω(result.Success()).Should(gomega.BeTrue())
 				}
 				for _, tx := range blk.Txs {
 					delete(requiredTxs, tx.ID())
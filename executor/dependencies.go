@@ -6,4 +6,7 @@ package executor
 type Metrics interface {
 	RecordBlocked()
 	RecordExecutable()
+	RecordConflicts(int)                // keys shared with a task that had not yet executed
+	RecordChainLength(float64)          // average dependency chain length of all tasks
+	RecordEffectiveParallelism(float64) // tasks divided by the longest dependency chain
 }
@@ -31,6 +31,10 @@ type Executor struct {
 	completed int
 	tasks     map[int]*task
 	edges     map[string]int
+
+	// dependency chain tracking (requires l)
+	chainSum int
+	chainMax int
 }
 
 // New creates a new [Executor].
@@ -55,6 +59,12 @@ type task struct {
 	dependencies set.Set[int]
 	blocking     set.Set[int]
 
+	// chain is the number of tasks (including this one) in the longest
+	// sequence of conflicting tasks that ends with this task. It is computed
+	// from the conflicts of each task, regardless of when tasks finish, so
+	// it reflects the key layout rather than timing.
+	chain int
+
 	executed bool
 }
 
@@ -112,17 +122,23 @@ func (e *Executor) Run(conflicts set.Set[string], f func() error) {
 	// Add task to map
 	id := len(e.tasks)
 	t := &task{
-		id: id,
-		f:  f,
+		id:    id,
+		f:     f,
+		chain: 1,
 	}
 	e.tasks[id] = t
 
 	// Record dependencies
+	blockingKeys := 0
 	for k := range conflicts {
 		latest, ok := e.edges[k]
 		if ok {
 			lt := e.tasks[latest]
+			if lt.chain+1 > t.chain {
+				t.chain = lt.chain + 1
+			}
 			if !lt.executed {
+				blockingKeys++
 				if t.dependencies == nil {
 					t.dependencies = set.NewSet[int](defaultSetSize)
 				}
@@ -135,6 +151,13 @@ func (e *Executor) Run(conflicts set.Set[string], f func() error) {
 		}
 		e.edges[k] = id
 	}
+	e.chainSum += t.chain
+	if t.chain > e.chainMax {
+		e.chainMax = t.chain
+	}
+	if blockingKeys > 0 && e.metrics != nil {
+		e.metrics.RecordConflicts(blockingKeys)
+	}
 
 	// Start execution if there are no blocking dependencies
 	if t.dependencies == nil || t.dependencies.Len() == 0 {
@@ -170,5 +193,9 @@ func (e *Executor) Wait() error {
 	}
 	e.l.Unlock()
 	e.wg.Wait()
+	if e.err == nil && e.metrics != nil && len(e.tasks) > 0 {
+		e.metrics.RecordChainLength(float64(e.chainSum) / float64(len(e.tasks)))
+		e.metrics.RecordEffectiveParallelism(float64(len(e.tasks)) / float64(e.chainMax))
+	}
 	return e.err
 }
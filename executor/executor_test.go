@@ -196,3 +196,50 @@ func TestStop(t *testing.T) {
 	require.True(len(completed) < 500)
 	require.ErrorIs(e.Wait(), ErrStopped) // no task running
 }
+
+type testMetrics struct {
+	conflicts   int
+	chainLength float64
+	parallelism float64
+}
+
+func (*testMetrics) RecordBlocked()    {}
+func (*testMetrics) RecordExecutable() {}
+
+func (m *testMetrics) RecordConflicts(n int) {
+	m.conflicts += n
+}
+
+func (m *testMetrics) RecordChainLength(l float64) {
+	m.chainLength = l
+}
+
+func (m *testMetrics) RecordEffectiveParallelism(p float64) {
+	m.parallelism = p
+}
+
+func TestExecutorChainMetrics(t *testing.T) {
+	var (
+		require = require.New(t)
+		m       = &testMetrics{}
+		e       = New(4, 4, m)
+		release = make(chan struct{})
+	)
+	// Tasks 0 -> 1 -> 2 share "a" and task 3 is independent, so
+	// chains are [1, 2, 3, 1].
+	for i := 0; i < 3; i++ {
+		ti := i
+		e.Run(set.Of("a"), func() error {
+			if ti == 0 {
+				<-release
+			}
+			return nil
+		})
+	}
+	e.Run(set.Of("b"), func() error { return nil })
+	close(release)
+	require.NoError(e.Wait())
+	require.Equal(2, m.conflicts) // tasks 1 and 2 are blocked on "a"
+	require.Equal(7.0/4, m.chainLength)
+	require.Equal(4.0/3, m.parallelism)
+}
@@ -0,0 +1,12 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fork
+
+import "errors"
+
+var (
+	ErrUnknownFeature    = errors.New("unknown feature")
+	ErrAlreadyScheduled  = errors.New("feature already scheduled")
+	ErrInvalidActivation = errors.New("invalid activation timestamp")
+)
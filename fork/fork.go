@@ -0,0 +1,61 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fork coordinates consensus-relevant changes to a live network. Each
+// change is guarded by a named feature flag that every validator activates at
+// the same timestamp (provided in upgradeBytes), so that blocks built before
+// the activation are still verified with the old behavior.
+package fork
+
+import "fmt"
+
+// Schedule maps the feature flags known by a VM to the timestamp (in
+// milliseconds) they activate at. Features that are not scheduled are never
+// active.
+type Schedule struct {
+	known       map[string]struct{}
+	activations map[string]int64
+}
+
+// NewSchedule returns an empty [Schedule] that can activate [features].
+// Activating any other feature fails, so that a typo in an upgrade doesn't
+// silently leave some behavior unchanged.
+func NewSchedule(features ...string) *Schedule {
+	known := make(map[string]struct{}, len(features))
+	for _, feature := range features {
+		known[feature] = struct{}{}
+	}
+	return &Schedule{
+		known:       known,
+		activations: map[string]int64{},
+	}
+}
+
+// Activate schedules [feature] to be active for all blocks with a timestamp
+// at or after [timestamp]. A feature can only be scheduled once.
+func (s *Schedule) Activate(feature string, timestamp int64) error {
+	if _, ok := s.known[feature]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownFeature, feature)
+	}
+	if timestamp < 0 {
+		return fmt.Errorf("%w: %s at %d", ErrInvalidActivation, feature, timestamp)
+	}
+	if prev, ok := s.activations[feature]; ok {
+		return fmt.Errorf("%w: %s at %d", ErrAlreadyScheduled, feature, prev)
+	}
+	s.activations[feature] = timestamp
+	return nil
+}
+
+// Activation returns the timestamp [feature] activates at, if it is
+// scheduled.
+func (s *Schedule) Activation(feature string) (int64, bool) {
+	timestamp, ok := s.activations[feature]
+	return timestamp, ok
+}
+
+// IsActive returns true if [feature] is active for a block with [timestamp].
+func (s *Schedule) IsActive(feature string, timestamp int64) bool {
+	activation, ok := s.activations[feature]
+	return ok && timestamp >= activation
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fork
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	require := require.New(t)
+
+	s := NewSchedule("a", "b")
+	require.NoError(s.Activate("a", 1_000))
+	require.ErrorIs(s.Activate("a", 2_000), ErrAlreadyScheduled)
+	require.ErrorIs(s.Activate("c", 1_000), ErrUnknownFeature)
+	require.ErrorIs(s.Activate("b", -1), ErrInvalidActivation)
+
+	activation, ok := s.Activation("a")
+	require.True(ok)
+	require.Equal(int64(1_000), activation)
+	_, ok = s.Activation("b")
+	require.False(ok)
+
+	require.False(s.IsActive("a", 999))
+	require.True(s.IsActive("a", 1_000))
+	require.True(s.IsActive("a", 5_000))
+	require.False(s.IsActive("b", 5_000))
+	require.False(s.IsActive("c", 5_000))
+}
@@ -23,7 +23,7 @@ type VM interface {
 	GetTargetGossipDuration() time.Duration
 	Proposers(ctx context.Context, diff int, depth int) (set.Set[ids.NodeID], error)
 	IsValidator(context.Context, ids.NodeID) (bool, error)
-	Logger() logging.Logger
+	ModuleLogger(module string) logging.Logger
 	PreferredBlock(context.Context) (*chain.StatelessBlock, error)
 	Registry() (chain.ActionRegistry, chain.AuthRegistry)
 	NodeID() ids.NodeID
@@ -35,4 +35,5 @@ type VM interface {
 	RecordTxsGossiped(int)
 	RecordSeenTxsReceived(int)
 	RecordTxsReceived(int)
+	RecordTxsFiltered(int)
 }
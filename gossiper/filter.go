@@ -0,0 +1,109 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// FilterGossiper is implemented by [Gossiper]s that periodically send
+// peers a bloom filter of the transactions in their mempool, so that peers
+// don't gossip those transactions back to them.
+type FilterGossiper interface {
+	// SetFilterSender must be called before [Gossiper.Run] for filters to be
+	// sent.
+	SetFilterSender(common.AppSender)
+	HandleFilter(ctx context.Context, nodeID ids.NodeID, msg []byte) error
+}
+
+// txHash is the value added to filters for [txID]. Transaction IDs are
+// already uniformly distributed and each filter uses new random seeds, so
+// there is no need to hash them again.
+func txHash(txID ids.ID) uint64 {
+	return binary.BigEndian.Uint64(txID[:])
+}
+
+// newMempoolFilter returns a filter containing [txIDs] with a false
+// positive probability of about [falsePositive].
+func newMempoolFilter(txIDs []ids.ID, falsePositive float64) (*bloom.Filter, error) {
+	numHashes, numEntries := bloom.OptimalParameters(math.Max(len(txIDs), 1), falsePositive)
+	filter, err := bloom.New(numHashes, numEntries)
+	if err != nil {
+		return nil, err
+	}
+	for _, txID := range txIDs {
+		filter.Add(txHash(txID))
+	}
+	return filter, nil
+}
+
+type peerFilter struct {
+	filter   *bloom.ReadFilter
+	received int64 // ms
+}
+
+// peerFilters holds the latest filter received from each peer until it is
+// older than [maxAge].
+type peerFilters struct {
+	maxAge int64 // ms
+
+	l       sync.RWMutex
+	filters map[ids.NodeID]*peerFilter
+}
+
+func newPeerFilters(maxAge int64) *peerFilters {
+	return &peerFilters{
+		maxAge:  maxAge,
+		filters: map[ids.NodeID]*peerFilter{},
+	}
+}
+
+func (p *peerFilters) set(nodeID ids.NodeID, filter *bloom.ReadFilter, now int64) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	for peer, f := range p.filters {
+		if now-f.received > p.maxAge {
+			delete(p.filters, peer)
+		}
+	}
+	p.filters[nodeID] = &peerFilter{filter, now}
+}
+
+// get returns the latest filter received from [nodeID] or nil if it hasn't
+// sent one recently.
+func (p *peerFilters) get(nodeID ids.NodeID, now int64) *bloom.ReadFilter {
+	p.l.RLock()
+	defer p.l.RUnlock()
+
+	f, ok := p.filters[nodeID]
+	if !ok || now-f.received > p.maxAge {
+		return nil
+	}
+	return f.filter
+}
+
+// unknownTxs returns the transactions in [txs] that aren't in [filter] (or
+// all of [txs] if [filter] is nil).
+func unknownTxs(filter *bloom.ReadFilter, txs []*chain.Transaction) []*chain.Transaction {
+	if filter == nil {
+		return txs
+	}
+	unknown := make([]*chain.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !filter.Contains(txHash(tx.ID())) {
+			unknown = append(unknown, tx)
+		}
+	}
+	return unknown
+}
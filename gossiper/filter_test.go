@@ -0,0 +1,39 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bloom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerFilters(t *testing.T) {
+	require := require.New(t)
+
+	known := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	filter, err := newMempoolFilter(known, 0.000_001)
+	require.NoError(err)
+	readFilter, err := bloom.Parse(filter.Marshal())
+	require.NoError(err)
+	for _, txID := range known {
+		require.True(readFilter.Contains(txHash(txID)))
+	}
+	require.False(readFilter.Contains(txHash(ids.GenerateTestID())))
+
+	var (
+		filters = newPeerFilters(1_000)
+		peer    = ids.GenerateTestNodeID()
+	)
+	require.Nil(filters.get(peer, 0))
+	filters.set(peer, readFilter, 0)
+	require.Equal(readFilter, filters.get(peer, 1_000))
+
+	// Old filters are ignored and then dropped
+	require.Nil(filters.get(peer, 1_001))
+	filters.set(ids.GenerateTestNodeID(), readFilter, 1_001)
+	require.NotContains(filters.filters, peer)
+}
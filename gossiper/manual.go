@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/logs"
 	"go.uber.org/zap"
 )
 
@@ -48,7 +49,7 @@ func (g *Manual) Force(ctx context.Context) error {
 		g.vm.GetTargetGossipDuration(),
 		func(ictx context.Context, next *chain.Transaction) (cont bool, rest bool, err error) {
 			// Remove txs that are expired
-			if next.Base.Timestamp < now {
+			if next.Expiry() < now {
 				return true, false, nil
 			}
 
@@ -73,13 +74,13 @@ func (g *Manual) Force(ctx context.Context) error {
 		return err
 	}
 	if err := g.appSender.SendAppGossip(ctx, b); err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"GossipTxs failed",
 			zap.Error(err),
 		)
 		return err
 	}
-	g.vm.Logger().Debug("gossiped txs", zap.Int("count", len(txs)))
+	g.vm.ModuleLogger(logs.Gossiper).Debug("gossiped txs", zap.Int("count", len(txs)))
 	return nil
 }
 
@@ -87,7 +88,7 @@ func (g *Manual) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []b
 	actionRegistry, authRegistry := g.vm.Registry()
 	_, txs, err := chain.UnmarshalTxs(msg, initialCapacity, actionRegistry, authRegistry)
 	if err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"AppGossip provided invalid txs",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
@@ -101,13 +102,13 @@ func (g *Manual) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []b
 		if err == nil {
 			continue
 		}
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"AppGossip failed to submit txs",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
 	}
-	g.vm.Logger().Info(
+	g.vm.ModuleLogger(logs.Gossiper).Info(
 		"tx gossip received",
 		zap.Int("txs", len(txs)),
 		zap.Stringer("nodeID", nodeID),
@@ -12,17 +12,22 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/bloom"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/vms/proposervm/proposer"
 	"github.com/ava-labs/hypersdk/cache"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/workers"
 	"go.uber.org/zap"
 )
 
-var _ Gossiper = (*Proposer)(nil)
+var (
+	_ Gossiper       = (*Proposer)(nil)
+	_ FilterGossiper = (*Proposer)(nil)
+)
 
 type Proposer struct {
 	vm         VM
@@ -41,6 +46,11 @@ type Proposer struct {
 
 	// cache is thread-safe
 	cache *cache.FIFO[ids.ID, any]
+
+	// filterSender is used to send the filter of our mempool to peers
+	// and [filters] are the latest filters received from them
+	filterSender common.AppSender
+	filters      *peerFilters
 }
 
 type ProposerConfig struct {
@@ -52,6 +62,13 @@ type ProposerConfig struct {
 	NoGossipBuilderDiff int
 	VerifyTimeout       int64 // ms
 	SeenCacheSize       int
+
+	// GossipFilterInterval is how often a filter of the mempool is sent to
+	// peers (0 disables filters). Txs a peer's filter contains aren't
+	// gossiped to it, so a false positive can prevent a tx from reaching
+	// that peer.
+	GossipFilterInterval      int64 // ms
+	GossipFilterFalsePositive float64
 }
 
 func DefaultProposerConfig() *ProposerConfig {
@@ -64,6 +81,9 @@ func DefaultProposerConfig() *ProposerConfig {
 		NoGossipBuilderDiff: 4,
 		VerifyTimeout:       proposer.MaxVerifyDelay.Milliseconds(),
 		SeenCacheSize:       2_500_000,
+
+		GossipFilterInterval:      1_000,
+		GossipFilterFalsePositive: 0.001,
 	}
 }
 
@@ -77,6 +97,10 @@ func NewProposer(vm VM, cfg *ProposerConfig) (*Proposer, error) {
 
 		q:         make(chan struct{}),
 		lastQueue: -1,
+
+		// Filters are only used until a few newer ones should have been
+		// received
+		filters: newPeerFilters(3 * cfg.GossipFilterInterval),
 	}
 	g.timer = timer.NewTimer(g.handleTimerNotify)
 	cache, err := cache.NewFIFO[ids.ID, any](cfg.SeenCacheSize)
@@ -117,12 +141,12 @@ func (g *Proposer) Force(ctx context.Context) error {
 		g.vm.GetTargetGossipDuration(),
 		func(ictx context.Context, next *chain.Transaction) (cont bool, rest bool, err error) {
 			// Remove txs that are expired
-			if next.Base.Timestamp < now {
+			if next.Expiry() < now {
 				return true, false, nil
 			}
 
 			// Don't gossip txs that are about to expire
-			life := next.Base.Timestamp - now
+			life := next.Expiry() - now
 			if life < g.cfg.GossipMinLife {
 				return true, true, nil
 			}
@@ -151,10 +175,10 @@ func (g *Proposer) Force(ctx context.Context) error {
 		return mempoolErr
 	}
 	if len(txs) == 0 {
-		g.vm.Logger().Debug("no transactions to gossip")
+		g.vm.ModuleLogger(logs.Gossiper).Debug("no transactions to gossip")
 		return nil
 	}
-	g.vm.Logger().Debug("gossiping transactions", zap.Int("txs", len(txs)), zap.Duration("t", time.Since(start)))
+	g.vm.ModuleLogger(logs.Gossiper).Debug("gossiping transactions", zap.Int("txs", len(txs)), zap.Duration("t", time.Since(start)))
 	g.vm.RecordTxsGossiped(len(txs))
 	return g.sendTxs(ctx, txs)
 }
@@ -163,7 +187,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 	actionRegistry, authRegistry := g.vm.Registry()
 	authCounts, txs, err := chain.UnmarshalTxs(msg, initialCapacity, actionRegistry, authRegistry)
 	if err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"received invalid txs",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
@@ -179,7 +203,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 	// a separate pool of workers for this verification.
 	job, err := workers.NewSerial().NewJob(len(txs))
 	if err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"unable to spawn new worker",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
@@ -192,7 +216,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 		// Verify signature async
 		txDigest, err := tx.Digest()
 		if err != nil {
-			g.vm.Logger().Warn(
+			g.vm.ModuleLogger(logs.Gossiper).Warn(
 				"unable to compute tx digest",
 				zap.Stringer("peerID", nodeID),
 				zap.Error(err),
@@ -214,7 +238,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 
 	// Wait for signature verification to finish
 	if err := job.Wait(); err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"received invalid gossip",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
@@ -225,7 +249,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 	// Mark incoming gossip as held by [nodeID], if it is a validator
 	isValidator, err := g.vm.IsValidator(ctx, nodeID)
 	if err != nil {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"unable to determine if nodeID is validator",
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
@@ -238,14 +262,14 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 		if err == nil || errors.Is(err, chain.ErrDuplicateTx) {
 			continue
 		}
-		g.vm.Logger().Debug(
+		g.vm.ModuleLogger(logs.Gossiper).Debug(
 			"failed to submit gossiped txs",
 			zap.Stringer("nodeID", nodeID),
 			zap.Bool("validator", isValidator),
 			zap.Error(err),
 		)
 	}
-	g.vm.Logger().Debug(
+	g.vm.ModuleLogger(logs.Gossiper).Debug(
 		"tx gossip received",
 		zap.Int("txs", len(txs)),
 		zap.Int("previously seen", seen),
@@ -274,7 +298,7 @@ func (g *Proposer) handleTimerNotify() {
 
 func (g *Proposer) Queue(context.Context) {
 	if !g.waiting.CompareAndSwap(false, true) {
-		g.vm.Logger().Debug("unable to start waiting")
+		g.vm.ModuleLogger(logs.Gossiper).Debug("unable to start waiting")
 		return
 	}
 	now := time.Now().UnixMilli()
@@ -287,7 +311,7 @@ func (g *Proposer) Queue(context.Context) {
 	sleep := force - now
 	sleepDur := time.Duration(sleep * int64(time.Millisecond))
 	g.timer.SetTimeoutIn(sleepDur)
-	g.vm.Logger().Debug("waiting to notify to gossip", zap.Duration("t", sleepDur))
+	g.vm.ModuleLogger(logs.Gossiper).Debug("waiting to notify to gossip", zap.Duration("t", sleepDur))
 }
 
 // periodically but less aggressively force-regossip the pending
@@ -298,6 +322,15 @@ func (g *Proposer) Run(appSender common.AppSender) {
 	// Timer blocks until stopped
 	go g.timer.Dispatch()
 
+	if g.filterSender != nil && g.cfg.GossipFilterInterval > 0 {
+		doneFilters := make(chan struct{})
+		go func() {
+			defer close(doneFilters)
+			g.runFilters()
+		}()
+		defer func() { <-doneFilters }()
+	}
+
 	for {
 		select {
 		case <-g.q:
@@ -313,25 +346,77 @@ func (g *Proposer) Run(appSender common.AppSender) {
 				)
 				if err == nil && proposers.Contains(g.vm.NodeID()) {
 					g.Queue(tctx) // requeue later in case peer validator
-					g.vm.Logger().Debug("not gossiping because soon to propose")
+					g.vm.ModuleLogger(logs.Gossiper).Debug("not gossiping because soon to propose")
 					continue
 				} else if err != nil {
-					g.vm.Logger().Warn("unable to determine if will propose soon, gossiping anyways", zap.Error(err))
+					g.vm.ModuleLogger(logs.Gossiper).Warn("unable to determine if will propose soon, gossiping anyways", zap.Error(err))
 				}
 			}
 
 			// Gossip to proposers who will produce next
 			if err := g.Force(tctx); err != nil {
-				g.vm.Logger().Warn("gossip txs failed", zap.Error(err))
+				g.vm.ModuleLogger(logs.Gossiper).Warn("gossip txs failed", zap.Error(err))
 				continue
 			}
 		case <-g.vm.StopChan():
-			g.vm.Logger().Info("stopping gossip loop")
+			g.vm.ModuleLogger(logs.Gossiper).Info("stopping gossip loop")
 			return
 		}
 	}
 }
 
+func (g *Proposer) SetFilterSender(appSender common.AppSender) {
+	g.filterSender = appSender
+}
+
+// runFilters sends a filter of our mempool to all peers every
+// [GossipFilterInterval] until the VM is stopped.
+func (g *Proposer) runFilters() {
+	t := time.NewTicker(time.Duration(g.cfg.GossipFilterInterval) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := g.sendFilter(context.Background()); err != nil {
+				g.vm.ModuleLogger(logs.Gossiper).Warn("unable to send mempool filter", zap.Error(err))
+			}
+		case <-g.vm.StopChan():
+			return
+		}
+	}
+}
+
+func (g *Proposer) sendFilter(ctx context.Context) error {
+	ctx, span := g.vm.Tracer().Start(ctx, "Gossiper.sendFilter")
+	defer span.End()
+
+	txIDs := g.vm.Mempool().IDs(ctx)
+	if len(txIDs) == 0 {
+		// Peers will stop using our last filter once it is too old
+		return nil
+	}
+	filter, err := newMempoolFilter(txIDs, g.cfg.GossipFilterFalsePositive)
+	if err != nil {
+		return err
+	}
+	return g.filterSender.SendAppGossip(ctx, filter.Marshal())
+}
+
+func (g *Proposer) HandleFilter(_ context.Context, nodeID ids.NodeID, msg []byte) error {
+	filter, err := bloom.Parse(msg)
+	if err != nil {
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
+			"received invalid mempool filter",
+			zap.Stringer("peerID", nodeID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	g.filters.set(nodeID, filter, time.Now().UnixMilli())
+	return nil
+}
+
 func (g *Proposer) BlockVerified(t int64) {
 	if t < g.lastVerified {
 		return
@@ -361,19 +446,44 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 		g.cfg.GossipProposerDepth,
 	)
 	if err != nil || proposers.Len() == 0 {
-		g.vm.Logger().Warn(
+		g.vm.ModuleLogger(logs.Gossiper).Warn(
 			"unable to find any proposers, falling back to all-to-all gossip",
 			zap.Error(err),
 		)
 		return g.appSender.SendAppGossip(ctx, b)
 	}
-	recipients := set.NewSet[ids.NodeID](len(proposers))
+	var (
+		recipients = set.NewSet[ids.NodeID](len(proposers))
+		now        = time.Now().UnixMilli()
+		filtered   int
+	)
 	for proposer := range proposers {
 		// Don't gossip to self
 		if proposer == g.vm.NodeID() {
 			continue
 		}
-		recipients.Add(proposer)
+
+		// Only send the txs that [proposer] doesn't already have
+		unknown := unknownTxs(g.filters.get(proposer, now), txs)
+		if len(unknown) == len(txs) {
+			recipients.Add(proposer)
+			continue
+		}
+		filtered += len(txs) - len(unknown)
+		if len(unknown) == 0 {
+			continue
+		}
+		pb, err := chain.MarshalTxs(unknown)
+		if err != nil {
+			return err
+		}
+		if err := g.appSender.SendAppGossipSpecific(ctx, set.Of(proposer), pb); err != nil {
+			return err
+		}
+	}
+	g.vm.RecordTxsFiltered(filtered)
+	if recipients.Len() == 0 {
+		return nil
 	}
 	return g.appSender.SendAppGossipSpecific(ctx, recipients, b)
 }
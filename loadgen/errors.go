@@ -0,0 +1,15 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import "errors"
+
+var (
+	ErrNoAccounts      = errors.New("no accounts")
+	ErrNoIssuers       = errors.New("no issuers")
+	ErrNoProfile       = errors.New("no profile")
+	ErrInvalidRate     = errors.New("invalid rate")
+	ErrIssuerAbandoned = errors.New("issuer abandoned")
+	ErrDrainTimeout    = errors.New("timed out waiting for outstanding txs")
+)
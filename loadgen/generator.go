@@ -0,0 +1,255 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//nolint:gosec
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+const (
+	backlogRetryInterval    = 1 * time.Second
+	unitPriceUpdateInterval = 1 * time.Second
+
+	// Transactions are issued with a timestamp close to the end of the
+	// validity window so that they aren't rejected for being too early if
+	// the clock of the receiving node lags behind.
+	timestampMargin = 5 * consts.MillisecondsPerSecond
+)
+
+// Account is a funded account that issues transactions during a load test.
+type Account struct {
+	Address codec.Address
+	Factory chain.AuthFactory
+
+	// Balance is the native balance of the account. It is decremented by the
+	// maximum fee and spend of each transaction issued by the account.
+	Balance uint64
+}
+
+type Config struct {
+	Parser   chain.Parser
+	Accounts []*Account
+	Profile  Profile
+
+	// TxsPerAccount is the number of transactions each account issues per
+	// second. It is ignored if [Ramp] is set.
+	TxsPerAccount int
+	Ramp          *TPSRamp
+
+	// MaxTxBacklog is the number of inflight transactions above which
+	// accounts pause issuance.
+	MaxTxBacklog int
+
+	// MaxFee overrides the fee computed from the current unit prices.
+	MaxFee *uint64
+}
+
+// Generator issues transactions from a set of accounts through a set of
+// [Issuer]s.
+type Generator struct {
+	config    *Config
+	addresses []codec.Address
+	issuers   []*Issuer
+	metrics   *Metrics
+
+	unitPrices atomic.Pointer[chain.Dimensions]
+}
+
+func New(config *Config, issuers []*Issuer, metrics *Metrics) (*Generator, error) {
+	if len(config.Accounts) == 0 {
+		return nil, ErrNoAccounts
+	}
+	if len(issuers) == 0 {
+		return nil, ErrNoIssuers
+	}
+	if config.Profile == nil {
+		return nil, ErrNoProfile
+	}
+	if config.Ramp != nil && (config.Ramp.Target <= 0 || config.Ramp.Start < 0) {
+		return nil, ErrInvalidRate
+	}
+	addresses := make([]codec.Address, len(config.Accounts))
+	for i, account := range config.Accounts {
+		addresses[i] = account.Address
+	}
+	return &Generator{
+		config:    config,
+		addresses: addresses,
+		issuers:   issuers,
+		metrics:   metrics,
+	}, nil
+}
+
+// Run issues transactions from all accounts until [ctx] is cancelled or an
+// account encounters an unrecoverable error.
+func (g *Generator) Run(ctx context.Context) error {
+	unitPrices, err := g.issuers[0].Client().UnitPrices(ctx, false)
+	if err != nil {
+		return err
+	}
+	g.unitPrices.Store(&unitPrices)
+
+	rctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go g.updateUnitPrices(rctx)
+
+	start := time.Now()
+	eg, ectx := errgroup.WithContext(rctx)
+	for ri := range g.config.Accounts {
+		i := ri
+		eg.Go(func() error {
+			return g.runAccount(ectx, i, start)
+		})
+	}
+	return eg.Wait()
+}
+
+// UnitPrices returns the unit prices most recently used to compute fees.
+func (g *Generator) UnitPrices() chain.Dimensions {
+	return *g.unitPrices.Load()
+}
+
+func (g *Generator) updateUnitPrices(ctx context.Context) {
+	t := time.NewTicker(unitPriceUpdateInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			unitPrices, err := g.issuers[0].Client().UnitPrices(ctx, false)
+			if err != nil {
+				continue
+			}
+			g.unitPrices.Store(&unitPrices)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (g *Generator) runAccount(ctx context.Context, i int, start time.Time) error {
+	t := time.NewTimer(0) // ensure no duplicates created
+	defer t.Stop()
+
+	var (
+		account  = g.config.Accounts[i]
+		issuer   = g.issuers[rand.Intn(len(g.issuers))]
+		workload = g.config.Profile(i, g.addresses)
+		ut       = time.Now().Unix()
+	)
+	for {
+		select {
+		case <-t.C:
+			// Ensure we aren't too backlogged
+			if g.metrics.Inflight() > int64(g.config.MaxTxBacklog) {
+				t.Reset(backlogRetryInterval)
+				continue
+			}
+
+			// Select tx time
+			//
+			// Needed to prevent duplicates if called within the same
+			// unix second.
+			nextTime := time.Now().Unix()
+			if nextTime <= ut {
+				nextTime = ut + 1
+			}
+			ut = nextTime
+			rules := g.config.Parser.Rules(nextTime)
+			tm := &timeModifier{nextTime*consts.MillisecondsPerSecond + rules.GetValidityWindow() - timestampMargin}
+
+			// Send transactions
+			roundStart := time.Now()
+			numTxs := g.config.TxsPerAccount
+			if g.config.Ramp != nil {
+				numTxs = g.config.Ramp.txs(roundStart.Sub(start), i, len(g.config.Accounts))
+			}
+			unitPrices := g.UnitPrices()
+			for k := 0; k < numTxs; k++ {
+				action, spend, err := workload.Next(k)
+				if err != nil {
+					return err
+				}
+				fee := g.config.MaxFee
+				if fee == nil {
+					units, err := chain.EstimateMaxUnits(rules, action, account.Factory, nil)
+					if err != nil {
+						return err
+					}
+					estimate, err := chain.EstimateFee(rules, unitPrices, units)
+					if err != nil {
+						return err
+					}
+					fee = &estimate
+				}
+				if *fee+spend > account.Balance {
+					continue
+				}
+				_, tx, err := issuer.Client().GenerateTransactionManual(g.config.Parser, nil, action, account.Factory, *fee, tm)
+				if err != nil {
+					continue
+				}
+				if err := issuer.Issue(tx); err != nil {
+					if errors.Is(err, ErrIssuerAbandoned) {
+						return err
+					}
+					continue
+				}
+				account.Balance -= *fee + spend
+				workload.Issued(action, tx.ID())
+			}
+
+			// Determine how long to sleep
+			dur := time.Since(roundStart)
+			sleep := math.Max(float64(consts.MillisecondsPerSecond-dur.Milliseconds()), 0)
+			t.Reset(time.Duration(sleep) * time.Millisecond)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Drain waits up to [timeout] for all outstanding transactions to be seen
+// and then closes all issuers. It should only be called after [Run]
+// returns.
+func (g *Generator) Drain(timeout time.Duration) error {
+	var (
+		wg       sync.WaitGroup
+		timedOut atomic.Bool
+	)
+	for _, issuer := range g.issuers {
+		wg.Add(1)
+		go func(issuer *Issuer) {
+			defer wg.Done()
+			if err := issuer.drain(timeout); err != nil {
+				timedOut.Store(true)
+			}
+		}(issuer)
+	}
+	wg.Wait()
+	if timedOut.Load() {
+		return ErrDrainTimeout
+	}
+	return nil
+}
+
+type timeModifier struct {
+	Timestamp int64
+}
+
+func (t *timeModifier) Base(b *chain.Base) {
+	b.Timestamp = t.Timestamp
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/pubsub"
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+const drainPollInterval = 500 * time.Millisecond
+
+// Issuer submits transactions to a single node over a WebSocket connection
+// and records their outcome in [Metrics].
+type Issuer struct {
+	uri     string
+	cli     *rpc.JSONRPCClient
+	metrics *Metrics
+
+	listeners sync.WaitGroup
+
+	l           sync.Mutex
+	ws          *rpc.WebSocketClient
+	abandoned   error
+	outstanding int
+	issued      map[ids.ID]time.Time
+}
+
+func NewIssuer(uri string, metrics *Metrics) (*Issuer, error) {
+	ws, err := newWebSocketClient(uri)
+	if err != nil {
+		return nil, err
+	}
+	i := &Issuer{
+		uri:     uri,
+		cli:     rpc.NewJSONRPCClient(uri),
+		metrics: metrics,
+		ws:      ws,
+		issued:  map[ids.ID]time.Time{},
+	}
+	i.listen(ws)
+	return i, nil
+}
+
+func newWebSocketClient(uri string) (*rpc.WebSocketClient, error) {
+	return rpc.NewWebSocketClient(uri, rpc.DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize) // we write the max read
+}
+
+// Client returns the JSON-RPC client of the node [i] sends transactions to.
+func (i *Issuer) Client() *rpc.JSONRPCClient {
+	return i.cli
+}
+
+func (i *Issuer) listen(ws *rpc.WebSocketClient) {
+	i.listeners.Add(1)
+	go func() {
+		defer i.listeners.Done()
+
+		for {
+			txID, dErr, result, err := ws.ListenTx(context.TODO())
+			if err != nil {
+				return
+			}
+			i.l.Lock()
+			i.outstanding--
+			issuedAt, tracked := i.issued[txID]
+			delete(i.issued, txID)
+			i.l.Unlock()
+			i.metrics.recordResult(result, dErr, time.Since(issuedAt), tracked)
+		}
+	}()
+}
+
+// Issue submits [tx]. If the connection to the node was closed, it is
+// re-created before sending. If it cannot be re-created, [i] is abandoned
+// and all further calls return [ErrIssuerAbandoned].
+func (i *Issuer) Issue(tx *chain.Transaction) error {
+	i.l.Lock()
+	if i.abandoned != nil {
+		i.l.Unlock()
+		return i.abandoned
+	}
+	if i.ws.Closed() {
+		ws, err := newWebSocketClient(i.uri)
+		if err != nil {
+			i.abandoned = fmt.Errorf("%w: %v", ErrIssuerAbandoned, err)
+			i.l.Unlock()
+			return i.abandoned
+		}
+		i.ws = ws
+		i.listen(ws)
+	}
+	ws := i.ws
+	txID := tx.ID()
+	i.issued[txID] = time.Now()
+	// [outstanding] is incremented before registering [tx] so that its
+	// decision can't be received first (which would let [drain] return early)
+	i.outstanding++
+	i.l.Unlock()
+
+	if err := ws.RegisterTx(tx); err != nil {
+		i.l.Lock()
+		delete(i.issued, txID)
+		i.outstanding--
+		i.l.Unlock()
+		return err
+	}
+	i.metrics.recordIssued()
+	return nil
+}
+
+// drain waits up to [timeout] for all outstanding transactions to be seen
+// and then closes the connection to the node.
+func (i *Issuer) drain(timeout time.Duration) error {
+	defer func() {
+		i.l.Lock()
+		_ = i.ws.Close()
+		i.l.Unlock()
+		i.listeners.Wait()
+	}()
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		i.l.Lock()
+		closed := i.ws.Closed()
+		outstanding := i.outstanding
+		i.l.Unlock()
+		if closed || outstanding <= 0 {
+			return nil
+		}
+		time.Sleep(drainPollInterval)
+	}
+	return ErrDrainTimeout
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//nolint:gosec
+package loadgen
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+const maxLatencySamples = 100_000
+
+// Snapshot is a point-in-time view of the transactions issued during a load
+// test.
+type Snapshot struct {
+	Issued   uint64
+	Inflight int64
+
+	// Seen is the number of transactions that were either included in a
+	// block or dropped by the node they were sent to.
+	Seen      uint64
+	Confirmed uint64
+	Failed    uint64
+	Expired   uint64
+	Dropped   uint64
+}
+
+// SuccessRate returns the fraction of seen transactions that were
+// successfully confirmed.
+func (s Snapshot) SuccessRate() float64 {
+	if s.Seen == 0 {
+		return 0
+	}
+	return float64(s.Confirmed) / float64(s.Seen)
+}
+
+// Latencies summarizes the time between issuing a transaction and
+// observing it in an accepted block.
+type Latencies struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Metrics tracks the outcome of all transactions issued by a set of
+// [Issuer]s. It is safe to use concurrently.
+type Metrics struct {
+	issued   atomic.Uint64
+	inflight atomic.Int64
+
+	l         sync.Mutex
+	seen      uint64
+	confirmed uint64
+	failed    uint64
+	expired   uint64
+	dropped   uint64
+	latencies *latencyRecorder
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{latencies: newLatencyRecorder(maxLatencySamples)}
+}
+
+func (m *Metrics) recordIssued() {
+	m.issued.Add(1)
+	m.inflight.Add(1)
+}
+
+func (m *Metrics) recordResult(result *chain.Result, dErr error, latency time.Duration, tracked bool) {
+	m.inflight.Add(-1)
+
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.seen++
+	switch {
+	case result == nil:
+		// We can't error match here because we receive it over the wire.
+		if strings.Contains(dErr.Error(), rpc.ErrExpired.Error()) {
+			m.expired++
+		} else {
+			m.dropped++
+		}
+		return
+	case result.Success():
+		m.confirmed++
+	default:
+		m.failed++
+	}
+	if tracked {
+		m.latencies.Record(latency)
+	}
+}
+
+// Inflight returns the number of issued transactions that have not yet
+// been seen.
+func (m *Metrics) Inflight() int64 {
+	return m.inflight.Load()
+}
+
+func (m *Metrics) Snapshot() Snapshot {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return Snapshot{
+		Issued:    m.issued.Load(),
+		Inflight:  m.inflight.Load(),
+		Seen:      m.seen,
+		Confirmed: m.confirmed,
+		Failed:    m.failed,
+		Expired:   m.expired,
+		Dropped:   m.dropped,
+	}
+}
+
+// Latencies returns the latency percentiles of all transactions included
+// in a block. Percentiles are computed over a bounded random sample.
+func (m *Metrics) Latencies() Latencies {
+	m.l.Lock()
+	defer m.l.Unlock()
+	ps := m.latencies.Percentiles(0.5, 0.9, 0.99)
+	return Latencies{
+		P50: ps[0],
+		P90: ps[1],
+		P99: ps[2],
+		Max: m.latencies.max,
+	}
+}
+
+// latencyRecorder tracks latencies using reservoir sampling, so that memory
+// usage stays bounded during long load tests.
+//
+// latencyRecorder is not thread-safe.
+type latencyRecorder struct {
+	seen    uint64
+	max     time.Duration
+	samples []time.Duration
+}
+
+func newLatencyRecorder(size int) *latencyRecorder {
+	return &latencyRecorder{samples: make([]time.Duration, 0, size)}
+}
+
+func (l *latencyRecorder) Record(d time.Duration) {
+	l.seen++
+	if d > l.max {
+		l.max = d
+	}
+	if len(l.samples) < cap(l.samples) {
+		l.samples = append(l.samples, d)
+		return
+	}
+	if j := rand.Int63n(int64(l.seen)); j < int64(len(l.samples)) {
+		l.samples[j] = d
+	}
+}
+
+// Percentiles returns the latency at each percentile in [ps] (in the range
+// (0, 1]).
+func (l *latencyRecorder) Percentiles(ps ...float64) []time.Duration {
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	results := make([]time.Duration, len(ps))
+	if len(sorted) == 0 {
+		return results
+	}
+	for i, p := range ps {
+		index := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if index < 0 {
+			index = 0
+		}
+		results[i] = sorted[index]
+	}
+	return results
+}
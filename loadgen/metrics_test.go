@@ -0,0 +1,60 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+func TestMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m := NewMetrics()
+	for i := 1; i <= 100; i++ {
+		m.recordIssued()
+		status := chain.StatusSuccess
+		if i%10 == 0 {
+			status = chain.StatusFailed
+		}
+		m.recordResult(&chain.Result{Status: status}, nil, time.Duration(i)*time.Millisecond, true)
+	}
+	m.recordIssued()
+	m.recordResult(nil, rpc.ErrExpired, 0, true)
+	m.recordIssued()
+	m.recordResult(nil, errors.New("invalid"), 0, true)
+	m.recordIssued()
+
+	s := m.Snapshot()
+	require.Equal(uint64(103), s.Issued)
+	require.Equal(int64(1), s.Inflight)
+	require.Equal(uint64(102), s.Seen)
+	require.Equal(uint64(90), s.Confirmed)
+	require.Equal(uint64(10), s.Failed)
+	require.Equal(uint64(1), s.Expired)
+	require.Equal(uint64(1), s.Dropped)
+
+	l := m.Latencies()
+	require.Equal(50*time.Millisecond, l.P50)
+	require.Equal(90*time.Millisecond, l.P90)
+	require.Equal(99*time.Millisecond, l.P99)
+	require.Equal(100*time.Millisecond, l.Max)
+}
+
+func TestLatencyRecorderBounded(t *testing.T) {
+	require := require.New(t)
+
+	r := newLatencyRecorder(10)
+	for i := 0; i < 1_000; i++ {
+		r.Record(time.Duration(i))
+	}
+	require.Len(r.samples, 10)
+	require.Equal(time.Duration(999), r.max)
+}
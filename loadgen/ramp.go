@@ -0,0 +1,34 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import "time"
+
+// TPSRamp linearly adjusts the number of transactions issued per second
+// across all accounts from [Start] to [Target] over [Duration].
+type TPSRamp struct {
+	Start    int
+	Target   int
+	Duration time.Duration
+}
+
+// TPS returns the target rate [elapsed] after the start of the ramp.
+func (r *TPSRamp) TPS(elapsed time.Duration) int {
+	if elapsed >= r.Duration {
+		return r.Target
+	}
+	progress := float64(elapsed) / float64(r.Duration)
+	return r.Start + int(float64(r.Target-r.Start)*progress)
+}
+
+// txs returns the number of transactions account [i] of [n] should issue in
+// the current second.
+func (r *TPSRamp) txs(elapsed time.Duration, i int, n int) int {
+	tps := r.TPS(elapsed)
+	txs := tps / n
+	if i < tps%n {
+		txs++
+	}
+	return txs
+}
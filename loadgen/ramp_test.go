@@ -0,0 +1,30 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTPSRamp(t *testing.T) {
+	require := require.New(t)
+
+	r := &TPSRamp{Start: 100, Target: 1_100, Duration: 10 * time.Second}
+	require.Equal(100, r.TPS(0))
+	require.Equal(600, r.TPS(5*time.Second))
+	require.Equal(1_100, r.TPS(10*time.Second))
+	require.Equal(1_100, r.TPS(time.Hour))
+
+	// Remainder is spread over the first accounts
+	total := 0
+	for i := 0; i < 7; i++ {
+		txs := r.txs(5*time.Second, i, 7)
+		require.Contains([]int{85, 86}, txs)
+		total += txs
+	}
+	require.Equal(600, total)
+}
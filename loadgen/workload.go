@@ -0,0 +1,121 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//nolint:gosec
+package loadgen
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const (
+	zipfS = 1.1
+	zipfV = 1
+)
+
+// Workload generates the actions issued by a single account during a load
+// test.
+type Workload interface {
+	// Next returns the [i]th action to issue in the current round and the
+	// amount of the native asset it consumes (excluding fees).
+	//
+	// All transactions issued in a round share a timestamp, so actions
+	// returned for different [i] in the same round must be unique.
+	Next(i int) (chain.Action, uint64, error)
+
+	// Issued is called after a transaction containing [action] is
+	// submitted.
+	Issued(action chain.Action, txID ids.ID)
+}
+
+// Profile creates the [Workload] for the account at index [self].
+type Profile func(self int, accounts []codec.Address) Workload
+
+// TransferFunc returns an action that sends [amount] of the native asset to
+// [to].
+type TransferFunc func(to codec.Address, amount uint64) chain.Action
+
+type recipientSelector func(self int, accounts []codec.Address) (codec.Address, error)
+
+type transferWorkload struct {
+	self        int
+	accounts    []codec.Address
+	next        recipientSelector
+	getTransfer TransferFunc
+
+	selected map[codec.Address]int
+}
+
+func (w *transferWorkload) Next(i int) (chain.Action, uint64, error) {
+	if i == 0 {
+		w.selected = map[codec.Address]int{}
+	}
+	recipient, err := w.next(w.self, w.accounts)
+	if err != nil {
+		return nil, 0, err
+	}
+	v := w.selected[recipient] + 1
+	w.selected[recipient] = v
+	return w.getTransfer(recipient, uint64(v)), uint64(v), nil
+}
+
+func (*transferWorkload) Issued(chain.Action, ids.ID) {}
+
+func newTransferProfile(getTransfer TransferFunc, next func(self int) recipientSelector) Profile {
+	return func(self int, accounts []codec.Address) Workload {
+		return &transferWorkload{
+			self:        self,
+			accounts:    accounts,
+			next:        next(self),
+			getTransfer: getTransfer,
+		}
+	}
+}
+
+// UniformProfile sends transfers to other accounts selected uniformly at
+// random.
+func UniformProfile(getTransfer TransferFunc) Profile {
+	return newTransferProfile(getTransfer, func(int) recipientSelector {
+		return func(self int, accounts []codec.Address) (codec.Address, error) {
+			index := rand.Intn(len(accounts))
+			if index == self {
+				index = (index + 1) % len(accounts)
+			}
+			return accounts[index], nil
+		}
+	})
+}
+
+// RandomRecipientProfile sends transfers to a new address returned by
+// [createAddress] each time.
+func RandomRecipientProfile(getTransfer TransferFunc, createAddress func() (codec.Address, error)) Profile {
+	return newTransferProfile(getTransfer, func(int) recipientSelector {
+		return func(int, []codec.Address) (codec.Address, error) {
+			return createAddress()
+		}
+	})
+}
+
+// ZipfianProfile sends transfers to other accounts selected from a zipfian
+// distribution, concentrating traffic on a small set of hot accounts.
+func ZipfianProfile(getTransfer TransferFunc) Profile {
+	return newTransferProfile(getTransfer, func(index int) recipientSelector {
+		r := rand.New(rand.NewSource(time.Now().UnixNano() + int64(index)))
+		var z *rand.Zipf
+		return func(self int, accounts []codec.Address) (codec.Address, error) {
+			if z == nil {
+				z = rand.NewZipf(r, zipfS, zipfV, uint64(len(accounts)-1))
+			}
+			index := int(z.Uint64())
+			if index == self {
+				index = (index + 1) % len(accounts)
+			}
+			return accounts[index], nil
+		}
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+type testTransfer struct {
+	chain.Action
+
+	to     codec.Address
+	amount uint64
+}
+
+func TestTransferProfiles(t *testing.T) {
+	accounts := make([]codec.Address, 5)
+	for i := range accounts {
+		accounts[i] = codec.CreateAddress(0, [32]byte{byte(i)})
+	}
+	getTransfer := func(to codec.Address, amount uint64) chain.Action {
+		return &testTransfer{to: to, amount: amount}
+	}
+	for name, profile := range map[string]Profile{
+		"uniform": UniformProfile(getTransfer),
+		"zipfian": ZipfianProfile(getTransfer),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			w := profile(2, accounts)
+			for round := 0; round < 10; round++ {
+				type key struct {
+					to     codec.Address
+					amount uint64
+				}
+				seen := map[key]struct{}{}
+				for i := 0; i < 20; i++ {
+					action, spend, err := w.Next(i)
+					require.NoError(err)
+					transfer := action.(*testTransfer)
+					require.NotEqual(accounts[2], transfer.to)
+					require.Equal(transfer.amount, spend)
+
+					// Actions within a round must be unique
+					k := key{transfer.to, transfer.amount}
+					require.NotContains(seen, k)
+					seen[k] = struct{}{}
+				}
+			}
+		})
+	}
+}
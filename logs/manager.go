@@ -0,0 +1,229 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package logs provides loggers for each module of the VM whose levels can
+// be changed independently at runtime, so that a single module can be
+// debugged without restarting the node or enabling debug logs everywhere.
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+)
+
+// Modules of the hypersdk that log through a [Manager].
+const (
+	VM       = "vm"
+	Chain    = "chain"
+	Builder  = "builder"
+	Gossiper = "gossiper"
+	Backfill = "backfill"
+)
+
+// Manager creates the logger of each module on top of a base logger (usually
+// the logger of the chain provided by avalanchego).
+//
+// Modules log at the default level unless their level is overridden with
+// [Manager.SetLevel]. Because the base logger drops anything below its own
+// level, it is lowered to the most verbose level of any module, which also
+// affects anything else that logs directly to it.
+type Manager struct {
+	base logging.Logger
+
+	l            sync.RWMutex
+	defaultLevel logging.Level
+	modules      map[string]*moduleLogger
+}
+
+// NewManager returns a [Manager] that logs to [base] and creates the loggers
+// of [modules]. [level] should be the current level of [base], which is only
+// changed once a level is set.
+func NewManager(base logging.Logger, level logging.Level, modules ...string) *Manager {
+	m := &Manager{
+		base:         base,
+		defaultLevel: level,
+		modules:      make(map[string]*moduleLogger, len(modules)),
+	}
+	for _, module := range modules {
+		l := &moduleLogger{Logger: base, m: m, name: module}
+		l.level.Store(int64(level))
+		m.modules[module] = l
+	}
+	return m
+}
+
+// Get returns the logger of [module], creating it if it doesn't exist.
+func (m *Manager) Get(module string) logging.Logger {
+	m.l.RLock()
+	l, ok := m.modules[module]
+	m.l.RUnlock()
+	if ok {
+		return l
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+	if l, ok := m.modules[module]; ok {
+		return l
+	}
+	l = &moduleLogger{Logger: m.base, m: m, name: module}
+	l.level.Store(int64(m.defaultLevel))
+	m.modules[module] = l
+	return l
+}
+
+// SetDefaultLevel sets the level of all modules that don't override it.
+func (m *Manager) SetDefaultLevel(level logging.Level) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.defaultLevel = level
+	m.update()
+}
+
+// SetLevel overrides the level of [module].
+func (m *Manager) SetLevel(module string, level logging.Level) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	l, ok := m.modules[module]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownModule, module)
+	}
+	l.override = &level
+	m.update()
+	return nil
+}
+
+// ResetLevel makes [module] log at the default level again.
+func (m *Manager) ResetLevel(module string) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	l, ok := m.modules[module]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownModule, module)
+	}
+	l.override = nil
+	m.update()
+	return nil
+}
+
+// Levels returns the level of each module.
+func (m *Manager) Levels() map[string]logging.Level {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	levels := make(map[string]logging.Level, len(m.modules))
+	for name, l := range m.modules {
+		levels[name] = l.getLevel()
+	}
+	return levels
+}
+
+// Modules returns the name of each module, in lexicographic order.
+func (m *Manager) Modules() []string {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	modules := make([]string, 0, len(m.modules))
+	for name := range m.modules {
+		modules = append(modules, name)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// DefaultLevel returns the level of modules that don't override it.
+func (m *Manager) DefaultLevel() logging.Level {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	return m.defaultLevel
+}
+
+// update sets the level of each module and of the base logger. It assumes
+// [m.l] is held.
+func (m *Manager) update() {
+	base := m.defaultLevel
+	for _, l := range m.modules {
+		level := m.defaultLevel
+		if l.override != nil {
+			level = *l.override
+		}
+		l.level.Store(int64(level))
+		if level < base {
+			base = level
+		}
+	}
+	m.base.SetLevel(base)
+}
+
+// moduleLogger only logs the messages of its module at or above the level of
+// the module, and tags them with the name of the module.
+type moduleLogger struct {
+	logging.Logger
+
+	m    *Manager
+	name string
+
+	override *logging.Level // nil if the default level is used (guarded by [m.l])
+	level    atomic.Int64
+}
+
+func (l *moduleLogger) getLevel() logging.Level {
+	return logging.Level(l.level.Load())
+}
+
+func (l *moduleLogger) log(level logging.Level, f func(string, ...zap.Field), msg string, fields []zap.Field) {
+	if !l.Enabled(level) {
+		return
+	}
+	f(msg, append(fields, zap.String("module", l.name))...)
+}
+
+func (l *moduleLogger) Fatal(msg string, fields ...zap.Field) {
+	l.log(logging.Fatal, l.Logger.Fatal, msg, fields)
+}
+
+func (l *moduleLogger) Error(msg string, fields ...zap.Field) {
+	l.log(logging.Error, l.Logger.Error, msg, fields)
+}
+
+func (l *moduleLogger) Warn(msg string, fields ...zap.Field) {
+	l.log(logging.Warn, l.Logger.Warn, msg, fields)
+}
+
+func (l *moduleLogger) Info(msg string, fields ...zap.Field) {
+	l.log(logging.Info, l.Logger.Info, msg, fields)
+}
+
+func (l *moduleLogger) Trace(msg string, fields ...zap.Field) {
+	l.log(logging.Trace, l.Logger.Trace, msg, fields)
+}
+
+func (l *moduleLogger) Debug(msg string, fields ...zap.Field) {
+	l.log(logging.Debug, l.Logger.Debug, msg, fields)
+}
+
+func (l *moduleLogger) Verbo(msg string, fields ...zap.Field) {
+	l.log(logging.Verbo, l.Logger.Verbo, msg, fields)
+}
+
+// SetLevel overrides the level of the module (see [Manager.SetLevel]).
+func (l *moduleLogger) SetLevel(level logging.Level) {
+	_ = l.m.SetLevel(l.name, level)
+}
+
+func (l *moduleLogger) Enabled(level logging.Level) bool {
+	return level >= l.getLevel() && l.Logger.Enabled(level)
+}
+
+// Stop is a no-op because the base logger is shared by all modules (and not
+// owned by the [Manager]).
+func (*moduleLogger) Stop() {}
@@ -0,0 +1,82 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type buffer struct{ bytes.Buffer }
+
+func (*buffer) Close() error { return nil }
+
+// lines returns the JSON entries written to [b] since the last call.
+func (b *buffer) lines(require *require.Assertions) []map[string]any {
+	entries := []map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	b.Reset()
+	return entries
+}
+
+func TestManager(t *testing.T) {
+	require := require.New(t)
+
+	w := &buffer{}
+	base := logging.NewLogger("", logging.NewWrappedCore(logging.Info, w, logging.JSON.ConsoleEncoder()))
+	m := NewManager(base, logging.Info, Chain, Gossiper)
+	chain, gossiper := m.Get(Chain), m.Get(Gossiper)
+
+	// Messages are tagged with their module
+	chain.Info("accepted")
+	chain.Debug("verified")
+	entries := w.lines(require)
+	require.Len(entries, 1)
+	require.Equal("accepted", entries[0]["msg"])
+	require.Equal(Chain, entries[0]["module"])
+
+	// Lowering the level of a module doesn't affect other modules
+	require.NoError(m.SetLevel(Gossiper, logging.Debug))
+	require.True(gossiper.Enabled(logging.Debug))
+	require.False(chain.Enabled(logging.Debug))
+	chain.Debug("verified")
+	gossiper.Debug("gossiped")
+	entries = w.lines(require)
+	require.Len(entries, 1)
+	require.Equal(Gossiper, entries[0]["module"])
+	require.Equal(map[string]logging.Level{Chain: logging.Info, Gossiper: logging.Debug}, m.Levels())
+
+	// Modules can be silenced
+	require.NoError(m.SetLevel(Chain, logging.Off))
+	chain.Error("failed")
+	require.Empty(w.lines(require))
+
+	// Resetting a module restores the default level
+	require.NoError(m.ResetLevel(Gossiper))
+	require.NoError(m.ResetLevel(Chain))
+	m.SetDefaultLevel(logging.Warn)
+	chain.Info("accepted")
+	gossiper.Warn("dropped")
+	entries = w.lines(require)
+	require.Len(entries, 1)
+	require.Equal("dropped", entries[0]["msg"])
+
+	// Modules are created on first use
+	require.ErrorIs(m.SetLevel(Builder, logging.Debug), ErrUnknownModule)
+	m.Get(Builder)
+	require.NoError(m.SetLevel(Builder, logging.Debug))
+	require.Equal([]string{Builder, Chain, Gossiper}, m.Modules())
+}
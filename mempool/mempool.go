@@ -14,12 +14,27 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/eheap"
+	"github.com/ava-labs/hypersdk/heap"
 	"github.com/ava-labs/hypersdk/list"
 	"go.opentelemetry.io/otel/attribute"
 )
 
 const maxPrealloc = 4_096
 
+// DropReason is why an item was not added to the mempool.
+type DropReason string
+
+const (
+	// DropFull means the mempool held [maxSize] items.
+	DropFull DropReason = "mempool_full"
+	// DropSponsorLimit means the sponsor of the item already had
+	// [maxSponsorSize] items in the mempool.
+	DropSponsorLimit DropReason = "sponsor_limit"
+	// DropFreeLimit means the item was free and the mempool already held
+	// [maxFree] free items (see [SetFreeLimit]).
+	DropFreeLimit DropReason = "free_limit"
+)
+
 type Item interface {
 	eheap.Item
 
@@ -40,6 +55,10 @@ type Mempool[T Item] struct {
 	queue *list.List[T]
 	eh    *eheap.ExpiryHeap[*list.Element[T]]
 
+	// deferred items are only returned once [queue] is empty (see
+	// [AddDeferred])
+	deferred *list.List[T]
+
 	// owned tracks the number of items in the mempool owned by a single
 	// [Sponsor]
 	owned map[codec.Address]int
@@ -53,6 +72,32 @@ type Mempool[T Item] struct {
 
 	// sponsors that are exempt from [maxSponsorSize]
 	exemptSponsors set.Set[codec.Address]
+
+	// If [isFree] is set, at most [maxFree] items it returns true for are
+	// held at once (see [SetFreeLimit]).
+	isFree  func(T) bool
+	maxFree int
+	free    set.Set[ids.ID]
+
+	// If [meter] is set, the items of [queue] and [deferred] are indexed by
+	// their units (see [SetMeter]) and streaming skips those that exceed
+	// [limits] (by dimension).
+	meter         Meter[T]
+	entries       map[ids.ID]*entry[T]
+	queueUnits    *unitIndex[T]
+	deferredUnits *unitIndex[T]
+	firstOrder    int64
+	lastOrder     int64
+	limits        map[int]uint64
+
+	// dropped is called with the number of items dropped by each call to
+	// [add] (if set)
+	dropped func(DropReason, int)
+
+	// If [heightExpiry] is set, items are also indexed by the height it
+	// returns in [heights] (see [SetHeightExpiry]).
+	heightExpiry func(T) int64
+	heights      *heap.Heap[*list.Element[T], int64]
 }
 
 // New creates a new [Mempool]. [maxSize] must be > 0 or else the
@@ -69,11 +114,13 @@ func New[T Item](
 		maxSize:        maxSize,
 		maxSponsorSize: maxSponsorSize,
 
-		queue: &list.List[T]{},
-		eh:    eheap.New[*list.Element[T]](math.Min(maxSize, maxPrealloc)),
+		queue:    &list.List[T]{},
+		eh:       eheap.New[*list.Element[T]](math.Min(maxSize, maxPrealloc)),
+		deferred: &list.List[T]{},
 
 		owned:          map[codec.Address]int{},
 		exemptSponsors: set.Set[codec.Address]{},
+		free:           set.Set[ids.ID]{},
 	}
 	for _, sponsor := range exemptSponsors {
 		m.exemptSponsors.Add(sponsor)
@@ -95,6 +142,33 @@ func (m *Mempool[T]) removeFromOwned(item T) {
 	m.owned[sender] = items - 1
 }
 
+// untrack removes [item] from the items tracked by the limits of m.
+func (m *Mempool[T]) untrack(item T) {
+	m.removeFromOwned(item)
+	m.free.Remove(item.ID())
+	if m.heights == nil {
+		return
+	}
+	if e, ok := m.heights.Get(item.ID()); ok {
+		m.heights.Remove(e.Index)
+	}
+}
+
+// Get returns the item with [itemID] in m, if it exists.
+func (m *Mempool[T]) Get(ctx context.Context, itemID ids.ID) (T, bool) {
+	_, span := m.tracer.Start(ctx, "Mempool.Get")
+	defer span.End()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	elem, ok := m.eh.Get(itemID)
+	if !ok {
+		return *new(T), false
+	}
+	return elem.Value(), true
+}
+
 // Has returns if the eh of [m] contains [itemID]
 func (m *Mempool[T]) Has(ctx context.Context, itemID ids.ID) bool {
 	_, span := m.tracer.Start(ctx, "Mempool.Has")
@@ -106,6 +180,87 @@ func (m *Mempool[T]) Has(ctx context.Context, itemID ids.ID) bool {
 	return m.eh.Has(itemID)
 }
 
+// IDs returns the IDs of all items in m (including deferred items but not
+// those that are being streamed).
+func (m *Mempool[T]) IDs(ctx context.Context) []ids.ID {
+	_, span := m.tracer.Start(ctx, "Mempool.IDs")
+	defer span.End()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	itemIDs := make([]ids.ID, 0, m.eh.Len())
+	for _, queue := range []*list.List[T]{m.queue, m.deferred} {
+		for elem := queue.First(); elem != nil; elem = elem.Next() {
+			itemIDs = append(itemIDs, elem.ID())
+		}
+	}
+	return itemIDs
+}
+
+// Range calls [f] on the items in m (including deferred items but not those
+// that are being streamed) until it returns false. [f] must not modify m.
+func (m *Mempool[T]) Range(ctx context.Context, f func(T) bool) {
+	_, span := m.tracer.Start(ctx, "Mempool.Range")
+	defer span.End()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, queue := range []*list.List[T]{m.queue, m.deferred} {
+		for elem := queue.First(); elem != nil; elem = elem.Next() {
+			if !f(elem.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// SetMeter makes m index the items added after it is called by the units
+// [f] returns for them, so that [LimitStream] can skip items that don't fit
+// without removing them. Items [f] fails to meter are never streamed while
+// a limit is set.
+func (m *Mempool[T]) SetMeter(f Meter[T]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.meter = f
+	m.entries = map[ids.ID]*entry[T]{}
+	m.queueUnits = &unitIndex[T]{}
+	m.deferredUnits = &unitIndex[T]{}
+}
+
+// SetFreeLimit makes m hold at most [maxFree] of the items added after it is
+// called that [isFree] returns true for (like transactions that nobody pays
+// fees for), so that they can't fill m at no cost.
+func (m *Mempool[T]) SetFreeLimit(isFree func(T) bool, maxFree int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.isFree = isFree
+	m.maxFree = maxFree
+}
+
+// SetHeightExpiry makes m index the items added after it is called by the
+// height [expiry] returns for them, so they can be removed with
+// [SetMinHeight]. Items keyed by 0 are not indexed.
+func (m *Mempool[T]) SetHeightExpiry(expiry func(T) int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.heightExpiry = expiry
+	m.heights = heap.New[*list.Element[T], int64](0, true)
+}
+
+// SetDropRecorder makes m call [f] whenever items are dropped (instead of
+// being added) because of the limits of m.
+func (m *Mempool[T]) SetDropRecorder(f func(reason DropReason, count int)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dropped = f
+}
+
 // Add pushes all new items from [items] to m. Does not add a item if
 // the item sponsor is not exempt and their items in the mempool exceed m.maxSponsorSize.
 // If the size of m exceeds m.maxSize, Add pops the lowest value item
@@ -117,10 +272,28 @@ func (m *Mempool[T]) Add(ctx context.Context, items []T) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.add(items, false)
+	m.add(items, false, false)
 }
 
-func (m *Mempool[T]) add(items []T, front bool) {
+// AddDeferred is like [Add] but [items] are only returned after all other
+// items (including those added later) in m. Deferred items that are streamed
+// and then restored are no longer deferred.
+func (m *Mempool[T]) AddDeferred(ctx context.Context, items []T) {
+	_, span := m.tracer.Start(ctx, "Mempool.AddDeferred")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.add(items, false, true)
+}
+
+func (m *Mempool[T]) add(items []T, front bool, deferred bool) {
+	queue := m.queue
+	if deferred {
+		queue = m.deferred
+	}
+	var full, sponsorLimit, freeLimit int
 	for _, item := range items {
 		sender := item.Sponsor()
 
@@ -136,25 +309,58 @@ func (m *Mempool[T]) add(items []T, front bool) {
 
 		// Ensure sender isn't abusing mempool
 		senderItems := m.owned[sender]
-		if !m.exemptSponsors.Contains(sender) && senderItems == m.maxSponsorSize {
+		if !m.exemptSponsors.Contains(sender) && senderItems >= m.maxSponsorSize {
+			sponsorLimit++
+			continue // do nothing, wait for items to expire
+		}
+		free := m.isFree != nil && m.isFree(item)
+		if free && m.free.Len() >= m.maxFree {
+			freeLimit++
 			continue // do nothing, wait for items to expire
 		}
 
 		// Ensure mempool isn't full
-		if m.queue.Size() == m.maxSize {
+		if m.eh.Len() >= m.maxSize {
+			full++
 			continue // do nothing, wait for items to expire
 		}
 
 		// Add to mempool
 		var elem *list.Element[T]
 		if !front {
-			elem = m.queue.PushBack(item)
+			elem = queue.PushBack(item)
 		} else {
-			elem = m.queue.PushFront(item)
+			elem = queue.PushFront(item)
 		}
 		m.eh.Add(elem)
+		if m.heightExpiry != nil {
+			if height := m.heightExpiry(item); height != 0 {
+				m.heights.Push(&heap.Entry[*list.Element[T], int64]{
+					ID:    itemID,
+					Val:   height,
+					Item:  elem,
+					Index: m.heights.Len(),
+				})
+			}
+		}
 		m.owned[sender]++
+		if free {
+			m.free.Add(itemID)
+		}
 		m.pendingSize += item.Size()
+		m.index(item, front, deferred)
+	}
+	if m.dropped == nil {
+		return
+	}
+	if full > 0 {
+		m.dropped(DropFull, full)
+	}
+	if sponsorLimit > 0 {
+		m.dropped(DropSponsorLimit, sponsorLimit)
+	}
+	if freeLimit > 0 {
+		m.dropped(DropFreeLimit, freeLimit)
 	}
 }
 
@@ -167,7 +373,7 @@ func (m *Mempool[T]) PeekNext(ctx context.Context) (T, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	first := m.queue.First()
+	first := m.first()
 	if first == nil {
 		return *new(T), false
 	}
@@ -186,16 +392,65 @@ func (m *Mempool[T]) PopNext(ctx context.Context) (T, bool) { // O(log N)
 	return m.popNext()
 }
 
+func (m *Mempool[T]) first() *list.Element[T] {
+	if first := m.queue.First(); first != nil {
+		return first
+	}
+	return m.deferred.First()
+}
+
+// unlink removes [elem] from whichever list (and [unitIndex]) holds it.
+func (m *Mempool[T]) unlink(elem *list.Element[T]) T {
+	m.deferred.Remove(elem)
+	v := m.queue.Remove(elem)
+	if e, ok := m.entries[v.ID()]; ok {
+		delete(m.entries, v.ID())
+		m.queueUnits.remove(e)
+		m.deferredUnits.remove(e)
+	}
+	return v
+}
+
+// index adds [item] to the [unitIndex] of its queue, if m is metered.
+func (m *Mempool[T]) index(item T, front bool, deferred bool) {
+	if m.meter == nil {
+		return
+	}
+	units, err := m.meter(item)
+	if err != nil {
+		return
+	}
+	e := &entry[T]{item: item, units: units}
+	if front {
+		m.firstOrder--
+		e.order = m.firstOrder
+	} else {
+		m.lastOrder++
+		e.order = m.lastOrder
+	}
+	m.entries[item.ID()] = e
+	if deferred {
+		m.deferredUnits.add(e, front)
+	} else {
+		m.queueUnits.add(e, front)
+	}
+}
+
+// remove removes the item of [elem] from m.
+func (m *Mempool[T]) remove(elem *list.Element[T]) T {
+	v := m.unlink(elem)
+	m.eh.Remove(v.ID())
+	m.untrack(v)
+	m.pendingSize -= v.Size()
+	return v
+}
+
 func (m *Mempool[T]) popNext() (T, bool) {
-	first := m.queue.First()
+	first := m.first()
 	if first == nil {
 		return *new(T), false
 	}
-	v := m.queue.Remove(first)
-	m.eh.Remove(v.ID())
-	m.removeFromOwned(v)
-	m.pendingSize -= v.Size()
-	return v, true
+	return m.remove(first), true
 }
 
 // Remove removes [items] from m.
@@ -211,12 +466,23 @@ func (m *Mempool[T]) Remove(ctx context.Context, items []T) {
 		if !ok {
 			continue
 		}
-		m.queue.Remove(elem)
-		m.removeFromOwned(item)
+		m.unlink(elem)
+		m.untrack(item)
 		m.pendingSize -= item.Size()
 	}
 }
 
+// SetLimits updates the maximum number of items in m and the maximum
+// number of items a single sponsor can have in m. Items already in m are
+// not evicted if they exceed the new limits.
+func (m *Mempool[T]) SetLimits(maxSize int, maxSponsorSize int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maxSize = maxSize
+	m.maxSponsorSize = maxSponsorSize
+}
+
 // Len returns the number of items in m.
 func (m *Mempool[T]) Len(ctx context.Context) int {
 	_, span := m.tracer.Start(ctx, "Mempool.Len")
@@ -247,15 +513,38 @@ func (m *Mempool[T]) SetMinTimestamp(ctx context.Context, t int64) []T {
 	removedElems := m.eh.SetMin(t)
 	removed := make([]T, len(removedElems))
 	for i, remove := range removedElems {
-		m.queue.Remove(remove)
+		m.unlink(remove)
 		v := remove.Value()
-		m.removeFromOwned(v)
+		m.untrack(v)
 		m.pendingSize -= v.Size()
 		removed[i] = v
 	}
 	return removed
 }
 
+// SetMinHeight removes and returns all items with a lower expiry height (see
+// [SetHeightExpiry]) than [h] from m.
+func (m *Mempool[T]) SetMinHeight(ctx context.Context, h int64) []T {
+	_, span := m.tracer.Start(ctx, "Mempool.SetMinHeight")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := []T{}
+	if m.heights == nil {
+		return removed
+	}
+	for {
+		first := m.heights.First()
+		if first == nil || first.Val >= h {
+			break
+		}
+		removed = append(removed, m.remove(first.Item))
+	}
+	return removed
+}
+
 // Top iterates over the highest-valued items in the mempool.
 func (m *Mempool[T]) Top(
 	ctx context.Context,
@@ -288,7 +577,7 @@ func (m *Mempool[T]) Top(
 	}
 
 	// Restore unused items
-	m.add(restorableItems, true)
+	m.add(restorableItems, true, false)
 	return err
 }
 
@@ -305,6 +594,55 @@ func (m *Mempool[T]) StartStreaming(_ context.Context) {
 
 	m.streamLock.Lock()
 	m.streamedItems = set.NewSet[ids.ID](maxPrealloc)
+	m.limits = nil
+}
+
+// LimitStream makes the rest of the current stream skip items that may
+// consume more than [limit] units in [dimension] (like once a block can only
+// fit [limit] more). Skipped items are left in m and can be streamed again
+// once streaming finishes.
+//
+// LimitStream has no effect if m is not metered (see [SetMeter]).
+func (m *Mempool[T]) LimitStream(ctx context.Context, dimension int, limit uint64) {
+	_, span := m.tracer.Start(ctx, "Mempool.LimitStream")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.meter == nil || m.streamedItems == nil {
+		return
+	}
+	if m.limits == nil {
+		m.limits = map[int]uint64{}
+	}
+	if existing, ok := m.limits[dimension]; ok && existing <= limit {
+		return
+	}
+	m.limits[dimension] = limit
+
+	// Return any prepared items that no longer fit, in their original
+	// order
+	if !m.nextStreamFetched {
+		return
+	}
+	var (
+		fitting   = make([]T, 0, len(m.nextStream))
+		unfitting []T
+	)
+	for _, item := range m.nextStream {
+		units, err := m.meter(item)
+		if err == nil && (&entry[T]{units: units}).fits(m.limits) {
+			fitting = append(fitting, item)
+			continue
+		}
+		m.streamedItems.Remove(item.ID())
+		unfitting = append(unfitting, item)
+	}
+	m.nextStream = fitting
+	for i := len(unfitting) - 1; i >= 0; i-- {
+		m.add(unfitting[i:i+1], true, false)
+	}
 }
 
 // PrepareStream prefetches the next [count] items from the mempool to
@@ -339,6 +677,9 @@ func (m *Mempool[T]) Stream(ctx context.Context, count int) []T {
 }
 
 func (m *Mempool[T]) streamItems(count int) []T {
+	if len(m.limits) > 0 {
+		return m.streamFittingItems(count)
+	}
 	txs := make([]T, 0, count)
 	for len(txs) < count {
 		item, ok := m.popNext()
@@ -351,6 +692,27 @@ func (m *Mempool[T]) streamItems(count int) []T {
 	return txs
 }
 
+// streamFittingItems is like [streamItems] but only returns items that fit
+// under [m.limits]. Items that don't fit are not removed from m.
+func (m *Mempool[T]) streamFittingItems(count int) []T {
+	txs := make([]T, 0, count)
+	for _, index := range []*unitIndex[T]{m.queueUnits, m.deferredUnits} {
+		for _, e := range index.fitting(m.limits, count-len(txs)) {
+			elem, ok := m.eh.Get(e.ID())
+			if !ok {
+				continue
+			}
+			item := m.remove(elem)
+			m.streamedItems.Add(item.ID())
+			txs = append(txs, item)
+		}
+		if len(txs) == count {
+			break
+		}
+	}
+	return txs
+}
+
 // FinishStreaming restores [restorable] items to the mempool and clears
 // the set of all previously streamed items.
 func (m *Mempool[T]) FinishStreaming(ctx context.Context, restorable []T) int {
@@ -366,9 +728,10 @@ func (m *Mempool[T]) FinishStreaming(ctx context.Context, restorable []T) int {
 
 	restored := len(restorable)
 	m.streamedItems = nil
-	m.add(restorable, true)
+	m.limits = nil
+	m.add(restorable, true, false)
 	if m.nextStreamFetched {
-		m.add(m.nextStream, true)
+		m.add(m.nextStream, true, false)
 		restored += len(m.nextStream)
 		m.nextStream = nil
 		m.nextStreamFetched = false
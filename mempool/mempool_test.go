@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/trace"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,7 @@ type TestItem struct {
 	id        ids.ID
 	sponsor   codec.Address
 	timestamp int64
+	height    int64
 }
 
 func (mti *TestItem) ID() ids.ID {
@@ -142,6 +144,31 @@ func TestMempoolAddExceedMaxSize(t *testing.T) {
 	require.Equal(0, txm.Len(ctx), "Mempool has incorrect number of txs.")
 }
 
+func TestMempoolSetLimits(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 4, 20, nil)
+	for i := int64(0); i < 4; i++ {
+		txm.Add(ctx, []*TestItem{GenerateTestItem(testSponsor, i)})
+	}
+	require.Equal(4, txm.Len(ctx))
+
+	// Existing items are kept when the limit is lowered
+	txm.SetLimits(2, 20)
+	txm.Add(ctx, []*TestItem{GenerateTestItem(testSponsor, 4)})
+	require.Equal(4, txm.Len(ctx))
+
+	// Sponsor limit is enforced against existing items
+	txm.SetLimits(10, 4)
+	txm.Add(ctx, []*TestItem{GenerateTestItem(testSponsor, 5)})
+	require.Equal(4, txm.Len(ctx))
+	txm.SetLimits(10, 5)
+	txm.Add(ctx, []*TestItem{GenerateTestItem(testSponsor, 6)})
+	require.Equal(5, txm.Len(ctx))
+}
+
 func TestMempoolRemoveTxs(t *testing.T) {
 	require := require.New(t)
 	ctrl := gomock.NewController(t)
@@ -191,3 +218,235 @@ func TestMempoolSetMinTimestamp(t *testing.T) {
 	// Mempool has same length
 	require.Equal(5, txm.Len(ctx), "Mempool has incorrect number of txs.")
 }
+
+func TestMempoolSetMinHeight(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 20, 20, nil)
+	require.Empty(txm.SetMinHeight(ctx, 100))
+	txm.SetHeightExpiry(func(item *TestItem) int64 { return item.height })
+
+	// Items that expire by height never reach their timestamp
+	timestamp := GenerateTestItem(testSponsor, 100)
+	heights := []*TestItem{}
+	for i := int64(1); i <= 4; i++ {
+		item := GenerateTestItem(testSponsor, consts.MaxInt64)
+		item.height = i * 10
+		heights = append(heights, item)
+	}
+	txm.Add(ctx, append([]*TestItem{timestamp}, heights...))
+	require.Empty(txm.SetMinTimestamp(ctx, 100))
+	require.Equal(5, txm.Len(ctx))
+
+	// Items are removed once their height is below the min
+	txm.Remove(ctx, []*TestItem{heights[0]})
+	require.ElementsMatch([]*TestItem{heights[1]}, txm.SetMinHeight(ctx, 30))
+	require.ElementsMatch([]*TestItem{heights[2]}, txm.SetMinHeight(ctx, 31))
+	require.Equal(2, txm.Len(ctx))
+	require.Equal(4, txm.Size(ctx))
+	require.True(txm.Has(ctx, timestamp.ID()))
+	require.True(txm.Has(ctx, heights[3].ID()))
+
+	// Streamed items that are restored are indexed again
+	txm.StartStreaming(ctx)
+	require.Len(txm.Stream(ctx, 2), 2)
+	txm.FinishStreaming(ctx, []*TestItem{heights[3]})
+	require.ElementsMatch([]*TestItem{heights[3]}, txm.SetMinHeight(ctx, 100))
+	require.Zero(txm.Len(ctx))
+}
+
+func TestMempoolAddDeferred(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 3, 16, nil)
+	deferred := GenerateTestItem(testSponsor, 100)
+	txm.AddDeferred(ctx, []*TestItem{deferred})
+	next, ok := txm.PeekNext(ctx)
+	require.True(ok)
+	require.Equal(deferred.ID(), next.ID())
+
+	// Items added later are returned first
+	item := GenerateTestItem(testSponsor, 200)
+	txm.Add(ctx, []*TestItem{item})
+	require.Equal(2, txm.Len(ctx))
+	next, ok = txm.PopNext(ctx)
+	require.True(ok)
+	require.Equal(item.ID(), next.ID())
+
+	// Deferred items count towards the size of the mempool
+	txm.AddDeferred(ctx, []*TestItem{GenerateTestItem(testSponsor, 300), GenerateTestItem(testSponsor, 400)})
+	require.Equal(3, txm.Len(ctx))
+	require.Len(txm.SetMinTimestamp(ctx, 301), 2)
+	next, ok = txm.PopNext(ctx)
+	require.True(ok)
+	require.Equal(int64(400), next.Expiry())
+	require.Zero(txm.Len(ctx))
+}
+
+func TestMempoolIDs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 3, 16, nil)
+	require.Empty(txm.IDs(ctx))
+	item := GenerateTestItem(testSponsor, 100)
+	deferred := GenerateTestItem(testSponsor, 200)
+	txm.Add(ctx, []*TestItem{item})
+	txm.AddDeferred(ctx, []*TestItem{deferred})
+	require.Equal([]ids.ID{item.ID(), deferred.ID()}, txm.IDs(ctx))
+
+	// Streamed items are no longer in the mempool
+	txm.StartStreaming(ctx)
+	require.Len(txm.Stream(ctx, 1), 1)
+	require.Equal([]ids.ID{deferred.ID()}, txm.IDs(ctx))
+	txm.FinishStreaming(ctx, nil)
+}
+
+func TestMempoolGet(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 3, 16, nil)
+	item := GenerateTestItem(testSponsor, 100)
+	_, ok := txm.Get(ctx, item.ID())
+	require.False(ok)
+	txm.Add(ctx, []*TestItem{item})
+	got, ok := txm.Get(ctx, item.ID())
+	require.True(ok)
+	require.Equal(item, got)
+}
+
+func TestMempoolRange(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 3, 16, nil)
+	item := GenerateTestItem(testSponsor, 100)
+	deferred := GenerateTestItem(testSponsor, 200)
+	txm.Add(ctx, []*TestItem{item})
+	txm.AddDeferred(ctx, []*TestItem{deferred})
+
+	var seen []*TestItem
+	txm.Range(ctx, func(i *TestItem) bool {
+		seen = append(seen, i)
+		return true
+	})
+	require.Equal([]*TestItem{item, deferred}, seen)
+
+	// Stops once [f] returns false
+	seen = nil
+	txm.Range(ctx, func(i *TestItem) bool {
+		seen = append(seen, i)
+		return false
+	})
+	require.Equal([]*TestItem{item}, seen)
+}
+
+func TestMempoolDropRecorder(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 3, 2, nil)
+	dropped := map[DropReason]int{}
+	txm.SetDropRecorder(func(reason DropReason, count int) {
+		dropped[reason] += count
+	})
+	otherSponsor := codec.CreateAddress(2, ids.GenerateTestID())
+	txm.Add(ctx, []*TestItem{
+		GenerateTestItem(testSponsor, 100),
+		GenerateTestItem(testSponsor, 200),
+		GenerateTestItem(testSponsor, 300),
+	})
+	require.Equal(map[DropReason]int{DropSponsorLimit: 1}, dropped)
+	txm.Add(ctx, []*TestItem{
+		GenerateTestItem(otherSponsor, 100),
+		GenerateTestItem(otherSponsor, 200),
+	})
+	require.Equal(map[DropReason]int{DropSponsorLimit: 1, DropFull: 1}, dropped)
+	require.Equal(3, txm.Len(ctx))
+}
+
+func TestMempoolFreeLimit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 10, 10, nil)
+	dropped := map[DropReason]int{}
+	txm.SetDropRecorder(func(reason DropReason, count int) {
+		dropped[reason] += count
+	})
+	// Items with an even timestamp are free
+	txm.SetFreeLimit(func(item *TestItem) bool { return item.timestamp%2 == 0 }, 2)
+	free := GenerateTestItem(testSponsor, 100)
+	txm.Add(ctx, []*TestItem{
+		free,
+		GenerateTestItem(testSponsor, 200),
+		GenerateTestItem(testSponsor, 300),
+		GenerateTestItem(testSponsor, 101),
+	})
+	require.Equal(map[DropReason]int{DropFreeLimit: 1}, dropped)
+	require.Equal(3, txm.Len(ctx))
+
+	// Removing a free item makes room for another
+	txm.Remove(ctx, []*TestItem{free})
+	txm.Add(ctx, []*TestItem{GenerateTestItem(testSponsor, 400)})
+	require.Equal(map[DropReason]int{DropFreeLimit: 1}, dropped)
+	require.Equal(3, txm.Len(ctx))
+}
+
+func TestMempoolLimitStream(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	tracer, _ := trace.New(&trace.Config{Enabled: false})
+
+	txm := New[*TestItem](tracer, 16, 16, nil)
+	units := map[ids.ID][]uint64{}
+	txm.SetMeter(func(item *TestItem) ([]uint64, error) {
+		return units[item.ID()], nil
+	})
+	var items []*TestItem
+	for i, u := range [][]uint64{{1, 100}, {1, 3}, {50, 1}, {1, 0}, {1, 7}, {1, 8}} {
+		item := GenerateTestItem(testSponsor, int64(i))
+		units[item.ID()] = u
+		items = append(items, item)
+	}
+	txm.Add(ctx, items[:5])
+	txm.AddDeferred(ctx, items[5:])
+
+	// Items that don't fit are skipped (in order) and left in the mempool
+	txm.StartStreaming(ctx)
+	require.Equal([]*TestItem{items[0]}, txm.Stream(ctx, 1))
+	txm.LimitStream(ctx, 1, 7)
+	txm.LimitStream(ctx, 1, 10) // limits never increase
+	require.Equal([]*TestItem{items[1], items[2], items[3]}, txm.Stream(ctx, 3))
+	txm.LimitStream(ctx, 0, 10)
+	require.Equal([]*TestItem{items[4]}, txm.Stream(ctx, 3))
+	require.Empty(txm.Stream(ctx, 3))
+	require.Equal(1, txm.Len(ctx))
+
+	// Prepared items that no longer fit are returned to the mempool
+	require.Equal(2, txm.FinishStreaming(ctx, []*TestItem{items[4], items[0]}))
+	txm.StartStreaming(ctx)
+	txm.PrepareStream(ctx, 3)
+	txm.LimitStream(ctx, 1, 8)
+	require.Equal([]*TestItem{items[4], items[5]}, txm.Stream(ctx, 3))
+	require.Equal(1, txm.Len(ctx))
+	next, ok := txm.PeekNext(ctx)
+	require.True(ok)
+	require.Equal(items[0], next)
+
+	// Limits are cleared once streaming finishes
+	txm.FinishStreaming(ctx, nil)
+	txm.StartStreaming(ctx)
+	require.Equal([]*TestItem{items[0]}, txm.Stream(ctx, 3))
+	txm.FinishStreaming(ctx, nil)
+}
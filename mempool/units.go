@@ -0,0 +1,176 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"math/bits"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/list"
+)
+
+// unitClasses is the number of classes items are grouped into in each
+// dimension of a [unitIndex] (one for each possible bit length of a uint64).
+const unitClasses = 65
+
+// Meter returns the most units [item] can consume in each dimension. It must
+// return the same number of dimensions for every item.
+type Meter[T Item] func(item T) ([]uint64, error)
+
+// entry is an item in a [unitIndex].
+type entry[T Item] struct {
+	item  T
+	units []uint64
+
+	// order increases from the front to the back of the queue holding [item]
+	order int64
+
+	// elems holds the element of [item] in each dimension of its [unitIndex]
+	elems []*list.Element[*entry[T]]
+}
+
+func (e *entry[T]) ID() ids.ID {
+	return e.item.ID()
+}
+
+func (e *entry[T]) Expiry() int64 {
+	return e.item.Expiry()
+}
+
+// unit returns the units [e] consumes in [dimension].
+func (e *entry[T]) unit(dimension int) uint64 {
+	if dimension >= len(e.units) {
+		return 0
+	}
+	return e.units[dimension]
+}
+
+// fits returns true if [e] consumes at most [limits] in each limited
+// dimension.
+func (e *entry[T]) fits(limits map[int]uint64) bool {
+	for dimension, limit := range limits {
+		if e.unit(dimension) > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// unitIndex groups the items of a queue by the units they consume in each
+// dimension, so that the items that fit under a limit can be found without
+// walking (or popping) those that don't.
+//
+// In each dimension, items are grouped by the bit length of their units and
+// kept in queue order within their group. All items in the groups below the
+// bit length of a limit fit under it, only some in the group of the limit do,
+// and none above it do.
+type unitIndex[T Item] struct {
+	classes [][unitClasses]*list.List[*entry[T]]
+}
+
+func unitClass(units uint64) int {
+	return bits.Len64(units)
+}
+
+func (u *unitIndex[T]) class(dimension int, class int) *list.List[*entry[T]] {
+	for len(u.classes) <= dimension {
+		u.classes = append(u.classes, [unitClasses]*list.List[*entry[T]]{})
+	}
+	l := u.classes[dimension][class]
+	if l == nil {
+		l = &list.List[*entry[T]]{}
+		u.classes[dimension][class] = l
+	}
+	return l
+}
+
+// add indexes [e] at the front or back of its queue.
+func (u *unitIndex[T]) add(e *entry[T], front bool) {
+	e.elems = make([]*list.Element[*entry[T]], len(e.units))
+	for dimension, units := range e.units {
+		l := u.class(dimension, unitClass(units))
+		if front {
+			e.elems[dimension] = l.PushFront(e)
+		} else {
+			e.elems[dimension] = l.PushBack(e)
+		}
+	}
+}
+
+// remove is a no-op if [e] is not in [u].
+func (u *unitIndex[T]) remove(e *entry[T]) {
+	for dimension, elem := range e.elems {
+		if dimension >= len(u.classes) {
+			return
+		}
+		if l := u.classes[dimension][unitClass(e.units[dimension])]; l != nil {
+			l.Remove(elem)
+		}
+	}
+}
+
+// fitting returns up to [count] items in [u] (in queue order) that fit under
+// [limits]. [limits] must not be empty.
+func (u *unitIndex[T]) fitting(limits map[int]uint64, count int) []*entry[T] {
+	if len(u.classes) == 0 {
+		return nil
+	}
+
+	// Walk the limited dimension with the fewest candidates (items don't
+	// consume dimensions they don't meter, so if none of those are limited
+	// every item is a candidate)
+	var (
+		dimension = 0
+		maxClass  = unitClasses - 1
+		best      = -1
+	)
+	for d, limit := range limits {
+		if d >= len(u.classes) {
+			continue
+		}
+		c := unitClass(limit)
+		candidates := 0
+		for class := 0; class <= c; class++ {
+			if l := u.classes[d][class]; l != nil {
+				candidates += l.Size()
+			}
+		}
+		if best < 0 || candidates < best {
+			dimension, maxClass, best = d, c, candidates
+		}
+	}
+	if best == 0 {
+		return nil
+	}
+
+	// Merge the candidates of each class in queue order
+	cursors := make([]*list.Element[*entry[T]], 0, maxClass+1)
+	for class := 0; class <= maxClass; class++ {
+		if l := u.classes[dimension][class]; l != nil && l.Size() > 0 {
+			cursors = append(cursors, l.First())
+		}
+	}
+	entries := make([]*entry[T], 0, count)
+	for len(entries) < count {
+		next := -1
+		for i, cursor := range cursors {
+			if cursor == nil {
+				continue
+			}
+			if next < 0 || cursor.Value().order < cursors[next].Value().order {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		e := cursors[next].Value()
+		cursors[next] = cursors[next].Next()
+		if e.fits(limits) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
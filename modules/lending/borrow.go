@@ -0,0 +1,155 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/modules/oracle"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Borrow)(nil)
+
+// Borrow deposits [Collateral] of [Config.CollateralAsset] into the position
+// of the actor and then borrows [Amount] of [Config.DebtAsset] against it
+// (either may be 0).
+//
+// The debt of the position after borrowing must be at most
+// [Config.CollateralFactor] of the value of its collateral. Depositing
+// collateral alone never requires a fresh price.
+type Borrow struct {
+	m *Module
+
+	Collateral uint64 `json:"collateral"`
+	Amount     uint64 `json:"amount"`
+}
+
+func (m *Module) NewBorrow(collateral uint64, amount uint64) *Borrow {
+	return &Borrow{m: m, Collateral: collateral, Amount: amount}
+}
+
+func (b *Borrow) GetTypeID() uint8 {
+	return b.m.c.BorrowID
+}
+
+func (b *Borrow) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(b.m.l.BalanceKey(actor, b.m.c.CollateralAsset)),
+		string(b.m.l.BalanceKey(actor, b.m.c.DebtAsset)),
+		string(b.m.marketKey()),
+		string(b.m.loanKey(actor)),
+		string(b.m.o.FeedKey(b.m.c.PriceFeed)),
+	}
+}
+
+func (b *Borrow) StateKeysMaxChunks() []uint16 {
+	return []uint16{b.m.l.BalanceChunks(), b.m.l.BalanceChunks(), MarketChunks, LoanChunks, oracle.FeedChunks}
+}
+
+func (*Borrow) OutputsWarpMessage() bool {
+	return false
+}
+
+func (b *Borrow) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := b.m.c.ComputeUnits
+	if b.Collateral == 0 && b.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	mkt, err := b.m.getMarket(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	l, err := b.m.getLoan(ctx, mu, mkt, actor)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if b.Collateral > 0 {
+		if l.Collateral, err = smath.Add64(l.Collateral, b.Collateral); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if err := b.m.l.SubBalance(ctx, mu, actor, b.m.c.CollateralAsset, b.Collateral); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if b.Amount > 0 {
+		if b.Amount > mkt.Cash {
+			return false, computeUnits, OutputInsufficientCash, nil, nil
+		}
+		scaled, err := scaledDebt(mkt.BorrowIndex, b.Amount, true)
+		if err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if l.ScaledDebt, err = smath.Add64(l.ScaledDebt, scaled); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if mkt.TotalScaledDebt, err = smath.Add64(mkt.TotalScaledDebt, scaled); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if l.Debt, err = debt(mkt.BorrowIndex, l.ScaledDebt); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		p, err := b.m.getPrice(ctx, mu, timestamp)
+		if err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if !withinFactor(l.Debt, l.Collateral, p, b.m.c.CollateralFactor) {
+			return false, computeUnits, OutputUndercollateralized, nil, nil
+		}
+		mkt.Cash -= b.Amount
+		if err := b.m.l.AddBalance(ctx, mu, actor, b.m.c.DebtAsset, b.Amount); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := b.m.setMarket(ctx, mu, mkt); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := b.m.setLoan(ctx, mu, actor, l); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (b *Borrow) MaxComputeUnits(chain.Rules) uint64 {
+	return b.m.c.ComputeUnits
+}
+
+func (*Borrow) Size() int {
+	return consts.Uint64Len * 2
+}
+
+func (b *Borrow) Marshal(p *codec.Packer) {
+	p.PackUint64(b.Collateral)
+	p.PackUint64(b.Amount)
+}
+
+func (m *Module) UnmarshalBorrow(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	borrow := Borrow{m: m}
+	borrow.Collateral = p.UnpackUint64(false)
+	borrow.Amount = p.UnpackUint64(false)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &borrow, nil
+}
+
+func (*Borrow) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import "errors"
+
+var (
+	ErrDuplicateTypeID       = errors.New("duplicate type ID")
+	ErrSameAssets            = errors.New("debt and collateral assets must differ")
+	ErrInvalidPriceFeed      = errors.New("invalid price feed")
+	ErrInvalidRiskParameters = errors.New("invalid risk parameters")
+	ErrCorruptRecord         = errors.New("corrupt record")
+	ErrInvalidPrice          = errors.New("invalid price")
+	ErrAmountOverflow        = errors.New("amount overflow")
+)
@@ -0,0 +1,180 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package lending is a reference implementation of a lending market that a
+// VM can register alongside its own actions.
+//
+// Lenders [Supply] [Config.DebtAsset] to the market in exchange for shares of
+// it and [Withdraw] it (with interest) by burning their shares. Borrowers
+// deposit [Config.CollateralAsset] and [Borrow] the debt asset against it, up
+// to [Config.CollateralFactor] of the value of their collateral, then [Repay]
+// it to get their collateral back.
+//
+// Collateral is priced by a feed of an [oracle.Module] (which its signers
+// should update with a time-weighted average price, so that a single trade
+// can't move it). Positions whose debt exceeds [Config.LiquidationThreshold]
+// of the value of their collateral can be repaid by anyone with [Liquidate],
+// who receives their collateral at a discount of [Config.LiquidationBonus].
+// There is no way to find these positions on-chain, so liquidators have to
+// track them (like with [Module.GetLoan]) and act when the price moves.
+//
+// At the end of each epoch (see [chain.Rules.GetEpochLength]), all debt
+// accrues [Config.InterestPerEpoch], which is earned by lenders in proportion
+// to their shares.
+package lending
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/modules/oracle"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.EpochHandler = (*Module)(nil)
+
+// Ledger moves the assets of a VM in and out of the market.
+type Ledger interface {
+	// BalanceKey returns the state key (including its chunk suffix) of the
+	// balance of [asset] of [addr].
+	BalanceKey(addr codec.Address, asset ids.ID) []byte
+	// BalanceChunks is the chunk suffix of all keys returned by [BalanceKey].
+	BalanceChunks() uint16
+
+	AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, asset ids.ID, amount uint64) error
+	SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, asset ids.ID, amount uint64) error
+}
+
+// Config parameterizes a [Module]. It must be identical on all nodes (and
+// clients) of a chain.
+type Config struct {
+	// Prefix is the first byte of all keys stored by the module. It must not
+	// be used by any other keys of the VM.
+	Prefix byte
+
+	// Type IDs of the actions of the module in the [chain.ActionRegistry].
+	SupplyID    uint8
+	WithdrawID  uint8
+	BorrowID    uint8
+	RepayID     uint8
+	LiquidateID uint8
+
+	// DebtAsset is supplied by lenders and borrowed against
+	// [CollateralAsset].
+	DebtAsset       ids.ID
+	CollateralAsset ids.ID
+
+	// PriceFeed is the oracle feed with the price of [CollateralAsset] in
+	// [DebtAsset] (see [EncodePrice]).
+	PriceFeed string
+
+	// CollateralFactor is the most debt (in basis points of the value of its
+	// collateral) a position can have after borrowing or withdrawing
+	// collateral.
+	CollateralFactor uint64
+	// LiquidationThreshold is the debt (in basis points of the value of its
+	// collateral) above which a position can be liquidated. It must be at
+	// least [CollateralFactor].
+	LiquidationThreshold uint64
+	// LiquidationBonus is the discount (in basis points) at which
+	// liquidators receive collateral.
+	LiquidationBonus uint64
+
+	// InterestPerEpoch is the interest (in basis points) accrued by all debt
+	// at the end of each epoch.
+	InterestPerEpoch uint64
+
+	// ComputeUnits are charged for each action of the module.
+	ComputeUnits uint64
+}
+
+func (c *Config) Verify() error {
+	if set.Of(c.SupplyID, c.WithdrawID, c.BorrowID, c.RepayID, c.LiquidateID).Len() != 5 {
+		return ErrDuplicateTypeID
+	}
+	if c.DebtAsset == c.CollateralAsset {
+		return ErrSameAssets
+	}
+	if len(c.PriceFeed) == 0 || len(c.PriceFeed) > oracle.MaxFeedLen {
+		return ErrInvalidPriceFeed
+	}
+	if c.CollateralFactor == 0 || c.CollateralFactor > c.LiquidationThreshold || c.LiquidationThreshold >= bps {
+		return ErrInvalidRiskParameters
+	}
+	if c.LiquidationBonus >= bps {
+		return ErrInvalidRiskParameters
+	}
+	return nil
+}
+
+// Module provides the actions and epoch handling of the market. The
+// [chain.EpochHandler] of the VM must call [Module.EndEpoch] (with the keys
+// from [Module.EpochStateKeys]) for interest to accrue.
+type Module struct {
+	c *Config
+	l Ledger
+	o *oracle.Module
+}
+
+// New returns a [Module] that prices collateral with the feeds of [o].
+func New(c *Config, l Ledger, o *oracle.Module) (*Module, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	return &Module{c, l, o}, nil
+}
+
+// Register adds the actions of the module to [registry]. Like any other
+// action, they must always be registered in the same order.
+func (m *Module) Register(registry *codec.TypeParser[chain.Action, *warp.Message, bool]) error {
+	for _, action := range []struct {
+		id        uint8
+		name      string
+		unmarshal func(*codec.Packer, *warp.Message) (chain.Action, error)
+	}{
+		{m.c.SupplyID, "supply", m.UnmarshalSupply},
+		{m.c.WithdrawID, "withdraw", m.UnmarshalWithdraw},
+		{m.c.BorrowID, "borrow", m.UnmarshalBorrow},
+		{m.c.RepayID, "repay", m.UnmarshalRepay},
+		{m.c.LiquidateID, "liquidate", m.UnmarshalLiquidate},
+	} {
+		if err := registry.Register(action.id, action.unmarshal, false); err != nil {
+			return err
+		}
+		if err := registry.SetName(action.id, action.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EpochStateKeys returns the keys accessed by [EndEpoch].
+func (m *Module) EpochStateKeys(uint64) []string {
+	return []string{string(m.marketKey())}
+}
+
+// EndEpoch accrues [Config.InterestPerEpoch] to all debt.
+func (m *Module) EndEpoch(ctx context.Context, _ chain.Rules, mu state.Mutable, _ int64, _ uint64) error {
+	if m.c.InterestPerEpoch == 0 {
+		return nil
+	}
+	mkt, err := m.getMarket(ctx, mu)
+	if err != nil {
+		return err
+	}
+	index := new(big.Int).Mul(mkt.BorrowIndex, big.NewInt(int64(bps+m.c.InterestPerEpoch)))
+	index.Quo(index, big.NewInt(bps))
+	if index.BitLen() > borrowIndexLen*8 {
+		// Failing here would halt the chain, so interest stops accruing
+		// instead (which would take longer than the chain will exist)
+		return nil
+	}
+	mkt.BorrowIndex = index
+	return m.setMarket(ctx, mu, mkt)
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/modules/oracle"
+)
+
+const testFeed = "COLL/DEBT"
+
+var (
+	debtAsset       = ids.GenerateTestID()
+	collateralAsset = ids.GenerateTestID()
+)
+
+// testModule returns a market where collateral can be borrowed against at
+// 50%, is liquidated at 80% (with a 10% bonus), and debt accrues 1% per
+// epoch.
+func testModule(t *testing.T) (*Module, *oracle.Module, codec.Address) {
+	signer := codec.CreateAddress(0, ids.GenerateTestID())
+	o, err := oracle.New(&oracle.Config{
+		Prefix:       0x1,
+		PostDataID:   0,
+		SetSignersID: 1,
+		Signers:      []codec.Address{signer},
+		MaxAge:       1_000,
+		ComputeUnits: 1,
+	})
+	require.NoError(t, err)
+	m, err := New(&Config{
+		Prefix:               0x2,
+		SupplyID:             2,
+		WithdrawID:           3,
+		BorrowID:             4,
+		RepayID:              5,
+		LiquidateID:          6,
+		DebtAsset:            debtAsset,
+		CollateralAsset:      collateralAsset,
+		PriceFeed:            testFeed,
+		CollateralFactor:     5_000,
+		LiquidationThreshold: 8_000,
+		LiquidationBonus:     1_000,
+		InterestPerEpoch:     100,
+		ComputeUnits:         1,
+	}, chaintest.AssetLedger{}, o)
+	require.NoError(t, err)
+	return m, o, signer
+}
+
+func TestConformance(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		m, o, sg = testModule(t)
+		l        = chaintest.AssetLedger{}
+		actor    = codec.CreateAddress(0, ids.GenerateTestID())
+		borrower = codec.CreateAddress(0, ids.GenerateTestID())
+		s        = chaintest.State{}
+	)
+	require.NoError(t, l.AddBalance(ctx, s, actor, debtAsset, 1_000))
+	require.NoError(t, l.AddBalance(ctx, s, actor, collateralAsset, 1_000))
+	require.NoError(t, l.AddBalance(ctx, s, borrower, collateralAsset, 1_000))
+	chaintest.Execute(t, s, o.NewPostData(testFeed, EncodePrice(2, 1), 0), 0, sg)
+	chaintest.Execute(t, s, m.NewSupply(500), 0, actor)
+	chaintest.Execute(t, s, m.NewBorrow(100, 100), 0, borrower)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "supply",
+			Action:          m.NewSupply(100),
+			Unmarshal:       m.UnmarshalSupply,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "withdraw",
+			Action:          m.NewWithdraw(100),
+			Unmarshal:       m.UnmarshalWithdraw,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "withdraw more than borrowed out",
+			Action:          m.NewWithdraw(500),
+			Unmarshal:       m.UnmarshalWithdraw,
+			ExpectedOutput:  OutputInsufficientCash,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "withdraw more than supplied",
+			Action:          m.NewWithdraw(501),
+			Unmarshal:       m.UnmarshalWithdraw,
+			ExpectedOutput:  OutputInsufficientShares,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "borrow",
+			Action:          m.NewBorrow(100, 100),
+			Unmarshal:       m.UnmarshalBorrow,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "borrow above collateral factor",
+			Action:          m.NewBorrow(100, 101),
+			Unmarshal:       m.UnmarshalBorrow,
+			ExpectedOutput:  OutputUndercollateralized,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "repay without debt",
+			Action:          m.NewRepay(10, 0),
+			Unmarshal:       m.UnmarshalRepay,
+			ExpectedOutput:  OutputNoDebt,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "liquidate healthy position",
+			Action:          m.NewLiquidate(borrower, 10),
+			Unmarshal:       m.UnmarshalLiquidate,
+			ExpectedOutput:  OutputNotLiquidatable,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.State = s
+		test.Actor = actor
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestInterestAndLiquidation(t *testing.T) {
+	var (
+		require    = require.New(t)
+		ctx        = context.Background()
+		m, o, sg   = testModule(t)
+		l          = chaintest.AssetLedger{}
+		lender     = codec.CreateAddress(0, ids.GenerateTestID())
+		borrower   = codec.CreateAddress(0, ids.GenerateTestID())
+		liquidator = codec.CreateAddress(0, ids.GenerateTestID())
+		s          = chaintest.State{}
+	)
+	require.NoError(l.AddBalance(ctx, s, lender, debtAsset, 10_000))
+	require.NoError(l.AddBalance(ctx, s, borrower, collateralAsset, 1_000))
+	require.NoError(l.AddBalance(ctx, s, liquidator, debtAsset, 10_000))
+
+	// Collateral is worth 2 of the debt asset, so 500 can be borrowed against
+	// 500 collateral
+	chaintest.Execute(t, s, o.NewPostData(testFeed, EncodePrice(2, 1), 0), 0, sg)
+	chaintest.Execute(t, s, m.NewSupply(1_000), 0, lender)
+	chaintest.Execute(t, s, m.NewBorrow(500, 500), 0, borrower)
+	success, _, output, _, err := m.NewBorrow(0, 1).Execute(ctx, nil, s, 0, borrower, ids.Empty, false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputUndercollateralized, output)
+
+	// Interest accrues to lenders at the end of the epoch
+	require.NoError(m.EndEpoch(ctx, nil, s, 0, 0))
+	loan, err := m.GetLoan(ctx, s, borrower)
+	require.NoError(err)
+	require.Equal(&Loan{Collateral: 500, ScaledDebt: 500, Debt: 505}, loan)
+	shares, value, err := m.GetShares(ctx, s, lender)
+	require.NoError(err)
+	require.Equal(uint64(1_000), shares)
+	require.Equal(uint64(1_005), value)
+
+	// Once the price halves, the position can be liquidated
+	chaintest.Execute(t, s, o.NewPostData(testFeed, EncodePrice(1, 1), 10), 10, sg)
+	chaintest.Execute(t, s, m.NewLiquidate(borrower, 200), 10, liquidator)
+	require.Equal(uint64(9_800), l.Balance(ctx, s, liquidator, debtAsset))
+	require.Equal(uint64(220), l.Balance(ctx, s, liquidator, collateralAsset))
+	loan, err = m.GetLoan(ctx, s, borrower)
+	require.NoError(err)
+	// Partial repayments round in favor of lenders
+	require.Equal(&Loan{Collateral: 280, ScaledDebt: 302, Debt: 306}, loan)
+
+	// Repaying all debt releases the remaining collateral
+	chaintest.Execute(t, s, m.NewRepay(1_000, 280), 10, borrower)
+	require.Equal(uint64(500-306), l.Balance(ctx, s, borrower, debtAsset))
+	require.Equal(uint64(500+280), l.Balance(ctx, s, borrower, collateralAsset))
+	loan, err = m.GetLoan(ctx, s, borrower)
+	require.NoError(err)
+	require.Equal(&Loan{}, loan)
+
+	// The lender withdraws the principal and all interest paid
+	chaintest.Execute(t, s, m.NewWithdraw(1_000), 10, lender)
+	require.Equal(uint64(9_000+1_006), l.Balance(ctx, s, lender, debtAsset))
+	mkt, err := m.GetMarket(ctx, s)
+	require.NoError(err)
+	require.Zero(mkt.Cash)
+	require.Zero(mkt.TotalShares)
+	require.Zero(mkt.TotalScaledDebt)
+}
+
+func TestRegister(t *testing.T) {
+	require := require.New(t)
+
+	m, o, _ := testModule(t)
+	registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	require.NoError(o.Register(registry))
+	require.NoError(m.Register(registry))
+	name, ok := registry.LookupName(m.NewLiquidate(codec.EmptyAddress, 1).GetTypeID())
+	require.True(ok)
+	require.Equal("liquidate", name)
+	require.ErrorIs(m.Register(registry), codec.ErrDuplicateItem)
+
+	_, err := New(&Config{SupplyID: 0, WithdrawID: 1, BorrowID: 2, RepayID: 3, LiquidateID: 4, PriceFeed: testFeed}, chaintest.AssetLedger{}, o)
+	require.ErrorIs(err, ErrSameAssets)
+}
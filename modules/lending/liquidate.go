@@ -0,0 +1,139 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/modules/oracle"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Liquidate)(nil)
+
+// Liquidate repays up to [Amount] of the debt of [Borrower] in exchange for
+// its collateral (at a discount of [Config.LiquidationBonus]).
+//
+// The position must have more debt than [Config.LiquidationThreshold] of the
+// value of its collateral.
+type Liquidate struct {
+	m *Module
+
+	Borrower codec.Address `json:"borrower"`
+	Amount   uint64        `json:"amount"`
+}
+
+func (m *Module) NewLiquidate(borrower codec.Address, amount uint64) *Liquidate {
+	return &Liquidate{m: m, Borrower: borrower, Amount: amount}
+}
+
+func (l *Liquidate) GetTypeID() uint8 {
+	return l.m.c.LiquidateID
+}
+
+func (l *Liquidate) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(l.m.l.BalanceKey(actor, l.m.c.CollateralAsset)),
+		string(l.m.l.BalanceKey(actor, l.m.c.DebtAsset)),
+		string(l.m.marketKey()),
+		string(l.m.loanKey(l.Borrower)),
+		string(l.m.o.FeedKey(l.m.c.PriceFeed)),
+	}
+}
+
+func (l *Liquidate) StateKeysMaxChunks() []uint16 {
+	return []uint16{l.m.l.BalanceChunks(), l.m.l.BalanceChunks(), MarketChunks, LoanChunks, oracle.FeedChunks}
+}
+
+func (*Liquidate) OutputsWarpMessage() bool {
+	return false
+}
+
+func (l *Liquidate) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := l.m.c.ComputeUnits
+	if l.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	mkt, err := l.m.getMarket(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	loan, err := l.m.getLoan(ctx, mu, mkt, l.Borrower)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if loan.Debt == 0 {
+		return false, computeUnits, OutputNoDebt, nil, nil
+	}
+	p, err := l.m.getPrice(ctx, mu, timestamp)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if withinFactor(loan.Debt, loan.Collateral, p, l.m.c.LiquidationThreshold) {
+		return false, computeUnits, OutputNotLiquidatable, nil, nil
+	}
+	paid, err := repay(mkt, loan, l.Amount)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	seized := seizable(paid, p, l.m.c.LiquidationBonus, loan.Collateral)
+	loan.Collateral -= seized
+	if err := l.m.l.SubBalance(ctx, mu, actor, l.m.c.DebtAsset, paid); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if seized > 0 {
+		if err := l.m.l.AddBalance(ctx, mu, actor, l.m.c.CollateralAsset, seized); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := l.m.setMarket(ctx, mu, mkt); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := l.m.setLoan(ctx, mu, l.Borrower, loan); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (l *Liquidate) MaxComputeUnits(chain.Rules) uint64 {
+	return l.m.c.ComputeUnits
+}
+
+func (*Liquidate) Size() int {
+	return codec.AddressLen + consts.Uint64Len
+}
+
+func (l *Liquidate) Marshal(p *codec.Packer) {
+	p.PackAddress(l.Borrower)
+	p.PackUint64(l.Amount)
+}
+
+func (m *Module) UnmarshalLiquidate(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	liquidate := Liquidate{m: m}
+	p.UnpackAddress(&liquidate.Borrower)
+	liquidate.Amount = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &liquidate, nil
+}
+
+func (*Liquidate) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// bps is the denominator of all parameters in basis points.
+const bps = 10_000
+
+// indexScale is the precision of [Market.BorrowIndex].
+var indexScale = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// mulDiv returns [a]*[b]/[d] (rounded up if [roundUp] is set) or
+// [ErrAmountOverflow] if it doesn't fit in a uint64.
+func mulDiv(a *big.Int, b *big.Int, d *big.Int, roundUp bool) (uint64, error) {
+	n := new(big.Int).Mul(a, b)
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if roundUp && r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsUint64() {
+		return 0, ErrAmountOverflow
+	}
+	return q.Uint64(), nil
+}
+
+func u(v uint64) *big.Int {
+	return new(big.Int).SetUint64(v)
+}
+
+// debt returns the debt owed for [scaledDebt] at [index] (rounded up, in
+// favor of lenders).
+func debt(index *big.Int, scaledDebt uint64) (uint64, error) {
+	return mulDiv(u(scaledDebt), index, indexScale, true)
+}
+
+// scaledDebt returns the scaled debt of [amount] at [index]. Borrows round up
+// and repayments round down (in favor of lenders).
+func scaledDebt(index *big.Int, amount uint64, roundUp bool) (uint64, error) {
+	return mulDiv(u(amount), indexScale, index, roundUp)
+}
+
+// assets returns the debt asset held by [mkt], both in cash and lent out.
+func assets(mkt *Market) (*big.Int, error) {
+	total, err := debt(mkt.BorrowIndex, mkt.TotalScaledDebt)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(u(mkt.Cash), u(total)), nil
+}
+
+// sharesValue returns the amount of the debt asset that [shares] of [mkt]
+// are worth (rounded down).
+func sharesValue(mkt *Market, shares uint64) (uint64, error) {
+	if mkt.TotalShares == 0 {
+		return 0, nil
+	}
+	a, err := assets(mkt)
+	if err != nil {
+		return 0, err
+	}
+	return mulDiv(u(shares), a, u(mkt.TotalShares), false)
+}
+
+// sharesFor returns the shares of [mkt] that [amount] of the debt asset buys
+// (rounded down).
+func sharesFor(mkt *Market, amount uint64) (uint64, error) {
+	a, err := assets(mkt)
+	if err != nil {
+		return 0, err
+	}
+	if mkt.TotalShares == 0 || a.Sign() == 0 {
+		return amount, nil
+	}
+	return mulDiv(u(amount), u(mkt.TotalShares), a, false)
+}
+
+// price is the price of the collateral asset: [Debt] of the debt asset are
+// worth [Collateral] of the collateral asset.
+type price struct {
+	Debt       uint64
+	Collateral uint64
+}
+
+// EncodePrice returns the data that oracle signers post to
+// [Config.PriceFeed] when [debt] of the debt asset are worth [collateral] of
+// the collateral asset.
+func EncodePrice(debt uint64, collateral uint64) []byte {
+	v := binary.BigEndian.AppendUint64(nil, debt)
+	return binary.BigEndian.AppendUint64(v, collateral)
+}
+
+// getPrice returns the price of [Config.PriceFeed] if it is fresh at
+// [timestamp].
+func (m *Module) getPrice(ctx context.Context, im state.Immutable, timestamp int64) (*price, error) {
+	f, err := m.o.GetFreshFeed(ctx, im, timestamp, m.c.PriceFeed)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Data) != consts.Uint64Len*2 {
+		return nil, ErrInvalidPrice
+	}
+	p := &price{
+		Debt:       binary.BigEndian.Uint64(f.Data),
+		Collateral: binary.BigEndian.Uint64(f.Data[consts.Uint64Len:]),
+	}
+	if p.Debt == 0 || p.Collateral == 0 {
+		return nil, ErrInvalidPrice
+	}
+	return p, nil
+}
+
+// withinFactor returns true if [debt] is at most [factor] (in basis points) of
+// the value of [collateral] at [p].
+func withinFactor(debt uint64, collateral uint64, p *price, factor uint64) bool {
+	// debt <= collateral*(p.Debt/p.Collateral)*(factor/bps)
+	lhs := new(big.Int).Mul(u(debt), u(bps))
+	lhs.Mul(lhs, u(p.Collateral))
+	rhs := new(big.Int).Mul(u(collateral), u(p.Debt))
+	rhs.Mul(rhs, u(factor))
+	return lhs.Cmp(rhs) <= 0
+}
+
+// seizable returns the collateral a liquidator receives for repaying [repay]
+// of debt at [p] (with a discount of [bonus] basis points), capped at
+// [collateral].
+func seizable(repay uint64, p *price, bonus uint64, collateral uint64) uint64 {
+	n := new(big.Int).Mul(u(repay), u(bps+bonus))
+	n.Mul(n, u(p.Collateral))
+	d := new(big.Int).Mul(u(p.Debt), u(bps))
+	seized, err := mulDiv(n, big.NewInt(1), d, false)
+	if err != nil || seized > collateral {
+		return collateral
+	}
+	return seized
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+var (
+	OutputValueZero              = []byte("value is zero")
+	OutputAmountTooSmall         = []byte("amount is too small")
+	OutputInsufficientShares     = []byte("insufficient shares")
+	OutputInsufficientCash       = []byte("insufficient cash in market")
+	OutputInsufficientCollateral = []byte("insufficient collateral")
+	OutputUndercollateralized    = []byte("position would be undercollateralized")
+	OutputNoDebt                 = []byte("position has no debt")
+	OutputNotLiquidatable        = []byte("position is not liquidatable")
+)
@@ -0,0 +1,177 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/modules/oracle"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Repay)(nil)
+
+// Repay pays back up to [Amount] of the debt of the position of the actor
+// and then withdraws [Collateral] from it (either may be 0).
+//
+// If the position still has debt, it must be at most
+// [Config.CollateralFactor] of the value of the remaining collateral.
+type Repay struct {
+	m *Module
+
+	Amount     uint64 `json:"amount"`
+	Collateral uint64 `json:"collateral"`
+}
+
+func (m *Module) NewRepay(amount uint64, collateral uint64) *Repay {
+	return &Repay{m: m, Amount: amount, Collateral: collateral}
+}
+
+func (r *Repay) GetTypeID() uint8 {
+	return r.m.c.RepayID
+}
+
+func (r *Repay) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(r.m.l.BalanceKey(actor, r.m.c.CollateralAsset)),
+		string(r.m.l.BalanceKey(actor, r.m.c.DebtAsset)),
+		string(r.m.marketKey()),
+		string(r.m.loanKey(actor)),
+		string(r.m.o.FeedKey(r.m.c.PriceFeed)),
+	}
+}
+
+func (r *Repay) StateKeysMaxChunks() []uint16 {
+	return []uint16{r.m.l.BalanceChunks(), r.m.l.BalanceChunks(), MarketChunks, LoanChunks, oracle.FeedChunks}
+}
+
+func (*Repay) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *Repay) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := r.m.c.ComputeUnits
+	if r.Amount == 0 && r.Collateral == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	mkt, err := r.m.getMarket(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	l, err := r.m.getLoan(ctx, mu, mkt, actor)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if r.Amount > 0 {
+		if l.Debt == 0 {
+			return false, computeUnits, OutputNoDebt, nil, nil
+		}
+		paid, err := repay(mkt, l, r.Amount)
+		if err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		if err := r.m.l.SubBalance(ctx, mu, actor, r.m.c.DebtAsset, paid); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if r.Collateral > 0 {
+		if r.Collateral > l.Collateral {
+			return false, computeUnits, OutputInsufficientCollateral, nil, nil
+		}
+		l.Collateral -= r.Collateral
+		if l.Debt > 0 {
+			p, err := r.m.getPrice(ctx, mu, timestamp)
+			if err != nil {
+				return false, computeUnits, nil, nil, chain.Fail(err)
+			}
+			if !withinFactor(l.Debt, l.Collateral, p, r.m.c.CollateralFactor) {
+				return false, computeUnits, OutputUndercollateralized, nil, nil
+			}
+		}
+		if err := r.m.l.AddBalance(ctx, mu, actor, r.m.c.CollateralAsset, r.Collateral); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := r.m.setMarket(ctx, mu, mkt); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := r.m.setLoan(ctx, mu, actor, l); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (r *Repay) MaxComputeUnits(chain.Rules) uint64 {
+	return r.m.c.ComputeUnits
+}
+
+func (*Repay) Size() int {
+	return consts.Uint64Len * 2
+}
+
+func (r *Repay) Marshal(p *codec.Packer) {
+	p.PackUint64(r.Amount)
+	p.PackUint64(r.Collateral)
+}
+
+func (m *Module) UnmarshalRepay(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	repay := Repay{m: m}
+	repay.Amount = p.UnpackUint64(false)
+	repay.Collateral = p.UnpackUint64(false)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &repay, nil
+}
+
+func (*Repay) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// repay pays back up to [amount] of the debt of [l] to [mkt] and returns how
+// much was paid (the caller must collect it).
+func repay(mkt *Market, l *Loan, amount uint64) (uint64, error) {
+	var (
+		paid   = amount
+		scaled = l.ScaledDebt
+	)
+	if paid < l.Debt {
+		var err error
+		scaled, err = scaledDebt(mkt.BorrowIndex, paid, false)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		paid = l.Debt
+	}
+	cash, err := smath.Add64(mkt.Cash, paid)
+	if err != nil {
+		return 0, err
+	}
+	// Partial repayments round down, so [scaled] is at most [l.ScaledDebt]
+	// (which is part of [mkt.TotalScaledDebt])
+	mkt.Cash = cash
+	mkt.TotalScaledDebt -= scaled
+	l.ScaledDebt -= scaled
+	if l.Debt, err = debt(mkt.BorrowIndex, l.ScaledDebt); err != nil {
+		return 0, err
+	}
+	return paid, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// State
+// [Prefix]/0x0 (market) => cash|totalShares|totalScaledDebt|borrowIndex
+// [Prefix]/0x1 (supply)
+//   -> [lender] => shares
+// [Prefix]/0x2 (loan)
+//   -> [borrower] => collateral|scaledDebt
+
+const (
+	marketPrefix = 0x0
+	supplyPrefix = 0x1
+	loanPrefix   = 0x2
+
+	MarketChunks uint16 = 1
+	SupplyChunks uint16 = 1
+	LoanChunks   uint16 = 1
+
+	// borrowIndexLen is the length of the encoding of [Market.BorrowIndex].
+	borrowIndexLen = 32
+
+	marketLen = consts.Uint64Len*3 + borrowIndexLen
+	supplyLen = consts.Uint64Len
+	loanLen   = consts.Uint64Len * 2
+)
+
+// Market tracks the assets lent by the market.
+type Market struct {
+	// Cash is the amount of the debt asset supplied but not borrowed.
+	Cash        uint64 `json:"cash"`
+	TotalShares uint64 `json:"totalShares"`
+	// TotalScaledDebt is the sum of [Loan.ScaledDebt] of all positions.
+	TotalScaledDebt uint64 `json:"totalScaledDebt"`
+	// BorrowIndex is the debt owed for each unit of scaled debt (scaled by
+	// [indexScale]). It starts at 1 and grows as interest accrues.
+	BorrowIndex *big.Int `json:"borrowIndex"`
+}
+
+// Loan is the position of a borrower.
+type Loan struct {
+	Collateral uint64 `json:"collateral"`
+	// ScaledDebt is the debt of the position divided by the
+	// [Market.BorrowIndex] at the time it was borrowed, so that it accrues
+	// interest without being updated.
+	ScaledDebt uint64 `json:"scaledDebt"`
+	// Debt is the amount of the debt asset that repays the position. It is
+	// computed from [ScaledDebt] (and not stored).
+	Debt uint64 `json:"debt"`
+}
+
+func (l *Loan) empty() bool {
+	return l.Collateral == 0 && l.ScaledDebt == 0
+}
+
+func (m *Module) marketKey() []byte {
+	return keys.EncodeChunks([]byte{m.c.Prefix, marketPrefix}, MarketChunks)
+}
+
+func (m *Module) supplyKey(lender codec.Address) []byte {
+	k := make([]byte, 0, 2+codec.AddressLen)
+	k = append(k, m.c.Prefix, supplyPrefix)
+	k = append(k, lender[:]...)
+	return keys.EncodeChunks(k, SupplyChunks)
+}
+
+func (m *Module) loanKey(borrower codec.Address) []byte {
+	k := make([]byte, 0, 2+codec.AddressLen)
+	k = append(k, m.c.Prefix, loanPrefix)
+	k = append(k, borrower[:]...)
+	return keys.EncodeChunks(k, LoanChunks)
+}
+
+// GetMarket returns the [Market] of the module.
+func (m *Module) GetMarket(ctx context.Context, im state.Immutable) (*Market, error) {
+	return m.getMarket(ctx, im)
+}
+
+// GetShares returns the shares of the market owned by [lender] and the
+// amount of the debt asset they are worth.
+func (m *Module) GetShares(ctx context.Context, im state.Immutable, lender codec.Address) (uint64, uint64, error) {
+	mkt, err := m.getMarket(ctx, im)
+	if err != nil {
+		return 0, 0, err
+	}
+	shares, err := m.getShares(ctx, im, lender)
+	if err != nil {
+		return 0, 0, err
+	}
+	value, err := sharesValue(mkt, shares)
+	if err != nil {
+		return 0, 0, err
+	}
+	return shares, value, nil
+}
+
+// GetLoan returns the [Loan] of [borrower] (with its [Loan.Debt] at the
+// current [Market.BorrowIndex]).
+func (m *Module) GetLoan(ctx context.Context, im state.Immutable, borrower codec.Address) (*Loan, error) {
+	mkt, err := m.getMarket(ctx, im)
+	if err != nil {
+		return nil, err
+	}
+	return m.getLoan(ctx, im, mkt, borrower)
+}
+
+func (m *Module) getMarket(ctx context.Context, im state.Immutable) (*Market, error) {
+	v, err := im.GetValue(ctx, m.marketKey())
+	if errors.Is(err, database.ErrNotFound) {
+		return &Market{BorrowIndex: new(big.Int).Set(indexScale)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != marketLen {
+		return nil, ErrCorruptRecord
+	}
+	return &Market{
+		Cash:            binary.BigEndian.Uint64(v),
+		TotalShares:     binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+		TotalScaledDebt: binary.BigEndian.Uint64(v[consts.Uint64Len*2:]),
+		BorrowIndex:     new(big.Int).SetBytes(v[consts.Uint64Len*3:]),
+	}, nil
+}
+
+func (m *Module) setMarket(ctx context.Context, mu state.Mutable, mkt *Market) error {
+	if mkt.BorrowIndex.BitLen() > borrowIndexLen*8 {
+		return ErrAmountOverflow
+	}
+	v := make([]byte, marketLen)
+	binary.BigEndian.PutUint64(v, mkt.Cash)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], mkt.TotalShares)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2:], mkt.TotalScaledDebt)
+	mkt.BorrowIndex.FillBytes(v[consts.Uint64Len*3:])
+	return mu.Insert(ctx, m.marketKey(), v)
+}
+
+func (m *Module) getShares(ctx context.Context, im state.Immutable, lender codec.Address) (uint64, error) {
+	v, err := im.GetValue(ctx, m.supplyKey(lender))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(v) != supplyLen {
+		return 0, ErrCorruptRecord
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (m *Module) setShares(ctx context.Context, mu state.Mutable, lender codec.Address, shares uint64) error {
+	k := m.supplyKey(lender)
+	if shares == 0 {
+		return mu.Remove(ctx, k)
+	}
+	return mu.Insert(ctx, k, binary.BigEndian.AppendUint64(nil, shares))
+}
+
+func (m *Module) getLoan(ctx context.Context, im state.Immutable, mkt *Market, borrower codec.Address) (*Loan, error) {
+	v, err := im.GetValue(ctx, m.loanKey(borrower))
+	if errors.Is(err, database.ErrNotFound) {
+		return &Loan{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != loanLen {
+		return nil, ErrCorruptRecord
+	}
+	l := &Loan{
+		Collateral: binary.BigEndian.Uint64(v),
+		ScaledDebt: binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+	}
+	if l.Debt, err = debt(mkt.BorrowIndex, l.ScaledDebt); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (m *Module) setLoan(ctx context.Context, mu state.Mutable, borrower codec.Address, l *Loan) error {
+	k := m.loanKey(borrower)
+	if l.empty() {
+		return mu.Remove(ctx, k)
+	}
+	v := make([]byte, loanLen)
+	binary.BigEndian.PutUint64(v, l.Collateral)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], l.ScaledDebt)
+	return mu.Insert(ctx, k, v)
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Supply)(nil)
+
+// Supply lends [Amount] of [Config.DebtAsset] to the market in exchange for
+// shares of it.
+type Supply struct {
+	m *Module
+
+	Amount uint64 `json:"amount"`
+}
+
+func (m *Module) NewSupply(amount uint64) *Supply {
+	return &Supply{m: m, Amount: amount}
+}
+
+func (s *Supply) GetTypeID() uint8 {
+	return s.m.c.SupplyID
+}
+
+func (s *Supply) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(s.m.l.BalanceKey(actor, s.m.c.DebtAsset)),
+		string(s.m.marketKey()),
+		string(s.m.supplyKey(actor)),
+	}
+}
+
+func (s *Supply) StateKeysMaxChunks() []uint16 {
+	return []uint16{s.m.l.BalanceChunks(), MarketChunks, SupplyChunks}
+}
+
+func (*Supply) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *Supply) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := s.m.c.ComputeUnits
+	if s.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	mkt, err := s.m.getMarket(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	minted, err := sharesFor(mkt, s.Amount)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if minted == 0 {
+		return false, computeUnits, OutputAmountTooSmall, nil, nil
+	}
+	shares, err := s.m.getShares(ctx, mu, actor)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if mkt.Cash, err = smath.Add64(mkt.Cash, s.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if mkt.TotalShares, err = smath.Add64(mkt.TotalShares, minted); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := s.m.l.SubBalance(ctx, mu, actor, s.m.c.DebtAsset, s.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := s.m.setMarket(ctx, mu, mkt); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	// [shares] can't overflow because it is at most [mkt.TotalShares]
+	if err := s.m.setShares(ctx, mu, actor, shares+minted); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (s *Supply) MaxComputeUnits(chain.Rules) uint64 {
+	return s.m.c.ComputeUnits
+}
+
+func (*Supply) Size() int {
+	return consts.Uint64Len
+}
+
+func (s *Supply) Marshal(p *codec.Packer) {
+	p.PackUint64(s.Amount)
+}
+
+func (m *Module) UnmarshalSupply(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	supply := Supply{m: m}
+	supply.Amount = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &supply, nil
+}
+
+func (*Supply) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lending
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Withdraw)(nil)
+
+// Withdraw burns [Shares] of the market in exchange for the amount of
+// [Config.DebtAsset] they are worth. It fails if too much of the market is
+// borrowed to pay them out.
+type Withdraw struct {
+	m *Module
+
+	Shares uint64 `json:"shares"`
+}
+
+func (m *Module) NewWithdraw(shares uint64) *Withdraw {
+	return &Withdraw{m: m, Shares: shares}
+}
+
+func (w *Withdraw) GetTypeID() uint8 {
+	return w.m.c.WithdrawID
+}
+
+func (w *Withdraw) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(w.m.l.BalanceKey(actor, w.m.c.DebtAsset)),
+		string(w.m.marketKey()),
+		string(w.m.supplyKey(actor)),
+	}
+}
+
+func (w *Withdraw) StateKeysMaxChunks() []uint16 {
+	return []uint16{w.m.l.BalanceChunks(), MarketChunks, SupplyChunks}
+}
+
+func (*Withdraw) OutputsWarpMessage() bool {
+	return false
+}
+
+func (w *Withdraw) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := w.m.c.ComputeUnits
+	if w.Shares == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	shares, err := w.m.getShares(ctx, mu, actor)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if shares < w.Shares {
+		return false, computeUnits, OutputInsufficientShares, nil, nil
+	}
+	mkt, err := w.m.getMarket(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	amount, err := sharesValue(mkt, w.Shares)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if amount > mkt.Cash {
+		return false, computeUnits, OutputInsufficientCash, nil, nil
+	}
+	mkt.Cash -= amount
+	mkt.TotalShares -= w.Shares
+	if err := w.m.setMarket(ctx, mu, mkt); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := w.m.setShares(ctx, mu, actor, shares-w.Shares); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if amount > 0 {
+		if err := w.m.l.AddBalance(ctx, mu, actor, w.m.c.DebtAsset, amount); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (w *Withdraw) MaxComputeUnits(chain.Rules) uint64 {
+	return w.m.c.ComputeUnits
+}
+
+func (*Withdraw) Size() int {
+	return consts.Uint64Len
+}
+
+func (w *Withdraw) Marshal(p *codec.Packer) {
+	p.PackUint64(w.Shares)
+}
+
+func (m *Module) UnmarshalWithdraw(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	withdraw := Withdraw{m: m}
+	withdraw.Shares = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &withdraw, nil
+}
+
+func (*Withdraw) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
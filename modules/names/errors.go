@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import "errors"
+
+var (
+	ErrDuplicateTypeID = errors.New("duplicate type ID")
+	ErrInvalidPeriod   = errors.New("period must be positive")
+	ErrInvalidName     = errors.New("invalid name")
+	ErrCorruptRecord   = errors.New("corrupt record")
+)
@@ -0,0 +1,132 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package names is a reference implementation of a name service (mapping
+// human-readable names to addresses) that a VM can register alongside its own
+// actions.
+//
+// Anyone can claim a name that is not registered (or has expired) with
+// [RegisterName], which makes them its owner for [Config.Period] in exchange
+// for [Config.Fee] (which is burned). The owner can extend that by another
+// [Config.Period] (for another [Config.Fee]) by registering it again before it
+// expires. Until then, the owner can change the address the name resolves to
+// with [UpdateRecord] and hand the name to someone else with [TransferName].
+//
+// Clients resolve names with [Resolve] once the VM serves
+// [Module.RegisterStateQueries]. Passing it to [cli.Handler.SetNameResolver]
+// lets a cli accept names wherever it prompts for an address.
+package names
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	// MinNameLen is the shortest allowed name.
+	MinNameLen = 3
+	// MaxNameLen is the longest allowed name.
+	MaxNameLen = 32
+)
+
+// Ledger charges registration fees in the native token of a VM.
+type Ledger interface {
+	// BalanceKey returns the state key (including its chunk suffix) of the
+	// balance of [addr].
+	BalanceKey(addr codec.Address) []byte
+	// BalanceChunks is the chunk suffix of all keys returned by [BalanceKey].
+	BalanceChunks() uint16
+
+	SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error
+}
+
+// Config parameterizes a [Module]. It must be identical on all nodes (and
+// clients) of a chain.
+type Config struct {
+	// Prefix is the first byte of all keys stored by the module. It must not
+	// be used by any other keys of the VM.
+	Prefix byte
+
+	// Type IDs of the actions of the module in the [chain.ActionRegistry].
+	RegisterNameID uint8
+	UpdateRecordID uint8
+	TransferNameID uint8
+
+	// Period is how long (in milliseconds) a registration or renewal lasts.
+	Period int64
+	// Fee is burned for each registration or renewal.
+	Fee uint64
+
+	// ComputeUnits are charged for each action of the module.
+	ComputeUnits uint64
+}
+
+func (c *Config) Verify() error {
+	if set.Of(c.RegisterNameID, c.UpdateRecordID, c.TransferNameID).Len() != 3 {
+		return ErrDuplicateTypeID
+	}
+	if c.Period <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Module provides the actions and records of the name service.
+type Module struct {
+	c *Config
+	l Ledger
+}
+
+func New(c *Config, l Ledger) (*Module, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	return &Module{c, l}, nil
+}
+
+// Register adds the actions of the module to [registry]. Like any other
+// action, they must always be registered in the same order.
+func (m *Module) Register(registry *codec.TypeParser[chain.Action, *warp.Message, bool]) error {
+	for _, action := range []struct {
+		id        uint8
+		name      string
+		unmarshal func(*codec.Packer, *warp.Message) (chain.Action, error)
+	}{
+		{m.c.RegisterNameID, "registerName", m.UnmarshalRegisterName},
+		{m.c.UpdateRecordID, "updateRecord", m.UnmarshalUpdateRecord},
+		{m.c.TransferNameID, "transferName", m.UnmarshalTransferName},
+	} {
+		if err := registry.Register(action.id, action.unmarshal, false); err != nil {
+			return err
+		}
+		if err := registry.SetName(action.id, action.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidName returns true if [name] can be registered: it must be between
+// [MinNameLen] and [MaxNameLen] lowercase letters, digits, and hyphens and
+// can't start or end with a hyphen.
+func ValidName(name string) bool {
+	if len(name) < MinNameLen || len(name) > MaxNameLen {
+		return false
+	}
+	if name[0] == '-' || name[len(name)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+// testModule returns a name service where registrations last 1000ms and cost
+// 10.
+func testModule(t *testing.T) *Module {
+	m, err := New(&Config{
+		Prefix:         0x1,
+		RegisterNameID: 0,
+		UpdateRecordID: 1,
+		TransferNameID: 2,
+		Period:         1_000,
+		Fee:            10,
+		ComputeUnits:   1,
+	}, chaintest.Ledger{})
+	require.NoError(t, err)
+	return m
+}
+
+func fund(t *testing.T, s chaintest.State, addr codec.Address, amount uint64) {
+	require.NoError(t, s.Insert(context.Background(), chaintest.Ledger{}.BalanceKey(addr), binary.BigEndian.AppendUint64(nil, amount)))
+}
+
+func TestConformance(t *testing.T) {
+	var (
+		m     = testModule(t)
+		owner = codec.CreateAddress(0, ids.GenerateTestID())
+		other = codec.CreateAddress(0, ids.GenerateTestID())
+		s     = chaintest.State{}
+	)
+	fund(t, s, owner, 100)
+	fund(t, s, other, 100)
+	chaintest.Execute(t, s, m.NewRegisterName("alice", owner), 0, owner)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "register",
+			Action:          m.NewRegisterName("bob", owner),
+			Unmarshal:       m.UnmarshalRegisterName,
+			Actor:           owner,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "register invalid name",
+			Action:          m.NewRegisterName("Bob", owner),
+			Unmarshal:       m.UnmarshalRegisterName,
+			Actor:           owner,
+			ExpectedOutput:  OutputInvalidName,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "register taken name",
+			Action:          m.NewRegisterName("alice", other),
+			Unmarshal:       m.UnmarshalRegisterName,
+			Actor:           other,
+			ExpectedOutput:  OutputNameTaken,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "register expired name",
+			Action:          m.NewRegisterName("alice", other),
+			Unmarshal:       m.UnmarshalRegisterName,
+			Actor:           other,
+			Timestamp:       1_000,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "register without fee",
+			Action:          m.NewRegisterName("bob", owner),
+			Unmarshal:       m.UnmarshalRegisterName,
+			Actor:           codec.CreateAddress(0, ids.GenerateTestID()),
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "update",
+			Action:          m.NewUpdateRecord("alice", other),
+			Unmarshal:       m.UnmarshalUpdateRecord,
+			Actor:           owner,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "update not owner",
+			Action:          m.NewUpdateRecord("alice", other),
+			Unmarshal:       m.UnmarshalUpdateRecord,
+			Actor:           other,
+			ExpectedOutput:  OutputNotOwner,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "update expired",
+			Action:          m.NewUpdateRecord("alice", other),
+			Unmarshal:       m.UnmarshalUpdateRecord,
+			Actor:           owner,
+			Timestamp:       1_000,
+			ExpectedOutput:  OutputNameExpired,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "update missing",
+			Action:          m.NewUpdateRecord("bob", other),
+			Unmarshal:       m.UnmarshalUpdateRecord,
+			Actor:           owner,
+			ExpectedOutput:  OutputNameMissing,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "transfer",
+			Action:          m.NewTransferName("alice", other),
+			Unmarshal:       m.UnmarshalTransferName,
+			Actor:           owner,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "transfer not owner",
+			Action:          m.NewTransferName("alice", other),
+			Unmarshal:       m.UnmarshalTransferName,
+			Actor:           other,
+			ExpectedOutput:  OutputNotOwner,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.State = s
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestRenewAndTransfer(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+		m       = testModule(t)
+		l       = chaintest.Ledger{}
+		owner   = codec.CreateAddress(0, ids.GenerateTestID())
+		buyer   = codec.CreateAddress(0, ids.GenerateTestID())
+		s       = chaintest.State{}
+	)
+	fund(t, s, owner, 100)
+	fund(t, s, buyer, 100)
+
+	// Renewing before expiry extends the registration from its expiry
+	chaintest.Execute(t, s, m.NewRegisterName("alice", owner), 100, owner)
+	chaintest.Execute(t, s, m.NewRegisterName("alice", owner), 500, owner)
+	require.Equal(uint64(80), l.Balance(ctx, s, owner))
+	r, err := m.GetRecord(ctx, s, "alice")
+	require.NoError(err)
+	require.Equal(&Record{Owner: owner, Address: owner, Expiry: 2_100}, r)
+
+	// The new owner controls the name but it still resolves to the old one
+	// until updated
+	chaintest.Execute(t, s, m.NewTransferName("alice", buyer), 600, owner)
+	addr, ok, err := m.Resolve(ctx, s, 600, "alice")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(owner, addr)
+	chaintest.Execute(t, s, m.NewUpdateRecord("alice", buyer), 700, buyer)
+	addr, ok, err = m.Resolve(ctx, s, 700, "alice")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(buyer, addr)
+
+	// Once expired, the name doesn't resolve and anyone can claim it
+	_, ok, err = m.Resolve(ctx, s, 2_100, "alice")
+	require.NoError(err)
+	require.False(ok)
+	chaintest.Execute(t, s, m.NewRegisterName("alice", owner), 2_100, owner)
+	r, err = m.GetRecord(ctx, s, "alice")
+	require.NoError(err)
+	require.Equal(&Record{Owner: owner, Address: owner, Expiry: 3_100}, r)
+}
+
+func TestValidName(t *testing.T) {
+	require := require.New(t)
+
+	for _, name := range []string{"abc", "alice-1", "0x00", "abcdefghijklmnopqrstuvwxyz012345"} {
+		require.True(ValidName(name), name)
+	}
+	for _, name := range []string{"", "ab", "Alice", "-alice", "alice-", "al ice", "al.ice", "abcdefghijklmnopqrstuvwxyz0123456"} {
+		require.False(ValidName(name), name)
+	}
+}
+
+func TestStateQuery(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+		m       = testModule(t)
+		owner   = codec.CreateAddress(0, ids.GenerateTestID())
+		s       = chaintest.State{}
+		r       = rpc.NewStateRegistry()
+	)
+	fund(t, s, owner, 100)
+	chaintest.Execute(t, s, m.NewRegisterName("alice", owner), 0, owner)
+	require.NoError(m.RegisterStateQueries(r))
+
+	q, ok := r.Lookup(StateQuery)
+	require.True(ok)
+	_, err := q.Key("Alice")
+	require.ErrorIs(err, ErrInvalidName)
+	k, err := q.Key("alice")
+	require.NoError(err)
+	v, err := s.GetValue(ctx, k)
+	require.NoError(err)
+	record, err := q.Decode(v)
+	require.NoError(err)
+	require.Equal(&Record{Owner: owner, Address: owner, Expiry: 1_000}, record)
+}
+
+func TestRegister(t *testing.T) {
+	require := require.New(t)
+
+	m := testModule(t)
+	registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	require.NoError(m.Register(registry))
+	name, ok := registry.LookupName(m.NewTransferName("alice", codec.EmptyAddress).GetTypeID())
+	require.True(ok)
+	require.Equal("transferName", name)
+	require.ErrorIs(m.Register(registry), codec.ErrDuplicateItem)
+
+	_, err := New(&Config{RegisterNameID: 0, UpdateRecordID: 1, TransferNameID: 2}, chaintest.Ledger{})
+	require.ErrorIs(err, ErrInvalidPeriod)
+}
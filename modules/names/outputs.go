@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+var (
+	OutputInvalidName    = []byte("invalid name")
+	OutputNameTaken      = []byte("name is owned by someone else")
+	OutputNameMissing    = []byte("name is not registered")
+	OutputNameExpired    = []byte("name has expired")
+	OutputNotOwner       = []byte("not the owner")
+	OutputExpiryOverflow = []byte("expiry overflows")
+)
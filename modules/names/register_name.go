@@ -0,0 +1,126 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RegisterName)(nil)
+
+// RegisterName claims [Name] for [Config.Period] and points it at [Address].
+//
+// If the actor already owns [Name] (and it hasn't expired), the registration
+// is instead renewed: it is extended by [Config.Period] from its current
+// expiry. Either way, [Config.Fee] is burned.
+type RegisterName struct {
+	m *Module
+
+	Name    string        `json:"name"`
+	Address codec.Address `json:"address"`
+}
+
+func (m *Module) NewRegisterName(name string, addr codec.Address) *RegisterName {
+	return &RegisterName{m: m, Name: name, Address: addr}
+}
+
+func (r *RegisterName) GetTypeID() uint8 {
+	return r.m.c.RegisterNameID
+}
+
+func (r *RegisterName) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(r.m.l.BalanceKey(actor)),
+		string(r.m.RecordKey(r.Name)),
+	}
+}
+
+func (r *RegisterName) StateKeysMaxChunks() []uint16 {
+	return []uint16{r.m.l.BalanceChunks(), RecordChunks}
+}
+
+func (*RegisterName) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RegisterName) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := r.m.c.ComputeUnits
+	if !ValidName(r.Name) {
+		return false, computeUnits, OutputInvalidName, nil, nil
+	}
+	prev, err := r.m.GetRecord(ctx, mu, r.Name)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	start := timestamp
+	if prev != nil && !prev.Expired(timestamp) {
+		if prev.Owner != actor {
+			return false, computeUnits, OutputNameTaken, nil, nil
+		}
+		start = prev.Expiry
+	}
+	expiry, err := smath.Add64(uint64(start), uint64(r.m.c.Period))
+	if err != nil || expiry > uint64(consts.MaxInt64) {
+		return false, computeUnits, OutputExpiryOverflow, nil, nil
+	}
+	if r.m.c.Fee > 0 {
+		if err := r.m.l.SubBalance(ctx, mu, actor, r.m.c.Fee); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+	}
+	if err := r.m.setRecord(ctx, mu, r.Name, &Record{
+		Owner:   actor,
+		Address: r.Address,
+		Expiry:  int64(expiry),
+	}); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (r *RegisterName) MaxComputeUnits(chain.Rules) uint64 {
+	return r.m.c.ComputeUnits
+}
+
+func (r *RegisterName) Size() int {
+	// Strings are packed with a 2-byte length prefix
+	return consts.Uint16Len + len(r.Name) + codec.AddressLen
+}
+
+func (r *RegisterName) Marshal(p *codec.Packer) {
+	p.PackString(r.Name)
+	p.PackAddress(r.Address)
+}
+
+func (m *Module) UnmarshalRegisterName(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	register := RegisterName{m: m}
+	register.Name = p.UnpackString(true)
+	p.UnpackAddress(&register.Address)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &register, nil
+}
+
+func (*RegisterName) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
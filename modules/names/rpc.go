@@ -0,0 +1,52 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+// StateQuery is the name [Module.RegisterStateQueries] serves records under.
+const StateQuery = "name"
+
+// RegisterStateQueries serves the [Record] of each name with the queryState
+// RPC (keyed by name).
+func (m *Module) RegisterStateQueries(r *rpc.StateRegistry) error {
+	return r.Register(StateQuery, &rpc.StateQuery{
+		Key: func(name string) ([]byte, error) {
+			if !ValidName(name) {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+			}
+			return m.RecordKey(name), nil
+		},
+		Decode: func(v []byte) (any, error) {
+			return decodeRecord(v)
+		},
+	})
+}
+
+// Resolve returns the address [name] resolves to according to the node
+// behind [cli]. False is returned if [name] isn't registered or has expired.
+//
+// Expiry is checked against the local clock, so a name may still resolve
+// shortly after it expires if the clock is behind that of the chain.
+func Resolve(ctx context.Context, cli *rpc.JSONRPCClient, name string) (codec.Address, bool, error) {
+	if !ValidName(name) {
+		return codec.EmptyAddress, false, nil
+	}
+	var r Record
+	exists, err := cli.QueryState(ctx, StateQuery, name, &r)
+	if err != nil || !exists {
+		return codec.EmptyAddress, false, err
+	}
+	if r.Expired(time.Now().UnixMilli()) {
+		return codec.EmptyAddress, false, nil
+	}
+	return r.Address, true, nil
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// State
+// [Prefix]/0x0 (record)
+//   -> [name] => owner|address|expiry
+
+const (
+	recordPrefix = 0x0
+
+	RecordChunks uint16 = 2 // 33 + 33 + 8 bytes
+
+	recordLen = codec.AddressLen*2 + consts.Int64Len
+)
+
+// Record is the registration of a name.
+type Record struct {
+	Owner codec.Address `json:"owner"`
+	// Address is what the name resolves to.
+	Address codec.Address `json:"address"`
+	// Expiry is the time (in milliseconds) after which the name can be
+	// registered by anyone.
+	Expiry int64 `json:"expiry"`
+}
+
+// Expired returns true if the registration is over at [timestamp].
+func (r *Record) Expired(timestamp int64) bool {
+	return timestamp >= r.Expiry
+}
+
+// RecordKey returns the state key of [name]. Actions that resolve a name must
+// include it in their state keys (with [RecordChunks]).
+func (m *Module) RecordKey(name string) []byte {
+	k := make([]byte, 0, 2+len(name))
+	k = append(k, m.c.Prefix, recordPrefix)
+	k = append(k, name...)
+	return keys.EncodeChunks(k, RecordChunks)
+}
+
+// GetRecord returns the registration of [name] (or nil if it was never
+// registered), regardless of whether it has expired.
+func (m *Module) GetRecord(ctx context.Context, im state.Immutable, name string) (*Record, error) {
+	v, err := im.GetValue(ctx, m.RecordKey(name))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecord(v)
+}
+
+// Resolve returns the address [name] resolves to at [timestamp] (usually the
+// timestamp of the block executing the caller). False is returned if [name]
+// isn't registered or has expired.
+func (m *Module) Resolve(ctx context.Context, im state.Immutable, timestamp int64, name string) (codec.Address, bool, error) {
+	r, err := m.GetRecord(ctx, im, name)
+	if err != nil {
+		return codec.EmptyAddress, false, err
+	}
+	if r == nil || r.Expired(timestamp) {
+		return codec.EmptyAddress, false, nil
+	}
+	return r.Address, true, nil
+}
+
+func (m *Module) setRecord(ctx context.Context, mu state.Mutable, name string, r *Record) error {
+	v := make([]byte, recordLen)
+	copy(v, r.Owner[:])
+	copy(v[codec.AddressLen:], r.Address[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen*2:], uint64(r.Expiry))
+	return mu.Insert(ctx, m.RecordKey(name), v)
+}
+
+func decodeRecord(v []byte) (*Record, error) {
+	if len(v) != recordLen {
+		return nil, ErrCorruptRecord
+	}
+	r := &Record{Expiry: int64(binary.BigEndian.Uint64(v[codec.AddressLen*2:]))}
+	copy(r.Owner[:], v)
+	copy(r.Address[:], v[codec.AddressLen:])
+	return r, nil
+}
+
+// ownedRecord returns the registration of [name] if it is owned by [actor]
+// at [timestamp]. Otherwise, it returns the output explaining why not.
+func (m *Module) ownedRecord(
+	ctx context.Context,
+	im state.Immutable,
+	timestamp int64,
+	actor codec.Address,
+	name string,
+) (*Record, []byte, error) {
+	r, err := m.GetRecord(ctx, im, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case r == nil:
+		return nil, OutputNameMissing, nil
+	case r.Expired(timestamp):
+		return nil, OutputNameExpired, nil
+	case r.Owner != actor:
+		return nil, OutputNotOwner, nil
+	}
+	return r, nil, nil
+}
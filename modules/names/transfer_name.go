@@ -0,0 +1,103 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*TransferName)(nil)
+
+// TransferName makes [To] the owner of [Name] until it expires. Only the
+// owner of [Name] can transfer it and only before it expires.
+//
+// The address [Name] resolves to is left unchanged, so [To] should usually
+// follow up with [UpdateRecord].
+type TransferName struct {
+	m *Module
+
+	Name string        `json:"name"`
+	To   codec.Address `json:"to"`
+}
+
+func (m *Module) NewTransferName(name string, to codec.Address) *TransferName {
+	return &TransferName{m: m, Name: name, To: to}
+}
+
+func (t *TransferName) GetTypeID() uint8 {
+	return t.m.c.TransferNameID
+}
+
+func (t *TransferName) StateKeys(codec.Address, ids.ID) []string {
+	return []string{string(t.m.RecordKey(t.Name))}
+}
+
+func (*TransferName) StateKeysMaxChunks() []uint16 {
+	return []uint16{RecordChunks}
+}
+
+func (*TransferName) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *TransferName) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := t.m.c.ComputeUnits
+	r, output, err := t.m.ownedRecord(ctx, mu, timestamp, actor, t.Name)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if output != nil {
+		return false, computeUnits, output, nil, nil
+	}
+	r.Owner = t.To
+	if err := t.m.setRecord(ctx, mu, t.Name, r); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (t *TransferName) MaxComputeUnits(chain.Rules) uint64 {
+	return t.m.c.ComputeUnits
+}
+
+func (t *TransferName) Size() int {
+	// Strings are packed with a 2-byte length prefix
+	return consts.Uint16Len + len(t.Name) + codec.AddressLen
+}
+
+func (t *TransferName) Marshal(p *codec.Packer) {
+	p.PackString(t.Name)
+	p.PackAddress(t.To)
+}
+
+func (m *Module) UnmarshalTransferName(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	transfer := TransferName{m: m}
+	transfer.Name = p.UnpackString(true)
+	p.UnpackAddress(&transfer.To)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (*TransferName) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package names
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*UpdateRecord)(nil)
+
+// UpdateRecord points [Name] at [Address]. Only the owner of [Name] can
+// update it and only before it expires.
+type UpdateRecord struct {
+	m *Module
+
+	Name    string        `json:"name"`
+	Address codec.Address `json:"address"`
+}
+
+func (m *Module) NewUpdateRecord(name string, addr codec.Address) *UpdateRecord {
+	return &UpdateRecord{m: m, Name: name, Address: addr}
+}
+
+func (u *UpdateRecord) GetTypeID() uint8 {
+	return u.m.c.UpdateRecordID
+}
+
+func (u *UpdateRecord) StateKeys(codec.Address, ids.ID) []string {
+	return []string{string(u.m.RecordKey(u.Name))}
+}
+
+func (*UpdateRecord) StateKeysMaxChunks() []uint16 {
+	return []uint16{RecordChunks}
+}
+
+func (*UpdateRecord) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UpdateRecord) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := u.m.c.ComputeUnits
+	r, output, err := u.m.ownedRecord(ctx, mu, timestamp, actor, u.Name)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if output != nil {
+		return false, computeUnits, output, nil, nil
+	}
+	r.Address = u.Address
+	if err := u.m.setRecord(ctx, mu, u.Name, r); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (u *UpdateRecord) MaxComputeUnits(chain.Rules) uint64 {
+	return u.m.c.ComputeUnits
+}
+
+func (u *UpdateRecord) Size() int {
+	// Strings are packed with a 2-byte length prefix
+	return consts.Uint16Len + len(u.Name) + codec.AddressLen
+}
+
+func (u *UpdateRecord) Marshal(p *codec.Packer) {
+	p.PackString(u.Name)
+	p.PackAddress(u.Address)
+}
+
+func (m *Module) UnmarshalUpdateRecord(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	update := UpdateRecord{m: m}
+	update.Name = p.UnpackString(true)
+	p.UnpackAddress(&update.Address)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+func (*UpdateRecord) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+import "errors"
+
+var (
+	ErrDuplicateTypeID = errors.New("duplicate type ID")
+	ErrNoSigners       = errors.New("no signers and no admin")
+	ErrTooManySigners  = errors.New("too many signers")
+	ErrDuplicateSigner = errors.New("duplicate signer")
+	ErrInvalidMaxAge   = errors.New("max age must be positive")
+	ErrCorruptRecord   = errors.New("corrupt record")
+	ErrFeedMissing     = errors.New("feed missing")
+	ErrStaleFeed       = errors.New("stale feed")
+)
@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package oracle is a reference implementation of data feeds (like prices)
+// written by a set of trusted oracle keys, that a VM can register alongside
+// its own actions.
+//
+// Signers post data to a named feed with [PostData]. Each feed only holds the
+// latest data, the time it was observed at, and the signer that posted it.
+// Other actions read feeds with [Module.GetFreshFeed], which rejects data
+// older than [Config.MaxAge].
+//
+// Signers start as [Config.Signers]. If [Config.Admin] is set, it can replace
+// them with [SetSigners] (e.g. as the result of governance).
+package oracle
+
+import (
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const (
+	// MaxSigners is the largest number of signers allowed at once.
+	MaxSigners = 16
+	// MaxFeedLen is the longest allowed feed name.
+	MaxFeedLen = 32
+	// MaxDataLen is the largest amount of data that can be posted to a feed.
+	MaxDataLen = 128
+)
+
+// Config parameterizes a [Module]. It must be identical on all nodes (and
+// clients) of a chain.
+type Config struct {
+	// Prefix is the first byte of all keys stored by the module. It must not
+	// be used by any other keys of the VM.
+	Prefix byte
+
+	// Type IDs of the actions of the module in the [chain.ActionRegistry].
+	PostDataID   uint8
+	SetSignersID uint8
+
+	// Signers may post data until [Admin] replaces them.
+	Signers []codec.Address
+	// Admin (if not empty) may replace the signers.
+	Admin codec.Address
+
+	// MaxAge is the age (in milliseconds) after which data is stale. Stale
+	// data can't be posted and isn't returned by [Module.GetFreshFeed].
+	MaxAge int64
+
+	// ComputeUnits are charged for each action of the module.
+	ComputeUnits uint64
+}
+
+func (c *Config) Verify() error {
+	if c.PostDataID == c.SetSignersID {
+		return ErrDuplicateTypeID
+	}
+	if err := verifySigners(c.Signers); err != nil {
+		return err
+	}
+	if len(c.Signers) == 0 && c.Admin == codec.EmptyAddress {
+		return ErrNoSigners
+	}
+	if c.MaxAge <= 0 {
+		return ErrInvalidMaxAge
+	}
+	return nil
+}
+
+func verifySigners(signers []codec.Address) error {
+	if len(signers) > MaxSigners {
+		return ErrTooManySigners
+	}
+	if set.Of(signers...).Len() != len(signers) {
+		return ErrDuplicateSigner
+	}
+	return nil
+}
+
+// Module provides the actions and feeds of the oracle.
+type Module struct {
+	c *Config
+}
+
+func New(c *Config) (*Module, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	return &Module{c}, nil
+}
+
+// Register adds the actions of the module to [registry]. Like any other
+// action, they must always be registered in the same order.
+func (m *Module) Register(registry *codec.TypeParser[chain.Action, *warp.Message, bool]) error {
+	for _, action := range []struct {
+		id        uint8
+		name      string
+		unmarshal func(*codec.Packer, *warp.Message) (chain.Action, error)
+	}{
+		{m.c.PostDataID, "postData", m.UnmarshalPostData},
+		{m.c.SetSignersID, "setSigners", m.UnmarshalSetSigners},
+	} {
+		if err := registry.Register(action.id, action.unmarshal, false); err != nil {
+			return err
+		}
+		if err := registry.SetName(action.id, action.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
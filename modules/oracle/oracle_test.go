@@ -0,0 +1,148 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+func testModule(t *testing.T, signers ...codec.Address) (*Module, codec.Address) {
+	admin := codec.CreateAddress(0, ids.GenerateTestID())
+	m, err := New(&Config{
+		Prefix:       0x1,
+		PostDataID:   0,
+		SetSignersID: 1,
+		Signers:      signers,
+		Admin:        admin,
+		MaxAge:       1_000,
+		ComputeUnits: 1,
+	})
+	require.NoError(t, err)
+	return m, admin
+}
+
+func TestConformance(t *testing.T) {
+	var (
+		signer = codec.CreateAddress(0, ids.GenerateTestID())
+		m, _   = testModule(t, signer)
+		s      = chaintest.State{}
+	)
+	chaintest.Execute(t, s, m.NewPostData("AVAX/USD", []byte{1}, 5_000), 5_000, signer)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "post",
+			Action:          m.NewPostData("AVAX/USD", []byte{2}, 5_500),
+			Unmarshal:       m.UnmarshalPostData,
+			Actor:           signer,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "not a signer",
+			Action:          m.NewPostData("AVAX/USD", []byte{2}, 5_500),
+			Unmarshal:       m.UnmarshalPostData,
+			Actor:           codec.CreateAddress(0, ids.GenerateTestID()),
+			ExpectedOutput:  OutputNotSigner,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "future",
+			Action:          m.NewPostData("AVAX/USD", []byte{2}, 6_001),
+			Unmarshal:       m.UnmarshalPostData,
+			Actor:           signer,
+			ExpectedOutput:  OutputFutureData,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "stale",
+			Action:          m.NewPostData("BTC/USD", []byte{2}, 4_999),
+			Unmarshal:       m.UnmarshalPostData,
+			Actor:           signer,
+			ExpectedOutput:  OutputStaleData,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "outdated",
+			Action:          m.NewPostData("AVAX/USD", []byte{2}, 5_000),
+			Unmarshal:       m.UnmarshalPostData,
+			Actor:           signer,
+			ExpectedOutput:  OutputOutdatedData,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "set signers without admin",
+			Action:          m.NewSetSigners(nil),
+			Unmarshal:       m.UnmarshalSetSigners,
+			Actor:           signer,
+			ExpectedOutput:  OutputNotAdmin,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.State = s
+		test.Timestamp = 6_000
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+		signer  = codec.CreateAddress(0, ids.GenerateTestID())
+		next    = codec.CreateAddress(0, ids.GenerateTestID())
+		m, adm  = testModule(t, signer)
+		s       = chaintest.State{}
+	)
+
+	// Feeds are only fresh for [MaxAge]
+	_, err := m.GetFreshFeed(ctx, s, 1_000, "AVAX/USD")
+	require.ErrorIs(err, ErrFeedMissing)
+	chaintest.Execute(t, s, m.NewPostData("AVAX/USD", []byte("12.5"), 900), 1_000, signer)
+	f, err := m.GetFreshFeed(ctx, s, 1_900, "AVAX/USD")
+	require.NoError(err)
+	require.Equal(&Feed{Timestamp: 900, Signer: signer, Data: []byte("12.5")}, f)
+	_, err = m.GetFreshFeed(ctx, s, 1_901, "AVAX/USD")
+	require.ErrorIs(err, ErrStaleFeed)
+
+	// The admin can replace the signers
+	chaintest.Execute(t, s, m.NewSetSigners([]codec.Address{next}), 2_000, adm)
+	signers, err := m.GetSigners(ctx, s)
+	require.NoError(err)
+	require.Equal([]codec.Address{next}, signers)
+	success, _, output, _, err := m.NewPostData("AVAX/USD", []byte("13"), 2_000).Execute(ctx, nil, s, 2_000, signer, ids.Empty, false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputNotSigner, output)
+	chaintest.Execute(t, s, m.NewPostData("AVAX/USD", []byte("13"), 2_000), 2_000, next)
+	f, err = m.GetFeed(ctx, s, "AVAX/USD")
+	require.NoError(err)
+	require.Equal(next, f.Signer)
+}
+
+func TestRegister(t *testing.T) {
+	require := require.New(t)
+
+	m, _ := testModule(t)
+	registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	require.NoError(m.Register(registry))
+	name, ok := registry.LookupName(m.NewSetSigners(nil).GetTypeID())
+	require.True(ok)
+	require.Equal("setSigners", name)
+	require.ErrorIs(m.Register(registry), codec.ErrDuplicateItem)
+
+	_, err := New(&Config{PostDataID: 1, SetSignersID: 2, MaxAge: 1})
+	require.ErrorIs(err, ErrNoSigners)
+	signer := codec.CreateAddress(0, ids.GenerateTestID())
+	_, err = New(&Config{PostDataID: 1, SetSignersID: 2, Signers: []codec.Address{signer, signer}, MaxAge: 1})
+	require.ErrorIs(err, ErrDuplicateSigner)
+}
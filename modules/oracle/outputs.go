@@ -0,0 +1,14 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+var (
+	OutputNotSigner    = []byte("not a signer")
+	OutputNotAdmin     = []byte("not the admin")
+	OutputInvalidFeed  = []byte("invalid feed name")
+	OutputInvalidData  = []byte("invalid data size")
+	OutputFutureData   = []byte("data is from the future")
+	OutputStaleData    = []byte("data is stale")
+	OutputOutdatedData = []byte("feed already has newer data")
+)
@@ -0,0 +1,140 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*PostData)(nil)
+
+// PostData replaces the data of [Feed] with [Data] observed at [Timestamp].
+//
+// The actor must be a signer. [Timestamp] must not be after the block
+// timestamp, must be newer than the data already in the feed, and must not
+// be stale.
+type PostData struct {
+	m *Module
+
+	Feed      string `json:"feed"`
+	Data      []byte `json:"data"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (m *Module) NewPostData(feed string, data []byte, timestamp int64) *PostData {
+	return &PostData{m: m, Feed: feed, Data: data, Timestamp: timestamp}
+}
+
+func (p *PostData) GetTypeID() uint8 {
+	return p.m.c.PostDataID
+}
+
+func (p *PostData) StateKeys(codec.Address, ids.ID) []string {
+	return []string{
+		string(p.m.signersKey()),
+		string(p.m.FeedKey(p.Feed)),
+	}
+}
+
+func (*PostData) StateKeysMaxChunks() []uint16 {
+	return []uint16{SignersChunks, FeedChunks}
+}
+
+func (*PostData) OutputsWarpMessage() bool {
+	return false
+}
+
+func (p *PostData) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := p.m.c.ComputeUnits
+	signers, err := p.m.GetSigners(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if !contains(signers, actor) {
+		return false, computeUnits, OutputNotSigner, nil, nil
+	}
+	if len(p.Feed) == 0 || len(p.Feed) > MaxFeedLen {
+		return false, computeUnits, OutputInvalidFeed, nil, nil
+	}
+	if len(p.Data) == 0 || len(p.Data) > MaxDataLen {
+		return false, computeUnits, OutputInvalidData, nil, nil
+	}
+	if p.Timestamp > timestamp {
+		return false, computeUnits, OutputFutureData, nil, nil
+	}
+	if timestamp-p.Timestamp > p.m.c.MaxAge {
+		return false, computeUnits, OutputStaleData, nil, nil
+	}
+	prev, err := p.m.GetFeed(ctx, mu, p.Feed)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if prev != nil && prev.Timestamp >= p.Timestamp {
+		return false, computeUnits, OutputOutdatedData, nil, nil
+	}
+	if err := p.m.setFeed(ctx, mu, p.Feed, &Feed{
+		Timestamp: p.Timestamp,
+		Signer:    actor,
+		Data:      p.Data,
+	}); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (p *PostData) MaxComputeUnits(chain.Rules) uint64 {
+	return p.m.c.ComputeUnits
+}
+
+func (p *PostData) Size() int {
+	// Strings are packed with a 2-byte length prefix
+	return consts.Uint16Len + len(p.Feed) + codec.BytesLen(p.Data) + consts.Int64Len
+}
+
+func (p *PostData) Marshal(pk *codec.Packer) {
+	pk.PackString(p.Feed)
+	pk.PackBytes(p.Data)
+	pk.PackInt64(p.Timestamp)
+}
+
+func (m *Module) UnmarshalPostData(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	post := PostData{m: m}
+	post.Feed = p.UnpackString(true)
+	p.UnpackBytes(MaxDataLen, true, &post.Data)
+	post.Timestamp = p.UnpackInt64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (*PostData) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+func contains(addrs []codec.Address, addr codec.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
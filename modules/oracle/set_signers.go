@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*SetSigners)(nil)
+
+// SetSigners replaces the signers with [Signers]. Only [Config.Admin] can
+// set the signers. Data already posted is kept.
+type SetSigners struct {
+	m *Module
+
+	Signers []codec.Address `json:"signers"`
+}
+
+func (m *Module) NewSetSigners(signers []codec.Address) *SetSigners {
+	return &SetSigners{m: m, Signers: signers}
+}
+
+func (s *SetSigners) GetTypeID() uint8 {
+	return s.m.c.SetSignersID
+}
+
+func (s *SetSigners) StateKeys(codec.Address, ids.ID) []string {
+	return []string{string(s.m.signersKey())}
+}
+
+func (*SetSigners) StateKeysMaxChunks() []uint16 {
+	return []uint16{SignersChunks}
+}
+
+func (*SetSigners) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SetSigners) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := s.m.c.ComputeUnits
+	if s.m.c.Admin == codec.EmptyAddress || actor != s.m.c.Admin {
+		return false, computeUnits, OutputNotAdmin, nil, nil
+	}
+	if err := verifySigners(s.Signers); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := s.m.setSigners(ctx, mu, s.Signers); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (s *SetSigners) MaxComputeUnits(chain.Rules) uint64 {
+	return s.m.c.ComputeUnits
+}
+
+func (s *SetSigners) Size() int {
+	return consts.IntLen + len(s.Signers)*codec.AddressLen
+}
+
+func (s *SetSigners) Marshal(p *codec.Packer) {
+	p.PackInt(len(s.Signers))
+	for _, signer := range s.Signers {
+		p.PackAddress(signer)
+	}
+}
+
+func (m *Module) UnmarshalSetSigners(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	set := SetSigners{m: m}
+	count := p.UnpackInt(false)
+	if count > MaxSigners {
+		return nil, ErrTooManySigners
+	}
+	set.Signers = make([]codec.Address, count)
+	for i := range set.Signers {
+		p.UnpackAddress(&set.Signers[i])
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+func (*SetSigners) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package oracle
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// State
+// [Prefix]/0x0 (signers) => count|signers
+// [Prefix]/0x1 (feed)
+//   -> [feed] => timestamp|signer|data
+
+const (
+	signersPrefix = 0x0
+	feedPrefix    = 0x1
+
+	SignersChunks uint16 = 9 // 2 + [MaxSigners]*33 bytes
+	FeedChunks    uint16 = 3 // 8 + 33 + [MaxDataLen] bytes
+
+	minFeedLen = consts.Uint64Len + codec.AddressLen
+)
+
+// Feed is the latest data posted to a feed.
+type Feed struct {
+	// Timestamp is the time (in milliseconds) the data was observed at.
+	Timestamp int64         `json:"timestamp"`
+	Signer    codec.Address `json:"signer"`
+	Data      []byte        `json:"data"`
+}
+
+func (m *Module) signersKey() []byte {
+	return keys.EncodeChunks([]byte{m.c.Prefix, signersPrefix}, SignersChunks)
+}
+
+// FeedKey returns the state key of [feed]. Actions that read a feed must
+// include it in their state keys (with [FeedChunks]).
+func (m *Module) FeedKey(feed string) []byte {
+	k := make([]byte, 0, 2+len(feed))
+	k = append(k, m.c.Prefix, feedPrefix)
+	k = append(k, feed...)
+	return keys.EncodeChunks(k, FeedChunks)
+}
+
+// GetSigners returns the addresses currently allowed to post data.
+func (m *Module) GetSigners(ctx context.Context, im state.Immutable) ([]codec.Address, error) {
+	v, err := im.GetValue(ctx, m.signersKey())
+	if errors.Is(err, database.ErrNotFound) {
+		return m.c.Signers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < consts.Uint16Len {
+		return nil, ErrCorruptRecord
+	}
+	count := int(binary.BigEndian.Uint16(v))
+	if len(v) != consts.Uint16Len+count*codec.AddressLen {
+		return nil, ErrCorruptRecord
+	}
+	signers := make([]codec.Address, count)
+	for i := range signers {
+		copy(signers[i][:], v[consts.Uint16Len+i*codec.AddressLen:])
+	}
+	return signers, nil
+}
+
+func (m *Module) setSigners(ctx context.Context, mu state.Mutable, signers []codec.Address) error {
+	v := make([]byte, consts.Uint16Len, consts.Uint16Len+len(signers)*codec.AddressLen)
+	binary.BigEndian.PutUint16(v, uint16(len(signers)))
+	for _, signer := range signers {
+		v = append(v, signer[:]...)
+	}
+	return mu.Insert(ctx, m.signersKey(), v)
+}
+
+// GetFeed returns the latest data posted to [feed] (or nil if nothing was
+// ever posted), regardless of its age.
+func (m *Module) GetFeed(ctx context.Context, im state.Immutable, feed string) (*Feed, error) {
+	v, err := im.GetValue(ctx, m.FeedKey(feed))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < minFeedLen {
+		return nil, ErrCorruptRecord
+	}
+	f := &Feed{
+		Timestamp: int64(binary.BigEndian.Uint64(v)),
+		Data:      v[minFeedLen:],
+	}
+	copy(f.Signer[:], v[consts.Uint64Len:])
+	return f, nil
+}
+
+// GetFreshFeed returns the latest data posted to [feed] if it was observed at
+// most [Config.MaxAge] before [timestamp] (usually the timestamp of the block
+// executing the caller).
+func (m *Module) GetFreshFeed(ctx context.Context, im state.Immutable, timestamp int64, feed string) (*Feed, error) {
+	f, err := m.GetFeed(ctx, im, feed)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFeedMissing, feed)
+	}
+	if timestamp-f.Timestamp > m.c.MaxAge {
+		return nil, fmt.Errorf("%w: %s observed at %d", ErrStaleFeed, feed, f.Timestamp)
+	}
+	return f, nil
+}
+
+func (m *Module) setFeed(ctx context.Context, mu state.Mutable, feed string, f *Feed) error {
+	v := make([]byte, minFeedLen, minFeedLen+len(f.Data))
+	binary.BigEndian.PutUint64(v, uint64(f.Timestamp))
+	copy(v[consts.Uint64Len:], f.Signer[:])
+	v = append(v, f.Data...)
+	return mu.Insert(ctx, m.FeedKey(feed), v)
+}
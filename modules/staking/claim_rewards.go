@@ -0,0 +1,118 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*ClaimRewards)(nil)
+
+// ClaimRewards credits the actor with the rewards of its position with
+// [Validator] and any of its stake that finished unbonding.
+type ClaimRewards struct {
+	m *Module
+
+	Validator codec.Address `json:"validator"`
+}
+
+func (m *Module) NewClaimRewards(validator codec.Address) *ClaimRewards {
+	return &ClaimRewards{m: m, Validator: validator}
+}
+
+func (c *ClaimRewards) GetTypeID() uint8 {
+	return c.m.c.ClaimRewardsID
+}
+
+func (c *ClaimRewards) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(c.m.l.BalanceKey(actor)),
+		string(c.m.poolKey()),
+		string(c.m.positionKey(actor, c.Validator)),
+	}
+}
+
+func (c *ClaimRewards) StateKeysMaxChunks() []uint16 {
+	return []uint16{c.m.l.BalanceChunks(), PoolChunks, PositionChunks}
+}
+
+func (*ClaimRewards) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *ClaimRewards) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := c.m.c.ComputeUnits
+	p, err := c.m.getPool(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	pos, err := c.m.getPosition(ctx, mu, actor, c.Validator)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := settle(p, pos); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	amount := pos.Unclaimed
+	pos.Unclaimed = 0
+	if pos.Unbonding > 0 && p.Epoch >= pos.UnbondingEpoch {
+		if amount, err = smath.Add64(amount, pos.Unbonding); err != nil {
+			return false, computeUnits, nil, nil, chain.Fail(err)
+		}
+		pos.Unbonding = 0
+		pos.UnbondingEpoch = 0
+	}
+	if amount == 0 {
+		return false, computeUnits, OutputNothingToClaim, nil, nil
+	}
+	if err := c.m.setPosition(ctx, mu, actor, c.Validator, pos); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := c.m.l.AddBalance(ctx, mu, actor, amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (c *ClaimRewards) MaxComputeUnits(chain.Rules) uint64 {
+	return c.m.c.ComputeUnits
+}
+
+func (*ClaimRewards) Size() int {
+	return codec.AddressLen
+}
+
+func (c *ClaimRewards) Marshal(p *codec.Packer) {
+	p.PackAddress(c.Validator)
+}
+
+func (m *Module) UnmarshalClaimRewards(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	claim := ClaimRewards{m: m}
+	p.UnpackAddress(&claim.Validator)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (*ClaimRewards) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Delegate)(nil)
+
+// Delegate stakes [Amount] of the actor's balance with [Validator].
+type Delegate struct {
+	m *Module
+
+	Validator codec.Address `json:"validator"`
+	Amount    uint64        `json:"amount"`
+}
+
+func (m *Module) NewDelegate(validator codec.Address, amount uint64) *Delegate {
+	return &Delegate{m: m, Validator: validator, Amount: amount}
+}
+
+func (d *Delegate) GetTypeID() uint8 {
+	return d.m.c.DelegateID
+}
+
+func (d *Delegate) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(d.m.l.BalanceKey(actor)),
+		string(d.m.poolKey()),
+		string(d.m.validatorKey(d.Validator)),
+		string(d.m.positionKey(actor, d.Validator)),
+	}
+}
+
+func (d *Delegate) StateKeysMaxChunks() []uint16 {
+	return []uint16{d.m.l.BalanceChunks(), PoolChunks, ValidatorChunks, PositionChunks}
+}
+
+func (*Delegate) OutputsWarpMessage() bool {
+	return false
+}
+
+func (d *Delegate) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := d.m.c.ComputeUnits
+	if d.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	if d.Validator == actor {
+		return false, computeUnits, OutputSelfDelegation, nil, nil
+	}
+	val, err := d.m.getValidator(ctx, mu, d.Validator)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if val.SelfStake < d.m.c.MinValidatorStake {
+		return false, computeUnits, OutputNotValidator, nil, nil
+	}
+	if val.DelegatedStake, err = smath.Add64(val.DelegatedStake, d.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := d.m.l.SubBalance(ctx, mu, actor, d.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := d.m.setValidator(ctx, mu, d.Validator, val); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := d.m.addStake(ctx, mu, actor, d.Validator, d.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (d *Delegate) MaxComputeUnits(chain.Rules) uint64 {
+	return d.m.c.ComputeUnits
+}
+
+func (*Delegate) Size() int {
+	return codec.AddressLen + consts.Uint64Len
+}
+
+func (d *Delegate) Marshal(p *codec.Packer) {
+	p.PackAddress(d.Validator)
+	p.PackUint64(d.Amount)
+}
+
+func (m *Module) UnmarshalDelegate(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	delegate := Delegate{m: m}
+	p.UnpackAddress(&delegate.Validator)
+	delegate.Amount = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &delegate, nil
+}
+
+func (*Delegate) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
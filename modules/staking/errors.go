@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import "errors"
+
+var (
+	ErrDuplicateTypeID          = errors.New("duplicate type ID")
+	ErrInvalidMinValidatorStake = errors.New("min validator stake must be positive")
+	ErrCorruptRecord            = errors.New("corrupt record")
+	ErrRewardOverflow           = errors.New("reward overflow")
+)
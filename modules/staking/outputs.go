@@ -0,0 +1,13 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+var (
+	OutputValueZero         = []byte("value is zero")
+	OutputBelowMinStake     = []byte("stake is below the minimum")
+	OutputNotValidator      = []byte("not a validator")
+	OutputSelfDelegation    = []byte("validators must stake instead of delegating to themselves")
+	OutputInsufficientStake = []byte("insufficient stake")
+	OutputNothingToClaim    = []byte("nothing to claim")
+)
@@ -0,0 +1,55 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"math/big"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// addRewardPerShare returns [rewardPerShare] after [reward] is split among
+// [totalStake].
+func addRewardPerShare(rewardPerShare *big.Int, reward uint64, totalStake uint64) *big.Int {
+	share := new(big.Int).Mul(new(big.Int).SetUint64(reward), rewardScale)
+	share.Quo(share, new(big.Int).SetUint64(totalStake))
+	return share.Add(share, rewardPerShare)
+}
+
+// accrued is the reward accrued by [amount] at [rewardPerShare].
+func accrued(rewardPerShare *big.Int, amount uint64) (uint64, error) {
+	r := new(big.Int).Mul(rewardPerShare, new(big.Int).SetUint64(amount))
+	r.Quo(r, rewardScale)
+	if !r.IsUint64() {
+		return 0, ErrRewardOverflow
+	}
+	return r.Uint64(), nil
+}
+
+// settle moves the rewards accrued by [pos] since it was last updated to
+// [Position.Unclaimed]. It must be called before [Position.Amount] is
+// modified (and [reset] after).
+func settle(p *Pool, pos *Position) error {
+	total, err := accrued(p.RewardPerShare, pos.Amount)
+	if err != nil {
+		return err
+	}
+	// [RewardPerShare] never decreases, so [total] is at least [RewardDebt].
+	pos.Unclaimed, err = smath.Add64(pos.Unclaimed, total-pos.RewardDebt)
+	if err != nil {
+		return err
+	}
+	pos.RewardDebt = total
+	return nil
+}
+
+// reset marks all rewards accrued by [pos] as accounted for.
+func reset(p *Pool, pos *Position) error {
+	debt, err := accrued(p.RewardPerShare, pos.Amount)
+	if err != nil {
+		return err
+	}
+	pos.RewardDebt = debt
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Stake)(nil)
+
+// Stake adds to the self-stake of the actor, making it a validator once its
+// self-stake reaches [Config.MinValidatorStake].
+type Stake struct {
+	m *Module
+
+	Amount uint64 `json:"amount"`
+}
+
+func (m *Module) NewStake(amount uint64) *Stake {
+	return &Stake{m: m, Amount: amount}
+}
+
+func (s *Stake) GetTypeID() uint8 {
+	return s.m.c.StakeID
+}
+
+func (s *Stake) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(s.m.l.BalanceKey(actor)),
+		string(s.m.poolKey()),
+		string(s.m.validatorKey(actor)),
+		string(s.m.positionKey(actor, actor)),
+	}
+}
+
+func (s *Stake) StateKeysMaxChunks() []uint16 {
+	return []uint16{s.m.l.BalanceChunks(), PoolChunks, ValidatorChunks, PositionChunks}
+}
+
+func (*Stake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *Stake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := s.m.c.ComputeUnits
+	if s.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	val, err := s.m.getValidator(ctx, mu, actor)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if val.SelfStake, err = smath.Add64(val.SelfStake, s.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if val.SelfStake < s.m.c.MinValidatorStake {
+		return false, computeUnits, OutputBelowMinStake, nil, nil
+	}
+	if err := s.m.l.SubBalance(ctx, mu, actor, s.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := s.m.setValidator(ctx, mu, actor, val); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := s.m.addStake(ctx, mu, actor, actor, s.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (s *Stake) MaxComputeUnits(chain.Rules) uint64 {
+	return s.m.c.ComputeUnits
+}
+
+func (*Stake) Size() int {
+	return consts.Uint64Len
+}
+
+func (s *Stake) Marshal(p *codec.Packer) {
+	p.PackUint64(s.Amount)
+}
+
+func (m *Module) UnmarshalStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	stake := Stake{m: m}
+	stake.Amount = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &stake, nil
+}
+
+func (*Stake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// addStake adds [amount] to the position of [delegator] with [validator] and
+// to the total stake of the pool (the validator record must be updated by
+// the caller).
+func (m *Module) addStake(
+	ctx context.Context,
+	mu state.Mutable,
+	delegator codec.Address,
+	validator codec.Address,
+	amount uint64,
+) error {
+	p, err := m.getPool(ctx, mu)
+	if err != nil {
+		return err
+	}
+	pos, err := m.getPosition(ctx, mu, delegator, validator)
+	if err != nil {
+		return err
+	}
+	if err := settle(p, pos); err != nil {
+		return err
+	}
+	if pos.Amount, err = smath.Add64(pos.Amount, amount); err != nil {
+		return err
+	}
+	if err := reset(p, pos); err != nil {
+		return err
+	}
+	if p.TotalStake, err = smath.Add64(p.TotalStake, amount); err != nil {
+		return err
+	}
+	if err := m.setPosition(ctx, mu, delegator, validator, pos); err != nil {
+		return err
+	}
+	return m.setPool(ctx, mu, p)
+}
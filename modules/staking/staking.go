@@ -0,0 +1,136 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package staking is a reference implementation of native-token staking that
+// a VM can register alongside its own actions.
+//
+// Any account can become a validator by staking at least
+// [Config.MinValidatorStake] of the native token ([Stake]). Other accounts can
+// then delegate to it ([Delegate]). At the end of each epoch (see
+// [chain.Rules.GetEpochLength]), [Config.RewardPerEpoch] is split among all
+// stake (self-bonded or delegated) in proportion to its amount. Rewards are
+// minted when they are claimed ([ClaimRewards]). Stake that is withdrawn
+// ([Unstake]) stops earning rewards immediately but can only be claimed
+// after [Config.UnbondingEpochs] epochs have ended.
+//
+// This staking is independent of the validation of the subnet on the
+// P-Chain.
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.EpochHandler = (*Module)(nil)
+
+// Ledger moves the native token of a VM in and out of stake.
+type Ledger interface {
+	// BalanceKey returns the state key (including its chunk suffix) of the
+	// balance of [addr].
+	BalanceKey(addr codec.Address) []byte
+	// BalanceChunks is the chunk suffix of all keys returned by [BalanceKey].
+	BalanceChunks() uint16
+
+	AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error
+	SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error
+}
+
+// Config parameterizes a [Module]. It must be identical on all nodes (and
+// clients) of a chain.
+type Config struct {
+	// Prefix is the first byte of all keys stored by the module. It must not
+	// be used by any other keys of the VM.
+	Prefix byte
+
+	// Type IDs of the actions of the module in the [chain.ActionRegistry].
+	StakeID        uint8
+	DelegateID     uint8
+	UnstakeID      uint8
+	ClaimRewardsID uint8
+
+	// MinValidatorStake is the minimum amount an account must stake to be
+	// delegated to.
+	MinValidatorStake uint64
+	// RewardPerEpoch is minted to all stake at the end of each epoch.
+	RewardPerEpoch uint64
+	// UnbondingEpochs is the number of epochs that must end before unstaked
+	// funds can be claimed.
+	UnbondingEpochs uint64
+
+	// ComputeUnits are charged for each action of the module.
+	ComputeUnits uint64
+}
+
+func (c *Config) Verify() error {
+	if set.Of(c.StakeID, c.DelegateID, c.UnstakeID, c.ClaimRewardsID).Len() != 4 {
+		return ErrDuplicateTypeID
+	}
+	if c.MinValidatorStake == 0 {
+		return ErrInvalidMinValidatorStake
+	}
+	return nil
+}
+
+// Module provides the actions and epoch handling of staking. The
+// [chain.EpochHandler] of the VM must call [Module.EndEpoch] (with the keys
+// from [Module.EpochStateKeys]) for rewards and unbonding to progress.
+type Module struct {
+	c *Config
+	l Ledger
+}
+
+func New(c *Config, l Ledger) (*Module, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	return &Module{c, l}, nil
+}
+
+// Register adds the actions of the module to [registry]. Like any other
+// action, they must always be registered in the same order.
+func (m *Module) Register(registry *codec.TypeParser[chain.Action, *warp.Message, bool]) error {
+	for _, action := range []struct {
+		id        uint8
+		name      string
+		unmarshal func(*codec.Packer, *warp.Message) (chain.Action, error)
+	}{
+		{m.c.StakeID, "stake", m.UnmarshalStake},
+		{m.c.DelegateID, "delegate", m.UnmarshalDelegate},
+		{m.c.UnstakeID, "unstake", m.UnmarshalUnstake},
+		{m.c.ClaimRewardsID, "claimRewards", m.UnmarshalClaimRewards},
+	} {
+		if err := registry.Register(action.id, action.unmarshal, false); err != nil {
+			return err
+		}
+		if err := registry.SetName(action.id, action.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EpochStateKeys returns the keys accessed by [EndEpoch].
+func (m *Module) EpochStateKeys(uint64) []string {
+	return []string{string(m.poolKey())}
+}
+
+// EndEpoch accrues the rewards of [epoch] to all stake and advances
+// unbonding.
+func (m *Module) EndEpoch(ctx context.Context, _ chain.Rules, mu state.Mutable, _ int64, epoch uint64) error {
+	p, err := m.getPool(ctx, mu)
+	if err != nil {
+		return err
+	}
+	p.Epoch = epoch + 1
+	if p.TotalStake > 0 && m.c.RewardPerEpoch > 0 {
+		p.RewardPerShare = addRewardPerShare(p.RewardPerShare, m.c.RewardPerEpoch, p.TotalStake)
+	}
+	return m.setPool(ctx, mu, p)
+}
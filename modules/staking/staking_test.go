@@ -0,0 +1,171 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+func testModule(t *testing.T) *Module {
+	m, err := New(&Config{
+		Prefix:            0x1,
+		StakeID:           0,
+		DelegateID:        1,
+		UnstakeID:         2,
+		ClaimRewardsID:    3,
+		MinValidatorStake: 100,
+		RewardPerEpoch:    30,
+		UnbondingEpochs:   2,
+		ComputeUnits:      1,
+	}, chaintest.Ledger{})
+	require.NoError(t, err)
+	return m
+}
+
+func TestConformance(t *testing.T) {
+	var (
+		m         = testModule(t)
+		actor     = codec.CreateAddress(0, ids.GenerateTestID())
+		validator = codec.CreateAddress(0, ids.GenerateTestID())
+		s         = chaintest.State{}
+	)
+	require.NoError(t, chaintest.Ledger{}.AddBalance(context.Background(), s, actor, 1_000))
+	require.NoError(t, chaintest.Ledger{}.AddBalance(context.Background(), s, validator, 1_000))
+	chaintest.Execute(t, s, m.NewStake(100), 0, validator)
+
+	for _, test := range []chaintest.ActionTest{
+		{
+			Name:            "stake",
+			Action:          m.NewStake(100),
+			Unmarshal:       m.UnmarshalStake,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "stake below min",
+			Action:          m.NewStake(99),
+			Unmarshal:       m.UnmarshalStake,
+			ExpectedOutput:  OutputBelowMinStake,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "delegate",
+			Action:          m.NewDelegate(validator, 10),
+			Unmarshal:       m.UnmarshalDelegate,
+			ExpectedSuccess: true,
+		},
+		{
+			Name:            "delegate to non-validator",
+			Action:          m.NewDelegate(codec.CreateAddress(0, ids.GenerateTestID()), 10),
+			Unmarshal:       m.UnmarshalDelegate,
+			ExpectedOutput:  OutputNotValidator,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "unstake without stake",
+			Action:          m.NewUnstake(validator, 10),
+			Unmarshal:       m.UnmarshalUnstake,
+			ExpectedOutput:  OutputInsufficientStake,
+			ExpectedSuccess: false,
+		},
+		{
+			Name:            "claim without rewards",
+			Action:          m.NewClaimRewards(validator),
+			Unmarshal:       m.UnmarshalClaimRewards,
+			ExpectedOutput:  OutputNothingToClaim,
+			ExpectedSuccess: false,
+		},
+	} {
+		test.State = s
+		test.Actor = actor
+		chaintest.RunActionTest(t, test)
+	}
+}
+
+func TestRewardsAndUnbonding(t *testing.T) {
+	var (
+		require   = require.New(t)
+		ctx       = context.Background()
+		m         = testModule(t)
+		l         = chaintest.Ledger{}
+		validator = codec.CreateAddress(0, ids.GenerateTestID())
+		delegator = codec.CreateAddress(0, ids.GenerateTestID())
+		s         = chaintest.State{}
+	)
+	require.NoError(l.AddBalance(ctx, s, validator, 1_000))
+	require.NoError(l.AddBalance(ctx, s, delegator, 1_000))
+	endEpoch := func(epoch uint64) {
+		require.NoError(m.EndEpoch(ctx, nil, s, 0, epoch))
+	}
+
+	// Rewards are split in proportion to stake
+	chaintest.Execute(t, s, m.NewStake(200), 0, validator)
+	chaintest.Execute(t, s, m.NewDelegate(validator, 100), 0, delegator)
+	val, err := m.GetValidator(ctx, s, validator)
+	require.NoError(err)
+	require.Equal(&Validator{SelfStake: 200, DelegatedStake: 100}, val)
+	endEpoch(0)
+	chaintest.Execute(t, s, m.NewClaimRewards(validator), 0, validator)
+	chaintest.Execute(t, s, m.NewClaimRewards(validator), 0, delegator)
+	require.Equal(uint64(800+20), l.Balance(ctx, s, validator))
+	require.Equal(uint64(900+10), l.Balance(ctx, s, delegator))
+
+	// Unstaked funds stop earning rewards and are locked until unbonded
+	chaintest.Execute(t, s, m.NewUnstake(validator, 100), 0, delegator)
+	endEpoch(1)
+	pos, err := m.GetPosition(ctx, s, delegator, validator)
+	require.NoError(err)
+	require.Equal(&Position{Unbonding: 100, UnbondingEpoch: 3}, pos)
+	success, _, output, _, err := m.NewClaimRewards(validator).Execute(ctx, nil, s, 0, delegator, ids.Empty, false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputNothingToClaim, output)
+	endEpoch(2)
+	chaintest.Execute(t, s, m.NewClaimRewards(validator), 0, delegator)
+	require.Equal(uint64(1_010), l.Balance(ctx, s, delegator))
+	pos, err = m.GetPosition(ctx, s, delegator, validator)
+	require.NoError(err)
+	require.Equal(&Position{}, pos)
+
+	// The validator earned all rewards while it was the only staker
+	chaintest.Execute(t, s, m.NewClaimRewards(validator), 0, validator)
+	require.Equal(uint64(820+30*2), l.Balance(ctx, s, validator))
+
+	// Validators must keep the minimum stake or unstake everything
+	success, _, output, _, err = m.NewUnstake(validator, 150).Execute(ctx, nil, s, 0, validator, ids.Empty, false)
+	require.NoError(err)
+	require.False(success)
+	require.Equal(OutputBelowMinStake, output)
+	chaintest.Execute(t, s, m.NewUnstake(validator, 200), 0, validator)
+	val, err = m.GetValidator(ctx, s, validator)
+	require.NoError(err)
+	require.Equal(&Validator{}, val)
+	p, err := m.GetPool(ctx, s)
+	require.NoError(err)
+	require.Zero(p.TotalStake)
+	require.Equal(uint64(3), p.Epoch)
+}
+
+func TestRegister(t *testing.T) {
+	require := require.New(t)
+
+	m := testModule(t)
+	registry := codec.NewTypeParser[chain.Action, *warp.Message]()
+	require.NoError(m.Register(registry))
+	name, ok := registry.LookupName(m.NewClaimRewards(codec.EmptyAddress).GetTypeID())
+	require.True(ok)
+	require.Equal("claimRewards", name)
+	require.ErrorIs(m.Register(registry), codec.ErrDuplicateItem)
+
+	_, err := New(&Config{MinValidatorStake: 1}, chaintest.Ledger{})
+	require.ErrorIs(err, ErrDuplicateTypeID)
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// State
+// [Prefix]/0x0 (pool) => epoch|totalStake|rewardPerShare
+// [Prefix]/0x1 (validator)
+//   -> [validator] => selfStake|delegatedStake
+// [Prefix]/0x2 (position)
+//   -> [delegator]|[validator] => amount|rewardDebt|unclaimed|unbonding|unbondingEpoch
+
+const (
+	poolPrefix      = 0x0
+	validatorPrefix = 0x1
+	positionPrefix  = 0x2
+
+	PoolChunks      uint16 = 1
+	ValidatorChunks uint16 = 1
+	PositionChunks  uint16 = 1
+
+	// rewardPerShareLen is the length of the encoding of
+	// [Pool.RewardPerShare].
+	rewardPerShareLen = 32
+
+	poolLen      = consts.Uint64Len*2 + rewardPerShareLen
+	validatorLen = consts.Uint64Len * 2
+	positionLen  = consts.Uint64Len * 5
+)
+
+// rewardScale is the precision of [Pool.RewardPerShare].
+var rewardScale = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// Pool tracks all stake of the chain.
+type Pool struct {
+	// Epoch is the current epoch (the last epoch ended by
+	// [Module.EndEpoch] plus 1).
+	Epoch      uint64 `json:"epoch"`
+	TotalStake uint64 `json:"totalStake"`
+	// RewardPerShare is the reward accrued by each unit of stake since
+	// genesis (scaled by [rewardScale]).
+	RewardPerShare *big.Int `json:"rewardPerShare"`
+}
+
+// Validator is an account that can be delegated to.
+type Validator struct {
+	SelfStake      uint64 `json:"selfStake"`
+	DelegatedStake uint64 `json:"delegatedStake"`
+}
+
+// Position is the stake of a delegator with a validator (validators have a
+// position with themselves for their self-stake).
+type Position struct {
+	Amount uint64 `json:"amount"`
+	// RewardDebt is the reward already accounted for [Amount].
+	RewardDebt uint64 `json:"rewardDebt"`
+	// Unclaimed rewards are claimable immediately.
+	Unclaimed uint64 `json:"unclaimed"`
+	// Unbonding stake is claimable once the pool reaches [UnbondingEpoch].
+	Unbonding      uint64 `json:"unbonding"`
+	UnbondingEpoch uint64 `json:"unbondingEpoch"`
+}
+
+func (p *Position) empty() bool {
+	return p.Amount == 0 && p.Unclaimed == 0 && p.Unbonding == 0
+}
+
+func (m *Module) poolKey() []byte {
+	return keys.EncodeChunks([]byte{m.c.Prefix, poolPrefix}, PoolChunks)
+}
+
+func (m *Module) validatorKey(validator codec.Address) []byte {
+	k := make([]byte, 0, 2+codec.AddressLen)
+	k = append(k, m.c.Prefix, validatorPrefix)
+	k = append(k, validator[:]...)
+	return keys.EncodeChunks(k, ValidatorChunks)
+}
+
+func (m *Module) positionKey(delegator codec.Address, validator codec.Address) []byte {
+	k := make([]byte, 0, 2+codec.AddressLen*2)
+	k = append(k, m.c.Prefix, positionPrefix)
+	k = append(k, delegator[:]...)
+	k = append(k, validator[:]...)
+	return keys.EncodeChunks(k, PositionChunks)
+}
+
+// GetPool returns the [Pool] of the chain.
+func (m *Module) GetPool(ctx context.Context, im state.Immutable) (*Pool, error) {
+	return m.getPool(ctx, im)
+}
+
+// GetValidator returns the [Validator] record of [validator] (which is empty
+// if it never staked).
+func (m *Module) GetValidator(ctx context.Context, im state.Immutable, validator codec.Address) (*Validator, error) {
+	return m.getValidator(ctx, im, validator)
+}
+
+// GetPosition returns the [Position] of [delegator] with [validator]
+// (including any rewards accrued but not yet accounted for).
+func (m *Module) GetPosition(
+	ctx context.Context,
+	im state.Immutable,
+	delegator codec.Address,
+	validator codec.Address,
+) (*Position, error) {
+	p, err := m.getPool(ctx, im)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := m.getPosition(ctx, im, delegator, validator)
+	if err != nil {
+		return nil, err
+	}
+	if err := settle(p, pos); err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+func (m *Module) getPool(ctx context.Context, im state.Immutable) (*Pool, error) {
+	v, err := im.GetValue(ctx, m.poolKey())
+	if errors.Is(err, database.ErrNotFound) {
+		return &Pool{RewardPerShare: new(big.Int)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != poolLen {
+		return nil, ErrCorruptRecord
+	}
+	return &Pool{
+		Epoch:          binary.BigEndian.Uint64(v),
+		TotalStake:     binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+		RewardPerShare: new(big.Int).SetBytes(v[consts.Uint64Len*2:]),
+	}, nil
+}
+
+func (m *Module) setPool(ctx context.Context, mu state.Mutable, p *Pool) error {
+	if p.RewardPerShare.BitLen() > rewardPerShareLen*8 {
+		return ErrRewardOverflow
+	}
+	v := make([]byte, poolLen)
+	binary.BigEndian.PutUint64(v, p.Epoch)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], p.TotalStake)
+	p.RewardPerShare.FillBytes(v[consts.Uint64Len*2:])
+	return mu.Insert(ctx, m.poolKey(), v)
+}
+
+func (m *Module) getValidator(ctx context.Context, im state.Immutable, validator codec.Address) (*Validator, error) {
+	v, err := im.GetValue(ctx, m.validatorKey(validator))
+	if errors.Is(err, database.ErrNotFound) {
+		return &Validator{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != validatorLen {
+		return nil, ErrCorruptRecord
+	}
+	return &Validator{
+		SelfStake:      binary.BigEndian.Uint64(v),
+		DelegatedStake: binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+	}, nil
+}
+
+func (m *Module) setValidator(ctx context.Context, mu state.Mutable, validator codec.Address, val *Validator) error {
+	k := m.validatorKey(validator)
+	if val.SelfStake == 0 && val.DelegatedStake == 0 {
+		return mu.Remove(ctx, k)
+	}
+	v := make([]byte, validatorLen)
+	binary.BigEndian.PutUint64(v, val.SelfStake)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], val.DelegatedStake)
+	return mu.Insert(ctx, k, v)
+}
+
+func (m *Module) getPosition(
+	ctx context.Context,
+	im state.Immutable,
+	delegator codec.Address,
+	validator codec.Address,
+) (*Position, error) {
+	v, err := im.GetValue(ctx, m.positionKey(delegator, validator))
+	if errors.Is(err, database.ErrNotFound) {
+		return &Position{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != positionLen {
+		return nil, ErrCorruptRecord
+	}
+	return &Position{
+		Amount:         binary.BigEndian.Uint64(v),
+		RewardDebt:     binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+		Unclaimed:      binary.BigEndian.Uint64(v[consts.Uint64Len*2:]),
+		Unbonding:      binary.BigEndian.Uint64(v[consts.Uint64Len*3:]),
+		UnbondingEpoch: binary.BigEndian.Uint64(v[consts.Uint64Len*4:]),
+	}, nil
+}
+
+func (m *Module) setPosition(
+	ctx context.Context,
+	mu state.Mutable,
+	delegator codec.Address,
+	validator codec.Address,
+	pos *Position,
+) error {
+	k := m.positionKey(delegator, validator)
+	if pos.empty() {
+		return mu.Remove(ctx, k)
+	}
+	v := make([]byte, positionLen)
+	binary.BigEndian.PutUint64(v, pos.Amount)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], pos.RewardDebt)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2:], pos.Unclaimed)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*3:], pos.Unbonding)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*4:], pos.UnbondingEpoch)
+	return mu.Insert(ctx, k, v)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*Unstake)(nil)
+
+// Unstake withdraws [Amount] of the actor's stake with [Validator] (the
+// actor itself for self-stake). It stops earning rewards immediately and can
+// be claimed with [ClaimRewards] after [Config.UnbondingEpochs].
+//
+// Validators can either keep at least [Config.MinValidatorStake] or unstake
+// all of their self-stake.
+type Unstake struct {
+	m *Module
+
+	Validator codec.Address `json:"validator"`
+	Amount    uint64        `json:"amount"`
+}
+
+func (m *Module) NewUnstake(validator codec.Address, amount uint64) *Unstake {
+	return &Unstake{m: m, Validator: validator, Amount: amount}
+}
+
+func (u *Unstake) GetTypeID() uint8 {
+	return u.m.c.UnstakeID
+}
+
+func (u *Unstake) StateKeys(actor codec.Address, _ ids.ID) []string {
+	return []string{
+		string(u.m.poolKey()),
+		string(u.m.validatorKey(u.Validator)),
+		string(u.m.positionKey(actor, u.Validator)),
+	}
+}
+
+func (*Unstake) StateKeysMaxChunks() []uint16 {
+	return []uint16{PoolChunks, ValidatorChunks, PositionChunks}
+}
+
+func (*Unstake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *Unstake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	computeUnits := u.m.c.ComputeUnits
+	if u.Amount == 0 {
+		return false, computeUnits, OutputValueZero, nil, nil
+	}
+	p, err := u.m.getPool(ctx, mu)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	pos, err := u.m.getPosition(ctx, mu, actor, u.Validator)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if pos.Amount < u.Amount {
+		return false, computeUnits, OutputInsufficientStake, nil, nil
+	}
+	val, err := u.m.getValidator(ctx, mu, u.Validator)
+	if err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if actor == u.Validator {
+		val.SelfStake -= u.Amount
+		if val.SelfStake > 0 && val.SelfStake < u.m.c.MinValidatorStake {
+			return false, computeUnits, OutputBelowMinStake, nil, nil
+		}
+	} else {
+		val.DelegatedStake -= u.Amount
+	}
+
+	// Move the stake to unbonding (resetting the unbonding period of any
+	// stake that is already unbonding)
+	if err := settle(p, pos); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	pos.Amount -= u.Amount
+	if err := reset(p, pos); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if pos.Unbonding, err = smath.Add64(pos.Unbonding, u.Amount); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if pos.UnbondingEpoch, err = smath.Add64(p.Epoch, u.m.c.UnbondingEpochs); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	p.TotalStake -= u.Amount
+
+	if err := u.m.setValidator(ctx, mu, u.Validator, val); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := u.m.setPosition(ctx, mu, actor, u.Validator, pos); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	if err := u.m.setPool(ctx, mu, p); err != nil {
+		return false, computeUnits, nil, nil, chain.Fail(err)
+	}
+	return true, computeUnits, nil, nil, nil
+}
+
+func (u *Unstake) MaxComputeUnits(chain.Rules) uint64 {
+	return u.m.c.ComputeUnits
+}
+
+func (*Unstake) Size() int {
+	return codec.AddressLen + consts.Uint64Len
+}
+
+func (u *Unstake) Marshal(p *codec.Packer) {
+	p.PackAddress(u.Validator)
+	p.PackUint64(u.Amount)
+}
+
+func (m *Module) UnmarshalUnstake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	unstake := Unstake{m: m}
+	p.UnpackAddress(&unstake.Validator)
+	unstake.Amount = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &unstake, nil
+}
+
+func (*Unstake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
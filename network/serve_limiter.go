@@ -0,0 +1,148 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// ServeLimitConfig bounds the resources used to serve historical blocks and
+// state sync requests to other nodes. Zero values disable the corresponding
+// limit.
+type ServeLimitConfig struct {
+	// Maximum number of requests served at once. Requests over this ceiling
+	// are dropped (so that the peer retries with another node).
+	MaxConcurrentRequests int
+
+	// Bytes of responses sent per second, with bursts of up to [BytesBurst]
+	// (defaults to the rate). Requests are dropped while the budget is
+	// exhausted.
+	BytesPerSecond int
+	BytesBurst     int
+}
+
+func NewDefaultServeLimitConfig() *ServeLimitConfig {
+	return &ServeLimitConfig{MaxConcurrentRequests: 4}
+}
+
+// ServeLimiter enforces a [ServeLimitConfig] across all requests served to
+// peers.
+//
+// The size of a response is usually not known until it is built, so bytes
+// are charged after they are sent and the budget may go negative (delaying
+// the next request until it is refilled).
+type ServeLimiter struct {
+	config *ServeLimitConfig
+
+	l      sync.Mutex
+	active int
+	tokens float64
+	last   time.Time
+}
+
+func NewServeLimiter(config *ServeLimitConfig) *ServeLimiter {
+	return &ServeLimiter{config: config}
+}
+
+func (s *ServeLimiter) burst() float64 {
+	if s.config.BytesBurst > 0 {
+		return float64(s.config.BytesBurst)
+	}
+	return float64(s.config.BytesPerSecond)
+}
+
+// refill must be called with [s.l] held.
+func (s *ServeLimiter) refill(now time.Time) {
+	if s.config.BytesPerSecond <= 0 {
+		return
+	}
+	burst := s.burst()
+	if s.last.IsZero() {
+		s.tokens = burst
+	} else {
+		s.tokens += now.Sub(s.last).Seconds() * float64(s.config.BytesPerSecond)
+		if s.tokens > burst {
+			s.tokens = burst
+		}
+	}
+	s.last = now
+}
+
+// Acquire reserves a slot to serve a request. If false is returned, the
+// request should be dropped. Otherwise, [Release] must be called once the
+// request is served.
+func (s *ServeLimiter) Acquire() bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.config.MaxConcurrentRequests > 0 && s.active >= s.config.MaxConcurrentRequests {
+		return false
+	}
+	s.refill(time.Now())
+	if s.config.BytesPerSecond > 0 && s.tokens <= 0 {
+		return false
+	}
+	s.active++
+	return true
+}
+
+func (s *ServeLimiter) Release() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.active--
+}
+
+// Charge consumes [bytes] of the bandwidth budget.
+func (s *ServeLimiter) Charge(bytes int) {
+	if s.config.BytesPerSecond <= 0 {
+		return
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.refill(time.Now())
+	s.tokens -= float64(bytes)
+}
+
+// Budget returns the number of bytes that can be sent before the bandwidth
+// budget is exhausted.
+func (s *ServeLimiter) Budget() int {
+	if s.config.BytesPerSecond <= 0 {
+		return math.MaxInt
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.refill(time.Now())
+	if s.tokens <= 0 {
+		return 0
+	}
+	return int(s.tokens)
+}
+
+// ChargeResponses returns a [common.AppSender] that charges the size of all
+// responses sent with [sender] to [s].
+func (s *ServeLimiter) ChargeResponses(sender common.AppSender) common.AppSender {
+	return &chargedSender{sender, s}
+}
+
+type chargedSender struct {
+	common.AppSender
+
+	s *ServeLimiter
+}
+
+func (c *chargedSender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	c.s.Charge(len(response))
+	return c.AppSender.SendAppResponse(ctx, nodeID, requestID, response)
+}
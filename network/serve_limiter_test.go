@@ -0,0 +1,45 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeLimiterConcurrency(t *testing.T) {
+	require := require.New(t)
+
+	s := NewServeLimiter(&ServeLimitConfig{MaxConcurrentRequests: 2})
+	require.True(s.Acquire())
+	require.True(s.Acquire())
+	require.False(s.Acquire())
+	s.Release()
+	require.True(s.Acquire())
+
+	// Bandwidth is unlimited
+	s.Charge(math.MaxInt32)
+	require.Equal(math.MaxInt, s.Budget())
+}
+
+func TestServeLimiterBandwidth(t *testing.T) {
+	require := require.New(t)
+
+	// Refills a negligible amount during the test
+	s := NewServeLimiter(&ServeLimitConfig{BytesPerSecond: 1, BytesBurst: 100})
+	require.Equal(100, s.Budget())
+	require.True(s.Acquire())
+	s.Charge(60)
+	s.Release()
+	require.Equal(40, s.Budget())
+
+	// Responses may exceed the remaining budget
+	require.True(s.Acquire())
+	s.Charge(60)
+	s.Release()
+	require.Zero(s.Budget())
+	require.False(s.Acquire())
+}
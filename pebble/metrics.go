@@ -25,6 +25,10 @@ type metrics struct {
 	activeCompactions prometheus.Gauge
 
 	tombstoneCount     prometheus.Gauge
+	tombstoneRatio     prometheus.Gauge
+	diskUsage          prometheus.Gauge
+	writeAmp           prometheus.Gauge
+	readAmp            prometheus.Gauge
 	obsoleteTableSize  prometheus.Gauge
 	obsoleteTableCount prometheus.Gauge
 	zombieTableSize    prometheus.Gauge
@@ -76,6 +80,26 @@ func newMetrics() (*prometheus.Registry, *metrics, error) {
 			Name:      "tombstone_count",
 			Help:      "approximate count of internal tombstones",
 		}),
+		tombstoneRatio: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pebble",
+			Name:      "tombstone_ratio",
+			Help:      "approximate fraction of entries in sstables that are tombstones",
+		}),
+		diskUsage: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pebble",
+			Name:      "disk_usage",
+			Help:      "bytes used on-disk by sstables, logs, and other files",
+		}),
+		writeAmp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pebble",
+			Name:      "write_amplification",
+			Help:      "bytes written to disk for each byte written to the database",
+		}),
+		readAmp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pebble",
+			Name:      "read_amplification",
+			Help:      "number of sstables (and memtables) a read may need to check",
+		}),
 		obsoleteTableSize: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: "pebble",
 			Name:      "obsolete_table_size",
@@ -113,6 +137,10 @@ func newMetrics() (*prometheus.Registry, *metrics, error) {
 		r.Register(m.otherCompactions),
 		r.Register(m.activeCompactions),
 		r.Register(m.tombstoneCount),
+		r.Register(m.tombstoneRatio),
+		r.Register(m.diskUsage),
+		r.Register(m.writeAmp),
+		r.Register(m.readAmp),
 		r.Register(m.obsoleteTableSize),
 		r.Register(m.obsoleteTableCount),
 		r.Register(m.zombieTableSize),
@@ -153,7 +181,12 @@ func (db *Database) collectMetrics() {
 		select {
 		case <-t.C:
 			metrics := db.db.Metrics()
-			db.metrics.tombstoneCount.Set(float64(metrics.Keys.TombstoneCount))
+			stats := db.stats(metrics)
+			db.metrics.tombstoneCount.Set(float64(stats.TombstoneCount))
+			db.metrics.tombstoneRatio.Set(stats.TombstoneRatio)
+			db.metrics.diskUsage.Set(float64(stats.DiskUsage))
+			db.metrics.writeAmp.Set(stats.WriteAmplification)
+			db.metrics.readAmp.Set(float64(stats.ReadAmplification))
 			db.metrics.obsoleteTableSize.Set(float64(metrics.Table.ObsoleteSize))
 			db.metrics.obsoleteTableCount.Set(float64(metrics.Table.ObsoleteCount))
 			db.metrics.zombieTableSize.Set(float64(metrics.Table.ZombieSize))
@@ -165,3 +198,50 @@ func (db *Database) collectMetrics() {
 		}
 	}
 }
+
+// Stats summarizes the health of a [Database] on-disk.
+type Stats struct {
+	DiskUsage          uint64  `json:"diskUsage"` // bytes
+	WriteAmplification float64 `json:"writeAmplification"`
+	ReadAmplification  int     `json:"readAmplification"`
+	TombstoneCount     uint64  `json:"tombstoneCount"`
+	TombstoneRatio     float64 `json:"tombstoneRatio"`
+}
+
+// Stats returns the current [Stats] of [db].
+func (db *Database) Stats() *Stats {
+	return db.stats(db.db.Metrics())
+}
+
+func (db *Database) stats(metrics *pebble.Metrics) *Stats {
+	total := metrics.Total()
+	s := &Stats{
+		DiskUsage:          metrics.DiskSpaceUsage(),
+		WriteAmplification: total.WriteAmp(),
+		ReadAmplification:  metrics.ReadAmp(),
+		TombstoneCount:     metrics.Keys.TombstoneCount,
+	}
+	if entries := db.countEntries(); entries > 0 {
+		s.TombstoneRatio = float64(s.TombstoneCount) / float64(entries)
+	}
+	return s
+}
+
+// countEntries returns the number of entries (including tombstones) in all
+// sstables.
+//
+// Reads that must skip many tombstones are slow, so the ratio of
+// tombstones to entries indicates when a manual compaction may help.
+func (db *Database) countEntries() uint64 {
+	levels, err := db.db.SSTables(pebble.WithProperties())
+	if err != nil {
+		return 0
+	}
+	var entries uint64
+	for _, tables := range levels {
+		for _, table := range tables {
+			entries += table.Properties.NumEntries
+		}
+	}
+	return entries
+}
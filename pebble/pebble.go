@@ -151,7 +151,24 @@ func (db *Database) Delete(key []byte) error {
 	return updateError(db.db.Delete(key, pebble.Sync))
 }
 
+// Compact compacts all keys in [start, limit]. A nil [limit] compacts all
+// keys after [start].
 func (db *Database) Compact(start []byte, limit []byte) error {
+	if limit == nil {
+		// Pebble treats a nil [limit] as a key before all keys, so we use the
+		// last key in the database instead.
+		it := db.db.NewIter(&pebble.IterOptions{})
+		if it.Last() {
+			limit = append([]byte{}, it.Key()...)
+		}
+		if err := it.Close(); err != nil {
+			return updateError(err)
+		}
+	}
+	if pebble.DefaultComparer.Compare(start, limit) >= 0 {
+		// Nothing to compact
+		return nil
+	}
 	return updateError(db.db.Compact(start, limit, false))
 }
 
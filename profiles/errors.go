@@ -0,0 +1,14 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiles
+
+import "errors"
+
+var (
+	ErrUnknownProfile    = errors.New("unknown profile")
+	ErrInvalidDuration   = errors.New("invalid duration")
+	ErrProfileRunning    = errors.New("profile running")
+	ErrProfileNotRunning = errors.New("profile not running")
+	ErrNoProfile         = errors.New("no profile")
+)
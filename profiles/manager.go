@@ -0,0 +1,152 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package profiles captures runtime profiles on demand so that a running node
+// can be diagnosed without restarting it.
+package profiles
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	// CPU samples the CPU usage of the process over the profile duration.
+	CPU Kind = "cpu"
+	// Heap is a snapshot of live allocations taken at the end of the
+	// profile duration.
+	Heap Kind = "heap"
+	// Block records where goroutines blocked on synchronization primitives
+	// over the profile duration.
+	Block Kind = "block"
+
+	// MaxDuration bounds how long a profile can run, so a forgotten profile
+	// doesn't degrade the node indefinitely.
+	MaxDuration = 5 * time.Minute
+)
+
+type capture struct {
+	buf   *bytes.Buffer
+	timer *time.Timer
+}
+
+// Manager runs at most one profile of each [Kind] at a time and keeps the
+// result of the last completed profile of each [Kind].
+type Manager struct {
+	l       sync.Mutex
+	running map[Kind]*capture
+	results map[Kind][]byte
+}
+
+func New() *Manager {
+	return &Manager{
+		running: map[Kind]*capture{},
+		results: map[Kind][]byte{},
+	}
+}
+
+// Start begins a profile of [kind] that stops automatically after
+// [duration] (unless [Stop] is called first).
+func (m *Manager) Start(kind Kind, duration time.Duration) error {
+	if duration <= 0 || duration > MaxDuration {
+		return fmt.Errorf("%w: %v (max=%v)", ErrInvalidDuration, duration, MaxDuration)
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	if _, ok := m.running[kind]; ok {
+		return ErrProfileRunning
+	}
+	c := &capture{buf: new(bytes.Buffer)}
+	switch kind {
+	case CPU:
+		// Errors if a CPU profile is already running (i.e. the continuous
+		// profiler is capturing)
+		if err := pprof.StartCPUProfile(c.buf); err != nil {
+			return err
+		}
+	case Heap:
+	case Block:
+		runtime.SetBlockProfileRate(1)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, kind)
+	}
+	c.timer = time.AfterFunc(duration, func() {
+		m.l.Lock()
+		defer m.l.Unlock()
+
+		// The profile may have been stopped (and another started) before
+		// the timer fired.
+		if m.running[kind] != c {
+			return
+		}
+		_ = m.stop(kind, c)
+	})
+	m.running[kind] = c
+	return nil
+}
+
+// Stop ends the profile of [kind] early. The result can be fetched with
+// [Result].
+func (m *Manager) Stop(kind Kind) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	c, ok := m.running[kind]
+	if !ok {
+		return ErrProfileNotRunning
+	}
+	return m.stop(kind, c)
+}
+
+// Assumes [m.l] is held.
+func (m *Manager) stop(kind Kind, c *capture) error {
+	c.timer.Stop()
+	delete(m.running, kind)
+
+	var err error
+	switch kind {
+	case CPU:
+		pprof.StopCPUProfile()
+	case Heap:
+		runtime.GC() // ensure the profile is up to date
+		err = pprof.Lookup("heap").WriteTo(c.buf, 0)
+	case Block:
+		err = pprof.Lookup("block").WriteTo(c.buf, 0)
+		runtime.SetBlockProfileRate(0)
+	}
+	if err != nil {
+		return err
+	}
+	m.results[kind] = c.buf.Bytes()
+	return nil
+}
+
+// Result returns the last completed profile of [kind] in the pprof format.
+func (m *Manager) Result(kind Kind) ([]byte, error) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	if _, ok := m.running[kind]; ok {
+		return nil, ErrProfileRunning
+	}
+	result, ok := m.results[kind]
+	if !ok {
+		return nil, ErrNoProfile
+	}
+	return result, nil
+}
+
+// Shutdown stops all running profiles.
+func (m *Manager) Shutdown() {
+	for _, kind := range []Kind{CPU, Heap, Block} {
+		_ = m.Stop(kind)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package profiles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	require := require.New(t)
+
+	m := New()
+	require.ErrorIs(m.Start(CPU, 0), ErrInvalidDuration)
+	require.ErrorIs(m.Start(CPU, MaxDuration+1), ErrInvalidDuration)
+	require.ErrorIs(m.Start("goroutine", time.Second), ErrUnknownProfile)
+	_, err := m.Result(CPU)
+	require.ErrorIs(err, ErrNoProfile)
+
+	// Profiles stop after their duration
+	require.NoError(m.Start(CPU, 50*time.Millisecond))
+	require.ErrorIs(m.Start(CPU, time.Second), ErrProfileRunning)
+	_, err = m.Result(CPU)
+	require.ErrorIs(err, ErrProfileRunning)
+	require.Eventually(func() bool {
+		result, err := m.Result(CPU)
+		return err == nil && len(result) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Profiles can be stopped early
+	for _, kind := range []Kind{Heap, Block} {
+		require.NoError(m.Start(kind, MaxDuration))
+		require.NoError(m.Stop(kind))
+		require.ErrorIs(m.Stop(kind), ErrProfileNotRunning)
+		result, err := m.Result(kind)
+		require.NoError(err)
+		require.NotEmpty(result)
+	}
+}
@@ -5,6 +5,7 @@ package pubsub
 
 import (
 	"io"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -24,6 +25,10 @@ type Connection struct {
 	// The websocket connection.
 	conn *websocket.Conn
 
+	// The IP of the peer and whether its connection slot has been released.
+	ip      string
+	release sync.Once
+
 	// Buffered channel of outbound messages.
 	mb *MessageBuffer
 
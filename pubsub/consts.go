@@ -4,6 +4,7 @@
 package pubsub
 
 import (
+	"compress/flate"
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/units"
@@ -19,5 +20,10 @@ const (
 	MaxReadMessageSize  = consts.NetworkSizeLimit
 	MaxWriteMessageSize = 16 * units.MiB
 	MaxMessageWait      = 50 * time.Millisecond
+	MaxBatchMessages    = 256
 	MaxPendingMessages  = 1024
+	MaxConnections      = 8192
+	MaxConnectionsPerIP = 0 // unlimited
+	EnableCompression   = true
+	CompressionLevel    = flate.BestSpeed
 )
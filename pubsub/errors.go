@@ -12,4 +12,7 @@ var (
 	ErrInvalidCommand       = errors.New("invalid command")
 	ErrMessageTooLarge      = errors.New("message too large")
 	ErrClosed               = errors.New("closed")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrTooManyConnections   = errors.New("too many connections")
+	ErrTooManyIPConnections = errors.New("too many connections from ip")
 )
@@ -22,19 +22,27 @@ type MessageBuffer struct {
 	pending      [][]byte
 	pendingSize  int
 	maxSize      int
+	maxMessages  int
 	timeout      time.Duration
 	pendingTimer *timer.Timer
 	closed       bool
 }
 
-func NewMessageBuffer(log logging.Logger, pending int, maxSize int, timeout time.Duration) *MessageBuffer {
+func NewMessageBuffer(
+	log logging.Logger,
+	pending int,
+	maxSize int,
+	maxMessages int,
+	timeout time.Duration,
+) *MessageBuffer {
 	m := &MessageBuffer{
 		Queue: make(chan []byte, pending),
 
-		log:     log,
-		pending: [][]byte{},
-		maxSize: maxSize,
-		timeout: timeout,
+		log:         log,
+		pending:     [][]byte{},
+		maxSize:     maxSize,
+		maxMessages: maxMessages,
+		timeout:     timeout,
 	}
 	m.pendingTimer = timer.NewTimer(func() {
 		m.l.Lock()
@@ -120,6 +128,12 @@ func (m *MessageBuffer) Send(msg []byte) error {
 
 	m.pendingSize += l
 	m.pending = append(m.pending, msg)
+
+	// Flush immediately if we've reached the max batch count
+	if m.maxMessages > 0 && len(m.pending) >= m.maxMessages {
+		m.pendingTimer.Cancel()
+		return m.clearPending()
+	}
 	if len(m.pending) == 1 {
 		m.pendingTimer.SetTimeoutIn(m.timeout)
 	}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBufferMaxMessages(t *testing.T) {
+	require := require.New(t)
+
+	// Use a long timeout so that only the count limit can trigger a flush
+	mb := NewMessageBuffer(logging.NoLog{}, 10, MaxWriteMessageSize, 3, time.Hour)
+	for i := 0; i < 7; i++ {
+		require.NoError(mb.Send([]byte{byte(i)}))
+	}
+	require.Len(mb.Queue, 2)
+	for i := 0; i < 2; i++ {
+		msgs, err := ParseBatchMessage(MaxWriteMessageSize, <-mb.Queue)
+		require.NoError(err)
+		require.Len(msgs, 3)
+		require.Equal([]byte{byte(i * 3)}, msgs[0])
+	}
+
+	// Remaining message is flushed on close
+	require.NoError(mb.Close())
+	msgs, err := ParseBatchMessage(MaxWriteMessageSize, <-mb.Queue)
+	require.NoError(err)
+	require.Equal([][]byte{{6}}, msgs)
+}
+
+func TestMessageBufferTimeout(t *testing.T) {
+	require := require.New(t)
+
+	mb := NewMessageBuffer(logging.NoLog{}, 10, MaxWriteMessageSize, 0, 10*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		require.NoError(mb.Send([]byte{byte(i)}))
+	}
+	select {
+	case batch := <-mb.Queue:
+		msgs, err := ParseBatchMessage(MaxWriteMessageSize, batch)
+		require.NoError(err)
+		require.Len(msgs, 5)
+	case <-time.After(5 * time.Second):
+		require.FailNow("batch was not flushed")
+	}
+	require.NoError(mb.Close())
+}
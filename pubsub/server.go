@@ -4,7 +4,12 @@
 package pubsub
 
 import (
+	"context"
+	"crypto/subtle"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -28,12 +33,31 @@ type ServerConfig struct {
 	MaxWriteMessageSize int
 	// Maximum delay for a single message to wait in the buffer
 	MaxMessageWait time.Duration
+	// Maximum number of messages to include in a single batch. A batch is
+	// flushed when it reaches this count, [MaxWriteMessageSize], or has
+	// waited [MaxMessageWait] (whichever comes first). If 0, batches are
+	// only limited by size and time.
+	MaxBatchMessages int
+	// Negotiate permessage-deflate compression with peers that support it.
+	EnableCompression bool
+	// Compression level used when compression is negotiated (see
+	// compress/flate).
+	CompressionLevel int
 	// Time allowed to write a message to the peer.
 	WriteWait time.Duration
 	// Time allowed to read the next pong message from the peer.
 	PongWait time.Duration
 	// Send pings to peer with this period. Must be less than pongWait.
 	PingPeriod time.Duration
+	// Maximum number of concurrent connections. If 0, there is no limit.
+	MaxConnections int
+	// Maximum number of concurrent connections from a single IP. If 0,
+	// there is no limit.
+	MaxConnectionsPerIP int
+	// If non-empty, peers must provide one of these tokens either in the
+	// "Authorization: Bearer <token>" header or in the "token" query
+	// parameter (for clients that can't set headers).
+	AuthTokens []string
 }
 
 func NewDefaultServerConfig() *ServerConfig {
@@ -44,9 +68,14 @@ func NewDefaultServerConfig() *ServerConfig {
 		MaxReadMessageSize:  MaxReadMessageSize,
 		MaxWriteMessageSize: MaxWriteMessageSize,
 		MaxMessageWait:      MaxMessageWait,
+		MaxBatchMessages:    MaxBatchMessages,
+		EnableCompression:   EnableCompression,
+		CompressionLevel:    CompressionLevel,
 		WriteWait:           WriteWait,
 		PongWait:            PongWait,
 		PingPeriod:          (9 * PongWait) / 10,
+		MaxConnections:      MaxConnections,
+		MaxConnectionsPerIP: MaxConnectionsPerIP,
 	}
 }
 
@@ -59,6 +88,20 @@ type Server struct {
 	callback Callback
 	upgrader *websocket.Upgrader
 	conns    *Connections
+
+	// Connection limits and auth tokens are copied from [config] so they
+	// can be updated with [SetLimits].
+	slotsL              sync.Mutex
+	slots               int
+	slotsByIP           map[string]int
+	maxConnections      int
+	maxConnectionsPerIP int
+	authTokens          []string
+	closed              bool
+
+	// pumps tracks the write pumps of all connections so that [Close] can
+	// wait for pending messages to be written.
+	pumps sync.WaitGroup
 }
 
 // New returns a new Server instance. The callback function [f] is called
@@ -76,29 +119,129 @@ func New(
 			CheckOrigin: func(*http.Request) bool {
 				return true
 			},
-			ReadBufferSize:  config.ReadBufferSize,
-			WriteBufferSize: config.WriteBufferSize,
+			ReadBufferSize:    config.ReadBufferSize,
+			WriteBufferSize:   config.WriteBufferSize,
+			EnableCompression: config.EnableCompression,
 		},
-		conns: NewConnections(),
+		conns:               NewConnections(),
+		slotsByIP:           map[string]int{},
+		maxConnections:      config.MaxConnections,
+		maxConnectionsPerIP: config.MaxConnectionsPerIP,
+		authTokens:          config.AuthTokens,
+	}
+}
+
+// authorized returns whether [r] carries one of the configured auth tokens.
+func (s *Server) authorized(r *http.Request) bool {
+	s.slotsL.Lock()
+	authTokens := s.authTokens
+	s.slotsL.Unlock()
+
+	if len(authTokens) == 0 {
+		return true
+	}
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if len(token) == 0 {
+		return false
+	}
+	for _, allowed := range authTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// reserveSlot reserves a connection slot for [ip] if doing so would not
+// exceed any connection limits.
+func (s *Server) reserveSlot(ip string) error {
+	s.slotsL.Lock()
+	defer s.slotsL.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.maxConnections > 0 && s.slots >= s.maxConnections {
+		return ErrTooManyConnections
+	}
+	if s.maxConnectionsPerIP > 0 && s.slotsByIP[ip] >= s.maxConnectionsPerIP {
+		return ErrTooManyIPConnections
+	}
+	s.slots++
+	s.slotsByIP[ip]++
+	return nil
+}
+
+func (s *Server) releaseSlot(ip string) {
+	s.slotsL.Lock()
+	defer s.slotsL.Unlock()
+
+	s.slots--
+	s.slotsByIP[ip]--
+	if s.slotsByIP[ip] <= 0 {
+		delete(s.slotsByIP, ip)
 	}
 }
 
 // ServeHTTP adds a connection to the server, and starts go routines for
 // reading and writing.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		s.log.Debug("rejecting connection",
+			zap.String("addr", r.RemoteAddr),
+			zap.Error(ErrUnauthorized),
+		)
+		http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if err := s.reserveSlot(ip); err != nil {
+		s.log.Debug("rejecting connection",
+			zap.String("addr", r.RemoteAddr),
+			zap.Error(err),
+		)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
 	// Upgrader.upgrade() is called to upgrade the HTTP connection.
 	// No nead to set any headers so we pass nil as the last argument.
 	wsConn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		s.releaseSlot(ip)
 		s.log.Warn("failed to upgrade",
 			zap.Error(err),
 		)
 		return
 	}
+	if s.config.EnableCompression {
+		// This only has an effect if compression was negotiated with the peer
+		if err := wsConn.SetCompressionLevel(s.config.CompressionLevel); err != nil {
+			s.log.Warn("failed to set compression level",
+				zap.Error(err),
+			)
+			_ = wsConn.Close()
+			s.releaseSlot(ip)
+			return
+		}
+	}
 	s.addConnection(&Connection{
-		s:      s,
-		conn:   wsConn,
-		mb:     NewMessageBuffer(s.log, s.config.MaxPendingMessages, s.config.MaxWriteMessageSize, s.config.MaxMessageWait),
+		s:    s,
+		conn: wsConn,
+		ip:   ip,
+		mb: NewMessageBuffer(
+			s.log,
+			s.config.MaxPendingMessages,
+			s.config.MaxWriteMessageSize,
+			s.config.MaxBatchMessages,
+			s.config.MaxMessageWait,
+		),
 		active: atomic.Bool{},
 	})
 	s.log.Debug("added pubsub connection", zap.Stringer("addr", wsConn.RemoteAddr()))
@@ -124,18 +267,79 @@ func (s *Server) Publish(msg []byte, conns *Connections) []*Connection {
 // addConnection adds [conn] to the servers connection set and starts go
 // routines for reading and writing messages for the connection.
 func (s *Server) addConnection(conn *Connection) {
+	s.slotsL.Lock()
+	if s.closed {
+		s.slotsL.Unlock()
+		_ = conn.conn.Close()
+		s.releaseSlot(conn.ip)
+		return
+	}
+	s.pumps.Add(1)
 	conn.active.Store(true)
 	s.conns.Add(conn)
+	s.slotsL.Unlock()
 
-	go conn.writePump()
+	go func() {
+		defer s.pumps.Done()
+		conn.writePump()
+	}()
 	go conn.readPump()
 }
 
 // removeConnection removes [conn] from the servers connection set.
+//
+// This is called by both the read and write pumps, so we ensure [conn]'s
+// slot is only released once.
 func (s *Server) removeConnection(conn *Connection) {
 	s.conns.Remove(conn)
+	conn.release.Do(func() {
+		s.releaseSlot(conn.ip)
+	})
 }
 
 func (s *Server) Connections() *Connections {
 	return s.conns
 }
+
+// SetLimits updates the connection limits and auth tokens of [s]. Existing
+// connections are not closed if they exceed the new limits.
+func (s *Server) SetLimits(maxConnections int, maxConnectionsPerIP int, authTokens []string) {
+	s.slotsL.Lock()
+	defer s.slotsL.Unlock()
+
+	s.maxConnections = maxConnections
+	s.maxConnectionsPerIP = maxConnectionsPerIP
+	s.authTokens = authTokens
+}
+
+// Close stops accepting new connections and closes all existing
+// connections after writing any pending messages to them.
+//
+// Close returns once all pending messages are written or [ctx] is done,
+// whichever comes first.
+func (s *Server) Close(ctx context.Context) error {
+	s.slotsL.Lock()
+	if s.closed {
+		s.slotsL.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	s.slotsL.Unlock()
+
+	// Closing the message buffer of a connection flushes any pending messages
+	// to its write pump, which exits after they are written.
+	for _, conn := range s.conns.Conns() {
+		conn.deactivate()
+	}
+	done := make(chan struct{})
+	go func() {
+		s.pumps.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
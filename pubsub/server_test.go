@@ -7,7 +7,9 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -274,3 +276,122 @@ func TestServerPublishSpecific(t *testing.T) {
 	// Wait for the server to finish shutting down
 	<-serverDone
 }
+
+func dial(t *testing.T, uri string, header http.Header) (*websocket.Conn, int) {
+	conn, resp, err := websocket.DefaultDialer.Dial(uri, header)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		return nil, resp.StatusCode
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, resp.StatusCode
+}
+
+func TestServerConnectionLimits(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NewDefaultServerConfig()
+	cfg.MaxConnections = 3
+	cfg.MaxConnectionsPerIP = 2
+	handler := New(logging.NoLog{}, cfg, nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	uri := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	conn, status := dial(t, uri, nil)
+	require.NotNil(conn)
+	require.Equal(http.StatusSwitchingProtocols, status)
+	_, status = dial(t, uri, nil)
+	require.Equal(http.StatusSwitchingProtocols, status)
+
+	// All test connections come from the same IP
+	conn2, status := dial(t, uri, nil)
+	require.Nil(conn2)
+	require.Equal(http.StatusServiceUnavailable, status)
+
+	// Closing a connection frees up its slot
+	require.NoError(conn.Close())
+	require.Eventually(func() bool {
+		_, status := dial(t, uri, nil)
+		return status == http.StatusSwitchingProtocols
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestServerAuth(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NewDefaultServerConfig()
+	cfg.AuthTokens = []string{"secret"}
+	handler := New(logging.NoLog{}, cfg, nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	uri := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, status := dial(t, uri, nil)
+	require.Equal(http.StatusUnauthorized, status)
+	_, status = dial(t, uri, http.Header{"Authorization": []string{"Bearer wrong"}})
+	require.Equal(http.StatusUnauthorized, status)
+	_, status = dial(t, uri, http.Header{"Authorization": []string{"Bearer secret"}})
+	require.Equal(http.StatusSwitchingProtocols, status)
+	_, status = dial(t, uri+"?token=secret", nil)
+	require.Equal(http.StatusSwitchingProtocols, status)
+}
+
+func TestServerClose(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NewDefaultServerConfig()
+	cfg.MaxMessageWait = time.Hour // only flushed on close
+	handler := New(logging.NoLog{}, cfg, nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	uri := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	conn, status := dial(t, uri, nil)
+	require.Equal(http.StatusSwitchingProtocols, status)
+	require.Eventually(func() bool {
+		return handler.Connections().Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	msg := []byte("hello")
+	require.Empty(handler.Publish(msg, handler.Connections()))
+
+	// Pending messages are written before the connection is closed
+	require.NoError(handler.Close(context.Background()))
+	_, read, err := conn.ReadMessage()
+	require.NoError(err)
+	msgs, err := ParseBatchMessage(consts.NetworkSizeLimit, read)
+	require.NoError(err)
+	require.Equal([][]byte{msg}, msgs)
+	_, _, err = conn.ReadMessage()
+	require.True(websocket.IsCloseError(err, websocket.CloseNoStatusReceived))
+
+	// New connections are rejected
+	_, status = dial(t, uri, nil)
+	require.Equal(http.StatusServiceUnavailable, status)
+	require.ErrorIs(handler.Close(context.Background()), ErrClosed)
+}
+
+func TestServerSetLimits(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NewDefaultServerConfig()
+	cfg.MaxConnections = 1
+	handler := New(logging.NoLog{}, cfg, nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	uri := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, status := dial(t, uri, nil)
+	require.Equal(http.StatusSwitchingProtocols, status)
+	_, status = dial(t, uri, nil)
+	require.Equal(http.StatusServiceUnavailable, status)
+
+	handler.SetLimits(2, 0, []string{"secret"})
+	_, status = dial(t, uri, nil)
+	require.Equal(http.StatusUnauthorized, status)
+	_, status = dial(t, uri+"?token=secret", nil)
+	require.Equal(http.StatusSwitchingProtocols, status)
+}
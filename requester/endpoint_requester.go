@@ -0,0 +1,306 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Config determines how an [EndpointRequester] spreads requests over its
+// endpoints and retries those that fail.
+type Config struct {
+	// Timeout is the maximum duration of each attempt of a request (0 for
+	// no timeout other than the deadline of the request context).
+	Timeout time.Duration `json:"timeout"`
+
+	// MaxRetries is how many times a request that failed with a transient
+	// error (see [IsTransient]) is retried. Retries are sent to the next
+	// healthy endpoint immediately or, if no endpoint is healthy, after an
+	// exponential backoff from [MinBackoff] to [MaxBackoff].
+	MaxRetries int           `json:"maxRetries"`
+	MinBackoff time.Duration `json:"minBackoff"`
+	MaxBackoff time.Duration `json:"maxBackoff"`
+
+	// Cooldown is how long an endpoint is considered unhealthy after a
+	// transient error (unless a health check succeeds in the meantime).
+	Cooldown time.Duration `json:"cooldown"`
+
+	// HealthMethod is the method sent to each endpoint by
+	// [EndpointRequester.CheckHealth]. Clients that support failover set it
+	// to a cheap method of their API if it is empty.
+	HealthMethod string `json:"healthMethod"`
+}
+
+// DefaultConfig doesn't retry or time out requests, which is the same
+// behavior as a client with a single endpoint.
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:    0,
+		MaxRetries: 0,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+		Cooldown:   30 * time.Second,
+	}
+}
+
+// WithDefaultHealthMethod returns a copy of [c] that sends [method] in health
+// checks if [c] doesn't specify a method.
+func (c *Config) WithDefaultHealthMethod(method string) *Config {
+	cp := *c
+	if len(cp.HealthMethod) == 0 {
+		cp.HealthMethod = method
+	}
+	return &cp
+}
+
+func (c *Config) backoff(attempt int) time.Duration {
+	backoff := c.MinBackoff
+	for i := 0; i < attempt && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > c.MaxBackoff {
+		return c.MaxBackoff
+	}
+	return backoff
+}
+
+type endpoint struct {
+	uri string
+
+	// Consecutive transient failures of the endpoint. The endpoint is
+	// unhealthy until [downUntil].
+	failures  int
+	lastErr   error
+	downUntil time.Time
+}
+
+func (e *endpoint) healthy(now time.Time) bool {
+	return !now.Before(e.downUntil)
+}
+
+// EndpointStatus is the health of an endpoint of an [EndpointRequester].
+type EndpointStatus struct {
+	URI       string `json:"uri"`
+	Healthy   bool   `json:"healthy"`
+	Failures  int    `json:"failures"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// EndpointRequester sends JSON-RPC requests to the first healthy endpoint of
+// a list of equivalent endpoints (like the same API served by different
+// nodes), in order of preference.
+type EndpointRequester struct {
+	cli    *http.Client
+	base   string
+	config Config
+
+	l         sync.Mutex
+	endpoints []*endpoint
+}
+
+func New(uri, base string) *EndpointRequester {
+	return NewWithConfig([]string{uri}, base, DefaultConfig())
+}
+
+// NewWithConfig returns an [EndpointRequester] that fails over between
+// [uris] (in order of preference) as configured by [config].
+func NewWithConfig(uris []string, base string, config *Config) *EndpointRequester {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 100_000
+	t.MaxConnsPerHost = 100_000
+	t.MaxIdleConnsPerHost = 100_000
+
+	endpoints := make([]*endpoint, len(uris))
+	for i, uri := range uris {
+		endpoints[i] = &endpoint{uri: uri}
+	}
+	return &EndpointRequester{
+		// Requests are bounded by [config.Timeout] instead of a client
+		// timeout, so that it can be overridden per request
+		cli:       &http.Client{Transport: t},
+		base:      base,
+		config:    *config,
+		endpoints: endpoints,
+	}
+}
+
+func (e *EndpointRequester) SendRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+	reply interface{},
+	options ...Option,
+) error {
+	timeout := e.config.Timeout
+	if ops := NewOptions(options); ops.timeout > 0 {
+		timeout = ops.timeout
+	}
+	for attempt := 0; ; attempt++ {
+		ep, _ := e.next()
+		if ep == nil {
+			return ErrNoEndpoints
+		}
+		err := e.send(ctx, ep, timeout, method, params, reply, options...)
+		if ctx.Err() != nil {
+			// The caller gave up, so the endpoint may not be at fault
+			return err
+		}
+		e.record(ep, err)
+		if err == nil || !IsTransient(err) || attempt >= e.config.MaxRetries {
+			return err
+		}
+
+		// Retry on the next healthy endpoint right away (if any)
+		if _, healthy := e.next(); healthy {
+			continue
+		}
+		t := time.NewTimer(e.config.backoff(attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+	}
+}
+
+func (e *EndpointRequester) send(
+	ctx context.Context,
+	ep *endpoint,
+	timeout time.Duration,
+	method string,
+	params interface{},
+	reply interface{},
+	options ...Option,
+) error {
+	uri, err := url.Parse(ep.uri)
+	if err != nil {
+		return err
+	}
+	attemptCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err = SendJSONRequest(
+		attemptCtx,
+		e.cli,
+		uri,
+		fmt.Sprintf("%s.%s", e.base, method),
+		params,
+		reply,
+		options...,
+	)
+	if err != nil && ctx.Err() == nil && attemptCtx.Err() != nil {
+		return fmt.Errorf("%w: %s after %s", ErrTimeout, ep.uri, timeout)
+	}
+	return err
+}
+
+// next returns the first healthy endpoint or, if there are none, the one
+// that will recover the soonest.
+func (e *EndpointRequester) next() (*endpoint, bool) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	var (
+		now  = time.Now()
+		next *endpoint
+	)
+	for _, ep := range e.endpoints {
+		if ep.healthy(now) {
+			return ep, true
+		}
+		if next == nil || ep.downUntil.Before(next.downUntil) {
+			next = ep
+		}
+	}
+	return next, false
+}
+
+// record updates the health of [ep] after a request to it failed with [err]
+// (which may be nil). Only transient errors make an endpoint unhealthy,
+// because anything else means it responded.
+func (e *EndpointRequester) record(ep *endpoint, err error) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if err == nil || !IsTransient(err) {
+		ep.failures = 0
+		ep.lastErr = nil
+		ep.downUntil = time.Time{}
+		return
+	}
+	ep.failures++
+	ep.lastErr = err
+	ep.downUntil = time.Now().Add(e.config.Cooldown)
+}
+
+// Endpoints returns the health of each endpoint, in order of preference.
+func (e *EndpointRequester) Endpoints() []*EndpointStatus {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	now := time.Now()
+	statuses := make([]*EndpointStatus, len(e.endpoints))
+	for i, ep := range e.endpoints {
+		statuses[i] = &EndpointStatus{
+			URI:      ep.uri,
+			Healthy:  ep.healthy(now),
+			Failures: ep.failures,
+		}
+		if ep.lastErr != nil {
+			statuses[i].LastError = ep.lastErr.Error()
+		}
+	}
+	return statuses
+}
+
+// CheckHealth sends [Config.HealthMethod] to every endpoint (without
+// retries) and updates their health with the result, so that endpoints that
+// recovered are preferred again before their cooldown ends.
+func (e *EndpointRequester) CheckHealth(ctx context.Context) error {
+	if len(e.config.HealthMethod) == 0 {
+		return ErrNoHealthCheck
+	}
+	var wg sync.WaitGroup
+	for _, ep := range e.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+
+			var reply json.RawMessage
+			err := e.send(ctx, ep, e.config.Timeout, e.config.HealthMethod, nil, &reply)
+			if ctx.Err() != nil {
+				return
+			}
+			e.record(ep, err)
+		}(ep)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// MonitorHealth calls [CheckHealth] every [interval] until [ctx] is done.
+func (e *EndpointRequester) MonitorHealth(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := e.CheckHealth(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package requester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testServer responds to each request with the status code returned by
+// [status] (and a JSON-RPC result if it is 200).
+func testServer(t *testing.T, status func(n int64) int) (*httptest.Server, *atomic.Int64) {
+	var requests atomic.Int64
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		code := status(requests.Add(1))
+		w.WriteHeader(code)
+		if code == http.StatusOK {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"pong","id":1}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s, &requests
+}
+
+func testConfig() *Config {
+	c := DefaultConfig()
+	c.MaxRetries = 2
+	c.MinBackoff = time.Millisecond
+	c.MaxBackoff = 2 * time.Millisecond
+	c.HealthMethod = "ping"
+	return c
+}
+
+func TestFailover(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	healthy := atomic.Bool{}
+	primary, primaryRequests := testServer(t, func(int64) int {
+		if healthy.Load() {
+			return http.StatusOK
+		}
+		return http.StatusServiceUnavailable
+	})
+	secondary, secondaryRequests := testServer(t, func(int64) int { return http.StatusOK })
+	r := NewWithConfig([]string{primary.URL, secondary.URL}, "test", testConfig())
+
+	var reply string
+	require.NoError(r.SendRequest(ctx, "ping", nil, &reply))
+	require.Equal("pong", reply)
+	require.Equal(int64(1), primaryRequests.Load())
+	require.Equal(int64(1), secondaryRequests.Load())
+	statuses := r.Endpoints()
+	require.False(statuses[0].Healthy)
+	require.Equal(1, statuses[0].Failures)
+	require.Contains(statuses[0].LastError, "503")
+	require.True(statuses[1].Healthy)
+
+	// Unhealthy endpoints are skipped until they recover
+	require.NoError(r.SendRequest(ctx, "ping", nil, &reply))
+	require.Equal(int64(1), primaryRequests.Load())
+	require.Equal(int64(2), secondaryRequests.Load())
+
+	healthy.Store(true)
+	require.NoError(r.CheckHealth(ctx))
+	require.True(r.Endpoints()[0].Healthy)
+	require.NoError(r.SendRequest(ctx, "ping", nil, &reply))
+	require.Equal(int64(3), primaryRequests.Load())
+}
+
+func TestRetries(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Transient errors are retried with backoff
+	s, requests := testServer(t, func(n int64) int {
+		if n < 3 {
+			return http.StatusTooManyRequests
+		}
+		return http.StatusOK
+	})
+	r := NewWithConfig([]string{s.URL}, "test", testConfig())
+	var reply string
+	require.NoError(r.SendRequest(ctx, "ping", nil, &reply))
+	require.Equal(int64(3), requests.Load())
+
+	// Retries are limited
+	s, requests = testServer(t, func(int64) int { return http.StatusBadGateway })
+	r = NewWithConfig([]string{s.URL}, "test", testConfig())
+	err := r.SendRequest(ctx, "ping", nil, &reply)
+	var se *StatusError
+	require.ErrorAs(err, &se)
+	require.Equal(http.StatusBadGateway, se.Code)
+	require.Equal(int64(3), requests.Load())
+
+	// Errors returned by the API are not retried
+	s, requests = testServer(t, func(int64) int { return http.StatusBadRequest })
+	r = NewWithConfig([]string{s.URL}, "test", testConfig())
+	err = r.SendRequest(ctx, "ping", nil, &reply)
+	require.False(IsTransient(err))
+	require.Equal(int64(1), requests.Load())
+	require.True(r.Endpoints()[0].Healthy)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	block := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-block
+	}))
+	defer s.Close()
+	defer close(block)
+
+	c := testConfig()
+	c.MaxRetries = 0
+	r := NewWithConfig([]string{s.URL}, "test", c)
+	var reply string
+	err := r.SendRequest(ctx, "ping", nil, &reply, WithTimeout(10*time.Millisecond))
+	require.ErrorIs(err, ErrTimeout)
+	require.True(IsTransient(err))
+
+	// Cancellation by the caller doesn't affect the health of the endpoint
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = r.SendRequest(cctx, "ping", nil, &reply)
+	require.ErrorIs(err, context.Canceled)
+	require.Equal(1, r.Endpoints()[0].Failures)
+}
+
+func TestDefaultNoTimeout(t *testing.T) {
+	require := require.New(t)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"pong","id":1}`))
+	}))
+	defer s.Close()
+
+	// Like a client without failover, only the caller bounds the request
+	r := New(s.URL, "test")
+	var reply string
+	require.NoError(r.SendRequest(context.Background(), "ping", nil, &reply))
+	require.Equal("pong", reply)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := r.SendRequest(ctx, "ping", nil, &reply)
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.Zero(r.Endpoints()[0].Failures)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package requester
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNoEndpoints   = errors.New("no endpoints")
+	ErrUnreachable   = errors.New("endpoint unreachable")
+	ErrTimeout       = errors.New("request timed out")
+	ErrNoHealthCheck = errors.New("no health check method")
+)
+
+// StatusError is returned when an endpoint responds with a non-successful
+// status code.
+type StatusError struct {
+	URI  string
+	Code int
+	Body []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received status code: %d %s %s", e.Code, e.Body, e.URI)
+}
+
+// IsTransient returns true if a request that failed with [err] may succeed
+// if retried (possibly on another endpoint). Errors returned by the API
+// itself are never transient.
+func IsTransient(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= http.StatusInternalServerError || se.Code == http.StatusTooManyRequests
+	}
+	return errors.Is(err, ErrUnreachable) || errors.Is(err, ErrTimeout)
+}
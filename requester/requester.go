@@ -20,6 +20,7 @@ type Option func(*Options)
 type Options struct {
 	headers     http.Header
 	queryParams url.Values
+	timeout     time.Duration
 }
 
 func NewOptions(ops []Option) *Options {
@@ -57,47 +58,11 @@ func WithQueryParam(key, val string) Option {
 	}
 }
 
-type EndpointRequester struct {
-	cli       *http.Client
-	uri, base string
-}
-
-func New(uri, base string) *EndpointRequester {
-	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.MaxIdleConns = 100_000
-	t.MaxConnsPerHost = 100_000
-	t.MaxIdleConnsPerHost = 100_000
-
-	return &EndpointRequester{
-		cli: &http.Client{
-			Timeout:   10 * time.Second,
-			Transport: t,
-		},
-		uri:  uri,
-		base: base,
-	}
-}
-
-func (e *EndpointRequester) SendRequest(
-	ctx context.Context,
-	method string,
-	params interface{},
-	reply interface{},
-	options ...Option,
-) error {
-	uri, err := url.Parse(e.uri)
-	if err != nil {
-		return err
+// WithTimeout overrides [Config.Timeout] for a single request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.timeout = timeout
 	}
-	return SendJSONRequest(
-		ctx,
-		e.cli,
-		uri,
-		fmt.Sprintf("%s.%s", e.base, method),
-		params,
-		reply,
-		options...,
-	)
 }
 
 func SendJSONRequest(
@@ -132,7 +97,7 @@ func SendJSONRequest(
 
 	resp, err := cli.Do(request)
 	if err != nil {
-		return fmt.Errorf("failed to issue request: %w", err)
+		return fmt.Errorf("%w: failed to issue request: %w", ErrUnreachable, err)
 	}
 
 	// Return an error for any non successful status code
@@ -140,7 +105,7 @@ func SendJSONRequest(
 		// Drop any error during close to report the original error
 		all, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return fmt.Errorf("received status code: %d %s %s", resp.StatusCode, all, uri.String())
+		return &StatusError{URI: uri.String(), Code: resp.StatusCode, Body: all}
 	}
 
 	if err := rpc.DecodeClientResponse(resp.Body, reply); err != nil {
@@ -0,0 +1,120 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespace determines who may call the methods served by a handler.
+type Namespace uint8
+
+const (
+	// PublicNamespace handlers can be called by anyone.
+	PublicNamespace Namespace = iota
+	// AdminNamespace handlers can only be called with one of the
+	// [AccessConfig.AdminTokens] (if any are set).
+	AdminNamespace
+)
+
+// NamespaceOf returns the [Namespace] of the handler served at [endpoint].
+// Any endpoint under [AdminEndpoint] (including those registered by a
+// [Controller]) is in [AdminNamespace].
+func NamespaceOf(endpoint string) Namespace {
+	if endpoint == AdminEndpoint || strings.HasPrefix(endpoint, AdminEndpoint+"/") {
+		return AdminNamespace
+	}
+	return PublicNamespace
+}
+
+// AccessConfig controls who can reach the handlers wrapped with [Wrap].
+type AccessConfig struct {
+	// If non-empty, requests to [AdminNamespace] handlers must provide one
+	// of these tokens in the "Authorization: Bearer <token>" header.
+	AdminTokens []string
+
+	// Origins that browsers may make cross-origin requests from ("*" allows
+	// any origin). If empty, no CORS headers are returned.
+	CORSAllowedOrigins []string
+	// How long browsers may cache the result of a preflight request.
+	CORSMaxAge time.Duration
+}
+
+// Wrap returns [h] with authentication (for [AdminNamespace]) and CORS
+// applied.
+//
+// CORS is applied first so that preflight requests (which never carry
+// credentials) can be answered for admin handlers.
+func (c *AccessConfig) Wrap(namespace Namespace, h http.Handler) http.Handler {
+	if namespace == AdminNamespace && len(c.AdminTokens) > 0 {
+		h = c.authenticate(h)
+	}
+	if len(c.CORSAllowedOrigins) > 0 {
+		h = c.cors(h)
+	}
+	return h
+}
+
+func (c *AccessConfig) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (c *AccessConfig) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := []byte(strings.TrimPrefix(auth, "Bearer "))
+	for _, allowed := range c.AdminTokens {
+		if subtle.ConstantTimeCompare(token, []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *AccessConfig) allowedOrigin(origin string) bool {
+	for _, allowed := range c.CORSAllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *AccessConfig) cors(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if len(origin) == 0 || !c.allowedOrigin(origin) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+
+		// Answer preflight requests without invoking [h]
+		if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+			header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			if c.CORSMaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.CORSMaxAge/time.Second)))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceOf(t *testing.T) {
+	require := require.New(t)
+	require.Equal(PublicNamespace, NamespaceOf(JSONRPCEndpoint))
+	require.Equal(AdminNamespace, NamespaceOf(AdminEndpoint))
+	require.Equal(AdminNamespace, NamespaceOf(AdminEndpoint+"/custom"))
+	require.Equal(PublicNamespace, NamespaceOf(AdminEndpoint+"istrator"))
+}
+
+func TestAccessConfig(t *testing.T) {
+	require := require.New(t)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	access := &AccessConfig{
+		AdminTokens:        []string{"secret"},
+		CORSAllowedOrigins: []string{"https://example.com"},
+		CORSMaxAge:         time.Minute,
+	}
+	serve := func(namespace Namespace, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		access.Wrap(namespace, ok).ServeHTTP(w, r)
+		return w
+	}
+
+	// Public handlers don't require a token
+	w := serve(PublicNamespace, httptest.NewRequest(http.MethodPost, JSONRPCEndpoint, nil))
+	require.Equal(http.StatusOK, w.Code)
+
+	// Admin handlers require one of the configured tokens
+	w = serve(AdminNamespace, httptest.NewRequest(http.MethodPost, AdminEndpoint, nil))
+	require.Equal(http.StatusUnauthorized, w.Code)
+	r := httptest.NewRequest(http.MethodPost, AdminEndpoint, nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	require.Equal(http.StatusUnauthorized, serve(AdminNamespace, r).Code)
+	r = httptest.NewRequest(http.MethodPost, AdminEndpoint, nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	require.Equal(http.StatusOK, serve(AdminNamespace, r).Code)
+
+	// Preflight requests are answered without credentials
+	r = httptest.NewRequest(http.MethodOptions, AdminEndpoint, nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w = serve(AdminNamespace, r)
+	require.Equal(http.StatusNoContent, w.Code)
+	require.Equal("https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal("60", w.Header().Get("Access-Control-Max-Age"))
+
+	// Unknown origins don't get CORS headers
+	r = httptest.NewRequest(http.MethodPost, JSONRPCEndpoint, nil)
+	r.Header.Set("Origin", "https://evil.com")
+	w = serve(PublicNamespace, r)
+	require.Equal(http.StatusOK, w.Code)
+	require.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/profiles"
+	"github.com/ava-labs/hypersdk/requester"
+)
+
+type AdminClient struct {
+	requester *requester.EndpointRequester
+	options   []requester.Option
+}
+
+func NewAdminClient(uri string) *AdminClient {
+	uri = strings.TrimSuffix(uri, "/")
+	uri += AdminEndpoint
+	return &AdminClient{requester: requester.New(uri, Name)}
+}
+
+// NewAuthenticatedAdminClient is the same as [NewAdminClient] but provides
+// [token] to nodes that require admin requests to be authenticated.
+func NewAuthenticatedAdminClient(uri string, token string) *AdminClient {
+	cli := NewAdminClient(uri)
+	cli.options = []requester.Option{requester.WithHeader("Authorization", "Bearer "+token)}
+	return cli
+}
+
+// ReloadConfig replaces the operational parameters of the VM with those in
+// [config] (see [vm.VM.ReloadConfig]).
+func (cli *AdminClient) ReloadConfig(ctx context.Context, config []byte) error {
+	resp := new(ReloadConfigReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"reloadConfig",
+		&ReloadConfigArgs{Config: config},
+		resp,
+		cli.options...,
+	)
+}
+
+// LogLevels returns the default log level and the level of each module.
+func (cli *AdminClient) LogLevels(ctx context.Context) (logging.Level, map[string]logging.Level, error) {
+	resp := new(LogLevelsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"logLevels",
+		nil,
+		resp,
+		cli.options...,
+	)
+	return resp.Default, resp.Modules, err
+}
+
+// SetLogLevel makes [module] log at [level] until it is reset. If [module] is
+// empty, the default level is set instead.
+func (cli *AdminClient) SetLogLevel(ctx context.Context, module string, level logging.Level) error {
+	resp := new(SetLogLevelReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"setLogLevel",
+		&SetLogLevelArgs{Module: module, Level: &level},
+		resp,
+		cli.options...,
+	)
+}
+
+// ResetLogLevel makes [module] log at the default level again.
+func (cli *AdminClient) ResetLogLevel(ctx context.Context, module string) error {
+	resp := new(SetLogLevelReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"setLogLevel",
+		&SetLogLevelArgs{Module: module},
+		resp,
+		cli.options...,
+	)
+}
+
+// StartProfile begins a profile of [kind] that stops automatically after
+// [duration]. The result can be fetched with [GetProfile] once it stops.
+func (cli *AdminClient) StartProfile(ctx context.Context, kind profiles.Kind, duration time.Duration) error {
+	resp := new(ProfileReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"startProfile",
+		&StartProfileArgs{Kind: kind, Duration: duration},
+		resp,
+		cli.options...,
+	)
+}
+
+func (cli *AdminClient) StopProfile(ctx context.Context, kind profiles.Kind) error {
+	resp := new(ProfileReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"stopProfile",
+		&ProfileArgs{Kind: kind},
+		resp,
+		cli.options...,
+	)
+}
+
+// GetProfile returns the last completed profile of [kind] in pprof format.
+func (cli *AdminClient) GetProfile(ctx context.Context, kind profiles.Kind) ([]byte, error) {
+	resp := new(GetProfileReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getProfile",
+		&ProfileArgs{Kind: kind},
+		resp,
+		cli.options...,
+	)
+	return resp.Profile, err
+}
+
+// StartContinuousProfiler periodically writes profiles to [dir] on the node,
+// replacing any continuous profiler that is already running.
+func (cli *AdminClient) StartContinuousProfiler(
+	ctx context.Context,
+	dir string,
+	freq time.Duration,
+	maxNumFiles int,
+) error {
+	resp := new(ProfileReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"startContinuousProfiler",
+		&StartContinuousProfilerArgs{Dir: dir, Frequency: freq, MaxNumFiles: maxNumFiles},
+		resp,
+		cli.options...,
+	)
+}
+
+func (cli *AdminClient) StopContinuousProfiler(ctx context.Context) error {
+	resp := new(ProfileReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"stopContinuousProfiler",
+		nil,
+		resp,
+		cli.options...,
+	)
+}
+
+// Compact forces a compaction of [db] and returns once it completes (which
+// may take minutes on large databases).
+func (cli *AdminClient) Compact(ctx context.Context, db string) error {
+	resp := new(CompactReply)
+	return cli.requester.SendRequest(
+		ctx,
+		"compact",
+		&CompactArgs{DB: db},
+		resp,
+		cli.options...,
+	)
+}
+
+// StorageStats returns the last [StorageStats] collected by the node.
+func (cli *AdminClient) StorageStats(ctx context.Context) (*StorageStats, error) {
+	resp := new(StorageStatsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"storageStats",
+		nil,
+		resp,
+		cli.options...,
+	)
+	return resp.Stats, err
+}
+
+// BlockTrace returns the [chain.BlockTrace] of [blkID], which is only
+// available if the node traced the execution of the block and still has it
+// in memory.
+func (cli *AdminClient) BlockTrace(ctx context.Context, blkID ids.ID) (*chain.BlockTrace, error) {
+	resp := new(BlockTraceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"blockTrace",
+		&BlockTraceArgs{BlockID: blkID},
+		resp,
+		cli.options...,
+	)
+	return resp.Trace, err
+}
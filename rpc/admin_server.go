@@ -0,0 +1,212 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/pebble"
+	"github.com/ava-labs/hypersdk/profiles"
+)
+
+// AdminServer exposes operational endpoints that should not be available to
+// the public. It is only registered if enabled in the VM config.
+type AdminServer struct {
+	vm AdminVM
+}
+
+func NewAdminServer(vm AdminVM) *AdminServer {
+	return &AdminServer{vm}
+}
+
+type ReloadConfigArgs struct {
+	Config json.RawMessage `json:"config"`
+}
+
+type ReloadConfigReply struct {
+	Success bool `json:"success"`
+}
+
+func (a *AdminServer) ReloadConfig(_ *http.Request, args *ReloadConfigArgs, reply *ReloadConfigReply) error {
+	if err := a.vm.ReloadConfig(args.Config); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+type LogLevelsReply struct {
+	Default logging.Level            `json:"default"`
+	Modules map[string]logging.Level `json:"modules"`
+}
+
+func (a *AdminServer) LogLevels(_ *http.Request, _ *struct{}, reply *LogLevelsReply) error {
+	reply.Default = a.vm.Logs().DefaultLevel()
+	reply.Modules = a.vm.Logs().Levels()
+	return nil
+}
+
+type SetLogLevelArgs struct {
+	// Module whose level is set. If empty, the default level (used by all
+	// modules that don't override it) is set instead.
+	Module string `json:"module"`
+	// Level to log at. If nil, [Module] is reset to the default level.
+	Level *logging.Level `json:"level"`
+}
+
+type SetLogLevelReply struct {
+	Success bool `json:"success"`
+}
+
+func (a *AdminServer) SetLogLevel(_ *http.Request, args *SetLogLevelArgs, reply *SetLogLevelReply) error {
+	var (
+		m   = a.vm.Logs()
+		err error
+	)
+	switch {
+	case len(args.Module) == 0 && args.Level == nil:
+		err = ErrMissingLogLevel
+	case len(args.Module) == 0:
+		m.SetDefaultLevel(*args.Level)
+	case args.Level == nil:
+		err = m.ResetLevel(args.Module)
+	default:
+		err = m.SetLevel(args.Module, *args.Level)
+	}
+	if err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+type StartProfileArgs struct {
+	Kind     profiles.Kind `json:"kind"`
+	Duration time.Duration `json:"duration"`
+}
+
+type ProfileArgs struct {
+	Kind profiles.Kind `json:"kind"`
+}
+
+type ProfileReply struct {
+	Success bool `json:"success"`
+}
+
+func (a *AdminServer) StartProfile(_ *http.Request, args *StartProfileArgs, reply *ProfileReply) error {
+	if err := a.vm.Profiles().Start(args.Kind, args.Duration); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+func (a *AdminServer) StopProfile(_ *http.Request, args *ProfileArgs, reply *ProfileReply) error {
+	if err := a.vm.Profiles().Stop(args.Kind); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+type GetProfileReply struct {
+	Profile []byte `json:"profile"`
+}
+
+func (a *AdminServer) GetProfile(_ *http.Request, args *ProfileArgs, reply *GetProfileReply) error {
+	profile, err := a.vm.Profiles().Result(args.Kind)
+	if err != nil {
+		return err
+	}
+	reply.Profile = profile
+	return nil
+}
+
+type StartContinuousProfilerArgs struct {
+	Dir         string        `json:"dir"`
+	Frequency   time.Duration `json:"frequency"`
+	MaxNumFiles int           `json:"maxNumFiles"`
+}
+
+func (a *AdminServer) StartContinuousProfiler(
+	_ *http.Request,
+	args *StartContinuousProfilerArgs,
+	reply *ProfileReply,
+) error {
+	if err := a.vm.StartContinuousProfiler(args.Dir, args.Frequency, args.MaxNumFiles); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+func (a *AdminServer) StopContinuousProfiler(_ *http.Request, _ *struct{}, reply *ProfileReply) error {
+	if err := a.vm.StopContinuousProfiler(); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+type CompactArgs struct {
+	DB string `json:"db"`
+}
+
+type CompactReply struct {
+	Success bool `json:"success"`
+}
+
+func (a *AdminServer) Compact(_ *http.Request, args *CompactArgs, reply *CompactReply) error {
+	if err := a.vm.Compact(args.DB); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// StorageStats summarizes the disk usage of the block and state databases.
+// The stats of a database are nil if it is not stored in pebble.
+type StorageStats struct {
+	BlockDB           *pebble.Stats `json:"blockDB"`
+	StateDB           *pebble.Stats `json:"stateDB"`
+	ValueNodes        uint64        `json:"valueNodes"`        // merkledb nodes with values
+	IntermediateNodes uint64        `json:"intermediateNodes"` // merkledb nodes without values
+	Timestamp         int64         `json:"timestamp"`         // when the stats were collected (ms)
+}
+
+type StorageStatsReply struct {
+	Stats *StorageStats `json:"stats"`
+}
+
+func (a *AdminServer) StorageStats(_ *http.Request, _ *struct{}, reply *StorageStatsReply) error {
+	stats, err := a.vm.StorageStats()
+	if err != nil {
+		return err
+	}
+	reply.Stats = stats
+	return nil
+}
+
+type BlockTraceArgs struct {
+	BlockID ids.ID `json:"blockID"`
+}
+
+type BlockTraceReply struct {
+	Trace *chain.BlockTrace `json:"trace"`
+}
+
+func (a *AdminServer) BlockTrace(_ *http.Request, args *BlockTraceArgs, reply *BlockTraceReply) error {
+	trace, err := a.vm.BlockTrace(args.BlockID)
+	if err != nil {
+		return err
+	}
+	reply.Trace = trace
+	return nil
+}
@@ -9,6 +9,17 @@ const (
 	Name              = "hypersdk"
 	JSONRPCEndpoint   = "/coreapi"
 	WebSocketEndpoint = "/corews"
+	AdminEndpoint     = "/admin"
 
 	DefaultHandshakeTimeout = 10 * time.Second
+	DefaultCORSMaxAge       = 10 * time.Minute
+
+	// LatestBlockTag can be provided to [GetBlock] to fetch the last
+	// accepted block.
+	LatestBlockTag = "latest"
+
+	// Databases that can be provided to [AdminClient.Compact].
+	CompactBlockDB = "block"
+	CompactStateDB = "state"
+	CompactAllDBs  = ""
 )
@@ -5,6 +5,7 @@ package rpc
 
 import (
 	"context"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -12,6 +13,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/profiles"
+	"github.com/ava-labs/hypersdk/slo"
+	"github.com/ava-labs/hypersdk/statesync"
 )
 
 type VM interface {
@@ -21,18 +27,47 @@ type VM interface {
 	Tracer() trace.Tracer
 	Logger() logging.Logger
 	Registry() (chain.ActionRegistry, chain.AuthRegistry)
+	Rules(int64) chain.Rules
 	Submit(
 		ctx context.Context,
 		verifySig bool,
 		txs []*chain.Transaction,
 	) (errs []error)
+	SubmitBundle(context.Context, *chain.Bundle) error
 	LastAcceptedBlock() *chain.StatelessBlock
+	GetStatelessBlock(context.Context, ids.ID) (*chain.StatelessBlock, error)
+	GetBlockIDAtHeight(context.Context, uint64) (ids.ID, error)
+	BlockResults(*chain.StatelessBlock) ([]*chain.Result, chain.Dimensions, error)
 	UnitPrices(context.Context) (chain.Dimensions, error)
+	Burned(context.Context) (chain.Dimensions, error)
+	RulesOverrides(context.Context) (*chain.RulesOverrides, error)
 	GetOutgoingWarpMessage(ids.ID) (*warp.UnsignedMessage, error)
 	GetWarpSignatures(ids.ID) ([]*chain.WarpSignature, error)
 	CurrentValidators(
 		context.Context,
 	) (map[ids.NodeID]*validators.GetValidatorOutput, map[string]struct{})
 	GatherSignatures(context.Context, ids.ID, []byte)
+	GetDiskWarpMessage(ids.ID) (*OutgoingWarpMessage, error)
+	ResignWarpMessage(context.Context, ids.ID, *warp.UnsignedMessage) (*chain.WarpSignature, int, error)
+	GetValidatorSnapshot(uint64) (*chain.ValidatorSnapshot, error)
 	GetVerifyAuth() bool
+	GetStreamingMempool() bool // if true, clients may subscribe to txs entering the mempool
+	SLOReport() *slo.Report
+	StateSyncProgress() *statesync.Progress
+	BlockBackfillStatus() *BlockBackfillStatus
+	GetDiskBlob(ids.ID) (uint64, []byte, error)
+	ReadState(context.Context, [][]byte) ([][]byte, []error)
+	GetNonce(context.Context, codec.Address) (uint64, error)
+	StateRegistry() *StateRegistry // nil if the VM doesn't serve [JSONRPCServer.QueryState]
+}
+
+type AdminVM interface {
+	ReloadConfig([]byte) error
+	Profiles() *profiles.Manager
+	Logs() *logs.Manager
+	StartContinuousProfiler(dir string, freq time.Duration, maxNumFiles int) error
+	StopContinuousProfiler() error
+	Compact(db string) error
+	StorageStats() (*StorageStats, error)
+	BlockTrace(ids.ID) (*chain.BlockTrace, error)
 }
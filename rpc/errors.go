@@ -9,4 +9,37 @@ var (
 	ErrClosed         = errors.New("closed")
 	ErrExpired        = errors.New("expired")
 	ErrMessageMissing = errors.New("message missing")
+	ErrUnauthorized   = errors.New("unauthorized")
+
+	ErrSnapshotMissing  = errors.New("validator snapshot missing")
+	ErrSnapshotMismatch = errors.New("validator snapshot does not match message")
+
+	ErrRequestTooLarge = errors.New("request too large")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrTooManyRequests = errors.New("too many concurrent requests")
+	ErrInvalidProxy    = errors.New("invalid trusted proxy")
+
+	ErrTooManyResumeTxs = errors.New("too many txs to resume")
+	ErrUnknownStream    = errors.New("unknown stream")
+	ErrGapTooLarge      = errors.New("gap too large to backfill")
+	ErrResumeGap        = errors.New("messages to resume are no longer retained")
+	ErrTxNotFound       = errors.New("tx not found")
+
+	ErrInvalidBlockSelector = errors.New("must specify exactly one of blockId, height, or tag")
+	ErrUnknownBlockTag      = errors.New("unknown block tag")
+	ErrBlockNotAccepted     = errors.New("block not accepted")
+
+	ErrInvalidExpiry       = errors.New("must specify exactly one of timestamp or height")
+	ErrTxNotExpired        = errors.New("tx not expired")
+	ErrTxIncluded          = errors.New("tx included")
+	ErrTooManyBlocks       = errors.New("too many blocks in validity window")
+	ErrInvalidNonInclusion = errors.New("invalid proof of non-inclusion")
+
+	ErrUnknownTxFormat = errors.New("unknown tx format")
+	ErrInvalidTxFormat = errors.New("tx does not match format")
+
+	ErrDuplicateStateQuery = errors.New("duplicate state query")
+	ErrUnknownStateQuery   = errors.New("unknown state query")
+
+	ErrMissingLogLevel = errors.New("default log level can't be reset")
 )
@@ -4,7 +4,10 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,7 +22,10 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/requester"
+	"github.com/ava-labs/hypersdk/slo"
+	"github.com/ava-labs/hypersdk/statesync"
 	"github.com/ava-labs/hypersdk/utils"
 )
 
@@ -46,6 +52,29 @@ func NewJSONRPCClient(uri string) *JSONRPCClient {
 	return &JSONRPCClient{requester: req}
 }
 
+// NewJSONRPCClientWithConfig returns a client that sends each request to the
+// first healthy node of [uris] and retries requests as configured by
+// [config].
+func NewJSONRPCClientWithConfig(uris []string, config *requester.Config) *JSONRPCClient {
+	req := requester.NewWithConfig(
+		EndpointURIs(uris, JSONRPCEndpoint),
+		Name,
+		config.WithDefaultHealthMethod("ping"),
+	)
+	return &JSONRPCClient{requester: req}
+}
+
+// Endpoints returns the health of each node [cli] sends requests to.
+func (cli *JSONRPCClient) Endpoints() []*requester.EndpointStatus {
+	return cli.requester.Endpoints()
+}
+
+// MonitorHealth pings each node [cli] sends requests to every [interval]
+// until [ctx] is done, so that nodes that recover are used again.
+func (cli *JSONRPCClient) MonitorHealth(ctx context.Context, interval time.Duration) error {
+	return cli.requester.MonitorHealth(ctx, interval)
+}
+
 func (cli *JSONRPCClient) Ping(ctx context.Context) (bool, error) {
 	resp := new(PingReply)
 	err := cli.requester.SendRequest(ctx,
@@ -88,6 +117,29 @@ func (cli *JSONRPCClient) Accepted(ctx context.Context) (ids.ID, uint64, int64,
 	return resp.BlockID, resp.Height, resp.Timestamp, err
 }
 
+func (cli *JSONRPCClient) getBlock(ctx context.Context, args *GetBlockArgs) (*GetBlockReply, error) {
+	resp := new(GetBlockReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getBlock",
+		args,
+		resp,
+	)
+	return resp, err
+}
+
+func (cli *JSONRPCClient) GetBlock(ctx context.Context, blkID ids.ID) (*GetBlockReply, error) {
+	return cli.getBlock(ctx, &GetBlockArgs{BlockID: blkID})
+}
+
+func (cli *JSONRPCClient) GetBlockByHeight(ctx context.Context, height uint64) (*GetBlockReply, error) {
+	return cli.getBlock(ctx, &GetBlockArgs{Height: &height})
+}
+
+func (cli *JSONRPCClient) GetLatestBlock(ctx context.Context) (*GetBlockReply, error) {
+	return cli.getBlock(ctx, &GetBlockArgs{Tag: LatestBlockTag})
+}
+
 func (cli *JSONRPCClient) UnitPrices(ctx context.Context, useCache bool) (chain.Dimensions, error) {
 	if useCache && time.Since(cli.lastUnitPrices) < unitPricesCacheRefresh {
 		return cli.unitPrices, nil
@@ -110,17 +162,137 @@ func (cli *JSONRPCClient) UnitPrices(ctx context.Context, useCache bool) (chain.
 	return resp.UnitPrices, nil
 }
 
+func (cli *JSONRPCClient) Burned(ctx context.Context) (chain.Dimensions, error) {
+	resp := new(BurnedReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"burned",
+		nil,
+		resp,
+	)
+	return resp.Burned, err
+}
+
+// RulesOverrides returns the rules overridden in state (or nil if there are
+// none). Transactions should use the overridden validity window instead of
+// the one in [chain.Parser.Rules].
+func (cli *JSONRPCClient) RulesOverrides(ctx context.Context) (*chain.RulesOverrides, error) {
+	resp := new(RulesOverridesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"rulesOverrides",
+		nil,
+		resp,
+	)
+	return resp.Overrides, err
+}
+
 func (cli *JSONRPCClient) SubmitTx(ctx context.Context, d []byte) (ids.ID, error) {
+	return cli.SubmitTxWithEncoding(ctx, chain.BinaryEncoding, d)
+}
+
+// SubmitTxWithEncoding submits a tx encoded with [e] (see [Encodings]).
+func (cli *JSONRPCClient) SubmitTxWithEncoding(ctx context.Context, e chain.Encoding, d []byte) (ids.ID, error) {
 	resp := new(SubmitTxReply)
 	err := cli.requester.SendRequest(
 		ctx,
 		"submitTx",
-		&SubmitTxArgs{Tx: d},
+		&SubmitTxArgs{Tx: d, Encoding: e},
 		resp,
 	)
 	return resp.TxID, err
 }
 
+// SubmitRawTx submits [tx] (signed transaction bytes formatted with
+// [EncodeRawTx]). If the transaction is parsed, its ID is returned even if
+// it is not added to the mempool.
+func (cli *JSONRPCClient) SubmitRawTx(ctx context.Context, tx string, format string) (ids.ID, error) {
+	resp := new(SubmitRawTxReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"submitRawTx",
+		&SubmitRawTxArgs{Tx: tx, Format: format},
+		resp,
+	)
+	if err != nil {
+		return resp.TxID, err
+	}
+	if len(resp.Error) > 0 {
+		return resp.TxID, errors.New(resp.Error)
+	}
+	return resp.TxID, nil
+}
+
+// SubmitBundle submits signed transactions ([chain.BinaryEncoding]) that
+// must be included contiguously in one block, in order, or not at all.
+func (cli *JSONRPCClient) SubmitBundle(ctx context.Context, txs [][]byte) (ids.ID, error) {
+	resp := new(SubmitBundleReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"submitBundle",
+		&SubmitBundleArgs{Txs: txs},
+		resp,
+	)
+	return resp.BundleID, err
+}
+
+func (cli *JSONRPCClient) Encodings(ctx context.Context) ([]chain.Encoding, error) {
+	resp := new(EncodingsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"encodings",
+		nil,
+		resp,
+	)
+	return resp.Encodings, err
+}
+
+func (cli *JSONRPCClient) GetSLOReport(ctx context.Context) (*slo.Report, error) {
+	resp := new(GetSLOReportReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getSLOReport",
+		nil,
+		resp,
+	)
+	return resp.Report, err
+}
+
+func (cli *JSONRPCClient) GetStateSyncProgress(ctx context.Context) (*statesync.Progress, error) {
+	resp := new(GetStateSyncProgressReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getStateSyncProgress",
+		nil,
+		resp,
+	)
+	return resp.Progress, err
+}
+
+func (cli *JSONRPCClient) GetBlockBackfillStatus(ctx context.Context) (*BlockBackfillStatus, error) {
+	resp := new(GetBlockBackfillStatusReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getBlockBackfillStatus",
+		nil,
+		resp,
+	)
+	return resp.Status, err
+}
+
+// GetBlob returns the blob of [txID] and the height of the block that
+// included it (if it is still retained by the node).
+func (cli *JSONRPCClient) GetBlob(ctx context.Context, txID ids.ID) (uint64, []byte, error) {
+	resp := new(GetBlobReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getBlob",
+		&GetBlobArgs{TxID: txID},
+		resp,
+	)
+	return resp.Height, resp.Blob, err
+}
+
 func (cli *JSONRPCClient) GetWarpSignatures(
 	ctx context.Context,
 	txID ids.ID,
@@ -156,10 +328,207 @@ func (cli *JSONRPCClient) GetWarpSignatures(
 	return resp.Message, m, resp.Signatures, nil
 }
 
+// ResignWarpMessage asks the current validators to sign the message emitted
+// by [txID] (if it is still retained by the node) and returns the message,
+// the signature of the node (nil if it is an API node), and the number of
+// validators that were asked. The new signatures can be fetched with
+// [GetWarpSignatures] once they are gathered.
+func (cli *JSONRPCClient) ResignWarpMessage(
+	ctx context.Context,
+	txID ids.ID,
+) (*OutgoingWarpMessage, *chain.WarpSignature, int, error) {
+	resp := new(ResignWarpMessageReply)
+	if err := cli.requester.SendRequest(
+		ctx,
+		"resignWarpMessage",
+		&ResignWarpMessageArgs{TxID: txID},
+		resp,
+	); err != nil {
+		return nil, nil, 0, err
+	}
+	// Ensure message is initialized
+	if err := resp.Message.Message.Initialize(); err != nil {
+		return nil, nil, 0, err
+	}
+	return resp.Message, resp.Signature, resp.Requested, nil
+}
+
+// Validators returns the current validator set of the subnet.
+func (cli *JSONRPCClient) Validators(ctx context.Context) ([]*WarpValidator, error) {
+	resp := new(ValidatorsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"validators",
+		nil,
+		resp,
+	)
+	return resp.Validators, err
+}
+
+// GetValidatorSnapshot returns the validator snapshot taken at the end of
+// [epoch] and the warp message attesting to it. The snapshot is checked
+// against the commitment in the message, so the snapshot can be trusted if
+// the message carries enough signatures (see
+// [GenerateAggregateWarpSignature] with [chain.ValidatorSnapshotID]).
+func (cli *JSONRPCClient) GetValidatorSnapshot(
+	ctx context.Context,
+	epoch uint64,
+) (*chain.ValidatorSnapshot, *warp.UnsignedMessage, error) {
+	resp := new(GetValidatorSnapshotReply)
+	if err := cli.requester.SendRequest(
+		ctx,
+		"getValidatorSnapshot",
+		&GetValidatorSnapshotArgs{Epoch: epoch},
+		resp,
+	); err != nil {
+		return nil, nil, err
+	}
+	if err := resp.Message.Initialize(); err != nil {
+		return nil, nil, err
+	}
+	commitment, err := resp.Snapshot.Commitment()
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Snapshot.Epoch != epoch || !bytes.Equal(commitment, resp.Message.Payload) {
+		return nil, nil, ErrSnapshotMismatch
+	}
+	return resp.Snapshot, resp.Message, nil
+}
+
+func (cli *JSONRPCClient) StatePrefixes(ctx context.Context) ([]string, error) {
+	resp := new(StatePrefixesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"statePrefixes",
+		nil,
+		resp,
+	)
+	return resp.Prefixes, err
+}
+
+// QueryState unmarshals the value stored at [key] under the key prefix
+// registered as [prefix] into [value]. If there is no value, false is
+// returned and [value] is left unchanged.
+func (cli *JSONRPCClient) QueryState(ctx context.Context, prefix string, key string, value any) (bool, error) {
+	resp := new(QueryStateReply)
+	if err := cli.requester.SendRequest(
+		ctx,
+		"queryState",
+		&QueryStateArgs{Prefix: prefix, Key: key},
+		resp,
+	); err != nil {
+		return false, err
+	}
+	if !resp.Exists {
+		return false, nil
+	}
+	return true, json.Unmarshal(resp.Value, value)
+}
+
+// GetNonce returns the nonce that the next transaction sponsored by [addr]
+// must use (see [WithNonce]).
+func (cli *JSONRPCClient) GetNonce(ctx context.Context, addr codec.Address) (uint64, error) {
+	resp := new(GetNonceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getNonce",
+		&GetNonceArgs{Address: addr},
+		resp,
+	)
+	return resp.Nonce, err
+}
+
+// TxNonInclusion returns proof that [txID], which expired at [timestamp] or
+// [height] (only one may be set), was never included in an accepted block.
+func (cli *JSONRPCClient) TxNonInclusion(
+	ctx context.Context,
+	txID ids.ID,
+	timestamp int64,
+	height uint64,
+) (*GetTxNonInclusionReply, error) {
+	resp := new(GetTxNonInclusionReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"getTxNonInclusion",
+		&GetTxNonInclusionArgs{TxID: txID, Timestamp: timestamp, Height: height},
+		resp,
+	)
+	return resp, err
+}
+
+// VerifyTxNonInclusion checks [proof] (which may have been returned by
+// another node) against the blocks accepted by the node [cli] sends requests
+// to. If it returns nil, the transaction can never be included and it is
+// safe to issue a replacement.
+func (cli *JSONRPCClient) VerifyTxNonInclusion(
+	ctx context.Context,
+	parser chain.Parser,
+	txID ids.ID,
+	timestamp int64,
+	height uint64,
+	proof *GetTxNonInclusionReply,
+) error {
+	overrides, err := cli.RulesOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	args := &GetTxNonInclusionArgs{TxID: txID, Timestamp: timestamp, Height: height}
+	return verifyTxNonInclusion(ctx, parser, overrides, args, proof, cli.GetBlock)
+}
+
 type Modifier interface {
 	Base(*chain.Base)
 }
 
+type nonceModifier uint64
+
+func (n nonceModifier) Base(b *chain.Base) {
+	b.Timestamp = 0
+	b.Height = 0
+	b.Nonce = uint64(n)
+}
+
+// WithNonce replaces the expiry of a generated transaction with [nonce], for
+// chains that use sequential nonces.
+func WithNonce(nonce uint64) Modifier {
+	return nonceModifier(nonce)
+}
+
+type heightModifier uint64
+
+func (h heightModifier) Base(b *chain.Base) {
+	b.Timestamp = 0
+	b.Height = uint64(h)
+}
+
+// WithHeightExpiry replaces the expiry of a generated transaction with
+// [height], for chains that allow height expiries (see
+// [chain.Rules.GetHeightValidityWindow]).
+func WithHeightExpiry(height uint64) Modifier {
+	return heightModifier(height)
+}
+
+type feeAssetModifier struct {
+	asset       ids.ID
+	maxFeeAsset uint64
+}
+
+func (f feeAssetModifier) Base(b *chain.Base) {
+	b.FeeAsset = f.asset
+	b.MaxFeeAsset = f.maxFeeAsset
+}
+
+// WithFeeAsset pays the fees of a generated transaction in [asset] instead
+// of the native asset, for chains whose [chain.StateManager] implements
+// [chain.FeeConverter]. The transaction is dropped if its max fee converts to
+// more than [maxFeeAsset] of [asset]. Because the fee asset touches different
+// keys, the max fee should be computed with [chain.EstimateMaxFeeAssetUnits]
+// and passed to [JSONRPCClient.GenerateTransactionManual].
+func WithFeeAsset(asset ids.ID, maxFeeAsset uint64) Modifier {
+	return feeAssetModifier{asset, maxFeeAsset}
+}
+
 func (cli *JSONRPCClient) GenerateTransaction(
 	ctx context.Context,
 	parser chain.Parser,
@@ -174,11 +543,12 @@ func (cli *JSONRPCClient) GenerateTransaction(
 		return nil, nil, 0, err
 	}
 
-	maxUnits, err := chain.EstimateMaxUnits(parser.Rules(time.Now().UnixMilli()), action, authFactory, wm)
+	rules := parser.Rules(time.Now().UnixMilli())
+	maxUnits, err := chain.EstimateMaxUnits(rules, action, authFactory, wm)
 	if err != nil {
 		return nil, nil, 0, err
 	}
-	maxFee, err := chain.MulSum(unitPrices, maxUnits)
+	maxFee, err := chain.EstimateFee(rules, unitPrices, maxUnits)
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -189,6 +559,38 @@ func (cli *JSONRPCClient) GenerateTransaction(
 	return f, tx, maxFee, nil
 }
 
+// GenerateBlobTransaction is like [GenerateTransaction] but attaches [blob]
+// to the transaction.
+func (cli *JSONRPCClient) GenerateBlobTransaction(
+	ctx context.Context,
+	parser chain.Parser,
+	action chain.Action,
+	blob []byte,
+	authFactory chain.AuthFactory,
+	modifiers ...Modifier,
+) (func(context.Context) error, *chain.Transaction, uint64, error) {
+	// Get latest fee info
+	unitPrices, err := cli.UnitPrices(ctx, true)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	rules := parser.Rules(time.Now().UnixMilli())
+	maxUnits, err := chain.EstimateMaxBlobUnits(rules, action, authFactory, blob)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	maxFee, err := chain.EstimateFee(rules, unitPrices, maxUnits)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	f, tx, err := cli.generateTransaction(parser, nil, action, blob, authFactory, maxFee, modifiers...)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return f, tx, maxFee, nil
+}
+
 func (cli *JSONRPCClient) GenerateTransactionManual(
 	parser chain.Parser,
 	wm *warp.Message,
@@ -196,6 +598,18 @@ func (cli *JSONRPCClient) GenerateTransactionManual(
 	authFactory chain.AuthFactory,
 	maxFee uint64,
 	modifiers ...Modifier,
+) (func(context.Context) error, *chain.Transaction, error) {
+	return cli.generateTransaction(parser, wm, action, nil, authFactory, maxFee, modifiers...)
+}
+
+func (cli *JSONRPCClient) generateTransaction(
+	parser chain.Parser,
+	wm *warp.Message,
+	action chain.Action,
+	blob []byte,
+	authFactory chain.AuthFactory,
+	maxFee uint64,
+	modifiers ...Modifier,
 ) (func(context.Context) error, *chain.Transaction, error) {
 	// Construct transaction
 	now := time.Now().UnixMilli()
@@ -205,6 +619,10 @@ func (cli *JSONRPCClient) GenerateTransactionManual(
 		ChainID:   rules.ChainID(),
 		MaxFee:    maxFee,
 	}
+	if rules.GetSequentialNonces() {
+		// Transactions don't expire (the nonce should be set with [WithNonce])
+		base.Timestamp = 0
+	}
 
 	// Modify gathered data
 	for _, m := range modifiers {
@@ -221,6 +639,7 @@ func (cli *JSONRPCClient) GenerateTransactionManual(
 	// Build transaction
 	actionRegistry, authRegistry := parser.Registry()
 	tx := chain.NewTx(base, wm, action)
+	tx.Blob = blob
 	tx, err := tx.Sign(authFactory, actionRegistry, authRegistry)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: failed to sign transaction", err)
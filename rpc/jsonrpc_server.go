@@ -4,17 +4,23 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/slo"
+	"github.com/ava-labs/hypersdk/statesync"
 	"go.uber.org/zap"
 )
 
@@ -36,6 +42,17 @@ func (j *JSONRPCServer) Ping(_ *http.Request, _ *struct{}, reply *PingReply) (er
 	return nil
 }
 
+type EncodingsReply struct {
+	Encodings []chain.Encoding `json:"encodings"`
+}
+
+// Encodings returns the wire encodings accepted by [SubmitTx] and
+// [GetBlock].
+func (*JSONRPCServer) Encodings(_ *http.Request, _ *struct{}, reply *EncodingsReply) error {
+	reply.Encodings = chain.Encodings()
+	return nil
+}
+
 type NetworkReply struct {
 	NetworkID uint32 `json:"networkId"`
 	SubnetID  ids.ID `json:"subnetId"`
@@ -51,6 +68,9 @@ func (j *JSONRPCServer) Network(_ *http.Request, _ *struct{}, reply *NetworkRepl
 
 type SubmitTxArgs struct {
 	Tx []byte `json:"tx"`
+
+	// Encoding of [Tx] (defaults to [chain.BinaryEncoding]).
+	Encoding chain.Encoding `json:"encoding"`
 }
 
 type SubmitTxReply struct {
@@ -66,14 +86,16 @@ func (j *JSONRPCServer) SubmitTx(
 	defer span.End()
 
 	actionRegistry, authRegistry := j.vm.Registry()
-	rtx := codec.NewReader(args.Tx, consts.NetworkSizeLimit) // will likely be much smaller than this
-	tx, err := chain.UnmarshalTx(rtx, actionRegistry, authRegistry)
+	tx, err := chain.UnmarshalTxEncoding(args.Encoding, args.Tx, actionRegistry, authRegistry)
 	if err != nil {
 		return fmt.Errorf("%w: unable to unmarshal on public service", err)
 	}
-	if !rtx.Empty() {
-		return errors.New("tx has extra bytes")
-	}
+	reply.TxID = tx.ID()
+	return j.submit(ctx, tx)
+}
+
+// submit verifies the signature of [tx] and adds it to the mempool.
+func (j *JSONRPCServer) submit(ctx context.Context, tx *chain.Transaction) error {
 	msg, err := tx.Digest()
 	if err != nil {
 		// Should never occur because populated during unmarshal
@@ -82,11 +104,109 @@ func (j *JSONRPCServer) SubmitTx(
 	if err := tx.Auth.Verify(ctx, msg); err != nil {
 		return err
 	}
-	txID := tx.ID()
-	reply.TxID = txID
 	return j.vm.Submit(ctx, false, []*chain.Transaction{tx})[0]
 }
 
+type SubmitRawTxArgs struct {
+	// Tx is a signed transaction encoded with [Encoding] and then formatted
+	// as a string according to [Format].
+	Tx string `json:"tx"`
+
+	// Format of [Tx] ("hex" or "base64"). If empty, [Tx] is treated as hex
+	// if it has a "0x" prefix and as base64 otherwise.
+	Format string `json:"format"`
+
+	// Encoding of [Tx] (defaults to [chain.BinaryEncoding]).
+	Encoding chain.Encoding `json:"encoding"`
+}
+
+type SubmitRawTxReply struct {
+	TxID ids.ID `json:"txId"`
+
+	// Error is the reason [TxID] was not added to the mempool (empty if it
+	// was added).
+	Error string `json:"error,omitempty"`
+}
+
+// SubmitRawTx submits a transaction that was built and signed outside of
+// the Go client. Unlike [SubmitTx], any error that occurs once the
+// transaction is parsed (invalid signature, insufficient funds, etc.) is
+// returned in [SubmitRawTxReply.Error] alongside the txID.
+func (j *JSONRPCServer) SubmitRawTx(
+	req *http.Request,
+	args *SubmitRawTxArgs,
+	reply *SubmitRawTxReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.SubmitRawTx")
+	defer span.End()
+
+	raw, err := DecodeRawTx(args.Tx, args.Format)
+	if err != nil {
+		return err
+	}
+	actionRegistry, authRegistry := j.vm.Registry()
+	tx, err := chain.UnmarshalTxEncoding(args.Encoding, raw, actionRegistry, authRegistry)
+	if err != nil {
+		return fmt.Errorf("%w: unable to unmarshal on public service", err)
+	}
+	reply.TxID = tx.ID()
+	if err := j.submit(ctx, tx); err != nil {
+		reply.Error = err.Error()
+	}
+	return nil
+}
+
+type SubmitBundleArgs struct {
+	Txs [][]byte `json:"txs"`
+
+	// Encoding of [Txs] (defaults to [chain.BinaryEncoding]).
+	Encoding chain.Encoding `json:"encoding"`
+}
+
+type SubmitBundleReply struct {
+	BundleID ids.ID `json:"bundleId"`
+}
+
+// SubmitBundle submits an ordered bundle of transactions that this node
+// includes contiguously at the start of a block it builds, or not at all
+// (see [chain.Bundle]). Bundles are not gossiped, so they should be
+// submitted to validators that are likely to build soon.
+func (j *JSONRPCServer) SubmitBundle(
+	req *http.Request,
+	args *SubmitBundleArgs,
+	reply *SubmitBundleReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.SubmitBundle")
+	defer span.End()
+
+	if len(args.Txs) > chain.MaxBundleTxs {
+		return chain.ErrBundleTooLarge
+	}
+	actionRegistry, authRegistry := j.vm.Registry()
+	txs := make([]*chain.Transaction, len(args.Txs))
+	for i, raw := range args.Txs {
+		tx, err := chain.UnmarshalTxEncoding(args.Encoding, raw, actionRegistry, authRegistry)
+		if err != nil {
+			return fmt.Errorf("%w: unable to unmarshal on public service", err)
+		}
+		msg, err := tx.Digest()
+		if err != nil {
+			// Should never occur because populated during unmarshal
+			return err
+		}
+		if err := tx.Auth.Verify(ctx, msg); err != nil {
+			return fmt.Errorf("%w: tx %d", err, i)
+		}
+		txs[i] = tx
+	}
+	bundle, err := chain.NewBundle(txs)
+	if err != nil {
+		return err
+	}
+	reply.BundleID = bundle.ID()
+	return j.vm.SubmitBundle(ctx, bundle)
+}
+
 type LastAcceptedReply struct {
 	Height    uint64 `json:"height"`
 	BlockID   ids.ID `json:"blockId"`
@@ -101,6 +221,131 @@ func (j *JSONRPCServer) LastAccepted(_ *http.Request, _ *struct{}, reply *LastAc
 	return nil
 }
 
+type GetBlockArgs struct {
+	BlockID ids.ID  `json:"blockId"`
+	Height  *uint64 `json:"height"`
+	Tag     string  `json:"tag"`
+
+	// Encoding of the bytes of each tx (defaults to [chain.BinaryEncoding]).
+	Encoding chain.Encoding `json:"encoding"`
+}
+
+type BlockTx struct {
+	TxID  ids.ID `json:"txId"`
+	Bytes []byte `json:"bytes"`
+
+	// Tx is the canonical JSON encoding of [Bytes]
+	Tx *chain.TransactionJSON `json:"tx"`
+}
+
+type BlockResult = chain.ResultJSON
+
+type GetBlockReply struct {
+	BlockID     ids.ID `json:"blockId"`
+	Parent      ids.ID `json:"parent"`
+	Height      uint64 `json:"height"`
+	Timestamp   int64  `json:"timestamp"`
+	StateRoot   ids.ID `json:"stateRoot"`
+	WarpResults uint64 `json:"warpResults"`
+	BlobsRoot   ids.ID `json:"blobsRoot"`
+	Size        int    `json:"size"`
+
+	Txs []*BlockTx `json:"txs"`
+
+	// Results and UnitPrices are only populated if this node executed the
+	// block (blocks accepted during state sync are not executed).
+	Results    []*BlockResult   `json:"results"`
+	UnitPrices chain.Dimensions `json:"unitPrices"`
+}
+
+func (j *JSONRPCServer) GetBlock(req *http.Request, args *GetBlockArgs, reply *GetBlockReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.GetBlock")
+	defer span.End()
+
+	selectors := 0
+	if args.BlockID != ids.Empty {
+		selectors++
+	}
+	if args.Height != nil {
+		selectors++
+	}
+	if len(args.Tag) > 0 {
+		selectors++
+	}
+	if selectors != 1 {
+		return ErrInvalidBlockSelector
+	}
+
+	var (
+		blk *chain.StatelessBlock
+		err error
+	)
+	switch {
+	case len(args.Tag) > 0:
+		if args.Tag != LatestBlockTag {
+			return fmt.Errorf("%w: %s", ErrUnknownBlockTag, args.Tag)
+		}
+		blk = j.vm.LastAcceptedBlock()
+	case args.Height != nil:
+		var blkID ids.ID
+		blkID, err = j.vm.GetBlockIDAtHeight(ctx, *args.Height)
+		if err != nil {
+			return err
+		}
+		blk, err = j.vm.GetStatelessBlock(ctx, blkID)
+		if err != nil {
+			return err
+		}
+	default:
+		blk, err = j.vm.GetStatelessBlock(ctx, args.BlockID)
+		if err != nil {
+			return err
+		}
+	}
+	// We don't want to serve blocks that are only processing (they may
+	// still be rejected)
+	if blk.Status() != choices.Accepted {
+		return ErrBlockNotAccepted
+	}
+
+	reply.BlockID = blk.ID()
+	reply.Parent = blk.Prnt
+	reply.Height = blk.Hght
+	reply.Timestamp = blk.Tmstmp
+	reply.StateRoot = blk.StateRoot
+	reply.WarpResults = uint64(blk.WarpResults)
+	reply.BlobsRoot = blk.BlobsRoot
+	reply.Size = blk.Size()
+	reply.Txs = make([]*BlockTx, len(blk.Txs))
+	for i, tx := range blk.Txs {
+		txJSON, err := chain.NewTransactionJSON(j.vm, tx)
+		if err != nil {
+			return err
+		}
+		txBytes, err := chain.MarshalTxEncoding(args.Encoding, tx)
+		if err != nil {
+			return err
+		}
+		reply.Txs[i] = &BlockTx{TxID: tx.ID(), Bytes: txBytes, Tx: txJSON}
+	}
+	results, unitPrices, err := j.vm.BlockResults(blk)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+	reply.Results = make([]*BlockResult, len(results))
+	for i, result := range results {
+		reply.Results[i], err = chain.NewTypedResultJSON(j.vm, blk.Txs[i], result)
+		if err != nil {
+			return err
+		}
+	}
+	reply.UnitPrices = unitPrices
+	return nil
+}
+
 type UnitPricesReply struct {
 	UnitPrices chain.Dimensions `json:"unitPrices"`
 }
@@ -121,6 +366,114 @@ func (j *JSONRPCServer) UnitPrices(
 	return nil
 }
 
+type BurnedReply struct {
+	Burned chain.Dimensions `json:"burned"`
+}
+
+// Burned returns the total fees burned in each dimension as of the last
+// accepted block.
+func (j *JSONRPCServer) Burned(
+	req *http.Request,
+	_ *struct{},
+	reply *BurnedReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.Burned")
+	defer span.End()
+
+	burned, err := j.vm.Burned(ctx)
+	if err != nil {
+		return err
+	}
+	reply.Burned = burned
+	return nil
+}
+
+type RulesOverridesReply struct {
+	Overrides *chain.RulesOverrides `json:"overrides"`
+}
+
+// RulesOverrides returns the rules overridden in state as of the last
+// accepted block. They apply to the next block (and any transactions it
+// includes).
+func (j *JSONRPCServer) RulesOverrides(
+	req *http.Request,
+	_ *struct{},
+	reply *RulesOverridesReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.RulesOverrides")
+	defer span.End()
+
+	overrides, err := j.vm.RulesOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	reply.Overrides = overrides
+	return nil
+}
+
+type GetSLOReportReply struct {
+	Report *slo.Report `json:"report"`
+}
+
+func (j *JSONRPCServer) GetSLOReport(_ *http.Request, _ *struct{}, reply *GetSLOReportReply) error {
+	reply.Report = j.vm.SLOReport()
+	return nil
+}
+
+type GetStateSyncProgressReply struct {
+	Progress *statesync.Progress `json:"progress"`
+}
+
+func (j *JSONRPCServer) GetStateSyncProgress(
+	_ *http.Request,
+	_ *struct{},
+	reply *GetStateSyncProgressReply,
+) error {
+	reply.Progress = j.vm.StateSyncProgress()
+	return nil
+}
+
+// BlockBackfillStatus reports the progress of fetching the blocks below the
+// state sync target from peers.
+type BlockBackfillStatus struct {
+	Active     bool   `json:"active"`
+	NextHeight uint64 `json:"nextHeight"` // next block to fetch (blocks are fetched in descending order)
+	Floor      uint64 `json:"floor"`      // lowest block that will be fetched
+	Remaining  uint64 `json:"remaining"`
+}
+
+type GetBlockBackfillStatusReply struct {
+	Status *BlockBackfillStatus `json:"status"`
+}
+
+func (j *JSONRPCServer) GetBlockBackfillStatus(
+	_ *http.Request,
+	_ *struct{},
+	reply *GetBlockBackfillStatusReply,
+) error {
+	reply.Status = j.vm.BlockBackfillStatus()
+	return nil
+}
+
+type GetBlobArgs struct {
+	TxID ids.ID `json:"txID"`
+}
+
+type GetBlobReply struct {
+	Height uint64 `json:"height"`
+	Blob   []byte `json:"blob"`
+}
+
+func (j *JSONRPCServer) GetBlob(_ *http.Request, args *GetBlobArgs, reply *GetBlobReply) error {
+	height, blob, err := j.vm.GetDiskBlob(args.TxID)
+	if err != nil {
+		return err
+	}
+	reply.Height = height
+	reply.Blob = blob
+	return nil
+}
+
 type GetWarpSignaturesArgs struct {
 	TxID ids.ID `json:"txID"`
 }
@@ -199,3 +552,205 @@ func (j *JSONRPCServer) GetWarpSignatures(
 	reply.Signatures = validSignatures
 	return nil
 }
+
+// OutgoingWarpMessage is a warp message emitted by an accepted block.
+type OutgoingWarpMessage struct {
+	Height    uint64                `json:"height"`
+	Timestamp int64                 `json:"timestamp"`
+	BlockID   ids.ID                `json:"blockId"`
+	Message   *warp.UnsignedMessage `json:"message"`
+}
+
+type ResignWarpMessageArgs struct {
+	TxID ids.ID `json:"txID"`
+}
+
+type ResignWarpMessageReply struct {
+	Message   *OutgoingWarpMessage `json:"message"`
+	Signature *chain.WarpSignature `json:"signature"` // nil if the node is an API node
+	Requested int                  `json:"requested"` // validators asked for a signature
+}
+
+// ResignWarpMessage asks the current validators that have not signed the
+// message emitted by [TxID] for their signatures. Relayers should call it
+// when the validator set has changed since the message was accepted (and
+// the signatures from [GetWarpSignatures] no longer reach a quorum), then
+// poll [GetWarpSignatures] for the new signatures.
+//
+// Only messages that are still retained by the node can be re-signed.
+func (j *JSONRPCServer) ResignWarpMessage(
+	req *http.Request,
+	args *ResignWarpMessageArgs,
+	reply *ResignWarpMessageReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.ResignWarpMessage")
+	defer span.End()
+
+	message, err := j.vm.GetDiskWarpMessage(args.TxID)
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrMessageMissing
+	}
+	if err != nil {
+		return err
+	}
+	signature, requested, err := j.vm.ResignWarpMessage(ctx, args.TxID, message.Message)
+	if err != nil {
+		return err
+	}
+	reply.Message = message
+	reply.Signature = signature
+	reply.Requested = requested
+	return nil
+}
+
+type ValidatorsReply struct {
+	Validators []*WarpValidator `json:"validators"`
+}
+
+// Validators returns the current validator set of the subnet (sorted by
+// NodeID).
+func (j *JSONRPCServer) Validators(req *http.Request, _ *struct{}, reply *ValidatorsReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.Validators")
+	defer span.End()
+
+	validators, _ := j.vm.CurrentValidators(ctx)
+	reply.Validators = make([]*WarpValidator, 0, len(validators))
+	for _, vdr := range validators {
+		wv := &WarpValidator{
+			NodeID: vdr.NodeID,
+			Weight: vdr.Weight,
+		}
+		if vdr.PublicKey != nil {
+			wv.PublicKey = bls.PublicKeyToBytes(vdr.PublicKey)
+		}
+		reply.Validators = append(reply.Validators, wv)
+	}
+	sort.Slice(reply.Validators, func(i, j int) bool {
+		return bytes.Compare(reply.Validators[i].NodeID[:], reply.Validators[j].NodeID[:]) < 0
+	})
+	return nil
+}
+
+type GetValidatorSnapshotArgs struct {
+	Epoch uint64 `json:"epoch"`
+}
+
+type GetValidatorSnapshotReply struct {
+	Snapshot *chain.ValidatorSnapshot `json:"snapshot"`
+	Message  *warp.UnsignedMessage    `json:"message"`
+}
+
+// GetValidatorSnapshot returns the validator snapshot taken at the end of
+// [Epoch] and the warp message attesting to it. Signatures of the message can
+// be fetched with [GetWarpSignatures] using [chain.ValidatorSnapshotID].
+func (j *JSONRPCServer) GetValidatorSnapshot(
+	req *http.Request,
+	args *GetValidatorSnapshotArgs,
+	reply *GetValidatorSnapshotReply,
+) error {
+	_, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.GetValidatorSnapshot")
+	defer span.End()
+
+	snapshot, err := j.vm.GetValidatorSnapshot(args.Epoch)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return ErrSnapshotMissing
+	}
+	message, err := j.vm.GetOutgoingWarpMessage(chain.ValidatorSnapshotID(args.Epoch))
+	if err != nil {
+		return err
+	}
+	if message == nil {
+		return ErrMessageMissing
+	}
+	reply.Snapshot = snapshot
+	reply.Message = message
+	return nil
+}
+
+type StatePrefixesReply struct {
+	Prefixes []string `json:"prefixes"`
+}
+
+// StatePrefixes returns the names of the key prefixes that can be passed to
+// [QueryState].
+func (j *JSONRPCServer) StatePrefixes(_ *http.Request, _ *struct{}, reply *StatePrefixesReply) error {
+	reply.Prefixes = []string{}
+	if registry := j.vm.StateRegistry(); registry != nil {
+		reply.Prefixes = registry.Names()
+	}
+	return nil
+}
+
+type QueryStateArgs struct {
+	Prefix string `json:"prefix"`
+	Key    string `json:"key"`
+}
+
+type QueryStateReply struct {
+	Exists bool            `json:"exists"`
+	Value  json.RawMessage `json:"value,omitempty"`
+}
+
+// QueryState returns the decoded value stored at [Key] under the key prefix
+// registered as [Prefix] (see [StateRegistry]).
+func (j *JSONRPCServer) QueryState(req *http.Request, args *QueryStateArgs, reply *QueryStateReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.QueryState")
+	defer span.End()
+
+	registry := j.vm.StateRegistry()
+	if registry == nil {
+		return ErrUnknownStateQuery
+	}
+	q, ok := registry.Lookup(args.Prefix)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownStateQuery, args.Prefix)
+	}
+	k, err := q.Key(args.Key)
+	if err != nil {
+		return err
+	}
+	values, errs := j.vm.ReadState(ctx, [][]byte{k})
+	if errors.Is(errs[0], database.ErrNotFound) {
+		return nil
+	}
+	if errs[0] != nil {
+		return errs[0]
+	}
+	decoded, err := q.Decode(values[0])
+	if err != nil {
+		return err
+	}
+	reply.Value, err = json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	reply.Exists = true
+	return nil
+}
+
+type GetNonceArgs struct {
+	Address codec.Address `json:"address"`
+}
+
+type GetNonceReply struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+// GetNonce returns the nonce that the next transaction sponsored by [Address]
+// must use, if the chain uses sequential nonces (see
+// [chain.Rules.GetSequentialNonces]). Transactions in the mempool are not
+// considered.
+func (j *JSONRPCServer) GetNonce(req *http.Request, args *GetNonceArgs, reply *GetNonceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.GetNonce")
+	defer span.End()
+
+	nonce, err := j.vm.GetNonce(ctx, args.Address)
+	if err != nil {
+		return err
+	}
+	reply.Nonce = nonce
+	return nil
+}
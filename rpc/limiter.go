@@ -0,0 +1,291 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	DefaultMaxRequestSize = 4 * units.MiB
+
+	// maxTrackedIPs bounds the memory used to track per-IP rate limits. When
+	// exceeded, the least recently seen IP is forgotten (and gets a full
+	// bucket if it returns).
+	maxTrackedIPs = 16_384
+)
+
+// LimitConfig bounds the requests served by handlers wrapped with a
+// [Limiter]. Zero values disable the corresponding limit.
+type LimitConfig struct {
+	// Maximum size (in bytes) of a request body.
+	MaxRequestSize int64
+	// Maximum number of requests served at once (across all IPs). Requests
+	// over this ceiling are rejected instead of queued.
+	MaxConcurrentRequests int
+
+	// Requests per second allowed from a single IP, with bursts of up to
+	// [IPBurst] requests (defaults to the rate).
+	IPRate  float64
+	IPBurst int
+
+	// Requests per second allowed from a single IP for specific JSON-RPC
+	// methods (e.g. "hypersdk.submitTx"). This is enforced in addition to
+	// [IPRate] and allows expensive methods to be limited more aggressively.
+	//
+	// Each call in a JSON-RPC batch is charged separately.
+	MethodRates map[string]float64
+
+	// Requests from these proxies (i.e. a load balancer in front of the
+	// node) are attributed to the client in their X-Forwarded-For header
+	// instead of to the proxy. Only proxies that append the address of their
+	// client to the header should be trusted, otherwise clients can pick
+	// their own IP.
+	TrustedProxies []netip.Prefix
+}
+
+func NewDefaultLimitConfig() *LimitConfig {
+	return &LimitConfig{MaxRequestSize: DefaultMaxRequestSize}
+}
+
+// ParseTrustedProxies parses [values] (IPs or CIDRs) for use as
+// [LimitConfig.TrustedProxies].
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, len(values))
+	for i, v := range values {
+		if strings.Contains(v, "/") {
+			p, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, v)
+			}
+			proxies[i] = p.Masked()
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, v)
+		}
+		addr = addr.Unmap()
+		proxies[i] = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return proxies, nil
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills [b] at [rate] tokens per second (up to [burst]) and consumes
+// a token if one is available.
+func (b *bucket) take(now time.Time, rate float64, burst float64) bool {
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func burstFor(rate float64, burst int) float64 {
+	switch {
+	case burst > 0:
+		return float64(burst)
+	case rate > 1:
+		return rate
+	default:
+		return 1
+	}
+}
+
+type ipBuckets struct {
+	all     bucket
+	methods map[string]*bucket
+}
+
+// Limiter enforces a [LimitConfig] across all handlers it wraps, so an IP's
+// quota is shared between endpoints.
+type Limiter struct {
+	config *LimitConfig
+	slots  chan struct{}
+
+	l   sync.Mutex
+	ips *cache.LRU[string, *ipBuckets]
+}
+
+func NewLimiter(config *LimitConfig) *Limiter {
+	l := &Limiter{
+		config: config,
+		ips:    &cache.LRU[string, *ipBuckets]{Size: maxTrackedIPs},
+	}
+	if config.MaxConcurrentRequests > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrentRequests)
+	}
+	return l
+}
+
+// allow returns true if [ip] may call [method] (which may be empty if it
+// could not be determined).
+func (l *Limiter) allow(ip string, method string) bool {
+	methodRate := l.config.MethodRates[method]
+	if l.config.IPRate <= 0 && methodRate <= 0 {
+		return true
+	}
+
+	l.l.Lock()
+	defer l.l.Unlock()
+
+	b, ok := l.ips.Get(ip)
+	if !ok {
+		b = &ipBuckets{}
+		l.ips.Put(ip, b)
+	}
+	now := time.Now()
+	if l.config.IPRate > 0 && !b.all.take(now, l.config.IPRate, burstFor(l.config.IPRate, l.config.IPBurst)) {
+		return false
+	}
+	if methodRate <= 0 {
+		return true
+	}
+	if b.methods == nil {
+		b.methods = map[string]*bucket{}
+	}
+	mb, ok := b.methods[method]
+	if !ok {
+		mb = &bucket{}
+		b.methods[method] = mb
+	}
+	return mb.take(now, methodRate, burstFor(methodRate, 0))
+}
+
+// trusted returns true if [ip] is one of [LimitConfig.TrustedProxies].
+func (l *Limiter) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range l.config.TrustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the IP [r] is charged to. If [r] was forwarded by trusted
+// proxies, this is the last address in X-Forwarded-For that wasn't added by
+// one of them (any addresses before it may have been forged by the client).
+func (l *Limiter) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !l.trusted(ip) {
+		return ip
+	}
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if len(hop) == 0 {
+			continue
+		}
+		ip = hop
+		if !l.trusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// requestMethods returns the JSON-RPC method of each call in [body] (a
+// single request or a batch). If this can't be determined, [body] is
+// charged as one call to an unknown method.
+func requestMethods(body []byte) []string {
+	type request struct {
+		Method string `json:"method"`
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err == nil {
+		return []string{req.Method}
+	}
+	var batch []request
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		return []string{""}
+	}
+	methods := make([]string, len(batch))
+	for i, req := range batch {
+		methods[i] = req.Method
+	}
+	return methods
+}
+
+// Wrap returns [h] with the limits of [l] applied. Rejected requests receive
+// a 413 (too large) or 429 (too many requests) status.
+func (l *Limiter) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.slots != nil {
+			select {
+			case l.slots <- struct{}{}:
+				defer func() { <-l.slots }()
+			default:
+				http.Error(w, ErrTooManyRequests.Error(), http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		// Read the body so that we can determine the methods being called
+		methods := []string{""}
+		if r.Body != nil && r.Method == http.MethodPost {
+			reader := io.Reader(r.Body)
+			if maxSize := l.config.MaxRequestSize; maxSize > 0 {
+				reader = io.LimitReader(r.Body, maxSize+1)
+			}
+			body, err := io.ReadAll(reader)
+			_ = r.Body.Close()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if maxSize := l.config.MaxRequestSize; maxSize > 0 && int64(len(body)) > maxSize {
+				http.Error(w, ErrRequestTooLarge.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			methods = requestMethods(body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		ip := l.clientIP(r)
+		for _, method := range methods {
+			if !l.allow(ip, method) {
+				http.Error(w, ErrRateLimited.Error(), http.StatusTooManyRequests)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	require := require.New(t)
+
+	block := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("block") {
+			<-block
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	l := NewLimiter(&LimitConfig{
+		MaxRequestSize:        64,
+		MaxConcurrentRequests: 1,
+		IPRate:                0.001,
+		IPBurst:               3,
+		MethodRates:           map[string]float64{"hypersdk.submitTx": 0.001},
+	})
+	wrapped := l.Wrap(h)
+	serve := func(addr string, target string, body string) int {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Oversized requests are rejected
+	require.Equal(http.StatusRequestEntityTooLarge, serve("1.1.1.1:1", "/", strings.Repeat("a", 65)))
+
+	// Method quotas are enforced per IP
+	submit := `{"method":"hypersdk.submitTx"}`
+	require.Equal(http.StatusOK, serve("2.2.2.2:1", "/", submit))
+	require.Equal(http.StatusTooManyRequests, serve("2.2.2.2:2", "/", submit))
+	require.Equal(http.StatusOK, serve("3.3.3.3:1", "/", submit))
+
+	// IP rate limits apply across methods
+	require.Equal(http.StatusOK, serve("2.2.2.2:1", "/", `{"method":"hypersdk.ping"}`))
+	require.Equal(http.StatusTooManyRequests, serve("2.2.2.2:1", "/", `{"method":"hypersdk.ping"}`))
+
+	// Requests over the concurrency ceiling are rejected
+	done := make(chan int)
+	go func() {
+		done <- serve("4.4.4.4:1", "/?block", "{}")
+	}()
+	require.Eventually(func() bool { return len(l.slots) == 1 }, time.Second, time.Millisecond)
+	require.Equal(http.StatusTooManyRequests, serve("5.5.5.5:1", "/", "{}"))
+	close(block)
+	require.Equal(http.StatusOK, <-done)
+	require.Equal(http.StatusOK, serve("5.5.5.5:1", "/", "{}"))
+}
+
+func TestLimiterTrustedProxies(t *testing.T) {
+	require := require.New(t)
+
+	_, err := ParseTrustedProxies([]string{"10.0.0.0/8", "not-an-ip"})
+	require.ErrorIs(err, ErrInvalidProxy)
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "::ffff:172.16.0.1"})
+	require.NoError(err)
+	require.Len(proxies, 3)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	l := NewLimiter(&LimitConfig{IPRate: 0.001, TrustedProxies: proxies})
+	wrapped := l.Wrap(h)
+	serve := func(addr string, forwarded ...string) int {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		r.RemoteAddr = addr
+		for _, f := range forwarded {
+			r.Header.Add("X-Forwarded-For", f)
+		}
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for _, test := range []struct {
+		name      string
+		addr      string
+		forwarded []string
+		ip        string
+	}{
+		{name: "no proxy", addr: "1.1.1.1:1", ip: "1.1.1.1"},
+		{name: "untrusted proxy", addr: "1.1.1.1:1", forwarded: []string{"2.2.2.2"}, ip: "1.1.1.1"},
+		{name: "trusted proxy", addr: "10.1.2.3:1", forwarded: []string{"2.2.2.2"}, ip: "2.2.2.2"},
+		{name: "forged by client", addr: "10.1.2.3:1", forwarded: []string{"3.3.3.3, 2.2.2.2"}, ip: "2.2.2.2"},
+		{name: "chained proxies", addr: "192.168.1.1:1", forwarded: []string{"3.3.3.3, 2.2.2.2", "172.16.0.1"}, ip: "2.2.2.2"},
+		{name: "only proxies", addr: "10.1.2.3:1", forwarded: []string{"192.168.1.1"}, ip: "192.168.1.1"},
+		{name: "no header", addr: "10.1.2.3:1", ip: "10.1.2.3"},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = test.addr
+		for _, f := range test.forwarded {
+			r.Header.Add("X-Forwarded-For", f)
+		}
+		require.Equal(test.ip, l.clientIP(r), test.name)
+	}
+
+	// Clients behind a trusted proxy are limited separately
+	require.Equal(http.StatusOK, serve("10.0.0.1:1", "4.4.4.4"))
+	require.Equal(http.StatusOK, serve("10.0.0.1:1", "5.5.5.5"))
+	require.Equal(http.StatusTooManyRequests, serve("10.0.0.1:2", "4.4.4.4"))
+
+	// Clients can't avoid limits by forging the header
+	require.Equal(http.StatusOK, serve("6.6.6.6:1", "7.7.7.7"))
+	require.Equal(http.StatusTooManyRequests, serve("6.6.6.6:1", "8.8.8.8"))
+}
+
+func TestLimiterBatch(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]string{"a"}, requestMethods([]byte(`{"method":"a"}`)))
+	require.Equal([]string{"a", "b", "a"}, requestMethods([]byte(`[{"method":"a"},{"method":"b"},{"method":"a"}]`)))
+	require.Equal([]string{""}, requestMethods([]byte(`[]`)))
+	require.Equal([]string{""}, requestMethods([]byte(`invalid`)))
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	l := NewLimiter(&LimitConfig{
+		IPRate:      0.001,
+		IPBurst:     3,
+		MethodRates: map[string]float64{"hypersdk.submitTx": 0.001},
+	})
+	wrapped := l.Wrap(h)
+	serve := func(addr string, body string) int {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Each call in a batch is charged against method quotas
+	submit := `{"method":"hypersdk.submitTx"}`
+	require.Equal(http.StatusTooManyRequests, serve("1.1.1.1:1", "["+submit+","+submit+"]"))
+
+	// Each call in a batch is charged against IP quotas
+	ping := `{"method":"hypersdk.ping"}`
+	require.Equal(http.StatusOK, serve("2.2.2.2:1", "["+ping+","+ping+","+ping+"]"))
+	require.Equal(http.StatusTooManyRequests, serve("2.2.2.2:1", ping))
+	require.Equal(http.StatusTooManyRequests, serve("3.3.3.3:1", "["+ping+","+ping+","+ping+","+ping+"]"))
+}
@@ -0,0 +1,264 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// maxNonInclusionBlocks is the most blocks that can be returned by
+// [JSONRPCServer.GetTxNonInclusion].
+const maxNonInclusionBlocks = 8_192
+
+type GetTxNonInclusionArgs struct {
+	TxID ids.ID `json:"txId"`
+
+	// Exactly one of Timestamp and Height must be set (like the expiry of
+	// [chain.Base]).
+	Timestamp int64  `json:"timestamp"`
+	Height    uint64 `json:"height"`
+}
+
+// NonInclusionBlock is the header of an accepted block in a
+// [GetTxNonInclusionReply].
+type NonInclusionBlock struct {
+	BlockID   ids.ID `json:"blockId"`
+	Parent    ids.ID `json:"parent"`
+	Height    uint64 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func newNonInclusionBlock(blk *chain.StatelessBlock) *NonInclusionBlock {
+	return &NonInclusionBlock{
+		BlockID:   blk.ID(),
+		Parent:    blk.Prnt,
+		Height:    blk.Hght,
+		Timestamp: blk.Tmstmp,
+	}
+}
+
+// GetTxNonInclusionReply attests that a transaction was never included by
+// listing the contiguous range of accepted blocks that could have included
+// it.
+//
+// Before is the last block that couldn't include the transaction because it
+// is too old (or genesis, which has no transactions). Each of Blocks (by
+// height) could include the transaction but doesn't. After is the first block
+// past the expiry of the transaction, so it can never be included.
+type GetTxNonInclusionReply struct {
+	Before *NonInclusionBlock   `json:"before"`
+	Blocks []*NonInclusionBlock `json:"blocks"`
+	After  *NonInclusionBlock   `json:"after"`
+}
+
+// GetTxNonInclusion returns proof that [TxID], which expired at [Timestamp]
+// or [Height], was never included in an accepted block, so it is safe to
+// issue a replacement. It fails if [TxID] has not expired yet or was included.
+//
+// The proof is not signed: clients should check it against the blocks served
+// by a node they trust with [JSONRPCClient.VerifyTxNonInclusion].
+func (j *JSONRPCServer) GetTxNonInclusion(
+	req *http.Request,
+	args *GetTxNonInclusionArgs,
+	reply *GetTxNonInclusionReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.GetTxNonInclusion")
+	defer span.End()
+
+	if (args.Timestamp == 0) == (args.Height == 0) {
+		return ErrInvalidExpiry
+	}
+	overrides, err := j.vm.RulesOverrides(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Find the first block past the expiry of the tx
+	var (
+		tip  = j.vm.LastAcceptedBlock()
+		next *chain.StatelessBlock
+	)
+	if args.Timestamp != 0 {
+		if tip.Tmstmp <= args.Timestamp {
+			return fmt.Errorf("%w: timestamp=%d last accepted=%d", ErrTxNotExpired, args.Timestamp, tip.Tmstmp)
+		}
+		next, err = j.firstBlockAfter(ctx, tip, args.Timestamp)
+	} else {
+		if tip.Hght <= args.Height {
+			return fmt.Errorf("%w: height=%d last accepted=%d", ErrTxNotExpired, args.Height, tip.Hght)
+		}
+		next, err = j.acceptedBlockAtHeight(ctx, args.Height+1)
+	}
+	if err != nil {
+		return err
+	}
+	reply.After = newNonInclusionBlock(next)
+
+	// Walk back from [next] until we reach a block that is too old to
+	// include the tx
+	blk := next
+	for blk.Hght > 0 {
+		blk, err = j.vm.GetStatelessBlock(ctx, blk.Prnt)
+		if err != nil {
+			return err
+		}
+		header := newNonInclusionBlock(blk)
+		if blk.Hght == 0 || !canInclude(j.vm.Rules(blk.Tmstmp), overrides, args, header) {
+			reply.Before = header
+			break
+		}
+		for _, tx := range blk.Txs {
+			if tx.ID() == args.TxID {
+				return fmt.Errorf("%w: block=%s height=%d", ErrTxIncluded, blk.ID(), blk.Hght)
+			}
+		}
+		if len(reply.Blocks) == maxNonInclusionBlocks {
+			return ErrTooManyBlocks
+		}
+		reply.Blocks = append(reply.Blocks, header)
+	}
+	for i, k := 0, len(reply.Blocks)-1; i < k; i, k = i+1, k-1 {
+		reply.Blocks[i], reply.Blocks[k] = reply.Blocks[k], reply.Blocks[i]
+	}
+	return nil
+}
+
+// firstBlockAfter returns the first accepted block with a timestamp greater
+// than [timestamp], given that [tip] is one of them. We gallop back from [tip]
+// before searching so that recent expiries never touch pruned blocks.
+func (j *JSONRPCServer) firstBlockAfter(
+	ctx context.Context,
+	tip *chain.StatelessBlock,
+	timestamp int64,
+) (*chain.StatelessBlock, error) {
+	var (
+		hi   = tip
+		lo   uint64
+		step uint64 = 1
+	)
+	for {
+		if hi.Hght == 0 {
+			return hi, nil
+		}
+		height := uint64(0)
+		if hi.Hght > step {
+			height = hi.Hght - step
+		}
+		blk, err := j.acceptedBlockAtHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if blk.Tmstmp <= timestamp {
+			lo = height
+			break
+		}
+		hi = blk
+		step *= 2
+	}
+	for hi.Hght-lo > 1 {
+		mid := lo + (hi.Hght-lo)/2
+		blk, err := j.acceptedBlockAtHeight(ctx, mid)
+		if err != nil {
+			return nil, err
+		}
+		if blk.Tmstmp <= timestamp {
+			lo = mid
+		} else {
+			hi = blk
+		}
+	}
+	return hi, nil
+}
+
+func (j *JSONRPCServer) acceptedBlockAtHeight(ctx context.Context, height uint64) (*chain.StatelessBlock, error) {
+	blkID, err := j.vm.GetBlockIDAtHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return j.vm.GetStatelessBlock(ctx, blkID)
+}
+
+// canInclude returns true if [blk] could include a tx that expires as
+// described by [args].
+//
+// Misaligned timestamps and disabled expiries are not considered (they only
+// make the proof longer). Because the validity window may have been
+// overridden in state since [blk] was accepted, we use the largest of the
+// configured and overridden windows.
+func canInclude(r chain.Rules, o *chain.RulesOverrides, args *GetTxNonInclusionArgs, blk *NonInclusionBlock) bool {
+	if args.Height != 0 {
+		return blk.Height <= args.Height && args.Height-blk.Height <= r.GetHeightValidityWindow()
+	}
+	window := r.GetValidityWindow()
+	if o != nil && o.ValidityWindow != nil && *o.ValidityWindow > window {
+		window = *o.ValidityWindow
+	}
+	return blk.Timestamp <= args.Timestamp && args.Timestamp <= blk.Timestamp+window
+}
+
+// verifyTxNonInclusion checks that [proof] is a contiguous range of blocks
+// (as served by [getBlock]) that covers the validity window of the tx
+// described by [args] without including it.
+func verifyTxNonInclusion(
+	ctx context.Context,
+	parser chain.Parser,
+	overrides *chain.RulesOverrides,
+	args *GetTxNonInclusionArgs,
+	proof *GetTxNonInclusionReply,
+	getBlock func(context.Context, ids.ID) (*GetBlockReply, error),
+) error {
+	if (args.Timestamp == 0) == (args.Height == 0) {
+		return ErrInvalidExpiry
+	}
+	if proof.Before == nil || proof.After == nil {
+		return fmt.Errorf("%w: missing bounds", ErrInvalidNonInclusion)
+	}
+
+	// The range must start with a block that can't include the tx
+	if proof.Before.Height != 0 && canInclude(parser.Rules(proof.Before.Timestamp), overrides, args, proof.Before) {
+		return fmt.Errorf("%w: block %s could include tx", ErrInvalidNonInclusion, proof.Before.BlockID)
+	}
+
+	// The range must end with a block that is past the expiry of the tx
+	if (args.Timestamp != 0 && proof.After.Timestamp <= args.Timestamp) ||
+		(args.Height != 0 && proof.After.Height <= args.Height) {
+		return fmt.Errorf("%w: block %s is not past expiry", ErrInvalidNonInclusion, proof.After.BlockID)
+	}
+
+	// Every block in between must be accepted, contiguous, and not include
+	// the tx
+	blocks := make([]*NonInclusionBlock, 0, len(proof.Blocks)+2)
+	blocks = append(blocks, proof.Before)
+	blocks = append(blocks, proof.Blocks...)
+	blocks = append(blocks, proof.After)
+	for i, header := range blocks {
+		if i > 0 && (header.Parent != blocks[i-1].BlockID || header.Height != blocks[i-1].Height+1) {
+			return fmt.Errorf("%w: block %s does not follow %s", ErrInvalidNonInclusion, header.BlockID, blocks[i-1].BlockID)
+		}
+		blk, err := getBlock(ctx, header.BlockID)
+		if err != nil {
+			return err
+		}
+		if blk.BlockID != header.BlockID || blk.Parent != header.Parent ||
+			blk.Height != header.Height || blk.Timestamp != header.Timestamp {
+			return fmt.Errorf("%w: block %s does not match header", ErrInvalidNonInclusion, header.BlockID)
+		}
+		if i == 0 || i == len(blocks)-1 {
+			// [Before] and [After] can't include the tx
+			continue
+		}
+		for _, tx := range blk.Txs {
+			if tx.TxID == args.TxID {
+				return fmt.Errorf("%w: block=%s height=%d", ErrTxIncluded, blk.BlockID, blk.Height)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	HexFormat    = "hex"
+	Base64Format = "base64"
+)
+
+// EncodeRawTx formats [tx] as a string that can be provided to
+// [JSONRPCServer.SubmitRawTx]. Hex-formatted transactions are prefixed with
+// "0x".
+func EncodeRawTx(tx []byte, format string) (string, error) {
+	switch format {
+	case HexFormat:
+		return "0x" + hex.EncodeToString(tx), nil
+	case Base64Format:
+		return base64.StdEncoding.EncodeToString(tx), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownTxFormat, format)
+	}
+}
+
+// DecodeRawTx parses [tx] according to [format]. If [format] is empty, [tx]
+// is treated as hex if it has a "0x" prefix and as base64 otherwise.
+func DecodeRawTx(tx string, format string) ([]byte, error) {
+	if len(format) == 0 {
+		format = Base64Format
+		if strings.HasPrefix(tx, "0x") {
+			format = HexFormat
+		}
+	}
+	var (
+		raw []byte
+		err error
+	)
+	switch format {
+	case HexFormat:
+		raw, err = hex.DecodeString(strings.TrimPrefix(tx, "0x"))
+	case Base64Format:
+		raw, err = base64.StdEncoding.DecodeString(tx)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTxFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTxFormat, err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawTxFormats(t *testing.T) {
+	require := require.New(t)
+
+	tx := []byte{0, 1, 2, 0xfe, 0xff}
+	for _, format := range []string{HexFormat, Base64Format} {
+		raw, err := EncodeRawTx(tx, format)
+		require.NoError(err)
+		decoded, err := DecodeRawTx(raw, format)
+		require.NoError(err)
+		require.Equal(tx, decoded)
+
+		// The format can be inferred
+		decoded, err = DecodeRawTx(raw, "")
+		require.NoError(err)
+		require.Equal(tx, decoded)
+	}
+
+	_, err := DecodeRawTx("0xzz", "")
+	require.ErrorIs(err, ErrInvalidTxFormat)
+	_, err = DecodeRawTx("AAE=", "rlp")
+	require.ErrorIs(err, ErrUnknownTxFormat)
+	_, err = EncodeRawTx(tx, "rlp")
+	require.ErrorIs(err, ErrUnknownTxFormat)
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+type replayEntry struct {
+	seq  uint64
+	txID ids.ID
+	msg  []byte
+}
+
+// replayBuffer assigns monotonically increasing sequence numbers to all
+// messages published on a stream and retains the last [size] of them, so
+// that clients that reconnect can catch up on what they missed.
+//
+// replayBuffer is not thread-safe.
+type replayBuffer struct {
+	mode    byte
+	seq     uint64
+	entries []*replayEntry
+	head    int // index of oldest entry once [entries] is full
+}
+
+func newReplayBuffer(mode byte, size int) *replayBuffer {
+	return &replayBuffer{
+		mode:    mode,
+		entries: make([]*replayEntry, 0, size),
+	}
+}
+
+func (r *replayBuffer) frame(seq uint64, payload []byte) []byte {
+	msg := make([]byte, 1+consts.Uint64Len+len(payload))
+	msg[0] = r.mode
+	binary.BigEndian.PutUint64(msg[1:], seq)
+	copy(msg[1+consts.Uint64Len:], payload)
+	return msg
+}
+
+// reply frames [payload] as a reply to a resume request. Replies have a
+// sequence number of 0 (which is never assigned to a message on the stream)
+// and are not retained.
+func (r *replayBuffer) reply(payload []byte) []byte {
+	return r.frame(0, payload)
+}
+
+// add frames [payload] with the next sequence number and returns the
+// resulting message.
+func (r *replayBuffer) add(txID ids.ID, payload []byte) []byte {
+	r.seq++
+	msg := r.frame(r.seq, payload)
+
+	e := &replayEntry{seq: r.seq, txID: txID, msg: msg}
+	switch {
+	case cap(r.entries) == 0:
+	case len(r.entries) < cap(r.entries):
+		r.entries = append(r.entries, e)
+	default:
+		r.entries[r.head] = e
+		r.head = (r.head + 1) % len(r.entries)
+	}
+	return msg
+}
+
+// dropped returns the range of sequence numbers of the messages since
+// [resumeFrom] that are no longer retained (if any).
+func (r *replayBuffer) dropped(resumeFrom uint64) (uint64, uint64, bool) {
+	oldest := r.seq - uint64(len(r.entries)) + 1
+	if resumeFrom == 0 {
+		// Sequence numbers start at 1
+		resumeFrom = 1
+	}
+	if resumeFrom >= oldest {
+		return 0, 0, false
+	}
+	return resumeFrom, oldest - 1, true
+}
+
+// since returns all retained entries with a sequence number greater than or
+// equal to [resumeFrom], from oldest to newest (see [replayBuffer.dropped]
+// for the entries that can't be returned).
+func (r *replayBuffer) since(resumeFrom uint64) []*replayEntry {
+	entries := []*replayEntry{}
+	for i := 0; i < len(r.entries); i++ {
+		e := r.entries[(r.head+i)%len(r.entries)]
+		if e.seq < resumeFrom {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
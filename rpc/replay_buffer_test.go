@@ -0,0 +1,67 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	require := require.New(t)
+
+	r := newReplayBuffer(TxMode, 3)
+	for i := 0; i < 5; i++ {
+		msg := r.add(ids.GenerateTestID(), []byte{byte(i)})
+		require.Equal(TxMode, msg[0])
+		seq, payload, err := UnpackStreamMessage(msg[1:])
+		require.NoError(err)
+		require.Equal(uint64(i+1), seq)
+		require.Equal([]byte{byte(i)}, payload)
+	}
+
+	// Only the last 3 messages are retained
+	entries := r.since(0)
+	require.Len(entries, 3)
+	for i, e := range entries {
+		require.Equal(uint64(i+3), e.seq)
+	}
+	require.Len(r.since(5), 1)
+	require.Empty(r.since(6))
+
+	// Messages 1 and 2 are no longer retained
+	for _, resumeFrom := range []uint64{0, 1} {
+		from, to, ok := r.dropped(resumeFrom)
+		require.True(ok)
+		require.Equal(uint64(1), from)
+		require.Equal(uint64(2), to)
+	}
+	from, to, ok := r.dropped(2)
+	require.True(ok)
+	require.Equal(uint64(2), from)
+	require.Equal(uint64(2), to)
+	for _, resumeFrom := range []uint64{3, 6} {
+		_, _, ok = r.dropped(resumeFrom)
+		require.False(ok)
+	}
+	_, _, ok = newReplayBuffer(TxMode, 3).dropped(1)
+	require.False(ok)
+}
+
+func TestResumeMessage(t *testing.T) {
+	require := require.New(t)
+
+	txIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	msg, err := PackResumeMessage(TxMode, 10, txIDs)
+	require.NoError(err)
+	require.Equal(ResumeMode, msg[0])
+
+	mode, resumeFrom, utxIDs, err := UnpackResumeMessage(msg[1:])
+	require.NoError(err)
+	require.Equal(TxMode, mode)
+	require.Equal(uint64(10), resumeFrom)
+	require.Equal(txIDs, utxIDs)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// StateQuery describes how [JSONRPCServer.QueryState] serves the values
+// stored under a key prefix.
+type StateQuery struct {
+	// Key returns the state key referenced by [key] (the argument of the
+	// query, like an address).
+	Key func(key string) ([]byte, error)
+
+	// Decode returns the JSON-serializable form of [value].
+	Decode func(value []byte) (any, error)
+}
+
+// StateRegistry maps names (like "balance") to the [StateQuery] served for
+// them by [JSONRPCServer.QueryState], so that VMs don't need to write an
+// endpoint for each type of state they store.
+type StateRegistry struct {
+	l       sync.RWMutex
+	queries map[string]*StateQuery
+}
+
+func NewStateRegistry() *StateRegistry {
+	return &StateRegistry{queries: map[string]*StateQuery{}}
+}
+
+func (r *StateRegistry) Register(name string, q *StateQuery) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if _, ok := r.queries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateStateQuery, name)
+	}
+	r.queries[name] = q
+	return nil
+}
+
+func (r *StateRegistry) Lookup(name string) (*StateQuery, bool) {
+	r.l.RLock()
+	defer r.l.RUnlock()
+
+	q, ok := r.queries[name]
+	return q, ok
+}
+
+// Names returns the names of all registered queries in sorted order.
+func (r *StateRegistry) Names() []string {
+	r.l.RLock()
+	defer r.l.RUnlock()
+
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateRegistry(t *testing.T) {
+	require := require.New(t)
+
+	r := NewStateRegistry()
+	require.Empty(r.Names())
+	q := &StateQuery{
+		Key:    func(key string) ([]byte, error) { return []byte(key), nil },
+		Decode: func(v []byte) (any, error) { return string(v), nil },
+	}
+	require.NoError(r.Register("order", q))
+	require.NoError(r.Register("balance", q))
+	require.ErrorIs(r.Register("order", q), ErrDuplicateStateQuery)
+	require.Equal([]string{"balance", "order"}, r.Names())
+
+	found, ok := r.Lookup("balance")
+	require.True(ok)
+	require.Equal(q, found)
+	_, ok = r.Lookup("asset")
+	require.False(ok)
+}
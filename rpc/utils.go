@@ -4,18 +4,52 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/gorilla/rpc/v2"
 )
 
+type requestStartKey struct{}
+
+// EndpointURIs returns the URI of [endpoint] on each node of [uris].
+func EndpointURIs(uris []string, endpoint string) []string {
+	endpoints := make([]string, len(uris))
+	for i, uri := range uris {
+		endpoints[i] = strings.TrimSuffix(uri, "/") + endpoint
+	}
+	return endpoints
+}
+
+// RequestRecorder is invoked after each JSON-RPC request is served.
+type RequestRecorder func(method string, err error, latency time.Duration)
+
 func NewJSONRPCHandler(
 	name string,
 	service interface{},
+	recorders ...RequestRecorder,
 ) (http.Handler, error) {
 	server := rpc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	if len(recorders) > 0 {
+		server.RegisterInterceptFunc(func(i *rpc.RequestInfo) *http.Request {
+			ctx := context.WithValue(i.Request.Context(), requestStartKey{}, time.Now())
+			return i.Request.WithContext(ctx)
+		})
+		server.RegisterAfterFunc(func(i *rpc.RequestInfo) {
+			start, ok := i.Request.Context().Value(requestStartKey{}).(time.Time)
+			if !ok {
+				return
+			}
+			latency := time.Since(start)
+			for _, r := range recorders {
+				r(i.Method, i.Error, latency)
+			}
+		})
+	}
 	return server, server.RegisterService(service, name)
 }
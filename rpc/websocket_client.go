@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -26,8 +27,18 @@ type WebSocketClient struct {
 	writeStopped chan struct{}
 	readStopped  chan struct{}
 
-	pendingBlocks chan []byte
-	pendingTxs    chan []byte
+	pendingBlocks  chan []byte
+	pendingTxs     chan []byte
+	pendingMempool chan []byte
+
+	pendingDecisions chan []byte
+
+	// Sequence numbers of the last messages returned by [ListenBlock],
+	// [ListenBlockDecision], and [ListenTx]. These can be used to resume a
+	// stream after reconnecting.
+	blockCursor    atomic.Uint64
+	decisionCursor atomic.Uint64
+	txCursor       atomic.Uint64
 
 	startedClose bool
 	closed       bool
@@ -38,6 +49,19 @@ type WebSocketClient struct {
 // NewWebSocketClient creates a new client for the decision rpc server.
 // Dials into the server at [uri] and returns a client.
 func NewWebSocketClient(uri string, handshakeTimeout time.Duration, pending int, maxSize int) (*WebSocketClient, error) {
+	return NewAuthenticatedWebSocketClient(uri, handshakeTimeout, pending, maxSize, "")
+}
+
+// NewAuthenticatedWebSocketClient is the same as [NewWebSocketClient] but
+// provides [token] as a bearer token when connecting to the server (if
+// non-empty).
+func NewAuthenticatedWebSocketClient(
+	uri string,
+	handshakeTimeout time.Duration,
+	pending int,
+	maxSize int,
+	token string,
+) (*WebSocketClient, error) {
 	uri = strings.ReplaceAll(uri, "http://", "ws://")
 	uri = strings.ReplaceAll(uri, "https://", "wss://")
 	if !strings.HasPrefix(uri, "ws") { // fallback to default usage
@@ -47,21 +71,31 @@ func NewWebSocketClient(uri string, handshakeTimeout time.Duration, pending int,
 	uri += WebSocketEndpoint
 	// source: https://github.com/gorilla/websocket/blob/76ecc29eff79f0cedf70c530605e486fc32131d1/client.go#L140-L144
 	dialer := &websocket.Dialer{
-		Proxy:            http.ProxyFromEnvironment,
-		HandshakeTimeout: handshakeTimeout,
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  handshakeTimeout,
+		EnableCompression: pubsub.EnableCompression,
+	}
+	var header http.Header
+	if len(token) > 0 {
+		header = http.Header{}
+		header.Set("Authorization", "Bearer "+token)
 	}
-	conn, resp, err := dialer.Dial(uri, nil)
+	conn, resp, err := dialer.Dial(uri, header)
 	if err != nil {
 		return nil, err
 	}
 	resp.Body.Close()
 	wc := &WebSocketClient{
 		conn:          conn,
-		mb:            pubsub.NewMessageBuffer(&logging.NoLog{}, pending, maxSize, pubsub.MaxMessageWait),
+		mb:            pubsub.NewMessageBuffer(&logging.NoLog{}, pending, maxSize, pubsub.MaxBatchMessages, pubsub.MaxMessageWait),
 		readStopped:   make(chan struct{}),
 		writeStopped:  make(chan struct{}),
 		pendingBlocks: make(chan []byte, pending),
 		pendingTxs:    make(chan []byte, pending),
+
+		pendingMempool: make(chan []byte, pending),
+
+		pendingDecisions: make(chan []byte, pending),
 	}
 	go func() {
 		defer close(wc.readStopped)
@@ -89,6 +123,10 @@ func NewWebSocketClient(uri string, handshakeTimeout time.Duration, pending int,
 					wc.pendingBlocks <- tmsg
 				case TxMode:
 					wc.pendingTxs <- tmsg
+				case MempoolMode:
+					wc.pendingMempool <- tmsg
+				case BlockDecisionMode:
+					wc.pendingDecisions <- tmsg
 				default:
 					utils.Outf("{{orange}}unexpected message mode:{{/}} %x\n", msg[0])
 					continue
@@ -137,14 +175,48 @@ func (c *WebSocketClient) RegisterBlocks() error {
 	return c.mb.Send([]byte{BlockMode})
 }
 
+// ResumeBlocks subscribes to block messages, first replaying any blocks
+// accepted since sequence number [resumeFrom] that the server still retains.
+//
+// When reconnecting, [resumeFrom] should be set to one more than the
+// [BlockCursor] of the previous client.
+func (c *WebSocketClient) ResumeBlocks(resumeFrom uint64) error {
+	if c.closed {
+		return ErrClosed
+	}
+	msg, err := PackResumeMessage(BlockMode, resumeFrom, nil)
+	if err != nil {
+		return err
+	}
+	return c.mb.Send(msg)
+}
+
+// BlockCursor returns the sequence number of the last block returned by
+// [ListenBlock].
+func (c *WebSocketClient) BlockCursor() uint64 {
+	return c.blockCursor.Load()
+}
+
 // Listen listens for block messages from the streaming server.
+//
+// If blocks to resume (see [ResumeBlocks]) are no longer retained by the
+// server, an error wrapping [ErrResumeGap] is returned before the blocks
+// that are. The stream can still be listened to after this error.
 func (c *WebSocketClient) ListenBlock(
 	ctx context.Context,
 	parser chain.Parser,
 ) (*chain.StatefulBlock, []*chain.Result, chain.Dimensions, error) {
 	select {
 	case msg := <-c.pendingBlocks:
-		return UnpackBlockMessage(msg, parser)
+		seq, payload, err := UnpackStreamMessage(msg)
+		if err != nil {
+			return nil, nil, chain.Dimensions{}, err
+		}
+		if seq == 0 {
+			return nil, nil, chain.Dimensions{}, UnpackGapMessage(payload)
+		}
+		c.blockCursor.Store(seq)
+		return UnpackBlockMessage(payload, parser)
 	case <-c.readStopped:
 		return nil, nil, chain.Dimensions{}, c.err
 	case <-ctx.Done():
@@ -152,6 +224,63 @@ func (c *WebSocketClient) ListenBlock(
 	}
 }
 
+// RegisterBlockDecisions subscribes to verified, accepted, and rejected
+// block notifications.
+func (c *WebSocketClient) RegisterBlockDecisions() error {
+	if c.closed {
+		return ErrClosed
+	}
+	return c.mb.Send([]byte{BlockDecisionMode})
+}
+
+// ResumeBlockDecisions subscribes to block decisions, first replaying any
+// decisions made since sequence number [resumeFrom] that the server still
+// retains.
+func (c *WebSocketClient) ResumeBlockDecisions(resumeFrom uint64) error {
+	if c.closed {
+		return ErrClosed
+	}
+	msg, err := PackResumeMessage(BlockDecisionMode, resumeFrom, nil)
+	if err != nil {
+		return err
+	}
+	return c.mb.Send(msg)
+}
+
+// BlockDecisionCursor returns the sequence number of the last decision
+// returned by [ListenBlockDecision].
+func (c *WebSocketClient) BlockDecisionCursor() uint64 {
+	return c.decisionCursor.Load()
+}
+
+// ListenBlockDecision returns the next block decision from the streaming
+// server. A block may be reported as verified and then later as either
+// accepted or rejected.
+//
+// As with [ListenBlock], an error wrapping [ErrResumeGap] is returned if
+// decisions to resume are no longer retained by the server.
+func (c *WebSocketClient) ListenBlockDecision(
+	ctx context.Context,
+	parser chain.Parser,
+) (*BlockDecision, error) {
+	select {
+	case msg := <-c.pendingDecisions:
+		seq, payload, err := UnpackStreamMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		if seq == 0 {
+			return nil, UnpackGapMessage(payload)
+		}
+		c.decisionCursor.Store(seq)
+		return UnpackBlockDecisionMessage(payload, parser)
+	case <-c.readStopped:
+		return nil, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // IssueTx sends [tx] to the streaming rpc server.
 func (c *WebSocketClient) RegisterTx(tx *chain.Transaction) error {
 	if c.closed {
@@ -160,6 +289,30 @@ func (c *WebSocketClient) RegisterTx(tx *chain.Transaction) error {
 	return c.mb.Send(append([]byte{TxMode}, tx.Bytes()...))
 }
 
+// ResumeTxs re-attaches to the outcome of [txIDs], which were registered
+// on a previous connection. Decisions made since sequence number
+// [resumeFrom] are replayed if the server still retains them.
+//
+// Any of [txIDs] that the server has no listener or retained decision for
+// is returned by [ListenTx] with [ErrResumeGap] (if decisions since
+// [resumeFrom] were dropped) or [ErrTxNotFound] as its error.
+func (c *WebSocketClient) ResumeTxs(resumeFrom uint64, txIDs []ids.ID) error {
+	if c.closed {
+		return ErrClosed
+	}
+	msg, err := PackResumeMessage(TxMode, resumeFrom, txIDs)
+	if err != nil {
+		return err
+	}
+	return c.mb.Send(msg)
+}
+
+// TxCursor returns the sequence number of the last tx decision returned by
+// [ListenTx].
+func (c *WebSocketClient) TxCursor() uint64 {
+	return c.txCursor.Load()
+}
+
 // ListenForTx listens for responses from the streamingServer.
 //
 // TODO: add the option to subscribe to a single TxID to avoid
@@ -168,7 +321,14 @@ func (c *WebSocketClient) RegisterTx(tx *chain.Transaction) error {
 func (c *WebSocketClient) ListenTx(ctx context.Context) (ids.ID, error, *chain.Result, error) {
 	select {
 	case msg := <-c.pendingTxs:
-		return UnpackTxMessage(msg)
+		seq, payload, err := UnpackStreamMessage(msg)
+		if err != nil {
+			return ids.Empty, nil, nil, err
+		}
+		if seq > 0 {
+			c.txCursor.Store(seq)
+		}
+		return UnpackTxMessage(payload)
 	case <-c.readStopped:
 		return ids.Empty, nil, nil, c.err
 	case <-ctx.Done():
@@ -176,6 +336,33 @@ func (c *WebSocketClient) ListenTx(ctx context.Context) (ids.ID, error, *chain.R
 	}
 }
 
+// RegisterMempool subscribes to transactions entering the server's mempool
+// (if the server has enabled the mempool stream). If [includeBytes] is true,
+// the full transaction is sent instead of only its ID.
+func (c *WebSocketClient) RegisterMempool(includeBytes bool) error {
+	if c.closed {
+		return ErrClosed
+	}
+	return c.mb.Send(PackMempoolRequest(includeBytes))
+}
+
+// ListenMempool returns the ID (and bytes, if requested) of the next
+// transaction to enter the server's mempool.
+func (c *WebSocketClient) ListenMempool(ctx context.Context) (ids.ID, []byte, error) {
+	select {
+	case msg := <-c.pendingMempool:
+		_, payload, err := UnpackStreamMessage(msg)
+		if err != nil {
+			return ids.Empty, nil, err
+		}
+		return UnpackMempoolTxMessage(payload)
+	case <-c.readStopped:
+		return ids.Empty, nil, c.err
+	case <-ctx.Done():
+		return ids.Empty, nil, ctx.Err()
+	}
+}
+
 // Close closes [c]'s connection to the decision rpc server.
 func (c *WebSocketClient) Close() error {
 	var err error
@@ -195,3 +382,13 @@ func (c *WebSocketClient) Close() error {
 func (c *WebSocketClient) Closed() bool {
 	return c.closed
 }
+
+// disconnected returns true if [c] stopped reading from its connection.
+func (c *WebSocketClient) disconnected() bool {
+	select {
+	case <-c.readStopped:
+		return true
+	default:
+		return false
+	}
+}
@@ -4,7 +4,9 @@
 package rpc
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
@@ -13,20 +15,33 @@ import (
 )
 
 const (
-	BlockMode byte = 0
-	TxMode    byte = 1
+	BlockMode   byte = 0
+	TxMode      byte = 1
+	ResumeMode  byte = 2
+	MempoolMode byte = 3
+
+	// BlockDecisionMode messages report every verified, accepted, and
+	// rejected block (unlike [BlockMode], which only reports accepted
+	// blocks).
+	BlockDecisionMode byte = 4
+
+	// maxResumeTxs bounds the number of txs a client can re-attach to
+	// when resuming the tx stream.
+	maxResumeTxs = 1024
 )
 
 func PackBlockMessage(b *chain.StatelessBlock) ([]byte, error) {
 	results := b.Results()
-	size := codec.BytesLen(b.Bytes()) + consts.IntLen + codec.CummSize(results) + chain.DimensionsLen
+	size := codec.BytesLen(b.Bytes()) + consts.IntLen + chain.ResultsSize(results) + chain.DimensionsLen()
 	p := codec.NewWriter(size, consts.MaxInt)
 	p.PackBytes(b.Bytes())
-	mresults, err := chain.MarshalResults(results)
-	if err != nil {
+	// The results are copied into [p], so their buffer can be reused
+	mresults := codec.GetWriter(chain.ResultsSize(results), consts.MaxInt)
+	defer codec.PutWriter(mresults)
+	if err := chain.PackResults(mresults, results); err != nil {
 		return nil, err
 	}
-	p.PackBytes(mresults)
+	p.PackBytes(mresults.Bytes())
 	p.PackFixedBytes(b.FeeManager().UnitPrices().Bytes())
 	return p.Bytes(), p.Err()
 }
@@ -48,8 +63,8 @@ func UnpackBlockMessage(
 	if err != nil {
 		return nil, nil, chain.Dimensions{}, err
 	}
-	pricesMsg := make([]byte, chain.DimensionsLen)
-	p.UnpackFixedBytes(chain.DimensionsLen, &pricesMsg)
+	pricesMsg := make([]byte, chain.DimensionsLen())
+	p.UnpackFixedBytes(chain.DimensionsLen(), &pricesMsg)
 	prices, err := chain.UnpackDimensions(pricesMsg)
 	if err != nil {
 		return nil, nil, chain.Dimensions{}, err
@@ -93,7 +108,7 @@ func UnpackTxMessage(msg []byte) (ids.ID, error, *chain.Result, error) {
 	p.UnpackID(true, &txID)
 	if p.UnpackBool() {
 		err := p.UnpackString(true)
-		return ids.Empty, errors.New(err), nil, p.Err()
+		return txID, errors.New(err), nil, p.Err()
 	}
 	result, err := chain.UnmarshalResult(p)
 	if err != nil {
@@ -104,3 +119,212 @@ func UnpackTxMessage(msg []byte) (ids.ID, error, *chain.Result, error) {
 	}
 	return txID, nil, result, p.Err()
 }
+
+// PackResumeMessage packs a request to resume the stream [mode] from
+// sequence number [resumeFrom]. When resuming [TxMode], [txIDs] are the
+// transactions the client is still waiting on.
+func PackResumeMessage(mode byte, resumeFrom uint64, txIDs []ids.ID) ([]byte, error) {
+	size := consts.ByteLen*2 + consts.Uint64Len + consts.IntLen + len(txIDs)*consts.IDLen
+	p := codec.NewWriter(size, consts.MaxInt)
+	p.PackByte(ResumeMode)
+	p.PackByte(mode)
+	p.PackUint64(resumeFrom)
+	p.PackInt(len(txIDs))
+	for _, txID := range txIDs {
+		p.PackID(txID)
+	}
+	return p.Bytes(), p.Err()
+}
+
+// UnpackResumeMessage unpacks a resume request (excluding the leading
+// [ResumeMode] byte).
+func UnpackResumeMessage(msg []byte) (byte, uint64, []ids.ID, error) {
+	p := codec.NewReader(msg, consts.NetworkSizeLimit)
+	mode := p.UnpackByte()
+	resumeFrom := p.UnpackUint64(false)
+	count := p.UnpackInt(false)
+	if count > maxResumeTxs {
+		return 0, 0, nil, ErrTooManyResumeTxs
+	}
+	txIDs := make([]ids.ID, count)
+	for i := 0; i < count; i++ {
+		p.UnpackID(true, &txIDs[i])
+	}
+	if !p.Empty() {
+		return 0, 0, nil, chain.ErrInvalidObject
+	}
+	return mode, resumeFrom, txIDs, p.Err()
+}
+
+// UnpackStreamMessage splits a message received from the streaming server
+// into its sequence number and payload.
+//
+// Replies to a resume request have a sequence number of 0 (see
+// [WebSocketServer.Resume]). They are not part of the stream, so they
+// don't move its cursor.
+func UnpackStreamMessage(msg []byte) (uint64, []byte, error) {
+	if len(msg) < consts.Uint64Len {
+		return 0, nil, chain.ErrInvalidObject
+	}
+	return binary.BigEndian.Uint64(msg), msg[consts.Uint64Len:], nil
+}
+
+// PackGapMessage packs a notification that the messages with sequence
+// numbers [from] to [to] can't be replayed because the server no longer
+// retains them.
+func PackGapMessage(from uint64, to uint64) []byte {
+	p := codec.NewWriter(consts.Uint64Len*2, consts.MaxInt)
+	p.PackUint64(from)
+	p.PackUint64(to)
+	return p.Bytes()
+}
+
+// UnpackGapMessage unpacks a notification packed with [PackGapMessage] into
+// an error wrapping [ErrResumeGap].
+func UnpackGapMessage(msg []byte) error {
+	p := codec.NewReader(msg, consts.MaxInt)
+	from := p.UnpackUint64(true)
+	to := p.UnpackUint64(true)
+	if !p.Empty() {
+		return chain.ErrInvalidObject
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: messages %d to %d", ErrResumeGap, from, to)
+}
+
+// PackMempoolRequest packs a request to subscribe to transactions entering
+// the mempool. If [includeBytes] is true, the full transaction is sent
+// instead of only its ID.
+func PackMempoolRequest(includeBytes bool) []byte {
+	if includeBytes {
+		return []byte{MempoolMode, 1}
+	}
+	return []byte{MempoolMode, 0}
+}
+
+// PackMempoolTxMessage packs a notification that [txID] entered the mempool.
+// [txBytes] is empty if the listener only requested IDs.
+func PackMempoolTxMessage(txID ids.ID, txBytes []byte) ([]byte, error) {
+	size := consts.IDLen + codec.BytesLen(txBytes)
+	p := codec.NewWriter(size, consts.MaxInt)
+	p.PackID(txID)
+	p.PackBytes(txBytes)
+	return p.Bytes(), p.Err()
+}
+
+// UnpackMempoolTxMessage unpacks a notification packed with
+// [PackMempoolTxMessage].
+func UnpackMempoolTxMessage(msg []byte) (ids.ID, []byte, error) {
+	p := codec.NewReader(msg, consts.MaxInt)
+	var txID ids.ID
+	p.UnpackID(true, &txID)
+	var txBytes []byte
+	p.UnpackBytes(-1, false, &txBytes)
+	if !p.Empty() {
+		return ids.Empty, nil, chain.ErrInvalidObject
+	}
+	return txID, txBytes, p.Err()
+}
+
+// BlockStatus is the state of a block reported in a [BlockDecisionMode]
+// message.
+type BlockStatus byte
+
+const (
+	// BlockVerified blocks are processing and may still be rejected.
+	BlockVerified BlockStatus = 0
+	// BlockAccepted blocks are final.
+	BlockAccepted BlockStatus = 1
+	// BlockRejected blocks will never be accepted.
+	BlockRejected BlockStatus = 2
+)
+
+func (s BlockStatus) String() string {
+	switch s {
+	case BlockVerified:
+		return "verified"
+	case BlockAccepted:
+		return "accepted"
+	case BlockRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// BlockDecision is a block lifecycle event. [Block], [Results], and
+// [UnitPrices] are only populated for verified and accepted blocks and
+// [Reason] is only populated for rejected blocks.
+type BlockDecision struct {
+	Status  BlockStatus
+	BlockID ids.ID
+	Height  uint64
+
+	Block      *chain.StatefulBlock
+	Results    []*chain.Result
+	UnitPrices chain.Dimensions
+
+	Reason string
+}
+
+// PackBlockDecisionMessage packs a verified or accepted block decision.
+func PackBlockDecisionMessage(status BlockStatus, b *chain.StatelessBlock) ([]byte, error) {
+	msg, err := PackBlockMessage(b)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(status)}, msg...), nil
+}
+
+// PackRejectedBlockMessage packs a rejected block decision.
+func PackRejectedBlockMessage(blkID ids.ID, height uint64, reason string) ([]byte, error) {
+	size := consts.ByteLen + consts.IDLen + consts.Uint64Len + codec.StringLen(reason)
+	p := codec.NewWriter(size, consts.MaxInt)
+	p.PackByte(byte(BlockRejected))
+	p.PackID(blkID)
+	p.PackUint64(height)
+	p.PackString(reason)
+	return p.Bytes(), p.Err()
+}
+
+// UnpackBlockDecisionMessage unpacks a message packed with
+// [PackBlockDecisionMessage] or [PackRejectedBlockMessage].
+func UnpackBlockDecisionMessage(msg []byte, parser chain.Parser) (*BlockDecision, error) {
+	if len(msg) == 0 {
+		return nil, chain.ErrInvalidObject
+	}
+	status := BlockStatus(msg[0])
+	switch status {
+	case BlockVerified, BlockAccepted:
+		blk, results, prices, err := UnpackBlockMessage(msg[1:], parser)
+		if err != nil {
+			return nil, err
+		}
+		blkID, err := blk.ID()
+		if err != nil {
+			return nil, err
+		}
+		return &BlockDecision{
+			Status:     status,
+			BlockID:    blkID,
+			Height:     blk.Hght,
+			Block:      blk,
+			Results:    results,
+			UnitPrices: prices,
+		}, nil
+	case BlockRejected:
+		p := codec.NewReader(msg[1:], consts.MaxInt)
+		d := &BlockDecision{Status: status}
+		p.UnpackID(true, &d.BlockID)
+		d.Height = p.UnpackUint64(false)
+		d.Reason = p.UnpackString(false)
+		if !p.Empty() {
+			return nil, chain.ErrInvalidObject
+		}
+		return d, p.Err()
+	default:
+		return nil, chain.ErrInvalidObject
+	}
+}
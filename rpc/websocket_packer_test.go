@@ -0,0 +1,36 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectedBlockMessage(t *testing.T) {
+	require := require.New(t)
+
+	blkID := ids.GenerateTestID()
+	msg, err := PackRejectedBlockMessage(blkID, 10, "ancestor rejected")
+	require.NoError(err)
+
+	decision, err := UnpackBlockDecisionMessage(msg, nil)
+	require.NoError(err)
+	require.Equal(BlockRejected, decision.Status)
+	require.Equal(blkID, decision.BlockID)
+	require.Equal(uint64(10), decision.Height)
+	require.Equal("ancestor rejected", decision.Reason)
+	require.Nil(decision.Block)
+
+	// Trailing bytes are not allowed
+	_, err = UnpackBlockDecisionMessage(append(msg, 0), nil)
+	require.ErrorIs(err, chain.ErrInvalidObject)
+
+	// Unknown statuses are not allowed
+	_, err = UnpackBlockDecisionMessage([]byte{3}, nil)
+	require.ErrorIs(err, chain.ErrInvalidObject)
+}
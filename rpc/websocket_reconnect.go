@@ -0,0 +1,458 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/pubsub"
+)
+
+// ReconnectConfig configures a [ReconnectingWebSocketClient].
+type ReconnectConfig struct {
+	HandshakeTimeout time.Duration
+	Pending          int
+	MaxSize          int
+	Token            string // provided as a bearer token (if non-empty)
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// attempts to reconnect. At most [MaxAttempts] consecutive attempts are
+	// made (0 for unlimited).
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	MaxAttempts int
+
+	// OnReconnect (if set) is called after reconnecting to the server because
+	// the previous connection failed with [err].
+	OnReconnect func(err error)
+
+	// OnGap (if set) is called before returning a block whose height is more
+	// than one above the previous block returned by
+	// [ReconnectingWebSocketClient.ListenBlock] (which happens when the
+	// server no longer retains the blocks accepted while disconnected).
+	OnGap func(*BlockGap)
+
+	// Backfill (if set) is used to fetch the blocks of each [BlockGap] of at
+	// most [MaxBackfill] blocks before it is passed to [OnGap].
+	Backfill    *JSONRPCClient
+	MaxBackfill int
+}
+
+func DefaultReconnectConfig() *ReconnectConfig {
+	return &ReconnectConfig{
+		HandshakeTimeout: DefaultHandshakeTimeout,
+		Pending:          pubsub.MaxPendingMessages,
+		MaxSize:          pubsub.MaxReadMessageSize,
+		MinBackoff:       250 * time.Millisecond,
+		MaxBackoff:       30 * time.Second,
+		MaxBackfill:      1_024,
+	}
+}
+
+// BlockGap is a range of blocks that were not received by a
+// [ReconnectingWebSocketClient].
+type BlockGap struct {
+	// From and To are the heights of the first and last missed blocks.
+	From uint64
+	To   uint64
+
+	// Blocks are the missed blocks (if backfilled), in order of height. If
+	// backfilling failed, [Err] is set and [Blocks] only includes the blocks
+	// fetched before the failure.
+	Blocks []*GetBlockReply
+	Err    error
+}
+
+// ReconnectingWebSocketClient is a [WebSocketClient] that reconnects to the
+// server whenever its connection fails. After reconnecting, it resumes all
+// of its subscriptions from the last message returned to the caller (see
+// [WebSocketClient.ResumeBlocks]), so long-lived listeners don't silently
+// miss blocks.
+//
+// Blocks that the server no longer retains are reported with
+// [ReconnectConfig.OnGap].
+type ReconnectingWebSocketClient struct {
+	uri    string
+	config ReconnectConfig
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	l   sync.Mutex
+	cli *WebSocketClient
+
+	// Subscriptions to restore after reconnecting
+	blocks    bool
+	decisions bool
+	mempool   *bool // include bytes
+	txs       set.Set[ids.ID]
+
+	// Sequence numbers of the last messages returned on each stream (across
+	// connections)
+	blockCursor    uint64
+	decisionCursor uint64
+	txCursor       uint64
+
+	lastHeight uint64
+	reconnects int
+}
+
+// NewReconnectingWebSocketClient dials into the server at [uri] and returns
+// a client that reconnects to it as configured by [config].
+func NewReconnectingWebSocketClient(uri string, config *ReconnectConfig) (*ReconnectingWebSocketClient, error) {
+	c := &ReconnectingWebSocketClient{
+		uri:    uri,
+		config: *config,
+		stop:   make(chan struct{}),
+		txs:    set.Set[ids.ID]{},
+	}
+	cli, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.cli = cli
+	return c, nil
+}
+
+func (c *ReconnectingWebSocketClient) dial() (*WebSocketClient, error) {
+	return NewAuthenticatedWebSocketClient(
+		c.uri,
+		c.config.HandshakeTimeout,
+		c.config.Pending,
+		c.config.MaxSize,
+		c.config.Token,
+	)
+}
+
+func (c *ReconnectingWebSocketClient) backoff(attempt int) time.Duration {
+	backoff := c.config.MinBackoff
+	for i := 0; i < attempt && backoff < c.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > c.config.MaxBackoff {
+		return c.config.MaxBackoff
+	}
+	return backoff
+}
+
+// reconnect replaces [old] (which failed with [cause]) with a new connection
+// and restores all subscriptions on it (unless another listener already
+// replaced [old]).
+func (c *ReconnectingWebSocketClient) reconnect(ctx context.Context, old *WebSocketClient, cause error) error {
+	replaced, err := c.replace(ctx, old)
+	if err != nil {
+		return err
+	}
+	if replaced && c.config.OnReconnect != nil {
+		c.config.OnReconnect(cause)
+	}
+	return nil
+}
+
+func (c *ReconnectingWebSocketClient) replace(ctx context.Context, old *WebSocketClient) (bool, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if c.cli != old {
+		return false, nil
+	}
+	_ = old.Close()
+	for attempt := 0; ; attempt++ {
+		cli, err := c.dial()
+		if err == nil {
+			if err = c.resubscribe(cli); err == nil {
+				c.cli = cli
+				c.reconnects++
+				return true, nil
+			}
+			_ = cli.Close()
+		}
+		if c.config.MaxAttempts > 0 && attempt+1 >= c.config.MaxAttempts {
+			return false, fmt.Errorf("unable to reconnect after %d attempts: %w", attempt+1, err)
+		}
+		t := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-t.C:
+		case <-c.stop:
+			t.Stop()
+			return false, ErrClosed
+		case <-ctx.Done():
+			t.Stop()
+			return false, ctx.Err()
+		}
+	}
+}
+
+// resubscribe must be called with [c.l] held.
+func (c *ReconnectingWebSocketClient) resubscribe(cli *WebSocketClient) error {
+	if c.blocks {
+		// Without a cursor, resuming would replay blocks accepted before we
+		// first subscribed
+		if c.blockCursor > 0 {
+			if err := cli.ResumeBlocks(c.blockCursor + 1); err != nil {
+				return err
+			}
+		} else if err := cli.RegisterBlocks(); err != nil {
+			return err
+		}
+	}
+	if c.decisions {
+		if c.decisionCursor > 0 {
+			if err := cli.ResumeBlockDecisions(c.decisionCursor + 1); err != nil {
+				return err
+			}
+		} else if err := cli.RegisterBlockDecisions(); err != nil {
+			return err
+		}
+	}
+	if c.mempool != nil {
+		if err := cli.RegisterMempool(*c.mempool); err != nil {
+			return err
+		}
+	}
+	// Only the decisions of [c.txs] are replayed, so we can resume from the
+	// cursor even if we haven't received any decisions yet
+	pending := c.txs.List()
+	for len(pending) > 0 {
+		batch := pending[:math.Min(len(pending), maxResumeTxs)]
+		pending = pending[len(batch):]
+		if err := cli.ResumeTxs(c.txCursor+1, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ReconnectingWebSocketClient) current() *WebSocketClient {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	return c.cli
+}
+
+// listen calls [f] with the current connection until it doesn't fail
+// because the connection was lost.
+func (c *ReconnectingWebSocketClient) listen(ctx context.Context, f func(*WebSocketClient) error) error {
+	for {
+		cli := c.current()
+		err := f(cli)
+		if err == nil || ctx.Err() != nil || !cli.disconnected() {
+			return err
+		}
+		select {
+		case <-c.stop:
+			return ErrClosed
+		default:
+		}
+		if err := c.reconnect(ctx, cli, err); err != nil {
+			return err
+		}
+	}
+}
+
+// subscribe records a subscription with [update] and sends it with [send]
+// on the current connection. If the connection was lost, the subscription
+// is sent when reconnecting.
+func (c *ReconnectingWebSocketClient) subscribe(update func(), send func(*WebSocketClient) error) error {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	select {
+	case <-c.stop:
+		return ErrClosed
+	default:
+	}
+	update()
+	if err := send(c.cli); err != nil && !c.cli.disconnected() {
+		return err
+	}
+	return nil
+}
+
+func (c *ReconnectingWebSocketClient) RegisterBlocks() error {
+	return c.subscribe(
+		func() { c.blocks = true },
+		func(cli *WebSocketClient) error { return cli.RegisterBlocks() },
+	)
+}
+
+// ListenBlock returns the next block from the streaming server. Blocks
+// replayed after reconnecting that were already returned are skipped.
+func (c *ReconnectingWebSocketClient) ListenBlock(
+	ctx context.Context,
+	parser chain.Parser,
+) (*chain.StatefulBlock, []*chain.Result, chain.Dimensions, error) {
+	for {
+		var (
+			blk     *chain.StatefulBlock
+			results []*chain.Result
+			prices  chain.Dimensions
+			cursor  uint64
+		)
+		err := c.listen(ctx, func(cli *WebSocketClient) error {
+			var err error
+			blk, results, prices, err = cli.ListenBlock(ctx, parser)
+			cursor = cli.BlockCursor()
+			return err
+		})
+		if errors.Is(err, ErrResumeGap) {
+			// The missed blocks are reported with [ReconnectConfig.OnGap] once
+			// the next block is received
+			continue
+		}
+		if err != nil {
+			return nil, nil, chain.Dimensions{}, err
+		}
+
+		c.l.Lock()
+		c.blockCursor = cursor
+		last := c.lastHeight
+		if blk.Hght > last {
+			c.lastHeight = blk.Hght
+		}
+		c.l.Unlock()
+		if last == 0 {
+			return blk, results, prices, nil
+		}
+		if blk.Hght <= last {
+			continue
+		}
+		if blk.Hght > last+1 {
+			c.handleGap(ctx, last+1, blk.Hght-1)
+		}
+		return blk, results, prices, nil
+	}
+}
+
+func (c *ReconnectingWebSocketClient) handleGap(ctx context.Context, from uint64, to uint64) {
+	if c.config.OnGap == nil {
+		return
+	}
+	gap := &BlockGap{From: from, To: to}
+	if c.config.Backfill != nil {
+		if to-from+1 > uint64(c.config.MaxBackfill) {
+			gap.Err = fmt.Errorf("%w: %d blocks", ErrGapTooLarge, to-from+1)
+		}
+		for height := from; gap.Err == nil && height <= to; height++ {
+			blk, err := c.config.Backfill.GetBlockByHeight(ctx, height)
+			if err != nil {
+				gap.Err = err
+				break
+			}
+			gap.Blocks = append(gap.Blocks, blk)
+		}
+	}
+	c.config.OnGap(gap)
+}
+
+func (c *ReconnectingWebSocketClient) RegisterBlockDecisions() error {
+	return c.subscribe(
+		func() { c.decisions = true },
+		func(cli *WebSocketClient) error { return cli.RegisterBlockDecisions() },
+	)
+}
+
+// ListenBlockDecision returns the next block decision from the streaming
+// server. Decisions made while disconnected that the server no longer
+// retains are reported with an error wrapping [ErrResumeGap] (after which
+// the client can keep listening).
+func (c *ReconnectingWebSocketClient) ListenBlockDecision(
+	ctx context.Context,
+	parser chain.Parser,
+) (*BlockDecision, error) {
+	var (
+		decision *BlockDecision
+		cursor   uint64
+	)
+	if err := c.listen(ctx, func(cli *WebSocketClient) error {
+		var err error
+		decision, err = cli.ListenBlockDecision(ctx, parser)
+		cursor = cli.BlockDecisionCursor()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	c.l.Lock()
+	c.decisionCursor = cursor
+	c.l.Unlock()
+	return decision, nil
+}
+
+// RegisterTx sends [tx] to the streaming server. If the connection is lost
+// before [tx] is decided, the client re-attaches to it when reconnecting.
+func (c *ReconnectingWebSocketClient) RegisterTx(tx *chain.Transaction) error {
+	return c.subscribe(
+		func() { c.txs.Add(tx.ID()) },
+		func(cli *WebSocketClient) error { return cli.RegisterTx(tx) },
+	)
+}
+
+func (c *ReconnectingWebSocketClient) ListenTx(ctx context.Context) (ids.ID, error, *chain.Result, error) {
+	var (
+		txID   ids.ID
+		txErr  error
+		result *chain.Result
+		cursor uint64
+	)
+	if err := c.listen(ctx, func(cli *WebSocketClient) error {
+		var err error
+		txID, txErr, result, err = cli.ListenTx(ctx)
+		cursor = cli.TxCursor()
+		return err
+	}); err != nil {
+		return ids.Empty, nil, nil, err
+	}
+	c.l.Lock()
+	c.txCursor = cursor
+	c.txs.Remove(txID)
+	c.l.Unlock()
+	return txID, txErr, result, nil
+}
+
+// RegisterMempool subscribes to transactions entering the server's mempool.
+// Transactions that enter the mempool while disconnected are not replayed.
+func (c *ReconnectingWebSocketClient) RegisterMempool(includeBytes bool) error {
+	return c.subscribe(
+		func() { c.mempool = &includeBytes },
+		func(cli *WebSocketClient) error { return cli.RegisterMempool(includeBytes) },
+	)
+}
+
+func (c *ReconnectingWebSocketClient) ListenMempool(ctx context.Context) (ids.ID, []byte, error) {
+	var (
+		txID  ids.ID
+		bytes []byte
+	)
+	err := c.listen(ctx, func(cli *WebSocketClient) error {
+		var err error
+		txID, bytes, err = cli.ListenMempool(ctx)
+		return err
+	})
+	return txID, bytes, err
+}
+
+// Reconnects returns how many times [c] reconnected to the server.
+func (c *ReconnectingWebSocketClient) Reconnects() int {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	return c.reconnects
+}
+
+// Close closes the current connection and stops [c] from reconnecting.
+func (c *ReconnectingWebSocketClient) Close() error {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	return c.current().Close()
+}
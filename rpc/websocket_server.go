@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/chain"
@@ -22,22 +23,48 @@ type WebSocketServer struct {
 	logger logging.Logger
 	s      *pubsub.Server
 
+	blockL         sync.Mutex
 	blockListeners *pubsub.Connections
+	blockReplay    *replayBuffer
+
+	decisionListeners *pubsub.Connections
+	decisionReplay    *replayBuffer
 
 	txL         sync.Mutex
 	txListeners map[ids.ID]*pubsub.Connections
-	expiringTxs *emap.EMap[*chain.Transaction] // ensures all tx listeners are eventually responded to
+	txReplay    *replayBuffer
+	// ensure all tx listeners are eventually responded to
+	expiringTxs     *emap.EMap[*chain.Transaction]
+	expiringHeights *emap.EMap[*chain.Transaction]
+
+	mempoolEnabled        bool
+	mempoolL              sync.Mutex
+	mempoolListeners      *pubsub.Connections // only receive IDs
+	mempoolBytesListeners *pubsub.Connections
+	mempoolSeq            *replayBuffer // nothing is retained for resumption
 }
 
-func NewWebSocketServer(vm VM, maxPendingMessages int) (*WebSocketServer, *pubsub.Server) {
+// NewWebSocketServer creates a new streaming server. The last [replaySize]
+// messages published on each stream are retained so that clients can resume
+// after a disconnect.
+func NewWebSocketServer(vm VM, cfg *pubsub.ServerConfig, replaySize int) (*WebSocketServer, *pubsub.Server) {
 	w := &WebSocketServer{
 		logger:         vm.Logger(),
 		blockListeners: pubsub.NewConnections(),
+		blockReplay:    newReplayBuffer(BlockMode, replaySize),
 		txListeners:    map[ids.ID]*pubsub.Connections{},
-		expiringTxs:    emap.NewEMap[*chain.Transaction](),
+		txReplay:       newReplayBuffer(TxMode, replaySize),
+		expiringTxs:    emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{Expiry: chain.ExpiryTimestamp}),
+
+		mempoolEnabled:        vm.GetStreamingMempool(),
+		mempoolListeners:      pubsub.NewConnections(),
+		mempoolBytesListeners: pubsub.NewConnections(),
+		mempoolSeq:            newReplayBuffer(MempoolMode, 0),
+
+		decisionListeners: pubsub.NewConnections(),
+		decisionReplay:    newReplayBuffer(BlockDecisionMode, replaySize),
 	}
-	cfg := pubsub.NewDefaultServerConfig()
-	cfg.MaxPendingMessages = maxPendingMessages
+	w.expiringHeights = emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{Expiry: chain.ExpiryHeight})
 	w.s = pubsub.New(w.logger, cfg, w.MessageCallback(vm))
 	return w, w.s
 }
@@ -55,6 +82,7 @@ func (w *WebSocketServer) AddTxListener(tx *chain.Transaction, c *pubsub.Connect
 	}
 	w.txListeners[txID].Add(c)
 	w.expiringTxs.Add([]*chain.Transaction{tx})
+	w.expiringHeights.Add([]*chain.Transaction{tx})
 }
 
 // If never possible for a tx to enter mempool, call this
@@ -74,17 +102,20 @@ func (w *WebSocketServer) removeTx(txID ids.ID, err error) error {
 	if err != nil {
 		return err
 	}
-	w.s.Publish(append([]byte{TxMode}, bytes...), listeners)
+	w.s.Publish(w.txReplay.add(txID, bytes), listeners)
 	delete(w.txListeners, txID)
 	// [expiringTxs] will be cleared eventually (does not support removal)
 	return nil
 }
 
-func (w *WebSocketServer) SetMinTx(t int64) error {
+// SetMinTx notifies the listeners of all transactions that expired before
+// timestamp [t] or height [height].
+func (w *WebSocketServer) SetMinTx(t int64, height uint64) error {
 	w.txL.Lock()
 	defer w.txL.Unlock()
 
 	expired := w.expiringTxs.SetMin(t)
+	expired = append(expired, w.expiringHeights.SetMin(int64(height))...)
 	for _, id := range expired {
 		if err := w.removeTx(id, ErrExpired); err != nil {
 			return err
@@ -96,17 +127,97 @@ func (w *WebSocketServer) SetMinTx(t int64) error {
 	return nil
 }
 
+// AddMempoolTxs notifies mempool listeners that [txs] were added to the
+// mempool. This is a no-op if the mempool stream is disabled.
+func (w *WebSocketServer) AddMempoolTxs(txs []*chain.Transaction) error {
+	if !w.mempoolEnabled || len(txs) == 0 {
+		return nil
+	}
+
+	w.mempoolL.Lock()
+	defer w.mempoolL.Unlock()
+
+	for _, listeners := range []*pubsub.Connections{w.mempoolListeners, w.mempoolBytesListeners} {
+		if listeners.Len() == 0 {
+			continue
+		}
+		withBytes := listeners == w.mempoolBytesListeners
+		for _, tx := range txs {
+			var txBytes []byte
+			if withBytes {
+				txBytes = tx.Bytes()
+			}
+			bytes, err := PackMempoolTxMessage(tx.ID(), txBytes)
+			if err != nil {
+				return err
+			}
+			inactiveConnection := w.s.Publish(w.mempoolSeq.add(ids.Empty, bytes), listeners)
+			for _, conn := range inactiveConnection {
+				listeners.Remove(conn)
+			}
+		}
+	}
+	return nil
+}
+
+// publishDecision sends [bytes] to all block decision listeners. The
+// caller must hold [blockL].
+func (w *WebSocketServer) publishDecision(bytes []byte) {
+	msg := w.decisionReplay.add(ids.Empty, bytes)
+	if w.decisionListeners.Len() == 0 {
+		return
+	}
+	inactiveConnection := w.s.Publish(msg, w.decisionListeners)
+	for _, conn := range inactiveConnection {
+		w.decisionListeners.Remove(conn)
+	}
+}
+
+// VerifyBlock notifies block decision listeners that [b] was verified and is
+// now processing. [b] must have been executed.
+func (w *WebSocketServer) VerifyBlock(b *chain.StatelessBlock) error {
+	bytes, err := PackBlockDecisionMessage(BlockVerified, b)
+	if err != nil {
+		return err
+	}
+	w.blockL.Lock()
+	defer w.blockL.Unlock()
+
+	w.publishDecision(bytes)
+	return nil
+}
+
+// RejectBlock notifies block decision listeners that [b] was rejected
+// because of [reason].
+func (w *WebSocketServer) RejectBlock(b *chain.StatelessBlock, reason string) error {
+	bytes, err := PackRejectedBlockMessage(b.ID(), b.Hght, reason)
+	if err != nil {
+		return err
+	}
+	w.blockL.Lock()
+	defer w.blockL.Unlock()
+
+	w.publishDecision(bytes)
+	return nil
+}
+
 func (w *WebSocketServer) AcceptBlock(b *chain.StatelessBlock) error {
+	// We pack the block even if there are no listeners so that it can be
+	// replayed to clients that are reconnecting.
+	bytes, err := PackBlockMessage(b)
+	if err != nil {
+		return err
+	}
+	w.blockL.Lock()
+	msg := w.blockReplay.add(ids.Empty, bytes)
 	if w.blockListeners.Len() > 0 {
-		bytes, err := PackBlockMessage(b)
-		if err != nil {
-			return err
-		}
-		inactiveConnection := w.s.Publish(append([]byte{BlockMode}, bytes...), w.blockListeners)
+		inactiveConnection := w.s.Publish(msg, w.blockListeners)
 		for _, conn := range inactiveConnection {
 			w.blockListeners.Remove(conn)
 		}
 	}
+	w.publishDecision(append([]byte{byte(BlockAccepted)}, bytes...))
+	w.blockL.Unlock()
 
 	w.txL.Lock()
 	defer w.txL.Unlock()
@@ -122,13 +233,102 @@ func (w *WebSocketServer) AcceptBlock(b *chain.StatelessBlock) error {
 		if err != nil {
 			return err
 		}
-		w.s.Publish(append([]byte{TxMode}, bytes...), listeners)
+		w.s.Publish(w.txReplay.add(txID, bytes), listeners)
 		delete(w.txListeners, txID)
 		// [expiringTxs] will be cleared eventually (does not support removal)
 	}
 	return nil
 }
 
+// Resume replays all retained messages on stream [mode] with a sequence
+// number of at least [resumeFrom] to [c] and then subscribes [c] to new
+// messages. If some of these messages are no longer retained, [c] is first
+// sent a reply packed with [PackGapMessage].
+//
+// When resuming [TxMode], only decisions for [txIDs] are replayed and [c]
+// is re-attached to any of [txIDs] that are still pending. [c] is sent a
+// removed tx reply for any other of [txIDs], with [ErrResumeGap] if its
+// decision may no longer be retained and [ErrTxNotFound] otherwise.
+func (w *WebSocketServer) Resume(
+	c *pubsub.Connection,
+	mode byte,
+	resumeFrom uint64,
+	txIDs []ids.ID,
+) (int, error) {
+	switch mode {
+	case BlockMode:
+		w.blockL.Lock()
+		defer w.blockL.Unlock()
+
+		replayed := replay(c, w.blockReplay, resumeFrom)
+		w.blockListeners.Add(c)
+		return replayed, nil
+	case BlockDecisionMode:
+		w.blockL.Lock()
+		defer w.blockL.Unlock()
+
+		replayed := replay(c, w.decisionReplay, resumeFrom)
+		w.decisionListeners.Add(c)
+		return replayed, nil
+	case TxMode:
+		w.txL.Lock()
+		defer w.txL.Unlock()
+
+		requested := set.Of(txIDs...)
+		replayed := 0
+		for _, e := range w.txReplay.since(resumeFrom) {
+			if !requested.Contains(e.txID) {
+				continue
+			}
+			requested.Remove(e.txID)
+			if c.Send(e.msg) {
+				replayed++
+			}
+		}
+		// Any remaining txs that still have listeners have not been decided
+		_, _, gap := w.txReplay.dropped(resumeFrom)
+		for txID := range requested {
+			if listeners, ok := w.txListeners[txID]; ok {
+				listeners.Add(c)
+				continue
+			}
+			// The tx was decided before [resumeFrom], its decision was dropped,
+			// or it was never registered on this server
+			reason := ErrTxNotFound
+			if gap {
+				reason = ErrResumeGap
+			}
+			bytes, err := PackRemovedTxMessage(txID, reason)
+			if err != nil {
+				return replayed, err
+			}
+			c.Send(w.txReplay.reply(bytes))
+		}
+		return replayed, nil
+	default:
+		return 0, ErrUnknownStream
+	}
+}
+
+// replay sends [c] all messages of [r] since [resumeFrom] (after a gap reply
+// if some of them are no longer retained) and returns how many were sent.
+func replay(c *pubsub.Connection, r *replayBuffer, resumeFrom uint64) int {
+	if from, to, ok := r.dropped(resumeFrom); ok {
+		if !c.Send(r.reply(PackGapMessage(from, to))) {
+			return 0
+		}
+	}
+	replayed := 0
+	for _, e := range r.since(resumeFrom) {
+		if !c.Send(e.msg) {
+			// The client will observe a gap in sequence numbers
+			break
+		}
+		replayed++
+	}
+	return replayed
+}
+
 func (w *WebSocketServer) MessageCallback(vm VM) pubsub.Callback {
 	// Assumes controller is initialized before this is called
 	var (
@@ -153,8 +353,50 @@ func (w *WebSocketServer) MessageCallback(vm VM) pubsub.Callback {
 		// implementations
 		switch msgBytes[0] {
 		case BlockMode:
+			w.blockL.Lock()
 			w.blockListeners.Add(c)
+			w.blockL.Unlock()
 			log.Debug("added block listener")
+		case BlockDecisionMode:
+			w.blockL.Lock()
+			w.decisionListeners.Add(c)
+			w.blockL.Unlock()
+			log.Debug("added block decision listener")
+		case MempoolMode:
+			if !w.mempoolEnabled {
+				log.Debug("ignoring mempool listener (stream disabled)")
+				return
+			}
+			w.mempoolL.Lock()
+			if len(msgBytes) > 1 && msgBytes[1] == 1 {
+				w.mempoolBytesListeners.Add(c)
+			} else {
+				w.mempoolListeners.Add(c)
+			}
+			w.mempoolL.Unlock()
+			log.Debug("added mempool listener")
+		case ResumeMode:
+			mode, resumeFrom, txIDs, err := UnpackResumeMessage(msgBytes[1:])
+			if err != nil {
+				log.Error("failed to unmarshal resume request",
+					zap.Int("len", len(msgBytes)),
+					zap.Error(err),
+				)
+				return
+			}
+			replayed, err := w.Resume(c, mode, resumeFrom, txIDs)
+			if err != nil {
+				log.Error("failed to resume stream",
+					zap.Uint8("mode", mode),
+					zap.Error(err),
+				)
+				return
+			}
+			log.Debug("resumed stream",
+				zap.Uint8("mode", mode),
+				zap.Uint64("resumeFrom", resumeFrom),
+				zap.Int("replayed", replayed),
+			)
 		case TxMode:
 			msgBytes = msgBytes[1:]
 			// Unmarshal TX
@@ -0,0 +1,107 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/pubsub"
+)
+
+// newResumeServer returns a [WebSocketServer] that only handles resume
+// requests (and retains the last [replaySize] messages of each stream) and a
+// client connected to it.
+func newResumeServer(t *testing.T, replaySize int) (*WebSocketServer, *WebSocketClient) {
+	w := &WebSocketServer{
+		logger:            logging.NoLog{},
+		blockListeners:    pubsub.NewConnections(),
+		blockReplay:       newReplayBuffer(BlockMode, replaySize),
+		decisionListeners: pubsub.NewConnections(),
+		decisionReplay:    newReplayBuffer(BlockDecisionMode, replaySize),
+		txListeners:       map[ids.ID]*pubsub.Connections{},
+		txReplay:          newReplayBuffer(TxMode, replaySize),
+	}
+	w.s = pubsub.New(w.logger, pubsub.NewDefaultServerConfig(), func(msg []byte, c *pubsub.Connection) {
+		mode, resumeFrom, txIDs, err := UnpackResumeMessage(msg[1:])
+		if err != nil {
+			return
+		}
+		_, _ = w.Resume(c, mode, resumeFrom, txIDs)
+	})
+	server := httptest.NewServer(w.s)
+	t.Cleanup(server.Close)
+
+	cli, err := NewWebSocketClient(server.URL, DefaultHandshakeTimeout, pubsub.MaxPendingMessages, pubsub.MaxReadMessageSize)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cli.Close() })
+	return w, cli
+}
+
+func TestResumeTxNotFound(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	w, cli := newResumeServer(t, 2)
+	decided := ids.GenerateTestID()
+	bytes, err := PackRemovedTxMessage(decided, ErrExpired)
+	require.NoError(err)
+	w.txReplay.add(decided, bytes)
+
+	// Txs without a listener or a retained decision are reported as not
+	// found (without moving the cursor)
+	unknown := ids.GenerateTestID()
+	require.NoError(cli.ResumeTxs(1, []ids.ID{decided, unknown}))
+	txID, txErr, _, err := cli.ListenTx(ctx)
+	require.NoError(err)
+	require.Equal(decided, txID)
+	require.EqualError(txErr, ErrExpired.Error())
+	txID, txErr, _, err = cli.ListenTx(ctx)
+	require.NoError(err)
+	require.Equal(unknown, txID)
+	require.EqualError(txErr, ErrTxNotFound.Error())
+	require.Equal(uint64(1), cli.TxCursor())
+}
+
+func TestResumeGap(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	w, cli := newResumeServer(t, 1)
+	dropped := ids.GenerateTestID()
+	retained := ids.GenerateTestID()
+	for _, txID := range []ids.ID{dropped, retained} {
+		bytes, err := PackRemovedTxMessage(txID, ErrExpired)
+		require.NoError(err)
+		w.txReplay.add(txID, bytes)
+	}
+	w.blockReplay.add(ids.Empty, []byte{0})
+	w.blockReplay.add(ids.Empty, []byte{1})
+
+	// Txs whose decision may have been dropped are reported with a gap
+	require.NoError(cli.ResumeTxs(1, []ids.ID{dropped, retained}))
+	txID, txErr, _, err := cli.ListenTx(ctx)
+	require.NoError(err)
+	require.Equal(retained, txID)
+	require.EqualError(txErr, ErrExpired.Error())
+	txID, txErr, _, err = cli.ListenTx(ctx)
+	require.NoError(err)
+	require.Equal(dropped, txID)
+	require.EqualError(txErr, ErrResumeGap.Error())
+	require.Equal(uint64(2), cli.TxCursor())
+
+	// Dropped blocks are reported before the retained ones
+	require.NoError(cli.ResumeBlocks(1))
+	_, _, _, err = cli.ListenBlock(ctx, nil)
+	require.ErrorIs(err, ErrResumeGap)
+	require.Zero(cli.BlockCursor())
+}
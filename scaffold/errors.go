@@ -0,0 +1,17 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import "errors"
+
+var (
+	ErrInvalidName     = errors.New("invalid name")
+	ErrInvalidModule   = errors.New("invalid module")
+	ErrInvalidHRP      = errors.New("invalid HRP")
+	ErrInvalidSymbol   = errors.New("invalid symbol")
+	ErrInvalidDecimals = errors.New("invalid decimals")
+	ErrMissingVersion  = errors.New("missing hypersdk version")
+	ErrDirNotEmpty     = errors.New("directory is not empty")
+	ErrInvalidTemplate = errors.New("invalid template")
+)
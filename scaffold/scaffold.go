@@ -0,0 +1,231 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package scaffold generates the skeleton of a new hypersdk VM (modeled after
+// morpheusvm) so that new VMs don't need to be copied from the examples by
+// hand.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	templatesDir = "templates"
+	templateExt  = ".tmpl"
+
+	// nameDir is replaced with [Config.Name] in template paths.
+	nameDir = "__name__"
+
+	// MaxDecimals is the largest number of decimals that still allows a
+	// balance of a few billion whole tokens to fit in a uint64.
+	MaxDecimals = 9
+
+	// MaxSymbolLen is the longest allowed token symbol.
+	MaxSymbolLen = 8
+
+	// ed25519ID is the type ID of ED25519 auth in generated VMs.
+	ed25519ID uint8 = 0
+
+	// DefaultHypersdkVersion is required by generated VMs if the version of
+	// the hypersdk this binary was built with can't be determined.
+	DefaultHypersdkVersion = "v0.0.1"
+
+	hypersdkModule = "github.com/ava-labs/hypersdk"
+
+	// demoPrivateKey is funded at genesis by the generated scripts and used
+	// by the generated e2e tests (it is the same key used by the examples).
+	demoPrivateKey = "323b1d8f4eed5f0da9da93071b034f2dce9d2d22692c172f3cb252a64ddfafd01b057de320297c29ad0c1f589ea216869cf1938d88c9fbd70d6748323dbf2fa7" //nolint:lll
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+var (
+	nameRegexp   = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	hrpRegexp    = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	symbolRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]*$`)
+)
+
+// Config parameterizes a generated VM.
+type Config struct {
+	// Name of the VM (also used to derive its ID and as the name of its
+	// binary).
+	Name string
+	// Module is the Go module path of the VM.
+	Module string
+
+	// HRP is the human-readable part of bech32 addresses.
+	HRP string
+	// Symbol and Decimals describe the native token.
+	Symbol   string
+	Decimals uint8
+
+	// HypersdkVersion is the version of the hypersdk required by the VM.
+	HypersdkVersion string
+	// HypersdkReplace (if set) replaces the hypersdk with a local checkout.
+	HypersdkReplace string
+}
+
+// Verify ensures [c] can generate a VM that compiles.
+func (c *Config) Verify() error {
+	if !nameRegexp.MatchString(c.Name) || len(c.Name) > consts.IDLen {
+		return fmt.Errorf("%w: %q (must be at most %d lowercase letters and digits)", ErrInvalidName, c.Name, consts.IDLen)
+	}
+	if len(c.Module) == 0 || strings.ContainsAny(c.Module, " \t\n\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidModule, c.Module)
+	}
+	if !hrpRegexp.MatchString(c.HRP) {
+		return fmt.Errorf("%w: %q (must be lowercase letters and digits)", ErrInvalidHRP, c.HRP)
+	}
+	if !symbolRegexp.MatchString(c.Symbol) || len(c.Symbol) > MaxSymbolLen {
+		return fmt.Errorf("%w: %q (must be at most %d uppercase letters and digits)", ErrInvalidSymbol, c.Symbol, MaxSymbolLen)
+	}
+	if c.Decimals > MaxDecimals {
+		return fmt.Errorf("%w: %d > %d", ErrInvalidDecimals, c.Decimals, MaxDecimals)
+	}
+	if len(c.HypersdkVersion) == 0 {
+		return ErrMissingVersion
+	}
+	return nil
+}
+
+// VMID is the ID of the VM (derived from [Name]). AvalancheGo expects the
+// VM's binary to be named after it.
+func (c *Config) VMID() (string, error) {
+	b := make([]byte, consts.IDLen)
+	copy(b, []byte(c.Name))
+	id, err := ids.ToID(b)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// DemoPrivateKey is the hex-encoded ED25519 private key funded at genesis by
+// the generated scripts.
+func (*Config) DemoPrivateKey() string {
+	return demoPrivateKey
+}
+
+// DemoAddress is the address of [DemoPrivateKey] (the generated VM derives
+// ED25519 addresses like the examples do).
+func (c *Config) DemoAddress() (string, error) {
+	b, err := codec.LoadHex(demoPrivateKey, ed25519.PrivateKeyLen)
+	if err != nil {
+		return "", err
+	}
+	pk := ed25519.PrivateKey(b).PublicKey()
+	return codec.AddressBech32(c.HRP, codec.CreateAddress(ed25519ID, utils.ToID(pk[:])))
+}
+
+// HypersdkVersion returns the version of the hypersdk this binary was built
+// with (or [DefaultHypersdkVersion] if it was built from a local checkout).
+func HypersdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return DefaultHypersdkVersion
+	}
+	if info.Main.Path == hypersdkModule && strings.HasPrefix(info.Main.Version, "v") {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == hypersdkModule && strings.HasPrefix(dep.Version, "v") {
+			return dep.Version
+		}
+	}
+	return DefaultHypersdkVersion
+}
+
+// Generate writes a new VM configured by [c] to [dir]. [dir] must either not
+// exist or be empty (we never overwrite existing files). It returns the paths
+// of all generated files (relative to [dir]).
+func Generate(c *Config, dir string) ([]string, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case len(entries) > 0:
+		return nil, fmt.Errorf("%w: %s", ErrDirNotEmpty, dir)
+	}
+
+	// Render all files before writing any, so that a broken template doesn't
+	// leave behind a partial VM.
+	files := map[string][]byte{}
+	paths := []string{}
+	if err := fs.WalkDir(templates, templatesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel := strings.TrimPrefix(path, templatesDir+"/")
+		rel = strings.TrimSuffix(strings.ReplaceAll(rel, nameDir, c.Name), templateExt)
+		b, err := render(c, path)
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(rel) == ".go" {
+			b, err = format.Source(b)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, path, err)
+			}
+		}
+		files[rel] = b
+		paths = append(paths, rel)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, rel := range paths {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		perm := os.FileMode(0o644)
+		if filepath.Ext(rel) == ".sh" {
+			perm = 0o755
+		}
+		if err := os.WriteFile(path, files[rel], perm); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func render(c *Config, path string) ([]byte, error) {
+	raw, err := templates.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Go code uses "{{" for colored output and scripts use "[[" for tests, so
+	// we use different delimiters.
+	t, err := template.New(path).Delims("{%", "%}").Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, path, err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, c); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, path, err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() *Config {
+	return &Config{
+		Name:            "examplevm",
+		Module:          "github.com/example/examplevm",
+		HRP:             "example",
+		Symbol:          "EX",
+		Decimals:        6,
+		HypersdkVersion: DefaultHypersdkVersion,
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	dir := filepath.Join(t.TempDir(), "examplevm")
+	files, err := Generate(testConfig(), dir)
+	require.NoError(err)
+	for _, file := range []string{
+		"go.mod",
+		"actions/transfer.go",
+		"controller/controller.go",
+		"genesis/genesis.go",
+		"registry/registry.go",
+		"rpc/jsonrpc_client.go",
+		"cmd/examplevm/main.go",
+		"tests/e2e/e2e_test.go",
+		"scripts/run.sh",
+	} {
+		require.Contains(files, file)
+	}
+
+	// Templates are parameterized
+	b, err := os.ReadFile(filepath.Join(dir, "consts", "consts.go"))
+	require.NoError(err)
+	require.Contains(string(b), `HRP      = "example"`)
+	require.Contains(string(b), `Symbol   = "EX"`)
+	require.Contains(string(b), `Decimals = 6`)
+	b, err = os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(err)
+	require.True(strings.HasPrefix(string(b), "module github.com/example/examplevm\n"))
+	require.NotContains(string(b), "replace")
+
+	// Scripts are executable
+	info, err := os.Stat(filepath.Join(dir, "scripts", "run.sh"))
+	require.NoError(err)
+	require.Equal(os.FileMode(0o755), info.Mode().Perm())
+
+	// Nothing references the examples
+	for _, file := range files {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(err)
+		require.NotContains(string(b), "morpheus", file)
+	}
+
+	// Existing VMs are never overwritten
+	_, err = Generate(testConfig(), dir)
+	require.ErrorIs(err, ErrDirNotEmpty)
+}
+
+func TestGenerateReplace(t *testing.T) {
+	require := require.New(t)
+
+	c := testConfig()
+	c.HypersdkReplace = "../hypersdk"
+	dir := t.TempDir()
+	_, err := Generate(c, dir)
+	require.NoError(err)
+	b, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(err)
+	require.Contains(string(b), "replace github.com/ava-labs/hypersdk => ../hypersdk\n")
+}
+
+// TestGenerateBuild ensures the generated VM compiles against this checkout
+// of the hypersdk.
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build of the generated VM in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not found")
+	}
+	require := require.New(t)
+
+	root, err := filepath.Abs("..")
+	require.NoError(err)
+	c := testConfig()
+	c.HypersdkReplace = root
+	dir := t.TempDir()
+	_, err = Generate(c, dir)
+	require.NoError(err)
+
+	// The generated VM only depends on modules the hypersdk already does, so
+	// its checksums can be reused.
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0o644))
+
+	cmd := exec.Command("go", "build", "-mod=mod", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	require.NoError(err, string(out))
+}
+
+func TestDemoAddress(t *testing.T) {
+	require := require.New(t)
+
+	addr, err := testConfig().DemoAddress()
+	require.NoError(err)
+	_, err = codec.ParseAddressBech32("example", addr)
+	require.NoError(err)
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    error
+	}{
+		{"valid", func(*Config) {}, nil},
+		{"uppercase name", func(c *Config) { c.Name = "ExampleVM" }, ErrInvalidName},
+		{"long name", func(c *Config) { c.Name = strings.Repeat("a", 33) }, ErrInvalidName},
+		{"empty module", func(c *Config) { c.Module = "" }, ErrInvalidModule},
+		{"invalid hrp", func(c *Config) { c.HRP = "ex-ample" }, ErrInvalidHRP},
+		{"lowercase symbol", func(c *Config) { c.Symbol = "ex" }, ErrInvalidSymbol},
+		{"long symbol", func(c *Config) { c.Symbol = "EXAMPLEVM" }, ErrInvalidSymbol},
+		{"decimals", func(c *Config) { c.Decimals = MaxDecimals + 1 }, ErrInvalidDecimals},
+		{"version", func(c *Config) { c.HypersdkVersion = "" }, ErrMissingVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig()
+			tt.modify(c)
+			require.ErrorIs(t, c.Verify(), tt.err)
+		})
+	}
+}
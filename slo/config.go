@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slo
+
+import "time"
+
+const (
+	BlockBuild = "block_build"
+	RPC        = "rpc"
+)
+
+// Objective describes the availability and latency goals of a single
+// service level indicator (like block production or RPC).
+type Objective struct {
+	Name string `json:"name"`
+
+	// AvailabilityTarget is the fraction of events that must succeed
+	// (e.g. 0.999).
+	AvailabilityTarget float64 `json:"availabilityTarget"`
+
+	// LatencyTarget is the fraction of events that must complete within
+	// [LatencyThreshold].
+	LatencyThreshold time.Duration `json:"latencyThreshold"`
+	LatencyTarget    float64       `json:"latencyTarget"`
+}
+
+// BurnRateAlert fires when the error budget of an objective is consumed at
+// least [Threshold] times faster than sustainable over both [ShortWindow]
+// and [LongWindow].
+//
+// Requiring both windows to burn avoids paging on short spikes while still
+// resetting quickly once the issue is resolved.
+type BurnRateAlert struct {
+	Name        string        `json:"name"`
+	ShortWindow time.Duration `json:"shortWindow"`
+	LongWindow  time.Duration `json:"longWindow"`
+	Threshold   float64       `json:"threshold"`
+}
+
+type Config struct {
+	// Window is the period over which compliance and remaining error budget
+	// are computed. Because all data is held in memory, this should not be
+	// set to more than a few hours.
+	Window     time.Duration `json:"window"`
+	BucketSize time.Duration `json:"bucketSize"`
+
+	Objectives []*Objective     `json:"objectives"`
+	Alerts     []*BurnRateAlert `json:"alerts"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Window:     6 * time.Hour,
+		BucketSize: time.Minute,
+		Objectives: []*Objective{
+			{
+				Name:               BlockBuild,
+				AvailabilityTarget: 0.99,
+				LatencyThreshold:   500 * time.Millisecond,
+				LatencyTarget:      0.99,
+			},
+			{
+				Name:               RPC,
+				AvailabilityTarget: 0.999,
+				LatencyThreshold:   250 * time.Millisecond,
+				LatencyTarget:      0.99,
+			},
+		},
+		Alerts: []*BurnRateAlert{
+			{
+				Name:        "page",
+				ShortWindow: 5 * time.Minute,
+				LongWindow:  time.Hour,
+				Threshold:   14.4,
+			},
+			{
+				Name:        "ticket",
+				ShortWindow: 30 * time.Minute,
+				LongWindow:  6 * time.Hour,
+				Threshold:   6,
+			},
+		},
+	}
+}
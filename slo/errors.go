@@ -0,0 +1,14 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slo
+
+import "errors"
+
+var (
+	ErrInvalidBucketSize = errors.New("invalid bucket size")
+	ErrWindowTooSmall    = errors.New("window smaller than bucket size")
+	ErrAlertWindow       = errors.New("alert window exceeds tracked window")
+	ErrInvalidTarget     = errors.New("target must be in (0, 1)")
+	ErrDuplicateName     = errors.New("duplicate objective name")
+)
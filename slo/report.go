@@ -0,0 +1,43 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slo
+
+import "time"
+
+const (
+	Availability = "availability"
+	Latency      = "latency"
+)
+
+type ObjectiveReport struct {
+	Name   string `json:"name"`
+	Total  uint64 `json:"total"`
+	Failed uint64 `json:"failed"`
+	Slow   uint64 `json:"slow"`
+
+	Availability                float64 `json:"availability"`
+	AvailabilityTarget          float64 `json:"availabilityTarget"`
+	AvailabilityBudgetRemaining float64 `json:"availabilityBudgetRemaining"`
+
+	LatencyThreshold       time.Duration `json:"latencyThreshold"`
+	LatencyCompliance      float64       `json:"latencyCompliance"`
+	LatencyTarget          float64       `json:"latencyTarget"`
+	LatencyBudgetRemaining float64       `json:"latencyBudgetRemaining"`
+}
+
+type Alert struct {
+	Name          string  `json:"name"`
+	Objective     string  `json:"objective"`
+	Indicator     string  `json:"indicator"`
+	ShortBurnRate float64 `json:"shortBurnRate"`
+	LongBurnRate  float64 `json:"longBurnRate"`
+	Threshold     float64 `json:"threshold"`
+}
+
+type Report struct {
+	Timestamp  int64              `json:"timestamp"`
+	Window     time.Duration      `json:"window"`
+	Objectives []*ObjectiveReport `json:"objectives"`
+	Alerts     []*Alert           `json:"alerts"`
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	index  int64
+	total  uint64
+	failed uint64
+	slow   uint64
+}
+
+type series struct {
+	objective *Objective
+	buckets   []bucket
+}
+
+// Tracker aggregates events into fixed-size time buckets so that
+// compliance can be computed over any window up to [Config.Window].
+type Tracker struct {
+	config *Config
+	now    func() time.Time
+
+	l      sync.Mutex
+	series map[string]*series
+}
+
+func New(config *Config) (*Tracker, error) {
+	if config.BucketSize <= 0 {
+		return nil, ErrInvalidBucketSize
+	}
+	if config.Window < config.BucketSize {
+		return nil, ErrWindowTooSmall
+	}
+	for _, alert := range config.Alerts {
+		if alert.ShortWindow > config.Window || alert.LongWindow > config.Window {
+			return nil, fmt.Errorf("%w: %s", ErrAlertWindow, alert.Name)
+		}
+	}
+	count := int(config.Window / config.BucketSize)
+	t := &Tracker{
+		config: config,
+		now:    time.Now,
+		series: make(map[string]*series, len(config.Objectives)),
+	}
+	for _, objective := range config.Objectives {
+		if !validTarget(objective.AvailabilityTarget) || !validTarget(objective.LatencyTarget) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, objective.Name)
+		}
+		if _, ok := t.series[objective.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateName, objective.Name)
+		}
+		t.series[objective.Name] = &series{
+			objective: objective,
+			buckets:   make([]bucket, count),
+		}
+	}
+	return t, nil
+}
+
+func validTarget(target float64) bool {
+	return target > 0 && target < 1
+}
+
+// Record adds an event to the objective [name]. Events for unknown
+// objectives are ignored.
+func (t *Tracker) Record(name string, success bool, latency time.Duration) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	s, ok := t.series[name]
+	if !ok {
+		return
+	}
+	index := t.now().UnixNano() / int64(t.config.BucketSize)
+	b := &s.buckets[index%int64(len(s.buckets))]
+	if b.index != index {
+		*b = bucket{index: index}
+	}
+	b.total++
+	if !success {
+		b.failed++
+	}
+	if latency > s.objective.LatencyThreshold {
+		b.slow++
+	}
+}
+
+// sum returns the totals of all buckets that fall within [window] of
+// [current].
+func (t *Tracker) sum(s *series, current int64, window time.Duration) bucket {
+	span := int64(window / t.config.BucketSize)
+	if span < 1 {
+		span = 1
+	}
+	var agg bucket
+	for _, b := range s.buckets {
+		if b.total == 0 || current-b.index >= span || b.index > current {
+			continue
+		}
+		agg.total += b.total
+		agg.failed += b.failed
+		agg.slow += b.slow
+	}
+	return agg
+}
+
+// Report computes compliance over the configured window and returns any
+// burn-rate alerts that are currently firing.
+func (t *Tracker) Report() *Report {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := t.now()
+	current := now.UnixNano() / int64(t.config.BucketSize)
+	r := &Report{
+		Timestamp:  now.UnixMilli(),
+		Window:     t.config.Window,
+		Objectives: make([]*ObjectiveReport, 0, len(t.config.Objectives)),
+		Alerts:     []*Alert{},
+	}
+	for _, objective := range t.config.Objectives {
+		s := t.series[objective.Name]
+		agg := t.sum(s, current, t.config.Window)
+		or := &ObjectiveReport{
+			Name:               objective.Name,
+			Total:              agg.total,
+			Failed:             agg.failed,
+			Slow:               agg.slow,
+			Availability:       compliance(agg.failed, agg.total),
+			AvailabilityTarget: objective.AvailabilityTarget,
+			LatencyThreshold:   objective.LatencyThreshold,
+			LatencyCompliance:  compliance(agg.slow, agg.total),
+			LatencyTarget:      objective.LatencyTarget,
+		}
+		or.AvailabilityBudgetRemaining = 1 - burnRate(agg.failed, agg.total, objective.AvailabilityTarget)
+		or.LatencyBudgetRemaining = 1 - burnRate(agg.slow, agg.total, objective.LatencyTarget)
+		r.Objectives = append(r.Objectives, or)
+
+		for _, alert := range t.config.Alerts {
+			short := t.sum(s, current, alert.ShortWindow)
+			long := t.sum(s, current, alert.LongWindow)
+			for _, indicator := range []struct {
+				name   string
+				target float64
+				short  uint64
+				long   uint64
+			}{
+				{Availability, objective.AvailabilityTarget, short.failed, long.failed},
+				{Latency, objective.LatencyTarget, short.slow, long.slow},
+			} {
+				shortBurn := burnRate(indicator.short, short.total, indicator.target)
+				longBurn := burnRate(indicator.long, long.total, indicator.target)
+				if shortBurn < alert.Threshold || longBurn < alert.Threshold {
+					continue
+				}
+				r.Alerts = append(r.Alerts, &Alert{
+					Name:          alert.Name,
+					Objective:     objective.Name,
+					Indicator:     indicator.name,
+					ShortBurnRate: shortBurn,
+					LongBurnRate:  longBurn,
+					Threshold:     alert.Threshold,
+				})
+			}
+		}
+	}
+	return r
+}
+
+// compliance returns the fraction of [total] events that were good. If there
+// were no events, we consider the objective to be met.
+func compliance(bad uint64, total uint64) float64 {
+	if total == 0 {
+		return 1
+	}
+	return 1 - float64(bad)/float64(total)
+}
+
+// burnRate returns how quickly the error budget of [target] is being
+// consumed. A burn rate of 1 exhausts the budget exactly at the end of
+// the window.
+func burnRate(bad uint64, total uint64, target float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - target)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerCompliance(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := New(DefaultConfig())
+	require.NoError(err)
+	now := time.Unix(1_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		tracker.Record(RPC, i%10 != 0, 10*time.Millisecond)
+	}
+	tracker.Record("unknown", false, time.Hour)
+
+	r := tracker.Report()
+	require.Len(r.Objectives, 2)
+	require.Equal(BlockBuild, r.Objectives[0].Name)
+	require.Zero(r.Objectives[0].Total)
+	require.InDelta(1, r.Objectives[0].Availability, 0.0001)
+
+	rpc := r.Objectives[1]
+	require.Equal(uint64(100), rpc.Total)
+	require.Equal(uint64(10), rpc.Failed)
+	require.Zero(rpc.Slow)
+	require.InDelta(0.9, rpc.Availability, 0.0001)
+	require.InDelta(1, rpc.LatencyCompliance, 0.0001)
+	require.Less(rpc.AvailabilityBudgetRemaining, float64(0))
+
+	// 10% errors against a 0.1% budget is a 100x burn in both windows
+	require.Len(r.Alerts, 2)
+	for _, alert := range r.Alerts {
+		require.Equal(RPC, alert.Objective)
+		require.Equal(Availability, alert.Indicator)
+		require.InDelta(100, alert.ShortBurnRate, 0.0001)
+	}
+}
+
+func TestTrackerExpiry(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := New(DefaultConfig())
+	require.NoError(err)
+	now := time.Unix(1_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		tracker.Record(BlockBuild, true, time.Second)
+	}
+	r := tracker.Report()
+	require.Equal(uint64(10), r.Objectives[0].Slow)
+	require.Len(r.Alerts, 2)
+
+	// Slow builds fall out of the short windows first, silencing alerts
+	now = now.Add(time.Hour)
+	tracker.Record(BlockBuild, true, time.Millisecond)
+	r = tracker.Report()
+	require.Equal(uint64(11), r.Objectives[0].Total)
+	require.Empty(r.Alerts)
+
+	// Everything falls out of the tracked window
+	now = now.Add(7 * time.Hour)
+	r = tracker.Report()
+	require.Zero(r.Objectives[0].Total)
+}
+
+func TestTrackerInvalidConfig(t *testing.T) {
+	require := require.New(t)
+
+	config := DefaultConfig()
+	config.Window = time.Second
+	_, err := New(config)
+	require.ErrorIs(err, ErrWindowTooSmall)
+
+	config = DefaultConfig()
+	config.Alerts[0].LongWindow = 24 * time.Hour
+	_, err = New(config)
+	require.ErrorIs(err, ErrAlertWindow)
+
+	config = DefaultConfig()
+	config.Objectives[0].AvailabilityTarget = 1
+	_, err = New(config)
+	require.ErrorIs(err, ErrInvalidTarget)
+
+	config = DefaultConfig()
+	config.Objectives[1].Name = BlockBuild
+	_, err = New(config)
+	require.ErrorIs(err, ErrDuplicateName)
+}
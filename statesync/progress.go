@@ -0,0 +1,42 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statesync tracks the progress of a MerkleDB state sync so that
+// operators can tell whether a bootstrapping node is making progress.
+package statesync
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Progress is a snapshot of an ongoing (or finished) state sync.
+type Progress struct {
+	// Started is true once the node has decided to state sync. It is false
+	// if the node is bootstrapping normally (or has not decided yet).
+	Started bool `json:"started"`
+	Done    bool `json:"done"`
+
+	TargetHeight uint64 `json:"targetHeight"`
+	TargetRoot   ids.ID `json:"targetRoot"`
+	// TargetUpdates is the number of times the target was moved to a more
+	// recent block while syncing.
+	TargetUpdates int `json:"targetUpdates"`
+
+	Requests     uint64 `json:"requests"`
+	KeysFetched  uint64 `json:"keysFetched"`
+	BytesFetched uint64 `json:"bytesFetched"`
+
+	// Completed is the estimated fraction of the key space that has been
+	// synced to [TargetRoot], in [0, 1].
+	//
+	// The estimate assumes keys are uniformly distributed, so it may move
+	// faster or slower than actual progress for heavily skewed state.
+	Completed float64       `json:"completed"`
+	Elapsed   time.Duration `json:"elapsed"`
+	// ETA is the estimated time remaining (0 if it cannot be estimated yet).
+	ETA time.Duration `json:"eta"`
+
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+	syncEng "github.com/ava-labs/avalanchego/x/sync"
+)
+
+// Tracker records the proofs fetched by a state sync to estimate how much
+// of the key space has been synced to the current target.
+type Tracker struct {
+	now func() time.Time
+
+	requests  prometheus.Counter
+	keys      prometheus.Counter
+	bytes     prometheus.Counter
+	completed prometheus.Gauge
+	eta       prometheus.Gauge
+
+	l           sync.Mutex
+	progress    Progress
+	start       time.Time
+	targetStart time.Time
+	end         time.Time
+}
+
+func New(r prometheus.Registerer) (*Tracker, error) {
+	t := &Tracker{
+		now: time.Now,
+		requests: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "statesync",
+			Name:      "requests",
+			Help:      "number of successful proof requests",
+		}),
+		keys: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "statesync",
+			Name:      "keys_fetched",
+			Help:      "number of keys fetched",
+		}),
+		bytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "statesync",
+			Name:      "bytes_fetched",
+			Help:      "number of key and value bytes fetched",
+		}),
+		completed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "statesync",
+			Name:      "completed",
+			Help:      "estimated fraction of the key space synced to the current target",
+		}),
+		eta: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "statesync",
+			Name:      "eta_seconds",
+			Help:      "estimated seconds until the sync completes",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		r.Register(t.requests),
+		r.Register(t.keys),
+		r.Register(t.bytes),
+		r.Register(t.completed),
+		r.Register(t.eta),
+	)
+	return t, errs.Err
+}
+
+// Start marks the beginning of a sync to [root] (the state root of the
+// block at [height]).
+func (t *Tracker) Start(height uint64, root ids.ID) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := t.now()
+	t.start = now
+	t.targetStart = now
+	t.progress.Started = true
+	t.progress.TargetHeight = height
+	t.progress.TargetRoot = root
+}
+
+// UpdateTarget records that the sync moved to a new [root]. Because the
+// syncer re-walks the key space for the new root, the completion estimate is
+// reset.
+func (t *Tracker) UpdateTarget(height uint64, root ids.ID) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	t.targetStart = t.now()
+	t.progress.TargetHeight = height
+	t.progress.TargetRoot = root
+	t.progress.TargetUpdates++
+	t.progress.Completed = 0
+	t.completed.Set(0)
+}
+
+// Finish marks the sync as done. [err] is nil if the sync succeeded.
+func (t *Tracker) Finish(err error) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	t.end = t.now()
+	t.progress.Done = true
+	if err != nil {
+		t.progress.Error = err.Error()
+		return
+	}
+	t.progress.Completed = 1
+	t.completed.Set(1)
+	t.eta.Set(0)
+}
+
+// Progress returns a snapshot of the sync.
+func (t *Tracker) Progress() *Progress {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	p := t.progress
+	if !p.Started {
+		return &p
+	}
+	now := t.now()
+	if p.Done {
+		now = t.end
+	}
+	p.Elapsed = now.Sub(t.start)
+	p.ETA = t.estimate(now)
+	return &p
+}
+
+// estimate assumes the rate of progress since the target was last updated
+// stays constant.
+//
+// Assumes [t.l] is held.
+func (t *Tracker) estimate(now time.Time) time.Duration {
+	completed := t.progress.Completed
+	if t.progress.Done || completed <= 0 || completed >= 1 {
+		return 0
+	}
+	elapsed := now.Sub(t.targetStart)
+	return time.Duration(float64(elapsed) * (1 - completed) / completed)
+}
+
+func (t *Tracker) record(keys int, bytes int, covered float64) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	t.progress.Requests++
+	t.progress.KeysFetched += uint64(keys)
+	t.progress.BytesFetched += uint64(bytes)
+	t.progress.Completed = math.Min(t.progress.Completed+covered, 1)
+	t.requests.Inc()
+	t.keys.Add(float64(keys))
+	t.bytes.Add(float64(bytes))
+	t.completed.Set(t.progress.Completed)
+	t.eta.Set(t.estimate(t.now()).Seconds())
+}
+
+// Client wraps [client] so that every proof it returns is recorded.
+func (t *Tracker) Client(client syncEng.Client) syncEng.Client {
+	return &trackedClient{Client: client, t: t}
+}
+
+var _ syncEng.Client = (*trackedClient)(nil)
+
+type trackedClient struct {
+	syncEng.Client
+
+	t *Tracker
+}
+
+func (c *trackedClient) GetRangeProof(
+	ctx context.Context,
+	request *pb.SyncGetRangeProofRequest,
+) (*merkledb.RangeProof, error) {
+	proof, err := c.Client.GetRangeProof(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	c.recordRangeProof(request.StartKey, request.EndKey, request.KeyLimit, proof)
+	return proof, nil
+}
+
+func (c *trackedClient) GetChangeProof(
+	ctx context.Context,
+	request *pb.SyncGetChangeProofRequest,
+	verificationDB syncEng.DB,
+) (*merkledb.ChangeOrRangeProof, error) {
+	proof, err := c.Client.GetChangeProof(ctx, request, verificationDB)
+	if err != nil {
+		return nil, err
+	}
+	if proof.RangeProof != nil {
+		c.recordRangeProof(request.StartKey, request.EndKey, request.KeyLimit, proof.RangeProof)
+		return proof, nil
+	}
+	var (
+		changes = proof.ChangeProof.KeyChanges
+		size    int
+		last    []byte
+	)
+	for _, change := range changes {
+		size += len(change.Key) + len(change.Value.Value())
+		last = change.Key
+	}
+	c.t.record(len(changes), size, covered(request.StartKey, request.EndKey, len(changes) < int(request.KeyLimit), last))
+	return proof, nil
+}
+
+func (c *trackedClient) recordRangeProof(start *pb.MaybeBytes, end *pb.MaybeBytes, limit uint32, proof *merkledb.RangeProof) {
+	var (
+		size int
+		last []byte
+	)
+	for _, kv := range proof.KeyValues {
+		size += len(kv.Key) + len(kv.Value)
+		last = kv.Key
+	}
+	c.t.record(len(proof.KeyValues), size, covered(start, end, len(proof.KeyValues) < int(limit), last))
+}
+
+// covered returns the fraction of the key space proven by a response to a
+// request for [start, end]. If the response was not truncated by the key
+// limit, the entire range has been synced. Otherwise, only the range up to
+// the last key returned has been synced.
+func covered(start *pb.MaybeBytes, end *pb.MaybeBytes, complete bool, last []byte) float64 {
+	from := 0.0
+	if start != nil && !start.IsNothing {
+		from = keyPosition(start.Value)
+	}
+	to := 1.0
+	switch {
+	case !complete:
+		to = keyPosition(last)
+	case end != nil && !end.IsNothing:
+		to = keyPosition(end.Value)
+	}
+	return math.Max(to-from, 0)
+}
+
+// keyPosition maps [k] to its position in the key space, in [0, 1), using its
+// first 8 bytes.
+func keyPosition(k []byte) float64 {
+	var b [8]byte
+	copy(b[:], k)
+	return float64(binary.BigEndian.Uint64(b[:])) / math.Exp2(64)
+}
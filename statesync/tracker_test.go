@@ -0,0 +1,106 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+func TestCovered(t *testing.T) {
+	require := require.New(t)
+
+	half := &pb.MaybeBytes{Value: []byte{0x80}}
+	quarter := []byte{0x40}
+
+	// Entire key space
+	require.InDelta(1, covered(nil, nil, true, nil), 0.0001)
+	require.InDelta(1, covered(&pb.MaybeBytes{IsNothing: true}, nil, true, nil), 0.0001)
+
+	// Bounded range
+	require.InDelta(0.5, covered(nil, half, true, nil), 0.0001)
+	require.InDelta(0.5, covered(half, nil, true, nil), 0.0001)
+
+	// Truncated by the key limit
+	require.InDelta(0.25, covered(nil, half, false, quarter), 0.0001)
+	require.Zero(covered(half, nil, false, quarter))
+}
+
+func TestTrackerProgress(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := New(prometheus.NewRegistry())
+	require.NoError(err)
+	now := time.Unix(1_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	require.False(tracker.Progress().Started)
+
+	root := ids.GenerateTestID()
+	tracker.Start(10, root)
+	now = now.Add(10 * time.Second)
+	tracker.record(100, 1_000, 0.25)
+	p := tracker.Progress()
+	require.True(p.Started)
+	require.False(p.Done)
+	require.Equal(uint64(10), p.TargetHeight)
+	require.Equal(root, p.TargetRoot)
+	require.Equal(uint64(1), p.Requests)
+	require.Equal(uint64(100), p.KeysFetched)
+	require.Equal(uint64(1_000), p.BytesFetched)
+	require.InDelta(0.25, p.Completed, 0.0001)
+	require.Equal(10*time.Second, p.Elapsed)
+	require.Equal(30*time.Second, p.ETA)
+
+	// Updating the target resets the estimate
+	newRoot := ids.GenerateTestID()
+	tracker.UpdateTarget(20, newRoot)
+	p = tracker.Progress()
+	require.Equal(uint64(20), p.TargetHeight)
+	require.Equal(newRoot, p.TargetRoot)
+	require.Equal(1, p.TargetUpdates)
+	require.Zero(p.Completed)
+	require.Zero(p.ETA)
+	now = now.Add(5 * time.Second)
+	tracker.record(100, 1_000, 0.5)
+	p = tracker.Progress()
+	require.Equal(uint64(200), p.KeysFetched)
+	require.Equal(15*time.Second, p.Elapsed)
+	require.Equal(5*time.Second, p.ETA)
+
+	// Completion is capped
+	tracker.record(0, 0, 0.75)
+	require.InDelta(1, tracker.Progress().Completed, 0.0001)
+
+	// Elapsed stops when the sync finishes
+	tracker.Finish(nil)
+	now = now.Add(time.Minute)
+	p = tracker.Progress()
+	require.True(p.Done)
+	require.Empty(p.Error)
+	require.Equal(15*time.Second, p.Elapsed)
+	require.Zero(p.ETA)
+}
+
+func TestTrackerFailed(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := New(prometheus.NewRegistry())
+	require.NoError(err)
+	tracker.Start(10, ids.GenerateTestID())
+	tracker.record(1, 1, 0.5)
+	tracker.Finish(errors.New("boom"))
+	p := tracker.Progress()
+	require.True(p.Done)
+	require.Equal("boom", p.Error)
+	require.InDelta(0.5, p.Completed, 0.0001)
+	require.Zero(p.ETA)
+}
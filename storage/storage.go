@@ -4,6 +4,8 @@
 package storage
 
 import (
+	"path"
+
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/corruptabledb"
@@ -56,3 +58,21 @@ func New(chainDataDir string, gatherer metrics.MultiGatherer) (database.Database
 	}
 	return corruptabledb.New(blockDB), corruptabledb.New(stateDB), corruptabledb.New(metaDB), nil
 }
+
+// Stats returns the [pebble.Stats] of [db] if it was created by [New].
+func Stats(db database.Database) (*pebble.Stats, bool) {
+	if c, ok := db.(*corruptabledb.Database); ok {
+		db = c.Database
+	}
+	p, ok := db.(*pebble.Database)
+	if !ok {
+		return nil, false
+	}
+	return p.Stats(), true
+}
+
+// BlockPath returns the path of the block database created by [New] in
+// [chainDataDir].
+func BlockPath(chainDataDir string) string {
+	return path.Join(chainDataDir, block)
+}
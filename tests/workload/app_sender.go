@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ common.AppSender = (*appSender)(nil)
+	_ common.AppSender = (*nodeSender)(nil)
+)
+
+// appSender delivers gossip from one instance to the next, in a round-robin
+// fashion. Requests and responses are delivered by [nodeSender] and all
+// other messages are dropped.
+type appSender struct {
+	l         sync.Mutex
+	next      int
+	instances []*Instance
+}
+
+func (app *appSender) SendAppGossip(ctx context.Context, appGossipBytes []byte) error {
+	app.l.Lock()
+	n := len(app.instances)
+	if n < 2 {
+		app.l.Unlock()
+		return nil
+	}
+	sender := app.instances[app.next].NodeID
+	app.next++
+	app.next %= n
+	recipient := app.instances[app.next]
+	app.l.Unlock()
+	return recipient.VM.AppGossip(ctx, sender, appGossipBytes)
+}
+
+func (*appSender) SendAppRequest(context.Context, set.Set[ids.NodeID], uint32, []byte) error {
+	return nil
+}
+
+func (*appSender) SendAppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+func (*appSender) SendAppGossipSpecific(context.Context, set.Set[ids.NodeID], []byte) error {
+	return nil
+}
+
+func (*appSender) SendCrossChainAppRequest(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+func (*appSender) SendCrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+func (app *appSender) instance(nodeID ids.NodeID) (*Instance, bool) {
+	app.l.Lock()
+	defer app.l.Unlock()
+
+	for _, inst := range app.instances {
+		if inst.NodeID == nodeID {
+			return inst, true
+		}
+	}
+	return nil, false
+}
+
+// nodeSender sends the messages of the instance with [nodeID]. Gossip is
+// delivered by [appSender] while requests and responses are delivered
+// directly to their recipients.
+type nodeSender struct {
+	*appSender
+	nodeID ids.NodeID
+}
+
+func (n *nodeSender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32, request []byte) error {
+	for nodeID := range nodeIDs {
+		recipient, ok := n.instance(nodeID)
+		if !ok {
+			continue
+		}
+		if err := recipient.VM.AppRequest(ctx, n.nodeID, requestID, time.Now().Add(time.Minute), request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nodeSender) SendAppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	recipient, ok := n.instance(nodeID)
+	if !ok {
+		return nil
+	}
+	return recipient.VM.AppResponse(ctx, n.nodeID, requestID, response)
+}
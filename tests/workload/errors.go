@@ -0,0 +1,15 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import "errors"
+
+var (
+	ErrTooFewNodes      = errors.New("network requires at least one node")
+	ErrMissingVM        = errors.New("missing vm constructor")
+	ErrMissingGenesis   = errors.New("missing genesis constructor")
+	ErrBlockNotAccepted = errors.New("block not accepted")
+	ErrTxNotIncluded    = errors.New("tx not included in block")
+	ErrTxFailed         = errors.New("tx failed on-chain")
+)
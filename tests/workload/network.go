@@ -0,0 +1,421 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package workload provides an in-process network of hypersdk VMs that can
+// be used to write end-to-end tests for custom VMs without running
+// avalanchego.
+package workload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+const defaultVMConfig = `{"testMode":true}`
+
+// Allocation is a balance assigned to [Address] at genesis.
+type Allocation struct {
+	Address codec.Address
+	Balance uint64
+}
+
+type Config struct {
+	// NumNodes is the number of VM instances to create.
+	NumNodes  int
+	NetworkID uint32
+
+	// NewVM returns a new, uninitialized VM (usually the controller's New
+	// function).
+	NewVM func() *vm.VM
+
+	// Genesis returns the genesis of the VM with [allocations] funded.
+	Genesis     func(allocations []*Allocation) ([]byte, error)
+	Allocations []*Allocation
+
+	// VMConfig is passed to each VM on initialization. If empty, the VM is
+	// started in test mode with its default configuration.
+	VMConfig []byte
+
+	// LogFactory is used to create the logger of each VM. If nil, logs are
+	// discarded.
+	LogFactory logging.Factory
+
+	// TrackValidators reports a current P-chain height to each VM, so that it
+	// can look up the validator set (to pick proposers and gather warp
+	// signatures). If false, looking up the current P-chain height fails.
+	TrackValidators bool
+}
+
+// Instance is a single VM in a [Network].
+type Instance struct {
+	NodeID  ids.NodeID
+	ChainID ids.ID
+	VM      *vm.VM
+
+	// URI serves all handlers of [VM] at their registered endpoints, so it
+	// can be passed to both [rpc.NewJSONRPCClient] and VM-specific clients.
+	URI    string
+	Client *rpc.JSONRPCClient
+
+	// Metrics gathers all metrics registered by [VM].
+	Metrics prometheus.Gatherer
+
+	toEngine chan common.Message
+	server   *httptest.Server
+	sk       *bls.SecretKey
+	dataDir  string
+}
+
+// Network is a set of in-process VM instances that share a genesis and
+// gossip transactions directly to each other.
+//
+// Blocks are produced on demand by calling [BuildBlock], which verifies and
+// accepts the block on every instance.
+//
+// Every instance is a validator with a weight of 1 at every P-Chain height
+// and blocks are built and verified with a block context (at P-Chain height
+// 0), so blocks that take validator snapshots are supported. The current
+// P-Chain height is not available, so warp signatures are never gathered.
+type Network struct {
+	config       *Config
+	networkID    uint32
+	chainID      ids.ID
+	subnetID     ids.ID
+	genesisBytes []byte
+	vmConfig     []byte
+	app          *appSender
+	instances    []*Instance
+}
+
+func New(ctx context.Context, config *Config) (*Network, error) {
+	if config.NumNodes < 1 {
+		return nil, ErrTooFewNodes
+	}
+	if config.NewVM == nil {
+		return nil, ErrMissingVM
+	}
+	if config.Genesis == nil {
+		return nil, ErrMissingGenesis
+	}
+	genesisBytes, err := config.Genesis(config.Allocations)
+	if err != nil {
+		return nil, err
+	}
+	vmConfig := config.VMConfig
+	if len(vmConfig) == 0 {
+		vmConfig = []byte(defaultVMConfig)
+	}
+
+	n := &Network{
+		config:       config,
+		networkID:    config.NetworkID,
+		chainID:      ids.GenerateTestID(),
+		subnetID:     ids.GenerateTestID(),
+		genesisBytes: genesisBytes,
+		vmConfig:     vmConfig,
+		app:          &appSender{},
+		instances:    make([]*Instance, 0, config.NumNodes),
+	}
+	for i := 0; i < config.NumNodes; i++ {
+		if _, err := n.AddInstance(ctx); err != nil {
+			return nil, errors.Join(err, n.Shutdown(ctx))
+		}
+	}
+	return n, nil
+}
+
+// AddInstance creates a new instance from genesis and adds it to the
+// network.
+//
+// If blocks have already been accepted, the instance must be caught up (e.g.
+// with [vm.VM.Replay]) before calling [BuildBlock] again.
+func (n *Network) AddInstance(ctx context.Context) (*Instance, error) {
+	inst, err := n.createInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n.instances = append(n.instances, inst)
+	n.app.l.Lock()
+	n.app.instances = n.instances
+	n.app.l.Unlock()
+	return inst, n.connect(ctx, inst)
+}
+
+// Restart shuts down instance [i] and starts a new VM with the same identity
+// and data directory in its place.
+func (n *Network) Restart(ctx context.Context, i int) error {
+	inst := n.instances[i]
+	inst.server.Close()
+	if err := inst.VM.Shutdown(ctx); err != nil {
+		return err
+	}
+	ninst, err := n.startInstance(ctx, inst.NodeID, inst.sk, inst.dataDir)
+	if err != nil {
+		return err
+	}
+	n.instances[i] = ninst
+	n.app.l.Lock()
+	n.app.instances = n.instances
+	n.app.l.Unlock()
+	return n.connect(ctx, ninst)
+}
+
+// connect notifies [inst] and every other instance that they are connected
+// to each other.
+func (n *Network) connect(ctx context.Context, inst *Instance) error {
+	for _, peer := range n.instances {
+		if peer == inst {
+			continue
+		}
+		if err := inst.VM.Connected(ctx, peer.NodeID, version.CurrentApp); err != nil {
+			return err
+		}
+		if err := peer.VM.Connected(ctx, inst.NodeID, version.CurrentApp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Network) createInstance(ctx context.Context) (*Instance, error) {
+	nodeID := ids.GenerateTestNodeID()
+	sk, err := bls.NewSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	dataDir, err := os.MkdirTemp("", fmt.Sprintf("%s-chainData", nodeID))
+	if err != nil {
+		return nil, err
+	}
+	inst, err := n.startInstance(ctx, nodeID, sk, dataDir)
+	if err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dataDir))
+	}
+	return inst, nil
+}
+
+func (n *Network) startInstance(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	sk *bls.SecretKey,
+	dataDir string,
+) (*Instance, error) {
+	var (
+		config                = n.config
+		log    logging.Logger = logging.NoLog{}
+		err    error
+	)
+	if config.LogFactory != nil {
+		log, err = config.LogFactory.Make(nodeID.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	snowCtx := &snow.Context{
+		NetworkID:      n.networkID,
+		SubnetID:       n.subnetID,
+		ChainID:        n.chainID,
+		NodeID:         nodeID,
+		Log:            log,
+		ChainDataDir:   dataDir,
+		Metrics:        metrics.NewOptionalGatherer(),
+		PublicKey:      bls.PublicFromSecretKey(sk),
+		WarpSigner:     warp.NewSigner(sk, n.networkID, n.chainID),
+		ValidatorState: n.validatorState(),
+	}
+
+	toEngine := make(chan common.Message, 1)
+	v := config.NewVM()
+	if err := v.Initialize(ctx, snowCtx, memdb.New(), n.genesisBytes, nil, n.vmConfig, toEngine, nil, &nodeSender{n.app, nodeID}); err != nil {
+		return nil, err
+	}
+	handlers, err := v.CreateHandlers(ctx)
+	if err != nil {
+		return nil, errors.Join(err, v.Shutdown(ctx))
+	}
+	mux := http.NewServeMux()
+	for endpoint, handler := range handlers {
+		mux.Handle(endpoint, handler)
+	}
+	server := httptest.NewServer(mux)
+
+	// Mimic bootstrapping from genesis
+	v.ForceReady()
+	return &Instance{
+		NodeID:   nodeID,
+		ChainID:  n.chainID,
+		VM:       v,
+		URI:      server.URL,
+		Client:   rpc.NewJSONRPCClient(server.URL),
+		Metrics:  snowCtx.Metrics,
+		toEngine: toEngine,
+		server:   server,
+		sk:       sk,
+		dataDir:  dataDir,
+	}, nil
+}
+
+// validatorState reports every instance in [n] as a validator with a weight
+// of 1 (at any P-chain height).
+func (n *Network) validatorState() validators.State {
+	state := &validators.TestState{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return n.subnetID, nil
+		},
+		GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			n.app.l.Lock()
+			defer n.app.l.Unlock()
+
+			vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(n.app.instances))
+			for _, inst := range n.app.instances {
+				vdrs[inst.NodeID] = &validators.GetValidatorOutput{
+					NodeID:    inst.NodeID,
+					PublicKey: bls.PublicFromSecretKey(inst.sk),
+					Weight:    1,
+				}
+			}
+			return vdrs, nil
+		},
+	}
+	if n.config.TrackValidators {
+		state.GetCurrentHeightF = func(context.Context) (uint64, error) {
+			return 1, nil
+		}
+	}
+	return state
+}
+
+func (n *Network) NetworkID() uint32 {
+	return n.networkID
+}
+
+func (n *Network) ChainID() ids.ID {
+	return n.chainID
+}
+
+func (n *Network) Instances() []*Instance {
+	return n.instances
+}
+
+// Issue signs [action] with [factory] and submits it to instance [i]. The
+// transaction is gossiped to the rest of the network.
+func (n *Network) Issue(
+	ctx context.Context,
+	i int,
+	action chain.Action,
+	factory chain.AuthFactory,
+	modifiers ...rpc.Modifier,
+) (*chain.Transaction, error) {
+	inst := n.instances[i]
+	submit, tx, _, err := inst.Client.GenerateTransaction(ctx, inst.VM, nil, action, factory, modifiers...)
+	if err != nil {
+		return nil, err
+	}
+	if err := submit(ctx); err != nil {
+		return nil, err
+	}
+	return tx, inst.VM.Gossiper().Force(ctx)
+}
+
+// BuildBlock builds a block on instance [i] and then verifies and accepts it
+// on every instance in the network.
+func (n *Network) BuildBlock(ctx context.Context, i int) (*chain.StatelessBlock, error) {
+	builder := n.instances[i]
+	if err := builder.VM.Builder().Force(ctx); err != nil {
+		return nil, err
+	}
+	<-builder.toEngine // manually ack ready sig as in engine
+
+	bctx := &smblock.Context{}
+	blk, err := builder.VM.BuildBlockWithContext(ctx, bctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range n.instances {
+		iblk := blk
+		if inst != builder {
+			iblk, err = inst.VM.ParseBlock(ctx, blk.Bytes())
+			if err != nil {
+				return nil, err
+			}
+		}
+		withContext, err := iblk.(smblock.WithVerifyContext).ShouldVerifyWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if withContext {
+			err = iblk.(smblock.WithVerifyContext).VerifyWithContext(ctx, bctx)
+		} else {
+			err = iblk.Verify(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s failed to verify block", err, inst.NodeID)
+		}
+		if err := inst.VM.SetPreference(ctx, iblk.ID()); err != nil {
+			return nil, err
+		}
+		if err := iblk.Accept(ctx); err != nil {
+			return nil, err
+		}
+		if iblk.Status() != choices.Accepted {
+			return nil, fmt.Errorf("%w: node %s", ErrBlockNotAccepted, inst.NodeID)
+		}
+	}
+	return blk.(*chain.StatelessBlock), nil
+}
+
+// Confirm builds a block on instance [i] and returns the result of [txID].
+//
+// An error is returned if [txID] is not included in the block or if it
+// failed.
+func (n *Network) Confirm(ctx context.Context, i int, txID ids.ID) (*chain.Result, error) {
+	blk, err := n.BuildBlock(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+	results := blk.Results()
+	for j, tx := range blk.Txs {
+		if tx.ID() != txID {
+			continue
+		}
+		result := results[j]
+		if !result.Success() {
+			return result, fmt.Errorf("%w: %s", ErrTxFailed, result.Output)
+		}
+		return result, nil
+	}
+	return nil, ErrTxNotIncluded
+}
+
+// Shutdown stops all instances and removes their data.
+func (n *Network) Shutdown(ctx context.Context) error {
+	errs := []error{}
+	for _, inst := range n.instances {
+		inst.server.Close()
+		errs = append(errs, inst.VM.Shutdown(ctx), os.RemoveAll(inst.dataDir))
+	}
+	return errors.Join(errs...)
+}
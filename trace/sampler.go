@@ -0,0 +1,47 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ava-labs/avalanchego/trace"
+)
+
+var _ sdktrace.Sampler = (*sampler)(nil)
+
+// sampler samples a fraction of traces that can be changed while the tracer
+// is running.
+type sampler struct {
+	s atomic.Pointer[sdktrace.Sampler]
+}
+
+func newSampler(rate float64) *sampler {
+	s := &sampler{}
+	s.setRate(rate)
+	return s
+}
+
+func (s *sampler) setRate(rate float64) {
+	ratio := sdktrace.TraceIDRatioBased(rate)
+	s.s.Store(&ratio)
+}
+
+func (s *sampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.s.Load()).ShouldSample(p)
+}
+
+func (s *sampler) Description() string {
+	return (*s.s.Load()).Description()
+}
+
+// SetSampleRate updates the fraction of traces sampled by [t]. It is a no-op
+// if [t] was not created by [New] or tracing is disabled.
+func SetSampleRate(t trace.Tracer, rate float64) {
+	if t, ok := t.(*tracer); ok {
+		t.sampler.setRate(rate)
+	}
+}
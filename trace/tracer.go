@@ -41,7 +41,8 @@ type Config struct {
 type tracer struct {
 	oteltrace.Tracer
 
-	tp *sdktrace.TracerProvider
+	tp      *sdktrace.TracerProvider
+	sampler *sampler
 }
 
 func (t *tracer) Close() error {
@@ -64,6 +65,7 @@ func New(config *Config) (trace.Tracer, error) {
 		return nil, err
 	}
 
+	sampler := newSampler(config.TraceSampleRate)
 	tracerProviderOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithBatcher(exporter, sdktrace.WithExportTimeout(tracerExportTimeout)),
 		sdktrace.WithResource(
@@ -73,12 +75,13 @@ func New(config *Config) (trace.Tracer, error) {
 				semconv.ServiceNameKey.String(config.Agent),
 			),
 		),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(config.TraceSampleRate)),
+		sdktrace.WithSampler(sampler),
 	}
 
 	tracerProvider := sdktrace.NewTracerProvider(tracerProviderOpts...)
 	return &tracer{
-		Tracer: tracerProvider.Tracer(config.AppName),
-		tp:     tracerProvider,
+		Tracer:  tracerProvider.Tracer(config.AppName),
+		tp:      tracerProvider,
+		sampler: sampler,
 	}, nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 	"github.com/ava-labs/hypersdk/state"
 	"go.opentelemetry.io/otel/attribute"
@@ -50,6 +51,19 @@ func (ts *TState) PendingChanges() int {
 	return len(ts.changedKeys)
 }
 
+// Changes returns a copy of the keys modified in ts and their new values
+// (or [maybe.Nothing] if they were removed). The values must not be modified.
+func (ts *TState) Changes() map[string]maybe.Maybe[[]byte] {
+	ts.l.RLock()
+	defer ts.l.RUnlock()
+
+	changes := make(map[string]maybe.Maybe[[]byte], len(ts.changedKeys))
+	for k, v := range ts.changedKeys {
+		changes[k] = v
+	}
+	return changes
+}
+
 // OpIndex returns the number of operations done on ts.
 func (ts *TState) OpIndex() int {
 	ts.l.RLock()
@@ -58,6 +72,47 @@ func (ts *TState) OpIndex() int {
 	return ts.ops
 }
 
+// Checkpoint is the state of some keys in a [TState] that can be restored
+// with [TState.Restore].
+type Checkpoint struct {
+	ops     int
+	keys    set.Set[string]
+	changed map[string]maybe.Maybe[[]byte]
+}
+
+// Checkpoint records the changes made to [keys] so that any later changes
+// to them can be undone.
+func (ts *TState) Checkpoint(keys set.Set[string]) *Checkpoint {
+	ts.l.RLock()
+	defer ts.l.RUnlock()
+
+	changed := make(map[string]maybe.Maybe[[]byte], len(keys))
+	for k := range keys {
+		if v, ok := ts.changedKeys[k]; ok {
+			changed[k] = v
+		}
+	}
+	return &Checkpoint{ts.ops, keys, changed}
+}
+
+// Restore undoes all changes made to the keys of [c] since it was created.
+//
+// Operations since [c] was created are discarded, so no view modifying keys
+// outside of [c] may be committed in the meantime.
+func (ts *TState) Restore(c *Checkpoint) {
+	ts.l.Lock()
+	defer ts.l.Unlock()
+
+	for k := range c.keys {
+		if v, ok := c.changed[k]; ok {
+			ts.changedKeys[k] = v
+			continue
+		}
+		delete(ts.changedKeys, k)
+	}
+	ts.ops = c.ops
+}
+
 // ExportMerkleDBView creates a slice of [database.BatchOp] of all
 // changes in [TState] that can be used to commit to [merkledb].
 func (ts *TState) ExportMerkleDBView(
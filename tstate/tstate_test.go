@@ -613,3 +613,31 @@ func TestCreateView(t *testing.T) {
 		require.ErrorIs(err, database.ErrNotFound, "value not removed from db")
 	}
 }
+
+func TestCheckpointRestore(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	ts := New(10)
+
+	// Changes made before the checkpoint are kept
+	tsv := ts.NewView(set.Of(key1str, key2str), map[string][]byte{})
+	require.NoError(tsv.Insert(ctx, key1, []byte("val1")))
+	require.NoError(tsv.Insert(ctx, key2, []byte("val2")))
+	tsv.Commit()
+	ops := ts.OpIndex()
+	c := ts.Checkpoint(set.Of(key1str, key2str, key3str))
+
+	tsv = ts.NewView(set.Of(key1str, key2str, key3str), map[string][]byte{})
+	require.NoError(tsv.Insert(ctx, key1, []byte("val4")))
+	require.NoError(tsv.Remove(ctx, key2))
+	require.NoError(tsv.Insert(ctx, key3, []byte("val3")))
+	tsv.Commit()
+	require.Equal(3, ts.PendingChanges())
+
+	ts.Restore(c)
+	require.Equal(ops, ts.OpIndex())
+	require.Equal(map[string]maybe.Maybe[[]byte]{
+		key1str: maybe.Some([]byte("val1")),
+		key2str: maybe.Some([]byte("val2")),
+	}, ts.Changes())
+}
@@ -293,6 +293,16 @@ func (ts *TStateView) PendingChanges() int {
 	return len(ts.pendingChangedKeys)
 }
 
+// Changes returns a copy of the keys modified by [ts] and their new values
+// (Nothing if the key was removed).
+func (ts *TStateView) Changes() map[string]maybe.Maybe[[]byte] {
+	changes := make(map[string]maybe.Maybe[[]byte], len(ts.pendingChangedKeys))
+	for k, v := range ts.pendingChangedKeys {
+		changes[k] = v
+	}
+	return changes
+}
+
 // Commit adds all pending changes to the parent view.
 func (ts *TStateView) Commit() {
 	ts.ts.l.Lock()
@@ -5,6 +5,7 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"net"
 	"net/url"
@@ -33,7 +34,16 @@ func ErrBytes(err error) []byte {
 	return []byte(err.Error())
 }
 
-// Outputs to stdout.
+// outfWriter is where [Outf] writes to.
+var outfWriter io.Writer = formatter.ColorableStdOut
+
+// SetOutfWriter changes where [Outf] writes to (e.g. to keep stdout free for
+// machine-readable output).
+func SetOutfWriter(w io.Writer) {
+	outfWriter = w
+}
+
+// Outputs to stdout (unless changed with [SetOutfWriter]).
 //
 // e.g.,
 //
@@ -44,7 +54,7 @@ func ErrBytes(err error) []byte {
 // https://github.com/onsi/ginkgo/blob/v2.0.0/formatter/formatter.go#L52-L73
 func Outf(format string, args ...interface{}) {
 	s := formatter.F(format, args...)
-	fmt.Fprint(formatter.ColorableStdOut, s)
+	fmt.Fprint(outfWriter, s)
 }
 
 func GetHost(uri string) (string, error) {
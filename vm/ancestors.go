@@ -0,0 +1,84 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var _ block.BatchedChainVM = (*VM)(nil)
+
+// GetAncestors returns [blkID] and up to [maxBlocksNum]-1 of its ancestors
+// to a bootstrapping peer.
+//
+// If the [network.ServeLimitConfig] of the VM is exceeded, no blocks are
+// returned (which the peer treats as a signal to ask another node).
+func (vm *VM) GetAncestors(
+	ctx context.Context,
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	if !vm.serveLimiter.Acquire() {
+		vm.metrics.servesDropped.Inc()
+		return nil, nil
+	}
+	defer vm.serveLimiter.Release()
+
+	start := time.Now()
+	if budget := vm.serveLimiter.Budget(); budget < maxBlocksSize {
+		maxBlocksSize = budget
+	}
+	blk, err := vm.GetStatelessBlock(ctx, blkID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The requested block is always returned (even if it exceeds the
+	// remaining budget) so that the peer makes progress.
+	ancestors := [][]byte{blk.Bytes()}
+	size := len(blk.Bytes()) + wrappers.IntLen
+	for len(ancestors) < maxBlocksNum && time.Since(start) < maxBlocksRetrivalTime {
+		blk, err = vm.GetStatelessBlock(ctx, blk.Parent())
+		if errors.Is(err, database.ErrNotFound) {
+			// The rest of the ancestors may have been pruned
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		next := len(blk.Bytes()) + wrappers.IntLen
+		if size+next > maxBlocksSize {
+			break
+		}
+		ancestors = append(ancestors, blk.Bytes())
+		size += next
+	}
+	vm.serveLimiter.Charge(size)
+	return ancestors, nil
+}
+
+func (vm *VM) BatchedParseBlock(ctx context.Context, blks [][]byte) ([]snowman.Block, error) {
+	parsed := make([]snowman.Block, len(blks))
+	for i, b := range blks {
+		blk, err := vm.ParseBlock(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = blk
+	}
+	return parsed, nil
+}
@@ -0,0 +1,424 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+const (
+	maxBackfillBlocks   = 256
+	maxBackfillResponse = consts.NetworkSizeLimit - 1024 // leave room for message overhead
+	backfillServeTime   = time.Second
+	backfillTimeout     = 10 * time.Second
+	backfillRetryDelay  = time.Second
+)
+
+// blockBackfillProgress is the next block to backfill, persisted so that
+// the backfill resumes after a restart.
+type blockBackfillProgress struct {
+	next       ids.ID
+	nextHeight uint64
+	floor      uint64 // lowest height to backfill
+}
+
+func (vm *VM) getBlockBackfillProgress() (*blockBackfillProgress, error) {
+	b, err := vm.vmDB.Get(blockBackfill)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != consts.IDLen+consts.Uint64Len*2 {
+		return nil, ErrCorruptedBackfill
+	}
+	return &blockBackfillProgress{
+		next:       ids.ID(b[:consts.IDLen]),
+		nextHeight: binary.BigEndian.Uint64(b[consts.IDLen:]),
+		floor:      binary.BigEndian.Uint64(b[consts.IDLen+consts.Uint64Len:]),
+	}, nil
+}
+
+func putBlockBackfillProgress(db database.KeyValueWriter, p *blockBackfillProgress) error {
+	b := make([]byte, 0, consts.IDLen+consts.Uint64Len*2)
+	b = append(b, p.next[:]...)
+	b = binary.BigEndian.AppendUint64(b, p.nextHeight)
+	b = binary.BigEndian.AppendUint64(b, p.floor)
+	return db.Put(blockBackfill, b)
+}
+
+// scheduleBlockBackfill records that the blocks below the state sync
+// [target] (up to [Config.GetBlockBackfillDepth]) should be fetched from
+// peers once the node is ready.
+func (vm *VM) scheduleBlockBackfill(target *chain.StatelessBlock) error {
+	depth := uint64(vm.config.GetBlockBackfillDepth())
+	if depth == 0 || target.Hght <= 1 {
+		return nil
+	}
+	floor := uint64(1) // never fetch genesis
+	if target.Hght > depth {
+		floor = target.Hght - depth
+	}
+	return putBlockBackfillProgress(vm.vmDB, &blockBackfillProgress{
+		next:       target.Prnt,
+		nextHeight: target.Hght - 1,
+		floor:      floor,
+	})
+}
+
+// updateBlockBackfill keeps the scheduled backfill within
+// [Config.GetBlockBackfillDepth] of the state sync [target] when it moves.
+// The blocks between the previous target and [target] were accepted (and
+// stored) while syncing, so only the floor of the backfill changes.
+func (vm *VM) updateBlockBackfill(target *chain.StatelessBlock) error {
+	progress, err := vm.getBlockBackfillProgress()
+	if errors.Is(err, database.ErrNotFound) {
+		// The previous target was too low to backfill below it
+		return vm.scheduleBlockBackfill(target)
+	}
+	if err != nil {
+		return err
+	}
+	depth := uint64(vm.config.GetBlockBackfillDepth())
+	if target.Hght <= depth || target.Hght-depth <= progress.floor {
+		return nil
+	}
+	progress.floor = target.Hght - depth
+	return putBlockBackfillProgress(vm.vmDB, progress)
+}
+
+// blockBackfiller fetches the ancestors of the oldest block on-disk from
+// peers after state sync, so that API nodes can serve blocks they never
+// executed.
+//
+// Backfilled blocks are verified against the IDs of their children (which
+// were accepted by consensus) and are stored without results.
+type blockBackfiller struct {
+	vm        *VM
+	appSender common.AppSender
+
+	l         sync.Mutex
+	peers     set.Set[ids.NodeID]
+	requestID uint32
+	pending   map[uint32]chan []byte
+	progress  *blockBackfillProgress
+}
+
+func newBlockBackfiller(vm *VM, appSender common.AppSender) *blockBackfiller {
+	return &blockBackfiller{
+		vm:        vm,
+		appSender: appSender,
+		pending:   map[uint32]chan []byte{},
+	}
+}
+
+func (b *blockBackfiller) Run() {
+	// Wait until state sync (if any) has finished
+	select {
+	case <-b.vm.ready:
+	case <-b.vm.stop:
+		return
+	}
+	progress, err := b.vm.getBlockBackfillProgress()
+	if errors.Is(err, database.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		b.vm.ModuleLogger(logs.Backfill).Error("unable to load block backfill progress", zap.Error(err))
+		return
+	}
+	b.setProgress(progress)
+	b.vm.ModuleLogger(logs.Backfill).Info("starting block backfill",
+		zap.Uint64("height", progress.nextHeight),
+		zap.Uint64("floor", progress.floor),
+	)
+	for {
+		done, err := b.step(context.Background())
+		if err != nil {
+			b.vm.ModuleLogger(logs.Backfill).Debug("unable to backfill blocks", zap.Error(err))
+			select {
+			case <-time.After(backfillRetryDelay):
+			case <-b.vm.stop:
+				return
+			}
+			continue
+		}
+		if done {
+			b.vm.ModuleLogger(logs.Backfill).Info("finished block backfill")
+			return
+		}
+		select {
+		case <-b.vm.stop:
+			return
+		default:
+		}
+	}
+}
+
+// floor returns the lowest height to backfill, ensuring we don't write
+// blocks that [UpdateLastAccepted] has already pruned.
+func (b *blockBackfiller) floor(p *blockBackfillProgress) uint64 {
+	window := uint64(b.vm.config.GetAcceptedBlockWindow())
+	lastAccepted := b.vm.LastAcceptedBlock().Height()
+	if lastAccepted >= window && lastAccepted-window+1 > p.floor {
+		return lastAccepted - window + 1
+	}
+	return p.floor
+}
+
+// step backfills a single batch of blocks and returns true once all blocks
+// down to the floor are stored.
+func (b *blockBackfiller) step(ctx context.Context) (bool, error) {
+	p := b.Progress()
+	floor := b.floor(p)
+	if p.nextHeight < floor {
+		return true, b.vm.vmDB.Delete(blockBackfill)
+	}
+
+	// The block may already be on-disk (if state sync was restarted with a
+	// newer target)
+	next := &blockBackfillProgress{next: p.next, nextHeight: p.nextHeight, floor: p.floor}
+	batch := b.vm.vmDB.NewBatch()
+	if blkID, err := b.vm.GetBlockHeightID(p.nextHeight); err == nil && blkID == p.next {
+		blk, err := b.vm.GetDiskBlock(ctx, p.nextHeight)
+		if err != nil {
+			return false, err
+		}
+		next.next, next.nextHeight = blk.Prnt, p.nextHeight-1
+	} else {
+		blks, err := b.fetch(ctx, p.next, int(p.nextHeight-floor+1))
+		if err != nil {
+			return false, err
+		}
+		for _, blk := range blks {
+			if err := putBackfilledBlock(batch, blk); err != nil {
+				return false, err
+			}
+		}
+		last := blks[len(blks)-1]
+		next.next, next.nextHeight = last.Prnt, last.Hght-1
+		b.vm.metrics.backfilledBlocks.Add(float64(len(blks)))
+	}
+	done := next.nextHeight < floor
+	if done {
+		if err := batch.Delete(blockBackfill); err != nil {
+			return false, err
+		}
+	} else if err := putBlockBackfillProgress(batch, next); err != nil {
+		return false, err
+	}
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+	b.setProgress(next)
+	return done, nil
+}
+
+// fetch requests up to [max] blocks from a random peer, starting at [blkID]
+// and walking back through its ancestors.
+func (b *blockBackfiller) fetch(ctx context.Context, blkID ids.ID, max int) ([]*chain.StatelessBlock, error) {
+	if max > maxBackfillBlocks {
+		max = maxBackfillBlocks
+	}
+	b.l.Lock()
+	if b.peers.Len() == 0 {
+		b.l.Unlock()
+		return nil, ErrNoPeers
+	}
+	peers := b.peers.List()
+	nodeID := peers[rand.Intn(len(peers))] //nolint:gosec
+	requestID := b.requestID
+	b.requestID++
+	response := make(chan []byte, 1)
+	b.pending[requestID] = response
+	b.l.Unlock()
+	defer func() {
+		b.l.Lock()
+		delete(b.pending, requestID)
+		b.l.Unlock()
+	}()
+
+	p := codec.NewWriter(consts.IDLen+consts.IntLen, consts.IDLen+consts.IntLen)
+	p.PackID(blkID)
+	p.PackInt(max)
+	if err := b.appSender.SendAppRequest(ctx, set.Of(nodeID), requestID, p.Bytes()); err != nil {
+		return nil, err
+	}
+	var raw []byte
+	select {
+	case raw = <-response:
+	case <-time.After(backfillTimeout):
+		return nil, fmt.Errorf("%w: %s", ErrBackfillTimeout, nodeID)
+	case <-b.vm.stop:
+		return nil, ErrShuttingDown
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBackfillUnavailable, nodeID)
+	}
+
+	rp := codec.NewReader(raw, consts.NetworkSizeLimit)
+	count := rp.UnpackInt(true)
+	if err := rp.Err(); err != nil {
+		return nil, err
+	}
+	if count > max {
+		return nil, fmt.Errorf("%w: too many blocks", ErrInvalidBackfill)
+	}
+	blks := make([]*chain.StatelessBlock, 0, count)
+	expected := blkID
+	for i := 0; i < count; i++ {
+		var blkBytes []byte
+		rp.UnpackBytes(consts.NetworkSizeLimit, true, &blkBytes)
+		if err := rp.Err(); err != nil {
+			return nil, err
+		}
+		if utils.ToID(blkBytes) != expected {
+			return nil, fmt.Errorf("%w: unexpected block", ErrInvalidBackfill)
+		}
+		blk, err := chain.ParseBlock(ctx, blkBytes, choices.Accepted, b.vm)
+		if err != nil {
+			return nil, err
+		}
+		if len(blks) > 0 && blk.Hght+1 != blks[len(blks)-1].Hght {
+			return nil, fmt.Errorf("%w: unexpected height", ErrInvalidBackfill)
+		}
+		blks = append(blks, blk)
+		expected = blk.Prnt
+	}
+	if !rp.Empty() {
+		return nil, fmt.Errorf("%w: remaining=%d", chain.ErrInvalidObject, len(raw)-rp.Offset())
+	}
+	if err := rp.Err(); err != nil {
+		return nil, err
+	}
+	return blks, nil
+}
+
+func putBackfilledBlock(batch database.KeyValueWriter, blk *chain.StatelessBlock) error {
+	height := binary.BigEndian.AppendUint64(nil, blk.Height())
+	if err := batch.Put(PrefixBlockKey(blk.Height()), blk.Bytes()); err != nil {
+		return err
+	}
+	if err := batch.Put(PrefixBlockIDHeightKey(blk.ID()), height); err != nil {
+		return err
+	}
+	blkID := blk.ID()
+	return batch.Put(PrefixBlockHeightIDKey(blk.Height()), blkID[:])
+}
+
+func (b *blockBackfiller) Progress() *blockBackfillProgress {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	return b.progress
+}
+
+func (b *blockBackfiller) setProgress(p *blockBackfillProgress) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.progress = p
+}
+
+func (b *blockBackfiller) Connected(nodeID ids.NodeID) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.peers.Add(nodeID)
+}
+
+func (b *blockBackfiller) Disconnected(nodeID ids.NodeID) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.peers.Remove(nodeID)
+}
+
+// HandleResponse delivers [response] to the pending request with
+// [requestID]. A nil [response] indicates that the request failed.
+func (b *blockBackfiller) HandleResponse(requestID uint32, response []byte) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	ch, ok := b.pending[requestID]
+	if !ok {
+		return
+	}
+	delete(b.pending, requestID)
+	ch <- response
+}
+
+// Serve sends the ancestors of the requested block to a backfilling
+// peer (within the limits of [Config.GetServeLimitConfig]).
+func (b *blockBackfiller) Serve(ctx context.Context, nodeID ids.NodeID, requestID uint32, request []byte) error {
+	rp := codec.NewReader(request, consts.IDLen+consts.IntLen)
+	var blkID ids.ID
+	rp.UnpackID(true, &blkID)
+	max := rp.UnpackInt(true)
+	if err := rp.Err(); err != nil {
+		b.vm.ModuleLogger(logs.Backfill).Warn("unable to unpack backfill request", zap.Error(err))
+		return nil
+	}
+	if max > maxBackfillBlocks {
+		max = maxBackfillBlocks
+	}
+	ancestors, err := b.vm.GetAncestors(ctx, blkID, max, maxBackfillResponse, backfillServeTime)
+	if err != nil {
+		b.vm.ModuleLogger(logs.Backfill).Warn("unable to get ancestors", zap.Error(err))
+		return nil
+	}
+	if len(ancestors) == 0 {
+		// An empty response tells the peer to try another node
+		return b.appSender.SendAppResponse(ctx, nodeID, requestID, nil)
+	}
+	p := codec.NewWriter(consts.IntLen, consts.NetworkSizeLimit)
+	p.PackInt(len(ancestors))
+	for _, blk := range ancestors {
+		p.PackBytes(blk)
+	}
+	if err := p.Err(); err != nil {
+		b.vm.ModuleLogger(logs.Backfill).Warn("unable to pack ancestors", zap.Error(err))
+		return nil
+	}
+	return b.appSender.SendAppResponse(ctx, nodeID, requestID, p.Bytes())
+}
+
+// BlockBackfillStatus returns the progress of fetching blocks below the
+// state sync target from peers.
+func (vm *VM) BlockBackfillStatus() *rpc.BlockBackfillStatus {
+	if vm.blockBackfiller == nil {
+		// Can occur in test
+		return &rpc.BlockBackfillStatus{}
+	}
+	p := vm.blockBackfiller.Progress()
+	if p == nil {
+		return &rpc.BlockBackfillStatus{}
+	}
+	floor := vm.blockBackfiller.floor(p)
+	status := &rpc.BlockBackfillStatus{Floor: floor}
+	if p.nextHeight >= floor {
+		status.Active = true
+		status.NextHeight = p.nextHeight
+		status.Remaining = p.nextHeight - floor + 1
+	}
+	return status
+}
@@ -0,0 +1,475 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ametrics "github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	syncEng "github.com/ava-labs/avalanchego/x/sync"
+
+	hcache "github.com/ava-labs/hypersdk/cache"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/config"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/network"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/statesync"
+	"github.com/ava-labs/hypersdk/trace"
+)
+
+type backfillConfig struct {
+	*config.Config
+
+	depth  int
+	window int
+}
+
+func (c *backfillConfig) GetBlockBackfillDepth() int  { return c.depth }
+func (c *backfillConfig) GetAcceptedBlockWindow() int { return c.window }
+
+// newBackfillVM returns a [VM] with just enough initialized to serve and
+// backfill blocks.
+func newBackfillVM(t *testing.T, depth int, window int) *VM {
+	require := require.New(t)
+
+	tracer, err := trace.New(&trace.Config{Enabled: false})
+	require.NoError(err)
+	bByID, err := hcache.NewFIFO[ids.ID, *chain.StatelessBlock](3)
+	require.NoError(err)
+	_, m, err := newMetrics()
+	require.NoError(err)
+	cfg := &backfillConfig{Config: &config.Config{}, depth: depth, window: window}
+	return &VM{
+		snowCtx: &snow.Context{Log: logging.NoLog{}, Metrics: ametrics.NewOptionalGatherer()},
+		logs:    logs.NewManager(logging.NoLog{}, logging.Info),
+		config:  cfg,
+		metrics: m,
+
+		vmDB: memdb.New(),
+
+		tracer:             tracer,
+		acceptedBlocksByID: bByID,
+		verifiedBlocks:     make(map[ids.ID]*chain.StatelessBlock),
+		serveLimiter:       network.NewServeLimiter(cfg.GetServeLimitConfig()),
+
+		ready: make(chan struct{}),
+		stop:  make(chan struct{}),
+	}
+}
+
+// newBackfillChain returns a chain of [length] empty blocks (starting with
+// genesis) parsed by [vm].
+func newBackfillChain(t *testing.T, vm *VM, length int) []*chain.StatelessBlock {
+	require := require.New(t)
+
+	genesis := chain.NewGenesisBlock(ids.Empty)
+	blks := make([]*chain.StatelessBlock, 0, length)
+	prnt := ids.Empty
+	for i := 0; i < length; i++ {
+		stateful := genesis
+		if i > 0 {
+			stateful = &chain.StatefulBlock{
+				Prnt:   prnt,
+				Hght:   uint64(i),
+				Tmstmp: genesis.Tmstmp + int64(i)*1_000,
+			}
+		}
+		blk, err := chain.ParseStatefulBlock(context.Background(), stateful, nil, choices.Accepted, vm)
+		require.NoError(err)
+		blks = append(blks, blk)
+		prnt = blk.ID()
+	}
+	return blks
+}
+
+// storeBackfillChain persists [blks] to the disk of [vm] and accepts the
+// last one.
+func storeBackfillChain(t *testing.T, vm *VM, blks []*chain.StatelessBlock) {
+	if blks[0].Height() == 0 {
+		vm.genesisBlk = blks[0]
+	}
+	for _, blk := range blks {
+		require.NoError(t, putBackfilledBlock(vm.vmDB, blk))
+	}
+	vm.lastAccepted = blks[len(blks)-1]
+}
+
+// connectBackfill routes the backfill requests of [client] to [server] (over
+// their [BlockBackfillHandler]s) and returns the IDs of the requested blocks.
+func connectBackfill(t *testing.T, client *VM, server *VM) *[]ids.ID {
+	var (
+		requested     []ids.ID
+		clientID      = ids.GenerateTestNodeID()
+		serverID      = ids.GenerateTestNodeID()
+		clientHandler = NewBlockBackfillHandler(client)
+		serverHandler = NewBlockBackfillHandler(server)
+	)
+	server.blockBackfiller = newBlockBackfiller(server, &common.SenderTest{
+		T: t,
+		SendAppResponseF: func(ctx context.Context, _ ids.NodeID, requestID uint32, response []byte) error {
+			return clientHandler.AppResponse(ctx, serverID, requestID, response)
+		},
+	})
+	client.blockBackfiller = newBlockBackfiller(client, &common.SenderTest{
+		T: t,
+		SendAppRequestF: func(ctx context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32, request []byte) error {
+			require.True(t, nodeIDs.Contains(serverID))
+			requested = append(requested, ids.ID(request[:consts.IDLen]))
+			return serverHandler.AppRequest(ctx, clientID, requestID, time.Time{}, request)
+		},
+	})
+	require.NoError(t, clientHandler.Connected(context.Background(), serverID, nil))
+	return &requested
+}
+
+func TestBlockBackfillDepth(t *testing.T) {
+	require := require.New(t)
+
+	server := newBackfillVM(t, 0, 1_000)
+	blks := newBackfillChain(t, server, 21)
+	storeBackfillChain(t, server, blks)
+
+	// Nothing is scheduled if backfilling is disabled
+	client := newBackfillVM(t, 0, 1_000)
+	storeBackfillChain(t, client, blks[20:])
+	require.NoError(client.scheduleBlockBackfill(blks[20]))
+	_, err := client.getBlockBackfillProgress()
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// Only the configured number of blocks below the target are fetched
+	client = newBackfillVM(t, 5, 1_000)
+	storeBackfillChain(t, client, blks[20:])
+	require.NoError(client.scheduleBlockBackfill(blks[20]))
+	requested := connectBackfill(t, client, server)
+	close(client.ready)
+	client.blockBackfiller.Run()
+	require.Equal([]ids.ID{blks[19].ID()}, *requested)
+	for _, blk := range blks[15:] {
+		stored, err := client.GetDiskBlock(context.Background(), blk.Height())
+		require.NoError(err)
+		require.Equal(blk.ID(), stored.ID())
+		height, err := client.GetBlockIDHeight(blk.ID())
+		require.NoError(err)
+		require.Equal(blk.Height(), height)
+	}
+	has, err := client.HasDiskBlock(14)
+	require.NoError(err)
+	require.False(has)
+	_, err = client.getBlockBackfillProgress()
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+// syncDBStub and syncClientStub let a [syncEng.Manager] be created without a
+// database or peers (the sync is never started).
+type (
+	syncDBStub     struct{ syncEng.DB }
+	syncClientStub struct{ syncEng.Client }
+)
+
+// newTestSyncClient returns a [stateSyncerClient] of [vm] that is syncing to
+// [target].
+func newTestSyncClient(t *testing.T, vm *VM, target *chain.StatelessBlock) *stateSyncerClient {
+	require := require.New(t)
+
+	manager, err := syncEng.NewManager(syncEng.ManagerConfig{
+		DB:                    syncDBStub{},
+		Client:                syncClientStub{},
+		SimultaneousWorkLimit: 1,
+		Log:                   logging.NoLog{},
+		TargetRoot:            target.StateRoot,
+		BranchFactor:          merkledb.BranchFactor16,
+	})
+	require.NoError(err)
+	tracker, err := statesync.New(prometheus.NewRegistry())
+	require.NoError(err)
+	tracker.Start(target.Hght, target.StateRoot)
+	vm.stateSyncClient = &stateSyncerClient{
+		vm:          vm,
+		syncManager: manager,
+		tracker:     tracker,
+		target:      target,
+		done:        make(chan struct{}),
+	}
+	return vm.stateSyncClient
+}
+
+func TestBlockBackfillSyncTargetUpdate(t *testing.T) {
+	require := require.New(t)
+
+	server := newBackfillVM(t, 0, 1_000)
+	blks := newBackfillChain(t, server, 31)
+	storeBackfillChain(t, server, blks)
+
+	// The backfill is scheduled below the first target
+	client := newBackfillVM(t, 10, 1_000)
+	storeBackfillChain(t, client, blks[20:21])
+	require.NoError(client.scheduleBlockBackfill(blks[20]))
+	syncer := newTestSyncClient(t, client, blks[20])
+
+	// Blocks accepted while syncing are stored and become the target, so the
+	// backfill follows the new target without refetching them
+	storeBackfillChain(t, client, blks[21:26])
+	updated, err := client.UpdateSyncTarget(blks[25])
+	require.NoError(err)
+	require.True(updated)
+	require.Equal(blks[25], syncer.target)
+	require.Equal(blks[25].Hght, syncer.tracker.Progress().TargetHeight)
+	progress, err := client.getBlockBackfillProgress()
+	require.NoError(err)
+	require.Equal(&blockBackfillProgress{next: blks[19].ID(), nextHeight: 19, floor: 15}, progress)
+
+	// The target can't move once the sync has finished
+	syncer.syncManager.Close()
+	close(syncer.done)
+	updated, err = client.UpdateSyncTarget(blks[30])
+	require.NoError(err)
+	require.False(updated)
+	saved, err := client.getBlockBackfillProgress()
+	require.NoError(err)
+	require.Equal(progress, saved)
+
+	requested := connectBackfill(t, client, server)
+	close(client.ready)
+	client.blockBackfiller.Run()
+	require.Equal([]ids.ID{blks[19].ID()}, *requested)
+	for _, blk := range blks[15:26] {
+		stored, err := client.GetDiskBlock(context.Background(), blk.Height())
+		require.NoError(err)
+		require.Equal(blk.ID(), stored.ID())
+	}
+	has, err := client.HasDiskBlock(14)
+	require.NoError(err)
+	require.False(has)
+
+	// A backfill is scheduled once the target is high enough to have blocks
+	// below it
+	client = newBackfillVM(t, 10, 1_000)
+	storeBackfillChain(t, client, blks[1:2])
+	require.NoError(client.scheduleBlockBackfill(blks[1]))
+	_, err = client.getBlockBackfillProgress()
+	require.ErrorIs(err, database.ErrNotFound)
+	newTestSyncClient(t, client, blks[1])
+	storeBackfillChain(t, client, blks[2:26])
+	updated, err = client.UpdateSyncTarget(blks[25])
+	require.NoError(err)
+	require.True(updated)
+	progress, err = client.getBlockBackfillProgress()
+	require.NoError(err)
+	require.Equal(&blockBackfillProgress{next: blks[24].ID(), nextHeight: 24, floor: 15}, progress)
+}
+
+func TestBlockBackfillResume(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	server := newBackfillVM(t, 0, 1_000)
+	blks := newBackfillChain(t, server, maxBackfillBlocks+51)
+	storeBackfillChain(t, server, blks)
+	target := blks[len(blks)-1]
+
+	// No status is reported until the backfill starts
+	client := newBackfillVM(t, maxBackfillBlocks+20, 1_000)
+	storeBackfillChain(t, client, blks[len(blks)-1:])
+	require.NoError(client.scheduleBlockBackfill(target))
+	requested := connectBackfill(t, client, server)
+	jsonrpc := rpc.NewJSONRPCServer(client)
+	reply := new(rpc.GetBlockBackfillStatusReply)
+	require.NoError(jsonrpc.GetBlockBackfillStatus(nil, nil, reply))
+	require.Equal(&rpc.BlockBackfillStatus{}, reply.Status)
+
+	// A single request fetches at most [maxBackfillBlocks]
+	progress, err := client.getBlockBackfillProgress()
+	require.NoError(err)
+	client.blockBackfiller.setProgress(progress)
+	done, err := client.blockBackfiller.step(ctx)
+	require.NoError(err)
+	require.False(done)
+	require.Equal([]ids.ID{blks[target.Hght-1].ID()}, *requested)
+	require.NoError(jsonrpc.GetBlockBackfillStatus(nil, nil, reply))
+	require.Equal(&rpc.BlockBackfillStatus{
+		Active:     true,
+		NextHeight: target.Hght - 1 - maxBackfillBlocks,
+		Floor:      target.Hght - maxBackfillBlocks - 20,
+		Remaining:  20,
+	}, reply.Status)
+
+	// After a restart, the backfill continues from the saved progress
+	requested = connectBackfill(t, client, server)
+	close(client.ready)
+	client.blockBackfiller.Run()
+	require.Equal([]ids.ID{blks[target.Hght-1-maxBackfillBlocks].ID()}, *requested)
+	for _, blk := range blks[target.Hght-maxBackfillBlocks-20:] {
+		has, err := client.HasDiskBlock(blk.Height())
+		require.NoError(err)
+		require.True(has)
+	}
+	require.NoError(jsonrpc.GetBlockBackfillStatus(nil, nil, reply))
+	require.Equal(&rpc.BlockBackfillStatus{Floor: target.Hght - maxBackfillBlocks - 20}, reply.Status)
+}
+
+func TestBlockBackfillStatusWindow(t *testing.T) {
+	require := require.New(t)
+
+	vm := newBackfillVM(t, 100, 30)
+	blks := newBackfillChain(t, vm, 41)
+	storeBackfillChain(t, vm, blks[40:])
+	vm.blockBackfiller = newBlockBackfiller(vm, nil)
+	require.NoError(vm.scheduleBlockBackfill(blks[40]))
+	progress, err := vm.getBlockBackfillProgress()
+	require.NoError(err)
+	require.Equal(uint64(1), progress.floor) // never fetch genesis
+	vm.blockBackfiller.setProgress(progress)
+
+	// Blocks that would be pruned by the accepted block window aren't fetched
+	require.Equal(&rpc.BlockBackfillStatus{
+		Active:     true,
+		NextHeight: 39,
+		Floor:      11,
+		Remaining:  29,
+	}, vm.BlockBackfillStatus())
+}
+
+func TestBlockBackfillInvalidResponse(t *testing.T) {
+	ctx := context.Background()
+
+	parser := newBackfillVM(t, 0, 1_000)
+	blks := newBackfillChain(t, parser, 11)
+
+	// A block at the expected height that isn't the expected block
+	fork, err := chain.ParseStatefulBlock(ctx, &chain.StatefulBlock{
+		Prnt:   blks[8].ID(),
+		Hght:   9,
+		Tmstmp: blks[9].Tmstmp + 1,
+	}, nil, choices.Accepted, parser)
+	require.NoError(t, err)
+	// A chain whose IDs link but whose heights don't
+	skipParent, err := chain.ParseStatefulBlock(ctx, &chain.StatefulBlock{
+		Prnt:   blks[7].ID(),
+		Hght:   8,
+		Tmstmp: blks[8].Tmstmp,
+	}, nil, choices.Accepted, parser)
+	require.NoError(t, err)
+	skip, err := chain.ParseStatefulBlock(ctx, &chain.StatefulBlock{
+		Prnt:   skipParent.ID(),
+		Hght:   10,
+		Tmstmp: blks[10].Tmstmp,
+	}, nil, choices.Accepted, parser)
+	require.NoError(t, err)
+	skipTarget, err := chain.ParseStatefulBlock(ctx, &chain.StatefulBlock{
+		Prnt:   skip.ID(),
+		Hght:   11,
+		Tmstmp: blks[10].Tmstmp + 1_000,
+	}, nil, choices.Accepted, parser)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name     string
+		target   *chain.StatelessBlock
+		response []*chain.StatelessBlock
+		failed   bool
+		err      error
+	}{
+		{
+			name:     "unexpected block",
+			target:   blks[10],
+			response: []*chain.StatelessBlock{fork},
+			err:      ErrInvalidBackfill,
+		},
+		{
+			name:     "unexpected parent",
+			target:   blks[10],
+			response: []*chain.StatelessBlock{blks[9], blks[7]},
+			err:      ErrInvalidBackfill,
+		},
+		{
+			name:     "unexpected height",
+			target:   skipTarget,
+			response: []*chain.StatelessBlock{skip, skipParent},
+			err:      ErrInvalidBackfill,
+		},
+		{
+			name:     "too many blocks",
+			target:   blks[3],
+			response: []*chain.StatelessBlock{blks[2], blks[1], blks[0]},
+			err:      ErrInvalidBackfill,
+		},
+		{
+			name:   "unavailable",
+			target: blks[10],
+			err:    ErrBackfillUnavailable,
+		},
+		{
+			name:   "request failed",
+			target: blks[10],
+			failed: true,
+			err:    ErrBackfillUnavailable,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := newBackfillVM(t, 1_000, 1_000)
+			storeBackfillChain(t, vm, []*chain.StatelessBlock{test.target})
+			require.NoError(vm.scheduleBlockBackfill(test.target))
+			handler := NewBlockBackfillHandler(vm)
+			peer := ids.GenerateTestNodeID()
+			vm.blockBackfiller = newBlockBackfiller(vm, &common.SenderTest{
+				T: t,
+				SendAppRequestF: func(ctx context.Context, _ set.Set[ids.NodeID], requestID uint32, _ []byte) error {
+					if test.failed {
+						return handler.AppRequestFailed(ctx, peer, requestID)
+					}
+					if len(test.response) == 0 {
+						return handler.AppResponse(ctx, peer, requestID, nil)
+					}
+					p := codec.NewWriter(consts.IntLen, consts.NetworkSizeLimit)
+					p.PackInt(len(test.response))
+					for _, blk := range test.response {
+						p.PackBytes(blk.Bytes())
+					}
+					require.NoError(p.Err())
+					return handler.AppResponse(ctx, peer, requestID, p.Bytes())
+				},
+			})
+
+			// Requests aren't sent without peers
+			progress, err := vm.getBlockBackfillProgress()
+			require.NoError(err)
+			vm.blockBackfiller.setProgress(progress)
+			_, err = vm.blockBackfiller.step(ctx)
+			require.ErrorIs(err, ErrNoPeers)
+
+			// Nothing is stored from an invalid response
+			require.NoError(handler.Connected(ctx, peer, nil))
+			_, err = vm.blockBackfiller.step(ctx)
+			require.ErrorIs(err, test.err)
+			for _, blk := range test.response {
+				has, err := vm.HasDiskBlock(blk.Height())
+				require.NoError(err)
+				require.False(has)
+			}
+			saved, err := vm.getBlockBackfillProgress()
+			require.NoError(err)
+			require.Equal(progress, saved)
+			require.Equal(progress, vm.blockBackfiller.Progress())
+		})
+	}
+}
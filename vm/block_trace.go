@@ -0,0 +1,34 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// BlockTrace returns the [chain.BlockTrace] of a block that is processing or
+// was recently accepted. Traces are only recorded when the execution of a
+// block is sampled by the tracer and are not persisted, so blocks read from
+// disk never have one.
+func (vm *VM) BlockTrace(blkID ids.ID) (*chain.BlockTrace, error) {
+	var blk *chain.StatelessBlock
+	vm.verifiedL.RLock()
+	blk = vm.verifiedBlocks[blkID]
+	vm.verifiedL.RUnlock()
+	if blk == nil {
+		if lastAccepted := vm.lastAccepted; lastAccepted.ID() == blkID {
+			blk = lastAccepted
+		} else if accepted, ok := vm.acceptedBlocksByID.Get(blkID); ok {
+			blk = accepted
+		}
+	}
+	if blk == nil || blk.Trace() == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoBlockTrace, blkID)
+	}
+	return blk.Trace(), nil
+}
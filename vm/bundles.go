@@ -0,0 +1,102 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+func (vm *VM) Bundles() chain.BundlePool {
+	if vm.bundles == nil {
+		// Avoid returning a non-nil interface
+		return nil
+	}
+	return vm.bundles
+}
+
+// SubmitBundle holds [bundle] for inclusion at the start of the next blocks
+// built by this node (see [chain.Bundle]).
+//
+// Only the first transaction in [bundle] is checked against the preferred
+// state because the rest may depend on the changes of the transactions
+// before them. Signatures must be verified by the caller.
+func (vm *VM) SubmitBundle(ctx context.Context, bundle *chain.Bundle) error {
+	ctx, span := vm.tracer.Start(ctx, "VM.SubmitBundle")
+	defer span.End()
+
+	if vm.bundles == nil {
+		return ErrBundlesDisabled
+	}
+	if !vm.isReady() {
+		return ErrNotReady
+	}
+	select {
+	case <-vm.stop:
+		return ErrShuttingDown
+	default:
+	}
+	if vm.bundles.Has(ctx, bundle.ID()) {
+		return ErrNotAdded
+	}
+
+	// Create temporary execution context
+	blk, err := vm.GetStatelessBlock(ctx, vm.preferred)
+	if err != nil {
+		return err
+	}
+	view, err := blk.View(ctx, false)
+	if err != nil {
+		return err
+	}
+	parentFeeManager, _, err := chain.GetFeeManager(ctx, vm.StateManager(), view)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	r, err := chain.StateRules(ctx, vm, vm.StateManager(), view, now)
+	if err != nil {
+		return err
+	}
+	feeManager, err := parentFeeManager.ComputeNext(blk.Tmstmp, now, r)
+	if err != nil {
+		return err
+	}
+	oldestAllowed := now - r.GetValidityWindow()
+	repeats, err := blk.IsRepeat(ctx, oldestAllowed, chain.OldestAllowedHeight(r, blk.Hght+1), bundle.Txs, set.NewBits(), true)
+	if err != nil {
+		return err
+	}
+	if repeats.Len() > 0 {
+		return chain.ErrDuplicateTx
+	}
+	for _, tx := range bundle.Txs {
+		// Transactions in the mempool could be included without the rest of
+		// the bundle
+		if vm.mempool.Has(ctx, tx.ID()) {
+			return fmt.Errorf("%w: %s is in the mempool", ErrNotAdded, tx.ID())
+		}
+		if _, err := tx.StateKeys(vm.c.StateManager(), r); err != nil {
+			return err
+		}
+		if err := tx.CheckDeprecated(r, blk.Hght+1); err != nil {
+			return err
+		}
+	}
+	first := bundle.Txs[0]
+	if err := first.PreExecute(ctx, feeManager, vm.c.StateManager(), r, view, now, blk.Hght+1); err != nil {
+		return err
+	}
+	if err := chain.PreExecuteHook(ctx, vm.Hooks(), r, view, now, blk.Hght+1, first); err != nil {
+		return err
+	}
+	vm.bundles.Add(ctx, []*chain.Bundle{bundle})
+	vm.checkActivity(ctx)
+	return nil
+}
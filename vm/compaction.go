@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/rpc"
+)
+
+// Compact forces a compaction of the entire keyspace of [db] (one of
+// [rpc.CompactBlockDB], [rpc.CompactStateDB], or [rpc.CompactAllDBs]),
+// dropping deleted and overwritten values so that reads don't need to skip
+// over them.
+//
+// Compactions are serialized, so this waits for any compaction already in
+// progress to finish.
+func (vm *VM) Compact(db string) error {
+	switch db {
+	case rpc.CompactBlockDB:
+		return vm.compact(rpc.CompactBlockDB, vm.vmDB, vm.metrics.blockCompaction)
+	case rpc.CompactStateDB:
+		return vm.compact(rpc.CompactStateDB, vm.rawStateDB, vm.metrics.stateCompaction)
+	case rpc.CompactAllDBs:
+		if err := vm.Compact(rpc.CompactBlockDB); err != nil {
+			return err
+		}
+		return vm.Compact(rpc.CompactStateDB)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownDatabase, db)
+	}
+}
+
+func (vm *VM) compact(name string, db database.Database, m metric.Averager) error {
+	vm.dbL.Lock()
+	defer vm.dbL.Unlock()
+
+	// The databases are closed during shutdown, so we can't start a
+	// compaction once [vm.stop] is closed.
+	select {
+	case <-vm.stop:
+		return ErrShuttingDown
+	default:
+	}
+
+	start := time.Now()
+	if err := db.Compact(nil, nil); err != nil {
+		return err
+	}
+	t := time.Since(start)
+	m.Observe(float64(t))
+	vm.Logger().Info("compacted database", zap.String("db", name), zap.Duration("t", t))
+	return nil
+}
+
+// compactPeriodically compacts all databases every [interval] until
+// shutdown.
+func (vm *VM) compactPeriodically(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-vm.stop:
+			return
+		}
+		if err := vm.Compact(rpc.CompactAllDBs); err != nil {
+			vm.Logger().Warn("unable to compact databases", zap.Error(err))
+		}
+	}
+}
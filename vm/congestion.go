@@ -0,0 +1,31 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import "fmt"
+
+// CongestionPolicy determines how [VM.Submit] handles transactions whose
+// dominant dimension is already over target in the fee window (see
+// [chain.FeeManager.Congested]).
+type CongestionPolicy string
+
+const (
+	// AcceptCongested adds congested transactions to the mempool like any
+	// other transaction.
+	AcceptCongested CongestionPolicy = "accept"
+	// RejectCongested does not add congested transactions to the mempool.
+	RejectCongested CongestionPolicy = "reject"
+	// DeprioritizeCongested only includes congested transactions in blocks
+	// once all other transactions in the mempool have been tried.
+	DeprioritizeCongested CongestionPolicy = "deprioritize"
+)
+
+func parseCongestionPolicy(s string) (CongestionPolicy, error) {
+	switch p := CongestionPolicy(s); p {
+	case AcceptCongested, RejectCongested, DeprioritizeCongested:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownCongestionPolicy, s)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow"
 	atrace "github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
@@ -19,6 +20,9 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/gossiper"
+	"github.com/ava-labs/hypersdk/network"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/slo"
 	"github.com/ava-labs/hypersdk/state"
 	trace "github.com/ava-labs/hypersdk/trace"
 )
@@ -26,25 +30,41 @@ import (
 type Handlers map[string]http.Handler
 
 type Config interface {
+	GetLogLevel() logging.Level // default level of all modules (see [VM.Logs])
 	GetTraceConfig() *trace.Config
 	GetMempoolSize() int
-	GetAuthVerificationCores() int
+	GetAuthVerificationCores() int    // maximum number of goroutines verifying signatures
+	GetAuthVerificationMinCores() int // goroutines kept verifying signatures when load is low
 	GetVerifyAuth() bool
+	GetED25519Backend() string // implementation used to verify ed25519 signatures (all accept the same signatures)
 	GetRootGenerationCores() int
 	GetTransactionExecutionCores() int
+	GetExecutionTrace() bool // re-execute verified blocks sequentially to find nondeterminism (expensive)
 	GetMempoolSponsorSize() int
 	GetMempoolExemptSponsors() []codec.Address
+	GetMempoolCongestionPolicy() string
+	GetMempoolMaxNonceGap() int   // how far ahead of its sponsor's next nonce a transaction may be to enter the mempool
+	GetMempoolBundleSize() int    // how many bundles to hold for inclusion in built blocks (0 disables bundles)
+	GetMempoolFeeExemptSize() int // how many txs exempt from fees (see [chain.Rules.GetActionFeePayer]) to hold
+	GetFCFSOrdering() bool        // if true, build blocks in the order txs were received and commit to their arrival times
 	GetStreamingBacklogSize() int
+	GetStreamingReplaySize() int              // how many messages to retain per stream for resuming clients
+	GetStreamingMaxConnections() int          // 0 for no limit
+	GetStreamingMaxConnectionsPerIP() int     // 0 for no limit
+	GetStreamingAuthTokens() []string         // if non-empty, streaming clients must provide one of these
+	GetStreamingMempool() bool                // if true, streaming clients may subscribe to txs entering the mempool
 	GetStateHistoryLength() int               // how many roots back of data to keep to serve state queries
 	GetIntermediateNodeCacheSize() int        // how many bytes to keep in intermediate cache
 	GetStateIntermediateWriteBufferSize() int // how many bytes to keep unwritten in intermediate cache
 	GetStateIntermediateWriteBatchSize() int  // how many bytes to to write from intermediate cache at once
 	GetValueNodeCacheSize() int               // how many bytes to keep in value cache
 	GetAcceptorSize() int                     // how far back we can fall in processing accepted blocks
+	GetShutdownTimeout() time.Duration        // how long to wait for in-flight work to drain on shutdown
 	GetStateSyncParallelism() int
 	GetStateSyncMinBlocks() uint64
 	GetStateSyncServerDelay() time.Duration
 	GetParsedBlockCacheSize() int
+	GetStateCacheSize() int // how many hot keys of accepted state to cache values of (0 to disable)
 	GetAcceptedBlockWindow() int
 	GetAcceptedBlockWindowCache() int
 	GetContinuousProfilerConfig() *profiler.Config
@@ -52,6 +72,86 @@ type Config interface {
 	GetProcessingBuildSkip() int
 	GetTargetGossipDuration() time.Duration
 	GetBlockCompactionFrequency() int
+	GetCompactionInterval() time.Duration   // how often to compact the block and state databases (0 to disable)
+	GetStorageStatsInterval() time.Duration // how often to collect disk usage and count state nodes (0 to disable)
+	GetBlockBackfillDepth() int             // how many blocks below the state sync target to fetch from peers (0 to disable)
+	GetSLOConfig() *slo.Config
+	GetAPINode() bool // if true, only track the chain and serve APIs (never build blocks or sign warp messages)
+	GetAdminAPIEnabled() bool
+	GetAdminAPITokens() []string     // if non-empty, admin requests must provide one of these
+	GetCORSAllowedOrigins() []string // origins that browsers may call APIs from ("*" for any)
+	GetRPCLimitConfig() *rpc.LimitConfig
+	GetServeLimitConfig() *network.ServeLimitConfig
+	GetReloadConfigFile() string     // if non-empty, the config is reloaded from this file on SIGHUP
+	GetBlobRetention() uint64        // how many accepted blocks of blobs to keep on-disk (0 to never delete)
+	GetWarpMessageRetention() uint64 // how many accepted blocks of outgoing warp messages to keep on-disk (0 to never delete)
+	GetSeenTimeGranularity() int64   // ms of expiries grouped in each bucket of the replay protection map
+	GetSeenHeightGranularity() int64 // blocks of expiries grouped in each bucket of the replay protection map
+}
+
+// ReloadableConfig can be implemented by a [Config] to support changing
+// operational parameters without restarting the node (see [VM.ReloadConfig]).
+type ReloadableConfig interface {
+	// Reload parses [b] (in the same format as the config provided on
+	// initialization) and updates the values that can be changed while the
+	// node is running. All other values are ignored.
+	Reload(b []byte) error
+}
+
+// BeneficiaryController can be implemented by a [Controller] to receive the
+// builder's share of the fees paid in blocks built by this node (see
+// [chain.Rules.GetBuilderFeeShare]).
+//
+// The share is credited to the [chain.StatefulBlock.Beneficiary] when the
+// block is executed (while it is built and verified) rather than when it is
+// accepted, so that it is committed to the state root like any other change.
+// Nothing checks that the beneficiary is the node that proposed the block:
+// any builder can name any address (so the share is only an incentive for
+// honest builders, not proof of who built the block).
+type BeneficiaryController interface {
+	// Beneficiary returns the address credited with the builder's share of
+	// fees. If false is returned, all fees are burned.
+	Beneficiary() (codec.Address, bool)
+}
+
+// BurnController can be implemented by a [Controller] to observe the fees
+// burned by each accepted block (e.g. to track the supply of the native
+// token).
+type BurnController interface {
+	// Burned is invoked after [Controller.Accepted] with the amount burned in
+	// each dimension by [blk].
+	Burned(ctx context.Context, blk *chain.StatelessBlock, burned chain.Dimensions) error
+}
+
+// RewardController can be implemented by a [Controller] to observe the block
+// reward minted by each accepted block (see [chain.Rules.GetBlockReward]).
+type RewardController interface {
+	// Rewarded is invoked after [Controller.Accepted] (and
+	// [BurnController.Burned]) if [blk] minted [amount] to [recipient].
+	Rewarded(ctx context.Context, blk *chain.StatelessBlock, recipient codec.Address, amount uint64) error
+}
+
+// StateRegistryController can be implemented by a [Controller] to serve its
+// state over the generic queryState RPC (see [rpc.StateRegistry]).
+type StateRegistryController interface {
+	StateRegistry() *rpc.StateRegistry
+}
+
+// StateSummaryController can be implemented by a [Controller] to add
+// VM-specific summaries (like the total balance of each asset) to
+// [VM.StateDigest].
+type StateSummaryController interface {
+	NewStateSummary() StateSummary
+}
+
+// StateSummary summarizes state as it is walked by [VM.StateDigest].
+type StateSummary interface {
+	// Add is called with every key-value pair in state, in key order.
+	Add(key []byte, value []byte) error
+
+	// Summaries returns the JSON-serializable summaries of all pairs added,
+	// by name.
+	Summaries() map[string]any
 }
 
 type Genesis interface {
@@ -61,6 +161,8 @@ type Genesis interface {
 }
 
 type AuthEngine interface {
+	// GetBatchVerifier returns a verifier for at most [count] auths (fewer
+	// may be added when auths are batched while a block is unmarshalled).
 	GetBatchVerifier(cores int, count int) chain.AuthBatchVerifier
 	Cache(auth chain.Auth)
 }
@@ -8,11 +8,40 @@ import (
 )
 
 var (
-	ErrNotAdded            = errors.New("not added")
-	ErrDropped             = errors.New("dropped")
-	ErrNotReady            = errors.New("not ready")
-	ErrStateMissing        = errors.New("state missing")
-	ErrStateSyncing        = errors.New("state still syncing")
-	ErrUnexpectedStateRoot = errors.New("unexpected state root")
-	ErrTooManyProcessing   = errors.New("too many processing")
+	ErrNotAdded             = errors.New("not added")
+	ErrDropped              = errors.New("dropped")
+	ErrNotReady             = errors.New("not ready")
+	ErrShuttingDown         = errors.New("shutting down")
+	ErrShutdownTimeout      = errors.New("shutdown timeout")
+	ErrConfigNotReloadable  = errors.New("config not reloadable")
+	ErrInvalidProfiler      = errors.New("invalid continuous profiler config")
+	ErrProfilerNotRunning   = errors.New("continuous profiler not running")
+	ErrStateMissing         = errors.New("state missing")
+	ErrStateSyncing         = errors.New("state still syncing")
+	ErrUnexpectedStateRoot  = errors.New("unexpected state root")
+	ErrTooManyProcessing    = errors.New("too many processing")
+	ErrAPINode              = errors.New("api node does not build blocks")
+	ErrCorruptedResults     = errors.New("corrupted results")
+	ErrCorruptedBlob        = errors.New("corrupted blob")
+	ErrCorruptedWarpMessage = errors.New("corrupted warp message")
+	ErrResignTooFrequent    = errors.New("warp message re-signed too recently")
+	ErrInvalidReplayRange   = errors.New("invalid replay range")
+	ErrMissingReplayBlock   = errors.New("missing replay block")
+	ErrReplayDiverged       = errors.New("replay diverged")
+	ErrUnknownDatabase      = errors.New("unknown database")
+	ErrNoStorageStats       = errors.New("storage stats not collected")
+	ErrNoBlockTrace         = errors.New("block trace not recorded")
+	ErrCorruptedBackfill    = errors.New("corrupted backfill progress")
+	ErrNoPeers              = errors.New("no peers")
+	ErrBackfillTimeout      = errors.New("backfill request timed out")
+	ErrBackfillUnavailable  = errors.New("backfill blocks unavailable")
+	ErrInvalidBackfill      = errors.New("invalid backfill response")
+	ErrTooManyTxs           = errors.New("too many txs")
+	ErrFetchTimeout         = errors.New("tx fetch request timed out")
+	ErrTxsUnavailable       = errors.New("txs unavailable")
+	ErrInvalidFetch         = errors.New("invalid tx fetch response")
+
+	ErrUnknownCongestionPolicy = errors.New("unknown congestion policy")
+	ErrCongested               = errors.New("dominant dimension over target")
+	ErrBundlesDisabled         = errors.New("bundles disabled")
 )
@@ -0,0 +1,63 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/mempool"
+)
+
+// mempoolFeeBuckets are the upper bounds of the max fee buckets of
+// [mempoolFeeCollector] (1 to 10^12).
+var mempoolFeeBuckets = prometheus.ExponentialBuckets(1, 10, 13)
+
+// mempoolFeeCollector reports the depth of the mempool by the max fee of its
+// transactions when scraped, so operators can see how much of the mempool
+// is priced out when throughput stalls.
+type mempoolFeeCollector struct {
+	desc    *prometheus.Desc
+	mempool *mempool.Mempool[*chain.Transaction]
+}
+
+func newMempoolFeeCollector(m *mempool.Mempool[*chain.Transaction]) *mempoolFeeCollector {
+	return &mempoolFeeCollector{
+		desc: prometheus.NewDesc(
+			"chain_mempool_max_fee",
+			"max fee of the txs in the mempool",
+			nil, nil,
+		),
+		mempool: m,
+	}
+}
+
+func (c *mempoolFeeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *mempoolFeeCollector) Collect(ch chan<- prometheus.Metric) {
+	var (
+		count   uint64
+		sum     float64
+		buckets = make(map[float64]uint64, len(mempoolFeeBuckets))
+	)
+	for _, upper := range mempoolFeeBuckets {
+		buckets[upper] = 0
+	}
+	c.mempool.Range(context.Background(), func(tx *chain.Transaction) bool {
+		fee := float64(tx.MaxFee())
+		count++
+		sum += fee
+		for _, upper := range mempoolFeeBuckets {
+			if fee <= upper {
+				buckets[upper]++
+			}
+		}
+		return true
+	})
+	ch <- prometheus.MustNewConstHistogram(c.desc, count, sum, buckets)
+}
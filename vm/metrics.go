@@ -6,13 +6,18 @@ package vm
 import (
 	"github.com/ava-labs/avalanchego/utils/metric"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/executor"
+	"github.com/ava-labs/hypersdk/workers"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type executorMetrics struct {
-	blocked    prometheus.Counter
-	executable prometheus.Counter
+	blocked     prometheus.Counter
+	executable  prometheus.Counter
+	conflicts   prometheus.Counter
+	chainLength metric.Averager
+	parallelism metric.Averager
 }
 
 func (em *executorMetrics) RecordBlocked() {
@@ -23,42 +28,94 @@ func (em *executorMetrics) RecordExecutable() {
 	em.executable.Inc()
 }
 
+func (em *executorMetrics) RecordConflicts(n int) {
+	em.conflicts.Add(float64(n))
+}
+
+func (em *executorMetrics) RecordChainLength(l float64) {
+	em.chainLength.Observe(l)
+}
+
+func (em *executorMetrics) RecordEffectiveParallelism(p float64) {
+	em.parallelism.Observe(p)
+}
+
+type workerMetrics struct {
+	workers prometheus.Gauge
+	busy    prometheus.Gauge
+}
+
+func (wm *workerMetrics) RecordWorkers(n int) {
+	wm.workers.Set(float64(n))
+}
+
+func (wm *workerMetrics) RecordBusy(n int) {
+	wm.busy.Set(float64(n))
+}
+
 type Metrics struct {
-	txsSubmitted             prometheus.Counter // includes gossip
-	txsReceived              prometheus.Counter
-	seenTxsReceived          prometheus.Counter
-	txsGossiped              prometheus.Counter
-	txsVerified              prometheus.Counter
-	txsAccepted              prometheus.Counter
-	stateChanges             prometheus.Counter
-	stateOperations          prometheus.Counter
-	buildCapped              prometheus.Counter
-	emptyBlockBuilt          prometheus.Counter
-	clearedMempool           prometheus.Counter
-	deletedBlocks            prometheus.Counter
-	blocksFromDisk           prometheus.Counter
-	blocksHeightsFromDisk    prometheus.Counter
-	executorBuildBlocked     prometheus.Counter
-	executorBuildExecutable  prometheus.Counter
-	executorVerifyBlocked    prometheus.Counter
-	executorVerifyExecutable prometheus.Counter
-	mempoolSize              prometheus.Gauge
-	bandwidthPrice           prometheus.Gauge
-	computePrice             prometheus.Gauge
-	storageReadPrice         prometheus.Gauge
-	storageAllocatePrice     prometheus.Gauge
-	storageWritePrice        prometheus.Gauge
-	rootCalculated           metric.Averager
-	waitRoot                 metric.Averager
-	waitSignatures           metric.Averager
-	blockBuild               metric.Averager
-	blockParse               metric.Averager
-	blockVerify              metric.Averager
-	blockAccept              metric.Averager
-	blockProcess             metric.Averager
+	txsSubmitted              prometheus.Counter // includes gossip
+	txsCongested              prometheus.Counter
+	txsReceived               prometheus.Counter
+	seenTxsReceived           prometheus.Counter
+	txsGossiped               prometheus.Counter
+	txsFiltered               prometheus.Counter
+	txsVerified               prometheus.Counter
+	txsAccepted               prometheus.Counter
+	stateChanges              prometheus.Counter
+	stateOperations           prometheus.Counter
+	buildCapped               prometheus.Counter
+	buildAttempts             prometheus.Counter
+	blocksBuilt               prometheus.Counter
+	buildFailures             prometheus.Counter
+	emptyBlockBuilt           prometheus.Counter
+	clearedMempool            prometheus.Counter
+	fcfsDeviations            prometheus.Counter
+	accessListMismatches      prometheus.Counter
+	stateCacheHits            prometheus.Counter
+	stateCacheMisses          prometheus.Counter
+	deletedBlocks             prometheus.Counter
+	servesDropped             prometheus.Counter
+	backfilledBlocks          prometheus.Counter
+	txsFetched                prometheus.Counter
+	blocksFromDisk            prometheus.Counter
+	blocksHeightsFromDisk     prometheus.Counter
+	executorBuildBlocked      prometheus.Counter
+	executorBuildExecutable   prometheus.Counter
+	executorVerifyBlocked     prometheus.Counter
+	executorVerifyExecutable  prometheus.Counter
+	executorBuildConflicts    prometheus.Counter
+	executorVerifyConflicts   prometheus.Counter
+	txsDropped                *prometheus.CounterVec
+	mempoolSize               prometheus.Gauge
+	valueNodes                prometheus.Gauge
+	intermediateNodes         prometheus.Gauge
+	authVerifierWorkers       prometheus.Gauge
+	authVerifierBusy          prometheus.Gauge
+	bandwidthPrice            prometheus.Gauge
+	computePrice              prometheus.Gauge
+	storageReadPrice          prometheus.Gauge
+	storageAllocatePrice      prometheus.Gauge
+	storageWritePrice         prometheus.Gauge
+	rootCalculated            metric.Averager
+	waitRoot                  metric.Averager
+	waitSignatures            metric.Averager
+	blockBuild                metric.Averager
+	blockParse                metric.Averager
+	blockVerify               metric.Averager
+	blockAccept               metric.Averager
+	blockProcess              metric.Averager
+	blockCompaction           metric.Averager
+	stateCompaction           metric.Averager
+	executorBuildChain        metric.Averager
+	executorBuildParallelism  metric.Averager
+	executorVerifyChain       metric.Averager
+	executorVerifyParallelism metric.Averager
+	buildPhases               [chain.NumBuildPhases]metric.Averager
 
 	executorBuildRecorder  executor.Metrics
 	executorVerifyRecorder executor.Metrics
+	authVerifierRecorder   workers.Metrics
 }
 
 func newMetrics() (*prometheus.Registry, *Metrics, error) {
@@ -136,6 +193,72 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	blockCompaction, err := metric.NewAverager(
+		"vm",
+		"block_compaction",
+		"time spent compacting the block database",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateCompaction, err := metric.NewAverager(
+		"vm",
+		"state_compaction",
+		"time spent compacting the state database",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	executorBuildChain, err := metric.NewAverager(
+		"chain",
+		"executor_build_chain_length",
+		"average dependency chain length of txs during build",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	executorBuildParallelism, err := metric.NewAverager(
+		"chain",
+		"executor_build_effective_parallelism",
+		"txs executed per step of the longest dependency chain during build",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	executorVerifyChain, err := metric.NewAverager(
+		"chain",
+		"executor_verify_chain_length",
+		"average dependency chain length of txs during verify",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	executorVerifyParallelism, err := metric.NewAverager(
+		"chain",
+		"executor_verify_effective_parallelism",
+		"txs executed per step of the longest dependency chain during verify",
+		r,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	var buildPhases [chain.NumBuildPhases]metric.Averager
+	for phase := chain.BuildPrepare; phase < chain.NumBuildPhases; phase++ {
+		buildPhases[phase], err = metric.NewAverager(
+			"chain",
+			"build_"+phase.String(),
+			"time spent in the "+phase.String()+" phase of block building",
+			r,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
 	m := &Metrics{
 		txsSubmitted: prometheus.NewCounter(prometheus.CounterOpts{
@@ -143,6 +266,11 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "txs_submitted",
 			Help:      "number of txs submitted to vm",
 		}),
+		txsCongested: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "txs_congested",
+			Help:      "number of submitted txs with a dominant dimension over target",
+		}),
 		txsReceived: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "vm",
 			Name:      "txs_received",
@@ -158,6 +286,11 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "txs_gossiped",
 			Help:      "number of txs gossiped by vm",
 		}),
+		txsFiltered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "txs_gossip_filtered",
+			Help:      "number of times a tx wasn't gossiped to a peer because its mempool filter contained it",
+		}),
 		txsVerified: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "vm",
 			Name:      "txs_verified",
@@ -183,6 +316,21 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "build_capped",
 			Help:      "number of times build capped by target duration",
 		}),
+		buildAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "build_attempts",
+			Help:      "number of times block building was attempted",
+		}),
+		blocksBuilt: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "blocks_built",
+			Help:      "number of blocks built",
+		}),
+		buildFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "build_failures",
+			Help:      "number of block build attempts that failed (excluding those with nothing to build)",
+		}),
 		emptyBlockBuilt: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "chain",
 			Name:      "empty_block_built",
@@ -193,6 +341,41 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "cleared_mempool",
 			Help:      "number of times cleared mempool while building",
 		}),
+		fcfsDeviations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "fcfs_deviations",
+			Help:      "number of txs left out of a built block because they arrived before a tx already in it",
+		}),
+		accessListMismatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "access_list_mismatches",
+			Help:      "number of txs rejected because their access list did not include their state keys",
+		}),
+		stateCacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "state_cache_hits",
+			Help:      "number of reads of accepted state served by the state cache",
+		}),
+		stateCacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "state_cache_misses",
+			Help:      "number of reads of accepted state not served by the state cache",
+		}),
+		servesDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "serves_dropped",
+			Help:      "number of block and state sync requests from peers dropped by the serve limiter",
+		}),
+		backfilledBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "backfilled_blocks",
+			Help:      "number of blocks below the state sync target fetched from peers",
+		}),
+		txsFetched: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "txs_fetched",
+			Help:      "number of txs fetched from peers by id",
+		}),
 		deletedBlocks: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "vm",
 			Name:      "deleted_blocks",
@@ -223,16 +406,51 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "executor_verify_blocked",
 			Help:      "executor tasks blocked during verify",
 		}),
+		executorBuildConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "executor_build_conflicts",
+			Help:      "executor keys shared with unexecuted tasks during build",
+		}),
+		executorVerifyConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "executor_verify_conflicts",
+			Help:      "executor keys shared with unexecuted tasks during verify",
+		}),
 		executorVerifyExecutable: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "chain",
 			Name:      "executor_verify_executable",
 			Help:      "executor tasks executable during verify",
 		}),
+		txsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "txs_dropped",
+			Help:      "number of txs dropped from the mempool (or never added to it) by reason",
+		}, []string{"reason"}),
 		mempoolSize: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: "chain",
 			Name:      "mempool_size",
 			Help:      "number of transactions in the mempool",
 		}),
+		valueNodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "vm",
+			Name:      "value_nodes",
+			Help:      "number of merkledb nodes with values on-disk",
+		}),
+		intermediateNodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "vm",
+			Name:      "intermediate_nodes",
+			Help:      "number of merkledb nodes without values on-disk",
+		}),
+		authVerifierWorkers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "chain",
+			Name:      "auth_verifier_workers",
+			Help:      "number of goroutines in the signature verification pool",
+		}),
+		authVerifierBusy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "chain",
+			Name:      "auth_verifier_busy",
+			Help:      "number of goroutines verifying signatures",
+		}),
 		bandwidthPrice: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: "chain",
 			Name:      "bandwidth_price",
@@ -258,32 +476,70 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "storage_modify_price",
 			Help:      "unit price of storage modifications",
 		}),
-		rootCalculated: rootCalculated,
-		waitRoot:       waitRoot,
-		waitSignatures: waitSignatures,
-		blockBuild:     blockBuild,
-		blockParse:     blockParse,
-		blockVerify:    blockVerify,
-		blockAccept:    blockAccept,
-		blockProcess:   blockProcess,
+		rootCalculated:  rootCalculated,
+		waitRoot:        waitRoot,
+		waitSignatures:  waitSignatures,
+		blockBuild:      blockBuild,
+		blockParse:      blockParse,
+		blockVerify:     blockVerify,
+		blockAccept:     blockAccept,
+		blockProcess:    blockProcess,
+		blockCompaction: blockCompaction,
+		stateCompaction: stateCompaction,
+
+		executorBuildChain:        executorBuildChain,
+		executorBuildParallelism:  executorBuildParallelism,
+		executorVerifyChain:       executorVerifyChain,
+		executorVerifyParallelism: executorVerifyParallelism,
+		buildPhases:               buildPhases,
+	}
+	m.executorBuildRecorder = &executorMetrics{
+		blocked:     m.executorBuildBlocked,
+		executable:  m.executorBuildExecutable,
+		conflicts:   m.executorBuildConflicts,
+		chainLength: m.executorBuildChain,
+		parallelism: m.executorBuildParallelism,
+	}
+	m.executorVerifyRecorder = &executorMetrics{
+		blocked:     m.executorVerifyBlocked,
+		executable:  m.executorVerifyExecutable,
+		conflicts:   m.executorVerifyConflicts,
+		chainLength: m.executorVerifyChain,
+		parallelism: m.executorVerifyParallelism,
 	}
-	m.executorBuildRecorder = &executorMetrics{blocked: m.executorBuildBlocked, executable: m.executorBuildExecutable}
-	m.executorVerifyRecorder = &executorMetrics{blocked: m.executorVerifyBlocked, executable: m.executorVerifyExecutable}
+	m.authVerifierRecorder = &workerMetrics{workers: m.authVerifierWorkers, busy: m.authVerifierBusy}
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		r.Register(m.txsSubmitted),
+		r.Register(m.txsCongested),
 		r.Register(m.txsReceived),
 		r.Register(m.seenTxsReceived),
 		r.Register(m.txsGossiped),
+		r.Register(m.txsFiltered),
 		r.Register(m.txsVerified),
 		r.Register(m.txsAccepted),
 		r.Register(m.stateChanges),
 		r.Register(m.stateOperations),
 		r.Register(m.mempoolSize),
+		r.Register(m.valueNodes),
+		r.Register(m.intermediateNodes),
+		r.Register(m.authVerifierWorkers),
+		r.Register(m.authVerifierBusy),
 		r.Register(m.buildCapped),
+		r.Register(m.buildAttempts),
+		r.Register(m.blocksBuilt),
+		r.Register(m.buildFailures),
+		r.Register(m.txsDropped),
 		r.Register(m.emptyBlockBuilt),
 		r.Register(m.clearedMempool),
+		r.Register(m.fcfsDeviations),
+		r.Register(m.accessListMismatches),
+		r.Register(m.stateCacheHits),
+		r.Register(m.stateCacheMisses),
+		r.Register(m.servesDropped),
+		r.Register(m.backfilledBlocks),
+		r.Register(m.txsFetched),
 		r.Register(m.deletedBlocks),
 		r.Register(m.blocksFromDisk),
 		r.Register(m.blocksHeightsFromDisk),
@@ -291,6 +547,8 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 		r.Register(m.executorBuildExecutable),
 		r.Register(m.executorVerifyBlocked),
 		r.Register(m.executorVerifyExecutable),
+		r.Register(m.executorBuildConflicts),
+		r.Register(m.executorVerifyConflicts),
 		r.Register(m.bandwidthPrice),
 		r.Register(m.computePrice),
 		r.Register(m.storageReadPrice),
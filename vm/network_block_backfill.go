@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+type BlockBackfillHandler struct {
+	vm *VM
+}
+
+func NewBlockBackfillHandler(vm *VM) *BlockBackfillHandler {
+	return &BlockBackfillHandler{vm}
+}
+
+func (b *BlockBackfillHandler) Connected(
+	_ context.Context,
+	nodeID ids.NodeID,
+	_ *version.Application,
+) error {
+	b.vm.blockBackfiller.Connected(nodeID)
+	return nil
+}
+
+func (b *BlockBackfillHandler) Disconnected(_ context.Context, nodeID ids.NodeID) error {
+	b.vm.blockBackfiller.Disconnected(nodeID)
+	return nil
+}
+
+func (*BlockBackfillHandler) AppGossip(context.Context, ids.NodeID, []byte) error {
+	return nil
+}
+
+func (b *BlockBackfillHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	_ time.Time,
+	request []byte,
+) error {
+	return b.vm.blockBackfiller.Serve(ctx, nodeID, requestID, request)
+}
+
+func (b *BlockBackfillHandler) AppRequestFailed(
+	_ context.Context,
+	_ ids.NodeID,
+	requestID uint32,
+) error {
+	b.vm.blockBackfiller.HandleResponse(requestID, nil)
+	return nil
+}
+
+func (b *BlockBackfillHandler) AppResponse(
+	_ context.Context,
+	_ ids.NodeID,
+	requestID uint32,
+	response []byte,
+) error {
+	b.vm.blockBackfiller.HandleResponse(requestID, response)
+	return nil
+}
+
+func (*BlockBackfillHandler) CrossChainAppRequest(
+	context.Context,
+	ids.ID,
+	uint32,
+	time.Time,
+	[]byte,
+) error {
+	return nil
+}
+
+func (*BlockBackfillHandler) CrossChainAppRequestFailed(context.Context, ids.ID, uint32) error {
+	return nil
+}
+
+func (*BlockBackfillHandler) CrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
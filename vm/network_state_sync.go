@@ -42,6 +42,13 @@ func (s *StateSyncHandler) AppRequest(
 	deadline time.Time,
 	request []byte,
 ) error {
+	if !s.vm.serveLimiter.Acquire() {
+		// The peer will retry with another node once the request times out
+		s.vm.metrics.servesDropped.Inc()
+		return nil
+	}
+	defer s.vm.serveLimiter.Release()
+
 	if delay := s.vm.config.GetStateSyncServerDelay(); delay > 0 {
 		time.Sleep(delay)
 	}
@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+type TxFetchHandler struct {
+	vm *VM
+}
+
+func NewTxFetchHandler(vm *VM) *TxFetchHandler {
+	return &TxFetchHandler{vm}
+}
+
+func (f *TxFetchHandler) Connected(
+	_ context.Context,
+	nodeID ids.NodeID,
+	_ *version.Application,
+) error {
+	f.vm.txFetcher.Connected(nodeID)
+	return nil
+}
+
+func (f *TxFetchHandler) Disconnected(_ context.Context, nodeID ids.NodeID) error {
+	f.vm.txFetcher.Disconnected(nodeID)
+	return nil
+}
+
+func (*TxFetchHandler) AppGossip(context.Context, ids.NodeID, []byte) error {
+	return nil
+}
+
+func (f *TxFetchHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	_ time.Time,
+	request []byte,
+) error {
+	return f.vm.txFetcher.Serve(ctx, nodeID, requestID, request)
+}
+
+func (f *TxFetchHandler) AppRequestFailed(
+	_ context.Context,
+	_ ids.NodeID,
+	requestID uint32,
+) error {
+	f.vm.txFetcher.HandleResponse(requestID, nil)
+	return nil
+}
+
+func (f *TxFetchHandler) AppResponse(
+	_ context.Context,
+	_ ids.NodeID,
+	requestID uint32,
+	response []byte,
+) error {
+	f.vm.txFetcher.HandleResponse(requestID, response)
+	return nil
+}
+
+func (*TxFetchHandler) CrossChainAppRequest(
+	context.Context,
+	ids.ID,
+	uint32,
+	time.Time,
+	[]byte,
+) error {
+	return nil
+}
+
+func (*TxFetchHandler) CrossChainAppRequestFailed(context.Context, ids.ID, uint32) error {
+	return nil
+}
+
+func (*TxFetchHandler) CrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/version"
 	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/gossiper"
 )
 
 type TxGossipHandler struct {
@@ -30,7 +32,7 @@ func (*TxGossipHandler) Disconnected(context.Context, ids.NodeID) error {
 
 func (t *TxGossipHandler) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
 	if !t.vm.isReady() {
-		t.vm.snowCtx.Log.Warn("handle app gossip failed", zap.Error(ErrNotReady))
+		t.vm.Logger().Warn("handle app gossip failed", zap.Error(ErrNotReady))
 		return nil
 	}
 
@@ -81,3 +83,24 @@ func (*TxGossipHandler) CrossChainAppRequestFailed(context.Context, ids.ID, uint
 func (*TxGossipHandler) CrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
 	return nil
 }
+
+// TxFilterHandler receives the mempool filters sent by peers (see
+// [gossiper.FilterGossiper]). Otherwise, it behaves like [TxGossipHandler].
+type TxFilterHandler struct {
+	*TxGossipHandler
+}
+
+func NewTxFilterHandler(vm *VM) *TxFilterHandler {
+	return &TxFilterHandler{NewTxGossipHandler(vm)}
+}
+
+func (t *TxFilterHandler) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	if !t.vm.isReady() {
+		return nil
+	}
+	fg, ok := t.vm.gossiper.(gossiper.FilterGossiper)
+	if !ok {
+		return nil
+	}
+	return fg.HandleFilter(ctx, nodeID, msg)
+}
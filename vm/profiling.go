@@ -0,0 +1,67 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/profiler"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/profiles"
+)
+
+// Profiles can be used to capture profiles of the running node on demand.
+func (vm *VM) Profiles() *profiles.Manager {
+	return vm.profiles
+}
+
+// StartContinuousProfiler periodically writes CPU, memory, and lock profiles
+// to [dir], keeping at most [maxNumFiles] of each. If the continuous profiler
+// is already running, it is restarted with the new parameters.
+func (vm *VM) StartContinuousProfiler(dir string, freq time.Duration, maxNumFiles int) error {
+	if len(dir) == 0 || freq <= 0 || maxNumFiles <= 0 {
+		return ErrInvalidProfiler
+	}
+
+	vm.profilerL.Lock()
+	defer vm.profilerL.Unlock()
+
+	if vm.profiler != nil {
+		vm.profiler.Shutdown()
+	}
+	p := profiler.NewContinuous(dir, freq, maxNumFiles)
+	go func() {
+		if err := p.Dispatch(); err != nil {
+			vm.Logger().Warn("continuous profiler stopped", zap.Error(err))
+		}
+	}()
+	vm.profiler = p
+	vm.Logger().Info("started continuous profiler",
+		zap.String("dir", dir),
+		zap.Duration("freq", freq),
+		zap.Int("max files", maxNumFiles),
+	)
+	return nil
+}
+
+func (vm *VM) StopContinuousProfiler() error {
+	if !vm.stopContinuousProfiler() {
+		return ErrProfilerNotRunning
+	}
+	vm.Logger().Info("stopped continuous profiler")
+	return nil
+}
+
+func (vm *VM) stopContinuousProfiler() bool {
+	vm.profilerL.Lock()
+	defer vm.profilerL.Unlock()
+
+	if vm.profiler == nil {
+		return false
+	}
+	vm.profiler.Shutdown()
+	vm.profiler = nil
+	return true
+}
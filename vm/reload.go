@@ -0,0 +1,74 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	htrace "github.com/ava-labs/hypersdk/trace"
+)
+
+// ReloadConfig updates the operational parameters of the VM from [b] without
+// restarting the node. Only the following values take effect:
+//
+//   - Gossip: target gossip duration
+//   - Mempool: max size and max sponsor size
+//   - Tracing: sample rate
+//   - Logging: default level (modules with an overridden level keep it)
+//   - Streaming: max connections, max connections per IP, and auth tokens
+//
+// Returns [ErrConfigNotReloadable] if the [Config] returned by the
+// [Controller] does not implement [ReloadableConfig].
+func (vm *VM) ReloadConfig(b []byte) error {
+	config, ok := vm.config.(ReloadableConfig)
+	if !ok {
+		return ErrConfigNotReloadable
+	}
+	if err := config.Reload(b); err != nil {
+		return err
+	}
+
+	// The target gossip duration is read from [vm.config] whenever it is
+	// used, so it does not need to be applied.
+	vm.mempool.SetLimits(vm.config.GetMempoolSize(), vm.config.GetMempoolSponsorSize())
+	htrace.SetSampleRate(vm.tracer, vm.config.GetTraceConfig().TraceSampleRate)
+	vm.logs.SetDefaultLevel(vm.config.GetLogLevel())
+	if vm.pubsubServer != nil {
+		vm.pubsubServer.SetLimits(
+			vm.config.GetStreamingMaxConnections(),
+			vm.config.GetStreamingMaxConnectionsPerIP(),
+			vm.config.GetStreamingAuthTokens(),
+		)
+	}
+	vm.Logger().Info("reloaded config")
+	return nil
+}
+
+// reloadOnSignal reloads the config from [file] whenever the process
+// receives SIGHUP.
+func (vm *VM) reloadOnSignal(file string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-signals:
+		case <-vm.stop:
+			return
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			vm.Logger().Warn("unable to read config", zap.String("file", file), zap.Error(err))
+			continue
+		}
+		if err := vm.ReloadConfig(b); err != nil {
+			vm.Logger().Warn("unable to reload config", zap.String("file", file), zap.Error(err))
+		}
+	}
+}
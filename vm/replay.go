@@ -0,0 +1,204 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	smblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// ReplayedBlock is the outcome of re-executing a block during [Replay].
+type ReplayedBlock struct {
+	Block *chain.StatelessBlock
+
+	// Root is the post-execution state root of [Block].
+	Root       ids.ID
+	Results    []*chain.Result
+	UnitPrices chain.Dimensions
+
+	Verify time.Duration
+	Accept time.Duration
+
+	// Trace is only recorded if [Replay] is called with a context returned by
+	// [chain.WithBlockTrace].
+	Trace *chain.BlockTrace
+}
+
+// Replay re-executes the blocks stored in [source] (the block database of
+// another node running the same chain) on top of the last accepted block of
+// [vm], which is usually a fresh VM that has only processed genesis.
+//
+// Each block is verified and accepted as it would be during bootstrapping, so
+// the state root committed to by every block is checked against the root
+// computed locally. If [source] also stores the results of a block, they must
+// match the results produced by re-execution.
+//
+// Blocks below [start] are executed to reconstruct state but are not passed
+// to [f]. Blocks must be available in [source] from the height after the last
+// accepted block of [vm] to [end].
+func (vm *VM) Replay(
+	ctx context.Context,
+	source database.KeyValueReader,
+	start uint64,
+	end uint64,
+	f func(*ReplayedBlock) error,
+) error {
+	parent := vm.LastAcceptedBlock()
+	if start <= parent.Hght || end < start {
+		return fmt.Errorf("%w: start=%d end=%d last accepted=%d", ErrInvalidReplayRange, start, end, parent.Hght)
+	}
+
+	// Warp messages are never re-verified during replay (the VM is not
+	// bootstrapped), so the context is only needed to satisfy [VerifyWithContext].
+	//
+	// Validator snapshots are taken at P-Chain height 0, so blocks that take
+	// them will not match their original execution.
+	bctx := &smblock.Context{}
+	for height := parent.Hght + 1; height <= end; height++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		raw, err := source.Get(PrefixBlockKey(height))
+		if errors.Is(err, database.ErrNotFound) {
+			return fmt.Errorf("%w: height=%d", ErrMissingReplayBlock, height)
+		}
+		if err != nil {
+			return err
+		}
+		blk, err := chain.ParseBlock(ctx, raw, choices.Processing, vm)
+		if err != nil {
+			return fmt.Errorf("%w: unable to parse block %d", err, height)
+		}
+		if blk.Parent() != parent.ID() {
+			return fmt.Errorf("%w: block %d does not extend %s", ErrReplayDiverged, height, parent.ID())
+		}
+
+		// Execute block
+		verifyStart := time.Now()
+		withContext, err := blk.ShouldVerifyWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		if withContext {
+			err = blk.VerifyWithContext(ctx, bctx)
+		} else {
+			err = blk.Verify(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: unable to verify block %d", err, height)
+		}
+		verifyDur := time.Since(verifyStart)
+		acceptStart := time.Now()
+		if err := blk.Accept(ctx); err != nil {
+			return fmt.Errorf("%w: unable to accept block %d", err, height)
+		}
+		acceptDur := time.Since(acceptStart)
+		root, err := vm.stateDB.GetMerkleRoot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := compareBlockResults(source, blk); err != nil {
+			return err
+		}
+		parent = blk
+
+		if height < start {
+			continue
+		}
+		if err := f(&ReplayedBlock{
+			Block:      blk,
+			Root:       root,
+			Results:    blk.Results(),
+			UnitPrices: blk.FeeManager().UnitPrices(),
+			Verify:     verifyDur,
+			Accept:     acceptDur,
+			Trace:      blk.Trace(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The post-execution root of [end] is only verified when its child is
+	// processed, so we check it against the next block (if stored).
+	next, err := source.Get(PrefixBlockKey(end + 1))
+	if errors.Is(err, database.ErrNotFound) {
+		vm.Logger().Info("unable to verify final state root", zap.Uint64("height", end))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	nextBlk, err := chain.UnmarshalBlock(next, vm)
+	if err != nil {
+		return err
+	}
+	root, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if nextBlk.StateRoot != root {
+		return fmt.Errorf(
+			"%w: expected=%s found=%s",
+			chain.ErrStateRootMismatch,
+			root,
+			nextBlk.StateRoot,
+		)
+	}
+	return nil
+}
+
+// compareBlockResults returns an error if [source] stores results for [blk]
+// that differ from the results produced by re-execution. Blocks accepted
+// during state sync do not have results, so they are skipped.
+func compareBlockResults(source database.KeyValueReader, blk *chain.StatelessBlock) error {
+	results, prices, err := getBlockResults(source, blk.Hght)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if computed := blk.FeeManager().UnitPrices(); prices != computed {
+		return fmt.Errorf("%w: block %d unit prices expected=%v found=%v", ErrReplayDiverged, blk.Hght, computed, prices)
+	}
+	computed := blk.Results()
+	if len(results) != len(computed) {
+		return fmt.Errorf("%w: block %d results expected=%d found=%d", ErrReplayDiverged, blk.Hght, len(computed), len(results))
+	}
+	for i, result := range results {
+		expected, err := marshalResult(computed[i])
+		if err != nil {
+			return err
+		}
+		found, err := marshalResult(result)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(expected, found) {
+			return fmt.Errorf("%w: block %d result of tx %s", ErrReplayDiverged, blk.Hght, blk.Txs[i].ID())
+		}
+	}
+	return nil
+}
+
+func marshalResult(result *chain.Result) ([]byte, error) {
+	p := codec.NewWriter(result.Size(), consts.MaxInt)
+	if err := result.Marshal(p); err != nil {
+		return nil, err
+	}
+	return p.Bytes(), p.Err()
+}
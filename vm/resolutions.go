@@ -5,8 +5,11 @@ package vm
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
@@ -14,14 +17,20 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/executor"
 	"github.com/ava-labs/hypersdk/gossiper"
+	"github.com/ava-labs/hypersdk/logs"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/slo"
+	"github.com/ava-labs/hypersdk/statesync"
 	"github.com/ava-labs/hypersdk/workers"
 )
 
@@ -32,6 +41,7 @@ var (
 	_ block.ChainVM                      = (*VM)(nil)
 	_ block.StateSyncableVM              = (*VM)(nil)
 	_ block.BuildBlockWithContextChainVM = (*VM)(nil)
+	_ chain.OutputTypeRegistry           = (*VM)(nil)
 )
 
 func (vm *VM) ChainID() ids.ID {
@@ -62,14 +72,39 @@ func (vm *VM) Tracer() trace.Tracer {
 	return vm.tracer
 }
 
+// Logger returns the logger of the [logs.VM] module.
 func (vm *VM) Logger() logging.Logger {
-	return vm.snowCtx.Log
+	return vm.logs.Get(logs.VM)
+}
+
+// ModuleLogger returns the logger of [module], whose level can be changed at
+// runtime with [VM.Logs].
+func (vm *VM) ModuleLogger(module string) logging.Logger {
+	return vm.logs.Get(module)
+}
+
+// Logs manages the level of each module of the VM.
+func (vm *VM) Logs() *logs.Manager {
+	return vm.logs
 }
 
 func (vm *VM) Rules(t int64) chain.Rules {
 	return vm.c.Rules(t)
 }
 
+// acceptedRules returns the [chain.Rules] at [t] with the
+// [chain.RulesOverrides] in effect after the last accepted block (or without
+// them if they can't be read), so that the validity window matches the one
+// used to verify blocks.
+func (vm *VM) acceptedRules(ctx context.Context, t int64) chain.Rules {
+	r, err := chain.StateRules(ctx, vm, vm.StateManager(), vm.stateDB, t)
+	if err != nil {
+		vm.Logger().Warn("unable to read rules overrides", zap.Error(err))
+		return vm.Rules(t)
+	}
+	return r
+}
+
 func (vm *VM) LastAcceptedBlock() *chain.StatelessBlock {
 	return vm.lastAccepted
 }
@@ -86,6 +121,10 @@ func (vm *VM) State() (merkledb.MerkleDB, error) {
 	return vm.stateDB, nil
 }
 
+func (vm *VM) StateCache() *chain.StateCache {
+	return vm.stateCache
+}
+
 func (vm *VM) Mempool() chain.Mempool {
 	return vm.mempool
 }
@@ -94,7 +133,11 @@ func (vm *VM) IsRepeat(ctx context.Context, txs []*chain.Transaction, marker set
 	_, span := vm.tracer.Start(ctx, "VM.IsRepeat")
 	defer span.End()
 
-	return vm.seen.Contains(txs, marker, stop)
+	marker = vm.seen.Contains(txs, marker, stop)
+	if stop && marker.Len() > 0 {
+		return marker
+	}
+	return vm.seenHeights.Contains(txs, marker, stop)
 }
 
 func (vm *VM) Verified(ctx context.Context, b *chain.StatelessBlock) {
@@ -112,7 +155,7 @@ func (vm *VM) Verified(ctx context.Context, b *chain.StatelessBlock) {
 
 	if b.Processed() {
 		fm := b.FeeManager()
-		vm.snowCtx.Log.Info(
+		vm.Logger().Info(
 			"verified block",
 			zap.Stringer("blkID", b.ID()),
 			zap.Uint64("height", b.Hght),
@@ -122,10 +165,13 @@ func (vm *VM) Verified(ctx context.Context, b *chain.StatelessBlock) {
 			zap.Any("unit prices", fm.UnitPrices()),
 			zap.Any("units consumed", fm.UnitsConsumed()),
 		)
+		if err := vm.webSocketServer.VerifyBlock(b); err != nil {
+			vm.Logger().Warn("unable to publish verified block", zap.Error(err))
+		}
 	} else {
 		// [b.FeeManager] is not populated if the block
 		// has not been processed.
-		vm.snowCtx.Log.Info(
+		vm.Logger().Info(
 			"skipped block verification",
 			zap.Stringer("blkID", b.ID()),
 			zap.Uint64("height", b.Hght),
@@ -143,15 +189,49 @@ func (vm *VM) Rejected(ctx context.Context, b *chain.StatelessBlock) {
 	vm.verifiedL.Lock()
 	delete(vm.verifiedBlocks, b.ID())
 	vm.verifiedL.Unlock()
-	vm.mempool.Add(ctx, b.Txs)
+
+	// Bundled transactions are only added back as part of their bundle, so
+	// that they are never included on their own
+	txs := b.Txs
+	if bundles := b.Bundles(); len(bundles) > 0 {
+		for _, bundle := range bundles {
+			txs = txs[len(bundle.Txs):]
+		}
+		vm.bundles.Add(ctx, bundles)
+	}
+	// Transactions we only saw in [b] are considered received now
+	now := time.Now().UnixMilli()
+	for _, tx := range txs {
+		if tx.Arrival() == 0 {
+			tx.SetArrival(now)
+		}
+	}
+	vm.mempool.Add(ctx, txs)
+	if err := vm.webSocketServer.AddMempoolTxs(txs); err != nil {
+		vm.Logger().Warn("unable to publish mempool txs", zap.Error(err))
+	}
 
 	if err := vm.c.Rejected(ctx, b); err != nil {
 		vm.Fatal("rejected processing failed", zap.Error(err))
 	}
+	if err := vm.webSocketServer.RejectBlock(b, vm.rejectionReason(ctx, b)); err != nil {
+		vm.Logger().Warn("unable to publish rejected block", zap.Error(err))
+	}
 
 	// Ensure children of block are cleared, they may never be
 	// verified
-	vm.snowCtx.Log.Info("rejected block", zap.Stringer("id", b.ID()))
+	vm.Logger().Info("rejected block", zap.Stringer("id", b.ID()))
+}
+
+// rejectionReason describes why consensus rejected [b]. Blocks are either
+// rejected because a sibling was accepted at the same height or because one
+// of their ancestors was rejected.
+func (vm *VM) rejectionReason(ctx context.Context, b *chain.StatelessBlock) string {
+	blkID, err := vm.GetBlockIDAtHeight(ctx, b.Hght)
+	if err == nil && blkID != b.ID() {
+		return fmt.Sprintf("conflicts with accepted block %s", blkID)
+	}
+	return "ancestor rejected"
 }
 
 func (vm *VM) processAcceptedBlock(b *chain.StatelessBlock) {
@@ -166,7 +246,7 @@ func (vm *VM) processAcceptedBlock(b *chain.StatelessBlock) {
 	// We don't need to worry about dangling messages in listeners because we
 	// don't allow subscription until the node is healthy.
 	if !b.Processed() {
-		vm.snowCtx.Log.Info("skipping unprocessed block", zap.Uint64("height", b.Hght))
+		vm.Logger().Info("skipping unprocessed block", zap.Uint64("height", b.Hght))
 		return
 	}
 
@@ -174,15 +254,31 @@ func (vm *VM) processAcceptedBlock(b *chain.StatelessBlock) {
 	if err := vm.c.Accepted(context.TODO(), b); err != nil {
 		vm.Fatal("accepted processing failed", zap.Error(err))
 	}
+	if c, ok := vm.c.(BurnController); ok {
+		if err := c.Burned(context.TODO(), b, b.Burned()); err != nil {
+			vm.Fatal("burn processing failed", zap.Error(err))
+		}
+	}
+	if c, ok := vm.c.(RewardController); ok {
+		if recipient, amount := b.Reward(); amount > 0 {
+			if err := c.Rewarded(context.TODO(), b, recipient, amount); err != nil {
+				vm.Fatal("reward processing failed", zap.Error(err))
+			}
+		}
+	}
 
 	// Sign and store any warp messages (regardless if validator now, may become one)
+	//
+	// API nodes never sign warp messages because they are not expected to ever
+	// become validators.
+	apiNode := vm.config.GetAPINode()
 	results := b.Results()
 	for i, tx := range b.Txs {
 		// Only cache auth for accepted blocks to prevent cache manipulation from RPC submissions
 		vm.cacheAuth(tx.Auth)
 
 		result := results[i]
-		if result.WarpMessage == nil {
+		if result.WarpMessage == nil || apiNode {
 			continue
 		}
 		start := time.Now()
@@ -193,7 +289,7 @@ func (vm *VM) processAcceptedBlock(b *chain.StatelessBlock) {
 		if err := vm.StoreWarpSignature(tx.ID(), vm.snowCtx.PublicKey, signature); err != nil {
 			vm.Fatal("unable to store warp signature", zap.Error(err))
 		}
-		vm.snowCtx.Log.Info(
+		vm.Logger().Info(
 			"signed and stored warp message signature",
 			zap.Stringer("txID", tx.ID()),
 			zap.Duration("t", time.Since(start)),
@@ -207,13 +303,38 @@ func (vm *VM) processAcceptedBlock(b *chain.StatelessBlock) {
 		vm.warpManager.GatherSignatures(context.TODO(), tx.ID(), result.WarpMessage.Bytes())
 	}
 
+	// Persist any validator snapshot taken by the block and sign the message
+	// attesting to it (like the warp messages of transactions)
+	if snapshot, msg := b.ValidatorSnapshot(); snapshot != nil {
+		if err := vm.PutValidatorSnapshot(snapshot); err != nil {
+			vm.Fatal("unable to store validator snapshot", zap.Error(err))
+		}
+		if !apiNode {
+			snapshotID := chain.ValidatorSnapshotID(snapshot.Epoch)
+			signature, err := vm.snowCtx.WarpSigner.Sign(msg)
+			if err != nil {
+				vm.Fatal("unable to sign validator snapshot", zap.Error(err))
+			}
+			if err := vm.StoreWarpSignature(snapshotID, vm.snowCtx.PublicKey, signature); err != nil {
+				vm.Fatal("unable to store warp signature", zap.Error(err))
+			}
+			vm.Logger().Info(
+				"signed validator snapshot",
+				zap.Uint64("epoch", snapshot.Epoch),
+				zap.Uint64("pChainHeight", snapshot.PChainHeight),
+				zap.Int("validators", len(snapshot.Validators)),
+			)
+			vm.warpManager.GatherSignatures(context.TODO(), snapshotID, msg.Bytes())
+		}
+	}
+
 	// Update server
 	if err := vm.webSocketServer.AcceptBlock(b); err != nil {
 		vm.Fatal("unable to accept block in websocket server", zap.Error(err))
 	}
 	// Must clear accepted txs before [SetMinTx] or else we will errnoueously
 	// send [ErrExpired] messages.
-	if err := vm.webSocketServer.SetMinTx(b.Tmstmp); err != nil {
+	if err := vm.webSocketServer.SetMinTx(b.Tmstmp, b.Hght); err != nil {
 		vm.Fatal("unable to set min tx in websocket server", zap.Error(err))
 	}
 
@@ -230,7 +351,7 @@ func (vm *VM) processAcceptedBlocks() {
 	// Always close [acceptorDone] or we may block shutdown.
 	defer func() {
 		close(vm.acceptorDone)
-		vm.snowCtx.Log.Info("acceptor queue shutdown")
+		vm.Logger().Info("acceptor queue shutdown")
 	}()
 
 	// The VM closes [acceptedQueue] during shutdown. We wait for all enqueued blocks
@@ -239,7 +360,7 @@ func (vm *VM) processAcceptedBlocks() {
 	// closed.
 	for b := range vm.acceptedQueue {
 		vm.processAcceptedBlock(b)
-		vm.snowCtx.Log.Info(
+		vm.Logger().Info(
 			"block processed",
 			zap.Stringer("blkID", b.ID()),
 			zap.Uint64("height", b.Hght),
@@ -272,11 +393,14 @@ func (vm *VM) Accepted(ctx context.Context, b *chain.StatelessBlock) {
 	// transform [blkTime] when calling [SetMin] here.
 	blkTime := b.Tmstmp
 	evicted := vm.seen.SetMin(blkTime)
+	evicted = append(evicted, vm.seenHeights.SetMin(int64(b.Hght))...)
 	vm.Logger().Debug("txs evicted from seen", zap.Int("len", len(evicted)))
 	vm.seen.Add(b.Txs)
+	vm.seenHeights.Add(b.Txs)
 
-	// Verify if emap is now sufficient (we need a consecutive run of blocks with
-	// timestamps of at least [ValidityWindow] for this to occur).
+	// Verify if emap is now sufficient (we need a consecutive run of blocks
+	// spanning at least [ValidityWindow] and [HeightValidityWindow] for this to
+	// occur).
 	if !vm.isReady() {
 		select {
 		case <-vm.seenValidityWindow:
@@ -287,9 +411,11 @@ func (vm *VM) Accepted(ctx context.Context, b *chain.StatelessBlock) {
 			// performing state sync.
 			if vm.startSeenTime < 0 {
 				vm.startSeenTime = blkTime
+				vm.startSeenHeight = b.Hght
 			}
-			r := vm.Rules(blkTime)
-			if blkTime-vm.startSeenTime > r.GetValidityWindow() {
+			r := vm.acceptedRules(ctx, blkTime)
+			if blkTime-vm.startSeenTime > r.GetValidityWindow() &&
+				b.Hght-vm.startSeenHeight > r.GetHeightValidityWindow() {
 				vm.seenValidityWindowOnce.Do(func() {
 					close(vm.seenValidityWindow)
 				})
@@ -297,17 +423,24 @@ func (vm *VM) Accepted(ctx context.Context, b *chain.StatelessBlock) {
 		}
 	}
 
-	// Update timestamp in mempool
+	// Update timestamp and height in mempool
 	//
 	// We rely on the [vm.waiters] map to notify listeners of dropped
 	// transactions instead of the mempool because we won't need to iterate
 	// through as many transactions.
 	removed := vm.mempool.SetMinTimestamp(ctx, blkTime)
+	removed = append(removed, vm.mempool.SetMinHeight(ctx, int64(b.Hght))...)
+	if len(removed) > 0 {
+		vm.RecordTxsDropped(chain.DropExpired, len(removed))
+	}
+	if vm.bundles != nil {
+		vm.bundles.SetMinTimestamp(ctx, blkTime)
+	}
 
 	// Enqueue block for processing
 	vm.acceptedQueue <- b
 
-	vm.snowCtx.Log.Info(
+	vm.Logger().Info(
 		"accepted block",
 		zap.Stringer("blkID", b.ID()),
 		zap.Uint64("height", b.Hght),
@@ -337,6 +470,14 @@ func (vm *VM) GatherSignatures(ctx context.Context, txID ids.ID, msg []byte) {
 	vm.warpManager.GatherSignatures(ctx, txID, msg)
 }
 
+func (vm *VM) ResignWarpMessage(
+	ctx context.Context,
+	txID ids.ID,
+	msg *warp.UnsignedMessage,
+) (*chain.WarpSignature, int, error) {
+	return vm.warpManager.ResignMessage(ctx, txID, msg)
+}
+
 func (vm *VM) NodeID() ids.NodeID {
 	return vm.snowCtx.NodeID
 }
@@ -421,6 +562,10 @@ func (vm *VM) RecordTxsGossiped(c int) {
 	vm.metrics.txsGossiped.Add(float64(c))
 }
 
+func (vm *VM) RecordTxsFiltered(c int) {
+	vm.metrics.txsFiltered.Add(float64(c))
+}
+
 func (vm *VM) RecordTxsReceived(c int) {
 	vm.metrics.txsReceived.Add(float64(c))
 }
@@ -461,6 +606,151 @@ func (vm *VM) cacheAuth(auth chain.Auth) {
 	bv.Cache(auth)
 }
 
+func (vm *VM) recordBlockBuild(t time.Duration, err error) {
+	vm.metrics.blockBuild.Observe(float64(t))
+	vm.metrics.buildAttempts.Inc()
+
+	// Not building a block because there is nothing to do (or because we
+	// are not ready to do it) is not a failure of block production.
+	if errors.Is(err, ErrNotReady) || errors.Is(err, chain.ErrNoTxs) || errors.Is(err, chain.ErrTimestampTooEarly) {
+		return
+	}
+	if err == nil {
+		vm.metrics.blocksBuilt.Inc()
+	} else {
+		vm.metrics.buildFailures.Inc()
+	}
+	vm.slo.Record(slo.BlockBuild, err == nil, t)
+}
+
+func (vm *VM) RecordBuildPhase(phase chain.BuildPhase, t time.Duration) {
+	vm.metrics.buildPhases[phase].Observe(float64(t))
+}
+
+func (vm *VM) RecordTxsDropped(reason string, count int) {
+	vm.metrics.txsDropped.WithLabelValues(reason).Add(float64(count))
+}
+
+// rpcClientErrors are returned when a request can't be served because of
+// the request itself (like a malformed or invalid transaction, or an unknown
+// block). Requests that fail with them don't count against the RPC service
+// level objective (only internal errors and unavailability do).
+var rpcClientErrors = []error{
+	// Requests
+	database.ErrNotFound,
+	rpc.ErrUnauthorized,
+	rpc.ErrMessageMissing,
+	rpc.ErrSnapshotMismatch,
+	rpc.ErrRequestTooLarge,
+	rpc.ErrRateLimited,
+	rpc.ErrTooManyRequests,
+	rpc.ErrInvalidBlockSelector,
+	rpc.ErrUnknownBlockTag,
+	rpc.ErrBlockNotAccepted,
+	rpc.ErrInvalidExpiry,
+	rpc.ErrTxNotExpired,
+	rpc.ErrTxIncluded,
+	rpc.ErrTooManyBlocks,
+	rpc.ErrInvalidNonInclusion,
+	rpc.ErrUnknownTxFormat,
+	rpc.ErrInvalidTxFormat,
+	rpc.ErrDuplicateStateQuery,
+	rpc.ErrUnknownStateQuery,
+	ErrBundlesDisabled,
+
+	// Parsing
+	chain.ErrInvalidObject,
+	chain.ErrBundleTooLarge,
+	codec.ErrTooManyItems,
+	codec.ErrDuplicateItem,
+	codec.ErrUnknownItem,
+	codec.ErrFieldNotPopulated,
+	codec.ErrInvalidBitset,
+	codec.ErrIncorrectHRP,
+	codec.ErrInsufficientLength,
+	codec.ErrInvalidSize,
+
+	// Transactions
+	chain.ErrInvalidChainID,
+	chain.ErrTimestampTooEarly,
+	chain.ErrTimestampTooLate,
+	chain.ErrMisalignedTime,
+	chain.ErrHeightTooEarly,
+	chain.ErrHeightTooLate,
+	chain.ErrHeightExpiryDisabled,
+	chain.ErrNoncesDisabled,
+	chain.ErrNonceRequired,
+	chain.ErrNonceTooLow,
+	chain.ErrNonceTooHigh,
+	chain.ErrTxCancelled,
+	chain.ErrInvalidSignature,
+	chain.ErrDuplicateTx,
+	chain.ErrInsufficientPrice,
+	chain.ErrInsufficientTip,
+	chain.ErrInvalidBalance,
+	chain.ErrMaxFeeAssetExceeded,
+	chain.ErrActionNotActivated,
+	chain.ErrAuthNotActivated,
+	chain.ErrActionDisabled,
+	chain.ErrActionDeprecated,
+	chain.ErrAuthDeprecated,
+	chain.ErrAuthNotAuthorized,
+	chain.ErrAuthFailed,
+	chain.ErrInvalidAccessList,
+	chain.ErrAccessListMismatch,
+	chain.ErrInvalidActor,
+	chain.ErrInvalidSponsor,
+	chain.ErrTxRejectedByHook,
+	chain.ErrDisabledChainID,
+	chain.ErrUnexpectedWarpMessage,
+	chain.ErrExpectedWarpMessage,
+	chain.ErrEmptyWarpPayload,
+	chain.ErrWarpReplay,
+	chain.ErrWarpMessageExpired,
+}
+
+// RecordRPCRequest can be provided to [rpc.NewJSONRPCHandler] to include
+// custom APIs in the RPC service level objective.
+func (vm *VM) RecordRPCRequest(_ string, err error, t time.Duration) {
+	success := err == nil
+	for _, clientErr := range rpcClientErrors {
+		if errors.Is(err, clientErr) {
+			success = true
+			break
+		}
+	}
+	vm.slo.Record(slo.RPC, success, t)
+}
+
+func (vm *VM) SLOReport() *slo.Report {
+	return vm.slo.Report()
+}
+
+func (vm *VM) StateSyncProgress() *statesync.Progress {
+	if vm.stateSyncClient == nil {
+		// Can occur in test
+		return &statesync.Progress{}
+	}
+	return vm.stateSyncClient.tracker.Progress()
+}
+
+// GetNonce returns the nonce that the next transaction sponsored by [addr]
+// must use (according to accepted state).
+func (vm *VM) GetNonce(ctx context.Context, addr codec.Address) (uint64, error) {
+	k, err := chain.NonceKey(vm.c.StateManager(), addr)
+	if err != nil {
+		return 0, err
+	}
+	values, errs := vm.ReadState(ctx, [][]byte{k})
+	if errors.Is(errs[0], database.ErrNotFound) {
+		return 0, nil
+	}
+	if errs[0] != nil {
+		return 0, errs[0]
+	}
+	return chain.ParseNonce(values[0])
+}
+
 func (vm *VM) RecordBlockVerify(t time.Duration) {
 	vm.metrics.blockVerify.Observe(float64(t))
 }
@@ -473,18 +763,131 @@ func (vm *VM) RecordClearedMempool() {
 	vm.metrics.clearedMempool.Inc()
 }
 
-func (vm *VM) UnitPrices(context.Context) (chain.Dimensions, error) {
-	v, err := vm.stateDB.Get(chain.FeeKey(vm.StateManager().FeeKey()))
+func (vm *VM) RecordFCFSDeviation() {
+	vm.metrics.fcfsDeviations.Inc()
+}
+
+func (vm *VM) RecordAccessListMismatch() {
+	vm.metrics.accessListMismatches.Inc()
+}
+
+func (vm *VM) RecordStateCacheHit() {
+	vm.metrics.stateCacheHits.Inc()
+}
+
+func (vm *VM) RecordStateCacheMiss() {
+	vm.metrics.stateCacheMisses.Inc()
+}
+
+func (vm *VM) UnitPrices(ctx context.Context) (chain.Dimensions, error) {
+	feeManager, _, err := chain.GetFeeManager(ctx, vm.StateManager(), vm.stateDB)
+	if err != nil {
+		return chain.Dimensions{}, err
+	}
+	return feeManager.UnitPrices(), nil
+}
+
+// Burned returns the total fees burned in each dimension as of the last
+// accepted block.
+func (vm *VM) Burned(context.Context) (chain.Dimensions, error) {
+	v, err := vm.stateDB.Get(chain.BurnKey(vm.StateManager().BurnKey()))
+	if errors.Is(err, database.ErrNotFound) {
+		return chain.Dimensions{}, nil
+	}
 	if err != nil {
 		return chain.Dimensions{}, err
 	}
-	return chain.NewFeeManager(v).UnitPrices(), nil
+	return chain.UnpackDimensions(v)
+}
+
+// RulesOverrides returns the [chain.RulesOverrides] in effect after the last
+// accepted block (nil if there are none).
+func (vm *VM) RulesOverrides(ctx context.Context) (*chain.RulesOverrides, error) {
+	return chain.GetRulesOverrides(ctx, vm.StateManager(), vm.stateDB)
+}
+
+// BlockResults returns the results and unit prices of an accepted block.
+//
+// If the block was recently executed by this node, these are served from
+// memory. Otherwise, they are read from disk.
+func (vm *VM) BlockResults(blk *chain.StatelessBlock) ([]*chain.Result, chain.Dimensions, error) {
+	if feeManager := blk.FeeManager(); feeManager != nil {
+		return blk.Results(), feeManager.UnitPrices(), nil
+	}
+	return vm.GetDiskBlockResults(blk.Height())
+}
+
+func (vm *VM) Beneficiary() codec.Address {
+	c, ok := vm.c.(BeneficiaryController)
+	if !ok {
+		return codec.EmptyAddress
+	}
+	addr, ok := c.Beneficiary()
+	if !ok {
+		return codec.EmptyAddress
+	}
+	return addr
+}
+
+// Hooks returns the [Controller] if it implements [chain.Hooks] (e.g. to
+// pause the chain or to only accept transactions from an allowlist).
+func (vm *VM) Hooks() chain.Hooks {
+	c, ok := vm.c.(chain.Hooks)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+// EpochHandler returns the [Controller] if it implements [chain.EpochHandler]
+// (e.g. to distribute rewards or snapshot the validator set at the end of
+// each epoch).
+func (vm *VM) EpochHandler() chain.EpochHandler {
+	c, ok := vm.c.(chain.EpochHandler)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+func (vm *VM) StateRegistry() *rpc.StateRegistry {
+	c, ok := vm.c.(StateRegistryController)
+	if !ok {
+		return nil
+	}
+	return c.StateRegistry()
+}
+
+// OutputRegistry returns the [chain.OutputRegistry] of the [Controller] (nil
+// if it doesn't implement [chain.OutputTypeRegistry]).
+func (vm *VM) OutputRegistry() *chain.OutputRegistry {
+	c, ok := vm.c.(chain.OutputTypeRegistry)
+	if !ok {
+		return nil
+	}
+	return c.OutputRegistry()
+}
+
+func (vm *VM) GetStateBranchFactor() merkledb.BranchFactor {
+	return vm.genesis.GetStateBranchFactor()
+}
+
+func (vm *VM) GetStreamingMempool() bool {
+	return vm.config.GetStreamingMempool()
 }
 
 func (vm *VM) GetTransactionExecutionCores() int {
 	return vm.config.GetTransactionExecutionCores()
 }
 
+func (vm *VM) GetFCFSOrdering() bool {
+	return vm.config.GetFCFSOrdering()
+}
+
+func (vm *VM) GetExecutionTrace() bool {
+	return vm.config.GetExecutionTrace()
+}
+
 func (vm *VM) GetExecutorBuildRecorder() executor.Metrics {
 	return vm.metrics.executorBuildRecorder
 }
@@ -0,0 +1,140 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// How many keys are walked between checks for cancellation
+const digestCheckInterval = 4_096
+
+// StateDigest is a canonical digest of every key-value pair in state at an
+// accepted root. Unlike the merkle root, it doesn't depend on how state is
+// stored, so it can be recomputed by any tool that can read state.
+//
+// The digest of a set of pairs is the SHA-256 of each pair (in key order)
+// encoded as its 4-byte big-endian key length, key, 4-byte big-endian value
+// length, and value.
+type StateDigest struct {
+	// The state is the result of executing [BlockID] at [Height] and
+	// committed to by [Root].
+	Height  uint64 `json:"height"`
+	BlockID ids.ID `json:"blockId"`
+	Root    ids.ID `json:"root"`
+
+	Digest ids.ID `json:"digest"`
+	Keys   uint64 `json:"keys"`
+	Bytes  uint64 `json:"bytes"` // of all keys and values
+
+	// Prefixes summarizes the pairs under each first byte of their key, so
+	// nodes that disagree can narrow down where.
+	Prefixes []*PrefixDigest `json:"prefixes"`
+
+	// Summaries are added by the [Controller] (see [StateSummaryController]).
+	Summaries map[string]any `json:"summaries,omitempty"`
+}
+
+type PrefixDigest struct {
+	Prefix byte   `json:"prefix"`
+	Digest ids.ID `json:"digest"`
+	Keys   uint64 `json:"keys"`
+	Bytes  uint64 `json:"bytes"`
+}
+
+// StateDigest walks all of state to compute its [StateDigest].
+//
+// State must not change while it is walked, so this should only be called on
+// a VM that is not accepting blocks (like one opened on the data of a stopped
+// node).
+func (vm *VM) StateDigest(ctx context.Context) (*StateDigest, error) {
+	vm.dbL.Lock()
+	defer vm.dbL.Unlock()
+
+	select {
+	case <-vm.stop:
+		return nil, ErrShuttingDown
+	default:
+	}
+	syncing, err := vm.GetDiskIsSyncing()
+	if err != nil {
+		return nil, err
+	}
+	if syncing {
+		return nil, ErrStateSyncing
+	}
+
+	blk := vm.LastAcceptedBlock()
+	root, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var summary StateSummary
+	if c, ok := vm.c.(StateSummaryController); ok {
+		summary = c.NewStateSummary()
+	}
+
+	var (
+		digest = &StateDigest{Height: blk.Hght, BlockID: blk.ID(), Root: root}
+		all    = sha256.New()
+		prefix *PrefixDigest
+		ph     hash.Hash
+	)
+	it := vm.stateDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if digest.Keys%digestCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		k, v := it.Key(), it.Value()
+		if prefix == nil || prefix.Prefix != k[0] {
+			if prefix != nil {
+				prefix.Digest = ids.ID(ph.Sum(nil))
+			}
+			prefix = &PrefixDigest{Prefix: k[0]}
+			ph = sha256.New()
+			digest.Prefixes = append(digest.Prefixes, prefix)
+		}
+		writeDigestPair(all, k, v)
+		writeDigestPair(ph, k, v)
+		size := uint64(len(k) + len(v))
+		digest.Keys++
+		digest.Bytes += size
+		prefix.Keys++
+		prefix.Bytes += size
+		if summary != nil {
+			if err := summary.Add(k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	if prefix != nil {
+		prefix.Digest = ids.ID(ph.Sum(nil))
+	}
+	digest.Digest = ids.ID(all.Sum(nil))
+	if summary != nil {
+		digest.Summaries = summary.Summaries()
+	}
+	return digest, nil
+}
+
+func writeDigestPair(h hash.Hash, k []byte, v []byte) {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(k)))
+	_, _ = h.Write(l[:])
+	_, _ = h.Write(k)
+	binary.BigEndian.PutUint32(l[:], uint32(len(v)))
+	_, _ = h.Write(l[:])
+	_, _ = h.Write(v)
+}
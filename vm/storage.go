@@ -21,8 +21,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/rpc"
 )
 
 // compactionOffset is used to randomize the height that we compact
@@ -40,11 +42,19 @@ const (
 	blockHeightIDPrefix = 0x2 // Height -> ID (don't always need full block from disk)
 	warpSignaturePrefix = 0x3
 	warpFetchPrefix     = 0x4
+	blockResultsPrefix  = 0x5 // Height -> UnitPrices|Results
+	mempoolPrefix       = 0x6 // TxID -> Tx (persisted on shutdown)
+	blobPrefix          = 0x7 // TxID -> Height|Blob
+	blobHeightPrefix    = 0x8 // Height -> TxIDs (of txs with blobs)
+	snapshotPrefix      = 0x9 // Epoch -> ValidatorSnapshot
+	warpMessagePrefix   = 0xa // TxID -> Height|Timestamp|BlkID|UnsignedMessage
+	warpHeightPrefix    = 0xb // Height -> TxIDs (of txs with warp messages)
 )
 
 var (
-	isSyncing    = []byte("is_syncing")
-	lastAccepted = []byte("last_accepted")
+	isSyncing     = []byte("is_syncing")
+	lastAccepted  = []byte("last_accepted")
+	blockBackfill = []byte("block_backfill") // nextID|nextHeight|floor
 
 	signatureLRU = &cache.LRU[string, *chain.WarpSignature]{Size: 1024}
 )
@@ -70,6 +80,13 @@ func PrefixBlockHeightIDKey(height uint64) []byte {
 	return k
 }
 
+func PrefixBlockResultsKey(height uint64) []byte {
+	k := make([]byte, 1+consts.Uint64Len)
+	k[0] = blockResultsPrefix
+	binary.BigEndian.PutUint64(k[1:], height)
+	return k
+}
+
 func (vm *VM) HasGenesis() (bool, error) {
 	return vm.HasDiskBlock(0)
 }
@@ -127,6 +144,26 @@ func (vm *VM) UpdateLastAccepted(blk *chain.StatelessBlock) error {
 	if err := batch.Put(PrefixBlockHeightIDKey(blk.Height()), blkID[:]); err != nil {
 		return err
 	}
+	// Blocks accepted during state sync are never executed, so we don't
+	// have any results to store for them.
+	if feeManager := blk.FeeManager(); feeManager != nil {
+		mresults, err := chain.MarshalResults(blk.Results())
+		if err != nil {
+			return err
+		}
+		v := make([]byte, 0, chain.DimensionsLen()+len(mresults))
+		v = append(v, feeManager.UnitPrices().Bytes()...)
+		v = append(v, mresults...)
+		if err := batch.Put(PrefixBlockResultsKey(blk.Height()), v); err != nil {
+			return err
+		}
+	}
+	if err := vm.putBlobs(batch, blk); err != nil {
+		return err
+	}
+	if err := vm.putWarpMessages(batch, blk); err != nil {
+		return err
+	}
 	expiryHeight := blk.Height() - uint64(vm.config.GetAcceptedBlockWindow())
 	var expired bool
 	if expiryHeight > 0 && expiryHeight < blk.Height() { // ensure we don't free genesis
@@ -144,6 +181,9 @@ func (vm *VM) UpdateLastAccepted(blk *chain.StatelessBlock) error {
 		if err := batch.Delete(PrefixBlockHeightIDKey(expiryHeight)); err != nil {
 			return err
 		}
+		if err := batch.Delete(PrefixBlockResultsKey(expiryHeight)); err != nil {
+			return err
+		}
 		expired = true
 		vm.metrics.deletedBlocks.Inc()
 		vm.Logger().Info("deleted block", zap.Uint64("height", expiryHeight))
@@ -175,6 +215,32 @@ func (vm *VM) GetDiskBlock(ctx context.Context, height uint64) (*chain.Stateless
 	return chain.ParseBlock(ctx, b, choices.Accepted, vm)
 }
 
+// GetDiskBlockResults returns the results and unit prices of the accepted block
+// at [height]. If the block was not executed by this node (i.e. it was accepted
+// during state sync), this will return [database.ErrNotFound].
+func (vm *VM) GetDiskBlockResults(height uint64) ([]*chain.Result, chain.Dimensions, error) {
+	return getBlockResults(vm.vmDB, height)
+}
+
+func getBlockResults(db database.KeyValueReader, height uint64) ([]*chain.Result, chain.Dimensions, error) {
+	v, err := db.Get(PrefixBlockResultsKey(height))
+	if err != nil {
+		return nil, chain.Dimensions{}, err
+	}
+	if len(v) < chain.DimensionsLen() {
+		return nil, chain.Dimensions{}, ErrCorruptedResults
+	}
+	prices, err := chain.UnpackDimensions(v[:chain.DimensionsLen()])
+	if err != nil {
+		return nil, chain.Dimensions{}, err
+	}
+	results, err := chain.UnmarshalResults(v[chain.DimensionsLen():])
+	if err != nil {
+		return nil, chain.Dimensions{}, err
+	}
+	return results, prices, nil
+}
+
 func (vm *VM) HasDiskBlock(height uint64) (bool, error) {
 	return vm.vmDB.Has(PrefixBlockKey(height))
 }
@@ -302,6 +368,159 @@ func (vm *VM) StoreWarpFetch(txID ids.ID) error {
 	return vm.vmDB.Put(k, binary.BigEndian.AppendUint64(nil, uint64(time.Now().UnixMilli())))
 }
 
+func PrefixSnapshotKey(epoch uint64) []byte {
+	k := make([]byte, 1+consts.Uint64Len)
+	k[0] = snapshotPrefix
+	binary.BigEndian.PutUint64(k[1:], epoch)
+	return k
+}
+
+// PutValidatorSnapshot persists [snapshot] so that it can be served alongside
+// the warp message attesting to it.
+func (vm *VM) PutValidatorSnapshot(snapshot *chain.ValidatorSnapshot) error {
+	b, err := snapshot.Marshal()
+	if err != nil {
+		return err
+	}
+	return vm.vmDB.Put(PrefixSnapshotKey(snapshot.Epoch), b)
+}
+
+// GetValidatorSnapshot returns the [chain.ValidatorSnapshot] taken at the end
+// of [epoch] (or nil if this node did not execute the block that took it).
+func (vm *VM) GetValidatorSnapshot(epoch uint64) (*chain.ValidatorSnapshot, error) {
+	b, err := vm.vmDB.Get(PrefixSnapshotKey(epoch))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return chain.UnmarshalValidatorSnapshot(b)
+}
+
+func PrefixMempoolKey(txID ids.ID) []byte {
+	k := make([]byte, 1+ids.IDLen)
+	k[0] = mempoolPrefix
+	copy(k[1:], txID[:])
+	return k
+}
+
+// PutDiskMempool persists [txs] so they can be re-submitted after a restart.
+func (vm *VM) PutDiskMempool(txs []*chain.Transaction) error {
+	batch := vm.vmDB.NewBatch()
+	for _, tx := range txs {
+		if err := batch.Put(PrefixMempoolKey(tx.ID()), tx.Bytes()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// PopDiskMempool returns and deletes all persisted mempool transactions.
+//
+// Transactions that can no longer be parsed (i.e. if the registry changed
+// while the node was offline) are dropped.
+func (vm *VM) PopDiskMempool() ([]*chain.Transaction, error) {
+	iter := vm.vmDB.NewIteratorWithPrefix([]byte{mempoolPrefix})
+	defer iter.Release()
+
+	var (
+		batch                        = vm.vmDB.NewBatch()
+		actionRegistry, authRegistry = vm.Registry()
+		txs                          = []*chain.Transaction{}
+	)
+	for iter.Next() {
+		if err := batch.Delete(iter.Key()); err != nil {
+			return nil, err
+		}
+		p := codec.NewReader(iter.Value(), consts.NetworkSizeLimit)
+		tx, err := chain.UnmarshalTx(p, actionRegistry, authRegistry)
+		if err != nil {
+			vm.Logger().Warn("unable to parse persisted tx", zap.Error(err))
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return txs, batch.Write()
+}
+
+func PrefixBlobKey(txID ids.ID) []byte {
+	k := make([]byte, 1+ids.IDLen)
+	k[0] = blobPrefix
+	copy(k[1:], txID[:])
+	return k
+}
+
+func PrefixBlobHeightKey(height uint64) []byte {
+	k := make([]byte, 1+consts.Uint64Len)
+	k[0] = blobHeightPrefix
+	binary.BigEndian.PutUint64(k[1:], height)
+	return k
+}
+
+// putBlobs stores the blobs in [blk] and deletes the blobs that are no longer
+// retained.
+//
+// Blobs are stored separately from blocks so that they can be retained for a
+// different amount of time.
+func (vm *VM) putBlobs(batch database.Batch, blk *chain.StatelessBlock) error {
+	var txIDs []byte
+	for _, tx := range blk.Txs {
+		if len(tx.Blob) == 0 {
+			continue
+		}
+		v := make([]byte, 0, consts.Uint64Len+len(tx.Blob))
+		v = binary.BigEndian.AppendUint64(v, blk.Height())
+		v = append(v, tx.Blob...)
+		if err := batch.Put(PrefixBlobKey(tx.ID()), v); err != nil {
+			return err
+		}
+		txID := tx.ID()
+		txIDs = append(txIDs, txID[:]...)
+	}
+	if len(txIDs) > 0 {
+		if err := batch.Put(PrefixBlobHeightKey(blk.Height()), txIDs); err != nil {
+			return err
+		}
+	}
+
+	retention := vm.config.GetBlobRetention()
+	if retention == 0 || blk.Height() <= retention {
+		return nil
+	}
+	expiryHeight := blk.Height() - retention
+	expired, err := vm.vmDB.Get(PrefixBlobHeightKey(expiryHeight))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := 0; i+ids.IDLen <= len(expired); i += ids.IDLen {
+		if err := batch.Delete(PrefixBlobKey(ids.ID(expired[i : i+ids.IDLen]))); err != nil {
+			return err
+		}
+	}
+	return batch.Delete(PrefixBlobHeightKey(expiryHeight))
+}
+
+// GetDiskBlob returns the blob of [txID] and the height of the block that
+// included it. If the blob is no longer retained (or [txID] did not have a
+// blob), this will return [database.ErrNotFound].
+func (vm *VM) GetDiskBlob(txID ids.ID) (uint64, []byte, error) {
+	v, err := vm.vmDB.Get(PrefixBlobKey(txID))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(v) < consts.Uint64Len {
+		return 0, nil, ErrCorruptedBlob
+	}
+	return binary.BigEndian.Uint64(v), v[consts.Uint64Len:], nil
+}
+
 func (vm *VM) GetWarpFetch(txID ids.ID) (int64, error) {
 	k := PrefixWarpFetchKey(txID)
 	v, err := vm.vmDB.Get(k)
@@ -313,3 +532,105 @@ func (vm *VM) GetWarpFetch(txID ids.ID) (int64, error) {
 	}
 	return int64(binary.BigEndian.Uint64(v)), nil
 }
+
+func PrefixWarpMessageKey(txID ids.ID) []byte {
+	k := make([]byte, 1+ids.IDLen)
+	k[0] = warpMessagePrefix
+	copy(k[1:], txID[:])
+	return k
+}
+
+func PrefixWarpHeightKey(height uint64) []byte {
+	k := make([]byte, 1+consts.Uint64Len)
+	k[0] = warpHeightPrefix
+	binary.BigEndian.PutUint64(k[1:], height)
+	return k
+}
+
+// putWarpMessages stores the warp messages emitted by [blk] (and the message
+// attesting to its validator snapshot, if any) with the context of [blk] and
+// deletes the messages that are no longer retained.
+//
+// Unlike the copy of each message in state, these records tell relayers
+// which block produced a message so they can gather signatures from the
+// validators that were active at that point (or ask for new ones after the
+// validator set changes).
+func (vm *VM) putWarpMessages(batch database.Batch, blk *chain.StatelessBlock) error {
+	var txIDs []byte
+	put := func(txID ids.ID, msg *warp.UnsignedMessage) error {
+		mb := msg.Bytes()
+		v := make([]byte, 0, consts.Uint64Len+consts.Int64Len+ids.IDLen+len(mb))
+		v = binary.BigEndian.AppendUint64(v, blk.Height())
+		v = binary.BigEndian.AppendUint64(v, uint64(blk.Tmstmp))
+		blkID := blk.ID()
+		v = append(v, blkID[:]...)
+		v = append(v, mb...)
+		if err := batch.Put(PrefixWarpMessageKey(txID), v); err != nil {
+			return err
+		}
+		txIDs = append(txIDs, txID[:]...)
+		return nil
+	}
+	results := blk.Results()
+	for i, result := range results {
+		if result.WarpMessage == nil {
+			continue
+		}
+		if err := put(blk.Txs[i].ID(), result.WarpMessage); err != nil {
+			return err
+		}
+	}
+	if snapshot, msg := blk.ValidatorSnapshot(); snapshot != nil {
+		if err := put(chain.ValidatorSnapshotID(snapshot.Epoch), msg); err != nil {
+			return err
+		}
+	}
+	if len(txIDs) > 0 {
+		if err := batch.Put(PrefixWarpHeightKey(blk.Height()), txIDs); err != nil {
+			return err
+		}
+	}
+
+	retention := vm.config.GetWarpMessageRetention()
+	if retention == 0 || blk.Height() <= retention {
+		return nil
+	}
+	expiryHeight := blk.Height() - retention
+	expired, err := vm.vmDB.Get(PrefixWarpHeightKey(expiryHeight))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := 0; i+ids.IDLen <= len(expired); i += ids.IDLen {
+		if err := batch.Delete(PrefixWarpMessageKey(ids.ID(expired[i : i+ids.IDLen]))); err != nil {
+			return err
+		}
+	}
+	return batch.Delete(PrefixWarpHeightKey(expiryHeight))
+}
+
+// GetDiskWarpMessage returns the warp message emitted by [txID] (or the
+// [chain.ValidatorSnapshotID] of an epoch) and the block that emitted it. If
+// the message is no longer retained (or [txID] did not emit one), this will
+// return [database.ErrNotFound].
+func (vm *VM) GetDiskWarpMessage(txID ids.ID) (*rpc.OutgoingWarpMessage, error) {
+	v, err := vm.vmDB.Get(PrefixWarpMessageKey(txID))
+	if err != nil {
+		return nil, err
+	}
+	if len(v) < consts.Uint64Len+consts.Int64Len+ids.IDLen {
+		return nil, ErrCorruptedWarpMessage
+	}
+	msg, err := warp.ParseUnsignedMessage(v[consts.Uint64Len+consts.Int64Len+ids.IDLen:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedWarpMessage, err)
+	}
+	return &rpc.OutgoingWarpMessage{
+		Height:    binary.BigEndian.Uint64(v),
+		Timestamp: int64(binary.BigEndian.Uint64(v[consts.Uint64Len:])),
+		BlockID:   ids.ID(v[consts.Uint64Len+consts.Int64Len : consts.Uint64Len+consts.Int64Len+ids.IDLen]),
+		Message:   msg,
+	}, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/storage"
+)
+
+// Prefixes of the nodes merkledb writes to [vm.rawStateDB]
+var (
+	valueNodePrefix        = []byte{1}
+	intermediateNodePrefix = []byte{2}
+)
+
+// StorageStats returns the last stats collected by the VM (which refreshes
+// them every [Config.GetStorageStatsInterval]).
+func (vm *VM) StorageStats() (*rpc.StorageStats, error) {
+	stats := vm.storageStats.Get()
+	if stats == nil {
+		return nil, ErrNoStorageStats
+	}
+	return stats, nil
+}
+
+// collectStorageStats collects stats immediately and then every [interval]
+// until shutdown.
+func (vm *VM) collectStorageStats(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := vm.updateStorageStats(); err != nil {
+			vm.Logger().Warn("unable to collect storage stats", zap.Error(err))
+		}
+		select {
+		case <-t.C:
+		case <-vm.stop:
+			return
+		}
+	}
+}
+
+func (vm *VM) updateStorageStats() error {
+	vm.dbL.Lock()
+	defer vm.dbL.Unlock()
+
+	select {
+	case <-vm.stop:
+		return ErrShuttingDown
+	default:
+	}
+
+	// Counting nodes iterates over all of state, which can take a while on
+	// large chains.
+	start := time.Now()
+	valueNodes, err := countKeys(vm.rawStateDB, valueNodePrefix)
+	if err != nil {
+		return err
+	}
+	intermediateNodes, err := countKeys(vm.rawStateDB, intermediateNodePrefix)
+	if err != nil {
+		return err
+	}
+	stats := &rpc.StorageStats{
+		ValueNodes:        valueNodes,
+		IntermediateNodes: intermediateNodes,
+		Timestamp:         time.Now().UnixMilli(),
+	}
+	stats.BlockDB, _ = storage.Stats(vm.vmDB)
+	stats.StateDB, _ = storage.Stats(vm.rawStateDB)
+	vm.storageStats.Set(stats)
+	vm.metrics.valueNodes.Set(float64(valueNodes))
+	vm.metrics.intermediateNodes.Set(float64(intermediateNodes))
+	vm.Logger().Debug("collected storage stats",
+		zap.Uint64("valueNodes", valueNodes),
+		zap.Uint64("intermediateNodes", intermediateNodes),
+		zap.Duration("t", time.Since(start)),
+	)
+	return nil
+}
+
+func countKeys(db database.Database, prefix []byte) (uint64, error) {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	var count uint64
+	for it.Next() {
+		count++
+	}
+	return count, it.Error()
+}
@@ -16,17 +16,18 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/statesync"
 )
 
 type stateSyncerClient struct {
 	vm          *VM
 	gatherer    ametrics.MultiGatherer
 	syncManager *syncEng.Manager
+	tracker     *statesync.Tracker
 
-	// tracks the sync target so we can update last accepted
-	// block when sync completes.
-	target        *chain.StatelessBlock
-	targetUpdated bool
+	// tracks the sync target (always the last accepted block while
+	// syncing)
+	target *chain.StatelessBlock
 
 	// State Sync results
 	init         bool
@@ -39,10 +40,12 @@ type stateSyncerClient struct {
 // TODO: break out into own package
 func (vm *VM) NewStateSyncClient(
 	gatherer ametrics.MultiGatherer,
+	tracker *statesync.Tracker,
 ) *stateSyncerClient {
 	return &stateSyncerClient{
 		vm:       vm,
 		gatherer: gatherer,
+		tracker:  tracker,
 		done:     make(chan struct{}),
 	}
 }
@@ -71,7 +74,7 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	sb *chain.SyncableBlock,
 ) (block.StateSyncMode, error) {
 	s.init = true
-	s.vm.snowCtx.Log.Info("accepted syncable block",
+	s.vm.Logger().Info("accepted syncable block",
 		zap.Uint64("height", sb.Height()),
 		zap.Stringer("blockID", sb.ID()),
 	)
@@ -79,11 +82,11 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	// If we did not finish syncing, we must state sync.
 	syncing, err := s.vm.GetDiskIsSyncing()
 	if err != nil {
-		s.vm.snowCtx.Log.Warn("could not determine if syncing", zap.Error(err))
+		s.vm.Logger().Warn("could not determine if syncing", zap.Error(err))
 		return block.StateSyncSkipped, err
 	}
 	if !syncing && (s.vm.lastAccepted.Hght+s.vm.config.GetStateSyncMinBlocks() > sb.Height()) {
-		s.vm.snowCtx.Log.Info(
+		s.vm.Logger().Info(
 			"bypassing state sync",
 			zap.Uint64("lastAccepted", s.vm.lastAccepted.Hght),
 			zap.Uint64("syncableHeight", sb.Height()),
@@ -110,7 +113,7 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	// MerkleDB will handle clearing any keys on-disk that are no
 	// longer necessary.
 	s.target = sb.StatelessBlock
-	s.vm.snowCtx.Log.Info(
+	s.vm.Logger().Info(
 		"starting state sync",
 		zap.Uint64("height", s.target.Hght),
 		zap.Stringer("summary", sb),
@@ -130,7 +133,7 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	syncClient, err := syncEng.NewClient(&syncEng.ClientConfig{
 		BranchFactor:     s.vm.genesis.GetStateBranchFactor(),
 		NetworkClient:    s.vm.stateSyncNetworkClient,
-		Log:              s.vm.snowCtx.Log,
+		Log:              s.vm.Logger(),
 		Metrics:          metrics,
 		StateSyncNodeIDs: nil, // pull from all
 	})
@@ -140,9 +143,9 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	s.syncManager, err = syncEng.NewManager(syncEng.ManagerConfig{
 		BranchFactor:          s.vm.genesis.GetStateBranchFactor(),
 		DB:                    s.vm.stateDB,
-		Client:                syncClient,
+		Client:                s.tracker.Client(syncClient),
 		SimultaneousWorkLimit: s.vm.config.GetStateSyncParallelism(),
-		Log:                   s.vm.snowCtx.Log,
+		Log:                   s.vm.Logger(),
 		TargetRoot:            sb.StateRoot,
 	})
 	if err != nil {
@@ -162,6 +165,12 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 		return block.StateSyncSkipped, err
 	}
 
+	// Blocks below the target will not be fetched by bootstrapping, so we
+	// optionally backfill them once the node is ready.
+	if err := s.vm.scheduleBlockBackfill(s.target); err != nil {
+		return block.StateSyncSkipped, err
+	}
+
 	// Update the last accepted to the state target block,
 	// since we don't want bootstrapping to fetch all the blocks
 	// from genesis to the sync target.
@@ -169,20 +178,22 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 
 	// Kickoff state syncing from [s.target]
 	if err := s.syncManager.Start(context.Background()); err != nil {
-		s.vm.snowCtx.Log.Warn("not starting state syncing", zap.Error(err))
+		s.vm.Logger().Warn("not starting state syncing", zap.Error(err))
 		return block.StateSyncSkipped, err
 	}
+	s.tracker.Start(s.target.Hght, s.target.StateRoot)
 	go func() {
 		// wait for the work to complete on this goroutine
 		//
 		// [syncManager] guarantees this will always return so it isn't possible to
 		// deadlock.
 		s.stateSyncErr = s.syncManager.Wait(context.Background())
-		s.vm.snowCtx.Log.Info("state sync done", zap.Error(s.stateSyncErr))
+		s.vm.Logger().Info("state sync done", zap.Error(s.stateSyncErr))
 		if s.stateSyncErr == nil {
 			// if the sync was successful, update the last accepted pointers.
 			s.stateSyncErr = s.finishSync()
 		}
+		s.tracker.Finish(s.stateSyncErr)
 		// notify the engine the VM is ready to participate
 		// in voting and it can verify blocks.
 		//
@@ -197,16 +208,14 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	return block.StateSyncDynamic, nil
 }
 
-// finishSync is responsible for updating disk and memory pointers
+// finishSync is responsible for updating disk pointers
+//
+// Every sync target is marked accepted when it becomes the target, so the
+// last accepted block already matches the synced state.
+//
+// NOTE: There may be a number of verified but unaccepted blocks above the
+// target.
 func (s *stateSyncerClient) finishSync() error {
-	if s.targetUpdated {
-		// Will look like block on start accepted then last block before beginning
-		// bootstrapping is accepted.
-		//
-		// NOTE: There may be a number of verified but unaccepted blocks above this
-		// block.
-		s.target.MarkAccepted(context.Background())
-	}
 	return s.vm.PutDiskIsSyncing(false)
 }
 
@@ -265,7 +274,13 @@ func (s *stateSyncerClient) UpdateSyncTarget(b *chain.StatelessBlock) (bool, err
 	if err != nil {
 		return false, err // Unexpected error
 	}
-	s.target = b           // Remember the new target
-	s.targetUpdated = true // Set [targetUpdated] so we call SetLastAccepted on finish
-	return true, nil       // Sync root target updated successfully
+	s.target = b // Remember the new target
+	s.tracker.UpdateTarget(b.Hght, b.StateRoot)
+
+	// Blocks accepted while syncing are stored as the target moves, so the
+	// backfill only needs to follow the new target.
+	if err := s.vm.updateBlockBackfill(b); err != nil {
+		return false, err
+	}
+	return true, nil // Sync root target updated successfully
 }
@@ -0,0 +1,256 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+const (
+	maxFetchTxs      = 256
+	maxFetchResponse = consts.NetworkSizeLimit - 1024 // leave room for message overhead
+	fetchTxsTimeout  = 5 * time.Second
+	fetchTxsPeers    = 3 // peers asked for missing txs before giving up
+)
+
+// txFetcher requests transactions by ID from peers and serves the same
+// requests from our mempool.
+type txFetcher struct {
+	vm        *VM
+	appSender common.AppSender
+
+	l         sync.Mutex
+	peers     set.Set[ids.NodeID]
+	requestID uint32
+	pending   map[uint32]chan []byte
+}
+
+func newTxFetcher(vm *VM, appSender common.AppSender) *txFetcher {
+	return &txFetcher{
+		vm:        vm,
+		appSender: appSender,
+		pending:   map[uint32]chan []byte{},
+	}
+}
+
+// FetchTxs returns the transactions with [txIDs] from our mempool or, if
+// they aren't in it, from up to a few connected peers. Fetched transactions
+// match the requested IDs but their signatures are not verified.
+//
+// If some transactions can't be found, the rest are returned (in the order
+// they were requested) along with [ErrTxsUnavailable].
+func (vm *VM) FetchTxs(ctx context.Context, txIDs []ids.ID) ([]*chain.Transaction, error) {
+	ctx, span := vm.tracer.Start(ctx, "VM.FetchTxs")
+	defer span.End()
+
+	if len(txIDs) > maxFetchTxs {
+		return nil, ErrTooManyTxs
+	}
+	var (
+		found   = make(map[ids.ID]*chain.Transaction, len(txIDs))
+		missing = make([]ids.ID, 0, len(txIDs))
+	)
+	for _, txID := range txIDs {
+		if tx, ok := vm.mempool.Get(ctx, txID); ok {
+			found[txID] = tx
+			continue
+		}
+		missing = append(missing, txID)
+	}
+	if len(missing) > 0 && vm.txFetcher != nil {
+		for _, nodeID := range vm.txFetcher.samplePeers(fetchTxsPeers) {
+			txs, err := vm.txFetcher.fetch(ctx, nodeID, missing)
+			if err != nil {
+				vm.Logger().Debug("unable to fetch txs", zap.Stringer("nodeID", nodeID), zap.Error(err))
+				continue
+			}
+			for _, tx := range txs {
+				found[tx.ID()] = tx
+			}
+			vm.metrics.txsFetched.Add(float64(len(txs)))
+			remaining := missing[:0]
+			for _, txID := range missing {
+				if _, ok := found[txID]; !ok {
+					remaining = append(remaining, txID)
+				}
+			}
+			if missing = remaining; len(missing) == 0 {
+				break
+			}
+		}
+	}
+
+	txs := make([]*chain.Transaction, 0, len(found))
+	for _, txID := range txIDs {
+		if tx, ok := found[txID]; ok {
+			txs = append(txs, tx)
+		}
+	}
+	if len(missing) > 0 {
+		return txs, fmt.Errorf("%w: missing=%d", ErrTxsUnavailable, len(missing))
+	}
+	return txs, nil
+}
+
+// samplePeers returns up to [max] connected peers in a random order.
+func (f *txFetcher) samplePeers(max int) []ids.NodeID {
+	f.l.Lock()
+	peers := f.peers.List()
+	f.l.Unlock()
+	rand.Shuffle(len(peers), func(i, j int) { //nolint:gosec
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	if len(peers) > max {
+		peers = peers[:max]
+	}
+	return peers
+}
+
+// fetch requests [txIDs] from [nodeID] and returns those it has.
+func (f *txFetcher) fetch(ctx context.Context, nodeID ids.NodeID, txIDs []ids.ID) ([]*chain.Transaction, error) {
+	f.l.Lock()
+	requestID := f.requestID
+	f.requestID++
+	response := make(chan []byte, 1)
+	f.pending[requestID] = response
+	f.l.Unlock()
+	defer func() {
+		f.l.Lock()
+		delete(f.pending, requestID)
+		f.l.Unlock()
+	}()
+
+	size := consts.IntLen + len(txIDs)*consts.IDLen
+	p := codec.NewWriter(size, size)
+	p.PackInt(len(txIDs))
+	for _, txID := range txIDs {
+		p.PackID(txID)
+	}
+	if err := f.appSender.SendAppRequest(ctx, set.Of(nodeID), requestID, p.Bytes()); err != nil {
+		return nil, err
+	}
+	var raw []byte
+	select {
+	case raw = <-response:
+	case <-time.After(fetchTxsTimeout):
+		return nil, fmt.Errorf("%w: %s", ErrFetchTimeout, nodeID)
+	case <-f.vm.stop:
+		return nil, ErrShuttingDown
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTxsUnavailable, nodeID)
+	}
+
+	actionRegistry, authRegistry := f.vm.Registry()
+	_, txs, err := chain.UnmarshalTxs(raw, len(txIDs), actionRegistry, authRegistry)
+	if err != nil {
+		return nil, err
+	}
+	requested := set.Of(txIDs...)
+	for _, tx := range txs {
+		if !requested.Contains(tx.ID()) {
+			return nil, fmt.Errorf("%w: unexpected tx %s", ErrInvalidFetch, tx.ID())
+		}
+		requested.Remove(tx.ID()) // don't accept duplicates
+	}
+	return txs, nil
+}
+
+func (f *txFetcher) Connected(nodeID ids.NodeID) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	f.peers.Add(nodeID)
+}
+
+func (f *txFetcher) Disconnected(nodeID ids.NodeID) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	f.peers.Remove(nodeID)
+}
+
+// HandleResponse delivers [response] to the pending request with
+// [requestID]. A nil [response] indicates that the request failed.
+func (f *txFetcher) HandleResponse(requestID uint32, response []byte) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	ch, ok := f.pending[requestID]
+	if !ok {
+		return
+	}
+	delete(f.pending, requestID)
+	ch <- response
+}
+
+// Serve sends a peer the requested transactions that are in our mempool
+// (within the limits of [Config.GetServeLimitConfig]).
+func (f *txFetcher) Serve(ctx context.Context, nodeID ids.NodeID, requestID uint32, request []byte) error {
+	if !f.vm.serveLimiter.Acquire() {
+		// The peer will retry with another node once the request times out
+		f.vm.metrics.servesDropped.Inc()
+		return nil
+	}
+	defer f.vm.serveLimiter.Release()
+
+	rp := codec.NewReader(request, consts.IntLen+maxFetchTxs*consts.IDLen)
+	count := rp.UnpackInt(true)
+	if count > maxFetchTxs {
+		f.vm.Logger().Warn("too many txs requested", zap.Stringer("nodeID", nodeID), zap.Int("count", count))
+		return nil
+	}
+	txIDs := make([]ids.ID, count)
+	for i := range txIDs {
+		rp.UnpackID(true, &txIDs[i])
+	}
+	if err := rp.Err(); err != nil {
+		f.vm.Logger().Warn("unable to unpack tx fetch request", zap.Error(err))
+		return nil
+	}
+
+	var (
+		txs     = make([]*chain.Transaction, 0, count)
+		size    = consts.IntLen
+		maxSize = maxFetchResponse
+	)
+	if budget := f.vm.serveLimiter.Budget(); budget < maxSize {
+		maxSize = budget
+	}
+	for _, txID := range txIDs {
+		tx, ok := f.vm.mempool.Get(ctx, txID)
+		if !ok {
+			continue
+		}
+		if size+tx.Size() > maxSize {
+			break
+		}
+		txs = append(txs, tx)
+		size += tx.Size()
+	}
+	f.vm.serveLimiter.Charge(size)
+	if len(txs) == 0 {
+		// An empty response tells the peer to try another node
+		return f.appSender.SendAppResponse(ctx, nodeID, requestID, nil)
+	}
+	b, err := chain.MarshalTxs(txs)
+	if err != nil {
+		f.vm.Logger().Warn("unable to marshal fetched txs", zap.Error(err))
+		return nil
+	}
+	return f.appSender.SendAppResponse(ctx, nodeID, requestID, b)
+}
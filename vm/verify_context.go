@@ -58,8 +58,8 @@ func (p *PendingVerifyContext) View(ctx context.Context, verify bool) (state.Vie
 	return p.blk.View(ctx, verify)
 }
 
-func (p *PendingVerifyContext) IsRepeat(ctx context.Context, oldestAllowed int64, txs []*chain.Transaction, marker set.Bits, stop bool) (set.Bits, error) {
-	return p.blk.IsRepeat(ctx, oldestAllowed, txs, marker, stop)
+func (p *PendingVerifyContext) IsRepeat(ctx context.Context, oldestAllowed int64, oldestAllowedHeight uint64, txs []*chain.Transaction, marker set.Bits, stop bool) (set.Bits, error) {
+	return p.blk.IsRepeat(ctx, oldestAllowed, oldestAllowedHeight, txs, marker, stop)
 }
 
 type AcceptedVerifyContext struct {
@@ -69,10 +69,17 @@ type AcceptedVerifyContext struct {
 // We disregard [verify] because [GetVerifyContext] ensures
 // we will never need to verify a block if [AcceptedVerifyContext] is returned.
 func (a *AcceptedVerifyContext) View(context.Context, bool) (state.View, error) {
-	return a.vm.State()
+	db, err := a.vm.State()
+	if err != nil {
+		return nil, err
+	}
+	if a.vm.stateCache != nil {
+		return a.vm.stateCache.View(db), nil
+	}
+	return db, nil
 }
 
-func (a *AcceptedVerifyContext) IsRepeat(ctx context.Context, _ int64, txs []*chain.Transaction, marker set.Bits, stop bool) (set.Bits, error) {
+func (a *AcceptedVerifyContext) IsRepeat(ctx context.Context, _ int64, _ uint64, txs []*chain.Transaction, marker set.Bits, stop bool) (set.Bits, error) {
 	bits := a.vm.IsRepeat(ctx, txs, marker, stop)
 	return bits, nil
 }
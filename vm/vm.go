@@ -6,6 +6,7 @@ package vm
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -23,6 +24,7 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/version"
@@ -35,12 +37,18 @@ import (
 
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
 	"github.com/ava-labs/hypersdk/emap"
 	"github.com/ava-labs/hypersdk/gossiper"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/mempool"
 	"github.com/ava-labs/hypersdk/network"
+	"github.com/ava-labs/hypersdk/profiles"
+	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/slo"
 	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/statesync"
 	htrace "github.com/ava-labs/hypersdk/trace"
 	hutils "github.com/ava-labs/hypersdk/utils"
 	"github.com/ava-labs/hypersdk/workers"
@@ -61,6 +69,7 @@ type VM struct {
 	gossiper       gossiper.Gossiper
 	rawStateDB     database.Database
 	stateDB        merkledb.MerkleDB
+	stateCache     *chain.StateCache // nil if disabled
 	vmDB           database.Database
 	handlers       Handlers
 	actionRegistry chain.ActionRegistry
@@ -69,10 +78,16 @@ type VM struct {
 
 	tracer  trace.Tracer
 	mempool *mempool.Mempool[*chain.Transaction]
+	bundles *mempool.Mempool[*chain.Bundle] // nil if disabled
+
+	// how [Submit] handles transactions that are unlikely to be included soon
+	congestionPolicy CongestionPolicy
 
 	// track all accepted but still valid txs (replay protection)
-	seen                   *emap.EMap[*chain.Transaction]
+	seen                   *emap.EMap[*chain.Transaction] // by timestamp
+	seenHeights            *emap.EMap[*chain.Transaction] // by height
 	startSeenTime          int64
+	startSeenHeight        uint64
 	seenValidityWindowOnce sync.Once
 	seenValidityWindow     chan struct{}
 
@@ -95,6 +110,7 @@ type VM struct {
 
 	// Transactions that streaming users are currently subscribed to
 	webSocketServer *rpc.WebSocketServer
+	pubsubServer    *pubsub.Server
 
 	// authVerifiers are used to verify signatures in parallel
 	// with limited parallelism
@@ -111,6 +127,12 @@ type VM struct {
 	stateSyncNetworkClient syncEng.NetworkClient
 	stateSyncNetworkServer *syncEng.NetworkServer
 
+	// Fetches blocks below the state sync target from peers
+	blockBackfiller *blockBackfiller
+
+	// Fetches txs by ID from peers
+	txFetcher *txFetcher
+
 	// Warp manager fetches signatures from other validators for a given accepted
 	// txID
 	warpManager *WarpManager
@@ -118,8 +140,20 @@ type VM struct {
 	// Network manager routes p2p messages to pre-registered handlers
 	networkManager *network.Manager
 
-	metrics  *Metrics
-	profiler profiler.ContinuousProfiler
+	// Bounds the blocks and state served to other nodes
+	serveLimiter *network.ServeLimiter
+
+	logs      *logs.Manager
+	metrics   *Metrics
+	slo       *slo.Tracker
+	profiles  *profiles.Manager
+	profilerL sync.Mutex
+	profiler  profiler.ContinuousProfiler
+
+	// Serializes database maintenance (compactions and storage stats) and
+	// prevents it from running once the databases are closed
+	dbL          sync.Mutex
+	storageStats utils.Atomic[*rpc.StorageStats]
 
 	ready chan struct{}
 	stop  chan struct{}
@@ -142,12 +176,11 @@ func (vm *VM) Initialize(
 	appSender common.AppSender,
 ) error {
 	vm.snowCtx = snowCtx
+	vm.logs = logs.NewManager(snowCtx.Log, logging.Info, logs.VM, logs.Chain, logs.Builder, logs.Gossiper, logs.Backfill)
 	vm.pkBytes = bls.PublicKeyToBytes(vm.snowCtx.PublicKey)
 	// This will be overwritten when we accept the first block (in state sync) or
 	// backfill existing blocks (during normal bootstrapping).
 	vm.startSeenTime = -1
-	// Init seen for tracking transactions that have been accepted on-chain
-	vm.seen = emap.NewEMap[*chain.Transaction]()
 	vm.seenValidityWindow = make(chan struct{})
 	vm.ready = make(chan struct{})
 	vm.stop = make(chan struct{})
@@ -185,6 +218,10 @@ func (vm *VM) Initialize(
 	if err != nil {
 		return fmt.Errorf("implementation initialization failed: %w", err)
 	}
+	vm.logs.SetDefaultLevel(vm.config.GetLogLevel())
+	if err := ed25519.SetBackend(ed25519.Backend(vm.config.GetED25519Backend())); err != nil {
+		return err
+	}
 
 	// Setup tracer
 	vm.tracer, err = htrace.New(vm.config.GetTraceConfig())
@@ -194,10 +231,21 @@ func (vm *VM) Initialize(
 	ctx, span := vm.tracer.Start(ctx, "VM.Initialize")
 	defer span.End()
 
+	// Setup SLO tracker
+	vm.slo, err = slo.New(vm.config.GetSLOConfig())
+	if err != nil {
+		return err
+	}
+
+	// Setup limits on serving peers
+	vm.serveLimiter = network.NewServeLimiter(vm.config.GetServeLimitConfig())
+
 	// Setup profiler
+	vm.profiles = profiles.New()
 	if cfg := vm.config.GetContinuousProfilerConfig(); cfg.Enabled {
-		vm.profiler = profiler.NewContinuous(cfg.Dir, cfg.Freq, cfg.MaxNumFiles)
-		go vm.profiler.Dispatch() //nolint:errcheck
+		if err := vm.StartContinuousProfiler(cfg.Dir, cfg.Freq, cfg.MaxNumFiles); err != nil {
+			return err
+		}
 	}
 
 	// Instantiate DBs
@@ -225,14 +273,33 @@ func (vm *VM) Initialize(
 
 	// Setup worker cluster for verifying signatures
 	//
-	// If [parallelism] is odd, we assign the extra
-	// core to signature verification.
-	vm.authVerifiers = workers.NewParallel(vm.config.GetAuthVerificationCores(), 100) // TODO: make job backlog a const
+	// The cluster grows from [GetAuthVerificationMinCores] to
+	// [GetAuthVerificationCores] when signatures back up and shrinks
+	// again once they drain.
+	vm.authVerifiers = workers.NewAdaptive(
+		vm.config.GetAuthVerificationMinCores(),
+		vm.config.GetAuthVerificationCores(),
+		100, // TODO: make job backlog a const
+		workers.DefaultIdleTimeout,
+		vm.metrics.authVerifierRecorder,
+	)
 
 	// Init channels before initializing other structs
 	vm.toEngine = toEngine
 
+	// Init seen for tracking transactions that have been accepted on-chain
+	vm.seen = emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{
+		Expiry:      chain.ExpiryTimestamp,
+		Granularity: vm.config.GetSeenTimeGranularity(),
+	})
+	vm.seenHeights = emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{
+		Expiry:      chain.ExpiryHeight,
+		Granularity: vm.config.GetSeenHeightGranularity(),
+	})
 	vm.parsedBlocks = &cache.LRU[ids.ID, *chain.StatelessBlock]{Size: vm.config.GetParsedBlockCacheSize()}
+	if size := vm.config.GetStateCacheSize(); size > 0 {
+		vm.stateCache = chain.NewStateCache(size, vm)
+	}
 	vm.verifiedBlocks = make(map[ids.ID]*chain.StatelessBlock)
 	vm.acceptedBlocksByID, err = hcache.NewFIFO[ids.ID, *chain.StatelessBlock](vm.config.GetAcceptedBlockWindowCache())
 	if err != nil {
@@ -251,43 +318,79 @@ func (vm *VM) Initialize(
 		vm.config.GetMempoolSponsorSize(),
 		vm.config.GetMempoolExemptSponsors(),
 	)
+	vm.mempool.SetDropRecorder(func(reason mempool.DropReason, count int) {
+		vm.RecordTxsDropped(string(reason), count)
+	})
+	// Index txs by their max units so that block building can skip those
+	// that don't fit in what is left of a block
+	vm.mempool.SetMeter(func(tx *chain.Transaction) ([]uint64, error) {
+		units, err := tx.MaxUnits(vm.c.StateManager(), vm.c.Rules(time.Now().UnixMilli()))
+		if err != nil {
+			return nil, err
+		}
+		return units[:chain.FeeDimensions()], nil
+	})
+	// Txs that expire by height never reach their [Expiry], so they are
+	// removed by height instead (see [Accepted])
+	vm.mempool.SetHeightExpiry(chain.ExpiryHeight)
+	// Nobody pays for txs that are exempt from fees, so only hold a few
+	vm.mempool.SetFreeLimit(func(tx *chain.Transaction) bool {
+		return tx.FeeExempt(vm.c.Rules(time.Now().UnixMilli()))
+	}, vm.config.GetMempoolFeeExemptSize())
+	if err := defaultRegistry.Register(newMempoolFeeCollector(vm.mempool)); err != nil {
+		return err
+	}
+	vm.congestionPolicy, err = parseCongestionPolicy(vm.config.GetMempoolCongestionPolicy())
+	if err != nil {
+		return err
+	}
+	// Bundles would be placed ahead of txs received before them, so they
+	// aren't accepted when ordering by arrival
+	if size := vm.config.GetMempoolBundleSize(); size > 0 && !vm.config.GetFCFSOrdering() {
+		vm.bundles = mempool.New[*chain.Bundle](
+			vm.tracer,
+			size,
+			vm.config.GetMempoolSponsorSize(),
+			vm.config.GetMempoolExemptSponsors(),
+		)
+	}
 
 	// Try to load last accepted
 	has, err := vm.HasLastAccepted()
 	if err != nil {
-		snowCtx.Log.Error("could not determine if have last accepted")
+		vm.Logger().Error("could not determine if have last accepted")
 		return err
 	}
 	if has { //nolint:nestif
 		genesisBlk, err := vm.GetGenesis(ctx)
 		if err != nil {
-			snowCtx.Log.Error("could not get genesis", zap.Error(err))
+			vm.Logger().Error("could not get genesis", zap.Error(err))
 			return err
 		}
 		vm.genesisBlk = genesisBlk
 		lastAcceptedHeight, err := vm.GetLastAcceptedHeight()
 		if err != nil {
-			snowCtx.Log.Error("could not get last accepted height", zap.Error(err))
+			vm.Logger().Error("could not get last accepted height", zap.Error(err))
 			return err
 		}
 		blk, err := vm.GetDiskBlock(ctx, lastAcceptedHeight)
 		if err != nil {
-			snowCtx.Log.Error("could not get last accepted block", zap.Error(err))
+			vm.Logger().Error("could not get last accepted block", zap.Error(err))
 			return err
 		}
 		vm.preferred, vm.lastAccepted = blk.ID(), blk
 		if err := vm.loadAcceptedBlocks(ctx); err != nil {
-			snowCtx.Log.Error("could not load accepted blocks from disk", zap.Error(err))
+			vm.Logger().Error("could not load accepted blocks from disk", zap.Error(err))
 			return err
 		}
 		// It is not guaranteed that the last accepted state on-disk matches the post-execution
 		// result of the last accepted block.
-		snowCtx.Log.Info("initialized vm from last accepted", zap.Stringer("block", blk.ID()))
+		vm.Logger().Info("initialized vm from last accepted", zap.Stringer("block", blk.ID()))
 	} else {
 		// Set balances and compute genesis root
 		sps := state.NewSimpleMutable(vm.stateDB)
 		if err := vm.genesis.Load(ctx, vm.tracer, sps); err != nil {
-			snowCtx.Log.Error("could not set genesis allocation", zap.Error(err))
+			vm.Logger().Error("could not set genesis allocation", zap.Error(err))
 			return err
 		}
 		if err := sps.Commit(ctx); err != nil {
@@ -295,10 +398,10 @@ func (vm *VM) Initialize(
 		}
 		root, err := vm.stateDB.GetMerkleRoot(ctx)
 		if err != nil {
-			snowCtx.Log.Error("could not get merkle root", zap.Error(err))
+			vm.Logger().Error("could not get merkle root", zap.Error(err))
 			return err
 		}
-		snowCtx.Log.Info("genesis state created", zap.Stringer("root", root))
+		vm.Logger().Info("genesis state created", zap.Stringer("root", root))
 
 		// Create genesis block
 		genesisBlk, err := chain.ParseStatefulBlock(
@@ -309,7 +412,7 @@ func (vm *VM) Initialize(
 			vm,
 		)
 		if err != nil {
-			snowCtx.Log.Error("unable to init genesis block", zap.Error(err))
+			vm.Logger().Error("unable to init genesis block", zap.Error(err))
 			return err
 		}
 
@@ -324,9 +427,9 @@ func (vm *VM) Initialize(
 		genesisRules := vm.c.Rules(0)
 		feeManager := chain.NewFeeManager(nil)
 		minUnitPrice := genesisRules.GetMinUnitPrice()
-		for i := chain.Dimension(0); i < chain.FeeDimensions; i++ {
+		for i := chain.Dimension(0); int(i) < chain.FeeDimensions(); i++ {
 			feeManager.SetUnitPrice(i, minUnitPrice[i])
-			snowCtx.Log.Info("set genesis unit price", zap.Int("dimension", int(i)), zap.Uint64("price", feeManager.UnitPrice(i)))
+			vm.Logger().Info("set genesis unit price", zap.Stringer("dimension", i), zap.Uint64("price", feeManager.UnitPrice(i)))
 		}
 		if err := sps.Insert(ctx, chain.FeeKey(vm.StateManager().FeeKey()), feeManager.Bytes()); err != nil {
 			return err
@@ -338,19 +441,19 @@ func (vm *VM) Initialize(
 		}
 		genesisRoot, err := vm.stateDB.GetMerkleRoot(ctx)
 		if err != nil {
-			snowCtx.Log.Error("could not get merkle root", zap.Error(err))
+			vm.Logger().Error("could not get merkle root", zap.Error(err))
 			return err
 		}
 
 		// Update last accepted and preferred block
 		vm.genesisBlk = genesisBlk
 		if err := vm.UpdateLastAccepted(genesisBlk); err != nil {
-			snowCtx.Log.Error("could not set genesis block as last accepted", zap.Error(err))
+			vm.Logger().Error("could not set genesis block as last accepted", zap.Error(err))
 			return err
 		}
 		gBlkID := genesisBlk.ID()
 		vm.preferred, vm.lastAccepted = gBlkID, genesisBlk
-		snowCtx.Log.Info("initialized vm from genesis",
+		vm.Logger().Info("initialized vm from genesis",
 			zap.Stringer("block", gBlkID),
 			zap.Stringer("pre-execution root", genesisBlk.StateRoot),
 			zap.Stringer("post-execution root", genesisRoot),
@@ -372,17 +475,43 @@ func (vm *VM) Initialize(
 	if err != nil {
 		return err
 	}
+	syncTracker, err := statesync.New(syncRegistry)
+	if err != nil {
+		return err
+	}
 	if err := gatherer.Register("sync", syncRegistry); err != nil {
 		return err
 	}
-	vm.stateSyncClient = vm.NewStateSyncClient(gatherer)
-	vm.stateSyncNetworkServer = syncEng.NewNetworkServer(stateSyncSender, vm.stateDB, vm.Logger())
+	vm.stateSyncClient = vm.NewStateSyncClient(gatherer, syncTracker)
+	vm.stateSyncNetworkServer = syncEng.NewNetworkServer(
+		vm.serveLimiter.ChargeResponses(stateSyncSender),
+		vm.stateDB,
+		vm.Logger(),
+	)
 	vm.networkManager.SetHandler(stateSyncHandler, NewStateSyncHandler(vm))
 
 	// Setup gossip networking
 	gossipHandler, gossipSender := vm.networkManager.Register()
 	vm.networkManager.SetHandler(gossipHandler, NewTxGossipHandler(vm))
 
+	// Setup block backfill (registered last to keep the IDs of existing
+	// handlers stable)
+	backfillHandler, backfillSender := vm.networkManager.Register()
+	vm.blockBackfiller = newBlockBackfiller(vm, backfillSender)
+	vm.networkManager.SetHandler(backfillHandler, NewBlockBackfillHandler(vm))
+
+	// Setup mempool filter exchange
+	filterHandler, filterSender := vm.networkManager.Register()
+	vm.networkManager.SetHandler(filterHandler, NewTxFilterHandler(vm))
+	if fg, ok := vm.gossiper.(gossiper.FilterGossiper); ok {
+		fg.SetFilterSender(filterSender)
+	}
+
+	// Setup tx fetching
+	fetchHandler, fetchSender := vm.networkManager.Register()
+	vm.txFetcher = newTxFetcher(vm, fetchSender)
+	vm.networkManager.SetHandler(fetchHandler, NewTxFetchHandler(vm))
+
 	// Startup block builder and gossiper
 	go vm.builder.Run()
 	go vm.gossiper.Run(gossipSender)
@@ -390,8 +519,28 @@ func (vm *VM) Initialize(
 	// Wait until VM is ready and then send a state sync message to engine
 	go vm.markReady()
 
+	// Reload operational config on SIGHUP
+	if file := vm.config.GetReloadConfigFile(); len(file) > 0 {
+		go vm.reloadOnSignal(file)
+	}
+
+	// Compact databases in the background
+	if interval := vm.config.GetCompactionInterval(); interval > 0 {
+		go vm.compactPeriodically(interval)
+	}
+
+	// Collect storage stats in the background
+	if interval := vm.config.GetStorageStatsInterval(); interval > 0 {
+		go vm.collectStorageStats(interval)
+	}
+
+	// Fetch blocks skipped by state sync in the background
+	if vm.config.GetBlockBackfillDepth() > 0 {
+		go vm.blockBackfiller.Run()
+	}
+
 	// Setup handlers
-	jsonRPCHandler, err := rpc.NewJSONRPCHandler(rpc.Name, rpc.NewJSONRPCServer(vm))
+	jsonRPCHandler, err := rpc.NewJSONRPCHandler(rpc.Name, rpc.NewJSONRPCServer(vm), vm.RecordRPCRequest)
 	if err != nil {
 		return fmt.Errorf("unable to create handler: %w", err)
 	}
@@ -399,11 +548,40 @@ func (vm *VM) Initialize(
 		return fmt.Errorf("duplicate JSONRPC handler found: %s", rpc.JSONRPCEndpoint)
 	}
 	vm.handlers[rpc.JSONRPCEndpoint] = jsonRPCHandler
+	if vm.config.GetAdminAPIEnabled() {
+		adminHandler, err := rpc.NewJSONRPCHandler(rpc.Name, rpc.NewAdminServer(vm))
+		if err != nil {
+			return fmt.Errorf("unable to create admin handler: %w", err)
+		}
+		if _, ok := vm.handlers[rpc.AdminEndpoint]; ok {
+			return fmt.Errorf("duplicate admin handler found: %s", rpc.AdminEndpoint)
+		}
+		vm.handlers[rpc.AdminEndpoint] = adminHandler
+	}
 	if _, ok := vm.handlers[rpc.WebSocketEndpoint]; ok {
 		return fmt.Errorf("duplicate WebSocket handler found: %s", rpc.WebSocketEndpoint)
 	}
-	webSocketServer, pubsubServer := rpc.NewWebSocketServer(vm, vm.config.GetStreamingBacklogSize())
+	pubsubConfig := pubsub.NewDefaultServerConfig()
+	pubsubConfig.MaxPendingMessages = vm.config.GetStreamingBacklogSize()
+	pubsubConfig.MaxConnections = vm.config.GetStreamingMaxConnections()
+	pubsubConfig.MaxConnectionsPerIP = vm.config.GetStreamingMaxConnectionsPerIP()
+	pubsubConfig.AuthTokens = vm.config.GetStreamingAuthTokens()
+	webSocketServer, pubsubServer := rpc.NewWebSocketServer(vm, pubsubConfig, vm.config.GetStreamingReplaySize())
 	vm.webSocketServer = webSocketServer
+	vm.pubsubServer = pubsubServer
+
+	// Apply access control and request limits to all HTTP handlers
+	// (including those registered by the [Controller]). The WebSocket server
+	// authenticates and limits its own connections.
+	access := &rpc.AccessConfig{
+		AdminTokens:        vm.config.GetAdminAPITokens(),
+		CORSAllowedOrigins: vm.config.GetCORSAllowedOrigins(),
+		CORSMaxAge:         rpc.DefaultCORSMaxAge,
+	}
+	limiter := rpc.NewLimiter(vm.config.GetRPCLimitConfig())
+	for endpoint, handler := range vm.handlers {
+		vm.handlers[endpoint] = access.Wrap(rpc.NamespaceOf(endpoint), limiter.Wrap(handler))
+	}
 	vm.handlers[rpc.WebSocketEndpoint] = pubsubServer
 	return nil
 }
@@ -424,7 +602,7 @@ func (vm *VM) markReady() {
 	// We can begin partailly verifying blocks here because
 	// we have the full state but can't detect duplicate transactions
 	// because we haven't yet observed a full [ValidityWindow].
-	vm.snowCtx.Log.Info("state sync client ready")
+	vm.Logger().Info("state sync client ready")
 
 	// Wait for a full [ValidityWindow] before
 	// we are willing to vote on blocks.
@@ -433,26 +611,51 @@ func (vm *VM) markReady() {
 		return
 	case <-vm.seenValidityWindow:
 	}
-	vm.snowCtx.Log.Info("validity window ready")
+	vm.Logger().Info("validity window ready")
 	if vm.stateSyncClient.Started() {
 		vm.toEngine <- common.StateSyncDone
 	}
 	close(vm.ready)
 
 	// Mark node ready and attempt to build a block.
-	vm.snowCtx.Log.Info(
+	vm.Logger().Info(
 		"node is now ready",
 		zap.Bool("synced", vm.stateSyncClient.Started()),
 	)
+	vm.restoreMempool(context.TODO())
 	vm.checkActivity(context.TODO())
 }
 
+// restoreMempool re-submits any transactions that were in the mempool
+// when the node last shut down.
+func (vm *VM) restoreMempool(ctx context.Context) {
+	txs, err := vm.PopDiskMempool()
+	if err != nil {
+		vm.Logger().Warn("unable to load persisted mempool", zap.Error(err))
+		return
+	}
+	if len(txs) == 0 {
+		return
+	}
+	var restored int
+	for _, err := range vm.Submit(ctx, true, txs) {
+		if err == nil {
+			restored++
+		}
+	}
+	vm.Logger().Info(
+		"restored persisted mempool",
+		zap.Int("txs", len(txs)),
+		zap.Int("restored", restored),
+	)
+}
+
 func (vm *VM) isReady() bool {
 	select {
 	case <-vm.ready:
 		return true
 	default:
-		vm.snowCtx.Log.Info("node is not ready yet")
+		vm.Logger().Info("node is not ready yet")
 		return false
 	}
 }
@@ -470,7 +673,7 @@ func (vm *VM) ReadState(ctx context.Context, keys [][]byte) ([][]byte, []error)
 	return vm.stateDB.GetValues(ctx, keys)
 }
 
-func (vm *VM) SetState(_ context.Context, state snow.State) error {
+func (vm *VM) SetState(ctx context.Context, state snow.State) error {
 	switch state {
 	case snow.StateSyncing:
 		vm.Logger().Info("state sync started")
@@ -506,7 +709,7 @@ func (vm *VM) SetState(_ context.Context, state snow.State) error {
 		// Backfill seen transactions, if any. This will exit as soon as we reach
 		// a block we no longer have on disk or if we have walked back the full
 		// [ValidityWindow].
-		vm.backfillSeenTransactions()
+		vm.backfillSeenTransactions(ctx)
 
 		// Trigger that bootstrapping has started
 		vm.Logger().Info("bootstrapping started", zap.Bool("state sync started", syncStarted))
@@ -548,6 +751,8 @@ func (vm *VM) onNormalOperationsStarted() error {
 
 // implements "block.ChainVM.common.VM"
 func (vm *VM) Shutdown(ctx context.Context) error {
+	// Closing [vm.stop] also prevents any new transactions from being
+	// submitted.
 	close(vm.stop)
 
 	// Shutdown state sync client if still running
@@ -556,17 +761,33 @@ func (vm *VM) Shutdown(ctx context.Context) error {
 	}
 
 	// Process remaining accepted blocks before shutdown
+	//
+	// If we time out, we can't close the DBs because the acceptor may still be
+	// writing to them.
+	drainCtx, cancel := context.WithTimeout(ctx, vm.config.GetShutdownTimeout())
+	defer cancel()
 	close(vm.acceptedQueue)
-	<-vm.acceptorDone
+	select {
+	case <-vm.acceptorDone:
+	case <-drainCtx.Done():
+		vm.Logger().Error("unable to process remaining accepted blocks", zap.Error(ErrShutdownTimeout))
+		return ErrShutdownTimeout
+	}
+
+	// Deliver any pending notifications for accepted blocks to subscribers
+	if vm.pubsubServer != nil {
+		if err := vm.pubsubServer.Close(drainCtx); err != nil {
+			vm.Logger().Warn("unable to flush websocket connections", zap.Error(err))
+		}
+	}
 
 	// Shutdown other async VM mechanisms
 	vm.warpManager.Done()
 	vm.builder.Done()
 	vm.gossiper.Done()
 	vm.authVerifiers.Stop()
-	if vm.profiler != nil {
-		vm.profiler.Shutdown()
-	}
+	vm.profiles.Shutdown()
+	vm.stopContinuousProfiler()
 
 	// Shutdown controller once all mechanisms that could invoke it have
 	// shutdown.
@@ -574,10 +795,28 @@ func (vm *VM) Shutdown(ctx context.Context) error {
 		return err
 	}
 
-	// Close DBs
 	if vm.snowCtx == nil {
 		return nil
 	}
+
+	// Persist the mempool so that pending transactions are not lost across
+	// restarts
+	txs := []*chain.Transaction{}
+	for {
+		tx, ok := vm.mempool.PopNext(ctx)
+		if !ok {
+			break
+		}
+		txs = append(txs, tx)
+	}
+	if err := vm.PutDiskMempool(txs); err != nil {
+		return err
+	}
+	vm.Logger().Info("persisted mempool", zap.Int("txs", len(txs)))
+
+	// Close DBs (waiting for any compaction in progress)
+	vm.dbL.Lock()
+	defer vm.dbL.Unlock()
 	if err := vm.vmDB.Close(); err != nil {
 		return err
 	}
@@ -679,7 +918,7 @@ func (vm *VM) ParseBlock(ctx context.Context, source []byte) (snowman.Block, err
 	// If we have seen this block before, return it with the most
 	// up-to-date info
 	if oldBlk, err := vm.GetStatelessBlock(ctx, id); err == nil {
-		vm.snowCtx.Log.Debug("returning previously parsed block", zap.Stringer("id", oldBlk.ID()))
+		vm.Logger().Debug("returning previously parsed block", zap.Stringer("id", oldBlk.ID()))
 		return oldBlk, nil
 	}
 
@@ -695,11 +934,11 @@ func (vm *VM) ParseBlock(ctx context.Context, source []byte) (snowman.Block, err
 		vm,
 	)
 	if err != nil {
-		vm.snowCtx.Log.Error("could not parse block", zap.Stringer("blkID", id), zap.Error(err))
+		vm.Logger().Error("could not parse block", zap.Stringer("blkID", id), zap.Error(err))
 		return nil, err
 	}
 	vm.parsedBlocks.Put(id, newBlk)
-	vm.snowCtx.Log.Info(
+	vm.Logger().Info(
 		"parsed block",
 		zap.Stringer("id", newBlk.ID()),
 		zap.Uint64("height", newBlk.Hght),
@@ -708,12 +947,19 @@ func (vm *VM) ParseBlock(ctx context.Context, source []byte) (snowman.Block, err
 }
 
 func (vm *VM) buildBlock(ctx context.Context, blockContext *smblock.Context) (snowman.Block, error) {
+	// API nodes are never expected to propose blocks, even if they are
+	// registered as a validator.
+	if vm.config.GetAPINode() {
+		vm.Logger().Warn("not building block", zap.Error(ErrAPINode))
+		return nil, ErrAPINode
+	}
+
 	// If the node isn't ready, we should exit.
 	//
 	// We call [QueueNotify] when the VM becomes ready, so exiting
 	// early here should not cause us to stop producing blocks.
 	if !vm.isReady() {
-		vm.snowCtx.Log.Warn("not building block", zap.Error(ErrNotReady))
+		vm.Logger().Warn("not building block", zap.Error(ErrNotReady))
 		return nil, ErrNotReady
 	}
 
@@ -727,21 +973,21 @@ func (vm *VM) buildBlock(ctx context.Context, blockContext *smblock.Context) (sn
 	processingBlocks := len(vm.verifiedBlocks)
 	vm.verifiedL.RUnlock()
 	if processingBlocks > vm.config.GetProcessingBuildSkip() {
-		vm.snowCtx.Log.Warn("not building block", zap.Error(ErrTooManyProcessing))
+		vm.Logger().Warn("not building block", zap.Error(ErrTooManyProcessing))
 		return nil, ErrTooManyProcessing
 	}
 
 	// Build block and store as parsed
 	preferredBlk, err := vm.GetStatelessBlock(ctx, vm.preferred)
 	if err != nil {
-		vm.snowCtx.Log.Warn("unable to get preferred block", zap.Error(err))
+		vm.Logger().Warn("unable to get preferred block", zap.Error(err))
 		return nil, err
 	}
 	blk, err := chain.BuildBlock(ctx, vm, preferredBlk, blockContext)
 	if err != nil {
 		// This is a DEBUG log because BuildBlock may fail before
 		// the min build gap (especially when there are no transactions).
-		vm.snowCtx.Log.Debug("BuildBlock failed", zap.Error(err))
+		vm.Logger().Debug("BuildBlock failed", zap.Error(err))
 		return nil, err
 	}
 	vm.parsedBlocks.Put(blk.ID(), blk)
@@ -749,10 +995,10 @@ func (vm *VM) buildBlock(ctx context.Context, blockContext *smblock.Context) (sn
 }
 
 // implements "block.ChainVM"
-func (vm *VM) BuildBlock(ctx context.Context) (snowman.Block, error) {
+func (vm *VM) BuildBlock(ctx context.Context) (blk snowman.Block, err error) {
 	start := time.Now()
 	defer func() {
-		vm.metrics.blockBuild.Observe(float64(time.Since(start)))
+		vm.recordBlockBuild(time.Since(start), err)
 	}()
 
 	ctx, span := vm.tracer.Start(ctx, "VM.BuildBlock")
@@ -762,10 +1008,10 @@ func (vm *VM) BuildBlock(ctx context.Context) (snowman.Block, error) {
 }
 
 // implements "block.BuildBlockWithContextChainVM"
-func (vm *VM) BuildBlockWithContext(ctx context.Context, blockContext *smblock.Context) (snowman.Block, error) {
+func (vm *VM) BuildBlockWithContext(ctx context.Context, blockContext *smblock.Context) (blk snowman.Block, err error) {
 	start := time.Now()
 	defer func() {
-		vm.metrics.blockBuild.Observe(float64(time.Since(start)))
+		vm.recordBlockBuild(time.Since(start), err)
 	}()
 
 	ctx, span := vm.tracer.Start(ctx, "VM.BuildBlockWithContext")
@@ -790,6 +1036,13 @@ func (vm *VM) Submit(
 		return []error{ErrNotReady}
 	}
 
+	// Transactions submitted during shutdown would not be persisted.
+	select {
+	case <-vm.stop:
+		return []error{ErrShuttingDown}
+	default:
+	}
+
 	// Create temporary execution context
 	blk, err := vm.GetStatelessBlock(ctx, vm.preferred)
 	if err != nil {
@@ -800,13 +1053,15 @@ func (vm *VM) Submit(
 		// This will error if a block does not yet have processed state.
 		return []error{err}
 	}
-	feeRaw, err := view.GetValue(ctx, chain.FeeKey(vm.StateManager().FeeKey()))
+	feeManager, _, err := chain.GetFeeManager(ctx, vm.StateManager(), view)
 	if err != nil {
 		return []error{err}
 	}
-	feeManager := chain.NewFeeManager(feeRaw)
 	now := time.Now().UnixMilli()
-	r := vm.c.Rules(now)
+	r, err := chain.StateRules(ctx, vm, vm.StateManager(), view, now)
+	if err != nil {
+		return []error{err}
+	}
 	nextFeeManager, err := feeManager.ComputeNext(blk.Tmstmp, now, r)
 	if err != nil {
 		return []error{err}
@@ -814,12 +1069,15 @@ func (vm *VM) Submit(
 
 	// Find repeats
 	oldestAllowed := now - r.GetValidityWindow()
-	repeats, err := blk.IsRepeat(ctx, oldestAllowed, txs, set.NewBits(), true)
+	repeats, err := blk.IsRepeat(ctx, oldestAllowed, chain.OldestAllowedHeight(r, blk.Hght+1), txs, set.NewBits(), true)
 	if err != nil {
 		return []error{err}
 	}
 
-	validTxs := []*chain.Transaction{}
+	var (
+		validTxs     = []*chain.Transaction{}
+		congestedTxs = []*chain.Transaction{}
+	)
 	for i, tx := range txs {
 		// Check if transaction is a repeat before doing any extra work
 		if repeats.Contains(i) {
@@ -837,7 +1095,12 @@ func (vm *VM) Submit(
 		}
 
 		// Ensure state keys are valid
-		_, err := tx.StateKeys(vm.c.StateManager())
+		_, err := tx.StateKeys(vm.c.StateManager(), r)
+		if errors.Is(err, chain.ErrAccessListMismatch) {
+			vm.metrics.accessListMismatches.Inc()
+			errs = append(errs, err)
+			continue
+		}
 		if err != nil {
 			errs = append(errs, ErrNotAdded)
 			continue
@@ -856,7 +1119,7 @@ func (vm *VM) Submit(
 				// a transaction in listeners. Every other case may still end up with
 				// the transaction in a block.
 				if err := vm.webSocketServer.RemoveTx(txID, err); err != nil {
-					vm.snowCtx.Log.Warn("unable to remove tx from webSocketServer", zap.Error(err))
+					vm.Logger().Warn("unable to remove tx from webSocketServer", zap.Error(err))
 				}
 				errs = append(errs, err)
 				continue
@@ -872,23 +1135,88 @@ func (vm *VM) Submit(
 		// Note, [PreExecute] ensures that the pending transaction does not have
 		// an expiry time further ahead than [ValidityWindow]. This ensures anything
 		// added to the [Mempool] is immediately executable.
-		if err := tx.PreExecute(ctx, nextFeeManager, vm.c.StateManager(), r, view, now); err != nil {
+		if err := tx.CheckDeprecated(r, blk.Hght+1); err != nil {
 			errs = append(errs, err)
 			continue
 		}
+		if err := tx.PreExecute(ctx, nextFeeManager, vm.c.StateManager(), r, view, now, blk.Hght+1); err != nil {
+			// Transactions ahead of the next nonce of their sponsor are held
+			// until the gap is filled (if it isn't too large)
+			if !errors.Is(err, chain.ErrNonceTooHigh) || !vm.withinNonceGap(ctx, view, tx) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if err := chain.PreExecuteHook(ctx, vm.Hooks(), r, view, now, blk.Hght+1, tx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		congested, err := vm.congested(tx, nextFeeManager, r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch {
+		case congested && vm.congestionPolicy == RejectCongested:
+			errs = append(errs, ErrCongested)
+			continue
+		case congested && vm.congestionPolicy == DeprioritizeCongested:
+			congestedTxs = append(congestedTxs, tx)
+		default:
+			validTxs = append(validTxs, tx)
+		}
+		tx.SetArrival(now)
 		errs = append(errs, nil)
-		validTxs = append(validTxs, tx)
 	}
 	vm.mempool.Add(ctx, validTxs)
+	vm.mempool.AddDeferred(ctx, congestedTxs)
+	validTxs = append(validTxs, congestedTxs...)
+	if err := vm.webSocketServer.AddMempoolTxs(validTxs); err != nil {
+		vm.Logger().Warn("unable to publish mempool txs", zap.Error(err))
+	}
 	vm.checkActivity(ctx)
 	vm.metrics.mempoolSize.Set(float64(vm.mempool.Len(ctx)))
 	return errs
 }
 
+// congested returns true if the dominant dimension of [tx] is over target in
+// the fee window of [feeManager]. It is only computed if the
+// [CongestionPolicy] of the VM is not [AcceptCongested].
+func (vm *VM) congested(tx *chain.Transaction, feeManager *chain.FeeManager, r chain.Rules) (bool, error) {
+	if vm.congestionPolicy == AcceptCongested {
+		return false, nil
+	}
+	units, err := tx.MaxUnits(vm.c.StateManager(), r)
+	if err != nil {
+		return false, err
+	}
+	d, congested := feeManager.Congested(units, r)
+	if congested {
+		vm.metrics.txsCongested.Inc()
+		vm.Logger().Debug(
+			"submitted tx is congested",
+			zap.Stringer("txID", tx.ID()),
+			zap.Int("dimension", int(d)),
+		)
+	}
+	return congested, nil
+}
+
+// withinNonceGap returns true if the nonce of [tx] is at most
+// [Config.GetMempoolMaxNonceGap] ahead of the next nonce of its sponsor in
+// [im].
+func (vm *VM) withinNonceGap(ctx context.Context, im state.Immutable, tx *chain.Transaction) bool {
+	next, err := chain.GetNonce(ctx, vm.c.StateManager(), im, tx.Sponsor())
+	if err != nil {
+		return false
+	}
+	return tx.Base.Nonce-next <= uint64(vm.config.GetMempoolMaxNonceGap())
+}
+
 // "SetPreference" implements "block.ChainVM"
 // replaces "core.SnowmanVM.SetPreference"
 func (vm *VM) SetPreference(_ context.Context, id ids.ID) error {
-	vm.snowCtx.Log.Debug("set preference", zap.Stringer("id", id))
+	vm.Logger().Debug("set preference", zap.Stringer("id", id))
 	vm.preferred = id
 	return nil
 }
@@ -1029,12 +1357,12 @@ func (vm *VM) GetBlockIDAtHeight(_ context.Context, height uint64) (ids.ID, erro
 // backfillSeenTransactions makes a best effort to populate [vm.seen]
 // with whatever transactions we already have on-disk. This will lead
 // a node to becoming ready faster during a restart.
-func (vm *VM) backfillSeenTransactions() {
+func (vm *VM) backfillSeenTransactions(ctx context.Context) {
 	// Exit early if we don't have any blocks other than genesis (which
 	// contains no transactions)
 	blk := vm.lastAccepted
 	if blk.Hght == 0 {
-		vm.snowCtx.Log.Info("no seen transactions to backfill")
+		vm.Logger().Info("no seen transactions to backfill")
 		vm.startSeenTime = 0
 		vm.seenValidityWindowOnce.Do(func() {
 			close(vm.seenValidityWindow)
@@ -1043,10 +1371,11 @@ func (vm *VM) backfillSeenTransactions() {
 	}
 
 	// Backfill [vm.seen] with lifeline worth of transactions
-	r := vm.Rules(vm.lastAccepted.Tmstmp)
+	r := vm.acceptedRules(ctx, vm.lastAccepted.Tmstmp)
 	oldest := uint64(0)
 	for {
-		if vm.lastAccepted.Tmstmp-blk.Tmstmp > r.GetValidityWindow() {
+		if vm.lastAccepted.Tmstmp-blk.Tmstmp > r.GetValidityWindow() &&
+			vm.lastAccepted.Hght-blk.Hght > r.GetHeightValidityWindow() {
 			// We are assured this function won't be running while we accept
 			// a block, so we don't need to protect against closing this channel
 			// twice.
@@ -1058,7 +1387,9 @@ func (vm *VM) backfillSeenTransactions() {
 
 		// It is ok to add transactions from newest to oldest
 		vm.seen.Add(blk.Txs)
+		vm.seenHeights.Add(blk.Txs)
 		vm.startSeenTime = blk.Tmstmp
+		vm.startSeenHeight = blk.Hght
 		oldest = blk.Hght
 
 		// Exit early if next block to fetch is genesis (which contains no
@@ -1067,6 +1398,7 @@ func (vm *VM) backfillSeenTransactions() {
 			// If we have walked back from the last accepted block to genesis, then
 			// we can be sure we have all required transactions to start validation.
 			vm.startSeenTime = 0
+			vm.startSeenHeight = 0
 			vm.seenValidityWindowOnce.Do(func() {
 				close(vm.seenValidityWindow)
 			})
@@ -1076,7 +1408,7 @@ func (vm *VM) backfillSeenTransactions() {
 		// Set next blk in lookback
 		tblk, err := vm.GetStatelessBlock(context.Background(), blk.Prnt)
 		if err != nil {
-			vm.snowCtx.Log.Info("could not load block, exiting backfill",
+			vm.Logger().Info("could not load block, exiting backfill",
 				zap.Uint64("height", blk.Height()-1),
 				zap.Stringer("blockID", blk.Prnt),
 				zap.Error(err),
@@ -1085,7 +1417,7 @@ func (vm *VM) backfillSeenTransactions() {
 		}
 		blk = tblk
 	}
-	vm.snowCtx.Log.Info(
+	vm.Logger().Info(
 		"backfilled seen txs",
 		zap.Uint64("start", oldest),
 		zap.Uint64("finish", vm.lastAccepted.Hght),
@@ -1101,13 +1433,13 @@ func (vm *VM) loadAcceptedBlocks(ctx context.Context) error {
 	for i := start; i <= vm.lastAccepted.Hght; i++ {
 		blk, err := vm.GetDiskBlock(ctx, i)
 		if err != nil {
-			vm.snowCtx.Log.Info("could not find block on-disk", zap.Uint64("height", i))
+			vm.Logger().Info("could not find block on-disk", zap.Uint64("height", i))
 			continue
 		}
 		vm.acceptedBlocksByID.Put(blk.ID(), blk)
 		vm.acceptedBlocksByHeight.Put(blk.Height(), blk.ID())
 	}
-	vm.snowCtx.Log.Info("loaded blocks from disk",
+	vm.Logger().Info("loaded blocks from disk",
 		zap.Uint64("start", start),
 		zap.Uint64("finish", vm.lastAccepted.Hght),
 	)
@@ -1120,6 +1452,6 @@ func (vm *VM) loadAcceptedBlocks(ctx context.Context) error {
 // the shutdown will complete given that we have encountered a fatal
 // issue. It is better to ensure we exit to surface the error.
 func (vm *VM) Fatal(msg string, fields ...zap.Field) {
-	vm.snowCtx.Log.Fatal(msg, fields...)
+	vm.Logger().Fatal(msg, fields...)
 	panic("fatal error")
 }
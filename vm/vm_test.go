@@ -5,7 +5,9 @@ package vm
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database/memdb"
@@ -19,7 +21,10 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/config"
 	"github.com/ava-labs/hypersdk/emap"
+	"github.com/ava-labs/hypersdk/logs"
 	"github.com/ava-labs/hypersdk/mempool"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/slo"
 	"github.com/ava-labs/hypersdk/trace"
 )
 
@@ -43,6 +48,7 @@ func TestBlockCache(t *testing.T) {
 	controller := NewMockController(ctrl)
 	vm := VM{
 		snowCtx: &snow.Context{Log: logging.NoLog{}, Metrics: ametrics.NewOptionalGatherer()},
+		logs:    logs.NewManager(logging.NoLog{}, logging.Info),
 		config:  &config.Config{},
 
 		vmDB: memdb.New(),
@@ -52,7 +58,8 @@ func TestBlockCache(t *testing.T) {
 		acceptedBlocksByHeight: bByHeight,
 
 		verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
-		seen:           emap.NewEMap[*chain.Transaction](),
+		seen:           emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{Expiry: chain.ExpiryTimestamp}),
+		seenHeights:    emap.NewEMapWithConfig(emap.Config[*chain.Transaction]{Expiry: chain.ExpiryHeight}),
 		mempool:        mempool.New[*chain.Transaction](tracer, 100, 32, nil),
 		acceptedQueue:  make(chan *chain.StatelessBlock, 1024), // don't block on queue
 		c:              controller,
@@ -71,7 +78,9 @@ func TestBlockCache(t *testing.T) {
 	ctx := context.TODO()
 	rules := chain.NewMockRules(ctrl)
 	rules.EXPECT().GetValidityWindow().Return(int64(60))
+	rules.EXPECT().GetHeightValidityWindow().Return(uint64(0)).AnyTimes()
 	controller.EXPECT().Rules(gomock.Any()).Return(rules)
+	controller.EXPECT().StateManager().Return(nil) // doesn't support rules overrides
 	vm.Accepted(ctx, blk)
 
 	// we have not set up any persistent db
@@ -80,3 +89,26 @@ func TestBlockCache(t *testing.T) {
 	require.NoError(err)
 	require.Equal(blk, blk2)
 }
+
+func TestRecordRPCRequest(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := slo.New(slo.DefaultConfig())
+	require.NoError(err)
+	vm := VM{slo: tracker}
+
+	// Only internal errors and unavailability count as failures
+	vm.RecordRPCRequest("", nil, time.Millisecond)
+	vm.RecordRPCRequest("", fmt.Errorf("%w: latest", rpc.ErrUnknownBlockTag), time.Millisecond)
+	vm.RecordRPCRequest("", fmt.Errorf("%w: tx 0", chain.ErrInvalidSignature), time.Millisecond)
+	vm.RecordRPCRequest("", ErrNotReady, time.Millisecond)
+	for _, objective := range vm.SLOReport().Objectives {
+		if objective.Name != slo.RPC {
+			continue
+		}
+		require.Equal(uint64(4), objective.Total)
+		require.Equal(uint64(1), objective.Failed)
+		return
+	}
+	require.FailNow("missing rpc objective")
+}
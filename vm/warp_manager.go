@@ -10,10 +10,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
@@ -25,6 +27,7 @@ import (
 const (
 	maxWarpResponse   = bls.PublicKeyLen + bls.SignatureLen
 	minGatherInterval = 30 * 60 // 30 minutes
+	minResignInterval = 60      // 1 minute
 	initialBackoff    = 2       // give time for others to sign
 	backoffIncrease   = 5
 	maxRetries        = 10
@@ -43,6 +46,9 @@ type WarpManager struct {
 	pendingJobs *heap.Heap[*signatureJob, int64]
 	jobs        map[uint32]*signatureJob
 
+	// resigned holds the last time (in seconds) each message was re-signed
+	resigned *cache.LRU[ids.ID, int64]
+
 	done chan struct{}
 }
 
@@ -60,6 +66,7 @@ func NewWarpManager(vm *VM) *WarpManager {
 		vm:          vm,
 		pendingJobs: heap.New[*signatureJob, int64](64, true),
 		jobs:        map[uint32]*signatureJob{},
+		resigned:    &cache.LRU[ids.ID, int64]{Size: 1024},
 		done:        make(chan struct{}),
 	}
 }
@@ -77,26 +84,33 @@ func (w *WarpManager) Run(appSender common.AppSender) {
 		case <-t.C:
 			w.l.Lock()
 			now := time.Now().Unix()
+			ready := map[uint32]*signatureJob{}
 			for w.pendingJobs.Len() > 0 && len(w.jobs) < maxOutstanding {
 				first := w.pendingJobs.First()
 				if first.Val > now {
 					break
 				}
 				w.pendingJobs.Pop()
+				requestID := w.requestID
+				w.requestID++
+				w.jobs[requestID] = first.Item
+				ready[requestID] = first.Item
+			}
+			l := w.pendingJobs.Len()
+			w.l.Unlock()
 
-				// Send request
-				job := first.Item
-				if err := w.request(context.Background(), job); err != nil {
-					w.vm.snowCtx.Log.Error(
+			// Send requests without holding [w.l], as responses may be
+			// handled before the request returns
+			for requestID, job := range ready {
+				if err := w.request(context.Background(), requestID, job); err != nil {
+					w.vm.Logger().Error(
 						"unable to request signature",
 						zap.Stringer("nodeID", job.nodeID),
 						zap.Error(err),
 					)
 				}
 			}
-			l := w.pendingJobs.Len()
-			w.l.Unlock()
-			w.vm.snowCtx.Log.Debug("checked for ready jobs", zap.Int("pending", l))
+			w.vm.Logger().Debug("checked for ready jobs", zap.Int("pending", l))
 		case <-w.vm.stop:
 			w.vm.Logger().Info("stopping warp manager")
 			return
@@ -114,21 +128,77 @@ func (w *WarpManager) Run(appSender common.AppSender) {
 func (w *WarpManager) GatherSignatures(ctx context.Context, txID ids.ID, msg []byte) {
 	lastFetch, err := w.vm.GetWarpFetch(txID)
 	if err != nil {
-		w.vm.snowCtx.Log.Error("unable to get last fetch", zap.Error(err))
+		w.vm.Logger().Error("unable to get last fetch", zap.Error(err))
 		return
 	}
 	if time.Now().Unix()-lastFetch < minGatherInterval {
-		w.vm.snowCtx.Log.Error("skipping fetch too recent", zap.Stringer("txID", txID))
+		w.vm.Logger().Error("skipping fetch too recent", zap.Stringer("txID", txID))
 		return
 	}
 	if err := w.vm.StoreWarpFetch(txID); err != nil {
-		w.vm.snowCtx.Log.Error("unable to get last fetch", zap.Error(err))
+		w.vm.Logger().Error("unable to get last fetch", zap.Error(err))
 		return
 	}
+	if _, err := w.gather(ctx, txID, msg); err != nil {
+		w.vm.Logger().Error("unable to gather signatures", zap.Stringer("txID", txID), zap.Error(err))
+	}
+}
+
+// ResignMessage asks the current validators that have not signed [msg]
+// (emitted by [txID]) for their signatures and returns the signature of this
+// node (or nil if this is an API node) and the number of validators asked.
+//
+// Signatures don't expire but a relayer can only aggregate those of the
+// validator set that is current when the message is delivered, so the
+// signatures gathered when [msg] was accepted may not be enough after the set
+// changes. ResignMessage ignores [minGatherInterval] so that relayers can
+// request signatures from new validators right away, but can only be called
+// once every [minResignInterval] for each message.
+func (w *WarpManager) ResignMessage(
+	ctx context.Context,
+	txID ids.ID,
+	msg *warp.UnsignedMessage,
+) (*chain.WarpSignature, int, error) {
+	now := time.Now().Unix()
+	w.l.Lock()
+	last, ok := w.resigned.Get(txID)
+	if ok && now-last < minResignInterval {
+		w.l.Unlock()
+		return nil, 0, ErrResignTooFrequent
+	}
+	w.resigned.Put(txID, now)
+	w.l.Unlock()
+
+	var signature *chain.WarpSignature
+	if !w.vm.config.GetAPINode() {
+		sig, err := w.signature(txID, msg)
+		if err != nil {
+			return nil, 0, err
+		}
+		signature = sig
+	}
+	if err := w.vm.StoreWarpFetch(txID); err != nil {
+		return nil, 0, err
+	}
+	requested, err := w.gather(ctx, txID, msg.Bytes())
+	if err != nil {
+		return nil, 0, err
+	}
+	w.vm.Logger().Info(
+		"re-signing warp message",
+		zap.Stringer("txID", txID),
+		zap.Int("requested", requested),
+	)
+	return signature, requested, nil
+}
+
+// gather enqueues a job to fetch the signature of [msg] from each current
+// validator that we don't have a signature from (unless one is already
+// pending) and returns the number of signatures being fetched.
+func (w *WarpManager) gather(ctx context.Context, txID ids.ID, msg []byte) (int, error) {
 	height, err := w.vm.snowCtx.ValidatorState.GetCurrentHeight(ctx)
 	if err != nil {
-		w.vm.snowCtx.Log.Error("unable to get current p-chain height", zap.Error(err))
-		return
+		return 0, err
 	}
 	validators, err := w.vm.snowCtx.ValidatorState.GetValidatorSet(
 		ctx,
@@ -136,14 +206,14 @@ func (w *WarpManager) GatherSignatures(ctx context.Context, txID ids.ID, msg []b
 		w.vm.snowCtx.SubnetID,
 	)
 	if err != nil {
-		w.vm.snowCtx.Log.Error("unable to get validator set", zap.Error(err))
-		return
+		return 0, err
 	}
+	requested := 0
 	for nodeID, validator := range validators {
 		// Only request from validators that have registered BLS public keys and
 		// that we have not already gotten a signature from.
 		if validator.PublicKey == nil {
-			w.vm.snowCtx.Log.Info(
+			w.vm.Logger().Info(
 				"skipping fetch for validator with no registered public key",
 				zap.Stringer("nodeID", nodeID),
 				zap.Uint64("pchain height", height),
@@ -152,13 +222,14 @@ func (w *WarpManager) GatherSignatures(ctx context.Context, txID ids.ID, msg []b
 		}
 		previousSignature, err := w.vm.GetWarpSignature(txID, validator.PublicKey)
 		if err != nil {
-			w.vm.snowCtx.Log.Error("unable to fetch previous signature", zap.Error(err))
-			return
+			return requested, err
 		}
 		if previousSignature != nil {
 			continue
 		}
 
+		requested++
+
 		idb := make([]byte, consts.IDLen+consts.NodeIDLen)
 		copy(idb, txID[:])
 		copy(idb[consts.IDLen:], nodeID.Bytes())
@@ -183,23 +254,39 @@ func (w *WarpManager) GatherSignatures(ctx context.Context, txID ids.ID, msg []b
 			Index: w.pendingJobs.Len(),
 		})
 		w.l.Unlock()
-		w.vm.snowCtx.Log.Debug(
+		w.vm.Logger().Debug(
 			"enqueued fetch job",
 			zap.Stringer("nodeID", nodeID),
 			zap.Stringer("txID", txID),
 		)
 	}
+	return requested, nil
 }
 
-// you must hold [w.l] when calling this function
+// signature returns the signature of [msg] by this node, signing and storing
+// it if we have not already.
+func (w *WarpManager) signature(txID ids.ID, msg *warp.UnsignedMessage) (*chain.WarpSignature, error) {
+	sig, err := w.vm.GetWarpSignature(txID, w.vm.snowCtx.PublicKey)
+	if err != nil || sig != nil {
+		return sig, err
+	}
+	rSig, err := w.vm.snowCtx.WarpSigner.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.vm.StoreWarpSignature(txID, w.vm.snowCtx.PublicKey, rSig); err != nil {
+		return nil, err
+	}
+	return chain.NewWarpSignature(w.vm.pkBytes, rSig), nil
+}
+
+// [j] must be registered in [w.jobs] under [requestID] before calling this
+// function
 func (w *WarpManager) request(
 	ctx context.Context,
+	requestID uint32,
 	j *signatureJob,
 ) error {
-	requestID := w.requestID
-	w.requestID++
-	w.jobs[requestID] = j
-
 	return w.appSender.SendAppRequest(
 		ctx,
 		set.Of(j.nodeID),
@@ -218,12 +305,12 @@ func (w *WarpManager) AppRequest(
 	var txID ids.ID
 	rp.UnpackID(true, &txID)
 	if err := rp.Err(); err != nil {
-		w.vm.snowCtx.Log.Warn("unable to unpack request", zap.Error(err))
+		w.vm.Logger().Warn("unable to unpack request", zap.Error(err))
 		return nil
 	}
 	sig, err := w.vm.GetWarpSignature(txID, w.vm.snowCtx.PublicKey)
 	if err != nil {
-		w.vm.snowCtx.Log.Warn("could not fetch warp signature", zap.Error(err))
+		w.vm.Logger().Warn("could not fetch warp signature", zap.Error(err))
 		return nil
 	}
 	if sig == nil {
@@ -231,29 +318,21 @@ func (w *WarpManager) AppRequest(
 		// have been offline when message was accepted)
 		msg, err := w.vm.GetOutgoingWarpMessage(txID)
 		if msg == nil || err != nil {
-			w.vm.snowCtx.Log.Warn("could not get outgoing warp message", zap.Error(err))
+			w.vm.Logger().Warn("could not get outgoing warp message", zap.Error(err))
 			return nil
 		}
-		rSig, err := w.vm.snowCtx.WarpSigner.Sign(msg)
+		sig, err = w.signature(txID, msg)
 		if err != nil {
-			w.vm.snowCtx.Log.Warn("could not sign outgoing warp message", zap.Error(err))
+			w.vm.Logger().Warn("could not sign outgoing warp message", zap.Error(err))
 			return nil
 		}
-		if err := w.vm.StoreWarpSignature(txID, w.vm.snowCtx.PublicKey, rSig); err != nil {
-			w.vm.snowCtx.Log.Warn("could not store warp signature", zap.Error(err))
-			return nil
-		}
-		sig = &chain.WarpSignature{
-			PublicKey: w.vm.pkBytes,
-			Signature: rSig,
-		}
 	}
 	size := len(sig.PublicKey) + len(sig.Signature)
 	wp := codec.NewWriter(size, maxWarpResponse)
 	wp.PackFixedBytes(sig.PublicKey)
 	wp.PackFixedBytes(sig.Signature)
 	if err := wp.Err(); err != nil {
-		w.vm.snowCtx.Log.Warn("could not encode warp signature", zap.Error(err))
+		w.vm.Logger().Warn("could not encode warp signature", zap.Error(err))
 		return nil
 	}
 	return w.appSender.SendAppResponse(ctx, nodeID, requestID, wp.Bytes())
@@ -275,13 +354,13 @@ func (w *WarpManager) HandleResponse(requestID uint32, msg []byte) error {
 	signature := make([]byte, bls.SignatureLen)
 	r.UnpackFixedBytes(bls.SignatureLen, &signature)
 	if err := r.Err(); err != nil {
-		w.vm.snowCtx.Log.Warn("could not decode warp signature", zap.Error(err))
+		w.vm.Logger().Warn("could not decode warp signature", zap.Error(err))
 		return nil
 	}
 
 	// Check public key is expected
 	if !bytes.Equal(publicKey, job.publicKey) {
-		w.vm.snowCtx.Log.Warn(
+		w.vm.Logger().Warn(
 			"public key mismatch",
 			zap.String("found", hex.EncodeToString(publicKey)),
 			zap.String("expected", hex.EncodeToString(job.publicKey)),
@@ -292,26 +371,26 @@ func (w *WarpManager) HandleResponse(requestID uint32, msg []byte) error {
 	// Check signature validity
 	pk, err := bls.PublicKeyFromBytes(publicKey)
 	if err != nil {
-		w.vm.snowCtx.Log.Warn("could not decode public key", zap.Error(err))
+		w.vm.Logger().Warn("could not decode public key", zap.Error(err))
 		return nil
 	}
 	sig, err := bls.SignatureFromBytes(signature)
 	if err != nil {
-		w.vm.snowCtx.Log.Warn("could not decode signature", zap.Error(err))
+		w.vm.Logger().Warn("could not decode signature", zap.Error(err))
 		return nil
 	}
 	if !bls.Verify(pk, sig, job.msg) {
-		w.vm.snowCtx.Log.Warn("could not verify signature")
+		w.vm.Logger().Warn("could not verify signature")
 		return nil
 	}
 
 	// Store in DB
 	if err := w.vm.StoreWarpSignature(job.txID, pk, signature); err != nil {
-		w.vm.snowCtx.Log.Warn("could not store warp signature", zap.Error(err))
+		w.vm.Logger().Warn("could not store warp signature", zap.Error(err))
 		return nil
 	}
 
-	w.vm.snowCtx.Log.Info(
+	w.vm.Logger().Info(
 		"fetched and stored signature",
 		zap.Stringer("txID", job.txID),
 		zap.Stringer(
@@ -334,7 +413,7 @@ func (w *WarpManager) HandleRequestFailed(requestID uint32) error {
 
 	// Drop if we've already retried too many times
 	if job.retry >= maxRetries {
-		w.vm.snowCtx.Log.Info(
+		w.vm.Logger().Info(
 			"fetch job failed",
 			zap.Stringer("nodeID", job.nodeID),
 			zap.Stringer("txID", job.txID),
@@ -0,0 +1,9 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workers
+
+type Metrics interface {
+	RecordWorkers(int) // number of live goroutines in the pool
+	RecordBusy(int)    // number of goroutines executing a task
+}
@@ -4,9 +4,15 @@
 package workers
 
 import (
+	"runtime"
 	"sync"
+	"time"
 )
 
+// DefaultIdleTimeout is how long a worker above the minimum pool size
+// waits for a task before exiting.
+const DefaultIdleTimeout = 5 * time.Second
+
 var (
 	_ Workers = (*ParallelWorkers)(nil)
 	_ Job     = (*ParallelJob)(nil)
@@ -16,14 +22,24 @@ var (
 //
 // Limit number of concurrent goroutines with resetable error
 // Ensure minimal overhead for parallel ops
+//
+// The pool starts with [minWorkers] goroutines and adds more (up to [count])
+// whenever a task is ready and no worker is idle. Workers above [minWorkers]
+// exit after waiting [idleTimeout] for a task.
 type ParallelWorkers struct {
-	count int               // Number of workers in the pool
-	queue chan *ParallelJob // A channel for passing jobs
+	count      int               // Maximum number of workers in the pool
+	minWorkers int               // Number of workers kept alive when idle
+	queue      chan *ParallelJob // A channel for passing jobs
+
+	idleTimeout time.Duration
+	metrics     Metrics
 
 	// tracking state
 	lock              sync.RWMutex
 	shouldShutdown    bool
 	triggeredShutdown bool
+	live              int // requires lock
+	busy              int // requires lock
 
 	// single job execution
 	err   error // requires lock
@@ -36,6 +52,9 @@ type ParallelWorkers struct {
 	stoppedWorkers chan struct{}
 }
 
+// NewParallel creates a pool of [workers] goroutines that never grows or
+// shrinks.
+//
 // Goroutines allocate a minimum of 2KB of memory, we can save this by reusing
 // the context. This is especially useful if the goroutine stack is expanded
 // during use.
@@ -43,9 +62,37 @@ type ParallelWorkers struct {
 // Current size: https://github.com/golang/go/blob/fa463cc96d797c218be4e218723f83be47e814c8/src/runtime/stack.go#L74-L75
 // Backstory: https://medium.com/a-journey-with-go/go-how-does-the-goroutine-stack-size-evolve-447fc02085e5
 func NewParallel(workers int, maxJobs int) Workers {
+	return newParallel(workers, workers, maxJobs, 0, nil)
+}
+
+// NewAdaptive creates a pool that keeps [minWorkers] goroutines alive and
+// grows to at most [maxWorkers] when tasks back up. [maxWorkers] is capped
+// by GOMAXPROCS, so the pool never runs more verification goroutines than
+// there are CPUs to schedule them on. [metrics] may be nil.
+func NewAdaptive(minWorkers, maxWorkers, maxJobs int, idleTimeout time.Duration, metrics Metrics) Workers {
+	if procs := runtime.GOMAXPROCS(0); maxWorkers > procs {
+		maxWorkers = procs
+	}
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
+	if minWorkers < 1 {
+		minWorkers = 1
+	}
+	if minWorkers > maxWorkers {
+		minWorkers = maxWorkers
+	}
+	return newParallel(minWorkers, maxWorkers, maxJobs, idleTimeout, metrics)
+}
+
+func newParallel(minWorkers, maxWorkers, maxJobs int, idleTimeout time.Duration, metrics Metrics) *ParallelWorkers {
 	w := &ParallelWorkers{
-		count: workers,
-		queue: make(chan *ParallelJob, maxJobs),
+		count:      maxWorkers,
+		minWorkers: minWorkers,
+		queue:      make(chan *ParallelJob, maxJobs),
+
+		idleTimeout: idleTimeout,
+		metrics:     metrics,
 
 		tasks:          make(chan func() error),
 		ackShutdown:    make(chan struct{}),
@@ -53,9 +100,11 @@ func NewParallel(workers int, maxJobs int) Workers {
 		stoppedWorkers: make(chan struct{}),
 	}
 	w.processQueue()
-	for i := 0; i < workers; i++ {
+	w.lock.Lock()
+	for i := 0; i < minWorkers; i++ {
 		w.startWorker()
 	}
+	w.lock.Unlock()
 	return w
 }
 
@@ -75,7 +124,7 @@ func (w *ParallelWorkers) processQueue() {
 			// Process tasks
 			for t := range j.tasks {
 				w.sg.Add(1)
-				w.tasks <- t
+				w.dispatch(t)
 			}
 			w.sg.Wait()
 			// Send result to queue and reset err
@@ -95,39 +144,130 @@ func (w *ParallelWorkers) processQueue() {
 	}()
 }
 
+// dispatch hands [t] to an idle worker. If no worker is idle, a new one is
+// started (when below [count]) before waiting for a worker to free up.
+func (w *ParallelWorkers) dispatch(t func() error) {
+	select {
+	case w.tasks <- t:
+		return
+	default:
+	}
+	w.lock.Lock()
+	if w.live < w.count {
+		w.startWorker()
+	}
+	w.lock.Unlock()
+	w.tasks <- t
+}
+
 // startWorker starts a new goroutine that listens to two channels.
 // The stopWorkers channel signals the worker to stop processing tasks.
 // The tasks channel attempts to process a job.
+//
+// Assumes w.lock is held.
 func (w *ParallelWorkers) startWorker() {
+	w.live++
+	w.recordWorkers()
+
+	var timer *time.Timer
+	if w.count > w.minWorkers {
+		timer = time.NewTimer(w.idleTimeout)
+	}
 	go func() {
 		for {
+			var idle <-chan time.Time
+			if timer != nil {
+				idle = timer.C
+			}
 			select {
 			case <-w.stopWorkers:
+				if timer != nil {
+					timer.Stop()
+				}
 				w.stoppedWorkers <- struct{}{}
 				return
-			case j := <-w.tasks:
-				// Check if we should even do the work
-				w.lock.RLock()
-				err := w.err
-				w.lock.RUnlock()
-				if err != nil {
-					w.sg.Done()
+			case <-idle:
+				if w.shrink() {
 					return
 				}
-				// Attempt to process the job
-				if err := j(); err != nil {
-					w.lock.Lock()
-					if w.err == nil {
-						w.err = err
-					}
-					w.lock.Unlock()
+				timer.Reset(w.idleTimeout)
+			case j := <-w.tasks:
+				if timer != nil && !timer.Stop() {
+					<-timer.C
+				}
+				w.run(j)
+				if timer != nil {
+					timer.Reset(w.idleTimeout)
 				}
-				w.sg.Done()
 			}
 		}
 	}()
 }
 
+// shrink returns true if an idle worker should exit because the pool is
+// larger than [minWorkers].
+func (w *ParallelWorkers) shrink() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// Once shutdown starts, [Stop] waits on every live worker.
+	if w.shouldShutdown || w.live <= w.minWorkers {
+		return false
+	}
+	w.live--
+	w.recordWorkers()
+	return true
+}
+
+// run executes [j] unless another task in the current job already failed.
+func (w *ParallelWorkers) run(j func() error) {
+	defer w.sg.Done()
+
+	// Check if we should even do the work
+	w.lock.Lock()
+	if w.err != nil {
+		w.lock.Unlock()
+		return
+	}
+	w.busy++
+	w.recordBusy()
+	w.lock.Unlock()
+
+	// Attempt to process the job
+	err := j()
+
+	w.lock.Lock()
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	w.busy--
+	w.recordBusy()
+	w.lock.Unlock()
+}
+
+// Assumes w.lock is held.
+func (w *ParallelWorkers) recordWorkers() {
+	if w.metrics != nil {
+		w.metrics.RecordWorkers(w.live)
+	}
+}
+
+// Assumes w.lock is held.
+func (w *ParallelWorkers) recordBusy() {
+	if w.metrics != nil {
+		w.metrics.RecordBusy(w.busy)
+	}
+}
+
+// Utilization returns the number of live workers and how many of them are
+// currently executing a task.
+func (w *ParallelWorkers) Utilization() (int, int) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.live, w.busy
+}
+
 // Stop stops the worker pool by setting shouldShutdown, closing the
 // queue and waiting for all workers to complete.
 func (w *ParallelWorkers) Stop() {
@@ -140,8 +280,12 @@ func (w *ParallelWorkers) Stop() {
 	<-w.ackShutdown
 	close(w.stopWorkers)
 
-	// Wait for all workers to return
-	for i := 0; i < w.count; i++ {
+	// Wait for all workers to return (no worker can exit on its own once
+	// shouldShutdown is set)
+	w.lock.RLock()
+	live := w.live
+	w.lock.RUnlock()
+	for i := 0; i < live; i++ {
 		<-w.stoppedWorkers
 	}
 }
@@ -177,7 +321,7 @@ func (j *ParallelJob) Wait() error {
 	return <-j.result
 }
 
-// Workers returns the number of workers that will execute the job. This can be used
+// Workers returns the maximum number of workers that will execute the job. This can be used
 // by callers to generate batch sizes that lead to the most efficient computation.
 func (j *ParallelJob) Workers() int {
 	return j.count
@@ -269,3 +269,76 @@ func TestNewJobShutdown(t *testing.T) {
 	require.ErrorIs(ErrShutdown, err, "NewJob returned no error")
 	require.Nil(job, "NewJob returned a not nil job pointer.")
 }
+
+type testMetrics struct {
+	l       sync.Mutex
+	workers int
+	maxBusy int
+}
+
+func (m *testMetrics) RecordWorkers(n int) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.workers = n
+}
+
+func (m *testMetrics) RecordBusy(n int) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	if n > m.maxBusy {
+		m.maxBusy = n
+	}
+}
+
+func TestAdaptiveWorkers(t *testing.T) {
+	require := require.New(t)
+	maxWorkers := 4
+	if procs := runtime.GOMAXPROCS(0); procs < maxWorkers {
+		maxWorkers = procs
+	}
+	m := &testMetrics{}
+	w := NewAdaptive(1, 4, 10, 50*time.Millisecond, m).(*ParallelWorkers)
+	require.Equal(maxWorkers, w.count)
+	live, busy := w.Utilization()
+	require.Equal(1, live)
+	require.Zero(busy)
+
+	// Blocked tasks should cause the pool to grow to its maximum
+	release := make(chan struct{})
+	job, err := w.NewJob(maxWorkers)
+	require.NoError(err)
+	require.Equal(maxWorkers, job.Workers())
+	for i := 0; i < maxWorkers; i++ {
+		job.Go(func() error {
+			<-release
+			return nil
+		})
+	}
+	job.Done(nil)
+	require.Eventually(func() bool {
+		_, busy := w.Utilization()
+		return busy == maxWorkers
+	}, 5*time.Second, 5*time.Millisecond)
+	close(release)
+	require.NoError(job.Wait())
+	m.l.Lock()
+	require.Equal(maxWorkers, m.maxBusy)
+	m.l.Unlock()
+
+	// Idle workers should exit until only the minimum remains
+	require.Eventually(func() bool {
+		live, _ := w.Utilization()
+		return live == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	m.l.Lock()
+	require.Equal(1, m.workers)
+	m.l.Unlock()
+
+	// The pool should keep working after shrinking
+	job, err = w.NewJob(1)
+	require.NoError(err)
+	job.Go(func() error { return nil })
+	job.Done(nil)
+	require.NoError(job.Wait())
+	w.Stop()
+}
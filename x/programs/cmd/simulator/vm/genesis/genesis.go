@@ -43,7 +43,8 @@ type Genesis struct {
 	MaxBlockUnits              chain.Dimensions `json:"maxBlockUnits"`     // must be possible to reach before block too large
 
 	// Tx Parameters
-	ValidityWindow int64 `json:"validityWindow"` // ms
+	ValidityWindow       int64  `json:"validityWindow"`       // ms
+	HeightValidityWindow uint64 `json:"heightValidityWindow"` // blocks (0 disables height expiry)
 
 	// Tx Fee Parameters
 	BaseComputeUnits                  uint64 `json:"baseUnits"`
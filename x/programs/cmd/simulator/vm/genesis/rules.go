@@ -6,6 +6,7 @@ package genesis
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
 )
 
 var _ chain.Rules = (*Rules)(nil)
@@ -26,6 +27,14 @@ func (*Rules) GetWarpConfig(ids.ID) (bool, uint64, uint64) {
 	return false, 0, 0
 }
 
+func (*Rules) GetWarpMaxSigners() int {
+	return 0
+}
+
+func (*Rules) GetWarpReplayRetention() int64 {
+	return 0
+}
+
 func (r *Rules) NetworkID() uint32 {
 	return r.networkID
 }
@@ -46,6 +55,10 @@ func (r *Rules) GetValidityWindow() int64 {
 	return r.g.ValidityWindow
 }
 
+func (r *Rules) GetHeightValidityWindow() uint64 {
+	return r.g.HeightValidityWindow
+}
+
 func (r *Rules) GetMaxBlockUnits() chain.Dimensions {
 	return r.g.MaxBlockUnits
 }
@@ -126,6 +139,66 @@ func (r *Rules) GetEnableBulkMemory() bool {
 	return r.g.EnableBulkMemory
 }
 
+func (*Rules) GetActionComputeUnits(uint8) (uint64, bool) {
+	return 0, false
+}
+
+func (*Rules) IsActionDisabled(uint8) bool {
+	return false
+}
+
+func (*Rules) GetActionDeprecationHeight(uint8, uint8) (uint64, bool) {
+	return 0, false
+}
+
+func (*Rules) GetAuthDeprecationHeight(uint8, uint8) (uint64, bool) {
+	return 0, false
+}
+
+func (*Rules) GetBuilderFeeShare() uint64 {
+	return 0
+}
+
+func (*Rules) GetMinFee() uint64 {
+	return 0
+}
+
+func (*Rules) GetEpochLength() uint64 {
+	return 0
+}
+
+func (*Rules) GetBlockReward(uint64) uint64 {
+	return 0
+}
+
+func (*Rules) GetBlockRewardPool() codec.Address {
+	return codec.EmptyAddress
+}
+
+func (*Rules) GetValidatorSnapshots() bool {
+	return false
+}
+
+func (*Rules) GetBlockWitnesses() bool {
+	return false
+}
+
+func (*Rules) GetSequentialNonces() bool {
+	return false
+}
+
+func (*Rules) GetTxCancellation() bool {
+	return false
+}
+
+func (*Rules) GetActionFeePayer(uint8) (codec.Address, bool) {
+	return codec.EmptyAddress, false
+}
+
+func (*Rules) IsFeatureActive(string) bool {
+	return false
+}
+
 func (*Rules) FetchCustom(string) (any, bool) {
 	return nil, false
 }
@@ -19,8 +19,12 @@ func (*StateManager) FeeKey() []byte {
 	return FeeKey()
 }
 
-func (*StateManager) IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) []byte {
-	return IncomingWarpKeyPrefix(sourceChainID, msgID)
+func (*StateManager) BurnKey() []byte {
+	return BurnKey()
+}
+
+func (*StateManager) IncomingWarpPrefix() []byte {
+	return IncomingWarpPrefix()
 }
 
 func (*StateManager) OutgoingWarpKeyPrefix(txID ids.ID) []byte {
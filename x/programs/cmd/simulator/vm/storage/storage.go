@@ -30,14 +30,17 @@ const (
 	feePrefix          = 0x4
 	incomingWarpPrefix = 0x5
 	outgoingWarpPrefix = 0x6
+	burnPrefix         = 0x7
 )
 
 var (
-	failureByte  = byte(0x0)
-	successByte  = byte(0x1)
-	heightKey    = []byte{heightPrefix}
-	timestampKey = []byte{timestampPrefix}
-	feeKey       = []byte{feePrefix}
+	failureByte     = byte(0x0)
+	successByte     = byte(0x1)
+	heightKey       = []byte{heightPrefix}
+	timestampKey    = []byte{timestampPrefix}
+	feeKey          = []byte{feePrefix}
+	burnKey         = []byte{burnPrefix}
+	incomingWarpKey = []byte{incomingWarpPrefix}
 )
 
 const ProgramChunks uint16 = 1
@@ -175,12 +178,12 @@ func FeeKey() (k []byte) {
 	return feeKey
 }
 
-func IncomingWarpKeyPrefix(sourceChainID ids.ID, msgID ids.ID) (k []byte) {
-	k = make([]byte, 1+consts.IDLen*2)
-	k[0] = incomingWarpPrefix
-	copy(k[1:], sourceChainID[:])
-	copy(k[1+consts.IDLen:], msgID[:])
-	return k
+func BurnKey() (k []byte) {
+	return burnKey
+}
+
+func IncomingWarpPrefix() (k []byte) {
+	return incomingWarpKey
 }
 
 func OutgoingWarpKeyPrefix(txID ids.ID) (k []byte) {